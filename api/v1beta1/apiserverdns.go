@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+)
+
+// ZoneName returns the name of the DNS zone, or an empty string if the zone resource ID is invalid.
+func (d *APIServerDNS) ZoneName() string {
+	if d == nil {
+		return ""
+	}
+	id, err := arm.ParseResourceID(d.ZoneResourceID)
+	if err != nil {
+		return ""
+	}
+	return id.Name
+}
+
+// ZoneSubscriptionID returns the subscription of the DNS zone, or an empty string if the zone resource ID is invalid.
+func (d *APIServerDNS) ZoneSubscriptionID() string {
+	if d == nil {
+		return ""
+	}
+	id, err := arm.ParseResourceID(d.ZoneResourceID)
+	if err != nil {
+		return ""
+	}
+	return id.SubscriptionID
+}
+
+// FQDN returns the fully qualified domain name of the record.
+func (d *APIServerDNS) FQDN() string {
+	if d == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", d.RecordName, d.ZoneName())
+}
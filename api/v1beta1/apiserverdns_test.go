@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAPIServerDNS(t *testing.T) {
+	g := NewWithT(t)
+
+	apiServerDNS := &APIServerDNS{
+		ZoneResourceID: "/subscriptions/456/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+		RecordName:     "api.cluster1",
+	}
+	g.Expect(apiServerDNS.ZoneName()).To(Equal("example.com"))
+	g.Expect(apiServerDNS.ZoneSubscriptionID()).To(Equal("456"))
+	g.Expect(apiServerDNS.FQDN()).To(Equal("api.cluster1.example.com"))
+
+	invalid := &APIServerDNS{ZoneResourceID: "example.com", RecordName: "api"}
+	g.Expect(invalid.ZoneName()).To(BeEmpty())
+	g.Expect(invalid.ZoneSubscriptionID()).To(BeEmpty())
+
+	var unset *APIServerDNS
+	g.Expect(unset.ZoneName()).To(BeEmpty())
+	g.Expect(unset.ZoneSubscriptionID()).To(BeEmpty())
+	g.Expect(unset.FQDN()).To(BeEmpty())
+}
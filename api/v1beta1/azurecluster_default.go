@@ -45,12 +45,15 @@ const (
 	DefaultOutboundRuleIdleTimeoutInMinutes = 4
 	// DefaultAzureCloud is the public cloud that will be used by most users.
 	DefaultAzureCloud = "AzurePublicCloud"
+	// DefaultAPIServerDNSTTL is the default time to live in seconds of the API server record in an Azure DNS zone.
+	DefaultAPIServerDNSTTL = 300
 )
 
 func (c *AzureCluster) setDefaults() {
 	c.Spec.AzureClusterClassSpec.setDefaults()
 	c.setResourceGroupDefault()
 	c.setNetworkSpecDefaults()
+	c.setAPIServerDNSDefaults()
 }
 
 func (c *AzureCluster) setNetworkSpecDefaults() {
@@ -69,6 +72,17 @@ func (c *AzureCluster) setResourceGroupDefault() {
 	}
 }
 
+func (c *AzureCluster) setAPIServerDNSDefaults() {
+	setDefaultAPIServerDNS(c.Spec.APIServerDNS)
+}
+
+// setDefaultAPIServerDNS defaults the TTL of the record of the API server in an Azure DNS zone.
+func setDefaultAPIServerDNS(apiServerDNS *APIServerDNS) {
+	if apiServerDNS != nil && apiServerDNS.TTL == nil {
+		apiServerDNS.TTL = ptr.To[int32](DefaultAPIServerDNSTTL)
+	}
+}
+
 func (c *AzureCluster) setAzureEnvironmentDefault() {
 	if c.Spec.AzureEnvironment == "" {
 		c.Spec.AzureEnvironment = DefaultAzureCloud
@@ -85,11 +99,22 @@ func (c *AzureCluster) setVnetDefaults() {
 	c.Spec.NetworkSpec.Vnet.VnetClassSpec.setDefaults()
 }
 
+// setSubnetDefaults sets the defaults of the subnets. Subnets without CIDR blocks get the next free block of the
+// virtual network, the cluster or control plane subnet first and then the node subnets in order.
 func (c *AzureCluster) setSubnetDefaults() {
+	var usedCIDRBlocks []string
+	for _, subnet := range c.Spec.NetworkSpec.Subnets {
+		usedCIDRBlocks = append(usedCIDRBlocks, subnet.CIDRBlocks...)
+	}
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		usedCIDRBlocks = append(usedCIDRBlocks, c.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks...)
+	}
+	cidrs := newSubnetCIDRAllocator(c.Spec.NetworkSpec.Vnet.VnetClassSpec, usedCIDRBlocks)
+
 	clusterSubnet, err := c.Spec.NetworkSpec.GetSubnet(SubnetCluster)
 	clusterSubnetExists := err == nil
 	if clusterSubnetExists {
-		clusterSubnet.setClusterSubnetDefaults(c.ObjectMeta.Name)
+		clusterSubnet.setClusterSubnetDefaults(c.ObjectMeta.Name, cidrs.allocate(clusterSubnet.SubnetClassSpec))
 		c.Spec.NetworkSpec.UpdateSubnet(clusterSubnet, SubnetCluster)
 	}
 
@@ -97,11 +122,11 @@ func (c *AzureCluster) setSubnetDefaults() {
 	   if no cp subnet and cluster subnet create a default cp subnet */
 	cpSubnet, errcp := c.Spec.NetworkSpec.GetSubnet(SubnetControlPlane)
 	if errcp == nil {
-		cpSubnet.setControlPlaneSubnetDefaults(c.ObjectMeta.Name)
+		cpSubnet.setControlPlaneSubnetDefaults(c.ObjectMeta.Name, cidrs.allocate(cpSubnet.SubnetClassSpec))
 		c.Spec.NetworkSpec.UpdateSubnet(cpSubnet, SubnetControlPlane)
 	} else if !clusterSubnetExists {
 		cpSubnet = SubnetSpec{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane}}
-		cpSubnet.setControlPlaneSubnetDefaults(c.ObjectMeta.Name)
+		cpSubnet.setControlPlaneSubnetDefaults(c.ObjectMeta.Name, cidrs.allocate(cpSubnet.SubnetClassSpec))
 		c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, cpSubnet)
 	}
 
@@ -113,16 +138,15 @@ func (c *AzureCluster) setSubnetDefaults() {
 		}
 		nodeSubnetCounter++
 		nodeSubnetFound = true
-		subnet.setNodeSubnetDefaults(c.ObjectMeta.Name, nodeSubnetCounter)
+		subnet.setNodeSubnetDefaults(c.ObjectMeta.Name, nodeSubnetCounter, cidrs.allocate(subnet.SubnetClassSpec))
 		c.Spec.NetworkSpec.Subnets[i] = subnet
 	}
 
 	if !nodeSubnetFound && !clusterSubnetExists {
 		nodeSubnet := SubnetSpec{
 			SubnetClassSpec: SubnetClassSpec{
-				Role:       SubnetNode,
-				CIDRBlocks: []string{DefaultNodeSubnetCIDR},
-				Name:       generateNodeSubnetName(c.ObjectMeta.Name),
+				Role: SubnetNode,
+				Name: generateNodeSubnetName(c.ObjectMeta.Name),
 			},
 			SecurityGroup: SecurityGroup{
 				Name: generateNodeSecurityGroupName(c.ObjectMeta.Name),
@@ -136,15 +160,16 @@ func (c *AzureCluster) setSubnetDefaults() {
 				},
 			},
 		}
+		nodeSubnet.SubnetClassSpec.setDefaults(cidrs.allocate(nodeSubnet.SubnetClassSpec))
 		c.Spec.NetworkSpec.Subnets = append(c.Spec.NetworkSpec.Subnets, nodeSubnet)
 	}
 }
 
-func (s *SubnetSpec) setNodeSubnetDefaults(clusterName string, index int) {
+func (s *SubnetSpec) setNodeSubnetDefaults(clusterName string, index int, cidr string) {
 	if s.Name == "" {
 		s.Name = withIndex(generateNodeSubnetName(clusterName), index)
 	}
-	s.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, index))
+	s.SubnetClassSpec.setDefaults(cidr)
 
 	if s.SecurityGroup.Name == "" {
 		s.SecurityGroup.Name = generateNodeSecurityGroupName(clusterName)
@@ -168,12 +193,12 @@ func (s *SubnetSpec) setNodeSubnetDefaults(clusterName string, index int) {
 	}
 }
 
-func (s *SubnetSpec) setControlPlaneSubnetDefaults(clusterName string) {
+func (s *SubnetSpec) setControlPlaneSubnetDefaults(clusterName string, cidr string) {
 	if s.Name == "" {
 		s.Name = generateControlPlaneSubnetName(clusterName)
 	}
 
-	s.SubnetClassSpec.setDefaults(DefaultControlPlaneSubnetCIDR)
+	s.SubnetClassSpec.setDefaults(cidr)
 
 	if s.SecurityGroup.Name == "" {
 		s.SecurityGroup.Name = generateControlPlaneSecurityGroupName(clusterName)
@@ -181,7 +206,7 @@ func (s *SubnetSpec) setControlPlaneSubnetDefaults(clusterName string) {
 	s.SecurityGroup.SecurityGroupClass.setDefaults()
 }
 
-func (s *SubnetSpec) setClusterSubnetDefaults(clusterName string) {
+func (s *SubnetSpec) setClusterSubnetDefaults(clusterName string, cidr string) {
 	if s.Name == "" {
 		s.Name = generateClusterSubnetSubnetName(clusterName)
 	}
@@ -197,7 +222,7 @@ func (s *SubnetSpec) setClusterSubnetDefaults(clusterName string) {
 	if !s.IsIPv6Enabled() && s.ID == "" && s.NatGateway.NatGatewayIP.Name == "" {
 		s.NatGateway.NatGatewayIP.Name = generateNatGatewayIPName(s.NatGateway.Name)
 	}
-	s.setDefaults(DefaultClusterSubnetCIDR)
+	s.setDefaults(cidr)
 	s.SecurityGroup.SecurityGroupClass.setDefaults()
 }
 
@@ -216,7 +241,7 @@ func (c *AzureCluster) setAPIServerLBDefaults() {
 
 	if lb.Type == Public {
 		if lb.Name == "" {
-			lb.Name = generatePublicLBName(c.ObjectMeta.Name)
+			lb.Name = c.loadBalancerName(APIServerLBNamingRole, generatePublicLBName(c.ObjectMeta.Name))
 		}
 		if len(lb.FrontendIPs) == 0 {
 			lb.FrontendIPs = []FrontendIP{
@@ -230,7 +255,7 @@ func (c *AzureCluster) setAPIServerLBDefaults() {
 		}
 	} else if lb.Type == Internal {
 		if lb.Name == "" {
-			lb.Name = generateInternalLBName(c.ObjectMeta.Name)
+			lb.Name = c.loadBalancerName(APIServerLBNamingRole, generateInternalLBName(c.ObjectMeta.Name))
 		}
 		if len(lb.FrontendIPs) == 0 {
 			lb.FrontendIPs = []FrontendIP{
@@ -275,7 +300,7 @@ func (c *AzureCluster) SetNodeOutboundLBDefaults() {
 	lb.LoadBalancerClassSpec.setNodeOutboundLBDefaults()
 
 	if lb.Name == "" {
-		lb.Name = c.ObjectMeta.Name
+		lb.Name = c.loadBalancerName(NodeOutboundLBNamingRole, c.ObjectMeta.Name)
 	}
 
 	if lb.FrontendIPsCount == nil {
@@ -290,13 +315,13 @@ func (c *AzureCluster) SetNodeOutboundLBDefaults() {
 func (c *AzureCluster) SetControlPlaneOutboundLBDefaults() {
 	lb := c.Spec.NetworkSpec.ControlPlaneOutboundLB
 
-	if lb == nil {
+	if lb == nil || lb.Disabled {
 		return
 	}
 
 	lb.LoadBalancerClassSpec.setControlPlaneOutboundLBDefaults()
 	if lb.Name == "" {
-		lb.Name = generateControlPlaneOutboundLBName(c.ObjectMeta.Name)
+		lb.Name = c.loadBalancerName(ControlPlaneOutboundLBNamingRole, generateControlPlaneOutboundLBName(c.ObjectMeta.Name))
 	}
 	if lb.FrontendIPsCount == nil {
 		lb.FrontendIPsCount = ptr.To[int32](1)
@@ -331,11 +356,25 @@ func (c *AzureCluster) SetNodeOutboundLBBackendPoolNameDefault() {
 // SetControlPlaneOutboundLBBackendPoolNameDefault defaults the name of the backend pool for control plane outbound LB.
 func (c *AzureCluster) SetControlPlaneOutboundLBBackendPoolNameDefault() {
 	controlPlaneOutboundLB := c.Spec.NetworkSpec.ControlPlaneOutboundLB
-	if controlPlaneOutboundLB != nil && controlPlaneOutboundLB.BackendPool.Name == "" {
+	if controlPlaneOutboundLB != nil && !controlPlaneOutboundLB.Disabled && controlPlaneOutboundLB.BackendPool.Name == "" {
 		controlPlaneOutboundLB.BackendPool.Name = generateOutboundBackendAddressPoolName(generateControlPlaneOutboundLBName(c.ObjectMeta.Name))
 	}
 }
 
+// loadBalancerName returns the name rendered by the load balancer naming template of the cluster for role, or
+// defaultName if there is no template.
+func (c *AzureCluster) loadBalancerName(role, defaultName string) string {
+	if c.Spec.NamingTemplate == nil || c.Spec.NamingTemplate.LoadBalancer == "" {
+		return defaultName
+	}
+	name, err := RenderName(c.Spec.NamingTemplate.LoadBalancer, NewNamingTemplateVariables(c.Namespace, c.Name, "", role))
+	if err != nil {
+		// The template is validated at admission, so this shouldn't happen.
+		return defaultName
+	}
+	return name
+}
+
 // setOutboundLBFrontendIPs sets the frontend ips for the given load balancer.
 // The name of the frontend ip is generated using generatePublicIPName function.
 func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePublicIPName func(string) string) {
@@ -420,8 +459,8 @@ func setControlPlaneOutboundLBDefaults(lb *LoadBalancerClassSpec, apiserverLBTyp
 		return
 	}
 
-	// private clusters can disable control plane outbound lb by setting it to nil.
-	if lb == nil {
+	// private clusters don't create the control plane outbound lb when it's nil or disabled.
+	if lb == nil || lb.Disabled {
 		return
 	}
 
@@ -21,6 +21,8 @@ import (
 	"reflect"
 	"testing"
 
+	. "github.com/onsi/gomega"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -1081,6 +1083,130 @@ func TestSubnetDefaults(t *testing.T) {
 	}
 }
 
+func TestSubnetDefaultsIPAMPool(t *testing.T) {
+	g := NewWithT(t)
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-test",
+		},
+		Spec: AzureClusterSpec{
+			NetworkSpec: NetworkSpec{
+				Subnets: Subnets{
+					{
+						SubnetClassSpec: SubnetClassSpec{
+							Role: SubnetNode,
+							IPAMPoolRef: &corev1.TypedLocalObjectReference{
+								APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+								Kind:     "InClusterIPPool",
+								Name:     "node-subnets",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cluster.setSubnetDefaults()
+
+	nodeSubnet, err := cluster.Spec.NetworkSpec.GetSubnet(SubnetNode)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(nodeSubnet.CIDRBlocks).To(BeEmpty())
+	controlPlaneSubnet, err := cluster.Spec.NetworkSpec.GetSubnet(SubnetControlPlane)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(controlPlaneSubnet.CIDRBlocks).To(Equal([]string{DefaultControlPlaneSubnetCIDR}))
+}
+
+func TestSubnetCIDRDefaults(t *testing.T) {
+	subnet := func(role SubnetRole, cidrBlocks ...string) SubnetSpec {
+		return SubnetSpec{SubnetClassSpec: SubnetClassSpec{Role: role, CIDRBlocks: cidrBlocks}}
+	}
+	tests := []struct {
+		name               string
+		vnetCIDRBlocks     []string
+		subnetPrefixLength *int32
+		bastion            *AzureBastion
+		subnets            Subnets
+		expected           [][]string
+	}{
+		{
+			name:           "one cluster subnet",
+			vnetCIDRBlocks: []string{"10.0.0.0/8"},
+			subnets:        Subnets{subnet(SubnetCluster)},
+			expected:       [][]string{{"10.0.0.0/16"}},
+		},
+		{
+			name:           "default control plane and node subnets",
+			vnetCIDRBlocks: []string{"172.16.0.0/16"},
+			expected:       [][]string{{"172.16.0.0/24"}, {"172.16.1.0/24"}},
+		},
+		{
+			name:               "three subnets with a custom prefix length",
+			vnetCIDRBlocks:     []string{"192.168.0.0/20"},
+			subnetPrefixLength: ptr.To[int32](24),
+			subnets:            Subnets{subnet(SubnetNode), subnet(SubnetControlPlane), subnet(SubnetNode)},
+			expected:           [][]string{{"192.168.1.0/24"}, {"192.168.0.0/24"}, {"192.168.2.0/24"}},
+		},
+		{
+			name:           "four subnets skip user-provided cidr blocks",
+			vnetCIDRBlocks: []string{"10.0.0.0/16"},
+			subnets:        Subnets{subnet(SubnetControlPlane, "10.0.1.0/24"), subnet(SubnetNode), subnet(SubnetNode, "10.0.2.0/23"), subnet(SubnetNode)},
+			expected:       [][]string{{"10.0.1.0/24"}, {"10.0.0.0/24"}, {"10.0.2.0/23"}, {"10.0.4.0/24"}},
+		},
+		{
+			name:           "subnets skip the bastion subnet",
+			vnetCIDRBlocks: []string{"10.0.0.0/24"},
+			bastion:        &AzureBastion{Subnet: SubnetSpec{SubnetClassSpec: SubnetClassSpec{CIDRBlocks: []string{"10.0.0.0/26"}}}},
+			expected:       [][]string{{"10.0.0.64/29"}, {"10.0.0.72/29"}},
+		},
+		{
+			name:           "allocation starts from the first IPv4 vnet cidr block",
+			vnetCIDRBlocks: []string{"2001:1234:5678:9a00::/56", "10.0.0.0/16"},
+			expected:       [][]string{{"10.0.0.0/24"}, {"10.0.1.0/24"}},
+		},
+		{
+			name:           "vnet too small for the node subnet",
+			vnetCIDRBlocks: []string{"10.0.0.0/29"},
+			expected:       [][]string{{"10.0.0.0/29"}, nil},
+		},
+		{
+			name:               "subnet prefix length shorter than the vnet prefix",
+			vnetCIDRBlocks:     []string{"10.0.0.0/24"},
+			subnetPrefixLength: ptr.To[int32](16),
+			expected:           [][]string{nil, nil},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Vnet: VnetSpec{
+							VnetClassSpec: VnetClassSpec{CIDRBlocks: tc.vnetCIDRBlocks, SubnetPrefixLength: tc.subnetPrefixLength},
+						},
+						Subnets: tc.subnets,
+					},
+					BastionSpec: BastionSpec{AzureBastion: tc.bastion},
+				},
+			}
+
+			cluster.setSubnetDefaults()
+			g.Expect(cluster.Spec.NetworkSpec.Subnets).To(HaveLen(len(tc.expected)))
+			for i, subnet := range cluster.Spec.NetworkSpec.Subnets {
+				g.Expect(subnet.CIDRBlocks).To(Equal(tc.expected[i]), "subnet %d", i)
+			}
+
+			// The allocation is stable when the defaults are applied again.
+			cluster.setSubnetDefaults()
+			for i, subnet := range cluster.Spec.NetworkSpec.Subnets {
+				g.Expect(subnet.CIDRBlocks).To(Equal(tc.expected[i]), "subnet %d", i)
+			}
+		})
+	}
+}
+
 func TestVnetPeeringDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -1443,6 +1569,34 @@ func TestAzureEnviromentDefault(t *testing.T) {
 	}
 }
 
+func TestAPIServerDNSDefaults(t *testing.T) {
+	cases := map[string]struct {
+		apiServerDNS *APIServerDNS
+		output       *APIServerDNS
+	}{
+		"no API server DNS": {},
+		"default TTL": {
+			apiServerDNS: &APIServerDNS{RecordName: "api"},
+			output:       &APIServerDNS{RecordName: "api", TTL: ptr.To[int32](DefaultAPIServerDNSTTL)},
+		},
+		"TTL set": {
+			apiServerDNS: &APIServerDNS{RecordName: "api", TTL: ptr.To[int32](60)},
+			output:       &APIServerDNS{RecordName: "api", TTL: ptr.To[int32](60)},
+		},
+	}
+
+	for name := range cases {
+		c := cases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			cluster := &AzureCluster{Spec: AzureClusterSpec{APIServerDNS: c.apiServerDNS}}
+			cluster.setAPIServerDNSDefaults()
+			g.Expect(cluster.Spec.APIServerDNS).To(Equal(c.output))
+		})
+	}
+}
+
 func TestNodeOutboundLBDefaults(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -2169,6 +2323,31 @@ func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "disabled cp lb for private clusters",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB:            LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Internal}},
+						ControlPlaneOutboundLB: &LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true}},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB:            LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Internal}},
+						ControlPlaneOutboundLB: &LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true}},
+					},
+				},
+			},
+		},
 		{
 			name: "frontendIPsCount > 1",
 			cluster: &AzureCluster{
@@ -49,6 +49,113 @@ type AzureClusterSpec struct {
 	// this when creating an AzureCluster as CAPZ will set this for you. However, if it is set, CAPZ will not change it.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// DeletionPolicy determines whether the Azure resources of the cluster are deleted along with the AzureCluster.
+	// With Retain, the Azure resources are left in place and only the Kubernetes objects are deleted. Changing the
+	// policy of an existing AzureCluster to Retain requires the
+	// infrastructure.cluster.x-k8s.io/confirm-retain-resources annotation to be set to "true".
+	// Defaults to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// APIServerDNS configures a record pointing at the API server load balancer in an Azure DNS zone.
+	// +optional
+	APIServerDNS *APIServerDNS `json:"apiServerDNS,omitempty"`
+
+	// ResourceLocks configures Azure management locks which protect critical resources of the cluster from being
+	// deleted outside of CAPZ. The locks are removed before the resources of the cluster are deleted.
+	// +optional
+	ResourceLocks *ResourceLocks `json:"resourceLocks,omitempty"`
+}
+
+// NamingTemplate defines Go templates rendering the names of Azure resources, e.g. "nic-{{ .MachineName }}". The
+// templates may only use the following variables:
+//   - .ClusterName is the name of the cluster.
+//   - .MachineName is the name of the AzureMachine, or empty for the resources of the cluster.
+//   - .Role is the role of the AzureMachine, control-plane or node, or the role of the load balancer.
+//   - .RandomSuffix is a string of 5 lowercase alphanumeric characters derived from the namespace and names of the
+//     cluster and machine, so that it doesn't change between reconciliations.
+//
+// Resources with a name set in their spec, and the ones without a template, keep the name CAPZ generates by default.
+type NamingTemplate struct {
+	// NetworkInterface renders the name of the network interfaces of AzureMachines. The index of the interface is
+	// appended to the name of the interfaces of machines with several interfaces, e.g. "-0".
+	// +optional
+	NetworkInterface string `json:"networkInterface,omitempty"`
+
+	// PublicIP renders the name of the public IPs of AzureMachines which allocate one.
+	// +optional
+	PublicIP string `json:"publicIP,omitempty"`
+
+	// Disk renders the name of the OS disk of AzureMachines. The name suffix of a data disk is appended to the name
+	// of the data disks, e.g. "_etcddisk".
+	// +optional
+	Disk string `json:"disk,omitempty"`
+
+	// LoadBalancer renders the name of the API server, node outbound and control plane outbound load balancers
+	// whose name isn't set. .Role is apiserver, node-outbound or controlplane-outbound respectively, so the template
+	// must use it to give each load balancer a different name.
+	// +optional
+	LoadBalancer string `json:"loadBalancer,omitempty"`
+}
+
+// ResourceLockLevel is the level of an Azure management lock.
+type ResourceLockLevel string
+
+const (
+	// ResourceLockLevelCanNotDelete allows reading and modifying a resource but not deleting it.
+	ResourceLockLevelCanNotDelete ResourceLockLevel = "CanNotDelete"
+)
+
+// ResourceLockScope is a kind of resource of the cluster which can be locked. The resource group of the cluster
+// can't be locked, since the lock would keep the virtual machines of deleted machines from being deleted.
+// +kubebuilder:validation:Enum=VNet;PublicIP
+type ResourceLockScope string
+
+const (
+	// ResourceLockScopeVNet locks the virtual network of the cluster.
+	ResourceLockScopeVNet ResourceLockScope = "VNet"
+	// ResourceLockScopePublicIP locks the public IP of the API server load balancer. It has no effect for an
+	// internal API server load balancer.
+	ResourceLockScopePublicIP ResourceLockScope = "PublicIP"
+)
+
+// ResourceLocks defines the Azure management locks placed on the critical resources of a cluster.
+type ResourceLocks struct {
+	// Level is the level of the locks. Only CanNotDelete is supported.
+	// +kubebuilder:validation:Enum=CanNotDelete
+	// +kubebuilder:default=CanNotDelete
+	// +optional
+	Level ResourceLockLevel `json:"level,omitempty"`
+
+	// Scope lists the resources to lock.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Scope []ResourceLockScope `json:"scope"`
+}
+
+// APIServerDNS defines a record pointing at the API server load balancer in an Azure DNS zone. For a public API
+// server, the record is an alias of its public IP. For an internal one, it is an A or AAAA record of its private IP.
+// For a managed cluster, it is a CNAME record of the FQDN of its API server.
+type APIServerDNS struct {
+	// ZoneResourceID is the Azure resource ID of the DNS zone in which the record is created, e.g.
+	// /subscriptions/<subscription ID>/resourceGroups/<resource group>/providers/Microsoft.Network/dnszones/example.com.
+	// The zone can be in another subscription than the cluster, as long as the identity of the cluster can manage its
+	// records. It is immutable.
+	ZoneResourceID string `json:"zoneResourceID"`
+
+	// RecordName is the name of the record relative to the zone, e.g. api.cluster1. It is immutable.
+	RecordName string `json:"recordName"`
+
+	// TTL is the time to live of the record in seconds. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int32 `json:"ttl,omitempty"`
+
+	// UseForControlPlaneEndpoint makes CAPZ set the host of the control plane endpoint to the FQDN of the record
+	// instead of the FQDN of the API server load balancer. It has no effect once the control plane endpoint is set.
+	// +optional
+	UseForControlPlaneEndpoint bool `json:"useForControlPlaneEndpoint,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster.
@@ -74,6 +181,100 @@ type AzureClusterStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ManagedResources records the Azure resources created by CAPZ for this cluster, which are the only
+	// resources CAPZ deletes along with the cluster. It is unset for clusters created before CAPZ started
+	// recording the resources it creates, in which case ownership is determined from resource tags.
+	// +optional
+	ManagedResources *ManagedResources `json:"managedResources,omitempty"`
+
+	// APIServerDNSLabel is the DNS label CAPZ generated for the public IP of the API server when its DNSName wasn't
+	// specified. It is reused if the public IP is recreated, so that the FQDN of the API server remains stable.
+	// +optional
+	APIServerDNSLabel string `json:"apiServerDNSLabel,omitempty"`
+
+	// APIServerLBMigration records the progress of the migration of the API server load balancer from Public to
+	// Internal requested with the MigrateAPIServerLBToInternalAnnotation.
+	// +optional
+	APIServerLBMigration *APIServerLBMigration `json:"apiServerLBMigration,omitempty"`
+
+	// OutboundIPs are the public IP addresses the cluster egresses from, i.e. the addresses of the public IPs of the
+	// node and control plane outbound load balancers, of the public API server load balancer, and of the NAT gateways.
+	// +optional
+	OutboundIPs []string `json:"outboundIPs,omitempty"`
+
+	// ReconcileBackoff records the consecutive transient failures of the reconciliation of the cluster. It is only
+	// set when the TransientErrorBackoff feature is enabled.
+	// +optional
+	ReconcileBackoff *ReconcileBackoff `json:"reconcileBackoff,omitempty"`
+
+	// V1Beta2 groups the conditions of the AzureCluster following the Cluster API v1beta2 contract.
+	// +optional
+	V1Beta2 *V1Beta2Status `json:"v1beta2,omitempty"`
+}
+
+// APIServerLBMigrationPhase is a phase of the migration of the API server load balancer from Public to Internal.
+// +kubebuilder:validation:Enum=Pending;CreatingInternalLB;SwitchingEndpoint;ReplacingMachines;DeletingPublicLB;Completed
+type APIServerLBMigrationPhase string
+
+const (
+	// APIServerLBMigrationPending means that the public load balancer was recorded, and that the type of the API
+	// server load balancer can be changed to Internal.
+	APIServerLBMigrationPending APIServerLBMigrationPhase = "Pending"
+	// APIServerLBMigrationCreatingInternalLB means that the internal load balancer is being created.
+	APIServerLBMigrationCreatingInternalLB APIServerLBMigrationPhase = "CreatingInternalLB"
+	// APIServerLBMigrationSwitchingEndpoint means that the control plane machines are added to the internal load
+	// balancer, and that the control plane endpoint is switched to it once the internal load balancer is available and
+	// the certificates of the control plane are valid for it.
+	APIServerLBMigrationSwitchingEndpoint APIServerLBMigrationPhase = "SwitchingEndpoint"
+	// APIServerLBMigrationReplacingMachines means that the control plane endpoint was switched to the internal load
+	// balancer, and that the machines created before the switch, whose kubelets still reach the API server through the
+	// public load balancer, are being replaced.
+	APIServerLBMigrationReplacingMachines APIServerLBMigrationPhase = "ReplacingMachines"
+	// APIServerLBMigrationDeletingPublicLB means that the control plane machines are removed from the public load
+	// balancer, and that it is deleted along with its public IPs.
+	APIServerLBMigrationDeletingPublicLB APIServerLBMigrationPhase = "DeletingPublicLB"
+	// APIServerLBMigrationCompleted means that the public load balancer was replaced by the internal one.
+	APIServerLBMigrationCompleted APIServerLBMigrationPhase = "Completed"
+)
+
+// APIServerLBMigration defines the progress of the migration of the API server load balancer from Public to
+// Internal, and the public load balancer being replaced, as it is no longer in the spec once the type changes.
+type APIServerLBMigration struct {
+	// Phase is the current phase of the migration.
+	Phase APIServerLBMigrationPhase `json:"phase"`
+
+	// PublicLBName is the name of the public API server load balancer.
+	PublicLBName string `json:"publicLBName"`
+
+	// PublicLBBackendPoolName is the name of the backend pool of the public API server load balancer.
+	// +optional
+	PublicLBBackendPoolName string `json:"publicLBBackendPoolName,omitempty"`
+
+	// PublicLBFrontendIPName is the name of the frontend IP configuration of the public API server load balancer.
+	// +optional
+	PublicLBFrontendIPName string `json:"publicLBFrontendIPName,omitempty"`
+
+	// PublicIPNames are the names of the public IPs of the public API server load balancer.
+	// +optional
+	PublicIPNames []string `json:"publicIPNames,omitempty"`
+
+	// EndpointSwitchTime is the time the control plane endpoint was switched to the internal load balancer. The
+	// public load balancer is deleted once all the machines of the cluster are created after it.
+	// +optional
+	EndpointSwitchTime *metav1.Time `json:"endpointSwitchTime,omitempty"`
+}
+
+// ManagedResources defines the Azure resources created by CAPZ for a cluster.
+type ManagedResources struct {
+	// IDs is the list of Azure resource IDs of the resources created by CAPZ.
+	// +optional
+	IDs []string `json:"ids,omitempty"`
+
+	// Deleting maps the resources deleting Azure resources recorded in IDs to the ID of the Azure resource being
+	// deleted, so that the ID is only forgotten once the deletion is confirmed.
+	// +optional
+	Deleting map[string]string `json:"deleting,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -118,6 +319,22 @@ func (c *AzureCluster) SetConditions(conditions clusterv1.Conditions) {
 	c.Status.Conditions = conditions
 }
 
+// GetV1Beta2Conditions returns the list of v1beta2 conditions for an AzureCluster API object.
+func (c *AzureCluster) GetV1Beta2Conditions() []metav1.Condition {
+	if c.Status.V1Beta2 == nil {
+		return nil
+	}
+	return c.Status.V1Beta2.Conditions
+}
+
+// SetV1Beta2Conditions will set the given v1beta2 conditions on an AzureCluster object.
+func (c *AzureCluster) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	if c.Status.V1Beta2 == nil {
+		c.Status.V1Beta2 = &V1Beta2Status{}
+	}
+	c.Status.V1Beta2.Conditions = conditions
+}
+
 // GetFutures returns the list of long running operation states for an AzureCluster API object.
 func (c *AzureCluster) GetFutures() Futures {
 	return c.Status.LongRunningOperationStates
@@ -128,6 +345,16 @@ func (c *AzureCluster) SetFutures(futures Futures) {
 	c.Status.LongRunningOperationStates = futures
 }
 
+// GetReconcileBackoff returns the reconcile backoff of an AzureCluster API object.
+func (c *AzureCluster) GetReconcileBackoff() *ReconcileBackoff {
+	return c.Status.ReconcileBackoff
+}
+
+// SetReconcileBackoff will set the given reconcile backoff on an AzureCluster object.
+func (c *AzureCluster) SetReconcileBackoff(backoff *ReconcileBackoff) {
+	c.Status.ReconcileBackoff = backoff
+}
+
 func init() {
 	SchemeBuilder.Register(&AzureCluster{}, &AzureClusterList{})
 }
@@ -21,11 +21,16 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	valid "github.com/asaskevich/govalidator"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
@@ -50,6 +55,10 @@ const (
 	MinLBIdleTimeoutInMinutes = 4
 	// MaxLBIdleTimeoutInMinutes is the maximum number of minutes for the LB idle timeout.
 	MaxLBIdleTimeoutInMinutes = 30
+	// MinNatGatewayIdleTimeoutInMinutes is the minimum number of minutes for the NAT gateway idle timeout.
+	MinNatGatewayIdleTimeoutInMinutes = 4
+	// MaxNatGatewayIdleTimeoutInMinutes is the maximum number of minutes for the NAT gateway idle timeout.
+	MaxNatGatewayIdleTimeoutInMinutes = 120
 	// Network security rules should be a number between 100 and 4096.
 	// https://learn.microsoft.com/azure/virtual-network/network-security-groups-overview#security-rules
 	minRulePriority = 100
@@ -60,13 +69,22 @@ const (
 	serviceEndpointLocationRegexPattern = `^([a-z]{1,42}\d{0,5}|[*])$`
 	// described in https://learn.microsoft.com/azure/azure-resource-manager/management/resource-name-rules.
 	privateEndpointRegex = `^[-\w\._]+$`
+	// availability zones are identified by a single logical zone number, e.g. "1".
+	availabilityZoneRegex = `^[1-9][0-9]*$`
+	// virtualNetworkResourceType is the Azure resource type of a virtual network.
+	virtualNetworkResourceType = "Microsoft.Network/virtualNetworks"
+	// dnsZoneResourceType is the Azure resource type of a DNS zone.
+	dnsZoneResourceType = "Microsoft.Network/dnszones"
 	// resource ID Pattern.
 	resourceIDPattern = `(?i)subscriptions/(.+)/resourceGroups/(.+)/providers/(.+?)/(.+?)/(.+)`
+	// the DNS label of a public IP, described in https://learn.microsoft.com/azure/virtual-network/ip-services/public-ip-addresses#dns-name-label.
+	publicIPDNSLabelRegexPattern = `^[a-z][a-z0-9-]{1,61}[a-z0-9]$`
 )
 
 var (
 	serviceEndpointServiceRegex  = regexp.MustCompile(serviceEndpointServiceRegexPattern)
 	serviceEndpointLocationRegex = regexp.MustCompile(serviceEndpointLocationRegexPattern)
+	publicIPDNSLabelRegex        = regexp.MustCompile(publicIPDNSLabelRegexPattern)
 )
 
 // validateCluster validates a cluster.
@@ -74,21 +92,67 @@ func (c *AzureCluster) validateCluster(old *AzureCluster) (admission.Warnings, e
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, c.validateClusterName()...)
 	allErrs = append(allErrs, c.validateClusterSpec(old)...)
+	warnings := c.ignoredFieldWarnings(old)
 	if len(allErrs) == 0 {
-		return nil, nil
+		return warnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(
+	return warnings, apierrors.NewInvalid(
 		schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: AzureClusterKind},
 		c.Name, allErrs)
 }
 
+// ignoredFieldWarnings returns a warning for each field of the spec which has no effect. Fields which were already
+// set the same way on the old cluster aren't reported again.
+func (c *AzureCluster) ignoredFieldWarnings(old *AzureCluster) admission.Warnings {
+	fldPath := field.NewPath("spec", "networkSpec", "subnets")
+	warnings := ignoredSubnetFieldWarnings(c.Spec.NetworkSpec.Subnets, fldPath)
+	if old == nil {
+		return warnings
+	}
+	oldWarnings := ignoredSubnetFieldWarnings(old.Spec.NetworkSpec.Subnets, fldPath)
+	var newWarnings admission.Warnings
+	for _, warning := range warnings {
+		if !slices.Contains(oldWarnings, warning) {
+			newWarnings = append(newWarnings, warning)
+		}
+	}
+	return newWarnings
+}
+
+// ignoredSubnetFieldWarnings returns a warning for each NAT gateway field of the subnets which has no effect.
+func ignoredSubnetFieldWarnings(subnets Subnets, fldPath *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+	for i, subnet := range subnets {
+		natGatewayPath := fldPath.Index(i).Child("natGateway")
+		natGateway := subnet.NatGateway
+		if subnet.Role == SubnetControlPlane {
+			if natGateway.Name != "" || natGateway.NatGatewayIP.Name != "" {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored, the control plane subnet uses the API server load balancer for outbound traffic", natGatewayPath))
+			}
+			continue
+		}
+		if natGateway.Name == "" && natGateway.NatGatewayIP.Name != "" {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored without %s, set it to create the NAT gateway", natGatewayPath.Child("ip"), natGatewayPath.Child("name")))
+		}
+	}
+	return warnings
+}
+
 // validateClusterSpec validates a ClusterSpec.
 func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	var allErrs field.ErrorList
 	var oldNetworkSpec NetworkSpec
 	if old != nil {
 		oldNetworkSpec = old.Spec.NetworkSpec
+		// The internal API server load balancer replaces the public one, so it is validated as a new load balancer.
+		if c.migratesAPIServerLBToInternal(old) {
+			oldNetworkSpec.APIServerLB = LoadBalancerSpec{}
+			if c.Spec.NetworkSpec.APIServerLB.Name == old.Spec.NetworkSpec.APIServerLB.Name {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "apiServerLB", "name"), c.Spec.NetworkSpec.APIServerLB.Name,
+					"the internal API Server load balancer must have another name than the public one it replaces"))
+			}
+		}
 	}
 	allErrs = append(allErrs, validateNetworkSpec(c.Spec.NetworkSpec, oldNetworkSpec, field.NewPath("spec").Child("networkSpec"))...)
 
@@ -104,17 +168,274 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ExtendedLocation"), "can be set only if the EdgeZone feature flag is enabled"))
 	}
 
-	if err := validateBastionSpec(c.Spec.BastionSpec, field.NewPath("spec").Child("azureBastion").Child("bastionSpec")); err != nil {
-		allErrs = append(allErrs, err)
-	}
+	allErrs = append(allErrs, validateBastionSpec(c.Spec.BastionSpec, field.NewPath("spec").Child("azureBastion").Child("bastionSpec"))...)
 
 	if err := validateIdentityRef(c.Spec.IdentityRef, field.NewPath("spec").Child("identityRef")); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateDefaultImage(c.Spec.DefaultImage, field.NewPath("spec").Child("defaultImage"))...)
+
+	allErrs = append(allErrs, validateClusterVMExtensions(c.Spec.VMExtensions, field.NewPath("spec").Child("vmExtensions"))...)
+
+	allErrs = append(allErrs, validateAPIServerDNS(c.Spec.APIServerDNS, field.NewPath("spec").Child("apiServerDNS"))...)
+
+	allErrs = append(allErrs, validateNamingTemplate(c.Spec.NamingTemplate, c.Namespace, c.Name, field.NewPath("spec").Child("namingTemplate"))...)
+
+	allErrs = append(allErrs, c.validateIPZones()...)
+
+	return allErrs
+}
+
+// validateAPIServerLBPorts validates the ports of the API server load balancer against apiServerPort, the port of the
+// API server load balancing rule.
+func (c *AzureCluster) validateAPIServerLBPorts(apiServerPort int32) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// The health probe port should match the backend port of the API server load balancing rule.
+	if probe := c.Spec.NetworkSpec.APIServerLB.HealthProbe; probe != nil && probe.Port != nil && *probe.Port != apiServerPort {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "apiServerLB", "healthProbe", "port"), *probe.Port,
+			fmt.Sprintf("API Server load balancer health probe port should match the API server port %d", apiServerPort)))
+	}
+
+	// Additional ports can't reuse the API server port.
+	for i, port := range c.Spec.NetworkSpec.APIServerLB.AdditionalAPIServerLBPorts {
+		if port.Port == apiServerPort {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "apiServerLB", "additionalAPIServerLBPorts").Index(i).Child("port"), port.Port,
+				fmt.Sprintf("additional API Server load balancer port should not be the API server port %d", apiServerPort)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAPIServerDNS validates the record of the API server in an Azure DNS zone.
+func validateAPIServerDNS(apiServerDNS *APIServerDNS, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if apiServerDNS == nil {
+		return allErrs
+	}
+
+	id, err := arm.ParseResourceID(apiServerDNS.ZoneResourceID)
+	if err != nil || !strings.EqualFold(id.ResourceType.String(), dnsZoneResourceType) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneResourceID"), apiServerDNS.ZoneResourceID,
+			"must be a valid Azure resource ID of a DNS zone, e.g. /subscriptions/<subscription ID>/resourceGroups/<resource group>/providers/Microsoft.Network/dnszones/<name>"))
+	}
+
+	if apiServerDNS.RecordName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("recordName"), "the name of the record is required"))
+	} else if errs := validation.IsDNS1123Subdomain(apiServerDNS.RecordName); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), apiServerDNS.RecordName, strings.Join(errs, "; ")))
+	} else if err == nil {
+		if errs := validation.IsDNS1123Subdomain(strings.ToLower(apiServerDNS.FQDN())); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("recordName"), apiServerDNS.RecordName,
+				fmt.Sprintf("the FQDN of the record %s is invalid: %s", apiServerDNS.FQDN(), strings.Join(errs, "; "))))
+		}
+	}
+
+	if apiServerDNS.TTL != nil && *apiServerDNS.TTL < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ttl"), *apiServerDNS.TTL, "must be at least 1"))
+	}
+
+	return allErrs
+}
+
+// validateLocationZones validates that the availability zones requested by the AzureCluster exist in the given
+// zones of its location.
+func (c *AzureCluster) validateLocationZones(zones []string) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Spec.ExtendedLocation != nil {
+		return allErrs
+	}
+
+	available := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		available[zone] = true
+	}
+
+	validateZone := func(zone string, fldPath *field.Path) {
+		if len(zones) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, zone,
+				fmt.Sprintf("location %s does not support availability zones, so zonal and zone-redundant resources cannot be created", c.Spec.Location)))
+		} else if !available[zone] {
+			allErrs = append(allErrs, field.NotSupported(fldPath, zone, zones))
+		}
+	}
+
+	failureDomainIDs := make([]string, 0, len(c.Spec.FailureDomains))
+	for id := range c.Spec.FailureDomains {
+		failureDomainIDs = append(failureDomainIDs, id)
+	}
+	sort.Strings(failureDomainIDs)
+	for _, id := range failureDomainIDs {
+		validateZone(id, field.NewPath("spec", "failureDomains").Key(id))
+	}
+
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		if subnet.NatGateway.Zone != nil {
+			validateZone(*subnet.NatGateway.Zone, field.NewPath("spec", "networkSpec", "subnets").Index(i).Child("natGateway", "zone"))
+		}
+	}
+
+	for _, ipZones := range c.ipZones() {
+		for i, zone := range ipZones.zones {
+			validateZone(zone, ipZones.fldPath.Index(i))
+		}
+	}
+
 	return allErrs
 }
 
+// ipZonesField is the availability zones set on a frontend IP or a public IP of a cluster.
+type ipZonesField struct {
+	zones   []string
+	fldPath *field.Path
+}
+
+// ipZones returns the availability zones set on the frontend IPs and public IPs of the cluster.
+func (c *AzureCluster) ipZones() []ipZonesField {
+	var fields []ipZonesField
+	add := func(zones []string, fldPath *field.Path) {
+		if len(zones) > 0 {
+			fields = append(fields, ipZonesField{zones: zones, fldPath: fldPath})
+		}
+	}
+
+	networkPath := field.NewPath("spec", "networkSpec")
+	for _, lb := range c.Spec.NetworkSpec.loadBalancers() {
+		for i, frontendIP := range lb.spec.FrontendIPs {
+			frontendIPPath := networkPath.Child(lb.name, "frontendIPs").Index(i)
+			add(frontendIP.Zones, frontendIPPath.Child("zones"))
+			if frontendIP.PublicIP != nil {
+				add(frontendIP.PublicIP.Zones, frontendIPPath.Child("publicIP", "zones"))
+			}
+		}
+	}
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		add(subnet.NatGateway.NatGatewayIP.Zones, networkPath.Child("subnets").Index(i).Child("natGateway", "ip", "zones"))
+	}
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		add(c.Spec.BastionSpec.AzureBastion.PublicIP.Zones, field.NewPath("spec", "bastionSpec", "azureBastion", "publicIP", "zones"))
+	}
+
+	return fields
+}
+
+// namedLoadBalancer is a load balancer of a NetworkSpec along with the JSON name of its field.
+type namedLoadBalancer struct {
+	name string
+	spec *LoadBalancerSpec
+}
+
+// loadBalancers returns the load balancers of the NetworkSpec.
+func (n *NetworkSpec) loadBalancers() []namedLoadBalancer {
+	lbs := []namedLoadBalancer{{name: "apiServerLB", spec: &n.APIServerLB}}
+	if n.NodeOutboundLB != nil {
+		lbs = append(lbs, namedLoadBalancer{name: "nodeOutboundLB", spec: n.NodeOutboundLB})
+	}
+	if n.ControlPlaneOutboundLB != nil {
+		lbs = append(lbs, namedLoadBalancer{name: "controlPlaneOutboundLB", spec: n.ControlPlaneOutboundLB})
+	}
+	return lbs
+}
+
+// validateIPZones validates the availability zones set on the frontend IPs and public IPs of the cluster.
+func (c *AzureCluster) validateIPZones() field.ErrorList {
+	var allErrs field.ErrorList
+	for _, ipZones := range c.ipZones() {
+		seen := make(map[string]bool, len(ipZones.zones))
+		for i, zone := range ipZones.zones {
+			if success, _ := regexp.MatchString(availabilityZoneRegex, zone); !success {
+				allErrs = append(allErrs, field.Invalid(ipZones.fldPath.Index(i), zone,
+					"zone must be an availability zone such as \"1\""))
+			} else if seen[zone] {
+				allErrs = append(allErrs, field.Duplicate(ipZones.fldPath.Index(i), zone))
+			}
+			seen[zone] = true
+		}
+	}
+
+	networkPath := field.NewPath("spec", "networkSpec")
+	for _, lb := range c.Spec.NetworkSpec.loadBalancers() {
+		for i, frontendIP := range lb.spec.FrontendIPs {
+			if frontendIP.PublicIP != nil && len(frontendIP.Zones) > 0 {
+				allErrs = append(allErrs, field.Forbidden(networkPath.Child(lb.name, "frontendIPs").Index(i).Child("zones"),
+					"the zones of a public frontend IP are set on its public IP"))
+			}
+		}
+	}
+
+	// A zonal NAT gateway requires its public IP to be in the same zone.
+	for i, subnet := range c.Spec.NetworkSpec.Subnets {
+		natGateway := subnet.NatGateway
+		if natGateway.Zone != nil && len(natGateway.NatGatewayIP.Zones) > 0 && !slices.Equal(natGateway.NatGatewayIP.Zones, []string{*natGateway.Zone}) {
+			allErrs = append(allErrs, field.Invalid(networkPath.Child("subnets").Index(i).Child("natGateway", "ip", "zones"), natGateway.NatGatewayIP.Zones,
+				fmt.Sprintf("the public IP of a zonal NAT gateway must be in the zone of the NAT gateway %s", *natGateway.Zone)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFrontendIPZonesUpdate validates that the availability zones of a frontend IP and of its public IP don't
+// change, as Azure can't move them to other zones.
+func validateFrontendIPZonesUpdate(frontendIP, old FrontendIP, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !slices.Equal(frontendIP.Zones, old.Zones) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("zones"), frontendIP.Zones, "field is immutable"))
+	}
+	if frontendIP.PublicIP != nil && old.PublicIP != nil && !slices.Equal(frontendIP.PublicIP.Zones, old.PublicIP.Zones) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("publicIP", "zones"), frontendIP.PublicIP.Zones, "field is immutable"))
+	}
+	return allErrs
+}
+
+// validateDefaultImage validates a DefaultImage.
+func validateDefaultImage(defaultImage *DefaultImage, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if defaultImage == nil {
+		return allErrs
+	}
+	if defaultImage.SubscriptionID != nil && defaultImage.ResourceGroup == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceGroup"), "", "ResourceGroup cannot be empty when SubscriptionID is specified"))
+	}
+	if defaultImage.ResourceGroup != nil && defaultImage.SubscriptionID == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subscriptionID"), "", "SubscriptionID cannot be empty when ResourceGroup is specified"))
+	}
+	return allErrs
+}
+
+// validateClusterVMExtensions validates the VM extensions installed on all the machines of a cluster.
+func validateClusterVMExtensions(extensions []ClusterVMExtension, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]struct{}, len(extensions))
+	for i, extension := range extensions {
+		if _, ok := names[extension.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), extension.Name))
+		}
+		names[extension.Name] = struct{}{}
+	}
+	return allErrs
+}
+
+// migratesAPIServerLBToInternal returns true if the update changes the type of the API server load balancer from
+// Public to Internal, once the MigrateAPIServerLBToInternalAnnotation is set and the public load balancer is recorded
+// in the status of the AzureCluster.
+func (c *AzureCluster) migratesAPIServerLBToInternal(old *AzureCluster) bool {
+	return c.Annotations[MigrateAPIServerLBToInternalAnnotation] == "true" &&
+		old.Status.APIServerLBMigration != nil && old.Status.APIServerLBMigration.Phase == APIServerLBMigrationPending &&
+		old.Spec.NetworkSpec.APIServerLB.Type == Public && c.Spec.NetworkSpec.APIServerLB.Type == Internal
+}
+
+// switchesControlPlaneEndpoint returns true if the control plane endpoint can be switched to the internal API server
+// load balancer by the migration of the API server load balancer.
+func switchesControlPlaneEndpoint(old *AzureCluster) bool {
+	migration := old.Status.APIServerLBMigration
+	return migration != nil &&
+		(migration.Phase == APIServerLBMigrationSwitchingEndpoint || migration.Phase == APIServerLBMigrationReplacingMachines ||
+			migration.Phase == APIServerLBMigrationDeletingPublicLB)
+}
+
 // validateClusterName validates ClusterName.
 func (c *AzureCluster) validateClusterName() field.ErrorList {
 	var allErrs field.ErrorList
@@ -134,12 +455,21 @@ func (c *AzureCluster) validateClusterName() field.ErrorList {
 }
 
 // validateBastionSpec validates a BastionSpec.
-func validateBastionSpec(bastionSpec BastionSpec, fldPath *field.Path) *field.Error {
-	if bastionSpec.AzureBastion != nil && bastionSpec.AzureBastion.Sku != StandardBastionHostSku && bastionSpec.AzureBastion.EnableTunneling {
-		return field.Invalid(fldPath.Child("sku"), bastionSpec.AzureBastion.Sku,
-			"sku must be Standard if tunneling is enabled")
+func validateBastionSpec(bastionSpec BastionSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	azureBastion := bastionSpec.AzureBastion
+	if azureBastion == nil {
+		return allErrs
 	}
-	return nil
+	if azureBastion.Sku != StandardBastionHostSku && azureBastion.EnableTunneling {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sku"), azureBastion.Sku,
+			"sku must be Standard if tunneling is enabled"))
+	}
+	if azureBastion.Sku != StandardBastionHostSku && azureBastion.EnableShareableLink {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sku"), azureBastion.Sku,
+			"sku must be Standard if shareable link is enabled"))
+	}
+	return allErrs
 }
 
 // validateIdentityRef validates an IdentityRef.
@@ -169,6 +499,8 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 		allErrs = append(allErrs, validateSubnets(networkSpec.Subnets, networkSpec.Vnet, fldPath.Child("subnets"))...)
 
+		allErrs = append(allErrs, validateSubnetCIDROverlaps(networkSpec.Subnets, old.Subnets, fldPath.Child("subnets"))...)
+
 		allErrs = append(allErrs, validateVnetPeerings(networkSpec.Vnet.Peerings, fldPath.Child("peerings"))...)
 	}
 
@@ -195,8 +527,12 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	allErrs = append(allErrs, validateControlPlaneOutboundLB(networkSpec.ControlPlaneOutboundLB, networkSpec.APIServerLB, fldPath.Child("controlPlaneOutboundLB"))...)
 
+	allErrs = append(allErrs, validateControlPlaneEgress(networkSpec, fldPath.Child("controlPlaneOutboundLB", "disabled"))...)
+
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec.PrivateDNSZoneName, networkSpec.APIServerLB.Type, fldPath.Child("privateDNSZoneName"))...)
 
+	allErrs = append(allErrs, validatePrivateDNSZone(networkSpec.PrivateDNSZone, networkSpec.APIServerLB.Type, fldPath.Child("privateDNSZone"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -223,6 +559,12 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 	}
 	clusterSubnet := false
 	numberofClusterSubnets := 0
+	var usedCIDRBlocks []string
+	for _, subnet := range subnets {
+		usedCIDRBlocks = append(usedCIDRBlocks, subnet.CIDRBlocks...)
+	}
+	// Subnets without CIDR blocks get one generated by the defaulting webhook unless the vnet is too small.
+	cidrs := newSubnetCIDRAllocator(vnet.VnetClassSpec, usedCIDRBlocks)
 	for i, subnet := range subnets {
 		if err := validateSubnetName(subnet.Name, fldPath.Index(i).Child("name")); err != nil {
 			allErrs = append(allErrs, err)
@@ -250,8 +592,14 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 				allErrs = append(allErrs, err...)
 			}
 		}
+		if cidrs != nil && len(subnet.CIDRBlocks) == 0 && subnet.IPAMPoolRef == nil && cidrs.allocate(subnet.SubnetClassSpec) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("cidrBlocks"),
+				fmt.Sprintf("no free /%d block left in vnet address space %s, specify the subnet CIDR blocks or a longer vnet subnetPrefixLength", cidrs.prefixLen, vnet.CIDRBlocks)))
+		}
 		allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, vnet.CIDRBlocks, fldPath.Index(i).Child("cidrBlocks"))...)
 
+		allErrs = append(allErrs, validateIPAMPoolRef(subnet.IPAMPoolRef, fldPath.Index(i).Child("ipamPoolRef"))...)
+
 		if len(subnet.ServiceEndpoints) > 0 {
 			allErrs = append(allErrs, validateServiceEndpoints(subnet.ServiceEndpoints, fldPath.Index(i).Child("serviceEndpoints"))...)
 		}
@@ -259,6 +607,8 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 		if len(subnet.PrivateEndpoints) > 0 {
 			allErrs = append(allErrs, validatePrivateEndpoints(subnet.PrivateEndpoints, subnet.CIDRBlocks, fldPath.Index(i).Child("privateEndpoints"))...)
 		}
+
+		allErrs = append(allErrs, validateNatGateway(subnet.NatGateway.NatGatewayClassSpec, fldPath.Index(i).Child("natGateway"))...)
 	}
 
 	// The clusterSubnet is applicable to both the control-plane and node pools.
@@ -276,6 +626,22 @@ func validateSubnets(subnets Subnets, vnet VnetSpec, fldPath *field.Path) field.
 	return allErrs
 }
 
+// validateNatGateway validates the zone and idle timeout of a NAT gateway.
+func validateNatGateway(natGateway NatGatewayClassSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if natGateway.Zone != nil {
+		if success, _ := regexp.MatchString(availabilityZoneRegex, *natGateway.Zone); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("zone"), *natGateway.Zone,
+				"NAT gateways can't be zone-redundant, zone must be a single availability zone such as \"1\""))
+		}
+	}
+	if natGateway.IdleTimeoutInMinutes != nil && (*natGateway.IdleTimeoutInMinutes < MinNatGatewayIdleTimeoutInMinutes || *natGateway.IdleTimeoutInMinutes > MaxNatGatewayIdleTimeoutInMinutes) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *natGateway.IdleTimeoutInMinutes,
+			fmt.Sprintf("NAT gateway idle timeout should be between %d and %d minutes", MinNatGatewayIdleTimeoutInMinutes, MaxNatGatewayIdleTimeoutInMinutes)))
+	}
+	return allErrs
+}
+
 // validateSubnetName validates the Name of a Subnet.
 func validateSubnetName(name string, fldPath *field.Path) *field.Error {
 	if success, _ := regexp.Match(subnetRegex, []byte(name)); !success {
@@ -297,14 +663,15 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	}
 
 	for _, subnetCidr := range subnetCidrBlocks {
-		subnetCidrIP, _, err := net.ParseCIDR(subnetCidr)
+		_, subnetNw, err := net.ParseCIDR(subnetCidr)
 		if err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath, subnetCidr, "invalid CIDR format"))
+			continue
 		}
 
 		var found bool
 		for _, vnetNw := range vnetNws {
-			if vnetNw.Contains(subnetCidrIP) {
+			if cidrContains(vnetNw, subnetNw) {
 				found = true
 				break
 			}
@@ -318,6 +685,67 @@ func validateSubnetCIDR(subnetCidrBlocks []string, vnetCidrBlocks []string, fldP
 	return allErrs
 }
 
+// cidrContains returns true if the whole of the inner network is part of the outer network.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// validateSubnetCIDROverlaps validates that the CIDR blocks of different subnets don't overlap. On update, only the
+// subnets which are new or whose CIDR blocks changed are validated, so that overlaps between existing subnets don't
+// block other changes to the cluster.
+func validateSubnetCIDROverlaps(subnets Subnets, oldSubnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	oldCIDRBlocks := make(map[string][]string, len(oldSubnets))
+	for _, subnet := range oldSubnets {
+		oldCIDRBlocks[subnet.Name] = subnet.CIDRBlocks
+	}
+	type subnetCIDR struct {
+		subnet  string
+		cidr    string
+		nw      *net.IPNet
+		changed bool
+	}
+	var seen []subnetCIDR
+	for i, subnet := range subnets {
+		old, ok := oldCIDRBlocks[subnet.Name]
+		changed := !ok || !slices.Equal(old, subnet.CIDRBlocks)
+		for _, cidr := range subnet.CIDRBlocks {
+			_, nw, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+			for _, other := range seen {
+				if other.subnet != subnet.Name && (changed || other.changed) && cidrsOverlap(nw, other.nw) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlocks"), cidr,
+						fmt.Sprintf("subnet CIDR overlaps with CIDR %s of subnet %s", other.cidr, other.subnet)))
+				}
+			}
+			seen = append(seen, subnetCIDR{subnet: subnet.Name, cidr: cidr, nw: nw, changed: changed})
+		}
+	}
+	return allErrs
+}
+
+// validateIPAMPoolRef validates the reference to the IPAM pool a subnet's address space is allocated from.
+func validateIPAMPoolRef(poolRef *corev1.TypedLocalObjectReference, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if poolRef == nil {
+		return allErrs
+	}
+	if poolRef.APIGroup == nil || *poolRef.APIGroup == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiGroup"), "the API group of the IPAM pool is required"))
+	}
+	if poolRef.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), "the kind of the IPAM pool is required"))
+	}
+	if poolRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "the name of the IPAM pool is required"))
+	}
+	return allErrs
+}
+
 // validateVnetCIDR validates the CIDR blocks of a Vnet.
 func validateVnetCIDR(vnetCIDRBlocks []string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -329,6 +757,45 @@ func validateVnetCIDR(vnetCIDRBlocks []string, fldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
+// validateVnetCIDRUpdate validates that CIDR blocks are only appended to the address space of a virtual network,
+// which Azure supports in place, and that the appended blocks don't overlap the other blocks.
+func validateVnetCIDRUpdate(vnetCIDRBlocks, oldVnetCIDRBlocks []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(oldVnetCIDRBlocks) == 0 {
+		return allErrs
+	}
+	existing := make(map[string]bool, len(oldVnetCIDRBlocks))
+	for _, cidr := range oldVnetCIDRBlocks {
+		existing[cidr] = true
+	}
+	for _, cidr := range oldVnetCIDRBlocks {
+		if !slices.Contains(vnetCIDRBlocks, cidr) {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("CIDR block %s can't be removed from the virtual network address space, CIDR blocks can only be added", cidr)))
+		}
+	}
+	for i, cidr := range vnetCIDRBlocks {
+		if existing[cidr] {
+			continue
+		}
+		_, nw, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, "invalid CIDR format"))
+			continue
+		}
+		for j, other := range vnetCIDRBlocks {
+			if j == i || (!existing[other] && j > i) {
+				continue
+			}
+			if _, otherNw, err := net.ParseCIDR(other); err == nil && cidrsOverlap(nw, otherNw) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr,
+					fmt.Sprintf("CIDR block overlaps with CIDR block %s of the virtual network", other)))
+			}
+		}
+	}
+	return allErrs
+}
+
 // validateVnetPeerings validates a list of virtual network peerings.
 func validateVnetPeerings(peerings VnetPeerings, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -427,12 +894,37 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 				allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPConfigs").Index(0).Child("privateIP"),
 					"Public Load Balancers cannot have a Private IP"))
 			}
+			// The DNS name is only validated when it changes, so that clusters with a DNS name generated by older
+			// versions of CAPZ can still be updated.
+			if publicIP := lb.FrontendIPs[0].PublicIP; publicIP != nil && publicIP.DNSName != "" &&
+				(len(old.FrontendIPs) == 0 || old.FrontendIPs[0].PublicIP == nil || old.FrontendIPs[0].PublicIP.DNSName != publicIP.DNSName) {
+				if err := validatePublicIPDNSName(publicIP.DNSName, fldPath.Child("frontendIPConfigs").Index(0).Child("publicIP", "dnsName")); err != nil {
+					allErrs = append(allErrs, err)
+				}
+			}
+		}
+	}
+
+	// The frontend IPs of the other load balancers can't be modified at all after the creation of the cluster.
+	for i, frontendIP := range lb.FrontendIPs {
+		if i < len(old.FrontendIPs) && old.FrontendIPs[i].Name == frontendIP.Name {
+			allErrs = append(allErrs, validateFrontendIPZonesUpdate(frontendIP, old.FrontendIPs[i], fldPath.Child("frontendIPs").Index(i))...)
 		}
 	}
 
 	return allErrs
 }
 
+// validatePublicIPDNSName validates the DNS name of a public IP, which is either a DNS label or a FQDN starting with
+// the DNS label.
+func validatePublicIPDNSName(dnsName string, fldPath *field.Path) *field.Error {
+	label := strings.Split(dnsName, ".")[0]
+	if !publicIPDNSLabelRegex.MatchString(label) {
+		return field.Invalid(fldPath, dnsName, fmt.Sprintf("DNS label %q must be 3 to 63 characters long, start with a lowercase letter, end with a lowercase letter or a digit, and contain only lowercase letters, digits and hyphens. The regex used for validation is %s", label, publicIPDNSLabelRegexPattern))
+	}
+	return nil
+}
+
 func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserverLB LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -494,7 +986,11 @@ func validateControlPlaneOutboundLB(lb *LoadBalancerSpec, apiserverLB LoadBalanc
 
 	allErrs = append(allErrs, validateClassSpecForControlPlaneOutboundLB(lbClassSpec, apiServerLBClassSpec, fldPath)...)
 
-	if apiServerLBClassSpec.Type == Internal && lb != nil {
+	if apiServerLBClassSpec.Type == Internal && lb != nil && lb.Disabled {
+		if lb.FrontendIPsCount != nil || len(lb.FrontendIPs) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPsCount"), "Front end ips cannot be set when the control plane outbound load balancer is disabled."))
+		}
+	} else if apiServerLBClassSpec.Type == Internal && lb != nil {
 		if lb.FrontendIPsCount != nil && *lb.FrontendIPsCount > MaxLoadBalancerOutboundIPs {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
 				fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
@@ -523,6 +1019,54 @@ func validatePrivateDNSZoneName(privateDNSZoneName string, apiserverLBType LBTyp
 	return allErrs
 }
 
+// validatePrivateDNSZone validates the PrivateDNSZone.
+func validatePrivateDNSZone(privateDNSZone *PrivateDNSZone, apiserverLBType LBType, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if privateDNSZone == nil || len(privateDNSZone.AdditionalVnetLinks) == 0 {
+		return allErrs
+	}
+
+	if apiserverLBType != Internal {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalVnetLinks"), apiserverLBType,
+			"AdditionalVnetLinks is available only if APIServerLB.Type is Internal"))
+	}
+
+	vnetIDs := make(map[string]bool, len(privateDNSZone.AdditionalVnetLinks))
+	for i, vnetID := range privateDNSZone.AdditionalVnetLinks {
+		idPath := fldPath.Child("additionalVnetLinks").Index(i)
+		id, err := arm.ParseResourceID(vnetID)
+		if err != nil || !strings.EqualFold(id.ResourceType.String(), virtualNetworkResourceType) {
+			allErrs = append(allErrs, field.Invalid(idPath, vnetID,
+				"must be a valid Azure resource ID of a virtual network, e.g. /subscriptions/<subscription ID>/resourceGroups/<resource group>/providers/Microsoft.Network/virtualNetworks/<name>"))
+			continue
+		}
+		if vnetIDs[strings.ToLower(vnetID)] {
+			allErrs = append(allErrs, field.Duplicate(idPath, vnetID))
+		}
+		vnetIDs[strings.ToLower(vnetID)] = true
+	}
+
+	return allErrs
+}
+
+// validateLoadBalancerHealthProbe validates the health probe of a load balancer.
+func validateLoadBalancerHealthProbe(probe *LoadBalancerHealthProbe, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if probe == nil {
+		return allErrs
+	}
+
+	if probe.Protocol == ProbeProtocolTCP && probe.RequestPath != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("requestPath"), "request path can only be set for Https health probes"))
+	}
+	if probe.RequestPath != "" && !strings.HasPrefix(probe.RequestPath, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestPath"), probe.RequestPath, "request path should start with /"))
+	}
+
+	return allErrs
+}
+
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
 func validateCloudProviderConfigOverrides(oldConfig, newConfig *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -553,7 +1097,9 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 
 	// Type should be immutable.
 	if old != nil && old.Type != "" && old.Type != lb.Type {
-		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("type"), "API Server load balancer type should not be modified after AzureCluster creation."))
+		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("type"),
+			fmt.Sprintf("API Server load balancer type should not be modified after AzureCluster creation, except from Public to Internal once the %s annotation is set and status.apiServerLBMigration.phase is %s.",
+				MigrateAPIServerLBToInternalAnnotation, APIServerLBMigrationPending)))
 	}
 
 	// IdletimeoutInMinutes should be immutable.
@@ -566,6 +1112,35 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 			fmt.Sprintf("Node outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 	}
 
+	if lb.Disabled {
+		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("disabled"), "API Server load balancer cannot be disabled."))
+	}
+
+	allErrs = append(allErrs, validateLoadBalancerHealthProbe(lb.HealthProbe, apiServerLBPath.Child("healthProbe"))...)
+	allErrs = append(allErrs, validateAdditionalAPIServerLBPorts(lb.AdditionalAPIServerLBPorts, apiServerLBPath.Child("additionalAPIServerLBPorts"))...)
+
+	return allErrs
+}
+
+// validateAdditionalAPIServerLBPorts validates the additional ports of the API server load balancer.
+func validateAdditionalAPIServerLBPorts(ports []LoadBalancerPort, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool, len(ports))
+	frontendPorts := make(map[int32]bool, len(ports))
+	for i, port := range ports {
+		if port.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("name"), "name is required for all additional ports"))
+		} else if names[strings.ToLower(port.Name)] {
+			// Azure resource names are case-insensitive.
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), port.Name))
+		}
+		names[strings.ToLower(port.Name)] = true
+
+		if frontendPorts[port.Port] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("port"), port.Port))
+		}
+		frontendPorts[port.Port] = true
+	}
 	return allErrs
 }
 
@@ -599,9 +1174,42 @@ func validateClassSpecForNodeOutboundLB(lb *LoadBalancerClassSpec, old *LoadBala
 			fmt.Sprintf("Node outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 	}
 
+	if lb.HealthProbe != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthProbe"), "Node outbound load balancer health probe cannot be set."))
+	}
+
+	if len(lb.AdditionalAPIServerLBPorts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalAPIServerLBPorts"), "Node outbound load balancer additional API server ports cannot be set."))
+	}
+
+	if lb.Disabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("disabled"), "Node outbound load balancer cannot be disabled, it can be omitted for private clusters instead."))
+	}
+
 	return allErrs
 }
 
+// validateControlPlaneEgress validates that the control plane machines of a cluster whose control plane outbound load
+// balancer is disabled have egress through a NAT gateway or user-defined routes on the control plane subnet.
+func validateControlPlaneEgress(networkSpec NetworkSpec, fldPath *field.Path) field.ErrorList {
+	lb := networkSpec.ControlPlaneOutboundLB
+	if networkSpec.APIServerLB.Type != Internal || lb == nil || !lb.Disabled {
+		return nil
+	}
+
+	subnet, err := networkSpec.GetControlPlaneSubnet()
+	if err != nil {
+		// The missing control plane subnet is reported by the subnets validation.
+		return nil
+	}
+	if subnet.IsNatGatewayEnabled() || subnet.RouteTable.Name != "" || subnet.RouteTable.ID != "" {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(fldPath, lb.Disabled,
+		fmt.Sprintf("Control plane outbound load balancer can only be disabled when the control plane subnet %q has a NAT gateway or a route table for egress", subnet.Name))}
+}
+
 func validateClassSpecForControlPlaneOutboundLB(lb *LoadBalancerClassSpec, apiserverLB LoadBalancerClassSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -620,6 +1228,14 @@ func validateClassSpecForControlPlaneOutboundLB(lb *LoadBalancerClassSpec, apise
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
 				fmt.Sprintf("Control plane outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
 		}
+
+		if lb.HealthProbe != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthProbe"), "Control plane outbound load balancer health probe cannot be set."))
+		}
+
+		if len(lb.AdditionalAPIServerLBPorts) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalAPIServerLBPorts"), "Control plane outbound load balancer additional API server ports cannot be set."))
+		}
 	}
 
 	return allErrs
@@ -753,3 +1369,26 @@ func validatePrivateEndpointIPAddress(address string, cidrs []string, fldPath *f
 	return field.Invalid(fldPath, address,
 		fmt.Sprintf("Private Endpoint IP address needs to be in subnet range (%s)", cidrs))
 }
+
+// validateDeletionPolicyUpdate validates that a cluster's deletion policy is only changed to Retain when the
+// change is confirmed with the RetainResourcesConfirmationAnnotation.
+func validateDeletionPolicyUpdate(oldPolicy, newPolicy DeletionPolicy, annotations map[string]string, fldPath *field.Path) *field.Error {
+	if newPolicy != DeletionPolicyRetain || oldPolicy == DeletionPolicyRetain {
+		return nil
+	}
+	if annotations[RetainResourcesConfirmationAnnotation] != "true" {
+		return field.Forbidden(fldPath, fmt.Sprintf("changing the deletion policy of an existing cluster to %s requires the %s annotation to be set to \"true\"",
+			DeletionPolicyRetain, RetainResourcesConfirmationAnnotation))
+	}
+	return nil
+}
+
+// validateLocationUpdate validates that a cluster's location isn't changed once set, since its resource group and
+// the resources in it already exist in that location and can't be moved by CAPZ.
+func validateLocationUpdate(oldLocation, newLocation string, fldPath *field.Path) *field.Error {
+	if oldLocation == "" || oldLocation == newLocation {
+		return nil
+	}
+	return field.Forbidden(fldPath, fmt.Sprintf("the location of an existing cluster can't be changed from %s to %s: its resource group and resources already exist in %s; create a new cluster in %s instead",
+		oldLocation, newLocation, oldLocation, newLocation))
+}
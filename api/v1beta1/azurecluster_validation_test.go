@@ -690,6 +690,120 @@ func TestSubnetNameInvalid(t *testing.T) {
 	})
 }
 
+func TestValidateSubnetsCIDRAllocation(t *testing.T) {
+	subnet := func(name string, role SubnetRole, cidrBlocks ...string) SubnetSpec {
+		return SubnetSpec{SubnetClassSpec: SubnetClassSpec{Name: name, Role: role, CIDRBlocks: cidrBlocks}}
+	}
+	tests := []struct {
+		name        string
+		vnet        VnetSpec
+		subnets     Subnets
+		expectedErr *field.Error
+	}{
+		{
+			name:    "non-overlapping subnets",
+			vnet:    VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/16"}}},
+			subnets: Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.1.0/24")},
+		},
+		{
+			name:    "subnet without cidr blocks fits in the vnet",
+			vnet:    VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/24"}}},
+			subnets: Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/25"), subnet("node", SubnetNode)},
+		},
+		{
+			name:    "subnet without cidr blocks doesn't fit in the vnet",
+			vnet:    VnetSpec{VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/29"}}},
+			subnets: Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/29"), subnet("node", SubnetNode)},
+			expectedErr: &field.Error{
+				Type:     field.ErrorTypeRequired,
+				Field:    "spec.networkSpec.subnets[1].cidrBlocks",
+				BadValue: "",
+				Detail:   "no free /29 block left in vnet address space [10.0.0.0/29], specify the subnet CIDR blocks or a longer vnet subnetPrefixLength",
+			},
+		},
+		{
+			name: "subnet prefix length shorter than the vnet prefix",
+			vnet: VnetSpec{
+				VnetClassSpec: VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/24"}, SubnetPrefixLength: ptr.To[int32](16)},
+			},
+			subnets: Subnets{subnet("cp", SubnetControlPlane), subnet("node", SubnetNode)},
+			expectedErr: &field.Error{
+				Type:     field.ErrorTypeRequired,
+				Field:    "spec.networkSpec.subnets[0].cidrBlocks",
+				BadValue: "",
+				Detail:   "no free /16 block left in vnet address space [10.0.0.0/24], specify the subnet CIDR blocks or a longer vnet subnetPrefixLength",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateSubnets(tc.subnets, tc.vnet, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if tc.expectedErr != nil {
+				g.Expect(errs).To(ContainElement(MatchError(tc.expectedErr.Error())))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateSubnetCIDROverlaps(t *testing.T) {
+	subnet := func(name string, role SubnetRole, cidrBlocks ...string) SubnetSpec {
+		return SubnetSpec{SubnetClassSpec: SubnetClassSpec{Name: name, Role: role, CIDRBlocks: cidrBlocks}}
+	}
+	overlapErr := &field.Error{
+		Type:     field.ErrorTypeInvalid,
+		Field:    "spec.networkSpec.subnets[1].cidrBlocks",
+		BadValue: "10.0.0.128/25",
+		Detail:   "subnet CIDR overlaps with CIDR 10.0.0.0/24 of subnet cp",
+	}
+	tests := []struct {
+		name        string
+		subnets     Subnets
+		oldSubnets  Subnets
+		expectedErr *field.Error
+	}{
+		{
+			name:    "non-overlapping subnets",
+			subnets: Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.1.0/24")},
+		},
+		{
+			name:        "overlapping subnets on create",
+			subnets:     Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.0.128/25")},
+			expectedErr: overlapErr,
+		},
+		{
+			name:       "existing overlapping subnets on update",
+			subnets:    Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.0.128/25")},
+			oldSubnets: Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.0.128/25")},
+		},
+		{
+			name:        "changed subnet overlapping an existing subnet on update",
+			subnets:     Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.0.128/25")},
+			oldSubnets:  Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.1.0/24")},
+			expectedErr: overlapErr,
+		},
+		{
+			name:        "new subnet overlapping an existing subnet on update",
+			subnets:     Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24"), subnet("node", SubnetNode, "10.0.0.128/25")},
+			oldSubnets:  Subnets{subnet("cp", SubnetControlPlane, "10.0.0.0/24")},
+			expectedErr: overlapErr,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateSubnetCIDROverlaps(tc.subnets, tc.oldSubnets, field.NewPath("spec").Child("networkSpec").Child("subnets"))
+			if tc.expectedErr != nil {
+				g.Expect(errs).To(ConsistOf(MatchError(tc.expectedErr.Error())))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestValidateSubnetCIDR(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -728,6 +842,18 @@ func TestValidateSubnetCIDR(t *testing.T) {
 				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/8]",
 			},
 		},
+		{
+			name:             "subnet cidr starts in vnet range but is larger than the vnet",
+			vnetCidrBlocks:   []string{"10.0.0.0/16"},
+			subnetCidrBlocks: []string{"10.0.0.0/8"},
+			wantErr:          true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets.cidrBlocks",
+				BadValue: "10.0.0.0/8",
+				Detail:   "subnet CIDR not in vnet address space: [10.0.0.0/16]",
+			},
+		},
 		{
 			name:             "subnet cidr in at least one vnet's range in case of multiple vnet cidr blocks",
 			vnetCidrBlocks:   []string{"10.0.0.0/8", "11.0.0.0/8"},
@@ -1027,6 +1153,93 @@ func TestValidateAPIServerLB(t *testing.T) {
 			cpCIDRS: []string{"10.0.0.0/24", "10.1.0.0/24"},
 			wantErr: false,
 		},
+		{
+			name: "public IP with a valid DNS name",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-config",
+						PublicIP: &PublicIPSpec{Name: "my-public-ip", DNSName: "my-cluster-api.westus2.cloudapp.azure.com"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public IP with a valid DNS label",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-config",
+						PublicIP: &PublicIPSpec{Name: "my-public-ip", DNSName: "my-cluster-api"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "public IP with an invalid DNS label",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-config",
+						PublicIP: &PublicIPSpec{Name: "my-public-ip", DNSName: "1-cluster-api.westus2.cloudapp.azure.com"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.frontendIPConfigs[0].publicIP.dnsName",
+				BadValue: "1-cluster-api.westus2.cloudapp.azure.com",
+				Detail:   "DNS label \"1-cluster-api\" must be 3 to 63 characters long, start with a lowercase letter, end with a lowercase letter or a digit, and contain only lowercase letters, digits and hyphens. The regex used for validation is ^[a-z][a-z0-9-]{1,61}[a-z0-9]$",
+			},
+		},
+		{
+			name: "public IP with an unchanged invalid DNS label",
+			lb: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-config",
+						PublicIP: &PublicIPSpec{Name: "my-public-ip", DNSName: "1-cluster-abc.westus2.cloudapp.azure.com"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			old: LoadBalancerSpec{
+				Name: "my-public-lb",
+				FrontendIPs: []FrontendIP{
+					{
+						Name:     "ip-config",
+						PublicIP: &PublicIPSpec{Name: "my-public-ip", DNSName: "1-cluster-abc.westus2.cloudapp.azure.com"},
+					},
+				},
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Public,
+					SKU:  SKUStandard,
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, test := range testcases {
@@ -1124,6 +1337,101 @@ func TestPrivateDNSZoneName(t *testing.T) {
 	}
 }
 
+func TestPrivateDNSZone(t *testing.T) {
+	vnetID := "/subscriptions/123/resourceGroups/mgmt-rg/providers/Microsoft.Network/virtualNetworks/mgmt-vnet"
+	testcases := []struct {
+		name        string
+		network     NetworkSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name: "valid additional vnet links",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZone: &PrivateDNSZone{
+						AdditionalVnetLinks: []string{vnetID},
+					},
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "additional vnet link is not a virtual network ID",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZone: &PrivateDNSZone{
+						AdditionalVnetLinks: []string{"/subscriptions/123/resourceGroups/mgmt-rg/providers/Microsoft.Network/publicIPAddresses/my-ip"},
+					},
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZone.additionalVnetLinks[0]",
+				BadValue: "/subscriptions/123/resourceGroups/mgmt-rg/providers/Microsoft.Network/publicIPAddresses/my-ip",
+				Detail:   "must be a valid Azure resource ID of a virtual network, e.g. /subscriptions/<subscription ID>/resourceGroups/<resource group>/providers/Microsoft.Network/virtualNetworks/<name>",
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate additional vnet links",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZone: &PrivateDNSZone{
+						AdditionalVnetLinks: []string{vnetID, vnetID},
+					},
+				},
+				APIServerLB: createValidAPIServerInternalLB(),
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "spec.networkSpec.privateDNSZone.additionalVnetLinks[1]",
+				BadValue: vnetID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional vnet links with a public API server",
+			network: NetworkSpec{
+				NetworkClassSpec: NetworkClassSpec{
+					PrivateDNSZone: &PrivateDNSZone{
+						AdditionalVnetLinks: []string{vnetID},
+					},
+				},
+				APIServerLB: LoadBalancerSpec{
+					Name: "my-lb",
+					LoadBalancerClassSpec: LoadBalancerClassSpec{
+						Type: Public,
+					},
+				},
+			},
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.networkSpec.privateDNSZone.additionalVnetLinks",
+				BadValue: "Public",
+				Detail:   "AdditionalVnetLinks is available only if APIServerLB.Type is Internal",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			err := validatePrivateDNSZone(test.network.PrivateDNSZone, test.network.APIServerLB.Type, field.NewPath("spec", "networkSpec", "privateDNSZone"))
+			if test.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(test.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestValidateNodeOutboundLB(t *testing.T) {
 	testcases := []struct {
 		name        string
@@ -1344,6 +1652,24 @@ func TestValidateControlPlaneNodeOutboundLB(t *testing.T) {
 				Detail:   "Max front end ips allowed is 16",
 			},
 		},
+		{
+			name: "frontend ips count cannot be set when cp outbound lb is disabled",
+			lb: &LoadBalancerSpec{
+				FrontendIPsCount:      ptr.To[int32](1),
+				LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true},
+			},
+			apiServerLB: LoadBalancerSpec{
+				LoadBalancerClassSpec: LoadBalancerClassSpec{
+					Type: Internal,
+				},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:   "FieldValueForbidden",
+				Field:  "controlPlaneOutboundLB.frontendIPsCount",
+				Detail: "Front end ips cannot be set when the control plane outbound load balancer is disabled.",
+			},
+		},
 	}
 
 	for _, test := range testcases {
@@ -1550,6 +1876,14 @@ func createValidSubnets() Subnets {
 	}
 }
 
+func createValidIPAMPoolRef() *corev1.TypedLocalObjectReference {
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+		Kind:     "InClusterIPPool",
+		Name:     "node-subnets",
+	}
+}
+
 func createValidVnet() VnetSpec {
 	return VnetSpec{
 		ResourceGroup: "custom-vnet",
@@ -1681,21 +2015,692 @@ func TestValidateServiceEndpoints(t *testing.T) {
 	}
 }
 
-func TestServiceEndpointsLackRequiredFieldService(t *testing.T) {
-	type test struct {
-		name             string
-		serviceEndpoints ServiceEndpoints
+func TestValidateNatGateway(t *testing.T) {
+	tests := []struct {
+		name        string
+		natGateway  NatGatewayClassSpec
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name:       "no zone or idle timeout",
+			natGateway: NatGatewayClassSpec{Name: "natgw"},
+			wantErr:    false,
+		},
+		{
+			name: "valid zone and idle timeout",
+			natGateway: NatGatewayClassSpec{
+				Name:                 "natgw",
+				Zone:                 ptr.To("3"),
+				IdleTimeoutInMinutes: ptr.To[int32](120),
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple zones",
+			natGateway: NatGatewayClassSpec{
+				Name: "natgw",
+				Zone: ptr.To("1,2"),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets[0].natGateway.zone",
+				BadValue: "1,2",
+				Detail:   "NAT gateways can't be zone-redundant, zone must be a single availability zone such as \"1\"",
+			},
+		},
+		{
+			name: "idle timeout too long",
+			natGateway: NatGatewayClassSpec{
+				Name:                 "natgw",
+				IdleTimeoutInMinutes: ptr.To[int32](121),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "subnets[0].natGateway.idleTimeoutInMinutes",
+				BadValue: 121,
+				Detail:   "NAT gateway idle timeout should be between 4 and 120 minutes",
+			},
+		},
 	}
-
-	testCase := test{
-		name: "service endpoint missing service name",
-		serviceEndpoints: []ServiceEndpointSpec{{
-			Locations: []string{"*"},
-		}},
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateNatGateway(testCase.natGateway, field.NewPath("subnets[0].natGateway"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
 	}
+}
 
-	t.Run(testCase.name, func(t *testing.T) {
-		g := NewWithT(t)
+func TestIgnoredFieldWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		cluster      func() *AzureCluster
+		old          func() *AzureCluster
+		wantWarnings []string
+	}{
+		{
+			name:    "no ignored fields",
+			cluster: createValidCluster,
+		},
+		{
+			name: "NAT gateway on the control plane subnet",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[0].Role = SubnetControlPlane
+				c.Spec.NetworkSpec.Subnets[0].NatGateway.Name = "natgw"
+				return c
+			},
+			wantWarnings: []string{"spec.networkSpec.subnets[0].natGateway is ignored, the control plane subnet uses the API server load balancer for outbound traffic"},
+		},
+		{
+			name: "NAT gateway public IP without NAT gateway",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[1].Role = SubnetNode
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Name = "natgw-ip"
+				return c
+			},
+			wantWarnings: []string{"spec.networkSpec.subnets[1].natGateway.ip is ignored without spec.networkSpec.subnets[1].natGateway.name, set it to create the NAT gateway"},
+		},
+		{
+			name: "ignored fields already set on the old cluster",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[1].Role = SubnetNode
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Name = "natgw-ip"
+				return c
+			},
+			old: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[1].Role = SubnetNode
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Name = "natgw-ip"
+				return c
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var old *AzureCluster
+			if tc.old != nil {
+				old = tc.old()
+			}
+			warnings := tc.cluster().ignoredFieldWarnings(old)
+			if tc.wantWarnings == nil {
+				g.Expect(warnings).To(BeEmpty())
+			} else {
+				g.Expect(warnings).To(ConsistOf(tc.wantWarnings))
+			}
+		})
+	}
+}
+
+func TestValidateIPZones(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster func() *AzureCluster
+		wantErr string
+	}{
+		{
+			name:    "no zones",
+			cluster: createValidCluster,
+		},
+		{
+			name: "zonal public IP",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"2"}
+				return c
+			},
+		},
+		{
+			name: "zone-redundant public IP",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"1", "2", "3"}
+				return c
+			},
+		},
+		{
+			name: "invalid public IP zone",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"eastus-1"}
+				return c
+			},
+			wantErr: "spec.networkSpec.apiServerLB.frontendIPs[0].publicIP.zones[0]: Invalid value",
+		},
+		{
+			name: "duplicate public IP zone",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"1", "1"}
+				return c
+			},
+			wantErr: "spec.networkSpec.apiServerLB.frontendIPs[0].publicIP.zones[1]: Duplicate value",
+		},
+		{
+			name: "zones on a public frontend IP",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].Zones = []string{"1"}
+				return c
+			},
+			wantErr: "spec.networkSpec.apiServerLB.frontendIPs[0].zones: Forbidden",
+		},
+		{
+			name: "zones on a private frontend IP",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				c.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].Zones = []string{"1"}
+				return c
+			},
+		},
+		{
+			name: "NAT gateway public IP in the zone of the NAT gateway",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.Zone = ptr.To("2")
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Zones = []string{"2"}
+				return c
+			},
+		},
+		{
+			name: "NAT gateway public IP in another zone than the NAT gateway",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.Zone = ptr.To("2")
+				c.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Zones = []string{"1", "2", "3"}
+				return c
+			},
+			wantErr: "spec.networkSpec.subnets[1].natGateway.ip.zones: Invalid value",
+		},
+		{
+			name: "invalid Azure Bastion public IP zone",
+			cluster: func() *AzureCluster {
+				c := createValidCluster()
+				c.Spec.BastionSpec.AzureBastion = &AzureBastion{PublicIP: PublicIPSpec{Zones: []string{""}}}
+				return c
+			},
+			wantErr: "spec.bastionSpec.azureBastion.publicIP.zones[0]: Invalid value",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := tc.cluster().validateIPZones()
+			if tc.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateIPAMPoolRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		poolRef     *corev1.TypedLocalObjectReference
+		expectedErr string
+	}{
+		{
+			name: "no IPAM pool",
+		},
+		{
+			name:    "valid IPAM pool",
+			poolRef: createValidIPAMPoolRef(),
+		},
+		{
+			name: "IPAM pool without API group",
+			poolRef: &corev1.TypedLocalObjectReference{
+				Kind: "InClusterIPPool",
+				Name: "node-subnets",
+			},
+			expectedErr: "subnets[0].ipamPoolRef.apiGroup: Required value: the API group of the IPAM pool is required",
+		},
+		{
+			name: "IPAM pool without kind",
+			poolRef: &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+				Name:     "node-subnets",
+			},
+			expectedErr: "subnets[0].ipamPoolRef.kind: Required value: the kind of the IPAM pool is required",
+		},
+		{
+			name: "IPAM pool without name",
+			poolRef: &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+				Kind:     "InClusterIPPool",
+			},
+			expectedErr: "subnets[0].ipamPoolRef.name: Required value: the name of the IPAM pool is required",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateIPAMPoolRef(testCase.poolRef, field.NewPath("subnets[0].ipamPoolRef"))
+			if testCase.expectedErr != "" {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr)))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerDNS(t *testing.T) {
+	zoneID := "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+	tests := []struct {
+		name         string
+		apiServerDNS *APIServerDNS
+		expectedErr  string
+	}{
+		{
+			name: "no API server DNS",
+		},
+		{
+			name: "valid API server DNS",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: zoneID,
+				RecordName:     "api.cluster1",
+				TTL:            ptr.To[int32](60),
+			},
+		},
+		{
+			name: "zone in another resource group and subscription with a mixed case name",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: "/subscriptions/456/resourceGroups/other/providers/Microsoft.Network/dnsZones/Example.com",
+				RecordName:     "api",
+			},
+		},
+		{
+			name: "invalid zone resource ID",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: "example.com",
+				RecordName:     "api",
+			},
+			expectedErr: "apiServerDNS.zoneResourceID: Invalid value: \"example.com\": must be a valid Azure resource ID of a DNS zone",
+		},
+		{
+			name: "resource ID of a private DNS zone",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/privateDnsZones/example.com",
+				RecordName:     "api",
+			},
+			expectedErr: "apiServerDNS.zoneResourceID: Invalid value",
+		},
+		{
+			name: "missing record name",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: zoneID,
+			},
+			expectedErr: "apiServerDNS.recordName: Required value: the name of the record is required",
+		},
+		{
+			name: "invalid record name",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: zoneID,
+				RecordName:     "api_server",
+			},
+			expectedErr: "apiServerDNS.recordName: Invalid value: \"api_server\"",
+		},
+		{
+			name: "invalid TTL",
+			apiServerDNS: &APIServerDNS{
+				ZoneResourceID: zoneID,
+				RecordName:     "api",
+				TTL:            ptr.To[int32](0),
+			},
+			expectedErr: "apiServerDNS.ttl: Invalid value: 0: must be at least 1",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateAPIServerDNS(testCase.apiServerDNS, field.NewPath("apiServerDNS"))
+			if testCase.expectedErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Error()).To(ContainSubstring(testCase.expectedErr))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateDefaultImage(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultImage *DefaultImage
+		wantErr      bool
+		expectedErr  field.Error
+	}{
+		{
+			name:         "no default image",
+			defaultImage: nil,
+			wantErr:      false,
+		},
+		{
+			name: "community gallery image",
+			defaultImage: &DefaultImage{
+				Gallery: "ClusterAPI-f72ceb4f-5159-4c26-a0fe-2ea738f0d019",
+				Name:    "capi-ubun2-2204",
+			},
+			wantErr: false,
+		},
+		{
+			name: "private gallery image",
+			defaultImage: &DefaultImage{
+				Gallery:        "my-gallery",
+				Name:           "capi-ubun2-2204",
+				SubscriptionID: ptr.To("123"),
+				ResourceGroup:  ptr.To("my-rg"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "subscription ID without resource group",
+			defaultImage: &DefaultImage{
+				Gallery:        "my-gallery",
+				Name:           "capi-ubun2-2204",
+				SubscriptionID: ptr.To("123"),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.defaultImage.resourceGroup",
+				BadValue: "",
+				Detail:   "ResourceGroup cannot be empty when SubscriptionID is specified",
+			},
+		},
+		{
+			name: "resource group without subscription ID",
+			defaultImage: &DefaultImage{
+				Gallery:       "my-gallery",
+				Name:          "capi-ubun2-2204",
+				ResourceGroup: ptr.To("my-rg"),
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "spec.defaultImage.subscriptionID",
+				BadValue: "",
+				Detail:   "SubscriptionID cannot be empty when ResourceGroup is specified",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateDefaultImage(testCase.defaultImage, field.NewPath("spec", "defaultImage"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateLoadBalancerHealthProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		probe       *LoadBalancerHealthProbe
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name:    "no health probe",
+			probe:   nil,
+			wantErr: false,
+		},
+		{
+			name: "Https health probe with request path",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:          ProbeProtocolHTTPS,
+				Port:              ptr.To[int32](443),
+				RequestPath:       "/livez",
+				IntervalInSeconds: ptr.To[int32](5),
+				NumberOfProbes:    ptr.To[int32](2),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tcp health probe",
+			probe: &LoadBalancerHealthProbe{
+				Protocol: ProbeProtocolTCP,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tcp health probe with request path",
+			probe: &LoadBalancerHealthProbe{
+				Protocol:    ProbeProtocolTCP,
+				RequestPath: "/readyz",
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueForbidden",
+				Field:    "apiServerLB.healthProbe.requestPath",
+				BadValue: "",
+				Detail:   "request path can only be set for Https health probes",
+			},
+		},
+		{
+			name: "relative request path",
+			probe: &LoadBalancerHealthProbe{
+				RequestPath: "readyz",
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueInvalid",
+				Field:    "apiServerLB.healthProbe.requestPath",
+				BadValue: "readyz",
+				Detail:   "request path should start with /",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateLoadBalancerHealthProbe(testCase.probe, field.NewPath("apiServerLB", "healthProbe"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateControlPlaneEgress(t *testing.T) {
+	fldPath := field.NewPath("spec", "networkSpec", "controlPlaneOutboundLB", "disabled")
+
+	testcases := []struct {
+		name       string
+		lb         *LoadBalancerSpec
+		natGateway string
+		routeTable string
+		wantErr    bool
+	}{
+		{
+			name: "egress through the control plane outbound lb",
+			lb:   &LoadBalancerSpec{Name: "cp-outbound-lb", FrontendIPsCount: ptr.To[int32](2)},
+		},
+		{
+			name:       "egress through a NAT gateway on the control plane subnet",
+			lb:         &LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true}},
+			natGateway: "cp-natgw",
+		},
+		{
+			name:       "egress through user-defined routes on the control plane subnet",
+			lb:         &LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true}},
+			routeTable: "cp-udr",
+		},
+		{
+			name:    "disabled cp outbound lb without a NAT gateway or route table",
+			lb:      &LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Disabled: true}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			cluster := createValidCluster()
+			cluster.Spec.NetworkSpec.APIServerLB.Type = Internal
+			cluster.Spec.NetworkSpec.ControlPlaneOutboundLB = test.lb
+			cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Name = test.natGateway
+			cluster.Spec.NetworkSpec.Subnets[0].RouteTable.Name = test.routeTable
+
+			errs := validateControlPlaneEgress(cluster.Spec.NetworkSpec, fldPath)
+			if test.wantErr {
+				g.Expect(errs).To(ConsistOf(MatchError(field.Invalid(fldPath, true,
+					`Control plane outbound load balancer can only be disabled when the control plane subnet "control-plane-subnet" has a NAT gateway or a route table for egress`).Error())))
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerLBCannotBeDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	lb := createValidAPIServerLB()
+	lb.Disabled = true
+	g.Expect(validateClassSpecForAPIServerLB(lb.LoadBalancerClassSpec, nil, field.NewPath("apiServerLB"))).To(ContainElement(MatchError(
+		field.Forbidden(field.NewPath("apiServerLB", "disabled"), "API Server load balancer cannot be disabled.").Error())))
+}
+
+func TestValidateAPIServerLBPortsHealthProbePort(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := createValidCluster()
+	cluster.Spec.NetworkSpec.APIServerLB.HealthProbe = &LoadBalancerHealthProbe{Port: ptr.To[int32](6443)}
+	g.Expect(cluster.validateAPIServerLBPorts(6443)).To(BeEmpty())
+
+	cluster.Spec.NetworkSpec.APIServerLB.HealthProbe.Port = ptr.To[int32](8443)
+	g.Expect(cluster.validateAPIServerLBPorts(6443)).To(ContainElement(MatchError(field.Invalid(
+		field.NewPath("spec", "networkSpec", "apiServerLB", "healthProbe", "port"), int32(8443),
+		"API Server load balancer health probe port should match the API server port 6443").Error())))
+}
+
+func TestValidateAdditionalAPIServerLBPorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		ports       []LoadBalancerPort
+		wantErr     bool
+		expectedErr field.Error
+	}{
+		{
+			name:    "no additional ports",
+			ports:   nil,
+			wantErr: false,
+		},
+		{
+			name: "multiple additional ports",
+			ports: []LoadBalancerPort{
+				{Name: "konnectivity", Port: 8132},
+				{Name: "metrics", Port: 9443, TargetPort: ptr.To[int32](10250)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing name",
+			ports: []LoadBalancerPort{
+				{Port: 8132},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueRequired",
+				Field:    "apiServerLB.additionalAPIServerLBPorts[0].name",
+				BadValue: "",
+				Detail:   "name is required for all additional ports",
+			},
+		},
+		{
+			name: "duplicate names",
+			ports: []LoadBalancerPort{
+				{Name: "konnectivity", Port: 8132},
+				{Name: "Konnectivity", Port: 8133},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "apiServerLB.additionalAPIServerLBPorts[1].name",
+				BadValue: "Konnectivity",
+			},
+		},
+		{
+			name: "duplicate ports",
+			ports: []LoadBalancerPort{
+				{Name: "konnectivity", Port: 8132},
+				{Name: "other", Port: 8132},
+			},
+			wantErr: true,
+			expectedErr: field.Error{
+				Type:     "FieldValueDuplicate",
+				Field:    "apiServerLB.additionalAPIServerLBPorts[1].port",
+				BadValue: int32(8132),
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateAdditionalAPIServerLBPorts(testCase.ports, field.NewPath("apiServerLB", "additionalAPIServerLBPorts"))
+			if testCase.wantErr {
+				g.Expect(err).To(ContainElement(MatchError(testCase.expectedErr.Error())))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerLBPortsAdditionalPorts(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := createValidCluster()
+	cluster.Spec.NetworkSpec.APIServerLB.AdditionalAPIServerLBPorts = []LoadBalancerPort{{Name: "konnectivity", Port: 8132}}
+	g.Expect(cluster.validateAPIServerLBPorts(6443)).To(BeEmpty())
+
+	cluster.Spec.NetworkSpec.APIServerLB.AdditionalAPIServerLBPorts[0].Port = 6443
+	g.Expect(cluster.validateAPIServerLBPorts(6443)).To(ContainElement(MatchError(field.Invalid(
+		field.NewPath("spec", "networkSpec", "apiServerLB", "additionalAPIServerLBPorts").Index(0).Child("port"), int32(6443),
+		"additional API Server load balancer port should not be the API server port 6443").Error())))
+}
+
+func TestServiceEndpointsLackRequiredFieldService(t *testing.T) {
+	type test struct {
+		name             string
+		serviceEndpoints ServiceEndpoints
+	}
+
+	testCase := test{
+		name: "service endpoint missing service name",
+		serviceEndpoints: []ServiceEndpointSpec{{
+			Locations: []string{"*"},
+		}},
+	}
+
+	t.Run(testCase.name, func(t *testing.T) {
+		g := NewWithT(t)
 		errs := validateServiceEndpoints(testCase.serviceEndpoints, field.NewPath("subnets[0].serviceEndpoints"))
 		g.Expect(errs).To(HaveLen(1))
 		g.Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
@@ -1749,3 +2754,109 @@ func TestClusterWithExtendedLocationInvalid(t *testing.T) {
 		g.Expect(err).NotTo(BeNil())
 	})
 }
+
+func TestValidateLocationUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldLocation string
+		newLocation string
+		wantErr     bool
+	}{
+		{
+			name:        "location is set",
+			oldLocation: "",
+			newLocation: "westeurope",
+			wantErr:     false,
+		},
+		{
+			name:        "location is unchanged",
+			oldLocation: "westeurope",
+			newLocation: "westeurope",
+			wantErr:     false,
+		},
+		{
+			name:        "location is changed",
+			oldLocation: "westeurope",
+			newLocation: "eastus",
+			wantErr:     true,
+		},
+		{
+			name:        "location is unset",
+			oldLocation: "westeurope",
+			newLocation: "",
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validateLocationUpdate(tc.oldLocation, tc.newLocation, field.NewPath("spec", "location"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(err.Type).To(Equal(field.ErrorTypeForbidden))
+				g.Expect(err.Field).To(Equal("spec.location"))
+				g.Expect(err.Error()).To(ContainSubstring("create a new cluster in"))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateClusterVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+	fldPath := field.NewPath("spec", "vmExtensions")
+
+	extensions := []ClusterVMExtension{
+		{VMExtension: VMExtension{Name: "AzureMonitorLinuxAgent"}, OSType: "Linux"},
+		{VMExtension: VMExtension{Name: "AzureMonitorWindowsAgent"}, OSType: "Windows"},
+	}
+	g.Expect(validateClusterVMExtensions(extensions, fldPath)).To(BeEmpty())
+
+	extensions = append(extensions, ClusterVMExtension{VMExtension: VMExtension{Name: "AzureMonitorLinuxAgent"}})
+	g.Expect(validateClusterVMExtensions(extensions, fldPath)).To(ConsistOf(
+		field.Duplicate(fldPath.Index(2).Child("name"), "AzureMonitorLinuxAgent"),
+	))
+}
+
+func TestValidateBastionSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		azureBastion *AzureBastion
+		wantErrs     []string
+	}{
+		{
+			name: "no bastion",
+		},
+		{
+			name:         "basic bastion",
+			azureBastion: &AzureBastion{Sku: BasicBastionHostSku, Subnet: SubnetSpec{SubnetClassSpec: SubnetClassSpec{Name: "AzureBastionSubnet"}}},
+		},
+		{
+			name:         "standard bastion with tunneling and shareable link",
+			azureBastion: &AzureBastion{Sku: StandardBastionHostSku, EnableTunneling: true, EnableShareableLink: true},
+		},
+		{
+			name:         "tunneling on a basic bastion",
+			azureBastion: &AzureBastion{Sku: BasicBastionHostSku, EnableTunneling: true},
+			wantErrs:     []string{"sku must be Standard if tunneling is enabled"},
+		},
+		{
+			name:         "shareable link on a basic bastion",
+			azureBastion: &AzureBastion{Sku: BasicBastionHostSku, EnableShareableLink: true},
+			wantErrs:     []string{"sku must be Standard if shareable link is enabled"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateBastionSpec(BastionSpec{AzureBastion: tc.azureBastion}, field.NewPath("spec", "bastionSpec", "azureBastion"))
+			g.Expect(errs).To(HaveLen(len(tc.wantErrs)))
+			for i, wantErr := range tc.wantErrs {
+				g.Expect(errs[i].Error()).To(ContainSubstring(wantErr))
+			}
+		})
+	}
+}
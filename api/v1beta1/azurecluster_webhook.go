@@ -17,21 +17,45 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"time"
 
+	"golang.org/x/exp/slices"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupWebhookWithManager sets up and registers the webhook with the manager.
-func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+const (
+	// zonesLookupTimeout is the maximum time spent looking up the availability zones of a location on admission.
+	zonesLookupTimeout = 5 * time.Second
+
+	// defaultAPIServerPort is the port of the API server when the Cluster doesn't set one.
+	defaultAPIServerPort int32 = 6443
+)
+
+// LocationZonesGetter gets the availability zones of the location of an AzureCluster.
+// +kubebuilder:object:generate=false
+type LocationZonesGetter interface {
+	GetZones(ctx context.Context, azureCluster *AzureCluster) ([]string, error)
+}
+
+// SetupWebhookWithManager sets up and registers the webhook with the manager. The zones getter is used to validate
+// the availability zones requested by new AzureClusters when the ZoneValidation feature is enabled.
+func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager, zonesGetter LocationZonesGetter, allowlist PlacementAllowlist) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(&azureClusterWebhook{Client: mgr.GetClient(), zonesGetter: zonesGetter, allowlist: allowlist}).
 		Complete()
 }
 
@@ -70,14 +94,23 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		allErrs = append(allErrs, err)
 	}
 
-	if err := webhookutils.ValidateImmutable(
-		field.NewPath("Spec", "Location"),
+	if err := validateLocationUpdate(
 		old.Spec.Location,
-		c.Spec.Location); err != nil {
+		c.Spec.Location,
+		field.NewPath("Spec", "Location")); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
-	if old.Spec.ControlPlaneEndpoint.Host != "" && c.Spec.ControlPlaneEndpoint.Host != old.Spec.ControlPlaneEndpoint.Host {
+	if err := validateDeletionPolicyUpdate(
+		old.Spec.DeletionPolicy,
+		c.Spec.DeletionPolicy,
+		c.Annotations,
+		field.NewPath("spec", "deletionPolicy")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if old.Spec.ControlPlaneEndpoint.Host != "" && c.Spec.ControlPlaneEndpoint.Host != old.Spec.ControlPlaneEndpoint.Host &&
+		!switchesControlPlaneEndpoint(old) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "ControlPlaneEndpoint", "Host"),
 				c.Spec.ControlPlaneEndpoint.Host, "field is immutable"),
@@ -123,13 +156,36 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) (admission.Warnings
 		)
 	}
 
+	// A control plane outbound load balancer can be added when the public API server load balancer, which provides
+	// the outbound connectivity of the control plane machines, is migrated to Internal.
+	if old.Spec.NetworkSpec.ControlPlaneOutboundLB != nil || !c.migratesAPIServerLBToInternal(old) {
+		if err := webhookutils.ValidateImmutable(
+			field.NewPath("Spec", "NetworkSpec", "ControlPlaneOutboundLB"),
+			old.Spec.NetworkSpec.ControlPlaneOutboundLB,
+			c.Spec.NetworkSpec.ControlPlaneOutboundLB); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	// Changing the naming template would rename the resources of existing machines.
 	if err := webhookutils.ValidateImmutable(
-		field.NewPath("Spec", "NetworkSpec", "ControlPlaneOutboundLB"),
-		old.Spec.NetworkSpec.ControlPlaneOutboundLB,
-		c.Spec.NetworkSpec.ControlPlaneOutboundLB); err != nil {
+		field.NewPath("spec", "namingTemplate"),
+		old.Spec.NamingTemplate,
+		c.Spec.NamingTemplate); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateAPIServerDNSUpdate(old.Spec.APIServerDNS, c.Spec.APIServerDNS, field.NewPath("spec", "apiServerDNS"))...)
+
+	// Azure supports adding address space to a virtual network in place, but not removing it from one that has
+	// subnets.
+	if old.Spec.NetworkSpec.Vnet.Tags.HasOwned(old.Name) {
+		allErrs = append(allErrs, validateVnetCIDRUpdate(
+			c.Spec.NetworkSpec.Vnet.CIDRBlocks,
+			old.Spec.NetworkSpec.Vnet.CIDRBlocks,
+			field.NewPath("spec", "networkSpec", "vnet", "cidrBlocks"))...)
+	}
+
 	allErrs = append(allErrs, c.validateSubnetUpdate(old)...)
 
 	if len(allErrs) == 0 {
@@ -157,7 +213,9 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 			// This technically allows the cidr block to be modified in the brief
 			// moments before the Vnet is created (because the tags haven't been
 			// set yet) but once the Vnet has been created it becomes immutable.
-			if old.Spec.NetworkSpec.Vnet.Tags.HasOwned(old.Name) && !reflect.DeepEqual(subnet.CIDRBlocks, oldSubnet.CIDRBlocks) {
+			// The address space allocated from an IPAM pool is written to the CIDR blocks once.
+			ipamAllocated := oldSubnet.IPAMPoolRef != nil && len(oldSubnet.CIDRBlocks) == 0
+			if old.Spec.NetworkSpec.Vnet.Tags.HasOwned(old.Name) && !ipamAllocated && !reflect.DeepEqual(subnet.CIDRBlocks, oldSubnet.CIDRBlocks) {
 				allErrs = append(allErrs,
 					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("CIDRBlocks"),
 						c.Spec.NetworkSpec.Subnets[i].CIDRBlocks, "field is immutable"),
@@ -175,6 +233,24 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 						c.Spec.NetworkSpec.Subnets[i].NatGateway.Name, "field is immutable"),
 				)
 			}
+			if oldSubnet.NatGateway.Name != "" && !ptr.Equal(subnet.NatGateway.Zone, oldSubnet.NatGateway.Zone) {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("NatGateway").Child("Zone"),
+						c.Spec.NetworkSpec.Subnets[i].NatGateway.Zone, "field is immutable"),
+				)
+			}
+			if oldSubnet.NatGateway.Name != "" && !slices.Equal(subnet.NatGateway.NatGatewayIP.Zones, oldSubnet.NatGateway.NatGatewayIP.Zones) {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("NatGateway").Child("NatGatewayIP").Child("Zones"),
+						c.Spec.NetworkSpec.Subnets[i].NatGateway.NatGatewayIP.Zones, "field is immutable"),
+				)
+			}
+			if !reflect.DeepEqual(subnet.IPAMPoolRef, oldSubnet.IPAMPoolRef) {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("IPAMPoolRef"),
+						c.Spec.NetworkSpec.Subnets[i].IPAMPoolRef, "field is immutable"),
+				)
+			}
 			if subnet.SecurityGroup.Name != oldSubnet.SecurityGroup.Name {
 				allErrs = append(allErrs,
 					field.Invalid(field.NewPath("spec", "networkSpec", "subnets").Index(oldSubnetIndex[subnet.Name]).Child("SecurityGroup").Child("Name"),
@@ -191,3 +267,143 @@ func (c *AzureCluster) validateSubnetUpdate(old *AzureCluster) field.ErrorList {
 func (c *AzureCluster) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
+
+// azureClusterWebhook implements a validating webhook for AzureClusters which, in addition to the AzureCluster
+// validations, checks the ports of the API server load balancer against the API server port of the Cluster, the
+// subscription and location against the allowlist and the requested availability zones against the zones of the
+// cluster's location.
+type azureClusterWebhook struct {
+	Client      client.Client
+	zonesGetter LocationZonesGetter
+	allowlist   PlacementAllowlist
+}
+
+var _ webhook.CustomValidator = &azureClusterWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+
+	warnings, err := c.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	apiServerPort, err := w.apiServerPort(ctx, c)
+	if err != nil {
+		return warnings, err
+	}
+	allErrs := c.validateAPIServerLBPorts(apiServerPort)
+
+	placementErrs, err := validatePlacement(ctx, w.allowlist, placement{
+		subscriptionID:   c.Spec.SubscriptionID,
+		subscriptionPath: field.NewPath("spec", "subscriptionID"),
+		location:         c.Spec.Location,
+		locationPath:     field.NewPath("spec", "location"),
+	})
+	if err != nil {
+		return warnings, err
+	}
+	allErrs = append(allErrs, placementErrs...)
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterKind).GroupKind(), c.Name, allErrs)
+	}
+
+	if !feature.Gates.Enabled(feature.ZoneValidation) || w.zonesGetter == nil {
+		return warnings, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, zonesLookupTimeout)
+	defer cancel()
+
+	zones, err := w.zonesGetter.GetZones(ctx, c)
+	if err != nil {
+		// Don't block the creation of the cluster when the zones can't be looked up, e.g. when Azure can't be reached.
+		return append(warnings, fmt.Sprintf("skipped validating availability zones for location %s: %v", c.Spec.Location, err)), nil
+	}
+
+	if allErrs := c.validateLocationZones(zones); len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterKind).GroupKind(), c.Name, allErrs)
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	c, ok := newObj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+
+	warnings, err := c.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	apiServerPort, err := w.apiServerPort(ctx, c)
+	if err != nil {
+		return warnings, err
+	}
+	if allErrs := c.validateAPIServerLBPorts(apiServerPort); len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterKind).GroupKind(), c.Name, allErrs)
+	}
+
+	return warnings, nil
+}
+
+// apiServerPort returns the port of the API server load balancing rule of the AzureCluster, i.e. the API server port
+// of the Cluster referencing it, or, when there is no such Cluster yet, the port of the control plane endpoint if it
+// is set or the default API server port otherwise.
+func (w *azureClusterWebhook) apiServerPort(ctx context.Context, c *AzureCluster) (int32, error) {
+	if w.Client != nil {
+		clusters := &clusterv1.ClusterList{}
+		if err := w.Client.List(ctx, clusters, client.InNamespace(c.Namespace)); err != nil {
+			return 0, apierrors.NewInternalError(fmt.Errorf("failed to list Clusters: %w", err))
+		}
+		for _, cluster := range clusters.Items {
+			ref := cluster.Spec.InfrastructureRef
+			if ref == nil || ref.Kind != AzureClusterKind || ref.Name != c.Name {
+				continue
+			}
+			if cluster.Spec.ClusterNetwork != nil && cluster.Spec.ClusterNetwork.APIServerPort != nil {
+				return *cluster.Spec.ClusterNetwork.APIServerPort, nil
+			}
+			return defaultAPIServerPort, nil
+		}
+	}
+	if c.Spec.ControlPlaneEndpoint.Port != 0 {
+		return c.Spec.ControlPlaneEndpoint.Port, nil
+	}
+	return defaultAPIServerPort, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureCluster resource")
+	}
+	return c.ValidateDelete()
+}
+
+// validateAPIServerDNSUpdate validates that the record of the API server in an Azure DNS zone, which can be added and
+// removed, isn't moved to another zone or name.
+func validateAPIServerDNSUpdate(old, apiServerDNS *APIServerDNS, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if old == nil || apiServerDNS == nil {
+		return allErrs
+	}
+
+	if err := webhookutils.ValidateImmutable(fldPath.Child("zoneResourceID"), old.ZoneResourceID, apiServerDNS.ZoneResourceID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(fldPath.Child("recordName"), old.RecordName, apiServerDNS.RecordName); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	return allErrs
+}
@@ -17,10 +17,20 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAzureCluster_ValidateCreate(t *testing.T) {
@@ -145,6 +155,70 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "azurecluster API server public IP zones changed - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"1"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = []string{"1", "2", "3"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster NAT gateway public IP zones changed - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[1].NatGateway.Name = "node-natgateway"
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[1].NatGateway.Name = "node-natgateway"
+				cluster.Spec.NetworkSpec.Subnets[1].NatGateway.NatGatewayIP.Zones = []string{"2"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster deletion policy changed to Retain without confirmation - invalid spec",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DeletionPolicy = DeletionPolicyRetain
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster deletion policy changed to Retain with confirmation - valid spec",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DeletionPolicy = DeletionPolicyRetain
+				cluster.Annotations = map[string]string{RetainResourcesConfirmationAnnotation: "true"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster deletion policy changed from Retain to Delete - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DeletionPolicy = DeletionPolicyRetain
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.DeletionPolicy = DeletionPolicyDelete
+				return cluster
+			}(),
+			wantErr: false,
+		},
 		{
 			name:       "azurecluster with no control plane endpoint - valid spec",
 			oldCluster: createValidCluster(),
@@ -178,6 +252,131 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "azurecluster with owned vnet - subnet address space allocated from IPAM",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.1.0/24"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with owned vnet - subnet address space allocated from IPAM is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.1.0/24"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				cluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks = []string{"10.0.2.0/24"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with owned vnet - CIDR block appended",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR, "172.16.0.0/16"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with owned vnet - subnet created from an appended CIDR block",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR, "172.16.0.0/16"}
+				cluster.Spec.NetworkSpec.Subnets = append(cluster.Spec.NetworkSpec.Subnets, SubnetSpec{
+					SubnetClassSpec: SubnetClassSpec{
+						Role:       SubnetNode,
+						Name:       "node-subnet-2",
+						CIDRBlocks: []string{"172.16.0.0/24"},
+					},
+				})
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster with owned vnet - CIDR block removed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR, "172.16.0.0/16"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with owned vnet - appended CIDR block overlaps",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Vnet.Tags = Tags{ClusterTagKey(cluster.Name): string(ResourceLifecycleOwned)}
+				cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{DefaultVnetCIDR, "10.128.0.0/16"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster with subnet IPAM pool changed",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef = createValidIPAMPoolRef()
+				cluster.Spec.NetworkSpec.Subnets[1].IPAMPoolRef.Name = "other-pool"
+				return cluster
+			}(),
+			wantErr: true,
+		},
 		{
 			name:       "azurecluster with pre-existing vnet - lack control plane subnet",
 			oldCluster: createValidCluster(),
@@ -339,6 +538,218 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "natGateway zone is immutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Name = "cluster-test-node-natgw"
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Zone = ptr.To("1")
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Name = "cluster-test-node-natgw"
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Zone = ptr.To("2")
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "natGateway idle timeout is mutable",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Name = "cluster-test-node-natgw"
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Zone = ptr.To("1")
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.IdleTimeoutInMinutes = ptr.To[int32](4)
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Name = "cluster-test-node-natgw"
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.Zone = ptr.To("1")
+				cluster.Spec.NetworkSpec.Subnets[0].NatGateway.IdleTimeoutInMinutes = ptr.To[int32](30)
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster API server DNS record added - valid spec",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster API server DNS record TTL changed - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				cluster.Spec.APIServerDNS.TTL = ptr.To[int32](60)
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster API server DNS record renamed - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				cluster.Spec.APIServerDNS.RecordName = "api2"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster API server DNS record moved to another zone - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.APIServerDNS = createValidAPIServerDNS()
+				cluster.Spec.APIServerDNS.ZoneResourceID = "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.org"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster naming template added - invalid spec",
+			oldCluster: createValidCluster(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NamingTemplate = &NamingTemplate{Disk: "disk-{{ .MachineName }}"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster naming template changed - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NamingTemplate = &NamingTemplate{Disk: "disk-{{ .MachineName }}"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NamingTemplate = &NamingTemplate{Disk: "osdisk-{{ .MachineName }}"}
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster naming template unchanged - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NamingTemplate = &NamingTemplate{Disk: "disk-{{ .MachineName }}"}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createValidCluster()
+				cluster.Spec.NamingTemplate = &NamingTemplate{Disk: "disk-{{ .MachineName }}"}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster API server load balancer type changed to Internal without annotation - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Annotations = nil
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Annotations = nil
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.NetworkSpec.APIServerLB.Name = "my-internal-lb"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster API server load balancer type changed to Internal before the public one is recorded - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Status.APIServerLBMigration = nil
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.NetworkSpec.APIServerLB.Name = "my-internal-lb"
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name:       "azurecluster API server load balancer type changed to Internal during a migration - valid spec",
+			oldCluster: createMigratingCluster(APIServerLBMigrationPending),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.NetworkSpec.APIServerLB.Name = "my-internal-lb"
+				cluster.Spec.NetworkSpec.ControlPlaneOutboundLB = &LoadBalancerSpec{FrontendIPsCount: ptr.To[int32](1)}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "azurecluster API server load balancer type changed to Internal keeping the public load balancer name - invalid spec",
+			oldCluster: createMigratingCluster(APIServerLBMigrationPending),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationPending)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				return cluster
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azurecluster control plane endpoint switched to the internal API server load balancer - valid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationSwitchingEndpoint)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "myfqdn.azure.com", Port: 6443}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationSwitchingEndpoint)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "apiserver.test-cluster.capz.io", Port: 6443}
+				return cluster
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "azurecluster control plane endpoint changed once the migration is completed - invalid spec",
+			oldCluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationCompleted)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "apiserver.test-cluster.capz.io", Port: 6443}
+				return cluster
+			}(),
+			cluster: func() *AzureCluster {
+				cluster := createMigratingCluster(APIServerLBMigrationCompleted)
+				cluster.Spec.NetworkSpec.APIServerLB = createValidAPIServerInternalLB()
+				cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "apiserver.example.com", Port: 6443}
+				return cluster
+			}(),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -354,3 +765,350 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// createMigratingCluster returns a valid cluster whose API server load balancer is migrated from Public to Internal.
+func createMigratingCluster(phase APIServerLBMigrationPhase) *AzureCluster {
+	cluster := createValidCluster()
+	cluster.Annotations = map[string]string{MigrateAPIServerLBToInternalAnnotation: "true"}
+	cluster.Spec.NetworkSpec.Vnet.CIDRBlocks = []string{"10.10.0.0/16"}
+	cluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks = []string{"10.10.1.0/24"}
+	cluster.Status.APIServerLBMigration = &APIServerLBMigration{
+		Phase:         phase,
+		PublicLBName:  "my-lb",
+		PublicIPNames: []string{"public-ip"},
+	}
+	return cluster
+}
+
+func createValidAPIServerDNS() *APIServerDNS {
+	return &APIServerDNS{
+		ZoneResourceID: "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+		RecordName:     "api",
+		TTL:            ptr.To[int32](DefaultAPIServerDNSTTL),
+	}
+}
+
+type fakeZonesGetter struct {
+	zones []string
+	err   error
+}
+
+func (f fakeZonesGetter) GetZones(_ context.Context, _ *AzureCluster) ([]string, error) {
+	return f.zones, f.err
+}
+
+func TestAzureClusterWebhook_ValidateCreateZones(t *testing.T) {
+	threeZones := fakeZonesGetter{zones: []string{"1", "2", "3"}}
+	noZones := fakeZonesGetter{zones: []string{}}
+
+	withFailureDomains := func(ids ...string) *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.Location = "westus"
+		cluster.Spec.FailureDomains = clusterv1.FailureDomains{}
+		for _, id := range ids {
+			cluster.Spec.FailureDomains[id] = clusterv1.FailureDomainSpec{ControlPlane: true}
+		}
+		return cluster
+	}
+	withNatGatewayZone := func(zone string) *AzureCluster {
+		cluster := withFailureDomains()
+		cluster.Spec.NetworkSpec.Subnets[1].NatGateway = NatGateway{
+			NatGatewayClassSpec: NatGatewayClassSpec{Name: "node-natgateway", Zone: ptr.To(zone)},
+		}
+		return cluster
+	}
+	withPublicIPZones := func(zones ...string) *AzureCluster {
+		cluster := withFailureDomains()
+		cluster.Spec.NetworkSpec.APIServerLB.FrontendIPs[0].PublicIP.Zones = zones
+		return cluster
+	}
+
+	tests := []struct {
+		name         string
+		featureGate  bool
+		zonesGetter  LocationZonesGetter
+		cluster      *AzureCluster
+		wantErr      bool
+		wantWarnings bool
+	}{
+		{
+			name:        "feature disabled skips the check",
+			featureGate: false,
+			zonesGetter: noZones,
+			cluster:     withFailureDomains("1"),
+			wantErr:     false,
+		},
+		{
+			name:        "failure domains in a 3-zone region",
+			featureGate: true,
+			zonesGetter: threeZones,
+			cluster:     withFailureDomains("1", "2", "3"),
+			wantErr:     false,
+		},
+		{
+			name:        "failure domain missing from a 3-zone region",
+			featureGate: true,
+			zonesGetter: threeZones,
+			cluster:     withFailureDomains("1", "4"),
+			wantErr:     true,
+		},
+		{
+			name:        "no failure domains in a zoneless region",
+			featureGate: true,
+			zonesGetter: noZones,
+			cluster:     withFailureDomains(),
+			wantErr:     false,
+		},
+		{
+			name:        "failure domains in a zoneless region",
+			featureGate: true,
+			zonesGetter: noZones,
+			cluster:     withFailureDomains("1"),
+			wantErr:     true,
+		},
+		{
+			name:        "NAT gateway zone in a 3-zone region",
+			featureGate: true,
+			zonesGetter: threeZones,
+			cluster:     withNatGatewayZone("2"),
+			wantErr:     false,
+		},
+		{
+			name:        "NAT gateway zone in a zoneless region",
+			featureGate: true,
+			zonesGetter: noZones,
+			cluster:     withNatGatewayZone("2"),
+			wantErr:     true,
+		},
+		{
+			name:        "zonal public IP in a 3-zone region",
+			featureGate: true,
+			zonesGetter: threeZones,
+			cluster:     withPublicIPZones("2"),
+			wantErr:     false,
+		},
+		{
+			name:        "public IP zone missing from a 3-zone region",
+			featureGate: true,
+			zonesGetter: threeZones,
+			cluster:     withPublicIPZones("1", "4"),
+			wantErr:     true,
+		},
+		{
+			name:        "public IP zones in a zoneless region",
+			featureGate: true,
+			zonesGetter: noZones,
+			cluster:     withPublicIPZones("1"),
+			wantErr:     true,
+		},
+		{
+			name:         "zones lookup failure skips the check",
+			featureGate:  true,
+			zonesGetter:  fakeZonesGetter{err: errors.New("network unreachable")},
+			cluster:      withFailureDomains("1"),
+			wantErr:      false,
+			wantWarnings: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ZoneValidation, tc.featureGate)()
+			g := NewWithT(t)
+			w := &azureClusterWebhook{zonesGetter: tc.zonesGetter}
+			warnings, err := w.ValidateCreate(context.Background(), tc.cluster)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+type fakePlacementAllowlist struct {
+	subscriptions []string
+	locations     []string
+	err           error
+}
+
+func (f fakePlacementAllowlist) AllowedPlacement(_ context.Context) ([]string, []string, error) {
+	return f.subscriptions, f.locations, f.err
+}
+
+func TestAzureClusterWebhook_ValidateCreatePlacement(t *testing.T) {
+	withPlacement := func(subscriptionID, location string) *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.SubscriptionID = subscriptionID
+		cluster.Spec.Location = location
+		return cluster
+	}
+
+	tests := []struct {
+		name      string
+		allowlist PlacementAllowlist
+		cluster   *AzureCluster
+		wantErr   bool
+	}{
+		{
+			name:      "no allowlist",
+			allowlist: nil,
+			cluster:   withPlacement("123", "westus2"),
+			wantErr:   false,
+		},
+		{
+			name:      "empty allowlist allows everything",
+			allowlist: fakePlacementAllowlist{},
+			cluster:   withPlacement("123", "westus2"),
+			wantErr:   false,
+		},
+		{
+			name:      "allowed subscription and location",
+			allowlist: fakePlacementAllowlist{subscriptions: []string{"123", "456"}, locations: []string{"West US 2"}},
+			cluster:   withPlacement("456", "westus2"),
+			wantErr:   false,
+		},
+		{
+			name:      "subscription not allowed",
+			allowlist: fakePlacementAllowlist{subscriptions: []string{"123"}},
+			cluster:   withPlacement("456", "westus2"),
+			wantErr:   true,
+		},
+		{
+			name:      "location not allowed",
+			allowlist: fakePlacementAllowlist{locations: []string{"eastus"}},
+			cluster:   withPlacement("123", "westus2"),
+			wantErr:   true,
+		},
+		{
+			name:      "allowlist lookup failure",
+			allowlist: fakePlacementAllowlist{err: errors.New("connection refused")},
+			cluster:   withPlacement("123", "westus2"),
+			wantErr:   true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			w := &azureClusterWebhook{allowlist: tc.allowlist}
+			_, err := w.ValidateCreate(context.Background(), tc.cluster)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureClusterWebhook_ValidateAPIServerLBPorts(t *testing.T) {
+	withProbePort := func(port int32) *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.NetworkSpec.APIServerLB.HealthProbe = &LoadBalancerHealthProbe{Port: ptr.To(port)}
+		return cluster
+	}
+	withAdditionalPort := func(port int32) *AzureCluster {
+		cluster := createValidCluster()
+		cluster.Spec.NetworkSpec.APIServerLB.AdditionalAPIServerLBPorts = []LoadBalancerPort{{Name: "konnectivity", Port: port}}
+		return cluster
+	}
+	ownerCluster := func(apiServerPort *int32) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "owner",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					Kind: AzureClusterKind,
+					Name: "test-cluster",
+				},
+			},
+		}
+		if apiServerPort != nil {
+			cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{APIServerPort: apiServerPort}
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name     string
+		existing []client.Object
+		cluster  *AzureCluster
+		wantErr  string
+	}{
+		{
+			name:    "probe port matching the default API server port on create",
+			cluster: withProbePort(6443),
+		},
+		{
+			name:    "probe port not matching the default API server port on create",
+			cluster: withProbePort(8443),
+			wantErr: "API Server load balancer health probe port should match the API server port 6443",
+		},
+		{
+			name:     "probe port matching the API server port of the Cluster",
+			existing: []client.Object{ownerCluster(ptr.To[int32](8443))},
+			cluster:  withProbePort(8443),
+		},
+		{
+			name:     "probe port not matching the API server port of the Cluster",
+			existing: []client.Object{ownerCluster(ptr.To[int32](8443))},
+			cluster:  withProbePort(6443),
+			wantErr:  "API Server load balancer health probe port should match the API server port 8443",
+		},
+		{
+			name:     "probe port matching the default API server port of the Cluster",
+			existing: []client.Object{ownerCluster(nil)},
+			cluster:  withProbePort(6443),
+		},
+		{
+			name:    "additional port reusing the default API server port on create",
+			cluster: withAdditionalPort(6443),
+			wantErr: "additional API Server load balancer port should not be the API server port 6443",
+		},
+		{
+			name:     "additional port reusing the API server port of the Cluster",
+			existing: []client.Object{ownerCluster(ptr.To[int32](8132))},
+			cluster:  withAdditionalPort(8132),
+			wantErr:  "additional API Server load balancer port should not be the API server port 8132",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			w := &azureClusterWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.existing...).Build()}
+			_, err := w.ValidateCreate(context.Background(), tc.cluster)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureClusterWebhook_ValidateUpdateAPIServerLBPorts(t *testing.T) {
+	g := NewWithT(t)
+
+	old := createValidCluster()
+	old.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "apiserver.example.com", Port: 8443}
+	cluster := old.DeepCopy()
+	cluster.Spec.NetworkSpec.APIServerLB.HealthProbe = &LoadBalancerHealthProbe{Port: ptr.To[int32](8443)}
+
+	w := &azureClusterWebhook{}
+	_, err := w.ValidateUpdate(context.Background(), old, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cluster.Spec.NetworkSpec.APIServerLB.HealthProbe.Port = ptr.To[int32](6443)
+	_, err = w.ValidateUpdate(context.Background(), old, cluster)
+	g.Expect(err).To(MatchError(ContainSubstring("API Server load balancer health probe port should match the API server port 8443")))
+}
@@ -67,6 +67,12 @@ type AzureClusterIdentitySpec struct {
 	// +optional
 	// +nullable
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces"`
+	// FallbackIdentityRef is a reference to an AzureClusterIdentity to authenticate with when this identity fails to
+	// authenticate, for example when the service principal is locked out by conditional access policies.
+	// If the namespace isn't specified, the fallback identity is assumed to be in the same namespace as this identity.
+	// The fallback identity must allow the same namespaces and can't have a fallback identity of its own.
+	// +optional
+	FallbackIdentityRef *corev1.ObjectReference `json:"fallbackIdentityRef,omitempty"`
 }
 
 // AzureClusterIdentityStatus defines the observed state of AzureClusterIdentity.
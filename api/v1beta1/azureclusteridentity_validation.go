@@ -17,8 +17,13 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -29,8 +34,94 @@ func (c *AzureClusterIdentity) validateClusterIdentity() (admission.Warnings, er
 	} else if c.Spec.Type != UserAssignedMSI && c.Spec.ResourceID != "" {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "resourceID"), c.Spec.ResourceID))
 	}
+	allErrs = append(allErrs, c.validateFallbackIdentityRef()...)
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
 	return nil, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterIdentityKind).GroupKind(), c.Name, allErrs)
 }
+
+// validateFallbackIdentityRef validates the fallback identity reference doesn't point back to the identity itself.
+func (c *AzureClusterIdentity) validateFallbackIdentityRef() field.ErrorList {
+	var allErrs field.ErrorList
+	ref := c.Spec.FallbackIdentityRef
+	if ref == nil {
+		return allErrs
+	}
+	fldPath := field.NewPath("spec", "fallbackIdentityRef")
+	if ref.Kind != "" && ref.Kind != AzureClusterIdentityKind {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("kind"), ref.Kind, []string{AzureClusterIdentityKind}))
+	}
+	if ref.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name of the fallback AzureClusterIdentity is required"))
+	}
+	if ref.Name == c.Name && (ref.Namespace == "" || ref.Namespace == c.Namespace) {
+		allErrs = append(allErrs, field.Invalid(fldPath, ref.Name, "an AzureClusterIdentity can't be its own fallback identity"))
+	}
+	return allErrs
+}
+
+// fallbackIdentityKey returns the key of the fallback identity of the AzureClusterIdentity, defaulting its namespace
+// to the namespace of the AzureClusterIdentity.
+func (c *AzureClusterIdentity) fallbackIdentityKey() client.ObjectKey {
+	namespace := c.Spec.FallbackIdentityRef.Namespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: c.Spec.FallbackIdentityRef.Name}
+}
+
+// validateFallbackIdentityChain validates the fallback identity is at most one hop away: the fallback identity can't
+// have a fallback identity of its own, and an identity used as a fallback can't get a fallback identity.
+func (c *AzureClusterIdentity) validateFallbackIdentityChain(ctx context.Context, cli client.Client) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	if c.Spec.FallbackIdentityRef == nil {
+		return warnings, allErrs
+	}
+	fldPath := field.NewPath("spec", "fallbackIdentityRef")
+
+	key := c.fallbackIdentityKey()
+	fallback := &AzureClusterIdentity{}
+	if err := cli.Get(ctx, key, fallback); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return warnings, append(allErrs, field.InternalError(fldPath, err))
+		}
+		warnings = append(warnings, fmt.Sprintf("fallback AzureClusterIdentity %s/%s not found, it won't be used until it is created", key.Namespace, key.Name))
+	} else if fallback.Spec.FallbackIdentityRef != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, c.Spec.FallbackIdentityRef.Name,
+			fmt.Sprintf("fallback AzureClusterIdentity %s/%s has a fallback identity of its own, only one level of fallback is supported", key.Namespace, key.Name)))
+	}
+
+	identities := &AzureClusterIdentityList{}
+	if err := cli.List(ctx, identities); err != nil {
+		return warnings, append(allErrs, field.InternalError(fldPath, err))
+	}
+	for i := range identities.Items {
+		identity := &identities.Items[i]
+		if identity.Spec.FallbackIdentityRef == nil {
+			continue
+		}
+		if identity.fallbackIdentityKey() == (client.ObjectKey{Namespace: c.Namespace, Name: c.Name}) {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("AzureClusterIdentity is the fallback identity of %s/%s, only one level of fallback is supported", identity.Namespace, identity.Name)))
+		}
+	}
+	return warnings, allErrs
+}
+
+// placement returns the subscription of the user-assigned identity, which is the only Azure placement an
+// AzureClusterIdentity references.
+func (c *AzureClusterIdentity) placement() placement {
+	if c.Spec.ResourceID == "" {
+		return placement{}
+	}
+	resourceID, err := arm.ParseResourceID(c.Spec.ResourceID)
+	if err != nil {
+		return placement{}
+	}
+	return placement{
+		subscriptionID:   resourceID.SubscriptionID,
+		subscriptionPath: field.NewPath("spec", "resourceID"),
+	}
+}
@@ -17,19 +17,23 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
-func (c *AzureClusterIdentity) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (c *AzureClusterIdentity) SetupWebhookWithManager(mgr ctrl.Manager, allowlist PlacementAllowlist) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(&azureClusterIdentityWebhook{Client: mgr.GetClient(), allowlist: allowlist}).
 		Complete()
 }
 
@@ -62,3 +66,63 @@ func (c *AzureClusterIdentity) ValidateUpdate(oldRaw runtime.Object) (admission.
 func (c *AzureClusterIdentity) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
+
+// azureClusterIdentityWebhook implements a validating webhook for AzureClusterIdentities which, in addition to the
+// AzureClusterIdentity validations, checks the fallback identity chain against the existing identities and the
+// subscription of a user-assigned identity against the allowlist.
+type azureClusterIdentityWebhook struct {
+	Client    client.Client
+	allowlist PlacementAllowlist
+}
+
+var _ webhook.CustomValidator = &azureClusterIdentityWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterIdentityWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	warnings, err := c.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+	allErrs, err := validatePlacement(ctx, w.allowlist, c.placement())
+	if err != nil {
+		return warnings, err
+	}
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterIdentityKind).GroupKind(), c.Name, allErrs)
+	}
+	return w.validateFallbackIdentityChain(ctx, c)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterIdentityWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	c, ok := newObj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	warnings, err := c.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+	return w.validateFallbackIdentityChain(ctx, c)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (w *azureClusterIdentityWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	c, ok := obj.(*AzureClusterIdentity)
+	if !ok {
+		return nil, apierrors.NewBadRequest("expected an AzureClusterIdentity resource")
+	}
+	return c.ValidateDelete()
+}
+
+func (w *azureClusterIdentityWebhook) validateFallbackIdentityChain(ctx context.Context, c *AzureClusterIdentity) (admission.Warnings, error) {
+	warnings, allErrs := c.validateFallbackIdentityChain(ctx, w.Client)
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureClusterIdentityKind).GroupKind(), c.Name, allErrs)
+}
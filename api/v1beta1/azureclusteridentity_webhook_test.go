@@ -17,9 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const fakeClientID = "fake-client-id"
@@ -67,6 +72,45 @@ func TestAzureClusterIdentity_ValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "azureclusteridentity with a fallback identity",
+			clusterIdentity: &AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "primary"},
+				Spec: AzureClusterIdentitySpec{
+					Type:                ServicePrincipal,
+					ClientID:            fakeClientID,
+					TenantID:            fakeTenantID,
+					FallbackIdentityRef: &corev1.ObjectReference{Name: "fallback"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azureclusteridentity that is its own fallback identity",
+			clusterIdentity: &AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "primary", Namespace: "default"},
+				Spec: AzureClusterIdentitySpec{
+					Type:                ServicePrincipal,
+					ClientID:            fakeClientID,
+					TenantID:            fakeTenantID,
+					FallbackIdentityRef: &corev1.ObjectReference{Name: "primary", Namespace: "default"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azureclusteridentity with a fallback identity of another kind",
+			clusterIdentity: &AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "primary"},
+				Spec: AzureClusterIdentitySpec{
+					Type:                ServicePrincipal,
+					ClientID:            fakeClientID,
+					TenantID:            fakeTenantID,
+					FallbackIdentityRef: &corev1.ObjectReference{Kind: "Secret", Name: "fallback"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "azureclusteridentity with user assigned msi and no resource id",
 			clusterIdentity: &AzureClusterIdentity{
@@ -172,3 +216,142 @@ func TestAzureClusterIdentity_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureClusterIdentityWebhook_ValidateFallbackIdentityChain(t *testing.T) {
+	identity := func(name string, fallback string) *AzureClusterIdentity {
+		identity := &AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: AzureClusterIdentitySpec{
+				Type:     ServicePrincipal,
+				ClientID: fakeClientID,
+				TenantID: fakeTenantID,
+			},
+		}
+		if fallback != "" {
+			identity.Spec.FallbackIdentityRef = &corev1.ObjectReference{Name: fallback}
+		}
+		return identity
+	}
+
+	tests := []struct {
+		name            string
+		existing        []runtime.Object
+		clusterIdentity *AzureClusterIdentity
+		wantErr         bool
+		wantWarnings    bool
+	}{
+		{
+			name:            "identity without a fallback",
+			existing:        []runtime.Object{identity("fallback", "")},
+			clusterIdentity: identity("primary", ""),
+		},
+		{
+			name:            "fallback identity without a fallback",
+			existing:        []runtime.Object{identity("fallback", "")},
+			clusterIdentity: identity("primary", "fallback"),
+		},
+		{
+			name:            "fallback identity with a fallback of its own",
+			existing:        []runtime.Object{identity("fallback", "second-fallback"), identity("second-fallback", "")},
+			clusterIdentity: identity("primary", "fallback"),
+			wantErr:         true,
+		},
+		{
+			name:            "identity that is the fallback of another identity can't get a fallback",
+			existing:        []runtime.Object{identity("other", "primary"), identity("fallback", "")},
+			clusterIdentity: identity("primary", "fallback"),
+			wantErr:         true,
+		},
+		{
+			name:            "missing fallback identity",
+			clusterIdentity: identity("primary", "fallback"),
+			wantWarnings:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.existing...).Build()
+			w := &azureClusterIdentityWebhook{Client: fakeClient}
+			warnings, err := w.ValidateCreate(context.Background(), tc.clusterIdentity)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureClusterIdentityWebhook_ValidateCreatePlacement(t *testing.T) {
+	identity := func(resourceID string) *AzureClusterIdentity {
+		return &AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "identity", Namespace: "default"},
+			Spec: AzureClusterIdentitySpec{
+				Type:       UserAssignedMSI,
+				ClientID:   fakeClientID,
+				TenantID:   fakeTenantID,
+				ResourceID: resourceID,
+			},
+		}
+	}
+	msiResourceID := "/subscriptions/456/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity"
+
+	tests := []struct {
+		name            string
+		allowlist       PlacementAllowlist
+		clusterIdentity *AzureClusterIdentity
+		wantErr         bool
+	}{
+		{
+			name:            "empty allowlist allows everything",
+			allowlist:       fakePlacementAllowlist{},
+			clusterIdentity: identity(msiResourceID),
+			wantErr:         false,
+		},
+		{
+			name:            "allowed subscription",
+			allowlist:       fakePlacementAllowlist{subscriptions: []string{"123", "456"}},
+			clusterIdentity: identity(msiResourceID),
+			wantErr:         false,
+		},
+		{
+			name:            "subscription not allowed",
+			allowlist:       fakePlacementAllowlist{subscriptions: []string{"123"}},
+			clusterIdentity: identity(msiResourceID),
+			wantErr:         true,
+		},
+		{
+			name:            "identity without a subscription",
+			allowlist:       fakePlacementAllowlist{subscriptions: []string{"123"}},
+			clusterIdentity: identity(fakeResourceID),
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			w := &azureClusterIdentityWebhook{Client: fakeClient, allowlist: tc.allowlist}
+			_, err := w.ValidateCreate(context.Background(), tc.clusterIdentity)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
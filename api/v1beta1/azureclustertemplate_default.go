@@ -16,10 +16,6 @@ limitations under the License.
 
 package v1beta1
 
-import (
-	"fmt"
-)
-
 func (c *AzureClusterTemplate) setDefaults() {
 	c.Spec.Template.Spec.AzureClusterClassSpec.setDefaults()
 	c.setNetworkTemplateSpecDefaults()
@@ -52,36 +48,45 @@ func (c *AzureClusterTemplate) setBastionTemplateDefaults() {
 	}
 }
 
+// setSubnetsTemplateDefaults sets the defaults of the subnets. Subnets without CIDR blocks get the next free block of
+// the virtual network, in the same order as the subnets of an AzureCluster.
 func (c *AzureClusterTemplate) setSubnetsTemplateDefaults() {
+	var usedCIDRBlocks []string
+	for _, subnet := range c.Spec.Template.Spec.NetworkSpec.Subnets {
+		usedCIDRBlocks = append(usedCIDRBlocks, subnet.CIDRBlocks...)
+	}
+	if c.Spec.Template.Spec.BastionSpec.AzureBastion != nil {
+		usedCIDRBlocks = append(usedCIDRBlocks, c.Spec.Template.Spec.BastionSpec.AzureBastion.Subnet.CIDRBlocks...)
+	}
+	cidrs := newSubnetCIDRAllocator(c.Spec.Template.Spec.NetworkSpec.Vnet.VnetClassSpec, usedCIDRBlocks)
+
 	clusterSubnet, err := c.Spec.Template.Spec.NetworkSpec.GetSubnetTemplate(SubnetCluster)
 	clusterSubnetExists := err == nil
 	if clusterSubnetExists {
-		clusterSubnet.SubnetClassSpec.setDefaults(DefaultClusterSubnetCIDR)
+		clusterSubnet.SubnetClassSpec.setDefaults(cidrs.allocate(clusterSubnet.SubnetClassSpec))
 		clusterSubnet.SecurityGroup.setDefaults()
 		c.Spec.Template.Spec.NetworkSpec.UpdateSubnetTemplate(clusterSubnet, SubnetCluster)
 	}
 
 	cpSubnet, errcp := c.Spec.Template.Spec.NetworkSpec.GetSubnetTemplate(SubnetControlPlane)
 	if errcp == nil {
-		cpSubnet.SubnetClassSpec.setDefaults(DefaultControlPlaneSubnetCIDR)
+		cpSubnet.SubnetClassSpec.setDefaults(cidrs.allocate(cpSubnet.SubnetClassSpec))
 		cpSubnet.SecurityGroup.setDefaults()
 		c.Spec.Template.Spec.NetworkSpec.UpdateSubnetTemplate(cpSubnet, SubnetControlPlane)
 	} else if errcp != nil && !clusterSubnetExists {
 		cpSubnet = SubnetTemplateSpec{SubnetClassSpec: SubnetClassSpec{Role: SubnetControlPlane}}
-		cpSubnet.SubnetClassSpec.setDefaults(DefaultControlPlaneSubnetCIDR)
+		cpSubnet.SubnetClassSpec.setDefaults(cidrs.allocate(cpSubnet.SubnetClassSpec))
 		cpSubnet.SecurityGroup.setDefaults()
 		c.Spec.Template.Spec.NetworkSpec.Subnets = append(c.Spec.Template.Spec.NetworkSpec.Subnets, cpSubnet)
 	}
 
 	var nodeSubnetFound bool
-	var nodeSubnetCounter int
 	for i, subnet := range c.Spec.Template.Spec.NetworkSpec.Subnets {
 		if subnet.Role != SubnetNode {
 			continue
 		}
-		nodeSubnetCounter++
 		nodeSubnetFound = true
-		subnet.SubnetClassSpec.setDefaults(fmt.Sprintf(DefaultNodeSubnetCIDRPattern, nodeSubnetCounter))
+		subnet.SubnetClassSpec.setDefaults(cidrs.allocate(subnet.SubnetClassSpec))
 		subnet.SecurityGroup.setDefaults()
 		c.Spec.Template.Spec.NetworkSpec.Subnets[i] = subnet
 	}
@@ -89,10 +94,10 @@ func (c *AzureClusterTemplate) setSubnetsTemplateDefaults() {
 	if !nodeSubnetFound && !clusterSubnetExists {
 		nodeSubnet := SubnetTemplateSpec{
 			SubnetClassSpec: SubnetClassSpec{
-				Role:       SubnetNode,
-				CIDRBlocks: []string{DefaultNodeSubnetCIDR},
+				Role: SubnetNode,
 			},
 		}
+		nodeSubnet.SubnetClassSpec.setDefaults(cidrs.allocate(nodeSubnet.SubnetClassSpec))
 		c.Spec.Template.Spec.NetworkSpec.Subnets = append(c.Spec.Template.Spec.NetworkSpec.Subnets, nodeSubnet)
 	}
 }
@@ -126,7 +131,7 @@ func (c *AzureClusterTemplate) setNodeOutboundLBDefaults() {
 
 func (c *AzureClusterTemplate) setControlPlaneOutboundLBDefaults() {
 	lb := c.Spec.Template.Spec.NetworkSpec.ControlPlaneOutboundLB
-	if lb == nil {
+	if lb == nil || lb.Disabled {
 		return
 	}
 	lb.setControlPlaneOutboundLBDefaults()
@@ -641,6 +641,85 @@ func TestSubnetsTemplateDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "subnets without cidr blocks get blocks of the vnet subnet prefix length",
+			clusterTemplate: &AzureClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster-template",
+				},
+				Spec: AzureClusterTemplateSpec{
+					Template: AzureClusterTemplateResource{
+						Spec: AzureClusterTemplateResourceSpec{
+							NetworkSpec: NetworkTemplateSpec{
+								Vnet: VnetTemplateSpec{
+									VnetClassSpec: VnetClassSpec{
+										CIDRBlocks:         []string{"10.0.0.0/16"},
+										SubnetPrefixLength: ptr.To[int32](24),
+									},
+								},
+								Subnets: SubnetTemplatesSpec{
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role:       SubnetControlPlane,
+											CIDRBlocks: []string{"10.0.0.0/24"},
+										},
+									},
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role: SubnetNode,
+										},
+									},
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role: SubnetNode,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			outputTemplate: &AzureClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster-template",
+				},
+				Spec: AzureClusterTemplateSpec{
+					Template: AzureClusterTemplateResource{
+						Spec: AzureClusterTemplateResourceSpec{
+							NetworkSpec: NetworkTemplateSpec{
+								Vnet: VnetTemplateSpec{
+									VnetClassSpec: VnetClassSpec{
+										CIDRBlocks:         []string{"10.0.0.0/16"},
+										SubnetPrefixLength: ptr.To[int32](24),
+									},
+								},
+								Subnets: SubnetTemplatesSpec{
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role:       SubnetControlPlane,
+											CIDRBlocks: []string{"10.0.0.0/24"},
+										},
+									},
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role:       SubnetNode,
+											CIDRBlocks: []string{"10.0.1.0/24"},
+										},
+									},
+									{
+										SubnetClassSpec: SubnetClassSpec{
+											Role:       SubnetNode,
+											CIDRBlocks: []string{"10.0.2.0/24"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
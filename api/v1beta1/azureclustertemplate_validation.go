@@ -62,6 +62,25 @@ func (c *AzureClusterTemplate) validateClusterTemplateSpec() field.ErrorList {
 
 	allErrs = append(allErrs, c.validatePrivateDNSZoneName()...)
 
+	allErrs = append(allErrs, validateDefaultImage(
+		c.Spec.Template.Spec.DefaultImage,
+		field.NewPath("spec").Child("template").Child("spec").Child("defaultImage"),
+	)...)
+
+	allErrs = append(allErrs, validateClusterVMExtensions(
+		c.Spec.Template.Spec.VMExtensions,
+		field.NewPath("spec").Child("template").Child("spec").Child("vmExtensions"),
+	)...)
+
+	// The names of the clusters of the template are only known once they are created, so the templates are validated
+	// with the name of the AzureClusterTemplate.
+	allErrs = append(allErrs, validateNamingTemplate(
+		c.Spec.Template.Spec.NamingTemplate,
+		c.Namespace,
+		c.Name,
+		field.NewPath("spec").Child("template").Child("spec").Child("namingTemplate"),
+	)...)
+
 	return allErrs
 }
 
@@ -113,6 +132,7 @@ func validateSubnetTemplates(subnets SubnetTemplatesSpec, vnet VnetTemplateSpec,
 			}
 		}
 		allErrs = append(allErrs, validateSubnetCIDR(subnet.CIDRBlocks, vnet.CIDRBlocks, fld.Index(i).Child("cidrBlocks"))...)
+		allErrs = append(allErrs, validateNatGateway(subnet.NatGateway, fld.Index(i).Child("natGateway"))...)
 	}
 	for k, v := range requiredSubnetRoles {
 		if !v {
@@ -166,5 +186,11 @@ func (c *AzureClusterTemplate) validatePrivateDNSZoneName() field.ErrorList {
 		fldPath,
 	)...)
 
+	allErrs = append(allErrs, validatePrivateDNSZone(
+		networkSpec.PrivateDNSZone,
+		networkSpec.APIServerLB.Type,
+		field.NewPath("spec").Child("template").Child("spec").Child("networkSpec").Child("privateDNSZone"),
+	)...)
+
 	return allErrs
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -894,3 +895,67 @@ func TestValidateNetworkSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateClusterTemplateNamingTemplate(t *testing.T) {
+	cases := []struct {
+		name           string
+		namingTemplate *NamingTemplate
+		expectValid    bool
+	}{
+		{
+			name:        "not set",
+			expectValid: true,
+		},
+		{
+			name: "valid templates",
+			namingTemplate: &NamingTemplate{
+				NetworkInterface: "nic-{{ .MachineName }}",
+				LoadBalancer:     "{{ .ClusterName }}-{{ .Role }}-lb",
+			},
+			expectValid: true,
+		},
+		{
+			name: "template rendering the same name for every machine",
+			namingTemplate: &NamingTemplate{
+				Disk: "{{ .ClusterName }}-disk",
+			},
+			expectValid: false,
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			clusterTemplate := &AzureClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster-template",
+				},
+				Spec: AzureClusterTemplateSpec{
+					Template: AzureClusterTemplateResource{
+						Spec: AzureClusterTemplateResourceSpec{
+							AzureClusterClassSpec: AzureClusterClassSpec{
+								NamingTemplate: tc.namingTemplate,
+							},
+						},
+					},
+				},
+			}
+			res := clusterTemplate.validateClusterTemplateSpec()
+
+			namingTemplatePath := field.NewPath("spec", "template", "spec", "namingTemplate").String()
+			var namingTemplateErrs field.ErrorList
+			for _, err := range res {
+				if strings.HasPrefix(err.Field, namingTemplatePath) {
+					namingTemplateErrs = append(namingTemplateErrs, err)
+				}
+			}
+			if tc.expectValid {
+				g.Expect(namingTemplateErrs).To(BeEmpty())
+			} else {
+				g.Expect(namingTemplateErrs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
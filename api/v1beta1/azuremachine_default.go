@@ -35,6 +35,9 @@ import (
 // ContributorRoleID is the ID of the built-in "Contributor" role.
 const ContributorRoleID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
 
+// NetworkContributorRoleID is the ID of the built-in "Network Contributor" role.
+const NetworkContributorRoleID = "4d97b98b-1d4f-4787-a291-c67834d212e7"
+
 // SetDefaultSSHPublicKey sets the default SSHPublicKey for an AzureMachine.
 func (s *AzureMachineSpec) SetDefaultSSHPublicKey() error {
 	if sshKeyData := s.SSHPublicKey; sshKeyData == "" {
@@ -174,6 +177,22 @@ func (s *AzureMachineSpec) SetNetworkInterfacesDefaults() {
 	}
 }
 
+// deprecatedFieldMigrationWarnings returns a warning for each deprecated field which was moved to its replacement
+// while defaulting the spec.
+func deprecatedFieldMigrationWarnings(before, after *AzureMachineSpec) []string {
+	var warnings []string
+	if before.RoleAssignmentName != "" && after.RoleAssignmentName == "" {
+		warnings = append(warnings, "spec.roleAssignmentName is deprecated and was moved to spec.systemAssignedIdentityRole.name")
+	}
+	if before.SubnetName != "" && after.SubnetName == "" {
+		warnings = append(warnings, "spec.subnetName is deprecated and was moved to spec.networkInterfaces[0].subnetName")
+	}
+	if before.AcceleratedNetworking != nil && after.AcceleratedNetworking == nil {
+		warnings = append(warnings, "spec.acceleratedNetworking is deprecated and was moved to spec.networkInterfaces[0].acceleratedNetworking")
+	}
+	return warnings
+}
+
 // GetOwnerAzureClusterNameAndNamespace returns the owner azure cluster's name and namespace for the given cluster name and namespace.
 func GetOwnerAzureClusterNameAndNamespace(cli client.Client, clusterName string, namespace string, maxAttempts int) (azureClusterName string, azureClusterNamespace string, err error) {
 	ctx := context.Background()
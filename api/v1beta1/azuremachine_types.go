@@ -77,6 +77,10 @@ type AzureMachineSpec struct {
 	// OSDisk specifies the parameters for the operating system disk of the machine
 	OSDisk OSDisk `json:"osDisk"`
 
+	// PatchSettings specifies the guest OS patching of the VM.
+	// +optional
+	PatchSettings *PatchSettings `json:"patchSettings,omitempty"`
+
 	// DataDisk specifies the parameters that are used to add one or more data disks to the machine
 	// +optional
 	DataDisks []DataDisk `json:"dataDisks,omitempty"`
@@ -96,6 +100,20 @@ type AzureMachineSpec struct {
 	// +optional
 	AdditionalCapabilities *AdditionalCapabilities `json:"additionalCapabilities,omitempty"`
 
+	// PowerState is the desired power state of the virtual machine. A Deallocated virtual machine releases its compute
+	// resources, and a Hibernated virtual machine also preserves its memory, which requires
+	// additionalCapabilities.hibernationEnabled. When unset, CAPZ doesn't change the power state of the virtual
+	// machine, and only reports a virtual machine stopped outside of CAPZ.
+	// +kubebuilder:validation:Enum=Running;Deallocated;Hibernated
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
+
+	// ProvisioningTimeout is how long the virtual machine may exist in Azure without its bootstrap data succeeding or
+	// its node registering before the BootstrapWithinTimeout condition is set to False, which MachineHealthChecks
+	// can target to replace the machine. The timer starts when Azure created the virtual machine.
+	// +optional
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+
 	// AllocatePublicIP allows the ability to create dynamic public ips for machines where this value is true.
 	// +optional
 	AllocatePublicIP bool `json:"allocatePublicIP,omitempty"`
@@ -187,6 +205,10 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *ProvisioningState `json:"vmState,omitempty"`
 
+	// PowerState is the power state of the Azure virtual machine reported by its instance view.
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -233,6 +255,32 @@ type AzureMachineStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ManagedResources records the Azure resources created by CAPZ for this machine, such as network interfaces,
+	// public IPs, disks and inbound NAT rules. They are deleted along with the machine even if they are not part of its
+	// spec anymore, e.g. when they were created by a failed attempt to create the virtual machine. It is unset for
+	// machines created before CAPZ started recording the resources it creates.
+	// +optional
+	ManagedResources *ManagedResources `json:"managedResources,omitempty"`
+
+	// VMCreationTime is when the virtual machine was created. A virtual machine that is not found shortly after its
+	// creation is assumed to not be visible yet due to Azure eventual consistency rather than to have been deleted.
+	// +optional
+	VMCreationTime *metav1.Time `json:"vmCreationTime,omitempty"`
+
+	// LastBootTime is when the virtual machine last started running, as reported by the power state status of its
+	// instance view. It isn't set when the instance view doesn't report that time.
+	// +optional
+	LastBootTime *metav1.Time `json:"lastBootTime,omitempty"`
+
+	// ReconcileBackoff records the consecutive transient failures of the reconciliation of the machine. It is only
+	// set when the TransientErrorBackoff feature is enabled.
+	// +optional
+	ReconcileBackoff *ReconcileBackoff `json:"reconcileBackoff,omitempty"`
+
+	// V1Beta2 groups the conditions of the AzureMachine following the Cluster API v1beta2 contract.
+	// +optional
+	V1Beta2 *V1Beta2Status `json:"v1beta2,omitempty"`
 }
 
 // AdditionalCapabilities enables or disables a capability on the virtual machine.
@@ -242,6 +290,11 @@ type AdditionalCapabilities struct {
 	// otherwise it doesn't set the capability on the VM.
 	// +optional
 	UltraSSDEnabled *bool `json:"ultraSSDEnabled,omitempty"`
+
+	// HibernationEnabled enables or disables hibernation for the virtual machine, allowing it to be hibernated by
+	// setting its powerState to Hibernated. The VM size must support hibernation.
+	// +optional
+	HibernationEnabled *bool `json:"hibernationEnabled,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -287,6 +340,22 @@ func (m *AzureMachine) SetConditions(conditions clusterv1.Conditions) {
 	m.Status.Conditions = conditions
 }
 
+// GetV1Beta2Conditions returns the list of v1beta2 conditions for an AzureMachine API object.
+func (m *AzureMachine) GetV1Beta2Conditions() []metav1.Condition {
+	if m.Status.V1Beta2 == nil {
+		return nil
+	}
+	return m.Status.V1Beta2.Conditions
+}
+
+// SetV1Beta2Conditions will set the given v1beta2 conditions on an AzureMachine object.
+func (m *AzureMachine) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	if m.Status.V1Beta2 == nil {
+		m.Status.V1Beta2 = &V1Beta2Status{}
+	}
+	m.Status.V1Beta2.Conditions = conditions
+}
+
 // GetFutures returns the list of long running operation states for an AzureMachine API object.
 func (m *AzureMachine) GetFutures() Futures {
 	return m.Status.LongRunningOperationStates
@@ -297,6 +366,16 @@ func (m *AzureMachine) SetFutures(futures Futures) {
 	m.Status.LongRunningOperationStates = futures
 }
 
+// GetReconcileBackoff returns the reconcile backoff of an AzureMachine API object.
+func (m *AzureMachine) GetReconcileBackoff() *ReconcileBackoff {
+	return m.Status.ReconcileBackoff
+}
+
+// SetReconcileBackoff will set the given reconcile backoff on an AzureMachine object.
+func (m *AzureMachine) SetReconcileBackoff(backoff *ReconcileBackoff) {
+	m.Status.ReconcileBackoff = backoff
+}
+
 func init() {
 	SchemeBuilder.Register(&AzureMachine{}, &AzureMachineList{})
 }
@@ -19,11 +19,16 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
 
@@ -39,6 +44,14 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateDiskControllerType(spec.OSDisk.DiskControllerType, spec.Image, spec.VMSize, field.NewPath("osDisk", "diskControllerType")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidatePatchSettings(spec.PatchSettings, spec.OSDisk.OSType, spec.VMExtensions, field.NewPath("patchSettings")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := ValidateConfidentialCompute(spec.OSDisk.ManagedDisk, spec.SecurityProfile, field.NewPath("securityProfile")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -67,9 +80,66 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateHibernation(spec.AdditionalCapabilities, spec.PowerState, spec.OSDisk, spec.SpotVMOptions); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateProvisioningTimeout(spec.ProvisioningTimeout, field.NewPath("provisioningTimeout")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	return allErrs
 }
 
+// deprecatedFieldWarnings returns a warning for each deprecated field of the spec which the defaulting webhook can't
+// migrate to its replacement.
+func deprecatedFieldWarnings(spec AzureMachineSpec) []string {
+	var warnings []string
+	if spec.Image != nil && spec.Image.SharedGallery != nil {
+		warnings = append(warnings, "spec.image.sharedGallery is deprecated, use spec.image.computeGallery instead")
+	}
+	return warnings
+}
+
+// ValidateProvisioningTimeout validates that a provisioning timeout is positive.
+func ValidateProvisioningTimeout(provisioningTimeout *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if provisioningTimeout != nil && provisioningTimeout.Duration <= 0 {
+		return field.ErrorList{field.Invalid(fldPath, provisioningTimeout.Duration.String(), "must be greater than zero")}
+	}
+	return nil
+}
+
+// ValidateHibernation validates that a virtual machine is only hibernated if hibernation is enabled, which Azure
+// doesn't support for ephemeral OS disks and Spot virtual machines.
+func ValidateHibernation(additionalCapabilities *AdditionalCapabilities, powerState VMPowerState, osDisk OSDisk, spotVMOptions *SpotVMOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hibernationEnabled := additionalCapabilities.hibernationEnabled()
+
+	if powerState == VMPowerStateHibernated && !hibernationEnabled {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("powerState"), "the virtual machine can only be hibernated if additionalCapabilities.hibernationEnabled is true"))
+	}
+	if !hibernationEnabled {
+		return allErrs
+	}
+
+	fieldPath := field.NewPath("additionalCapabilities", "hibernationEnabled")
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "hibernation can't be enabled with an ephemeral OS disk"))
+	}
+	if spotVMOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "hibernation can't be enabled for Spot virtual machines"))
+	}
+	return allErrs
+}
+
+// hibernationEnabled returns whether hibernation is enabled by the capabilities.
+func (c *AdditionalCapabilities) hibernationEnabled() bool {
+	return c != nil && ptr.Deref(c.HibernationEnabled, false)
+}
+
+// MaxPrivateIPConfigs is the maximum number of private IP configurations Azure supports per network interface.
+const MaxPrivateIPConfigs = 256
+
 // ValidateNetwork validates the network configuration.
 func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInterfaces []NetworkInterface, fldPath *field.Path) field.ErrorList {
 	if (networkInterfaces != nil) && len(networkInterfaces) > 0 && subnetName != "" {
@@ -84,6 +154,9 @@ func ValidateNetwork(subnetName string, acceleratedNetworking *bool, networkInte
 		if nic.PrivateIPConfigs < 1 {
 			return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "number of privateIPConfigs per interface must be at least 1")}
 		}
+		if nic.PrivateIPConfigs > MaxPrivateIPConfigs {
+			return field.ErrorList{field.Invalid(fldPath, networkInterfaces, fmt.Sprintf("number of privateIPConfigs per interface must be at most %d", MaxPrivateIPConfigs))}
+		}
 	}
 
 	return field.ErrorList{}
@@ -243,6 +316,131 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateDiskControllerType validates the disk controller type against the image and the VM size, when they are known
+// to not support it. Whether a VM size of a newer generation supports the disk controller type is checked against its
+// resource SKU when the VM is created.
+func ValidateDiskControllerType(diskControllerType DiskControllerType, image *Image, vmSize string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if diskControllerType != DiskControllerTypeNVMe {
+		return allErrs
+	}
+	if IsGen1MarketplaceImage(image) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, diskControllerType,
+			fmt.Sprintf("the NVMe disk controller requires a generation 2 image, but marketplace image SKU %q is a generation 1 image", image.Marketplace.SKU)))
+	}
+	if IsPreNVMeVMSize(vmSize) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, diskControllerType,
+			fmt.Sprintf("VM size %s doesn't support the NVMe disk controller, which requires a v5 or newer VM size", vmSize)))
+	}
+
+	return allErrs
+}
+
+// customScriptExtensionTypes are the types of the Linux and Windows custom script extensions.
+var customScriptExtensionTypes = []string{"CustomScript", "CustomScriptExtension"}
+
+// rebootCommandRegexp matches the commands rebooting a Linux or Windows VM.
+var rebootCommandRegexp = regexp.MustCompile(`(?i)\b(reboot|shutdown\s+[-/]r|restart-computer)\b`)
+
+// ValidatePatchSettings validates the patch mode against the OS type, that the settings of the AutomaticByPlatform
+// patch mode are only set along with it, and that platform-orchestrated patching isn't combined with custom script
+// extensions that reboot the VM, since such reboots interfere with the installation of patches.
+func ValidatePatchSettings(patchSettings *PatchSettings, osType string, vmExtensions []VMExtension, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if patchSettings == nil {
+		return allErrs
+	}
+
+	if patchSettings.PatchMode != "" {
+		var allowed []PatchMode
+		if osType == WindowsOS {
+			allowed = []PatchMode{PatchModeAutomaticByPlatform, PatchModeAutomaticByOS, PatchModeManual}
+		} else {
+			allowed = []PatchMode{PatchModeAutomaticByPlatform, PatchModeImageDefault}
+		}
+		supported := false
+		for _, mode := range allowed {
+			if patchSettings.PatchMode == mode {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			allErrs = append(allErrs, field.NotSupported(fieldPath.Child("patchMode"), patchSettings.PatchMode, patchModeStrings(allowed)))
+		}
+	}
+
+	if patchSettings.PatchMode != PatchModeAutomaticByPlatform {
+		if patchSettings.BypassPlatformSafetyChecksOnUserSchedule != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("bypassPlatformSafetyChecksOnUserSchedule"),
+				fmt.Sprintf("can only be set with the %s patch mode", PatchModeAutomaticByPlatform)))
+		}
+		if patchSettings.RebootSetting != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rebootSetting"),
+				fmt.Sprintf("can only be set with the %s patch mode", PatchModeAutomaticByPlatform)))
+		}
+		return allErrs
+	}
+
+	for _, extension := range vmExtensions {
+		if isRebootingCustomScriptExtension(extension) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("patchMode"),
+				fmt.Sprintf("the %s patch mode can't be combined with the custom script extension %q, which reboots the VM", PatchModeAutomaticByPlatform, extension.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+func patchModeStrings(modes []PatchMode) []string {
+	strs := make([]string, len(modes))
+	for i, mode := range modes {
+		strs[i] = string(mode)
+	}
+	return strs
+}
+
+// isRebootingCustomScriptExtension returns true if the extension is a custom script extension whose command reboots
+// the VM.
+func isRebootingCustomScriptExtension(extension VMExtension) bool {
+	isCustomScript := false
+	for _, extensionType := range customScriptExtensionTypes {
+		if strings.EqualFold(extension.Name, extensionType) {
+			isCustomScript = true
+			break
+		}
+	}
+	if !isCustomScript {
+		return false
+	}
+	return rebootCommandRegexp.MatchString(extension.Settings["commandToExecute"]) ||
+		rebootCommandRegexp.MatchString(extension.ProtectedSettings["commandToExecute"])
+}
+
+// IsGen1MarketplaceImage returns true if the image is a marketplace image whose SKU is tagged as a generation 1
+// image, e.g. the "ubuntu-2204-gen1" SKU of the CAPZ reference images. Generation 1 images don't support NVMe.
+func IsGen1MarketplaceImage(image *Image) bool {
+	return image != nil && image.Marketplace != nil && strings.HasSuffix(strings.ToLower(image.Marketplace.SKU), "-gen1")
+}
+
+// vmSizeVersionRegexp matches the version suffix of a VM size, e.g. "_v3" in "Standard_D2s_v3".
+var vmSizeVersionRegexp = regexp.MustCompile(`(?i)_v(\d+)$`)
+
+// IsPreNVMeVMSize returns true if the VM size belongs to a generation older than v5, e.g. "Standard_D2s_v3" or
+// "Standard_A2", none of which support the NVMe disk controller. Only some v5 and newer VM sizes support it.
+func IsPreNVMeVMSize(vmSize string) bool {
+	if vmSize == "" {
+		return false
+	}
+	match := vmSizeVersionRegexp.FindStringSubmatch(vmSize)
+	if match == nil {
+		return true
+	}
+	version, err := strconv.Atoi(match[1])
+	return err == nil && version < 5
+}
+
 // validateManagedDisk validates updates to the ManagedDiskParameters field.
 func validateManagedDisk(m *ManagedDiskParameters, fieldPath *field.Path, isOSDisk bool) field.ErrorList {
 	allErrs := field.ErrorList{}
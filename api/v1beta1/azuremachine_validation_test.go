@@ -22,11 +22,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 )
@@ -915,6 +917,16 @@ func TestAzureMachine_ValidateNetwork(t *testing.T) {
 			}},
 			wantErr: true,
 		},
+		{
+			name:                  "invalid config setting privateIPConfigs to more than the maximum",
+			subnetName:            "",
+			acceleratedNetworking: nil,
+			networkInterfaces: []NetworkInterface{{
+				SubnetName:       "subnet1",
+				PrivateIPConfigs: MaxPrivateIPConfigs + 1,
+			}},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -1111,3 +1123,312 @@ func TestAzureMachine_ValidateConfidentialCompute(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureMachine_ValidateDiskControllerType(t *testing.T) {
+	marketplaceImage := func(sku string) *Image {
+		return &Image{
+			Marketplace: &AzureMarketplaceImage{
+				ImagePlan: ImagePlan{Publisher: "cncf-upstream", Offer: "capi", SKU: sku},
+				Version:   "latest",
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		diskControllerType DiskControllerType
+		image              *Image
+		vmSize             string
+		wantErr            bool
+	}{
+		{
+			name:  "no disk controller type",
+			image: marketplaceImage("ubuntu-2204-gen1"),
+		},
+		{
+			name:               "SCSI with a generation 1 image",
+			diskControllerType: DiskControllerTypeSCSI,
+			image:              marketplaceImage("ubuntu-2204-gen1"),
+		},
+		{
+			name:               "NVMe with a generation 2 image",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+		},
+		{
+			name:               "NVMe with an image ID",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              &Image{ID: ptr.To("fake-image-id")},
+		},
+		{
+			name:               "NVMe with the default image",
+			diskControllerType: DiskControllerTypeNVMe,
+		},
+		{
+			name:               "NVMe with a generation 1 image",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen1"),
+			wantErr:            true,
+		},
+		{
+			name:               "NVMe with a v6 VM size",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+			vmSize:             "Standard_D2ds_v6",
+		},
+		{
+			name:               "NVMe with a v5 VM size",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+			vmSize:             "Standard_E2bds_v5",
+		},
+		{
+			name:               "NVMe with a v3 VM size",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+			vmSize:             "Standard_D2s_v3",
+			wantErr:            true,
+		},
+		{
+			name:               "NVMe with an unversioned VM size",
+			diskControllerType: DiskControllerTypeNVMe,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+			vmSize:             "Standard_A2",
+			wantErr:            true,
+		},
+		{
+			name:               "SCSI with a v3 VM size",
+			diskControllerType: DiskControllerTypeSCSI,
+			image:              marketplaceImage("ubuntu-2204-gen2"),
+			vmSize:             "Standard_D2s_v3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateDiskControllerType(tc.diskControllerType, tc.image, tc.vmSize, field.NewPath("osDisk", "diskControllerType"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidatePatchSettings(t *testing.T) {
+	rebootingExtension := VMExtension{
+		Name:      "CustomScript",
+		Publisher: "Microsoft.Azure.Extensions",
+		Version:   "2.1",
+		Settings:  Tags{"commandToExecute": "apt-get install -y foo && shutdown -r now"},
+	}
+
+	tests := []struct {
+		name          string
+		patchSettings *PatchSettings
+		osType        string
+		vmExtensions  []VMExtension
+		wantErr       bool
+	}{
+		{
+			name:   "no patch settings",
+			osType: LinuxOS,
+		},
+		{
+			name: "AutomaticByPlatform on Linux",
+			patchSettings: &PatchSettings{
+				PatchMode:                                PatchModeAutomaticByPlatform,
+				AssessmentMode:                           PatchAssessmentModeAutomaticByPlatform,
+				RebootSetting:                            PatchRebootSettingIfRequired,
+				BypassPlatformSafetyChecksOnUserSchedule: ptr.To(true),
+			},
+			osType: LinuxOS,
+		},
+		{
+			name:          "ImageDefault on Linux",
+			patchSettings: &PatchSettings{PatchMode: PatchModeImageDefault},
+			osType:        LinuxOS,
+		},
+		{
+			name:          "AutomaticByOS on Linux",
+			patchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByOS},
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+		{
+			name:          "Manual on Windows",
+			patchSettings: &PatchSettings{PatchMode: PatchModeManual},
+			osType:        WindowsOS,
+		},
+		{
+			name:          "ImageDefault on Windows",
+			patchSettings: &PatchSettings{PatchMode: PatchModeImageDefault},
+			osType:        WindowsOS,
+			wantErr:       true,
+		},
+		{
+			name:          "reboot setting without AutomaticByPlatform",
+			patchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByOS, RebootSetting: PatchRebootSettingNever},
+			osType:        WindowsOS,
+			wantErr:       true,
+		},
+		{
+			name:          "bypass platform safety checks without a patch mode",
+			patchSettings: &PatchSettings{BypassPlatformSafetyChecksOnUserSchedule: ptr.To(true)},
+			osType:        LinuxOS,
+			wantErr:       true,
+		},
+		{
+			name:          "AutomaticByPlatform with a rebooting custom script extension",
+			patchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByPlatform},
+			osType:        LinuxOS,
+			vmExtensions:  []VMExtension{rebootingExtension},
+			wantErr:       true,
+		},
+		{
+			name:          "AutomaticByPlatform with a rebooting Windows custom script extension",
+			patchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByPlatform},
+			osType:        WindowsOS,
+			vmExtensions: []VMExtension{{
+				Name:              "CustomScriptExtension",
+				Publisher:         "Microsoft.Compute",
+				Version:           "1.10",
+				ProtectedSettings: Tags{"commandToExecute": "powershell -Command Restart-Computer -Force"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:          "AutomaticByPlatform with a custom script extension that doesn't reboot",
+			patchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByPlatform},
+			osType:        LinuxOS,
+			vmExtensions: []VMExtension{{
+				Name:      "CustomScript",
+				Publisher: "Microsoft.Azure.Extensions",
+				Version:   "2.1",
+				Settings:  Tags{"commandToExecute": "echo rebooted-at > /tmp/marker"},
+			}},
+		},
+		{
+			name:          "ImageDefault with a rebooting custom script extension",
+			patchSettings: &PatchSettings{PatchMode: PatchModeImageDefault},
+			osType:        LinuxOS,
+			vmExtensions:  []VMExtension{rebootingExtension},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidatePatchSettings(tc.patchSettings, tc.osType, tc.vmExtensions, field.NewPath("patchSettings"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateHibernation(t *testing.T) {
+	tests := []struct {
+		name                   string
+		additionalCapabilities *AdditionalCapabilities
+		powerState             VMPowerState
+		osDisk                 OSDisk
+		spotVMOptions          *SpotVMOptions
+		wantErr                bool
+	}{
+		{
+			name: "hibernation not enabled",
+		},
+		{
+			name:       "deallocated without hibernation",
+			powerState: VMPowerStateDeallocated,
+		},
+		{
+			name:                   "hibernated with hibernation enabled",
+			additionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+			powerState:             VMPowerStateHibernated,
+		},
+		{
+			name:       "hibernated without hibernation enabled",
+			powerState: VMPowerStateHibernated,
+			wantErr:    true,
+		},
+		{
+			name:                   "hibernated with hibernation disabled",
+			additionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(false)},
+			powerState:             VMPowerStateHibernated,
+			wantErr:                true,
+		},
+		{
+			name:                   "hibernation enabled with an ephemeral OS disk",
+			additionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+			osDisk:                 OSDisk{DiffDiskSettings: &DiffDiskSettings{Option: "Local"}},
+			wantErr:                true,
+		},
+		{
+			name:                   "hibernation enabled for a Spot VM",
+			additionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+			spotVMOptions:          &SpotVMOptions{},
+			wantErr:                true,
+		},
+		{
+			name:                   "hibernation disabled for a Spot VM",
+			additionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(false)},
+			spotVMOptions:          &SpotVMOptions{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateHibernation(tc.additionalCapabilities, tc.powerState, tc.osDisk, tc.spotVMOptions)
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateProvisioningTimeout(t *testing.T) {
+	tests := []struct {
+		name                string
+		provisioningTimeout *metav1.Duration
+		wantErr             bool
+	}{
+		{
+			name: "no provisioning timeout",
+		},
+		{
+			name:                "positive provisioning timeout",
+			provisioningTimeout: &metav1.Duration{Duration: 30 * time.Minute},
+		},
+		{
+			name:                "zero provisioning timeout",
+			provisioningTimeout: &metav1.Duration{},
+			wantErr:             true,
+		},
+		{
+			name:                "negative provisioning timeout",
+			provisioningTimeout: &metav1.Duration{Duration: -time.Minute},
+			wantErr:             true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateProvisioningTimeout(tc.provisioningTimeout, field.NewPath("provisioningTimeout"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
@@ -32,9 +32,11 @@ import (
 // SetupAzureMachineWebhookWithManager sets up and registers the webhook with the manager.
 func SetupAzureMachineWebhookWithManager(mgr ctrl.Manager) error {
 	mw := &azureMachineWebhook{Client: mgr.GetClient()}
+	// The defaulting webhook is registered separately to return warnings about the deprecated fields it migrates.
+	mgr.GetWebhookServer().Register("/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachine",
+		webhookutils.DefaulterWithWarnings(mgr.GetScheme(), &AzureMachine{}, mw))
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureMachine{}).
-		WithDefaulter(mw).
 		WithValidator(mw).
 		Complete()
 }
@@ -66,11 +68,12 @@ func (mw *azureMachineWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings := deprecatedFieldWarnings(spec)
 	if len(allErrs) == 0 {
-		return nil, nil
+		return warnings, nil
 	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind(AzureMachineKind).GroupKind(), m.Name, allErrs)
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureMachineKind).GroupKind(), m.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -172,6 +175,13 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "PatchSettings"),
+		old.Spec.PatchSettings,
+		m.Spec.PatchSettings); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "SecurityProfile"),
 		old.Spec.SecurityProfile,
@@ -179,6 +189,16 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "AdditionalCapabilities", "HibernationEnabled"),
+		old.Spec.AdditionalCapabilities.hibernationEnabled(),
+		m.Spec.AdditionalCapabilities.hibernationEnabled()); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// The power state can be changed, but a virtual machine can't be hibernated without hibernation enabled.
+	allErrs = append(allErrs, ValidateHibernation(m.Spec.AdditionalCapabilities, m.Spec.PowerState, m.Spec.OSDisk, m.Spec.SpotVMOptions)...)
+
 	if old.Spec.Diagnostics != nil {
 		if err := webhookutils.ValidateImmutable(
 			field.NewPath("Spec", "Diagnostics"),
@@ -197,6 +217,15 @@ func (mw *azureMachineWebhook) ValidateUpdate(ctx context.Context, oldObj, newOb
 			old.Spec.NetworkInterfaces[0].SubnetName = m.Spec.NetworkInterfaces[0].SubnetName
 		}
 
+		// Secondary IP configurations can be added to and removed from existing network interfaces.
+		if len(old.Spec.NetworkInterfaces) == len(m.Spec.NetworkInterfaces) {
+			for i := range old.Spec.NetworkInterfaces {
+				if m.Spec.NetworkInterfaces[i].PrivateIPConfigs > 0 {
+					old.Spec.NetworkInterfaces[i].PrivateIPConfigs = m.Spec.NetworkInterfaces[i].PrivateIPConfigs
+				}
+			}
+		}
+
 		// Enforce immutability for all other changes to NetworkInterfaces.
 		if !reflect.DeepEqual(m.Spec.NetworkInterfaces, old.Spec.NetworkInterfaces) {
 			allErrs = append(allErrs,
@@ -223,5 +252,8 @@ func (mw *azureMachineWebhook) Default(ctx context.Context, obj runtime.Object)
 	if !ok {
 		return apierrors.NewBadRequest("expected an AzureMachine resource")
 	}
-	return m.SetDefaults(mw.Client)
+	before := m.Spec.DeepCopy()
+	err := m.SetDefaults(mw.Client)
+	webhookutils.AddDefaulterWarnings(ctx, deprecatedFieldMigrationWarnings(before, &m.Spec)...)
+	return err
 }
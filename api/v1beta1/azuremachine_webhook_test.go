@@ -18,17 +18,22 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
+	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var (
@@ -693,6 +698,59 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.AdditionalCapabilities.HibernationEnabled is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.PowerState is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalCapabilities: &AdditionalCapabilities{HibernationEnabled: ptr.To(true)},
+					PowerState:             VMPowerStateHibernated,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.PowerState can't be Hibernated without hibernation enabled",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					PowerState: VMPowerStateHibernated,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.PatchSettings is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					PatchSettings: &PatchSettings{PatchMode: PatchModeImageDefault},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					PatchSettings: &PatchSettings{PatchMode: PatchModeAutomaticByPlatform},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.Diagnostics is immutable",
 			oldMachine: &AzureMachine{
@@ -791,6 +849,65 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "validtest: adding secondary private IP configurations",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 1}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 3}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validtest: removing secondary private IP configurations",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 3}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 2}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validtest: removing all secondary private IP configurations",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 3}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 1}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidtest: adding a network interface",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{{SubnetName: "subnet1", PrivateIPConfigs: 1}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					NetworkInterfaces: []NetworkInterface{
+						{SubnetName: "subnet1", PrivateIPConfigs: 1},
+						{SubnetName: "subnet2", PrivateIPConfigs: 1},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidtest: updating subnet name from empty to non empty",
 			oldMachine: &AzureMachine{
@@ -884,6 +1001,56 @@ func TestAzureMachine_Default(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_DefaultWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	mw := &azureMachineWebhook{
+		Client: mockDefaultClient{SubscriptionID: "test-subscription-id"},
+	}
+	machine := createMachineWithNetworkConfig("subnet1", ptr.To(true), nil)
+	machine.Labels = map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+	machine.Spec.RoleAssignmentName = "role-assignment"
+	machine.Spec.Identity = VMIdentitySystemAssigned
+
+	ctx := context.Background()
+	raw, err := json.Marshal(machine)
+	g.Expect(err).NotTo(HaveOccurred())
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	resp := webhookutils.DefaulterWithWarnings(scheme, &AzureMachine{}, mw).Handle(ctx, admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	g.Expect(resp.Allowed).To(BeTrue())
+	g.Expect(resp.Warnings).To(ConsistOf(
+		"spec.roleAssignmentName is deprecated and was moved to spec.systemAssignedIdentityRole.name",
+		"spec.subnetName is deprecated and was moved to spec.networkInterfaces[0].subnetName",
+		"spec.acceleratedNetworking is deprecated and was moved to spec.networkInterfaces[0].acceleratedNetworking",
+	))
+
+	// Defaulting outside of the webhook still migrates the deprecated fields.
+	g.Expect(mw.Default(ctx, machine)).To(Succeed())
+	g.Expect(machine.Spec.SubnetName).To(BeEmpty())
+	g.Expect(machine.Spec.NetworkInterfaces).To(HaveLen(1))
+	g.Expect(machine.Spec.NetworkInterfaces[0].SubnetName).To(Equal("subnet1"))
+	g.Expect(machine.Spec.SystemAssignedIdentityRole.Name).To(Equal("role-assignment"))
+}
+
+func TestAzureMachine_ValidateCreateWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	mw := &azureMachineWebhook{}
+	warnings, err := mw.ValidateCreate(context.Background(), createMachineWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ConsistOf("spec.image.sharedGallery is deprecated, use spec.image.computeGallery instead"))
+
+	warnings, err = mw.ValidateCreate(context.Background(), createMachineWithImageByID("ID123"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
+
 func createMachineWithNetworkConfig(subnetName string, acceleratedNetworking *bool, interfaces []NetworkInterface) *AzureMachine {
 	return &AzureMachine{
 		Spec: AzureMachineSpec{
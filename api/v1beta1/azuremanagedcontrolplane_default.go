@@ -192,6 +192,14 @@ func setDefaultAutoScalerProfile(autoScalerProfile *AutoScalerProfile) *AutoScal
 	return result
 }
 
+// setDefaultSecurityProfile defaults the network access of the key vault of the KMS etcd encryption to Public.
+func setDefaultSecurityProfile(securityProfile *ManagedClusterSecurityProfile) {
+	if securityProfile == nil || securityProfile.AzureKeyVaultKms == nil {
+		return
+	}
+	setDefault[*KeyVaultNetworkAccessTypes](&securityProfile.AzureKeyVaultKms.KeyVaultNetworkAccess, ptr.To(KeyVaultNetworkAccessTypesPublic))
+}
+
 func (m *AzureManagedControlPlane) setDefaultOIDCIssuerProfile() {
 	if m.Spec.OIDCIssuerProfile == nil {
 		m.Spec.OIDCIssuerProfile = &OIDCIssuerProfile{}
@@ -138,3 +138,47 @@ func TestSetDefaultAutoScalerProfile(t *testing.T) {
 
 	g.Expect(allFieldsAreNotNilTest.amcp.Spec.AutoScalerProfile).To(Equal(expectedNotNil.Spec.AutoScalerProfile))
 }
+
+func TestSetDefaultSecurityProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		securityProfile *ManagedClusterSecurityProfile
+		expected        *ManagedClusterSecurityProfile
+	}{
+		{
+			name:            "nil security profile",
+			securityProfile: nil,
+			expected:        nil,
+		},
+		{
+			name:            "no KMS",
+			securityProfile: &ManagedClusterSecurityProfile{},
+			expected:        &ManagedClusterSecurityProfile{},
+		},
+		{
+			name: "KMS without network access defaults to Public",
+			securityProfile: &ManagedClusterSecurityProfile{
+				AzureKeyVaultKms: &AzureKeyVaultKms{Enabled: true, KeyID: "key"},
+			},
+			expected: &ManagedClusterSecurityProfile{
+				AzureKeyVaultKms: &AzureKeyVaultKms{Enabled: true, KeyID: "key", KeyVaultNetworkAccess: ptr.To(KeyVaultNetworkAccessTypesPublic)},
+			},
+		},
+		{
+			name: "KMS network access is kept",
+			securityProfile: &ManagedClusterSecurityProfile{
+				AzureKeyVaultKms: &AzureKeyVaultKms{Enabled: true, KeyID: "key", KeyVaultNetworkAccess: ptr.To(KeyVaultNetworkAccessTypesPrivate)},
+			},
+			expected: &ManagedClusterSecurityProfile{
+				AzureKeyVaultKms: &AzureKeyVaultKms{Enabled: true, KeyID: "key", KeyVaultNetworkAccess: ptr.To(KeyVaultNetworkAccessTypesPrivate)},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			setDefaultSecurityProfile(tc.securityProfile)
+			g.Expect(tc.securityProfile).To(Equal(tc.expected))
+		})
+	}
+}
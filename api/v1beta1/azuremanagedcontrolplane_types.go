@@ -31,6 +31,9 @@ const (
 
 	// PrivateDNSZoneModeNone represents mode None for azuremanagedcontrolplane.
 	PrivateDNSZoneModeNone string = "None"
+
+	// NetAppVolumesDelegation is the service subnets hosting Azure NetApp Files volumes are delegated to.
+	NetAppVolumesDelegation = "Microsoft.NetApp/volumes"
 )
 
 // UpgradeChannel determines the type of upgrade channel for automatically upgrading the cluster.
@@ -65,6 +68,23 @@ const (
 	UpgradeChannelStable UpgradeChannel = "stable"
 )
 
+// NodeOSUpgradeChannel determines the manner in which the OS on the nodes is updated.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/en-us/azure/aks/auto-upgrade-node-os-image
+type NodeOSUpgradeChannel string
+
+const (
+	// NodeOSUpgradeChannelNodeImage updates the node image of the nodes to the latest version available, typically weekly.
+	NodeOSUpgradeChannelNodeImage NodeOSUpgradeChannel = "NodeImage"
+
+	// NodeOSUpgradeChannelNone applies no OS updates to the nodes, which are only updated on node image upgrades.
+	NodeOSUpgradeChannelNone NodeOSUpgradeChannel = "None"
+
+	// NodeOSUpgradeChannelUnmanaged lets the OS apply its own updates, nodes only being rebooted by the user.
+	NodeOSUpgradeChannelUnmanaged NodeOSUpgradeChannel = "Unmanaged"
+)
+
 // ManagedControlPlaneOutboundType enumerates the values for the managed control plane OutboundType.
 type ManagedControlPlaneOutboundType string
 
@@ -166,12 +186,54 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	DNSPrefix *string `json:"dnsPrefix,omitempty"`
 
+	// DeletionPolicy determines whether the AKS cluster and the other Azure resources of the cluster are deleted along
+	// with the AzureManagedControlPlane. With Retain, the Azure resources are left in place and only the Kubernetes
+	// objects are deleted. Changing the policy of an existing AzureManagedControlPlane to Retain requires the
+	// infrastructure.cluster.x-k8s.io/confirm-retain-resources annotation to be set to "true".
+	// Defaults to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// FleetsMember is the spec for the fleet this cluster is a member of.
 	// See also [AKS doc].
 	//
 	// [AKS doc]: https://learn.microsoft.com/en-us/azure/templates/microsoft.containerservice/2023-03-15-preview/fleets/members
 	// +optional
 	FleetsMember *FleetsMember `json:"fleetsMember,omitempty"`
+
+	// APIServerDNS configures a CNAME record pointing at the FQDN of the API server of the managed cluster in an
+	// Azure DNS zone. useForControlPlaneEndpoint isn't supported since the certificate of the API server doesn't
+	// include the name of the record.
+	// +optional
+	APIServerDNS *APIServerDNS `json:"apiServerDNS,omitempty"`
+}
+
+// PodIdentityProfile is the AAD pod identity profile of the managed cluster.
+type PodIdentityProfile struct {
+	// Enabled is whether the pod identity addon is enabled.
+	Enabled bool `json:"enabled"`
+
+	// AllowNetworkPluginKubenet allows the pod identity addon to run on clusters using the kubenet network plugin,
+	// which is disabled by default due to the risk of IP spoofing.
+	// +optional
+	AllowNetworkPluginKubenet *bool `json:"allowNetworkPluginKubenet,omitempty"`
+
+	// UserAssignedIdentityExceptions are the pods allowed to access the instance metadata endpoint without being
+	// intercepted by the node managed identity daemon.
+	// +optional
+	UserAssignedIdentityExceptions []PodIdentityException `json:"userAssignedIdentityExceptions,omitempty"`
+}
+
+// PodIdentityException is a pod identity exception, matching pods by their labels.
+type PodIdentityException struct {
+	// Name is the name of the pod identity exception.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the pods the exception applies to.
+	Namespace string `json:"namespace"`
+
+	// PodLabels are the labels of the pods the exception applies to.
+	PodLabels map[string]string `json:"podLabels"`
 }
 
 // ManagedClusterSecurityProfile defines the security profile for the cluster.
@@ -268,6 +330,33 @@ type AzureKeyVaultKms struct {
 	KeyVaultResourceID *string `json:"keyVaultResourceID,omitempty"`
 }
 
+// ManagedClusterWindowsProfile configures the Windows nodes of the cluster.
+type ManagedClusterWindowsProfile struct {
+	// GmsaProfile configures Windows group managed service accounts (gMSA) on the cluster.
+	// +optional
+	GmsaProfile *WindowsGmsaProfile `json:"gmsaProfile,omitempty"`
+}
+
+// WindowsGmsaProfile configures Windows group managed service accounts (gMSA) on the cluster.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/azure/aks/use-group-managed-service-accounts
+type WindowsGmsaProfile struct {
+	// Enabled enables Windows gMSA on the cluster.
+	// +kubebuilder:validation:Required
+	Enabled bool `json:"enabled"`
+
+	// DNSServer is the DNS server for Windows gMSA. It must be set together with RootDomainName, and can be left
+	// empty if the DNS server is configured on the virtual network of the cluster.
+	// +optional
+	DNSServer *string `json:"dnsServer,omitempty"`
+
+	// RootDomainName is the root domain name for Windows gMSA. It must be set together with DNSServer, and can be
+	// left empty if the DNS server is configured on the virtual network of the cluster.
+	// +optional
+	RootDomainName *string `json:"rootDomainName,omitempty"`
+}
+
 // HTTPProxyConfig is the HTTP proxy configuration for the cluster.
 type HTTPProxyConfig struct {
 	// HTTPProxy is the HTTP proxy server endpoint to use.
@@ -292,7 +381,8 @@ type HTTPProxyConfig struct {
 //
 // [AKS doc]: https://learn.microsoft.com/azure/aks/managed-aad
 type AADProfile struct {
-	// Managed - Whether to enable managed AAD.
+	// Managed - Whether to enable managed AAD. Clusters using the legacy AAD integration, which CAPZ keeps unchanged
+	// while Managed is false, can be migrated to managed AAD by setting it to true. The migration can't be reverted.
 	// +kubebuilder:validation:Required
 	Managed bool `json:"managed"`
 
@@ -301,6 +391,26 @@ type AADProfile struct {
 	AdminGroupObjectIDs []string `json:"adminGroupObjectIDs"`
 }
 
+const (
+	// PreserveUnmanagedAddonsAnnotation can be set to "true" on an AzureManagedControlPlane to keep the add-ons that
+	// were enabled outside of CAPZ and aren't declared in its addonProfiles, instead of disabling them.
+	PreserveUnmanagedAddonsAnnotation = "infrastructure.cluster.x-k8s.io/preserve-unmanaged-addons"
+
+	// ProtectNodeResourceGroupAnnotation can be set to "true" on an AzureManagedControlPlane to only delete it once its
+	// node resource group contains only resources created by AKS, so that resources placed there by users aren't
+	// deleted along with the cluster.
+	ProtectNodeResourceGroupAnnotation = "infrastructure.cluster.x-k8s.io/protect-node-resource-group"
+
+	// AzureKeyvaultSecretsProviderAddonName is the name of the Azure Key Vault Secrets Provider add-on.
+	AzureKeyvaultSecretsProviderAddonName = "azureKeyvaultSecretsProvider"
+	// AzureKeyvaultSecretsProviderEnableSecretRotationKey is the config key of the Azure Key Vault Secrets Provider
+	// add-on enabling the rotation of the mounted secrets, either "true" or "false".
+	AzureKeyvaultSecretsProviderEnableSecretRotationKey = "enableSecretRotation"
+	// AzureKeyvaultSecretsProviderRotationPollIntervalKey is the config key of the Azure Key Vault Secrets Provider
+	// add-on setting the interval at which the mounted secrets are rotated, e.g. "2m".
+	AzureKeyvaultSecretsProviderRotationPollIntervalKey = "rotationPollInterval"
+)
+
 // AddonProfile represents a managed cluster add-on.
 type AddonProfile struct {
 	// Name - The name of the managed cluster add-on.
@@ -361,6 +471,20 @@ type LoadBalancerProfile struct {
 	IdleTimeoutInMinutes *int `json:"idleTimeoutInMinutes,omitempty"`
 }
 
+// NatGatewayProfile - Profile of the NAT gateway AKS creates for the cluster when OutboundType is managedNATGateway.
+// See also [AKS doc].
+//
+// [AKS doc]: https://learn.microsoft.com/azure/aks/nat-gateway
+type NatGatewayProfile struct {
+	// ManagedOutboundIPCount - Desired number of outbound IPs AKS creates for the NAT gateway. Allowed values must be in the range of 1 to 16 (inclusive). The default value is 1.
+	// +optional
+	ManagedOutboundIPCount *int `json:"managedOutboundIPCount,omitempty"`
+
+	// IdleTimeoutInMinutes - Desired outbound flow idle timeout in minutes. Allowed values must be in the range of 4 to 120 (inclusive). The default value is 4 minutes.
+	// +optional
+	IdleTimeoutInMinutes *int `json:"idleTimeoutInMinutes,omitempty"`
+}
+
 // APIServerAccessProfile tunes the accessibility of the cluster's control plane.
 // See also [AKS doc].
 //
@@ -394,6 +518,28 @@ type ManagedControlPlaneSubnet struct {
 	// PrivateEndpoints is a slice of Virtual Network private endpoints to create for the subnets.
 	// +optional
 	PrivateEndpoints PrivateEndpoints `json:"privateEndpoints,omitempty"`
+
+	// NatGatewayID is the Azure resource ID of an existing NAT gateway associated with the subnet, which the nodes
+	// use for outbound traffic. It is required when OutboundType is userAssignedNATGateway. CAPZ associates the NAT
+	// gateway with the subnet when it manages the virtual network.
+	// +optional
+	NatGatewayID string `json:"natGatewayID,omitempty"`
+}
+
+// ManagedControlPlaneAdditionalSubnet describes an additional subnet of the virtual network of an
+// AzureManagedControlPlane.
+type ManagedControlPlaneAdditionalSubnet struct {
+	// Name is the name of the subnet.
+	Name string `json:"name"`
+
+	// CIDRBlock is the address space of the subnet.
+	CIDRBlock string `json:"cidrBlock"`
+
+	// Delegations are the services the subnet is delegated to, e.g. Microsoft.NetApp/volumes for a subnet hosting
+	// Azure NetApp Files volumes. A subnet delegated to Microsoft.NetApp/volumes must be at least a /28 and can't be
+	// the node or pod subnet of a node pool.
+	// +optional
+	Delegations []string `json:"delegations,omitempty"`
 }
 
 // AzureManagedControlPlaneStatus defines the observed state of AzureManagedControlPlane.
@@ -426,9 +572,38 @@ type AzureManagedControlPlaneStatus struct {
 	// +optional
 	OIDCIssuerProfile *OIDCIssuerProfileStatus `json:"oidcIssuerProfile,omitempty"`
 
+	// AADProfile is the AAD integration in effect on the Managed Cluster, which may still be the legacy AAD integration
+	// while a migration to managed AAD is in progress.
+	// +optional
+	AADProfile *AADProfileStatus `json:"aadProfile,omitempty"`
+
 	// Version defines the Kubernetes version for the control plane instance.
 	// +optional
 	Version string `json:"version"`
+
+	// NodeResourceGroupName is the name of the resource group AKS created for the resources of the Managed Cluster,
+	// e.g. its nodes.
+	// +optional
+	NodeResourceGroupName string `json:"nodeResourceGroupName,omitempty"`
+
+	// OutboundIPs are the public IP addresses the Managed Cluster egresses from, i.e. the addresses of the effective
+	// outbound IPs of its load balancer or NAT gateway.
+	// +optional
+	OutboundIPs []string `json:"outboundIPs,omitempty"`
+
+	// IdentityPrincipalID is the principal ID of the system-assigned identity of the Managed Cluster, once AKS
+	// created it.
+	// +optional
+	IdentityPrincipalID string `json:"identityPrincipalID,omitempty"`
+
+	// ManagedResources records the Azure resources created by CAPZ for this managed cluster. Unlike for AzureCluster,
+	// the ownership of managed cluster resources is still determined from resource tags and ASO owner references.
+	// +optional
+	ManagedResources *ManagedResources `json:"managedResources,omitempty"`
+
+	// V1Beta2 groups the conditions of the AzureManagedControlPlane following the Cluster API v1beta2 contract.
+	// +optional
+	V1Beta2 *V1Beta2Status `json:"v1beta2,omitempty"`
 }
 
 // OIDCIssuerProfileStatus is the OIDC issuer profile of the Managed Cluster.
@@ -438,6 +613,16 @@ type OIDCIssuerProfileStatus struct {
 	IssuerURL *string `json:"issuerURL,omitempty"`
 }
 
+// AADProfileStatus is the AAD integration in effect on the Managed Cluster.
+type AADProfileStatus struct {
+	// Managed is whether the Managed Cluster uses managed AAD rather than the legacy AAD integration.
+	Managed bool `json:"managed"`
+
+	// AdminGroupObjectIDs are the AAD group object IDs that have the admin role of the cluster.
+	// +optional
+	AdminGroupObjectIDs []string `json:"adminGroupObjectIDs,omitempty"`
+}
+
 // AutoScalerProfile parameters to be applied to the cluster-autoscaler.
 // See also [AKS doc], [K8s doc].
 //
@@ -667,6 +852,22 @@ func (m *AzureManagedControlPlane) SetConditions(conditions clusterv1.Conditions
 	m.Status.Conditions = conditions
 }
 
+// GetV1Beta2Conditions returns the list of v1beta2 conditions for an AzureManagedControlPlane API object.
+func (m *AzureManagedControlPlane) GetV1Beta2Conditions() []metav1.Condition {
+	if m.Status.V1Beta2 == nil {
+		return nil
+	}
+	return m.Status.V1Beta2.Conditions
+}
+
+// SetV1Beta2Conditions will set the given v1beta2 conditions on an AzureManagedControlPlane object.
+func (m *AzureManagedControlPlane) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	if m.Status.V1Beta2 == nil {
+		m.Status.V1Beta2 = &V1Beta2Status{}
+	}
+	m.Status.V1Beta2.Conditions = conditions
+}
+
 // GetFutures returns the list of long running operation states for an AzureManagedControlPlane API object.
 func (m *AzureManagedControlPlane) GetFutures() Futures {
 	return m.Status.LongRunningOperationStates
@@ -26,8 +26,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
@@ -48,9 +52,12 @@ var (
 	rScanInterval              = regexp.MustCompile(`^(\d+)s$`)
 )
 
+// minMaintenanceWindowHours is the minimum length of a planned maintenance window accepted by AKS.
+const minMaintenanceWindowHours = 4
+
 // SetupAzureManagedControlPlaneWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureManagedControlPlaneWebhook{Client: mgr.GetClient()}
+func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager, allowlist PlacementAllowlist) error {
+	mw := &azureManagedControlPlaneWebhook{Client: mgr.GetClient(), allowlist: allowlist}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureManagedControlPlane{}).
 		WithDefaulter(mw).
@@ -62,7 +69,8 @@ func SetupAzureManagedControlPlaneWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureManagedControlPlaneWebhook implements a validating and defaulting webhook for AzureManagedControlPlane.
 type azureManagedControlPlaneWebhook struct {
-	Client client.Client
+	Client    client.Client
+	allowlist PlacementAllowlist
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -83,8 +91,18 @@ func (mw *azureManagedControlPlaneWebhook) Default(ctx context.Context, obj runt
 	})
 	m.Spec.Version = setDefaultVersion(m.Spec.Version)
 	m.Spec.SKU = setDefaultSku(m.Spec.SKU)
-	m.Spec.AutoScalerProfile = setDefaultAutoScalerProfile(m.Spec.AutoScalerProfile)
+	// The autoscaler profile of a cluster cloned from a template is merged with the one of the template when the
+	// cluster is reconciled, so the keys it leaves unset must not be defaulted here when the template sets them.
+	template, err := GetAzureManagedControlPlaneTemplate(ctx, mw.Client, m)
+	if err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "failed to get the AzureManagedControlPlaneTemplate of the cluster"))
+	}
+	if template == nil || template.Spec.Template.Spec.AutoScalerProfile == nil {
+		m.Spec.AutoScalerProfile = setDefaultAutoScalerProfile(m.Spec.AutoScalerProfile)
+	}
 	m.Spec.FleetsMember = setDefaultFleetsMember(m.Spec.FleetsMember, m.Labels)
+	setDefaultAPIServerDNS(m.Spec.APIServerDNS)
+	setDefaultSecurityProfile(m.Spec.SecurityProfile)
 
 	if err := m.setDefaultSSHPublicKey(); err != nil {
 		ctrl.Log.WithName("AzureManagedControlPlaneWebHookLogger").Error(err, "setDefaultSSHPublicKey failed")
@@ -118,7 +136,29 @@ func (mw *azureManagedControlPlaneWebhook) ValidateCreate(ctx context.Context, o
 		)
 	}
 
-	return nil, m.Validate(mw.Client)
+	warnings := m.podIdentityProfileWarnings()
+	warnings = append(warnings, m.Spec.dockerBridgeCIDRWarnings(field.NewPath("spec", "dockerBridgeCidr"))...)
+	if err := m.Validate(mw.Client); err != nil {
+		return warnings, err
+	}
+
+	if err := m.validateIdentityRef(); err != nil {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneKind).GroupKind(), m.Name, field.ErrorList{err})
+	}
+
+	allErrs, err := validatePlacement(ctx, mw.allowlist, placement{
+		subscriptionID:   m.Spec.SubscriptionID,
+		subscriptionPath: field.NewPath("spec", "subscriptionID"),
+		location:         m.Spec.Location,
+		locationPath:     field.NewPath("spec", "location"),
+	})
+	if err != nil {
+		return warnings, err
+	}
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneKind).GroupKind(), m.Name, allErrs)
+	}
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -141,7 +181,6 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		{field.NewPath("Spec", "SubscriptionID"), old.Spec.SubscriptionID, m.Spec.SubscriptionID},
 		{field.NewPath("Spec", "ResourceGroupName"), old.Spec.ResourceGroupName, m.Spec.ResourceGroupName},
 		{field.NewPath("Spec", "NodeResourceGroupName"), old.Spec.NodeResourceGroupName, m.Spec.NodeResourceGroupName},
-		{field.NewPath("Spec", "Location"), old.Spec.Location, m.Spec.Location},
 		{field.NewPath("Spec", "SSHPublicKey"), old.Spec.SSHPublicKey, m.Spec.SSHPublicKey},
 		{field.NewPath("Spec", "DNSServiceIP"), old.Spec.DNSServiceIP, m.Spec.DNSServiceIP},
 		{field.NewPath("Spec", "NetworkPlugin"), old.Spec.NetworkPlugin, m.Spec.NetworkPlugin},
@@ -158,6 +197,16 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		}
 	}
 
+	if err := validateLocationUpdate(old.Spec.Location, m.Spec.Location, field.NewPath("Spec", "Location")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if old.Spec.IdentityRef != nil {
+		if err := m.validateIdentityRef(); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
 	// This nil check is only to streamline tests from having to define this correctly in every test case.
 	// Normally, the defaulting webhooks will always set the new DNSPrefix so users can never entirely unset it.
 	if m.Spec.DNSPrefix != nil {
@@ -177,6 +226,14 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		}
 	}
 
+	if err := validateDeletionPolicyUpdate(
+		old.Spec.DeletionPolicy,
+		m.Spec.DeletionPolicy,
+		m.Annotations,
+		field.NewPath("spec", "deletionPolicy")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	// Consider removing this once moves out of preview
 	// Updating outboundType after cluster creation (PREVIEW)
 	// https://learn.microsoft.com/en-us/azure/aks/egress-outboundtype#updating-outboundtype-after-cluster-creation-preview
@@ -223,6 +280,10 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := validateAPIServerDNSUpdate(old.Spec.APIServerDNS, m.Spec.APIServerDNS, field.NewPath("spec", "apiServerDNS")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := validateAKSExtensionsUpdate(old.Spec.Extensions, m.Spec.Extensions); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -231,11 +292,19 @@ func (mw *azureManagedControlPlaneWebhook) ValidateUpdate(ctx context.Context, o
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings := m.podIdentityProfileWarnings()
+	warnings = append(warnings, m.Spec.dockerBridgeCIDRWarnings(field.NewPath("spec", "dockerBridgeCidr"))...)
+	template, err := GetAzureManagedControlPlaneTemplate(ctx, mw.Client, m)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to get the AzureManagedControlPlaneTemplate of the cluster to check its autoscaler profile: %v", err))
+	}
+	warnings = append(warnings, clearedAutoScalerProfileWarnings(old, m, template)...)
+
 	if len(allErrs) == 0 {
-		return nil, m.Validate(mw.Client)
+		return warnings, m.Validate(mw.Client)
 	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneKind).GroupKind(), m.Name, allErrs)
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneKind).GroupKind(), m.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -253,6 +322,8 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 		m.validateNetworkPluginMode,
 		m.validateDNSPrefix,
 		m.validateDisableLocalAccounts,
+		m.validatePodIdentityProfile,
+		m.validateNetAppVolumesSubnets,
 	}
 	for _, validator := range validators {
 		if err := validator(cli); err != nil {
@@ -268,6 +339,12 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 		m.Spec.LoadBalancerProfile,
 		field.NewPath("Spec").Child("LoadBalancerProfile"))...)
 
+	allErrs = append(allErrs, validateOutboundType(
+		m.Spec.OutboundType,
+		m.Spec.NatGatewayProfile,
+		m.Spec.VirtualNetwork.Subnet,
+		field.NewPath("Spec"))...)
+
 	allErrs = append(allErrs, validateManagedClusterNetwork(
 		cli,
 		m.Labels,
@@ -280,6 +357,18 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 
 	allErrs = append(allErrs, validateAutoScalerProfile(m.Spec.AutoScalerProfile, field.NewPath("spec").Child("AutoScalerProfile"))...)
 
+	allErrs = append(allErrs, validateUpgradeChannel(m.Spec.AutoUpgradeProfile, field.NewPath("spec").Child("autoUpgradeProfile").Child("upgradeChannel"))...)
+
+	allErrs = append(allErrs, validateNodeOSUpgradeChannel(m.Spec.AutoUpgradeProfile, field.NewPath("spec").Child("autoUpgradeProfile").Child("nodeOSUpgradeChannel"))...)
+
+	allErrs = append(allErrs, validateMaintenanceWindow(m.Spec.MaintenanceWindow, field.NewPath("spec").Child("maintenanceWindow"))...)
+
+	allErrs = append(allErrs, validateWindowsProfile(m.Spec.WindowsProfile, field.NewPath("spec").Child("windowsProfile"))...)
+
+	allErrs = append(allErrs, validateAdditionalSubnets(m.Spec.VirtualNetwork, field.NewPath("spec").Child("virtualNetwork").Child("subnets"))...)
+
+	allErrs = append(allErrs, validateAddonProfiles(m.Spec.AddonProfiles, field.NewPath("spec").Child("addonProfiles"))...)
+
 	allErrs = append(allErrs, validateAKSExtensions(m.Spec.Extensions, field.NewPath("spec").Child("AKSExtensions"))...)
 
 	allErrs = append(allErrs, m.Spec.AzureManagedControlPlaneClassSpec.validateSecurityProfile()...)
@@ -288,6 +377,8 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 
 	allErrs = append(allErrs, validateNetworkDataplane(m.Spec.NetworkDataplane, m.Spec.NetworkPolicy, m.Spec.NetworkPluginMode, field.NewPath("spec").Child("NetworkDataplane"))...)
 
+	allErrs = append(allErrs, validateManagedAPIServerDNS(m.Spec.APIServerDNS, field.NewPath("spec").Child("apiServerDNS"))...)
+
 	return allErrs.ToAggregate()
 }
 
@@ -439,6 +530,40 @@ func validateLoadBalancerProfile(loadBalancerProfile *LoadBalancerProfile, fldPa
 	return allErrs
 }
 
+// validateOutboundType validates the outbound type against the NAT gateway settings it depends on.
+func validateOutboundType(outboundType *ManagedControlPlaneOutboundType, natGatewayProfile *NatGatewayProfile, subnet ManagedControlPlaneSubnet, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if natGatewayProfile != nil {
+		if ptr.Deref(outboundType, "") != ManagedControlPlaneOutboundTypeManagedNATGateway {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("NatGatewayProfile"), "NatGatewayProfile may only be set when OutboundType is managedNATGateway"))
+		}
+		if natGatewayProfile.ManagedOutboundIPCount != nil {
+			if *natGatewayProfile.ManagedOutboundIPCount < 1 || *natGatewayProfile.ManagedOutboundIPCount > 16 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("NatGatewayProfile", "ManagedOutboundIPCount"), *natGatewayProfile.ManagedOutboundIPCount, "value should be in between 1 and 16"))
+			}
+		}
+		if natGatewayProfile.IdleTimeoutInMinutes != nil {
+			if *natGatewayProfile.IdleTimeoutInMinutes < 4 || *natGatewayProfile.IdleTimeoutInMinutes > 120 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("NatGatewayProfile", "IdleTimeoutInMinutes"), *natGatewayProfile.IdleTimeoutInMinutes, "value should be in between 4 and 120"))
+			}
+		}
+	}
+
+	natGatewayIDPath := fldPath.Child("VirtualNetwork", "Subnet", "NatGatewayID")
+	if ptr.Deref(outboundType, "") == ManagedControlPlaneOutboundTypeUserAssignedNATGateway && subnet.NatGatewayID == "" {
+		allErrs = append(allErrs, field.Required(natGatewayIDPath, "NatGatewayID is required when OutboundType is userAssignedNATGateway"))
+	}
+	if subnet.NatGatewayID != "" {
+		id, err := arm.ParseResourceID(subnet.NatGatewayID)
+		if err != nil || !strings.EqualFold(id.ResourceType.String(), "Microsoft.Network/natGateways") {
+			allErrs = append(allErrs, field.Invalid(natGatewayIDPath, subnet.NatGatewayID, "must be the resource ID of a Microsoft.Network/natGateways resource"))
+		}
+	}
+
+	return allErrs
+}
+
 // validateAPIServerAccessProfile validates an APIServerAccessProfile.
 func (m *AzureManagedControlPlane) validateAPIServerAccessProfile(_ client.Client) field.ErrorList {
 	if m.Spec.APIServerAccessProfile != nil {
@@ -515,8 +640,12 @@ func validateManagedClusterNetwork(cli client.Client, labels map[string]string,
 			allErrs = append(allErrs, field.Invalid(field.NewPath("Cluster", "Spec", "ClusterNetwork", "Services", "DNSServiceIP"), *dnsServiceIP, "must be a valid IP address"))
 		}
 
-		if dnsIP != nil && !cidr.Contains(dnsIP) {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("Cluster", "Spec", "ClusterNetwork", "Services", "CIDRBlocks"), serviceCIDR, "DNSServiceIP must reside within the associated cluster serviceCIDR"))
+		if dnsIP != nil && cidr != nil {
+			if !cidr.Contains(dnsIP) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("Cluster", "Spec", "ClusterNetwork", "Services", "CIDRBlocks"), serviceCIDR, "DNSServiceIP must reside within the associated cluster serviceCIDR"))
+			} else if isNetworkOrBroadcastAddress(dnsIP, cidr) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("Cluster", "Spec", "ClusterNetwork", "Services", "DNSServiceIP"), *dnsServiceIP, "must not be the network or broadcast address of the associated cluster serviceCIDR"))
+			}
 		}
 
 		// AKS only supports .10 as the last octet for the DNSServiceIP.
@@ -543,11 +672,223 @@ func (m *AzureManagedControlPlane) validateAutoUpgradeProfile(old *AzureManagedC
 			// Unsetting the field is not allowed.
 			allErrs = append(allErrs,
 				field.Invalid(
-					field.NewPath("Spec", "AutoUpgradeProfile", "UpgradeChannel"),
+					field.NewPath("spec", "autoUpgradeProfile", "upgradeChannel"),
 					old.Spec.AutoUpgradeProfile.UpgradeChannel,
 					"field cannot be set to nil, to disable auto upgrades set the channel to none."))
 		}
+		if old.Spec.AutoUpgradeProfile.NodeOSUpgradeChannel != nil && (m.Spec.AutoUpgradeProfile == nil || m.Spec.AutoUpgradeProfile.NodeOSUpgradeChannel == nil) {
+			// Unsetting the field would leave the channel of the managed cluster as is.
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("spec", "autoUpgradeProfile", "nodeOSUpgradeChannel"),
+					old.Spec.AutoUpgradeProfile.NodeOSUpgradeChannel,
+					"field cannot be set to nil, to disable node OS upgrades set the channel to None."))
+		}
+	}
+	return allErrs
+}
+
+// validateUpgradeChannel validates the UpgradeChannel of an auto upgrade profile.
+func validateUpgradeChannel(autoUpgradeProfile *ManagedClusterAutoUpgradeProfile, fldPath *field.Path) field.ErrorList {
+	if autoUpgradeProfile == nil || autoUpgradeProfile.UpgradeChannel == nil {
+		return nil
+	}
+	switch *autoUpgradeProfile.UpgradeChannel {
+	case UpgradeChannelNodeImage, UpgradeChannelNone, UpgradeChannelPatch, UpgradeChannelRapid, UpgradeChannelStable:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, *autoUpgradeProfile.UpgradeChannel, []string{
+		string(UpgradeChannelNodeImage),
+		string(UpgradeChannelNone),
+		string(UpgradeChannelPatch),
+		string(UpgradeChannelRapid),
+		string(UpgradeChannelStable),
+	})}
+}
+
+// validateNodeOSUpgradeChannel validates the NodeOSUpgradeChannel of an auto upgrade profile.
+func validateNodeOSUpgradeChannel(autoUpgradeProfile *ManagedClusterAutoUpgradeProfile, fldPath *field.Path) field.ErrorList {
+	if autoUpgradeProfile == nil || autoUpgradeProfile.NodeOSUpgradeChannel == nil {
+		return nil
+	}
+	switch *autoUpgradeProfile.NodeOSUpgradeChannel {
+	case NodeOSUpgradeChannelNodeImage, NodeOSUpgradeChannelNone, NodeOSUpgradeChannelUnmanaged:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, *autoUpgradeProfile.NodeOSUpgradeChannel, []string{
+		string(NodeOSUpgradeChannelNodeImage),
+		string(NodeOSUpgradeChannelNone),
+		string(NodeOSUpgradeChannelUnmanaged),
+	})}
+}
+
+// validateAdditionalSubnets validates the subnets of a virtual network in addition to the node subnet. Their names
+// must be unique and differ from the name of the node subnet, and their CIDR blocks must be valid.
+func validateAdditionalSubnets(virtualNetwork ManagedControlPlaneVirtualNetwork, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{virtualNetwork.Subnet.Name: true}
+	for i, subnet := range virtualNetwork.Subnets {
+		if names[subnet.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), subnet.Name))
+		}
+		names[subnet.Name] = true
+		if _, _, err := net.ParseCIDR(subnet.CIDRBlock); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlock"), subnet.CIDRBlock, fmt.Sprintf("failed to parse subnet cidr: %v", err)))
+		}
+	}
+	return allErrs
+}
+
+// validateNetAppVolumesSubnets validates the subnets delegated to Azure NetApp Files against the node pools of the
+// cluster. The node pools are only listed when a subnet is delegated and the cluster name label is set.
+func (m *AzureManagedControlPlane) validateNetAppVolumesSubnets(cli client.Client) field.ErrorList {
+	hasNetAppVolumesSubnet := false
+	for _, subnet := range m.Spec.VirtualNetwork.Subnets {
+		hasNetAppVolumesSubnet = hasNetAppVolumesSubnet || isNetAppVolumesSubnet(subnet)
+	}
+	if !hasNetAppVolumesSubnet {
+		return nil
+	}
+	var pools []AzureManagedMachinePool
+	if clusterName, ok := m.Labels[clusterv1.ClusterNameLabel]; ok && cli != nil {
+		poolList := &AzureManagedMachinePoolList{}
+		if err := cli.List(context.Background(), poolList, client.InNamespace(m.Namespace), client.MatchingLabels{
+			clusterv1.ClusterNameLabel: clusterName,
+		}); err != nil {
+			return field.ErrorList{field.InternalError(field.NewPath("spec", "virtualNetwork", "subnets"), err)}
+		}
+		pools = poolList.Items
+	}
+	return validateNetAppVolumesDelegatedSubnets(m.Spec.VirtualNetwork, pools, field.NewPath("spec", "virtualNetwork", "subnets"))
+}
+
+// validateNetAppVolumesDelegatedSubnets validates the subnets of a virtual network delegated to Azure NetApp Files.
+// Like Azure, it requires them to be at least a /28, and it rejects them as the node or pod subnet of a node pool.
+func validateNetAppVolumesDelegatedSubnets(virtualNetwork ManagedControlPlaneVirtualNetwork, pools []AzureManagedMachinePool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, subnet := range virtualNetwork.Subnets {
+		if !isNetAppVolumesSubnet(subnet) {
+			continue
+		}
+		if _, cidr, err := net.ParseCIDR(subnet.CIDRBlock); err == nil {
+			if ones, bits := cidr.Mask.Size(); bits == 32 && ones > 28 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlock"), subnet.CIDRBlock,
+					fmt.Sprintf("subnet %s is delegated to %s which requires at least a /28, use a CIDR block with a prefix length of 28 or less", subnet.Name, NetAppVolumesDelegation)))
+			}
+		}
+		for j := range pools {
+			pool := &pools[j]
+			if name, _ := nodeSubnet(pool, virtualNetwork); name == subnet.Name {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("delegations"), subnet.Delegations,
+					fmt.Sprintf("subnet %s is delegated to %s but is the node subnet of AzureManagedMachinePool %s, use a dedicated subnet for Azure NetApp Files", subnet.Name, NetAppVolumesDelegation, pool.Name)))
+			}
+			if ptr.Deref(pool.Spec.PodSubnetName, "") == subnet.Name {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("delegations"), subnet.Delegations,
+					fmt.Sprintf("subnet %s is delegated to %s but is the pod subnet of AzureManagedMachinePool %s, use a dedicated subnet for Azure NetApp Files", subnet.Name, NetAppVolumesDelegation, pool.Name)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// isNetAppVolumesSubnet returns whether a subnet is delegated to Azure NetApp Files.
+func isNetAppVolumesSubnet(subnet ManagedControlPlaneAdditionalSubnet) bool {
+	for _, delegation := range subnet.Delegations {
+		if delegation == NetAppVolumesDelegation {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWindowsProfile validates a WindowsProfile. Like the AKS API, it requires the DNS server and the root domain
+// name of gMSA to be set together.
+func validateWindowsProfile(windowsProfile *ManagedClusterWindowsProfile, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if windowsProfile == nil || windowsProfile.GmsaProfile == nil {
+		return allErrs
+	}
+	gmsaProfile := windowsProfile.GmsaProfile
+	dnsServer := ptr.Deref(gmsaProfile.DNSServer, "")
+	rootDomainName := ptr.Deref(gmsaProfile.RootDomainName, "")
+	if (dnsServer == "") != (rootDomainName == "") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gmsaProfile"), gmsaProfile,
+			"dnsServer and rootDomainName must be either both set or both empty"))
+	}
+	if !gmsaProfile.Enabled && dnsServer != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gmsaProfile", "dnsServer"), dnsServer,
+			"dnsServer and rootDomainName can only be set when gMSA is enabled"))
+	}
+	return allErrs
+}
+
+// validateMaintenanceWindow validates a planned maintenance window. Like the AKS API, it rejects continuous windows
+// shorter than minMaintenanceWindowHours, including windows spanning several days.
+func validateMaintenanceWindow(window *MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	if window == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+
+	days := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	var allowed [7 * 24]bool
+	seenDays := map[string]bool{}
+	for i, timeInWeek := range window.AllowedTimes {
+		dayIndex := -1
+		for j, day := range days {
+			if day == timeInWeek.Day {
+				dayIndex = j
+			}
+		}
+		if dayIndex < 0 {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("allowedTimes").Index(i).Child("day"), timeInWeek.Day, days))
+			continue
+		}
+		if seenDays[timeInWeek.Day] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("allowedTimes").Index(i).Child("day"), timeInWeek.Day))
+		}
+		seenDays[timeInWeek.Day] = true
+		for j, hour := range timeInWeek.HourSlots {
+			if hour < 0 || hour > 23 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedTimes").Index(i).Child("hourSlots").Index(j), hour, "hour slots must be between 0 and 23"))
+				continue
+			}
+			allowed[dayIndex*24+int(hour)] = true
+		}
 	}
+
+	// Walk the week from a disallowed hour so that windows wrapping around the end of the week are measured whole.
+	start := -1
+	for i, ok := range allowed {
+		if !ok {
+			start = i
+			break
+		}
+	}
+	if start >= 0 {
+		length := 0
+		for k := 1; k <= len(allowed); k++ {
+			hour := (start + k) % len(allowed)
+			if allowed[hour] {
+				length++
+				continue
+			}
+			if length > 0 && length < minMaintenanceWindowHours {
+				windowStart := (hour - length + len(allowed)) % len(allowed)
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedTimes"), window.AllowedTimes,
+					fmt.Sprintf("maintenance windows must be at least %d hours long, the window starting on %s at %02d:00 is %d hours long",
+						minMaintenanceWindowHours, days[windowStart/24], windowStart%24, length)))
+			}
+			length = 0
+		}
+	}
+
+	for i, notAllowed := range window.NotAllowedTimes {
+		if !notAllowed.End.After(notAllowed.Start.Time) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("notAllowedTimes").Index(i).Child("end"), notAllowed.End, "end must be after start"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -703,7 +1044,7 @@ func (m *AzureManagedControlPlane) validateAADProfileUpdateAndLocalAccounts(old
 					field.Invalid(
 						field.NewPath("Spec", "AADProfile.Managed"),
 						m.Spec.AADProfile.Managed,
-						"cannot set AADProfile.Managed to false"))
+						"cannot set AADProfile.Managed to false, migrating from managed AAD back to the legacy AAD integration is not supported"))
 			}
 			if len(m.Spec.AADProfile.AdminGroupObjectIDs) == 0 {
 				allErrs = append(allErrs,
@@ -844,6 +1185,16 @@ func (m *AzureManagedControlPlane) validateFleetsMember(old *AzureManagedControl
 	return allErrs
 }
 
+// validateManagedAPIServerDNS validates the CNAME record of the API server of a managed cluster in an Azure DNS zone.
+// The record can't be the control plane endpoint since the certificate of the API server doesn't include its name.
+func validateManagedAPIServerDNS(apiServerDNS *APIServerDNS, fldPath *field.Path) field.ErrorList {
+	allErrs := validateAPIServerDNS(apiServerDNS, fldPath)
+	if apiServerDNS != nil && apiServerDNS.UseForControlPlaneEndpoint {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("useForControlPlaneEndpoint"), "the control plane endpoint of a managed cluster can't be the record"))
+	}
+	return allErrs
+}
+
 // validateAKSExtensionsUpdate validates update to AKS extensions.
 func validateAKSExtensionsUpdate(old []AKSExtension, current []AKSExtension) field.ErrorList {
 	var allErrs field.ErrorList
@@ -1017,6 +1368,26 @@ func validateAutoScalerProfile(autoScalerProfile *AutoScalerProfile, fldPath *fi
 		return nil
 	}
 
+	if autoScalerProfile.BalanceSimilarNodeGroups != nil {
+		allErrs = append(allErrs, validateAutoScalerProfileEnum(string(*autoScalerProfile.BalanceSimilarNodeGroups), fldPath.Child("BalanceSimilarNodeGroups"),
+			string(BalanceSimilarNodeGroupsTrue), string(BalanceSimilarNodeGroupsFalse))...)
+	}
+
+	if autoScalerProfile.Expander != nil {
+		allErrs = append(allErrs, validateAutoScalerProfileEnum(string(*autoScalerProfile.Expander), fldPath.Child("Expander"),
+			string(ExpanderLeastWaste), string(ExpanderMostPods), string(ExpanderPriority), string(ExpanderRandom))...)
+	}
+
+	if autoScalerProfile.SkipNodesWithLocalStorage != nil {
+		allErrs = append(allErrs, validateAutoScalerProfileEnum(string(*autoScalerProfile.SkipNodesWithLocalStorage), fldPath.Child("SkipNodesWithLocalStorage"),
+			string(SkipNodesWithLocalStorageTrue), string(SkipNodesWithLocalStorageFalse))...)
+	}
+
+	if autoScalerProfile.SkipNodesWithSystemPods != nil {
+		allErrs = append(allErrs, validateAutoScalerProfileEnum(string(*autoScalerProfile.SkipNodesWithSystemPods), fldPath.Child("SkipNodesWithSystemPods"),
+			string(SkipNodesWithSystemPodsTrue), string(SkipNodesWithSystemPodsFalse))...)
+	}
+
 	if errs := validateIntegerStringGreaterThanZero(autoScalerProfile.MaxEmptyBulkDelete, fldPath, "MaxEmptyBulkDelete"); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -1032,7 +1403,7 @@ func validateAutoScalerProfile(autoScalerProfile *AutoScalerProfile, fldPath *fi
 	if autoScalerProfile.MaxTotalUnreadyPercentage != nil {
 		val, err := strconv.Atoi(*autoScalerProfile.MaxTotalUnreadyPercentage)
 		if err != nil || val < 0 || val > 100 {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoscalerProfile", "MaxTotalUnreadyPercentage"), autoScalerProfile.MaxTotalUnreadyPercentage, "invalid value"))
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxTotalUnreadyPercentage"), autoScalerProfile.MaxTotalUnreadyPercentage, "invalid value"))
 		}
 	}
 
@@ -1071,13 +1442,46 @@ func validateAutoScalerProfile(autoScalerProfile *AutoScalerProfile, fldPath *fi
 	if autoScalerProfile.ScaleDownUtilizationThreshold != nil {
 		val, err := strconv.ParseFloat(*autoScalerProfile.ScaleDownUtilizationThreshold, 32)
 		if err != nil || val < 0 || val > 1 {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoscalerProfile", "ScaleDownUtilizationThreshold"), autoScalerProfile.ScaleDownUtilizationThreshold, "invalid value"))
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ScaleDownUtilizationThreshold"), autoScalerProfile.ScaleDownUtilizationThreshold, "invalid value"))
 		}
 	}
 
 	return allErrs
 }
 
+// validateAddonProfiles validates the config of the add-ons handled specifically by CAPZ.
+func validateAddonProfiles(addonProfiles []AddonProfile, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, addonProfile := range addonProfiles {
+		if !strings.EqualFold(addonProfile.Name, AzureKeyvaultSecretsProviderAddonName) {
+			continue
+		}
+		configPath := fldPath.Index(i).Child("config")
+		enableSecretRotation, hasEnableSecretRotation := addonProfile.Config[AzureKeyvaultSecretsProviderEnableSecretRotationKey]
+		if hasEnableSecretRotation && enableSecretRotation != "true" && enableSecretRotation != "false" {
+			allErrs = append(allErrs, field.NotSupported(configPath.Key(AzureKeyvaultSecretsProviderEnableSecretRotationKey), enableSecretRotation, []string{"true", "false"}))
+		}
+		rotationPollInterval, hasRotationPollInterval := addonProfile.Config[AzureKeyvaultSecretsProviderRotationPollIntervalKey]
+		if !hasRotationPollInterval {
+			continue
+		}
+		if interval, err := time.ParseDuration(rotationPollInterval); err != nil || interval <= 0 {
+			allErrs = append(allErrs, field.Invalid(configPath.Key(AzureKeyvaultSecretsProviderRotationPollIntervalKey), rotationPollInterval, "must be a positive duration, e.g. 2m"))
+		}
+	}
+	return allErrs
+}
+
+// validateAutoScalerProfileEnum validates that an AutoscalerProfile value is one of the values supported by AKS.
+func validateAutoScalerProfileEnum(value string, fldPath *field.Path, supported ...string) field.ErrorList {
+	for _, v := range supported {
+		if value == v {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, supported)}
+}
+
 // validateMaxNodeProvisionTime validates update to AutoscalerProfile.MaxNodeProvisionTime.
 func validateMaxNodeProvisionTime(maxNodeProvisionTime *string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -1171,6 +1575,16 @@ func (m *AzureManagedControlPlane) validateIdentity(_ client.Client) field.Error
 	return nil
 }
 
+// validateIdentityRef validates that an IdentityRef was provided, either by the AzureManagedControlPlaneTemplate
+// a ClusterClass generated this AzureManagedControlPlane from or by the cluster itself.
+func (m *AzureManagedControlPlane) validateIdentityRef() *field.Error {
+	fldPath := field.NewPath("spec", "identityRef")
+	if m.Spec.IdentityRef == nil {
+		return field.Required(fldPath, "identityRef must be set in the AzureManagedControlPlaneTemplate or supplied for this cluster, for example through a ClusterClass variable patch")
+	}
+	return validateIdentityRef(m.Spec.IdentityRef, fldPath)
+}
+
 // validateNetworkPluginMode validates a NetworkPluginMode.
 func (m *AzureManagedControlPlane) validateNetworkPluginMode(_ client.Client) field.ErrorList {
 	var allErrs field.ErrorList
@@ -1188,6 +1602,90 @@ func (m *AzureManagedControlPlane) validateNetworkPluginMode(_ client.Client) fi
 	return nil
 }
 
+// validatePodIdentityProfile validates a PodIdentityProfile.
+func (m *AzureManagedControlPlane) validatePodIdentityProfile(_ client.Client) field.ErrorList {
+	return m.Spec.AzureManagedControlPlaneClassSpec.validatePodIdentityProfile(field.NewPath("Spec", "PodIdentityProfile"))
+}
+
+// validatePodIdentityProfile validates the PodIdentityProfile of a class spec.
+func (m *AzureManagedControlPlaneClassSpec) validatePodIdentityProfile(fldPath *field.Path) field.ErrorList {
+	profile := m.PodIdentityProfile
+	if profile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	const kubenet = "kubenet"
+	if profile.Enabled && ptr.Deref(m.NetworkPlugin, "") == kubenet && !ptr.Deref(profile.AllowNetworkPluginKubenet, false) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("Enabled"), fmt.Sprintf("pod identity can be enabled with NetworkPlugin %q only when AllowNetworkPluginKubenet is true", kubenet)))
+	}
+
+	exceptions := make(map[string]bool, len(profile.UserAssignedIdentityExceptions))
+	for i, exception := range profile.UserAssignedIdentityExceptions {
+		exceptionPath := fldPath.Child("UserAssignedIdentityExceptions").Index(i)
+		if exception.Name == "" {
+			allErrs = append(allErrs, field.Required(exceptionPath.Child("Name"), "the name of a pod identity exception cannot be empty"))
+		}
+		for _, msg := range validation.IsDNS1123Label(exception.Namespace) {
+			allErrs = append(allErrs, field.Invalid(exceptionPath.Child("Namespace"), exception.Namespace, msg))
+		}
+		key := exception.Namespace + "/" + exception.Name
+		if exceptions[key] {
+			allErrs = append(allErrs, field.Duplicate(exceptionPath.Child("Name"), exception.Name))
+		}
+		exceptions[key] = true
+
+		if len(exception.PodLabels) == 0 {
+			allErrs = append(allErrs, field.Required(exceptionPath.Child("PodLabels"), "a pod identity exception must match at least one pod label"))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabels(exception.PodLabels, exceptionPath.Child("PodLabels"))...)
+	}
+
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	return nil
+}
+
+// podIdentityProfileWarnings returns a deprecation warning when AAD pod identity is enabled.
+func (m *AzureManagedControlPlane) podIdentityProfileWarnings() admission.Warnings {
+	if m.Spec.PodIdentityProfile == nil || !m.Spec.PodIdentityProfile.Enabled {
+		return nil
+	}
+	return admission.Warnings{"spec.podIdentityProfile: AAD pod identity is deprecated, use workload identity (spec.securityProfile.workloadIdentity) instead"}
+}
+
+// dockerBridgeCIDRWarnings returns a warning when the Docker bridge CIDR removed from AKS is set.
+func (m *AzureManagedControlPlaneClassSpec) dockerBridgeCIDRWarnings(fldPath *field.Path) admission.Warnings {
+	if m.DockerBridgeCIDR == nil {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf("%s: the Docker bridge network is no longer supported by AKS, the field is ignored", fldPath)}
+}
+
+// isNetworkOrBroadcastAddress returns true if the IP is the network address of the CIDR, or its broadcast address
+// for IPv4.
+func isNetworkOrBroadcastAddress(ip net.IP, cidr *net.IPNet) bool {
+	if ip.Equal(cidr.IP) {
+		return true
+	}
+	ip4, network4 := ip.To4(), cidr.IP.To4()
+	if ip4 == nil || network4 == nil {
+		return false
+	}
+	mask := cidr.Mask
+	if len(mask) == net.IPv6len {
+		mask = mask[12:]
+	}
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range network4 {
+		broadcast[i] = network4[i] | ^mask[i]
+	}
+	return ip4.Equal(broadcast)
+}
+
 // isOIDCEnabled return true if OIDC issuer is enabled.
 func (m *AzureManagedControlPlaneClassSpec) isOIDCEnabled() bool {
 	if m.OIDCIssuerProfile == nil {
@@ -18,16 +18,23 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
+	"net"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestDefaultingWebhook(t *testing.T) {
@@ -126,6 +133,7 @@ func TestDefaultingWebhook(t *testing.T) {
 	g.Expect(*amcp.Spec.AutoUpgradeProfile.UpgradeChannel).To(Equal(UpgradeChannelPatch))
 	g.Expect(amcp.Spec.SecurityProfile).ToNot(BeNil())
 	g.Expect(amcp.Spec.SecurityProfile.AzureKeyVaultKms).ToNot(BeNil())
+	g.Expect(amcp.Spec.SecurityProfile.AzureKeyVaultKms.KeyVaultNetworkAccess).To(Equal(ptr.To(KeyVaultNetworkAccessTypesPublic)))
 	g.Expect(amcp.Spec.SecurityProfile.ImageCleaner).ToNot(BeNil())
 	g.Expect(amcp.Spec.SecurityProfile.ImageCleaner.IntervalHours).ToNot(BeNil())
 	g.Expect(*amcp.Spec.SecurityProfile.ImageCleaner.IntervalHours).To(Equal(48))
@@ -210,6 +218,538 @@ func TestValidateVersion(t *testing.T) {
 	}
 }
 
+func TestValidateUpgradeChannel(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   *ManagedClusterAutoUpgradeProfile
+		expectErr bool
+	}{
+		{
+			name:      "no auto upgrade profile",
+			profile:   nil,
+			expectErr: false,
+		},
+		{
+			name:      "no upgrade channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{},
+			expectErr: false,
+		},
+		{
+			name:      "patch channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{UpgradeChannel: ptr.To(UpgradeChannelPatch)},
+			expectErr: false,
+		},
+		{
+			name:      "node-image channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{UpgradeChannel: ptr.To(UpgradeChannelNodeImage)},
+			expectErr: false,
+		},
+		{
+			name:      "unknown channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{UpgradeChannel: ptr.To(UpgradeChannel("nightly"))},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateUpgradeChannel(tt.profile, field.NewPath("spec").Child("autoUpgradeProfile").Child("upgradeChannel"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateNodeOSUpgradeChannel(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   *ManagedClusterAutoUpgradeProfile
+		expectErr bool
+	}{
+		{
+			name:      "no auto upgrade profile",
+			profile:   nil,
+			expectErr: false,
+		},
+		{
+			name:      "no node OS upgrade channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{UpgradeChannel: ptr.To(UpgradeChannelPatch)},
+			expectErr: false,
+		},
+		{
+			name:      "NodeImage channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannelNodeImage)},
+			expectErr: false,
+		},
+		{
+			name:      "Unmanaged channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannelUnmanaged)},
+			expectErr: false,
+		},
+		{
+			name:      "unknown channel",
+			profile:   &ManagedClusterAutoUpgradeProfile{NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannel("node-image"))},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateNodeOSUpgradeChannel(tt.profile, field.NewPath("spec").Child("autoUpgradeProfile").Child("nodeOSUpgradeChannel"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, time.December, 20, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(14 * 24 * time.Hour))
+	tests := []struct {
+		name      string
+		window    *MaintenanceWindow
+		expectErr bool
+	}{
+		{
+			name:      "no maintenance window",
+			window:    nil,
+			expectErr: false,
+		},
+		{
+			name: "4 hours window",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Saturday", HourSlots: []int32{1, 2, 3, 4}},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "window shorter than 4 hours",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Saturday", HourSlots: []int32{1, 2, 3, 4}},
+					{Day: "Sunday", HourSlots: []int32{1, 2, 3}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "window spanning midnight",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Friday", HourSlots: []int32{22, 23}},
+					{Day: "Saturday", HourSlots: []int32{0, 1}},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "window spanning the end of the week",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Saturday", HourSlots: []int32{23}},
+					{Day: "Sunday", HourSlots: []int32{0, 1, 2}},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "hour slot out of range",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Saturday", HourSlots: []int32{21, 22, 23, 24}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "duplicate day",
+			window: &MaintenanceWindow{
+				AllowedTimes: []MaintenanceTimeInWeek{
+					{Day: "Saturday", HourSlots: []int32{0, 1, 2, 3}},
+					{Day: "Saturday", HourSlots: []int32{10, 11, 12, 13}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "not allowed time range",
+			window: &MaintenanceWindow{
+				NotAllowedTimes: []MaintenanceTimeSpan{{Start: start, End: end}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "not allowed time range ending before its start",
+			window: &MaintenanceWindow{
+				NotAllowedTimes: []MaintenanceTimeSpan{{Start: end, End: start}},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateMaintenanceWindow(tt.window, field.NewPath("spec").Child("maintenanceWindow"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateWindowsProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		windowsProfile *ManagedClusterWindowsProfile
+		expectErr      bool
+	}{
+		{
+			name:           "no windows profile",
+			windowsProfile: nil,
+			expectErr:      false,
+		},
+		{
+			name:           "gMSA enabled with the DNS server of the virtual network",
+			windowsProfile: &ManagedClusterWindowsProfile{GmsaProfile: &WindowsGmsaProfile{Enabled: true}},
+			expectErr:      false,
+		},
+		{
+			name: "gMSA enabled with a DNS server and a root domain name",
+			windowsProfile: &ManagedClusterWindowsProfile{GmsaProfile: &WindowsGmsaProfile{
+				Enabled:        true,
+				DNSServer:      ptr.To("10.0.0.4"),
+				RootDomainName: ptr.To("contoso.com"),
+			}},
+			expectErr: false,
+		},
+		{
+			name: "gMSA enabled with a DNS server but no root domain name",
+			windowsProfile: &ManagedClusterWindowsProfile{GmsaProfile: &WindowsGmsaProfile{
+				Enabled:   true,
+				DNSServer: ptr.To("10.0.0.4"),
+			}},
+			expectErr: true,
+		},
+		{
+			name: "gMSA disabled with a DNS server and a root domain name",
+			windowsProfile: &ManagedClusterWindowsProfile{GmsaProfile: &WindowsGmsaProfile{
+				Enabled:        false,
+				DNSServer:      ptr.To("10.0.0.4"),
+				RootDomainName: ptr.To("contoso.com"),
+			}},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateWindowsProfile(tt.windowsProfile, field.NewPath("spec").Child("windowsProfile"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateAdditionalSubnets(t *testing.T) {
+	virtualNetwork := func(subnets ...ManagedControlPlaneAdditionalSubnet) ManagedControlPlaneVirtualNetwork {
+		return ManagedControlPlaneVirtualNetwork{
+			ManagedControlPlaneVirtualNetworkClassSpec: ManagedControlPlaneVirtualNetworkClassSpec{
+				Subnet:  ManagedControlPlaneSubnet{Name: "node-subnet", CIDRBlock: "10.240.0.0/16"},
+				Subnets: subnets,
+			},
+		}
+	}
+	tests := []struct {
+		name           string
+		virtualNetwork ManagedControlPlaneVirtualNetwork
+		expectErr      bool
+	}{
+		{
+			name:           "no additional subnets",
+			virtualNetwork: virtualNetwork(),
+			expectErr:      false,
+		},
+		{
+			name: "valid additional subnets",
+			virtualNetwork: virtualNetwork(
+				ManagedControlPlaneAdditionalSubnet{Name: "pod-subnet", CIDRBlock: "10.241.0.0/16"},
+				ManagedControlPlaneAdditionalSubnet{Name: "other-pod-subnet", CIDRBlock: "10.242.0.0/16"},
+			),
+			expectErr: false,
+		},
+		{
+			name: "additional subnet named like the node subnet",
+			virtualNetwork: virtualNetwork(
+				ManagedControlPlaneAdditionalSubnet{Name: "node-subnet", CIDRBlock: "10.241.0.0/16"},
+			),
+			expectErr: true,
+		},
+		{
+			name: "duplicate additional subnets",
+			virtualNetwork: virtualNetwork(
+				ManagedControlPlaneAdditionalSubnet{Name: "pod-subnet", CIDRBlock: "10.241.0.0/16"},
+				ManagedControlPlaneAdditionalSubnet{Name: "pod-subnet", CIDRBlock: "10.242.0.0/16"},
+			),
+			expectErr: true,
+		},
+		{
+			name: "invalid CIDR block",
+			virtualNetwork: virtualNetwork(
+				ManagedControlPlaneAdditionalSubnet{Name: "pod-subnet", CIDRBlock: "10.241.0.0"},
+			),
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateAdditionalSubnets(tt.virtualNetwork, field.NewPath("spec").Child("virtualNetwork").Child("subnets"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidateNetAppVolumesDelegatedSubnets(t *testing.T) {
+	virtualNetwork := func(subnets ...ManagedControlPlaneAdditionalSubnet) ManagedControlPlaneVirtualNetwork {
+		return ManagedControlPlaneVirtualNetwork{
+			ManagedControlPlaneVirtualNetworkClassSpec: ManagedControlPlaneVirtualNetworkClassSpec{
+				Subnet:  ManagedControlPlaneSubnet{Name: "node-subnet", CIDRBlock: "10.240.0.0/16"},
+				Subnets: subnets,
+			},
+		}
+	}
+	pool := func(subnetName, podSubnetName *string) AzureManagedMachinePool {
+		return AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool0"},
+			Spec: AzureManagedMachinePoolSpec{
+				AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+					SubnetName:    subnetName,
+					PodSubnetName: podSubnetName,
+				},
+			},
+		}
+	}
+	anfSubnet := func(cidrBlock string) ManagedControlPlaneAdditionalSubnet {
+		return ManagedControlPlaneAdditionalSubnet{Name: "anf-subnet", CIDRBlock: cidrBlock, Delegations: []string{NetAppVolumesDelegation}}
+	}
+	tests := []struct {
+		name           string
+		virtualNetwork ManagedControlPlaneVirtualNetwork
+		pools          []AzureManagedMachinePool
+		wantErr        string
+	}{
+		{
+			name:           "no delegated subnets",
+			virtualNetwork: virtualNetwork(ManagedControlPlaneAdditionalSubnet{Name: "small-subnet", CIDRBlock: "10.241.0.0/29"}),
+			pools:          []AzureManagedMachinePool{pool(ptr.To("small-subnet"), nil)},
+		},
+		{
+			name:           "dedicated /28 subnet",
+			virtualNetwork: virtualNetwork(anfSubnet("10.241.0.0/28")),
+			pools:          []AzureManagedMachinePool{pool(nil, nil)},
+		},
+		{
+			name:           "dedicated /24 subnet",
+			virtualNetwork: virtualNetwork(anfSubnet("10.241.0.0/24")),
+		},
+		{
+			name:           "subnet smaller than /28",
+			virtualNetwork: virtualNetwork(anfSubnet("10.241.0.0/29")),
+			wantErr:        "spec.virtualNetwork.subnets[0].cidrBlock: Invalid value: \"10.241.0.0/29\": subnet anf-subnet is delegated to Microsoft.NetApp/volumes which requires at least a /28, use a CIDR block with a prefix length of 28 or less",
+		},
+		{
+			name:           "subnet used as a node subnet",
+			virtualNetwork: virtualNetwork(anfSubnet("10.241.0.0/24")),
+			pools:          []AzureManagedMachinePool{pool(ptr.To("anf-subnet"), nil)},
+			wantErr:        "spec.virtualNetwork.subnets[0].delegations: Invalid value: []string{\"Microsoft.NetApp/volumes\"}: subnet anf-subnet is delegated to Microsoft.NetApp/volumes but is the node subnet of AzureManagedMachinePool pool0, use a dedicated subnet for Azure NetApp Files",
+		},
+		{
+			name:           "subnet used as a pod subnet",
+			virtualNetwork: virtualNetwork(anfSubnet("10.241.0.0/24")),
+			pools:          []AzureManagedMachinePool{pool(nil, ptr.To("anf-subnet"))},
+			wantErr:        "spec.virtualNetwork.subnets[0].delegations: Invalid value: []string{\"Microsoft.NetApp/volumes\"}: subnet anf-subnet is delegated to Microsoft.NetApp/volumes but is the pod subnet of AzureManagedMachinePool pool0, use a dedicated subnet for Azure NetApp Files",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateNetAppVolumesDelegatedSubnets(tt.virtualNetwork, tt.pools, field.NewPath("spec").Child("virtualNetwork").Child("subnets"))
+			if tt.wantErr != "" {
+				g.Expect(allErrs.ToAggregate()).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestAzureManagedControlPlane_ValidateNetAppVolumesSubnets(t *testing.T) {
+	g := NewWithT(t)
+	amcp := &AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-control-plane",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		},
+		Spec: AzureManagedControlPlaneSpec{
+			AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+				VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+					ManagedControlPlaneVirtualNetworkClassSpec: ManagedControlPlaneVirtualNetworkClassSpec{
+						Subnet: ManagedControlPlaneSubnet{Name: "node-subnet", CIDRBlock: "10.240.0.0/16"},
+						Subnets: []ManagedControlPlaneAdditionalSubnet{
+							{Name: "anf-subnet", CIDRBlock: "10.241.0.0/24", Delegations: []string{NetAppVolumesDelegation}},
+						},
+					},
+				},
+			},
+		},
+	}
+	pool := func(name, clusterName string) client.Object {
+		return &AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: AzureManagedMachinePoolSpec{
+				AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{SubnetName: ptr.To("anf-subnet")},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+
+	t.Logf("Testing the pools of another cluster are ignored")
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool("pool0", "other-cluster")).Build()
+	g.Expect(amcp.validateNetAppVolumesSubnets(cli)).To(BeEmpty())
+
+	t.Logf("Testing a pool of the cluster in the delegated subnet is rejected")
+	cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool("pool1", "test-cluster")).Build()
+	g.Expect(amcp.validateNetAppVolumesSubnets(cli).ToAggregate()).To(MatchError(ContainSubstring("is the node subnet of AzureManagedMachinePool pool1, use a dedicated subnet for Azure NetApp Files")))
+}
+
+func TestValidateAddonProfiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		addonProfiles []AddonProfile
+		expectErr     bool
+	}{
+		{
+			name:          "no add-ons",
+			addonProfiles: nil,
+			expectErr:     false,
+		},
+		{
+			name: "key vault secrets provider with secret rotation",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    AzureKeyvaultSecretsProviderAddonName,
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderEnableSecretRotationKey: "true",
+						AzureKeyvaultSecretsProviderRotationPollIntervalKey: "2m",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "key vault secrets provider with invalid enableSecretRotation",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    AzureKeyvaultSecretsProviderAddonName,
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderEnableSecretRotationKey: "yes",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "key vault secrets provider with invalid rotationPollInterval",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    AzureKeyvaultSecretsProviderAddonName,
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderEnableSecretRotationKey: "true",
+						AzureKeyvaultSecretsProviderRotationPollIntervalKey: "2 minutes",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "key vault secrets provider with negative rotationPollInterval",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    AzureKeyvaultSecretsProviderAddonName,
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderRotationPollIntervalKey: "-2m",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "key vault secrets provider name is matched case-insensitively",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    "AzureKeyvaultSecretsProvider",
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderEnableSecretRotationKey: "yes",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "config of other add-ons isn't validated",
+			addonProfiles: []AddonProfile{
+				{
+					Name:    "azurepolicy",
+					Enabled: true,
+					Config: map[string]string{
+						AzureKeyvaultSecretsProviderEnableSecretRotationKey: "yes",
+					},
+				},
+			},
+			expectErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateAddonProfiles(tt.addonProfiles, field.NewPath("spec").Child("addonProfiles"))
+			if tt.expectErr {
+				g.Expect(allErrs).NotTo(BeNil())
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestValidateLoadBalancerProfile(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -278,6 +818,101 @@ func TestValidateLoadBalancerProfile(t *testing.T) {
 	}
 }
 
+func TestValidateOutboundType(t *testing.T) {
+	natGatewayID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/natGateways/my-nat-gateway"
+	tests := []struct {
+		name              string
+		outboundType      *ManagedControlPlaneOutboundType
+		natGatewayProfile *NatGatewayProfile
+		subnet            ManagedControlPlaneSubnet
+		expectedErr       field.Error
+	}{
+		{
+			name:         "Valid managedNATGateway with NatGatewayProfile",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeManagedNATGateway),
+			natGatewayProfile: &NatGatewayProfile{
+				ManagedOutboundIPCount: ptr.To(2),
+				IdleTimeoutInMinutes:   ptr.To(10),
+			},
+		},
+		{
+			name:         "Valid userAssignedNATGateway with NatGatewayID",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeUserAssignedNATGateway),
+			subnet:       ManagedControlPlaneSubnet{NatGatewayID: natGatewayID},
+		},
+		{
+			name:              "NatGatewayProfile with loadBalancer OutboundType",
+			outboundType:      ptr.To(ManagedControlPlaneOutboundTypeLoadBalancer),
+			natGatewayProfile: &NatGatewayProfile{},
+			expectedErr: field.Error{
+				Type:   field.ErrorTypeForbidden,
+				Field:  "spec.NatGatewayProfile",
+				Detail: "NatGatewayProfile may only be set when OutboundType is managedNATGateway",
+			},
+		},
+		{
+			name:         "Invalid NatGatewayProfile.ManagedOutboundIPCount",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeManagedNATGateway),
+			natGatewayProfile: &NatGatewayProfile{
+				ManagedOutboundIPCount: ptr.To(17),
+			},
+			expectedErr: field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "spec.NatGatewayProfile.ManagedOutboundIPCount",
+				BadValue: 17,
+				Detail:   "value should be in between 1 and 16",
+			},
+		},
+		{
+			name:         "Invalid NatGatewayProfile.IdleTimeoutInMinutes",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeManagedNATGateway),
+			natGatewayProfile: &NatGatewayProfile{
+				IdleTimeoutInMinutes: ptr.To(2),
+			},
+			expectedErr: field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "spec.NatGatewayProfile.IdleTimeoutInMinutes",
+				BadValue: 2,
+				Detail:   "value should be in between 4 and 120",
+			},
+		},
+		{
+			name:         "userAssignedNATGateway without NatGatewayID",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeUserAssignedNATGateway),
+			expectedErr: field.Error{
+				Type:   field.ErrorTypeRequired,
+				Field:  "spec.VirtualNetwork.Subnet.NatGatewayID",
+				Detail: "NatGatewayID is required when OutboundType is userAssignedNATGateway",
+			},
+		},
+		{
+			name:         "NatGatewayID of another resource type",
+			outboundType: ptr.To(ManagedControlPlaneOutboundTypeUserAssignedNATGateway),
+			subnet: ManagedControlPlaneSubnet{
+				NatGatewayID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
+			},
+			expectedErr: field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "spec.VirtualNetwork.Subnet.NatGatewayID",
+				BadValue: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
+				Detail:   "must be the resource ID of a Microsoft.Network/natGateways resource",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			allErrs := validateOutboundType(tt.outboundType, tt.natGatewayProfile, tt.subnet, field.NewPath("spec"))
+			if tt.expectedErr != (field.Error{}) {
+				g.Expect(allErrs).To(ContainElement(MatchError(tt.expectedErr.Error())))
+			} else {
+				g.Expect(allErrs).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestValidateAutoScalerProfile(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -440,6 +1075,34 @@ func TestValidateAutoScalerProfile(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Testing invalid AutoScalerProfile.Expander",
+			profile: &AutoScalerProfile{
+				Expander: (*Expander)(ptr.To("biggest")),
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid AutoScalerProfile.BalanceSimilarNodeGroups",
+			profile: &AutoScalerProfile{
+				BalanceSimilarNodeGroups: (*BalanceSimilarNodeGroups)(ptr.To("yes")),
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid AutoScalerProfile.SkipNodesWithLocalStorage",
+			profile: &AutoScalerProfile{
+				SkipNodesWithLocalStorage: (*SkipNodesWithLocalStorage)(ptr.To("True")),
+			},
+			expectErr: true,
+		},
+		{
+			name: "Testing invalid AutoScalerProfile.SkipNodesWithSystemPods",
+			profile: &AutoScalerProfile{
+				SkipNodesWithSystemPods: (*SkipNodesWithSystemPods)(ptr.To("")),
+			},
+			expectErr: true,
+		},
 		{
 			name: "Testing valid AutoScalerProfile.SkipNodesWithLocalStorageTrue",
 			profile: &AutoScalerProfile{
@@ -485,6 +1148,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.17.8",
 					},
@@ -498,6 +1162,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10.3"),
 						Version:      "v1.17.8",
 					},
@@ -511,6 +1176,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.11"),
 						Version:      "v1.17.8",
 					},
@@ -524,7 +1190,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -536,6 +1203,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "honk",
 					},
@@ -549,6 +1217,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "1.19.0",
 					},
@@ -562,6 +1231,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "",
 					},
@@ -575,6 +1245,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.17.8",
 					},
@@ -588,7 +1259,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						AADProfile: &AADProfile{
 							Managed: true,
 							AdminGroupObjectIDs: []string{
@@ -606,7 +1278,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						LoadBalancerProfile: &LoadBalancerProfile{
 							ManagedOutboundIPs:     ptr.To(10),
 							AllocatedOutboundPorts: ptr.To(1000),
@@ -623,7 +1296,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						LoadBalancerProfile: &LoadBalancerProfile{
 							ManagedOutboundIPs: ptr.To(200),
 						},
@@ -638,7 +1312,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						LoadBalancerProfile: &LoadBalancerProfile{
 							AllocatedOutboundPorts: ptr.To(80000),
 						},
@@ -653,7 +1328,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						LoadBalancerProfile: &LoadBalancerProfile{
 							IdleTimeoutInMinutes: ptr.To(600),
 						},
@@ -668,7 +1344,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						LoadBalancerProfile: &LoadBalancerProfile{
 							ManagedOutboundIPs: ptr.To(1),
 							OutboundIPs: []string{
@@ -686,7 +1363,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						APIServerAccessProfile: &APIServerAccessProfile{
 							AuthorizedIPRanges: []string{"1.2.3.400/32"},
 						},
@@ -701,7 +1379,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							BalanceSimilarNodeGroups:      (*BalanceSimilarNodeGroups)(ptr.To(string(BalanceSimilarNodeGroupsFalse))),
 							Expander:                      (*Expander)(ptr.To(string(ExpanderRandom))),
@@ -732,7 +1411,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							Expander: (*Expander)(ptr.To(string(ExpanderRandom))),
 						},
@@ -747,7 +1427,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							Expander: (*Expander)(ptr.To(string(ExpanderLeastWaste))),
 						},
@@ -762,7 +1443,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							Expander: (*Expander)(ptr.To(string(ExpanderMostPods))),
 						},
@@ -777,7 +1459,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							Expander: (*Expander)(ptr.To(string(ExpanderPriority))),
 						},
@@ -792,7 +1475,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							BalanceSimilarNodeGroups: (*BalanceSimilarNodeGroups)(ptr.To(string(BalanceSimilarNodeGroupsTrue))),
 						},
@@ -807,7 +1491,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							BalanceSimilarNodeGroups: (*BalanceSimilarNodeGroups)(ptr.To(string(BalanceSimilarNodeGroupsFalse))),
 						},
@@ -822,7 +1507,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							MaxEmptyBulkDelete: ptr.To("invalid"),
 						},
@@ -837,7 +1523,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							MaxGracefulTerminationSec: ptr.To("invalid"),
 						},
@@ -852,7 +1539,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							MaxNodeProvisionTime: ptr.To("invalid"),
 						},
@@ -867,7 +1555,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							MaxTotalUnreadyPercentage: ptr.To("invalid"),
 						},
@@ -882,7 +1571,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							NewPodScaleUpDelay: ptr.To("invalid"),
 						},
@@ -897,7 +1587,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							OkTotalUnreadyCount: ptr.To("invalid"),
 						},
@@ -912,7 +1603,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScanInterval: ptr.To("invalid"),
 						},
@@ -927,7 +1619,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownDelayAfterAdd: ptr.To("invalid"),
 						},
@@ -942,7 +1635,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownDelayAfterDelete: ptr.To("invalid"),
 						},
@@ -957,7 +1651,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownDelayAfterFailure: ptr.To("invalid"),
 						},
@@ -972,7 +1667,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownUnneededTime: ptr.To("invalid"),
 						},
@@ -987,7 +1683,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownUnreadyTime: ptr.To("invalid"),
 						},
@@ -1002,7 +1699,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							ScaleDownUtilizationThreshold: ptr.To("invalid"),
 						},
@@ -1017,7 +1715,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							SkipNodesWithLocalStorage: (*SkipNodesWithLocalStorage)(ptr.To(string(SkipNodesWithLocalStorageTrue))),
 						},
@@ -1032,7 +1731,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							SkipNodesWithLocalStorage: (*SkipNodesWithLocalStorage)(ptr.To(string(SkipNodesWithLocalStorageFalse))),
 						},
@@ -1047,7 +1747,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							SkipNodesWithSystemPods: (*SkipNodesWithSystemPods)(ptr.To(string(SkipNodesWithSystemPodsTrue))),
 						},
@@ -1062,7 +1763,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						AutoScalerProfile: &AutoScalerProfile{
 							SkipNodesWithSystemPods: (*SkipNodesWithSystemPods)(ptr.To(string(SkipNodesWithSystemPodsFalse))),
 						},
@@ -1077,7 +1779,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						Identity: &Identity{
 							Type: ManagedControlPlaneIdentityTypeSystemAssigned,
 						},
@@ -1092,7 +1795,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "/resource/id",
@@ -1108,7 +1812,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeSystemAssigned,
 							UserAssignedIdentityResourceID: "/resource/id",
@@ -1124,7 +1829,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.24.1",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.24.1",
 						Identity: &Identity{
 							Type: ManagedControlPlaneIdentityTypeUserAssigned,
 						},
@@ -1139,6 +1845,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.24.1",
 						NetworkPlugin:     ptr.To("kubenet"),
 						NetworkPluginMode: ptr.To(NetworkPluginModeOverlay),
@@ -1153,6 +1860,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.24.1",
 						NetworkPlugin:     ptr.To("azure"),
 						NetworkPluginMode: ptr.To(NetworkPluginModeOverlay),
@@ -1167,7 +1875,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Extensions: []AKSExtension{
 							{
 								Name:          "extension1",
@@ -1190,7 +1899,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Extensions: []AKSExtension{
 							{
 								Name:                    "extension1",
@@ -1215,7 +1925,8 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Extensions: []AKSExtension{
 							{
 								Name:                    "extension1",
@@ -1237,7 +1948,8 @@ func TestValidatingWebhook(t *testing.T) {
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						SecurityProfile: &ManagedClusterSecurityProfile{
 							AzureKeyVaultKms: &AzureKeyVaultKms{
 								Enabled:               true,
@@ -1255,6 +1967,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.17.8",
 						NetworkPluginMode: ptr.To(NetworkPluginModeOverlay),
 						NetworkDataplane:  ptr.To(NetworkDataplaneTypeCilium),
@@ -1270,6 +1983,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.17.8",
 						NetworkPluginMode: nil,
 						NetworkDataplane:  ptr.To(NetworkDataplaneTypeCilium),
@@ -1284,7 +1998,8 @@ func TestValidatingWebhook(t *testing.T) {
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "not empty",
@@ -1306,7 +2021,8 @@ func TestValidatingWebhook(t *testing.T) {
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "not empty",
@@ -1328,6 +2044,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.17.8",
 						NetworkPluginMode: nil,
 						NetworkDataplane:  ptr.To(NetworkDataplaneTypeCilium),
@@ -1343,6 +2060,7 @@ func TestValidatingWebhook(t *testing.T) {
 				ObjectMeta: getAMCPMetaData(),
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:       validIdentityRef(),
 						Version:           "v1.17.8",
 						NetworkPluginMode: nil,
 						NetworkDataplane:  ptr.To(NetworkDataplaneTypeAzure),
@@ -1389,6 +2107,32 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 			amcp:    getKnownValidAzureManagedControlPlane(),
 			wantErr: false,
 		},
+		{
+			name: "valid API server record",
+			amcp: func() *AzureManagedControlPlane {
+				amcp := getKnownValidAzureManagedControlPlane()
+				amcp.Spec.APIServerDNS = &APIServerDNS{
+					ZoneResourceID: "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+					RecordName:     "api",
+				}
+				return amcp
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "API server record used for the control plane endpoint",
+			amcp: func() *AzureManagedControlPlane {
+				amcp := getKnownValidAzureManagedControlPlane()
+				amcp.Spec.APIServerDNS = &APIServerDNS{
+					ZoneResourceID:             "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+					RecordName:                 "api",
+					UseForControlPlaneEndpoint: true,
+				}
+				return amcp
+			}(),
+			wantErr:  true,
+			errorLen: 1,
+		},
 		{
 			name:     "invalid DNSServiceIP",
 			amcp:     createAzureManagedControlPlane("192.168.0.10.3", "v1.18.0", generateSSHPublicKey(true)),
@@ -1425,7 +2169,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("-thisi$"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1437,7 +2182,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("thisisaverylong$^clusternameconsistingofmorethan54characterswhichshouldbeinvalid"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1449,7 +2195,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("no_underscore"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1461,7 +2208,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("no-dollar$@%"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1473,7 +2221,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("hyphen-allowed"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1485,7 +2234,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("palette-test07"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1497,7 +2247,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					DNSPrefix: ptr.To("thisisavlerylongclu7l0sternam3leconsistingofmorethan54"),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 					},
 				},
 			},
@@ -1512,6 +2263,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					SSHPublicKey: ptr.To(generateSSHPublicKey(true)),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.23.5",
 					},
@@ -1529,6 +2281,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					SSHPublicKey: ptr.To(generateSSHPublicKey(true)),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.23.5",
 					},
@@ -1546,6 +2299,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 					},
 					SSHPublicKey: ptr.To(generateSSHPublicKey(true)),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.18.0",
 						AADProfile: &AADProfile{
@@ -1568,6 +2322,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 					},
 					SSHPublicKey: ptr.To(generateSSHPublicKey(true)),
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:  validIdentityRef(),
 						DNSServiceIP: ptr.To("192.168.0.10"),
 						Version:      "v1.18.0",
 						AADProfile: &AADProfile{
@@ -1586,6 +2341,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef:          validIdentityRef(),
 						Version:              "v1.21.2",
 						DisableLocalAccounts: ptr.To[bool](true),
 					},
@@ -1598,7 +2354,8 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.21.2",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.21.2",
 						AADProfile: &AADProfile{
 							Managed:             true,
 							AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
@@ -1699,6 +2456,44 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			amcp:    createAzureManagedControlPlane("192.168.0.10", "1.999.9", generateSSHPublicKey(true)),
 			wantErr: true,
 		},
+		{
+			name:    "AzureManagedControlPlane AutoScalerProfile can be updated",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey),
+			amcp: func() *AzureManagedControlPlane {
+				amcp := createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey)
+				amcp.Spec.AutoScalerProfile = &AutoScalerProfile{
+					BalanceSimilarNodeGroups:  (*BalanceSimilarNodeGroups)(ptr.To(string(BalanceSimilarNodeGroupsTrue))),
+					Expander:                  (*Expander)(ptr.To(string(ExpanderLeastWaste))),
+					MaxEmptyBulkDelete:        ptr.To("20"),
+					ScanInterval:              ptr.To("5s"),
+					ScaleDownDelayAfterAdd:    ptr.To("2m"),
+					SkipNodesWithLocalStorage: (*SkipNodesWithLocalStorage)(ptr.To(string(SkipNodesWithLocalStorageFalse))),
+				}
+				return amcp
+			}(),
+			wantErr: false,
+		},
+		{
+			name:    "AzureManagedControlPlane DeletionPolicy can't change to Retain without confirmation",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey),
+			amcp: func() *AzureManagedControlPlane {
+				amcp := createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey)
+				amcp.Spec.DeletionPolicy = DeletionPolicyRetain
+				return amcp
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "AzureManagedControlPlane DeletionPolicy can change to Retain with confirmation",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey),
+			amcp: func() *AzureManagedControlPlane {
+				amcp := createAzureManagedControlPlane("192.168.0.10", "v1.18.0", commonSSHKey)
+				amcp.Spec.DeletionPolicy = DeletionPolicyRetain
+				amcp.Annotations = map[string]string{RetainResourcesConfirmationAnnotation: "true"}
+				return amcp
+			}(),
+			wantErr: false,
+		},
 		{
 			name: "AzureManagedControlPlane AddonProfiles is mutable",
 			oldAMCP: &AzureManagedControlPlane{
@@ -1837,21 +2632,72 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 						Version: "v1.18.0",
 					},
 				},
-				Status: AzureManagedControlPlaneStatus{
-					AutoUpgradeVersion: "v1.18.3",
-				},
+				Status: AzureManagedControlPlaneStatus{
+					AutoUpgradeVersion: "v1.18.3",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						Version: "v1.18.1",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Autoupgrade cannot be set to nil",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						DNSServiceIP:   ptr.To("192.168.0.10"),
+						SubscriptionID: "212ec1q8",
+						Version:        "v1.18.0",
+						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
+							UpgradeChannel: ptr.To(UpgradeChannelStable),
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						DNSServiceIP:   ptr.To("192.168.0.10"),
+						SubscriptionID: "212ec1q8",
+						Version:        "v1.18.0",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Autoupgrade cannot be set to nil",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						DNSServiceIP:   ptr.To("192.168.0.10"),
+						SubscriptionID: "212ec1q8",
+						Version:        "v1.18.0",
+						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
+							UpgradeChannel: ptr.To(UpgradeChannelStable),
+						},
+					},
+				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.18.1",
+						DNSServiceIP:       ptr.To("192.168.0.10"),
+						SubscriptionID:     "212ec1q8",
+						Version:            "v1.18.0",
+						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{},
 					},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "AzureManagedControlPlane Autoupgrade cannot be set to nil",
+			name: "AzureManagedControlPlane Autoupgrade is mutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
@@ -1870,13 +2716,16 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 						DNSServiceIP:   ptr.To("192.168.0.10"),
 						SubscriptionID: "212ec1q8",
 						Version:        "v1.18.0",
+						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
+							UpgradeChannel: ptr.To(UpgradeChannelNone),
+						},
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "AzureManagedControlPlane Autoupgrade cannot be set to nil",
+			name: "AzureManagedControlPlane NodeOSUpgradeChannel cannot be set to nil",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
@@ -1884,7 +2733,7 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 						SubscriptionID: "212ec1q8",
 						Version:        "v1.18.0",
 						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
-							UpgradeChannel: ptr.To(UpgradeChannelStable),
+							NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannelNodeImage),
 						},
 					},
 				},
@@ -1902,7 +2751,7 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "AzureManagedControlPlane Autoupgrade is mutable",
+			name: "AzureManagedControlPlane NodeOSUpgradeChannel is mutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
@@ -1910,7 +2759,7 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 						SubscriptionID: "212ec1q8",
 						Version:        "v1.18.0",
 						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
-							UpgradeChannel: ptr.To(UpgradeChannelStable),
+							NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannelUnmanaged),
 						},
 					},
 				},
@@ -1922,7 +2771,7 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 						SubscriptionID: "212ec1q8",
 						Version:        "v1.18.0",
 						AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
-							UpgradeChannel: ptr.To(UpgradeChannelNone),
+							NodeOSUpgradeChannel: ptr.To(NodeOSUpgradeChannelNodeImage),
 						},
 					},
 				},
@@ -2329,6 +3178,36 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane legacy AAD can be migrated to managed AAD",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						Version: "v1.18.0",
+						AADProfile: &AADProfile{
+							Managed: false,
+							AdminGroupObjectIDs: []string{
+								"616077a8-5db7-4c98-b856-b34619afg75h",
+							},
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						Version: "v1.18.0",
+						AADProfile: &AADProfile{
+							Managed: true,
+							AdminGroupObjectIDs: []string{
+								"616077a8-5db7-4c98-b856-b34619afg75h",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "AzureManagedControlPlane adminGroupObjectIDs cannot set to empty",
 			oldAMCP: &AzureManagedControlPlane{
@@ -3171,12 +4050,72 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestAzureManagedControlPlane_ValidateCreatePlacement(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	withPlacement := func(subscriptionID, location string) *AzureManagedControlPlane {
+		amcp := getKnownValidAzureManagedControlPlane()
+		amcp.Spec.SubscriptionID = subscriptionID
+		amcp.Spec.Location = location
+		return amcp
+	}
+
+	tests := []struct {
+		name      string
+		allowlist PlacementAllowlist
+		amcp      *AzureManagedControlPlane
+		wantErr   bool
+	}{
+		{
+			name:      "empty allowlist allows everything",
+			allowlist: fakePlacementAllowlist{},
+			amcp:      withPlacement("123", "westus2"),
+			wantErr:   false,
+		},
+		{
+			name:      "allowed subscription and location",
+			allowlist: fakePlacementAllowlist{subscriptions: []string{"123"}, locations: []string{"eastus", "westus2"}},
+			amcp:      withPlacement("123", "westus2"),
+			wantErr:   false,
+		},
+		{
+			name:      "subscription not allowed",
+			allowlist: fakePlacementAllowlist{subscriptions: []string{"456"}},
+			amcp:      withPlacement("123", "westus2"),
+			wantErr:   true,
+		},
+		{
+			name:      "location not allowed",
+			allowlist: fakePlacementAllowlist{locations: []string{"eastus"}},
+			amcp:      withPlacement("123", "westus2"),
+			wantErr:   true,
+		},
+	}
+	client := mockClient{ReturnError: false}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mcpw := &azureManagedControlPlaneWebhook{
+				Client:    client,
+				allowlist: tc.allowlist,
+			}
+			_, err := mcpw.ValidateCreate(context.Background(), tc.amcp)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func createAzureManagedControlPlane(serviceIP, version, sshKey string) *AzureManagedControlPlane {
 	return &AzureManagedControlPlane{
 		ObjectMeta: getAMCPMetaData(),
 		Spec: AzureManagedControlPlaneSpec{
 			SSHPublicKey: &sshKey,
 			AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+				IdentityRef:  validIdentityRef(),
 				DNSServiceIP: ptr.To(serviceIP),
 				Version:      version,
 			},
@@ -3184,11 +4123,185 @@ func createAzureManagedControlPlane(serviceIP, version, sshKey string) *AzureMan
 	}
 }
 
+func TestAzureManagedControlPlane_ValidateIdentityRefFromTemplate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	// fromTemplate mimics the topology controller generating an AzureManagedControlPlane from a
+	// template, then applying the cluster-specific override (e.g. from a ClusterClass variable).
+	fromTemplate := func(templateRef, clusterRef *corev1.ObjectReference) *AzureManagedControlPlane {
+		cpt := getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+			cpt.Spec.Template.Spec.IdentityRef = templateRef
+		})
+		amcp := &AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-AMCP",
+			},
+			Spec: AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: cpt.Spec.Template.Spec.AzureManagedControlPlaneClassSpec,
+				SSHPublicKey:                      ptr.To(generateSSHPublicKey(true)),
+			},
+		}
+		if clusterRef != nil {
+			amcp.Spec.IdentityRef = clusterRef
+		}
+		return amcp
+	}
+	clusterRef := &corev1.ObjectReference{
+		Kind:      AzureClusterIdentityKind,
+		Name:      "team-a-identity",
+		Namespace: "team-a",
+	}
+
+	tests := []struct {
+		name    string
+		amcp    *AzureManagedControlPlane
+		wantRef *corev1.ObjectReference
+		wantErr string
+	}{
+		{
+			name:    "identityRef from the template",
+			amcp:    fromTemplate(validIdentityRef(), nil),
+			wantRef: validIdentityRef(),
+		},
+		{
+			name:    "identityRef supplied by the cluster",
+			amcp:    fromTemplate(nil, clusterRef),
+			wantRef: clusterRef,
+		},
+		{
+			name:    "identityRef supplied by the cluster overrides the template",
+			amcp:    fromTemplate(validIdentityRef(), clusterRef),
+			wantRef: clusterRef,
+		},
+		{
+			name:    "identityRef set by neither the template nor the cluster",
+			amcp:    fromTemplate(nil, nil),
+			wantErr: "identityRef must be set in the AzureManagedControlPlaneTemplate or supplied for this cluster",
+		},
+		{
+			name: "identityRef with an unsupported kind",
+			amcp: fromTemplate(nil, &corev1.ObjectReference{
+				Kind: "Secret",
+				Name: "cluster-identity",
+			}),
+			wantErr: "spec.identityRef.name",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mcpw := &azureManagedControlPlaneWebhook{}
+			_, err := mcpw.ValidateCreate(context.Background(), tc.amcp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tc.amcp.Spec.IdentityRef).To(Equal(tc.wantRef))
+		})
+	}
+}
+
+func TestAzureManagedControlPlane_ValidateIdentityRefUpdate(t *testing.T) {
+	g := NewWithT(t)
+	mcpw := &azureManagedControlPlaneWebhook{}
+
+	old := getKnownValidAzureManagedControlPlane()
+	old.Labels = nil
+	amcp := old.DeepCopy()
+	amcp.Spec.IdentityRef = nil
+	_, err := mcpw.ValidateUpdate(context.Background(), old, amcp)
+	g.Expect(err).To(MatchError(ContainSubstring("spec.identityRef")))
+
+	// Control planes created before identityRef was optional on templates are left alone.
+	old.Spec.IdentityRef = nil
+	_, err = mcpw.ValidateUpdate(context.Background(), old, amcp)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestAzureManagedControlPlane_DockerBridgeCIDRWarnings(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	g := NewWithT(t)
+	mcpw := &azureManagedControlPlaneWebhook{
+		Client: mockClient{ReturnError: false},
+	}
+
+	// An AzureManagedControlPlane stored by a release that passed the Docker bridge CIDR to AKS.
+	old := getKnownValidAzureManagedControlPlane()
+	g.Expect(json.Unmarshal([]byte(`{"dockerBridgeCidr": "172.17.0.1/16"}`), &old.Spec)).To(Succeed())
+	g.Expect(old.Spec.DockerBridgeCIDR).To(Equal(ptr.To("172.17.0.1/16")))
+
+	warnings, err := mcpw.ValidateCreate(context.Background(), old)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ConsistOf(ContainSubstring("spec.dockerBridgeCidr: the Docker bridge network is no longer supported by AKS")))
+
+	amcp := old.DeepCopy()
+	amcp.Spec.Version = "v1.18.1"
+	warnings, err = mcpw.ValidateUpdate(context.Background(), old, amcp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ContainElement(ContainSubstring("spec.dockerBridgeCidr: the Docker bridge network is no longer supported by AKS")))
+
+	amcp.Spec.DockerBridgeCIDR = nil
+	warnings, err = mcpw.ValidateUpdate(context.Background(), old, amcp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).NotTo(ContainElement(ContainSubstring("dockerBridgeCidr")))
+}
+
+func TestIsNetworkOrBroadcastAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		ip       string
+		cidr     string
+		expected bool
+	}{
+		{
+			name:     "IPv4 host address",
+			ip:       "10.0.0.10",
+			cidr:     "10.0.0.0/16",
+			expected: false,
+		},
+		{
+			name:     "IPv4 network address",
+			ip:       "10.0.0.10",
+			cidr:     "10.0.0.10/31",
+			expected: true,
+		},
+		{
+			name:     "IPv4 broadcast address",
+			ip:       "10.0.0.15",
+			cidr:     "10.0.0.8/29",
+			expected: true,
+		},
+		{
+			name:     "IPv6 network address",
+			ip:       "fd00::",
+			cidr:     "fd00::/108",
+			expected: true,
+		},
+		{
+			name:     "IPv6 last address",
+			ip:       "fd00::f:ffff",
+			cidr:     "fd00::/108",
+			expected: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, cidr, err := net.ParseCIDR(tc.cidr)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(isNetworkOrBroadcastAddress(net.ParseIP(tc.ip), cidr)).To(Equal(tc.expected))
+		})
+	}
+}
+
 func getKnownValidAzureManagedControlPlane() *AzureManagedControlPlane {
 	return &AzureManagedControlPlane{
 		ObjectMeta: getAMCPMetaData(),
 		Spec: AzureManagedControlPlaneSpec{
 			AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+				IdentityRef:  validIdentityRef(),
 				DNSServiceIP: ptr.To("192.168.0.10"),
 				Version:      "v1.18.0",
 				AADProfile: &AADProfile{
@@ -3203,6 +4316,14 @@ func getKnownValidAzureManagedControlPlane() *AzureManagedControlPlane {
 	}
 }
 
+func validIdentityRef() *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:      AzureClusterIdentityKind,
+		Name:      "cluster-identity",
+		Namespace: "default",
+	}
+}
+
 func getAMCPMetaData() metav1.ObjectMeta {
 	return metav1.ObjectMeta{
 		Name: "test-AMCP",
@@ -3225,7 +4346,8 @@ func TestAzureManagedClusterSecurityProfileValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						SecurityProfile: &ManagedClusterSecurityProfile{
 							WorkloadIdentity: &ManagedClusterSecurityProfileWorkloadIdentity{
 								Enabled: true,
@@ -3241,7 +4363,8 @@ func TestAzureManagedClusterSecurityProfileValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						SecurityProfile: &ManagedClusterSecurityProfile{
 							AzureKeyVaultKms: &AzureKeyVaultKms{
 								Enabled: true,
@@ -3257,6 +4380,7 @@ func TestAzureManagedClusterSecurityProfileValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						IdentityRef: validIdentityRef(),
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "not empty",
@@ -3279,7 +4403,8 @@ func TestAzureManagedClusterSecurityProfileValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "not empty",
@@ -3302,7 +4427,8 @@ func TestAzureManagedClusterSecurityProfileValidateCreate(t *testing.T) {
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
-						Version: "v1.17.8",
+						IdentityRef: validIdentityRef(),
+						Version:     "v1.17.8",
 						Identity: &Identity{
 							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
 							UserAssignedIdentityResourceID: "not empty",
@@ -3589,6 +4715,46 @@ func TestAzureClusterSecurityProfileValidateUpdate(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "AzureManagedControlPlane SecurityProfile.AzureKeyVaultKms.KeyID can be rotated",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						Version: "v1.18.0",
+						Identity: &Identity{
+							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+							UserAssignedIdentityResourceID: "not empty",
+						},
+						SecurityProfile: &ManagedClusterSecurityProfile{
+							AzureKeyVaultKms: &AzureKeyVaultKms{
+								Enabled:               true,
+								KeyID:                 "https://my-vault.vault.azure.net/keys/my-key/0000",
+								KeyVaultNetworkAccess: ptr.To(KeyVaultNetworkAccessTypesPublic),
+							},
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						Version: "v1.18.0",
+						Identity: &Identity{
+							Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+							UserAssignedIdentityResourceID: "not empty",
+						},
+						SecurityProfile: &ManagedClusterSecurityProfile{
+							AzureKeyVaultKms: &AzureKeyVaultKms{
+								Enabled:               true,
+								KeyID:                 "https://my-vault.vault.azure.net/keys/my-key/1111",
+								KeyVaultNetworkAccess: ptr.To(KeyVaultNetworkAccessTypesPublic),
+							},
+						},
+					},
+				},
+			},
+			wantErr: "",
+		},
 		{
 			name: "AzureManagedControlPlane SecurityProfile.AzureKeyVaultKms.KeyVaultNetworkAccess can be updated when KMS is enabled",
 			oldAMCP: &AzureManagedControlPlane{
@@ -3854,3 +5020,131 @@ func TestAzureClusterSecurityProfileValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureManagedControlPlane_ValidatePodIdentityProfile(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+
+	withPodIdentityProfile := func(networkPlugin string, profile *PodIdentityProfile) *AzureManagedControlPlane {
+		amcp := getKnownValidAzureManagedControlPlane()
+		if networkPlugin != "" {
+			amcp.Spec.NetworkPlugin = ptr.To(networkPlugin)
+		}
+		amcp.Spec.PodIdentityProfile = profile
+		return amcp
+	}
+	exception := func(name, namespace string, podLabels map[string]string) PodIdentityException {
+		return PodIdentityException{Name: name, Namespace: namespace, PodLabels: podLabels}
+	}
+
+	tests := []struct {
+		name         string
+		amcp         *AzureManagedControlPlane
+		wantErr      string
+		wantWarnings bool
+	}{
+		{
+			name: "disabled pod identity",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{}),
+		},
+		{
+			name: "enabled pod identity with exceptions",
+			amcp: withPodIdentityProfile("azure", &PodIdentityProfile{
+				Enabled: true,
+				UserAssignedIdentityExceptions: []PodIdentityException{
+					exception("legacy-app", "legacy", map[string]string{"app": "legacy-app"}),
+					exception("legacy-app", "other", map[string]string{"app.kubernetes.io/name": "legacy-app"}),
+				},
+			}),
+			wantWarnings: true,
+		},
+		{
+			name:         "enabled pod identity with kubenet",
+			amcp:         withPodIdentityProfile("kubenet", &PodIdentityProfile{Enabled: true}),
+			wantErr:      "Spec.PodIdentityProfile.Enabled: Forbidden",
+			wantWarnings: true,
+		},
+		{
+			name:         "enabled pod identity with kubenet allowed",
+			amcp:         withPodIdentityProfile("kubenet", &PodIdentityProfile{Enabled: true, AllowNetworkPluginKubenet: ptr.To(true)}),
+			wantWarnings: true,
+		},
+		{
+			name: "exception without name",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{
+				UserAssignedIdentityExceptions: []PodIdentityException{exception("", "legacy", map[string]string{"app": "legacy-app"})},
+			}),
+			wantErr: "Spec.PodIdentityProfile.UserAssignedIdentityExceptions[0].Name: Required value",
+		},
+		{
+			name: "exception with invalid namespace",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{
+				UserAssignedIdentityExceptions: []PodIdentityException{exception("legacy-app", "Legacy_NS", map[string]string{"app": "legacy-app"})},
+			}),
+			wantErr: "Spec.PodIdentityProfile.UserAssignedIdentityExceptions[0].Namespace: Invalid value",
+		},
+		{
+			name: "duplicate exceptions",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{
+				UserAssignedIdentityExceptions: []PodIdentityException{
+					exception("legacy-app", "legacy", map[string]string{"app": "legacy-app"}),
+					exception("legacy-app", "legacy", map[string]string{"app": "other-app"}),
+				},
+			}),
+			wantErr: "Spec.PodIdentityProfile.UserAssignedIdentityExceptions[1].Name: Duplicate value",
+		},
+		{
+			name: "exception without pod labels",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{
+				UserAssignedIdentityExceptions: []PodIdentityException{exception("legacy-app", "legacy", nil)},
+			}),
+			wantErr: "Spec.PodIdentityProfile.UserAssignedIdentityExceptions[0].PodLabels: Required value",
+		},
+		{
+			name: "exception with invalid pod label",
+			amcp: withPodIdentityProfile("", &PodIdentityProfile{
+				UserAssignedIdentityExceptions: []PodIdentityException{exception("legacy-app", "legacy", map[string]string{"app": "not a valid value"})},
+			}),
+			wantErr: "Spec.PodIdentityProfile.UserAssignedIdentityExceptions[0].PodLabels: Invalid value",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mcpw := &azureManagedControlPlaneWebhook{
+				Client: mockClient{ReturnError: false},
+			}
+			warnings, err := mcpw.ValidateCreate(context.Background(), tc.amcp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).To(ConsistOf(ContainSubstring("AAD pod identity is deprecated")))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureManagedControlPlane_ValidatePodIdentityProfileUpdate(t *testing.T) {
+	g := NewWithT(t)
+	mcpw := &azureManagedControlPlaneWebhook{
+		Client: mockClient{ReturnError: false},
+	}
+	old := getKnownValidAzureManagedControlPlane()
+	old.Spec.PodIdentityProfile = &PodIdentityProfile{Enabled: true}
+	amcp := old.DeepCopy()
+	amcp.Spec.PodIdentityProfile = &PodIdentityProfile{
+		Enabled: true,
+		UserAssignedIdentityExceptions: []PodIdentityException{
+			{Name: "legacy-app", Namespace: "legacy", PodLabels: map[string]string{"app": "legacy-app"}},
+		},
+	}
+
+	warnings, err := mcpw.ValidateUpdate(context.Background(), old, amcp)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ConsistOf(ContainSubstring("AAD pod identity is deprecated")))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// IsClonedFromAzureManagedControlPlaneTemplate returns true if the AzureManagedControlPlane was cloned from an
+// AzureManagedControlPlaneTemplate, e.g. by the ClusterClass topology controller.
+func (m *AzureManagedControlPlane) IsClonedFromAzureManagedControlPlaneTemplate() bool {
+	annotations := m.GetAnnotations()
+	return annotations[clusterv1.TemplateClonedFromNameAnnotation] != "" &&
+		annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] == GroupVersion.WithKind(AzureManagedControlPlaneTemplateKind).GroupKind().String()
+}
+
+// GetAzureManagedControlPlaneTemplate returns the AzureManagedControlPlaneTemplate the AzureManagedControlPlane was
+// cloned from. It returns nil if the AzureManagedControlPlane wasn't cloned from a template or the template doesn't
+// exist anymore.
+func GetAzureManagedControlPlaneTemplate(ctx context.Context, cli client.Reader, m *AzureManagedControlPlane) (*AzureManagedControlPlaneTemplate, error) {
+	if !m.IsClonedFromAzureManagedControlPlaneTemplate() {
+		return nil, nil
+	}
+	template := &AzureManagedControlPlaneTemplate{}
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation]}
+	if err := cli.Get(ctx, key, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+// autoScalerProfileValues returns the values of the autoscaler profile keyed by their JSON field name.
+func autoScalerProfileValues(p *AutoScalerProfile) map[string]*string {
+	if p == nil {
+		return nil
+	}
+	return map[string]*string{
+		"balanceSimilarNodeGroups":      (*string)(p.BalanceSimilarNodeGroups),
+		"expander":                      (*string)(p.Expander),
+		"maxEmptyBulkDelete":            p.MaxEmptyBulkDelete,
+		"maxGracefulTerminationSec":     p.MaxGracefulTerminationSec,
+		"maxNodeProvisionTime":          p.MaxNodeProvisionTime,
+		"maxTotalUnreadyPercentage":     p.MaxTotalUnreadyPercentage,
+		"newPodScaleUpDelay":            p.NewPodScaleUpDelay,
+		"okTotalUnreadyCount":           p.OkTotalUnreadyCount,
+		"scanInterval":                  p.ScanInterval,
+		"scaleDownDelayAfterAdd":        p.ScaleDownDelayAfterAdd,
+		"scaleDownDelayAfterDelete":     p.ScaleDownDelayAfterDelete,
+		"scaleDownDelayAfterFailure":    p.ScaleDownDelayAfterFailure,
+		"scaleDownUnneededTime":         p.ScaleDownUnneededTime,
+		"scaleDownUnreadyTime":          p.ScaleDownUnreadyTime,
+		"scaleDownUtilizationThreshold": p.ScaleDownUtilizationThreshold,
+		"skipNodesWithLocalStorage":     (*string)(p.SkipNodesWithLocalStorage),
+		"skipNodesWithSystemPods":       (*string)(p.SkipNodesWithSystemPods),
+	}
+}
+
+// clearedAutoScalerProfileWarnings returns a warning for each autoscaler profile key the AzureManagedControlPlane
+// clears while its template sets it, as the value of the template applies to the cluster instead.
+func clearedAutoScalerProfileWarnings(old, new *AzureManagedControlPlane, template *AzureManagedControlPlaneTemplate) admission.Warnings {
+	if template == nil {
+		return nil
+	}
+	oldValues := autoScalerProfileValues(old.Spec.AutoScalerProfile)
+	newValues := autoScalerProfileValues(new.Spec.AutoScalerProfile)
+	templateValues := autoScalerProfileValues(template.Spec.Template.Spec.AutoScalerProfile)
+
+	keys := make([]string, 0, len(templateValues))
+	for key := range templateValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings admission.Warnings
+	for _, key := range keys {
+		if templateValues[key] == nil || oldValues[key] == nil || newValues[key] != nil {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("spec.autoscalerProfile.%s was cleared, the value %q of AzureManagedControlPlaneTemplate %s applies instead",
+			key, *templateValues[key], template.Name))
+	}
+	return warnings
+}
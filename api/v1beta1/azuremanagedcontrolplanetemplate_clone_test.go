@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func clonedAzureManagedControlPlane(profile *AutoScalerProfile) *AzureManagedControlPlane {
+	return &AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "default",
+			Annotations: map[string]string{
+				clusterv1.TemplateClonedFromNameAnnotation:      "class-control-plane",
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "AzureManagedControlPlaneTemplate.infrastructure.cluster.x-k8s.io",
+			},
+		},
+		Spec: AzureManagedControlPlaneSpec{
+			AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+				AutoScalerProfile: profile,
+			},
+		},
+	}
+}
+
+func TestGetAzureManagedControlPlaneTemplate(t *testing.T) {
+	template := &AzureManagedControlPlaneTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-control-plane", Namespace: "default"},
+	}
+	notCloned := clonedAzureManagedControlPlane(nil)
+	notCloned.Annotations = nil
+	clonedFromOtherKind := clonedAzureManagedControlPlane(nil)
+	clonedFromOtherKind.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] = "KubeadmControlPlaneTemplate.controlplane.cluster.x-k8s.io"
+	deletedTemplate := clonedAzureManagedControlPlane(nil)
+	deletedTemplate.Annotations[clusterv1.TemplateClonedFromNameAnnotation] = "deleted"
+
+	tests := []struct {
+		name     string
+		amcp     *AzureManagedControlPlane
+		expected string
+	}{
+		{
+			name:     "cloned from a template",
+			amcp:     clonedAzureManagedControlPlane(nil),
+			expected: "class-control-plane",
+		},
+		{
+			name: "not cloned from a template",
+			amcp: notCloned,
+		},
+		{
+			name: "cloned from a template of another kind",
+			amcp: clonedFromOtherKind,
+		},
+		{
+			name: "cloned from a template that doesn't exist anymore",
+			amcp: deletedTemplate,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template.DeepCopy()).Build()
+
+			actual, err := GetAzureManagedControlPlaneTemplate(context.Background(), fakeClient, tc.amcp)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == "" {
+				g.Expect(actual).To(BeNil())
+			} else {
+				g.Expect(actual).NotTo(BeNil())
+				g.Expect(actual.Name).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestDefaultingWebhookClonedAutoScalerProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *AutoScalerProfile
+		expected *AutoScalerProfile
+	}{
+		{
+			name:     "template sets an autoscaler profile",
+			template: &AutoScalerProfile{Expander: ptr.To(ExpanderPriority)},
+			expected: &AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste)},
+		},
+		{
+			name:     "template doesn't set an autoscaler profile",
+			expected: setDefaultAutoScalerProfile(&AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste)}),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			template := &AzureManagedControlPlaneTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "class-control-plane", Namespace: "default"},
+				Spec: AzureManagedControlPlaneTemplateSpec{
+					Template: AzureManagedControlPlaneTemplateResource{
+						Spec: AzureManagedControlPlaneTemplateResourceSpec{
+							AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+								AutoScalerProfile: tc.template,
+							},
+						},
+					},
+				},
+			}
+			mcpw := &azureManagedControlPlaneWebhook{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(),
+			}
+			amcp := clonedAzureManagedControlPlane(&AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste)})
+
+			g.Expect(mcpw.Default(context.Background(), amcp)).To(Succeed())
+
+			g.Expect(amcp.Spec.AutoScalerProfile).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestClearedAutoScalerProfileWarnings(t *testing.T) {
+	template := &AzureManagedControlPlaneTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-control-plane", Namespace: "default"},
+		Spec: AzureManagedControlPlaneTemplateSpec{
+			Template: AzureManagedControlPlaneTemplateResource{
+				Spec: AzureManagedControlPlaneTemplateResourceSpec{
+					AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+						AutoScalerProfile: &AutoScalerProfile{
+							BalanceSimilarNodeGroups: ptr.To(BalanceSimilarNodeGroupsTrue),
+							Expander:                 ptr.To(ExpanderPriority),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		old      *AutoScalerProfile
+		new      *AutoScalerProfile
+		template *AzureManagedControlPlaneTemplate
+		expected []string
+	}{
+		{
+			name:     "cluster clears a key set by the template",
+			old:      &AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste), ScanInterval: ptr.To("20s")},
+			new:      &AutoScalerProfile{ScanInterval: ptr.To("20s")},
+			template: template,
+			expected: []string{`spec.autoscalerProfile.expander was cleared, the value "priority" of AzureManagedControlPlaneTemplate class-control-plane applies instead`},
+		},
+		{
+			name:     "cluster clears its whole profile",
+			old:      &AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste), BalanceSimilarNodeGroups: ptr.To(BalanceSimilarNodeGroupsFalse)},
+			new:      nil,
+			template: template,
+			expected: []string{
+				`spec.autoscalerProfile.balanceSimilarNodeGroups was cleared, the value "true" of AzureManagedControlPlaneTemplate class-control-plane applies instead`,
+				`spec.autoscalerProfile.expander was cleared, the value "priority" of AzureManagedControlPlaneTemplate class-control-plane applies instead`,
+			},
+		},
+		{
+			name:     "cluster clears a key the template doesn't set",
+			old:      &AutoScalerProfile{ScanInterval: ptr.To("20s")},
+			new:      &AutoScalerProfile{},
+			template: template,
+		},
+		{
+			name:     "cluster overrides a key set by the template",
+			old:      &AutoScalerProfile{},
+			new:      &AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste)},
+			template: template,
+		},
+		{
+			name: "cluster not cloned from a template",
+			old:  &AutoScalerProfile{Expander: ptr.To(ExpanderLeastWaste)},
+			new:  nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			warnings := clearedAutoScalerProfileWarnings(clonedAzureManagedControlPlane(tc.old), clonedAzureManagedControlPlane(tc.new), tc.template)
+			if tc.expected == nil {
+				g.Expect(warnings).To(BeEmpty())
+			} else {
+				g.Expect([]string(warnings)).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -74,7 +74,7 @@ func (mcpw *azureManagedControlPlaneTemplateWebhook) ValidateCreate(ctx context.
 		)
 	}
 
-	return nil, mcp.validateManagedControlPlaneTemplate(mcpw.Client)
+	return mcp.Spec.Template.Spec.dockerBridgeCIDRWarnings(field.NewPath("spec", "template", "spec", "dockerBridgeCidr")), mcp.validateManagedControlPlaneTemplate(mcpw.Client)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -150,7 +150,7 @@ func (mcpw *azureManagedControlPlaneTemplateWebhook) ValidateUpdate(ctx context.
 					field.Invalid(
 						field.NewPath("Spec", "Template", "Spec", "AADProfile.Managed"),
 						mcp.Spec.Template.Spec.AADProfile.Managed,
-						"cannot set AADProfile.Managed to false"))
+						"cannot set AADProfile.Managed to false, migrating from managed AAD back to the legacy AAD integration is not supported"))
 			}
 			if len(mcp.Spec.Template.Spec.AADProfile.AdminGroupObjectIDs) == 0 {
 				allErrs = append(allErrs,
@@ -187,11 +187,12 @@ func (mcpw *azureManagedControlPlaneTemplateWebhook) ValidateUpdate(ctx context.
 		allErrs = append(allErrs, errs...)
 	}
 
+	warnings := mcp.Spec.Template.Spec.dockerBridgeCIDRWarnings(field.NewPath("spec", "template", "spec", "dockerBridgeCidr"))
 	if len(allErrs) == 0 {
-		return nil, mcp.validateManagedControlPlaneTemplate(mcpw.Client)
+		return warnings, mcp.validateManagedControlPlaneTemplate(mcpw.Client)
 	}
 
-	return nil, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneTemplateKind).GroupKind(), mcp.Name, allErrs)
+	return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedControlPlaneTemplateKind).GroupKind(), mcp.Name, allErrs)
 }
 
 // Validate the Azure Managed Control Plane Template and return an aggregate error.
@@ -206,6 +207,12 @@ func (mcp *AzureManagedControlPlaneTemplate) validateManagedControlPlaneTemplate
 		mcp.Spec.Template.Spec.LoadBalancerProfile,
 		field.NewPath("spec").Child("template").Child("spec").Child("LoadBalancerProfile"))...)
 
+	allErrs = append(allErrs, validateOutboundType(
+		mcp.Spec.Template.Spec.OutboundType,
+		mcp.Spec.Template.Spec.NatGatewayProfile,
+		mcp.Spec.Template.Spec.VirtualNetwork.Subnet,
+		field.NewPath("spec").Child("template").Child("spec"))...)
+
 	allErrs = append(allErrs, validateManagedClusterNetwork(
 		cli,
 		mcp.Labels,
@@ -216,12 +223,33 @@ func (mcp *AzureManagedControlPlaneTemplate) validateManagedControlPlaneTemplate
 
 	allErrs = append(allErrs, validateName(mcp.Name, field.NewPath("Name"))...)
 
+	// IdentityRef may be left unset so each cluster can supply its own.
+	if mcp.Spec.Template.Spec.IdentityRef != nil {
+		if err := validateIdentityRef(mcp.Spec.Template.Spec.IdentityRef, field.NewPath("spec").Child("template").Child("spec").Child("identityRef")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
 	allErrs = append(allErrs, validateAutoScalerProfile(mcp.Spec.Template.Spec.AutoScalerProfile, field.NewPath("spec").Child("template").Child("spec").Child("AutoScalerProfile"))...)
 
+	allErrs = append(allErrs, validateUpgradeChannel(mcp.Spec.Template.Spec.AutoUpgradeProfile, field.NewPath("spec").Child("template").Child("spec").Child("autoUpgradeProfile").Child("upgradeChannel"))...)
+
+	allErrs = append(allErrs, validateNodeOSUpgradeChannel(mcp.Spec.Template.Spec.AutoUpgradeProfile, field.NewPath("spec").Child("template").Child("spec").Child("autoUpgradeProfile").Child("nodeOSUpgradeChannel"))...)
+
+	allErrs = append(allErrs, validateMaintenanceWindow(mcp.Spec.Template.Spec.MaintenanceWindow, field.NewPath("spec").Child("template").Child("spec").Child("maintenanceWindow"))...)
+
+	allErrs = append(allErrs, validateWindowsProfile(mcp.Spec.Template.Spec.WindowsProfile, field.NewPath("spec").Child("template").Child("spec").Child("windowsProfile"))...)
+
+	allErrs = append(allErrs, validateAdditionalSubnets(mcp.Spec.Template.Spec.VirtualNetwork, field.NewPath("spec").Child("template").Child("spec").Child("virtualNetwork").Child("subnets"))...)
+
+	allErrs = append(allErrs, validateAddonProfiles(mcp.Spec.Template.Spec.AddonProfiles, field.NewPath("spec").Child("template").Child("spec").Child("addonProfiles"))...)
+
 	allErrs = append(allErrs, validateAKSExtensions(mcp.Spec.Template.Spec.Extensions, field.NewPath("spec").Child("Extensions"))...)
 
 	allErrs = append(allErrs, mcp.Spec.Template.Spec.AzureManagedControlPlaneClassSpec.validateSecurityProfile()...)
 
+	allErrs = append(allErrs, mcp.Spec.Template.Spec.AzureManagedControlPlaneClassSpec.validatePodIdentityProfile(field.NewPath("spec").Child("template").Child("spec").Child("podIdentityProfile"))...)
+
 	allErrs = append(allErrs, validateNetworkPolicy(mcp.Spec.Template.Spec.NetworkPolicy, mcp.Spec.Template.Spec.NetworkDataplane, field.NewPath("spec").Child("template").Child("spec").Child("NetworkPolicy"))...)
 
 	allErrs = append(allErrs, validateNetworkDataplane(mcp.Spec.Template.Spec.NetworkDataplane, mcp.Spec.Template.Spec.NetworkPolicy, mcp.Spec.Template.Spec.NetworkPluginMode, field.NewPath("spec").Child("template").Child("spec").Child("NetworkDataplane"))...)
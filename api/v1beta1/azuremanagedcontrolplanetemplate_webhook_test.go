@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
@@ -162,6 +163,22 @@ func TestControlPlaneTemplateUpdateWebhook(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			name: "legacy AADProfile can be migrated to managed AAD",
+			oldControlPlaneTemplate: getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+				cpt.Spec.Template.Spec.AADProfile = &AADProfile{
+					Managed:             false,
+					AdminGroupObjectIDs: []string{"foo"},
+				}
+			}),
+			controlPlaneTemplate: getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+				cpt.Spec.Template.Spec.AADProfile = &AADProfile{
+					Managed:             true,
+					AdminGroupObjectIDs: []string{"foo"},
+				}
+			}),
+			wantErr: false,
+		},
 		{
 			name: "length of AADProfile.AdminGroupObjectIDs cannot be zero",
 			oldControlPlaneTemplate: getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
@@ -427,6 +444,111 @@ func TestValidateAPIServerAccessProfileUpdate(t *testing.T) {
 	}
 }
 
+func TestControlPlaneTemplateDockerBridgeCIDRWarnings(t *testing.T) {
+	g := NewWithT(t)
+	cpw := &azureManagedControlPlaneTemplateWebhook{}
+
+	old := getAzureManagedControlPlaneTemplate()
+	cpt := getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+		cpt.Spec.Template.Spec.DockerBridgeCIDR = ptr.To("172.17.0.1/16")
+	})
+
+	warnings, err := cpw.ValidateUpdate(context.Background(), old, cpt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(ConsistOf(ContainSubstring("spec.template.spec.dockerBridgeCidr: the Docker bridge network is no longer supported by AKS")))
+
+	warnings, err = cpw.ValidateUpdate(context.Background(), old, old)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
+
+func TestControlPlaneTemplateIdentityRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		identityRef *corev1.ObjectReference
+		wantErr     bool
+	}{
+		{
+			name:        "identityRef may be left for each cluster to supply",
+			identityRef: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "identityRef shared by every cluster",
+			identityRef: validIdentityRef(),
+			wantErr:     false,
+		},
+		{
+			name: "identityRef with an unsupported kind",
+			identityRef: &corev1.ObjectReference{
+				Kind: "Secret",
+				Name: "cluster-identity",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cpt := getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+				cpt.Spec.Template.Spec.IdentityRef = tc.identityRef
+			})
+			err := cpt.validateManagedControlPlaneTemplate(nil)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestControlPlaneTemplatePodIdentityProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *PodIdentityProfile
+		wantErr string
+	}{
+		{
+			name:    "pod identity profile may be left for each cluster to supply",
+			profile: nil,
+		},
+		{
+			name: "valid pod identity profile shared by every cluster",
+			profile: &PodIdentityProfile{
+				Enabled: true,
+				UserAssignedIdentityExceptions: []PodIdentityException{
+					{Name: "exception", Namespace: "default", PodLabels: map[string]string{"app": "web"}},
+				},
+			},
+		},
+		{
+			name: "pod identity exception without pod labels",
+			profile: &PodIdentityProfile{
+				Enabled: true,
+				UserAssignedIdentityExceptions: []PodIdentityException{
+					{Name: "exception", Namespace: "default"},
+				},
+			},
+			wantErr: "spec.template.spec.podIdentityProfile.UserAssignedIdentityExceptions[0].PodLabels: Required value",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cpt := getAzureManagedControlPlaneTemplate(func(cpt *AzureManagedControlPlaneTemplate) {
+				cpt.Spec.Template.Spec.PodIdentityProfile = tc.profile
+			})
+			err := cpt.validateManagedControlPlaneTemplate(nil)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func getAzureManagedControlPlaneTemplate(changes ...func(*AzureManagedControlPlaneTemplate)) *AzureManagedControlPlaneTemplate {
 	input := &AzureManagedControlPlaneTemplate{
 		ObjectMeta: metav1.ObjectMeta{
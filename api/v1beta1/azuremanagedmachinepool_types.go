@@ -34,6 +34,37 @@ const (
 
 	// DefaultOSType represents the default operating system for azmachinepool.
 	DefaultOSType string = LinuxOS
+
+	// OsDiskTypeEphemeral represents an ephemeral OS disk placed on the VM cache or temp disk.
+	OsDiskTypeEphemeral string = "Ephemeral"
+
+	// OsDiskTypeManaged represents a managed OS disk.
+	OsDiskTypeManaged string = "Managed"
+
+	// ScaleSetPrioritySpot represents a node pool of spot VMs, which can be evicted.
+	ScaleSetPrioritySpot string = "Spot"
+
+	// ScaleDownModeDelete represents a node pool whose nodes are deleted when scaling down.
+	ScaleDownModeDelete string = "Delete"
+
+	// ScaleDownModeDeallocate represents a node pool whose nodes are deallocated when scaling down and started again
+	// when scaling up.
+	ScaleDownModeDeallocate string = "Deallocate"
+
+	// OSSKUAzureLinux represents the Azure Linux node image.
+	OSSKUAzureLinux string = "AzureLinux"
+
+	// OSSKUCBLMariner represents the CBL-Mariner node image.
+	OSSKUCBLMariner string = "CBLMariner"
+
+	// OSSKUUbuntu represents the Ubuntu node image.
+	OSSKUUbuntu string = "Ubuntu"
+
+	// OSSKUWindows2019 represents the Windows Server 2019 node image.
+	OSSKUWindows2019 string = "Windows2019"
+
+	// OSSKUWindows2022 represents the Windows Server 2022 node image.
+	OSSKUWindows2022 string = "Windows2022"
 )
 
 // NodePoolMode enumerates the values for agent pool mode.
@@ -380,6 +411,18 @@ type SysctlConfig struct {
 	VMVfsCachePressure *int `json:"vmVfsCachePressure,omitempty"`
 }
 
+// AgentPoolNetworkProfile specifies the network settings of the nodes of an agent pool.
+type AgentPoolNetworkProfile struct {
+	// NodePublicIPTags specifies the IP tags of the public IPs of the nodes, keyed by IP tag type.
+	// Example: RoutingPreference: Internet. Requires EnableNodePublicIP.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/use-node-public-ips#use-public-ip-tags-on-node-public-ips
+	// +optional
+	NodePublicIPTags map[string]string `json:"nodePublicIPTags,omitempty"`
+}
+
 // LinuxOSConfig specifies the custom Linux OS settings and configurations.
 // See also [AKS doc].
 //
@@ -460,6 +503,18 @@ type AzureManagedMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// ScaleDownMode is the most recently observed scale down mode of the agent pool.
+	// +optional
+	ScaleDownMode *string `json:"scaleDownMode,omitempty"`
+
+	// NodeImageVersion is the most recently observed version of the node image of the agent pool.
+	// +optional
+	NodeImageVersion *string `json:"nodeImageVersion,omitempty"`
+
+	// SpotEvictions counts the evictions of the spot nodes of the agent pool.
+	// +optional
+	SpotEvictions *SpotEvictionsStatus `json:"spotEvictions,omitempty"`
+
 	// Any transient errors that occur during the reconciliation of Machines
 	// can be added as events to the Machine object and/or logged in the
 	// controller's output.
@@ -482,6 +537,30 @@ type AzureManagedMachinePoolStatus struct {
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
 }
 
+// SpotEvictionsStatus counts the evictions of the spot nodes of an agent pool.
+type SpotEvictionsStatus struct {
+	// Count is the number of evictions of the spot nodes of the agent pool observed since it was created.
+	Count int32 `json:"count"`
+
+	// LastEvictionTime is the time of the last observed eviction.
+	// +optional
+	LastEvictionTime *metav1.Time `json:"lastEvictionTime,omitempty"`
+
+	// CountedEvents are the eviction events of the workload cluster which are already counted, so that they aren't
+	// counted again.
+	// +optional
+	CountedEvents []CountedEvictionEvent `json:"countedEvents,omitempty"`
+}
+
+// CountedEvictionEvent is an eviction event of the workload cluster which is already counted.
+type CountedEvictionEvent struct {
+	// UID is the UID of the event.
+	UID string `json:"uid"`
+
+	// Count is the number of occurrences of the event which are already counted.
+	Count int32 `json:"count"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AzureManagedMachinePool belongs"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
@@ -19,13 +19,17 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -41,11 +45,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-var validNodePublicPrefixID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.network/publicipprefixes/[^/]+$`)
+var (
+	validNodePublicPrefixID         = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.network/publicipprefixes/[^/]+$`)
+	validProximityPlacementGroupID  = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.compute/proximityplacementgroups/[^/]+$`)
+	validCapacityReservationGroupID = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.compute/capacityreservationgroups/[^/]+$`)
+	validNodePoolSnapshotID         = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.containerservice/snapshots/[^/]+$`)
+	validSubnetID                   = regexp.MustCompile(`(?i)^/?subscriptions/[0-9a-f]{8}-([0-9a-f]{4}-){3}[0-9a-f]{12}/resourcegroups/[^/]+/providers/microsoft\.network/virtualnetworks/[^/]+/subnets/[^/]+$`)
+)
+
+// defaultAzureCNIMaxPods is the maximum number of pods per node AKS uses by default with Azure CNI.
+const defaultAzureCNIMaxPods = 30
+
+// localDiskSizeLookupTimeout is how long the webhook waits for the local disk sizes of a VM size before admitting the
+// AzureManagedMachinePool without checking its disks.
+const localDiskSizeLookupTimeout = 5 * time.Second
+
+// LocalDiskSizeGetter gets the sizes of the local disks of the VM size of an AzureManagedMachinePool. It is implemented
+// outside of the API package as it needs to call Azure.
+// +kubebuilder:object:generate=false
+type LocalDiskSizeGetter interface {
+	// GetMaxEphemeralOSDiskSizeGB returns the largest ephemeral OS disk size supported by the VM size.
+	GetMaxEphemeralOSDiskSizeGB(ctx context.Context, managedMachinePool *AzureManagedMachinePool) (int, error)
+	// GetTempDiskSizeGB returns the size of the temp disk of the VM size, which is 0 when it has no temp disk.
+	GetTempDiskSizeGB(ctx context.Context, managedMachinePool *AzureManagedMachinePool) (int, error)
+}
 
-// SetupAzureManagedMachinePoolWebhookWithManager sets up and registers the webhook with the manager.
-func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
-	mw := &azureManagedMachinePoolWebhook{Client: mgr.GetClient()}
+// SetupAzureManagedMachinePoolWebhookWithManager sets up and registers the webhook with the manager. When
+// enforceSubnetCapacity is set, node pools whose pods may not fit in their subnet are rejected rather than admitted
+// with a warning.
+func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager, diskSizeGetter LocalDiskSizeGetter, enforceSubnetCapacity bool) error {
+	mw := &azureManagedMachinePoolWebhook{Client: mgr.GetClient(), diskSizeGetter: diskSizeGetter, enforceSubnetCapacity: enforceSubnetCapacity}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AzureManagedMachinePool{}).
 		WithDefaulter(mw).
@@ -57,7 +86,9 @@ func SetupAzureManagedMachinePoolWebhookWithManager(mgr ctrl.Manager) error {
 
 // azureManagedMachinePoolWebhook implements a validating and defaulting webhook for AzureManagedMachinePool.
 type azureManagedMachinePoolWebhook struct {
-	Client client.Client
+	Client                client.Client
+	diskSizeGetter        LocalDiskSizeGetter
+	enforceSubnetCapacity bool
 }
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
@@ -79,6 +110,14 @@ func (mw *azureManagedMachinePoolWebhook) Default(ctx context.Context, obj runti
 		m.Spec.OSType = ptr.To(DefaultOSType)
 	}
 
+	// The scale down mode is only defaulted for new node pools, so that the agent pools of existing node pools aren't
+	// updated.
+	if m.Spec.ScaleDownMode == nil {
+		if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Create {
+			m.Spec.ScaleDownMode = ptr.To(ScaleDownModeDelete)
+		}
+	}
+
 	return nil
 }
 
@@ -110,6 +149,11 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 		m.Spec.OSType,
 		field.NewPath("Spec", "OSType")))
 
+	errs = append(errs, validateOSSKU(
+		m.Spec.OSType,
+		m.Spec.OSSKU,
+		field.NewPath("Spec", "OSSKU")))
+
 	errs = append(errs, validateMPName(
 		m.Name,
 		m.Spec.Name,
@@ -129,6 +173,26 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 		m.Spec.NodePublicIPPrefixID,
 		field.NewPath("Spec", "EnableNodePublicIP")))
 
+	errs = append(errs, validateResourceID(
+		m.Spec.ProximityPlacementGroupID,
+		validProximityPlacementGroupID,
+		field.NewPath("Spec", "ProximityPlacementGroupID")))
+
+	errs = append(errs, validateResourceID(
+		m.Spec.CapacityReservationGroupID,
+		validCapacityReservationGroupID,
+		field.NewPath("Spec", "CapacityReservationGroupID")))
+
+	errs = append(errs, validateResourceID(
+		m.Spec.NodePoolSnapshotID,
+		validNodePoolSnapshotID,
+		field.NewPath("Spec", "NodePoolSnapshotID")))
+
+	errs = append(errs, validateNodePublicIPTags(
+		m.Spec.EnableNodePublicIP,
+		m.Spec.NetworkProfile,
+		field.NewPath("Spec", "NetworkProfile", "NodePublicIPTags")))
+
 	errs = append(errs, validateKubeletConfig(
 		m.Spec.KubeletConfig,
 		field.NewPath("Spec", "KubeletConfig")))
@@ -142,7 +206,360 @@ func (mw *azureManagedMachinePoolWebhook) ValidateCreate(ctx context.Context, ob
 		m.Spec.SubnetName,
 		field.NewPath("Spec", "SubnetName")))
 
-	return nil, kerrors.NewAggregate(errs)
+	errs = append(errs, validatePodSubnetName(
+		m.Spec.PodSubnetName,
+		field.NewPath("Spec", "PodSubnetName")))
+
+	errs = append(errs, validateSpotMaxPrice(
+		m.Spec.ScaleSetPriority,
+		m.Spec.SpotMaxPrice,
+		field.NewPath("Spec", "SpotMaxPrice")).ToAggregate())
+
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+
+	warnings := encryptionAtHostWarnings(m)
+	ephemeralOSDiskWarnings, err := mw.validateEphemeralOSDiskSize(ctx, m)
+	warnings = append(warnings, ephemeralOSDiskWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+	kubeletDiskWarnings, err := mw.validateKubeletDiskType(ctx, m)
+	warnings = append(warnings, kubeletDiskWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+	controlPlaneWarnings, err := mw.validateControlPlaneCompatibility(ctx, m)
+	return append(warnings, controlPlaneWarnings...), err
+}
+
+// validateControlPlaneCompatibility validates the AzureManagedMachinePool against the AzureManagedControlPlane and the
+// system pools of its cluster, which AKS would otherwise only report after trying to create the agent pool. If they
+// can't be read, the AzureManagedMachinePool is admitted with a warning.
+func (mw *azureManagedMachinePoolWebhook) validateControlPlaneCompatibility(ctx context.Context, m *AzureManagedMachinePool) (admission.Warnings, error) {
+	clusterName, ok := m.Labels[clusterv1.ClusterNameLabel]
+	if mw.Client == nil || !ok {
+		return nil, nil
+	}
+
+	controlPlane, err := getClusterAzureManagedControlPlane(ctx, mw.Client, m.Namespace, clusterName)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("skipped validating against the AzureManagedControlPlane of cluster %s: %v", clusterName, err)}, nil
+	}
+	if controlPlane == nil {
+		return nil, nil
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateWindowsNetworkPlugin(m, controlPlane)...)
+	allErrs = append(allErrs, validatePodSubnet(m, controlPlane)...)
+	allErrs = append(allErrs, validateNetAppVolumesSubnetUsage(m, controlPlane)...)
+	warnings, errs := mw.validateSubnetCapacity(m, controlPlane)
+	allErrs = append(allErrs, errs...)
+
+	pools := &AzureManagedMachinePoolList{}
+	if err := mw.Client.List(ctx, pools, client.InNamespace(m.Namespace), client.MatchingLabels{
+		clusterv1.ClusterNameLabel: clusterName,
+	}); err != nil {
+		warnings = append(warnings, fmt.Sprintf("skipped validating against the other node pools of cluster %s: %v", clusterName, err))
+		return warnings, allErrs.ToAggregate()
+	}
+	var otherPools, systemPools []AzureManagedMachinePool
+	for _, pool := range pools.Items {
+		if pool.Name == m.Name {
+			continue
+		}
+		otherPools = append(otherPools, pool)
+		if pool.Labels[LabelAgentPoolMode] == string(NodePoolModeSystem) {
+			systemPools = append(systemPools, pool)
+		}
+	}
+	allErrs = append(allErrs, validateUltraSSDZones(m, systemPools)...)
+	allErrs = append(allErrs, validatePodSubnetConsistency(m, otherPools)...)
+
+	return warnings, allErrs.ToAggregate()
+}
+
+// validateSubnetCapacity validates that the pods of a node pool fit in its subnet, returning the problems as warnings
+// unless the webhook enforces the subnet capacity.
+func (mw *azureManagedMachinePoolWebhook) validateSubnetCapacity(m *AzureManagedMachinePool, controlPlane *AzureManagedControlPlane) (admission.Warnings, field.ErrorList) {
+	errs := validatePodIPCapacity(m, controlPlane)
+	if mw.enforceSubnetCapacity {
+		return nil, errs
+	}
+	var warnings admission.Warnings
+	for _, err := range errs {
+		warnings = append(warnings, err.Error())
+	}
+	return warnings, nil
+}
+
+// validateUpdatedSubnetCapacity validates that the pods of a node pool still fit in its subnet when its maximum
+// number of nodes changes. If the AzureManagedControlPlane can't be read, the update is admitted with a warning.
+func (mw *azureManagedMachinePoolWebhook) validateUpdatedSubnetCapacity(ctx context.Context, old, m *AzureManagedMachinePool) (admission.Warnings, field.ErrorList) {
+	clusterName, ok := m.Labels[clusterv1.ClusterNameLabel]
+	if mw.Client == nil || !ok || maxNodes(old) == maxNodes(m) {
+		return nil, nil
+	}
+	controlPlane, err := getClusterAzureManagedControlPlane(ctx, mw.Client, m.Namespace, clusterName)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("skipped validating the subnet capacity against the AzureManagedControlPlane of cluster %s: %v", clusterName, err)}, nil
+	}
+	if controlPlane == nil {
+		return nil, nil
+	}
+	return mw.validateSubnetCapacity(m, controlPlane)
+}
+
+// getClusterAzureManagedControlPlane returns the AzureManagedControlPlane of a cluster. It returns nil if the cluster's
+// control plane isn't an AzureManagedControlPlane or isn't set yet.
+func getClusterAzureManagedControlPlane(ctx context.Context, cli client.Reader, namespace, clusterName string) (*AzureManagedControlPlane, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		return nil, err
+	}
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != AzureManagedControlPlaneKind {
+		return nil, nil
+	}
+
+	controlPlane := &AzureManagedControlPlane{}
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = namespace
+	}
+	if err := cli.Get(ctx, key, controlPlane); err != nil {
+		return nil, err
+	}
+	return controlPlane, nil
+}
+
+// validateWindowsNetworkPlugin validates that Windows node pools are only added to clusters using Azure CNI.
+func validateWindowsNetworkPlugin(m *AzureManagedMachinePool, controlPlane *AzureManagedControlPlane) field.ErrorList {
+	networkPlugin := ptr.Deref(controlPlane.Spec.NetworkPlugin, AzureNetworkPluginName)
+	if ptr.Deref(m.Spec.OSType, "") != WindowsOS || networkPlugin == AzureNetworkPluginName {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(
+		field.NewPath("Spec", "OSType"),
+		WindowsOS,
+		fmt.Sprintf("Windows node pools require the %q network plugin but AzureManagedControlPlane %s uses %q, use a Linux node pool or a cluster with Azure CNI", AzureNetworkPluginName, controlPlane.Name, networkPlugin))}
+}
+
+// validatePodSubnet validates that a node pool only uses a pod subnet with Azure CNI dynamic IP allocation, and that
+// a pod subnet referenced by name is one of the subnets of the control plane.
+func validatePodSubnet(m *AzureManagedMachinePool, controlPlane *AzureManagedControlPlane) field.ErrorList {
+	if m.Spec.PodSubnetName == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "PodSubnetName")
+	podSubnetName := *m.Spec.PodSubnetName
+	networkPlugin := ptr.Deref(controlPlane.Spec.NetworkPlugin, AzureNetworkPluginName)
+	if networkPlugin != AzureNetworkPluginName || ptr.Deref(controlPlane.Spec.NetworkPluginMode, "") == NetworkPluginModeOverlay {
+		allErrs = append(allErrs, field.Invalid(fldPath, podSubnetName,
+			fmt.Sprintf("pod subnets require the %q network plugin without overlay but AzureManagedControlPlane %s uses %q, remove the pod subnet or use a cluster with Azure CNI dynamic IP allocation", AzureNetworkPluginName, controlPlane.Name, networkPlugin)))
+	}
+	if IsSubnetID(podSubnetName) {
+		return allErrs
+	}
+	if podSubnetName == ptr.Deref(m.Spec.SubnetName, controlPlane.Spec.VirtualNetwork.Subnet.Name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, podSubnetName,
+			"the pod subnet must not be the node subnet of the pool, use another subnet of VirtualNetwork.Subnets"))
+		return allErrs
+	}
+	for _, subnet := range controlPlane.Spec.VirtualNetwork.Subnets {
+		if subnet.Name == podSubnetName {
+			return allErrs
+		}
+	}
+	allErrs = append(allErrs, field.Invalid(fldPath, podSubnetName,
+		fmt.Sprintf("subnet %s is not one of the VirtualNetwork.Subnets of AzureManagedControlPlane %s, add it there or use the resource ID of an existing subnet", podSubnetName, controlPlane.Name)))
+	return allErrs
+}
+
+// validateNetAppVolumesSubnetUsage validates that a node pool uses neither a subnet delegated to Azure NetApp Files
+// as its node subnet nor as its pod subnet, as Azure doesn't allow such a subnet to host other resources.
+func validateNetAppVolumesSubnetUsage(m *AzureManagedMachinePool, controlPlane *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+	nodeSubnetName, _ := nodeSubnet(m, controlPlane.Spec.VirtualNetwork)
+	for _, subnet := range controlPlane.Spec.VirtualNetwork.Subnets {
+		if !isNetAppVolumesSubnet(subnet) {
+			continue
+		}
+		if subnet.Name == nodeSubnetName {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "SubnetName"), subnet.Name,
+				fmt.Sprintf("subnet %s of AzureManagedControlPlane %s is delegated to %s, use another subnet of VirtualNetwork.Subnets for the nodes", subnet.Name, controlPlane.Name, NetAppVolumesDelegation)))
+		}
+		if ptr.Deref(m.Spec.PodSubnetName, "") == subnet.Name {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "PodSubnetName"), subnet.Name,
+				fmt.Sprintf("subnet %s of AzureManagedControlPlane %s is delegated to %s, use another subnet of VirtualNetwork.Subnets for the pods", subnet.Name, controlPlane.Name, NetAppVolumesDelegation)))
+		}
+	}
+	return allErrs
+}
+
+// validatePodIPCapacity validates that the subnet of a node pool has enough addresses for the nodes of the pool and
+// their pods in the worst case, i.e. when the autoscaler scales it to its max count, when pods get their IPs from the
+// node subnet, i.e. with Azure CNI without overlay.
+func validatePodIPCapacity(m *AzureManagedMachinePool, controlPlane *AzureManagedControlPlane) field.ErrorList {
+	if ptr.Deref(controlPlane.Spec.NetworkPlugin, AzureNetworkPluginName) != AzureNetworkPluginName ||
+		ptr.Deref(controlPlane.Spec.NetworkPluginMode, "") == NetworkPluginModeOverlay ||
+		m.Spec.PodSubnetName != nil {
+		return nil
+	}
+	subnetName, cidrBlock := nodeSubnet(m, controlPlane.Spec.VirtualNetwork)
+	_, cidr, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return nil
+	}
+
+	ones, bits := cidr.Mask.Size()
+	if bits-ones >= 31 {
+		return nil
+	}
+	// Azure reserves the first four and the last address of each subnet.
+	usable := (1 << (bits - ones)) - 5
+	nodes := maxNodes(m)
+	maxPods := ptr.Deref(m.Spec.MaxPods, defaultAzureCNIMaxPods)
+	// Each node uses one address for itself and one for each pod it can run.
+	required := nodes * (maxPods + 1)
+	if required <= usable {
+		return nil
+	}
+	fldPath, value := field.NewPath("Spec", "MaxPods"), maxPods
+	if m.Spec.Scaling != nil && m.Spec.Scaling.MaxSize != nil {
+		fldPath, value = field.NewPath("Spec", "Scaling", "MaxSize"), nodes
+	}
+	return field.ErrorList{field.Invalid(
+		fldPath,
+		value,
+		fmt.Sprintf("with Azure CNI pods get their IPs from subnet %s (%s) which has %d usable addresses, but %d node(s) with maxPods %d need %d * (%d + 1) = %d, use a larger subnet, lower the max count or maxPods, or use the %q network plugin mode", subnetName, cidrBlock, usable, nodes, maxPods, nodes, maxPods, required, NetworkPluginModeOverlay))}
+}
+
+// nodeSubnet returns the name and the CIDR block of the subnet of the virtual network a node pool is placed in. The
+// CIDR block is empty if the subnet isn't one of the subnets of the virtual network.
+func nodeSubnet(m *AzureManagedMachinePool, virtualNetwork ManagedControlPlaneVirtualNetwork) (string, string) {
+	name := ptr.Deref(m.Spec.SubnetName, virtualNetwork.Subnet.Name)
+	if name == virtualNetwork.Subnet.Name {
+		return name, virtualNetwork.Subnet.CIDRBlock
+	}
+	for _, subnet := range virtualNetwork.Subnets {
+		if subnet.Name == name {
+			return name, subnet.CIDRBlock
+		}
+	}
+	return name, ""
+}
+
+// maxNodes returns the maximum number of nodes of a node pool, i.e. the max count of the autoscaler when it is
+// enabled, or 1 otherwise.
+func maxNodes(m *AzureManagedMachinePool) int {
+	nodes := 1
+	if m.Spec.Scaling != nil && ptr.Deref(m.Spec.Scaling.MaxSize, 0) > nodes {
+		nodes = *m.Spec.Scaling.MaxSize
+	}
+	return nodes
+}
+
+// validateUltraSSDZones validates that a node pool enabling ultra SSD is zonal when the system pools of the cluster
+// are, as ultra SSD is then only supported on zonal VMs.
+func validateUltraSSDZones(m *AzureManagedMachinePool, systemPools []AzureManagedMachinePool) field.ErrorList {
+	if !ptr.Deref(m.Spec.EnableUltraSSD, false) || len(m.Spec.AvailabilityZones) > 0 {
+		return nil
+	}
+	for _, pool := range systemPools {
+		if len(pool.Spec.AvailabilityZones) == 0 {
+			continue
+		}
+		return field.ErrorList{field.Required(
+			field.NewPath("Spec", "AvailabilityZones"),
+			fmt.Sprintf("ultra SSD requires a zonal node pool in a region with availability zones, as used by system pool %s (zones %s), set availabilityZones or disable enableUltraSSD", pool.Name, strings.Join(pool.Spec.AvailabilityZones, ",")))}
+	}
+	return nil
+}
+
+// validatePodSubnetConsistency validates that a node pool uses a pod subnet if and only if the other node pools of its
+// cluster do, as AKS doesn't allow mixing node pools with and without a pod subnet in a cluster.
+func validatePodSubnetConsistency(m *AzureManagedMachinePool, otherPools []AzureManagedMachinePool) field.ErrorList {
+	for _, pool := range otherPools {
+		if (pool.Spec.PodSubnetName == nil) == (m.Spec.PodSubnetName == nil) {
+			continue
+		}
+		if m.Spec.PodSubnetName == nil {
+			return field.ErrorList{field.Required(
+				field.NewPath("Spec", "PodSubnetName"),
+				fmt.Sprintf("node pool %s of the cluster uses a pod subnet, so every node pool of the cluster must use one", pool.Name))}
+		}
+		return field.ErrorList{field.Invalid(
+			field.NewPath("Spec", "PodSubnetName"),
+			*m.Spec.PodSubnetName,
+			fmt.Sprintf("node pool %s of the cluster doesn't use a pod subnet, so no node pool of the cluster can use one", pool.Name))}
+	}
+	return nil
+}
+
+// encryptionAtHostWarnings warns that encryption at host requires the EncryptionAtHost feature to be registered on
+// the subscription, which the webhook can't check and AKS only reports after trying to create the agent pool.
+func encryptionAtHostWarnings(m *AzureManagedMachinePool) admission.Warnings {
+	if !ptr.Deref(m.Spec.EnableEncryptionAtHost, false) {
+		return nil
+	}
+	return admission.Warnings{
+		"spec.enableEncryptionAtHost requires the Microsoft.Compute/EncryptionAtHost feature to be registered on the subscription, " +
+			"see https://learn.microsoft.com/azure/aks/enable-host-encryption",
+	}
+}
+
+// validateEphemeralOSDiskSize validates that an ephemeral OS disk fits in the cache or temp disk of the VM size, which
+// AKS would otherwise only report after trying to create the agent pool. If the size can't be looked up, the
+// AzureManagedMachinePool is admitted with a warning.
+func (mw *azureManagedMachinePoolWebhook) validateEphemeralOSDiskSize(ctx context.Context, m *AzureManagedMachinePool) (admission.Warnings, error) {
+	if mw.diskSizeGetter == nil ||
+		!strings.EqualFold(ptr.Deref(m.Spec.OsDiskType, ""), OsDiskTypeEphemeral) ||
+		ptr.Deref(m.Spec.OSDiskSizeGB, 0) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, localDiskSizeLookupTimeout)
+	defer cancel()
+	maxSizeGB, err := mw.diskSizeGetter.GetMaxEphemeralOSDiskSizeGB(ctx, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("skipped validating the ephemeral OS disk size for VM size %s: %v", m.Spec.SKU, err)}, nil
+	}
+
+	if *m.Spec.OSDiskSizeGB > maxSizeGB {
+		return nil, field.Invalid(
+			field.NewPath("Spec", "OSDiskSizeGB"),
+			*m.Spec.OSDiskSizeGB,
+			fmt.Sprintf("ephemeral OS disks on VM size %s can be at most %d GB, set it to 0 to use the largest supported size or use a Managed OS disk", m.Spec.SKU, maxSizeGB))
+	}
+	return nil, nil
+}
+
+// validateKubeletDiskType validates that the VM size has a temp disk when the kubelet data is placed on it, which AKS
+// would otherwise only report after trying to create the agent pool. If the temp disk size can't be looked up, the
+// AzureManagedMachinePool is admitted with a warning.
+func (mw *azureManagedMachinePoolWebhook) validateKubeletDiskType(ctx context.Context, m *AzureManagedMachinePool) (admission.Warnings, error) {
+	if mw.diskSizeGetter == nil || ptr.Deref(m.Spec.KubeletDiskType, "") != KubeletDiskTypeTemporary {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, localDiskSizeLookupTimeout)
+	defer cancel()
+	tempDiskSizeGB, err := mw.diskSizeGetter.GetTempDiskSizeGB(ctx, m)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("skipped validating the temp disk of VM size %s: %v", m.Spec.SKU, err)}, nil
+	}
+
+	if tempDiskSizeGB == 0 {
+		return nil, field.Invalid(
+			field.NewPath("Spec", "KubeletDiskType"),
+			*m.Spec.KubeletDiskType,
+			fmt.Sprintf("VM size %s has no temp disk, use a VM size with a temp disk or the %s kubelet disk type", m.Spec.SKU, KubeletDiskTypeOS))
+	}
+	return nil, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -179,6 +596,13 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "OSSKU"),
+		old.Spec.OSSKU,
+		m.Spec.OSSKU); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "SKU"),
 		old.Spec.SKU,
@@ -200,6 +624,13 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "PodSubnetName"),
+		old.Spec.PodSubnetName,
+		m.Spec.PodSubnetName); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "EnableFIPS"),
 		old.Spec.EnableFIPS,
@@ -252,6 +683,8 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateSpotMaxPrice(m.Spec.ScaleSetPriority, m.Spec.SpotMaxPrice, field.NewPath("Spec", "SpotMaxPrice"))...)
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "EnableUltraSSD"),
 		old.Spec.EnableUltraSSD,
@@ -270,11 +703,36 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		m.Spec.NodePublicIPPrefixID); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "ProximityPlacementGroupID"),
+		old.Spec.ProximityPlacementGroupID,
+		m.Spec.ProximityPlacementGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "CapacityReservationGroupID"),
+		old.Spec.CapacityReservationGroupID,
+		m.Spec.CapacityReservationGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "NodePoolSnapshotID"),
+		old.Spec.NodePoolSnapshotID,
+		m.Spec.NodePoolSnapshotID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "NetworkProfile", "NodePublicIPTags"),
+		nodePublicIPTags(old.Spec.NetworkProfile),
+		nodePublicIPTags(m.Spec.NetworkProfile)); err != nil {
+		allErrs = append(allErrs, err)
+	}
 
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "KubeletConfig"),
 		old.Spec.KubeletConfig,
 		m.Spec.KubeletConfig); err != nil {
+		err.Detail = fmt.Sprintf("%s: %s", err.Detail, agentPoolCreationOnlyDetail)
 		allErrs = append(allErrs, err)
 	}
 
@@ -289,14 +747,18 @@ func (mw *azureManagedMachinePoolWebhook) ValidateUpdate(ctx context.Context, ol
 		field.NewPath("Spec", "LinuxOSConfig"),
 		old.Spec.LinuxOSConfig,
 		m.Spec.LinuxOSConfig); err != nil {
+		err.Detail = fmt.Sprintf("%s: %s", err.Detail, agentPoolCreationOnlyDetail)
 		allErrs = append(allErrs, err)
 	}
 
+	warnings, errs := mw.validateUpdatedSubnetCapacity(ctx, old, m)
+	allErrs = append(allErrs, errs...)
+
 	if len(allErrs) != 0 {
-		return nil, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedMachinePoolKind).GroupKind(), m.Name, allErrs)
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind(AzureManagedMachinePoolKind).GroupKind(), m.Name, allErrs)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -384,6 +846,23 @@ func validateOSType(mode string, osType *string, fldPath *field.Path) error {
 	return nil
 }
 
+// validateOSSKU validates that the OSSKU is a node image of the OSType.
+func validateOSSKU(osType *string, osSKU *string, fldPath *field.Path) error {
+	if osSKU == nil {
+		return nil
+	}
+	if ptr.Deref(osType, DefaultOSType) == WindowsOS {
+		if *osSKU != OSSKUWindows2019 && *osSKU != OSSKUWindows2022 {
+			return field.NotSupported(fldPath, *osSKU, []string{OSSKUWindows2019, OSSKUWindows2022})
+		}
+		return nil
+	}
+	if *osSKU != OSSKUAzureLinux && *osSKU != OSSKUCBLMariner && *osSKU != OSSKUUbuntu {
+		return field.NotSupported(fldPath, *osSKU, []string{OSSKUAzureLinux, OSSKUCBLMariner, OSSKUUbuntu})
+	}
+	return nil
+}
+
 func validateMPName(mpName string, specName *string, osType *string, fldPath *field.Path) error {
 	var name *string
 	var fieldNameMessage string
@@ -464,6 +943,18 @@ func validateNodePublicIPPrefixID(nodePublicIPPrefixID *string, fldPath *field.P
 	return nil
 }
 
+// validateResourceID validates that the resource ID of an optional field matches the ARM ID of the expected resource
+// type.
+func validateResourceID(resourceID *string, validResourceID *regexp.Regexp, fldPath *field.Path) error {
+	if resourceID != nil && !validResourceID.MatchString(*resourceID) {
+		return field.Invalid(
+			fldPath,
+			*resourceID,
+			fmt.Sprintf("resource ID must match %q", validResourceID.String()))
+	}
+	return nil
+}
+
 func validateEnableNodePublicIP(enableNodePublicIP *bool, nodePublicIPPrefixID *string, fldPath *field.Path) error {
 	if (enableNodePublicIP == nil || !*enableNodePublicIP) &&
 		nodePublicIPPrefixID != nil {
@@ -475,6 +966,35 @@ func validateEnableNodePublicIP(enableNodePublicIP *bool, nodePublicIPPrefixID *
 	return nil
 }
 
+func validateNodePublicIPTags(enableNodePublicIP *bool, networkProfile *AgentPoolNetworkProfile, fldPath *field.Path) error {
+	tags := nodePublicIPTags(networkProfile)
+	if len(tags) == 0 {
+		return nil
+	}
+	if !ptr.Deref(enableNodePublicIP, false) {
+		return field.Forbidden(
+			fldPath,
+			"can be set only when EnableNodePublicIP is set to true")
+	}
+	for tagType, tag := range tags {
+		if tagType == "" || tag == "" {
+			return field.Invalid(
+				fldPath,
+				tags,
+				"IP tag types and values must not be empty")
+		}
+	}
+	return nil
+}
+
+// nodePublicIPTags returns the node public IP tags of the network profile, or nil if there are none.
+func nodePublicIPTags(networkProfile *AgentPoolNetworkProfile) map[string]string {
+	if networkProfile == nil || len(networkProfile.NodePublicIPTags) == 0 {
+		return nil
+	}
+	return networkProfile.NodePublicIPTags
+}
+
 func validateMPSubnetName(subnetName *string, fldPath *field.Path) error {
 	if subnetName != nil {
 		subnetRegex := "^[a-zA-Z0-9][a-zA-Z0-9._-]{0,78}[a-zA-Z0-9]$"
@@ -487,6 +1007,39 @@ func validateMPSubnetName(subnetName *string, fldPath *field.Path) error {
 	return nil
 }
 
+// validatePodSubnetName validates that a pod subnet is either the name of a subnet or the resource ID of a subnet.
+func validatePodSubnetName(podSubnetName *string, fldPath *field.Path) error {
+	if podSubnetName == nil || !IsSubnetID(*podSubnetName) {
+		return validateMPSubnetName(podSubnetName, fldPath)
+	}
+	return validateResourceID(podSubnetName, validSubnetID, fldPath)
+}
+
+// IsSubnetID returns true if the subnet reference of a node pool is the resource ID of a subnet rather than the name
+// of a subnet of the virtual network of the cluster.
+func IsSubnetID(subnet string) bool {
+	return strings.HasPrefix(subnet, "/")
+}
+
+// validateSpotMaxPrice validates that the spot max price is only set for spot node pools, and is either -1 or greater
+// than zero.
+func validateSpotMaxPrice(scaleSetPriority *string, spotMaxPrice *resource.Quantity, fldPath *field.Path) field.ErrorList {
+	if spotMaxPrice == nil {
+		return nil
+	}
+	if ptr.Deref(scaleSetPriority, "") != ScaleSetPrioritySpot {
+		return field.ErrorList{field.Forbidden(fldPath, "can only be set when ScaleSetPriority is Spot")}
+	}
+	if spotMaxPrice.Sign() <= 0 && spotMaxPrice.Cmp(resource.MustParse("-1")) != 0 {
+		return field.ErrorList{field.Invalid(fldPath, spotMaxPrice.String(), "must be -1 or greater than zero")}
+	}
+	return nil
+}
+
+// agentPoolCreationOnlyDetail explains why a node configuration which AKS only applies when creating the agent pool
+// can't be updated.
+const agentPoolCreationOnlyDetail = "AKS only applies it when the node pool is created, create a new node pool to change it"
+
 // validateKubeletConfig enforces the AKS API configuration for KubeletConfig.
 // See:  https://learn.microsoft.com/en-us/azure/aks/custom-node-configuration.
 func validateKubeletConfig(kubeletConfig *KubeletConfig, fldPath *field.Path) error {
@@ -18,10 +18,14 @@ package v1beta1
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -32,6 +36,7 @@ import (
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
@@ -54,7 +59,8 @@ func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
 	mw := &azureManagedMachinePoolWebhook{
 		Client: client,
 	}
-	err := mw.Default(context.Background(), ammp)
+	createCtx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}})
+	err := mw.Default(createCtx, ammp)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(ammp.Labels).NotTo(BeNil())
 	val, ok := ammp.Labels[LabelAgentPoolMode]
@@ -62,6 +68,7 @@ func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
 	g.Expect(val).To(Equal("System"))
 	g.Expect(*ammp.Spec.Name).To(Equal("fooname"))
 	g.Expect(*ammp.Spec.OSType).To(Equal(LinuxOS))
+	g.Expect(*ammp.Spec.ScaleDownMode).To(Equal(ScaleDownModeDelete))
 
 	t.Logf("Testing ammp defaulting webhook with empty string name specified in Spec")
 	emptyName := ""
@@ -83,16 +90,30 @@ func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
 	err = mw.Default(context.Background(), ammp)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(*ammp.Spec.OsDiskType).To(Equal("Ephemeral"))
+
+	t.Logf("Testing ammp defaulting webhook with Deallocate ScaleDownMode specified in Spec")
+	ammp.Spec.ScaleDownMode = ptr.To(ScaleDownModeDeallocate)
+	err = mw.Default(context.Background(), ammp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*ammp.Spec.ScaleDownMode).To(Equal(ScaleDownModeDeallocate))
+
+	t.Logf("Testing ammp defaulting webhook doesn't default ScaleDownMode on update")
+	ammp.Spec.ScaleDownMode = nil
+	updateCtx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update}})
+	err = mw.Default(updateCtx, ammp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ammp.Spec.ScaleDownMode).To(BeNil())
 }
 
 func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 	t.Logf("Testing ammp updating webhook with mode system")
 
 	tests := []struct {
-		name    string
-		new     *AzureManagedMachinePool
-		old     *AzureManagedMachinePool
-		wantErr bool
+		name       string
+		new        *AzureManagedMachinePool
+		old        *AzureManagedMachinePool
+		wantErr    bool
+		wantErrMsg string
 	}{
 		{
 			name: "Cannot change Name of the agentpool",
@@ -444,7 +465,200 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: "Spec.EnableNodePublicIP",
+		},
+		{
+			name: "NodePublicIPPrefixID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP:   ptr.To(true),
+						NodePublicIPPrefixID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/public-ip-test/providers/Microsoft.Network/publicipprefixes/public-ip-prefix-2"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP:   ptr.To(true),
+						NodePublicIPPrefixID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/public-ip-test/providers/Microsoft.Network/publicipprefixes/public-ip-prefix"),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.NodePublicIPPrefixID: Invalid value",
+		},
+		{
+			name: "NodePublicIPPrefixID can't be set on an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP:   ptr.To(true),
+						NodePublicIPPrefixID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/public-ip-test/providers/Microsoft.Network/publicipprefixes/public-ip-prefix"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(true),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.NodePublicIPPrefixID: Invalid value",
+		},
+		{
+			name: "NodePublicIPPrefixID is unchanged",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP:   ptr.To(true),
+						NodePublicIPPrefixID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/public-ip-test/providers/Microsoft.Network/publicipprefixes/public-ip-prefix"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP:   ptr.To(true),
+						NodePublicIPPrefixID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/public-ip-test/providers/Microsoft.Network/publicipprefixes/public-ip-prefix"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "NodePoolSnapshotID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NodePoolSnapshotID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snapshot-2"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NodePoolSnapshotID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snapshot"),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.NodePoolSnapshotID: Invalid value",
+		},
+		{
+			name: "NodePoolSnapshotID can't be set on an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NodePoolSnapshotID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snapshot"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.NodePoolSnapshotID: Invalid value",
+		},
+		{
+			name: "PodSubnetName is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						PodSubnetName: ptr.To("pod-subnet-2"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						PodSubnetName: ptr.To("pod-subnet"),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.PodSubnetName: Invalid value",
+		},
+		{
+			name: "ProximityPlacementGroupID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg-2"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg"),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.ProximityPlacementGroupID: Invalid value",
+		},
+		{
+			name: "ProximityPlacementGroupID can't be set on an existing agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.ProximityPlacementGroupID: Invalid value",
+		},
+		{
+			name: "CapacityReservationGroupID is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg-2"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg"),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.CapacityReservationGroupID: Invalid value",
+		},
+		{
+			name: "OSSKU is immutable",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						OSType: ptr.To(WindowsOS),
+						OSSKU:  ptr.To(OSSKUWindows2022),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						OSType: ptr.To(WindowsOS),
+						OSSKU:  ptr.To(OSSKUWindows2019),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "Spec.OSSKU: Invalid value",
 		},
 		{
 			name: "NodeTaints are mutable",
@@ -499,6 +713,24 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Can update ScaleDownMode",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleDownMode: ptr.To(ScaleDownModeDeallocate),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleDownMode: ptr.To(ScaleDownModeDelete),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Can't update kubeletconfig",
 			new: &AzureManagedMachinePool{
@@ -519,7 +751,8 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: "create a new node pool to change it",
 		},
 		{
 			name: "Can't update LinuxOSConfig",
@@ -541,7 +774,8 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr:    true,
+			wantErrMsg: "create a new node pool to change it",
 		},
 		{
 			name: "Can't update SubnetName with error",
@@ -616,123 +850,236 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Cannot update enableEncryptionAtHost",
+			name: "Can update SpotMaxPrice",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						EnableEncryptionAtHost: ptr.To(true),
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.5")),
 					},
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						EnableEncryptionAtHost: ptr.To(false),
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.2")),
 					},
 				},
 			},
-			wantErr: true,
-		},
-	}
-	var client client.Client
-	for _, tc := range tests {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			g := NewWithT(t)
-			mw := &azureManagedMachinePoolWebhook{
-				Client: client,
-			}
-			_, err := mw.ValidateUpdate(context.Background(), tc.old, tc.new)
-			if tc.wantErr {
-				g.Expect(err).To(HaveOccurred())
-			} else {
-				g.Expect(err).NotTo(HaveOccurred())
-			}
-		})
-	}
-}
-
-func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
-	// NOTE: AzureManagedMachinePool is behind AKS feature gate flag; the webhook
-	// must prevent creating new objects in case the feature flag is disabled.
-	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
-	tests := []struct {
-		name     string
-		ammp     *AzureManagedMachinePool
-		wantErr  bool
-		errorLen int
-	}{
-		{
-			name:    "valid",
-			ammp:    getKnownValidAzureManagedMachinePool(),
 			wantErr: false,
 		},
 		{
-			name: "another valid permutation",
-			ammp: &AzureManagedMachinePool{
+			name: "Can unset SpotMaxPrice",
+			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						MaxPods:    ptr.To(249),
-						OsDiskType: ptr.To(string(asocontainerservicev1.OSDiskType_Managed)),
+						ScaleSetPriority: ptr.To("Spot"),
 					},
 				},
 			},
-			wantErr: false,
-		},
-		{
-			name: "valid - optional configuration not present",
-			ammp: &AzureManagedMachinePool{
-				Spec: AzureManagedMachinePoolSpec{},
-			},
-			wantErr: false,
-		},
-		{
-			name: "too many MaxPods",
-			ammp: &AzureManagedMachinePool{
+			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						MaxPods: ptr.To(251),
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.2")),
 					},
 				},
 			},
-			wantErr:  true,
-			errorLen: 1,
+			wantErr: false,
 		},
 		{
-			name: "invalid subnetname",
-			ammp: &AzureManagedMachinePool{
+			name: "Cannot update SpotMaxPrice to an invalid value",
+			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						SubnetName: ptr.To("1+subnet"),
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0")),
 					},
 				},
 			},
-			wantErr:  true,
-			errorLen: 1,
-		},
-		{
-			name: "invalid subnetname",
-			ammp: &AzureManagedMachinePool{
+			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						SubnetName: ptr.To("1"),
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.2")),
 					},
 				},
 			},
-			wantErr:  true,
-			errorLen: 1,
+			wantErr: true,
 		},
 		{
-			name: "invalid subnetname",
-			ammp: &AzureManagedMachinePool{
+			name: "Cannot update enableEncryptionAtHost",
+			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
-						SubnetName: ptr.To("-a_b-c"),
+						EnableEncryptionAtHost: ptr.To(true),
 					},
 				},
 			},
-			wantErr:  true,
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableEncryptionAtHost: ptr.To(false),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot update kubeletDiskType",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						KubeletDiskType: ptr.To(KubeletDiskTypeTemporary),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						KubeletDiskType: ptr.To(KubeletDiskTypeOS),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot update nodePublicIPTags",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(true),
+						NetworkProfile: &AgentPoolNetworkProfile{
+							NodePublicIPTags: map[string]string{"RoutingPreference": "Internet"},
+						},
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(true),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can set an empty network profile",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NetworkProfile: &AgentPoolNetworkProfile{},
+					},
+				},
+			},
+			old:     &AzureManagedMachinePool{},
+			wantErr: false,
+		},
+	}
+	var client client.Client
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			mw := &azureManagedMachinePoolWebhook{
+				Client: client,
+			}
+			_, err := mw.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				if tc.wantErrMsg != "" {
+					g.Expect(err.Error()).To(ContainSubstring(tc.wantErrMsg))
+				}
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
+	// NOTE: AzureManagedMachinePool is behind AKS feature gate flag; the webhook
+	// must prevent creating new objects in case the feature flag is disabled.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	tests := []struct {
+		name     string
+		ammp     *AzureManagedMachinePool
+		wantErr  bool
+		errorLen int
+	}{
+		{
+			name:    "valid",
+			ammp:    getKnownValidAzureManagedMachinePool(),
+			wantErr: false,
+		},
+		{
+			name: "another valid permutation",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						MaxPods:    ptr.To(249),
+						OsDiskType: ptr.To(string(asocontainerservicev1.OSDiskType_Managed)),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid - optional configuration not present",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "too many MaxPods",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						MaxPods: ptr.To(251),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "invalid subnetname",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						SubnetName: ptr.To("1+subnet"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "invalid subnetname",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						SubnetName: ptr.To("1"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "invalid subnetname",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						SubnetName: ptr.To("-a_b-c"),
+					},
+				},
+			},
+			wantErr:  true,
 			errorLen: 1,
 		},
 		{
@@ -998,6 +1345,196 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pool with node pool snapshot ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NodePoolSnapshotID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snapshot"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with invalid node pool snapshot",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						NodePoolSnapshotID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/cluster"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with pod subnet resource ID ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						PodSubnetName: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with invalid pod subnet resource ID",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						PodSubnetName: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with invalid pod subnet name",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						PodSubnetName: ptr.To("pod_subnet-"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with proximity placement group and capacity reservation group ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ProximityPlacementGroupID:  ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg"),
+						CapacityReservationGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pool with invalid proximity placement group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ProximityPlacementGroupID: ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with invalid capacity reservation group",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						CapacityReservationGroupID: ptr.To("crg"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "Windows pool with a Windows OSSKU",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						Name:   ptr.To("win22"),
+						OSType: ptr.To(WindowsOS),
+						OSSKU:  ptr.To(OSSKUWindows2022),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Linux pool with a Linux OSSKU",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						OSType: ptr.To(LinuxOS),
+						OSSKU:  ptr.To(OSSKUAzureLinux),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Linux pool with a Windows OSSKU",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						OSType: ptr.To(LinuxOS),
+						OSSKU:  ptr.To(OSSKUWindows2019),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "Windows pool with a Linux OSSKU",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						Name:   ptr.To("win22"),
+						OSType: ptr.To(WindowsOS),
+						OSSKU:  ptr.To(OSSKUUbuntu),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with node public IP tags cannot disable node public IP",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(false),
+						NetworkProfile: &AgentPoolNetworkProfile{
+							NodePublicIPTags: map[string]string{"RoutingPreference": "Internet"},
+						},
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with empty node public IP tag",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(true),
+						NetworkProfile: &AgentPoolNetworkProfile{
+							NodePublicIPTags: map[string]string{"RoutingPreference": ""},
+						},
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "pool with node public IP tags with node public IP enabled ok",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						EnableNodePublicIP: ptr.To(true),
+						NetworkProfile: &AgentPoolNetworkProfile{
+							NodePublicIPTags: map[string]string{"RoutingPreference": "Internet"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "pool without public ip prefix with node public IP unset ok",
 			ammp: &AzureManagedMachinePool{
@@ -1288,6 +1825,56 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			wantErr:  true,
 			errorLen: 1,
 		},
+		{
+			name: "valid SpotMaxPrice",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.25")),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "SpotMaxPrice of -1",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("-1")),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative SpotMaxPrice",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleSetPriority: ptr.To("Spot"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("-0.5")),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "SpotMaxPrice without Spot priority",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					AzureManagedMachinePoolClassSpec: AzureManagedMachinePoolClassSpec{
+						ScaleSetPriority: ptr.To("Regular"),
+						SpotMaxPrice:     ptr.To(resource.MustParse("0.25")),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
 	}
 
 	var client client.Client
@@ -1308,6 +1895,588 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 	}
 }
 
+type fakeLocalDiskSizeGetter struct {
+	maxSizeGB      int
+	tempDiskSizeGB int
+	err            error
+}
+
+func (f fakeLocalDiskSizeGetter) GetMaxEphemeralOSDiskSizeGB(_ context.Context, _ *AzureManagedMachinePool) (int, error) {
+	return f.maxSizeGB, f.err
+}
+
+func (f fakeLocalDiskSizeGetter) GetTempDiskSizeGB(_ context.Context, _ *AzureManagedMachinePool) (int, error) {
+	return f.tempDiskSizeGB, f.err
+}
+
+func TestAzureManagedMachinePool_ValidateCreateEphemeralOSDiskSize(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	withOSDisk := func(osDiskType string, osDiskSizeGB int) *AzureManagedMachinePool {
+		ammp := getKnownValidAzureManagedMachinePool()
+		ammp.Spec.SKU = "Standard_D4s_v3"
+		ammp.Spec.OsDiskType = ptr.To(osDiskType)
+		ammp.Spec.OSDiskSizeGB = ptr.To(osDiskSizeGB)
+		return ammp
+	}
+	tests := []struct {
+		name           string
+		ammp           *AzureManagedMachinePool
+		diskSizeGetter LocalDiskSizeGetter
+		wantErr        bool
+		wantWarnings   bool
+	}{
+		{
+			name:           "ephemeral OS disk that fits in the cache disk",
+			ammp:           withOSDisk(OsDiskTypeEphemeral, 100),
+			diskSizeGetter: fakeLocalDiskSizeGetter{maxSizeGB: 100},
+		},
+		{
+			name:           "ephemeral OS disk larger than the cache disk",
+			ammp:           withOSDisk(OsDiskTypeEphemeral, 128),
+			diskSizeGetter: fakeLocalDiskSizeGetter{maxSizeGB: 100},
+			wantErr:        true,
+		},
+		{
+			name:           "ephemeral OS disk sized by AKS",
+			ammp:           withOSDisk(OsDiskTypeEphemeral, 0),
+			diskSizeGetter: fakeLocalDiskSizeGetter{err: errors.New("should not be called")},
+		},
+		{
+			name:           "managed OS disk is not limited by the cache disk",
+			ammp:           withOSDisk(OsDiskTypeManaged, 512),
+			diskSizeGetter: fakeLocalDiskSizeGetter{maxSizeGB: 100},
+		},
+		{
+			name:           "disk size lookup failure skips the check",
+			ammp:           withOSDisk(OsDiskTypeEphemeral, 512),
+			diskSizeGetter: fakeLocalDiskSizeGetter{err: errors.New("network unreachable")},
+			wantWarnings:   true,
+		},
+		{
+			name: "no disk size getter skips the check",
+			ammp: withOSDisk(OsDiskTypeEphemeral, 512),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mw := &azureManagedMachinePoolWebhook{diskSizeGetter: tc.diskSizeGetter}
+			warnings, err := mw.ValidateCreate(context.Background(), tc.ammp)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateCreateEncryptionAtHost(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	tests := []struct {
+		name                   string
+		enableEncryptionAtHost *bool
+		wantWarnings           bool
+	}{
+		{
+			name:                   "encryption at host is not set",
+			enableEncryptionAtHost: nil,
+		},
+		{
+			name:                   "encryption at host is disabled",
+			enableEncryptionAtHost: ptr.To(false),
+		},
+		{
+			name:                   "encryption at host is enabled",
+			enableEncryptionAtHost: ptr.To(true),
+			wantWarnings:           true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ammp := getKnownValidAzureManagedMachinePool()
+			ammp.Spec.EnableEncryptionAtHost = tc.enableEncryptionAtHost
+			mw := &azureManagedMachinePoolWebhook{}
+			warnings, err := mw.ValidateCreate(context.Background(), ammp)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.wantWarnings {
+				g.Expect(warnings).To(ConsistOf(ContainSubstring("Microsoft.Compute/EncryptionAtHost")))
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateCreateKubeletDiskType(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	withKubeletDiskType := func(kubeletDiskType *KubeletDiskType) *AzureManagedMachinePool {
+		ammp := getKnownValidAzureManagedMachinePool()
+		ammp.Spec.SKU = "Standard_D4s_v5"
+		ammp.Spec.KubeletDiskType = kubeletDiskType
+		return ammp
+	}
+	tests := []struct {
+		name           string
+		ammp           *AzureManagedMachinePool
+		diskSizeGetter LocalDiskSizeGetter
+		wantErr        string
+		wantWarnings   bool
+	}{
+		{
+			name:           "temporary kubelet disk on a VM size with a temp disk",
+			ammp:           withKubeletDiskType(ptr.To(KubeletDiskTypeTemporary)),
+			diskSizeGetter: fakeLocalDiskSizeGetter{tempDiskSizeGB: 150},
+		},
+		{
+			name:           "temporary kubelet disk on a VM size without a temp disk",
+			ammp:           withKubeletDiskType(ptr.To(KubeletDiskTypeTemporary)),
+			diskSizeGetter: fakeLocalDiskSizeGetter{tempDiskSizeGB: 0},
+			wantErr:        "Spec.KubeletDiskType: Invalid value: \"Temporary\": VM size Standard_D4s_v5 has no temp disk, use a VM size with a temp disk or the OS kubelet disk type",
+		},
+		{
+			name:           "OS kubelet disk is not checked",
+			ammp:           withKubeletDiskType(ptr.To(KubeletDiskTypeOS)),
+			diskSizeGetter: fakeLocalDiskSizeGetter{err: errors.New("should not be called")},
+		},
+		{
+			name:           "default kubelet disk is not checked",
+			ammp:           withKubeletDiskType(nil),
+			diskSizeGetter: fakeLocalDiskSizeGetter{err: errors.New("should not be called")},
+		},
+		{
+			name:           "temp disk lookup failure skips the check",
+			ammp:           withKubeletDiskType(ptr.To(KubeletDiskTypeTemporary)),
+			diskSizeGetter: fakeLocalDiskSizeGetter{err: errors.New("network unreachable")},
+			wantWarnings:   true,
+		},
+		{
+			name: "no disk size getter skips the check",
+			ammp: withKubeletDiskType(ptr.To(KubeletDiskTypeTemporary)),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mw := &azureManagedMachinePoolWebhook{diskSizeGetter: tc.diskSizeGetter}
+			warnings, err := mw.ValidateCreate(context.Background(), tc.ammp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateCreateControlPlaneCompatibility(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, capifeature.MachinePool, true)()
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: GroupVersion.String(),
+				Kind:       AzureManagedControlPlaneKind,
+				Name:       "test-control-plane",
+			},
+		},
+	}
+	controlPlane := func(changes ...func(*AzureManagedControlPlane)) *AzureManagedControlPlane {
+		amcp := &AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-control-plane", Namespace: metav1.NamespaceDefault},
+			Spec: AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+					NetworkPlugin: ptr.To(AzureNetworkPluginName),
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						ManagedControlPlaneVirtualNetworkClassSpec: ManagedControlPlaneVirtualNetworkClassSpec{
+							Subnet: ManagedControlPlaneSubnet{Name: "test-subnet", CIDRBlock: "10.240.0.0/16"},
+						},
+					},
+				},
+			},
+		}
+		for _, change := range changes {
+			change(amcp)
+		}
+		return amcp
+	}
+	systemPool := func(zones ...string) *AzureManagedMachinePool {
+		ammp := getManagedMachinePoolWithSystemMode()
+		ammp.Name = "pool0"
+		ammp.Spec.AvailabilityZones = zones
+		return ammp
+	}
+	userPool := func(changes ...func(*AzureManagedMachinePool)) *AzureManagedMachinePool {
+		ammp := getKnownValidAzureManagedMachinePool()
+		ammp.Name = "pool1"
+		ammp.Namespace = metav1.NamespaceDefault
+		ammp.Labels = map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+		ammp.Spec.Mode = string(NodePoolModeUser)
+		for _, change := range changes {
+			change(ammp)
+		}
+		return ammp
+	}
+	tests := []struct {
+		name                  string
+		ammp                  *AzureManagedMachinePool
+		objects               []client.Object
+		enforceSubnetCapacity bool
+		wantErr               string
+		wantWarnings          bool
+	}{
+		{
+			name:    "compatible node pool",
+			ammp:    userPool(),
+			objects: []client.Object{cluster, controlPlane(), systemPool("1")},
+		},
+		{
+			name: "ultra SSD on a zonal node pool",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.EnableUltraSSD = ptr.To(true)
+				ammp.Spec.AvailabilityZones = []string{"1"}
+			}),
+			objects: []client.Object{cluster, controlPlane(), systemPool("1")},
+		},
+		{
+			name: "ultra SSD on a regional node pool of a zonal cluster",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.EnableUltraSSD = ptr.To(true)
+			}),
+			objects: []client.Object{cluster, controlPlane(), systemPool("1")},
+			wantErr: "Spec.AvailabilityZones: Required value: ultra SSD requires a zonal node pool in a region with availability zones, as used by system pool pool0 (zones 1), set availabilityZones or disable enableUltraSSD",
+		},
+		{
+			name: "ultra SSD on a regional node pool of a regional cluster",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.EnableUltraSSD = ptr.To(true)
+			}),
+			objects: []client.Object{cluster, controlPlane(), systemPool()},
+		},
+		{
+			name: "Windows node pool with kubenet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.OSType = ptr.To(WindowsOS)
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.NetworkPlugin = ptr.To("kubenet")
+			})},
+			wantErr: "Spec.OSType: Invalid value: \"Windows\": Windows node pools require the \"azure\" network plugin but AzureManagedControlPlane test-control-plane uses \"kubenet\", use a Linux node pool or a cluster with Azure CNI",
+		},
+		{
+			name: "Windows node pool with Azure CNI",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.OSType = ptr.To(WindowsOS)
+			}),
+			objects: []client.Object{cluster, controlPlane()},
+		},
+		{
+			name: "node pool pods don't fit in the subnet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+			wantWarnings: true,
+		},
+		{
+			name: "node pool pods don't fit in the subnet with enforced subnet capacity",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+			enforceSubnetCapacity: true,
+			wantErr:               "Spec.Scaling.MaxSize: Invalid value: 10: with Azure CNI pods get their IPs from subnet test-subnet (10.240.0.0/24) which has 251 usable addresses, but 10 node(s) with maxPods 30 need 10 * (30 + 1) = 310, use a larger subnet, lower the max count or maxPods, or use the \"overlay\" network plugin mode",
+		},
+		{
+			name: "pods of a node pool without autoscaling don't fit in the subnet with enforced subnet capacity",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.MaxPods = ptr.To(250)
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/25"
+			})},
+			enforceSubnetCapacity: true,
+			wantErr:               "Spec.MaxPods: Invalid value: 250: with Azure CNI pods get their IPs from subnet test-subnet (10.240.0.0/25) which has 123 usable addresses, but 1 node(s) with maxPods 250 need 1 * (250 + 1) = 251, use a larger subnet, lower the max count or maxPods, or use the \"overlay\" network plugin mode",
+		},
+		{
+			name: "node pool pods fit in the subnet with enforced subnet capacity",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(8)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+			enforceSubnetCapacity: true,
+		},
+		{
+			name: "node pool pods don't fit in an additional subnet with enforced subnet capacity",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.SubnetName = ptr.To("other-subnet")
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "other-subnet", CIDRBlock: "10.241.0.0/24"}}
+			})},
+			enforceSubnetCapacity: true,
+			wantErr:               "Spec.Scaling.MaxSize: Invalid value: 10: with Azure CNI pods get their IPs from subnet other-subnet (10.241.0.0/24) which has 251 usable addresses, but 10 node(s) with maxPods 30 need 10 * (30 + 1) = 310, use a larger subnet, lower the max count or maxPods, or use the \"overlay\" network plugin mode",
+		},
+		{
+			name: "node pool pods with kubenet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.NetworkPlugin = ptr.To("kubenet")
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+			enforceSubnetCapacity: true,
+		},
+		{
+			name: "node pool pods in another subnet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.SubnetName = ptr.To("other-subnet")
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+		},
+		{
+			name: "node pool pods with Azure CNI overlay",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.NetworkPluginMode = ptr.To(NetworkPluginModeOverlay)
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+			})},
+			enforceSubnetCapacity: true,
+		},
+		{
+			name: "node pool pods in a pod subnet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("pod-subnet")
+				ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(10)}
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "pod-subnet", CIDRBlock: "10.241.0.0/16"}}
+			})},
+		},
+		{
+			name: "node pool pods in an existing pod subnet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods")
+			}),
+			objects: []client.Object{cluster, controlPlane()},
+		},
+		{
+			name: "pod subnet missing from the control plane",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("other-subnet")
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "pod-subnet", CIDRBlock: "10.241.0.0/16"}}
+			})},
+			wantErr: "Spec.PodSubnetName: Invalid value: \"other-subnet\": subnet other-subnet is not one of the VirtualNetwork.Subnets of AzureManagedControlPlane test-control-plane, add it there or use the resource ID of an existing subnet",
+		},
+		{
+			name: "pod subnet is the node subnet",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("test-subnet")
+			}),
+			objects: []client.Object{cluster, controlPlane()},
+			wantErr: "Spec.PodSubnetName: Invalid value: \"test-subnet\": the pod subnet must not be the node subnet of the pool, use another subnet of VirtualNetwork.Subnets",
+		},
+		{
+			name: "pod subnet with Azure CNI overlay",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("pod-subnet")
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnet.CIDRBlock = "10.240.0.0/24"
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "pod-subnet", CIDRBlock: "10.241.0.0/16"}}
+			}, func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.NetworkPluginMode = ptr.To(NetworkPluginModeOverlay)
+			})},
+			wantErr: "Spec.PodSubnetName: Invalid value: \"pod-subnet\": pod subnets require the \"azure\" network plugin without overlay but AzureManagedControlPlane test-control-plane uses \"azure\", remove the pod subnet or use a cluster with Azure CNI dynamic IP allocation",
+		},
+		{
+			name: "node subnet delegated to Azure NetApp Files",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.SubnetName = ptr.To("anf-subnet")
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "anf-subnet", CIDRBlock: "10.241.0.0/26", Delegations: []string{NetAppVolumesDelegation}}}
+			})},
+			wantErr: "Spec.SubnetName: Invalid value: \"anf-subnet\": subnet anf-subnet of AzureManagedControlPlane test-control-plane is delegated to Microsoft.NetApp/volumes, use another subnet of VirtualNetwork.Subnets for the nodes",
+		},
+		{
+			name: "pod subnet delegated to Azure NetApp Files",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("anf-subnet")
+			}),
+			objects: []client.Object{cluster, controlPlane(func(amcp *AzureManagedControlPlane) {
+				amcp.Spec.VirtualNetwork.Subnets = []ManagedControlPlaneAdditionalSubnet{{Name: "anf-subnet", CIDRBlock: "10.241.0.0/26", Delegations: []string{NetAppVolumesDelegation}}}
+			})},
+			wantErr: "Spec.PodSubnetName: Invalid value: \"anf-subnet\": subnet anf-subnet of AzureManagedControlPlane test-control-plane is delegated to Microsoft.NetApp/volumes, use another subnet of VirtualNetwork.Subnets for the pods",
+		},
+		{
+			name: "node pool without a pod subnet in a cluster whose node pools use one",
+			ammp: userPool(),
+			objects: []client.Object{cluster, controlPlane(), func() *AzureManagedMachinePool {
+				pool := systemPool("1")
+				pool.Spec.PodSubnetName = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods")
+				return pool
+			}()},
+			wantErr: "Spec.PodSubnetName: Required value: node pool pool0 of the cluster uses a pod subnet, so every node pool of the cluster must use one",
+		},
+		{
+			name: "node pool with a pod subnet in a cluster whose node pools don't use one",
+			ammp: userPool(func(ammp *AzureManagedMachinePool) {
+				ammp.Spec.PodSubnetName = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods")
+			}),
+			objects: []client.Object{cluster, controlPlane(), systemPool("1")},
+			wantErr: "Spec.PodSubnetName: Invalid value: \"/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods\": node pool pool0 of the cluster doesn't use a pod subnet, so no node pool of the cluster can use one",
+		},
+		{
+			name:         "missing cluster skips the checks",
+			ammp:         userPool(),
+			wantWarnings: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+			mw := &azureManagedMachinePoolWebhook{Client: fakeClient, enforceSubnetCapacity: tc.enforceSubnetCapacity}
+			warnings, err := mw.ValidateCreate(context.Background(), tc.ammp)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateUpdateSubnetCapacity(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: GroupVersion.String(),
+				Kind:       AzureManagedControlPlaneKind,
+				Name:       "test-control-plane",
+			},
+		},
+	}
+	controlPlane := &AzureManagedControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-control-plane", Namespace: metav1.NamespaceDefault},
+		Spec: AzureManagedControlPlaneSpec{
+			AzureManagedControlPlaneClassSpec: AzureManagedControlPlaneClassSpec{
+				NetworkPlugin: ptr.To(AzureNetworkPluginName),
+				VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+					ManagedControlPlaneVirtualNetworkClassSpec: ManagedControlPlaneVirtualNetworkClassSpec{
+						Subnet: ManagedControlPlaneSubnet{Name: "test-subnet", CIDRBlock: "10.240.0.0/24"},
+					},
+				},
+			},
+		},
+	}
+	pool := func(maxSize int) *AzureManagedMachinePool {
+		ammp := getKnownValidAzureManagedMachinePool()
+		ammp.Name = "pool1"
+		ammp.Namespace = metav1.NamespaceDefault
+		ammp.Labels = map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+		ammp.Spec.Scaling = &ManagedMachinePoolScaling{MinSize: ptr.To(1), MaxSize: ptr.To(maxSize)}
+		return ammp
+	}
+	tests := []struct {
+		name                  string
+		oldAMMP               *AzureManagedMachinePool
+		ammp                  *AzureManagedMachinePool
+		enforceSubnetCapacity bool
+		wantErr               bool
+		wantWarnings          bool
+	}{
+		{
+			name:    "max count within the subnet capacity",
+			oldAMMP: pool(4),
+			ammp:    pool(8),
+		},
+		{
+			name:         "max count beyond the subnet capacity",
+			oldAMMP:      pool(4),
+			ammp:         pool(10),
+			wantWarnings: true,
+		},
+		{
+			name:                  "max count beyond the subnet capacity with enforced subnet capacity",
+			oldAMMP:               pool(4),
+			ammp:                  pool(10),
+			enforceSubnetCapacity: true,
+			wantErr:               true,
+		},
+		{
+			name:                  "unchanged max count beyond the subnet capacity",
+			oldAMMP:               pool(10),
+			ammp:                  pool(10),
+			enforceSubnetCapacity: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, controlPlane).Build()
+			mw := &azureManagedMachinePoolWebhook{Client: fakeClient, enforceSubnetCapacity: tc.enforceSubnetCapacity}
+			warnings, err := mw.ValidateUpdate(context.Background(), tc.oldAMMP, tc.ammp)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantWarnings {
+				g.Expect(warnings).NotTo(BeEmpty())
+			} else {
+				g.Expect(warnings).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func TestAzureManagedMachinePool_ValidateCreateFailure(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -96,6 +97,11 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateCreate(ctx context.Co
 		mp.Spec.Template.Spec.OSType,
 		field.NewPath("Spec", "Template", "Spec", "OSType")))
 
+	errs = append(errs, validateOSSKU(
+		mp.Spec.Template.Spec.OSType,
+		mp.Spec.Template.Spec.OSSKU,
+		field.NewPath("Spec", "Template", "Spec", "OSSKU")))
+
 	errs = append(errs, validateMPName(
 		mp.Name,
 		mp.Spec.Template.Spec.Name,
@@ -115,6 +121,30 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateCreate(ctx context.Co
 		mp.Spec.Template.Spec.NodePublicIPPrefixID,
 		field.NewPath("Spec", "Template", "Spec", "EnableNodePublicIP")))
 
+	errs = append(errs, validateResourceID(
+		mp.Spec.Template.Spec.ProximityPlacementGroupID,
+		validProximityPlacementGroupID,
+		field.NewPath("Spec", "Template", "Spec", "ProximityPlacementGroupID")))
+
+	errs = append(errs, validateResourceID(
+		mp.Spec.Template.Spec.CapacityReservationGroupID,
+		validCapacityReservationGroupID,
+		field.NewPath("Spec", "Template", "Spec", "CapacityReservationGroupID")))
+
+	errs = append(errs, validateResourceID(
+		mp.Spec.Template.Spec.NodePoolSnapshotID,
+		validNodePoolSnapshotID,
+		field.NewPath("Spec", "Template", "Spec", "NodePoolSnapshotID")))
+
+	errs = append(errs, validatePodSubnetName(
+		mp.Spec.Template.Spec.PodSubnetName,
+		field.NewPath("Spec", "Template", "Spec", "PodSubnetName")))
+
+	errs = append(errs, validateNodePublicIPTags(
+		mp.Spec.Template.Spec.EnableNodePublicIP,
+		mp.Spec.Template.Spec.NetworkProfile,
+		field.NewPath("Spec", "Template", "Spec", "NetworkProfile", "NodePublicIPTags")))
+
 	errs = append(errs, validateKubeletConfig(
 		mp.Spec.Template.Spec.KubeletConfig,
 		field.NewPath("Spec", "Template", "Spec", "KubeletConfig")))
@@ -124,6 +154,11 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateCreate(ctx context.Co
 		mp.Spec.Template.Spec.KubeletConfig,
 		field.NewPath("Spec", "Template", "Spec", "LinuxOSConfig")))
 
+	errs = append(errs, validateSpotMaxPrice(
+		mp.Spec.Template.Spec.ScaleSetPriority,
+		mp.Spec.Template.Spec.SpotMaxPrice,
+		field.NewPath("Spec", "Template", "Spec", "SpotMaxPrice")).ToAggregate())
+
 	return nil, kerrors.NewAggregate(errs)
 }
 
@@ -161,6 +196,13 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Co
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "OSSKU"),
+		old.Spec.Template.Spec.OSSKU,
+		mp.Spec.Template.Spec.OSSKU); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "Template", "Spec", "SKU"),
 		old.Spec.Template.Spec.SKU,
@@ -182,6 +224,13 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Co
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "PodSubnetName"),
+		old.Spec.Template.Spec.PodSubnetName,
+		mp.Spec.Template.Spec.PodSubnetName); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "Template", "Spec", "EnableFIPS"),
 		old.Spec.Template.Spec.EnableFIPS,
@@ -189,6 +238,13 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Co
 		allErrs = append(allErrs, err)
 	}
 
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "EnableEncryptionAtHost"),
+		old.Spec.Template.Spec.EnableEncryptionAtHost,
+		mp.Spec.Template.Spec.EnableEncryptionAtHost); err != nil && old.Spec.Template.Spec.EnableEncryptionAtHost != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if !webhookutils.EnsureStringSlicesAreEquivalent(mp.Spec.Template.Spec.AvailabilityZones, old.Spec.Template.Spec.AvailabilityZones) {
 		allErrs = append(allErrs,
 			field.Invalid(
@@ -245,11 +301,36 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Co
 		mp.Spec.Template.Spec.NodePublicIPPrefixID); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "ProximityPlacementGroupID"),
+		old.Spec.Template.Spec.ProximityPlacementGroupID,
+		mp.Spec.Template.Spec.ProximityPlacementGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "CapacityReservationGroupID"),
+		old.Spec.Template.Spec.CapacityReservationGroupID,
+		mp.Spec.Template.Spec.CapacityReservationGroupID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "NodePoolSnapshotID"),
+		old.Spec.Template.Spec.NodePoolSnapshotID,
+		mp.Spec.Template.Spec.NodePoolSnapshotID); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := webhookutils.ValidateImmutable(
+		field.NewPath("Spec", "Template", "Spec", "NetworkProfile", "NodePublicIPTags"),
+		nodePublicIPTags(old.Spec.Template.Spec.NetworkProfile),
+		nodePublicIPTags(mp.Spec.Template.Spec.NetworkProfile)); err != nil {
+		allErrs = append(allErrs, err)
+	}
 
 	if err := webhookutils.ValidateImmutable(
 		field.NewPath("Spec", "Template", "Spec", "KubeletConfig"),
 		old.Spec.Template.Spec.KubeletConfig,
 		mp.Spec.Template.Spec.KubeletConfig); err != nil {
+		err.Detail = fmt.Sprintf("%s: %s", err.Detail, agentPoolCreationOnlyDetail)
 		allErrs = append(allErrs, err)
 	}
 
@@ -264,6 +345,7 @@ func (mpw *azureManagedMachinePoolTemplateWebhook) ValidateUpdate(ctx context.Co
 		field.NewPath("Spec", "Template", "Spec", "LinuxOSConfig"),
 		old.Spec.Template.Spec.LinuxOSConfig,
 		mp.Spec.Template.Spec.LinuxOSConfig); err != nil {
+		err.Detail = fmt.Sprintf("%s: %s", err.Detail, agentPoolCreationOnlyDetail)
 		allErrs = append(allErrs, err)
 	}
 
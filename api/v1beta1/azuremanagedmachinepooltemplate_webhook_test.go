@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -136,6 +137,46 @@ func TestManagedMachinePoolTemplateUpdateWebhook(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			name: "azuremanagedmachinepooltemplate enableEncryptionAtHost is immutable",
+			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.EnableEncryptionAtHost = ptr.To(true)
+			}),
+			machinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.EnableEncryptionAtHost = ptr.To(false)
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremanagedmachinepooltemplate ProximityPlacementGroupID is immutable",
+			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.ProximityPlacementGroupID = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg")
+			}),
+			machinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.ProximityPlacementGroupID = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/ppg-test/providers/Microsoft.Compute/proximityPlacementGroups/ppg-2")
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremanagedmachinepooltemplate CapacityReservationGroupID is immutable",
+			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.CapacityReservationGroupID = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg")
+			}),
+			machinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.CapacityReservationGroupID = ptr.To("/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/crg-test/providers/Microsoft.Compute/capacityReservationGroups/crg-2")
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremanagedmachinepooltemplate OSSKU is immutable",
+			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.OSSKU = ptr.To(OSSKUUbuntu)
+			}),
+			machinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.OSSKU = ptr.To(OSSKUAzureLinux)
+			}),
+			wantErr: true,
+		},
 		{
 			name: "azuremanagedmachinepooltemplate MaxPods is immutable",
 			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
@@ -196,6 +237,22 @@ func TestManagedMachinePoolTemplateUpdateWebhook(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			name: "azuremanagedmachinepooltemplate nodePublicIPTags is immutable",
+			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.EnableNodePublicIP = ptr.To(true)
+				ammpt.Spec.Template.Spec.NetworkProfile = &AgentPoolNetworkProfile{
+					NodePublicIPTags: map[string]string{"RoutingPreference": "Internet"},
+				}
+			}),
+			machinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+				ammpt.Spec.Template.Spec.EnableNodePublicIP = ptr.To(true)
+				ammpt.Spec.Template.Spec.NetworkProfile = &AgentPoolNetworkProfile{
+					NodePublicIPTags: map[string]string{"RoutingPreference": "Microsoft"},
+				}
+			}),
+			wantErr: true,
+		},
 		{
 			name: "azuremanagedmachinepooltemplate kubeletConfig is immutable",
 			oldMachinePoolTemplate: getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
@@ -267,3 +324,19 @@ func getAzureManagedMachinePoolTemplate(changes ...func(*AzureManagedMachinePool
 
 	return input
 }
+
+func TestManagedMachinePoolTemplateKubeletDiskTypeRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+	ammpt := getAzureManagedMachinePoolTemplate(func(ammpt *AzureManagedMachinePoolTemplate) {
+		ammpt.Spec.Template.Spec.KubeletDiskType = ptr.To(KubeletDiskTypeTemporary)
+	})
+
+	// The topology controller creates the AzureManagedMachinePools of a ClusterClass from the template spec.
+	data, err := json.Marshal(ammpt.Spec.Template.Spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`"kubeletDiskType":"Temporary"`))
+
+	ammp := &AzureManagedMachinePool{}
+	g.Expect(json.Unmarshal(data, &ammp.Spec)).To(Succeed())
+	g.Expect(ammp.Spec.KubeletDiskType).To(Equal(ptr.To(KubeletDiskTypeTemporary)))
+}
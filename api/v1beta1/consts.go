@@ -24,12 +24,60 @@ const (
 	NetworkInfrastructureReadyCondition clusterv1.ConditionType = "NetworkInfrastructureReady"
 	// NamespaceNotAllowedByIdentity used to indicate cluster in a namespace not allowed by identity.
 	NamespaceNotAllowedByIdentity = "NamespaceNotAllowedByIdentity"
+	// PrimaryIdentityAuthenticatedCondition reports whether the AzureClusterIdentity referenced by the cluster
+	// authenticated. It is only set when the identity failed to authenticate and its fallback identity was used.
+	PrimaryIdentityAuthenticatedCondition clusterv1.ConditionType = "PrimaryIdentityAuthenticated"
+	// FallbackIdentityInUseReason used when the cluster authenticates with the fallback identity of its AzureClusterIdentity.
+	FallbackIdentityInUseReason = "FallbackIdentityInUse"
+	// WaitingForIPAddressAllocationReason used when the cluster is waiting for the IPAM provider to allocate the address
+	// space of a subnet.
+	WaitingForIPAddressAllocationReason = "WaitingForIPAddressAllocation"
+	// ASOCredentialSecretReadyCondition reports whether the ASO credential secret of an AzureCluster or
+	// AzureManagedControlPlane targets the subscription of the cluster.
+	ASOCredentialSecretReadyCondition clusterv1.ConditionType = "ASOCredentialSecretReady"
+	// ASOCredentialSubscriptionMismatchReason used when a user-provided ASO credential secret targets another
+	// subscription than the one of the cluster.
+	ASOCredentialSubscriptionMismatchReason = "ASOCredentialSubscriptionMismatch"
+	// RegionDegradedCondition reports that an active Azure Service Health incident impacts the region of an
+	// AzureCluster which repeatedly failed to reconcile. It is only set while the incident is active.
+	RegionDegradedCondition clusterv1.ConditionType = "RegionDegraded"
+	// ServiceIssueActiveReason used when an Azure Service Health service issue is active in the region of a cluster.
+	ServiceIssueActiveReason = "ServiceIssueActive"
+	// InternalAPIServerLBReadyCondition reports whether the internal load balancer replacing the public API server
+	// load balancer of an AzureCluster is created. It is only set while the API server load balancer is migrated.
+	InternalAPIServerLBReadyCondition clusterv1.ConditionType = "InternalAPIServerLBReady"
+	// ControlPlaneEndpointMigratedCondition reports whether the control plane endpoint of an AzureCluster was switched
+	// to the internal API server load balancer, and whether the machines created before the switch were replaced. It
+	// is only set while the API server load balancer is migrated.
+	ControlPlaneEndpointMigratedCondition clusterv1.ConditionType = "ControlPlaneEndpointMigrated"
+	// PublicAPIServerLBDeletedCondition reports whether the public API server load balancer of an AzureCluster and its
+	// public IPs are deleted. It is only set while the API server load balancer is migrated.
+	PublicAPIServerLBDeletedCondition clusterv1.ConditionType = "PublicAPIServerLBDeleted"
+	// WaitingForLoadBalancerTypeChangeReason used when the API server load balancer migration waits for its type to
+	// be changed to Internal.
+	WaitingForLoadBalancerTypeChangeReason = "WaitingForLoadBalancerTypeChange"
+	// InternalAPIServerLBCreatingReason used when the internal API server load balancer is being created.
+	InternalAPIServerLBCreatingReason = "InternalAPIServerLBCreating"
+	// InternalAPIServerLBUnavailableReason used when the control plane endpoint waits for Azure Resource Health to
+	// report the internal API server load balancer as available, i.e. for its backends to pass their health probes.
+	InternalAPIServerLBUnavailableReason = "InternalAPIServerLBUnavailable"
+	// ControlPlaneCertificatesRollingOutReason used when the control plane endpoint waits for the machines of the
+	// control plane to be rolled out with certificates valid for the host of the internal API server load balancer.
+	ControlPlaneCertificatesRollingOutReason = "ControlPlaneCertificatesRollingOut"
+	// WaitingForMachinesReplacementReason used when the public API server load balancer waits for the machines created
+	// before the control plane endpoint was switched to be replaced.
+	WaitingForMachinesReplacementReason = "WaitingForMachinesReplacement"
+	// PublicAPIServerLBDeletingReason used when the public API server load balancer and its public IPs are being
+	// deleted.
+	PublicAPIServerLBDeletingReason = "PublicAPIServerLBDeleting"
 )
 
 // AzureMachine Conditions and Reasons.
 const (
 	// VMRunningCondition reports on current status of the Azure VM.
 	VMRunningCondition clusterv1.ConditionType = "VMRunning"
+	// VMAgentReadyCondition reports on the status of the Azure VM agent of a running Azure VM.
+	VMAgentReadyCondition clusterv1.ConditionType = "VMAgentReady"
 	// VMIdentitiesReadyCondition reports on the readiness of the Azure VM identities.
 	VMIdentitiesReadyCondition clusterv1.ConditionType = "VMIdentitiesReady"
 	// VMCreatingReason used when the vm creation is in progress.
@@ -40,18 +88,52 @@ const (
 	VMDeletingReason = "VMDeleting"
 	// VMProvisionFailedReason used for failures during vm provisioning.
 	VMProvisionFailedReason = "VMProvisionFailed"
+	// VMDeallocatedReason used when the vm is deallocated as requested by its power state.
+	VMDeallocatedReason = "VMDeallocated"
+	// VMHibernatedReason used when the vm is hibernated as requested by its power state.
+	VMHibernatedReason = "VMHibernated"
+	// VMPowerStateChangingReason used when the vm is being started, deallocated or hibernated.
+	VMPowerStateChangingReason = "VMPowerStateChanging"
+	// VMStoppedOutOfBandReason used when the vm was stopped, deallocated or hibernated outside of CAPZ while CAPZ
+	// doesn't manage its power state.
+	VMStoppedOutOfBandReason = "VMStoppedOutOfBand"
+	// VMSpotEvictedReason used when a Spot vm with the Deallocate eviction policy was deallocated by an eviction.
+	VMSpotEvictedReason = "VMSpotEvicted"
+	// VMAgentNotReadyReason used when the Azure VM agent of a running vm reports it isn't ready.
+	VMAgentNotReadyReason = "VMAgentNotReady"
 	// UserAssignedIdentityMissingReason used for failures when a user-assigned identity is missing.
 	UserAssignedIdentityMissingReason = "UserAssignedIdentityMissing"
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// InvalidBootstrapDataReason used when the bootstrap data can't be used by the VMs, e.g. because it is too large or
+	// its format isn't supported by the OS image.
+	InvalidBootstrapDataReason = "InvalidBootstrapData"
+	// ImageReplicatedCondition reports whether the compute gallery image version of the VMs has been replicated to the
+	// location of the cluster. It is only set once the creation of VMs has been held because it wasn't.
+	ImageReplicatedCondition clusterv1.ConditionType = "ImageReplicated"
+	// ImageNotReplicatedReason used when the creation of VMs is held until the compute gallery image version is
+	// replicated to the location of the cluster.
+	ImageNotReplicatedReason = "ImageNotReplicated"
 	// BootstrapSucceededCondition reports the result of the execution of the bootstrap data on the machine.
 	BootstrapSucceededCondition clusterv1.ConditionType = "BootstrapSucceeded"
 	// BootstrapInProgressReason is used to indicate the bootstrap data has not finished executing.
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// BootstrapWithinTimeoutCondition reports whether the bootstrap data of a machine with a provisioning timeout
+	// succeeded, or its node registered, before the timeout expired.
+	BootstrapWithinTimeoutCondition clusterv1.ConditionType = "BootstrapWithinTimeout"
+	// BootstrapTimedOutReason used when a virtual machine existed longer than its provisioning timeout without its
+	// bootstrap data succeeding or its node registering.
+	BootstrapTimedOutReason = "BootstrapTimedOut"
+	// WaitingForPreTerminateHookCondition reports that the deletion of the Azure resources of an AzureMachine waits for
+	// the pre-terminate deletion hooks of its Machine to be removed.
+	WaitingForPreTerminateHookCondition clusterv1.ConditionType = "WaitingForPreTerminateHook"
+	// PreTerminateHookPendingReason used when a pre-terminate deletion hook is still set on the Machine of an
+	// AzureMachine being deleted.
+	PreTerminateHookPendingReason = "PreTerminateHookPending"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -78,6 +160,19 @@ const (
 	ScaleSetModelUpdatedCondition clusterv1.ConditionType = "ScaleSetModelUpdated"
 	// ScaleSetModelOutOfDateReason describes the machine pool model being out of date.
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
+
+	// EvictionOccurredCondition reports that the Spot instance of an AzureMachinePoolMachine was deallocated by an
+	// eviction. It is only set until the instance is running again.
+	EvictionOccurredCondition clusterv1.ConditionType = "EvictionOccurred"
+	// SpotInstanceEvictedReason used while a Spot instance deallocated by an eviction is being restarted.
+	SpotInstanceEvictedReason = "SpotInstanceEvicted"
+
+	// ZonesUnbalancedCondition reports that the numbers of instances of the zones of an AzureMachinePool differ by
+	// more than the maximum skew of its zone spread policy. It is only set until the zones are balanced again.
+	ZonesUnbalancedCondition clusterv1.ConditionType = "ZonesUnbalanced"
+	// ZoneSkewExceededReason used when the difference between the numbers of instances of two zones exceeds the
+	// maximum skew.
+	ZoneSkewExceededReason = "ZoneSkewExceeded"
 )
 
 // AzureManagedCluster Conditions and Reasons.
@@ -88,6 +183,14 @@ const (
 	AgentPoolsReadyCondition clusterv1.ConditionType = "AgentPoolsReady"
 	// AzureResourceAvailableCondition means the AKS cluster is healthy according to Azure's Resource Health API.
 	AzureResourceAvailableCondition clusterv1.ConditionType = "AzureResourceAvailable"
+	// NodeResourceGroupEmptyCondition reports whether the node resource group of a deleted AKS cluster protected by
+	// the ProtectNodeResourceGroupAnnotation annotation contains only resources created by AKS.
+	NodeResourceGroupEmptyCondition clusterv1.ConditionType = "NodeResourceGroupEmpty"
+	// NodeResourceGroupNotEmptyReason used when the node resource group contains resources that weren't created by AKS.
+	NodeResourceGroupNotEmptyReason = "NodeResourceGroupNotEmpty"
+	// WaitingForIdentityPrincipalReason used when the subnet permissions of an AKS cluster wait for AKS to create its
+	// system-assigned identity.
+	WaitingForIdentityPrincipalReason = "WaitingForIdentityPrincipal"
 )
 
 // Azure Services Conditions and Reasons.
@@ -118,6 +221,10 @@ const (
 	PrivateDNSRecordReadyCondition clusterv1.ConditionType = "PrivateDNSRecordReady"
 	// BastionHostReadyCondition means the bastion host exists and is ready to be used.
 	BastionHostReadyCondition clusterv1.ConditionType = "BastionHostReady"
+	// APIServerDNSRecordReadyCondition means the record of the API server in the Azure DNS zone exists and is ready to be used.
+	APIServerDNSRecordReadyCondition clusterv1.ConditionType = "APIServerDNSRecordReady"
+	// ResourceLocksReadyCondition means the management locks protecting the critical resources of the cluster exist.
+	ResourceLocksReadyCondition clusterv1.ConditionType = "ResourceLocksReady"
 	// InboundNATRulesReadyCondition means the inbound NAT rules exist and are ready to be used.
 	InboundNATRulesReadyCondition clusterv1.ConditionType = "InboundNATRulesReady"
 	// AvailabilitySetReadyCondition means the availability set exists and is ready to be used.
@@ -134,6 +241,10 @@ const (
 	FleetReadyCondition clusterv1.ConditionType = "FleetReady"
 	// AKSExtensionsReadyCondition means the AKS Extensions exist and are ready to be used.
 	AKSExtensionsReadyCondition clusterv1.ConditionType = "AKSExtensionsReady"
+	// MaintenanceConfigurationReadyCondition means the planned maintenance configuration of the AKS cluster is up to date.
+	MaintenanceConfigurationReadyCondition clusterv1.ConditionType = "MaintenanceConfigurationReady"
+	// SubnetRoleAssignmentReadyCondition means the role assignment granting the identity of the AKS cluster permissions on its subnet exists.
+	SubnetRoleAssignmentReadyCondition clusterv1.ConditionType = "SubnetRoleAssignmentReady"
 
 	// CreatingReason means the resource is being created.
 	CreatingReason = "Creating"
@@ -147,6 +258,17 @@ const (
 	DeletionFailedReason = "DeletionFailed"
 	// UpdatingReason means the resource is being updated.
 	UpdatingReason = "Updating"
+	// DNSLabelInUseReason means the DNS label of a public IP is already used by another public IP in the location.
+	DNSLabelInUseReason = "DNSLabelInUse"
+	// ResourceGroupConflictReason means ASO reported a conflict between the resource group of the cluster and a change
+	// made to it outside of CAPZ, e.g. the resource group was recreated in another location.
+	ResourceGroupConflictReason = "ResourceGroupConflict"
+	// ResourceLockAuthorizationFailedReason means the identity of the cluster isn't allowed to manage management locks,
+	// which requires the Owner or User Access Administrator role.
+	ResourceLockAuthorizationFailedReason = "ResourceLockAuthorizationFailed"
+	// PodDisruptionBudgetBlockingDrainReason means the deletion of an agent pool is taking long, most likely because
+	// PodDisruptionBudgets block the drain of its nodes.
+	PodDisruptionBudgetBlockingDrainReason = "PodDisruptionBudgetBlockingDrain"
 )
 
 const (
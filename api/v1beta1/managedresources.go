@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import "strings"
+
+// Has returns true if the Azure resource with the given ID is recorded. Resource IDs are compared case-insensitively.
+func (m *ManagedResources) Has(id string) bool {
+	if m == nil {
+		return false
+	}
+	for _, recordedID := range m.IDs {
+		if strings.EqualFold(recordedID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add records the Azure resource with the given ID unless it is already recorded.
+func (m *ManagedResources) Add(id string) {
+	if m == nil || id == "" || m.Has(id) {
+		return
+	}
+	m.IDs = append(m.IDs, id)
+}
+
+// Remove removes the Azure resource with the given ID from the recorded resources, including from the deletions in
+// progress.
+func (m *ManagedResources) Remove(id string) {
+	if m == nil {
+		return
+	}
+	ids := m.IDs[:0]
+	for _, recordedID := range m.IDs {
+		if !strings.EqualFold(recordedID, id) {
+			ids = append(ids, recordedID)
+		}
+	}
+	m.IDs = ids
+	for key, deletingID := range m.Deleting {
+		if strings.EqualFold(deletingID, id) {
+			delete(m.Deleting, key)
+		}
+	}
+	if len(m.Deleting) == 0 {
+		m.Deleting = nil
+	}
+}
+
+// MarkDeleting records that the recorded Azure resource with the given ID is being deleted by the resource with the
+// given key.
+func (m *ManagedResources) MarkDeleting(key, id string) {
+	if m == nil || key == "" || !m.Has(id) {
+		return
+	}
+	if m.Deleting == nil {
+		m.Deleting = map[string]string{}
+	}
+	m.Deleting[key] = id
+}
+
+// DeletingID returns the ID of the Azure resource being deleted by the resource with the given key, or an empty
+// string if there is none.
+func (m *ManagedResources) DeletingID(key string) string {
+	if m == nil {
+		return ""
+	}
+	return m.Deleting[key]
+}
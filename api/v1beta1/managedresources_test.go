@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestManagedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	var unrecorded *ManagedResources
+	unrecorded.Add("my-id")
+	unrecorded.Remove("my-id")
+	unrecorded.MarkDeleting("my-key", "my-id")
+	g.Expect(unrecorded.Has("my-id")).To(BeFalse())
+	g.Expect(unrecorded.DeletingID("my-key")).To(BeEmpty())
+
+	m := &ManagedResources{}
+	m.Add("my-id")
+	m.Add("MY-ID")
+	m.Add("")
+	m.Add("other-id")
+	g.Expect(m.IDs).To(Equal([]string{"my-id", "other-id"}))
+	g.Expect(m.Has("My-Id")).To(BeTrue())
+
+	m.Remove("MY-ID")
+	m.Remove("unknown-id")
+	g.Expect(m.IDs).To(Equal([]string{"other-id"}))
+	g.Expect(m.Has("my-id")).To(BeFalse())
+
+	m.MarkDeleting("unknown-key", "unknown-id")
+	g.Expect(m.Deleting).To(BeNil())
+	m.MarkDeleting("other-key", "other-id")
+	g.Expect(m.DeletingID("other-key")).To(Equal("other-id"))
+	g.Expect(m.IDs).To(Equal([]string{"other-id"}))
+
+	m.Remove("OTHER-ID")
+	g.Expect(m.IDs).To(BeEmpty())
+	g.Expect(m.DeletingID("other-key")).To(BeEmpty())
+	g.Expect(m.Deleting).To(BeNil())
+}
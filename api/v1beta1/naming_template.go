@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// APIServerLBNamingRole is the .Role of the API server load balancer in NamingTemplate.LoadBalancer.
+	APIServerLBNamingRole = "apiserver"
+	// NodeOutboundLBNamingRole is the .Role of the node outbound load balancer in NamingTemplate.LoadBalancer.
+	NodeOutboundLBNamingRole = "node-outbound"
+	// ControlPlaneOutboundLBNamingRole is the .Role of the control plane outbound load balancer in
+	// NamingTemplate.LoadBalancer.
+	ControlPlaneOutboundLBNamingRole = "controlplane-outbound"
+
+	// maxResourceNameLength is the maximum length of the names of the network interfaces, public IPs, disks and load
+	// balancers.
+	maxResourceNameLength = 80
+
+	randomSuffixLength   = 5
+	randomSuffixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var (
+	// networkResourceNameRegex matches the valid names of network interfaces, public IPs and load balancers.
+	networkResourceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9_])?$`)
+	// diskNameRegex matches the valid names of managed disks, which can't contain periods.
+	diskNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?$`)
+)
+
+// NamingTemplateVariables are the variables available to the templates of a NamingTemplate.
+type NamingTemplateVariables struct {
+	ClusterName  string
+	MachineName  string
+	Role         string
+	RandomSuffix string
+}
+
+// NewNamingTemplateVariables returns the variables to render the name of a resource of machineName, or of the
+// cluster if machineName is empty. RandomSuffix is derived from the namespace and names, so that the names rendered
+// for a resource don't change between reconciliations.
+func NewNamingTemplateVariables(namespace, clusterName, machineName, role string) NamingTemplateVariables {
+	sum := sha256.Sum256([]byte(strings.Join([]string{namespace, clusterName, machineName}, "/")))
+	suffix := make([]byte, randomSuffixLength)
+	for i := range suffix {
+		suffix[i] = randomSuffixAlphabet[int(sum[i])%len(randomSuffixAlphabet)]
+	}
+	return NamingTemplateVariables{
+		ClusterName:  clusterName,
+		MachineName:  machineName,
+		Role:         role,
+		RandomSuffix: string(suffix),
+	}
+}
+
+// RenderName renders the name of a resource with tmpl. Referencing a variable which doesn't exist is an error.
+func RenderName(tmpl string, vars NamingTemplateVariables) (string, error) {
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse naming template")
+	}
+	var name strings.Builder
+	if err := t.Execute(&name, vars); err != nil {
+		return "", errors.Wrap(err, "failed to render naming template")
+	}
+	return name.String(), nil
+}
+
+// validateNamingTemplate validates that the templates of namingTemplate render valid and distinct names for sample
+// machines and load balancers of the cluster.
+func validateNamingTemplate(namingTemplate *NamingTemplate, namespace, clusterName string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if namingTemplate == nil {
+		return allErrs
+	}
+
+	machineNames := []string{
+		fmt.Sprintf("%s-control-plane-abcde", clusterName),
+		fmt.Sprintf("%s-md-0-abcde-fghij", clusterName),
+	}
+	machineTemplates := []struct {
+		name  string
+		tmpl  string
+		regex *regexp.Regexp
+	}{
+		{name: "networkInterface", tmpl: namingTemplate.NetworkInterface, regex: networkResourceNameRegex},
+		{name: "publicIP", tmpl: namingTemplate.PublicIP, regex: networkResourceNameRegex},
+		{name: "disk", tmpl: namingTemplate.Disk, regex: diskNameRegex},
+	}
+	for _, t := range machineTemplates {
+		if t.tmpl == "" {
+			continue
+		}
+		fld := fldPath.Child(t.name)
+		names := make([]string, 0, len(machineNames))
+		for i, machineName := range machineNames {
+			role := ControlPlane
+			if i > 0 {
+				role = Node
+			}
+			name, err := validateRenderedName(t.tmpl, NewNamingTemplateVariables(namespace, clusterName, machineName, role), t.regex, fld)
+			if err != nil {
+				allErrs = append(allErrs, err)
+				break
+			}
+			names = append(names, name)
+		}
+		if len(names) == len(machineNames) && names[0] == names[1] {
+			allErrs = append(allErrs, field.Invalid(fld, t.tmpl, "template must render a distinct name for each machine, e.g. by using .MachineName"))
+		}
+	}
+
+	if namingTemplate.LoadBalancer != "" {
+		fld := fldPath.Child("loadBalancer")
+		seen := make(map[string]bool)
+		for _, role := range []string{APIServerLBNamingRole, NodeOutboundLBNamingRole, ControlPlaneOutboundLBNamingRole} {
+			name, err := validateRenderedName(namingTemplate.LoadBalancer, NewNamingTemplateVariables(namespace, clusterName, "", role), networkResourceNameRegex, fld)
+			if err != nil {
+				allErrs = append(allErrs, err)
+				break
+			}
+			if seen[name] {
+				allErrs = append(allErrs, field.Invalid(fld, namingTemplate.LoadBalancer, "template must render a distinct name for each load balancer, e.g. by using .Role"))
+				break
+			}
+			seen[name] = true
+		}
+	}
+
+	return allErrs
+}
+
+// validateRenderedName renders tmpl with vars and validates the length and characters of the name.
+func validateRenderedName(tmpl string, vars NamingTemplateVariables, regex *regexp.Regexp, fldPath *field.Path) (string, *field.Error) {
+	name, err := RenderName(tmpl, vars)
+	if err != nil {
+		return "", field.Invalid(fldPath, tmpl, err.Error())
+	}
+	if len(name) > maxResourceNameLength {
+		return "", field.Invalid(fldPath, tmpl, fmt.Sprintf("template renders names longer than %d characters, e.g. %q", maxResourceNameLength, name))
+	}
+	if !regex.MatchString(name) {
+		return "", field.Invalid(fldPath, tmpl, fmt.Sprintf("template renders invalid names, e.g. %q: names must match the regex %s", name, regex.String()))
+	}
+	return name, nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestNewNamingTemplateVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	vars := NewNamingTemplateVariables("default", "my-cluster", "my-machine", Node)
+	g.Expect(vars.ClusterName).To(Equal("my-cluster"))
+	g.Expect(vars.MachineName).To(Equal("my-machine"))
+	g.Expect(vars.Role).To(Equal(Node))
+	g.Expect(vars.RandomSuffix).To(MatchRegexp(`^[a-z0-9]{5}$`))
+
+	// The suffix is stable for a machine, and differs between machines.
+	g.Expect(NewNamingTemplateVariables("default", "my-cluster", "my-machine", Node).RandomSuffix).To(Equal(vars.RandomSuffix))
+	g.Expect(NewNamingTemplateVariables("default", "my-cluster", "my-other-machine", Node).RandomSuffix).NotTo(Equal(vars.RandomSuffix))
+}
+
+func TestRenderName(t *testing.T) {
+	vars := NamingTemplateVariables{
+		ClusterName:  "my-cluster",
+		MachineName:  "my-machine",
+		Role:         ControlPlane,
+		RandomSuffix: "abcde",
+	}
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "network interface",
+			tmpl: "nic-{{ .MachineName }}",
+			want: "nic-my-machine",
+		},
+		{
+			name: "public IP",
+			tmpl: "{{ .ClusterName }}-pip-{{ .RandomSuffix }}",
+			want: "my-cluster-pip-abcde",
+		},
+		{
+			name: "disk",
+			tmpl: "{{ .MachineName }}_{{ .Role }}_osdisk",
+			want: "my-machine_control-plane_osdisk",
+		},
+		{
+			name:    "invalid syntax",
+			tmpl:    "nic-{{ .MachineName",
+			wantErr: "failed to parse naming template",
+		},
+		{
+			name:    "unknown variable",
+			tmpl:    "nic-{{ .Namespace }}",
+			wantErr: "failed to render naming template",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := RenderName(tc.tmpl, vars)
+			if tc.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestValidateNamingTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		namingTemplate *NamingTemplate
+		wantErrs       []string
+	}{
+		{
+			name: "no naming template",
+		},
+		{
+			name: "valid templates",
+			namingTemplate: &NamingTemplate{
+				NetworkInterface: "nic-{{ .MachineName }}",
+				PublicIP:         "pip-{{ .MachineName }}-{{ .RandomSuffix }}",
+				Disk:             "{{ .MachineName }}_os",
+				LoadBalancer:     "{{ .ClusterName }}-{{ .Role }}-lb",
+			},
+		},
+		{
+			name:           "network interface template with invalid syntax",
+			namingTemplate: &NamingTemplate{NetworkInterface: "nic-{{ .MachineName"},
+			wantErrs:       []string{"spec.namingTemplate.networkInterface"},
+		},
+		{
+			name:           "network interface template with unknown variable",
+			namingTemplate: &NamingTemplate{NetworkInterface: "{{ .Namespace }}-{{ .MachineName }}"},
+			wantErrs:       []string{"spec.namingTemplate.networkInterface"},
+		},
+		{
+			name:           "network interface template with invalid characters",
+			namingTemplate: &NamingTemplate{NetworkInterface: "nic/{{ .MachineName }}"},
+			wantErrs:       []string{"spec.namingTemplate.networkInterface"},
+		},
+		{
+			name:           "network interface template ending with a hyphen",
+			namingTemplate: &NamingTemplate{NetworkInterface: "{{ .MachineName }}-"},
+			wantErrs:       []string{"spec.namingTemplate.networkInterface"},
+		},
+		{
+			name:           "network interface template rendering the same name for every machine",
+			namingTemplate: &NamingTemplate{NetworkInterface: "{{ .ClusterName }}-nic"},
+			wantErrs:       []string{"spec.namingTemplate.networkInterface"},
+		},
+		{
+			name:           "public IP template too long",
+			namingTemplate: &NamingTemplate{PublicIP: strings.Repeat("p", 70) + "-{{ .MachineName }}"},
+			wantErrs:       []string{"spec.namingTemplate.publicIP"},
+		},
+		{
+			name:           "public IP template rendering an empty name",
+			namingTemplate: &NamingTemplate{PublicIP: "{{ if false }}{{ .MachineName }}{{ end }}"},
+			wantErrs:       []string{"spec.namingTemplate.publicIP"},
+		},
+		{
+			name:           "disk template with periods",
+			namingTemplate: &NamingTemplate{Disk: "{{ .MachineName }}.osdisk"},
+			wantErrs:       []string{"spec.namingTemplate.disk"},
+		},
+		{
+			name:           "disk template with unknown variable",
+			namingTemplate: &NamingTemplate{Disk: "{{ .Zone }}-{{ .MachineName }}"},
+			wantErrs:       []string{"spec.namingTemplate.disk"},
+		},
+		{
+			name:           "load balancer template rendering the same name for every role",
+			namingTemplate: &NamingTemplate{LoadBalancer: "{{ .ClusterName }}-lb"},
+			wantErrs:       []string{"spec.namingTemplate.loadBalancer"},
+		},
+		{
+			name:           "load balancer template with invalid characters",
+			namingTemplate: &NamingTemplate{LoadBalancer: "{{ .ClusterName }} {{ .Role }}"},
+			wantErrs:       []string{"spec.namingTemplate.loadBalancer"},
+		},
+		{
+			name: "several invalid templates",
+			namingTemplate: &NamingTemplate{
+				NetworkInterface: "nic-{{ .MachineName }}",
+				Disk:             "disk.{{ .MachineName }}",
+				LoadBalancer:     "lb",
+			},
+			wantErrs: []string{"spec.namingTemplate.disk", "spec.namingTemplate.loadBalancer"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateNamingTemplate(tc.namingTemplate, "default", "my-cluster", field.NewPath("spec", "namingTemplate"))
+			fields := make([]string, len(errs))
+			for i, err := range errs {
+				fields[i] = err.Field
+			}
+			g.Expect(fields).To(ConsistOf(tc.wantErrs))
+		})
+	}
+}
+
+func TestLoadBalancerNamingTemplateDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: AzureClusterSpec{
+			AzureClusterClassSpec: AzureClusterClassSpec{
+				NamingTemplate: &NamingTemplate{LoadBalancer: "lb-{{ .ClusterName }}-{{ .Role }}"},
+			},
+			NetworkSpec: NetworkSpec{
+				APIServerLB: LoadBalancerSpec{
+					LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Public},
+				},
+				NodeOutboundLB:         &LoadBalancerSpec{},
+				ControlPlaneOutboundLB: &LoadBalancerSpec{},
+			},
+		},
+	}
+	cluster.setAPIServerLBDefaults()
+	cluster.SetNodeOutboundLBDefaults()
+	cluster.SetControlPlaneOutboundLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.Name).To(Equal("lb-my-cluster-apiserver"))
+	g.Expect(cluster.Spec.NetworkSpec.NodeOutboundLB.Name).To(Equal("lb-my-cluster-node-outbound"))
+	g.Expect(cluster.Spec.NetworkSpec.ControlPlaneOutboundLB.Name).To(Equal("lb-my-cluster-controlplane-outbound"))
+
+	// The names set in the spec are kept.
+	cluster.Spec.NetworkSpec.APIServerLB = LoadBalancerSpec{
+		Name:                  "my-lb",
+		LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Internal},
+		FrontendIPs:           []FrontendIP{{Name: "my-frontend", FrontendIPClass: FrontendIPClass{PrivateIPAddress: "10.0.0.100"}}},
+	}
+	cluster.setAPIServerLBDefaults()
+	g.Expect(cluster.Spec.NetworkSpec.APIServerLB.Name).To(Equal("my-lb"))
+}
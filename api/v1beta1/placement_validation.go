@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// placementAllowlistLookupTimeout is the maximum time a webhook waits for the allowed subscriptions and locations.
+const placementAllowlistLookupTimeout = 5 * time.Second
+
+// PlacementAllowlist provides the Azure subscriptions and locations that clusters are allowed to target. An empty list
+// allows any subscription or location.
+// +kubebuilder:object:generate=false
+type PlacementAllowlist interface {
+	AllowedPlacement(ctx context.Context) (subscriptions []string, locations []string, err error)
+}
+
+// placement is an Azure subscription and location referenced by an object, along with their field paths. Empty values
+// and nil paths are not validated.
+type placement struct {
+	subscriptionID   string
+	subscriptionPath *field.Path
+	location         string
+	locationPath     *field.Path
+}
+
+// validatePlacement checks the placement against the allowlist. The allowlist is enforced on creation only, as the
+// subscription and location of existing objects can't be changed. An error is returned if the allowlist can't be
+// read, so that objects aren't admitted while the allowlist is unknown.
+func validatePlacement(ctx context.Context, allowlist PlacementAllowlist, p placement) (field.ErrorList, error) {
+	if allowlist == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, placementAllowlistLookupTimeout)
+	defer cancel()
+
+	subscriptions, locations, err := allowlist.AllowedPlacement(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(errors.Wrap(err, "failed to get the allowed subscriptions and locations"))
+	}
+
+	var allErrs field.ErrorList
+	if p.subscriptionPath != nil && p.subscriptionID != "" && !isAllowed(subscriptions, p.subscriptionID, strings.ToLower) {
+		allErrs = append(allErrs, field.Forbidden(p.subscriptionPath, fmt.Sprintf("subscription %q is not allowed on this management cluster, allowed subscriptions are: %s", p.subscriptionID, strings.Join(subscriptions, ", "))))
+	}
+	if p.locationPath != nil && p.location != "" && !isAllowed(locations, p.location, normalizeLocation) {
+		allErrs = append(allErrs, field.Forbidden(p.locationPath, fmt.Sprintf("location %q is not allowed on this management cluster, allowed locations are: %s", p.location, strings.Join(locations, ", "))))
+	}
+	return allErrs, nil
+}
+
+// isAllowed returns true if allowed is empty or contains value once both are normalized.
+func isAllowed(allowed []string, value string, normalize func(string) string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if normalize(a) == normalize(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLocation turns a location display name such as "West US 2" into its name, "westus2".
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+const (
+	// defaultSubnetPrefixLengthOffset is how many bits longer than the prefix of the virtual network the prefix of a
+	// generated subnet CIDR block is by default, e.g. /16 subnets in a /8 virtual network.
+	defaultSubnetPrefixLengthOffset = 8
+	// MaxSubnetPrefixLength is the longest prefix of an Azure subnet.
+	MaxSubnetPrefixLength = 29
+)
+
+// subnetCIDRAllocator carves the CIDR blocks of subnets out of the first IPv4 CIDR block of a virtual network. Blocks
+// are handed out in address order, skipping any that overlap a CIDR block already in use, so the result only depends
+// on the spec and is stable across webhook invocations.
+type subnetCIDRAllocator struct {
+	vnet      *net.IPNet
+	prefixLen int
+	used      []*net.IPNet
+}
+
+// newSubnetCIDRAllocator returns an allocator for the virtual network that avoids the given CIDR blocks. The virtual
+// network falls back to its default CIDR block if it has none. It returns nil if the virtual network has no IPv4 CIDR block.
+func newSubnetCIDRAllocator(vnet VnetClassSpec, usedCIDRBlocks []string) *subnetCIDRAllocator {
+	a := &subnetCIDRAllocator{}
+	vnetCIDRBlocks := vnet.CIDRBlocks
+	if len(vnetCIDRBlocks) == 0 {
+		vnetCIDRBlocks = []string{DefaultVnetCIDR}
+	}
+	for _, cidr := range vnetCIDRBlocks {
+		if _, nw, err := net.ParseCIDR(cidr); err == nil && nw.IP.To4() != nil {
+			a.vnet = nw
+			break
+		}
+	}
+	if a.vnet == nil {
+		return nil
+	}
+	a.prefixLen = vnet.GeneratedSubnetPrefixLength(a.vnet)
+	for _, cidr := range usedCIDRBlocks {
+		if _, nw, err := net.ParseCIDR(cidr); err == nil {
+			a.used = append(a.used, nw)
+		}
+	}
+	return a
+}
+
+// GeneratedSubnetPrefixLength returns the prefix length of the subnet CIDR blocks generated from the given virtual
+// network CIDR block, which is also the prefix length of the IPv4 address spaces allocated to subnets from IPAM pools.
+func (v VnetClassSpec) GeneratedSubnetPrefixLength(vnet *net.IPNet) int {
+	if v.SubnetPrefixLength != nil {
+		return int(*v.SubnetPrefixLength)
+	}
+	ones, _ := vnet.Mask.Size()
+	if ones+defaultSubnetPrefixLengthOffset > MaxSubnetPrefixLength {
+		return MaxSubnetPrefixLength
+	}
+	return ones + defaultSubnetPrefixLengthOffset
+}
+
+// allocate returns the first free CIDR block for a subnet that has neither CIDR blocks nor an IPAM pool and marks it
+// as used. It returns an empty string if the subnet doesn't need a CIDR block or the virtual network is full.
+func (a *subnetCIDRAllocator) allocate(subnet SubnetClassSpec) string {
+	if a == nil || len(subnet.CIDRBlocks) > 0 || subnet.IPAMPoolRef != nil {
+		return ""
+	}
+	vnetOnes, _ := a.vnet.Mask.Size()
+	if a.prefixLen < vnetOnes || a.prefixLen > MaxSubnetPrefixLength {
+		return ""
+	}
+	base := binary.BigEndian.Uint32(a.vnet.IP.To4())
+	mask := net.CIDRMask(a.prefixLen, 8*net.IPv4len)
+	step := uint64(1) << (8*net.IPv4len - a.prefixLen)
+	slots := uint64(1) << (a.prefixLen - vnetOnes)
+	for i := uint64(0); i < slots; i++ {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, base+uint32(i*step))
+		candidate := &net.IPNet{IP: ip, Mask: mask}
+		if !a.overlapsUsed(candidate) {
+			a.used = append(a.used, candidate)
+			return candidate.String()
+		}
+	}
+	return ""
+}
+
+func (a *subnetCIDRAllocator) overlapsUsed(nw *net.IPNet) bool {
+	for _, used := range a.used {
+		if cidrsOverlap(nw, used) {
+			return true
+		}
+	}
+	return false
+}
+
+// cidrsOverlap returns true if the two networks share any address.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -19,6 +19,9 @@ package v1beta1
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Tags defines a map of tags.
@@ -36,8 +39,12 @@ func (t Tags) HasMatchingSpecVersionHash(hash string) bool {
 }
 
 // HasOwned returns true if the tags contains a tag that marks the resource as owned by the cluster from the perspective of this management tooling.
+// Tags using NameAzureProviderPrefix are recognized when a different cluster tag prefix is configured.
 func (t Tags) HasOwned(cluster string) bool {
 	value, ok := t[ClusterTagKey(cluster)]
+	if !ok {
+		value, ok = t[NameAzureProviderOwned+cluster]
+	}
 	return ok && ResourceLifecycle(value) == ResourceLifecycleOwned
 }
 
@@ -49,9 +56,37 @@ func (t Tags) HasAzureCloudProviderOwned(cluster string) bool {
 
 // GetRole returns the Cluster API role for the tagged resource.
 func (t Tags) GetRole() string {
+	if role, ok := t[ClusterAPIRoleTagKey()]; ok {
+		return role
+	}
 	return t[NameAzureClusterAPIRole]
 }
 
+// MigrateLegacyPrefix returns the tags with the keys using NameAzureProviderPrefix renamed to use the configured
+// cluster tag prefix instead, along with the legacy tags that were renamed. The tags are returned unchanged when the
+// default prefix is used.
+func (t Tags) MigrateLegacyPrefix() (migrated Tags, legacy Tags) {
+	prefix := ClusterTagPrefix()
+	if prefix == NameAzureProviderPrefix {
+		return t, nil
+	}
+	migrated = make(Tags, len(t))
+	legacy = make(Tags)
+	for key, value := range t {
+		name, isLegacy := strings.CutPrefix(key, NameAzureProviderPrefix)
+		if !isLegacy || strings.HasPrefix(key, prefix) {
+			migrated[key] = value
+			continue
+		}
+		legacy[key] = value
+		// A tag already set with the new prefix takes precedence over the legacy one.
+		if _, ok := t[prefix+name]; !ok {
+			migrated[prefix+name] = value
+		}
+	}
+	return migrated, legacy
+}
+
 // Difference returns the difference between this map of tags and the other map of tags.
 // Items are considered equals if key and value are equals.
 func (t Tags) Difference(other Tags) Tags {
@@ -104,7 +139,7 @@ const (
 
 	// NameAzureProviderPrefix is the tag prefix we use to differentiate
 	// cluster-api-provider-azure owned components from other tooling that
-	// uses NameKubernetesClusterPrefix.
+	// uses NameKubernetesClusterPrefix. It is the default cluster tag prefix, see SetClusterTagPrefix.
 	NameAzureProviderPrefix = "sigs.k8s.io_cluster-api-provider-azure_"
 
 	// NameAzureProviderOwned is the tag name we use to differentiate
@@ -146,14 +181,47 @@ const (
 	RGTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-rg"
 )
 
+// clusterTagPrefix is the prefix of the keys of the tags CAPZ sets on the resources it manages.
+var clusterTagPrefix = NameAzureProviderPrefix
+
+// SetClusterTagPrefix sets the prefix of the keys of the tags CAPZ sets on the resources it manages, which defaults to
+// NameAzureProviderPrefix. Resources tagged with NameAzureProviderPrefix are still recognized as owned, and their tags
+// are rewritten with the new prefix when CAPZ reconciles them. It must be called before any controller or webhook
+// starts.
+func SetClusterTagPrefix(prefix string) error {
+	if prefix == "" {
+		return errors.New("cluster tag prefix must not be empty")
+	}
+	if strings.ContainsAny(prefix, `<>%&\?/`) {
+		return errors.Errorf("cluster tag prefix %q must not contain any of the characters <>%%&\\?/", prefix)
+	}
+	clusterTagPrefix = prefix
+	return nil
+}
+
+// ClusterTagPrefix returns the prefix of the keys of the tags CAPZ sets on the resources it manages.
+func ClusterTagPrefix() string {
+	return clusterTagPrefix
+}
+
 // SpecVersionHashTagKey is the key for the spec version hash used to enable quick spec difference comparison.
 func SpecVersionHashTagKey() string {
-	return fmt.Sprintf("%s%s", NameAzureProviderPrefix, "spec-version-hash")
+	return fmt.Sprintf("%s%s", ClusterTagPrefix(), "spec-version-hash")
 }
 
 // ClusterTagKey generates the key for resources associated with a cluster.
 func ClusterTagKey(name string) string {
-	return fmt.Sprintf("%s%s", NameAzureProviderOwned, name)
+	return fmt.Sprintf("%s%s%s", ClusterTagPrefix(), "cluster_", name)
+}
+
+// ClusterAPIRoleTagKey is the key for the Cluster API role of a resource.
+func ClusterAPIRoleTagKey() string {
+	return fmt.Sprintf("%s%s", ClusterTagPrefix(), "role")
+}
+
+// MachinePoolNameTagKey is the key for the name of the machine pool of a resource.
+func MachinePoolNameTagKey() string {
+	return fmt.Sprintf("%s%s", ClusterTagPrefix(), "machinepool")
 }
 
 // ClusterAzureCloudProviderTagKey generates the key for resources associated a cluster's Azure cloud provider.
@@ -194,7 +262,7 @@ func Build(params BuildParams) Tags {
 
 	tags[ClusterTagKey(params.ClusterName)] = string(params.Lifecycle)
 	if params.Role != nil {
-		tags[NameAzureClusterAPIRole] = *params.Role
+		tags[ClusterAPIRoleTagKey()] = *params.Role
 	}
 
 	if params.Name != nil {
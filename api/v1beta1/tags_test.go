@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
 )
 
 func TestTags_Merge(t *testing.T) {
@@ -87,3 +88,203 @@ func TestTags_Merge(t *testing.T) {
 		})
 	}
 }
+
+// setClusterTagPrefix sets the cluster tag prefix for the duration of the test. Tests calling it must not run in
+// parallel.
+func setClusterTagPrefix(t *testing.T, prefix string) {
+	t.Helper()
+	previous := ClusterTagPrefix()
+	if err := SetClusterTagPrefix(prefix); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { clusterTagPrefix = previous })
+}
+
+func TestSetClusterTagPrefix(t *testing.T) {
+	g := NewWithT(t)
+	setClusterTagPrefix(t, "acme_capz_")
+
+	g.Expect(ClusterTagKey("my-cluster")).To(Equal("acme_capz_cluster_my-cluster"))
+	g.Expect(ClusterAPIRoleTagKey()).To(Equal("acme_capz_role"))
+	g.Expect(SpecVersionHashTagKey()).To(Equal("acme_capz_spec-version-hash"))
+	g.Expect(Build(BuildParams{
+		ClusterName: "my-cluster",
+		Lifecycle:   ResourceLifecycleOwned,
+		Role:        ptr.To(CommonRole),
+	})).To(Equal(Tags{
+		"acme_capz_cluster_my-cluster": "owned",
+		"acme_capz_role":               "common",
+	}))
+
+	g.Expect(SetClusterTagPrefix("")).To(HaveOccurred())
+	g.Expect(SetClusterTagPrefix("acme/capz_")).To(HaveOccurred())
+	g.Expect(ClusterTagPrefix()).To(Equal("acme_capz_"))
+}
+
+func TestTags_HasOwned(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		tags     Tags
+		expected bool
+	}{
+		{
+			name:     "default prefix",
+			tags:     Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned"},
+			expected: true,
+		},
+		{
+			name:     "shared resource",
+			tags:     Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "shared"},
+			expected: false,
+		},
+		{
+			name:     "other cluster",
+			tags:     Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_other-cluster": "owned"},
+			expected: false,
+		},
+		{
+			name:     "custom prefix",
+			prefix:   "acme_capz_",
+			tags:     Tags{"acme_capz_cluster_my-cluster": "owned"},
+			expected: true,
+		},
+		{
+			name:     "custom prefix recognizes the legacy prefix",
+			prefix:   "acme_capz_",
+			tags:     Tags{"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned"},
+			expected: true,
+		},
+		{
+			name:   "custom prefix takes precedence over the legacy prefix",
+			prefix: "acme_capz_",
+			tags: Tags{
+				"acme_capz_cluster_my-cluster":                              "shared",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+			},
+			expected: false,
+		},
+		{
+			name:     "default prefix ignores custom prefixes",
+			tags:     Tags{"acme_capz_cluster_my-cluster": "owned"},
+			expected: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			if tc.prefix != "" {
+				setClusterTagPrefix(t, tc.prefix)
+			}
+			g.Expect(tc.tags.HasOwned("my-cluster")).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestTags_GetRole(t *testing.T) {
+	g := NewWithT(t)
+	setClusterTagPrefix(t, "acme_capz_")
+
+	g.Expect(Tags{"acme_capz_role": "apiserver"}.GetRole()).To(Equal("apiserver"))
+	g.Expect(Tags{"sigs.k8s.io_cluster-api-provider-azure_role": "apiserver"}.GetRole()).To(Equal("apiserver"))
+	g.Expect(Tags{
+		"acme_capz_role": "nodeOutbound",
+		"sigs.k8s.io_cluster-api-provider-azure_role": "apiserver",
+	}.GetRole()).To(Equal("nodeOutbound"))
+}
+
+func TestTags_MigrateLegacyPrefix(t *testing.T) {
+	tests := []struct {
+		name             string
+		prefix           string
+		tags             Tags
+		expectedMigrated Tags
+		expectedLegacy   Tags
+	}{
+		{
+			name:   "default prefix leaves the tags unchanged",
+			prefix: NameAzureProviderPrefix,
+			tags: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				"foo": "bar",
+			},
+			expectedMigrated: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				"foo": "bar",
+			},
+		},
+		{
+			name:   "legacy prefix is rewritten",
+			prefix: "acme_capz_",
+			tags: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":               "common",
+				"foo": "bar",
+			},
+			expectedMigrated: Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+				"acme_capz_role":               "common",
+				"foo":                          "bar",
+			},
+			expectedLegacy: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":               "common",
+			},
+		},
+		{
+			name:   "new prefix is left as is",
+			prefix: "acme_capz_",
+			tags: Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+				"foo":                          "bar",
+			},
+			expectedMigrated: Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+				"foo":                          "bar",
+			},
+			expectedLegacy: Tags{},
+		},
+		{
+			name:   "mixed prefixes keep the values of the new prefix",
+			prefix: "acme_capz_",
+			tags: Tags{
+				"acme_capz_cluster_my-cluster":                              "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "shared",
+				"sigs.k8s.io_cluster-api-provider-azure_role":               "common",
+			},
+			expectedMigrated: Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+				"acme_capz_role":               "common",
+			},
+			expectedLegacy: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "shared",
+				"sigs.k8s.io_cluster-api-provider-azure_role":               "common",
+			},
+		},
+		{
+			name:   "new prefix extending the legacy prefix",
+			prefix: "sigs.k8s.io_cluster-api-provider-azure_acme_",
+			tags: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_acme_cluster_my-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":                    "common",
+			},
+			expectedMigrated: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_acme_cluster_my-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_acme_role":               "common",
+			},
+			expectedLegacy: Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_role": "common",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			setClusterTagPrefix(t, tc.prefix)
+
+			migrated, legacy := tc.tags.MigrateLegacyPrefix()
+			g.Expect(migrated).To(Equal(tc.expectedMigrated))
+			g.Expect(legacy).To(Equal(tc.expectedLegacy))
+		})
+	}
+}
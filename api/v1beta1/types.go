@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/net"
 )
 
@@ -88,6 +89,33 @@ type Future struct {
 	Data string `json:"data"`
 }
 
+// V1Beta2Status groups the conditions following the Cluster API v1beta2 contract, which are reported alongside the
+// v1beta1 conditions until the latter are removed.
+type V1Beta2Status struct {
+	// Conditions represents the observations of the current state of the object following the v1beta2 contract,
+	// where the Ready condition aggregates the other conditions and every condition records the generation it observed.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ReconcileBackoff records the consecutive reconciliations of an object which ended with a transient Azure error, so
+// that they are retried with an exponential backoff rather than at a fixed interval.
+type ReconcileBackoff struct {
+	// Failures is the number of consecutive reconciliations which ended with a transient error.
+	Failures int32 `json:"failures"`
+
+	// NextAttemptTime is the earliest time at which the reconciliation is attempted again.
+	NextAttemptTime metav1.Time `json:"nextAttemptTime"`
+
+	// ObservedGeneration is the generation of the object when the failures were recorded. A change of the spec
+	// resets the backoff.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
 // NetworkSpec specifies what the Azure networking resources should look like.
 type NetworkSpec struct {
 	// Vnet is the configuration for the Azure virtual network.
@@ -245,6 +273,17 @@ type NatGateway struct {
 // NatGatewayClassSpec defines a NAT gateway class specification.
 type NatGatewayClassSpec struct {
 	Name string `json:"name"`
+
+	// Zone is the availability zone the NAT gateway and its public IP are deployed to.
+	// NAT gateways can't be zone-redundant, so a single zone must be specified.
+	// If not set, the NAT gateway is deployed without a zone. This field is immutable.
+	// +optional
+	Zone *string `json:"zone,omitempty"`
+
+	// IdleTimeoutInMinutes is the idle timeout for outbound flows through the NAT gateway.
+	// If not set, Azure defaults to 4 minutes.
+	// +optional
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
 }
 
 // SecurityGroupProtocol defines the protocol type for a security group rule.
@@ -369,6 +408,13 @@ type FrontendIP struct {
 	// +optional
 	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
 
+	// Zones specifies the availability zones of a private frontend IP of an internal load balancer. A public frontend
+	// IP uses the zones of its public IP instead.
+	// If not set, the frontend IP is zone-redundant across the availability zones of the location, or has no zone in a
+	// location without availability zones. This field is immutable.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
 	FrontendIPClass `json:",inline"`
 }
 
@@ -379,6 +425,12 @@ type PublicIPSpec struct {
 	DNSName string `json:"dnsName,omitempty"`
 	// +optional
 	IPTags []IPTag `json:"ipTags,omitempty"`
+	// Zones specifies the availability zones of the public IP. A single zone pins the public IP to that zone.
+	// If not set, the public IP is zone-redundant across the availability zones of the location, or has no zone in a
+	// location without availability zones. The public IP of a zonal NAT gateway defaults to the zone of the NAT
+	// gateway. This field is immutable.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
 // IPTag contains the IpTag associated with the object.
@@ -416,6 +468,26 @@ const (
 	Deleted ProvisioningState = "Deleted"
 )
 
+// VMPowerState describes the power state of an Azure virtual machine.
+type VMPowerState string
+
+const (
+	// VMPowerStateRunning is the power state of a running virtual machine.
+	VMPowerStateRunning VMPowerState = "Running"
+	// VMPowerStateDeallocated is the power state of a virtual machine whose compute resources were released.
+	VMPowerStateDeallocated VMPowerState = "Deallocated"
+	// VMPowerStateHibernated is the power state of a deallocated virtual machine whose memory was saved to its OS disk.
+	VMPowerStateHibernated VMPowerState = "Hibernated"
+	// VMPowerStateStarting is the power state of a virtual machine being started.
+	VMPowerStateStarting VMPowerState = "Starting"
+	// VMPowerStateStopping is the power state of a virtual machine being stopped.
+	VMPowerStateStopping VMPowerState = "Stopping"
+	// VMPowerStateStopped is the power state of a virtual machine stopped without releasing its compute resources.
+	VMPowerStateStopped VMPowerState = "Stopped"
+	// VMPowerStateDeallocating is the power state of a virtual machine being deallocated or hibernated.
+	VMPowerStateDeallocating VMPowerState = "Deallocating"
+)
+
 // Image defines information about the image to use for VM creation.
 // There are three ways to specify an image: by ID, Marketplace Image or SharedImageGallery
 // One of ID, SharedImage or Marketplace should be set.
@@ -464,6 +536,23 @@ type AzureComputeGalleryImage struct {
 	Plan *ImagePlan `json:"plan,omitempty"`
 }
 
+// DefaultImage defines an image definition in the Azure Compute Gallery used for machines that don't specify an image.
+// The image version is selected to match the Kubernetes version of each machine, e.g. "1.28.3".
+type DefaultImage struct {
+	// Gallery specifies the name of the compute image gallery that contains the image
+	// +kubebuilder:validation:MinLength=1
+	Gallery string `json:"gallery"`
+	// Name is the name of the image definition
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// SubscriptionID is the identifier of the subscription that contains the private compute gallery.
+	// +optional
+	SubscriptionID *string `json:"subscriptionID,omitempty"`
+	// ResourceGroup specifies the resource group containing the private compute gallery.
+	// +optional
+	ResourceGroup *string `json:"resourceGroup,omitempty"`
+}
+
 // ImagePlan contains plan information for marketplace images.
 type ImagePlan struct {
 	// Publisher is the name of the organization that created the image
@@ -610,8 +699,89 @@ type OSDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// DiskControllerType specifies the disk controller type of the VM, which applies to the OS disk and all data disks.
+	// NVMe requires a VM size and an image that support it. Defaults to the type chosen by Azure, usually SCSI.
+	// +optional
+	DiskControllerType DiskControllerType `json:"diskControllerType,omitempty"`
 }
 
+// DiskControllerType defines the disk controller type of a VM.
+// +kubebuilder:validation:Enum=SCSI;NVMe
+type DiskControllerType string
+
+const (
+	// DiskControllerTypeSCSI attaches the disks of a VM to a SCSI controller.
+	DiskControllerTypeSCSI DiskControllerType = "SCSI"
+	// DiskControllerTypeNVMe attaches the disks of a VM to an NVMe controller.
+	DiskControllerTypeNVMe DiskControllerType = "NVMe"
+)
+
+// PatchSettings defines the guest OS patching of a VM. See
+// https://learn.microsoft.com/azure/virtual-machines/automatic-vm-guest-patching.
+type PatchSettings struct {
+	// PatchMode specifies how the guest OS is patched. AutomaticByPlatform is supported on Linux and Windows,
+	// ImageDefault on Linux only, and AutomaticByOS and Manual on Windows only. Defaults to the image's own patching on
+	// Linux, and to Manual on Windows.
+	// +optional
+	PatchMode PatchMode `json:"patchMode,omitempty"`
+
+	// AssessmentMode specifies how the guest OS is assessed for available patches. AutomaticByPlatform assesses the
+	// VM every 24 hours. Defaults to ImageDefault.
+	// +optional
+	AssessmentMode PatchAssessmentMode `json:"assessmentMode,omitempty"`
+
+	// BypassPlatformSafetyChecksOnUserSchedule leaves the scheduling of patches to the maintenance configurations
+	// assigned to the VM rather than to the safe deployment practices of the platform. It requires the
+	// AutomaticByPlatform patch mode.
+	// +optional
+	BypassPlatformSafetyChecksOnUserSchedule *bool `json:"bypassPlatformSafetyChecksOnUserSchedule,omitempty"`
+
+	// RebootSetting specifies whether the VM is rebooted after patches are installed. It requires the
+	// AutomaticByPlatform patch mode.
+	// +optional
+	RebootSetting PatchRebootSetting `json:"rebootSetting,omitempty"`
+}
+
+// PatchMode defines how the guest OS of a VM is patched.
+// +kubebuilder:validation:Enum=AutomaticByPlatform;ImageDefault;AutomaticByOS;Manual
+type PatchMode string
+
+const (
+	// PatchModeAutomaticByPlatform installs patches through the platform's orchestration, across availability zones
+	// and during off-peak hours.
+	PatchModeAutomaticByPlatform PatchMode = "AutomaticByPlatform"
+	// PatchModeImageDefault keeps the patching configuration of the image. It is only supported on Linux.
+	PatchModeImageDefault PatchMode = "ImageDefault"
+	// PatchModeAutomaticByOS lets Windows Update install patches. It is only supported on Windows.
+	PatchModeAutomaticByOS PatchMode = "AutomaticByOS"
+	// PatchModeManual disables automatic updates. It is only supported on Windows.
+	PatchModeManual PatchMode = "Manual"
+)
+
+// PatchAssessmentMode defines how the guest OS of a VM is assessed for available patches.
+// +kubebuilder:validation:Enum=AutomaticByPlatform;ImageDefault
+type PatchAssessmentMode string
+
+const (
+	// PatchAssessmentModeAutomaticByPlatform periodically assesses the VM through the platform.
+	PatchAssessmentModeAutomaticByPlatform PatchAssessmentMode = "AutomaticByPlatform"
+	// PatchAssessmentModeImageDefault only assesses the VM on demand.
+	PatchAssessmentModeImageDefault PatchAssessmentMode = "ImageDefault"
+)
+
+// PatchRebootSetting defines whether a VM is rebooted after patches are installed by the platform.
+// +kubebuilder:validation:Enum=Always;IfRequired;Never
+type PatchRebootSetting string
+
+const (
+	// PatchRebootSettingAlways always reboots the VM after installing patches.
+	PatchRebootSettingAlways PatchRebootSetting = "Always"
+	// PatchRebootSettingIfRequired reboots the VM after installing patches that require it.
+	PatchRebootSettingIfRequired PatchRebootSetting = "IfRequired"
+	// PatchRebootSettingNever never reboots the VM, so patches that require a reboot aren't installed.
+	PatchRebootSettingNever PatchRebootSetting = "Never"
+)
+
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
 type DataDisk struct {
 	// NameSuffix is the suffix to be appended to the machine name to generate the disk name.
@@ -789,7 +959,8 @@ type NetworkInterface struct {
 	SubnetName string `json:"subnetName,omitempty"`
 
 	// PrivateIPConfigs specifies the number of private IP addresses to attach to the interface.
-	// Defaults to 1 if not specified.
+	// Defaults to 1 if not specified. Secondary IP configurations can be added to and removed from the network
+	// interfaces of existing AzureMachines, and their addresses are reported in the AzureMachine's status.
 	// +optional
 	PrivateIPConfigs int `json:"privateIPConfigs,omitempty"`
 
@@ -1006,6 +1177,7 @@ type AzureBastion struct {
 	// +optional
 	PublicIP PublicIPSpec `json:"publicIP,omitempty"`
 	// BastionHostSkuName configures the tier of the Azure Bastion Host. Can be either Basic or Standard. Defaults to Basic.
+	// The Developer SKU isn't supported as the version of the BastionHost API used by CAPZ doesn't offer it.
 	// +kubebuilder:default=Basic
 	// +kubebuilder:validation:Enum=Basic;Standard
 	// +optional
@@ -1014,6 +1186,11 @@ type AzureBastion struct {
 	// +kubebuilder:default=false
 	// +optional
 	EnableTunneling bool `json:"enableTunneling,omitempty"`
+	// EnableShareableLink enables the shareable link feature for the Azure Bastion Host. It requires the Standard
+	// SKU. Defaults to false.
+	// +kubebuilder:default=false
+	// +optional
+	EnableShareableLink bool `json:"enableShareableLink,omitempty"`
 }
 
 // FleetsMember defines the fleets member configuration.
@@ -1180,3 +1357,27 @@ const (
 	// AKSAssignedIdentityUserAssigned ...
 	AKSAssignedIdentityUserAssigned AKSAssignedIdentity = "UserAssigned"
 )
+
+// DeletionPolicy defines what happens to the Azure resources of a cluster when the cluster is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Azure resources managed by CAPZ along with the cluster.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the Azure resources of the cluster and only deletes the Kubernetes objects.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// RetainResourcesConfirmationAnnotation must be set to "true" on an existing AzureCluster or
+// AzureManagedControlPlane to change its deletion policy to Retain.
+const RetainResourcesConfirmationAnnotation = "infrastructure.cluster.x-k8s.io/confirm-retain-resources"
+
+// MigrateAPIServerLBToInternalAnnotation must be set to "true" on an AzureCluster to migrate its API server load
+// balancer from Public to Internal. Once the public load balancer is recorded in the status of the AzureCluster, the
+// type of the API server load balancer can be changed to Internal.
+const MigrateAPIServerLBToInternalAnnotation = "infrastructure.cluster.x-k8s.io/migrate-apiserver-lb-to-internal"
+
+// SkipImageReplicationCheckAnnotation can be set to "true" on an AzureMachine or AzureMachinePool to create its VMs
+// without waiting for its compute gallery image version to be replicated to the location of the cluster.
+const SkipImageReplicationCheckAnnotation = "infrastructure.cluster.x-k8s.io/skip-image-replication-check"
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -77,6 +78,33 @@ type AzureClusterClassSpec struct {
 	// See: https://learn.microsoft.com/azure/reliability/availability-zones-overview
 	// +optional
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+
+	// DefaultImage is an optional Azure Compute Gallery image definition used for Linux machines in the cluster
+	// that don't specify an image, in place of the reference images from the Azure Marketplace.
+	// +optional
+	DefaultImage *DefaultImage `json:"defaultImage,omitempty"`
+
+	// VMExtensions is a list of extensions installed on the virtual machines of all the AzureMachines and
+	// AzureMachinePools in the cluster, including the ones created before the extension was added. An extension of a
+	// machine with the same name takes precedence. Extensions removed from the list are uninstalled.
+	// +optional
+	VMExtensions []ClusterVMExtension `json:"vmExtensions,omitempty"`
+
+	// NamingTemplate overrides the names CAPZ generates for some of the Azure resources of the cluster and of its
+	// AzureMachines. It is immutable, so that the resources of existing clusters keep the names they were created with.
+	// +optional
+	NamingTemplate *NamingTemplate `json:"namingTemplate,omitempty"`
+}
+
+// ClusterVMExtension is a VM extension installed on the virtual machines of a cluster.
+type ClusterVMExtension struct {
+	VMExtension `json:",inline"`
+
+	// OSType restricts the extension to the machines with this operating system, e.g. for extensions only published
+	// for Linux. If unset, the extension is installed on all machines.
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +optional
+	OSType string `json:"osType,omitempty"`
 }
 
 // AzureManagedControlPlaneClassSpec defines the AzureManagedControlPlane properties that may be shared across several azure managed control planes.
@@ -134,12 +162,23 @@ type AzureManagedControlPlaneClassSpec struct {
 	// +optional
 	OutboundType *ManagedControlPlaneOutboundType `json:"outboundType,omitempty"`
 
+	// NatGatewayProfile is the profile of the NAT gateway AKS creates for the cluster.
+	// It may only be set when OutboundType is managedNATGateway.
+	// +optional
+	NatGatewayProfile *NatGatewayProfile `json:"natGatewayProfile,omitempty"`
+
 	// DNSServiceIP is an IP address assigned to the Kubernetes DNS service.
 	// It must be within the Kubernetes service address range specified in serviceCidr.
 	// Immutable.
 	// +optional
 	DNSServiceIP *string `json:"dnsServiceIP,omitempty"`
 
+	// DockerBridgeCIDR is a CIDR notation IP range assigned to the Docker bridge network.
+	// Deprecated: AKS API versions from 2023-10-01 removed the Docker bridge network, this field is ignored and will
+	// be removed in a future API version.
+	// +optional
+	DockerBridgeCIDR *string `json:"dockerBridgeCidr,omitempty"`
+
 	// LoadBalancerSKU is the SKU of the loadBalancer to be provisioned.
 	// Immutable.
 	// +kubebuilder:validation:Enum=Basic;Standard
@@ -147,8 +186,11 @@ type AzureManagedControlPlaneClassSpec struct {
 	// +optional
 	LoadBalancerSKU *string `json:"loadBalancerSKU,omitempty"`
 
-	// IdentityRef is a reference to a AzureClusterIdentity to be used when reconciling this cluster
-	IdentityRef *corev1.ObjectReference `json:"identityRef"`
+	// IdentityRef is a reference to a AzureClusterIdentity to be used when reconciling this cluster.
+	// It may be left unset on an AzureManagedControlPlaneTemplate and supplied per cluster instead,
+	// but an AzureManagedControlPlane cannot be created without one.
+	// +optional
+	IdentityRef *corev1.ObjectReference `json:"identityRef,omitempty"`
 
 	// AadProfile is Azure Active Directory configuration to integrate with AKS for aad authentication.
 	// +optional
@@ -235,6 +277,66 @@ type AzureManagedControlPlaneClassSpec struct {
 	// SecurityProfile defines the security profile for cluster.
 	// +optional
 	SecurityProfile *ManagedClusterSecurityProfile `json:"securityProfile,omitempty"`
+
+	// MaintenanceWindow defines when AKS is allowed to perform planned maintenance, like upgrades and node image
+	// updates, on the cluster. Removing it deletes the maintenance configuration of the cluster.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/planned-maintenance
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// WindowsProfile configures the Windows nodes of the cluster.
+	// +optional
+	WindowsProfile *ManagedClusterWindowsProfile `json:"windowsProfile,omitempty"`
+
+	// PodIdentityProfile is the AAD pod identity profile of the managed cluster.
+	// AAD pod identity is deprecated, use workload identity instead when possible.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/use-azure-ad-pod-identity
+	// +optional
+	PodIdentityProfile *PodIdentityProfile `json:"podIdentityProfile,omitempty"`
+
+	// AutoGrantSubnetPermissions grants the identity of the control plane the Network Contributor role on the subnet
+	// of the cluster, which AKS requires to manage the load balancers and IPs of a cluster in a bring-your-own
+	// virtual network. The role is granted before the managed cluster is created to a user-assigned identity, or
+	// once the managed cluster is created to a system-assigned identity, and is removed with the cluster.
+	// +optional
+	AutoGrantSubnetPermissions *bool `json:"autoGrantSubnetPermissions,omitempty"`
+}
+
+// MaintenanceWindow defines the times in which AKS may perform planned maintenance on a managed cluster.
+type MaintenanceWindow struct {
+	// AllowedTimes are the days of the week and the hours of those days in which maintenance is allowed.
+	// Each continuous window must be at least 4 hours long.
+	// +optional
+	AllowedTimes []MaintenanceTimeInWeek `json:"allowedTimes,omitempty"`
+
+	// NotAllowedTimes are the time ranges in which maintenance is not allowed, e.g. during a release freeze.
+	// +optional
+	NotAllowedTimes []MaintenanceTimeSpan `json:"notAllowedTimes,omitempty"`
+}
+
+// MaintenanceTimeInWeek defines the hours of a day of the week in which maintenance is allowed.
+type MaintenanceTimeInWeek struct {
+	// Day is the day of the week.
+	// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+	Day string `json:"day"`
+
+	// HourSlots are the hours of the day, in UTC, in which maintenance is allowed. Each hour slot is one hour long,
+	// e.g. 2 allows maintenance between 02:00 and 03:00.
+	// +kubebuilder:validation:MinItems=1
+	HourSlots []int32 `json:"hourSlots"`
+}
+
+// MaintenanceTimeSpan defines a time range.
+type MaintenanceTimeSpan struct {
+	// Start is the start of the time range.
+	Start metav1.Time `json:"start"`
+
+	// End is the end of the time range.
+	End metav1.Time `json:"end"`
 }
 
 // ManagedClusterAutoUpgradeProfile defines the auto upgrade profile for a managed cluster.
@@ -243,6 +345,11 @@ type ManagedClusterAutoUpgradeProfile struct {
 	// +kubebuilder:validation:Enum=node-image;none;patch;rapid;stable
 	// +optional
 	UpgradeChannel *UpgradeChannel `json:"upgradeChannel,omitempty"`
+
+	// NodeOSUpgradeChannel determines the manner in which the OS on the nodes is updated. AKS defaults it to NodeImage.
+	// +kubebuilder:validation:Enum=NodeImage;None;Unmanaged
+	// +optional
+	NodeOSUpgradeChannel *NodeOSUpgradeChannel `json:"nodeOSUpgradeChannel,omitempty"`
 }
 
 // AzureManagedMachinePoolClassSpec defines the AzureManagedMachinePool properties that may be shared across several Azure managed machinepools.
@@ -266,7 +373,8 @@ type AzureManagedMachinePoolClassSpec struct {
 	SKU string `json:"sku"`
 
 	// OSDiskSizeGB is the disk size for every machine in this agent pool.
-	// If you specify 0, it will apply the default osDisk size according to the vmSize specified.
+	// If you specify 0, it will apply the default osDisk size according to the vmSize specified, which for an
+	// Ephemeral osDiskType is the largest size that fits in the VM's cache or temp disk.
 	// Immutable.
 	// +optional
 	OSDiskSizeGB *int `json:"osDiskSizeGB,omitempty"`
@@ -328,6 +436,17 @@ type AzureManagedMachinePoolClassSpec struct {
 	// +optional
 	OSType *string `json:"osType,omitempty"`
 
+	// OSSKU specifies the node image of the pool. Possible values are 'AzureLinux', 'CBLMariner' and 'Ubuntu' when
+	// OSType is 'Linux', and 'Windows2019' and 'Windows2022' when OSType is 'Windows'. Defaults to the default image of
+	// the OSType.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/rest/api/aks/agent-pools/create-or-update?tabs=HTTP#ossku
+	// +kubebuilder:validation:Enum=AzureLinux;CBLMariner;Ubuntu;Windows2019;Windows2022
+	// +optional
+	OSSKU *string `json:"osSKU,omitempty"`
+
 	// EnableNodePublicIP controls whether or not nodes in the pool each have a public IP address.
 	// Immutable.
 	// +optional
@@ -338,6 +457,10 @@ type AzureManagedMachinePoolClassSpec struct {
 	// +optional
 	NodePublicIPPrefixID *string `json:"nodePublicIPPrefixID,omitempty"`
 
+	// NetworkProfile specifies the network settings of the nodes of the pool.
+	// +optional
+	NetworkProfile *AgentPoolNetworkProfile `json:"networkProfile,omitempty"`
+
 	// ScaleSetPriority specifies the ScaleSetPriority value. Default to Regular. Possible values include: 'Regular', 'Spot'
 	// Immutable.
 	// +kubebuilder:validation:Enum=Regular;Spot
@@ -381,6 +504,16 @@ type AzureManagedMachinePoolClassSpec struct {
 	// +optional
 	SubnetName *string `json:"subnetName,omitempty"`
 
+	// PodSubnetName specifies the subnet the pods of the MachinePool get their IPs from with Azure CNI dynamic IP
+	// allocation. It is either the name of one of the AzureManagedControlPlane's VirtualNetwork.Subnets, or the
+	// resource ID of an existing subnet delegated to Microsoft.ContainerService/managedClusters.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/configure-azure-cni-dynamic-ip-allocation
+	// +optional
+	PodSubnetName *string `json:"podSubnetName,omitempty"`
+
 	// EnableFIPS indicates whether FIPS is enabled on the node pool.
 	// Immutable.
 	// +optional
@@ -393,6 +526,33 @@ type AzureManagedMachinePoolClassSpec struct {
 	// [AKS doc]: https://learn.microsoft.com/en-us/azure/aks/enable-host-encryption
 	// +optional
 	EnableEncryptionAtHost *bool `json:"enableEncryptionAtHost,omitempty"`
+
+	// ProximityPlacementGroupID specifies the resource ID of the proximity placement group the nodes of the pool are
+	// placed in, to reduce the latency between them.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/reduce-latency-ppg
+	// +optional
+	ProximityPlacementGroupID *string `json:"proximityPlacementGroupID,omitempty"`
+
+	// CapacityReservationGroupID specifies the resource ID of the capacity reservation group the nodes of the pool
+	// consume reserved capacity from.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/manage-node-pools#associate-capacity-reservation-groups-to-node-pools
+	// +optional
+	CapacityReservationGroupID *string `json:"capacityReservationGroupID,omitempty"`
+
+	// NodePoolSnapshotID specifies the resource ID of the node pool snapshot the pool is created from, to use the same
+	// node image as the snapshotted pool. It is only honored when the pool is created.
+	// Immutable.
+	// See also [AKS doc].
+	//
+	// [AKS doc]: https://learn.microsoft.com/azure/aks/node-pool-snapshot
+	// +optional
+	NodePoolSnapshotID *string `json:"nodePoolSnapshotID,omitempty"`
 }
 
 // ManagedControlPlaneVirtualNetworkClassSpec defines the ManagedControlPlaneVirtualNetwork properties that may be shared across several managed control plane vnets.
@@ -401,6 +561,14 @@ type ManagedControlPlaneVirtualNetworkClassSpec struct {
 	CIDRBlock string `json:"cidrBlock"`
 	// +optional
 	Subnet ManagedControlPlaneSubnet `json:"subnet,omitempty"`
+
+	// Subnets are additional subnets of the virtual network, which node pools can use as node subnet with SubnetName
+	// or as pod subnet with PodSubnetName. CAPZ creates them when it manages the virtual network, and delegates the
+	// pod subnets to AKS.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Subnets []ManagedControlPlaneAdditionalSubnet `json:"subnets,omitempty"`
 }
 
 // APIServerAccessProfileClassSpec defines the APIServerAccessProfile properties that may be shared across several API server access profiles.
@@ -434,6 +602,20 @@ type NetworkClassSpec struct {
 	// PrivateDNSZoneName defines the zone name for the Azure Private DNS.
 	// +optional
 	PrivateDNSZoneName string `json:"privateDNSZoneName,omitempty"`
+
+	// PrivateDNSZone defines additional configuration for the Azure Private DNS zone of a private cluster.
+	// +optional
+	PrivateDNSZone *PrivateDNSZone `json:"privateDNSZone,omitempty"`
+}
+
+// PrivateDNSZone defines additional configuration for the Azure Private DNS zone.
+type PrivateDNSZone struct {
+	// AdditionalVnetLinks is a list of Azure resource IDs of virtual networks, in addition to the cluster virtual
+	// network and its peerings, that should be linked to the private DNS zone so they can resolve the API server.
+	// The virtual networks may live in a different subscription as long as the cluster identity is allowed to
+	// link them. Links that are removed from the list are deleted.
+	// +optional
+	AdditionalVnetLinks []string `json:"additionalVnetLinks,omitempty"`
 }
 
 // VnetClassSpec defines the VnetSpec properties that may be shared across several Azure clusters.
@@ -445,6 +627,14 @@ type VnetClassSpec struct {
 	// Tags is a collection of tags describing the resource.
 	// +optional
 	Tags Tags `json:"tags,omitempty"`
+
+	// SubnetPrefixLength is the prefix length of the subnet CIDR blocks generated from the first IPv4 CIDR block of
+	// the virtual network for subnets that don't specify CIDR blocks. Defaults to 8 bits longer than the prefix of the
+	// virtual network, up to 29, e.g. /16 subnets in a /8 virtual network.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=29
+	// +optional
+	SubnetPrefixLength *int32 `json:"subnetPrefixLength,omitempty"`
 }
 
 // SubnetClassSpec defines the SubnetSpec properties that may be shared across several Azure clusters.
@@ -460,6 +650,14 @@ type SubnetClassSpec struct {
 	// +optional
 	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
 
+	// IPAMPoolRef is a reference to a Cluster API IPAM pool to allocate the subnet's address space from when
+	// CIDRBlocks is empty. The AzureCluster controller claims an address from the pool with an IPAddressClaim and
+	// writes the address space starting at the claimed address to CIDRBlocks before creating the subnet. The address
+	// space has the vnet's subnetPrefixLength for IPv4 and a /64 prefix for IPv6, so the pool must only contain
+	// addresses aligned on that prefix length.
+	// +optional
+	IPAMPoolRef *corev1.TypedLocalObjectReference `json:"ipamPoolRef,omitempty"`
+
 	// ServiceEndpoints is a slice of Virtual Network service endpoints to enable for the subnets.
 	// +optional
 	ServiceEndpoints ServiceEndpoints `json:"serviceEndpoints,omitempty"`
@@ -478,6 +676,79 @@ type LoadBalancerClassSpec struct {
 	// IdleTimeoutInMinutes specifies the timeout for the TCP idle connection.
 	// +optional
 	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+	// HealthProbe configures the health probe of the API server load balancing rule.
+	// It can only be set on the API server load balancer.
+	// +optional
+	HealthProbe *LoadBalancerHealthProbe `json:"healthProbe,omitempty"`
+	// AdditionalAPIServerLBPorts are additional ports exposed by the API server load balancer, e.g. for konnectivity.
+	// Each port gets a load balancing rule and a TCP health probe reusing the frontend IP and backend pool of the API
+	// server, and a security rule allowing it on the control plane subnet.
+	// It can only be set on the API server load balancer.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalAPIServerLBPorts []LoadBalancerPort `json:"additionalAPIServerLBPorts,omitempty"`
+	// Disabled prevents the creation of the load balancer. It allows clusters with an internal API server load
+	// balancer to configure the egress of the control plane machines through a NAT gateway or user-defined routes on
+	// the control plane subnet instead.
+	// It can only be set on the control plane outbound load balancer.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// LoadBalancerPort defines an additional port exposed by a load balancer.
+type LoadBalancerPort struct {
+	// Name is the name of the port. It is used to name the load balancing rule, health probe and security rule.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Name string `json:"name"`
+	// Port is the frontend port of the load balancer.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// TargetPort is the backend port on the control plane machines. Defaults to Port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TargetPort *int32 `json:"targetPort,omitempty"`
+}
+
+// ProbeProtocol defines the protocol of a load balancer health probe.
+type ProbeProtocol string
+
+const (
+	// ProbeProtocolTCP probes the backend by opening a TCP connection.
+	ProbeProtocolTCP = ProbeProtocol("Tcp")
+	// ProbeProtocolHTTPS probes the backend with an HTTPS request.
+	ProbeProtocolHTTPS = ProbeProtocol("Https")
+)
+
+// LoadBalancerHealthProbe defines the health probe of a load balancing rule.
+type LoadBalancerHealthProbe struct {
+	// Protocol is the protocol of the health probe. Defaults to Https.
+	// +kubebuilder:validation:Enum=Tcp;Https
+	// +optional
+	Protocol ProbeProtocol `json:"protocol,omitempty"`
+	// Port is the port the health probe connects to. It must match the backend port of the load balancing rule,
+	// which is the API server port of the cluster. Defaults to the API server port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// RequestPath is the URI requested to get the health status of the backend. It can only be set when the protocol
+	// is Https. Defaults to /readyz.
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+	// IntervalInSeconds is the interval between two health probes. Defaults to 15.
+	// +kubebuilder:validation:Minimum=5
+	// +optional
+	IntervalInSeconds *int32 `json:"intervalInSeconds,omitempty"`
+	// NumberOfProbes is the number of consecutive failed probes after which a backend is taken out of rotation.
+	// Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumberOfProbes *int32 `json:"numberOfProbes,omitempty"`
 }
 
 // FleetsMemberClassSpec defines the FleetsMemberSpec properties that may be shared across several Azure clusters.
@@ -523,7 +794,11 @@ func (vc *VnetClassSpec) setDefaults() {
 
 // setDefaults sets default values for SubnetClassSpec.
 func (sc *SubnetClassSpec) setDefaults(cidr string) {
-	if len(sc.CIDRBlocks) == 0 {
+	// The address space of the subnet is allocated from the IPAM pool.
+	if sc.IPAMPoolRef != nil {
+		return
+	}
+	if len(sc.CIDRBlocks) == 0 && cidr != "" {
 		sc.CIDRBlocks = []string{cidr}
 	}
 }
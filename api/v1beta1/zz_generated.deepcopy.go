@@ -48,6 +48,26 @@ func (in *AADProfile) DeepCopy() *AADProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AADProfileStatus) DeepCopyInto(out *AADProfileStatus) {
+	*out = *in
+	if in.AdminGroupObjectIDs != nil {
+		in, out := &in.AdminGroupObjectIDs, &out.AdminGroupObjectIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AADProfileStatus.
+func (in *AADProfileStatus) DeepCopy() *AADProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AADProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AKSExtension) DeepCopyInto(out *AKSExtension) {
 	*out = *in
@@ -166,6 +186,50 @@ func (in *APIServerAccessProfileClassSpec) DeepCopy() *APIServerAccessProfileCla
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerDNS) DeepCopyInto(out *APIServerDNS) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerDNS.
+func (in *APIServerDNS) DeepCopy() *APIServerDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerLBMigration) DeepCopyInto(out *APIServerLBMigration) {
+	*out = *in
+	if in.PublicIPNames != nil {
+		in, out := &in.PublicIPNames, &out.PublicIPNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EndpointSwitchTime != nil {
+		in, out := &in.EndpointSwitchTime, &out.EndpointSwitchTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerLBMigration.
+func (in *APIServerLBMigration) DeepCopy() *APIServerLBMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerLBMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 	*out = *in
@@ -174,6 +238,11 @@ func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HibernationEnabled != nil {
+		in, out := &in.HibernationEnabled, &out.HibernationEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalCapabilities.
@@ -223,6 +292,28 @@ func (in *AddressRecord) DeepCopy() *AddressRecord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentPoolNetworkProfile) DeepCopyInto(out *AgentPoolNetworkProfile) {
+	*out = *in
+	if in.NodePublicIPTags != nil {
+		in, out := &in.NodePublicIPTags, &out.NodePublicIPTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentPoolNetworkProfile.
+func (in *AgentPoolNetworkProfile) DeepCopy() *AgentPoolNetworkProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentPoolNetworkProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AllowedNamespaces) DeepCopyInto(out *AllowedNamespaces) {
 	*out = *in
@@ -440,6 +531,23 @@ func (in *AzureClusterClassSpec) DeepCopyInto(out *AzureClusterClassSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.DefaultImage != nil {
+		in, out := &in.DefaultImage, &out.DefaultImage
+		*out = new(DefaultImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]ClusterVMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamingTemplate != nil {
+		in, out := &in.NamingTemplate, &out.NamingTemplate
+		*out = new(NamingTemplate)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterClassSpec.
@@ -520,6 +628,11 @@ func (in *AzureClusterIdentitySpec) DeepCopyInto(out *AzureClusterIdentitySpec)
 		*out = new(AllowedNamespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FallbackIdentityRef != nil {
+		in, out := &in.FallbackIdentityRef, &out.FallbackIdentityRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentitySpec.
@@ -593,6 +706,16 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	in.BastionSpec.DeepCopyInto(&out.BastionSpec)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.APIServerDNS != nil {
+		in, out := &in.APIServerDNS, &out.APIServerDNS
+		*out = new(APIServerDNS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceLocks != nil {
+		in, out := &in.ResourceLocks, &out.ResourceLocks
+		*out = new(ResourceLocks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterSpec.
@@ -627,6 +750,31 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = new(ManagedResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerLBMigration != nil {
+		in, out := &in.APIServerLBMigration, &out.APIServerLBMigration
+		*out = new(APIServerLBMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutboundIPs != nil {
+		in, out := &in.OutboundIPs, &out.OutboundIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReconcileBackoff != nil {
+		in, out := &in.ReconcileBackoff, &out.ReconcileBackoff
+		*out = new(ReconcileBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.V1Beta2 != nil {
+		in, out := &in.V1Beta2, &out.V1Beta2
+		*out = new(V1Beta2Status)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -890,6 +1038,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		**out = **in
 	}
 	in.OSDisk.DeepCopyInto(&out.OSDisk)
+	if in.PatchSettings != nil {
+		in, out := &in.PatchSettings, &out.PatchSettings
+		*out = new(PatchSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
 		*out = make([]DataDisk, len(*in))
@@ -909,6 +1062,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(AdditionalCapabilities)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProvisioningTimeout != nil {
+		in, out := &in.ProvisioningTimeout, &out.ProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.AcceleratedNetworking != nil {
 		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
 		*out = new(bool)
@@ -995,6 +1153,29 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = new(ManagedResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VMCreationTime != nil {
+		in, out := &in.VMCreationTime, &out.VMCreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBootTime != nil {
+		in, out := &in.LastBootTime, &out.LastBootTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReconcileBackoff != nil {
+		in, out := &in.ReconcileBackoff, &out.ReconcileBackoff
+		*out = new(ReconcileBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.V1Beta2 != nil {
+		in, out := &in.V1Beta2, &out.V1Beta2
+		*out = new(V1Beta2Status)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineStatus.
@@ -1361,11 +1542,21 @@ func (in *AzureManagedControlPlaneClassSpec) DeepCopyInto(out *AzureManagedContr
 		*out = new(ManagedControlPlaneOutboundType)
 		**out = **in
 	}
+	if in.NatGatewayProfile != nil {
+		in, out := &in.NatGatewayProfile, &out.NatGatewayProfile
+		*out = new(NatGatewayProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DNSServiceIP != nil {
 		in, out := &in.DNSServiceIP, &out.DNSServiceIP
 		*out = new(string)
 		**out = **in
 	}
+	if in.DockerBridgeCIDR != nil {
+		in, out := &in.DockerBridgeCIDR, &out.DockerBridgeCIDR
+		*out = new(string)
+		**out = **in
+	}
 	if in.LoadBalancerSKU != nil {
 		in, out := &in.LoadBalancerSKU, &out.LoadBalancerSKU
 		*out = new(string)
@@ -1450,6 +1641,26 @@ func (in *AzureManagedControlPlaneClassSpec) DeepCopyInto(out *AzureManagedContr
 		*out = new(ManagedClusterSecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WindowsProfile != nil {
+		in, out := &in.WindowsProfile, &out.WindowsProfile
+		*out = new(ManagedClusterWindowsProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodIdentityProfile != nil {
+		in, out := &in.PodIdentityProfile, &out.PodIdentityProfile
+		*out = new(PodIdentityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoGrantSubnetPermissions != nil {
+		in, out := &in.AutoGrantSubnetPermissions, &out.AutoGrantSubnetPermissions
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneClassSpec.
@@ -1514,6 +1725,11 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(FleetsMember)
 		**out = **in
 	}
+	if in.APIServerDNS != nil {
+		in, out := &in.APIServerDNS, &out.APIServerDNS
+		*out = new(APIServerDNS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneSpec.
@@ -1546,6 +1762,26 @@ func (in *AzureManagedControlPlaneStatus) DeepCopyInto(out *AzureManagedControlP
 		*out = new(OIDCIssuerProfileStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AADProfile != nil {
+		in, out := &in.AADProfile, &out.AADProfile
+		*out = new(AADProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutboundIPs != nil {
+		in, out := &in.OutboundIPs, &out.OutboundIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = new(ManagedResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.V1Beta2 != nil {
+		in, out := &in.V1Beta2, &out.V1Beta2
+		*out = new(V1Beta2Status)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneStatus.
@@ -1768,6 +2004,11 @@ func (in *AzureManagedMachinePoolClassSpec) DeepCopyInto(out *AzureManagedMachin
 		*out = new(string)
 		**out = **in
 	}
+	if in.OSSKU != nil {
+		in, out := &in.OSSKU, &out.OSSKU
+		*out = new(string)
+		**out = **in
+	}
 	if in.EnableNodePublicIP != nil {
 		in, out := &in.EnableNodePublicIP, &out.EnableNodePublicIP
 		*out = new(bool)
@@ -1778,6 +2019,11 @@ func (in *AzureManagedMachinePoolClassSpec) DeepCopyInto(out *AzureManagedMachin
 		*out = new(string)
 		**out = **in
 	}
+	if in.NetworkProfile != nil {
+		in, out := &in.NetworkProfile, &out.NetworkProfile
+		*out = new(AgentPoolNetworkProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ScaleSetPriority != nil {
 		in, out := &in.ScaleSetPriority, &out.ScaleSetPriority
 		*out = new(string)
@@ -1813,6 +2059,11 @@ func (in *AzureManagedMachinePoolClassSpec) DeepCopyInto(out *AzureManagedMachin
 		*out = new(string)
 		**out = **in
 	}
+	if in.PodSubnetName != nil {
+		in, out := &in.PodSubnetName, &out.PodSubnetName
+		*out = new(string)
+		**out = **in
+	}
 	if in.EnableFIPS != nil {
 		in, out := &in.EnableFIPS, &out.EnableFIPS
 		*out = new(bool)
@@ -1823,6 +2074,21 @@ func (in *AzureManagedMachinePoolClassSpec) DeepCopyInto(out *AzureManagedMachin
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ProximityPlacementGroupID != nil {
+		in, out := &in.ProximityPlacementGroupID, &out.ProximityPlacementGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CapacityReservationGroupID != nil {
+		in, out := &in.CapacityReservationGroupID, &out.CapacityReservationGroupID
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodePoolSnapshotID != nil {
+		in, out := &in.NodePoolSnapshotID, &out.NodePoolSnapshotID
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedMachinePoolClassSpec.
@@ -1891,6 +2157,21 @@ func (in *AzureManagedMachinePoolSpec) DeepCopy() *AzureManagedMachinePoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedMachinePoolStatus) DeepCopyInto(out *AzureManagedMachinePoolStatus) {
 	*out = *in
+	if in.ScaleDownMode != nil {
+		in, out := &in.ScaleDownMode, &out.ScaleDownMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeImageVersion != nil {
+		in, out := &in.NodeImageVersion, &out.NodeImageVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.SpotEvictions != nil {
+		in, out := &in.SpotEvictions, &out.SpotEvictions
+		*out = new(SpotEvictionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ErrorReason != nil {
 		in, out := &in.ErrorReason, &out.ErrorReason
 		*out = new(errors.MachineStatusError)
@@ -2232,6 +2513,37 @@ func (in *CloudProviderConfigOverrides) DeepCopy() *CloudProviderConfigOverrides
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVMExtension) DeepCopyInto(out *ClusterVMExtension) {
+	*out = *in
+	in.VMExtension.DeepCopyInto(&out.VMExtension)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVMExtension.
+func (in *ClusterVMExtension) DeepCopy() *ClusterVMExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVMExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CountedEvictionEvent) DeepCopyInto(out *CountedEvictionEvent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CountedEvictionEvent.
+func (in *CountedEvictionEvent) DeepCopy() *CountedEvictionEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(CountedEvictionEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 	*out = *in
@@ -2257,6 +2569,31 @@ func (in *DataDisk) DeepCopy() *DataDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultImage) DeepCopyInto(out *DefaultImage) {
+	*out = *in
+	if in.SubscriptionID != nil {
+		in, out := &in.SubscriptionID, &out.SubscriptionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceGroup != nil {
+		in, out := &in.ResourceGroup, &out.ResourceGroup
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultImage.
+func (in *DefaultImage) DeepCopy() *DefaultImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
 	*out = *in
@@ -2391,6 +2728,11 @@ func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
 		*out = new(PublicIPSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.FrontendIPClass = in.FrontendIPClass
 }
 
@@ -2681,6 +3023,18 @@ func (in *LoadBalancerClassSpec) DeepCopyInto(out *LoadBalancerClassSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(LoadBalancerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalAPIServerLBPorts != nil {
+		in, out := &in.AdditionalAPIServerLBPorts, &out.AdditionalAPIServerLBPorts
+		*out = make([]LoadBalancerPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerClassSpec.
@@ -2693,6 +3047,56 @@ func (in *LoadBalancerClassSpec) DeepCopy() *LoadBalancerClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthProbe) DeepCopyInto(out *LoadBalancerHealthProbe) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IntervalInSeconds != nil {
+		in, out := &in.IntervalInSeconds, &out.IntervalInSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumberOfProbes != nil {
+		in, out := &in.NumberOfProbes, &out.NumberOfProbes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthProbe.
+func (in *LoadBalancerHealthProbe) DeepCopy() *LoadBalancerHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerPort) DeepCopyInto(out *LoadBalancerPort) {
+	*out = *in
+	if in.TargetPort != nil {
+		in, out := &in.TargetPort, &out.TargetPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerPort.
+func (in *LoadBalancerPort) DeepCopy() *LoadBalancerPort {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
 	*out = *in
@@ -2763,17 +3167,88 @@ func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedClusterAutoUpgradeProfile) DeepCopyInto(out *ManagedClusterAutoUpgradeProfile) {
+func (in *MaintenanceTimeInWeek) DeepCopyInto(out *MaintenanceTimeInWeek) {
 	*out = *in
-	if in.UpgradeChannel != nil {
-		in, out := &in.UpgradeChannel, &out.UpgradeChannel
-		*out = new(UpgradeChannel)
-		**out = **in
+	if in.HourSlots != nil {
+		in, out := &in.HourSlots, &out.HourSlots
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterAutoUpgradeProfile.
-func (in *ManagedClusterAutoUpgradeProfile) DeepCopy() *ManagedClusterAutoUpgradeProfile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceTimeInWeek.
+func (in *MaintenanceTimeInWeek) DeepCopy() *MaintenanceTimeInWeek {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceTimeInWeek)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceTimeSpan) DeepCopyInto(out *MaintenanceTimeSpan) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceTimeSpan.
+func (in *MaintenanceTimeSpan) DeepCopy() *MaintenanceTimeSpan {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceTimeSpan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.AllowedTimes != nil {
+		in, out := &in.AllowedTimes, &out.AllowedTimes
+		*out = make([]MaintenanceTimeInWeek, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NotAllowedTimes != nil {
+		in, out := &in.NotAllowedTimes, &out.NotAllowedTimes
+		*out = make([]MaintenanceTimeSpan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterAutoUpgradeProfile) DeepCopyInto(out *ManagedClusterAutoUpgradeProfile) {
+	*out = *in
+	if in.UpgradeChannel != nil {
+		in, out := &in.UpgradeChannel, &out.UpgradeChannel
+		*out = new(UpgradeChannel)
+		**out = **in
+	}
+	if in.NodeOSUpgradeChannel != nil {
+		in, out := &in.NodeOSUpgradeChannel, &out.NodeOSUpgradeChannel
+		*out = new(NodeOSUpgradeChannel)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterAutoUpgradeProfile.
+func (in *ManagedClusterAutoUpgradeProfile) DeepCopy() *ManagedClusterAutoUpgradeProfile {
 	if in == nil {
 		return nil
 	}
@@ -2883,6 +3358,46 @@ func (in *ManagedClusterSecurityProfileWorkloadIdentity) DeepCopy() *ManagedClus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterWindowsProfile) DeepCopyInto(out *ManagedClusterWindowsProfile) {
+	*out = *in
+	if in.GmsaProfile != nil {
+		in, out := &in.GmsaProfile, &out.GmsaProfile
+		*out = new(WindowsGmsaProfile)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterWindowsProfile.
+func (in *ManagedClusterWindowsProfile) DeepCopy() *ManagedClusterWindowsProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterWindowsProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneAdditionalSubnet) DeepCopyInto(out *ManagedControlPlaneAdditionalSubnet) {
+	*out = *in
+	if in.Delegations != nil {
+		in, out := &in.Delegations, &out.Delegations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneAdditionalSubnet.
+func (in *ManagedControlPlaneAdditionalSubnet) DeepCopy() *ManagedControlPlaneAdditionalSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedControlPlaneAdditionalSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
 	*out = *in
@@ -2932,6 +3447,13 @@ func (in *ManagedControlPlaneVirtualNetwork) DeepCopy() *ManagedControlPlaneVirt
 func (in *ManagedControlPlaneVirtualNetworkClassSpec) DeepCopyInto(out *ManagedControlPlaneVirtualNetworkClassSpec) {
 	*out = *in
 	in.Subnet.DeepCopyInto(&out.Subnet)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]ManagedControlPlaneAdditionalSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneVirtualNetworkClassSpec.
@@ -2994,11 +3516,53 @@ func (in *ManagedMachinePoolScaling) DeepCopy() *ManagedMachinePoolScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedResources) DeepCopyInto(out *ManagedResources) {
+	*out = *in
+	if in.IDs != nil {
+		in, out := &in.IDs, &out.IDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deleting != nil {
+		in, out := &in.Deleting, &out.Deleting
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedResources.
+func (in *ManagedResources) DeepCopy() *ManagedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamingTemplate) DeepCopyInto(out *NamingTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamingTemplate.
+func (in *NamingTemplate) DeepCopy() *NamingTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NamingTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NatGateway) DeepCopyInto(out *NatGateway) {
 	*out = *in
 	in.NatGatewayIP.DeepCopyInto(&out.NatGatewayIP)
-	out.NatGatewayClassSpec = in.NatGatewayClassSpec
+	in.NatGatewayClassSpec.DeepCopyInto(&out.NatGatewayClassSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGateway.
@@ -3014,6 +3578,16 @@ func (in *NatGateway) DeepCopy() *NatGateway {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NatGatewayClassSpec) DeepCopyInto(out *NatGatewayClassSpec) {
 	*out = *in
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = new(string)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGatewayClassSpec.
@@ -3026,9 +3600,39 @@ func (in *NatGatewayClassSpec) DeepCopy() *NatGatewayClassSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGatewayProfile) DeepCopyInto(out *NatGatewayProfile) {
+	*out = *in
+	if in.ManagedOutboundIPCount != nil {
+		in, out := &in.ManagedOutboundIPCount, &out.ManagedOutboundIPCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGatewayProfile.
+func (in *NatGatewayProfile) DeepCopy() *NatGatewayProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGatewayProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkClassSpec) DeepCopyInto(out *NetworkClassSpec) {
 	*out = *in
+	if in.PrivateDNSZone != nil {
+		in, out := &in.PrivateDNSZone, &out.PrivateDNSZone
+		*out = new(PrivateDNSZone)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkClassSpec.
@@ -3083,7 +3687,7 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = new(LoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
-	out.NetworkClassSpec = in.NetworkClassSpec
+	in.NetworkClassSpec.DeepCopyInto(&out.NetworkClassSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -3099,7 +3703,7 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkTemplateSpec) DeepCopyInto(out *NetworkTemplateSpec) {
 	*out = *in
-	out.NetworkClassSpec = in.NetworkClassSpec
+	in.NetworkClassSpec.DeepCopyInto(&out.NetworkClassSpec)
 	in.Vnet.DeepCopyInto(&out.Vnet)
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
@@ -3201,6 +3805,95 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchSettings) DeepCopyInto(out *PatchSettings) {
+	*out = *in
+	if in.BypassPlatformSafetyChecksOnUserSchedule != nil {
+		in, out := &in.BypassPlatformSafetyChecksOnUserSchedule, &out.BypassPlatformSafetyChecksOnUserSchedule
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchSettings.
+func (in *PatchSettings) DeepCopy() *PatchSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIdentityException) DeepCopyInto(out *PodIdentityException) {
+	*out = *in
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIdentityException.
+func (in *PodIdentityException) DeepCopy() *PodIdentityException {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIdentityException)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIdentityProfile) DeepCopyInto(out *PodIdentityProfile) {
+	*out = *in
+	if in.AllowNetworkPluginKubenet != nil {
+		in, out := &in.AllowNetworkPluginKubenet, &out.AllowNetworkPluginKubenet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserAssignedIdentityExceptions != nil {
+		in, out := &in.UserAssignedIdentityExceptions, &out.UserAssignedIdentityExceptions
+		*out = make([]PodIdentityException, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIdentityProfile.
+func (in *PodIdentityProfile) DeepCopy() *PodIdentityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIdentityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateDNSZone) DeepCopyInto(out *PrivateDNSZone) {
+	*out = *in
+	if in.AdditionalVnetLinks != nil {
+		in, out := &in.AdditionalVnetLinks, &out.AdditionalVnetLinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateDNSZone.
+func (in *PrivateDNSZone) DeepCopy() *PrivateDNSZone {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateDNSZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrivateEndpointSpec) DeepCopyInto(out *PrivateEndpointSpec) {
 	*out = *in
@@ -3282,6 +3975,11 @@ func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
 		*out = make([]IPTag, len(*in))
 		copy(*out, *in)
 	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPSpec.
@@ -3335,6 +4033,42 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileBackoff) DeepCopyInto(out *ReconcileBackoff) {
+	*out = *in
+	in.NextAttemptTime.DeepCopyInto(&out.NextAttemptTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileBackoff.
+func (in *ReconcileBackoff) DeepCopy() *ReconcileBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceLocks) DeepCopyInto(out *ResourceLocks) {
+	*out = *in
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = make([]ResourceLockScope, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLocks.
+func (in *ResourceLocks) DeepCopy() *ResourceLocks {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceLocks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -3528,6 +4262,30 @@ func (in ServiceEndpoints) DeepCopy() ServiceEndpoints {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotEvictionsStatus) DeepCopyInto(out *SpotEvictionsStatus) {
+	*out = *in
+	if in.LastEvictionTime != nil {
+		in, out := &in.LastEvictionTime, &out.LastEvictionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CountedEvents != nil {
+		in, out := &in.CountedEvents, &out.CountedEvents
+		*out = make([]CountedEvictionEvent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotEvictionsStatus.
+func (in *SpotEvictionsStatus) DeepCopy() *SpotEvictionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotEvictionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
 	*out = *in
@@ -3561,6 +4319,11 @@ func (in *SubnetClassSpec) DeepCopyInto(out *SubnetClassSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IPAMPoolRef != nil {
+		in, out := &in.IPAMPoolRef, &out.IPAMPoolRef
+		*out = new(corev1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServiceEndpoints != nil {
 		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
 		*out = make(ServiceEndpoints, len(*in))
@@ -3611,7 +4374,7 @@ func (in *SubnetTemplateSpec) DeepCopyInto(out *SubnetTemplateSpec) {
 	*out = *in
 	in.SubnetClassSpec.DeepCopyInto(&out.SubnetClassSpec)
 	in.SecurityGroup.DeepCopyInto(&out.SecurityGroup)
-	out.NatGateway = in.NatGateway
+	in.NatGateway.DeepCopyInto(&out.NatGateway)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetTemplateSpec.
@@ -3946,6 +4709,28 @@ func (in *UserManagedBootDiagnostics) DeepCopy() *UserManagedBootDiagnostics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *V1Beta2Status) DeepCopyInto(out *V1Beta2Status) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new V1Beta2Status.
+func (in *V1Beta2Status) DeepCopy() *V1Beta2Status {
+	if in == nil {
+		return nil
+	}
+	out := new(V1Beta2Status)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMDiskSecurityProfile) DeepCopyInto(out *VMDiskSecurityProfile) {
 	*out = *in
@@ -4010,6 +4795,11 @@ func (in *VnetClassSpec) DeepCopyInto(out *VnetClassSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.SubnetPrefixLength != nil {
+		in, out := &in.SubnetPrefixLength, &out.SubnetPrefixLength
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VnetClassSpec.
@@ -4177,3 +4967,28 @@ func (in *VnetTemplateSpec) DeepCopy() *VnetTemplateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WindowsGmsaProfile) DeepCopyInto(out *WindowsGmsaProfile) {
+	*out = *in
+	if in.DNSServer != nil {
+		in, out := &in.DNSServer, &out.DNSServer
+		*out = new(string)
+		**out = **in
+	}
+	if in.RootDomainName != nil {
+		in, out := &in.RootDomainName, &out.RootDomainName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WindowsGmsaProfile.
+func (in *WindowsGmsaProfile) DeepCopy() *WindowsGmsaProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(WindowsGmsaProfile)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -23,6 +23,12 @@ const (
 	// for annotation formatting rules.
 	VMTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vm"
 
+	// VMSSTagsLastAppliedAnnotation is the key for the AzureMachinePool object annotation
+	// which tracks the AdditionalTags applied to the virtual machine scale set.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	VMSSTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-vmss"
+
 	// RGTagsLastAppliedAnnotation is the key for the Azure Cluster object annotation
 	// which tracks the AdditionalTags for Resource Group which is part in the Azure Cluster.
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
@@ -41,9 +47,46 @@ const (
 	// for annotation formatting rules.
 	SecurityRuleLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-security-rules"
 
+	// PrivateDNSLinksLastAppliedAnnotation is the key for the Azure Cluster
+	// object annotation which tracks the virtual network links of the private DNS zone.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	PrivateDNSLinksLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-private-dns-links"
+
+	// InstanceMetadataLabelsLastAppliedAnnotation is the key for the AzureMachinePoolMachine object annotation
+	// which tracks the instance metadata labels applied to the node of the instance.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	InstanceMetadataLabelsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-instance-metadata-labels"
+
+	// ResourceLocksLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the management locks created on the resources of the cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ResourceLocksLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-resource-locks"
+
+	// ClusterVMExtensionsLastAppliedAnnotation is the key for the AzureMachine and AzureMachinePool object annotation
+	// which tracks the VM extensions of the cluster installed on the VMs.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ClusterVMExtensionsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-cluster-vm-extensions"
+
+	// MaintenanceConfigurationLastAppliedAnnotation is the key for the AzureManagedControlPlane object annotation
+	// which tracks the maintenance configuration applied to the managed cluster.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	MaintenanceConfigurationLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-maintenance-configuration"
+
 	// CustomDataHashAnnotation is the key for the machine object annotation
 	// which tracks the hash of the custom data.
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	CustomDataHashAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vmss-custom-data-hash"
+
+	// ProtectedFromScaleInAnnotation is the key for the AzureMachinePoolMachine object annotation which records that
+	// its instance is protected from scale-in, whether the protection was requested on the AzureMachinePoolMachine or
+	// on its Machine.
+	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
+	// for annotation formatting rules.
+	ProtectedFromScaleInAnnotation = "sigs.k8s.io/cluster-api-provider-azure-protected-from-scale-in"
 )
@@ -45,6 +45,7 @@ func AgentPoolToManagedClusterAgentPoolProfile(pool *asocontainerservicev1.Manag
 		EnableUltraSSD:              properties.EnableUltraSSD,
 		EnableNodePublicIP:          properties.EnableNodePublicIP,
 		NodePublicIPPrefixReference: properties.NodePublicIPPrefixReference,
+		NetworkProfile:              properties.NetworkProfile,
 		ScaleSetPriority:            properties.ScaleSetPriority,
 		ScaleDownMode:               properties.ScaleDownMode,
 		SpotMaxPrice:                properties.SpotMaxPrice,
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// GetLinuxPatchSettings converts CAPZ patch settings to Azure SDK Linux patch settings.
+func GetLinuxPatchSettings(patchSettings *infrav1.PatchSettings) *armcompute.LinuxPatchSettings {
+	if patchSettings == nil {
+		return nil
+	}
+
+	linuxPatchSettings := &armcompute.LinuxPatchSettings{}
+	if patchSettings.PatchMode != "" {
+		linuxPatchSettings.PatchMode = ptr.To(armcompute.LinuxVMGuestPatchMode(patchSettings.PatchMode))
+	}
+	if patchSettings.AssessmentMode != "" {
+		linuxPatchSettings.AssessmentMode = ptr.To(armcompute.LinuxPatchAssessmentMode(patchSettings.AssessmentMode))
+	}
+	if patchSettings.BypassPlatformSafetyChecksOnUserSchedule != nil || patchSettings.RebootSetting != "" {
+		linuxPatchSettings.AutomaticByPlatformSettings = &armcompute.LinuxVMGuestPatchAutomaticByPlatformSettings{
+			BypassPlatformSafetyChecksOnUserSchedule: patchSettings.BypassPlatformSafetyChecksOnUserSchedule,
+		}
+		if patchSettings.RebootSetting != "" {
+			linuxPatchSettings.AutomaticByPlatformSettings.RebootSetting = ptr.To(armcompute.LinuxVMGuestPatchAutomaticByPlatformRebootSetting(patchSettings.RebootSetting))
+		}
+	}
+
+	return linuxPatchSettings
+}
+
+// GetWindowsPatchSettings converts CAPZ patch settings to Azure SDK Windows patch settings.
+func GetWindowsPatchSettings(patchSettings *infrav1.PatchSettings) *armcompute.PatchSettings {
+	if patchSettings == nil {
+		return nil
+	}
+
+	windowsPatchSettings := &armcompute.PatchSettings{}
+	if patchSettings.PatchMode != "" {
+		windowsPatchSettings.PatchMode = ptr.To(armcompute.WindowsVMGuestPatchMode(patchSettings.PatchMode))
+	}
+	if patchSettings.AssessmentMode != "" {
+		windowsPatchSettings.AssessmentMode = ptr.To(armcompute.WindowsPatchAssessmentMode(patchSettings.AssessmentMode))
+	}
+	if patchSettings.BypassPlatformSafetyChecksOnUserSchedule != nil || patchSettings.RebootSetting != "" {
+		windowsPatchSettings.AutomaticByPlatformSettings = &armcompute.WindowsVMGuestPatchAutomaticByPlatformSettings{
+			BypassPlatformSafetyChecksOnUserSchedule: patchSettings.BypassPlatformSafetyChecksOnUserSchedule,
+		}
+		if patchSettings.RebootSetting != "" {
+			windowsPatchSettings.AutomaticByPlatformSettings.RebootSetting = ptr.To(armcompute.WindowsVMGuestPatchAutomaticByPlatformRebootSetting(patchSettings.RebootSetting))
+		}
+	}
+
+	return windowsPatchSettings
+}
+
+// IsWindowsAutomaticUpdatesEnabled returns true if the patch mode requires Windows automatic updates to be enabled.
+// Automatic updates are otherwise disabled on CAPZ VMs.
+func IsWindowsAutomaticUpdatesEnabled(patchSettings *infrav1.PatchSettings) bool {
+	if patchSettings == nil {
+		return false
+	}
+	return patchSettings.PatchMode == infrav1.PatchModeAutomaticByOS || patchSettings.PatchMode == infrav1.PatchModeAutomaticByPlatform
+}
+
+// SDKToPatchSettings converts the Linux or Windows patch settings of an Azure SDK scale set OS profile to CAPZ patch
+// settings.
+func SDKToPatchSettings(osProfile *armcompute.VirtualMachineScaleSetOSProfile) *infrav1.PatchSettings {
+	if osProfile == nil {
+		return nil
+	}
+
+	switch {
+	case osProfile.LinuxConfiguration != nil && osProfile.LinuxConfiguration.PatchSettings != nil:
+		linuxPatchSettings := osProfile.LinuxConfiguration.PatchSettings
+		patchSettings := &infrav1.PatchSettings{
+			PatchMode:      infrav1.PatchMode(ptr.Deref(linuxPatchSettings.PatchMode, "")),
+			AssessmentMode: infrav1.PatchAssessmentMode(ptr.Deref(linuxPatchSettings.AssessmentMode, "")),
+		}
+		if linuxPatchSettings.AutomaticByPlatformSettings != nil {
+			patchSettings.BypassPlatformSafetyChecksOnUserSchedule = linuxPatchSettings.AutomaticByPlatformSettings.BypassPlatformSafetyChecksOnUserSchedule
+			patchSettings.RebootSetting = infrav1.PatchRebootSetting(ptr.Deref(linuxPatchSettings.AutomaticByPlatformSettings.RebootSetting, ""))
+		}
+		return patchSettings
+	case osProfile.WindowsConfiguration != nil && osProfile.WindowsConfiguration.PatchSettings != nil:
+		windowsPatchSettings := osProfile.WindowsConfiguration.PatchSettings
+		patchSettings := &infrav1.PatchSettings{
+			PatchMode:      infrav1.PatchMode(ptr.Deref(windowsPatchSettings.PatchMode, "")),
+			AssessmentMode: infrav1.PatchAssessmentMode(ptr.Deref(windowsPatchSettings.AssessmentMode, "")),
+		}
+		if windowsPatchSettings.AutomaticByPlatformSettings != nil {
+			patchSettings.BypassPlatformSafetyChecksOnUserSchedule = windowsPatchSettings.AutomaticByPlatformSettings.BypassPlatformSafetyChecksOnUserSchedule
+			patchSettings.RebootSetting = infrav1.PatchRebootSetting(ptr.Deref(windowsPatchSettings.AutomaticByPlatformSettings.RebootSetting, ""))
+		}
+		return patchSettings
+	}
+
+	return nil
+}
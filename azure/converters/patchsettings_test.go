@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetLinuxPatchSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		patchSettings *infrav1.PatchSettings
+		want          *armcompute.LinuxPatchSettings
+	}{
+		{
+			name: "no patch settings",
+		},
+		{
+			name:          "patch mode only",
+			patchSettings: &infrav1.PatchSettings{PatchMode: infrav1.PatchModeImageDefault},
+			want: &armcompute.LinuxPatchSettings{
+				PatchMode: ptr.To(armcompute.LinuxVMGuestPatchModeImageDefault),
+			},
+		},
+		{
+			name: "AutomaticByPlatform settings",
+			patchSettings: &infrav1.PatchSettings{
+				PatchMode:                                infrav1.PatchModeAutomaticByPlatform,
+				AssessmentMode:                           infrav1.PatchAssessmentModeAutomaticByPlatform,
+				RebootSetting:                            infrav1.PatchRebootSettingIfRequired,
+				BypassPlatformSafetyChecksOnUserSchedule: ptr.To(true),
+			},
+			want: &armcompute.LinuxPatchSettings{
+				PatchMode:      ptr.To(armcompute.LinuxVMGuestPatchModeAutomaticByPlatform),
+				AssessmentMode: ptr.To(armcompute.LinuxPatchAssessmentModeAutomaticByPlatform),
+				AutomaticByPlatformSettings: &armcompute.LinuxVMGuestPatchAutomaticByPlatformSettings{
+					BypassPlatformSafetyChecksOnUserSchedule: ptr.To(true),
+					RebootSetting:                            ptr.To(armcompute.LinuxVMGuestPatchAutomaticByPlatformRebootSettingIfRequired),
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(GetLinuxPatchSettings(tc.patchSettings)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestGetWindowsPatchSettings(t *testing.T) {
+	tests := []struct {
+		name            string
+		patchSettings   *infrav1.PatchSettings
+		want            *armcompute.PatchSettings
+		wantAutoUpdates bool
+	}{
+		{
+			name: "no patch settings",
+		},
+		{
+			name:          "Manual",
+			patchSettings: &infrav1.PatchSettings{PatchMode: infrav1.PatchModeManual},
+			want: &armcompute.PatchSettings{
+				PatchMode: ptr.To(armcompute.WindowsVMGuestPatchModeManual),
+			},
+		},
+		{
+			name:          "AutomaticByOS",
+			patchSettings: &infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByOS},
+			want: &armcompute.PatchSettings{
+				PatchMode: ptr.To(armcompute.WindowsVMGuestPatchModeAutomaticByOS),
+			},
+			wantAutoUpdates: true,
+		},
+		{
+			name: "AutomaticByPlatform settings",
+			patchSettings: &infrav1.PatchSettings{
+				PatchMode:     infrav1.PatchModeAutomaticByPlatform,
+				RebootSetting: infrav1.PatchRebootSettingNever,
+			},
+			want: &armcompute.PatchSettings{
+				PatchMode: ptr.To(armcompute.WindowsVMGuestPatchModeAutomaticByPlatform),
+				AutomaticByPlatformSettings: &armcompute.WindowsVMGuestPatchAutomaticByPlatformSettings{
+					RebootSetting: ptr.To(armcompute.WindowsVMGuestPatchAutomaticByPlatformRebootSettingNever),
+				},
+			},
+			wantAutoUpdates: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(GetWindowsPatchSettings(tc.patchSettings)).To(Equal(tc.want))
+			g.Expect(IsWindowsAutomaticUpdatesEnabled(tc.patchSettings)).To(Equal(tc.wantAutoUpdates))
+		})
+	}
+}
+
+func TestSDKToPatchSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		osProfile *armcompute.VirtualMachineScaleSetOSProfile
+		want      *infrav1.PatchSettings
+	}{
+		{
+			name: "no OS profile",
+		},
+		{
+			name: "no patch settings",
+			osProfile: &armcompute.VirtualMachineScaleSetOSProfile{
+				LinuxConfiguration: &armcompute.LinuxConfiguration{},
+			},
+		},
+		{
+			name: "Linux patch settings",
+			osProfile: &armcompute.VirtualMachineScaleSetOSProfile{
+				LinuxConfiguration: &armcompute.LinuxConfiguration{
+					PatchSettings: &armcompute.LinuxPatchSettings{
+						PatchMode:      ptr.To(armcompute.LinuxVMGuestPatchModeAutomaticByPlatform),
+						AssessmentMode: ptr.To(armcompute.LinuxPatchAssessmentModeImageDefault),
+						AutomaticByPlatformSettings: &armcompute.LinuxVMGuestPatchAutomaticByPlatformSettings{
+							RebootSetting: ptr.To(armcompute.LinuxVMGuestPatchAutomaticByPlatformRebootSettingAlways),
+						},
+					},
+				},
+			},
+			want: &infrav1.PatchSettings{
+				PatchMode:      infrav1.PatchModeAutomaticByPlatform,
+				AssessmentMode: infrav1.PatchAssessmentModeImageDefault,
+				RebootSetting:  infrav1.PatchRebootSettingAlways,
+			},
+		},
+		{
+			name: "Windows patch settings",
+			osProfile: &armcompute.VirtualMachineScaleSetOSProfile{
+				WindowsConfiguration: &armcompute.WindowsConfiguration{
+					PatchSettings: &armcompute.PatchSettings{
+						PatchMode: ptr.To(armcompute.WindowsVMGuestPatchModeAutomaticByOS),
+					},
+				},
+			},
+			want: &infrav1.PatchSettings{
+				PatchMode: infrav1.PatchModeAutomaticByOS,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(SDKToPatchSettings(tc.osProfile)).To(Equal(tc.want))
+		})
+	}
+}
@@ -106,6 +106,21 @@ func TestGetSpotVMOptions(t *testing.T) {
 				billingProfile:        nil,
 			},
 		},
+		{
+			name: "spot with max price and deallocate eviction policy",
+			spot: &infrav1.SpotVMOptions{
+				MaxPrice:       ptr.To(resource.MustParse("0.05")),
+				EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDeallocate),
+			},
+			diffDiskSettings: nil,
+			want: resultParams{
+				vmPriorityTypes:       ptr.To(armcompute.VirtualMachinePriorityTypesSpot),
+				vmEvictionPolicyTypes: ptr.To(armcompute.VirtualMachineEvictionPolicyTypesDeallocate),
+				billingProfile: &armcompute.BillingProfile{
+					MaxPrice: ptr.To[float64](0.05),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
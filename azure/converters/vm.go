@@ -17,6 +17,8 @@ limitations under the License.
 package converters
 
 import (
+	"time"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
@@ -77,3 +79,84 @@ func SDKToVM(v armcompute.VirtualMachine) *VM {
 
 	return vm
 }
+
+// vmPowerStates maps the power state codes of an Azure virtual machine instance view to CAPZ power states.
+var vmPowerStates = map[string]infrav1.VMPowerState{
+	"PowerState/running":      infrav1.VMPowerStateRunning,
+	"PowerState/deallocated":  infrav1.VMPowerStateDeallocated,
+	"PowerState/starting":     infrav1.VMPowerStateStarting,
+	"PowerState/stopping":     infrav1.VMPowerStateStopping,
+	"PowerState/stopped":      infrav1.VMPowerStateStopped,
+	"PowerState/deallocating": infrav1.VMPowerStateDeallocating,
+}
+
+// hibernatedStatusCode is the status code of the instance view of a hibernated virtual machine, which is also
+// reported as deallocated.
+const hibernatedStatusCode = "HibernationState/Hibernated"
+
+// SDKToVMPowerState converts the instance view of an Azure SDK VirtualMachine to the power state of the virtual
+// machine, or an empty power state if the instance view doesn't report it.
+func SDKToVMPowerState(instanceView *armcompute.VirtualMachineInstanceView) infrav1.VMPowerState {
+	if instanceView == nil {
+		return ""
+	}
+	return statusesToVMPowerState(instanceView.Statuses)
+}
+
+// statusesToVMPowerState converts the statuses of the instance view of a virtual machine to its power state.
+func statusesToVMPowerState(statuses []*armcompute.InstanceViewStatus) infrav1.VMPowerState {
+	var powerState infrav1.VMPowerState
+	hibernated := false
+	for _, status := range statuses {
+		if status == nil || status.Code == nil {
+			continue
+		}
+		if *status.Code == hibernatedStatusCode {
+			hibernated = true
+		} else if state, ok := vmPowerStates[*status.Code]; ok {
+			powerState = state
+		}
+	}
+	if hibernated && powerState == infrav1.VMPowerStateDeallocated {
+		return infrav1.VMPowerStateHibernated
+	}
+	return powerState
+}
+
+// vmAgentReadyDisplayStatus is the display status of a ready VM agent in the instance view of a virtual machine.
+const vmAgentReadyDisplayStatus = "Ready"
+
+// SDKToVMAgentStatus converts the instance view of an Azure SDK VirtualMachine to whether its VM agent is ready, along
+// with the message of the VM agent status. reported is false if the instance view doesn't report a VM agent status.
+func SDKToVMAgentStatus(instanceView *armcompute.VirtualMachineInstanceView) (ready bool, message string, reported bool) {
+	if instanceView == nil || instanceView.VMAgent == nil {
+		return false, "", false
+	}
+	for _, status := range instanceView.VMAgent.Statuses {
+		if status == nil || status.DisplayStatus == nil {
+			continue
+		}
+		return *status.DisplayStatus == vmAgentReadyDisplayStatus, ptr.Deref(status.Message, ""), true
+	}
+	return false, "", false
+}
+
+// runningStatusCode is the code of the power state status of the instance view of a running virtual machine.
+const runningStatusCode = "PowerState/running"
+
+// SDKToVMLastBootTime converts the instance view of a running Azure SDK VirtualMachine to when the virtual machine
+// started running, i.e. the time of its running power state status, or nil if the instance view doesn't report it.
+func SDKToVMLastBootTime(instanceView *armcompute.VirtualMachineInstanceView) *time.Time {
+	if instanceView == nil {
+		return nil
+	}
+	for _, status := range instanceView.Statuses {
+		if status == nil || status.Code == nil || status.Time == nil {
+			continue
+		}
+		if *status.Code == runningStatusCode {
+			return status.Time
+		}
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package converters
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/go-cmp/cmp"
@@ -135,3 +136,171 @@ func TestSDKToVM(t *testing.T) {
 		})
 	}
 }
+
+func TestSDKToVMPowerState(t *testing.T) {
+	statuses := func(codes ...string) *armcompute.VirtualMachineInstanceView {
+		instanceView := &armcompute.VirtualMachineInstanceView{}
+		for _, code := range codes {
+			instanceView.Statuses = append(instanceView.Statuses, &armcompute.InstanceViewStatus{Code: ptr.To(code)})
+		}
+		return instanceView
+	}
+	tests := []struct {
+		name         string
+		instanceView *armcompute.VirtualMachineInstanceView
+		want         infrav1.VMPowerState
+	}{
+		{
+			name:         "no instance view",
+			instanceView: nil,
+			want:         "",
+		},
+		{
+			name:         "no power state",
+			instanceView: statuses("ProvisioningState/succeeded"),
+			want:         "",
+		},
+		{
+			name:         "running",
+			instanceView: statuses("ProvisioningState/succeeded", "PowerState/running"),
+			want:         infrav1.VMPowerStateRunning,
+		},
+		{
+			name:         "deallocated",
+			instanceView: statuses("ProvisioningState/succeeded", "PowerState/deallocated"),
+			want:         infrav1.VMPowerStateDeallocated,
+		},
+		{
+			name:         "hibernated",
+			instanceView: statuses("ProvisioningState/succeeded", "PowerState/deallocated", "HibernationState/Hibernated"),
+			want:         infrav1.VMPowerStateHibernated,
+		},
+		{
+			name:         "being hibernated",
+			instanceView: statuses("ProvisioningState/updating", "PowerState/deallocating", "HibernationState/Hibernated"),
+			want:         infrav1.VMPowerStateDeallocating,
+		},
+		{
+			name:         "starting",
+			instanceView: statuses("ProvisioningState/updating", "PowerState/starting"),
+			want:         infrav1.VMPowerStateStarting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SDKToVMPowerState(tt.instanceView); got != tt.want {
+				t.Errorf("SDKToVMPowerState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSDKToVMAgentStatus(t *testing.T) {
+	agent := func(displayStatus, message string) *armcompute.VirtualMachineInstanceView {
+		return &armcompute.VirtualMachineInstanceView{
+			VMAgent: &armcompute.VirtualMachineAgentInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{
+					{
+						Code:          ptr.To("ProvisioningState/succeeded"),
+						DisplayStatus: ptr.To(displayStatus),
+						Message:       ptr.To(message),
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		instanceView *armcompute.VirtualMachineInstanceView
+		wantReady    bool
+		wantMessage  string
+		wantReported bool
+	}{
+		{
+			name:         "no instance view",
+			instanceView: nil,
+		},
+		{
+			name:         "no vm agent",
+			instanceView: &armcompute.VirtualMachineInstanceView{},
+		},
+		{
+			name:         "no vm agent status",
+			instanceView: &armcompute.VirtualMachineInstanceView{VMAgent: &armcompute.VirtualMachineAgentInstanceView{}},
+		},
+		{
+			name:         "ready",
+			instanceView: agent("Ready", "Guest Agent is running"),
+			wantReady:    true,
+			wantMessage:  "Guest Agent is running",
+			wantReported: true,
+		},
+		{
+			name:         "not ready",
+			instanceView: agent("Not Ready", "VM status blob is found but not yet populated."),
+			wantReady:    false,
+			wantMessage:  "VM status blob is found but not yet populated.",
+			wantReported: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, message, reported := SDKToVMAgentStatus(tt.instanceView)
+			if ready != tt.wantReady || message != tt.wantMessage || reported != tt.wantReported {
+				t.Errorf("SDKToVMAgentStatus() = %t, %q, %t, want %t, %q, %t", ready, message, reported, tt.wantReady, tt.wantMessage, tt.wantReported)
+			}
+		})
+	}
+}
+
+func TestSDKToVMLastBootTime(t *testing.T) {
+	bootTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		instanceView *armcompute.VirtualMachineInstanceView
+		want         *time.Time
+	}{
+		{
+			name:         "no instance view",
+			instanceView: nil,
+			want:         nil,
+		},
+		{
+			name: "no running power state time",
+			instanceView: &armcompute.VirtualMachineInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{
+					{Code: ptr.To("ProvisioningState/succeeded"), Time: ptr.To(bootTime)},
+					{Code: ptr.To("PowerState/running")},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "running power state time",
+			instanceView: &armcompute.VirtualMachineInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{
+					{Code: ptr.To("ProvisioningState/succeeded"), Time: ptr.To(bootTime.Add(time.Hour))},
+					{Code: ptr.To("PowerState/running"), Time: ptr.To(bootTime)},
+				},
+			},
+			want: &bootTime,
+		},
+		{
+			name: "stopped power state time",
+			instanceView: &armcompute.VirtualMachineInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{
+					{Code: ptr.To("PowerState/stopped"), Time: ptr.To(bootTime)},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SDKToVMLastBootTime(tt.instanceView)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("SDKToVMLastBootTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
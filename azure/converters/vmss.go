@@ -54,6 +54,17 @@ func SDKToVMSS(sdkvmss armcompute.VirtualMachineScaleSet, sdkinstances []armcomp
 		vmss.Tags = MapToTags(sdkvmss.Tags)
 	}
 
+	if sdkvmss.Properties.VirtualMachineProfile != nil {
+		vmss.PatchSettings = SDKToPatchSettings(sdkvmss.Properties.VirtualMachineProfile.OSProfile)
+		if profile := sdkvmss.Properties.VirtualMachineProfile.ExtensionProfile; profile != nil {
+			for _, extension := range profile.Extensions {
+				if extension != nil && extension.Name != nil {
+					vmss.Extensions = append(vmss.Extensions, *extension.Name)
+				}
+			}
+		}
+	}
+
 	if len(sdkinstances) > 0 {
 		vmss.Instances = make([]azure.VMSSVM, len(sdkinstances))
 		orchestrationMode := ptr.Deref(sdkvmss.Properties.OrchestrationMode, "")
@@ -102,6 +113,19 @@ func SDKVMToVMSSVM(sdkInstance armcompute.VirtualMachine, mode infrav1.Orchestra
 		instance.AvailabilityZone = *sdkInstance.Zones[0]
 	}
 
+	if sdkInstance.Properties.HardwareProfile != nil && sdkInstance.Properties.HardwareProfile.VMSize != nil {
+		instance.VMSize = string(*sdkInstance.Properties.HardwareProfile.VMSize)
+	}
+
+	if sdkInstance.Properties.Priority != nil {
+		instance.Priority = string(*sdkInstance.Properties.Priority)
+	}
+
+	if sdkInstance.Properties.InstanceView != nil {
+		instance.FaultDomain = sdkInstance.Properties.InstanceView.PlatformFaultDomain
+		instance.PowerState = SDKToVMPowerState(sdkInstance.Properties.InstanceView)
+	}
+
 	instance.OrchestrationMode = mode
 
 	return &instance
@@ -154,6 +178,15 @@ func SDKToVMSSVM(sdkInstance armcompute.VirtualMachineScaleSetVM) *azure.VMSSVM
 		instance.AvailabilityZone = *sdkInstance.Zones[0]
 	}
 
+	if sdkInstance.SKU != nil {
+		instance.VMSize = ptr.Deref(sdkInstance.SKU.Name, "")
+	}
+
+	if sdkInstance.Properties.InstanceView != nil {
+		instance.FaultDomain = sdkInstance.Properties.InstanceView.PlatformFaultDomain
+		instance.PowerState = statusesToVMPowerState(sdkInstance.Properties.InstanceView.Statuses)
+	}
+
 	return &instance
 }
 
@@ -202,6 +202,48 @@ func Test_SDKToVMSSVM(t *testing.T) {
 				State:            "Creating",
 			},
 		},
+		{
+			Name: "VM with instance view and SKU",
+			SDKInstance: armcompute.VirtualMachineScaleSetVM{
+				ID:  ptr.To("/subscriptions/foo/resourceGroups/MY_RESOURCE_GROUP/providers/bar"),
+				SKU: &armcompute.SKU{Name: ptr.To("Standard_D2s_v3")},
+				Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+					OSProfile: &armcompute.OSProfile{ComputerName: ptr.To("instance-000003")},
+					InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+						PlatformFaultDomain: ptr.To[int32](2),
+					},
+				},
+			},
+			VMSSVM: &azure.VMSSVM{
+				ID:          "/subscriptions/foo/resourceGroups/my_resource_group/providers/bar",
+				Name:        "instance-000003",
+				FaultDomain: ptr.To[int32](2),
+				VMSize:      "Standard_D2s_v3",
+				State:       "Creating",
+			},
+		},
+		{
+			Name: "evicted VM",
+			SDKInstance: armcompute.VirtualMachineScaleSetVM{
+				ID: ptr.To("/subscriptions/foo/resourceGroups/MY_RESOURCE_GROUP/providers/bar"),
+				Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+					ProvisioningState: ptr.To("Succeeded"),
+					OSProfile:         &armcompute.OSProfile{ComputerName: ptr.To("instance-000004")},
+					InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: ptr.To("ProvisioningState/succeeded")},
+							{Code: ptr.To("PowerState/deallocated")},
+						},
+					},
+				},
+			},
+			VMSSVM: &azure.VMSSVM{
+				ID:         "/subscriptions/foo/resourceGroups/my_resource_group/providers/bar",
+				Name:       "instance-000004",
+				State:      "Succeeded",
+				PowerState: infrav1.VMPowerStateDeallocated,
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -397,6 +439,55 @@ func Test_SDKVMToVMSSVM(t *testing.T) {
 				State: "Succeeded",
 			},
 		},
+		{
+			Name: "VM with instance view and hardware profile",
+			Subject: armcompute.VirtualMachine{
+				ID: ptr.To("vmID5"),
+				Properties: &armcompute.VirtualMachineProperties{
+					OSProfile: &armcompute.OSProfile{
+						ComputerName: ptr.To("vmwithinstanceview"),
+					},
+					HardwareProfile: &armcompute.HardwareProfile{
+						VMSize: ptr.To(armcompute.VirtualMachineSizeTypesStandardD2SV3),
+					},
+					Priority: ptr.To(armcompute.VirtualMachinePriorityTypesSpot),
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						PlatformFaultDomain: ptr.To[int32](1),
+					},
+				},
+			},
+			Expected: &azure.VMSSVM{
+				ID:          "vmID5",
+				Name:        "vmwithinstanceview",
+				State:       "Creating",
+				FaultDomain: ptr.To[int32](1),
+				VMSize:      "Standard_D2s_v3",
+				Priority:    "Spot",
+			},
+		},
+		{
+			Name: "running VM",
+			Subject: armcompute.VirtualMachine{
+				ID: ptr.To("vmID6"),
+				Properties: &armcompute.VirtualMachineProperties{
+					OSProfile: &armcompute.OSProfile{
+						ComputerName: ptr.To("runningvm"),
+					},
+					ProvisioningState: ptr.To("Succeeded"),
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: ptr.To("PowerState/running")},
+						},
+					},
+				},
+			},
+			Expected: &azure.VMSSVM{
+				ID:         "vmID6",
+				Name:       "runningvm",
+				State:      "Succeeded",
+				PowerState: infrav1.VMPowerStateRunning,
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -18,12 +18,19 @@ package azure
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
@@ -164,6 +171,21 @@ func GenerateVNetLinkName(vnetName string) string {
 	return fmt.Sprintf("%s-link", vnetName)
 }
 
+// GenerateVNetLinkNameWithID generates the name of a virtual network link based on the vnet name and a hash of the vnet
+// resource ID, so that virtual networks with the same name in different resource groups or subscriptions get
+// different links.
+func GenerateVNetLinkNameWithID(vnetName, vnetID string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(vnetID)))
+	return fmt.Sprintf("%s-%x-link", vnetName, h.Sum32())
+}
+
+// GenerateAPIServerDNSRecordName generates the name of the ASO resource of the API server record in an Azure DNS
+// zone based on the cluster name.
+func GenerateAPIServerDNSRecordName(clusterName string) string {
+	return fmt.Sprintf("%s-apiserver", clusterName)
+}
+
 // GenerateNICName generates the name of a network interface based on the name of a VM.
 func GenerateNICName(machineName string, multiNIC bool, index int) string {
 	if multiNIC {
@@ -187,6 +209,50 @@ func GenerateDataDiskName(machineName, nameSuffix string) string {
 	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
 }
 
+// MachineResourceNames are the names of the resources of a machine rendered by the naming template of its cluster.
+// The resources without a rendered name get the names generated from the name of the machine.
+type MachineResourceNames struct {
+	MachineName      string
+	NetworkInterface string
+	PublicIP         string
+	Disk             string
+}
+
+// NICName returns the name of the network interface at index of the machine.
+func (n MachineResourceNames) NICName(multiNIC bool, index int) string {
+	if n.NetworkInterface == "" {
+		return GenerateNICName(n.MachineName, multiNIC, index)
+	}
+	if multiNIC {
+		return fmt.Sprintf("%s-%d", n.NetworkInterface, index)
+	}
+	return n.NetworkInterface
+}
+
+// PublicIPName returns the name of the public IP of the machine.
+func (n MachineResourceNames) PublicIPName() string {
+	if n.PublicIP == "" {
+		return GenerateNodePublicIPName(n.MachineName)
+	}
+	return n.PublicIP
+}
+
+// OSDiskName returns the name of the OS disk of the machine.
+func (n MachineResourceNames) OSDiskName() string {
+	if n.Disk == "" {
+		return GenerateOSDiskName(n.MachineName)
+	}
+	return n.Disk
+}
+
+// DataDiskName returns the name of the data disk of the machine with nameSuffix.
+func (n MachineResourceNames) DataDiskName(nameSuffix string) string {
+	if n.Disk == "" {
+		return GenerateDataDiskName(n.MachineName, nameSuffix)
+	}
+	return GenerateDataDiskName(n.Disk, nameSuffix)
+}
+
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
 func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) string {
 	return fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName)
@@ -205,6 +271,22 @@ func WithIndex(name string, n int) string {
 	return fmt.Sprintf("%s-%d", name, n)
 }
 
+// GenerateResourceLockName generates the name of the management lock of a cluster on a kind of resource.
+func GenerateResourceLockName(clusterName, kind string) string {
+	return fmt.Sprintf("%s-%s-lock", clusterName, strings.ToLower(kind))
+}
+
+// GenerateRoleAssignmentName generates the name of a role assignment, which must be a UUID, from a hash of its scope,
+// role definition and principal, so that retrying the creation of a role assignment always uses the same name.
+func GenerateRoleAssignmentName(scope, roleDefinitionID, principalID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(fmt.Sprintf("%s/%s/%s", scope, roleDefinitionID, principalID)))).String()
+}
+
+// ResourceLockID returns the azure resource ID for a given management lock on a resource.
+func ResourceLockID(scope, lockName string) string {
+	return fmt.Sprintf("%s/providers/Microsoft.Authorization/locks/%s", scope, lockName)
+}
+
 // ResourceGroupID returns the azure resource ID for a given resource group.
 func ResourceGroupID(subscriptionID, resourceGroup string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
@@ -215,6 +297,11 @@ func VMID(subscriptionID, resourceGroup, vmName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s", subscriptionID, resourceGroup, vmName)
 }
 
+// VMSSID returns the azure resource ID for a given virtual machine scale set.
+func VMSSID(subscriptionID, resourceGroup, vmssName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets/%s", subscriptionID, resourceGroup, vmssName)
+}
+
 // VNetID returns the azure resource ID for a given VNet.
 func VNetID(subscriptionID, resourceGroup, vnetName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s", subscriptionID, resourceGroup, vnetName)
@@ -250,11 +337,21 @@ func NatGatewayID(subscriptionID, resourceGroup, natgatewayName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/natGateways/%s", subscriptionID, resourceGroup, natgatewayName)
 }
 
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
 // NetworkInterfaceID returns the azure resource ID for a given network interface.
 func NetworkInterfaceID(subscriptionID, resourceGroup, nicName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces/%s", subscriptionID, resourceGroup, nicName)
 }
 
+// LoadBalancerID returns the azure resource ID for a given load balancer.
+func LoadBalancerID(subscriptionID, resourceGroup, loadBalancerName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s", subscriptionID, resourceGroup, loadBalancerName)
+}
+
 // FrontendIPConfigID returns the azure resource ID for a given frontend IP config.
 func FrontendIPConfigID(subscriptionID, resourceGroup, loadBalancerName, configName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/loadBalancers/%s/frontendIPConfigurations/%s", subscriptionID, resourceGroup, loadBalancerName, configName)
@@ -367,6 +464,7 @@ func ARMClientOptions(azureEnvironment string, extraPolicies ...policy.Policy) (
 	opts.PerCallPolicies = []policy.Policy{
 		correlationIDPolicy{},
 		userAgentPolicy{},
+		tracingPolicy{},
 	}
 	opts.PerCallPolicies = append(opts.PerCallPolicies, extraPolicies...)
 	opts.Retry.MaxRetries = -1 // Less than zero means one try and no retries.
@@ -396,6 +494,37 @@ func (p userAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
 	return req.Next()
 }
 
+// tracingPolicy records ARM requests as spans, with the type of the Azure resource, the Azure request ID and the
+// status code of the response.
+// It implements the policy.Policy interface.
+type tracingPolicy struct{}
+
+// Do sends a request in a span which is a child of the span of the request's context.
+func (p tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	attrs := []attribute.KeyValue{semconv.HTTPMethodKey.String(raw.Method)}
+	if resourceID, err := arm.ParseResourceID(raw.URL.Path); err == nil {
+		attrs = append(attrs, attribute.String("resourceType", resourceID.ResourceType.String()))
+	}
+	_, span := tele.Tracer().Start(raw.Context(), "ARM "+raw.Method, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp, err := req.Next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(
+		semconv.HTTPStatusCodeKey.Int(resp.StatusCode),
+		attribute.String("azure.requestID", resp.Header.Get("x-ms-request-id")),
+	)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
 // CustomPutPatchHeaderPolicy adds custom headers to a PUT or PATCH request.
 // It implements the policy.Policy interface.
 type CustomPutPatchHeaderPolicy struct {
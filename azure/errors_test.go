@@ -113,3 +113,41 @@ func TestResourceNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		success bool
+	}{
+		{
+			name:    "Conflict detailed error",
+			err:     autorest.DetailedError{StatusCode: http.StatusConflict},
+			success: true,
+		},
+		{
+			name:    "Conflict response error",
+			err:     &azcore.ResponseError{StatusCode: http.StatusConflict},
+			success: true,
+		},
+		{
+			name:    "Not Found response error",
+			err:     &azcore.ResponseError{StatusCode: http.StatusNotFound},
+			success: false,
+		},
+		{
+			name:    "Conflict generic error",
+			err:     errors.New("409: Conflict"),
+			success: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ResourceConflict(tc.err); got != tc.success {
+				t.Errorf("ResourceConflict() = %v, want %v", got, tc.success)
+			}
+		})
+	}
+}
@@ -39,6 +39,13 @@ type Pauser interface {
 	Pause(context.Context) error
 }
 
+// Retainer may be implemented for a ServiceReconciler whose resources need to be detached from the cluster to be
+// kept in Azure when the cluster is deleted.
+type Retainer interface {
+	// Retain detaches the service's resources and returns the IDs of the retained Azure resources.
+	Retain(context.Context) ([]string, error)
+}
+
 // ServiceReconciler is an Azure service reconciler which can reconcile an Azure service.
 type ServiceReconciler interface {
 	Name() string
@@ -73,6 +80,7 @@ type NetworkDescriber interface {
 	APIServerLBName() string
 	APIServerLBPoolName() string
 	IsAPIServerPrivate() bool
+	APIServerLBMigration() *infrav1.APIServerLBMigration
 	GetPrivateDNSZoneName() string
 	OutboundLBName(string) string
 	OutboundPoolName(string) string
@@ -119,6 +127,25 @@ type ClusterScoper interface {
 	AsyncStatusUpdater
 	GetClient() client.Client
 	GetDeletionTimestamp() *metav1.Time
+	DefaultImage() *infrav1.DefaultImage
+	ClusterVMExtensions() []infrav1.ClusterVMExtension
+	NamingTemplate() *infrav1.NamingTemplate
+}
+
+// ResourceOwnershipRecorder is an interface used to record the Azure resources created by CAPZ, so that
+// their lifecycle can be determined without relying on tags that may have been copied to other resources.
+type ResourceOwnershipRecorder interface {
+	IsOwnershipRecorded() bool
+	RecordManagedResource(id string)
+	ForgetManagedResource(id string)
+	IsManagedResource(id string, ownedByTags bool) bool
+}
+
+// ResourceDeletionRecorder is an interface used to remember which recorded Azure resource is being deleted by a
+// resource, e.g. an ASO resource, that may be gone by the time the deletion is confirmed.
+type ResourceDeletionRecorder interface {
+	RecordManagedResourceDeletion(key, id string)
+	ManagedResourceDeletion(key string) string
 }
 
 // ManagedClusterScoper defines the interface for ManagedClusterScope.
@@ -127,6 +127,44 @@ func (mr *MockPauserMockRecorder) Pause(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockPauser)(nil).Pause), arg0)
 }
 
+// MockRetainer is a mock of Retainer interface.
+type MockRetainer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetainerMockRecorder
+}
+
+// MockRetainerMockRecorder is the mock recorder for MockRetainer.
+type MockRetainerMockRecorder struct {
+	mock *MockRetainer
+}
+
+// NewMockRetainer creates a new mock instance.
+func NewMockRetainer(ctrl *gomock.Controller) *MockRetainer {
+	mock := &MockRetainer{ctrl: ctrl}
+	mock.recorder = &MockRetainerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetainer) EXPECT() *MockRetainerMockRecorder {
+	return m.recorder
+}
+
+// Retain mocks base method.
+func (m *MockRetainer) Retain(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retain", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retain indicates an expected call of Retain.
+func (mr *MockRetainerMockRecorder) Retain(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockRetainer)(nil).Retain), arg0)
+}
+
 // MockServiceReconciler is a mock of ServiceReconciler interface.
 type MockServiceReconciler struct {
 	ctrl     *gomock.Controller
@@ -364,6 +402,20 @@ func (mr *MockNetworkDescriberMockRecorder) APIServerLB() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLB", reflect.TypeOf((*MockNetworkDescriber)(nil).APIServerLB))
 }
 
+// APIServerLBMigration mocks base method.
+func (m *MockNetworkDescriber) APIServerLBMigration() *v1beta1.APIServerLBMigration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLBMigration")
+	ret0, _ := ret[0].(*v1beta1.APIServerLBMigration)
+	return ret0
+}
+
+// APIServerLBMigration indicates an expected call of APIServerLBMigration.
+func (mr *MockNetworkDescriberMockRecorder) APIServerLBMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBMigration", reflect.TypeOf((*MockNetworkDescriber)(nil).APIServerLBMigration))
+}
+
 // APIServerLBName mocks base method.
 func (m *MockNetworkDescriber) APIServerLBName() string {
 	m.ctrl.T.Helper()
@@ -1102,6 +1154,20 @@ func (mr *MockClusterScoperMockRecorder) APIServerLB() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLB", reflect.TypeOf((*MockClusterScoper)(nil).APIServerLB))
 }
 
+// APIServerLBMigration mocks base method.
+func (m *MockClusterScoper) APIServerLBMigration() *v1beta1.APIServerLBMigration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLBMigration")
+	ret0, _ := ret[0].(*v1beta1.APIServerLBMigration)
+	return ret0
+}
+
+// APIServerLBMigration indicates an expected call of APIServerLBMigration.
+func (mr *MockClusterScoperMockRecorder) APIServerLBMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBMigration", reflect.TypeOf((*MockClusterScoper)(nil).APIServerLBMigration))
+}
+
 // APIServerLBName mocks base method.
 func (m *MockClusterScoper) APIServerLBName() string {
 	m.ctrl.T.Helper()
@@ -1242,6 +1308,20 @@ func (mr *MockClusterScoperMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockClusterScoper)(nil).ClusterName))
 }
 
+// ClusterVMExtensions mocks base method.
+func (m *MockClusterScoper) ClusterVMExtensions() []v1beta1.ClusterVMExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterVMExtensions")
+	ret0, _ := ret[0].([]v1beta1.ClusterVMExtension)
+	return ret0
+}
+
+// ClusterVMExtensions indicates an expected call of ClusterVMExtensions.
+func (mr *MockClusterScoperMockRecorder) ClusterVMExtensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterVMExtensions", reflect.TypeOf((*MockClusterScoper)(nil).ClusterVMExtensions))
+}
+
 // ControlPlaneRouteTable mocks base method.
 func (m *MockClusterScoper) ControlPlaneRouteTable() v1beta1.RouteTable {
 	m.ctrl.T.Helper()
@@ -1270,6 +1350,20 @@ func (mr *MockClusterScoperMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockClusterScoper)(nil).ControlPlaneSubnet))
 }
 
+// DefaultImage mocks base method.
+func (m *MockClusterScoper) DefaultImage() *v1beta1.DefaultImage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1beta1.DefaultImage)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockClusterScoperMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockClusterScoper)(nil).DefaultImage))
+}
+
 // DefaultedAzureCallTimeout mocks base method.
 func (m *MockClusterScoper) DefaultedAzureCallTimeout() time.Duration {
 	m.ctrl.T.Helper()
@@ -1506,6 +1600,20 @@ func (mr *MockClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterScoper)(nil).Location))
 }
 
+// NamingTemplate mocks base method.
+func (m *MockClusterScoper) NamingTemplate() *v1beta1.NamingTemplate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamingTemplate")
+	ret0, _ := ret[0].(*v1beta1.NamingTemplate)
+	return ret0
+}
+
+// NamingTemplate indicates an expected call of NamingTemplate.
+func (mr *MockClusterScoperMockRecorder) NamingTemplate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamingTemplate", reflect.TypeOf((*MockClusterScoper)(nil).NamingTemplate))
+}
+
 // NodeResourceGroup mocks base method.
 func (m *MockClusterScoper) NodeResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1720,6 +1828,130 @@ func (mr *MockClusterScoperMockRecorder) Vnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockClusterScoper)(nil).Vnet))
 }
 
+// MockResourceOwnershipRecorder is a mock of ResourceOwnershipRecorder interface.
+type MockResourceOwnershipRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceOwnershipRecorderMockRecorder
+}
+
+// MockResourceOwnershipRecorderMockRecorder is the mock recorder for MockResourceOwnershipRecorder.
+type MockResourceOwnershipRecorderMockRecorder struct {
+	mock *MockResourceOwnershipRecorder
+}
+
+// NewMockResourceOwnershipRecorder creates a new mock instance.
+func NewMockResourceOwnershipRecorder(ctrl *gomock.Controller) *MockResourceOwnershipRecorder {
+	mock := &MockResourceOwnershipRecorder{ctrl: ctrl}
+	mock.recorder = &MockResourceOwnershipRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceOwnershipRecorder) EXPECT() *MockResourceOwnershipRecorderMockRecorder {
+	return m.recorder
+}
+
+// ForgetManagedResource mocks base method.
+func (m *MockResourceOwnershipRecorder) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockResourceOwnershipRecorderMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockResourceOwnershipRecorder)(nil).ForgetManagedResource), id)
+}
+
+// IsManagedResource mocks base method.
+func (m *MockResourceOwnershipRecorder) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockResourceOwnershipRecorderMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockResourceOwnershipRecorder)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockResourceOwnershipRecorder) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockResourceOwnershipRecorderMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockResourceOwnershipRecorder)(nil).IsOwnershipRecorded))
+}
+
+// RecordManagedResource mocks base method.
+func (m *MockResourceOwnershipRecorder) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockResourceOwnershipRecorderMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockResourceOwnershipRecorder)(nil).RecordManagedResource), id)
+}
+
+// MockResourceDeletionRecorder is a mock of ResourceDeletionRecorder interface.
+type MockResourceDeletionRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceDeletionRecorderMockRecorder
+}
+
+// MockResourceDeletionRecorderMockRecorder is the mock recorder for MockResourceDeletionRecorder.
+type MockResourceDeletionRecorderMockRecorder struct {
+	mock *MockResourceDeletionRecorder
+}
+
+// NewMockResourceDeletionRecorder creates a new mock instance.
+func NewMockResourceDeletionRecorder(ctrl *gomock.Controller) *MockResourceDeletionRecorder {
+	mock := &MockResourceDeletionRecorder{ctrl: ctrl}
+	mock.recorder = &MockResourceDeletionRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceDeletionRecorder) EXPECT() *MockResourceDeletionRecorderMockRecorder {
+	return m.recorder
+}
+
+// ManagedResourceDeletion mocks base method.
+func (m *MockResourceDeletionRecorder) ManagedResourceDeletion(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedResourceDeletion", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ManagedResourceDeletion indicates an expected call of ManagedResourceDeletion.
+func (mr *MockResourceDeletionRecorderMockRecorder) ManagedResourceDeletion(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedResourceDeletion", reflect.TypeOf((*MockResourceDeletionRecorder)(nil).ManagedResourceDeletion), key)
+}
+
+// RecordManagedResourceDeletion mocks base method.
+func (m *MockResourceDeletionRecorder) RecordManagedResourceDeletion(key, id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResourceDeletion", key, id)
+}
+
+// RecordManagedResourceDeletion indicates an expected call of RecordManagedResourceDeletion.
+func (mr *MockResourceDeletionRecorderMockRecorder) RecordManagedResourceDeletion(key, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResourceDeletion", reflect.TypeOf((*MockResourceDeletionRecorder)(nil).RecordManagedResourceDeletion), key, id)
+}
+
 // MockManagedClusterScoper is a mock of ManagedClusterScoper interface.
 type MockManagedClusterScoper struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	// maxCustomDataLength is the maximum length of the base64 encoded custom data of a VM or scale set.
+	maxCustomDataLength = 87380
+
+	// flatcarPublisher is the publisher of the Flatcar images, which support Ignition.
+	flatcarPublisher = "kinvolk"
+)
+
+// gzipMagic are the first bytes of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// encodeBootstrapData returns the bootstrap data in secret base64 encoded as the custom data of a VM. It returns an
+// error naming the problem when the data can't be used by the VM: its format is not supported by the OS image, or it
+// exceeds the custom data size limit. Cloud-init data exceeding the limit is gzip compressed for Linux VMs.
+func encodeBootstrapData(secret *corev1.Secret, osType string, image *infrav1.Image) (string, error) {
+	value := secret.Data["value"]
+	if len(value) == 0 {
+		return "", errors.New("bootstrap data is empty")
+	}
+
+	format := bootstrapDataFormat(secret)
+	switch format {
+	case bootstrapv1.CloudConfig:
+	case bootstrapv1.Ignition:
+		if !supportsIgnition(osType, image) {
+			return "", errors.New("bootstrap data is in Ignition format, which is not supported by the OS image of the VM. Use an image that supports Ignition such as Flatcar, or cloud-config bootstrap data")
+		}
+	default:
+		return "", errors.Errorf("bootstrap data format %q is not supported, it must be %q or %q", format, bootstrapv1.CloudConfig, bootstrapv1.Ignition)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(value)
+	if len(encoded) <= maxCustomDataLength {
+		return encoded, nil
+	}
+
+	// cloud-init decompresses gzip compressed custom data, Ignition and Windows VMs don't.
+	if format != bootstrapv1.CloudConfig || osType == infrav1.WindowsOS || bytes.HasPrefix(value, gzipMagic) {
+		return "", errors.Errorf("bootstrap data is %d bytes once base64 encoded, which exceeds the %d bytes limit of the custom data of a VM", len(encoded), maxCustomDataLength)
+	}
+	compressed, err := gzipData(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compress bootstrap data")
+	}
+	encodedCompressed := base64.StdEncoding.EncodeToString(compressed)
+	if len(encodedCompressed) > maxCustomDataLength {
+		return "", errors.Errorf("bootstrap data is %d bytes once gzip compressed and base64 encoded, which exceeds the %d bytes limit of the custom data of a VM", len(encodedCompressed), maxCustomDataLength)
+	}
+	return encodedCompressed, nil
+}
+
+// bootstrapDataFormat returns the format of the bootstrap data, as set by the bootstrap provider in the "format" key
+// of the secret. When unset, Ignition configs are detected from their content, and the data is otherwise assumed to
+// be cloud-config.
+func bootstrapDataFormat(secret *corev1.Secret) bootstrapv1.Format {
+	if format, ok := secret.Data["format"]; ok {
+		return bootstrapv1.Format(format)
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(secret.Data["value"], &config); err == nil {
+		if _, ok := config["ignition"]; ok {
+			return bootstrapv1.Ignition
+		}
+	}
+	return bootstrapv1.CloudConfig
+}
+
+// supportsIgnition returns false if the OS image is known not to support Ignition. The default reference images and
+// Windows images don't, nor do images from publishers other than Flatcar's. Custom images may.
+func supportsIgnition(osType string, image *infrav1.Image) bool {
+	switch {
+	case osType == infrav1.WindowsOS || image == nil:
+		return false
+	case image.Marketplace != nil:
+		return image.Marketplace.Publisher == flatcarPublisher
+	case image.ComputeGallery != nil && image.ComputeGallery.Plan != nil:
+		return image.ComputeGallery.Plan.Publisher == flatcarPublisher
+	case image.SharedGallery != nil && image.SharedGallery.Publisher != nil:
+		return ptr.Deref(image.SharedGallery.Publisher, "") == flatcarPublisher
+	default:
+		return true
+	}
+}
+
+// gzipData returns data gzip compressed.
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
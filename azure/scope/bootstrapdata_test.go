@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// cloudConfig returns cloud-config bootstrap data of exactly size bytes, which compresses well.
+func cloudConfig(size int) []byte {
+	header := "#cloud-config\n"
+	return []byte(header + strings.Repeat("a", size-len(header)))
+}
+
+// incompressibleCloudConfig returns cloud-config bootstrap data of exactly size bytes, which doesn't compress.
+func incompressibleCloudConfig(size int) []byte {
+	header := "#cloud-config\n"
+	data := make([]byte, size-len(header))
+	_, _ = rand.New(rand.NewSource(1)).Read(data) //nolint:gosec // Deterministic data is needed for the test.
+	return append([]byte(header), data...)
+}
+
+func bootstrapSecret(value []byte, format string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default"},
+		Data:       map[string][]byte{"value": value},
+	}
+	if format != "" {
+		secret.Data["format"] = []byte(format)
+	}
+	return secret
+}
+
+func gunzip(g *WithT, data []byte) []byte {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	g.Expect(err).NotTo(HaveOccurred())
+	decompressed, err := io.ReadAll(r)
+	g.Expect(err).NotTo(HaveOccurred())
+	return decompressed
+}
+
+func TestEncodeBootstrapData(t *testing.T) {
+	flatcar := &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{ImagePlan: infrav1.ImagePlan{Publisher: "kinvolk", Offer: "flatcar-container-linux-free", SKU: "stable"}, Version: "latest"}}
+	ubuntu := &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{ImagePlan: infrav1.ImagePlan{Publisher: "cncf-upstream", Offer: "capi", SKU: "ubuntu-2204-gen1"}, Version: "latest"}}
+	ignitionConfig := []byte(`{"ignition":{"version":"3.1.0"}}`)
+
+	tests := []struct {
+		name          string
+		secret        *corev1.Secret
+		osType        string
+		image         *infrav1.Image
+		expectedData  []byte
+		expectGzip    bool
+		expectedError string
+	}{
+		{
+			name:         "cloud-config",
+			secret:       bootstrapSecret([]byte("#cloud-config\n"), "cloud-config"),
+			osType:       infrav1.LinuxOS,
+			expectedData: []byte("#cloud-config\n"),
+		},
+		{
+			name:         "cloud-config without a format",
+			secret:       bootstrapSecret([]byte("#cloud-config\n"), ""),
+			osType:       infrav1.LinuxOS,
+			expectedData: []byte("#cloud-config\n"),
+		},
+		{
+			name:          "empty value",
+			secret:        bootstrapSecret([]byte{}, "cloud-config"),
+			osType:        infrav1.LinuxOS,
+			expectedError: "bootstrap data is empty",
+		},
+		{
+			name:          "unknown format",
+			secret:        bootstrapSecret([]byte("#cloud-config\n"), "mime"),
+			osType:        infrav1.LinuxOS,
+			expectedError: `bootstrap data format "mime" is not supported, it must be "cloud-config" or "ignition"`,
+		},
+		{
+			name:         "ignition with a Flatcar marketplace image",
+			secret:       bootstrapSecret(ignitionConfig, "ignition"),
+			osType:       infrav1.LinuxOS,
+			image:        flatcar,
+			expectedData: ignitionConfig,
+		},
+		{
+			name:         "ignition without a format with a custom image",
+			secret:       bootstrapSecret(ignitionConfig, ""),
+			osType:       infrav1.LinuxOS,
+			image:        &infrav1.Image{ID: ptr.To("/subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/images/flatcar")},
+			expectedData: ignitionConfig,
+		},
+		{
+			name:          "ignition with another marketplace image",
+			secret:        bootstrapSecret(ignitionConfig, "ignition"),
+			osType:        infrav1.LinuxOS,
+			image:         ubuntu,
+			expectedError: "bootstrap data is in Ignition format, which is not supported by the OS image of the VM",
+		},
+		{
+			name:          "ignition detected from the content with the default image",
+			secret:        bootstrapSecret(ignitionConfig, ""),
+			osType:        infrav1.LinuxOS,
+			expectedError: "bootstrap data is in Ignition format, which is not supported by the OS image of the VM",
+		},
+		{
+			name:          "ignition with Windows",
+			secret:        bootstrapSecret(ignitionConfig, "ignition"),
+			osType:        infrav1.WindowsOS,
+			image:         &infrav1.Image{ID: ptr.To("/subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/images/windows")},
+			expectedError: "bootstrap data is in Ignition format, which is not supported by the OS image of the VM",
+		},
+		{
+			name:         "cloud-config at the size limit",
+			secret:       bootstrapSecret(cloudConfig(65535), "cloud-config"),
+			osType:       infrav1.LinuxOS,
+			expectedData: cloudConfig(65535),
+		},
+		{
+			name:         "cloud-config over the size limit is compressed",
+			secret:       bootstrapSecret(cloudConfig(65536), "cloud-config"),
+			osType:       infrav1.LinuxOS,
+			expectedData: cloudConfig(65536),
+			expectGzip:   true,
+		},
+		{
+			name:          "cloud-config over the size limit that doesn't compress",
+			secret:        bootstrapSecret(incompressibleCloudConfig(70000), "cloud-config"),
+			osType:        infrav1.LinuxOS,
+			expectedError: "once gzip compressed and base64 encoded, which exceeds the 87380 bytes limit of the custom data of a VM",
+		},
+		{
+			name:          "cloud-config over the size limit for Windows",
+			secret:        bootstrapSecret(cloudConfig(65536), "cloud-config"),
+			osType:        infrav1.WindowsOS,
+			expectedError: "bootstrap data is 87384 bytes once base64 encoded, which exceeds the 87380 bytes limit of the custom data of a VM",
+		},
+		{
+			name:          "ignition over the size limit",
+			secret:        bootstrapSecret(append([]byte(`{"ignition":{"version":"3.1.0"},"x":"`), append(bytes.Repeat([]byte("a"), 65536), '"', '}')...), "ignition"),
+			osType:        infrav1.LinuxOS,
+			image:         flatcar,
+			expectedError: "exceeds the 87380 bytes limit of the custom data of a VM",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			encoded, err := encodeBootstrapData(tc.secret, tc.osType, tc.image)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(len(encoded)).To(BeNumerically("<=", maxCustomDataLength))
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectGzip {
+				data = gunzip(g, data)
+			}
+			g.Expect(data).To(Equal(tc.expectedData))
+		})
+	}
+}
+
+func TestMachineScope_GetBootstrapDataInvalid(t *testing.T) {
+	g := NewWithT(t)
+	secret := bootstrapSecret([]byte(`{"ignition":{"version":"3.1.0"}}`), "ignition")
+	machineScope := &MachineScope{
+		client: fake.NewClientBuilder().WithObjects(secret).Build(),
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-data")},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{OSType: infrav1.LinuxOS},
+			},
+		},
+	}
+
+	_, err := machineScope.GetBootstrapData(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("invalid bootstrap data secret default/bootstrap-data: bootstrap data is in Ignition format")))
+	var reconcileErr azure.ReconcileError
+	g.Expect(err).To(BeAssignableToTypeOf(reconcileErr))
+	g.Expect(err.(azure.ReconcileError).IsTerminal()).To(BeTrue())
+	g.Expect(conditions.GetReason(machineScope.AzureMachine, infrav1.VMRunningCondition)).To(Equal(infrav1.InvalidBootstrapDataReason))
+	g.Expect(conditions.GetSeverity(machineScope.AzureMachine, infrav1.VMRunningCondition)).To(Equal(ptr.To(clusterv1.ConditionSeverityError)))
+}
@@ -71,6 +71,18 @@ func (c *AzureClients) Token() azcore.TokenCredential {
 	return c.TokenCredential
 }
 
+// FallbackIdentity returns the name of the fallback AzureClusterIdentity and true if the cluster's
+// AzureClusterIdentity failed to authenticate and its fallback identity was used instead. ok is false if the
+// identity has no fallback identity or no token has been requested yet.
+func (c *AzureClients) FallbackIdentity() (name string, fallback bool, ok bool) {
+	cred, isFallbackCred := c.TokenCredential.(*fallbackTokenCredential)
+	if !isFallbackCred {
+		return "", false, false
+	}
+	fallback, ok = cred.usingFallbackIdentity()
+	return cred.fallbackIdentityName, fallback, ok
+}
+
 // HashKey returns a base64 url encoded sha256 hash for the Auth scope (Azure TenantID + CloudEnv + SubscriptionID +
 // ClientID).
 func (c *AzureClients) HashKey() string {
@@ -25,18 +25,26 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	asonetworkv1api20180501 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
 	asonetworkv1api20201101 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	asonetworkv1api20220701 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/net"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
@@ -46,14 +54,33 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/util/aso"
+	conditionsutils "sigs.k8s.io/cluster-api-provider-azure/util/conditions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// apiServerSecurityRuleName is the name of the default security rule allowing the API server port.
+	apiServerSecurityRuleName = "allow_apiserver"
+	// virtualNetworkServiceTag is the service tag matching the address space of the virtual network, the peered
+	// virtual networks and the networks connected to it.
+	virtualNetworkServiceTag = "VirtualNetwork"
+	// additionalAPIServerPortRulePrefix prefixes the names of the security rules of the additional API server ports.
+	additionalAPIServerPortRulePrefix = "allow_apiserver_"
+	// additionalAPIServerPortRulePriority is the lowest priority of the security rules of the additional API server
+	// ports, right after the default control plane rules.
+	additionalAPIServerPortRulePriority int32 = 2202
+	// additionalAPIServerPortRulePriorities is the number of priorities the security rules of the additional API server
+	// ports are spread across.
+	additionalAPIServerPortRulePriorities int32 = 1000
+)
+
 // ClusterScopeParams defines the input parameters used to create a new Scope.
 type ClusterScopeParams struct {
 	AzureClients
@@ -62,6 +89,10 @@ type ClusterScopeParams struct {
 	AzureCluster *infrav1.AzureCluster
 	Cache        *ClusterCache
 	Timeouts     azure.AsyncReconciler
+
+	// ForceDeleteUnmanaged determines ownership of Azure resources from tags even when the resources
+	// created by CAPZ are recorded in the AzureCluster status.
+	ForceDeleteUnmanaged bool
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -96,21 +127,23 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	return &ClusterScope{
-		Client:          params.Client,
-		AzureClients:    params.AzureClients,
-		Cluster:         params.Cluster,
-		AzureCluster:    params.AzureCluster,
-		patchHelper:     helper,
-		cache:           params.Cache,
-		AsyncReconciler: params.Timeouts,
+		Client:               params.Client,
+		AzureClients:         params.AzureClients,
+		Cluster:              params.Cluster,
+		AzureCluster:         params.AzureCluster,
+		patchHelper:          helper,
+		cache:                params.Cache,
+		AsyncReconciler:      params.Timeouts,
+		forceDeleteUnmanaged: params.ForceDeleteUnmanaged,
 	}, nil
 }
 
 // ClusterScope defines the basic context for an actuator to operate upon.
 type ClusterScope struct {
-	Client      client.Client
-	patchHelper *patch.Helper
-	cache       *ClusterCache
+	Client               client.Client
+	patchHelper          *patch.Helper
+	cache                *ClusterCache
+	forceDeleteUnmanaged bool
 
 	AzureClients
 	Cluster      *clusterv1.Cluster
@@ -161,8 +194,9 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 					IsIPv6:           false, // Set to default value
 					Location:         s.Location(),
 					ExtendedLocation: s.ExtendedLocation(),
-					FailureDomains:   s.FailureDomains(),
+					FailureDomains:   s.publicIPZones(ip.PublicIP.Zones),
 					AdditionalTags:   s.AdditionalTags(),
+					Outbound:         true,
 				})
 			}
 		}
@@ -176,9 +210,10 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				ClusterName:      s.ClusterName(),
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
-				FailureDomains:   s.FailureDomains(),
+				FailureDomains:   s.publicIPZones(s.APIServerPublicIP().Zones),
 				AdditionalTags:   s.AdditionalTags(),
 				IPTags:           s.APIServerPublicIP().IPTags,
+				Outbound:         true,
 			},
 		}
 	}
@@ -195,8 +230,9 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				IsIPv6:           false, // Set to default value
 				Location:         s.Location(),
 				ExtendedLocation: s.ExtendedLocation(),
-				FailureDomains:   s.FailureDomains(),
+				FailureDomains:   s.publicIPZones(ip.PublicIP.Zones),
 				AdditionalTags:   s.AdditionalTags(),
+				Outbound:         true,
 			})
 		}
 	}
@@ -205,6 +241,11 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	var nodeNatGatewayIPSpecs []azure.ResourceSpecGetter
 	for _, subnet := range s.NodeSubnets() {
 		if subnet.IsNatGatewayEnabled() {
+			// A zonal NAT gateway requires its public IP to be in the same zone.
+			failureDomains := s.publicIPZones(subnet.NatGateway.NatGatewayIP.Zones)
+			if subnet.NatGateway.Zone != nil && len(subnet.NatGateway.NatGatewayIP.Zones) == 0 {
+				failureDomains = []*string{subnet.NatGateway.Zone}
+			}
 			nodeNatGatewayIPSpecs = append(nodeNatGatewayIPSpecs, &publicips.PublicIPSpec{
 				Name:           subnet.NatGateway.NatGatewayIP.Name,
 				ResourceGroup:  s.ResourceGroup(),
@@ -212,9 +253,10 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 				IsIPv6:         false, // Public IP is IPv4 by default
 				ClusterName:    s.ClusterName(),
 				Location:       s.Location(),
-				FailureDomains: s.FailureDomains(),
+				FailureDomains: failureDomains,
 				AdditionalTags: s.AdditionalTags(),
 				IPTags:         subnet.NatGateway.NatGatewayIP.IPTags,
+				Outbound:       true,
 			})
 		}
 		publicIPSpecs = append(publicIPSpecs, nodeNatGatewayIPSpecs...)
@@ -229,7 +271,7 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 			IsIPv6:         false, // Public IP is IPv4 by default
 			ClusterName:    s.ClusterName(),
 			Location:       s.Location(),
-			FailureDomains: s.FailureDomains(),
+			FailureDomains: s.publicIPZones(azureBastion.PublicIP.Zones),
 			AdditionalTags: s.AdditionalTags(),
 			IPTags:         azureBastion.PublicIP.IPTags,
 		}
@@ -239,6 +281,16 @@ func (s *ClusterScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	return publicIPSpecs
 }
 
+// publicIPZones returns the given availability zones of a public IP or, if there are none, the failure domains of the
+// cluster so that the public IP is zone-redundant. There are no failure domains in a location without availability
+// zones.
+func (s *ClusterScope) publicIPZones(zones []string) []*string {
+	if len(zones) > 0 {
+		return azure.PtrSlice(&zones)
+	}
+	return s.FailureDomains()
+}
+
 // LBSpecs returns the load balancer specs.
 func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 	specs := []azure.ResourceSpecGetter{
@@ -260,6 +312,9 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			Role:                 infrav1.APIServerRole,
 			BackendPoolName:      s.APIServerLB().BackendPool.Name,
 			IdleTimeoutInMinutes: s.APIServerLB().IdleTimeoutInMinutes,
+			HealthProbe:          s.APIServerLB().HealthProbe,
+			AdditionalPorts:      s.APIServerLB().AdditionalAPIServerLBPorts,
+			FailureDomains:       s.FailureDomains(),
 			AdditionalTags:       s.AdditionalTags(),
 		},
 	}
@@ -281,6 +336,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			BackendPoolName:      s.NodeOutboundLB().BackendPool.Name,
 			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
 			Role:                 infrav1.NodeOutboundRole,
+			FailureDomains:       s.FailureDomains(),
 			AdditionalTags:       s.AdditionalTags(),
 		})
 	}
@@ -302,6 +358,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			BackendPoolName:      s.ControlPlaneOutboundLB().BackendPool.Name,
 			IdleTimeoutInMinutes: s.ControlPlaneOutboundLB().IdleTimeoutInMinutes,
 			Role:                 infrav1.ControlPlaneOutboundRole,
+			FailureDomains:       s.FailureDomains(),
 			AdditionalTags:       s.AdditionalTags(),
 		})
 	}
@@ -343,10 +400,12 @@ func (s *ClusterScope) NatGatewaySpecs() []azure.ASOResourceSpecGetter[*asonetwo
 					SubscriptionID: s.SubscriptionID(),
 					Location:       s.Location(),
 					ClusterName:    s.ClusterName(),
+					Zone:           ptr.Deref(subnet.NatGateway.Zone, ""),
 					NatGatewayIP: infrav1.PublicIPSpec{
 						Name: subnet.NatGateway.NatGatewayIP.Name,
 					},
-					AdditionalTags: s.AdditionalTags(),
+					IdleTimeoutInMinutes: subnet.NatGateway.IdleTimeoutInMinutes,
+					AdditionalTags:       s.AdditionalTags(),
 					// We need to know if the VNet is managed to decide if this NAT Gateway was-managed or not.
 					IsVnetManaged: s.IsVnetManaged(),
 				})
@@ -361,9 +420,13 @@ func (s *ClusterScope) NatGatewaySpecs() []azure.ASOResourceSpecGetter[*asonetwo
 func (s *ClusterScope) NSGSpecs() []azure.ResourceSpecGetter {
 	nsgspecs := make([]azure.ResourceSpecGetter, len(s.AzureCluster.Spec.NetworkSpec.Subnets))
 	for i, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		securityRules := subnet.SecurityGroup.SecurityRules
+		if subnet.Role == infrav1.SubnetControlPlane {
+			securityRules = s.withAdditionalAPIServerPortRules(securityRules)
+		}
 		nsgspecs[i] = &securitygroups.NSGSpec{
 			Name:                     subnet.SecurityGroup.Name,
-			SecurityRules:            subnet.SecurityGroup.SecurityRules,
+			SecurityRules:            securityRules,
 			ResourceGroup:            s.Vnet().ResourceGroup,
 			Location:                 s.Location(),
 			ClusterName:              s.ClusterName(),
@@ -375,6 +438,62 @@ func (s *ClusterScope) NSGSpecs() []azure.ResourceSpecGetter {
 	return nsgspecs
 }
 
+// withAdditionalAPIServerPortRules returns the security rules with a rule allowing each additional port of the API
+// server load balancer appended. Rules already present with the same name are left untouched. The priority of an added
+// rule is derived from its name, so that reordering the ports doesn't change the priorities of their rules. When the
+// priority is used by another rule, the next free one is taken.
+func (s *ClusterScope) withAdditionalAPIServerPortRules(rules infrav1.SecurityRules) infrav1.SecurityRules {
+	if len(s.APIServerLB().AdditionalAPIServerLBPorts) == 0 {
+		return rules
+	}
+	// Sort the ports by name so that colliding priorities are resolved the same way whatever the order of the ports.
+	ports := slices.Clone(s.APIServerLB().AdditionalAPIServerLBPorts)
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+
+	names := make(map[string]bool, len(rules))
+	priorities := make(map[int32]bool, len(rules))
+	for _, rule := range rules {
+		names[rule.Name] = true
+		priorities[rule.Priority] = true
+	}
+
+	// Copy the rules so that the spec of the AzureCluster isn't modified.
+	result := make(infrav1.SecurityRules, len(rules), len(rules)+len(ports))
+	copy(result, rules)
+	for _, port := range ports {
+		name := additionalAPIServerPortRulePrefix + port.Name
+		if names[name] {
+			continue
+		}
+		priority := additionalAPIServerPortRulePriorityFor(name)
+		for priorities[priority] {
+			priority = additionalAPIServerPortRulePriority + (priority-additionalAPIServerPortRulePriority+1)%additionalAPIServerPortRulePriorities
+		}
+		priorities[priority] = true
+		result = append(result, infrav1.SecurityRule{
+			Name:             name,
+			Description:      fmt.Sprintf("Allow additional API Server load balancer port %s", port.Name),
+			Priority:         priority,
+			Protocol:         infrav1.SecurityGroupProtocolTCP,
+			Direction:        infrav1.SecurityRuleDirectionInbound,
+			Source:           ptr.To("*"),
+			SourcePorts:      ptr.To("*"),
+			Destination:      ptr.To("*"),
+			DestinationPorts: ptr.To(strconv.Itoa(int(ptr.Deref(port.TargetPort, port.Port)))),
+			Action:           infrav1.SecurityRuleActionAllow,
+		})
+	}
+	return result
+}
+
+// additionalAPIServerPortRulePriorityFor returns the priority of the security rule of an additional API server port
+// derived from the name of the rule.
+func additionalAPIServerPortRulePriorityFor(name string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return additionalAPIServerPortRulePriority + int32(h.Sum32()%uint32(additionalAPIServerPortRulePriorities))
+}
+
 // SubnetSpecs returns the subnets specs.
 func (s *ClusterScope) SubnetSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet] {
 	numberOfSubnets := len(s.AzureCluster.Spec.NetworkSpec.Subnets)
@@ -385,6 +504,10 @@ func (s *ClusterScope) SubnetSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1
 	subnetSpecs := make([]azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet], 0, numberOfSubnets)
 
 	for _, subnet := range s.AzureCluster.Spec.NetworkSpec.Subnets {
+		// Subnets can't be created before their address space is allocated from the IPAM pool.
+		if subnet.IPAMPoolRef != nil && len(subnet.CIDRBlocks) == 0 {
+			continue
+		}
 		subnetSpec := &subnets.SubnetSpec{
 			Name:              subnet.Name,
 			ResourceGroup:     s.ResourceGroup(),
@@ -441,6 +564,45 @@ func (s *ClusterScope) GroupSpecs() []azure.ASOResourceSpecGetter[*asoresourcesv
 	return specs
 }
 
+// ResourceLockSpecs returns the specs of the management locks on the critical resources of the cluster.
+func (s *ClusterScope) ResourceLockSpecs() []azure.ResourceSpecGetter {
+	resourceLocks := s.AzureCluster.Spec.ResourceLocks
+	if resourceLocks == nil {
+		return nil
+	}
+	level := resourceLocks.Level
+	if level == "" {
+		level = infrav1.ResourceLockLevelCanNotDelete
+	}
+
+	var specs []azure.ResourceSpecGetter
+	for _, kind := range resourceLocks.Scope {
+		var resourceGroup, scope string
+		switch kind {
+		case infrav1.ResourceLockScopeVNet:
+			resourceGroup = s.Vnet().ResourceGroup
+			scope = azure.VNetID(s.SubscriptionID(), resourceGroup, s.Vnet().Name)
+		case infrav1.ResourceLockScopePublicIP:
+			if s.IsAPIServerPrivate() || len(s.APIServerLB().FrontendIPs) == 0 || s.APIServerPublicIP() == nil {
+				continue
+			}
+			resourceGroup = s.ResourceGroup()
+			scope = azure.PublicIPID(s.SubscriptionID(), resourceGroup, s.APIServerPublicIP().Name)
+		default:
+			continue
+		}
+		specs = append(specs, &locks.LockSpec{
+			Name:          azure.GenerateResourceLockName(s.ClusterName(), string(kind)),
+			ResourceGroup: resourceGroup,
+			Scope:         scope,
+			Level:         level,
+			ClusterName:   s.ClusterName(),
+		})
+	}
+
+	return specs
+}
+
 // VnetPeeringSpecs returns the virtual network peering specs.
 func (s *ClusterScope) VnetPeeringSpecs() []azure.ResourceSpecGetter {
 	peeringSpecs := make([]azure.ResourceSpecGetter, 2*len(s.Vnet().Peerings))
@@ -486,6 +648,16 @@ func (s *ClusterScope) VNetSpec() azure.ASOResourceSpecGetter[*asonetworkv1api20
 		Location:         s.Location(),
 		ClusterName:      s.ClusterName(),
 		AdditionalTags:   s.AdditionalTags(),
+		IsManaged: func(ownedByTags bool) bool {
+			return s.IsManagedResource(azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name), ownedByTags)
+		},
+		GetPeeredAddressSpaces: func(ctx context.Context) ([]string, error) {
+			client, err := vnetpeerings.NewClient(s, s.DefaultedAzureCallTimeout())
+			if err != nil {
+				return nil, err
+			}
+			return client.ListRemoteAddressSpaces(ctx, s.Vnet().ResourceGroup, s.Vnet().Name)
+		},
 	}
 }
 
@@ -499,7 +671,12 @@ func (s *ClusterScope) PrivateDNSSpec() (zoneSpec azure.ResourceSpecGetter, link
 			AdditionalTags: s.AdditionalTags(),
 		}
 
-		links := make([]azure.ResourceSpecGetter, 1+len(s.Vnet().Peerings))
+		var additionalVnetLinks []string
+		if s.AzureCluster.Spec.NetworkSpec.PrivateDNSZone != nil {
+			additionalVnetLinks = s.AzureCluster.Spec.NetworkSpec.PrivateDNSZone.AdditionalVnetLinks
+		}
+
+		links := make([]azure.ResourceSpecGetter, 1+len(s.Vnet().Peerings), 1+len(s.Vnet().Peerings)+len(additionalVnetLinks))
 		links[0] = privatedns.LinkSpec{
 			Name:              azure.GenerateVNetLinkName(s.Vnet().Name),
 			ZoneName:          s.GetPrivateDNSZoneName(),
@@ -522,6 +699,23 @@ func (s *ClusterScope) PrivateDNSSpec() (zoneSpec azure.ResourceSpecGetter, link
 				AdditionalTags:    s.AdditionalTags(),
 			}
 		}
+		for _, vnetID := range additionalVnetLinks {
+			// The webhook guarantees the IDs are valid virtual network resource IDs.
+			id, err := arm.ParseResourceID(vnetID)
+			if err != nil {
+				continue
+			}
+			links = append(links, privatedns.LinkSpec{
+				Name:              azure.GenerateVNetLinkNameWithID(id.Name, vnetID),
+				ZoneName:          s.GetPrivateDNSZoneName(),
+				SubscriptionID:    id.SubscriptionID,
+				VNetResourceGroup: id.ResourceGroupName,
+				VNetName:          id.Name,
+				ResourceGroup:     s.ResourceGroup(),
+				ClusterName:       s.ClusterName(),
+				AdditionalTags:    s.AdditionalTags(),
+			})
+		}
 
 		records := make([]azure.ResourceSpecGetter, 1)
 		records[0] = privatedns.RecordSpec{
@@ -556,20 +750,81 @@ func (s *ClusterScope) AzureBastionSpec() azure.ASOResourceSpecGetter[*asonetwor
 		publicIPID := azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), s.AzureBastion().PublicIP.Name)
 
 		return &bastionhosts.AzureBastionSpec{
-			Name:            s.AzureBastion().Name,
-			ResourceGroup:   s.ResourceGroup(),
-			Location:        s.Location(),
-			ClusterName:     s.ClusterName(),
-			SubnetID:        subnetID,
-			PublicIPID:      publicIPID,
-			Sku:             s.AzureBastion().Sku,
-			EnableTunneling: s.AzureBastion().EnableTunneling,
+			Name:                s.AzureBastion().Name,
+			ResourceGroup:       s.ResourceGroup(),
+			Location:            s.Location(),
+			ClusterName:         s.ClusterName(),
+			SubnetID:            subnetID,
+			PublicIPID:          publicIPID,
+			Sku:                 s.AzureBastion().Sku,
+			EnableTunneling:     s.AzureBastion().EnableTunneling,
+			EnableShareableLink: s.AzureBastion().EnableShareableLink,
 		}
 	}
 
 	return nil
 }
 
+// APIServerDNSARecordSpecs returns the A record of the API server in an Azure DNS zone. The record of a public API
+// server is an alias of its public IP, so that it follows the address of the public IP.
+func (s *ClusterScope) APIServerDNSARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord] {
+	apiServerDNS := s.AzureCluster.Spec.APIServerDNS
+	if apiServerDNS == nil {
+		return nil
+	}
+	spec := &dnsrecords.ARecordSpec{
+		Name:             azure.GenerateAPIServerDNSRecordName(s.ClusterName()),
+		ZoneID:           apiServerDNS.ZoneResourceID,
+		RecordName:       apiServerDNS.RecordName,
+		TTL:              ptr.Deref(apiServerDNS.TTL, infrav1.DefaultAPIServerDNSTTL),
+		CredentialSecret: s.apiServerDNSCredentialSecret(),
+	}
+	switch {
+	case !s.IsAPIServerPrivate():
+		spec.PublicIPID = azure.PublicIPID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerPublicIP().Name)
+	case net.IsIPv4String(s.APIServerPrivateIP()):
+		spec.IPAddress = s.APIServerPrivateIP()
+	default:
+		return nil
+	}
+	return []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord]{spec}
+}
+
+// APIServerDNSAAAARecordSpecs returns the AAAA record of an internal API server with an IPv6 address in an Azure DNS
+// zone.
+func (s *ClusterScope) APIServerDNSAAAARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesAAAARecord] {
+	apiServerDNS := s.AzureCluster.Spec.APIServerDNS
+	if apiServerDNS == nil || !s.IsAPIServerPrivate() || !net.IsIPv6String(s.APIServerPrivateIP()) {
+		return nil
+	}
+	return []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesAAAARecord]{
+		&dnsrecords.AAAARecordSpec{
+			Name:             azure.GenerateAPIServerDNSRecordName(s.ClusterName()),
+			ZoneID:           apiServerDNS.ZoneResourceID,
+			RecordName:       apiServerDNS.RecordName,
+			TTL:              ptr.Deref(apiServerDNS.TTL, infrav1.DefaultAPIServerDNSTTL),
+			IPAddress:        s.APIServerPrivateIP(),
+			CredentialSecret: s.apiServerDNSCredentialSecret(),
+		},
+	}
+}
+
+// APIServerDNSCNAMERecordSpecs returns no CNAME record, since the record of the API server of a self-managed cluster
+// points at its load balancer.
+func (s *ClusterScope) APIServerDNSCNAMERecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesCNAMERecord] {
+	return nil
+}
+
+// apiServerDNSCredentialSecret returns the name of the ASO credential secret for the subscription of the DNS zone
+// of the API server record when it isn't the subscription of the cluster.
+func (s *ClusterScope) apiServerDNSCredentialSecret() string {
+	zoneSubscriptionID := s.AzureCluster.Spec.APIServerDNS.ZoneSubscriptionID()
+	if zoneSubscriptionID == "" || strings.EqualFold(zoneSubscriptionID, s.SubscriptionID()) {
+		return ""
+	}
+	return aso.GetASOSecretNameForSubscription(s.ClusterName(), zoneSubscriptionID)
+}
+
 // Vnet returns the cluster Vnet.
 func (s *ClusterScope) Vnet() *infrav1.VnetSpec {
 	return &s.AzureCluster.Spec.NetworkSpec.Vnet
@@ -580,11 +835,60 @@ func (s *ClusterScope) IsVnetManaged() bool {
 	if s.cache.isVnetManaged != nil {
 		return ptr.Deref(s.cache.isVnetManaged, false)
 	}
-	isVnetManaged := s.Vnet().ID == "" || s.Vnet().Tags.HasOwned(s.ClusterName())
+	isVnetManaged := s.Vnet().ID == "" || s.IsManagedResource(s.Vnet().ID, s.Vnet().Tags.HasOwned(s.ClusterName()))
 	s.cache.isVnetManaged = ptr.To(isVnetManaged)
 	return isVnetManaged
 }
 
+// InitManagedResources starts recording the Azure resources created by CAPZ for a cluster whose network has not
+// been reconciled yet. Clusters created before CAPZ recorded the resources it creates keep relying on tags.
+func (s *ClusterScope) InitManagedResources() {
+	if s.AzureCluster.Status.ManagedResources == nil && s.Vnet().ID == "" {
+		s.AzureCluster.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+}
+
+// IsOwnershipRecorded returns true if the Azure resources created by CAPZ are recorded for the cluster.
+func (s *ClusterScope) IsOwnershipRecorded() bool {
+	return s.AzureCluster.Status.ManagedResources != nil
+}
+
+// RecordManagedResource records that CAPZ created the Azure resource with the given ID.
+func (s *ClusterScope) RecordManagedResource(id string) {
+	s.AzureCluster.Status.ManagedResources.Add(id)
+}
+
+// ForgetManagedResource removes the Azure resource with the given ID from the resources recorded as created by CAPZ
+// once it has been deleted.
+func (s *ClusterScope) ForgetManagedResource(id string) {
+	s.AzureCluster.Status.ManagedResources.Remove(id)
+}
+
+// RecordManagedResourceDeletion records that the recorded Azure resource with the given ID is being deleted by the
+// resource with the given key.
+func (s *ClusterScope) RecordManagedResourceDeletion(key, id string) {
+	s.AzureCluster.Status.ManagedResources.MarkDeleting(key, id)
+}
+
+// ManagedResourceDeletion returns the ID of the recorded Azure resource being deleted by the resource with the given
+// key.
+func (s *ClusterScope) ManagedResourceDeletion(key string) string {
+	return s.AzureCluster.Status.ManagedResources.DeletingID(key)
+}
+
+// IsManagedResource returns true if the lifecycle of the Azure resource with the given ID is managed by CAPZ.
+// When the resources created by CAPZ are recorded, only recorded resources are managed unless forced deletion of
+// unmanaged resources is enabled. Otherwise, ownedByTags, the result of the legacy tag-based check, is returned.
+func (s *ClusterScope) IsManagedResource(id string, ownedByTags bool) bool {
+	if !s.IsOwnershipRecorded() {
+		return ownedByTags
+	}
+	if s.AzureCluster.Status.ManagedResources.Has(id) {
+		return true
+	}
+	return s.forceDeleteUnmanaged && ownedByTags
+}
+
 // IsIPv6Enabled returns true if IPv6 is enabled.
 func (s *ClusterScope) IsIPv6Enabled() bool {
 	for _, cidr := range s.AzureCluster.Spec.NetworkSpec.Vnet.CIDRBlocks {
@@ -679,9 +983,13 @@ func (s *ClusterScope) NodeOutboundLB() *infrav1.LoadBalancerSpec {
 	return s.AzureCluster.Spec.NetworkSpec.NodeOutboundLB
 }
 
-// ControlPlaneOutboundLB returns the cluster control plane outbound load balancer.
+// ControlPlaneOutboundLB returns the cluster control plane outbound load balancer, or nil when it is disabled.
 func (s *ClusterScope) ControlPlaneOutboundLB() *infrav1.LoadBalancerSpec {
-	return s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB
+	lb := s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB
+	if lb == nil || lb.Disabled {
+		return nil
+	}
+	return lb
 }
 
 // APIServerLBName returns the API Server LB name.
@@ -704,6 +1012,142 @@ func (s *ClusterScope) APIServerPrivateIP() string {
 	return s.APIServerLB().FrontendIPs[0].PrivateIPAddress
 }
 
+// APIServerLBMigration returns the progress of the migration of the API server load balancer from Public to
+// Internal, or nil if it isn't migrated.
+func (s *ClusterScope) APIServerLBMigration() *infrav1.APIServerLBMigration {
+	return s.AzureCluster.Status.APIServerLBMigration
+}
+
+// IsAPIServerLBMigrationRequested returns true if the migration of the API server load balancer from Public to
+// Internal is requested with the MigrateAPIServerLBToInternalAnnotation.
+func (s *ClusterScope) IsAPIServerLBMigrationRequested() bool {
+	return s.AzureCluster.GetAnnotations()[infrav1.MigrateAPIServerLBToInternalAnnotation] == "true"
+}
+
+// StartAPIServerLBMigration records the public API server load balancer to be replaced by an internal one, so that
+// it can be migrated once the type of the API server load balancer is changed to Internal.
+func (s *ClusterScope) StartAPIServerLBMigration() {
+	lb := s.APIServerLB()
+	migration := &infrav1.APIServerLBMigration{
+		Phase:                   infrav1.APIServerLBMigrationPending,
+		PublicLBName:            lb.Name,
+		PublicLBBackendPoolName: lb.BackendPool.Name,
+	}
+	for _, frontendIP := range lb.FrontendIPs {
+		if migration.PublicLBFrontendIPName == "" {
+			migration.PublicLBFrontendIPName = frontendIP.Name
+		}
+		if frontendIP.PublicIP != nil {
+			migration.PublicIPNames = append(migration.PublicIPNames, frontendIP.PublicIP.Name)
+		}
+	}
+	s.AzureCluster.Status.APIServerLBMigration = migration
+	s.setAPIServerLBMigrationConditions()
+}
+
+// SetAPIServerLBMigrationPhase sets the phase of the migration of the API server load balancer, and the conditions
+// reporting it.
+func (s *ClusterScope) SetAPIServerLBMigrationPhase(phase infrav1.APIServerLBMigrationPhase) {
+	if s.AzureCluster.Status.APIServerLBMigration == nil {
+		return
+	}
+	s.AzureCluster.Status.APIServerLBMigration.Phase = phase
+	s.setAPIServerLBMigrationConditions()
+}
+
+// SetAPIServerLBMigrationProgress reports what the current phase of the migration of the API server load balancer is
+// waiting for on the condition of the phase.
+func (s *ClusterScope) SetAPIServerLBMigrationProgress(reason, message string) {
+	migration := s.APIServerLBMigration()
+	if migration == nil {
+		return
+	}
+	if current := apiServerLBMigrationPhaseIndex(migration.Phase); current < len(apiServerLBMigrationPhaseConditions) {
+		conditions.MarkFalse(s.AzureCluster, apiServerLBMigrationPhaseConditions[current], reason, clusterv1.ConditionSeverityInfo, message)
+	}
+}
+
+// ClearAPIServerLBMigration forgets the migration of the API server load balancer, and removes the conditions
+// reporting it.
+func (s *ClusterScope) ClearAPIServerLBMigration() {
+	s.AzureCluster.Status.APIServerLBMigration = nil
+	s.setAPIServerLBMigrationConditions()
+}
+
+// apiServerLBMigrationPhaseConditions are the conditions reporting the phases of the migration of the API server load
+// balancer, in order.
+var apiServerLBMigrationPhaseConditions = []clusterv1.ConditionType{
+	infrav1.InternalAPIServerLBReadyCondition,
+	infrav1.ControlPlaneEndpointMigratedCondition,
+	infrav1.PublicAPIServerLBDeletedCondition,
+}
+
+// apiServerLBMigrationPhaseIndex returns the index in apiServerLBMigrationPhaseConditions of the condition reporting
+// phase, or the number of conditions once the migration is completed.
+func apiServerLBMigrationPhaseIndex(phase infrav1.APIServerLBMigrationPhase) int {
+	switch phase {
+	case infrav1.APIServerLBMigrationPending, infrav1.APIServerLBMigrationCreatingInternalLB:
+		return 0
+	case infrav1.APIServerLBMigrationSwitchingEndpoint, infrav1.APIServerLBMigrationReplacingMachines:
+		return 1
+	case infrav1.APIServerLBMigrationDeletingPublicLB:
+		return 2
+	default:
+		return len(apiServerLBMigrationPhaseConditions)
+	}
+}
+
+// setAPIServerLBMigrationConditions sets a condition for each phase of the migration of the API server load balancer
+// that is reached: it is false while the phase is in progress, and true once it is done.
+func (s *ClusterScope) setAPIServerLBMigrationConditions() {
+	migration := s.APIServerLBMigration()
+	if migration == nil {
+		for _, condition := range apiServerLBMigrationPhaseConditions {
+			conditions.Delete(s.AzureCluster, condition)
+		}
+		return
+	}
+
+	var reason, message string
+	switch migration.Phase {
+	case infrav1.APIServerLBMigrationPending:
+		reason = infrav1.WaitingForLoadBalancerTypeChangeReason
+		message = "set the type of the API server load balancer to Internal to start the migration"
+	case infrav1.APIServerLBMigrationCreatingInternalLB:
+		reason = infrav1.InternalAPIServerLBCreatingReason
+	case infrav1.APIServerLBMigrationSwitchingEndpoint:
+		reason = infrav1.InternalAPIServerLBUnavailableReason
+	case infrav1.APIServerLBMigrationReplacingMachines:
+		reason = infrav1.WaitingForMachinesReplacementReason
+	case infrav1.APIServerLBMigrationDeletingPublicLB:
+		reason = infrav1.PublicAPIServerLBDeletingReason
+		message = "waiting for the control plane machines to be removed from the public load balancer"
+	}
+	current := apiServerLBMigrationPhaseIndex(migration.Phase)
+	for i, condition := range apiServerLBMigrationPhaseConditions {
+		switch {
+		case i < current:
+			conditions.MarkTrue(s.AzureCluster, condition)
+		case i == current:
+			conditions.MarkFalse(s.AzureCluster, condition, reason, clusterv1.ConditionSeverityInfo, message)
+		default:
+			conditions.Delete(s.AzureCluster, condition)
+		}
+	}
+}
+
+// RestrictAPIServerSecurityRule restricts the default security rule allowing the API server port from any source to
+// the virtual network, once the API server is no longer reachable from the Internet.
+func (s *ClusterScope) RestrictAPIServerSecurityRule() {
+	subnet := s.ControlPlaneSubnet()
+	for i, rule := range subnet.SecurityGroup.SecurityRules {
+		if rule.Name == apiServerSecurityRuleName && ptr.Deref(rule.Source, "*") == "*" {
+			subnet.SecurityGroup.SecurityRules[i].Source = ptr.To(virtualNetworkServiceTag)
+		}
+	}
+	s.AzureCluster.Spec.NetworkSpec.UpdateControlPlaneSubnet(subnet)
+}
+
 // GetPrivateDNSZoneName returns the Private DNS Zone from the spec or generate it from cluster name.
 func (s *ClusterScope) GetPrivateDNSZoneName() string {
 	if len(s.AzureCluster.Spec.NetworkSpec.PrivateDNSZoneName) > 0 {
@@ -782,6 +1226,21 @@ func (s *ClusterScope) CloudProviderConfigOverrides() *infrav1.CloudProviderConf
 	return s.AzureCluster.Spec.CloudProviderConfigOverrides
 }
 
+// DefaultImage returns the image used for machines in the cluster that don't specify an image.
+func (s *ClusterScope) DefaultImage() *infrav1.DefaultImage {
+	return s.AzureCluster.Spec.DefaultImage
+}
+
+// ClusterVMExtensions returns the VM extensions installed on all the machines in the cluster.
+func (s *ClusterScope) ClusterVMExtensions() []infrav1.ClusterVMExtension {
+	return s.AzureCluster.Spec.VMExtensions
+}
+
+// NamingTemplate returns the templates rendering the names of the Azure resources of the cluster.
+func (s *ClusterScope) NamingTemplate() *infrav1.NamingTemplate {
+	return s.AzureCluster.Spec.NamingTemplate
+}
+
 // ExtendedLocationName returns ExtendedLocation name for the cluster.
 func (s *ClusterScope) ExtendedLocationName() string {
 	if s.ExtendedLocation() == nil {
@@ -810,7 +1269,12 @@ func (s *ClusterScope) GenerateFQDN(ipName string) string {
 		return ""
 	}
 	hash := fmt.Sprintf("%x", h.Sum32())
-	return strings.ToLower(fmt.Sprintf("%s-%s.%s.%s", s.ClusterName(), hash, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix))
+	return s.GenerateFQDNFromLabel(fmt.Sprintf("%s-%s", s.ClusterName(), hash))
+}
+
+// GenerateFQDNFromLabel generates the fully qualified domain name of a public IP with the DNS label in the cluster location.
+func (s *ClusterScope) GenerateFQDNFromLabel(label string) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s", label, s.Location(), s.AzureClients.ResourceManagerVMDNSSuffix))
 }
 
 // GenerateLegacyFQDN generates an IP name and a fully qualified domain name, based on a hash, cluster name and cluster location.
@@ -838,6 +1302,7 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 	defer done()
 
 	conditions.SetSummary(s.AzureCluster)
+	conditionsutils.SetV1Beta2Conditions(s.AzureCluster)
 
 	return s.patchHelper.Patch(
 		ctx,
@@ -859,6 +1324,9 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 			infrav1.PrivateDNSLinkReadyCondition,
 			infrav1.PrivateDNSRecordReadyCondition,
 			infrav1.PrivateEndpointsReadyCondition,
+			infrav1.ResourceLocksReadyCondition,
+			infrav1.PrimaryIdentityAuthenticatedCondition,
+			infrav1.RegionDegradedCondition,
 		}})
 }
 
@@ -886,6 +1354,9 @@ func (s *ClusterScope) APIServerPort() int32 {
 
 // APIServerHost returns the hostname used to reach the API server.
 func (s *ClusterScope) APIServerHost() string {
+	if apiServerDNS := s.AzureCluster.Spec.APIServerDNS; apiServerDNS != nil && apiServerDNS.UseForControlPlaneEndpoint {
+		return apiServerDNS.FQDN()
+	}
 	if s.IsAPIServerPrivate() {
 		return azure.GeneratePrivateFQDN(s.GetPrivateDNSZoneName())
 	}
@@ -923,6 +1394,50 @@ func (s *ClusterScope) FailureDomains() []*string {
 	return fds
 }
 
+// ControlPlaneVMSize returns the VM size of the AzureMachineTemplate referenced by the machine template of the
+// control plane of the cluster, e.g. a KubeadmControlPlane. It returns an empty size when the cluster has no control
+// plane yet, when the controller isn't allowed to read the control plane, as it is only granted access to
+// KubeadmControlPlanes, or when its machines are not created from an AzureMachineTemplate.
+func (s *ClusterScope) ControlPlaneVMSize(ctx context.Context) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.ControlPlaneVMSize")
+	defer done()
+
+	if s.Cluster.Spec.ControlPlaneRef == nil {
+		return "", nil
+	}
+	controlPlane, err := external.Get(ctx, s.Client, s.Cluster.Spec.ControlPlaneRef, s.Namespace())
+	if apierrors.IsNotFound(errors.Cause(err)) {
+		return "", nil
+	}
+	if apierrors.IsForbidden(errors.Cause(err)) {
+		log.V(4).Info("not allowed to get the control plane of the cluster, ignoring its VM size", "kind", s.Cluster.Spec.ControlPlaneRef.Kind)
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the control plane of the cluster")
+	}
+
+	// Control planes creating machines, such as KubeadmControlPlane, reference their infrastructure template there.
+	ref, _, _ := unstructured.NestedStringMap(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef")
+	gv, err := schema.ParseGroupVersion(ref["apiVersion"])
+	if err != nil || gv.Group != infrav1.GroupVersion.Group || ref["kind"] != infrav1.AzureMachineTemplateKind {
+		return "", nil //nolint:nilerr // The machines of the control plane are not created from an AzureMachineTemplate.
+	}
+
+	namespace := ref["namespace"]
+	if namespace == "" {
+		namespace = controlPlane.GetNamespace()
+	}
+	template := &infrav1.AzureMachineTemplate{}
+	if err := s.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref["name"]}, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get AzureMachineTemplate %s/%s", namespace, ref["name"])
+	}
+	return template.Spec.Template.Spec.VMSize, nil
+}
+
 // SetControlPlaneSecurityRules sets the default security rules of the control plane subnet.
 // Note that this is not done in a webhook as it requires a valid Cluster object to exist to get the API Server port.
 func (s *ClusterScope) SetControlPlaneSecurityRules() {
@@ -942,7 +1457,7 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				Action:           infrav1.SecurityRuleActionAllow,
 			},
 			infrav1.SecurityRule{
-				Name:             "allow_apiserver",
+				Name:             apiServerSecurityRuleName,
 				Description:      "Allow K8s API Server",
 				Priority:         2201,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
@@ -984,13 +1499,36 @@ func (s *ClusterScope) SetDNSName() {
 		}
 		lb.DeepCopyInto(s.APIServerLB())
 	}
-	// Generate valid FQDN if not set.
-	// Note: this function uses the AzureCluster subscription ID.
-	if !s.IsAPIServerPrivate() && s.APIServerPublicIP().DNSName == "" {
-		s.APIServerPublicIP().DNSName = s.GenerateFQDN(s.APIServerPublicIP().Name)
+	if s.IsAPIServerPrivate() {
+		return
+	}
+	publicIP := s.APIServerPublicIP()
+	switch {
+	case publicIP.DNSName == "" && s.AzureCluster.Status.APIServerDNSLabel != "":
+		// Reuse the DNS label generated for a previous public IP, so that the FQDN of the API server remains stable
+		// when the public IP is recreated.
+		publicIP.DNSName = s.GenerateFQDNFromLabel(s.AzureCluster.Status.APIServerDNSLabel)
+	case publicIP.DNSName == "":
+		// Generate valid FQDN if not set.
+		// Note: this function uses the AzureCluster subscription ID.
+		publicIP.DNSName = s.GenerateFQDN(publicIP.Name)
+		s.AzureCluster.Status.APIServerDNSLabel = strings.Split(publicIP.DNSName, ".")[0]
+	case !strings.Contains(publicIP.DNSName, "."):
+		// Only the DNS label was specified.
+		publicIP.DNSName = s.GenerateFQDNFromLabel(publicIP.DNSName)
 	}
 }
 
+// SetOutboundIPs sets the public IP addresses the cluster egresses from.
+func (s *ClusterScope) SetOutboundIPs(addresses []string) {
+	s.AzureCluster.Status.OutboundIPs = addresses
+}
+
+// SetConditionFalse sets the specified AzureCluster condition to false.
+func (s *ClusterScope) SetConditionFalse(conditionType clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string) {
+	conditions.MarkFalse(s.AzureCluster, conditionType, reason, severity, message)
+}
+
 // SetLongRunningOperationState will set the future on the AzureCluster status to allow the resource to continue
 // in the next reconciliation.
 func (s *ClusterScope) SetLongRunningOperationState(future *infrav1.Future) {
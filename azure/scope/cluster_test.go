@@ -23,20 +23,28 @@ import (
 	"strings"
 	"testing"
 
+	asonetworkv1api20180501 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
 	asonetworkv1api20201101 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	asonetworkv1api20220701 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
@@ -44,7 +52,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 const fakeClientID = "fake-client-id"
@@ -157,6 +168,39 @@ func TestAPIServerHost(t *testing.T) {
 			},
 			want: "apiserver.example.private",
 		},
+		{
+			name: "public apiserver lb with a record used for the control plane endpoint",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: fakeSubscriptionID,
+						IdentityRef: &corev1.ObjectReference{
+							Kind: infrav1.AzureClusterIdentityKind,
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										DNSName: "my-cluster-apiserver.example.com",
+									},
+								},
+							},
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Public,
+							},
+						},
+					},
+					APIServerDNS: &infrav1.APIServerDNS{
+						ZoneResourceID:             "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/company.com",
+						RecordName:                 "api.cluster1",
+						UseForControlPlaneEndpoint: true,
+					},
+				},
+			},
+			want: "api.cluster1.company.com",
+		},
 	}
 
 	for _, tc := range tests {
@@ -377,6 +421,55 @@ func TestPublicIPSpecs(t *testing.T) {
 			},
 			expectedPublicIPSpec: nil,
 		},
+		{
+			name: "Azure cluster with internal type apiserver LB and disabled control plane outbound LB",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					FailureDomains: map[string]clusterv1.FailureDomainSpec{
+						"failure-domain-id-1": {},
+						"failure-domain-id-2": {},
+						"failure-domain-id-3": {},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "centralIndia",
+						AdditionalTags: infrav1.Tags{
+							"Name": "my-publicip-ipv6",
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+						},
+						IdentityRef: &corev1.ObjectReference{
+							Kind: infrav1.AzureClusterIdentityKind,
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						ControlPlaneOutboundLB: &infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Disabled: true,
+							},
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								Type: infrav1.Internal,
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: nil,
+		},
 		{
 			name: "Azure cluster with internal type apiserver LB and 1 frontend IP count",
 			azureCluster: &infrav1.AzureCluster{
@@ -444,6 +537,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 			},
 		},
@@ -526,6 +620,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 				&publicips.PublicIPSpec{
 					Name:           "pip-my-cluster-controlplane-outbound-2",
@@ -539,6 +634,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 				&publicips.PublicIPSpec{
 					Name:           "pip-my-cluster-controlplane-outbound-3",
@@ -552,6 +648,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 			},
 		},
@@ -619,6 +716,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 			},
 		},
@@ -689,6 +787,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 			},
 		},
@@ -780,6 +879,7 @@ func TestPublicIPSpecs(t *testing.T) {
 						"Name": "my-publicip-ipv6",
 						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
 					},
+					Outbound: true,
 				},
 				&publicips.PublicIPSpec{
 					Name:           "fake-bastion-public-ip",
@@ -796,6 +896,140 @@ func TestPublicIPSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Azure cluster with zonal public IPs",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					FailureDomains: map[string]clusterv1.FailureDomainSpec{
+						"1": {},
+						"2": {},
+						"3": {},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "centralIndia",
+						IdentityRef: &corev1.ObjectReference{
+							Kind: infrav1.AzureClusterIdentityKind,
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								SubnetClassSpec: infrav1.SubnetClassSpec{
+									Role: infrav1.SubnetNode,
+								},
+								NatGateway: infrav1.NatGateway{
+									NatGatewayIP: infrav1.PublicIPSpec{
+										Name:  "fake-natgw-public-ip",
+										Zones: []string{"3"},
+									},
+									NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
+										Name: "fake-natgw",
+									},
+								},
+							},
+						},
+						APIServerLB: infrav1.LoadBalancerSpec{
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "40.60.89.22",
+										DNSName: "fake-dns",
+										Zones:   []string{"1"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: []azure.ResourceSpecGetter{
+				&publicips.PublicIPSpec{
+					Name:           "40.60.89.22",
+					ResourceGroup:  "my-rg",
+					DNSName:        "fake-dns",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []*string{ptr.To("1")},
+					AdditionalTags: infrav1.Tags{},
+					Outbound:       true,
+				},
+				&publicips.PublicIPSpec{
+					Name:           "fake-natgw-public-ip",
+					ResourceGroup:  "my-rg",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "centralIndia",
+					FailureDomains: []*string{ptr.To("3")},
+					AdditionalTags: infrav1.Tags{},
+					Outbound:       true,
+				},
+			},
+		},
+		{
+			name: "Azure cluster in a location without availability zones",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						Location:       "westcentralus",
+						IdentityRef: &corev1.ObjectReference{
+							Kind: infrav1.AzureClusterIdentityKind,
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "40.60.89.22",
+										DNSName: "fake-dns",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: []azure.ResourceSpecGetter{
+				&publicips.PublicIPSpec{
+					Name:           "40.60.89.22",
+					ResourceGroup:  "my-rg",
+					DNSName:        "fake-dns",
+					IsIPv6:         false,
+					ClusterName:    "my-cluster",
+					Location:       "westcentralus",
+					FailureDomains: []*string{},
+					AdditionalTags: infrav1.Tags{},
+					Outbound:       true,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -1332,97 +1566,259 @@ func TestNSGSpecs(t *testing.T) {
 				},
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			if got := tt.clusterScope.NSGSpecs(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("RouteTableSpecs() = %s, want %s", specArrayToString(got), specArrayToString(tt.want))
-			}
-		})
-	}
-}
-
-func TestSubnetSpecs(t *testing.T) {
-	tests := []struct {
-		name         string
-		clusterScope ClusterScope
-		want         []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]
-	}{
-		{
-			name: "returns empty if no subnets are specified",
-			clusterScope: ClusterScope{
-				AzureCluster: &infrav1.AzureCluster{
-					Spec: infrav1.AzureClusterSpec{
-						NetworkSpec: infrav1.NetworkSpec{
-							Subnets: infrav1.Subnets{},
-						},
-					},
-				},
-				cache: &ClusterCache{},
-			},
-			want: []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{},
-		},
 		{
-			name: "returns specified subnet spec",
+			name: "opens the additional API server load balancer ports on the control plane subnet",
 			clusterScope: ClusterScope{
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "my-cluster",
 					},
 				},
-				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
-						Values: map[string]string{
-							auth.SubscriptionID: "123",
-						},
-					},
-				},
 				AzureCluster: &infrav1.AzureCluster{
 					Spec: infrav1.AzureClusterSpec{
-						ResourceGroup: "my-rg",
 						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
 							Location: "centralIndia",
-							IdentityRef: &corev1.ObjectReference{
-								Kind: infrav1.AzureClusterIdentityKind,
-							},
 						},
 						NetworkSpec: infrav1.NetworkSpec{
 							Vnet: infrav1.VnetSpec{
-								ID:            "fake-vnet-id-1",
-								Name:          "fake-vnet-1",
-								ResourceGroup: "my-rg-vnet",
+								ResourceGroup: "my-rg",
+							},
+							APIServerLB: infrav1.LoadBalancerSpec{
+								LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+									AdditionalAPIServerLBPorts: []infrav1.LoadBalancerPort{
+										{Name: "konnectivity", Port: 8132},
+										{Name: "metrics", Port: 9443, TargetPort: ptr.To[int32](10250)},
+									},
+								},
 							},
 							Subnets: infrav1.Subnets{
 								{
 									SubnetClassSpec: infrav1.SubnetClassSpec{
-										Role:       infrav1.SubnetNode,
-										CIDRBlocks: []string{"192.168.1.1/16"},
-										Name:       "fake-subnet-1",
-									},
-									NatGateway: infrav1.NatGateway{
-										NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
-											Name: "fake-natgateway-1",
-										},
-									},
-									RouteTable: infrav1.RouteTable{
-										ID:   "fake-route-table-id-1",
-										Name: "fake-route-table-1",
+										Role: infrav1.SubnetControlPlane,
 									},
 									SecurityGroup: infrav1.SecurityGroup{
-										Name: "fake-security-group-1",
+										Name: "cp-nsg",
 										SecurityGroupClass: infrav1.SecurityGroupClass{
 											SecurityRules: infrav1.SecurityRules{
-												{
-													Name: "fake-rule-1",
-												},
+												{Name: "allow_ssh", Priority: 2200},
+												{Name: "allow_apiserver", Priority: 2201},
+												{Name: "custom", Priority: 2206},
 											},
 										},
 									},
 								},
-							},
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role: infrav1.SubnetNode,
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "node-nsg",
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ResourceSpecGetter{
+				&securitygroups.NSGSpec{
+					Name: "cp-nsg",
+					SecurityRules: infrav1.SecurityRules{
+						{Name: "allow_ssh", Priority: 2200},
+						{Name: "allow_apiserver", Priority: 2201},
+						{Name: "custom", Priority: 2206},
+						{
+							Name:             "allow_apiserver_konnectivity",
+							Description:      "Allow additional API Server load balancer port konnectivity",
+							Priority:         2207,
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Source:           ptr.To("*"),
+							SourcePorts:      ptr.To("*"),
+							Destination:      ptr.To("*"),
+							DestinationPorts: ptr.To("8132"),
+							Action:           infrav1.SecurityRuleActionAllow,
+						},
+						{
+							Name:             "allow_apiserver_metrics",
+							Description:      "Allow additional API Server load balancer port metrics",
+							Priority:         2450,
+							Protocol:         infrav1.SecurityGroupProtocolTCP,
+							Direction:        infrav1.SecurityRuleDirectionInbound,
+							Source:           ptr.To("*"),
+							SourcePorts:      ptr.To("*"),
+							Destination:      ptr.To("*"),
+							DestinationPorts: ptr.To("10250"),
+							Action:           infrav1.SecurityRuleActionAllow,
+						},
+					},
+					ResourceGroup:            "my-rg",
+					Location:                 "centralIndia",
+					ClusterName:              "my-cluster",
+					AdditionalTags:           make(infrav1.Tags),
+					LastAppliedSecurityRules: map[string]interface{}{},
+				},
+				&securitygroups.NSGSpec{
+					Name:                     "node-nsg",
+					ResourceGroup:            "my-rg",
+					Location:                 "centralIndia",
+					ClusterName:              "my-cluster",
+					AdditionalTags:           make(infrav1.Tags),
+					LastAppliedSecurityRules: map[string]interface{}{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.clusterScope.NSGSpecs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RouteTableSpecs() = %s, want %s", specArrayToString(got), specArrayToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestAdditionalAPIServerPortRulePrioritiesIgnoreOrder(t *testing.T) {
+	g := NewWithT(t)
+	ports := []infrav1.LoadBalancerPort{
+		{Name: "konnectivity", Port: 8132},
+		{Name: "metrics", Port: 9443},
+		{Name: "webhook", Port: 9444},
+	}
+	priorities := func(ports []infrav1.LoadBalancerPort) map[string]int32 {
+		s := &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								AdditionalAPIServerLBPorts: ports,
+							},
+						},
+					},
+				},
+			},
+		}
+		result := map[string]int32{}
+		for _, rule := range s.withAdditionalAPIServerPortRules(nil) {
+			result[rule.Name] = rule.Priority
+		}
+		return result
+	}
+
+	reversed := []infrav1.LoadBalancerPort{ports[2], ports[1], ports[0]}
+	g.Expect(priorities(reversed)).To(Equal(priorities(ports)))
+	g.Expect(priorities(ports[1:])["allow_apiserver_metrics"]).To(Equal(priorities(ports)["allow_apiserver_metrics"]))
+}
+
+func TestSubnetSpecs(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusterScope ClusterScope
+		want         []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]
+	}{
+		{
+			name: "returns empty if no subnets are specified",
+			clusterScope: ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{},
+		},
+		{
+			name: "skips subnets waiting for their address space from IPAM",
+			clusterScope: ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role: infrav1.SubnetNode,
+										Name: "fake-subnet-1",
+										IPAMPoolRef: &corev1.TypedLocalObjectReference{
+											APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+											Kind:     "InClusterIPPool",
+											Name:     "node-subnets",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{},
+		},
+		{
+			name: "returns specified subnet spec",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "centralIndia",
+							IdentityRef: &corev1.ObjectReference{
+								Kind: infrav1.AzureClusterIdentityKind,
+							},
+						},
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ID:            "fake-vnet-id-1",
+								Name:          "fake-vnet-1",
+								ResourceGroup: "my-rg-vnet",
+							},
+							Subnets: infrav1.Subnets{
+								{
+									SubnetClassSpec: infrav1.SubnetClassSpec{
+										Role:       infrav1.SubnetNode,
+										CIDRBlocks: []string{"192.168.1.1/16"},
+										Name:       "fake-subnet-1",
+									},
+									NatGateway: infrav1.NatGateway{
+										NatGatewayClassSpec: infrav1.NatGatewayClassSpec{
+											Name: "fake-natgateway-1",
+										},
+									},
+									RouteTable: infrav1.RouteTable{
+										ID:   "fake-route-table-id-1",
+										Name: "fake-route-table-1",
+									},
+									SecurityGroup: infrav1.SecurityGroup{
+										Name: "fake-security-group-1",
+										SecurityGroupClass: infrav1.SecurityGroupClass{
+											SecurityRules: infrav1.SecurityRules{
+												{
+													Name: "fake-rule-1",
+												},
+											},
+										},
+									},
+								},
+							},
 						},
 					},
 				},
@@ -1647,6 +2043,33 @@ func TestIsVnetManaged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Has owning tags but was not created by CAPZ",
+			clusterScope: ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								ID: "my-id",
+								VnetClassSpec: infrav1.VnetClassSpec{Tags: map[string]string{
+									"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+								}},
+							},
+						},
+					},
+					Status: infrav1.AzureClusterStatus{
+						ManagedResources: &infrav1.ManagedResources{},
+					},
+				},
+				cache: &ClusterCache{},
+			},
+			want: false,
+		},
 		{
 			name: "Has cached value of false",
 			clusterScope: ClusterScope{
@@ -1688,6 +2111,370 @@ func TestIsVnetManaged(t *testing.T) {
 	}
 }
 
+func TestInitManagedResources(t *testing.T) {
+	tests := []struct {
+		name         string
+		azureCluster *infrav1.AzureCluster
+		want         *infrav1.ManagedResources
+	}{
+		{
+			name:         "new cluster starts recording",
+			azureCluster: &infrav1.AzureCluster{},
+			want:         &infrav1.ManagedResources{},
+		},
+		{
+			name: "existing cluster keeps relying on tags",
+			azureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							ID: "my-id",
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "recorded resources are kept",
+			azureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							ID: "my-id",
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					ManagedResources: &infrav1.ManagedResources{IDs: []string{"my-id"}},
+				},
+			},
+			want: &infrav1.ManagedResources{IDs: []string{"my-id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := &ClusterScope{AzureCluster: tt.azureCluster}
+			clusterScope.InitManagedResources()
+			g.Expect(clusterScope.AzureCluster.Status.ManagedResources).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsManagedResource(t *testing.T) {
+	const id = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-rt"
+
+	tests := []struct {
+		name                 string
+		managedResources     *infrav1.ManagedResources
+		forceDeleteUnmanaged bool
+		ownedByTags          bool
+		want                 bool
+	}{
+		{
+			name:        "legacy cluster with owning tags",
+			ownedByTags: true,
+			want:        true,
+		},
+		{
+			name:        "legacy cluster without owning tags",
+			ownedByTags: false,
+			want:        false,
+		},
+		{
+			name:             "recorded resource",
+			managedResources: &infrav1.ManagedResources{IDs: []string{strings.ToUpper(id)}},
+			ownedByTags:      false,
+			want:             true,
+		},
+		{
+			name:             "unrecorded resource with owning tags",
+			managedResources: &infrav1.ManagedResources{IDs: []string{"other-id"}},
+			ownedByTags:      true,
+			want:             false,
+		},
+		{
+			name:                 "unrecorded resource with owning tags when forcing deletion of unmanaged resources",
+			managedResources:     &infrav1.ManagedResources{},
+			forceDeleteUnmanaged: true,
+			ownedByTags:          true,
+			want:                 true,
+		},
+		{
+			name:                 "unrecorded resource without owning tags when forcing deletion of unmanaged resources",
+			managedResources:     &infrav1.ManagedResources{},
+			forceDeleteUnmanaged: true,
+			ownedByTags:          false,
+			want:                 false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			clusterScope := &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Status: infrav1.AzureClusterStatus{
+						ManagedResources: tt.managedResources,
+					},
+				},
+				forceDeleteUnmanaged: tt.forceDeleteUnmanaged,
+			}
+			g.Expect(clusterScope.IsManagedResource(id, tt.ownedByTags)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRecordManagedResource(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &ClusterScope{AzureCluster: &infrav1.AzureCluster{}}
+	clusterScope.RecordManagedResource("my-id")
+	g.Expect(clusterScope.AzureCluster.Status.ManagedResources).To(BeNil())
+
+	clusterScope.InitManagedResources()
+	clusterScope.RecordManagedResource("my-id")
+	clusterScope.RecordManagedResource("MY-ID")
+	clusterScope.RecordManagedResource("")
+	clusterScope.RecordManagedResource("other-id")
+	g.Expect(clusterScope.AzureCluster.Status.ManagedResources.IDs).To(Equal([]string{"my-id", "other-id"}))
+}
+
+func TestForgetManagedResource(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &ClusterScope{AzureCluster: &infrav1.AzureCluster{}}
+	clusterScope.ForgetManagedResource("my-id")
+	g.Expect(clusterScope.AzureCluster.Status.ManagedResources).To(BeNil())
+
+	clusterScope.InitManagedResources()
+	clusterScope.RecordManagedResource("my-id")
+	clusterScope.RecordManagedResource("other-id")
+	clusterScope.ForgetManagedResource("MY-ID")
+	clusterScope.ForgetManagedResource("unknown-id")
+	g.Expect(clusterScope.AzureCluster.Status.ManagedResources.IDs).To(Equal([]string{"other-id"}))
+	g.Expect(clusterScope.IsManagedResource("my-id", false)).To(BeFalse())
+}
+
+func TestAPIServerLBMigration(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &ClusterScope{
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+		AzureCluster: &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-cluster",
+				Annotations: map[string]string{infrav1.MigrateAPIServerLBToInternalAnnotation: "true"},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "my-cluster-apiserver.capz.io", Port: 6443},
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name:        "my-cluster-public-lb",
+						BackendPool: infrav1.BackendPool{Name: "my-cluster-public-lb-backendPool"},
+						FrontendIPs: []infrav1.FrontendIP{
+							{
+								Name:     "my-cluster-public-lb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver", DNSName: "my-cluster-apiserver.capz.io"},
+							},
+						},
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{Type: infrav1.Public},
+					},
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{Role: infrav1.SubnetControlPlane, Name: "cp-subnet"},
+							SecurityGroup: infrav1.SecurityGroup{
+								SecurityGroupClass: infrav1.SecurityGroupClass{
+									SecurityRules: infrav1.SecurityRules{
+										{Name: "allow_ssh", Source: ptr.To("*")},
+										{Name: "allow_apiserver", Source: ptr.To("*")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	g.Expect(clusterScope.IsAPIServerLBMigrationRequested()).To(BeTrue())
+	g.Expect(clusterScope.APIServerLBMigration()).To(BeNil())
+
+	clusterScope.StartAPIServerLBMigration()
+	g.Expect(clusterScope.APIServerLBMigration()).To(Equal(&infrav1.APIServerLBMigration{
+		Phase:                   infrav1.APIServerLBMigrationPending,
+		PublicLBName:            "my-cluster-public-lb",
+		PublicLBBackendPoolName: "my-cluster-public-lb-backendPool",
+		PublicLBFrontendIPName:  "my-cluster-public-lb-frontEnd",
+		PublicIPNames:           []string{"pip-my-cluster-apiserver"},
+	}))
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.InternalAPIServerLBReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.InternalAPIServerLBReadyCondition)).To(Equal(infrav1.WaitingForLoadBalancerTypeChangeReason))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(BeFalse())
+
+	// The type of the API server load balancer is changed to Internal.
+	clusterScope.AzureCluster.Spec.NetworkSpec.APIServerLB = infrav1.LoadBalancerSpec{
+		Name: "my-cluster-internal-lb",
+		FrontendIPs: []infrav1.FrontendIP{
+			{Name: "my-cluster-internal-lb-frontEnd", FrontendIPClass: infrav1.FrontendIPClass{PrivateIPAddress: "10.0.0.100"}},
+		},
+		LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{Type: infrav1.Internal},
+	}
+	clusterScope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationSwitchingEndpoint)
+	g.Expect(conditions.IsTrue(clusterScope.AzureCluster, infrav1.InternalAPIServerLBReadyCondition)).To(BeTrue())
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(Equal(infrav1.InternalAPIServerLBUnavailableReason))
+	clusterScope.SetAPIServerLBMigrationProgress(infrav1.ControlPlaneCertificatesRollingOutReason, "waiting for the control plane to be rolled out")
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(Equal(infrav1.ControlPlaneCertificatesRollingOutReason))
+	g.Expect(conditions.GetMessage(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(Equal("waiting for the control plane to be rolled out"))
+
+	// The control plane endpoint isn't migrated until the machines created before the switch are replaced.
+	clusterScope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationReplacingMachines)
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(Equal(infrav1.WaitingForMachinesReplacementReason))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.PublicAPIServerLBDeletedCondition)).To(BeFalse())
+
+	clusterScope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationDeletingPublicLB)
+	g.Expect(conditions.IsTrue(clusterScope.AzureCluster, infrav1.ControlPlaneEndpointMigratedCondition)).To(BeTrue())
+	g.Expect(conditions.IsFalse(clusterScope.AzureCluster, infrav1.PublicAPIServerLBDeletedCondition)).To(BeTrue())
+
+	clusterScope.RestrictAPIServerSecurityRule()
+	g.Expect(clusterScope.ControlPlaneSubnet().SecurityGroup.SecurityRules).To(Equal(infrav1.SecurityRules{
+		{Name: "allow_ssh", Source: ptr.To("*")},
+		{Name: "allow_apiserver", Source: ptr.To("VirtualNetwork")},
+	}))
+	clusterScope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCompleted)
+	for _, condition := range []clusterv1.ConditionType{
+		infrav1.InternalAPIServerLBReadyCondition,
+		infrav1.ControlPlaneEndpointMigratedCondition,
+		infrav1.PublicAPIServerLBDeletedCondition,
+	} {
+		g.Expect(conditions.IsTrue(clusterScope.AzureCluster, condition)).To(BeTrue())
+	}
+
+	clusterScope.ClearAPIServerLBMigration()
+	g.Expect(clusterScope.APIServerLBMigration()).To(BeNil())
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.PublicAPIServerLBDeletedCondition)).To(BeFalse())
+}
+
+func TestAPIServerDNSRecordSpecs(t *testing.T) {
+	zoneID := "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+	otherSubscriptionZoneID := "/subscriptions/456/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+
+	tests := []struct {
+		name         string
+		lbType       infrav1.LBType
+		privateIP    string
+		apiServerDNS *infrav1.APIServerDNS
+		wantA        []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord]
+		wantAAAA     []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesAAAARecord]
+	}{
+		{
+			name:   "no record",
+			lbType: infrav1.Public,
+		},
+		{
+			name:         "public API server",
+			lbType:       infrav1.Public,
+			apiServerDNS: &infrav1.APIServerDNS{ZoneResourceID: zoneID, RecordName: "api", TTL: ptr.To[int32](60)},
+			wantA: []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord]{
+				&dnsrecords.ARecordSpec{
+					Name:       "my-cluster-apiserver",
+					ZoneID:     zoneID,
+					RecordName: "api",
+					TTL:        60,
+					PublicIPID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-apiserver",
+				},
+			},
+		},
+		{
+			name:         "internal API server with an IPv4 address",
+			lbType:       infrav1.Internal,
+			privateIP:    "10.0.0.100",
+			apiServerDNS: &infrav1.APIServerDNS{ZoneResourceID: zoneID, RecordName: "api"},
+			wantA: []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord]{
+				&dnsrecords.ARecordSpec{
+					Name:       "my-cluster-apiserver",
+					ZoneID:     zoneID,
+					RecordName: "api",
+					TTL:        infrav1.DefaultAPIServerDNSTTL,
+					IPAddress:  "10.0.0.100",
+				},
+			},
+		},
+		{
+			name:         "internal API server with an IPv6 address in a zone of another subscription",
+			lbType:       infrav1.Internal,
+			privateIP:    "fd00::100",
+			apiServerDNS: &infrav1.APIServerDNS{ZoneResourceID: otherSubscriptionZoneID, RecordName: "api"},
+			wantAAAA: []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesAAAARecord]{
+				&dnsrecords.AAAARecordSpec{
+					Name:             "my-cluster-apiserver",
+					ZoneID:           otherSubscriptionZoneID,
+					RecordName:       "api",
+					TTL:              infrav1.DefaultAPIServerDNSTTL,
+					IPAddress:        "fd00::100",
+					CredentialSecret: "my-cluster-aso-secret-456",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec: infrav1.NetworkSpec{
+							APIServerLB: infrav1.LoadBalancerSpec{
+								FrontendIPs: []infrav1.FrontendIP{
+									{
+										PublicIP: &infrav1.PublicIPSpec{
+											Name: "pip-my-cluster-apiserver",
+										},
+										FrontendIPClass: infrav1.FrontendIPClass{
+											PrivateIPAddress: tt.privateIP,
+										},
+									},
+								},
+								LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+									Type: tt.lbType,
+								},
+							},
+						},
+						APIServerDNS: tt.apiServerDNS,
+					},
+				},
+			}
+
+			g.Expect(clusterScope.APIServerDNSARecordSpecs()).To(Equal(tt.wantA))
+			g.Expect(clusterScope.APIServerDNSAAAARecordSpecs()).To(Equal(tt.wantAAAA))
+		})
+	}
+}
+
 func TestAzureBastionSpec(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2098,22 +2885,119 @@ func TestGetPrivateDNSZoneName(t *testing.T) {
 					ClientID: fakeClientID,
 					TenantID: fakeTenantID,
 				},
-			}
-			fakeSecret := &corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}}
+			}
+			fakeSecret := &corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}}
+
+			initObjects := []runtime.Object{cluster, azureCluster, fakeIdentity, fakeSecret}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			got := clusterScope.GetPrivateDNSZoneName()
+			g.Expect(tc.expectPrivateDNSZoneName).Should(Equal(got))
+		})
+	}
+}
+
+func TestPrivateDNSSpecAdditionalVnetLinks(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-cluster",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			ResourceGroup: "my-rg",
+			NetworkSpec: infrav1.NetworkSpec{
+				Vnet: infrav1.VnetSpec{
+					ResourceGroup: "my-rg",
+					Name:          "my-vnet",
+				},
+				APIServerLB: infrav1.LoadBalancerSpec{
+					LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+						Type: infrav1.Internal,
+					},
+					FrontendIPs: []infrav1.FrontendIP{
+						{
+							Name: "api-server-lb-frontend-ip",
+							FrontendIPClass: infrav1.FrontendIPClass{
+								PrivateIPAddress: "10.0.0.100",
+							},
+						},
+					},
+				},
+				NetworkClassSpec: infrav1.NetworkClassSpec{
+					PrivateDNSZone: &infrav1.PrivateDNSZone{
+						AdditionalVnetLinks: []string{
+							"/subscriptions/456/resourceGroups/mgmt-rg/providers/Microsoft.Network/virtualNetworks/mgmt-vnet",
+						},
+					},
+				},
+			},
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+				IdentityRef: &corev1.ObjectReference{
+					Kind: infrav1.AzureClusterIdentityKind,
+				},
+			},
+		},
+	}
+	fakeIdentity := &infrav1.AzureClusterIdentity{
+		Spec: infrav1.AzureClusterIdentitySpec{
+			Type:     infrav1.ServicePrincipal,
+			ClientID: fakeClientID,
+			TenantID: fakeTenantID,
+		},
+	}
+	fakeSecret := &corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}}
 
-			initObjects := []runtime.Object{cluster, azureCluster, fakeIdentity, fakeSecret}
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+	initObjects := []runtime.Object{cluster, azureCluster, fakeIdentity, fakeSecret}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
-			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				Cluster:      cluster,
-				AzureCluster: azureCluster,
-				Client:       fakeClient,
-			})
-			g.Expect(err).NotTo(HaveOccurred())
-			got := clusterScope.GetPrivateDNSZoneName()
-			g.Expect(tc.expectPrivateDNSZoneName).Should(Equal(got))
-		})
-	}
+	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+		Client:       fakeClient,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, links, _ := clusterScope.PrivateDNSSpec()
+	g.Expect(links).To(Equal([]azure.ResourceSpecGetter{
+		privatedns.LinkSpec{
+			Name:              "my-vnet-link",
+			ZoneName:          "my-cluster.capz.io",
+			SubscriptionID:    "123",
+			VNetResourceGroup: "my-rg",
+			VNetName:          "my-vnet",
+			ResourceGroup:     "my-rg",
+			ClusterName:       "my-cluster",
+			AdditionalTags:    infrav1.Tags{},
+		},
+		privatedns.LinkSpec{
+			Name:              "mgmt-vnet-e5f99a52-link",
+			ZoneName:          "my-cluster.capz.io",
+			SubscriptionID:    "456",
+			VNetResourceGroup: "mgmt-rg",
+			VNetName:          "mgmt-vnet",
+			ResourceGroup:     "my-rg",
+			ClusterName:       "my-cluster",
+			AdditionalTags:    infrav1.Tags{},
+		},
+	}))
 }
 
 func TestAPIServerLBPoolName(t *testing.T) {
@@ -2261,6 +3145,20 @@ func TestOutboundLBName(t *testing.T) {
 				}},
 			expected: "",
 		},
+		{
+			clusterName: "my-cluster",
+			name:        "private cluster with disabled control plane outbound lb",
+			role:        "control-plane",
+			apiServerLB: &infrav1.LoadBalancerSpec{
+				LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+					Type: "Internal",
+				}},
+			controlPlaneOutboundLB: &infrav1.LoadBalancerSpec{
+				LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+					Disabled: true,
+				}},
+			expected: "",
+		},
 	}
 
 	for _, tc := range tests {
@@ -2681,6 +3579,98 @@ func TestGenerateFQDN(t *testing.T) {
 	}
 }
 
+func TestSetDNSName(t *testing.T) {
+	newClusterScope := func(publicIP *infrav1.PublicIPSpec, dnsLabel string) *ClusterScope {
+		return &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+			},
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+				ResourceManagerVMDNSSuffix: "cloudapp.azure.com",
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus2",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							Name: "my-cluster-public-lb",
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									Name:     "my-cluster-public-lb-frontEnd",
+									PublicIP: publicIP,
+								},
+							},
+							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+								SKU:  infrav1.SKUStandard,
+								Type: infrav1.Public,
+							},
+						},
+					},
+				},
+				Status: infrav1.AzureClusterStatus{
+					APIServerDNSLabel: dnsLabel,
+				},
+			},
+		}
+	}
+
+	t.Run("generates the DNS name and persists its label", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newClusterScope(&infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver"}, "")
+
+		s.SetDNSName()
+
+		g.Expect(s.APIServerPublicIP().DNSName).To(Equal(s.GenerateFQDN("pip-my-cluster-apiserver")))
+		g.Expect(s.APIServerPublicIP().DNSName).To(HavePrefix(s.AzureCluster.Status.APIServerDNSLabel + ".westus2."))
+		g.Expect(s.AzureCluster.Status.APIServerDNSLabel).To(HavePrefix("my-cluster-"))
+	})
+
+	t.Run("reuses the persisted DNS label when the public IP is recreated", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newClusterScope(&infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver"}, "")
+		s.SetDNSName()
+		dnsName := s.APIServerPublicIP().DNSName
+		dnsLabel := s.AzureCluster.Status.APIServerDNSLabel
+
+		// The public IP is recreated with another name and without a DNS name.
+		recreated := newClusterScope(&infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver-standard"}, dnsLabel)
+		recreated.SetDNSName()
+
+		g.Expect(recreated.APIServerPublicIP().DNSName).To(Equal(dnsName))
+		g.Expect(recreated.AzureCluster.Status.APIServerDNSLabel).To(Equal(dnsLabel))
+	})
+
+	t.Run("keeps a specified DNS name", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newClusterScope(&infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver", DNSName: "my-api.westus2.cloudapp.azure.com"}, "")
+
+		s.SetDNSName()
+
+		g.Expect(s.APIServerPublicIP().DNSName).To(Equal("my-api.westus2.cloudapp.azure.com"))
+		g.Expect(s.AzureCluster.Status.APIServerDNSLabel).To(BeEmpty())
+	})
+
+	t.Run("expands a specified DNS label", func(t *testing.T) {
+		g := NewWithT(t)
+		s := newClusterScope(&infrav1.PublicIPSpec{Name: "pip-my-cluster-apiserver", DNSName: "my-api"}, "")
+
+		s.SetDNSName()
+
+		g.Expect(s.APIServerPublicIP().DNSName).To(Equal("my-api.westus2.cloudapp.azure.com"))
+		g.Expect(s.AzureCluster.Status.APIServerDNSLabel).To(BeEmpty())
+	})
+}
+
 func TestAdditionalTags(t *testing.T) {
 	tests := []struct {
 		name                       string
@@ -3089,6 +4079,7 @@ func TestClusterScope_LBSpecs(t *testing.T) {
 					Role:                 infrav1.APIServerRole,
 					BackendPoolName:      "api-server-lb-backend-pool",
 					IdleTimeoutInMinutes: ptr.To[int32](30),
+					FailureDomains:       []*string{},
 					AdditionalTags: infrav1.Tags{
 						"foo": "bar",
 					},
@@ -3114,6 +4105,7 @@ func TestClusterScope_LBSpecs(t *testing.T) {
 					Role:                 infrav1.NodeOutboundRole,
 					BackendPoolName:      "node-outbound-backend-pool",
 					IdleTimeoutInMinutes: ptr.To[int32](50),
+					FailureDomains:       []*string{},
 					AdditionalTags: infrav1.Tags{
 						"foo": "bar",
 					},
@@ -3139,6 +4131,7 @@ func TestClusterScope_LBSpecs(t *testing.T) {
 					BackendPoolName:      "cp-outbound-backend-pool",
 					IdleTimeoutInMinutes: ptr.To[int32](15),
 					Role:                 infrav1.ControlPlaneOutboundRole,
+					FailureDomains:       []*string{},
 					AdditionalTags: infrav1.Tags{
 						"foo": "bar",
 					},
@@ -3209,6 +4202,7 @@ func TestClusterScope_LBSpecs(t *testing.T) {
 					Role:                 infrav1.APIServerRole,
 					BackendPoolName:      "api-server-lb-backend-pool",
 					IdleTimeoutInMinutes: ptr.To[int32](30),
+					FailureDomains:       []*string{},
 					AdditionalTags:       infrav1.Tags{},
 				},
 			},
@@ -3674,6 +4668,167 @@ func TestVNetPeerings(t *testing.T) {
 	}
 }
 
+func TestResourceLockSpecs(t *testing.T) {
+	publicLB := infrav1.LoadBalancerSpec{
+		Name: "my-cluster-public-lb",
+		FrontendIPs: []infrav1.FrontendIP{
+			{
+				Name: "my-cluster-public-lb-frontEnd",
+				PublicIP: &infrav1.PublicIPSpec{
+					Name: "my-cluster-pip",
+				},
+			},
+		},
+		LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+			Type: infrav1.Public,
+		},
+	}
+	privateLB := infrav1.LoadBalancerSpec{
+		Name: "my-cluster-internal-lb",
+		FrontendIPs: []infrav1.FrontendIP{
+			{
+				Name: "my-cluster-internal-lb-frontEnd",
+			},
+		},
+		LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+			Type: infrav1.Internal,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		resourceLocks *infrav1.ResourceLocks
+		apiServerLB   infrav1.LoadBalancerSpec
+		want          []azure.ResourceSpecGetter
+	}{
+		{
+			name:          "resource locks are not specified",
+			resourceLocks: nil,
+			apiServerLB:   publicLB,
+			want:          nil,
+		},
+		{
+			name: "all resources are locked",
+			resourceLocks: &infrav1.ResourceLocks{
+				Scope: []infrav1.ResourceLockScope{
+					infrav1.ResourceLockScopeVNet,
+					infrav1.ResourceLockScopePublicIP,
+				},
+			},
+			apiServerLB: publicLB,
+			want: []azure.ResourceSpecGetter{
+				&locks.LockSpec{
+					Name:          "my-cluster-vnet-lock",
+					ResourceGroup: "vnet-rg",
+					Scope:         "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet1",
+					Level:         infrav1.ResourceLockLevelCanNotDelete,
+					ClusterName:   "my-cluster",
+				},
+				&locks.LockSpec{
+					Name:          "my-cluster-publicip-lock",
+					ResourceGroup: "rg1",
+					Scope:         "/subscriptions/123/resourceGroups/rg1/providers/Microsoft.Network/publicIPAddresses/my-cluster-pip",
+					Level:         infrav1.ResourceLockLevelCanNotDelete,
+					ClusterName:   "my-cluster",
+				},
+			},
+		},
+		{
+			name: "public IP of a private API server is not locked",
+			resourceLocks: &infrav1.ResourceLocks{
+				Level: infrav1.ResourceLockLevelCanNotDelete,
+				Scope: []infrav1.ResourceLockScope{
+					infrav1.ResourceLockScopeVNet,
+					infrav1.ResourceLockScopePublicIP,
+				},
+			},
+			apiServerLB: privateLB,
+			want: []azure.ResourceSpecGetter{
+				&locks.LockSpec{
+					Name:          "my-cluster-vnet-lock",
+					ResourceGroup: "vnet-rg",
+					Scope:         "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet1",
+					Level:         infrav1.ResourceLockLevelCanNotDelete,
+					ClusterName:   "my-cluster",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
+
+			clusterName := "my-cluster"
+			clusterNamespace := "default"
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterName,
+					Namespace: clusterNamespace,
+				},
+			}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterName,
+					Namespace: clusterNamespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       clusterName,
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "rg1",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+						IdentityRef: &corev1.ObjectReference{
+							Kind: infrav1.AzureClusterIdentityKind,
+						},
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							ResourceGroup: "vnet-rg",
+							Name:          "vnet1",
+						},
+						APIServerLB: tc.apiServerLB,
+					},
+					ResourceLocks: tc.resourceLocks,
+				},
+			}
+			fakeIdentity := &infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: clusterNamespace,
+				},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:     infrav1.ServicePrincipal,
+					ClientID: fakeClientID,
+					TenantID: fakeTenantID,
+				},
+			}
+			fakeSecret := &corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}}
+
+			initObjects := []runtime.Object{cluster, azureCluster, fakeIdentity, fakeSecret}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+				Client:       fakeClient,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			got := clusterScope.ResourceLockSpecs()
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
 func TestPrivateEndpointSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -3986,3 +5141,125 @@ func TestSetFailureDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestControlPlaneVMSize(t *testing.T) {
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+		Kind:       "KubeadmControlPlane",
+		Name:       "my-control-plane",
+		Namespace:  "default",
+	}
+	controlPlane := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"machineTemplate": map[string]interface{}{
+				"infrastructureRef": map[string]interface{}{
+					"apiVersion": infrav1.GroupVersion.String(),
+					"kind":       infrav1.AzureMachineTemplateKind,
+					"name":       "my-template",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name            string
+		controlPlaneRef *corev1.ObjectReference
+		getControlPlane func(obj *unstructured.Unstructured) error
+		expected        string
+		expectedErr     string
+	}{
+		{
+			name:     "no control plane",
+			expected: "",
+		},
+		{
+			name:            "control plane created from an AzureMachineTemplate",
+			controlPlaneRef: controlPlaneRef,
+			getControlPlane: func(obj *unstructured.Unstructured) error {
+				obj.Object = controlPlane
+				obj.SetNamespace("default")
+				return nil
+			},
+			expected: "Standard_D4s_v3",
+		},
+		{
+			name:            "control plane not found",
+			controlPlaneRef: controlPlaneRef,
+			getControlPlane: func(_ *unstructured.Unstructured) error {
+				return apierrors.NewNotFound(schema.GroupResource{Group: "controlplane.cluster.x-k8s.io", Resource: "kubeadmcontrolplanes"}, "my-control-plane")
+			},
+			expected: "",
+		},
+		{
+			name: "control plane not allowed to be read",
+			controlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+				Kind:       "OtherControlPlane",
+				Name:       "my-control-plane",
+				Namespace:  "default",
+			},
+			getControlPlane: func(_ *unstructured.Unstructured) error {
+				return apierrors.NewForbidden(schema.GroupResource{Group: "controlplane.cluster.x-k8s.io", Resource: "othercontrolplanes"}, "my-control-plane", errors.New("not allowed"))
+			},
+			expected: "",
+		},
+		{
+			name:            "failure getting the control plane",
+			controlPlaneRef: controlPlaneRef,
+			getControlPlane: func(_ *unstructured.Unstructured) error {
+				return errors.New("an error")
+			},
+			expectedErr: "failed to get the control plane of the cluster",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			template := &infrav1.AzureMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-template",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureMachineTemplateSpec{
+					Template: infrav1.AzureMachineTemplateResource{
+						Spec: infrav1.AzureMachineSpec{VMSize: "Standard_D4s_v3"},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(template).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							return tc.getControlPlane(u)
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+				}).
+				Build()
+			s := &ClusterScope{
+				Client: fakeClient,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cluster",
+						Namespace: "default",
+					},
+					Spec: clusterv1.ClusterSpec{
+						ControlPlaneRef: tc.controlPlaneRef,
+					},
+				},
+			}
+
+			vmSize, err := s.ControlPlaneVMSize(context.Background())
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(vmSize).To(Equal(tc.expected))
+			}
+		})
+	}
+}
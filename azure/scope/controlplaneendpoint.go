@@ -0,0 +1,306 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubeadmControlPlaneKind is the kind of the only control plane whose certificates and machines can be rolled out when
+// the control plane endpoint is switched to the internal API server load balancer.
+const kubeadmControlPlaneKind = "KubeadmControlPlane"
+
+// kubeadmControlPlaneCertSANsPath is the path of the certificate SANs of the API server in a KubeadmControlPlane.
+var kubeadmControlPlaneCertSANsPath = []string{"spec", "kubeadmConfigSpec", "clusterConfiguration", "apiServer", "certSANs"}
+
+// ControlPlaneCertificatesRolledOut adds host to the certificate SANs of the API server of the KubeadmControlPlane of
+// the cluster, which rolls out its machines, and returns true once all of them are up to date and ready. The control
+// plane endpoint can only be switched to host once the certificates of all the API servers are valid for it, as the
+// machines created after the switch join the cluster through it.
+func (s *ClusterScope) ControlPlaneCertificatesRolledOut(ctx context.Context, host string) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.ControlPlaneCertificatesRolledOut")
+	defer done()
+
+	kcp, err := s.getKubeadmControlPlane(ctx)
+	if err != nil {
+		return false, err
+	}
+	certSANs, _, err := unstructured.NestedStringSlice(kcp.Object, kubeadmControlPlaneCertSANsPath...)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the certificate SANs of KubeadmControlPlane %s", kcp.GetName())
+	}
+	if slices.Contains(certSANs, host) {
+		return isKubeadmControlPlaneRolledOut(kcp), nil
+	}
+
+	log.Info("adding the host of the internal API server load balancer to the certificate SANs of the control plane", "host", host, "kubeadmControlPlane", kcp.GetName())
+	before := kcp.DeepCopy()
+	if err := unstructured.SetNestedStringSlice(kcp.Object, append(certSANs, host), kubeadmControlPlaneCertSANsPath...); err != nil {
+		return false, errors.Wrapf(err, "failed to set the certificate SANs of KubeadmControlPlane %s", kcp.GetName())
+	}
+	if err := s.Client.Patch(ctx, kcp, client.MergeFrom(before)); err != nil {
+		return false, errors.Wrapf(err, "failed to add %s to the certificate SANs of KubeadmControlPlane %s", host, kcp.GetName())
+	}
+	return false, nil
+}
+
+// SwitchControlPlaneEndpoint points the control plane endpoint of the cluster to the internal API server load
+// balancer. The Cluster API only copies the control plane endpoint of the AzureCluster to the Cluster while it is
+// unset, and the control plane provider only generates the kubeconfig secret of the cluster once, so the endpoint is
+// also switched in both of them, as well as in the kubeconfigs and kubeadm configuration the workload cluster
+// publishes to its nodes. The KubeadmControlPlane is then rolled out so that the kubelets of the control plane
+// machines reach the API server through the internal load balancer, and the time of the switch is recorded to tell the
+// machines created before it.
+func (s *ClusterScope) SwitchControlPlaneEndpoint(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.SwitchControlPlaneEndpoint")
+	defer done()
+
+	endpoint := s.Cluster.Spec.ControlPlaneEndpoint
+	endpoint.Host = s.APIServerHost()
+	if endpoint.Port == 0 {
+		endpoint.Port = s.APIServerPort()
+	}
+	server := "https://" + endpoint.String()
+
+	// The workload cluster is updated first, while the kubeconfig secret still points to the public load balancer.
+	if err := s.switchWorkloadClusterEndpoint(ctx, server, endpoint.String()); err != nil {
+		return err
+	}
+	if s.Cluster.Spec.ControlPlaneEndpoint != endpoint {
+		log.Info("switching the control plane endpoint of the cluster to the internal API server load balancer", "endpoint", endpoint.String())
+		before := s.Cluster.DeepCopy()
+		s.Cluster.Spec.ControlPlaneEndpoint = endpoint
+		if err := s.Client.Patch(ctx, s.Cluster, client.MergeFrom(before)); err != nil {
+			return errors.Wrapf(err, "failed to switch the control plane endpoint of Cluster %s", s.Cluster.Name)
+		}
+	}
+	if err := s.switchKubeconfigSecretEndpoint(ctx, server); err != nil {
+		return err
+	}
+	s.AzureCluster.Spec.ControlPlaneEndpoint.Host = endpoint.Host
+
+	switchTime := metav1.Now()
+	if err := s.rollOutControlPlane(ctx, switchTime); err != nil {
+		return err
+	}
+	if migration := s.APIServerLBMigration(); migration != nil {
+		migration.EndpointSwitchTime = &switchTime
+	}
+	return nil
+}
+
+// MachinesCreatedBeforeEndpointSwitch returns the names of the machines of the cluster created before the control
+// plane endpoint was switched to the internal API server load balancer, whose kubelets still reach the API server
+// through the public load balancer.
+func (s *ClusterScope) MachinesCreatedBeforeEndpointSwitch(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.MachinesCreatedBeforeEndpointSwitch")
+	defer done()
+
+	migration := s.APIServerLBMigration()
+	if migration == nil || migration.EndpointSwitchTime == nil {
+		return nil, errors.New("the time the control plane endpoint was switched to the internal API server load balancer isn't recorded")
+	}
+	machines := &clusterv1.MachineList{}
+	if err := s.Client.List(ctx, machines, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterNameLabel: s.ClusterName()}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the machines of the cluster")
+	}
+	var names []string
+	for _, machine := range machines.Items {
+		if machine.CreationTimestamp.Before(migration.EndpointSwitchTime) {
+			names = append(names, machine.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// getKubeadmControlPlane returns the control plane of the cluster, which must be a KubeadmControlPlane for the control
+// plane endpoint to be switched.
+func (s *ClusterScope) getKubeadmControlPlane(ctx context.Context) (*unstructured.Unstructured, error) {
+	ref := s.Cluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != kubeadmControlPlaneKind {
+		return nil, errors.New("the control plane endpoint can only be switched to the internal API server load balancer for a control plane managed by a KubeadmControlPlane")
+	}
+	kcp, err := external.Get(ctx, s.Client, ref, s.Namespace())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get KubeadmControlPlane %s", ref.Name)
+	}
+	return kcp, nil
+}
+
+// isKubeadmControlPlaneRolledOut returns true once the KubeadmControlPlane observed its latest spec, and all its
+// replicas are up to date and ready.
+func isKubeadmControlPlaneRolledOut(kcp *unstructured.Unstructured) bool {
+	observedGeneration, _, _ := unstructured.NestedInt64(kcp.Object, "status", "observedGeneration")
+	desired, found, _ := unstructured.NestedInt64(kcp.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	replicas, _, _ := unstructured.NestedInt64(kcp.Object, "status", "replicas")
+	updated, _, _ := unstructured.NestedInt64(kcp.Object, "status", "updatedReplicas")
+	ready, _, _ := unstructured.NestedInt64(kcp.Object, "status", "readyReplicas")
+	return observedGeneration >= kcp.GetGeneration() && replicas == desired && updated == desired && ready == desired
+}
+
+// rollOutControlPlane sets the rolloutAfter of the KubeadmControlPlane of the cluster, so that its machines created
+// before after are replaced.
+func (s *ClusterScope) rollOutControlPlane(ctx context.Context, after metav1.Time) error {
+	kcp, err := s.getKubeadmControlPlane(ctx)
+	if err != nil {
+		return err
+	}
+	before := kcp.DeepCopy()
+	if err := unstructured.SetNestedField(kcp.Object, after.UTC().Format(time.RFC3339), "spec", "rolloutAfter"); err != nil {
+		return errors.Wrapf(err, "failed to set the rolloutAfter of KubeadmControlPlane %s", kcp.GetName())
+	}
+	if err := s.Client.Patch(ctx, kcp, client.MergeFrom(before)); err != nil {
+		return errors.Wrapf(err, "failed to roll out KubeadmControlPlane %s", kcp.GetName())
+	}
+	return nil
+}
+
+// switchKubeconfigSecretEndpoint points the kubeconfig secret of the cluster to server.
+func (s *ClusterScope) switchKubeconfigSecretEndpoint(ctx context.Context, server string) error {
+	kubeconfig := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: s.Namespace(), Name: secret.Name(s.ClusterName(), secret.Kubeconfig)}
+	if err := s.Client.Get(ctx, key, kubeconfig); err != nil {
+		return errors.Wrapf(err, "failed to get kubeconfig secret %s", key.Name)
+	}
+	data := string(kubeconfig.Data[secret.KubeconfigDataName])
+	updated, err := setKubeconfigServer(data, server)
+	if err != nil {
+		return errors.Wrapf(err, "failed to switch the server of kubeconfig secret %s", key.Name)
+	}
+	if updated == data {
+		return nil
+	}
+	before := kubeconfig.DeepCopy()
+	kubeconfig.Data[secret.KubeconfigDataName] = []byte(updated)
+	if err := s.Client.Patch(ctx, kubeconfig, client.MergeFrom(before)); err != nil {
+		return errors.Wrapf(err, "failed to switch the server of kubeconfig secret %s", key.Name)
+	}
+	return nil
+}
+
+// switchWorkloadClusterEndpoint points the kubeconfigs the workload cluster publishes to server, i.e. the one of the
+// cluster-info ConfigMap kubeadm reads when joining nodes and the one of kube-proxy, and the control plane endpoint of
+// the kubeadm configuration read when joining control plane machines to endpoint.
+func (s *ClusterScope) switchWorkloadClusterEndpoint(ctx context.Context, server, endpoint string) error {
+	remoteClient, err := GetRemoteClientCache().GetClient(ctx, s.Client, client.ObjectKeyFromObject(s.Cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to get a client of the workload cluster")
+	}
+	setServer := func(data string) (string, error) {
+		return setKubeconfigServer(data, server)
+	}
+	if err := updateConfigMapData(ctx, remoteClient, client.ObjectKey{Namespace: metav1.NamespacePublic, Name: "cluster-info"}, "kubeconfig", setServer); err != nil {
+		return err
+	}
+	if err := updateConfigMapData(ctx, remoteClient, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "kube-proxy"}, "kubeconfig.conf", setServer); err != nil {
+		return err
+	}
+	return updateConfigMapData(ctx, remoteClient, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "kubeadm-config"}, "ClusterConfiguration", func(data string) (string, error) {
+		return setKubeadmControlPlaneEndpoint(data, endpoint)
+	})
+}
+
+// updateConfigMapData updates the value of dataKey in a ConfigMap of the workload cluster. A missing ConfigMap or key is
+// ignored, e.g. when kube-proxy isn't deployed.
+func updateConfigMapData(ctx context.Context, c client.Client, key client.ObjectKey, dataKey string, update func(string) (string, error)) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ConfigMap %s", key)
+	}
+	data, ok := configMap.Data[dataKey]
+	if !ok {
+		return nil
+	}
+	updated, err := update(data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update %s of ConfigMap %s", dataKey, key)
+	}
+	if updated == data {
+		return nil
+	}
+	before := configMap.DeepCopy()
+	configMap.Data[dataKey] = updated
+	if err := c.Patch(ctx, configMap, client.MergeFrom(before)); err != nil {
+		return errors.Wrapf(err, "failed to update %s of ConfigMap %s", dataKey, key)
+	}
+	return nil
+}
+
+// setKubeconfigServer points all the clusters of a kubeconfig to server. The kubeconfig is returned unchanged if they
+// already do.
+func setKubeconfigServer(data, server string) (string, error) {
+	config, err := clientcmd.Load([]byte(data))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load kubeconfig")
+	}
+	var changed bool
+	for _, cluster := range config.Clusters {
+		if cluster.Server != server {
+			cluster.Server = server
+			changed = true
+		}
+	}
+	if !changed {
+		return data, nil
+	}
+	updated, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write kubeconfig")
+	}
+	return string(updated), nil
+}
+
+// setKubeadmControlPlaneEndpoint sets the control plane endpoint of a kubeadm ClusterConfiguration. The configuration
+// is returned unchanged if it is already set.
+func setKubeadmControlPlaneEndpoint(data, endpoint string) (string, error) {
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal kubeadm ClusterConfiguration")
+	}
+	if config["controlPlaneEndpoint"] == endpoint {
+		return data, nil
+	}
+	config["controlPlaneEndpoint"] = endpoint
+	updated, err := yaml.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal kubeadm ClusterConfiguration")
+	}
+	return string(updated), nil
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	publicAPIServerHost   = "my-cluster-apiserver.capz.io"
+	internalAPIServerHost = "apiserver.my-cluster.capz.io"
+)
+
+func newEndpointSwitchScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = controlplanev1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return scheme
+}
+
+func newEndpointSwitchCluster() *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: publicAPIServerHost, Port: 6443},
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: controlplanev1.GroupVersion.String(),
+				Kind:       "KubeadmControlPlane",
+				Name:       "my-control-plane",
+				Namespace:  "default",
+			},
+		},
+	}
+}
+
+func newEndpointSwitchKubeadmControlPlane(certSANs []string, updatedReplicas int32) *controlplanev1.KubeadmControlPlane {
+	return &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-control-plane", Namespace: "default", Generation: 2},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: ptr.To[int32](3),
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+					APIServer: bootstrapv1.APIServer{CertSANs: certSANs},
+				},
+			},
+		},
+		Status: controlplanev1.KubeadmControlPlaneStatus{
+			ObservedGeneration: 2,
+			Replicas:           3,
+			UpdatedReplicas:    updatedReplicas,
+			ReadyReplicas:      3,
+		},
+	}
+}
+
+func newEndpointSwitchAzureCluster() *infrav1.AzureCluster {
+	return &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: publicAPIServerHost, Port: 6443},
+			NetworkSpec: infrav1.NetworkSpec{
+				APIServerLB: infrav1.LoadBalancerSpec{
+					Name:                  "my-cluster-internal-lb",
+					LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{Type: infrav1.Internal},
+				},
+			},
+		},
+		Status: infrav1.AzureClusterStatus{
+			APIServerLBMigration: &infrav1.APIServerLBMigration{
+				Phase:        infrav1.APIServerLBMigrationSwitchingEndpoint,
+				PublicLBName: "my-cluster-public-lb",
+			},
+		},
+	}
+}
+
+func newKubeconfig(server string) string {
+	data, _ := clientcmd.Write(clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"my-cluster": {Server: server}},
+		Contexts:       map[string]*clientcmdapi.Context{"my-cluster": {Cluster: "my-cluster", AuthInfo: "admin"}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"admin": {Token: "token"}},
+		CurrentContext: "my-cluster",
+	})
+	return string(data)
+}
+
+func kubeconfigServer(g *WithT, data string) string {
+	config, err := clientcmd.Load([]byte(data))
+	g.Expect(err).NotTo(HaveOccurred())
+	return config.Clusters["my-cluster"].Server
+}
+
+func TestControlPlaneCertificatesRolledOut(t *testing.T) {
+	tests := []struct {
+		name             string
+		controlPlaneRef  *corev1.ObjectReference
+		kcp              *controlplanev1.KubeadmControlPlane
+		expected         bool
+		expectedCertSANs []string
+		expectedErr      string
+	}{
+		{
+			name:            "control plane which isn't a KubeadmControlPlane",
+			controlPlaneRef: &corev1.ObjectReference{Kind: "OtherControlPlane", Name: "my-control-plane"},
+			expectedErr:     "the control plane endpoint can only be switched to the internal API server load balancer for a control plane managed by a KubeadmControlPlane",
+		},
+		{
+			name:             "add the host to the certificate SANs",
+			kcp:              newEndpointSwitchKubeadmControlPlane([]string{"localhost"}, 3),
+			expected:         false,
+			expectedCertSANs: []string{"localhost", internalAPIServerHost},
+		},
+		{
+			name:             "wait for the control plane to be rolled out",
+			kcp:              newEndpointSwitchKubeadmControlPlane([]string{"localhost", internalAPIServerHost}, 1),
+			expected:         false,
+			expectedCertSANs: []string{"localhost", internalAPIServerHost},
+		},
+		{
+			name:             "control plane rolled out",
+			kcp:              newEndpointSwitchKubeadmControlPlane([]string{"localhost", internalAPIServerHost}, 3),
+			expected:         true,
+			expectedCertSANs: []string{"localhost", internalAPIServerHost},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			cluster := newEndpointSwitchCluster()
+			if tc.controlPlaneRef != nil {
+				cluster.Spec.ControlPlaneRef = tc.controlPlaneRef
+			}
+			builder := fake.NewClientBuilder().WithScheme(newEndpointSwitchScheme())
+			if tc.kcp != nil {
+				builder = builder.WithObjects(tc.kcp)
+			}
+			fakeClient := builder.Build()
+			clusterScope := &ClusterScope{
+				Client:       fakeClient,
+				Cluster:      cluster,
+				AzureCluster: newEndpointSwitchAzureCluster(),
+			}
+
+			rolledOut, err := clusterScope.ControlPlaneCertificatesRolledOut(context.TODO(), internalAPIServerHost)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(rolledOut).To(Equal(tc.expected))
+
+			kcp := &controlplanev1.KubeadmControlPlane{}
+			g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.kcp), kcp)).To(Succeed())
+			g.Expect(kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.CertSANs).To(Equal(tc.expectedCertSANs))
+		})
+	}
+}
+
+func TestSwitchControlPlaneEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newEndpointSwitchCluster()
+	kubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: secret.Name("my-cluster", secret.Kubeconfig)},
+		Data: map[string][]byte{
+			secret.KubeconfigDataName: []byte(newKubeconfig("https://" + publicAPIServerHost + ":6443")),
+		},
+	}
+	kcp := newEndpointSwitchKubeadmControlPlane([]string{internalAPIServerHost}, 3)
+	fakeClient := fake.NewClientBuilder().WithScheme(newEndpointSwitchScheme()).WithObjects(cluster, kubeconfig, kcp).Build()
+
+	workloadClient := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespacePublic, Name: "cluster-info"},
+			Data:       map[string]string{"kubeconfig": newKubeconfig("https://" + publicAPIServerHost + ":6443")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "kubeadm-config"},
+			Data: map[string]string{
+				"ClusterConfiguration": "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\ncontrolPlaneEndpoint: " + publicAPIServerHost + ":6443\n",
+			},
+		},
+	).Build()
+	cache := GetRemoteClientCache()
+	newClient := cache.newClient
+	cache.newClient = func(_ context.Context, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+		return workloadClient, nil
+	}
+	defer func() {
+		cache.newClient = newClient
+		cache.Invalidate(client.ObjectKeyFromObject(cluster))
+	}()
+
+	clusterScope := &ClusterScope{
+		Client:       fakeClient,
+		Cluster:      cluster,
+		AzureCluster: newEndpointSwitchAzureCluster(),
+	}
+	g.Expect(clusterScope.APIServerHost()).To(Equal(internalAPIServerHost))
+	g.Expect(clusterScope.SwitchControlPlaneEndpoint(context.TODO())).To(Succeed())
+
+	// The endpoint is switched in the AzureCluster, the Cluster, and the kubeconfig secret.
+	g.Expect(clusterScope.AzureCluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: internalAPIServerHost, Port: 6443}))
+	switchTime := clusterScope.APIServerLBMigration().EndpointSwitchTime
+	g.Expect(switchTime).NotTo(BeNil())
+	updatedCluster := &clusterv1.Cluster{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), updatedCluster)).To(Succeed())
+	g.Expect(updatedCluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: internalAPIServerHost, Port: 6443}))
+	updatedKubeconfig := &corev1.Secret{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(kubeconfig), updatedKubeconfig)).To(Succeed())
+	g.Expect(kubeconfigServer(g, string(updatedKubeconfig.Data[secret.KubeconfigDataName]))).To(Equal("https://" + internalAPIServerHost + ":6443"))
+
+	// The control plane is rolled out to replace the machines created before the switch.
+	updatedKCP := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(kcp), updatedKCP)).To(Succeed())
+	g.Expect(updatedKCP.Spec.RolloutAfter).NotTo(BeNil())
+	g.Expect(updatedKCP.Spec.RolloutAfter.Time).To(BeTemporally("~", switchTime.Time, time.Second))
+
+	// The endpoint is switched in the workload cluster, where kube-proxy isn't deployed.
+	clusterInfo := &corev1.ConfigMap{}
+	g.Expect(workloadClient.Get(context.TODO(), client.ObjectKey{Namespace: metav1.NamespacePublic, Name: "cluster-info"}, clusterInfo)).To(Succeed())
+	g.Expect(kubeconfigServer(g, clusterInfo.Data["kubeconfig"])).To(Equal("https://" + internalAPIServerHost + ":6443"))
+	kubeadmConfig := &corev1.ConfigMap{}
+	g.Expect(workloadClient.Get(context.TODO(), client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "kubeadm-config"}, kubeadmConfig)).To(Succeed())
+	g.Expect(kubeadmConfig.Data["ClusterConfiguration"]).To(ContainSubstring("controlPlaneEndpoint: " + internalAPIServerHost + ":6443"))
+	g.Expect(kubeadmConfig.Data["ClusterConfiguration"]).To(ContainSubstring("kind: ClusterConfiguration"))
+
+	// Switching the endpoint again, e.g. when the AzureCluster failed to be patched, doesn't change anything.
+	g.Expect(clusterScope.SwitchControlPlaneEndpoint(context.TODO())).To(Succeed())
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(kubeconfig), kubeconfig)).To(Succeed())
+	g.Expect(kubeconfig.ResourceVersion).To(Equal(updatedKubeconfig.ResourceVersion))
+}
+
+func TestMachinesCreatedBeforeEndpointSwitch(t *testing.T) {
+	g := NewWithT(t)
+
+	switchTime := metav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	newMachine := func(name, clusterName string, created time.Time) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newEndpointSwitchScheme()).WithObjects(
+		newMachine("md-1", "my-cluster", switchTime.Add(-time.Hour)),
+		newMachine("cp-0", "my-cluster", switchTime.Add(-time.Hour)),
+		newMachine("cp-1", "my-cluster", switchTime.Add(time.Minute)),
+		newMachine("other-0", "other-cluster", switchTime.Add(-time.Hour)),
+	).Build()
+
+	azureCluster := newEndpointSwitchAzureCluster()
+	azureCluster.Status.APIServerLBMigration.Phase = infrav1.APIServerLBMigrationReplacingMachines
+	clusterScope := &ClusterScope{
+		Client:       fakeClient,
+		Cluster:      newEndpointSwitchCluster(),
+		AzureCluster: azureCluster,
+	}
+	_, err := clusterScope.MachinesCreatedBeforeEndpointSwitch(context.TODO())
+	g.Expect(err).To(MatchError("the time the control plane endpoint was switched to the internal API server load balancer isn't recorded"))
+
+	azureCluster.Status.APIServerLBMigration.EndpointSwitchTime = &switchTime
+	machines, err := clusterScope.MachinesCreatedBeforeEndpointSwitch(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(machines).To(Equal([]string{"cp-0", "md-1"}))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+)
+
+const (
+	// identityUnauthenticated means no token has been requested yet.
+	identityUnauthenticated int32 = iota
+	// primaryIdentityAuthenticated means the last token was issued to the primary identity.
+	primaryIdentityAuthenticated
+	// fallbackIdentityAuthenticated means the primary identity failed to authenticate and the last token was issued
+	// to the fallback identity.
+	fallbackIdentityAuthenticated
+)
+
+// fallbackTokenCredential authenticates with the primary identity of an AzureClusterIdentity and switches to its
+// fallback identity when the primary identity fails to authenticate. Once switched, it keeps using the fallback
+// identity for its lifetime, which is a single reconciliation, so the primary identity is tried again on the next
+// reconciliation.
+type fallbackTokenCredential struct {
+	primary              azcore.TokenCredential
+	fallback             azcore.TokenCredential
+	fallbackIdentityName string
+	state                atomic.Int32
+}
+
+var _ azcore.TokenCredential = (*fallbackTokenCredential)(nil)
+
+func newFallbackTokenCredential(primary, fallback azcore.TokenCredential, fallbackIdentityName string) *fallbackTokenCredential {
+	return &fallbackTokenCredential{
+		primary:              primary,
+		fallback:             fallback,
+		fallbackIdentityName: fallbackIdentityName,
+	}
+}
+
+// GetToken requests an access token from the primary identity, or from the fallback identity if the primary
+// identity fails to authenticate.
+func (c *fallbackTokenCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if c.state.Load() != fallbackIdentityAuthenticated {
+		token, err := c.primary.GetToken(ctx, options)
+		if err == nil {
+			c.state.Store(primaryIdentityAuthenticated)
+			return token, nil
+		}
+		if !isAuthenticationFailure(err) {
+			return token, err
+		}
+		token, fallbackErr := c.fallback.GetToken(ctx, options)
+		if fallbackErr != nil {
+			return token, errors.Wrapf(fallbackErr, "failed to authenticate with fallback identity %s after the primary identity failed to authenticate: %v", c.fallbackIdentityName, err)
+		}
+		c.state.Store(fallbackIdentityAuthenticated)
+		return token, nil
+	}
+	return c.fallback.GetToken(ctx, options)
+}
+
+// usingFallbackIdentity returns true if the primary identity failed to authenticate and the fallback identity was
+// used instead. ok is false if no token has been requested yet.
+func (c *fallbackTokenCredential) usingFallbackIdentity() (fallback bool, ok bool) {
+	switch c.state.Load() {
+	case primaryIdentityAuthenticated:
+		return false, true
+	case fallbackIdentityAuthenticated:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// isAuthenticationFailure returns true if the error means the identity was rejected by Microsoft Entra ID, either
+// with a 401 or because the client is invalid, as opposed to a transient or network error.
+func isAuthenticationFailure(err error) bool {
+	var authErr *azidentity.AuthenticationFailedError
+	if !errors.As(err, &authErr) {
+		return false
+	}
+	if authErr.RawResponse != nil && authErr.RawResponse.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	return strings.Contains(authErr.Error(), "invalid_client")
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// fakeTokenCredential returns a token named after the identity, or fails with err.
+type fakeTokenCredential struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (f *fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	f.calls++
+	if f.err != nil {
+		return azcore.AccessToken{}, f.err
+	}
+	return azcore.AccessToken{Token: f.name}, nil
+}
+
+func authenticationFailedError(statusCode int, body string) error {
+	return &azidentity.AuthenticationFailedError{
+		RawResponse: &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		},
+	}
+}
+
+func TestFallbackTokenCredential(t *testing.T) {
+	tests := []struct {
+		name            string
+		primaryErr      error
+		fallbackErr     error
+		wantToken       string
+		wantErr         bool
+		wantFallback    bool
+		wantFallbackSet bool
+	}{
+		{
+			name:            "primary identity authenticates",
+			wantToken:       "primary",
+			wantFallback:    false,
+			wantFallbackSet: true,
+		},
+		{
+			name:            "primary identity is unauthorized",
+			primaryErr:      authenticationFailedError(http.StatusUnauthorized, `{"error":"unauthorized_client"}`),
+			wantToken:       "fallback",
+			wantFallback:    true,
+			wantFallbackSet: true,
+		},
+		{
+			name:            "primary identity is an invalid client",
+			primaryErr:      authenticationFailedError(http.StatusBadRequest, `{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret provided."}`),
+			wantToken:       "fallback",
+			wantFallback:    true,
+			wantFallbackSet: true,
+		},
+		{
+			name:            "primary identity fails with a non authentication error",
+			primaryErr:      errors.New("dial tcp: i/o timeout"),
+			wantErr:         true,
+			wantFallbackSet: false,
+		},
+		{
+			name:            "primary identity fails with another AAD error",
+			primaryErr:      authenticationFailedError(http.StatusBadRequest, `{"error":"invalid_request"}`),
+			wantErr:         true,
+			wantFallbackSet: false,
+		},
+		{
+			name:            "both identities fail to authenticate",
+			primaryErr:      authenticationFailedError(http.StatusUnauthorized, `{"error":"unauthorized_client"}`),
+			fallbackErr:     authenticationFailedError(http.StatusUnauthorized, `{"error":"unauthorized_client"}`),
+			wantErr:         true,
+			wantFallbackSet: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			primary := &fakeTokenCredential{name: "primary", err: tc.primaryErr}
+			fallback := &fakeTokenCredential{name: "fallback", err: tc.fallbackErr}
+			cred := newFallbackTokenCredential(primary, fallback, "fallback-identity")
+
+			token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token.Token).To(Equal(tc.wantToken))
+			}
+
+			clients := &AzureClients{TokenCredential: cred}
+			name, usingFallback, ok := clients.FallbackIdentity()
+			g.Expect(ok).To(Equal(tc.wantFallbackSet))
+			g.Expect(usingFallback).To(Equal(tc.wantFallback))
+			g.Expect(name).To(Equal("fallback-identity"))
+		})
+	}
+}
+
+func TestFallbackTokenCredentialKeepsUsingFallback(t *testing.T) {
+	g := NewWithT(t)
+	primary := &fakeTokenCredential{name: "primary", err: authenticationFailedError(http.StatusUnauthorized, "")}
+	fallback := &fakeTokenCredential{name: "fallback"}
+	cred := newFallbackTokenCredential(primary, fallback, "fallback-identity")
+
+	for i := 0; i < 3; i++ {
+		token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(token.Token).To(Equal("fallback"))
+	}
+	g.Expect(primary.calls).To(Equal(1))
+	g.Expect(fallback.calls).To(Equal(3))
+}
+
+func TestFallbackIdentityWithoutFallback(t *testing.T) {
+	g := NewWithT(t)
+	clients := &AzureClients{TokenCredential: &fakeTokenCredential{name: "primary"}}
+	_, _, ok := clients.FallbackIdentity()
+	g.Expect(ok).To(BeFalse())
+}
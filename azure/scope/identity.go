@@ -28,6 +28,7 @@ import (
 	"github.com/jongio/azidext/go/azidext"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -51,6 +52,8 @@ type CredentialsProvider interface {
 type AzureCredentialsProvider struct {
 	Client   client.Client
 	Identity *infrav1.AzureClusterIdentity
+	// FallbackIdentity is the identity to authenticate with when Identity fails to authenticate.
+	FallbackIdentity *infrav1.AzureClusterIdentity
 }
 
 // AzureClusterCredentialsProvider wraps AzureCredentialsProvider with AzureCluster.
@@ -85,11 +88,16 @@ func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.C
 	if err := kubeClient.Get(ctx, key, identity); err != nil {
 		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
 	}
+	fallbackIdentity, err := getFallbackIdentity(ctx, kubeClient, identity)
+	if err != nil {
+		return nil, err
+	}
 
 	return &AzureClusterCredentialsProvider{
 		AzureCredentialsProvider{
-			Client:   kubeClient,
-			Identity: identity,
+			Client:           kubeClient,
+			Identity:         identity,
+			FallbackIdentity: fallbackIdentity,
 		},
 		azureCluster,
 	}, nil
@@ -122,11 +130,16 @@ func NewManagedControlPlaneCredentialsProvider(ctx context.Context, kubeClient c
 	if err := kubeClient.Get(ctx, key, identity); err != nil {
 		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
 	}
+	fallbackIdentity, err := getFallbackIdentity(ctx, kubeClient, identity)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ManagedControlPlaneCredentialsProvider{
 		AzureCredentialsProvider{
-			Client:   kubeClient,
-			Identity: identity,
+			Client:           kubeClient,
+			Identity:         identity,
+			FallbackIdentity: fallbackIdentity,
 		},
 		managedControlPlane,
 	}, nil
@@ -142,22 +155,42 @@ func (p *ManagedControlPlaneCredentialsProvider) GetTokenCredential(ctx context.
 	return p.AzureCredentialsProvider.GetTokenCredential(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience, p.AzureManagedControlPlane.ObjectMeta)
 }
 
-// GetTokenCredential returns an Azure TokenCredential based on the provided azure identity.
+// GetTokenCredential returns an Azure TokenCredential based on the provided azure identity. If the identity has a
+// fallback identity, the TokenCredential authenticates with the fallback identity when the identity fails to
+// authenticate.
 func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience string, clusterMeta metav1.ObjectMeta) (azcore.TokenCredential, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "azure.scope.AzureCredentialsProvider.GetTokenCredential")
 	defer done()
 
+	cred, err := p.getTokenCredential(ctx, p.Identity, resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience)
+	if err != nil || p.FallbackIdentity == nil {
+		return cred, err
+	}
+
+	fallbackCred, err := p.getTokenCredential(ctx, p.FallbackIdentity, resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience)
+	if err != nil {
+		log.Error(err, "failed to create credential for fallback identity, authenticating without fallback", "fallbackIdentity", p.FallbackIdentity.Name)
+		return cred, nil
+	}
+	return newFallbackTokenCredential(cred, fallbackCred, p.FallbackIdentity.Name), nil
+}
+
+// getTokenCredential returns an Azure TokenCredential for the given azure identity.
+func (p *AzureCredentialsProvider) getTokenCredential(ctx context.Context, identity *infrav1.AzureClusterIdentity, resourceManagerEndpoint, activeDirectoryEndpoint, tokenAudience string) (azcore.TokenCredential, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "azure.scope.AzureCredentialsProvider.getTokenCredential")
+	defer done()
+
 	var authErr error
 	var cred azcore.TokenCredential
 
-	switch p.Identity.Spec.Type {
+	switch identity.Spec.Type {
 	case infrav1.WorkloadIdentity:
 		azwiCredOptions, err := NewWorkloadIdentityCredentialOptions().
-			WithTenantID(p.Identity.Spec.TenantID).
-			WithClientID(p.Identity.Spec.ClientID).
+			WithTenantID(identity.Spec.TenantID).
+			WithClientID(identity.Spec.ClientID).
 			WithDefaults()
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to setup azwi options for identity %s", p.Identity.Name)
+			return nil, errors.Wrapf(err, "failed to setup azwi options for identity %s", identity.Name)
 		}
 		cred, authErr = NewWorkloadIdentityCredential(azwiCredOptions)
 
@@ -165,7 +198,7 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		log.Info("Identity type ManualServicePrincipal is deprecated and will be removed in a future release. See https://capz.sigs.k8s.io/topics/identities to find a supported identity type.")
 		fallthrough
 	case infrav1.ServicePrincipal:
-		clientSecret, err := p.GetClientSecret(ctx)
+		clientSecret, err := p.getClientSecret(ctx, identity)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get client secret")
 		}
@@ -182,10 +215,10 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 				},
 			},
 		}
-		cred, authErr = azidentity.NewClientSecretCredential(p.GetTenantID(), p.Identity.Spec.ClientID, clientSecret, &options)
+		cred, authErr = azidentity.NewClientSecretCredential(identity.Spec.TenantID, identity.Spec.ClientID, clientSecret, &options)
 
 	case infrav1.ServicePrincipalCertificate:
-		clientSecret, err := p.GetClientSecret(ctx)
+		clientSecret, err := p.getClientSecret(ctx, identity)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get client secret")
 		}
@@ -193,16 +226,16 @@ func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resou
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to parse certificate data")
 		}
-		cred, authErr = azidentity.NewClientCertificateCredential(p.GetTenantID(), p.Identity.Spec.ClientID, certs, key, nil)
+		cred, authErr = azidentity.NewClientCertificateCredential(identity.Spec.TenantID, identity.Spec.ClientID, certs, key, nil)
 
 	case infrav1.UserAssignedMSI:
 		options := azidentity.ManagedIdentityCredentialOptions{
-			ID: azidentity.ClientID(p.Identity.Spec.ClientID),
+			ID: azidentity.ClientID(identity.Spec.ClientID),
 		}
 		cred, authErr = azidentity.NewManagedIdentityCredential(&options)
 
 	default:
-		return nil, errors.Errorf("identity type %s not supported", p.Identity.Spec.Type)
+		return nil, errors.Errorf("identity type %s not supported", identity.Spec.Type)
 	}
 
 	if authErr != nil {
@@ -233,8 +266,13 @@ func (p *AzureCredentialsProvider) GetClientID() string {
 // NOTE: this only works if the Identity references a Service Principal Client Secret.
 // If using another type of credentials, such a Certificate, we return an empty string.
 func (p *AzureCredentialsProvider) GetClientSecret(ctx context.Context) (string, error) {
-	if p.hasClientSecret() {
-		secretRef := p.Identity.Spec.ClientSecret
+	return p.getClientSecret(ctx, p.Identity)
+}
+
+// getClientSecret returns the Client Secret associated with the given azure identity.
+func (p *AzureCredentialsProvider) getClientSecret(ctx context.Context, identity *infrav1.AzureClusterIdentity) (string, error) {
+	if hasClientSecret(identity) {
+		secretRef := identity.Spec.ClientSecret
 		key := types.NamespacedName{
 			Namespace: secretRef.Namespace,
 			Name:      secretRef.Name,
@@ -257,7 +295,12 @@ func (p *AzureCredentialsProvider) GetTenantID() string {
 // hasClientSecret returns true if the identity has a Service Principal Client Secret.
 // This does not include managed identities.
 func (p *AzureCredentialsProvider) hasClientSecret() bool {
-	switch p.Identity.Spec.Type {
+	return hasClientSecret(p.Identity)
+}
+
+// hasClientSecret returns true if the identity has a Service Principal Client Secret.
+func hasClientSecret(identity *infrav1.AzureClusterIdentity) bool {
+	switch identity.Spec.Type {
 	case infrav1.ServicePrincipal, infrav1.ManualServicePrincipal, infrav1.ServicePrincipalCertificate:
 		return true
 	default:
@@ -265,6 +308,33 @@ func (p *AzureCredentialsProvider) hasClientSecret() bool {
 	}
 }
 
+// getFallbackIdentity returns the fallback identity of the AzureClusterIdentity, or nil if it doesn't have one.
+// Only one level of fallback is followed, so the fallback identity's own fallback identity is never used.
+func getFallbackIdentity(ctx context.Context, kubeClient client.Client, identity *infrav1.AzureClusterIdentity) (*infrav1.AzureClusterIdentity, error) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "azure.scope.getFallbackIdentity")
+	defer done()
+
+	ref := identity.Spec.FallbackIdentityRef
+	if ref == nil {
+		return nil, nil
+	}
+	// if the namespace isn't specified then assume it's in the same namespace as the AzureClusterIdentity
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = identity.Namespace
+	}
+	fallbackIdentity := &infrav1.AzureClusterIdentity{}
+	key := client.ObjectKey{Name: ref.Name, Namespace: namespace}
+	if err := kubeClient.Get(ctx, key, fallbackIdentity); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("fallback AzureClusterIdentity not found, authenticating without fallback", "fallbackIdentity", key)
+			return nil, nil
+		}
+		return nil, errors.Errorf("failed to retrieve fallback AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
+	}
+	return fallbackIdentity, nil
+}
+
 // IsClusterNamespaceAllowed indicates if the cluster namespace is allowed.
 func IsClusterNamespaceAllowed(ctx context.Context, k8sClient client.Client, allowedNamespaces *infrav1.AllowedNamespaces, namespace string) bool {
 	if allowedNamespaces == nil {
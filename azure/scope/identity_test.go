@@ -360,3 +360,105 @@ func TestGetTokenCredential(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTokenCredentialWithFallbackIdentity(t *testing.T) {
+	cluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				IdentityRef: &corev1.ObjectReference{
+					Kind: infrav1.AzureClusterIdentityKind,
+					Name: "primary",
+				},
+			},
+		},
+	}
+	primary := func(fallbackRef *corev1.ObjectReference) *infrav1.AzureClusterIdentity {
+		return &infrav1.AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "primary",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureClusterIdentitySpec{
+				Type:                infrav1.UserAssignedMSI,
+				ClientID:            fakeClientID,
+				TenantID:            fakeTenantID,
+				FallbackIdentityRef: fallbackRef,
+			},
+		}
+	}
+	fallback := func(name string, fallbackRef *corev1.ObjectReference) *infrav1.AzureClusterIdentity {
+		return &infrav1.AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureClusterIdentitySpec{
+				Type:                infrav1.UserAssignedMSI,
+				ClientID:            "fallback-client-id",
+				TenantID:            fakeTenantID,
+				FallbackIdentityRef: fallbackRef,
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		identities       []runtime.Object
+		wantFallbackName string
+	}{
+		{
+			name:       "identity without a fallback",
+			identities: []runtime.Object{primary(nil)},
+		},
+		{
+			name: "identity with a fallback",
+			identities: []runtime.Object{
+				primary(&corev1.ObjectReference{Name: "fallback"}),
+				fallback("fallback", nil),
+			},
+			wantFallbackName: "fallback",
+		},
+		{
+			name:       "identity with a missing fallback",
+			identities: []runtime.Object{primary(&corev1.ObjectReference{Name: "fallback"})},
+		},
+		{
+			name: "fallback of the fallback is not followed",
+			identities: []runtime.Object{
+				primary(&corev1.ObjectReference{Name: "fallback"}),
+				fallback("fallback", &corev1.ObjectReference{Name: "second-fallback"}),
+				fallback("second-fallback", nil),
+			},
+			wantFallbackName: "fallback",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(append(tc.identities, cluster)...).Build()
+			provider, err := NewAzureClusterCredentialsProvider(context.Background(), fakeClient, cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			cred, err := provider.GetTokenCredential(context.Background(), "", "", "")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			fallbackCred, ok := cred.(*fallbackTokenCredential)
+			if tc.wantFallbackName == "" {
+				g.Expect(ok).To(BeFalse())
+				return
+			}
+			g.Expect(ok).To(BeTrue())
+			g.Expect(fallbackCred.fallbackIdentityName).To(Equal(tc.wantFallbackName))
+			_, isFallbackCred := fallbackCred.fallback.(*fallbackTokenCredential)
+			g.Expect(isFallbackCred).To(BeFalse())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// imageReplicationRequeue is how long to wait before checking again whether a compute gallery image version has been
+// replicated to the location of the cluster.
+const imageReplicationRequeue = time.Minute
+
+// imageReplicationChecker checks whether the compute gallery image version of an image is replicated to a location.
+type imageReplicationChecker interface {
+	IsGalleryImageReplicated(ctx context.Context, image *infrav1.Image, location string) (bool, error)
+}
+
+// skipImageReplicationCheck returns true if the image replication check is disabled for the given object.
+func skipImageReplicationCheck(annotations map[string]string) bool {
+	return annotations[infrav1.SkipImageReplicationCheckAnnotation] == "true"
+}
+
+// checkImageReplicated holds the creation of VMs from a compute gallery image version that has not been replicated
+// to the location of the cluster yet, as the VMs would fail to be created. The ImageReplicated condition of the
+// object is set to false with a transient error until it is.
+func checkImageReplicated(ctx context.Context, checker imageReplicationChecker, obj conditions.Setter, image *infrav1.Image, location string) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.checkImageReplicated")
+	defer done()
+
+	replicated, err := checker.IsGalleryImageReplicated(ctx, image, location)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the replication of the image")
+	}
+	if !replicated {
+		log.Info("Waiting for the gallery image version to be replicated", "location", location)
+		conditions.MarkFalse(obj, infrav1.ImageReplicatedCondition, infrav1.ImageNotReplicatedReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for the gallery image version to be replicated to %s", location)
+		return azure.WithTransientError(errors.Errorf("gallery image version is not replicated to %s yet", location), imageReplicationRequeue)
+	}
+	if conditions.Has(obj, infrav1.ImageReplicatedCondition) {
+		conditions.MarkTrue(obj, infrav1.ImageReplicatedCondition)
+	}
+	return nil
+}
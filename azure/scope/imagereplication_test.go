@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+type fakeImageReplicationChecker struct {
+	replicated bool
+	err        error
+	calls      int
+}
+
+func (f *fakeImageReplicationChecker) IsGalleryImageReplicated(_ context.Context, _ *infrav1.Image, _ string) (bool, error) {
+	f.calls++
+	return f.replicated, f.err
+}
+
+func TestMachineScope_CheckImageReplicated(t *testing.T) {
+	image := &infrav1.Image{
+		ComputeGallery: &infrav1.AzureComputeGalleryImage{
+			Gallery:        "gallery",
+			Name:           "image",
+			Version:        "1.0.0",
+			SubscriptionID: ptr.To("sub"),
+			ResourceGroup:  ptr.To("rg"),
+		},
+	}
+	newMachineScope := func(checker imageReplicationChecker) *MachineScope {
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{Location: "eastus"},
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine"}},
+			cache:        &MachineCache{VMImage: image},
+			imageChecker: checker,
+		}
+	}
+
+	t.Run("holds the VM until the image is replicated", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachineScope(checker)
+
+		err := scope.checkImageReplicated(context.TODO())
+		g.Expect(err).To(HaveOccurred())
+		var reconcileError azure.ReconcileError
+		g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+		g.Expect(reconcileError.IsTransient()).To(BeTrue())
+		g.Expect(reconcileError.RequeueAfter()).To(Equal(imageReplicationRequeue))
+		g.Expect(conditions.IsFalse(scope.AzureMachine, infrav1.ImageReplicatedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(scope.AzureMachine, infrav1.ImageReplicatedCondition)).To(Equal(infrav1.ImageNotReplicatedReason))
+
+		checker.replicated = true
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(conditions.IsTrue(scope.AzureMachine, infrav1.ImageReplicatedCondition)).To(BeTrue())
+	})
+
+	t.Run("doesn't set the condition when the image is replicated", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := newMachineScope(&fakeImageReplicationChecker{replicated: true})
+
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(conditions.Has(scope.AzureMachine, infrav1.ImageReplicatedCondition)).To(BeFalse())
+	})
+
+	t.Run("returns the error of the check", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := newMachineScope(&fakeImageReplicationChecker{err: errors.New("boom")})
+
+		err := scope.checkImageReplicated(context.TODO())
+		g.Expect(err).To(MatchError(ContainSubstring("boom")))
+	})
+
+	t.Run("skips the check when the annotation is set", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachineScope(checker)
+		scope.AzureMachine.Annotations = map[string]string{infrav1.SkipImageReplicationCheckAnnotation: "true"}
+
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(checker.calls).To(BeZero())
+	})
+
+	t.Run("skips the check once the VM exists", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachineScope(checker)
+		scope.AzureMachine.Spec.ProviderID = ptr.To("azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/machine")
+
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(checker.calls).To(BeZero())
+	})
+}
+
+func TestMachinePoolScope_CheckImageReplicated(t *testing.T) {
+	image := &infrav1.Image{
+		SharedGallery: &infrav1.AzureSharedGalleryImage{
+			SubscriptionID: "sub",
+			ResourceGroup:  "rg",
+			Gallery:        "gallery",
+			Name:           "image",
+			Version:        "2.0.0",
+		},
+	}
+	newMachinePoolScope := func(checker imageReplicationChecker) *MachinePoolScope {
+		return &MachinePoolScope{
+			ClusterScoper: &ClusterScope{
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{Location: "eastus"},
+					},
+				},
+			},
+			AzureMachinePool: &infrav1exp.AzureMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}},
+			cache:            &MachinePoolCache{VMImage: image},
+			imageChecker:     checker,
+		}
+	}
+
+	t.Run("holds the rollout of a new image until it is replicated", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachinePoolScope(checker)
+
+		err := scope.checkImageReplicated(context.TODO())
+		var reconcileError azure.ReconcileError
+		g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+		g.Expect(reconcileError.IsTransient()).To(BeTrue())
+		g.Expect(conditions.IsFalse(scope.AzureMachinePool, infrav1.ImageReplicatedCondition)).To(BeTrue())
+
+		checker.replicated = true
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(conditions.IsTrue(scope.AzureMachinePool, infrav1.ImageReplicatedCondition)).To(BeTrue())
+	})
+
+	t.Run("skips the check when the annotation is set", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachinePoolScope(checker)
+		scope.AzureMachinePool.Annotations = map[string]string{infrav1.SkipImageReplicationCheckAnnotation: "true"}
+
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(checker.calls).To(BeZero())
+	})
+
+	t.Run("skips the check for the image already rolled out", func(t *testing.T) {
+		g := NewWithT(t)
+		checker := &fakeImageReplicationChecker{}
+		scope := newMachinePoolScope(checker)
+		scope.AzureMachinePool.Status.Image = image.DeepCopy()
+
+		g.Expect(scope.checkImageReplicated(context.TODO())).To(Succeed())
+		g.Expect(checker.calls).To(BeZero())
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ infrav1.LocalDiskSizeGetter = (*ManagedMachinePoolLocalDiskSizeGetter)(nil)
+
+// ManagedMachinePoolLocalDiskSizeGetter gets the sizes of the local disks of the VM size of an AzureManagedMachinePool
+// using the identity of its AzureManagedControlPlane. The VM size is looked up from the resource SKUs, which are
+// cached per location and identity.
+type ManagedMachinePoolLocalDiskSizeGetter struct {
+	Client client.Client
+}
+
+// GetMaxEphemeralOSDiskSizeGB returns the largest ephemeral OS disk size in GB supported by the VM size of the
+// AzureManagedMachinePool.
+func (g *ManagedMachinePoolLocalDiskSizeGetter) GetMaxEphemeralOSDiskSizeGB(ctx context.Context, managedMachinePool *infrav1.AzureManagedMachinePool) (int, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedMachinePoolLocalDiskSizeGetter.GetMaxEphemeralOSDiskSizeGB")
+	defer done()
+
+	sku, err := g.getSKU(ctx, managedMachinePool)
+	if err != nil {
+		return 0, err
+	}
+
+	maxSizeGB, err := sku.MaxEphemeralOSDiskSizeGB()
+	if err != nil {
+		return 0, err
+	}
+	return int(maxSizeGB), nil
+}
+
+// GetTempDiskSizeGB returns the size in GB of the temp disk of the VM size of the AzureManagedMachinePool, which is 0
+// when the VM size has no temp disk.
+func (g *ManagedMachinePoolLocalDiskSizeGetter) GetTempDiskSizeGB(ctx context.Context, managedMachinePool *infrav1.AzureManagedMachinePool) (int, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedMachinePoolLocalDiskSizeGetter.GetTempDiskSizeGB")
+	defer done()
+
+	sku, err := g.getSKU(ctx, managedMachinePool)
+	if err != nil {
+		return 0, err
+	}
+
+	sizeGB, err := sku.TempDiskSizeGB()
+	if err != nil {
+		return 0, err
+	}
+	return int(sizeGB), nil
+}
+
+// getSKU returns the resource SKU of the VM size of the AzureManagedMachinePool.
+func (g *ManagedMachinePoolLocalDiskSizeGetter) getSKU(ctx context.Context, managedMachinePool *infrav1.AzureManagedMachinePool) (resourceskus.SKU, error) {
+	managedControlPlane, err := g.getManagedControlPlane(ctx, managedMachinePool)
+	if err != nil {
+		return resourceskus.SKU{}, err
+	}
+
+	credentialsProvider, err := NewManagedControlPlaneCredentialsProvider(ctx, g.Client, managedControlPlane)
+	if err != nil {
+		return resourceskus.SKU{}, errors.Wrap(err, "failed to init credentials provider")
+	}
+
+	auth := &clientsAuthorizer{}
+	if err := auth.setCredentialsWithProvider(ctx, managedControlPlane.Spec.SubscriptionID, managedControlPlane.Spec.AzureEnvironment, credentialsProvider); err != nil {
+		return resourceskus.SKU{}, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
+	}
+
+	skuCache, err := resourceskus.GetCache(auth, managedControlPlane.Spec.Location)
+	if err != nil {
+		return resourceskus.SKU{}, errors.Wrap(err, "failed to init resourceskus cache")
+	}
+
+	sku, err := skuCache.Get(ctx, managedMachinePool.Spec.SKU, resourceskus.VirtualMachines)
+	if err != nil {
+		return resourceskus.SKU{}, errors.Wrapf(err, "failed to get SKU %s", managedMachinePool.Spec.SKU)
+	}
+	return sku, nil
+}
+
+// getManagedControlPlane returns the AzureManagedControlPlane of the Cluster the AzureManagedMachinePool belongs to.
+func (g *ManagedMachinePoolLocalDiskSizeGetter) getManagedControlPlane(ctx context.Context, managedMachinePool *infrav1.AzureManagedMachinePool) (*infrav1.AzureManagedControlPlane, error) {
+	clusterName, ok := managedMachinePool.Labels[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil, errors.Errorf("missing %s label", clusterv1.ClusterNameLabel)
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := g.Client.Get(ctx, client.ObjectKey{Namespace: managedMachinePool.Namespace, Name: clusterName}, cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s", clusterName)
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return nil, errors.Errorf("Cluster %s has no control plane reference", clusterName)
+	}
+
+	managedControlPlane := &infrav1.AzureManagedControlPlane{}
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.ControlPlaneRef.Name}
+	if err := g.Client.Get(ctx, key, managedControlPlane); err != nil {
+		return nil, errors.Wrapf(err, "failed to get AzureManagedControlPlane %s", key.Name)
+	}
+	return managedControlPlane, nil
+}
@@ -18,13 +18,16 @@ package scope
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -40,6 +43,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	conditionsutils "sigs.k8s.io/cluster-api-provider-azure/util/conditions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -50,6 +54,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Types of the Azure resources created for a machine that are recorded in its status.
+const (
+	diskResourceType             = "Microsoft.Compute/disks"
+	inboundNatRuleResourceType   = "Microsoft.Network/loadBalancers/inboundNatRules"
+	networkInterfaceResourceType = "Microsoft.Network/networkInterfaces"
+	publicIPResourceType         = "Microsoft.Network/publicIPAddresses"
+)
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -58,6 +70,9 @@ type MachineScopeParams struct {
 	AzureMachine *infrav1.AzureMachine
 	Cache        *MachineCache
 	SKUCache     SKUCacher
+	// VMNotFoundGracePeriod is the duration after the creation of the VM during which the VM not being found is
+	// retried rather than treated as the VM having been deleted.
+	VMNotFoundGracePeriod time.Duration
 }
 
 // NewMachineScope creates a new MachineScope from the supplied parameters.
@@ -86,6 +101,8 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		ClusterScoper: params.ClusterScope,
 		cache:         params.Cache,
 		skuCache:      params.SKUCache,
+
+		vmNotFoundGracePeriod: params.VMNotFoundGracePeriod,
 	}, nil
 }
 
@@ -99,6 +116,9 @@ type MachineScope struct {
 	AzureMachine *infrav1.AzureMachine
 	cache        *MachineCache
 	skuCache     SKUCacher
+	imageChecker imageReplicationChecker
+
+	vmNotFoundGracePeriod time.Duration
 }
 
 // SKUCacher fetches a SKU from its cache.
@@ -133,6 +153,10 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
+		if err := m.checkImageReplicated(ctx); err != nil {
+			return err
+		}
+
 		skuCache := m.skuCache
 		if skuCache == nil {
 			cache, err := resourceskus.GetCache(m, m.Location())
@@ -156,6 +180,33 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 	return nil
 }
 
+// ResourceNames returns the names of the resources of the machine rendered by the naming template of the cluster.
+// The resources without a template, or whose template fails to render, keep the names generated from the name of
+// the machine.
+func (m *MachineScope) ResourceNames() azure.MachineResourceNames {
+	names := azure.MachineResourceNames{MachineName: m.Name()}
+	namingTemplate := m.ClusterScoper.NamingTemplate()
+	if namingTemplate == nil {
+		return names
+	}
+	vars := infrav1.NewNamingTemplateVariables(m.AzureMachine.Namespace, m.ClusterName(), m.Name(), m.Role())
+	render := func(tmpl string) string {
+		if tmpl == "" {
+			return ""
+		}
+		name, err := infrav1.RenderName(tmpl, vars)
+		if err != nil {
+			// The templates are validated at admission, so this shouldn't happen.
+			return ""
+		}
+		return name
+	}
+	names.NetworkInterface = render(namingTemplate.NetworkInterface)
+	names.PublicIP = render(namingTemplate.PublicIP)
+	names.Disk = render(namingTemplate.Disk)
+	return names
+}
+
 // VMSpec returns the VM spec.
 func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 	spec := &virtualmachines.VMSpec{
@@ -169,6 +220,7 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 		SSHKeyData:             m.AzureMachine.Spec.SSHPublicKey,
 		Size:                   m.AzureMachine.Spec.VMSize,
 		OSDisk:                 m.AzureMachine.Spec.OSDisk,
+		PatchSettings:          m.AzureMachine.Spec.PatchSettings,
 		DataDisks:              m.AzureMachine.Spec.DataDisks,
 		AvailabilitySetID:      m.AvailabilitySetID(),
 		Zone:                   m.AvailabilityZone(),
@@ -180,6 +232,11 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 		AdditionalTags:         m.AdditionalTags(),
 		AdditionalCapabilities: m.AzureMachine.Spec.AdditionalCapabilities,
 		ProviderID:             m.ProviderID(),
+		NotFoundGracePeriod:    m.vmNotFoundGracePeriod,
+		ResourceNames:          m.ResourceNames(),
+	}
+	if m.AzureMachine.Status.VMCreationTime != nil {
+		spec.CreationTime = m.AzureMachine.Status.VMCreationTime.Time
 	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
@@ -205,7 +262,7 @@ func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 	var specs []azure.ResourceSpecGetter
 	if m.AzureMachine.Spec.AllocatePublicIP {
 		specs = append(specs, &publicips.PublicIPSpec{
-			Name:             azure.GenerateNodePublicIPName(m.Name()),
+			Name:             m.ResourceNames().PublicIPName(),
 			ResourceGroup:    m.NodeResourceGroup(),
 			ClusterName:      m.ClusterName(),
 			DNSName:          "",    // Set to default value
@@ -216,11 +273,21 @@ func (m *MachineScope) PublicIPSpecs() []azure.ResourceSpecGetter {
 			AdditionalTags:   m.ClusterScoper.AdditionalTags(),
 		})
 	}
+	for _, id := range m.orphanedManagedResources(publicIPResourceType, specs, func(spec azure.ResourceSpecGetter) string {
+		return azure.PublicIPID(m.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+	}) {
+		specs = append(specs, &publicips.PublicIPSpec{
+			Name:          id.Name,
+			ResourceGroup: id.ResourceGroupName,
+			ClusterName:   m.ClusterName(),
+		})
+	}
 	return specs
 }
 
 // InboundNatSpecs returns the inbound NAT specs.
 func (m *MachineScope) InboundNatSpecs() []azure.ResourceSpecGetter {
+	specs := []azure.ResourceSpecGetter{}
 	// The existing inbound NAT rules are needed in order to find an available SSH port for each new inbound NAT rule.
 	if m.Role() == infrav1.ControlPlane {
 		spec := &inboundnatrules.InboundNatSpec{
@@ -234,10 +301,28 @@ func (m *MachineScope) InboundNatSpecs() []azure.ResourceSpecGetter {
 			id := azure.FrontendIPConfigID(m.SubscriptionID(), m.NodeResourceGroup(), m.APIServerLBName(), ipConfig)
 			spec.FrontendIPConfigurationID = ptr.To(id)
 		}
+		// The inbound NAT rule stays on the public load balancer until the machines still reaching the API server
+		// through it are replaced.
+		if migration := m.migratingAPIServerLB(); migration != nil && migration.Phase != infrav1.APIServerLBMigrationDeletingPublicLB {
+			spec.LoadBalancerName = migration.PublicLBName
+			spec.FrontendIPConfigurationID = nil
+			if migration.PublicLBFrontendIPName != "" {
+				spec.FrontendIPConfigurationID = ptr.To(azure.FrontendIPConfigID(m.SubscriptionID(), m.NodeResourceGroup(), migration.PublicLBName, migration.PublicLBFrontendIPName))
+			}
+		}
 
-		return []azure.ResourceSpecGetter{spec}
+		specs = append(specs, spec)
 	}
-	return []azure.ResourceSpecGetter{}
+	for _, id := range m.orphanedManagedResources(inboundNatRuleResourceType, specs, func(spec azure.ResourceSpecGetter) string {
+		return azure.NATRuleID(m.SubscriptionID(), spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName())
+	}) {
+		specs = append(specs, &inboundnatrules.InboundNatSpec{
+			Name:             id.Name,
+			ResourceGroup:    id.ResourceGroupName,
+			LoadBalancerName: id.Parent.Name,
+		})
+	}
+	return specs
 }
 
 // NICSpecs returns the network interface specs.
@@ -247,12 +332,24 @@ func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 	// For backwards compatibility we need to ensure the NIC Name does not change on existing machines
 	// created prior to multiple NIC support
 	isMultiNIC := len(m.AzureMachine.Spec.NetworkInterfaces) > 1
+	names := m.ResourceNames()
 
 	for i := 0; i < len(m.AzureMachine.Spec.NetworkInterfaces); i++ {
 		isPrimary := i == 0
-		nicName := azure.GenerateNICName(m.Name(), isMultiNIC, i)
+		nicName := names.NICName(isMultiNIC, i)
 		nicSpecs = append(nicSpecs, m.BuildNICSpec(nicName, m.AzureMachine.Spec.NetworkInterfaces[i], isPrimary))
 	}
+	for _, id := range m.orphanedManagedResources(networkInterfaceResourceType, nicSpecs, func(spec azure.ResourceSpecGetter) string {
+		return azure.NetworkInterfaceID(m.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+	}) {
+		nicSpecs = append(nicSpecs, &networkinterfaces.NICSpec{
+			Name:           id.Name,
+			ResourceGroup:  id.ResourceGroupName,
+			SubscriptionID: id.SubscriptionID,
+			MachineName:    m.Name(),
+			ClusterName:    m.ClusterName(),
+		})
+	}
 	return nicSpecs
 }
 
@@ -280,10 +377,19 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 		spec.SKU = &m.cache.VMSKU
 	}
 
-	for i := 0; i < infrav1NetworkInterface.PrivateIPConfigs; i++ {
+	// An unset PrivateIPConfigs still means the primary IP configuration, so that the secondary ones are removed.
+	spec.IPConfigs = append(spec.IPConfigs, networkinterfaces.IPConfig{})
+	for i := 1; i < infrav1NetworkInterface.PrivateIPConfigs; i++ {
 		spec.IPConfigs = append(spec.IPConfigs, networkinterfaces.IPConfig{})
 	}
 
+	for _, subnet := range m.Subnets() {
+		if subnet.Name == infrav1NetworkInterface.SubnetName {
+			spec.SubnetCIDRs = subnet.CIDRBlocks
+			break
+		}
+	}
+
 	if primaryNetworkInterface {
 		spec.DNSServers = m.AzureMachine.Spec.DNSServers
 
@@ -297,10 +403,11 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 				spec.PublicLBNATRuleName = m.Name()
 				spec.PublicLBAddressPoolName = m.APIServerLBPoolName()
 			}
+			m.setAPIServerLBMigrationReferences(spec)
 		}
 
 		if m.Role() == infrav1.Node && m.AzureMachine.Spec.AllocatePublicIP {
-			spec.PublicIPName = azure.GenerateNodePublicIPName(m.Name())
+			spec.PublicIPName = m.ResourceNames().PublicIPName()
 		}
 		// If the NAT gateway is not enabled and node has no public IP, then the NIC needs to reference the LB to get outbound traffic.
 		if m.Role() == infrav1.Node && !m.Subnet().IsNatGatewayEnabled() && !m.AzureMachine.Spec.AllocatePublicIP {
@@ -312,6 +419,53 @@ func (m *MachineScope) BuildNICSpec(nicName string, infrav1NetworkInterface infr
 	return spec
 }
 
+// migratingAPIServerLB returns the migration of the API server load balancer from Public to Internal once its type is
+// changed, or nil if it isn't migrated or the machine isn't a control plane machine.
+func (m *MachineScope) migratingAPIServerLB() *infrav1.APIServerLBMigration {
+	if m.Role() != infrav1.ControlPlane || !m.IsAPIServerPrivate() {
+		return nil
+	}
+	migration := m.APIServerLBMigration()
+	if migration == nil {
+		return nil
+	}
+	switch migration.Phase {
+	case infrav1.APIServerLBMigrationCreatingInternalLB, infrav1.APIServerLBMigrationSwitchingEndpoint,
+		infrav1.APIServerLBMigrationReplacingMachines, infrav1.APIServerLBMigrationDeletingPublicLB:
+		return migration
+	default:
+		return nil
+	}
+}
+
+// setAPIServerLBMigrationReferences sets the load balancers of the primary network interface of a control plane machine
+// while the API server load balancer is migrated from Public to Internal. The machine stays in the public load
+// balancer, which also provides its outbound connectivity, until the machines still reaching the API server through it
+// are replaced. It is added to the internal load balancer once it exists, and then removed from the public one so
+// that it can be deleted.
+func (m *MachineScope) setAPIServerLBMigrationReferences(spec *networkinterfaces.NICSpec) {
+	migration := m.migratingAPIServerLB()
+	if migration == nil {
+		return
+	}
+	switch migration.Phase {
+	case infrav1.APIServerLBMigrationCreatingInternalLB, infrav1.APIServerLBMigrationSwitchingEndpoint,
+		infrav1.APIServerLBMigrationReplacingMachines:
+		spec.PublicLBName = migration.PublicLBName
+		spec.PublicLBAddressPoolName = migration.PublicLBBackendPoolName
+		spec.PublicLBNATRuleName = m.Name()
+		if migration.Phase == infrav1.APIServerLBMigrationCreatingInternalLB {
+			spec.InternalLBName = ""
+			spec.InternalLBAddressPoolName = ""
+		} else {
+			spec.UpdateLBReferences = true
+		}
+	case infrav1.APIServerLBMigrationDeletingPublicLB:
+		spec.DetachedLBName = migration.PublicLBName
+		spec.UpdateLBReferences = true
+	}
+}
+
 // NICIDs returns the NIC resource IDs.
 func (m *MachineScope) NICIDs() []string {
 	nicspecs := m.NICSpecs()
@@ -325,18 +479,27 @@ func (m *MachineScope) NICIDs() []string {
 
 // DiskSpecs returns the disk specs.
 func (m *MachineScope) DiskSpecs() []azure.ResourceSpecGetter {
+	names := m.ResourceNames()
 	diskSpecs := make([]azure.ResourceSpecGetter, 1+len(m.AzureMachine.Spec.DataDisks))
 	diskSpecs[0] = &disks.DiskSpec{
-		Name:          azure.GenerateOSDiskName(m.Name()),
+		Name:          names.OSDiskName(),
 		ResourceGroup: m.NodeResourceGroup(),
 	}
 
 	for i, dd := range m.AzureMachine.Spec.DataDisks {
 		diskSpecs[i+1] = &disks.DiskSpec{
-			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
+			Name:          names.DataDiskName(dd.NameSuffix),
 			ResourceGroup: m.NodeResourceGroup(),
 		}
 	}
+	for _, id := range m.orphanedManagedResources(diskResourceType, diskSpecs, func(spec azure.ResourceSpecGetter) string {
+		return azure.DiskID(m.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+	}) {
+		diskSpecs = append(diskSpecs, &disks.DiskSpec{
+			Name:          id.Name,
+			ResourceGroup: id.ResourceGroupName,
+		})
+	}
 	return diskSpecs
 }
 
@@ -369,10 +532,11 @@ func (m *MachineScope) HasSystemAssignedIdentity() bool {
 	return m.AzureMachine.Spec.Identity == infrav1.VMIdentitySystemAssigned
 }
 
-// VMExtensionSpecs returns the VM extension specs.
+// VMExtensionSpecs returns the VM extension specs, including the extensions of the cluster.
 func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 	var extensionSpecs = []azure.ResourceSpecGetter{}
-	for _, extension := range m.AzureMachine.Spec.VMExtensions {
+	extensions := append(append([]infrav1.VMExtension{}, m.AzureMachine.Spec.VMExtensions...), m.clusterVMExtensions()...)
+	for _, extension := range extensions {
 		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
 			ExtensionSpec: azure.ExtensionSpec{
 				Name:              extension.Name,
@@ -401,6 +565,39 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 	return extensionSpecs
 }
 
+// ClusterVMExtensionNames returns the names of the extensions of the cluster installed on the VM.
+func (m *MachineScope) ClusterVMExtensionNames() []string {
+	extensions := m.clusterVMExtensions()
+	names := make([]string, 0, len(extensions))
+	for _, extension := range extensions {
+		names = append(names, extension.Name)
+	}
+	return names
+}
+
+// clusterVMExtensions returns the extensions of the cluster which apply to the VM and aren't overridden by an
+// extension of the machine.
+func (m *MachineScope) clusterVMExtensions() []infrav1.VMExtension {
+	return clusterVMExtensions(m.ClusterScoper.ClusterVMExtensions(), m.AzureMachine.Spec.OSDisk.OSType, m.AzureMachine.Spec.VMExtensions)
+}
+
+// clusterVMExtensions returns the extensions of the cluster for machines with the given OS type, skipping the ones
+// with the same name as an extension of the machine, which takes precedence.
+func clusterVMExtensions(clusterExtensions []infrav1.ClusterVMExtension, osType string, machineExtensions []infrav1.VMExtension) []infrav1.VMExtension {
+	overridden := make(map[string]bool, len(machineExtensions))
+	for _, extension := range machineExtensions {
+		overridden[extension.Name] = true
+	}
+	var extensions []infrav1.VMExtension
+	for _, extension := range clusterExtensions {
+		if overridden[extension.Name] || (extension.OSType != "" && extension.OSType != osType) {
+			continue
+		}
+		extensions = append(extensions, extension.VMExtension)
+	}
+	return extensions
+}
+
 // Subnet returns the machine's subnet.
 func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 	for _, subnet := range m.Subnets() {
@@ -572,6 +769,29 @@ func (m *MachineScope) SetVMState(v infrav1.ProvisioningState) {
 	m.AzureMachine.Status.VMState = &v
 }
 
+// DesiredPowerState returns the power state the AzureMachine VM should be in, or an empty power state if CAPZ doesn't
+// manage the power state of the VM.
+func (m *MachineScope) DesiredPowerState() infrav1.VMPowerState {
+	return m.AzureMachine.Spec.PowerState
+}
+
+// SetPowerState sets the AzureMachine VM power state.
+func (m *MachineScope) SetPowerState(v infrav1.VMPowerState) {
+	m.AzureMachine.Status.PowerState = v
+}
+
+// SetVMCreationTime records when the AzureMachine VM was created if it isn't already known.
+func (m *MachineScope) SetVMCreationTime(v time.Time) {
+	if m.AzureMachine.Status.VMCreationTime == nil {
+		m.AzureMachine.Status.VMCreationTime = &metav1.Time{Time: v}
+	}
+}
+
+// SetLastBootTime records when the AzureMachine VM last booted.
+func (m *MachineScope) SetLastBootTime(v time.Time) {
+	m.AzureMachine.Status.LastBootTime = &metav1.Time{Time: v}
+}
+
 // SetReady sets the AzureMachine Ready Status to true.
 func (m *MachineScope) SetReady() {
 	m.AzureMachine.Status.Ready = true
@@ -582,6 +802,31 @@ func (m *MachineScope) SetNotReady() {
 	m.AzureMachine.Status.Ready = false
 }
 
+// CheckProvisioningTimeout reports whether the VM has existed longer than the provisioning timeout of the AzureMachine
+// at now without its bootstrap data succeeding or its node registering, and updates the BootstrapWithinTimeout
+// condition. While the timeout hasn't expired, it returns how long is left.
+func (m *MachineScope) CheckProvisioningTimeout(now time.Time) (bool, time.Duration) {
+	timeout := m.AzureMachine.Spec.ProvisioningTimeout
+	if timeout == nil {
+		m.DeleteCondition(infrav1.BootstrapWithinTimeoutCondition)
+		return false, 0
+	}
+	if m.Machine.Status.NodeRef != nil || conditions.IsTrue(m.AzureMachine, infrav1.BootstrapSucceededCondition) {
+		m.SetConditionTrue(infrav1.BootstrapWithinTimeoutCondition)
+		return false, 0
+	}
+	// The timer is based on when Azure created the VM, which is only known once the VM exists.
+	if m.AzureMachine.Status.VMCreationTime == nil {
+		return false, 0
+	}
+	if remaining := m.AzureMachine.Status.VMCreationTime.Add(timeout.Duration).Sub(now); remaining > 0 {
+		return false, remaining
+	}
+	m.SetConditionFalse(infrav1.BootstrapWithinTimeoutCondition, infrav1.BootstrapTimedOutReason, clusterv1.ConditionSeverityError,
+		fmt.Sprintf("VM has existed for longer than %s without its bootstrap data succeeding or its node registering", timeout.Duration))
+	return true, 0
+}
+
 // SetFailureMessage sets the AzureMachine status failure message.
 func (m *MachineScope) SetFailureMessage(v error) {
 	m.AzureMachine.Status.FailureMessage = ptr.To(v.Error())
@@ -597,6 +842,16 @@ func (m *MachineScope) SetConditionFalse(conditionType clusterv1.ConditionType,
 	conditions.MarkFalse(m.AzureMachine, conditionType, reason, severity, message)
 }
 
+// SetConditionTrue sets the specified AzureMachine condition to true.
+func (m *MachineScope) SetConditionTrue(conditionType clusterv1.ConditionType) {
+	conditions.MarkTrue(m.AzureMachine, conditionType)
+}
+
+// DeleteCondition removes the specified condition from the AzureMachine.
+func (m *MachineScope) DeleteCondition(conditionType clusterv1.ConditionType) {
+	conditions.Delete(m.AzureMachine, conditionType)
+}
+
 // SetAnnotation sets a key value annotation on the AzureMachine.
 func (m *MachineScope) SetAnnotation(key, value string) {
 	if m.AzureMachine.Annotations == nil {
@@ -637,9 +892,13 @@ func (m *MachineScope) SetAddresses(addrs []corev1.NodeAddress) {
 	m.AzureMachine.Status.Addresses = addrs
 }
 
+// SetOutboundIPs is a no-op, as the public IPs of machines aren't reported as outbound IPs of the cluster.
+func (m *MachineScope) SetOutboundIPs(_ []string) {}
+
 // PatchObject persists the machine spec and status.
 func (m *MachineScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(m.AzureMachine)
+	conditionsutils.SetV1Beta2Conditions(m.AzureMachine)
 
 	return m.patchHelper.Patch(
 		ctx,
@@ -647,8 +906,11 @@ func (m *MachineScope) PatchObject(ctx context.Context) error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.VMAgentReadyCondition,
+			infrav1.BootstrapWithinTimeoutCondition,
 			infrav1.AvailabilitySetReadyCondition,
 			infrav1.NetworkInterfaceReadyCondition,
+			infrav1.WaitingForPreTerminateHookCondition,
 		}})
 }
 
@@ -671,7 +933,8 @@ func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	return tags
 }
 
-// GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
+// GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName, encoded as
+// the custom data of the VM. Bootstrap data the VM can't use is a terminal error.
 func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetBootstrapData")
 	defer done()
@@ -685,11 +948,88 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachine %s/%s", m.Namespace(), m.Name())
 	}
 
-	value, ok := secret.Data["value"]
-	if !ok {
+	if _, ok := secret.Data["value"]; !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
-	return base64.StdEncoding.EncodeToString(value), nil
+
+	data, err := encodeBootstrapData(secret, m.AzureMachine.Spec.OSDisk.OSType, m.AzureMachine.Spec.Image)
+	if err != nil {
+		conditions.MarkFalse(m.AzureMachine, infrav1.VMRunningCondition, infrav1.InvalidBootstrapDataReason, clusterv1.ConditionSeverityError, err.Error())
+		return "", azure.WithTerminalError(errors.Wrapf(err, "invalid bootstrap data secret %s", key))
+	}
+	return data, nil
+}
+
+// InitManagedResources starts recording the Azure resources created by CAPZ for a machine whose virtual machine has
+// not been created yet. Machines created before CAPZ recorded the resources it creates keep relying on their spec.
+func (m *MachineScope) InitManagedResources() {
+	if m.AzureMachine.Status.ManagedResources == nil && m.ProviderID() == "" {
+		m.AzureMachine.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+}
+
+// IsOwnershipRecorded returns true if the Azure resources created by CAPZ are recorded for the machine.
+func (m *MachineScope) IsOwnershipRecorded() bool {
+	return m.AzureMachine.Status.ManagedResources != nil
+}
+
+// RecordManagedResource records that CAPZ created the Azure resource with the given ID.
+func (m *MachineScope) RecordManagedResource(id string) {
+	m.AzureMachine.Status.ManagedResources.Add(id)
+}
+
+// ForgetManagedResource removes the Azure resource with the given ID from the resources recorded as created by CAPZ
+// once it has been deleted.
+func (m *MachineScope) ForgetManagedResource(id string) {
+	m.AzureMachine.Status.ManagedResources.Remove(id)
+}
+
+// IsManagedResource returns true if the Azure resource with the given ID was recorded as created by CAPZ or if
+// ownedByTags, the result of the tag-based check, is true. Unlike for clusters, the record only adds to the tag-based
+// check as the resources of a machine are named after it.
+func (m *MachineScope) IsManagedResource(id string, ownedByTags bool) bool {
+	return ownedByTags || m.AzureMachine.Status.ManagedResources.Has(id)
+}
+
+// orphanedManagedResources returns the resources of the given type recorded as created by CAPZ that aren't part of
+// specs, e.g. the ones created by a failed attempt to create the machine, so that they are deleted along with it.
+// Nothing is returned unless the machine is being deleted.
+func (m *MachineScope) orphanedManagedResources(resourceType string, specs []azure.ResourceSpecGetter, idFunc func(azure.ResourceSpecGetter) string) []*arm.ResourceID {
+	if m.AzureMachine.DeletionTimestamp.IsZero() || !m.IsOwnershipRecorded() {
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[strings.ToLower(idFunc(spec))] = true
+	}
+
+	var orphaned []*arm.ResourceID
+	for _, recordedID := range m.AzureMachine.Status.ManagedResources.IDs {
+		id, err := azureutil.ParseResourceID(recordedID)
+		if err != nil || !strings.EqualFold(id.ResourceType.String(), resourceType) || wanted[strings.ToLower(recordedID)] {
+			continue
+		}
+		orphaned = append(orphaned, id)
+	}
+	return orphaned
+}
+
+// checkImageReplicated holds the creation of the VM until its compute gallery image version has been replicated to
+// the location of the cluster.
+func (m *MachineScope) checkImageReplicated(ctx context.Context) error {
+	if m.ProviderID() != "" || skipImageReplicationCheck(m.AzureMachine.Annotations) {
+		return nil
+	}
+	checker := m.imageChecker
+	if checker == nil {
+		svc, err := virtualmachineimages.New(m)
+		if err != nil {
+			return errors.Wrap(err, "failed to create virtualmachineimages service")
+		}
+		checker = svc
+	}
+	return checkImageReplicated(ctx, checker, m.AzureMachine, m.cache.VMImage, m.Location())
 }
 
 // GetVMImage returns the image from the machine configuration, or a default one.
@@ -714,6 +1054,11 @@ func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 		return svc.GetDefaultWindowsImage(ctx, m.Location(), ptr.Deref(m.Machine.Spec.Version, ""), runtime, windowsServerVersion)
 	}
 
+	if defaultImage := m.ClusterScoper.DefaultImage(); defaultImage != nil {
+		log.Info("No image specified for machine, using cluster default image", "machine", m.AzureMachine.GetName(), "gallery", defaultImage.Gallery, "image", defaultImage.Name)
+		return virtualmachineimages.GetDefaultComputeGalleryImage(defaultImage, ptr.Deref(m.Machine.Spec.Version, ""))
+	}
+
 	log.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
 	return svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.Machine.Spec.Version, ""))
 }
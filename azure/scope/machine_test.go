@@ -19,7 +19,9 @@ package scope
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	azureautorest "github.com/Azure/go-autorest/autorest/azure"
@@ -27,6 +29,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -43,6 +46,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -874,6 +878,96 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "If the cluster has VM extensions, it returns the ones for the OS type which the machine doesn't override",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+						VMExtensions: []infrav1.VMExtension{
+							{
+								Name:      "AzureMonitorLinuxAgent",
+								Publisher: "Microsoft.Azure.Monitor",
+								Version:   "1.29",
+							},
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: azureautorest.Environment{
+								Name: azureautorest.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+								VMExtensions: []infrav1.ClusterVMExtension{
+									{
+										VMExtension: infrav1.VMExtension{
+											Name:      "AzureMonitorLinuxAgent",
+											Publisher: "Microsoft.Azure.Monitor",
+											Version:   "1.28",
+										},
+										OSType: "Linux",
+									},
+									{
+										VMExtension: infrav1.VMExtension{
+											Name:      "AzureMonitorWindowsAgent",
+											Publisher: "Microsoft.Azure.Monitor",
+											Version:   "1.22",
+										},
+										OSType: "Windows",
+									},
+									{
+										VMExtension: infrav1.VMExtension{
+											Name:      "DependencyAgent",
+											Publisher: "Microsoft.Azure.Monitoring.DependencyAgent",
+											Version:   "9.10",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: resourceskus.SKU{},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "AzureMonitorLinuxAgent",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.Monitor",
+						Version:   "1.29",
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "DependencyAgent",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.Monitoring.DependencyAgent",
+						Version:   "9.10",
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -884,6 +978,36 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_ClusterVMExtensionNames(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := MachineScope{
+		AzureMachine: &infrav1.AzureMachine{
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					OSType: "Windows",
+				},
+				VMExtensions: []infrav1.VMExtension{
+					{Name: "DependencyAgent"},
+				},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						VMExtensions: []infrav1.ClusterVMExtension{
+							{VMExtension: infrav1.VMExtension{Name: "AzureMonitorLinuxAgent"}, OSType: "Linux"},
+							{VMExtension: infrav1.VMExtension{Name: "AzureMonitorWindowsAgent"}, OSType: "Windows"},
+							{VMExtension: infrav1.VMExtension{Name: "DependencyAgent"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	g.Expect(machineScope.ClusterVMExtensionNames()).To(Equal([]string{"AzureMonitorWindowsAgent"}))
+}
+
 func TestMachineScope_Subnet(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1182,6 +1306,116 @@ func TestMachineScope_Role(t *testing.T) {
 	}
 }
 
+func TestMachineScope_ResourceNames(t *testing.T) {
+	newMachineScope := func(namingTemplate *infrav1.NamingTemplate) *MachineScope {
+		return &MachineScope{
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.MachineControlPlaneLabel: "",
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine-name",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					DataDisks: []infrav1.DataDisk{{NameSuffix: "etcddisk"}},
+				},
+			},
+			ClusterScoper: &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							NamingTemplate: namingTemplate,
+						},
+					},
+				},
+			},
+		}
+	}
+	suffix := infrav1.NewNamingTemplateVariables("default", "my-cluster", "machine-name", infrav1.ControlPlane).RandomSuffix
+
+	tests := []struct {
+		name           string
+		namingTemplate *infrav1.NamingTemplate
+		wantNIC        string
+		wantMultiNIC   string
+		wantPublicIP   string
+		wantOSDisk     string
+		wantDataDisk   string
+	}{
+		{
+			name:         "without a naming template",
+			wantNIC:      "machine-name-nic",
+			wantMultiNIC: "machine-name-nic-1",
+			wantPublicIP: "pip-machine-name",
+			wantOSDisk:   "machine-name_OSDisk",
+			wantDataDisk: "machine-name_etcddisk",
+		},
+		{
+			name: "with templates for every resource",
+			namingTemplate: &infrav1.NamingTemplate{
+				NetworkInterface: "nic-{{ .MachineName }}",
+				PublicIP:         "pip-{{ .ClusterName }}-{{ .RandomSuffix }}",
+				Disk:             "disk-{{ .Role }}-{{ .MachineName }}",
+			},
+			wantNIC:      "nic-machine-name",
+			wantMultiNIC: "nic-machine-name-1",
+			wantPublicIP: "pip-my-cluster-" + suffix,
+			wantOSDisk:   "disk-control-plane-machine-name",
+			wantDataDisk: "disk-control-plane-machine-name_etcddisk",
+		},
+		{
+			name: "with a template for some resources",
+			namingTemplate: &infrav1.NamingTemplate{
+				Disk: "{{ .ClusterName }}-{{ .MachineName }}-osdisk",
+			},
+			wantNIC:      "machine-name-nic",
+			wantMultiNIC: "machine-name-nic-1",
+			wantPublicIP: "pip-machine-name",
+			wantOSDisk:   "my-cluster-machine-name-osdisk",
+			wantDataDisk: "my-cluster-machine-name-osdisk_etcddisk",
+		},
+		{
+			name: "with a template failing to render",
+			namingTemplate: &infrav1.NamingTemplate{
+				NetworkInterface: "{{ .Namespace }}-nic",
+			},
+			wantNIC:      "machine-name-nic",
+			wantMultiNIC: "machine-name-nic-1",
+			wantPublicIP: "pip-machine-name",
+			wantOSDisk:   "machine-name_OSDisk",
+			wantDataDisk: "machine-name_etcddisk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := newMachineScope(tt.namingTemplate)
+			names := machineScope.ResourceNames()
+			g.Expect(names.NICName(false, 0)).To(Equal(tt.wantNIC))
+			g.Expect(names.NICName(true, 1)).To(Equal(tt.wantMultiNIC))
+			g.Expect(names.PublicIPName()).To(Equal(tt.wantPublicIP))
+			g.Expect(names.OSDiskName()).To(Equal(tt.wantOSDisk))
+			g.Expect(names.DataDiskName("etcddisk")).To(Equal(tt.wantDataDisk))
+
+			diskSpecs := machineScope.DiskSpecs()
+			g.Expect(diskSpecs).To(HaveLen(2))
+			g.Expect(diskSpecs[0].ResourceName()).To(Equal(tt.wantOSDisk))
+			g.Expect(diskSpecs[1].ResourceName()).To(Equal(tt.wantDataDisk))
+		})
+	}
+}
+
 func TestMachineScope_AvailabilitySet(t *testing.T) {
 	tests := []struct {
 		name                         string
@@ -1438,6 +1672,16 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 	clusterMock.EXPECT().SubscriptionID().AnyTimes()
 	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
 	clusterMock.EXPECT().Token().Return(&azidentity.DefaultAzureCredential{}).AnyTimes()
+	clusterMock.EXPECT().DefaultImage().AnyTimes()
+	defaultImageClusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
+	defaultImageClusterMock.EXPECT().Location().AnyTimes()
+	defaultImageClusterMock.EXPECT().SubscriptionID().AnyTimes()
+	defaultImageClusterMock.EXPECT().CloudEnvironment().AnyTimes()
+	defaultImageClusterMock.EXPECT().Token().Return(&azidentity.DefaultAzureCredential{}).AnyTimes()
+	defaultImageClusterMock.EXPECT().DefaultImage().Return(&infrav1.DefaultImage{
+		Gallery: "my-gallery",
+		Name:    "capi-ubun2-2204",
+	}).AnyTimes()
 	svc := virtualmachineimages.Service{Client: mock_virtualmachineimages.NewMockClient(mockCtrl)}
 
 	tests := []struct {
@@ -1704,6 +1948,82 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 			}(),
 			expectedErr: "",
 		},
+		{
+			name: "if no image is specified and the cluster has a default image, returns the cluster default image for the machine's version",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: ptr.To("v1.28.3"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				ClusterScoper: defaultImageClusterMock,
+			},
+			want: &infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery: "my-gallery",
+					Name:    "capi-ubun2-2204",
+					Version: "1.28.3",
+				},
+			},
+			expectedErr: "",
+		},
+		{
+			name: "AzureMachine image takes precedence over the cluster default image",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Image: &infrav1.Image{
+							ID: ptr.To("1"),
+						},
+					},
+				},
+				ClusterScoper: defaultImageClusterMock,
+			},
+			want: &infrav1.Image{
+				ID: ptr.To("1"),
+			},
+			expectedErr: "",
+		},
+		{
+			name: "if no image is specified and os specified is windows, the cluster default image is not used",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: ptr.To("1.23.3"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: azure.WindowsOS,
+						},
+					},
+				},
+				ClusterScoper: defaultImageClusterMock,
+			},
+			want: func() *infrav1.Image {
+				image, _ := svc.GetDefaultWindowsImage(context.TODO(), "", "1.23.3", "", "")
+				return image
+			}(),
+			expectedErr: "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1831,6 +2151,112 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Node Machine without privateIPConfigs still has the primary IP configuration",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{
+								auth.SubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+											Name: "subnet1",
+										},
+									},
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+									BackendPool: infrav1.BackendPool{
+										Name: "outbound-lb-outboundBackendPool",
+									},
+								},
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: ptr.To("azure:///subscriptions/1234-5678/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/machine-name"),
+						NetworkInterfaces: []infrav1.NetworkInterface{{
+							SubnetName: "subnet1",
+						}},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "machine",
+						Labels: map[string]string{
+							// clusterv1.MachineControlPlaneLabel: "true",
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&networkinterfaces.NICSpec{
+					Name:                      "machine-name-nic",
+					ResourceGroup:             "my-rg",
+					Location:                  "westus",
+					SubscriptionID:            "123",
+					MachineName:               "machine-name",
+					SubnetName:                "subnet1",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}},
+					VNetName:                  "vnet1",
+					VNetResourceGroup:         "rg1",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
+					PublicIPName:              "",
+					AcceleratedNetworking:     nil,
+					DNSServers:                nil,
+					IPv6Enabled:               false,
+					EnableIPForwarding:        false,
+					SKU:                       nil,
+					ClusterName:               "cluster",
+					AdditionalTags: infrav1.Tags{
+						"kubernetes.io_cluster_cluster": "owned",
+					},
+				},
+			},
+		},
 		{
 			name: "Node Machine with no NAT gateway and no public IP address and SKU is in machine cache",
 			machineScope: MachineScope{
@@ -3063,3 +3489,396 @@ func TestDiskSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineScope_APIServerLBMigrationSpecs(t *testing.T) {
+	publicNATRuleFrontend := ptr.To(azure.FrontendIPConfigID("123", "my-rg", "my-public-lb", "my-public-lb-frontEnd"))
+	internalNATRuleFrontend := ptr.To(azure.FrontendIPConfigID("123", "my-rg", "my-internal-lb", "my-internal-lb-frontEnd"))
+	tests := []struct {
+		name        string
+		phase       infrav1.APIServerLBMigrationPhase
+		wantNIC     networkinterfaces.NICSpec
+		wantNATRule inboundnatrules.InboundNatSpec
+	}{
+		{
+			name:  "control plane machines stay in the public load balancer until the migration starts",
+			phase: infrav1.APIServerLBMigrationPending,
+			wantNIC: networkinterfaces.NICSpec{
+				InternalLBName:            "my-internal-lb",
+				InternalLBAddressPoolName: "my-internal-lb-backendPool",
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-internal-lb", FrontendIPConfigurationID: internalNATRuleFrontend},
+		},
+		{
+			name:  "control plane machines stay in the public load balancer while the internal one is created",
+			phase: infrav1.APIServerLBMigrationCreatingInternalLB,
+			wantNIC: networkinterfaces.NICSpec{
+				PublicLBName:            "my-public-lb",
+				PublicLBAddressPoolName: "my-public-lb-backendPool",
+				PublicLBNATRuleName:     "machine-name",
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-public-lb", FrontendIPConfigurationID: publicNATRuleFrontend},
+		},
+		{
+			name:  "control plane machines are added to the internal load balancer before the endpoint is switched",
+			phase: infrav1.APIServerLBMigrationSwitchingEndpoint,
+			wantNIC: networkinterfaces.NICSpec{
+				PublicLBName:              "my-public-lb",
+				PublicLBAddressPoolName:   "my-public-lb-backendPool",
+				PublicLBNATRuleName:       "machine-name",
+				InternalLBName:            "my-internal-lb",
+				InternalLBAddressPoolName: "my-internal-lb-backendPool",
+				UpdateLBReferences:        true,
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-public-lb", FrontendIPConfigurationID: publicNATRuleFrontend},
+		},
+		{
+			name:  "control plane machines stay in the public load balancer until the machines created before the switch are replaced",
+			phase: infrav1.APIServerLBMigrationReplacingMachines,
+			wantNIC: networkinterfaces.NICSpec{
+				PublicLBName:              "my-public-lb",
+				PublicLBAddressPoolName:   "my-public-lb-backendPool",
+				PublicLBNATRuleName:       "machine-name",
+				InternalLBName:            "my-internal-lb",
+				InternalLBAddressPoolName: "my-internal-lb-backendPool",
+				UpdateLBReferences:        true,
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-public-lb", FrontendIPConfigurationID: publicNATRuleFrontend},
+		},
+		{
+			name:  "control plane machines are removed from the public load balancer before it is deleted",
+			phase: infrav1.APIServerLBMigrationDeletingPublicLB,
+			wantNIC: networkinterfaces.NICSpec{
+				InternalLBName:            "my-internal-lb",
+				InternalLBAddressPoolName: "my-internal-lb-backendPool",
+				UpdateLBReferences:        true,
+				DetachedLBName:            "my-public-lb",
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-internal-lb", FrontendIPConfigurationID: internalNATRuleFrontend},
+		},
+		{
+			name:  "control plane machines are only in the internal load balancer once the migration is completed",
+			phase: infrav1.APIServerLBMigrationCompleted,
+			wantNIC: networkinterfaces.NICSpec{
+				InternalLBName:            "my-internal-lb",
+				InternalLBAddressPoolName: "my-internal-lb-backendPool",
+			},
+			wantNATRule: inboundnatrules.InboundNatSpec{LoadBalancerName: "my-internal-lb", FrontendIPConfigurationID: internalNATRuleFrontend},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			machineScope := MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{clusterv1.MachineControlPlaneLabel: ""},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine-name"},
+					Spec: infrav1.AzureMachineSpec{
+						NetworkInterfaces: []infrav1.NetworkInterface{{SubnetName: "cp-subnet", PrivateIPConfigs: 1}},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Values: map[string]string{auth.SubscriptionID: "123"},
+						},
+					},
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								SubscriptionID: "123",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name:                  "my-internal-lb",
+									BackendPool:           infrav1.BackendPool{Name: "my-internal-lb-backendPool"},
+									FrontendIPs:           []infrav1.FrontendIP{{Name: "my-internal-lb-frontEnd"}},
+									LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{Type: infrav1.Internal},
+								},
+							},
+						},
+						Status: infrav1.AzureClusterStatus{
+							APIServerLBMigration: &infrav1.APIServerLBMigration{
+								Phase:                   tt.phase,
+								PublicLBName:            "my-public-lb",
+								PublicLBBackendPoolName: "my-public-lb-backendPool",
+								PublicLBFrontendIPName:  "my-public-lb-frontEnd",
+							},
+						},
+					},
+				},
+			}
+
+			nicSpecs := machineScope.NICSpecs()
+			g.Expect(nicSpecs).To(HaveLen(1))
+			nicSpec := nicSpecs[0].(*networkinterfaces.NICSpec)
+			g.Expect(networkinterfaces.NICSpec{
+				PublicLBName:              nicSpec.PublicLBName,
+				PublicLBAddressPoolName:   nicSpec.PublicLBAddressPoolName,
+				PublicLBNATRuleName:       nicSpec.PublicLBNATRuleName,
+				InternalLBName:            nicSpec.InternalLBName,
+				InternalLBAddressPoolName: nicSpec.InternalLBAddressPoolName,
+				UpdateLBReferences:        nicSpec.UpdateLBReferences,
+				DetachedLBName:            nicSpec.DetachedLBName,
+			}).To(Equal(tt.wantNIC))
+
+			natSpecs := machineScope.InboundNatSpecs()
+			g.Expect(natSpecs).To(HaveLen(1))
+			natSpec := natSpecs[0].(*inboundnatrules.InboundNatSpec)
+			g.Expect(natSpec.LoadBalancerName).To(Equal(tt.wantNATRule.LoadBalancerName))
+			g.Expect(natSpec.FrontendIPConfigurationID).To(Equal(tt.wantNATRule.FrontendIPConfigurationID))
+		})
+	}
+}
+
+func TestMachineScope_ManagedResources(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := MachineScope{
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+	nicID := azure.NetworkInterfaceID("123", "my-rg", "my-azure-machine-nic")
+
+	// Recording is a no-op until it is initialized.
+	machineScope.RecordManagedResource(nicID)
+	g.Expect(machineScope.IsOwnershipRecorded()).To(BeFalse())
+	g.Expect(machineScope.IsManagedResource(nicID, false)).To(BeFalse())
+	g.Expect(machineScope.IsManagedResource(nicID, true)).To(BeTrue())
+
+	machineScope.InitManagedResources()
+	g.Expect(machineScope.IsOwnershipRecorded()).To(BeTrue())
+	machineScope.RecordManagedResource(nicID)
+	machineScope.RecordManagedResource(strings.ToUpper(nicID))
+	g.Expect(machineScope.AzureMachine.Status.ManagedResources.IDs).To(Equal([]string{nicID}))
+	g.Expect(machineScope.IsManagedResource(strings.ToUpper(nicID), false)).To(BeTrue())
+	g.Expect(machineScope.IsManagedResource(azure.PublicIPID("123", "my-rg", "my-azure-machine-public-ip"), false)).To(BeFalse())
+
+	// Machines whose VM already exists keep relying on tags.
+	existingMachineScope := MachineScope{
+		AzureMachine: &infrav1.AzureMachine{
+			Spec: infrav1.AzureMachineSpec{
+				ProviderID: ptr.To("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-azure-machine"),
+			},
+		},
+	}
+	existingMachineScope.InitManagedResources()
+	g.Expect(existingMachineScope.IsOwnershipRecorded()).To(BeFalse())
+}
+
+func TestMachineScope_CheckProvisioningTimeout(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeout := &metav1.Duration{Duration: 20 * time.Minute}
+
+	tests := []struct {
+		name              string
+		timeout           *metav1.Duration
+		vmCreationTime    *metav1.Time
+		nodeRef           *corev1.ObjectReference
+		bootstrapped      bool
+		now               time.Time
+		expectedTimedOut  bool
+		expectedRemaining time.Duration
+		expectedCondition *clusterv1.Condition
+	}{
+		{
+			name:           "no provisioning timeout",
+			vmCreationTime: &metav1.Time{Time: created},
+			now:            created.Add(time.Hour),
+		},
+		{
+			name:    "VM not created yet",
+			timeout: timeout,
+			now:     created.Add(time.Hour),
+		},
+		{
+			name:              "VM bootstrapping within the timeout",
+			timeout:           timeout,
+			vmCreationTime:    &metav1.Time{Time: created},
+			now:               created.Add(5 * time.Minute),
+			expectedRemaining: 15 * time.Minute,
+		},
+		{
+			name:             "VM bootstrapping past the timeout",
+			timeout:          timeout,
+			vmCreationTime:   &metav1.Time{Time: created},
+			now:              created.Add(20 * time.Minute),
+			expectedTimedOut: true,
+			expectedCondition: &clusterv1.Condition{
+				Type:     infrav1.BootstrapWithinTimeoutCondition,
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityError,
+				Reason:   infrav1.BootstrapTimedOutReason,
+			},
+		},
+		{
+			name:           "node registered",
+			timeout:        timeout,
+			vmCreationTime: &metav1.Time{Time: created},
+			nodeRef:        &corev1.ObjectReference{Name: "my-node"},
+			now:            created.Add(time.Hour),
+			expectedCondition: &clusterv1.Condition{
+				Type:   infrav1.BootstrapWithinTimeoutCondition,
+				Status: corev1.ConditionTrue,
+			},
+		},
+		{
+			name:           "bootstrap data succeeded",
+			timeout:        timeout,
+			vmCreationTime: &metav1.Time{Time: created},
+			bootstrapped:   true,
+			now:            created.Add(time.Hour),
+			expectedCondition: &clusterv1.Condition{
+				Type:   infrav1.BootstrapWithinTimeoutCondition,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				Machine: &clusterv1.Machine{
+					Status: clusterv1.MachineStatus{NodeRef: tt.nodeRef},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						ProvisioningTimeout: tt.timeout,
+					},
+					Status: infrav1.AzureMachineStatus{
+						VMCreationTime: tt.vmCreationTime,
+					},
+				},
+			}
+			if tt.bootstrapped {
+				conditions.MarkTrue(machineScope.AzureMachine, infrav1.BootstrapSucceededCondition)
+			}
+
+			timedOut, remaining := machineScope.CheckProvisioningTimeout(tt.now)
+			g.Expect(timedOut).To(Equal(tt.expectedTimedOut))
+			g.Expect(remaining).To(Equal(tt.expectedRemaining))
+
+			cond := conditions.Get(machineScope.AzureMachine, infrav1.BootstrapWithinTimeoutCondition)
+			if tt.expectedCondition == nil {
+				g.Expect(cond).To(BeNil())
+				return
+			}
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tt.expectedCondition.Status))
+			g.Expect(cond.Severity).To(Equal(tt.expectedCondition.Severity))
+			g.Expect(cond.Reason).To(Equal(tt.expectedCondition.Reason))
+		})
+	}
+}
+
+func TestMachineScope_OrphanedManagedResourceSpecs(t *testing.T) {
+	// The resources recorded by a failed attempt to create the VM. The OS disk is still part of the spec, the other
+	// resources aren't as the spec of the machine changed since, e.g. it no longer allocates a public IP.
+	recordedIDs := []string{
+		azure.PublicIPID("123", "my-rg", "pip-my-azure-machine"),
+		azure.NetworkInterfaceID("123", "my-rg", "my-azure-machine-nic"),
+		azure.NATRuleID("123", "my-rg", "my-lb", "my-azure-machine"),
+		azure.DiskID("123", "my-rg", "my-azure-machine_OSDisk"),
+		azure.DiskID("123", "my-rg", "my-azure-machine_etcddisk"),
+	}
+	newMachineScope := func(deleting bool) *MachineScope {
+		azureMachine := &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					DiskSizeGB: ptr.To[int32](30),
+					OSType:     "Linux",
+				},
+			},
+			Status: infrav1.AzureMachineStatus{
+				ManagedResources: &infrav1.ManagedResources{IDs: recordedIDs},
+			},
+		}
+		if deleting {
+			azureMachine.DeletionTimestamp = ptr.To(metav1.Now())
+		}
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: auth.EnvironmentSettings{
+						Values: map[string]string{
+							auth.SubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+					},
+				},
+			},
+			AzureMachine: azureMachine,
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "machine",
+				},
+			},
+		}
+	}
+
+	t.Run("recorded resources are not reconciled while the machine exists", func(t *testing.T) {
+		g := NewWithT(t)
+		machineScope := newMachineScope(false)
+		g.Expect(machineScope.PublicIPSpecs()).To(BeEmpty())
+		g.Expect(machineScope.NICSpecs()).To(BeEmpty())
+		g.Expect(machineScope.InboundNatSpecs()).To(BeEmpty())
+		g.Expect(machineScope.DiskSpecs()).To(HaveLen(1))
+	})
+
+	t.Run("recorded resources are deleted along with the machine", func(t *testing.T) {
+		g := NewWithT(t)
+		machineScope := newMachineScope(true)
+		g.Expect(machineScope.PublicIPSpecs()).To(Equal([]azure.ResourceSpecGetter{
+			&publicips.PublicIPSpec{
+				Name:          "pip-my-azure-machine",
+				ResourceGroup: "my-rg",
+				ClusterName:   "my-cluster",
+			},
+		}))
+		g.Expect(machineScope.NICSpecs()).To(Equal([]azure.ResourceSpecGetter{
+			&networkinterfaces.NICSpec{
+				Name:           "my-azure-machine-nic",
+				ResourceGroup:  "my-rg",
+				SubscriptionID: "123",
+				MachineName:    "my-azure-machine",
+				ClusterName:    "my-cluster",
+			},
+		}))
+		g.Expect(machineScope.InboundNatSpecs()).To(Equal([]azure.ResourceSpecGetter{
+			&inboundnatrules.InboundNatSpec{
+				Name:             "my-azure-machine",
+				ResourceGroup:    "my-rg",
+				LoadBalancerName: "my-lb",
+			},
+		}))
+		g.Expect(machineScope.DiskSpecs()).To(Equal([]azure.ResourceSpecGetter{
+			&disks.DiskSpec{
+				Name:          "my-azure-machine_OSDisk",
+				ResourceGroup: "my-rg",
+			},
+			&disks.DiskSpec{
+				Name:          "my-azure-machine_etcddisk",
+				ResourceGroup: "my-rg",
+			},
+		}))
+	})
+}
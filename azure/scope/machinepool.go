@@ -19,15 +19,18 @@ package scope
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -39,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	conditionsutils "sigs.k8s.io/cluster-api-provider-azure/util/conditions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -66,6 +70,7 @@ type (
 		AzureMachinePool *infrav1exp.AzureMachinePool
 		ClusterScope     azure.ClusterScoper
 		Cache            *MachinePoolCache
+		Recorder         record.EventRecorder
 	}
 
 	// MachinePoolScope defines a scope defined around a machine pool and its cluster.
@@ -78,6 +83,8 @@ type (
 		capiMachinePoolPatchHelper *patch.Helper
 		vmssState                  *azure.VMSS
 		cache                      *MachinePoolCache
+		imageChecker               imageReplicationChecker
+		recorder                   record.EventRecorder
 	}
 
 	// NodeStatus represents the status of a Kubernetes node.
@@ -128,6 +135,7 @@ func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, erro
 		patchHelper:                helper,
 		capiMachinePoolPatchHelper: capiMachinePoolPatchHelper,
 		ClusterScoper:              params.ClusterScope,
+		recorder:                   params.Recorder,
 	}, nil
 }
 
@@ -154,6 +162,9 @@ func (m *MachinePoolScope) InitMachinePoolCache(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if err := m.checkImageReplicated(ctx); err != nil {
+			return err
+		}
 		m.SaveVMImageToStatus(m.cache.VMImage)
 
 		m.cache.MaxSurge, err = m.MaxSurge()
@@ -187,6 +198,7 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		Capacity:                     int64(ptr.Deref[int32](m.MachinePool.Spec.Replicas, 0)),
 		SSHKeyData:                   m.AzureMachinePool.Spec.Template.SSHPublicKey,
 		OSDisk:                       m.AzureMachinePool.Spec.Template.OSDisk,
+		PatchSettings:                m.AzureMachinePool.Spec.Template.PatchSettings,
 		DataDisks:                    m.AzureMachinePool.Spec.Template.DataDisks,
 		SubnetName:                   m.AzureMachinePool.Spec.Template.NetworkInterfaces[0].SubnetName,
 		VNetName:                     m.Vnet().Name,
@@ -199,7 +211,7 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		DiagnosticsProfile:           m.AzureMachinePool.Spec.Template.Diagnostics,
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
-		FailureDomains:               m.MachinePool.Spec.FailureDomains,
+		FailureDomains:               m.ScaleSetFailureDomains(),
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
 		NetworkInterfaces:            m.AzureMachinePool.Spec.Template.NetworkInterfaces,
 		IPv6Enabled:                  m.IsIPv6Enabled(),
@@ -209,6 +221,10 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 		HasReplicasExternallyManaged: m.HasReplicasExternallyManaged(ctx),
 		ClusterName:                  m.ClusterName(),
 		AdditionalTags:               m.AzureMachinePool.Spec.AdditionalTags,
+		AutomaticRepairsPolicy:       m.AzureMachinePool.Spec.AutomaticRepairsPolicy,
+		AutomaticOSUpgradePolicy:     m.AzureMachinePool.Spec.AutomaticOSUpgradePolicy,
+		ZoneSpreadPolicy:             m.AzureMachinePool.Spec.ZoneSpreadPolicy,
+		ExpandInstanceView:           needsInstanceView(m.AzureMachinePool),
 	}
 
 	if m.cache != nil {
@@ -217,10 +233,14 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 			log.V(4).Info("has bootstrap data changed?", "shouldPatchCustomData", spec.ShouldPatchCustomData)
 		}
 		spec.VMSSExtensionSpecs = m.VMSSExtensionSpecs()
+		spec.ClusterVMExtensionNames, spec.RemovedVMExtensionNames = m.clusterVMExtensionChanges(ctx, spec.VMSSExtensionSpecs)
 		spec.SKU = m.cache.VMSKU
 		spec.VMImage = m.cache.VMImage
 		spec.BootstrapData = m.cache.BootstrapData
 		spec.MaxSurge = m.cache.MaxSurge
+		if m.AzureMachinePool.Spec.Strategy.Type == infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType {
+			spec.RollingUpdate = m.AzureMachinePool.Spec.Strategy.RollingUpdate
+		}
 	} else {
 		log.V(4).Info("machinepool cache is nil, this is only expected when deleting a machinepool")
 	}
@@ -228,6 +248,35 @@ func (m *MachinePoolScope) ScaleSetSpec(ctx context.Context) azure.ResourceSpecG
 	return spec
 }
 
+// needsInstanceView returns true if the instances of the machine pool need to be fetched with their instance views,
+// either for the instance metadata labels of their nodes, or to find the Spot instances deallocated by an eviction.
+// Expanding the instance views makes every list and get of the instances more expensive, so it's only done when needed.
+func needsInstanceView(amp *infrav1exp.AzureMachinePool) bool {
+	return amp.Spec.InstanceMetadataLabels != nil || deallocatesSpotInstances(amp)
+}
+
+// deallocatesSpotInstances returns true if the machine pool uses Spot instances which are deallocated on eviction.
+func deallocatesSpotInstances(amp *infrav1exp.AzureMachinePool) bool {
+	spot := amp.Spec.Template.SpotVMOptions
+	return spot != nil && ptr.Deref(spot.EvictionPolicy, infrav1.SpotEvictionPolicyDeallocate) == infrav1.SpotEvictionPolicyDeallocate
+}
+
+// ScaleSetFailureDomains returns the availability zones the scale set is placed in. When the MachinePool does not list
+// any failure domains and the AzureMachinePool opts in with spreadAcrossAllFailureDomains, all of the cluster's failure
+// domains are returned. The failure domains of the MachinePool are returned as is otherwise, even in regions without
+// availability zones, so that the scale set service rejects the ones the region doesn't offer.
+func (m *MachinePoolScope) ScaleSetFailureDomains() []string {
+	clusterFailureDomains := m.FailureDomains()
+	if len(m.MachinePool.Spec.FailureDomains) == 0 && len(clusterFailureDomains) > 0 && ptr.Deref(m.AzureMachinePool.Spec.SpreadAcrossAllFailureDomains, false) {
+		failureDomains := make([]string, 0, len(clusterFailureDomains))
+		for _, fd := range clusterFailureDomains {
+			failureDomains = append(failureDomains, ptr.Deref(fd, ""))
+		}
+		return failureDomains
+	}
+	return m.MachinePool.Spec.FailureDomains
+}
+
 // Name returns the Azure Machine Pool Name.
 func (m *MachinePoolScope) Name() string {
 	// Windows Machine pools names cannot be longer than 9 chars
@@ -295,9 +344,29 @@ func (m *MachinePoolScope) ProvisioningState() infrav1.ProvisioningState {
 	return ""
 }
 
-// SetVMSSState updates the machine pool scope with the current state of the VMSS.
+// SetVMSSState updates the machine pool scope with the current state of the VMSS, and records the extensions of the
+// cluster in its model.
 func (m *MachinePoolScope) SetVMSSState(vmssState *azure.VMSS) {
 	m.vmssState = vmssState
+	if vmssState == nil {
+		return
+	}
+
+	lastApplied, _ := m.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	for _, extension := range m.clusterVMExtensions() {
+		lastApplied[extension.Name] = true
+	}
+	applied := map[string]interface{}{}
+	for _, name := range vmssState.Extensions {
+		if _, ok := lastApplied[name]; ok {
+			applied[name] = true
+		}
+	}
+	if _, ok := m.AzureMachinePool.GetAnnotations()[azure.ClusterVMExtensionsLastAppliedAnnotation]; !ok && len(applied) == 0 {
+		return
+	}
+	// UpdateAnnotationJSON only fails to marshal values which aren't JSON serializable.
+	_ = m.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, applied)
 }
 
 // NeedsRequeue return true if any machines are not on the latest model or the VMSS is not in a terminal provisioning
@@ -403,6 +472,9 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 
 	// determine which machines need to be created to reflect the current state in Azure
 	azureMachinesByProviderID := m.vmssState.InstancesByProviderID(m.AzureMachinePool.Spec.OrchestrationMode)
+	if err := m.adoptReplacedInstances(ctx, existingMachinesByProviderID, azureMachinesByProviderID); err != nil {
+		return err
+	}
 	for key, val := range azureMachinesByProviderID {
 		if _, ok := existingMachinesByProviderID[key]; !ok {
 			log.V(4).Info("creating AzureMachinePoolMachine", "providerID", key)
@@ -472,6 +544,91 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 	return nil
 }
 
+// adoptReplacedInstances moves the AzureMachinePoolMachines of instances that Azure replaced, e.g. through the
+// automatic repairs of the scale set, to the replacing instance. The replacing instance gets a new providerID but keeps
+// the computer name or the instance ID of the replaced one. Updating the providerID in place keeps the Machine and its
+// node instead of deleting the Machine of the replaced instance and creating a new one for the replacing instance.
+func (m *MachinePoolScope) adoptReplacedInstances(ctx context.Context, existingMachinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine, azureMachinesByProviderID map[string]azure.VMSSVM) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.adoptReplacedInstances")
+	defer done()
+
+	var newInstances []azure.VMSSVM
+	for providerID, instance := range azureMachinesByProviderID {
+		if _, ok := existingMachinesByProviderID[providerID]; !ok {
+			newInstances = append(newInstances, instance)
+		}
+	}
+	if len(newInstances) == 0 {
+		return nil
+	}
+
+	for oldProviderID, ampm := range existingMachinesByProviderID {
+		ampm := ampm
+		if _, ok := azureMachinesByProviderID[oldProviderID]; ok {
+			continue
+		}
+		for i, instance := range newInstances {
+			if !isReplacementInstance(ampm, instance) {
+				continue
+			}
+			newProviderID := instance.ProviderID()
+			log.Info("updating the providerID of an AzureMachinePoolMachine whose instance was replaced by Azure", "ampm", klog.KObj(&ampm), "oldProviderID", oldProviderID, "providerID", newProviderID)
+			if err := m.updateMachineProviderID(ctx, &ampm, instance); err != nil {
+				return errors.Wrap(err, "failed updating the providerID of AzureMachinePoolMachine")
+			}
+			if m.recorder != nil {
+				m.recorder.Eventf(&ampm, corev1.EventTypeNormal, "InstanceRepaired", "Azure replaced instance %s with %s", oldProviderID, newProviderID)
+			}
+			delete(existingMachinesByProviderID, oldProviderID)
+			existingMachinesByProviderID[newProviderID] = ampm
+			newInstances = append(newInstances[:i], newInstances[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// isReplacementInstance returns true if the instance replaced the one of the AzureMachinePoolMachine, i.e. it has the
+// same computer name or the same instance ID.
+func isReplacementInstance(ampm infrav1exp.AzureMachinePoolMachine, instance azure.VMSSVM) bool {
+	if ampm.Status.InstanceName != "" && strings.EqualFold(ampm.Status.InstanceName, instance.Name) {
+		return true
+	}
+	return ampm.Spec.InstanceID != "" && ampm.Spec.InstanceID == instance.InstanceID
+}
+
+// updateMachineProviderID sets the providerID of the AzureMachinePoolMachine and of its owner Machine to the one of
+// the instance.
+func (m *MachinePoolScope) updateMachineProviderID(ctx context.Context, ampm *infrav1exp.AzureMachinePoolMachine, instance azure.VMSSVM) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.updateMachineProviderID")
+	defer done()
+
+	providerID := instance.ProviderID()
+	ampmBefore := ampm.DeepCopy()
+	ampm.Spec.ProviderID = providerID
+	ampm.Spec.InstanceID = instance.InstanceID
+	if err := m.client.Patch(ctx, ampm, client.MergeFrom(ampmBefore)); err != nil {
+		return errors.Wrapf(err, "failed to patch AzureMachinePoolMachine %s/%s", ampm.Namespace, ampm.Name)
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, m.client, ampm.ObjectMeta)
+	if err != nil {
+		return errors.Wrapf(err, "error getting owner Machine for AzureMachinePoolMachine %s/%s", ampm.Namespace, ampm.Name)
+	}
+	if machine == nil {
+		// The MachinePool controller creates the Machine with the providerID of the AzureMachinePoolMachine.
+		return nil
+	}
+	machineBefore := machine.DeepCopy()
+	machine.Spec.ProviderID = ptr.To(providerID)
+	if err := m.client.Patch(ctx, machine, client.MergeFrom(machineBefore)); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s/%s", machine.Namespace, machine.Name)
+	}
+
+	return nil
+}
+
 func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSSVM) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.createMachine")
 	defer done()
@@ -594,6 +751,57 @@ func (m *MachinePoolScope) setProvisioningStateAndConditions(v infrav1.Provision
 	}
 }
 
+// setZoneSpread records the number of instances of each zone of the scale set in the AzureMachinePool status, and sets
+// the ZonesUnbalanced condition when the zone spread policy of the AzureMachinePool tolerates less skew between the
+// zones.
+func (m *MachinePoolScope) setZoneSpread() {
+	if len(m.vmssState.Zones) == 0 {
+		m.AzureMachinePool.Status.InstancesPerZone = nil
+		conditions.Delete(m.AzureMachinePool, infrav1.ZonesUnbalancedCondition)
+		return
+	}
+
+	instancesPerZone := make(map[string]int32, len(m.vmssState.Zones))
+	for _, zone := range m.vmssState.Zones {
+		instancesPerZone[zone] = 0
+	}
+	for _, instance := range m.vmssState.Instances {
+		if _, ok := instancesPerZone[instance.AvailabilityZone]; ok {
+			instancesPerZone[instance.AvailabilityZone]++
+		}
+	}
+	m.AzureMachinePool.Status.InstancesPerZone = instancesPerZone
+
+	policy := m.AzureMachinePool.Spec.ZoneSpreadPolicy
+	if policy == nil || len(instancesPerZone) < 2 {
+		conditions.Delete(m.AzureMachinePool, infrav1.ZonesUnbalancedCondition)
+		return
+	}
+
+	var minZone, maxZone string
+	for zone, count := range instancesPerZone {
+		if minZone == "" || count < instancesPerZone[minZone] || count == instancesPerZone[minZone] && zone < minZone {
+			minZone = zone
+		}
+		if maxZone == "" || count > instancesPerZone[maxZone] || count == instancesPerZone[maxZone] && zone < maxZone {
+			maxZone = zone
+		}
+	}
+	skew := instancesPerZone[maxZone] - instancesPerZone[minZone]
+	maxSkew := ptr.Deref(policy.MaxSkew, 1)
+	if skew <= maxSkew {
+		conditions.Delete(m.AzureMachinePool, infrav1.ZonesUnbalancedCondition)
+		return
+	}
+	conditions.Set(m.AzureMachinePool, &clusterv1.Condition{
+		Type:   infrav1.ZonesUnbalancedCondition,
+		Status: corev1.ConditionTrue,
+		Reason: infrav1.ZoneSkewExceededReason,
+		Message: fmt.Sprintf("zone %s has %d instances and zone %s has %d, exceeding the maximum skew of %d",
+			maxZone, instancesPerZone[maxZone], minZone, instancesPerZone[minZone], maxSkew),
+	})
+}
+
 // SetReady sets the AzureMachinePool Ready Status to true.
 func (m *MachinePoolScope) SetReady() {
 	m.AzureMachinePool.Status.Ready = true
@@ -636,12 +844,51 @@ func (m *MachinePoolScope) SetAnnotation(key, value string) {
 	m.AzureMachinePool.Annotations[key] = value
 }
 
+// TagsSpecs returns the tags for the AzureMachinePool's scale set.
+func (m *MachinePoolScope) TagsSpecs() []azure.TagsSpec {
+	return []azure.TagsSpec{
+		{
+			Scope:      azure.VMSSID(m.SubscriptionID(), m.NodeResourceGroup(), m.Name()),
+			Tags:       m.AzureMachinePool.Spec.AdditionalTags,
+			Annotation: azure.VMSSTagsLastAppliedAnnotation,
+		},
+	}
+}
+
+// AnnotationJSON returns a map[string]interface from a JSON annotation.
+func (m *MachinePoolScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	jsonAnnotation := m.AzureMachinePool.GetAnnotations()[annotation]
+	if jsonAnnotation == "" {
+		return out, nil
+	}
+	err := json.Unmarshal([]byte(jsonAnnotation), &out)
+	if err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateAnnotationJSON updates the `annotation` with
+// `content`. `content` in this case should be a `map[string]interface{}`
+// suitable for turning into JSON. This `content` map will be marshalled into a
+// JSON string before being set as the given `annotation`.
+func (m *MachinePoolScope) UpdateAnnotationJSON(annotation string, content map[string]interface{}) error {
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	m.SetAnnotation(annotation, string(b))
+	return nil
+}
+
 // PatchObject persists the AzureMachinePool spec and status.
 func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.PatchObject")
 	defer done()
 
 	conditions.SetSummary(m.AzureMachinePool)
+	conditionsutils.SetV1Beta2Conditions(m.AzureMachinePool)
 	return m.patchHelper.Patch(
 		ctx,
 		m.AzureMachinePool,
@@ -651,6 +898,7 @@ func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 			infrav1.ScaleSetDesiredReplicasCondition,
 			infrav1.ScaleSetModelUpdatedCondition,
 			infrav1.ScaleSetRunningCondition,
+			infrav1.ZonesUnbalancedCondition,
 		}})
 }
 
@@ -666,6 +914,7 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 		}
 
 		m.setProvisioningStateAndConditions(m.vmssState.State)
+		m.setZoneSpread()
 		if err := m.updateReplicasAndProviderIDs(ctx); err != nil {
 			return errors.Wrap(err, "failed to update replicas and providerIDs")
 		}
@@ -686,7 +935,8 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 	return nil
 }
 
-// GetBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName.
+// GetBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName, encoded
+// as the custom data of the scale set. Bootstrap data the VMs can't use is a terminal error.
 func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetBootstrapData")
 	defer done()
@@ -701,11 +951,16 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
 	}
 
-	value, ok := secret.Data["value"]
-	if !ok {
+	if _, ok := secret.Data["value"]; !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
-	return base64.StdEncoding.EncodeToString(value), nil
+
+	data, err := encodeBootstrapData(secret, m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.AzureMachinePool.Spec.Template.Image)
+	if err != nil {
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.ScaleSetRunningCondition, infrav1.InvalidBootstrapDataReason, clusterv1.ConditionSeverityError, err.Error())
+		return "", azure.WithTerminalError(errors.Wrapf(err, "invalid bootstrap data secret %s", key))
+	}
+	return data, nil
 }
 
 // calculateBootstrapDataHash calculates the sha256 hash of the bootstrap data.
@@ -763,6 +1018,9 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 		windowsServerVersion := m.AzureMachinePool.Annotations["windowsServerVersion"]
 		log.V(4).Info("No image specified for machine, using default Windows Image", "machine", m.MachinePool.GetName(), "runtime", runtime, "windowsServerVersion", windowsServerVersion)
 		defaultImage, err = svc.GetDefaultWindowsImage(ctx, m.Location(), ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""), runtime, windowsServerVersion)
+	} else if clusterDefaultImage := m.ClusterScoper.DefaultImage(); clusterDefaultImage != nil {
+		log.V(4).Info("No image specified for machine, using cluster default image", "machine", m.MachinePool.GetName(), "gallery", clusterDefaultImage.Gallery, "image", clusterDefaultImage.Name)
+		defaultImage, err = virtualmachineimages.GetDefaultComputeGalleryImage(clusterDefaultImage, ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""))
 	} else {
 		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), ptr.Deref(m.MachinePool.Spec.Template.Spec.Version, ""))
 	}
@@ -774,6 +1032,23 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 	return defaultImage, nil
 }
 
+// checkImageReplicated holds the rollout of a new image until its compute gallery image version has been replicated
+// to the location of the cluster. The image the scale set already uses isn't checked again.
+func (m *MachinePoolScope) checkImageReplicated(ctx context.Context) error {
+	if reflect.DeepEqual(m.cache.VMImage, m.AzureMachinePool.Status.Image) || skipImageReplicationCheck(m.AzureMachinePool.Annotations) {
+		return nil
+	}
+	checker := m.imageChecker
+	if checker == nil {
+		svc, err := virtualmachineimages.New(m)
+		if err != nil {
+			return errors.Wrap(err, "failed to create virtualmachineimages service")
+		}
+		checker = svc
+	}
+	return checkImageReplicated(ctx, checker, m.AzureMachinePool, m.cache.VMImage, m.Location())
+}
+
 // SaveVMImageToStatus persists the AzureMachinePool image to the status.
 func (m *MachinePoolScope) SaveVMImageToStatus(image *infrav1.Image) {
 	m.AzureMachinePool.Status.Image = image
@@ -808,11 +1083,12 @@ func (m *MachinePoolScope) HasSystemAssignedIdentity() bool {
 	return m.AzureMachinePool.Spec.Identity == infrav1.VMIdentitySystemAssigned
 }
 
-// VMSSExtensionSpecs returns the VMSS extension specs.
+// VMSSExtensionSpecs returns the VMSS extension specs, including the extensions of the cluster.
 func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ResourceSpecGetter {
 	var extensionSpecs = []azure.ResourceSpecGetter{}
 
-	for _, extension := range m.AzureMachinePool.Spec.Template.VMExtensions {
+	extensions := append(append([]infrav1.VMExtension{}, m.AzureMachinePool.Spec.Template.VMExtensions...), m.clusterVMExtensions()...)
+	for _, extension := range extensions {
 		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
 			ExtensionSpec: azure.ExtensionSpec{
 				Name:              extension.Name,
@@ -839,6 +1115,40 @@ func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ResourceSpecGetter {
 	return extensionSpecs
 }
 
+// clusterVMExtensions returns the extensions of the cluster which apply to the scale set and aren't overridden by an
+// extension of the machine pool.
+func (m *MachinePoolScope) clusterVMExtensions() []infrav1.VMExtension {
+	return clusterVMExtensions(m.ClusterScoper.ClusterVMExtensions(), m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.AzureMachinePool.Spec.Template.VMExtensions)
+}
+
+// clusterVMExtensionChanges returns the names of the extensions of the cluster to install on the scale set, and the
+// names of the previously installed extensions of the cluster which are no longer desired.
+func (m *MachinePoolScope) clusterVMExtensionChanges(ctx context.Context, specs []azure.ResourceSpecGetter) (desired []string, removed []string) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.clusterVMExtensionChanges")
+	defer done()
+
+	for _, extension := range m.clusterVMExtensions() {
+		desired = append(desired, extension.Name)
+	}
+
+	lastApplied, err := m.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	if err != nil {
+		log.Error(err, "failed to get the VM extensions of the cluster last applied to the scale set")
+		return desired, nil
+	}
+	current := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		current[spec.ResourceName()] = true
+	}
+	for name := range lastApplied {
+		if !current[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return desired, removed
+}
+
 func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelector {
 	if m.AzureMachinePool == nil {
 		return nil
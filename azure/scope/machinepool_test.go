@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -41,6 +42,7 @@ import (
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -397,18 +399,11 @@ func TestMachinePoolScope_SaveVMImageToStatus(t *testing.T) {
 }
 
 func TestMachinePoolScope_GetVMImage(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
-	clusterMock.EXPECT().Location().AnyTimes()
-	clusterMock.EXPECT().SubscriptionID().AnyTimes()
-	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
-	clusterMock.EXPECT().Token().Return(&azidentity.DefaultAzureCredential{}).AnyTimes()
 	cases := []struct {
-		Name   string
-		Setup  func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool)
-		Verify func(g *WithT, amp *infrav1exp.AzureMachinePool, vmImage *infrav1.Image, err error)
+		Name         string
+		DefaultImage *infrav1.DefaultImage
+		Setup        func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool)
+		Verify       func(g *WithT, amp *infrav1exp.AzureMachinePool, vmImage *infrav1.Image, err error)
 	}{
 		{
 			Name: "should set and default the image if no image is specified for the AzureMachinePool",
@@ -465,6 +460,46 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 				g.Expect(amp.Spec.Template.Image).To(Equal(image))
 			},
 		},
+		{
+			Name: "should use the cluster default image if no image is specified for the AzureMachinePool",
+			DefaultImage: &infrav1.DefaultImage{
+				Gallery: "my-gallery",
+				Name:    "capi-ubun2-2204",
+			},
+			Setup: func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Template.Spec.Version = ptr.To("v1.28.3")
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, vmImage *infrav1.Image, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(vmImage).To(Equal(&infrav1.Image{
+					ComputeGallery: &infrav1.AzureComputeGalleryImage{
+						Gallery: "my-gallery",
+						Name:    "capi-ubun2-2204",
+						Version: "1.28.3",
+					},
+				}))
+				g.Expect(amp.Spec.Template.Image).To(BeNil())
+			},
+		},
+		{
+			Name: "should prefer the AzureMachinePool image over the cluster default image",
+			DefaultImage: &infrav1.DefaultImage{
+				Gallery: "my-gallery",
+				Name:    "capi-ubun2-2204",
+			},
+			Setup: func(mp *expv1.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Template.Spec.Version = ptr.To("v1.28.3")
+				amp.Spec.Template.Image = &infrav1.Image{
+					ID: ptr.To("my-image-id"),
+				}
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, vmImage *infrav1.Image, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(vmImage).To(Equal(&infrav1.Image{
+					ID: ptr.To("my-image-id"),
+				}))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -494,6 +529,13 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
+			clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
+			clusterMock.EXPECT().Location().AnyTimes()
+			clusterMock.EXPECT().SubscriptionID().AnyTimes()
+			clusterMock.EXPECT().CloudEnvironment().AnyTimes()
+			clusterMock.EXPECT().Token().Return(&azidentity.DefaultAzureCredential{}).AnyTimes()
+			clusterMock.EXPECT().DefaultImage().Return(c.DefaultImage).AnyTimes()
+
 			if c.Setup != nil {
 				c.Setup(mp, amp)
 			}
@@ -860,6 +902,72 @@ func TestMachinePoolScope_RoleAssignmentSpecs(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_ClusterVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+	clusterExtensions := []infrav1.ClusterVMExtension{
+		{VMExtension: infrav1.VMExtension{Name: "AzureMonitorLinuxAgent"}, OSType: "Linux"},
+		{VMExtension: infrav1.VMExtension{Name: "DependencyAgent"}},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				VMExtensions: clusterExtensions,
+			},
+		},
+	}
+	machinePoolScope := MachinePoolScope{
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{
+					OSDisk: infrav1.OSDisk{
+						OSType: "Linux",
+					},
+				},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureCluster: azureCluster,
+		},
+	}
+	specs := []azure.ResourceSpecGetter{
+		&scalesets.VMSSExtensionSpec{ExtensionSpec: azure.ExtensionSpec{Name: "AzureMonitorLinuxAgent"}},
+		&scalesets.VMSSExtensionSpec{ExtensionSpec: azure.ExtensionSpec{Name: "DependencyAgent"}},
+	}
+
+	// Scale sets without extensions of the cluster aren't annotated.
+	machinePoolScope.SetVMSSState(&azure.VMSS{Extensions: []string{"CAPZ.Linux.Bootstrapping"}})
+	g.Expect(machinePoolScope.AzureMachinePool.Annotations).NotTo(HaveKey(azure.ClusterVMExtensionsLastAppliedAnnotation))
+
+	// The extensions of the cluster are rolled out to the existing scale set.
+	desired, removed := machinePoolScope.clusterVMExtensionChanges(context.TODO(), specs)
+	g.Expect(desired).To(Equal([]string{"AzureMonitorLinuxAgent", "DependencyAgent"}))
+	g.Expect(removed).To(BeEmpty())
+
+	machinePoolScope.SetVMSSState(&azure.VMSS{Extensions: []string{"CAPZ.Linux.Bootstrapping", "AzureMonitorLinuxAgent", "DependencyAgent"}})
+	applied, err := machinePoolScope.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(applied).To(Equal(map[string]interface{}{"AzureMonitorLinuxAgent": true, "DependencyAgent": true}))
+
+	// An extension removed from the cluster is removed from the scale set, and tracked until it is.
+	azureCluster.Spec.VMExtensions = clusterExtensions[1:]
+	desired, removed = machinePoolScope.clusterVMExtensionChanges(context.TODO(), specs[1:])
+	g.Expect(desired).To(Equal([]string{"DependencyAgent"}))
+	g.Expect(removed).To(Equal([]string{"AzureMonitorLinuxAgent"}))
+
+	machinePoolScope.SetVMSSState(&azure.VMSS{Extensions: []string{"CAPZ.Linux.Bootstrapping", "AzureMonitorLinuxAgent", "DependencyAgent"}})
+	applied, err = machinePoolScope.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(applied).To(HaveKey("AzureMonitorLinuxAgent"))
+
+	machinePoolScope.SetVMSSState(&azure.VMSS{Extensions: []string{"CAPZ.Linux.Bootstrapping", "DependencyAgent"}})
+	applied, err = machinePoolScope.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(applied).To(Equal(map[string]interface{}{"DependencyAgent": true}))
+
+	_, removed = machinePoolScope.clusterVMExtensionChanges(context.TODO(), specs[1:])
+	g.Expect(removed).To(BeEmpty())
+}
+
 func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -1511,3 +1619,370 @@ func TestMachinePoolScope_applyAzureMachinePoolMachines(t *testing.T) {
 		})
 	}
 }
+
+func TestMachinePoolScope_applyAzureMachinePoolMachinesReplacedInstances(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	const replacedProviderID = "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/5"
+
+	tests := []struct {
+		name           string
+		setup          func(ampm *infrav1exp.AzureMachinePoolMachine, replacement *azure.VMSSVM)
+		wantProviderID string
+	}{
+		{
+			name: "replacement matched by computer name keeps the Machine",
+			setup: func(ampm *infrav1exp.AzureMachinePoolMachine, replacement *azure.VMSSVM) {
+				ampm.Status.InstanceName = "my-vmss000001"
+				replacement.Name = "my-vmss000001"
+			},
+			wantProviderID: replacedProviderID,
+		},
+		{
+			name: "replacement matched by instance ID keeps the Machine",
+			setup: func(ampm *infrav1exp.AzureMachinePoolMachine, replacement *azure.VMSSVM) {
+				ampm.Spec.InstanceID = "1"
+				replacement.InstanceID = "1"
+				replacement.Name = "my-vmss000005"
+			},
+			wantProviderID: replacedProviderID,
+		},
+		{
+			name: "unrelated new instance deletes the Machine of the vanished instance",
+			setup: func(ampm *infrav1exp.AzureMachinePoolMachine, replacement *azure.VMSSVM) {
+				ampm.Status.InstanceName = "my-vmss000001"
+				replacement.Name = "my-vmss000005"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			mp := &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp1", Namespace: "default"},
+				Spec:       expv1.MachinePoolSpec{Replicas: ptr.To[int32](2)},
+			}
+			amp := &infrav1exp.AzureMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default"}}
+			mpm1, ampm1 := getAzureMachinePoolMachineWithOwnerMachine(1)
+			mpm2, ampm2 := getAzureMachinePoolMachineWithOwnerMachine(2)
+			replacement := azure.VMSSVM{
+				ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/5",
+			}
+			tt.setup(&ampm1, &replacement)
+
+			recorder := record.NewFakeRecorder(10)
+			s := &MachinePoolScope{
+				client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(amp, &mpm1, &ampm1, &mpm2, &ampm2).Build(),
+				ClusterScoper:    &ClusterScope{Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "default"}}},
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+				vmssState: &azure.VMSS{
+					Instances: []azure.VMSSVM{
+						replacement,
+						{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/2"},
+					},
+				},
+				recorder: recorder,
+			}
+			g.Expect(s.applyAzureMachinePoolMachines(ctx)).To(Succeed())
+
+			machine := &clusterv1.Machine{}
+			err := s.client.Get(ctx, client.ObjectKeyFromObject(&mpm1), machine)
+			if tt.wantProviderID == "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(recorder.Events).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(machine.Spec.ProviderID).To(Equal(ptr.To(tt.wantProviderID)))
+
+			ampm := &infrav1exp.AzureMachinePoolMachine{}
+			g.Expect(s.client.Get(ctx, client.ObjectKeyFromObject(&ampm1), ampm)).To(Succeed())
+			g.Expect(ampm.Spec.ProviderID).To(Equal(tt.wantProviderID))
+			g.Expect(ampm.Spec.InstanceID).To(Equal(replacement.InstanceID))
+
+			// No AzureMachinePoolMachine is created for the replacing instance.
+			list := infrav1exp.AzureMachinePoolMachineList{}
+			g.Expect(s.client.List(ctx, &list)).To(Succeed())
+			g.Expect(list.Items).To(HaveLen(2))
+
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("InstanceRepaired")))
+		})
+	}
+}
+
+func TestMachinePoolScope_TagsSpecs(t *testing.T) {
+	g := NewWithT(t)
+	mps := MachinePoolScope{
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine-pool-name",
+			},
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				AdditionalTags: infrav1.Tags{"env": "prod"},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+	}
+
+	g.Expect(mps.TagsSpecs()).To(Equal([]azure.TagsSpec{
+		{
+			Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machine-pool-name",
+			Tags:       infrav1.Tags{"env": "prod"},
+			Annotation: azure.VMSSTagsLastAppliedAnnotation,
+		},
+	}))
+}
+
+func TestMachinePoolScope_ScaleSetFailureDomains(t *testing.T) {
+	// regionFailureDomains are the failure domains discovered for a zonal and a zoneless region.
+	regionFailureDomains := map[string]clusterv1.FailureDomains{
+		"eastus": {
+			"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+			"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+			"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+		},
+		"westcentralus": {},
+	}
+
+	tests := []struct {
+		name                   string
+		region                 string
+		machinePoolFDs         []string
+		spreadAcrossAllDomains *bool
+		want                   []string
+	}{
+		{
+			name:           "zonal region uses the MachinePool failure domains",
+			region:         "eastus",
+			machinePoolFDs: []string{"2"},
+			want:           []string{"2"},
+		},
+		{
+			name:   "zonal region without failure domains and without opt-in",
+			region: "eastus",
+			want:   nil,
+		},
+		{
+			name:                   "zonal region without failure domains spreads across all zones with opt-in",
+			region:                 "eastus",
+			spreadAcrossAllDomains: ptr.To(true),
+			want:                   []string{"1", "2", "3"},
+		},
+		{
+			name:                   "opt-in does not override the MachinePool failure domains",
+			region:                 "eastus",
+			machinePoolFDs:         []string{"1"},
+			spreadAcrossAllDomains: ptr.To(true),
+			want:                   []string{"1"},
+		},
+		{
+			name:           "zoneless region keeps the MachinePool failure domains",
+			region:         "westcentralus",
+			machinePoolFDs: []string{"1", "2", "3"},
+			want:           []string{"1", "2", "3"},
+		},
+		{
+			name:                   "zoneless region with opt-in",
+			region:                 "westcentralus",
+			spreadAcrossAllDomains: ptr.To(true),
+			want:                   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mps := MachinePoolScope{
+				MachinePool: &expv1.MachinePool{
+					Spec: expv1.MachinePoolSpec{
+						FailureDomains: tt.machinePoolFDs,
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						SpreadAcrossAllFailureDomains: tt.spreadAcrossAllDomains,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: regionFailureDomains[tt.region],
+						},
+					},
+				},
+			}
+			g.Expect(mps.ScaleSetFailureDomains()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolScope_setZoneSpread(t *testing.T) {
+	instancesIn := func(zones ...string) []azure.VMSSVM {
+		instances := make([]azure.VMSSVM, 0, len(zones))
+		for _, zone := range zones {
+			instances = append(instances, azure.VMSSVM{AvailabilityZone: zone})
+		}
+		return instances
+	}
+
+	tests := []struct {
+		name                 string
+		zones                []string
+		instances            []azure.VMSSVM
+		policy               *infrav1exp.ZoneSpreadPolicy
+		wantInstancesPerZone map[string]int32
+		wantUnbalanced       bool
+	}{
+		{
+			name:      "zoneless scale set",
+			instances: instancesIn("", ""),
+			policy:    &infrav1exp.ZoneSpreadPolicy{},
+		},
+		{
+			name:                 "zones without instances are counted",
+			zones:                []string{"1", "2", "3"},
+			instances:            instancesIn("1", "1", "2"),
+			wantInstancesPerZone: map[string]int32{"1": 2, "2": 1, "3": 0},
+		},
+		{
+			name:                 "unbalanced zones without a zone spread policy",
+			zones:                []string{"1", "2", "3"},
+			instances:            instancesIn("1", "1", "1"),
+			wantInstancesPerZone: map[string]int32{"1": 3, "2": 0, "3": 0},
+		},
+		{
+			name:                 "skew within the default maximum skew",
+			zones:                []string{"1", "2", "3"},
+			instances:            instancesIn("1", "1", "2", "3"),
+			policy:               &infrav1exp.ZoneSpreadPolicy{},
+			wantInstancesPerZone: map[string]int32{"1": 2, "2": 1, "3": 1},
+		},
+		{
+			name:                 "skew exceeding the default maximum skew",
+			zones:                []string{"1", "2", "3"},
+			instances:            instancesIn("1", "1", "2", "2"),
+			policy:               &infrav1exp.ZoneSpreadPolicy{},
+			wantInstancesPerZone: map[string]int32{"1": 2, "2": 2, "3": 0},
+			wantUnbalanced:       true,
+		},
+		{
+			name:                 "skew within a custom maximum skew",
+			zones:                []string{"1", "2", "3"},
+			instances:            instancesIn("1", "1", "2", "2"),
+			policy:               &infrav1exp.ZoneSpreadPolicy{MaxSkew: ptr.To[int32](2)},
+			wantInstancesPerZone: map[string]int32{"1": 2, "2": 2, "3": 0},
+		},
+		{
+			name:                 "single zone is never unbalanced",
+			zones:                []string{"1"},
+			instances:            instancesIn("1", "1", "1"),
+			policy:               &infrav1exp.ZoneSpreadPolicy{},
+			wantInstancesPerZone: map[string]int32{"1": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &infrav1exp.AzureMachinePool{
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					ZoneSpreadPolicy: tt.policy,
+				},
+			}
+			// A condition left over from a previous reconciliation is removed once the zones are balanced.
+			conditions.MarkTrue(amp, infrav1.ZonesUnbalancedCondition)
+			mps := MachinePoolScope{
+				AzureMachinePool: amp,
+				vmssState: &azure.VMSS{
+					Zones:     tt.zones,
+					Instances: tt.instances,
+				},
+			}
+
+			mps.setZoneSpread()
+			g.Expect(amp.Status.InstancesPerZone).To(Equal(tt.wantInstancesPerZone))
+			if tt.wantUnbalanced {
+				c := conditions.Get(amp, infrav1.ZonesUnbalancedCondition)
+				g.Expect(c).NotTo(BeNil())
+				g.Expect(c.Status).To(Equal(corev1.ConditionTrue))
+				g.Expect(c.Reason).To(Equal(infrav1.ZoneSkewExceededReason))
+				g.Expect(c.Message).To(Equal("zone 1 has 2 instances and zone 3 has 0, exceeding the maximum skew of 1"))
+			} else {
+				g.Expect(conditions.Has(amp, infrav1.ZonesUnbalancedCondition)).To(BeFalse())
+			}
+		})
+	}
+}
+
+func TestMachinePoolScope_AnnotationJSON(t *testing.T) {
+	g := NewWithT(t)
+	mps := MachinePoolScope{
+		AzureMachinePool: &infrav1exp.AzureMachinePool{},
+	}
+
+	// Pools created before the scale set tags were tracked have no annotation.
+	tags, err := mps.AnnotationJSON(azure.VMSSTagsLastAppliedAnnotation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tags).To(BeEmpty())
+
+	g.Expect(mps.UpdateAnnotationJSON(azure.VMSSTagsLastAppliedAnnotation, map[string]interface{}{"env": "prod"})).To(Succeed())
+	g.Expect(mps.AzureMachinePool.Annotations).To(HaveKeyWithValue(azure.VMSSTagsLastAppliedAnnotation, `{"env":"prod"}`))
+
+	tags, err = mps.AnnotationJSON(azure.VMSSTagsLastAppliedAnnotation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tags).To(Equal(map[string]interface{}{"env": "prod"}))
+}
+
+func TestNeedsInstanceView(t *testing.T) {
+	tests := []struct {
+		name string
+		spec infrav1exp.AzureMachinePoolSpec
+		want bool
+	}{
+		{
+			name: "regular instances without instance metadata labels",
+		},
+		{
+			name: "instance metadata labels",
+			spec: infrav1exp.AzureMachinePoolSpec{InstanceMetadataLabels: &infrav1exp.InstanceMetadataLabels{}},
+			want: true,
+		},
+		{
+			name: "spot instances deallocated on eviction",
+			spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{SpotVMOptions: &infrav1.SpotVMOptions{}},
+			},
+			want: true,
+		},
+		{
+			name: "spot instances deleted on eviction",
+			spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{
+					SpotVMOptions: &infrav1.SpotVMOptions{EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDelete)},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(needsInstanceView(&infrav1exp.AzureMachinePool{Spec: tt.spec})).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,12 +18,17 @@ package scope
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -35,7 +40,6 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
-	"sigs.k8s.io/cluster-api/controllers/remote"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -46,12 +50,24 @@ import (
 const (
 	// MachinePoolMachineScopeName is the sourceName, or more specifically the UserAgent, of client used in cordon and drain.
 	MachinePoolMachineScopeName = "azuremachinepoolmachine-scope"
+
+	// Names of the instance metadata labels, which are prefixed with the prefix configured on the AzureMachinePool.
+	instanceZoneLabel        = "zone"
+	instanceFaultDomainLabel = "fault-domain"
+	instanceIDLabel          = "instance-id"
+	instanceVMSizeLabel      = "vm-size"
+	instancePriorityLabel    = "priority"
+
+	// defaultEvictedInstanceRestartInterval is how often evicted Spot instances are restarted when the AzureMachinePool
+	// doesn't set an interval.
+	defaultEvictedInstanceRestartInterval = 5 * time.Minute
 )
 
 type (
 	nodeGetter interface {
 		GetNodeByProviderID(ctx context.Context, providerID string) (*corev1.Node, error)
 		GetNodeByObjectReference(ctx context.Context, nodeRef corev1.ObjectReference) (*corev1.Node, error)
+		PatchNode(ctx context.Context, original, node *corev1.Node) error
 	}
 
 	workloadClusterProxy struct {
@@ -163,6 +179,9 @@ func (s *MachinePoolMachineScope) ScaleSetVMSpec() azure.ResourceSpecGetter {
 		ScaleSetName:  s.ScaleSetName(),
 		ProviderID:    s.ProviderID(),
 		IsFlex:        s.OrchestrationMode() == infrav1.FlexibleOrchestrationMode,
+
+		ProtectFromScaleIn: s.protectFromScaleIn(),
+		ExpandInstanceView: needsInstanceView(s.AzureMachinePool),
 	}
 
 	if spec.IsFlex {
@@ -172,6 +191,28 @@ func (s *MachinePoolMachineScope) ScaleSetVMSpec() azure.ResourceSpecGetter {
 	return spec
 }
 
+// protectFromScaleIn returns true if the ProtectFromScaleInAnnotation is set to "true" on the AzureMachinePoolMachine
+// or its Machine, unless the machine is being deleted, as the protection would prevent the scale set from removing it.
+func (s *MachinePoolMachineScope) protectFromScaleIn() bool {
+	objects := []metav1.Object{s.AzureMachinePoolMachine}
+	if s.Machine != nil {
+		objects = append(objects, s.Machine)
+	}
+	protect := false
+	for _, obj := range objects {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			return false
+		}
+		if _, ok := obj.GetAnnotations()[clusterv1.DeleteMachineAnnotation]; ok {
+			return false
+		}
+		if obj.GetAnnotations()[infrav1exp.ProtectFromScaleInAnnotation] == "true" {
+			protect = true
+		}
+	}
+	return protect
+}
+
 // Name is the name of the Machine Pool Machine.
 func (s *MachinePoolMachineScope) Name() string {
 	return s.AzureMachinePoolMachine.Name
@@ -247,6 +288,10 @@ func (s *MachinePoolMachineScope) UpdatePatchStatus(condition clusterv1.Conditio
 // SetVMSSVM update the scope with the current state of the VMSS VM.
 func (s *MachinePoolMachineScope) SetVMSSVM(instance *azure.VMSSVM) {
 	s.instance = instance
+	if instance != nil && instance.PowerState == infrav1.VMPowerStateRunning {
+		// The instance restarted after an eviction, if any.
+		conditions.Delete(s.AzureMachinePoolMachine, infrav1.EvictionOccurredCondition)
+	}
 }
 
 // SetVMSSVMState update the scope with the current provisioning state of the VMSS VM.
@@ -256,6 +301,37 @@ func (s *MachinePoolMachineScope) SetVMSSVMState(state infrav1.ProvisioningState
 	}
 }
 
+// EvictedInstanceRestartInterval returns how often to restart the instance when it was deallocated by a Spot eviction,
+// or 0 if it shouldn't be restarted as the machine pool doesn't use Spot instances deallocated on eviction, or the
+// KeepDeallocatedAnnotation is set on the AzureMachinePoolMachine.
+func (s *MachinePoolMachineScope) EvictedInstanceRestartInterval() time.Duration {
+	if s.AzureMachinePoolMachine != nil && s.AzureMachinePoolMachine.GetAnnotations()[infrav1exp.KeepDeallocatedAnnotation] == "true" {
+		return 0
+	}
+	if !deallocatesSpotInstances(s.AzureMachinePool) {
+		return 0
+	}
+	if interval := s.AzureMachinePool.Spec.EvictedInstanceRestartInterval; interval != nil {
+		return interval.Duration
+	}
+	return defaultEvictedInstanceRestartInterval
+}
+
+// MarkEvicted sets the EvictionOccurred condition of the AzureMachinePoolMachine, recording the time of the eviction
+// when it is first noticed.
+func (s *MachinePoolMachineScope) MarkEvicted() {
+	status := &s.AzureMachinePoolMachine.Status
+	if !conditions.IsTrue(s.AzureMachinePoolMachine, infrav1.EvictionOccurredCondition) || status.LastEvictionTime == nil {
+		status.LastEvictionTime = ptr.To(metav1.Now())
+	}
+	conditions.Set(s.AzureMachinePoolMachine, &clusterv1.Condition{
+		Type:    infrav1.EvictionOccurredCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.SpotInstanceEvictedReason,
+		Message: fmt.Sprintf("Spot instance was evicted at %s, restarting it", status.LastEvictionTime.UTC().Format(time.RFC3339)),
+	})
+}
+
 // ProvisioningState returns the AzureMachinePoolMachine provisioning state.
 func (s *MachinePoolMachineScope) ProvisioningState() infrav1.ProvisioningState {
 	if s.AzureMachinePoolMachine.Status.ProvisioningState != nil {
@@ -285,6 +361,51 @@ func (s *MachinePoolMachineScope) ProviderID() string {
 	return s.AzureMachinePoolMachine.Spec.ProviderID
 }
 
+// TagsSpecs returns the tags for the Flexible scale set VM. Instances of Uniform scale sets take their tags from the
+// scale set, so no tags are returned for them.
+func (s *MachinePoolMachineScope) TagsSpecs() []azure.TagsSpec {
+	if s.OrchestrationMode() != infrav1.FlexibleOrchestrationMode || s.ProviderID() == "" {
+		return nil
+	}
+	return []azure.TagsSpec{
+		{
+			Scope:      strings.TrimPrefix(s.ProviderID(), azureutil.ProviderIDPrefix),
+			Tags:       s.AzureMachinePool.Spec.AdditionalTags,
+			Annotation: azure.VMTagsLastAppliedAnnotation,
+		},
+	}
+}
+
+// AnnotationJSON returns a map[string]interface from a JSON annotation.
+func (s *MachinePoolMachineScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	jsonAnnotation := s.AzureMachinePoolMachine.GetAnnotations()[annotation]
+	if jsonAnnotation == "" {
+		return out, nil
+	}
+	err := json.Unmarshal([]byte(jsonAnnotation), &out)
+	if err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateAnnotationJSON updates the `annotation` with
+// `content`. `content` in this case should be a `map[string]interface{}`
+// suitable for turning into JSON. This `content` map will be marshalled into a
+// JSON string before being set as the given `annotation`.
+func (s *MachinePoolMachineScope) UpdateAnnotationJSON(annotation string, content map[string]interface{}) error {
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	if s.AzureMachinePoolMachine.Annotations == nil {
+		s.AzureMachinePoolMachine.Annotations = map[string]string{}
+	}
+	s.AzureMachinePoolMachine.Annotations[annotation] = string(b)
+	return nil
+}
+
 // updateDeleteMachineAnnotation sets the clusterv1.DeleteMachineAnnotation on the AzureMachinePoolMachine if it exists on the owner Machine.
 func (s *MachinePoolMachineScope) updateDeleteMachineAnnotation() {
 	if s.Machine.Annotations != nil {
@@ -298,6 +419,21 @@ func (s *MachinePoolMachineScope) updateDeleteMachineAnnotation() {
 	}
 }
 
+// updateProtectedFromScaleInAnnotation records on the AzureMachinePoolMachine whether its instance of a uniform scale set
+// is protected from scale-in, so that it isn't selected when the replica count of the machine pool is lowered.
+func (s *MachinePoolMachineScope) updateProtectedFromScaleInAnnotation() {
+	if s.OrchestrationMode() != infrav1.FlexibleOrchestrationMode && s.protectFromScaleIn() {
+		if s.AzureMachinePoolMachine.Annotations == nil {
+			s.AzureMachinePoolMachine.Annotations = map[string]string{}
+		}
+
+		s.AzureMachinePoolMachine.Annotations[azure.ProtectedFromScaleInAnnotation] = "true"
+		return
+	}
+
+	delete(s.AzureMachinePoolMachine.Annotations, azure.ProtectedFromScaleInAnnotation)
+}
+
 // PatchObject persists the MachinePoolMachine spec and status.
 func (s *MachinePoolMachineScope) PatchObject(ctx context.Context) error {
 	conditions.SetSummary(s.AzureMachinePoolMachine)
@@ -320,6 +456,7 @@ func (s *MachinePoolMachineScope) Close(ctx context.Context) error {
 	defer done()
 
 	s.updateDeleteMachineAnnotation()
+	s.updateProtectedFromScaleInAnnotation()
 
 	return s.PatchObject(ctx)
 }
@@ -384,11 +521,113 @@ func (s *MachinePoolMachineScope) UpdateNodeStatus(ctx context.Context) error {
 		}
 
 		s.AzureMachinePoolMachine.Status.Version = node.Status.NodeInfo.KubeletVersion
+
+		if err := s.reconcileInstanceMetadataLabels(ctx, node); err != nil {
+			return errors.Wrap(err, "failed to reconcile the instance metadata labels of the node")
+		}
 	}
 
 	return nil
 }
 
+// reconcileInstanceMetadataLabels applies the labels derived from the Azure instance to the node and removes the
+// previously applied labels which no longer apply, e.g. because the labels were disabled on the AzureMachinePool.
+func (s *MachinePoolMachineScope) reconcileInstanceMetadataLabels(ctx context.Context, node *corev1.Node) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.reconcileInstanceMetadataLabels",
+	)
+	defer done()
+
+	if s.AzureMachinePool.Spec.InstanceMetadataLabels != nil && s.instance == nil {
+		// The labels can't be derived until the instance has been fetched.
+		return nil
+	}
+
+	lastApplied, err := s.AnnotationJSON(azure.InstanceMetadataLabelsLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	desired := s.instanceMetadataLabels()
+
+	patched := node.DeepCopy()
+	changed := false
+	for key := range lastApplied {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, ok := patched.Labels[key]; ok {
+			delete(patched.Labels, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if current, ok := patched.Labels[key]; ok && current == value {
+			continue
+		}
+		if patched.Labels == nil {
+			patched.Labels = map[string]string{}
+		}
+		patched.Labels[key] = value
+		changed = true
+	}
+
+	if changed {
+		log.V(4).Info("updating instance metadata labels of node", "node", node.Name, "labels", desired)
+		if err := s.workloadNodeGetter.PatchNode(ctx, node, patched); err != nil {
+			return err
+		}
+	}
+
+	if len(desired) == 0 {
+		delete(s.AzureMachinePoolMachine.Annotations, azure.InstanceMetadataLabelsLastAppliedAnnotation)
+		return nil
+	}
+	applied := make(map[string]interface{}, len(desired))
+	for key, value := range desired {
+		applied[key] = value
+	}
+	return s.UpdateAnnotationJSON(azure.InstanceMetadataLabelsLastAppliedAnnotation, applied)
+}
+
+// instanceMetadataLabels returns the node labels derived from the Azure instance, or nil when the labels are disabled.
+func (s *MachinePoolMachineScope) instanceMetadataLabels() map[string]string {
+	config := s.AzureMachinePool.Spec.InstanceMetadataLabels
+	if config == nil || s.instance == nil {
+		return nil
+	}
+
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = infrav1exp.DefaultInstanceMetadataLabelPrefix
+	}
+	labels := map[string]string{}
+	add := func(name, value string) {
+		if value != "" {
+			labels[prefix+"/"+name] = value
+		}
+	}
+
+	add(instanceZoneLabel, s.instance.AvailabilityZone)
+	if s.instance.FaultDomain != nil {
+		add(instanceFaultDomainLabel, strconv.Itoa(int(*s.instance.FaultDomain)))
+	}
+	add(instanceIDLabel, s.InstanceID())
+	add(instanceVMSizeLabel, s.instance.VMSize)
+	switch {
+	case s.instance.Priority != "":
+		add(instancePriorityLabel, strings.ToLower(s.instance.Priority))
+	case s.AzureMachinePool.Spec.Template.SpotVMOptions != nil:
+		// The instances of Uniform scale sets don't report their priority, which is the immutable priority of the
+		// scale set.
+		add(instancePriorityLabel, "spot")
+	default:
+		add(instancePriorityLabel, "regular")
+	}
+
+	return labels
+}
+
 // UpdateInstanceStatus updates the provisioning state of the AzureMachinePoolMachine and if it has the latest model applied
 // using the VMSS VM instance.
 // Note: This func should be called at the end of a reconcile request and after updating the scope with the most recent Azure data.
@@ -504,6 +743,22 @@ func (np *workloadClusterProxy) GetNodeByObjectReference(ctx context.Context, no
 	return &node, err
 }
 
+// PatchNode patches the node in the workload cluster with the changes between original and node.
+func (np *workloadClusterProxy) PatchNode(ctx context.Context, original, node *corev1.Node) error {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.PatchNode",
+	)
+	defer done()
+
+	workloadClient, err := getWorkloadClient(ctx, np.Client, np.Cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the workload cluster client")
+	}
+
+	return workloadClient.Patch(ctx, node, client.MergeFrom(original))
+}
+
 // GetNodeByProviderID will fetch a node from the workload cluster by it's providerID.
 func (np *workloadClusterProxy) GetNodeByProviderID(ctx context.Context, providerID string) (*corev1.Node, error) {
 	ctx, _, done := tele.StartSpanWithLogger(
@@ -520,6 +775,9 @@ func (np *workloadClusterProxy) GetNodeByProviderID(ctx context.Context, provide
 	return getNodeByProviderID(ctx, workloadClient, providerID)
 }
 
+// getNodeByProviderID returns the node with the given providerID. Azure resource IDs are case-insensitive, and the
+// cloud provider may report the ID of a VM, e.g. the one of a Flexible scale set instance, with another casing than
+// the Azure API, so the providerIDs are compared ignoring case.
 func getNodeByProviderID(ctx context.Context, workloadClient client.Client, providerID string) (*corev1.Node, error) {
 	ctx, _, done := tele.StartSpanWithLogger(
 		ctx,
@@ -534,7 +792,7 @@ func getNodeByProviderID(ctx context.Context, workloadClient client.Client, prov
 		}
 
 		for _, node := range nodeList.Items {
-			if node.Spec.ProviderID == providerID {
+			if strings.EqualFold(node.Spec.ProviderID, providerID) {
 				return &node, nil
 			}
 		}
@@ -554,5 +812,5 @@ func getWorkloadClient(ctx context.Context, c client.Client, cluster client.Obje
 	)
 	defer done()
 
-	return remote.NewClusterClient(ctx, MachinePoolMachineScopeName, c, cluster)
+	return GetRemoteClientCache().GetClient(ctx, c, cluster)
 }
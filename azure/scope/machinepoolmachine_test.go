@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
@@ -39,6 +40,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -272,6 +274,209 @@ func TestMachinePoolMachineScope_ScaleSetVMSpecs(t *testing.T) {
 		})
 	}
 }
+func TestMachinePoolMachineScope_TagsSpecs(t *testing.T) {
+	flexProviderID := "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machinepool-name_1234"
+	tests := []struct {
+		name              string
+		orchestrationMode infrav1.OrchestrationModeType
+		providerID        string
+		want              []azure.TagsSpec
+	}{
+		{
+			name:              "uniform instances take their tags from the scale set",
+			orchestrationMode: infrav1.UniformOrchestrationMode,
+			providerID:        "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/machinepool-name/virtualMachines/0",
+			want:              nil,
+		},
+		{
+			name:              "flex instance without a provider ID yet",
+			orchestrationMode: infrav1.FlexibleOrchestrationMode,
+			want:              nil,
+		},
+		{
+			name:              "flex instance",
+			orchestrationMode: infrav1.FlexibleOrchestrationMode,
+			providerID:        flexProviderID,
+			want: []azure.TagsSpec{
+				{
+					Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machinepool-name_1234",
+					Tags:       infrav1.Tags{"env": "prod"},
+					Annotation: azure.VMTagsLastAppliedAnnotation,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := MachinePoolMachineScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						AdditionalTags:    infrav1.Tags{"env": "prod"},
+						OrchestrationMode: tt.orchestrationMode,
+					},
+				},
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					Spec: infrav1exp.AzureMachinePoolMachineSpec{
+						ProviderID: tt.providerID,
+					},
+				},
+			}
+			g.Expect(s.TagsSpecs()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_EvictedInstanceRestartInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		spot        *infrav1.SpotVMOptions
+		interval    *metav1.Duration
+		annotations map[string]string
+		want        time.Duration
+	}{
+		{
+			name: "regular instances",
+			want: 0,
+		},
+		{
+			name: "spot instances deleted on eviction",
+			spot: &infrav1.SpotVMOptions{EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDelete)},
+			want: 0,
+		},
+		{
+			name: "spot instances deallocated on eviction by default",
+			spot: &infrav1.SpotVMOptions{},
+			want: 5 * time.Minute,
+		},
+		{
+			name:     "spot instances deallocated on eviction with a restart interval",
+			spot:     &infrav1.SpotVMOptions{EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDeallocate)},
+			interval: &metav1.Duration{Duration: 2 * time.Minute},
+			want:     2 * time.Minute,
+		},
+		{
+			name:        "spot instance kept deallocated",
+			spot:        &infrav1.SpotVMOptions{},
+			annotations: map[string]string{infrav1exp.KeepDeallocatedAnnotation: "true"},
+			want:        0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := MachinePoolMachineScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Template:                       infrav1exp.AzureMachinePoolMachineTemplate{SpotVMOptions: tt.spot},
+						EvictedInstanceRestartInterval: tt.interval,
+					},
+				},
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				},
+			}
+			g.Expect(s.EvictedInstanceRestartInterval()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_ProtectFromScaleIn(t *testing.T) {
+	protected := map[string]string{infrav1exp.ProtectFromScaleInAnnotation: "true"}
+	tests := []struct {
+		name                     string
+		ampmAnnotations          map[string]string
+		machineAnnotations       map[string]string
+		ampmDeletionTimestamp    *metav1.Time
+		machineDeletionTimestamp *metav1.Time
+		want                     bool
+	}{
+		{
+			name: "no annotation",
+			want: false,
+		},
+		{
+			name:            "annotated AzureMachinePoolMachine",
+			ampmAnnotations: protected,
+			want:            true,
+		},
+		{
+			name:               "annotated Machine",
+			machineAnnotations: protected,
+			want:               true,
+		},
+		{
+			name:            "annotation not set to true",
+			ampmAnnotations: map[string]string{infrav1exp.ProtectFromScaleInAnnotation: "false"},
+			want:            false,
+		},
+		{
+			name:                  "deleted AzureMachinePoolMachine",
+			ampmAnnotations:       protected,
+			ampmDeletionTimestamp: &metav1.Time{Time: time.Now()},
+			want:                  false,
+		},
+		{
+			name:                     "deleted Machine",
+			ampmAnnotations:          protected,
+			machineDeletionTimestamp: &metav1.Time{Time: time.Now()},
+			want:                     false,
+		},
+		{
+			name:               "Machine marked for deletion",
+			ampmAnnotations:    protected,
+			machineAnnotations: map[string]string{clusterv1.DeleteMachineAnnotation: "true"},
+			want:               false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := MachinePoolMachineScope{
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations:       tt.ampmAnnotations,
+						DeletionTimestamp: tt.ampmDeletionTimestamp,
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations:       tt.machineAnnotations,
+						DeletionTimestamp: tt.machineDeletionTimestamp,
+					},
+				},
+			}
+			g.Expect(s.protectFromScaleIn()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_MarkEvicted(t *testing.T) {
+	g := NewWithT(t)
+	s := MachinePoolMachineScope{
+		AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{},
+	}
+
+	s.MarkEvicted()
+	g.Expect(conditions.IsTrue(s.AzureMachinePoolMachine, infrav1.EvictionOccurredCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(s.AzureMachinePoolMachine, infrav1.EvictionOccurredCondition)).To(Equal(infrav1.SpotInstanceEvictedReason))
+	evictionTime := s.AzureMachinePoolMachine.Status.LastEvictionTime
+	g.Expect(evictionTime).NotTo(BeNil())
+
+	// The eviction time is kept while the instance is being restarted.
+	s.SetVMSSVM(&azure.VMSSVM{PowerState: infrav1.VMPowerStateStarting})
+	s.MarkEvicted()
+	g.Expect(s.AzureMachinePoolMachine.Status.LastEvictionTime).To(BeIdenticalTo(evictionTime))
+
+	// The condition is removed once the instance is running again, and the eviction time kept.
+	s.SetVMSSVM(&azure.VMSSVM{PowerState: infrav1.VMPowerStateRunning})
+	g.Expect(conditions.Has(s.AzureMachinePoolMachine, infrav1.EvictionOccurredCondition)).To(BeFalse())
+	g.Expect(s.AzureMachinePoolMachine.Status.LastEvictionTime).To(BeIdenticalTo(evictionTime))
+}
+
 func TestMachineScope_updateDeleteMachineAnnotation(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -322,6 +527,61 @@ func TestMachineScope_updateDeleteMachineAnnotation(t *testing.T) {
 	}
 }
 
+func TestMachinePoolMachineScope_updateProtectedFromScaleInAnnotation(t *testing.T) {
+	tests := []struct {
+		name               string
+		orchestrationMode  infrav1.OrchestrationModeType
+		ampmAnnotations    map[string]string
+		machineAnnotations map[string]string
+		want               bool
+	}{
+		{
+			name:              "not protected",
+			orchestrationMode: infrav1.UniformOrchestrationMode,
+			ampmAnnotations:   map[string]string{azure.ProtectedFromScaleInAnnotation: "true"},
+			want:              false,
+		},
+		{
+			name:               "protected through the Machine",
+			orchestrationMode:  infrav1.UniformOrchestrationMode,
+			machineAnnotations: map[string]string{infrav1exp.ProtectFromScaleInAnnotation: "true"},
+			want:               true,
+		},
+		{
+			name:              "protected in a flexible scale set",
+			orchestrationMode: infrav1.FlexibleOrchestrationMode,
+			ampmAnnotations:   map[string]string{infrav1exp.ProtectFromScaleInAnnotation: "true"},
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := MachinePoolMachineScope{
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						OrchestrationMode: tt.orchestrationMode,
+					},
+				},
+				AzureMachinePoolMachine: &infrav1exp.AzureMachinePoolMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: tt.ampmAnnotations,
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: tt.machineAnnotations,
+					},
+				},
+			}
+			s.updateProtectedFromScaleInAnnotation()
+			_, protected := s.AzureMachinePoolMachine.Annotations[azure.ProtectedFromScaleInAnnotation]
+			g.Expect(protected).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMachineScope_UpdateNodeStatus(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = expv1.AddToScheme(scheme)
@@ -462,6 +722,211 @@ func TestMachineScope_UpdateNodeStatus(t *testing.T) {
 	}
 }
 
+// fakeWorkloadCluster serves the nodes of a workload cluster from a fake client.
+type fakeWorkloadCluster struct {
+	client.Client
+}
+
+func (f *fakeWorkloadCluster) GetNodeByProviderID(ctx context.Context, providerID string) (*corev1.Node, error) {
+	return getNodeByProviderID(ctx, f.Client, providerID)
+}
+
+func (f *fakeWorkloadCluster) GetNodeByObjectReference(ctx context.Context, nodeRef corev1.ObjectReference) (*corev1.Node, error) {
+	node := &corev1.Node{}
+	err := f.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node)
+	return node, err
+}
+
+func (f *fakeWorkloadCluster) PatchNode(ctx context.Context, original, node *corev1.Node) error {
+	return f.Patch(ctx, node, client.MergeFrom(original))
+}
+
+func TestGetNodeByProviderID(t *testing.T) {
+	g := NewWithT(t)
+
+	node := getReadyNode()
+	node.Spec.ProviderID = "azure:///subscriptions/123/resourcegroups/my-cluster/providers/microsoft.compute/virtualmachines/my-cluster-mp-0_1a2b3c4d"
+	workloadClient := fake.NewClientBuilder().WithObjects(node).Build()
+
+	// The providerID of a Flexible scale set instance built from its Azure resource ID matches its node regardless of case.
+	found, err := getNodeByProviderID(context.TODO(), workloadClient, "azure:///subscriptions/123/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/my-cluster-mp-0_1a2b3c4d")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).NotTo(BeNil())
+	g.Expect(found.Name).To(Equal(node.Name))
+
+	found, err = getNodeByProviderID(context.TODO(), workloadClient, "azure:///subscriptions/123/resourceGroups/my-cluster/providers/Microsoft.Compute/virtualMachines/my-cluster-mp-0_5e6f7a8b")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeNil())
+}
+
+func TestMachinePoolMachineScope_InstanceMetadataLabels(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = expv1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clusterScope := mock_azure.NewMockClusterScoper(mockCtrl)
+	clusterScope.EXPECT().BaseURI().AnyTimes()
+	clusterScope.EXPECT().Location().AnyTimes()
+	clusterScope.EXPECT().SubscriptionID().AnyTimes()
+	clusterScope.EXPECT().ClusterName().Return("cluster-foo").AnyTimes()
+
+	instance := &azure.VMSSVM{
+		InstanceID:       "3",
+		AvailabilityZone: "1",
+		FaultDomain:      ptr.To[int32](2),
+		VMSize:           "Standard_D2s_v3",
+	}
+
+	cases := []struct {
+		Name               string
+		Labels             *infrav1exp.InstanceMetadataLabels
+		SpotVMOptions      *infrav1.SpotVMOptions
+		Instance           *azure.VMSSVM
+		NodeLabels         map[string]string
+		LastApplied        string
+		ExpectedLabels     map[string]string
+		ExpectedAnnotation string
+	}{
+		{
+			Name:       "labels the node with the default prefix",
+			Labels:     &infrav1exp.InstanceMetadataLabels{},
+			Instance:   instance,
+			NodeLabels: map[string]string{"foo": "bar"},
+			ExpectedLabels: map[string]string{
+				"foo":                                  "bar",
+				"instance.azure.cluster.x-k8s.io/zone": "1",
+				"instance.azure.cluster.x-k8s.io/fault-domain": "2",
+				"instance.azure.cluster.x-k8s.io/instance-id":  "3",
+				"instance.azure.cluster.x-k8s.io/vm-size":      "Standard_D2s_v3",
+				"instance.azure.cluster.x-k8s.io/priority":     "regular",
+			},
+			ExpectedAnnotation: `{"instance.azure.cluster.x-k8s.io/fault-domain":"2","instance.azure.cluster.x-k8s.io/instance-id":"3","instance.azure.cluster.x-k8s.io/priority":"regular","instance.azure.cluster.x-k8s.io/vm-size":"Standard_D2s_v3","instance.azure.cluster.x-k8s.io/zone":"1"}`,
+		},
+		{
+			Name:          "labels spot instances with a custom prefix",
+			Labels:        &infrav1exp.InstanceMetadataLabels{Prefix: "example.com"},
+			SpotVMOptions: &infrav1.SpotVMOptions{},
+			Instance: &azure.VMSSVM{
+				InstanceID: "3",
+				VMSize:     "Standard_D2s_v3",
+			},
+			ExpectedLabels: map[string]string{
+				"example.com/instance-id": "3",
+				"example.com/vm-size":     "Standard_D2s_v3",
+				"example.com/priority":    "spot",
+			},
+			ExpectedAnnotation: `{"example.com/instance-id":"3","example.com/priority":"spot","example.com/vm-size":"Standard_D2s_v3"}`,
+		},
+		{
+			Name:          "labels instances with the priority they report",
+			Labels:        &infrav1exp.InstanceMetadataLabels{Prefix: "example.com"},
+			SpotVMOptions: &infrav1.SpotVMOptions{},
+			Instance: &azure.VMSSVM{
+				InstanceID: "3",
+				Priority:   "Regular",
+			},
+			ExpectedLabels: map[string]string{
+				"example.com/instance-id": "3",
+				"example.com/priority":    "regular",
+			},
+			ExpectedAnnotation: `{"example.com/instance-id":"3","example.com/priority":"regular"}`,
+		},
+		{
+			Name:     "removes the labels of a previous prefix",
+			Labels:   &infrav1exp.InstanceMetadataLabels{Prefix: "example.com"},
+			Instance: &azure.VMSSVM{InstanceID: "3"},
+			NodeLabels: map[string]string{
+				"foo": "bar",
+				"instance.azure.cluster.x-k8s.io/instance-id": "3",
+			},
+			LastApplied: `{"instance.azure.cluster.x-k8s.io/instance-id":"3"}`,
+			ExpectedLabels: map[string]string{
+				"foo":                     "bar",
+				"example.com/instance-id": "3",
+				"example.com/priority":    "regular",
+			},
+			ExpectedAnnotation: `{"example.com/instance-id":"3","example.com/priority":"regular"}`,
+		},
+		{
+			Name:     "removes the labels when disabled",
+			Instance: instance,
+			NodeLabels: map[string]string{
+				"foo": "bar",
+				"instance.azure.cluster.x-k8s.io/instance-id": "3",
+				"instance.azure.cluster.x-k8s.io/priority":    "regular",
+			},
+			LastApplied:    `{"instance.azure.cluster.x-k8s.io/instance-id":"3","instance.azure.cluster.x-k8s.io/priority":"regular"}`,
+			ExpectedLabels: map[string]string{"foo": "bar"},
+		},
+		{
+			Name:        "waits for the instance before labeling the node",
+			Labels:      &infrav1exp.InstanceMetadataLabels{},
+			NodeLabels:  map[string]string{"instance.azure.cluster.x-k8s.io/instance-id": "3"},
+			LastApplied: `{"instance.azure.cluster.x-k8s.io/instance-id":"3"}`,
+			ExpectedLabels: map[string]string{
+				"instance.azure.cluster.x-k8s.io/instance-id": "3",
+			},
+			ExpectedAnnotation: `{"instance.azure.cluster.x-k8s.io/instance-id":"3"}`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			node := getReadyNode()
+			node.Labels = c.NodeLabels
+			node.Spec.ProviderID = FakeProviderID
+			workloadCluster := &fakeWorkloadCluster{fake.NewClientBuilder().WithObjects(node).Build()}
+
+			ampm := &infrav1exp.AzureMachinePoolMachine{
+				Spec: infrav1exp.AzureMachinePoolMachineSpec{
+					ProviderID: FakeProviderID,
+					InstanceID: "3",
+				},
+			}
+			if c.LastApplied != "" {
+				ampm.Annotations = map[string]string{azure.InstanceMetadataLabelsLastAppliedAnnotation: c.LastApplied}
+			}
+			amp := &infrav1exp.AzureMachinePool{
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					InstanceMetadataLabels: c.Labels,
+					Template: infrav1exp.AzureMachinePoolMachineTemplate{
+						SpotVMOptions: c.SpotVMOptions,
+					},
+				},
+			}
+
+			s, err := NewMachinePoolMachineScope(MachinePoolMachineScopeParams{
+				Client:                  fake.NewClientBuilder().WithScheme(scheme).Build(),
+				ClusterScope:            clusterScope,
+				MachinePool:             &expv1.MachinePool{},
+				AzureMachinePool:        amp,
+				AzureMachinePoolMachine: ampm,
+				Machine:                 new(clusterv1.Machine),
+				workloadNodeGetter:      workloadCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			s.instance = c.Instance
+
+			g.Expect(s.UpdateNodeStatus(context.TODO())).To(Succeed())
+
+			updated := &corev1.Node{}
+			g.Expect(workloadCluster.Get(context.TODO(), client.ObjectKeyFromObject(node), updated)).To(Succeed())
+			if len(c.ExpectedLabels) == 0 {
+				g.Expect(updated.Labels).To(BeEmpty())
+			} else {
+				g.Expect(updated.Labels).To(Equal(c.ExpectedLabels))
+			}
+			g.Expect(s.AzureMachinePoolMachine.Annotations[azure.InstanceMetadataLabelsLastAppliedAnnotation]).To(Equal(c.ExpectedAnnotation))
+		})
+	}
+}
+
 func getReadyNode() *corev1.Node {
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -21,15 +21,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
 	asocontainerservicev1preview "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20230315preview"
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	asokubernetesconfigurationv1 "github.com/Azure/azure-service-operator/v2/api/kubernetesconfiguration/v1api20230501"
+	asonetworkv1api20180501 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
 	asonetworkv1api20201101 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	asonetworkv1api20220701 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
@@ -41,16 +45,20 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aksextensions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/maintenanceconfigurations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnetroleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/aso"
+	conditionsutils "sigs.k8s.io/cluster-api-provider-azure/util/conditions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -60,8 +68,9 @@ import (
 
 const (
 	resourceHealthWarningInitialGracePeriod = 1 * time.Hour
-	// managedControlPlaneScopeName is the sourceName, or more specifically the UserAgent, of client used to store the Cluster Info configmap.
-	managedControlPlaneScopeName = "azuremanagedcontrolplane-scope"
+
+	// OIDCIssuerURLSecretKey is the key of the OIDC issuer URL in the secret made by MakeOIDCIssuerSecret.
+	OIDCIssuerURLSecretKey = "issuerURL"
 )
 
 // ManagedControlPlaneScopeParams defines the input parameters used to create a new managed
@@ -103,20 +112,26 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 		params.Cache = &ManagedControlPlaneCache{}
 	}
 
+	controlPlaneTemplate, err := infrav1.GetAzureManagedControlPlaneTemplate(ctx, params.Client, params.ControlPlane)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get AzureManagedControlPlaneTemplate")
+	}
+
 	helper, err := patch.NewHelper(params.ControlPlane, params.Client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
 
 	return &ManagedControlPlaneScope{
-		Client:              params.Client,
-		AzureClients:        params.AzureClients,
-		Cluster:             params.Cluster,
-		ControlPlane:        params.ControlPlane,
-		ManagedMachinePools: params.ManagedMachinePools,
-		PatchHelper:         helper,
-		cache:               params.Cache,
-		AsyncReconciler:     params.Timeouts,
+		Client:               params.Client,
+		AzureClients:         params.AzureClients,
+		Cluster:              params.Cluster,
+		ControlPlane:         params.ControlPlane,
+		ControlPlaneTemplate: controlPlaneTemplate,
+		ManagedMachinePools:  params.ManagedMachinePools,
+		PatchHelper:          helper,
+		cache:                params.Cache,
+		AsyncReconciler:      params.Timeouts,
 	}, nil
 }
 
@@ -127,16 +142,22 @@ type ManagedControlPlaneScope struct {
 	adminKubeConfigData []byte
 	userKubeConfigData  []byte
 	cache               *ManagedControlPlaneCache
+	// authorizedIPRangesDrift holds the authorized IP ranges observed in Azure when they drifted from the spec.
+	authorizedIPRangesDrift *[]string
 
 	AzureClients
-	Cluster             *clusterv1.Cluster
-	ControlPlane        *infrav1.AzureManagedControlPlane
-	ManagedMachinePools []ManagedMachinePool
+	Cluster      *clusterv1.Cluster
+	ControlPlane *infrav1.AzureManagedControlPlane
+	// ControlPlaneTemplate is the AzureManagedControlPlaneTemplate ControlPlane was cloned from, if any.
+	ControlPlaneTemplate *infrav1.AzureManagedControlPlaneTemplate
+	ManagedMachinePools  []ManagedMachinePool
 	azure.AsyncReconciler
 }
 
 // ManagedControlPlaneCache stores ManagedControlPlane data locally so we don't have to hit the API multiple times within the same reconcile loop.
+// It is guarded by a mutex as the scope is shared between the AzureManagedMachinePools reconciling at the same time.
 type ManagedControlPlaneCache struct {
+	mu            sync.Mutex
 	isVnetManaged *bool
 }
 
@@ -234,6 +255,7 @@ func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
 	defer done()
 
 	conditions.SetSummary(s.ControlPlane)
+	conditionsutils.SetV1Beta2Conditions(s.ControlPlane)
 
 	return s.PatchHelper.Patch(
 		ctx,
@@ -246,6 +268,9 @@ func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
 			infrav1.ManagedClusterRunningCondition,
 			infrav1.AgentPoolsReadyCondition,
 			infrav1.AzureResourceAvailableCondition,
+			infrav1.PrimaryIdentityAuthenticatedCondition,
+			infrav1.MaintenanceConfigurationReadyCondition,
+			infrav1.NodeResourceGroupEmptyCondition,
 		}})
 }
 
@@ -325,7 +350,7 @@ func (s *ManagedControlPlaneScope) NodeNatGateway() infrav1.NatGateway {
 
 // SubnetSpecs returns the subnets specs.
 func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet] {
-	return []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{
+	subnetSpecs := []azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{
 		&subnets.SubnetSpec{
 			Name:              s.NodeSubnet().Name,
 			ResourceGroup:     s.ResourceGroup(),
@@ -335,8 +360,34 @@ func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.ASOResourceSpecGetter[*
 			VNetResourceGroup: s.Vnet().ResourceGroup,
 			IsVNetManaged:     s.IsVnetManaged(),
 			ServiceEndpoints:  s.NodeSubnet().ServiceEndpoints,
+			NatGatewayID:      s.ControlPlane.Spec.VirtualNetwork.Subnet.NatGatewayID,
 		},
 	}
+
+	// Pod subnets of the node pools are delegated to AKS.
+	podSubnets := map[string]bool{}
+	for _, pool := range s.ManagedMachinePools {
+		if pool.InfraMachinePool != nil && pool.InfraMachinePool.Spec.PodSubnetName != nil {
+			podSubnets[*pool.InfraMachinePool.Spec.PodSubnetName] = true
+		}
+	}
+	for _, subnet := range s.ControlPlane.Spec.VirtualNetwork.Subnets {
+		subnetSpec := &subnets.SubnetSpec{
+			Name:              subnet.Name,
+			ResourceGroup:     s.ResourceGroup(),
+			SubscriptionID:    s.SubscriptionID(),
+			CIDRs:             []string{subnet.CIDRBlock},
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			IsVNetManaged:     s.IsVnetManaged(),
+			Delegations:       subnet.Delegations,
+		}
+		if podSubnets[subnet.Name] && !slices.Contains(subnet.Delegations, subnets.ManagedClustersDelegation) {
+			subnetSpec.Delegations = append(slices.Clone(subnet.Delegations), subnets.ManagedClustersDelegation)
+		}
+		subnetSpecs = append(subnetSpecs, subnetSpec)
+	}
+	return subnetSpecs
 }
 
 // Subnets returns the subnets specs.
@@ -356,6 +407,19 @@ func (s *ManagedControlPlaneScope) NodeSubnet() infrav1.SubnetSpec {
 	}
 }
 
+// AuthorizedIPRangesDrift returns the authorized IP ranges of the API server observed in Azure and true if they
+// drifted from the spec during this reconciliation.
+func (s *ManagedControlPlaneScope) AuthorizedIPRangesDrift() ([]string, bool) {
+	if s.authorizedIPRangesDrift == nil {
+		return nil, false
+	}
+	return *s.authorizedIPRangesDrift, true
+}
+
+func (s *ManagedControlPlaneScope) setAuthorizedIPRangesDrift(observed []string) {
+	s.authorizedIPRangesDrift = &observed
+}
+
 // SetSubnet sets the passed subnet spec into the scope.
 // This is not used when using a managed control plane.
 func (s *ManagedControlPlaneScope) SetSubnet(_ infrav1.SubnetSpec) {
@@ -414,6 +478,8 @@ func (s *ManagedControlPlaneScope) IsIPv6Enabled() bool {
 
 // IsVnetManaged returns true if the vnet is managed.
 func (s *ManagedControlPlaneScope) IsVnetManaged() bool {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
 	if s.cache.isVnetManaged != nil {
 		return ptr.Deref(s.cache.isVnetManaged, false)
 	}
@@ -457,6 +523,11 @@ func (s *ManagedControlPlaneScope) IsAPIServerPrivate() bool {
 	return false
 }
 
+// APIServerLBMigration returns nil as managed control planes have no API server load balancer.
+func (s *ManagedControlPlaneScope) APIServerLBMigration() *infrav1.APIServerLBMigration {
+	return nil
+}
+
 // OutboundLBName returns the name of the outbound LB.
 // Note: for managed clusters, the outbound LB lifecycle is not managed.
 func (s *ManagedControlPlaneScope) OutboundLBName(_ string) string {
@@ -479,6 +550,65 @@ func (s *ManagedControlPlaneScope) CloudProviderConfigOverrides() *infrav1.Cloud
 	return nil
 }
 
+// InitManagedResources starts recording the Azure resources created by CAPZ for the managed cluster.
+func (s *ManagedControlPlaneScope) InitManagedResources() {
+	if s.ControlPlane.Status.ManagedResources == nil {
+		s.ControlPlane.Status.ManagedResources = &infrav1.ManagedResources{}
+	}
+}
+
+// IsOwnershipRecorded returns true if the Azure resources created by CAPZ are recorded for the managed cluster.
+func (s *ManagedControlPlaneScope) IsOwnershipRecorded() bool {
+	return s.ControlPlane.Status.ManagedResources != nil
+}
+
+// RecordManagedResource records that CAPZ created the Azure resource with the given ID.
+func (s *ManagedControlPlaneScope) RecordManagedResource(id string) {
+	s.ControlPlane.Status.ManagedResources.Add(id)
+}
+
+// ForgetManagedResource removes the Azure resource with the given ID from the resources recorded as created by CAPZ
+// once it has been deleted.
+func (s *ManagedControlPlaneScope) ForgetManagedResource(id string) {
+	s.ControlPlane.Status.ManagedResources.Remove(id)
+}
+
+// RecordManagedResourceDeletion records that the recorded Azure resource with the given ID is being deleted by the
+// resource with the given key.
+func (s *ManagedControlPlaneScope) RecordManagedResourceDeletion(key, id string) {
+	s.ControlPlane.Status.ManagedResources.MarkDeleting(key, id)
+}
+
+// ManagedResourceDeletion returns the ID of the recorded Azure resource being deleted by the resource with the given
+// key.
+func (s *ManagedControlPlaneScope) ManagedResourceDeletion(key string) string {
+	return s.ControlPlane.Status.ManagedResources.DeletingID(key)
+}
+
+// IsManagedResource returns the result of the tag-based check as the ownership of managed cluster resources is
+// determined from tags, the recorded resources are only informational.
+func (s *ManagedControlPlaneScope) IsManagedResource(_ string, ownedByTags bool) bool {
+	return ownedByTags
+}
+
+// DefaultImage returns the default image for machines in the cluster.
+// Currently always nil as AKS manages the node images of managed clusters.
+func (s *ManagedControlPlaneScope) DefaultImage() *infrav1.DefaultImage {
+	return nil
+}
+
+// ClusterVMExtensions returns the VM extensions installed on all the machines in the cluster.
+// Currently always nil as cluster-wide VM extensions are only supported for self-managed clusters.
+func (s *ManagedControlPlaneScope) ClusterVMExtensions() []infrav1.ClusterVMExtension {
+	return nil
+}
+
+// NamingTemplate returns the templates rendering the names of the Azure resources of the cluster.
+// Currently always nil as AKS names the resources of managed clusters.
+func (s *ManagedControlPlaneScope) NamingTemplate() *infrav1.NamingTemplate {
+	return nil
+}
+
 // FailureDomains returns the failure domains for the cluster.
 func (s *ManagedControlPlaneScope) FailureDomains() []*string {
 	return []*string{}
@@ -507,6 +637,37 @@ func (s *ManagedControlPlaneScope) SetVersionStatus(version string) {
 	s.ControlPlane.Status.Version = version
 }
 
+// SetNodeResourceGroupNameStatus sets the name of the node resource group in status.
+func (s *ManagedControlPlaneScope) SetNodeResourceGroupNameStatus(name string) {
+	s.ControlPlane.Status.NodeResourceGroupName = name
+}
+
+// SetOutboundIPsStatus sets the addresses of the effective outbound public IPs in status.
+func (s *ManagedControlPlaneScope) SetOutboundIPsStatus(addresses []string) {
+	s.ControlPlane.Status.OutboundIPs = addresses
+}
+
+// SetIdentityPrincipalIDStatus sets the principal ID of the system-assigned identity of the managed cluster in status.
+func (s *ManagedControlPlaneScope) SetIdentityPrincipalIDStatus(principalID string) {
+	s.ControlPlane.Status.IdentityPrincipalID = principalID
+}
+
+// IdentityPrincipalID returns the principal ID of the system-assigned identity of the managed cluster, or an empty
+// string until AKS created it.
+func (s *ManagedControlPlaneScope) IdentityPrincipalID() string {
+	return s.ControlPlane.Status.IdentityPrincipalID
+}
+
+// UserAssignedIdentityResourceID returns the resource ID of the user-assigned identity of the control plane, or an
+// empty string if the control plane uses a system-assigned identity.
+func (s *ManagedControlPlaneScope) UserAssignedIdentityResourceID() string {
+	identity := s.ControlPlane.Spec.Identity
+	if identity == nil || identity.Type != infrav1.ManagedControlPlaneIdentityTypeUserAssigned {
+		return ""
+	}
+	return identity.UserAssignedIdentityResourceID
+}
+
 // SetAutoUpgradeVersionStatus sets the auto upgrade version in status.
 func (s *ManagedControlPlaneScope) SetAutoUpgradeVersionStatus(version string) {
 	s.ControlPlane.Status.AutoUpgradeVersion = version
@@ -542,6 +703,7 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
 		),
 		GetAllAgentPools:            s.GetAllAgentPoolSpecs,
+		OnAuthorizedIPRangesDrift:   s.setAuthorizedIPRangesDrift,
 		OutboundType:                s.ControlPlane.Spec.OutboundType,
 		Identity:                    s.ControlPlane.Spec.Identity,
 		KubeletUserAssignedIdentity: s.ControlPlane.Spec.KubeletUserAssignedIdentity,
@@ -595,6 +757,7 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 			})
 		}
 	}
+	managedClusterSpec.PreserveUnmanagedAddons = s.ControlPlane.Annotations[infrav1.PreserveUnmanagedAddonsAnnotation] == "true"
 
 	if s.ControlPlane.Spec.SKU != nil {
 		managedClusterSpec.SKU = &managedclusters.SKU{
@@ -612,6 +775,13 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 		}
 	}
 
+	if s.ControlPlane.Spec.NatGatewayProfile != nil {
+		managedClusterSpec.NatGatewayProfile = &managedclusters.NatGatewayProfile{
+			ManagedOutboundIPCount: s.ControlPlane.Spec.NatGatewayProfile.ManagedOutboundIPCount,
+			IdleTimeoutInMinutes:   s.ControlPlane.Spec.NatGatewayProfile.IdleTimeoutInMinutes,
+		}
+	}
+
 	if s.ControlPlane.Spec.APIServerAccessProfile != nil {
 		managedClusterSpec.APIServerAccessProfile = &managedclusters.APIServerAccessProfile{
 			AuthorizedIPRanges:             s.ControlPlane.Spec.APIServerAccessProfile.AuthorizedIPRanges,
@@ -621,26 +791,9 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 		}
 	}
 
-	if s.ControlPlane.Spec.AutoScalerProfile != nil {
-		managedClusterSpec.AutoScalerProfile = &managedclusters.AutoScalerProfile{
-			BalanceSimilarNodeGroups:      (*string)(s.ControlPlane.Spec.AutoScalerProfile.BalanceSimilarNodeGroups),
-			Expander:                      (*string)(s.ControlPlane.Spec.AutoScalerProfile.Expander),
-			MaxEmptyBulkDelete:            s.ControlPlane.Spec.AutoScalerProfile.MaxEmptyBulkDelete,
-			MaxGracefulTerminationSec:     s.ControlPlane.Spec.AutoScalerProfile.MaxGracefulTerminationSec,
-			MaxNodeProvisionTime:          s.ControlPlane.Spec.AutoScalerProfile.MaxNodeProvisionTime,
-			MaxTotalUnreadyPercentage:     s.ControlPlane.Spec.AutoScalerProfile.MaxTotalUnreadyPercentage,
-			NewPodScaleUpDelay:            s.ControlPlane.Spec.AutoScalerProfile.NewPodScaleUpDelay,
-			OkTotalUnreadyCount:           s.ControlPlane.Spec.AutoScalerProfile.OkTotalUnreadyCount,
-			ScanInterval:                  s.ControlPlane.Spec.AutoScalerProfile.ScanInterval,
-			ScaleDownDelayAfterAdd:        s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterAdd,
-			ScaleDownDelayAfterDelete:     s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterDelete,
-			ScaleDownDelayAfterFailure:    s.ControlPlane.Spec.AutoScalerProfile.ScaleDownDelayAfterFailure,
-			ScaleDownUnneededTime:         s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUnneededTime,
-			ScaleDownUnreadyTime:          s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUnreadyTime,
-			ScaleDownUtilizationThreshold: s.ControlPlane.Spec.AutoScalerProfile.ScaleDownUtilizationThreshold,
-			SkipNodesWithLocalStorage:     (*string)(s.ControlPlane.Spec.AutoScalerProfile.SkipNodesWithLocalStorage),
-			SkipNodesWithSystemPods:       (*string)(s.ControlPlane.Spec.AutoScalerProfile.SkipNodesWithSystemPods),
-		}
+	managedClusterSpec.AutoScalerProfile = autoScalerProfile(s.ControlPlane.Spec.AutoScalerProfile)
+	if s.ControlPlaneTemplate != nil {
+		managedClusterSpec.AutoScalerProfileDefaults = autoScalerProfile(s.ControlPlaneTemplate.Spec.Template.Spec.AutoScalerProfile)
 	}
 
 	if s.ControlPlane.Spec.HTTPProxyConfig != nil {
@@ -659,9 +812,9 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 	}
 
 	if s.ControlPlane.Spec.AutoUpgradeProfile != nil {
-		managedClusterSpec.AutoUpgradeProfile = &managedclusters.ManagedClusterAutoUpgradeProfile{}
-		if s.ControlPlane.Spec.AutoUpgradeProfile.UpgradeChannel != nil {
-			managedClusterSpec.AutoUpgradeProfile.UpgradeChannel = s.ControlPlane.Spec.AutoUpgradeProfile.UpgradeChannel
+		managedClusterSpec.AutoUpgradeProfile = &managedclusters.ManagedClusterAutoUpgradeProfile{
+			UpgradeChannel:       s.ControlPlane.Spec.AutoUpgradeProfile.UpgradeChannel,
+			NodeOSUpgradeChannel: s.ControlPlane.Spec.AutoUpgradeProfile.NodeOSUpgradeChannel,
 		}
 	}
 
@@ -669,9 +822,74 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() azure.ASOResourceSpecGet
 		managedClusterSpec.SecurityProfile = s.getManagedClusterSecurityProfile()
 	}
 
+	if windowsProfile := s.ControlPlane.Spec.WindowsProfile; windowsProfile != nil {
+		managedClusterSpec.WindowsProfile = &managedclusters.ManagedClusterWindowsProfile{}
+		if windowsProfile.GmsaProfile != nil {
+			managedClusterSpec.WindowsProfile.GmsaProfile = &managedclusters.WindowsGmsaProfile{
+				Enabled:        ptr.To(windowsProfile.GmsaProfile.Enabled),
+				DNSServer:      windowsProfile.GmsaProfile.DNSServer,
+				RootDomainName: windowsProfile.GmsaProfile.RootDomainName,
+			}
+		}
+	}
+
+	if s.ControlPlane.Spec.PodIdentityProfile != nil {
+		managedClusterSpec.PodIdentityProfile = &managedclusters.PodIdentityProfile{
+			Enabled:                   s.ControlPlane.Spec.PodIdentityProfile.Enabled,
+			AllowNetworkPluginKubenet: s.ControlPlane.Spec.PodIdentityProfile.AllowNetworkPluginKubenet,
+		}
+		for _, exception := range s.ControlPlane.Spec.PodIdentityProfile.UserAssignedIdentityExceptions {
+			managedClusterSpec.PodIdentityProfile.UserAssignedIdentityExceptions = append(managedClusterSpec.PodIdentityProfile.UserAssignedIdentityExceptions, managedclusters.PodIdentityException{
+				Name:      exception.Name,
+				Namespace: exception.Namespace,
+				PodLabels: exception.PodLabels,
+			})
+		}
+	}
+
 	return &managedClusterSpec
 }
 
+// MaintenanceConfigurationSpec returns the spec of the maintenance configuration of the managed cluster, or nil when
+// no maintenance window is set.
+func (s *ManagedControlPlaneScope) MaintenanceConfigurationSpec() azure.ResourceSpecGetter {
+	if s.ControlPlane.Spec.MaintenanceWindow == nil {
+		return nil
+	}
+	return &maintenanceconfigurations.MaintenanceConfigurationSpec{
+		Name:          maintenanceconfigurations.DefaultConfigName,
+		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
+		ClusterName:   s.ControlPlane.Name,
+		Window:        s.ControlPlane.Spec.MaintenanceWindow,
+	}
+}
+
+// autoScalerProfile converts an AutoScalerProfile to the autoscaler profile of a managed cluster.
+func autoScalerProfile(profile *infrav1.AutoScalerProfile) *managedclusters.AutoScalerProfile {
+	if profile == nil {
+		return nil
+	}
+	return &managedclusters.AutoScalerProfile{
+		BalanceSimilarNodeGroups:      (*string)(profile.BalanceSimilarNodeGroups),
+		Expander:                      (*string)(profile.Expander),
+		MaxEmptyBulkDelete:            profile.MaxEmptyBulkDelete,
+		MaxGracefulTerminationSec:     profile.MaxGracefulTerminationSec,
+		MaxNodeProvisionTime:          profile.MaxNodeProvisionTime,
+		MaxTotalUnreadyPercentage:     profile.MaxTotalUnreadyPercentage,
+		NewPodScaleUpDelay:            profile.NewPodScaleUpDelay,
+		OkTotalUnreadyCount:           profile.OkTotalUnreadyCount,
+		ScanInterval:                  profile.ScanInterval,
+		ScaleDownDelayAfterAdd:        profile.ScaleDownDelayAfterAdd,
+		ScaleDownDelayAfterDelete:     profile.ScaleDownDelayAfterDelete,
+		ScaleDownDelayAfterFailure:    profile.ScaleDownDelayAfterFailure,
+		ScaleDownUnneededTime:         profile.ScaleDownUnneededTime,
+		ScaleDownUnreadyTime:          profile.ScaleDownUnreadyTime,
+		ScaleDownUtilizationThreshold: profile.ScaleDownUtilizationThreshold,
+		SkipNodesWithLocalStorage:     (*string)(profile.SkipNodesWithLocalStorage),
+		SkipNodesWithSystemPods:       (*string)(profile.SkipNodesWithSystemPods),
+	}
+}
+
 // GetManagedClusterSecurityProfile gets the security profile for managed cluster.
 func (s *ManagedControlPlaneScope) getManagedClusterSecurityProfile() *managedclusters.ManagedClusterSecurityProfile {
 	securityProfile := &managedclusters.ManagedClusterSecurityProfile{}
@@ -796,9 +1014,37 @@ func (s *ManagedControlPlaneScope) MakeClusterCA() *corev1.Secret {
 	}
 }
 
+// MakeOIDCIssuerSecret returns an empty secret to store the OIDC issuer URL of the AKS cluster in, so tooling can read
+// it without parsing the AzureManagedControlPlane.
+func (s *ManagedControlPlaneScope) MakeOIDCIssuerSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-oidc-issuer", s.Cluster.Name),
+			Namespace: s.Cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s.ControlPlane, infrav1.GroupVersion.WithKind(infrav1.AzureManagedControlPlaneKind)),
+			},
+			Labels: map[string]string{clusterv1.ClusterNameLabel: s.Cluster.Name},
+		},
+	}
+}
+
+// OIDCIssuerURL returns the OIDC issuer URL of the AKS cluster, or an empty string when the issuer is not enabled.
+func (s *ManagedControlPlaneScope) OIDCIssuerURL() string {
+	if s.ControlPlane.Status.OIDCIssuerProfile == nil {
+		return ""
+	}
+	return ptr.Deref(s.ControlPlane.Status.OIDCIssuerProfile.IssuerURL, "")
+}
+
+// InvalidateRemoteClient removes the cached client of the AKS cluster.
+func (s *ManagedControlPlaneScope) InvalidateRemoteClient() {
+	GetRemoteClientCache().Invalidate(client.ObjectKeyFromObject(s.Cluster))
+}
+
 // StoreClusterInfo stores the discovery cluster-info configmap in the kube-public namespace on the AKS cluster so kubeadm can access it to join nodes.
 func (s *ManagedControlPlaneScope) StoreClusterInfo(ctx context.Context, caData []byte) error {
-	remoteclient, err := remote.NewClusterClient(ctx, managedControlPlaneScopeName, s.Client, types.NamespacedName{
+	remoteclient, err := GetRemoteClientCache().GetClient(ctx, s.Client, types.NamespacedName{
 		Namespace: s.Cluster.Namespace,
 		Name:      s.Cluster.Name,
 	})
@@ -869,6 +1115,11 @@ func (s *ManagedControlPlaneScope) UpdateDeleteStatus(condition clusterv1.Condit
 	}
 }
 
+// SetConditionFalse sets the specified AzureManagedControlPlane condition to false.
+func (s *ManagedControlPlaneScope) SetConditionFalse(conditionType clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string) {
+	conditions.MarkFalse(s.ControlPlane, conditionType, reason, severity, message)
+}
+
 // UpdatePutStatus updates a condition on the AzureManagedControlPlane status after a PUT operation.
 func (s *ManagedControlPlaneScope) UpdatePutStatus(condition clusterv1.ConditionType, service string, err error) {
 	switch {
@@ -986,11 +1237,72 @@ func (s *ManagedControlPlaneScope) PrivateEndpointSpecs() []azure.ASOResourceSpe
 	return privateEndpointSpecs
 }
 
+// SubnetRoleAssignmentSpecs returns the spec of the role assignment granting the given principal, i.e. the identity
+// of the control plane, the Network Contributor role on the subnet of the cluster when AutoGrantSubnetPermissions is
+// set.
+func (s *ManagedControlPlaneScope) SubnetRoleAssignmentSpecs(principalID string) []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment] {
+	if !ptr.Deref(s.ControlPlane.Spec.AutoGrantSubnetPermissions, false) {
+		return nil
+	}
+	return []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment]{
+		&subnetroleassignments.SubnetRoleAssignmentSpec{
+			Name: fmt.Sprintf("%s-subnet-network-contributor", s.ControlPlane.Name),
+			SubnetID: azure.SubnetID(
+				s.ControlPlane.Spec.SubscriptionID,
+				s.Vnet().ResourceGroup,
+				s.ControlPlane.Spec.VirtualNetwork.Name,
+				s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
+			),
+			RoleDefinitionID: fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.ControlPlane.Spec.SubscriptionID, infrav1.NetworkContributorRoleID),
+			PrincipalID:      principalID,
+		},
+	}
+}
+
+// APIServerDNSARecordSpecs returns no A record, since the record of the API server of a managed cluster is a CNAME.
+func (s *ManagedControlPlaneScope) APIServerDNSARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesARecord] {
+	return nil
+}
+
+// APIServerDNSAAAARecordSpecs returns no AAAA record, since the record of the API server of a managed cluster is a
+// CNAME.
+func (s *ManagedControlPlaneScope) APIServerDNSAAAARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesAAAARecord] {
+	return nil
+}
+
+// APIServerDNSCNAMERecordSpecs returns the CNAME record of the FQDN of the API server in an Azure DNS zone, once AKS
+// reported the FQDN in the control plane endpoint.
+func (s *ManagedControlPlaneScope) APIServerDNSCNAMERecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesCNAMERecord] {
+	apiServerDNS := s.ControlPlane.Spec.APIServerDNS
+	if apiServerDNS == nil || s.ControlPlane.Spec.ControlPlaneEndpoint.Host == "" {
+		return nil
+	}
+	var credentialSecret string
+	if zoneSubscriptionID := apiServerDNS.ZoneSubscriptionID(); zoneSubscriptionID != "" && !strings.EqualFold(zoneSubscriptionID, s.SubscriptionID()) {
+		credentialSecret = aso.GetASOSecretNameForSubscription(s.ClusterName(), zoneSubscriptionID)
+	}
+	return []azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesCNAMERecord]{
+		&dnsrecords.CNAMERecordSpec{
+			Name:             azure.GenerateAPIServerDNSRecordName(s.ClusterName()),
+			ZoneID:           apiServerDNS.ZoneResourceID,
+			RecordName:       apiServerDNS.RecordName,
+			TTL:              ptr.Deref(apiServerDNS.TTL, infrav1.DefaultAPIServerDNSTTL),
+			Target:           s.ControlPlane.Spec.ControlPlaneEndpoint.Host,
+			CredentialSecret: credentialSecret,
+		},
+	}
+}
+
 // SetOIDCIssuerProfileStatus sets the status for the OIDC issuer profile config.
 func (s *ManagedControlPlaneScope) SetOIDCIssuerProfileStatus(oidc *infrav1.OIDCIssuerProfileStatus) {
 	s.ControlPlane.Status.OIDCIssuerProfile = oidc
 }
 
+// SetAADProfileStatus sets the AAD integration in effect on the managed cluster.
+func (s *ManagedControlPlaneScope) SetAADProfileStatus(aad *infrav1.AADProfileStatus) {
+	s.ControlPlane.Status.AADProfile = aad
+}
+
 // AKSExtension returns the cluster AKS extensions.
 func (s *ManagedControlPlaneScope) AKSExtension() []infrav1.AKSExtension {
 	return s.ControlPlane.Spec.Extensions
@@ -19,11 +19,16 @@ package scope
 import (
 	"context"
 	"reflect"
+	"sync"
 	"testing"
 
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	asokubernetesconfigurationv1 "github.com/Azure/azure-service-operator/v2/api/kubernetesconfiguration/v1api20230501"
+	asonetworkv1api20180501 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
+	asonetworkv1api20201101 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,8 +38,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aksextensions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnetroleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -308,9 +316,10 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 	_ = corev1.AddToScheme(scheme)
 
 	cases := []struct {
-		Name     string
-		Input    ManagedControlPlaneScopeParams
-		Expected []managedclusters.AddonProfile
+		Name             string
+		Input            ManagedControlPlaneScopeParams
+		Expected         []managedclusters.AddonProfile
+		ExpectedPreserve bool
 	}{
 		{
 			Name: "Without add-ons",
@@ -387,6 +396,44 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 				{Name: "addon2", Config: map[string]string{"k1": "v1", "k2": "v2"}, Enabled: true},
 			},
 		},
+		{
+			Name: "Preserving unmanaged add-ons",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							infrav1.PreserveUnmanagedAddonsAnnotation: "true",
+						},
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID: "00000000-0000-0000-0000-000000000000",
+							IdentityRef: &corev1.ObjectReference{
+								Name:      "fake-identity",
+								Namespace: "default",
+								Kind:      "AzureClusterIdentity",
+							},
+						},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected:         nil,
+			ExpectedPreserve: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -413,6 +460,7 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 			g.Expect(err).To(Succeed())
 			managedCluster := s.ManagedClusterSpec()
 			g.Expect(managedCluster.(*managedclusters.ManagedClusterSpec).AddonProfiles).To(Equal(c.Expected))
+			g.Expect(managedCluster.(*managedclusters.ManagedClusterSpec).PreserveUnmanagedAddons).To(Equal(c.ExpectedPreserve))
 		})
 	}
 }
@@ -1603,6 +1651,42 @@ func TestManagedControlPlaneScope_AutoUpgradeProfile(t *testing.T) {
 				UpgradeChannel: ptr.To(infrav1.UpgradeChannelNodeImage),
 			},
 		},
+		{
+			name: "With AutoUpgradeProfile NodeOSUpgradeChannel",
+			input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID: "00000000-0000-0000-0000-000000000000",
+							AutoUpgradeProfile: &infrav1.ManagedClusterAutoUpgradeProfile{
+								UpgradeChannel:       ptr.To(infrav1.UpgradeChannelPatch),
+								NodeOSUpgradeChannel: ptr.To(infrav1.NodeOSUpgradeChannelNodeImage),
+							},
+						},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			expected: &managedclusters.ManagedClusterAutoUpgradeProfile{
+				UpgradeChannel:       ptr.To(infrav1.UpgradeChannelPatch),
+				NodeOSUpgradeChannel: ptr.To(infrav1.NodeOSUpgradeChannelNodeImage),
+			},
+		},
 	}
 	for _, c := range cases {
 		c := c
@@ -1619,3 +1703,445 @@ func TestManagedControlPlaneScope_AutoUpgradeProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestManagedControlPlaneScope_AutoScalerProfile(t *testing.T) {
+	cases := []struct {
+		name             string
+		profile          *infrav1.AutoScalerProfile
+		template         *infrav1.AzureManagedControlPlaneTemplate
+		expected         *managedclusters.AutoScalerProfile
+		expectedDefaults *managedclusters.AutoScalerProfile
+	}{
+		{
+			name: "Without AutoScalerProfile",
+		},
+		{
+			name:     "With AutoScalerProfile",
+			profile:  &infrav1.AutoScalerProfile{Expander: ptr.To(infrav1.ExpanderLeastWaste)},
+			expected: &managedclusters.AutoScalerProfile{Expander: ptr.To("least-waste")},
+		},
+		{
+			name:    "With AutoScalerProfile of the AzureManagedControlPlaneTemplate",
+			profile: &infrav1.AutoScalerProfile{Expander: ptr.To(infrav1.ExpanderLeastWaste)},
+			template: &infrav1.AzureManagedControlPlaneTemplate{
+				Spec: infrav1.AzureManagedControlPlaneTemplateSpec{
+					Template: infrav1.AzureManagedControlPlaneTemplateResource{
+						Spec: infrav1.AzureManagedControlPlaneTemplateResourceSpec{
+							AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+								AutoScalerProfile: &infrav1.AutoScalerProfile{
+									BalanceSimilarNodeGroups: ptr.To(infrav1.BalanceSimilarNodeGroupsTrue),
+									Expander:                 ptr.To(infrav1.ExpanderPriority),
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &managedclusters.AutoScalerProfile{Expander: ptr.To("least-waste")},
+			expectedDefaults: &managedclusters.AutoScalerProfile{
+				BalanceSimilarNodeGroups: ptr.To("true"),
+				Expander:                 ptr.To("priority"),
+			},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ManagedControlPlaneScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+							AutoScalerProfile: c.profile,
+						},
+					},
+				},
+				ControlPlaneTemplate: c.template,
+			}
+			managedCluster, ok := s.ManagedClusterSpec().(*managedclusters.ManagedClusterSpec)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(managedCluster.AutoScalerProfile).To(Equal(c.expected))
+			g.Expect(managedCluster.AutoScalerProfileDefaults).To(Equal(c.expectedDefaults))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_PodIdentityProfile(t *testing.T) {
+	cases := []struct {
+		name     string
+		profile  *infrav1.PodIdentityProfile
+		expected *managedclusters.PodIdentityProfile
+	}{
+		{
+			name: "Without PodIdentityProfile",
+		},
+		{
+			name: "With PodIdentityProfile",
+			profile: &infrav1.PodIdentityProfile{
+				Enabled: true,
+				UserAssignedIdentityExceptions: []infrav1.PodIdentityException{
+					{
+						Name:      "legacy-app",
+						Namespace: "legacy",
+						PodLabels: map[string]string{"app": "legacy-app"},
+					},
+				},
+			},
+			expected: &managedclusters.PodIdentityProfile{
+				Enabled: true,
+				UserAssignedIdentityExceptions: []managedclusters.PodIdentityException{
+					{
+						Name:      "legacy-app",
+						Namespace: "legacy",
+						PodLabels: map[string]string{"app": "legacy-app"},
+					},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ManagedControlPlaneScope{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID:     "00000000-0000-0000-0000-000000000000",
+							PodIdentityProfile: c.profile,
+						},
+					},
+				},
+			}
+			managedCluster, ok := s.ManagedClusterSpec().(*managedclusters.ManagedClusterSpec)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(managedCluster.PodIdentityProfile).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_ManagedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ManagedControlPlaneScope{ControlPlane: &infrav1.AzureManagedControlPlane{}}
+	s.RecordManagedResource("my-id")
+	g.Expect(s.IsOwnershipRecorded()).To(BeFalse())
+
+	s.InitManagedResources()
+	s.RecordManagedResource("my-id")
+	s.RecordManagedResource("MY-ID")
+	s.RecordManagedResource("other-id")
+	g.Expect(s.ControlPlane.Status.ManagedResources.IDs).To(Equal([]string{"my-id", "other-id"}))
+
+	// Recording resources doesn't affect the tag-based ownership of managed cluster resources.
+	g.Expect(s.IsManagedResource("my-id", false)).To(BeFalse())
+	g.Expect(s.IsManagedResource("unknown-id", true)).To(BeTrue())
+
+	s.ForgetManagedResource("My-Id")
+	g.Expect(s.ControlPlane.Status.ManagedResources.IDs).To(Equal([]string{"other-id"}))
+
+	// The recorded resources are kept.
+	s.InitManagedResources()
+	g.Expect(s.ControlPlane.Status.ManagedResources.IDs).To(Equal([]string{"other-id"}))
+}
+
+func TestManagedControlPlaneScope_SubnetSpecs(t *testing.T) {
+	g := NewWithT(t)
+	podPool := getAzureMachinePool("pool1", infrav1.NodePoolModeUser)
+	podPool.Spec.PodSubnetName = ptr.To("pods")
+	s := &ManagedControlPlaneScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: auth.EnvironmentSettings{
+				Values: map[string]string{
+					auth.SubscriptionID: "123",
+				},
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+					VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+						ResourceGroup: "vnet-rg",
+						ManagedControlPlaneVirtualNetworkClassSpec: infrav1.ManagedControlPlaneVirtualNetworkClassSpec{
+							Name:      "vnet",
+							CIDRBlock: "10.0.0.0/8",
+							Subnet: infrav1.ManagedControlPlaneSubnet{
+								Name:      "nodes",
+								CIDRBlock: "10.240.0.0/16",
+							},
+							Subnets: []infrav1.ManagedControlPlaneAdditionalSubnet{
+								{Name: "pods", CIDRBlock: "10.241.0.0/16"},
+								{Name: "anf", CIDRBlock: "10.242.0.0/28", Delegations: []string{infrav1.NetAppVolumesDelegation}},
+							},
+						},
+					},
+				},
+				ResourceGroupName: "rg",
+			},
+		},
+		ManagedMachinePools: []ManagedMachinePool{
+			{
+				MachinePool:      getMachinePool("pool0"),
+				InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+			},
+			{
+				MachinePool:      getMachinePool("pool1"),
+				InfraMachinePool: podPool,
+			},
+		},
+		cache: &ManagedControlPlaneCache{
+			isVnetManaged: ptr.To(true),
+		},
+	}
+
+	g.Expect(s.SubnetSpecs()).To(Equal([]azure.ASOResourceSpecGetter[*asonetworkv1api20201101.VirtualNetworksSubnet]{
+		&subnets.SubnetSpec{
+			Name:              "nodes",
+			ResourceGroup:     "rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"10.240.0.0/16"},
+			VNetName:          "vnet",
+			VNetResourceGroup: "vnet-rg",
+			IsVNetManaged:     true,
+		},
+		&subnets.SubnetSpec{
+			Name:              "pods",
+			ResourceGroup:     "rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"10.241.0.0/16"},
+			VNetName:          "vnet",
+			VNetResourceGroup: "vnet-rg",
+			IsVNetManaged:     true,
+			Delegations:       []string{subnets.ManagedClustersDelegation},
+		},
+		&subnets.SubnetSpec{
+			Name:              "anf",
+			ResourceGroup:     "rg",
+			SubscriptionID:    "123",
+			CIDRs:             []string{"10.242.0.0/28"},
+			VNetName:          "vnet",
+			VNetResourceGroup: "vnet-rg",
+			IsVNetManaged:     true,
+			Delegations:       []string{infrav1.NetAppVolumesDelegation},
+		},
+	}))
+}
+
+func TestManagedControlPlaneScope_SubnetRoleAssignmentSpecs(t *testing.T) {
+	controlPlane := func(autoGrant *bool) *infrav1.AzureManagedControlPlane {
+		return &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+					SubscriptionID: "123",
+					VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+						ResourceGroup: "vnet-rg",
+						ManagedControlPlaneVirtualNetworkClassSpec: infrav1.ManagedControlPlaneVirtualNetworkClassSpec{
+							Name: "vnet",
+							Subnet: infrav1.ManagedControlPlaneSubnet{
+								Name: "nodes",
+							},
+						},
+					},
+					AutoGrantSubnetPermissions: autoGrant,
+				},
+			},
+		}
+	}
+
+	t.Run("no role assignment unless autoGrantSubnetPermissions is set", func(t *testing.T) {
+		g := NewWithT(t)
+		s := &ManagedControlPlaneScope{ControlPlane: controlPlane(ptr.To(false))}
+		g.Expect(s.SubnetRoleAssignmentSpecs("principal")).To(BeEmpty())
+	})
+
+	t.Run("role assignment of Network Contributor on the subnet", func(t *testing.T) {
+		g := NewWithT(t)
+		s := &ManagedControlPlaneScope{ControlPlane: controlPlane(ptr.To(true))}
+		g.Expect(s.SubnetRoleAssignmentSpecs("principal")).To(Equal([]azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment]{
+			&subnetroleassignments.SubnetRoleAssignmentSpec{
+				Name:             "cluster-subnet-network-contributor",
+				SubnetID:         "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/nodes",
+				RoleDefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/" + infrav1.NetworkContributorRoleID,
+				PrincipalID:      "principal",
+			},
+		}))
+	})
+}
+
+func TestManagedControlPlaneScope_APIServerDNSCNAMERecordSpecs(t *testing.T) {
+	scope := func(zoneSubscriptionID, host string) *ManagedControlPlaneScope {
+		return &ManagedControlPlaneScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Values: map[string]string{
+						auth.SubscriptionID: "123",
+					},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+			},
+			ControlPlane: &infrav1.AzureManagedControlPlane{
+				Spec: infrav1.AzureManagedControlPlaneSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: host, Port: 443},
+					APIServerDNS: &infrav1.APIServerDNS{
+						ZoneResourceID: "/subscriptions/" + zoneSubscriptionID + "/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+						RecordName:     "api",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("no record until the FQDN of the API server is known", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope("123", "")
+		g.Expect(s.APIServerDNSCNAMERecordSpecs()).To(BeEmpty())
+	})
+
+	t.Run("CNAME record of the FQDN of the API server", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope("123", "my-cluster-dns.hcp.eastus.azmk8s.io")
+		g.Expect(s.APIServerDNSARecordSpecs()).To(BeEmpty())
+		g.Expect(s.APIServerDNSAAAARecordSpecs()).To(BeEmpty())
+		g.Expect(s.APIServerDNSCNAMERecordSpecs()).To(Equal([]azure.ASOResourceSpecGetter[*asonetworkv1api20180501.DnsZonesCNAMERecord]{
+			&dnsrecords.CNAMERecordSpec{
+				Name:       "my-cluster-apiserver",
+				ZoneID:     "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+				RecordName: "api",
+				TTL:        infrav1.DefaultAPIServerDNSTTL,
+				Target:     "my-cluster-dns.hcp.eastus.azmk8s.io",
+			},
+		}))
+	})
+
+	t.Run("CNAME record in a DNS zone of another subscription", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope("456", "my-cluster-dns.hcp.eastus.azmk8s.io")
+		specs := s.APIServerDNSCNAMERecordSpecs()
+		g.Expect(specs).To(HaveLen(1))
+		g.Expect(specs[0].(*dnsrecords.CNAMERecordSpec).CredentialSecret).To(Equal("my-cluster-aso-secret-456"))
+	})
+}
+
+func TestManagedControlPlaneScope_UserAssignedIdentityResourceID(t *testing.T) {
+	const identityID = "/subscriptions/123/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane"
+	tests := []struct {
+		name     string
+		identity *infrav1.Identity
+		expected string
+	}{
+		{
+			name: "default identity",
+		},
+		{
+			name: "system-assigned identity",
+			identity: &infrav1.Identity{
+				Type: infrav1.ManagedControlPlaneIdentityTypeSystemAssigned,
+			},
+		},
+		{
+			name: "user-assigned identity",
+			identity: &infrav1.Identity{
+				Type:                           infrav1.ManagedControlPlaneIdentityTypeUserAssigned,
+				UserAssignedIdentityResourceID: identityID,
+			},
+			expected: identityID,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ManagedControlPlaneScope{
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							Identity: tc.identity,
+						},
+					},
+				},
+			}
+			g.Expect(s.UserAssignedIdentityResourceID()).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_IsVnetManagedConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = asonetworkv1api20201101.AddToScheme(scheme)
+
+	vnet := &asonetworkv1api20201101.VirtualNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vnet",
+			Namespace: "default",
+		},
+		Status: asonetworkv1api20201101.VirtualNetwork_STATUS{
+			Tags: infrav1.Build(infrav1.BuildParams{
+				ClusterName: "cluster",
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+			}),
+		},
+	}
+	s := &ManagedControlPlaneScope{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(vnet).Build(),
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+					VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+						ManagedControlPlaneVirtualNetworkClassSpec: infrav1.ManagedControlPlaneVirtualNetworkClassSpec{
+							Name: "vnet",
+						},
+					},
+				},
+			},
+		},
+		cache: &ManagedControlPlaneCache{},
+	}
+
+	// The scope is shared between concurrently reconciling node pools, run with -race to check the cache.
+	var wg sync.WaitGroup
+	results := make([]bool, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = s.IsVnetManaged()
+		}(i)
+	}
+	wg.Wait()
+
+	g.Expect(results).To(HaveEach(BeTrue()))
+}
@@ -19,10 +19,16 @@ package scope
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -45,6 +51,7 @@ type ManagedMachinePoolScopeParams struct {
 	Cluster                  *clusterv1.Cluster
 	ControlPlane             *infrav1.AzureManagedControlPlane
 	ManagedControlPlaneScope azure.ManagedClusterScoper
+	Recorder                 record.EventRecorder
 }
 
 // ManagedMachinePool defines the scope interface for a managed machine pool.
@@ -86,6 +93,7 @@ func NewManagedMachinePoolScope(ctx context.Context, params ManagedMachinePoolSc
 		patchHelper:                helper,
 		capiMachinePoolPatchHelper: capiMachinePoolPatchHelper,
 		ManagedClusterScoper:       params.ManagedControlPlaneScope,
+		recorder:                   params.Recorder,
 	}, nil
 }
 
@@ -94,6 +102,7 @@ type ManagedMachinePoolScope struct {
 	Client                     client.Client
 	patchHelper                *patch.Helper
 	capiMachinePoolPatchHelper *patch.Helper
+	recorder                   record.EventRecorder
 
 	azure.ManagedClusterScoper
 	Cluster          *clusterv1.Cluster
@@ -150,6 +159,19 @@ func (s *ManagedMachinePoolScope) AgentPoolSpec() azure.ASOResourceSpecGetter[*a
 	return buildAgentPoolSpec(s.ControlPlane, s.MachinePool, s.InfraMachinePool)
 }
 
+// AgentPoolScaleSetTags returns the tags of the scale set backing the agent pool, which attribute its nodes to the
+// cluster and the machine pool.
+func (s *ManagedMachinePoolScope) AgentPoolScaleSetTags() infrav1.Tags {
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.Cluster.Name,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Additional:  s.ControlPlane.Spec.AdditionalTags,
+	})
+	tags.Merge(s.InfraMachinePool.Spec.AdditionalTags)
+	tags[infrav1.MachinePoolNameTagKey()] = s.MachinePool.Name
+	return tags
+}
+
 func getAgentPoolSubnet(controlPlane *infrav1.AzureManagedControlPlane, infraMachinePool *infrav1.AzureManagedMachinePool) *string {
 	if infraMachinePool.Spec.SubnetName == nil {
 		return ptr.To(controlPlane.Spec.VirtualNetwork.Subnet.Name)
@@ -176,33 +198,53 @@ func buildAgentPoolSpec(managedControlPlane *infrav1.AzureManagedControlPlane,
 		Replicas:      int(replicas),
 		Version:       normalizedVersion,
 		OSType:        managedMachinePool.Spec.OSType,
+		OSSKU:         managedMachinePool.Spec.OSSKU,
 		VnetSubnetID: azure.SubnetID(
 			managedControlPlane.Spec.SubscriptionID,
 			managedControlPlane.Spec.VirtualNetwork.ResourceGroup,
 			managedControlPlane.Spec.VirtualNetwork.Name,
 			ptr.Deref(getAgentPoolSubnet(managedControlPlane, managedMachinePool), ""),
 		),
-		Mode:                   managedMachinePool.Spec.Mode,
-		MaxPods:                managedMachinePool.Spec.MaxPods,
-		AvailabilityZones:      managedMachinePool.Spec.AvailabilityZones,
-		OsDiskType:             managedMachinePool.Spec.OsDiskType,
-		EnableUltraSSD:         managedMachinePool.Spec.EnableUltraSSD,
-		EnableNodePublicIP:     managedMachinePool.Spec.EnableNodePublicIP,
-		NodePublicIPPrefixID:   ptr.Deref(managedMachinePool.Spec.NodePublicIPPrefixID, ""),
-		ScaleSetPriority:       managedMachinePool.Spec.ScaleSetPriority,
-		ScaleDownMode:          managedMachinePool.Spec.ScaleDownMode,
-		SpotMaxPrice:           managedMachinePool.Spec.SpotMaxPrice,
-		AdditionalTags:         managedMachinePool.Spec.AdditionalTags,
-		KubeletDiskType:        managedMachinePool.Spec.KubeletDiskType,
-		LinuxOSConfig:          managedMachinePool.Spec.LinuxOSConfig,
-		EnableFIPS:             managedMachinePool.Spec.EnableFIPS,
-		EnableEncryptionAtHost: managedMachinePool.Spec.EnableEncryptionAtHost,
+		Mode:                       managedMachinePool.Spec.Mode,
+		MaxPods:                    managedMachinePool.Spec.MaxPods,
+		AvailabilityZones:          managedMachinePool.Spec.AvailabilityZones,
+		OsDiskType:                 managedMachinePool.Spec.OsDiskType,
+		EnableUltraSSD:             managedMachinePool.Spec.EnableUltraSSD,
+		EnableNodePublicIP:         managedMachinePool.Spec.EnableNodePublicIP,
+		NodePublicIPPrefixID:       ptr.Deref(managedMachinePool.Spec.NodePublicIPPrefixID, ""),
+		ScaleSetPriority:           managedMachinePool.Spec.ScaleSetPriority,
+		ScaleDownMode:              managedMachinePool.Spec.ScaleDownMode,
+		SpotMaxPrice:               managedMachinePool.Spec.SpotMaxPrice,
+		AdditionalTags:             managedMachinePool.Spec.AdditionalTags,
+		KubeletDiskType:            managedMachinePool.Spec.KubeletDiskType,
+		LinuxOSConfig:              managedMachinePool.Spec.LinuxOSConfig,
+		EnableFIPS:                 managedMachinePool.Spec.EnableFIPS,
+		EnableEncryptionAtHost:     managedMachinePool.Spec.EnableEncryptionAtHost,
+		ProximityPlacementGroupID:  ptr.Deref(managedMachinePool.Spec.ProximityPlacementGroupID, ""),
+		CapacityReservationGroupID: ptr.Deref(managedMachinePool.Spec.CapacityReservationGroupID, ""),
+		NodePoolSnapshotID:         ptr.Deref(managedMachinePool.Spec.NodePoolSnapshotID, ""),
+	}
+
+	if podSubnetName := ptr.Deref(managedMachinePool.Spec.PodSubnetName, ""); podSubnetName != "" {
+		agentPoolSpec.PodSubnetID = podSubnetName
+		if !infrav1.IsSubnetID(podSubnetName) {
+			agentPoolSpec.PodSubnetID = azure.SubnetID(
+				managedControlPlane.Spec.SubscriptionID,
+				managedControlPlane.Spec.VirtualNetwork.ResourceGroup,
+				managedControlPlane.Spec.VirtualNetwork.Name,
+				podSubnetName,
+			)
+		}
 	}
 
 	if managedMachinePool.Spec.OSDiskSizeGB != nil {
 		agentPoolSpec.OSDiskSizeGB = *managedMachinePool.Spec.OSDiskSizeGB
 	}
 
+	if managedMachinePool.Spec.NetworkProfile != nil {
+		agentPoolSpec.NodePublicIPTags = managedMachinePool.Spec.NetworkProfile.NodePublicIPTags
+	}
+
 	if len(managedMachinePool.Spec.Taints) > 0 {
 		nodeTaints := make([]string, 0, len(managedMachinePool.Spec.Taints))
 		for _, t := range managedMachinePool.Spec.Taints {
@@ -257,6 +299,23 @@ func (s *ManagedMachinePoolScope) SetAgentPoolReady(ready bool) {
 	s.InfraMachinePool.Status.Ready = ready
 }
 
+// SetAgentPoolScaleDownMode sets the scale down mode reported for the agent pool.
+func (s *ManagedMachinePoolScope) SetAgentPoolScaleDownMode(scaleDownMode *string) {
+	s.InfraMachinePool.Status.ScaleDownMode = scaleDownMode
+}
+
+// SetAgentPoolNodeImageVersion sets the node image version reported for the agent pool. An event is emitted when it
+// differs from the version observed previously, so that node image upgrades can be correlated with disruptions.
+func (s *ManagedMachinePoolScope) SetAgentPoolNodeImageVersion(nodeImageVersion *string) {
+	previous := s.InfraMachinePool.Status.NodeImageVersion
+	if previous != nil && nodeImageVersion != nil && *previous != *nodeImageVersion && s.recorder != nil {
+		s.recorder.Eventf(s.InfraMachinePool, corev1.EventTypeNormal, "NodeImageVersionChanged", "Node image version changed from %s to %s", *previous, *nodeImageVersion)
+	}
+	if nodeImageVersion != nil {
+		s.InfraMachinePool.Status.NodeImageVersion = nodeImageVersion
+	}
+}
+
 // SetLongRunningOperationState will set the future on the AzureManagedMachinePool status to allow the resource to continue
 // in the next reconciliation.
 func (s *ManagedMachinePoolScope) SetLongRunningOperationState(future *infrav1.Future) {
@@ -345,6 +404,66 @@ func (s *ManagedMachinePoolScope) GetCAPIMachinePoolAnnotation(key string) (succ
 	return ok, val
 }
 
+// agentPoolNodeLabel is the label AKS sets on the nodes of an agent pool to the name of the agent pool.
+const agentPoolNodeLabel = "kubernetes.azure.com/agentpool"
+
+// PodDisruptionBudgetsBlockingDrain returns the namespaced names of the workload cluster's PodDisruptionBudgets which
+// don't currently allow any disruption and select pods running on the nodes of the agent pool, and so block the drain
+// of its nodes.
+func (s *ManagedMachinePoolScope) PodDisruptionBudgetsBlockingDrain(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedMachinePoolScope.PodDisruptionBudgetsBlockingDrain")
+	defer done()
+
+	workloadClient, err := getWorkloadClient(ctx, s.Client, client.ObjectKeyFromObject(s.Cluster))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the workload cluster client")
+	}
+	return podDisruptionBudgetsBlockingDrain(ctx, workloadClient, ptr.Deref(s.InfraMachinePool.Spec.Name, s.InfraMachinePool.Name))
+}
+
+func podDisruptionBudgetsBlockingDrain(ctx context.Context, workloadClient client.Client, agentPoolName string) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	if err := workloadClient.List(ctx, nodes, client.MatchingLabels{agentPoolNodeLabel: agentPoolName}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the nodes of the agent pool")
+	}
+	podsByNamespace := map[string][]corev1.Pod{}
+	for _, node := range nodes.Items {
+		pods := &corev1.PodList{}
+		if err := workloadClient.List(ctx, pods, client.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list the pods of node %s", node.Name)
+		}
+		for _, pod := range pods.Items {
+			podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+		}
+	}
+
+	var blocking []string
+	for namespace, pods := range podsByNamespace {
+		pdbs := &policyv1.PodDisruptionBudgetList{}
+		if err := workloadClient.List(ctx, pdbs, client.InNamespace(namespace)); err != nil {
+			return nil, errors.Wrapf(err, "failed to list the PodDisruptionBudgets of namespace %s", namespace)
+		}
+		for _, pdb := range pdbs.Items {
+			if pdb.Status.DisruptionsAllowed > 0 || pdb.Status.ExpectedPods == 0 {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() {
+				// PodDisruptionBudgets with an invalid or empty selector don't select any pod.
+				continue
+			}
+			for _, pod := range pods {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					blocking = append(blocking, pdb.Namespace+"/"+pdb.Name)
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(blocking)
+	return blocking, nil
+}
+
 func getManagedMachinePoolVersion(managedControlPlane *infrav1.AzureManagedControlPlane, machinePool *expv1.MachinePool) *string {
 	var v, av string
 	if machinePool != nil {
@@ -24,14 +24,18 @@ import (
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -343,6 +347,37 @@ func TestManagedMachinePoolScope_AdditionalTags(t *testing.T) {
 	}
 }
 
+func TestManagedMachinePoolScope_AgentPoolScaleSetTags(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ManagedMachinePoolScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+					AdditionalTags: infrav1.Tags{
+						"environment": "staging",
+						"team":        "platform",
+					},
+				},
+			},
+		},
+		MachinePool: getMachinePool("pool1"),
+		InfraMachinePool: getAzureMachinePoolWithAdditionalTags("pool1", map[string]string{
+			"environment": "production",
+		}),
+	}
+
+	g.Expect(s.AgentPoolScaleSetTags()).To(Equal(infrav1.Tags{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster1": "owned",
+		"sigs.k8s.io_cluster-api-provider-azure_machinepool":      "pool1",
+		"environment": "production",
+		"team":        "platform",
+	}))
+}
+
 func TestManagedMachinePoolScope_MaxPods(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = expv1.AddToScheme(scheme)
@@ -780,6 +815,96 @@ func TestManagedMachinePoolScope_SubnetName(t *testing.T) {
 				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-resource-group/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
 			},
 		},
+		{
+			Name: "With PodSubnetName",
+			Input: ManagedMachinePoolScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID: "00000000-0000-0000-0000-000000000000",
+							VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+								ManagedControlPlaneVirtualNetworkClassSpec: infrav1.ManagedControlPlaneVirtualNetworkClassSpec{
+									Name: "my-vnet",
+									Subnet: infrav1.ManagedControlPlaneSubnet{
+										Name: "my-vnet-subnet",
+									},
+								},
+								ResourceGroup: "my-resource-group",
+							},
+						},
+					},
+				},
+				ManagedMachinePool: ManagedMachinePool{
+					MachinePool:      getMachinePool("pool1"),
+					InfraMachinePool: getAzureMachinePoolWithPodSubnetName("pool1", ptr.To("my-pod-subnet")),
+				},
+			},
+			Expected: &agentpools.AgentPoolSpec{
+				Name:         "pool1",
+				AzureName:    "pool1",
+				SKU:          "Standard_D2s_v3",
+				Mode:         "User",
+				Cluster:      "cluster1",
+				Replicas:     1,
+				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-resource-group/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-vnet-subnet",
+				PodSubnetID:  "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-resource-group/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-pod-subnet",
+			},
+		},
+		{
+			Name: "With pod subnet resource ID",
+			Input: ManagedMachinePoolScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						AzureManagedControlPlaneClassSpec: infrav1.AzureManagedControlPlaneClassSpec{
+							SubscriptionID: "00000000-0000-0000-0000-000000000000",
+							VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+								ManagedControlPlaneVirtualNetworkClassSpec: infrav1.ManagedControlPlaneVirtualNetworkClassSpec{
+									Name: "my-vnet",
+									Subnet: infrav1.ManagedControlPlaneSubnet{
+										Name: "my-vnet-subnet",
+									},
+								},
+								ResourceGroup: "my-resource-group",
+							},
+						},
+					},
+				},
+				ManagedMachinePool: ManagedMachinePool{
+					MachinePool:      getMachinePool("pool1"),
+					InfraMachinePool: getAzureMachinePoolWithPodSubnetName("pool1", ptr.To("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/other-resource-group/providers/Microsoft.Network/virtualNetworks/other-vnet/subnets/pods")),
+				},
+			},
+			Expected: &agentpools.AgentPoolSpec{
+				Name:         "pool1",
+				AzureName:    "pool1",
+				SKU:          "Standard_D2s_v3",
+				Mode:         "User",
+				Cluster:      "cluster1",
+				Replicas:     1,
+				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-resource-group/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-vnet-subnet",
+				PodSubnetID:  "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/other-resource-group/providers/Microsoft.Network/virtualNetworks/other-vnet/subnets/pods",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -898,6 +1023,147 @@ func TestManagedMachinePoolScope_KubeletDiskType(t *testing.T) {
 	}
 }
 
+func TestManagedMachinePoolScope_SetAgentPoolNodeImageVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		previous       *string
+		observed       *string
+		expected       *string
+		expectedEvents int
+	}{
+		{
+			name:     "first observed version",
+			observed: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+			expected: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+		},
+		{
+			name:     "unchanged version",
+			previous: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+			observed: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+			expected: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+		},
+		{
+			name:           "changed version",
+			previous:       ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+			observed:       ptr.To("AKSUbuntu-2204gen2containerd-202405.03.0"),
+			expected:       ptr.To("AKSUbuntu-2204gen2containerd-202405.03.0"),
+			expectedEvents: 1,
+		},
+		{
+			name:     "version not reported",
+			previous: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+			expected: ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(10)
+			s := &ManagedMachinePoolScope{
+				InfraMachinePool: &infrav1.AzureManagedMachinePool{
+					Status: infrav1.AzureManagedMachinePoolStatus{NodeImageVersion: tt.previous},
+				},
+				recorder: recorder,
+			}
+
+			s.SetAgentPoolNodeImageVersion(tt.observed)
+
+			g.Expect(s.InfraMachinePool.Status.NodeImageVersion).To(Equal(tt.expected))
+			g.Expect(recorder.Events).To(HaveLen(tt.expectedEvents))
+			if tt.expectedEvents > 0 {
+				g.Expect(<-recorder.Events).To(ContainSubstring("NodeImageVersionChanged"))
+			}
+		})
+	}
+}
+
+func Test_podDisruptionBudgetsBlockingDrain(t *testing.T) {
+	pdb := func(namespace, name, app string, expectedPods, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				ExpectedPods:       expectedPods,
+				DisruptionsAllowed: disruptionsAllowed,
+			},
+		}
+	}
+	pod := func(namespace, name, app, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	nodes := []runtime.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pool0-node", Labels: map[string]string{agentPoolNodeLabel: "pool0"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pool1-node", Labels: map[string]string{agentPoolNodeLabel: "pool1"}}},
+	}
+	cases := []struct {
+		name     string
+		objects  []runtime.Object
+		expected []string
+	}{
+		{
+			name:     "no PodDisruptionBudgets",
+			objects:  []runtime.Object{pod("default", "web-0", "web", "pool0-node")},
+			expected: nil,
+		},
+		{
+			name: "PodDisruptionBudgets allowing disruptions",
+			objects: []runtime.Object{
+				pod("default", "web-0", "web", "pool0-node"),
+				pdb("default", "web", "web", 3, 1),
+			},
+			expected: nil,
+		},
+		{
+			name: "PodDisruptionBudget without pods",
+			objects: []runtime.Object{
+				pod("default", "web-0", "web", "pool0-node"),
+				pdb("default", "web", "web", 0, 0),
+			},
+			expected: nil,
+		},
+		{
+			name: "PodDisruptionBudgets blocking disruptions of pods on the agent pool",
+			objects: []runtime.Object{
+				pod("monitoring", "prometheus-0", "prometheus", "pool0-node"),
+				pod("apps", "db-0", "db", "pool0-node"),
+				pod("apps", "cache-0", "cache", "pool1-node"),
+				pod("other", "queue-0", "queue", "pool1-node"),
+				pdb("monitoring", "prometheus", "prometheus", 1, 0),
+				pdb("apps", "db", "db", 2, 0),
+				pdb("apps", "cache", "cache", 1, 0),
+				pdb("other", "queue", "queue", 1, 0),
+			},
+			expected: []string{"apps/db", "monitoring/prometheus"},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(policyv1.AddToScheme(scheme)).To(Succeed())
+			workloadClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(append(c.objects, nodes...)...).
+				WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+					return []string{o.(*corev1.Pod).Spec.NodeName}
+				}).
+				Build()
+
+			pdbs, err := podDisruptionBudgetsBlockingDrain(context.Background(), workloadClient, "pool0")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pdbs).To(Equal(c.expected))
+		})
+	}
+}
+
 func Test_getManagedMachinePoolVersion(t *testing.T) {
 	cases := []struct {
 		name                string
@@ -1067,6 +1333,12 @@ func getAzureMachinePoolWithSubnetName(name string, subnetName *string) *infrav1
 	return managedPool
 }
 
+func getAzureMachinePoolWithPodSubnetName(name string, podSubnetName *string) *infrav1.AzureManagedMachinePool {
+	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
+	managedPool.Spec.PodSubnetName = podSubnetName
+	return managedPool
+}
+
 func getAzureMachinePoolWithOsDiskType(name string, osDiskType string) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
 	managedPool.Spec.OsDiskType = ptr.To(osDiskType)
@@ -85,3 +85,17 @@ func (mr *MocknodeGetterMockRecorder) GetNodeByProviderID(ctx, providerID any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeByProviderID", reflect.TypeOf((*MocknodeGetter)(nil).GetNodeByProviderID), ctx, providerID)
 }
+
+// PatchNode mocks base method.
+func (m *MocknodeGetter) PatchNode(ctx context.Context, original, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchNode", ctx, original, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchNode indicates an expected call of PatchNode.
+func (mr *MocknodeGetterMockRecorder) PatchNode(ctx, original, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchNode", reflect.TypeOf((*MocknodeGetter)(nil).PatchNode), ctx, original, node)
+}
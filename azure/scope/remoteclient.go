@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// remoteClientSourceName is the user agent of the workload cluster clients.
+const remoteClientSourceName = "capz-remote-client"
+
+// RemoteClientCache caches the clients of workload clusters so that reconcilers don't create a new client, which
+// requires discovering the API of the workload cluster, on every reconcile. A client is reused as long as the
+// resource version of the kubeconfig secret of its cluster doesn't change.
+type RemoteClientCache struct {
+	mu      sync.Mutex
+	clients map[client.ObjectKey]remoteClientEntry
+
+	// newClient creates a workload cluster client, it is only overridden for testing purposes.
+	newClient func(ctx context.Context, c client.Client, cluster client.ObjectKey) (client.Client, error)
+}
+
+type remoteClientEntry struct {
+	client          client.Client
+	resourceVersion string
+}
+
+var (
+	remoteClientCacheOnce sync.Once
+	remoteClientCache     *RemoteClientCache
+)
+
+// NewRemoteClientCache creates a new workload cluster client cache.
+func NewRemoteClientCache() *RemoteClientCache {
+	return &RemoteClientCache{
+		clients: map[client.ObjectKey]remoteClientEntry{},
+		newClient: func(ctx context.Context, c client.Client, cluster client.ObjectKey) (client.Client, error) {
+			return remote.NewClusterClient(ctx, remoteClientSourceName, c, cluster)
+		},
+	}
+}
+
+// GetRemoteClientCache either creates a new workload cluster client cache or returns the existing one.
+func GetRemoteClientCache() *RemoteClientCache {
+	remoteClientCacheOnce.Do(func() {
+		remoteClientCache = NewRemoteClientCache()
+	})
+	return remoteClientCache
+}
+
+// GetClient returns a client of the workload cluster. The cached client of the cluster is returned unless its
+// kubeconfig secret has changed since the client was created.
+func (c *RemoteClientCache) GetClient(ctx context.Context, mgmtClient client.Client, cluster client.ObjectKey) (client.Client, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.RemoteClientCache.GetClient")
+	defer done()
+
+	kubeconfig := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: secret.Name(cluster.Name, secret.Kubeconfig)}
+	if err := mgmtClient.Get(ctx, key, kubeconfig); err != nil {
+		c.Invalidate(cluster)
+		return nil, errors.Wrapf(err, "failed to get kubeconfig secret %s", key.Name)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.clients[cluster]
+	c.mu.Unlock()
+	if ok && entry.resourceVersion == kubeconfig.ResourceVersion {
+		return entry.client, nil
+	}
+
+	// The client is created without holding the lock so that a slow or unreachable workload cluster doesn't hold up
+	// the reconciles of other clusters.
+	remoteClient, err := c.newClient(ctx, mgmtClient, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients[cluster] = remoteClientEntry{
+		client:          remoteClient,
+		resourceVersion: kubeconfig.ResourceVersion,
+	}
+	return remoteClient, nil
+}
+
+// Invalidate removes the cached client of a workload cluster, e.g. when the cluster is paused or deleted.
+func (c *RemoteClientCache) Invalidate(cluster client.ObjectKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, cluster)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeRemoteClient identifies the workload cluster a client was created for.
+type fakeRemoteClient struct {
+	client.Client
+	cluster client.ObjectKey
+}
+
+func newFakeRemoteClientCache() (*RemoteClientCache, *int64) {
+	var created int64
+	cache := NewRemoteClientCache()
+	cache.newClient = func(_ context.Context, _ client.Client, cluster client.ObjectKey) (client.Client, error) {
+		atomic.AddInt64(&created, 1)
+		return &fakeRemoteClient{cluster: cluster}, nil
+	}
+	return cache, &created
+}
+
+func newKubeconfigSecret(cluster client.ObjectKey) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      secret.Name(cluster.Name, secret.Kubeconfig),
+		},
+		Data: map[string][]byte{
+			secret.KubeconfigDataName: []byte("kubeconfig"),
+		},
+	}
+}
+
+func TestRemoteClientCache_GetClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	NewWithT(t).Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	cluster := client.ObjectKey{Namespace: "default", Name: "my-cluster"}
+
+	t.Run("reuses the client while the kubeconfig is unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+		mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newKubeconfigSecret(cluster)).Build()
+		cache, created := newFakeRemoteClientCache()
+
+		first, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		second, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(second).To(BeIdenticalTo(first))
+		g.Expect(*created).To(BeEquivalentTo(1))
+	})
+
+	t.Run("creates a new client when the kubeconfig changes", func(t *testing.T) {
+		g := NewWithT(t)
+		kubeconfig := newKubeconfigSecret(cluster)
+		mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kubeconfig).Build()
+		cache, created := newFakeRemoteClientCache()
+
+		first, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(mgmtClient.Get(context.Background(), client.ObjectKeyFromObject(kubeconfig), kubeconfig)).To(Succeed())
+		kubeconfig.Data[secret.KubeconfigDataName] = []byte("rotated")
+		g.Expect(mgmtClient.Update(context.Background(), kubeconfig)).To(Succeed())
+
+		second, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(second).NotTo(BeIdenticalTo(first))
+		g.Expect(*created).To(BeEquivalentTo(2))
+	})
+
+	t.Run("creates a new client after it is invalidated", func(t *testing.T) {
+		g := NewWithT(t)
+		mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newKubeconfigSecret(cluster)).Build()
+		cache, created := newFakeRemoteClientCache()
+
+		first, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		cache.Invalidate(cluster)
+		second, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(second).NotTo(BeIdenticalTo(first))
+		g.Expect(*created).To(BeEquivalentTo(2))
+	})
+
+	t.Run("drops the client when the kubeconfig is gone", func(t *testing.T) {
+		g := NewWithT(t)
+		kubeconfig := newKubeconfigSecret(cluster)
+		mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kubeconfig).Build()
+		cache, _ := newFakeRemoteClientCache()
+
+		_, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtClient.Delete(context.Background(), kubeconfig)).To(Succeed())
+
+		_, err = cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(cache.clients).NotTo(HaveKey(cluster))
+	})
+
+	t.Run("doesn't share clients between clusters", func(t *testing.T) {
+		g := NewWithT(t)
+		other := client.ObjectKey{Namespace: "other", Name: cluster.Name}
+		mgmtClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newKubeconfigSecret(cluster), newKubeconfigSecret(other)).Build()
+		cache, _ := newFakeRemoteClientCache()
+
+		c, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(c.(*fakeRemoteClient).cluster).To(Equal(cluster))
+		c, err = cache.GetClient(context.Background(), mgmtClient, other)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(c.(*fakeRemoteClient).cluster).To(Equal(other))
+
+		cache.Invalidate(other)
+		g.Expect(cache.clients).To(HaveKey(cluster))
+	})
+}
+
+func TestRemoteClientCache_GetClientConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	var clusters []client.ObjectKey
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := 0; i < 10; i++ {
+		cluster := client.ObjectKey{Namespace: fmt.Sprintf("ns-%d", i%2), Name: fmt.Sprintf("cluster-%d", i)}
+		clusters = append(clusters, cluster)
+		builder = builder.WithObjects(newKubeconfigSecret(cluster))
+	}
+	mgmtClient := builder.Build()
+	cache, _ := newFakeRemoteClientCache()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(clusters)*20)
+	for i := 0; i < 20; i++ {
+		for _, cluster := range clusters {
+			wg.Add(1)
+			go func(cluster client.ObjectKey, invalidate bool) {
+				defer wg.Done()
+				if invalidate {
+					cache.Invalidate(cluster)
+				}
+				c, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got := c.(*fakeRemoteClient).cluster; got != cluster {
+					errs <- fmt.Errorf("got the client of cluster %s for cluster %s", got, cluster)
+				}
+			}(cluster, i%5 == 0)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, cluster := range clusters {
+		c, err := cache.GetClient(context.Background(), mgmtClient, cluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(c.(*fakeRemoteClient).cluster).To(Equal(cluster))
+	}
+}
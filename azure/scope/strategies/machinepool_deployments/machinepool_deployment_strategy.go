@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -132,6 +133,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		failedMachines             = order(getFailedMachines(machinesByProviderID))
 		deletingMachines           = order(getDeletingMachines(machinesByProviderID))
 		readyMachines              = order(getReadyMachines(machinesByProviderID))
+		scaleInCandidates          = getMachinesNotProtectedFromScaleIn(readyMachines)
 		machinesWithoutLatestModel = order(getMachinesWithoutLatestModel(machinesByProviderID))
 		overProvisionCount         = len(readyMachines) - int(desiredReplicaCount)
 		disruptionBudget           = func() int {
@@ -148,6 +150,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 	failedMachines = orderByDeleteMachineAnnotation(failedMachines)
 	deletingMachines = orderByDeleteMachineAnnotation(deletingMachines)
 	readyMachines = orderByDeleteMachineAnnotation(readyMachines)
+	scaleInCandidates = orderByDeleteMachineAnnotation(scaleInCandidates)
 	machinesWithoutLatestModel = orderByDeleteMachineAnnotation(machinesWithoutLatestModel)
 
 	log.Info("selecting machines to delete",
@@ -172,9 +175,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
 	}
 
-	// we have too many machines, let's choose the oldest to remove
+	// we have too many machines, let's choose the oldest to remove, leaving out the machines protected from scale-in
 	if overProvisionCount > 0 {
 		var toDelete []infrav1exp.AzureMachinePoolMachine
+		machinesWithoutLatestModel := getMachinesNotProtectedFromScaleIn(machinesWithoutLatestModel)
 		log.Info("over-provisioned", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
 		// we are over-provisioned try to remove old models
 		for _, v := range machinesWithoutLatestModel {
@@ -185,9 +189,9 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 			toDelete = append(toDelete, v)
 		}
 
-		log.Info("over-provisioned ready", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "readyMachines", getProviderIDs(readyMachines))
+		log.Info("over-provisioned ready", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "scaleInCandidates", getProviderIDs(scaleInCandidates))
 		// remove ready machines
-		for _, v := range readyMachines {
+		for _, v := range scaleInCandidates {
 			if len(toDelete) >= overProvisionCount {
 				return toDelete, nil
 			}
@@ -195,6 +199,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 			toDelete = append(toDelete, v)
 		}
 
+		if len(toDelete) < overProvisionCount {
+			log.Info("not scaling in machines protected from scale-in", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "toDelete", getProviderIDs(toDelete))
+		}
+
 		return toDelete, nil
 	}
 
@@ -270,6 +278,20 @@ func getReadyMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoo
 	return readyMachines
 }
 
+// getMachinesNotProtectedFromScaleIn returns the machines which can be removed when the machine pool scales in, i.e.
+// those whose instance isn't protected from scale-in, or which are marked for deletion anyway.
+func getMachinesNotProtectedFromScaleIn(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
+	var candidates []infrav1exp.AzureMachinePoolMachine
+	for _, v := range machines {
+		_, markedForDeletion := v.Annotations[clusterv1.DeleteMachineAnnotation]
+		if markedForDeletion || v.Annotations[azure.ProtectedFromScaleInAnnotation] != "true" {
+			candidates = append(candidates, v)
+		}
+	}
+
+	return candidates
+}
+
 func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var machinesWithLatestModel []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomega"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -41,8 +42,11 @@ func TestMachinePoolRollingUpdateStrategy_Type(t *testing.T) {
 
 func TestMachinePoolRollingUpdateStrategy_Surge(t *testing.T) {
 	var (
-		two           = intstr.FromInt(2)
-		twentyPercent = intstr.FromString("20%")
+		zero           = intstr.FromInt(0)
+		two            = intstr.FromInt(2)
+		zeroPercent    = intstr.FromString("0%")
+		twentyPercent  = intstr.FromString("20%")
+		hundredPercent = intstr.FromString("100%")
 	)
 
 	tests := []struct {
@@ -86,6 +90,56 @@ func TestMachinePoolRollingUpdateStrategy_Surge(t *testing.T) {
 			desiredReplicas: 21,
 			want:            5,
 		},
+		{
+			name: "MaxSurge is set to 0",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &zero,
+				},
+			},
+			desiredReplicas: 3,
+			want:            0,
+		},
+		{
+			name: "MaxSurge is set to 0%",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &zeroPercent,
+				},
+			},
+			desiredReplicas: 3,
+			want:            0,
+		},
+		{
+			name: "MaxSurge is set to 20% and desiredReplicas is 1; rounds up to a single machine",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &twentyPercent,
+				},
+			},
+			desiredReplicas: 1,
+			want:            1,
+		},
+		{
+			name: "MaxSurge is set to 20% and desiredReplicas is 0",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &twentyPercent,
+				},
+			},
+			desiredReplicas: 0,
+			want:            0,
+		},
+		{
+			name: "MaxSurge is set to 100% and desiredReplicas is 2",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxSurge: &hundredPercent,
+				},
+			},
+			desiredReplicas: 2,
+			want:            2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -104,7 +158,9 @@ func TestMachinePoolRollingUpdateStrategy_Surge(t *testing.T) {
 
 func TestMachinePoolScope_maxUnavailable(t *testing.T) {
 	var (
+		zero          = intstr.FromInt(0)
 		two           = intstr.FromInt(2)
+		five          = intstr.FromInt(5)
 		twentyPercent = intstr.FromString("20%")
 	)
 
@@ -155,6 +211,36 @@ func TestMachinePoolScope_maxUnavailable(t *testing.T) {
 			desiredReplicas: 21,
 			want:            4,
 		},
+		{
+			name: "MaxUnavailable is set to 0",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxUnavailable: &zero,
+				},
+			},
+			desiredReplicas: 3,
+			want:            0,
+		},
+		{
+			name: "MaxUnavailable is set to 20% and desiredReplicas is 1; rounds down to no machine",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxUnavailable: &twentyPercent,
+				},
+			},
+			desiredReplicas: 1,
+			want:            0,
+		},
+		{
+			name: "MaxUnavailable is larger than desiredReplicas",
+			strategy: &rollingUpdateStrategy{
+				MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+					MaxUnavailable: &five,
+				},
+			},
+			desiredReplicas: 2,
+			want:            5,
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,7 +259,9 @@ func TestMachinePoolScope_maxUnavailable(t *testing.T) {
 
 func TestMachinePoolRollingUpdateStrategy_SelectMachinesToDelete(t *testing.T) {
 	var (
+		zero             = intstr.FromInt(0)
 		one              = intstr.FromInt(1)
+		five             = intstr.FromInt(5)
 		two              = intstr.FromInt(2)
 		fortyFivePercent = intstr.FromString("45%")
 		thirtyPercent    = intstr.FromString("30%")
@@ -253,6 +341,46 @@ func TestMachinePoolRollingUpdateStrategy_SelectMachinesToDelete(t *testing.T) {
 				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour))}),
 			}),
 		},
+		{
+			name:            "if over-provisioned, don't select machines protected from scale-in",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour)), ProtectedFromScaleIn: true}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour)), ProtectedFromScaleIn: true}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(4 * time.Hour))}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(4 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if over-provisioned and all machines are protected from scale-in, select only those with the delete machine annotation",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 1,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(1 * time.Hour)), ProtectedFromScaleIn: true}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour)), ProtectedFromScaleIn: true, HasDeleteMachineAnnotation: true}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(3 * time.Hour)), ProtectedFromScaleIn: true}),
+			},
+			want: gomega.DiffEq([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour)), ProtectedFromScaleIn: true, HasDeleteMachineAnnotation: true}),
+			}),
+		},
+		{
+			name:            "if maxUnavailable is 1, replace out-of-date machines even if they are protected from scale-in",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxUnavailable: &one}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, ProtectedFromScaleIn: true}),
+				"bin": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, ProtectedFromScaleIn: true}),
+			}),
+		},
 		{
 			name:            "if over-provisioned, select machines ordered by creation date",
 			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{DeletePolicy: infrav1exp.OldestDeletePolicyType}),
@@ -426,6 +554,76 @@ func TestMachinePoolRollingUpdateStrategy_SelectMachinesToDelete(t *testing.T) {
 			},
 			want: BeEmpty(),
 		},
+		{
+			name:            "if a single replica pool surged with maxUnavailable 0, delete the out-of-date machine once the new one is ready",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &one, MaxUnavailable: &zero, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 1,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: true, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(time.Hour))}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+			}),
+		},
+		{
+			name:            "if a single replica pool surged with maxUnavailable 0, keep the out-of-date machine until the new one is ready",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &one, MaxUnavailable: &zero, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 1,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+				"bar": makeAMPM(ampmOptions{Ready: false, LatestModel: true, ProvisioningState: infrav1.Creating, CreationTime: metav1.NewTime(baseTime.Add(time.Hour))}),
+			},
+			want: BeEmpty(),
+		},
+		{
+			name:            "if a single replica pool has maxSurge 0 and maxUnavailable 1, delete the out-of-date machine",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &one, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 1,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+			}),
+		},
+		{
+			name:            "if maxUnavailable is larger than the pool, delete at most the desired number of machines",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &five, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 2,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(time.Hour))}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(time.Hour))}),
+			}),
+		},
+		{
+			name:            "if maxUnavailable is 1, replace out-of-date machines in the order of the delete policy",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &one, DeletePolicy: infrav1exp.NewestDeletePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime)}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+				"baz": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(time.Hour))}),
+			},
+			want: Equal([]infrav1exp.AzureMachinePoolMachine{
+				makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded, CreationTime: metav1.NewTime(baseTime.Add(2 * time.Hour))}),
+			}),
+		},
+		{
+			name:            "if maxUnavailable is 1 and a machine is already unavailable, delete nothing",
+			strategy:        makeRollingUpdateStrategy(infrav1exp.MachineRollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &one, DeletePolicy: infrav1exp.OldestDeletePolicyType}),
+			desiredReplicas: 3,
+			input: map[string]infrav1exp.AzureMachinePoolMachine{
+				"foo": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"bar": makeAMPM(ampmOptions{Ready: true, LatestModel: false, ProvisioningState: succeeded}),
+				"baz": makeAMPM(ampmOptions{Ready: false, LatestModel: true, ProvisioningState: infrav1.Creating}),
+			},
+			want: BeEmpty(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -455,6 +653,7 @@ type ampmOptions struct {
 	CreationTime               metav1.Time
 	DeletionTime               *metav1.Time
 	HasDeleteMachineAnnotation bool
+	ProtectedFromScaleIn       bool
 }
 
 func makeAMPM(opts ampmOptions) infrav1exp.AzureMachinePoolMachine {
@@ -475,5 +674,9 @@ func makeAMPM(opts ampmOptions) infrav1exp.AzureMachinePoolMachine {
 		ampm.Annotations[clusterv1.DeleteMachineAnnotation] = "true"
 	}
 
+	if opts.ProtectedFromScaleIn {
+		ampm.Annotations[azure.ProtectedFromScaleInAnnotation] = "true"
+	}
+
 	return ampm
 }
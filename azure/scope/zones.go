@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ infrav1.LocationZonesGetter = (*AzureClusterZonesGetter)(nil)
+
+// AzureClusterZonesGetter gets the availability zones of the location of an AzureCluster using the cluster's
+// identity. The zones are looked up from the resource SKUs, which are cached per location and identity.
+type AzureClusterZonesGetter struct {
+	Client client.Client
+}
+
+// GetZones returns the availability zones of the location of the AzureCluster.
+func (g *AzureClusterZonesGetter) GetZones(ctx context.Context, azureCluster *infrav1.AzureCluster) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.AzureClusterZonesGetter.GetZones")
+	defer done()
+
+	credentialsProvider, err := NewAzureClusterCredentialsProvider(ctx, g.Client, azureCluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init credentials provider")
+	}
+
+	auth := &clientsAuthorizer{}
+	if err := auth.setCredentialsWithProvider(ctx, azureCluster.Spec.SubscriptionID, azureCluster.Spec.AzureEnvironment, credentialsProvider); err != nil {
+		return nil, errors.Wrap(err, "failed to configure azure settings and credentials for Identity")
+	}
+
+	skuCache, err := resourceskus.GetCache(auth, azureCluster.Spec.Location)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init resourceskus cache")
+	}
+
+	return skuCache.GetZones(ctx, azureCluster.Spec.Location)
+}
+
+// clientsAuthorizer adapts AzureClients to an azure.Authorizer.
+type clientsAuthorizer struct {
+	AzureClients
+}
+
+// BaseURI returns the Azure ResourceManagerEndpoint.
+func (a *clientsAuthorizer) BaseURI() string {
+	return a.ResourceManagerEndpoint
+}
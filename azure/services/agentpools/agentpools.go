@@ -35,22 +35,31 @@ type AgentPoolScope interface {
 
 	Name() string
 	NodeResourceGroup() string
+	AgentPoolScaleSetTags() infrav1.Tags
 	AgentPoolSpec() azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool]
 	SetAgentPoolProviderIDList([]string)
 	SetAgentPoolReplicas(int32)
 	SetAgentPoolReady(bool)
+	SetAgentPoolScaleDownMode(*string)
+	SetAgentPoolNodeImageVersion(*string)
 	SetCAPIMachinePoolReplicas(replicas *int)
 	SetCAPIMachinePoolAnnotation(key, value string)
 	RemoveCAPIMachinePoolAnnotation(key string)
 	SetSubnetName()
 }
 
-// New creates a new service.
-func New(scope AgentPoolScope) *aso.Service[*asocontainerservicev1.ManagedClustersAgentPool, AgentPoolScope] {
+// New creates a new service. The scale sets lister and the tags client are used to tag the scale set backing the
+// agent pool in the node resource group.
+func New(scope AgentPoolScope, scaleSets ScaleSetLister, tagsClient TagsUpdater) *aso.Service[*asocontainerservicev1.ManagedClustersAgentPool, AgentPoolScope] {
 	svc := aso.NewService[*asocontainerservicev1.ManagedClustersAgentPool](serviceName, scope)
 	svc.Specs = []azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool]{scope.AgentPoolSpec()}
 	svc.ConditionType = infrav1.AgentPoolsReadyCondition
-	svc.PostCreateOrUpdateResourceHook = postCreateOrUpdateResourceHook
+	svc.PostCreateOrUpdateResourceHook = func(ctx context.Context, scope AgentPoolScope, agentPool *asocontainerservicev1.ManagedClustersAgentPool, err error) error {
+		if err := postCreateOrUpdateResourceHook(ctx, scope, agentPool, err); err != nil {
+			return err
+		}
+		return tagScaleSet(ctx, scope, scaleSets, tagsClient, agentPool)
+	}
 	return svc
 }
 
@@ -58,11 +67,19 @@ func postCreateOrUpdateResourceHook(ctx context.Context, scope AgentPoolScope, a
 	if err != nil {
 		return err
 	}
-	// When autoscaling is set, add the annotation to the machine pool and update the replica count.
-	if ptr.Deref(agentPool.Status.EnableAutoScaling, false) {
+	scope.SetAgentPoolScaleDownMode((*string)(agentPool.Status.ScaleDownMode))
+	scope.SetAgentPoolNodeImageVersion(agentPool.Status.NodeImageVersion)
+	// When autoscaling is enabled, AKS owns the node count. Mark the MachinePool replicas as externally managed
+	// and propagate the count reported by Azure back to it so CAPI never fights the autoscaler. The desired state
+	// in the spec is used rather than the status so toggling autoscaling takes effect in a single reconcile.
+	if ptr.Deref(agentPool.Spec.EnableAutoScaling, false) {
 		scope.SetCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation, "true")
-		scope.SetCAPIMachinePoolReplicas(agentPool.Status.Count)
-	} else { // Otherwise, remove the annotation.
+		// Leave the MachinePool replicas untouched until Azure reports a count, otherwise CAPI would
+		// default the nil replicas back to 1 and trigger a spurious scale operation.
+		if agentPool.Status.Count != nil {
+			scope.SetCAPIMachinePoolReplicas(agentPool.Status.Count)
+		}
+	} else { // Otherwise, the MachinePool replicas drive the agent pool count.
 		scope.RemoveCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation)
 	}
 	return nil
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools/mock_agentpools"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -44,11 +45,18 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
 
+		scope.EXPECT().SetAgentPoolScaleDownMode(ptr.To(infrav1.ScaleDownModeDeallocate))
+		scope.EXPECT().SetAgentPoolNodeImageVersion(ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"))
 		scope.EXPECT().RemoveCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation)
 
 		managedCluster := &asocontainerservicev1.ManagedClustersAgentPool{
+			Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+				EnableAutoScaling: ptr.To(false),
+			},
 			Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
 				EnableAutoScaling: ptr.To(false),
+				ScaleDownMode:     ptr.To(asocontainerservicev1.ScaleDownMode_STATUS_Deallocate),
+				NodeImageVersion:  ptr.To("AKSUbuntu-2204gen2containerd-202404.09.0"),
 			},
 		}
 
@@ -61,16 +69,87 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
 
+		scope.EXPECT().SetAgentPoolScaleDownMode(nil)
+		scope.EXPECT().SetAgentPoolNodeImageVersion(nil)
 		scope.EXPECT().SetCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation, "true")
 		scope.EXPECT().SetCAPIMachinePoolReplicas(ptr.To(1234))
 
 		managedCluster := &asocontainerservicev1.ManagedClustersAgentPool{
+			Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+				EnableAutoScaling: ptr.To(true),
+			},
 			Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
 				EnableAutoScaling: ptr.To(true),
 				Count:             ptr.To(1234),
 			},
 		}
 
+		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+	t.Run("successful create or update, autoscaling enabled without a reported count", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
+
+		scope.EXPECT().SetAgentPoolScaleDownMode(nil)
+		scope.EXPECT().SetAgentPoolNodeImageVersion(nil)
+		scope.EXPECT().SetCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation, "true")
+		scope.EXPECT().SetCAPIMachinePoolReplicas(gomock.Any()).Times(0)
+
+		managedCluster := &asocontainerservicev1.ManagedClustersAgentPool{
+			Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+				EnableAutoScaling: ptr.To(true),
+			},
+		}
+
+		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("autoscaling being enabled before Azure reports it", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
+
+		scope.EXPECT().SetAgentPoolScaleDownMode(nil)
+		scope.EXPECT().SetAgentPoolNodeImageVersion(nil)
+		scope.EXPECT().SetCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation, "true")
+		scope.EXPECT().SetCAPIMachinePoolReplicas(ptr.To(2))
+
+		managedCluster := &asocontainerservicev1.ManagedClustersAgentPool{
+			Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+				EnableAutoScaling: ptr.To(true),
+			},
+			Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
+				EnableAutoScaling: ptr.To(false),
+				Count:             ptr.To(2),
+			},
+		}
+
+		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("autoscaling being disabled before Azure reports it", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
+
+		scope.EXPECT().SetAgentPoolScaleDownMode(nil)
+		scope.EXPECT().SetAgentPoolNodeImageVersion(nil)
+		scope.EXPECT().RemoveCAPIMachinePoolAnnotation(clusterv1.ReplicasManagedByAnnotation)
+
+		managedCluster := &asocontainerservicev1.ManagedClustersAgentPool{
+			Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+				EnableAutoScaling: ptr.To(false),
+			},
+			Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
+				EnableAutoScaling: ptr.To(true),
+				Count:             ptr.To(5),
+			},
+		}
+
 		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
 		g.Expect(err).NotTo(HaveOccurred())
 	})
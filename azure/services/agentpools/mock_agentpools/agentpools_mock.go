@@ -74,6 +74,20 @@ func (mr *MockAgentPoolScopeMockRecorder) ASOOwner() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ASOOwner", reflect.TypeOf((*MockAgentPoolScope)(nil).ASOOwner))
 }
 
+// AgentPoolScaleSetTags mocks base method.
+func (m *MockAgentPoolScope) AgentPoolScaleSetTags() v1beta1.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AgentPoolScaleSetTags")
+	ret0, _ := ret[0].(v1beta1.Tags)
+	return ret0
+}
+
+// AgentPoolScaleSetTags indicates an expected call of AgentPoolScaleSetTags.
+func (mr *MockAgentPoolScopeMockRecorder) AgentPoolScaleSetTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AgentPoolScaleSetTags", reflect.TypeOf((*MockAgentPoolScope)(nil).AgentPoolScaleSetTags))
+}
+
 // AgentPoolSpec mocks base method.
 func (m *MockAgentPoolScope) AgentPoolSpec() azure.ASOResourceSpecGetter[*v1api20231001.ManagedClustersAgentPool] {
 	m.ctrl.T.Helper()
@@ -224,6 +238,18 @@ func (mr *MockAgentPoolScopeMockRecorder) RemoveCAPIMachinePoolAnnotation(key an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCAPIMachinePoolAnnotation", reflect.TypeOf((*MockAgentPoolScope)(nil).RemoveCAPIMachinePoolAnnotation), key)
 }
 
+// SetAgentPoolNodeImageVersion mocks base method.
+func (m *MockAgentPoolScope) SetAgentPoolNodeImageVersion(arg0 *string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAgentPoolNodeImageVersion", arg0)
+}
+
+// SetAgentPoolNodeImageVersion indicates an expected call of SetAgentPoolNodeImageVersion.
+func (mr *MockAgentPoolScopeMockRecorder) SetAgentPoolNodeImageVersion(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAgentPoolNodeImageVersion", reflect.TypeOf((*MockAgentPoolScope)(nil).SetAgentPoolNodeImageVersion), arg0)
+}
+
 // SetAgentPoolProviderIDList mocks base method.
 func (m *MockAgentPoolScope) SetAgentPoolProviderIDList(arg0 []string) {
 	m.ctrl.T.Helper()
@@ -260,6 +286,18 @@ func (mr *MockAgentPoolScopeMockRecorder) SetAgentPoolReplicas(arg0 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAgentPoolReplicas", reflect.TypeOf((*MockAgentPoolScope)(nil).SetAgentPoolReplicas), arg0)
 }
 
+// SetAgentPoolScaleDownMode mocks base method.
+func (m *MockAgentPoolScope) SetAgentPoolScaleDownMode(arg0 *string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAgentPoolScaleDownMode", arg0)
+}
+
+// SetAgentPoolScaleDownMode indicates an expected call of SetAgentPoolScaleDownMode.
+func (mr *MockAgentPoolScopeMockRecorder) SetAgentPoolScaleDownMode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAgentPoolScaleDownMode", reflect.TypeOf((*MockAgentPoolScope)(nil).SetAgentPoolScaleDownMode), arg0)
+}
+
 // SetCAPIMachinePoolAnnotation mocks base method.
 func (m *MockAgentPoolScope) SetCAPIMachinePoolAnnotation(key, value string) {
 	m.ctrl.T.Helper()
@@ -17,5 +17,7 @@ limitations under the License.
 // Run go generate to regenerate this mock.
 //go:generate ../../../../hack/tools/bin/mockgen -destination agentpools_mock.go -package mock_agentpools -source ../agentpools.go AgentPoolScope
 //go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt agentpools_mock.go > _agentpools_mock.go && mv _agentpools_mock.go agentpools_mock.go"
+//go:generate ../../../../hack/tools/bin/mockgen -destination scalesettags_mock.go -package mock_agentpools -source ../scalesettags.go ScaleSetLister,TagsUpdater
+//go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt scalesettags_mock.go > _scalesettags_mock.go && mv _scalesettags_mock.go scalesettags_mock.go"
 
 package mock_agentpools
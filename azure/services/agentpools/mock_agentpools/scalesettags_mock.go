@@ -0,0 +1,111 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../scalesettags.go
+//
+// Generated by this command:
+//
+//	mockgen -destination scalesettags_mock.go -package mock_agentpools -source ../scalesettags.go ScaleSetLister,TagsUpdater
+//
+
+// Package mock_agentpools is a generated GoMock package.
+package mock_agentpools
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	armresources "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScaleSetLister is a mock of ScaleSetLister interface.
+type MockScaleSetLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockScaleSetListerMockRecorder
+}
+
+// MockScaleSetListerMockRecorder is the mock recorder for MockScaleSetLister.
+type MockScaleSetListerMockRecorder struct {
+	mock *MockScaleSetLister
+}
+
+// NewMockScaleSetLister creates a new mock instance.
+func NewMockScaleSetLister(ctrl *gomock.Controller) *MockScaleSetLister {
+	mock := &MockScaleSetLister{ctrl: ctrl}
+	mock.recorder = &MockScaleSetListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScaleSetLister) EXPECT() *MockScaleSetListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockScaleSetLister) List(ctx context.Context, resourceGroupName string) ([]armcompute.VirtualMachineScaleSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, resourceGroupName)
+	ret0, _ := ret[0].([]armcompute.VirtualMachineScaleSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScaleSetListerMockRecorder) List(ctx, resourceGroupName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScaleSetLister)(nil).List), ctx, resourceGroupName)
+}
+
+// MockTagsUpdater is a mock of TagsUpdater interface.
+type MockTagsUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsUpdaterMockRecorder
+}
+
+// MockTagsUpdaterMockRecorder is the mock recorder for MockTagsUpdater.
+type MockTagsUpdaterMockRecorder struct {
+	mock *MockTagsUpdater
+}
+
+// NewMockTagsUpdater creates a new mock instance.
+func NewMockTagsUpdater(ctrl *gomock.Controller) *MockTagsUpdater {
+	mock := &MockTagsUpdater{ctrl: ctrl}
+	mock.recorder = &MockTagsUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsUpdater) EXPECT() *MockTagsUpdaterMockRecorder {
+	return m.recorder
+}
+
+// UpdateAtScope mocks base method.
+func (m *MockTagsUpdater) UpdateAtScope(ctx context.Context, scope string, parameters armresources.TagsPatchResource) (armresources.TagsResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAtScope", ctx, scope, parameters)
+	ret0, _ := ret[0].(armresources.TagsResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAtScope indicates an expected call of UpdateAtScope.
+func (mr *MockTagsUpdaterMockRecorder) UpdateAtScope(ctx, scope, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAtScope", reflect.TypeOf((*MockTagsUpdater)(nil).UpdateAtScope), ctx, scope, parameters)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agentpools
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ScaleSetLister lists the virtual machine scale sets of a resource group.
+type ScaleSetLister interface {
+	List(ctx context.Context, resourceGroupName string) ([]armcompute.VirtualMachineScaleSet, error)
+}
+
+// TagsUpdater updates the tags of an Azure resource.
+type TagsUpdater interface {
+	UpdateAtScope(ctx context.Context, scope string, parameters armresources.TagsPatchResource) (armresources.TagsResource, error)
+}
+
+// FindScaleSet returns the scale set backing the agent pool named poolName, or nil if there is none.
+func FindScaleSet(scaleSets []armcompute.VirtualMachineScaleSet, poolName string) *armcompute.VirtualMachineScaleSet {
+	for i, ss := range scaleSets {
+		if ptr.Deref(ss.Tags["poolName"], "") == poolName || ptr.Deref(ss.Tags["aks-managed-poolName"], "") == poolName {
+			return &scaleSets[i]
+		}
+	}
+	return nil
+}
+
+// tagScaleSet merges the CAPZ tags of the agent pool into the scale set backing it, so that every node resource can
+// be attributed to its cluster and machine pool. The node resource group is managed by AKS, so tags are only ever
+// added or updated, and tags CAPZ doesn't know about are never removed.
+func tagScaleSet(ctx context.Context, scope AgentPoolScope, scaleSets ScaleSetLister, tagsClient TagsUpdater, agentPool *asocontainerservicev1.ManagedClustersAgentPool) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "agentpools.tagScaleSet")
+	defer done()
+
+	desired := scope.AgentPoolScaleSetTags()
+	if len(desired) == 0 {
+		return nil
+	}
+
+	nodeResourceGroup := scope.NodeResourceGroup()
+	vmss, err := scaleSets.List(ctx, nodeResourceGroup)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list vmss in resource group %s", nodeResourceGroup)
+	}
+	match := FindScaleSet(vmss, agentPool.AzureName())
+	if match == nil || match.ID == nil {
+		// The scale set is not created yet, its tags are applied in a later reconcile.
+		return nil
+	}
+
+	changed := infrav1.Tags{}
+	for key, value := range desired {
+		if current, ok := match.Tags[key]; !ok || ptr.Deref(current, "") != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	log.V(4).Info("updating scale set tags", "scaleSet", ptr.Deref(match.Name, ""), "tags", changed)
+	_, err = tagsClient.UpdateAtScope(ctx, *match.ID, armresources.TagsPatchResource{
+		Operation: ptr.To(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{
+			Tags: converters.TagsToMap(changed),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update tags of vmss %s", ptr.Deref(match.Name, ""))
+	}
+	return nil
+}
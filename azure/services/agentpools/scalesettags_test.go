@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agentpools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools/mock_agentpools"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const fakeScaleSetID = "/subscriptions/123/resourceGroups/node-rg/providers/Microsoft.Compute/virtualMachineScaleSets/aks-pool0-12345-vmss"
+
+func TestFindScaleSet(t *testing.T) {
+	scaleSets := []armcompute.VirtualMachineScaleSet{
+		{Name: ptr.To("aks-pool0-vmss"), Tags: map[string]*string{"poolName": ptr.To("pool0")}},
+		{Name: ptr.To("aks-pool1-vmss"), Tags: map[string]*string{"aks-managed-poolName": ptr.To("pool1")}},
+	}
+
+	g := NewWithT(t)
+	g.Expect(FindScaleSet(scaleSets, "pool0")).To(Equal(&scaleSets[0]))
+	g.Expect(FindScaleSet(scaleSets, "pool1")).To(Equal(&scaleSets[1]))
+	g.Expect(FindScaleSet(scaleSets, "pool2")).To(BeNil())
+}
+
+func TestTagScaleSet(t *testing.T) {
+	desiredTags := infrav1.Tags{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+		"sigs.k8s.io_cluster-api-provider-azure_machinepool":        "my-machinepool",
+		"cost-center": "1234",
+	}
+	agentPool := &asocontainerservicev1.ManagedClustersAgentPool{
+		Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+			AzureName: "pool0",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		expect        func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "no tags",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(nil)
+			},
+		},
+		{
+			name: "scale set not created yet",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(desiredTags)
+				s.NodeResourceGroup().Return("node-rg")
+				l.List(gomockinternal.AContext(), "node-rg").Return(nil, nil)
+			},
+		},
+		{
+			name: "missing and changed tags are merged",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(desiredTags)
+				s.NodeResourceGroup().Return("node-rg")
+				l.List(gomockinternal.AContext(), "node-rg").Return([]armcompute.VirtualMachineScaleSet{
+					{
+						ID:   ptr.To(fakeScaleSetID),
+						Name: ptr.To("aks-pool0-12345-vmss"),
+						Tags: map[string]*string{
+							"aks-managed-poolName": ptr.To("pool0"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+							"cost-center": ptr.To("5678"),
+						},
+					},
+				}, nil)
+				u.UpdateAtScope(gomockinternal.AContext(), fakeScaleSetID, armresources.TagsPatchResource{
+					Operation: ptr.To(armresources.TagsPatchOperationMerge),
+					Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_machinepool": ptr.To("my-machinepool"),
+							"cost-center": ptr.To("1234"),
+						},
+					},
+				}).Return(armresources.TagsResource{}, nil)
+			},
+		},
+		{
+			name: "scale set is up to date",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(desiredTags)
+				s.NodeResourceGroup().Return("node-rg")
+				l.List(gomockinternal.AContext(), "node-rg").Return([]armcompute.VirtualMachineScaleSet{
+					{
+						ID:   ptr.To(fakeScaleSetID),
+						Name: ptr.To("aks-pool0-12345-vmss"),
+						Tags: map[string]*string{
+							"aks-managed-poolName": ptr.To("pool0"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_machinepool":        ptr.To("my-machinepool"),
+							"cost-center": ptr.To("1234"),
+							"unknown-tag": ptr.To("kept"),
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "error listing scale sets",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(desiredTags)
+				s.NodeResourceGroup().Return("node-rg")
+				l.List(gomockinternal.AContext(), "node-rg").Return(nil, errors.New("boom"))
+			},
+			expectedError: "failed to list vmss in resource group node-rg: boom",
+		},
+		{
+			name: "error updating tags",
+			expect: func(s *mock_agentpools.MockAgentPoolScopeMockRecorder, l *mock_agentpools.MockScaleSetListerMockRecorder, u *mock_agentpools.MockTagsUpdaterMockRecorder) {
+				s.AgentPoolScaleSetTags().Return(desiredTags)
+				s.NodeResourceGroup().Return("node-rg")
+				l.List(gomockinternal.AContext(), "node-rg").Return([]armcompute.VirtualMachineScaleSet{
+					{
+						ID:   ptr.To(fakeScaleSetID),
+						Name: ptr.To("aks-pool0-12345-vmss"),
+						Tags: map[string]*string{"aks-managed-poolName": ptr.To("pool0")},
+					},
+				}, nil)
+				u.UpdateAtScope(gomockinternal.AContext(), fakeScaleSetID, gomock.Any()).Return(armresources.TagsResource{}, errors.New("boom"))
+			},
+			expectedError: "failed to update tags of vmss aks-pool0-12345-vmss: boom",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			scope := mock_agentpools.NewMockAgentPoolScope(mockCtrl)
+			lister := mock_agentpools.NewMockScaleSetLister(mockCtrl)
+			updater := mock_agentpools.NewMockTagsUpdater(mockCtrl)
+
+			tc.expect(scope.EXPECT(), lister.EXPECT(), updater.EXPECT())
+
+			err := tagScaleSet(context.Background(), scope, lister, updater, agentPool)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
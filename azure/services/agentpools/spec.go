@@ -18,6 +18,7 @@ package agentpools
 
 import (
 	"context"
+	"sort"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
@@ -79,7 +80,8 @@ type AgentPoolSpec struct {
 	// Replicas is the number of desired machines.
 	Replicas int
 
-	// OSDiskSizeGB is the OS disk size in GB for every machine in this agent pool.
+	// OSDiskSizeGB is the OS disk size in GB for every machine in this agent pool. When zero, AKS picks the size,
+	// which for ephemeral OS disks is the largest size the VM size supports.
 	OSDiskSizeGB int
 
 	// VnetSubnetID is the Azure Resource ID for the subnet which should contain nodes.
@@ -118,12 +120,18 @@ type AgentPoolSpec struct {
 	// OSType specifies the operating system for the node pool. Allowed values are 'Linux' and 'Windows'
 	OSType *string `json:"osType,omitempty"`
 
+	// OSSKU specifies the node image of the agent pool, e.g. 'Ubuntu' or 'Windows2022'.
+	OSSKU *string `json:"osSKU,omitempty"`
+
 	// EnableNodePublicIP controls whether or not nodes in the agent pool each have a public IP address.
 	EnableNodePublicIP *bool `json:"enableNodePublicIP,omitempty"`
 
 	// NodePublicIPPrefixID specifies the public IP prefix resource ID which VM nodes should use IPs from.
 	NodePublicIPPrefixID string `json:"nodePublicIPPrefixID,omitempty"`
 
+	// NodePublicIPTags specifies the IP tags of the public IPs of the nodes, keyed by IP tag type.
+	NodePublicIPTags map[string]string `json:"nodePublicIPTags,omitempty"`
+
 	// ScaleSetPriority specifies the ScaleSetPriority for the node pool. Allowed values are 'Spot' and 'Regular'
 	ScaleSetPriority *string `json:"scaleSetPriority,omitempty"`
 
@@ -150,6 +158,18 @@ type AgentPoolSpec struct {
 
 	// EnableEncryptionAtHost indicates whether host encryption is enabled on the node pool
 	EnableEncryptionAtHost *bool
+
+	// ProximityPlacementGroupID specifies the resource ID of the proximity placement group of the nodes
+	ProximityPlacementGroupID string
+
+	// CapacityReservationGroupID specifies the resource ID of the capacity reservation group of the nodes
+	CapacityReservationGroupID string
+
+	// NodePoolSnapshotID specifies the resource ID of the node pool snapshot the pool is created from
+	NodePoolSnapshotID string
+
+	// PodSubnetID specifies the resource ID of the subnet pod IPs are allocated from with Azure CNI dynamic IP allocation
+	PodSubnetID string
 }
 
 // ResourceRef implements azure.ASOResourceSpecGetter.
@@ -176,6 +196,28 @@ func (s *AgentPoolSpec) getManagedMachinePoolVersion(existing *asocontainerservi
 	return ptr.To(v)
 }
 
+// getCount returns the node count to send to Azure.
+// For manually scaled pools the MachinePool replicas are the source of truth. For autoscaled pools the count is
+// owned by the AKS cluster autoscaler, so the count last reported by Azure is preserved to avoid an update loop
+// where CAPZ and AKS keep overwriting each other's value. The desired replicas are only used when the pool is
+// created.
+func (s *AgentPoolSpec) getCount(existing *asocontainerservicev1.ManagedClustersAgentPool) *int {
+	if !s.EnableAutoScaling {
+		return ptr.To(s.Replicas)
+	}
+
+	count := s.Replicas
+	if existing != nil {
+		switch {
+		case existing.Status.Count != nil:
+			count = *existing.Status.Count
+		case existing.Spec.Count != nil:
+			count = *existing.Spec.Count
+		}
+	}
+	return ptr.To(count)
+}
+
 // Parameters returns the parameters for the agent pool.
 func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerservicev1.ManagedClustersAgentPool) (params *asocontainerservicev1.ManagedClustersAgentPool, err error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "agentpools.Service.Parameters")
@@ -191,7 +233,7 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerse
 		Name: s.Cluster,
 	}
 	agentPool.Spec.AvailabilityZones = s.AvailabilityZones
-	agentPool.Spec.Count = &s.Replicas
+	agentPool.Spec.Count = s.getCount(existing)
 	agentPool.Spec.EnableAutoScaling = ptr.To(s.EnableAutoScaling)
 	agentPool.Spec.EnableUltraSSD = s.EnableUltraSSD
 	agentPool.Spec.KubeletDiskType = azure.AliasOrNil[asocontainerservicev1.KubeletDiskType]((*string)(s.KubeletDiskType))
@@ -201,9 +243,13 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerse
 	agentPool.Spec.Mode = ptr.To(asocontainerservicev1.AgentPoolMode(s.Mode))
 	agentPool.Spec.NodeLabels = s.NodeLabels
 	agentPool.Spec.NodeTaints = s.NodeTaints
-	agentPool.Spec.OsDiskSizeGB = ptr.To(asocontainerservicev1.ContainerServiceOSDisk(s.OSDiskSizeGB))
+	agentPool.Spec.OsDiskSizeGB = nil
+	if s.OSDiskSizeGB != 0 {
+		agentPool.Spec.OsDiskSizeGB = ptr.To(asocontainerservicev1.ContainerServiceOSDisk(s.OSDiskSizeGB))
+	}
 	agentPool.Spec.OsDiskType = azure.AliasOrNil[asocontainerservicev1.OSDiskType](s.OsDiskType)
 	agentPool.Spec.OsType = azure.AliasOrNil[asocontainerservicev1.OSType](s.OSType)
+	agentPool.Spec.OsSKU = azure.AliasOrNil[asocontainerservicev1.OSSKU](s.OSSKU)
 	agentPool.Spec.ScaleSetPriority = azure.AliasOrNil[asocontainerservicev1.ScaleSetPriority](s.ScaleSetPriority)
 	agentPool.Spec.ScaleDownMode = azure.AliasOrNil[asocontainerservicev1.ScaleDownMode](s.ScaleDownMode)
 	agentPool.Spec.Type = ptr.To(asocontainerservicev1.AgentPoolType_VirtualMachineScaleSets)
@@ -235,8 +281,12 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerse
 		agentPool.Spec.VmSize = &s.SKU
 	}
 
-	if s.SpotMaxPrice != nil {
+	switch {
+	case s.SpotMaxPrice != nil:
 		agentPool.Spec.SpotMaxPrice = ptr.To(s.SpotMaxPrice.AsApproximateFloat64())
+	case agentPool.Spec.SpotMaxPrice != nil:
+		// Unsetting the max price resets it to the AKS default, which doesn't evict nodes based on price.
+		agentPool.Spec.SpotMaxPrice = ptr.To[float64](-1)
 	}
 
 	if s.VnetSubnetID != "" {
@@ -251,6 +301,39 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerse
 		}
 	}
 
+	if s.ProximityPlacementGroupID != "" {
+		agentPool.Spec.ProximityPlacementGroupReference = &genruntime.ResourceReference{
+			ARMID: s.ProximityPlacementGroupID,
+		}
+	}
+
+	if s.CapacityReservationGroupID != "" {
+		agentPool.Spec.CapacityReservationGroupReference = &genruntime.ResourceReference{
+			ARMID: s.CapacityReservationGroupID,
+		}
+	}
+
+	if s.NodePoolSnapshotID != "" {
+		agentPool.Spec.CreationData = &asocontainerservicev1.CreationData{
+			SourceResourceReference: &genruntime.ResourceReference{
+				ARMID: s.NodePoolSnapshotID,
+			},
+		}
+	}
+
+	if s.PodSubnetID != "" {
+		agentPool.Spec.PodSubnetReference = &genruntime.ResourceReference{
+			ARMID: s.PodSubnetID,
+		}
+	}
+
+	agentPool.Spec.NetworkProfile = nil
+	if len(s.NodePublicIPTags) > 0 {
+		agentPool.Spec.NetworkProfile = &asocontainerservicev1.AgentPoolNetworkProfile{
+			NodePublicIPTags: nodePublicIPTags(s.NodePublicIPTags),
+		}
+	}
+
 	if s.LinuxOSConfig != nil {
 		agentPool.Spec.LinuxOSConfig = &asocontainerservicev1.LinuxOSConfig{
 			SwapFileSizeMB:             s.LinuxOSConfig.SwapFileSizeMB,
@@ -291,16 +374,24 @@ func (s *AgentPoolSpec) Parameters(ctx context.Context, existing *asocontainerse
 		}
 	}
 
-	// When autoscaling is set, the count of the nodes differ based on the autoscaler and should not depend on the
-	// count present in MachinePool or AzureManagedMachinePool, hence we should not make an update API call based
-	// on difference in count.
-	if s.EnableAutoScaling && agentPool.Status.Count != nil {
-		agentPool.Spec.Count = agentPool.Status.Count
-	}
-
 	return agentPool, nil
 }
 
+// nodePublicIPTags returns the IP tags sorted by type, so that the agent pool spec is stable across reconciles.
+func nodePublicIPTags(tags map[string]string) []asocontainerservicev1.IPTag {
+	ipTags := make([]asocontainerservicev1.IPTag, 0, len(tags))
+	for tagType, tag := range tags {
+		ipTags = append(ipTags, asocontainerservicev1.IPTag{
+			IpTagType: ptr.To(tagType),
+			Tag:       ptr.To(tag),
+		})
+	}
+	sort.Slice(ipTags, func(i, j int) bool {
+		return *ipTags[i].IpTagType < *ipTags[j].IpTagType
+	})
+	return ipTags
+}
+
 // WasManaged implements azure.ASOResourceSpecGetter.
 func (s *AgentPoolSpec) WasManaged(resource *asocontainerservicev1.ManagedClustersAgentPool) bool {
 	// CAPZ has never supported BYO agent pools.
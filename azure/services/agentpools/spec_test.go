@@ -156,4 +156,417 @@ func TestParameters(t *testing.T) {
 		g.Expect(actual.Spec.OrchestratorVersion).ToNot(BeNil())
 		g.Expect(*actual.Spec.OrchestratorVersion).To(Equal("1.27.2"))
 	})
+
+	t.Run("zero OS disk size lets AKS pick the size", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		spec := &AgentPoolSpec{
+			OSDiskSizeGB: 0,
+			OsDiskType:   ptr.To(string(asocontainerservicev1.OSDiskType_Ephemeral)),
+		}
+
+		actual, err := spec.Parameters(context.Background(), nil)
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.OsDiskSizeGB).To(BeNil())
+		g.Expect(actual.Spec.OsDiskType).To(Equal(ptr.To(asocontainerservicev1.OSDiskType_Ephemeral)))
+	})
+}
+
+func TestParametersCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *AgentPoolSpec
+		existing *asocontainerservicev1.ManagedClustersAgentPool
+		expected *int
+	}{
+		{
+			name:     "manual scaling, new agent pool",
+			spec:     &AgentPoolSpec{Replicas: 3},
+			existing: nil,
+			expected: ptr.To(3),
+		},
+		{
+			name: "manual scaling, existing agent pool uses MachinePool replicas",
+			spec: &AgentPoolSpec{Replicas: 3},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec:   asocontainerservicev1.ManagedClusters_AgentPool_Spec{Count: ptr.To(5)},
+				Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{Count: ptr.To(5)},
+			},
+			expected: ptr.To(3),
+		},
+		{
+			name:     "autoscaling, new agent pool uses MachinePool replicas as the initial count",
+			spec:     &AgentPoolSpec{Replicas: 3, EnableAutoScaling: true, MinCount: ptr.To(1), MaxCount: ptr.To(5)},
+			existing: nil,
+			expected: ptr.To(3),
+		},
+		{
+			name: "autoscaling, existing agent pool keeps the count reported by Azure",
+			spec: &AgentPoolSpec{Replicas: 3, EnableAutoScaling: true, MinCount: ptr.To(1), MaxCount: ptr.To(5)},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec:   asocontainerservicev1.ManagedClusters_AgentPool_Spec{Count: ptr.To(2)},
+				Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{Count: ptr.To(4)},
+			},
+			expected: ptr.To(4),
+		},
+		{
+			name: "autoscaling, existing agent pool without a reported count keeps its current count",
+			spec: &AgentPoolSpec{Replicas: 3, EnableAutoScaling: true, MinCount: ptr.To(1), MaxCount: ptr.To(5)},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{Count: ptr.To(2)},
+			},
+			expected: ptr.To(2),
+		},
+		{
+			name: "enabling autoscaling on a manually scaled agent pool keeps the count reported by Azure",
+			spec: &AgentPoolSpec{Replicas: 3, EnableAutoScaling: true, MinCount: ptr.To(1), MaxCount: ptr.To(5)},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					Count:             ptr.To(2),
+					EnableAutoScaling: ptr.To(false),
+				},
+				Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
+					Count:             ptr.To(2),
+					EnableAutoScaling: ptr.To(false),
+				},
+			},
+			expected: ptr.To(2),
+		},
+		{
+			name: "disabling autoscaling hands the count back to the MachinePool replicas",
+			spec: &AgentPoolSpec{Replicas: 4},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					Count:             ptr.To(4),
+					EnableAutoScaling: ptr.To(true),
+				},
+				Status: asocontainerservicev1.ManagedClusters_AgentPool_STATUS{
+					Count:             ptr.To(5),
+					EnableAutoScaling: ptr.To(true),
+				},
+			},
+			expected: ptr.To(4),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), tc.existing)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.Count).To(Equal(tc.expected))
+			g.Expect(actual.Spec.EnableAutoScaling).To(Equal(ptr.To(tc.spec.EnableAutoScaling)))
+		})
+	}
+}
+
+func TestParametersSpotMaxPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *AgentPoolSpec
+		existing *asocontainerservicev1.ManagedClustersAgentPool
+		expected *float64
+	}{
+		{
+			name:     "new spot agent pool without a max price",
+			spec:     &AgentPoolSpec{ScaleSetPriority: ptr.To("Spot")},
+			existing: nil,
+			expected: nil,
+		},
+		{
+			name:     "new spot agent pool with a max price",
+			spec:     &AgentPoolSpec{ScaleSetPriority: ptr.To("Spot"), SpotMaxPrice: ptr.To(resource.MustParse("0.25"))},
+			existing: nil,
+			expected: ptr.To(0.25),
+		},
+		{
+			name: "max price is updated in place",
+			spec: &AgentPoolSpec{ScaleSetPriority: ptr.To("Spot"), SpotMaxPrice: ptr.To(resource.MustParse("0.5"))},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					ScaleSetPriority: ptr.To(asocontainerservicev1.ScaleSetPriority_Spot),
+					SpotMaxPrice:     ptr.To(0.25),
+				},
+			},
+			expected: ptr.To(0.5),
+		},
+		{
+			name: "unset max price is reset to the AKS default",
+			spec: &AgentPoolSpec{ScaleSetPriority: ptr.To("Spot")},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					ScaleSetPriority: ptr.To(asocontainerservicev1.ScaleSetPriority_Spot),
+					SpotMaxPrice:     ptr.To(0.25),
+				},
+			},
+			expected: ptr.To[float64](-1),
+		},
+		{
+			name: "existing spot agent pool without a max price",
+			spec: &AgentPoolSpec{ScaleSetPriority: ptr.To("Spot")},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					ScaleSetPriority: ptr.To(asocontainerservicev1.ScaleSetPriority_Spot),
+				},
+			},
+			expected: nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), tc.existing)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.SpotMaxPrice).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParametersKubeletDiskType(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *AgentPoolSpec
+		expected *asocontainerservicev1.KubeletDiskType
+	}{
+		{
+			name:     "kubelet disk type is not set",
+			spec:     &AgentPoolSpec{},
+			expected: nil,
+		},
+		{
+			name:     "kubelet data on the OS disk",
+			spec:     &AgentPoolSpec{KubeletDiskType: ptr.To(infrav1.KubeletDiskTypeOS)},
+			expected: ptr.To(asocontainerservicev1.KubeletDiskType_OS),
+		},
+		{
+			name:     "kubelet data on the temp disk",
+			spec:     &AgentPoolSpec{KubeletDiskType: ptr.To(infrav1.KubeletDiskTypeTemporary)},
+			expected: ptr.To(asocontainerservicev1.KubeletDiskType_Temporary),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), nil)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.KubeletDiskType).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParametersFIPSAndEncryptionAtHost(t *testing.T) {
+	tests := []struct {
+		name                   string
+		spec                   *AgentPoolSpec
+		enableFIPS             *bool
+		enableEncryptionAtHost *bool
+	}{
+		{
+			name: "FIPS and encryption at host are not set",
+			spec: &AgentPoolSpec{},
+		},
+		{
+			name:                   "FIPS and encryption at host are enabled",
+			spec:                   &AgentPoolSpec{EnableFIPS: ptr.To(true), EnableEncryptionAtHost: ptr.To(true)},
+			enableFIPS:             ptr.To(true),
+			enableEncryptionAtHost: ptr.To(true),
+		},
+		{
+			name:                   "FIPS and encryption at host are disabled",
+			spec:                   &AgentPoolSpec{EnableFIPS: ptr.To(false), EnableEncryptionAtHost: ptr.To(false)},
+			enableFIPS:             ptr.To(false),
+			enableEncryptionAtHost: ptr.To(false),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), nil)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.EnableFIPS).To(Equal(tc.enableFIPS))
+			g.Expect(actual.Spec.EnableEncryptionAtHost).To(Equal(tc.enableEncryptionAtHost))
+		})
+	}
+}
+
+func TestParametersPlacementGroups(t *testing.T) {
+	proximityPlacementGroupID := "/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Compute/proximityPlacementGroups/ppg"
+	capacityReservationGroupID := "/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Compute/capacityReservationGroups/crg"
+	tests := []struct {
+		name                       string
+		spec                       *AgentPoolSpec
+		proximityPlacementGroupID  *genruntime.ResourceReference
+		capacityReservationGroupID *genruntime.ResourceReference
+	}{
+		{
+			name: "proximity placement group and capacity reservation group are not set",
+			spec: &AgentPoolSpec{},
+		},
+		{
+			name: "proximity placement group and capacity reservation group are set",
+			spec: &AgentPoolSpec{
+				ProximityPlacementGroupID:  proximityPlacementGroupID,
+				CapacityReservationGroupID: capacityReservationGroupID,
+			},
+			proximityPlacementGroupID:  &genruntime.ResourceReference{ARMID: proximityPlacementGroupID},
+			capacityReservationGroupID: &genruntime.ResourceReference{ARMID: capacityReservationGroupID},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), nil)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.ProximityPlacementGroupReference).To(Equal(tc.proximityPlacementGroupID))
+			g.Expect(actual.Spec.CapacityReservationGroupReference).To(Equal(tc.capacityReservationGroupID))
+		})
+	}
+}
+
+func TestParametersNodePoolSnapshot(t *testing.T) {
+	nodePoolSnapshotID := "/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.ContainerService/snapshots/snapshot"
+	tests := []struct {
+		name         string
+		spec         *AgentPoolSpec
+		creationData *asocontainerservicev1.CreationData
+	}{
+		{
+			name: "node pool snapshot is not set",
+			spec: &AgentPoolSpec{},
+		},
+		{
+			name: "node pool snapshot is set",
+			spec: &AgentPoolSpec{NodePoolSnapshotID: nodePoolSnapshotID},
+			creationData: &asocontainerservicev1.CreationData{
+				SourceResourceReference: &genruntime.ResourceReference{ARMID: nodePoolSnapshotID},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), nil)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.CreationData).To(Equal(tc.creationData))
+		})
+	}
+}
+
+func TestParametersPodSubnet(t *testing.T) {
+	podSubnetID := "/subscriptions/11111111-2222-aaaa-bbbb-cccccccccccc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods"
+	tests := []struct {
+		name      string
+		spec      *AgentPoolSpec
+		podSubnet *genruntime.ResourceReference
+	}{
+		{
+			name: "pod subnet is not set",
+			spec: &AgentPoolSpec{},
+		},
+		{
+			name:      "pod subnet is set",
+			spec:      &AgentPoolSpec{PodSubnetID: podSubnetID},
+			podSubnet: &genruntime.ResourceReference{ARMID: podSubnetID},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), nil)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.PodSubnetReference).To(Equal(tc.podSubnet))
+		})
+	}
+}
+
+func TestParametersOSSKU(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &AgentPoolSpec{
+		OSType: ptr.To(infrav1.WindowsOS),
+		OSSKU:  ptr.To(infrav1.OSSKUWindows2022),
+	}
+
+	actual, err := spec.Parameters(context.Background(), nil)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.OsType).To(Equal(ptr.To(asocontainerservicev1.OSType_Windows)))
+	g.Expect(actual.Spec.OsSKU).To(Equal(ptr.To(asocontainerservicev1.OSSKU_Windows2022)))
+}
+
+func TestParametersNodePublicIPTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *AgentPoolSpec
+		existing *asocontainerservicev1.ManagedClustersAgentPool
+		expected *asocontainerservicev1.AgentPoolNetworkProfile
+	}{
+		{
+			name:     "node public IP tags are not set",
+			spec:     &AgentPoolSpec{},
+			expected: nil,
+		},
+		{
+			name: "node public IP tags are set",
+			spec: &AgentPoolSpec{
+				EnableNodePublicIP: ptr.To(true),
+				NodePublicIPTags: map[string]string{
+					"RoutingPreference": "Internet",
+					"FirstPartyUsage":   "/NonProd",
+				},
+			},
+			expected: &asocontainerservicev1.AgentPoolNetworkProfile{
+				NodePublicIPTags: []asocontainerservicev1.IPTag{
+					{IpTagType: ptr.To("FirstPartyUsage"), Tag: ptr.To("/NonProd")},
+					{IpTagType: ptr.To("RoutingPreference"), Tag: ptr.To("Internet")},
+				},
+			},
+		},
+		{
+			name: "node public IP tags are removed from an existing agent pool",
+			spec: &AgentPoolSpec{},
+			existing: &asocontainerservicev1.ManagedClustersAgentPool{
+				Spec: asocontainerservicev1.ManagedClusters_AgentPool_Spec{
+					NetworkProfile: &asocontainerservicev1.AgentPoolNetworkProfile{
+						NodePublicIPTags: []asocontainerservicev1.IPTag{
+							{IpTagType: ptr.To("RoutingPreference"), Tag: ptr.To("Internet")},
+						},
+					},
+				},
+			},
+			expected: nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			actual, err := tc.spec.Parameters(context.Background(), tc.existing)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.NetworkProfile).To(Equal(tc.expected))
+		})
+	}
 }
@@ -43,6 +43,10 @@ const (
 	// asoannotations.ReconcilePolicy that was set before pausing.
 	prePauseReconcilePolicyAnnotation = "sigs.k8s.io/cluster-api-provider-azure-pre-pause-reconcile-policy"
 
+	// preResyncReconcilePolicyAnnotation is the annotation key for the value of
+	// asoannotations.ReconcilePolicy that was set before a resync.
+	preResyncReconcilePolicyAnnotation = "sigs.k8s.io/cluster-api-provider-azure-pre-resync-reconcile-policy"
+
 	requeueInterval = 20 * time.Second
 
 	createOrUpdateFutureType = "ASOCreateOrUpdate"
@@ -85,6 +89,7 @@ func (r *reconciler[T]) CreateOrUpdateResource(ctx context.Context, spec azure.A
 	resourceNamespace := resource.GetNamespace()
 
 	log = log.WithValues("service", serviceName, "resource", resourceName, "namespace", resourceNamespace)
+	r.addSpanKVPs(ctx, resource, serviceName)
 
 	var readyErr error
 	var adopt bool
@@ -196,10 +201,25 @@ func (r *reconciler[T]) CreateOrUpdateResource(ctx context.Context, spec azure.A
 	if adopt {
 		annotations[asoannotations.ReconcilePolicy] = string(asoannotations.ReconcilePolicyManage)
 	}
+	// A resync skips the reconciliation of the resource by ASO and resumes it on the next reconciliation.
+	if prevReconcilePolicy, ok := annotations[preResyncReconcilePolicyAnnotation]; ok {
+		annotations[asoannotations.ReconcilePolicy] = prevReconcilePolicy
+		delete(annotations, preResyncReconcilePolicyAnnotation)
+	} else if resyncer, ok := spec.(Resyncer[T]); ok && resourceExists &&
+		annotations[asoannotations.ReconcilePolicy] == string(asoannotations.ReconcilePolicyManage) && resyncer.NeedsResync(existing) {
+		log.V(2).Info("resyncing resource")
+		annotations[preResyncReconcilePolicyAnnotation] = annotations[asoannotations.ReconcilePolicy]
+		annotations[asoannotations.ReconcilePolicy] = string(asoannotations.ReconcilePolicySkip)
+	}
 
 	// Set the secret name annotation in order to leverage the ASO resource credential scope as defined in
 	// https://azure.github.io/azure-service-operator/guide/authentication/credential-scope/#resource-scope.
 	annotations[asoannotations.PerResourceSecret] = aso.GetASOSecretName(r.clusterName)
+	if namer, ok := spec.(CredentialSecretNamer); ok {
+		if secretName := namer.CredentialSecretName(); secretName != "" {
+			annotations[asoannotations.PerResourceSecret] = secretName
+		}
+	}
 
 	if len(labels) == 0 {
 		labels = nil
@@ -256,6 +276,7 @@ func (r *reconciler[T]) DeleteResource(ctx context.Context, resource T, serviceN
 	resourceNamespace := resource.GetNamespace()
 
 	log = log.WithValues("service", serviceName, "resource", resourceName, "namespace", resourceNamespace)
+	r.addSpanKVPs(ctx, resource, serviceName)
 
 	managed, err := IsManaged(ctx, r.Client, resource, r.owner)
 	if apierrors.IsNotFound(err) {
@@ -359,3 +380,17 @@ func (r *reconciler[T]) PauseResource(ctx context.Context, resource T, serviceNa
 
 	return r.Client.Patch(ctx, resource, client.MergeFrom(before))
 }
+
+// addSpanKVPs records the cluster, service and ASO resource of an operation on the span of ctx.
+func (r *reconciler[T]) addSpanKVPs(ctx context.Context, resource T, serviceName string) {
+	opts := []tele.Option{
+		tele.KVP("cluster", r.clusterName),
+		tele.KVP("service", serviceName),
+		tele.KVP("name", resource.GetName()),
+		tele.KVP("namespace", resource.GetNamespace()),
+	}
+	if gvk, err := apiutil.GVKForObject(resource, r.Scheme()); err == nil {
+		opts = append(opts, tele.KVP("kind", gvk.Kind))
+	}
+	tele.AddKVPs(ctx, opts...)
+}
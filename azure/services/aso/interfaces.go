@@ -39,6 +39,22 @@ type TagsGetterSetter[T genruntime.MetaObject] interface {
 	SetTags(resource T, tags infrav1.Tags)
 }
 
+// CredentialSecretNamer may be implemented by specs of resources that must be managed with another ASO credential
+// than the one of the cluster, e.g. because they are in another subscription.
+type CredentialSecretNamer interface {
+	// CredentialSecretName returns the name of the ASO credential secret, or an empty string for the one of the cluster.
+	CredentialSecretName() string
+}
+
+// Resyncer may be implemented by specs of resources that can drift in Azure without any change to their ASO resource.
+// ASO only puts the spec of a resource to Azure when it reconciles it, which happens when the spec changes, when the
+// reconcile policy changes to or from "skip", or when the sync period of ASO elapses. A resync makes ASO reconcile the
+// resource again by skipping its reconciliation and resuming it on the next reconciliation of CAPZ.
+type Resyncer[T genruntime.MetaObject] interface {
+	// NeedsResync returns whether ASO needs to reconcile the existing resource again.
+	NeedsResync(existing T) bool
+}
+
 // Scope represents the common functionality related to all scopes needed for ASO services.
 type Scope interface {
 	azure.AsyncStatusUpdater
@@ -167,6 +167,80 @@ func (mr *MockTagsGetterSetterMockRecorder[T]) SetTags(resource, tags any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockTagsGetterSetter[T])(nil).SetTags), resource, tags)
 }
 
+// MockCredentialSecretNamer is a mock of CredentialSecretNamer interface.
+type MockCredentialSecretNamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialSecretNamerMockRecorder
+}
+
+// MockCredentialSecretNamerMockRecorder is the mock recorder for MockCredentialSecretNamer.
+type MockCredentialSecretNamerMockRecorder struct {
+	mock *MockCredentialSecretNamer
+}
+
+// NewMockCredentialSecretNamer creates a new mock instance.
+func NewMockCredentialSecretNamer(ctrl *gomock.Controller) *MockCredentialSecretNamer {
+	mock := &MockCredentialSecretNamer{ctrl: ctrl}
+	mock.recorder = &MockCredentialSecretNamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialSecretNamer) EXPECT() *MockCredentialSecretNamerMockRecorder {
+	return m.recorder
+}
+
+// CredentialSecretName mocks base method.
+func (m *MockCredentialSecretNamer) CredentialSecretName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CredentialSecretName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CredentialSecretName indicates an expected call of CredentialSecretName.
+func (mr *MockCredentialSecretNamerMockRecorder) CredentialSecretName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CredentialSecretName", reflect.TypeOf((*MockCredentialSecretNamer)(nil).CredentialSecretName))
+}
+
+// MockResyncer is a mock of Resyncer interface.
+type MockResyncer[T genruntime.MetaObject] struct {
+	ctrl     *gomock.Controller
+	recorder *MockResyncerMockRecorder[T]
+}
+
+// MockResyncerMockRecorder is the mock recorder for MockResyncer.
+type MockResyncerMockRecorder[T genruntime.MetaObject] struct {
+	mock *MockResyncer[T]
+}
+
+// NewMockResyncer creates a new mock instance.
+func NewMockResyncer[T genruntime.MetaObject](ctrl *gomock.Controller) *MockResyncer[T] {
+	mock := &MockResyncer[T]{ctrl: ctrl}
+	mock.recorder = &MockResyncerMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResyncer[T]) EXPECT() *MockResyncerMockRecorder[T] {
+	return m.recorder
+}
+
+// NeedsResync mocks base method.
+func (m *MockResyncer[T]) NeedsResync(existing T) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsResync", existing)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsResync indicates an expected call of NeedsResync.
+func (mr *MockResyncerMockRecorder[T]) NeedsResync(existing any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsResync", reflect.TypeOf((*MockResyncer[T])(nil).NeedsResync), existing)
+}
+
 // MockScope is a mock of Scope interface.
 type MockScope struct {
 	ctrl     *gomock.Controller
@@ -19,7 +19,11 @@ package aso
 import (
 	"context"
 
+	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -42,15 +46,18 @@ type Service[T deepCopier[T], S Scope] struct {
 	PostReconcileHook              func(ctx context.Context, scope S, err error) error
 	PostDeleteHook                 func(ctx context.Context, scope S, err error) error
 
-	name string
+	name        string
+	clusterName string
 }
 
 // NewService creates a new Service.
 func NewService[T deepCopier[T], S Scope](name string, scope S) *Service[T, S] {
+	clusterName := scope.ClusterName()
 	return &Service[T, S]{
-		Reconciler: New[T](scope.GetClient(), scope.ClusterName(), scope.ASOOwner()),
-		Scope:      scope,
-		name:       name,
+		Reconciler:  New[T](scope.GetClient(), clusterName, scope.ASOOwner()),
+		Scope:       scope,
+		name:        name,
+		clusterName: clusterName,
 	}
 }
 
@@ -61,7 +68,10 @@ func (s *Service[T, S]) Name() string {
 
 // Reconcile idempotently creates or updates the resources.
 func (s *Service[T, S]) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Service.Reconcile",
+		tele.KVP("service", s.name),
+		tele.KVP("cluster", s.clusterName),
+	)
 	defer done()
 
 	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
@@ -98,6 +108,9 @@ func (s *Service[T, S]) Reconcile(ctx context.Context) error {
 
 	for _, spec := range s.Specs {
 		result, err := s.CreateOrUpdateResource(ctx, spec, s.Name())
+		if err == nil {
+			s.recordManagedResource(result)
+		}
 		if s.PostCreateOrUpdateResourceHook != nil {
 			err = s.PostCreateOrUpdateResourceHook(ctx, s.Scope, result, err)
 		}
@@ -115,7 +128,10 @@ func (s *Service[T, S]) Reconcile(ctx context.Context) error {
 
 // Delete deletes the resources.
 func (s *Service[T, S]) Delete(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Service.Delete")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Service.Delete",
+		tele.KVP("service", s.name),
+		tele.KVP("cluster", s.clusterName),
+	)
 	defer done()
 
 	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
@@ -133,7 +149,15 @@ func (s *Service[T, S]) Delete(ctx context.Context) error {
 	//   - no error (i.e. deleted)
 	var resultErr error
 	for _, spec := range s.Specs {
-		err := s.DeleteResource(ctx, spec.ResourceRef(), s.Name())
+		ref := spec.ResourceRef()
+		err := s.DeleteResource(ctx, ref, s.Name())
+		switch {
+		case err == nil:
+			// ASO only removes the ASO resource once the Azure resource is deleted.
+			s.forgetManagedResource(ref)
+		case azure.IsOperationNotDoneError(err):
+			s.recordManagedResourceDeletion(ref)
+		}
 		if err != nil && (!azure.IsOperationNotDoneError(err) || resultErr == nil) {
 			resultErr = err
 		}
@@ -146,6 +170,56 @@ func (s *Service[T, S]) Delete(ctx context.Context) error {
 	return resultErr
 }
 
+// recordManagedResource records the Azure resource of an ASO resource actively managed by CAPZ, i.e. one CAPZ
+// created, if the scope records the resources created by CAPZ. Resources controlled by another owner, e.g. a virtual
+// network shared with another cluster, aren't recorded.
+func (s *Service[T, S]) recordManagedResource(resource T) {
+	recorder, ok := any(s.Scope).(azure.ResourceOwnershipRecorder)
+	if !ok {
+		return
+	}
+	if !metav1.IsControlledBy(resource, s.Scope.ASOOwner()) {
+		return
+	}
+	annotations := resource.GetAnnotations()
+	if annotations[asoannotations.ReconcilePolicy] != string(asoannotations.ReconcilePolicyManage) {
+		return
+	}
+	recorder.RecordManagedResource(annotations[genruntime.ResourceIDAnnotation])
+}
+
+// recordManagedResourceDeletion remembers the Azure resource of an ASO resource being deleted so that it can be
+// forgotten once the ASO resource, and with it its resource ID annotation, is gone.
+func (s *Service[T, S]) recordManagedResourceDeletion(resource T) {
+	recorder, ok := any(s.Scope).(azure.ResourceDeletionRecorder)
+	if !ok {
+		return
+	}
+	recorder.RecordManagedResourceDeletion(s.deletionKey(resource), resource.GetAnnotations()[genruntime.ResourceIDAnnotation])
+}
+
+// forgetManagedResource removes the Azure resource of a deleted ASO resource from the resources recorded as created
+// by CAPZ.
+func (s *Service[T, S]) forgetManagedResource(resource T) {
+	recorder, ok := any(s.Scope).(azure.ResourceOwnershipRecorder)
+	if !ok {
+		return
+	}
+	id := resource.GetAnnotations()[genruntime.ResourceIDAnnotation]
+	if deletions, ok := any(s.Scope).(azure.ResourceDeletionRecorder); ok && id == "" {
+		id = deletions.ManagedResourceDeletion(s.deletionKey(resource))
+	}
+	if id == "" {
+		return
+	}
+	recorder.ForgetManagedResource(id)
+}
+
+// deletionKey identifies the ASO resource deleting an Azure resource among the resources of the scope.
+func (s *Service[T, S]) deletionKey(resource T) string {
+	return s.Name() + "/" + resource.GetName()
+}
+
 // Pause implements azure.Pauser.
 func (s *Service[T, S]) Pause(ctx context.Context) error {
 	var _ azure.Pauser = (*Service[T, S])(nil)
@@ -162,3 +236,29 @@ func (s *Service[T, S]) Pause(ctx context.Context) error {
 
 	return nil
 }
+
+// Retain implements azure.Retainer. It sets the reconcile-policy of the resources to skip so that ASO leaves the
+// resources in Azure when the ASO resources are garbage collected along with their owner.
+func (s *Service[T, S]) Retain(ctx context.Context) ([]string, error) {
+	var _ azure.Retainer = (*Service[T, S])(nil)
+
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Service.Retain")
+	defer done()
+
+	var ids []string
+	for _, spec := range s.Specs {
+		ref := spec.ResourceRef()
+		if err := s.PauseResource(ctx, ref, s.Name()); err != nil {
+			if apierrors.IsNotFound(err) {
+				// The resource was never created, there is nothing to retain.
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to retain ASO resource %s %s/%s", ref.GetObjectKind().GroupVersionKind(), s.Scope.ASOOwner().GetNamespace(), ref.GetName())
+		}
+		if id := ref.GetAnnotations()[genruntime.ResourceIDAnnotation]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
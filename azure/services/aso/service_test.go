@@ -21,11 +21,15 @@ import (
 	"testing"
 
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
@@ -551,8 +555,276 @@ func TestServicePause(t *testing.T) {
 	})
 }
 
+func TestServiceRetain(t *testing.T) {
+	t.Run("PauseResource succeeds for all resources", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+
+		scope := mock_aso.NewMockScope(mockCtrl)
+		specs := []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+			mockSpecExpectingResourceRef(mockCtrl, &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "created",
+					Annotations: map[string]string{
+						genruntime.ResourceIDAnnotation: "/subscriptions/123/resourceGroups/created",
+					},
+				},
+			}),
+			mockSpecExpectingResourceRef(mockCtrl, &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "creating",
+				},
+			}),
+			mockSpecExpectingResourceRef(mockCtrl, &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "not-found",
+				},
+			}),
+		}
+
+		reconciler := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+		reconciler.EXPECT().PauseResource(gomockinternal.AContext(), specs[0].ResourceRef(), serviceName).Return(nil)
+		reconciler.EXPECT().PauseResource(gomockinternal.AContext(), specs[1].ResourceRef(), serviceName).Return(nil)
+		reconciler.EXPECT().PauseResource(gomockinternal.AContext(), specs[2].ResourceRef(), serviceName).
+			Return(apierrors.NewNotFound(asoresourcesv1.GroupVersion.WithResource("resourcegroups").GroupResource(), "not-found"))
+
+		s := &Service[*asoresourcesv1.ResourceGroup, *mock_aso.MockScope]{
+			Reconciler:    reconciler,
+			Scope:         scope,
+			Specs:         specs,
+			name:          serviceName,
+			ConditionType: conditionType,
+		}
+
+		ids, err := s.Retain(context.Background())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ids).To(Equal([]string{"/subscriptions/123/resourceGroups/created"}))
+	})
+
+	t.Run("PauseResource fails for one resource", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+
+		scope := mock_aso.NewMockScope(mockCtrl)
+		specs := []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+			mockSpecExpectingResourceRef(mockCtrl, &asoresourcesv1.ResourceGroup{}),
+			mockSpecExpectingResourceRef(mockCtrl, &asoresourcesv1.ResourceGroup{}),
+		}
+		scope.EXPECT().ASOOwner().Return(&asoresourcesv1.ResourceGroup{})
+
+		pauseErr := errors.New("Pause error")
+		reconciler := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+		reconciler.EXPECT().PauseResource(gomockinternal.AContext(), specs[0].ResourceRef(), serviceName).Return(pauseErr)
+
+		s := &Service[*asoresourcesv1.ResourceGroup, *mock_aso.MockScope]{
+			Reconciler:    reconciler,
+			Scope:         scope,
+			Specs:         specs,
+			name:          serviceName,
+			ConditionType: conditionType,
+		}
+
+		ids, err := s.Retain(context.Background())
+		g.Expect(err).To(MatchError(pauseErr))
+		g.Expect(ids).To(BeNil())
+	})
+}
+
 func mockSpecExpectingResourceRef(ctrl *gomock.Controller, resource *asoresourcesv1.ResourceGroup) azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup] {
 	spec := mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](ctrl)
 	spec.EXPECT().ResourceRef().Return(resource).AnyTimes()
 	return spec
 }
+
+// recordingScope is a Scope that records the Azure resources created by CAPZ.
+type recordingScope struct {
+	*mock_aso.MockScope
+	managed *infrav1.ManagedResources
+}
+
+func (s *recordingScope) IsOwnershipRecorded() bool { return true }
+
+func (s *recordingScope) RecordManagedResource(id string) { s.managed.Add(id) }
+
+func (s *recordingScope) ForgetManagedResource(id string) { s.managed.Remove(id) }
+
+func (s *recordingScope) IsManagedResource(id string, ownedByTags bool) bool {
+	return ownedByTags || s.managed.Has(id)
+}
+
+func (s *recordingScope) RecordManagedResourceDeletion(key, id string) {
+	s.managed.MarkDeleting(key, id)
+}
+
+func (s *recordingScope) ManagedResourceDeletion(key string) string { return s.managed.DeletingID(key) }
+
+func TestServiceManagedResources(t *testing.T) {
+	owner := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			UID:  "cluster-uid",
+		},
+	}
+	ownerRefs := []metav1.OwnerReference{
+		{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       infrav1.AzureClusterKind,
+			Name:       owner.Name,
+			UID:        owner.UID,
+			Controller: ptr.To(true),
+		},
+	}
+	created := &asoresourcesv1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "created",
+			OwnerReferences: ownerRefs,
+			Annotations: map[string]string{
+				asoannotations.ReconcilePolicy:  string(asoannotations.ReconcilePolicyManage),
+				genruntime.ResourceIDAnnotation: "/subscriptions/123/resourceGroups/created",
+			},
+		},
+	}
+	adopted := &asoresourcesv1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "adopted",
+			OwnerReferences: ownerRefs,
+			Annotations: map[string]string{
+				asoannotations.ReconcilePolicy:  string(asoannotations.ReconcilePolicySkip),
+				genruntime.ResourceIDAnnotation: "/subscriptions/123/resourceGroups/adopted",
+			},
+		},
+	}
+	shared := &asoresourcesv1.ResourceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "shared",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       infrav1.AzureClusterKind,
+					Name:       "other-cluster",
+					UID:        "other-cluster-uid",
+					Controller: ptr.To(true),
+				},
+				{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       infrav1.AzureClusterKind,
+					Name:       owner.Name,
+					UID:        owner.UID,
+				},
+			},
+			Annotations: map[string]string{
+				asoannotations.ReconcilePolicy:  string(asoannotations.ReconcilePolicyManage),
+				genruntime.ResourceIDAnnotation: "/subscriptions/123/resourceGroups/shared",
+			},
+		},
+	}
+
+	t.Run("records the resources created by CAPZ", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+
+		scope := &recordingScope{MockScope: mock_aso.NewMockScope(mockCtrl), managed: &infrav1.ManagedResources{}}
+		specs := []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+			mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl),
+			mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl),
+			mock_azure.NewMockASOResourceSpecGetter[*asoresourcesv1.ResourceGroup](mockCtrl),
+		}
+
+		reconciler := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+		reconciler.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), specs[0], serviceName).Return(created, nil).Times(2)
+		reconciler.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), specs[1], serviceName).Return(adopted, nil).Times(2)
+		reconciler.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), specs[2], serviceName).Return(shared, nil).Times(2)
+		scope.EXPECT().ASOOwner().Return(owner).AnyTimes()
+		scope.EXPECT().UpdatePutStatus(conditionType, serviceName, nil).Times(2)
+		scope.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconcilerutils.DefaultAzureServiceReconcileTimeout).Times(2)
+
+		s := &Service[*asoresourcesv1.ResourceGroup, *recordingScope]{
+			Reconciler:    reconciler,
+			Scope:         scope,
+			Specs:         specs,
+			name:          serviceName,
+			ConditionType: conditionType,
+		}
+
+		g.Expect(s.Reconcile(context.Background())).To(Succeed())
+		g.Expect(s.Reconcile(context.Background())).To(Succeed())
+		g.Expect(scope.managed.IDs).To(ConsistOf("/subscriptions/123/resourceGroups/created"))
+	})
+
+	t.Run("keeps the resources while their deletion is in progress", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+
+		scope := &recordingScope{
+			MockScope: mock_aso.NewMockScope(mockCtrl),
+			managed:   &infrav1.ManagedResources{IDs: []string{"/subscriptions/123/resourceGroups/created", "/subscriptions/123/resourceGroups/other"}},
+		}
+		specs := []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+			mockSpecExpectingResourceRef(mockCtrl, created.DeepCopy()),
+		}
+
+		reconciler := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+		notDone := azure.NewOperationNotDoneError(&infrav1.Future{})
+		reconciler.EXPECT().DeleteResource(gomockinternal.AContext(), specs[0].ResourceRef(), serviceName).Return(notDone)
+		scope.EXPECT().UpdateDeleteStatus(conditionType, serviceName, notDone)
+		scope.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconcilerutils.DefaultAzureServiceReconcileTimeout)
+
+		s := &Service[*asoresourcesv1.ResourceGroup, *recordingScope]{
+			Reconciler:    reconciler,
+			Scope:         scope,
+			Specs:         specs,
+			name:          serviceName,
+			ConditionType: conditionType,
+		}
+
+		err := s.Delete(context.Background())
+		g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+		g.Expect(scope.managed.IDs).To(ConsistOf("/subscriptions/123/resourceGroups/created", "/subscriptions/123/resourceGroups/other"))
+		g.Expect(scope.managed.Deleting).To(Equal(map[string]string{serviceName + "/created": "/subscriptions/123/resourceGroups/created"}))
+	})
+
+	t.Run("forgets the resources once their deletion is confirmed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+
+		scope := &recordingScope{
+			MockScope: mock_aso.NewMockScope(mockCtrl),
+			managed: &infrav1.ManagedResources{
+				IDs:      []string{"/subscriptions/123/resourceGroups/created", "/subscriptions/123/resourceGroups/other"},
+				Deleting: map[string]string{serviceName + "/created": "/subscriptions/123/resourceGroups/created"},
+			},
+		}
+		// The ASO resource is gone, so the resource ID annotation isn't available anymore.
+		deleted := &asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "created",
+			},
+		}
+		specs := []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{
+			mockSpecExpectingResourceRef(mockCtrl, deleted),
+		}
+
+		reconciler := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+		reconciler.EXPECT().DeleteResource(gomockinternal.AContext(), specs[0].ResourceRef(), serviceName).Return(nil)
+		scope.EXPECT().UpdateDeleteStatus(conditionType, serviceName, nil)
+		scope.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconcilerutils.DefaultAzureServiceReconcileTimeout)
+
+		s := &Service[*asoresourcesv1.ResourceGroup, *recordingScope]{
+			Reconciler:    reconciler,
+			Scope:         scope,
+			Specs:         specs,
+			name:          serviceName,
+			ConditionType: conditionType,
+		}
+
+		err := s.Delete(context.Background())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(scope.managed.IDs).To(ConsistOf("/subscriptions/123/resourceGroups/other"))
+		g.Expect(scope.managed.Deleting).To(BeNil())
+	})
+}
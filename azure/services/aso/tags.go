@@ -46,7 +46,8 @@ func reconcileTags[T genruntime.MetaObject](t TagsGetterSetter[T], existing T, r
 			}
 		}
 
-		existingTags = t.GetDesiredTags(existing)
+		// Tags set with the legacy cluster tag prefix are rewritten with the configured prefix.
+		existingTags, _ = t.GetDesiredTags(existing).MigrateLegacyPrefix()
 	}
 
 	existingTagsMap := converters.TagsToMap(existingTags)
@@ -31,6 +31,7 @@ import (
 func TestReconcileTags(t *testing.T) {
 	tests := []struct {
 		name               string
+		clusterTagPrefix   string
 		lastAppliedTags    infrav1.Tags
 		existingTags       infrav1.Tags
 		additionalTagsSpec infrav1.Tags
@@ -70,6 +71,37 @@ func TestReconcileTags(t *testing.T) {
 				"additionalTag": "additionalVal",
 			},
 		},
+		{
+			name:             "tags with the legacy cluster tag prefix are rewritten",
+			clusterTagPrefix: "acme_capz_",
+			existingTags: infrav1.Tags{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_role":               "common",
+				"nonAdditionalTag": "nonAdditionalVal",
+			},
+			tagsFromParams: infrav1.Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+			},
+			expectedTags: infrav1.Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+				"acme_capz_role":               "common",
+				"nonAdditionalTag":             "nonAdditionalVal",
+			},
+		},
+		{
+			name:             "tags with both cluster tag prefixes keep the new prefix",
+			clusterTagPrefix: "acme_capz_",
+			existingTags: infrav1.Tags{
+				"acme_capz_cluster_my-cluster":                              "owned",
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+			},
+			tagsFromParams: infrav1.Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+			},
+			expectedTags: infrav1.Tags{
+				"acme_capz_cluster_my-cluster": "owned",
+			},
+		},
 		{
 			name:               "no additional tags",
 			lastAppliedTags:    nil,
@@ -81,6 +113,10 @@ func TestReconcileTags(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			g := NewWithT(t)
+			if test.clusterTagPrefix != "" {
+				g.Expect(infrav1.SetClusterTagPrefix(test.clusterTagPrefix)).To(Succeed())
+				t.Cleanup(func() { _ = infrav1.SetClusterTagPrefix(infrav1.NameAzureProviderPrefix) })
+			}
 
 			mockCtrl := gomock.NewController(t)
 			tag := mock_aso.NewMockTagsGetterSetter[*asoresourcesv1.ResourceGroup](mockCtrl)
@@ -41,6 +41,11 @@ type Service[C, D any] struct {
 	Scope FutureScope
 	Creator[C]
 	Deleter[D]
+	// deleteConfirmer, when set, gets a resource once its deletion completed to confirm that it's gone.
+	deleteConfirmer Getter
+	// adoptable, when set, makes the creation of a resource which fails with a conflict return the resource when it
+	// exists and adoptable returns true for it.
+	adoptable func(spec azure.ResourceSpecGetter, existing interface{}) bool
 }
 
 // New creates an async Service.
@@ -52,6 +57,24 @@ func New[C, D any](scope FutureScope, createClient Creator[C], deleteClient Dele
 	}
 }
 
+// WithDeleteConfirmation makes DeleteResource only report a resource as deleted once getter no longer finds it,
+// rather than as soon as the delete operation completed. Resources which still exist are reported as a transient
+// error, so that whatever depends on their deletion, like the removal of a finalizer, waits for them to be gone.
+func (s *Service[C, D]) WithDeleteConfirmation(getter Getter) *Service[C, D] {
+	s.deleteConfirmer = getter
+	return s
+}
+
+// WithConflictAdoption makes CreateOrUpdateResource adopt a resource whose creation fails with a conflict when the
+// resource exists by then, e.g. because a previous creation interrupted before its result was recorded is still
+// completing. The existing resource is returned as if it had been created if adoptable returns true for it, i.e. it
+// is owned by the cluster and matches the spec. Otherwise the creation fails, so that a resource created by someone
+// else with the same name isn't mistaken for the one of the spec.
+func (s *Service[C, D]) WithConflictAdoption(adoptable func(spec azure.ResourceSpecGetter, existing interface{}) bool) *Service[C, D] {
+	s.adoptable = adoptable
+	return s
+}
+
 // CreateOrUpdateResource creates a new resource or updates an existing one asynchronously.
 func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (result interface{}, err error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.Service.CreateOrUpdateResource")
@@ -60,6 +83,7 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 	resourceName := spec.ResourceName()
 	rgName := spec.ResourceGroupName()
 	futureType := infrav1.PutFuture
+	tele.AddKVPs(ctx, tele.KVP("service", serviceName), tele.KVP("name", resourceName), tele.KVP("resourceGroup", rgName))
 
 	// Check if there is an ongoing long-running operation.
 	resumeToken := ""
@@ -75,6 +99,7 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 	// Only when no long running operation is currently in progress do we need to get the parameters.
 	// The polling implemented by the SDK does not use parameters when a resume token exists.
 	var parameters interface{}
+	var creating bool
 	if resumeToken == "" {
 		// Get the resource if it already exists, and use it to construct the desired resource parameters.
 		var existingResource interface{}
@@ -101,6 +126,7 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 			log.V(2).Info("updating resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
 		} else {
 			log.V(2).Info("creating resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+			creating = true
 		}
 	}
 
@@ -120,6 +146,15 @@ func (s *Service[C, D]) CreateOrUpdateResource(ctx context.Context, spec azure.R
 	s.Scope.DeleteLongRunningOperationState(resourceName, serviceName, futureType)
 
 	if err != nil {
+		if creating && s.adoptable != nil && azure.ResourceConflict(err) {
+			if existing, getErr := s.Creator.Get(ctx, spec); getErr == nil {
+				if !s.adoptable(spec, existing) {
+					return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s), a resource which isn't owned by the cluster or doesn't match the spec already exists with the same name", rgName, resourceName, serviceName)
+				}
+				log.V(2).Info("adopting existing resource after a conflict", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
+				return existing, nil
+			}
+		}
 		return nil, errWrapped
 	}
 
@@ -135,6 +170,7 @@ func (s *Service[C, D]) DeleteResource(ctx context.Context, spec azure.ResourceS
 	resourceName := spec.ResourceName()
 	rgName := spec.ResourceGroupName()
 	futureType := infrav1.DeleteFuture
+	tele.AddKVPs(ctx, tele.KVP("service", serviceName), tele.KVP("name", resourceName), tele.KVP("resourceGroup", rgName))
 
 	// Check for an ongoing long-running operation.
 	resumeToken := ""
@@ -167,10 +203,55 @@ func (s *Service[C, D]) DeleteResource(ctx context.Context, spec azure.ResourceS
 		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
 	}
 
+	if s.deleteConfirmer != nil {
+		if _, err := s.deleteConfirmer.Get(ctx, spec); err == nil {
+			return azure.WithTransientError(errors.Errorf("resource %s/%s still exists after being deleted (service: %s)", rgName, resourceName, serviceName), requeueTime(s.Scope))
+		} else if !azure.ResourceNotFound(err) {
+			errWrapped := errors.Wrapf(err, "failed to confirm the deletion of resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+			return azure.WithTransientError(errWrapped, getRetryAfterFromError(err))
+		}
+	}
+
 	log.V(2).Info("successfully deleted resource", "service", serviceName, "resource", resourceName, "resourceGroup", rgName)
 	return nil
 }
 
+// RecordManagedResourceIfNotFound records the resource with the given Azure resource ID as created by CAPZ if
+// the resource does not exist yet, i.e. CAPZ is about to create it. Recording before the resource is created
+// ensures ownership is not lost if the creation is still in progress when the reconciliation ends.
+func RecordManagedResourceIfNotFound(ctx context.Context, recorder azure.ResourceOwnershipRecorder, getter Getter, spec azure.ResourceSpecGetter, id string) error {
+	return RecordManagedResourceIfNotFoundOrOwned(ctx, recorder, getter, spec, id, nil)
+}
+
+// RecordManagedResourceIfNotFoundOrOwned is like RecordManagedResourceIfNotFound, but also records an existing
+// resource for which owned returns true, e.g. one tagged as owned by the cluster. This adopts the resources created by
+// CAPZ in a reconciliation interrupted before their ownership was persisted, which would otherwise be mistaken for
+// pre-existing resources and never deleted.
+func RecordManagedResourceIfNotFoundOrOwned(ctx context.Context, recorder azure.ResourceOwnershipRecorder, getter Getter, spec azure.ResourceSpecGetter, id string, owned func(existing interface{}) bool) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.RecordManagedResourceIfNotFoundOrOwned")
+	defer done()
+
+	if recorder.IsManagedResource(id, false) {
+		return nil
+	}
+
+	if existing, err := getter.Get(ctx, spec); err == nil {
+		if owned != nil && owned(existing) {
+			log.V(2).Info("adopting existing resource owned by the cluster as managed", "resource", spec.ResourceName(), "resourceGroup", spec.ResourceGroupName())
+			recorder.RecordManagedResource(id)
+			return nil
+		}
+		log.V(4).Info("not recording pre-existing resource as managed", "resource", spec.ResourceName(), "resourceGroup", spec.ResourceGroupName())
+		return nil
+	} else if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get existing resource %s/%s", spec.ResourceGroupName(), spec.ResourceName())
+	}
+
+	log.V(2).Info("recording resource as managed", "resource", spec.ResourceName(), "resourceGroup", spec.ResourceGroupName())
+	recorder.RecordManagedResource(id)
+	return nil
+}
+
 // requeueTime returns the time to wait before requeuing a reconciliation.
 // It would be ideal to use the "retry-after" header from the API response, but
 // that is not readily accessible in the SDK v2 Poller framework.
@@ -32,6 +32,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
@@ -198,6 +199,190 @@ func TestServiceCreateOrUpdateResource(t *testing.T) {
 	}
 }
 
+func TestServiceCreateOrUpdateResourceWithConflictAdoption(t *testing.T) {
+	conflictError := &azcore.ResponseError{StatusCode: http.StatusConflict}
+	testcases := []struct {
+		name           string
+		expectedError  string
+		expectedResult interface{}
+		expect         func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder)
+	}{
+		{
+			name:           "creation conflicts with the resource created by an interrupted operation",
+			expectedResult: fakeResource,
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}),
+					r.Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "", gomock.Any()).Return(nil, nil, conflictError),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil),
+				)
+			},
+		},
+		{
+			name:          "creation conflicts but the resource doesn't exist",
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service)",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}),
+					r.Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "", gomock.Any()).Return(nil, nil, conflictError),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}),
+				)
+			},
+		},
+		{
+			name:          "creation conflicts with a resource not owned by the cluster",
+			expectedError: "a resource which isn't owned by the cluster or doesn't match the spec already exists with the same name",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}),
+					r.Parameters(gomockinternal.AContext(), nil).Return(fakeParameters, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "", gomock.Any()).Return(nil, nil, conflictError),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(armresources.GenericResource{Name: ptr.To("foreign")}, nil),
+				)
+			},
+		},
+		{
+			name:          "update conflicts",
+			expectedError: "failed to create or update resource mock-resourcegroup/mock-resource (service: mock-service)",
+			expect: func(g *WithT, s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockCreatorMockRecorder[MockCreator], r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture).Return(nil),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil),
+					r.Parameters(gomockinternal.AContext(), fakeResource).Return(fakeParameters, nil),
+					c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "", gomock.Any()).Return(nil, nil, conflictError),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.PutFuture),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			creatorMock := mock_async.NewMockCreator[MockCreator](mockCtrl)
+			svc := New[MockCreator, MockDeleter](scopeMock, creatorMock, nil).WithConflictAdoption(func(_ azure.ResourceSpecGetter, existing interface{}) bool {
+				return existing.(armresources.GenericResource).Name == nil
+			})
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+
+			tc.expect(g, scopeMock.EXPECT(), creatorMock.EXPECT(), specMock.EXPECT())
+
+			result, err := svc.CreateOrUpdateResource(context.TODO(), specMock, serviceName)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				g.Expect(azure.ResourceConflict(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(tc.expectedResult))
+			}
+		})
+	}
+}
+
+func TestRecordManagedResourceIfNotFoundOrOwned(t *testing.T) {
+	const id = "/subscriptions/123/resourceGroups/mock-resourcegroup/providers/Mock/mock-resource"
+	owned := func(existing interface{}) bool {
+		return existing == "owned"
+	}
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder)
+	}{
+		{
+			name: "resource already recorded",
+			expect: func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				o.IsManagedResource(id, false).Return(true)
+			},
+		},
+		{
+			name: "resource about to be created",
+			expect: func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				o.IsManagedResource(id, false).Return(false)
+				g.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+				r.ResourceName().Return(resourceName).AnyTimes()
+				r.ResourceGroupName().Return(resourceGroupName).AnyTimes()
+				o.RecordManagedResource(id)
+			},
+		},
+		{
+			name: "resource owned by the cluster created by an interrupted reconciliation",
+			expect: func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				o.IsManagedResource(id, false).Return(false)
+				g.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return("owned", nil)
+				r.ResourceName().Return(resourceName).AnyTimes()
+				r.ResourceGroupName().Return(resourceGroupName).AnyTimes()
+				o.RecordManagedResource(id)
+			},
+		},
+		{
+			name: "pre-existing resource",
+			expect: func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				o.IsManagedResource(id, false).Return(false)
+				g.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return("unowned", nil)
+				r.ResourceName().Return(resourceName).AnyTimes()
+				r.ResourceGroupName().Return(resourceGroupName).AnyTimes()
+			},
+		},
+		{
+			name:          "resource can't be read",
+			expectedError: "failed to get existing resource mock-resourcegroup/mock-resource: foo",
+			expect: func(o *mock_azure.MockResourceOwnershipRecorderMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				o.IsManagedResource(id, false).Return(false)
+				g.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, errors.New("foo"))
+				r.ResourceName().Return(resourceName).AnyTimes()
+				r.ResourceGroupName().Return(resourceGroupName).AnyTimes()
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			recorderMock := mock_azure.NewMockResourceOwnershipRecorder(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+
+			tc.expect(recorderMock.EXPECT(), getterMock.EXPECT(), specMock.EXPECT())
+
+			err := RecordManagedResourceIfNotFoundOrOwned(context.TODO(), recorderMock, getterMock, specMock, id, owned)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestServiceDeleteResource(t *testing.T) {
 	testcases := []struct {
 		name           string
@@ -290,6 +475,115 @@ func TestServiceDeleteResource(t *testing.T) {
 	}
 }
 
+func TestServiceDeleteResourceWithDeleteConfirmation(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(g *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], c *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder)
+	}{
+		{
+			name:          "delete operation still in progress",
+			expectedError: "operation type DELETE on Azure resource mock-resourcegroup/mock-resource is not done. Object will be requeued after 15s",
+			expect: func(g *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], _ *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(validDeleteFuture),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken).Return(fakePoller[MockDeleter](g, http.StatusAccepted), context.DeadlineExceeded),
+					s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{})),
+					s.DefaultedReconcilerRequeue().Return(reconciler.DefaultReconcilerRequeue),
+				)
+			},
+		},
+		{
+			name:          "delete operation fails mid-way",
+			expectedError: "failed to delete resource mock-resourcegroup/mock-resource (service: mock-service): foo",
+			expect: func(g *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], _ *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(validDeleteFuture),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken).Return(fakePoller[MockDeleter](g, http.StatusAccepted), errors.New("foo")),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture),
+				)
+			},
+		},
+		{
+			name:          "delete operation succeeds and the resource is gone",
+			expectedError: "",
+			expect: func(_ *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], c *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(validDeleteFuture),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), resumeToken).Return(nil, nil),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}),
+				)
+			},
+		},
+		{
+			name:          "delete operation succeeds but the resource still exists",
+			expectedError: "resource mock-resourcegroup/mock-resource still exists after being deleted (service: mock-service)",
+			expect: func(_ *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], c *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(nil),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "").Return(nil, nil),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(fakeResource, nil),
+					s.DefaultedReconcilerRequeue().Return(reconciler.DefaultReconcilerRequeue),
+				)
+			},
+		},
+		{
+			name:          "delete operation succeeds but the deletion can't be confirmed",
+			expectedError: "failed to confirm the deletion of resource mock-resourcegroup/mock-resource (service: mock-service): foo",
+			expect: func(_ *GomegaWithT, s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockDeleterMockRecorder[MockDeleter], c *mock_async.MockGetterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder) {
+				gomock.InOrder(
+					r.ResourceName().Return(resourceName),
+					r.ResourceGroupName().Return(resourceGroupName),
+					s.GetLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture).Return(nil),
+					d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType), "").Return(nil, nil),
+					s.DeleteLongRunningOperationState(resourceName, serviceName, infrav1.DeleteFuture),
+					c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(azureResourceGetterType)).Return(nil, errors.New("foo")),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			deleterMock := mock_async.NewMockDeleter[MockDeleter](mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			svc := New[MockCreator, MockDeleter](scopeMock, nil, deleterMock).WithDeleteConfirmation(getterMock)
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+
+			tc.expect(g, scopeMock.EXPECT(), deleterMock.EXPECT(), getterMock.EXPECT(), specMock.EXPECT())
+
+			err := svc.DeleteResource(context.TODO(), specMock, serviceName)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				var recerr azure.ReconcileError
+				if errors.As(err, &recerr) {
+					g.Expect(recerr.IsTransient()).To(BeTrue())
+				}
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 const (
 	resourceGroupName  = "mock-resourcegroup"
 	resourceName       = "mock-resource"
@@ -30,14 +30,15 @@ import (
 
 // AzureBastionSpec defines the specification for azure bastion feature.
 type AzureBastionSpec struct {
-	Name            string
-	ResourceGroup   string
-	Location        string
-	ClusterName     string
-	SubnetID        string
-	PublicIPID      string
-	Sku             infrav1.BastionHostSkuName
-	EnableTunneling bool
+	Name                string
+	ResourceGroup       string
+	Location            string
+	ClusterName         string
+	SubnetID            string
+	PublicIPID          string
+	Sku                 infrav1.BastionHostSkuName
+	EnableTunneling     bool
+	EnableShareableLink bool
 }
 
 // ResourceRef implements azure.ASOResourceSpecGetter.
@@ -72,6 +73,7 @@ func (s *AzureBastionSpec) Parameters(ctx context.Context, existingBastionHost *
 		Name: ptr.To(asonetworkv1.Sku_Name(s.Sku)),
 	}
 	bastionHost.Spec.EnableTunneling = ptr.To(s.EnableTunneling)
+	bastionHost.Spec.EnableShareableLink = ptr.To(s.EnableShareableLink)
 	bastionHost.Spec.DnsName = ptr.To(fmt.Sprintf("%s-bastion", strings.ToLower(s.Name)))
 	bastionHost.Spec.IpConfigurations = []asonetworkv1.BastionHostIPConfiguration{
 		{
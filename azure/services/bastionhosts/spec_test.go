@@ -40,8 +40,9 @@ var (
 			Owner: &genruntime.KnownResourceReference{
 				Name: fakeAzureBastionSpec1.ResourceGroup,
 			},
-			Tags:            fakeBastionHostTags,
-			EnableTunneling: ptr.To(false),
+			Tags:                fakeBastionHostTags,
+			EnableTunneling:     ptr.To(false),
+			EnableShareableLink: ptr.To(false),
 			IpConfigurations: []asonetworkv1.BastionHostIPConfiguration{
 				{
 					Name: ptr.To(fmt.Sprintf("%s-%s", fakeAzureBastionSpec1.Name, "bastionIP")),
@@ -112,6 +113,24 @@ func TestAzureBastionSpec_Parameters(t *testing.T) {
 				g.Expect(result.Spec).To(Equal(getASOBastionHost().Spec))
 			},
 		},
+		{
+			name: "Creating a new BastionHost with a shareable link",
+			spec: &AzureBastionSpec{
+				Name:                fakeAzureBastionSpec1.Name,
+				ClusterName:         fakeAzureBastionSpec1.ClusterName,
+				Location:            fakeAzureBastionSpec1.Location,
+				SubnetID:            fakeAzureBastionSpec1.SubnetID,
+				PublicIPID:          fakeAzureBastionSpec1.PublicIPID,
+				Sku:                 infrav1.StandardBastionHostSku,
+				EnableShareableLink: true,
+			},
+			existing: nil,
+			expect: func(g *WithT, result asonetworkv1.BastionHost) {
+				g.Expect(result.Spec.Sku).To(Equal(&asonetworkv1.Sku{Name: ptr.To(asonetworkv1.Sku_Name_Standard)}))
+				g.Expect(result.Spec.EnableShareableLink).To(Equal(ptr.To(true)))
+				g.Expect(result.Spec.IpConfigurations).To(HaveLen(1))
+			},
+		},
 		{
 			name: "user updates to bastion hosts DisableCopyPaste should be accepted",
 			spec: &fakeAzureBastionSpec1,
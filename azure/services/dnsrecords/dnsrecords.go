@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "dnsrecords"
+
+// DNSRecordScope defines the scope interface for a DNS records service.
+type DNSRecordScope interface {
+	aso.Scope
+	APIServerDNSARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1.DnsZonesARecord]
+	APIServerDNSAAAARecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1.DnsZonesAAAARecord]
+	APIServerDNSCNAMERecordSpecs() []azure.ASOResourceSpecGetter[*asonetworkv1.DnsZonesCNAMERecord]
+}
+
+// Service manages the records of a cluster in Azure DNS zones. A, AAAA and CNAME records are different kinds of ASO
+// resources, so they are reconciled by one ASO service each.
+type Service struct {
+	aRecords     *aso.Service[*asonetworkv1.DnsZonesARecord, DNSRecordScope]
+	aaaaRecords  *aso.Service[*asonetworkv1.DnsZonesAAAARecord, DNSRecordScope]
+	cnameRecords *aso.Service[*asonetworkv1.DnsZonesCNAMERecord, DNSRecordScope]
+}
+
+// New creates a new service.
+func New(scope DNSRecordScope) *Service {
+	aRecords := aso.NewService[*asonetworkv1.DnsZonesARecord, DNSRecordScope](ServiceName, scope)
+	aRecords.Specs = scope.APIServerDNSARecordSpecs()
+	aRecords.ListFunc = listARecords
+	aRecords.ConditionType = infrav1.APIServerDNSRecordReadyCondition
+
+	aaaaRecords := aso.NewService[*asonetworkv1.DnsZonesAAAARecord, DNSRecordScope](ServiceName, scope)
+	aaaaRecords.Specs = scope.APIServerDNSAAAARecordSpecs()
+	aaaaRecords.ListFunc = listAAAARecords
+	aaaaRecords.ConditionType = infrav1.APIServerDNSRecordReadyCondition
+
+	cnameRecords := aso.NewService[*asonetworkv1.DnsZonesCNAMERecord, DNSRecordScope](ServiceName, scope)
+	cnameRecords.Specs = scope.APIServerDNSCNAMERecordSpecs()
+	cnameRecords.ListFunc = listCNAMERecords
+	cnameRecords.ConditionType = infrav1.APIServerDNSRecordReadyCondition
+
+	return &Service{
+		aRecords:     aRecords,
+		aaaaRecords:  aaaaRecords,
+		cnameRecords: cnameRecords,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the records.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.Service.Reconcile")
+	defer done()
+
+	// All services set the same condition, so the results of the first ones are folded into the last one.
+	cnameErr := s.cnameRecords.Reconcile(ctx)
+	s.aaaaRecords.PostReconcileHook = func(_ context.Context, _ DNSRecordScope, err error) error {
+		return mostPressingError(err, cnameErr)
+	}
+	aaaaErr := s.aaaaRecords.Reconcile(ctx)
+	s.aRecords.PostReconcileHook = func(_ context.Context, _ DNSRecordScope, err error) error {
+		return mostPressingError(err, aaaaErr)
+	}
+	return s.aRecords.Reconcile(ctx)
+}
+
+// Delete deletes the records.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsrecords.Service.Delete")
+	defer done()
+
+	cnameErr := s.cnameRecords.Delete(ctx)
+	s.aaaaRecords.PostDeleteHook = func(_ context.Context, _ DNSRecordScope, err error) error {
+		return mostPressingError(err, cnameErr)
+	}
+	aaaaErr := s.aaaaRecords.Delete(ctx)
+	s.aRecords.PostDeleteHook = func(_ context.Context, _ DNSRecordScope, err error) error {
+		return mostPressingError(err, aaaaErr)
+	}
+	return s.aRecords.Delete(ctx)
+}
+
+// Pause implements azure.Pauser.
+func (s *Service) Pause(ctx context.Context) error {
+	var _ azure.Pauser = (*Service)(nil)
+
+	if err := s.aRecords.Pause(ctx); err != nil {
+		return err
+	}
+	if err := s.aaaaRecords.Pause(ctx); err != nil {
+		return err
+	}
+	return s.cnameRecords.Pause(ctx)
+}
+
+// Retain implements azure.Retainer.
+func (s *Service) Retain(ctx context.Context) ([]string, error) {
+	var _ azure.Retainer = (*Service)(nil)
+
+	aIDs, err := s.aRecords.Retain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aaaaIDs, err := s.aaaaRecords.Retain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cnameIDs, err := s.cnameRecords.Retain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(aIDs, aaaaIDs...), cnameIDs...), nil
+}
+
+// mostPressingError returns the error that isn't an OperationNotDoneError if there is one, following the order of
+// precedence of the errors of ASO services.
+func mostPressingError(err, other error) error {
+	if err == nil || (azure.IsOperationNotDoneError(err) && other != nil) {
+		return other
+	}
+	return err
+}
+
+func listARecords(ctx context.Context, client client.Client, opts ...client.ListOption) ([]*asonetworkv1.DnsZonesARecord, error) {
+	list := &asonetworkv1.DnsZonesARecordList{}
+	err := client.List(ctx, list, opts...)
+	return slice.ToPtrs(list.Items), err
+}
+
+func listAAAARecords(ctx context.Context, client client.Client, opts ...client.ListOption) ([]*asonetworkv1.DnsZonesAAAARecord, error) {
+	list := &asonetworkv1.DnsZonesAAAARecordList{}
+	err := client.List(ctx, list, opts...)
+	return slice.ToPtrs(list.Items), err
+}
+
+func listCNAMERecords(ctx context.Context, client client.Client, opts ...client.ListOption) ([]*asonetworkv1.DnsZonesCNAMERecord, error) {
+	list := &asonetworkv1.DnsZonesCNAMERecordList{}
+	err := client.List(ctx, list, opts...)
+	return slice.ToPtrs(list.Items), err
+}
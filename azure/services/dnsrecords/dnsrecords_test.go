@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestMostPressingError(t *testing.T) {
+	notDone := azure.NewOperationNotDoneError(&infrav1.Future{})
+	failed := errors.New("failed")
+
+	testcases := []struct {
+		name     string
+		err      error
+		other    error
+		expected error
+	}{
+		{name: "no errors"},
+		{name: "only the other service failed", other: failed, expected: failed},
+		{name: "only this service failed", err: failed, expected: failed},
+		{name: "other service failed while this one is not done", err: notDone, other: failed, expected: failed},
+		{name: "this service failed while the other one is not done", err: failed, other: notDone, expected: failed},
+		{name: "this service is not done", err: notDone, expected: notDone},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := mostPressingError(tc.err, tc.other)
+			if tc.expected == nil {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// ARecordSpec defines the specification for an A record in an Azure DNS zone. The record is either an alias of a
+// public IP or the given IPv4 address.
+type ARecordSpec struct {
+	Name             string
+	ZoneID           string
+	RecordName       string
+	TTL              int32
+	PublicIPID       string
+	IPAddress        string
+	CredentialSecret string
+}
+
+// ResourceRef implements azure.ASOResourceSpecGetter.
+func (s *ARecordSpec) ResourceRef() *asonetworkv1.DnsZonesARecord {
+	return &asonetworkv1.DnsZonesARecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: s.Name,
+		},
+	}
+}
+
+// Parameters implements azure.ASOResourceSpecGetter.
+func (s *ARecordSpec) Parameters(ctx context.Context, existing *asonetworkv1.DnsZonesARecord) (*asonetworkv1.DnsZonesARecord, error) {
+	record := &asonetworkv1.DnsZonesARecord{}
+	if existing != nil {
+		record = existing
+	}
+
+	record.Spec.AzureName = s.RecordName
+	record.Spec.Owner = &genruntime.KnownResourceReference{
+		ARMID: s.ZoneID,
+	}
+	record.Spec.TTL = ptr.To(int(s.TTL))
+	record.Spec.ARecords = nil
+	record.Spec.TargetResource = nil
+	if s.PublicIPID != "" {
+		record.Spec.TargetResource = &asonetworkv1.SubResource{
+			Reference: &genruntime.ResourceReference{
+				ARMID: s.PublicIPID,
+			},
+		}
+	} else {
+		record.Spec.ARecords = []asonetworkv1.ARecord{
+			{Ipv4Address: ptr.To(s.IPAddress)},
+		}
+	}
+
+	return record, nil
+}
+
+// WasManaged implements azure.ASOResourceSpecGetter.
+func (s *ARecordSpec) WasManaged(resource *asonetworkv1.DnsZonesARecord) bool {
+	// Records don't have tags, so a record that already existed is assumed to be managed by the user.
+	return false
+}
+
+// CredentialSecretName implements aso.CredentialSecretNamer.
+func (s *ARecordSpec) CredentialSecretName() string {
+	return s.CredentialSecret
+}
+
+// AAAARecordSpec defines the specification for an AAAA record of an IPv6 address in an Azure DNS zone.
+type AAAARecordSpec struct {
+	Name             string
+	ZoneID           string
+	RecordName       string
+	TTL              int32
+	IPAddress        string
+	CredentialSecret string
+}
+
+// ResourceRef implements azure.ASOResourceSpecGetter.
+func (s *AAAARecordSpec) ResourceRef() *asonetworkv1.DnsZonesAAAARecord {
+	return &asonetworkv1.DnsZonesAAAARecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: s.Name,
+		},
+	}
+}
+
+// Parameters implements azure.ASOResourceSpecGetter.
+func (s *AAAARecordSpec) Parameters(ctx context.Context, existing *asonetworkv1.DnsZonesAAAARecord) (*asonetworkv1.DnsZonesAAAARecord, error) {
+	record := &asonetworkv1.DnsZonesAAAARecord{}
+	if existing != nil {
+		record = existing
+	}
+
+	record.Spec.AzureName = s.RecordName
+	record.Spec.Owner = &genruntime.KnownResourceReference{
+		ARMID: s.ZoneID,
+	}
+	record.Spec.TTL = ptr.To(int(s.TTL))
+	record.Spec.AAAARecords = []asonetworkv1.AaaaRecord{
+		{Ipv6Address: ptr.To(s.IPAddress)},
+	}
+
+	return record, nil
+}
+
+// WasManaged implements azure.ASOResourceSpecGetter.
+func (s *AAAARecordSpec) WasManaged(resource *asonetworkv1.DnsZonesAAAARecord) bool {
+	// Records don't have tags, so a record that already existed is assumed to be managed by the user.
+	return false
+}
+
+// CredentialSecretName implements aso.CredentialSecretNamer.
+func (s *AAAARecordSpec) CredentialSecretName() string {
+	return s.CredentialSecret
+}
+
+// CNAMERecordSpec defines the specification for a CNAME record of a host name, e.g. the FQDN of the API server of a
+// managed cluster, in an Azure DNS zone.
+type CNAMERecordSpec struct {
+	Name             string
+	ZoneID           string
+	RecordName       string
+	TTL              int32
+	Target           string
+	CredentialSecret string
+}
+
+// ResourceRef implements azure.ASOResourceSpecGetter.
+func (s *CNAMERecordSpec) ResourceRef() *asonetworkv1.DnsZonesCNAMERecord {
+	return &asonetworkv1.DnsZonesCNAMERecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: s.Name,
+		},
+	}
+}
+
+// Parameters implements azure.ASOResourceSpecGetter.
+func (s *CNAMERecordSpec) Parameters(ctx context.Context, existing *asonetworkv1.DnsZonesCNAMERecord) (*asonetworkv1.DnsZonesCNAMERecord, error) {
+	record := &asonetworkv1.DnsZonesCNAMERecord{}
+	if existing != nil {
+		record = existing
+	}
+
+	record.Spec.AzureName = s.RecordName
+	record.Spec.Owner = &genruntime.KnownResourceReference{
+		ARMID: s.ZoneID,
+	}
+	record.Spec.TTL = ptr.To(int(s.TTL))
+	record.Spec.CNAMERecord = &asonetworkv1.CnameRecord{
+		Cname: ptr.To(s.Target),
+	}
+
+	return record, nil
+}
+
+// WasManaged implements azure.ASOResourceSpecGetter.
+func (s *CNAMERecordSpec) WasManaged(resource *asonetworkv1.DnsZonesCNAMERecord) bool {
+	// Records don't have tags, so a record that already existed is assumed to be managed by the user.
+	return false
+}
+
+// CredentialSecretName implements aso.CredentialSecretNamer.
+func (s *CNAMERecordSpec) CredentialSecretName() string {
+	return s.CredentialSecret
+}
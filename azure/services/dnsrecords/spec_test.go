@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecords
+
+import (
+	"context"
+	"testing"
+
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+const fakeZoneID = "/subscriptions/123/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+
+func TestARecordSpec_Parameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     *ARecordSpec
+		existing *asonetworkv1.DnsZonesARecord
+		expected asonetworkv1.DnsZones_A_Spec
+	}{
+		{
+			name: "alias of a public IP",
+			spec: &ARecordSpec{
+				Name:       "cluster-apiserver",
+				ZoneID:     fakeZoneID,
+				RecordName: "api",
+				TTL:        300,
+				PublicIPID: "/subscriptions/123/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip",
+			},
+			expected: asonetworkv1.DnsZones_A_Spec{
+				AzureName: "api",
+				Owner:     &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+				TTL:       ptr.To(300),
+				TargetResource: &asonetworkv1.SubResource{
+					Reference: &genruntime.ResourceReference{
+						ARMID: "/subscriptions/123/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/pip",
+					},
+				},
+			},
+		},
+		{
+			name: "IPv4 address",
+			spec: &ARecordSpec{
+				Name:       "cluster-apiserver",
+				ZoneID:     fakeZoneID,
+				RecordName: "api",
+				TTL:        60,
+				IPAddress:  "10.0.0.100",
+			},
+			expected: asonetworkv1.DnsZones_A_Spec{
+				AzureName: "api",
+				Owner:     &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+				TTL:       ptr.To(60),
+				ARecords:  []asonetworkv1.ARecord{{Ipv4Address: ptr.To("10.0.0.100")}},
+			},
+		},
+		{
+			name: "existing record keeps user changes to metadata",
+			spec: &ARecordSpec{
+				Name:       "cluster-apiserver",
+				ZoneID:     fakeZoneID,
+				RecordName: "api",
+				TTL:        60,
+				IPAddress:  "10.0.0.100",
+			},
+			existing: &asonetworkv1.DnsZonesARecord{
+				Spec: asonetworkv1.DnsZones_A_Spec{
+					AzureName: "api",
+					Owner:     &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+					TTL:       ptr.To(300),
+					ARecords:  []asonetworkv1.ARecord{{Ipv4Address: ptr.To("10.0.0.4")}},
+					Metadata:  map[string]string{"team": "platform"},
+				},
+			},
+			expected: asonetworkv1.DnsZones_A_Spec{
+				AzureName: "api",
+				Owner:     &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+				TTL:       ptr.To(60),
+				ARecords:  []asonetworkv1.ARecord{{Ipv4Address: ptr.To("10.0.0.100")}},
+				Metadata:  map[string]string{"team": "platform"},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.Background(), tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.Spec).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestAAAARecordSpec_Parameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := &AAAARecordSpec{
+		Name:             "cluster-apiserver",
+		ZoneID:           fakeZoneID,
+		RecordName:       "api",
+		TTL:              300,
+		IPAddress:        "fd00::100",
+		CredentialSecret: "cluster-aso-secret-456",
+	}
+	result, err := spec.Parameters(context.Background(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Spec).To(Equal(asonetworkv1.DnsZones_AAAA_Spec{
+		AzureName:   "api",
+		Owner:       &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+		TTL:         ptr.To(300),
+		AAAARecords: []asonetworkv1.AaaaRecord{{Ipv6Address: ptr.To("fd00::100")}},
+	}))
+	g.Expect(spec.CredentialSecretName()).To(Equal("cluster-aso-secret-456"))
+}
+
+func TestCNAMERecordSpec_Parameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := &CNAMERecordSpec{
+		Name:       "cluster-apiserver",
+		ZoneID:     fakeZoneID,
+		RecordName: "api",
+		TTL:        300,
+		Target:     "cluster-dns-12345678.hcp.eastus.azmk8s.io",
+	}
+	result, err := spec.Parameters(context.Background(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Spec).To(Equal(asonetworkv1.DnsZones_CNAME_Spec{
+		AzureName:   "api",
+		Owner:       &genruntime.KnownResourceReference{ARMID: fakeZoneID},
+		TTL:         ptr.To(300),
+		CNAMERecord: &asonetworkv1.CnameRecord{Cname: ptr.To("cluster-dns-12345678.hcp.eastus.azmk8s.io")},
+	}))
+	g.Expect(spec.CredentialSecretName()).To(BeEmpty())
+}
@@ -18,19 +18,31 @@ package groups
 
 import (
 	"context"
+	"fmt"
 
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ServiceName is the name of this service.
 const ServiceName = "group"
 
+// conflictReasons are the reasons of the Ready condition of an ASO ResourceGroup which mean its spec conflicts with
+// the resource group in Azure, e.g. because the resource group was recreated in another location outside of CAPZ.
+var conflictReasons = map[string]struct{}{
+	"Conflict":                     {},
+	"InvalidResourceGroupLocation": {},
+	"ResourceGroupBeingDeleted":    {},
+}
+
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope GroupScope
@@ -41,6 +53,7 @@ type Service struct {
 type GroupScope interface {
 	aso.Scope
 	GroupSpecs() []azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]
+	SetConditionFalse(conditionType clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string)
 }
 
 // New creates a new service.
@@ -55,6 +68,34 @@ func New(scope GroupScope) *Service {
 	}
 }
 
+// Reconcile idempotently creates or updates the resource groups. When ASO reports a conflict between a resource group
+// and a change made to it outside of CAPZ, the condition of the resource group explains it.
+func (s *Service) Reconcile(ctx context.Context) error {
+	err := s.Service.Reconcile(ctx)
+	if err == nil || azure.IsOperationNotDoneError(err) {
+		return err
+	}
+
+	for _, spec := range s.Specs {
+		group := spec.ResourceRef()
+		if getErr := s.Scope.GetClient().Get(ctx, client.ObjectKey{Namespace: s.Scope.ASOOwner().GetNamespace(), Name: group.Name}, group); getErr != nil {
+			continue
+		}
+		for _, cond := range group.Status.Conditions {
+			if cond.Type != conditions.ConditionTypeReady || cond.Status == metav1.ConditionTrue {
+				continue
+			}
+			if _, ok := conflictReasons[cond.Reason]; ok {
+				s.Scope.SetConditionFalse(infrav1.ResourceGroupReadyCondition, infrav1.ResourceGroupConflictReason, clusterv1.ConditionSeverityError,
+					fmt.Sprintf("resource group %s conflicts with a change made to it outside of CAPZ, which must be reverted: %s", group.Name, cond.Message))
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
 // IsManaged returns true if all resource groups are
 // managed and reconciled by ASO, meaning that we can rely on a single resource
 // group delete operation as opposed to deleting every individual resource.
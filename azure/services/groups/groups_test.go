@@ -18,17 +18,25 @@ package groups
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso/mock_aso"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups/mock_groups"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -177,3 +185,211 @@ func TestIsManaged(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileConflict(t *testing.T) {
+	newGroup := func(status metav1.ConditionStatus, reason string) *asoresourcesv1.ResourceGroup {
+		return &asoresourcesv1.ResourceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+			Status: asoresourcesv1.ResourceGroup_STATUS{
+				Conditions: []conditions.Condition{
+					{
+						Type:    conditions.ConditionTypeReady,
+						Status:  status,
+						Reason:  reason,
+						Message: "Invalid resource group location 'eastus'. The Resource group already exists in location 'westus'.",
+					},
+				},
+			},
+		}
+	}
+	reconcileErr := errors.New("resource is not Ready")
+
+	tests := []struct {
+		name          string
+		group         *asoresourcesv1.ResourceGroup
+		reconcileErr  error
+		expect        func(s *mock_groups.MockGroupScopeMockRecorder)
+		expectedError error
+	}{
+		{
+			name:         "group is ready",
+			group:        newGroup(metav1.ConditionTrue, conditions.ReasonSucceeded),
+			reconcileErr: nil,
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder) {
+				s.UpdatePutStatus(infrav1.ResourceGroupReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:         "group conflicts with a change made outside of CAPZ",
+			group:        newGroup(metav1.ConditionFalse, "InvalidResourceGroupLocation"),
+			reconcileErr: reconcileErr,
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder) {
+				s.UpdatePutStatus(infrav1.ResourceGroupReadyCondition, ServiceName, reconcileErr)
+				s.SetConditionFalse(infrav1.ResourceGroupReadyCondition, infrav1.ResourceGroupConflictReason, clusterv1.ConditionSeverityError,
+					"resource group name conflicts with a change made to it outside of CAPZ, which must be reverted: Invalid resource group location 'eastus'. The Resource group already exists in location 'westus'.")
+			},
+			expectedError: reconcileErr,
+		},
+		{
+			name:         "group fails for another reason",
+			group:        newGroup(metav1.ConditionFalse, "InternalServerError"),
+			reconcileErr: reconcileErr,
+			expect: func(s *mock_groups.MockGroupScopeMockRecorder) {
+				s.UpdatePutStatus(infrav1.ResourceGroupReadyCondition, ServiceName, reconcileErr)
+			},
+			expectedError: reconcileErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_groups.NewMockGroupScope(mockCtrl)
+			reconcilerMock := mock_aso.NewMockReconciler[*asoresourcesv1.ResourceGroup](mockCtrl)
+
+			scheme := runtime.NewScheme()
+			g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+			ctrlClient := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(test.group).
+				Build()
+			spec := &GroupSpec{Name: "name"}
+			scopeMock.EXPECT().GetClient().Return(ctrlClient).AnyTimes()
+			scopeMock.EXPECT().ClusterName().Return("cluster").AnyTimes()
+			scopeMock.EXPECT().ASOOwner().Return(&asoresourcesv1.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace"}}).AnyTimes()
+			scopeMock.EXPECT().GroupSpecs().Return([]azure.ASOResourceSpecGetter[*asoresourcesv1.ResourceGroup]{spec})
+			scopeMock.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+			reconcilerMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), spec, ServiceName).Return(nil, test.reconcileErr)
+			test.expect(scopeMock.EXPECT())
+
+			s := New(scopeMock)
+			s.ListFunc = nil
+			s.Reconciler = reconcilerMock
+
+			err := s.Reconcile(context.Background())
+			if test.expectedError != nil {
+				g.Expect(err).To(MatchError(test.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		lastApplied    string
+		existingTags   infrav1.Tags
+		additionalTags infrav1.Tags
+		expectedTags   infrav1.Tags
+	}{
+		{
+			name:           "tag is added",
+			lastApplied:    `{"foo":"bar"}`,
+			existingTags:   infrav1.Tags{"foo": "bar"},
+			additionalTags: infrav1.Tags{"foo": "bar", "thing": "stuff"},
+			expectedTags:   infrav1.Tags{"foo": "bar", "thing": "stuff"},
+		},
+		{
+			name:           "tag is updated",
+			lastApplied:    `{"foo":"bar"}`,
+			existingTags:   infrav1.Tags{"foo": "bar"},
+			additionalTags: infrav1.Tags{"foo": "baz"},
+			expectedTags:   infrav1.Tags{"foo": "baz"},
+		},
+		{
+			name:           "tag is removed",
+			lastApplied:    `{"foo":"bar","thing":"stuff"}`,
+			existingTags:   infrav1.Tags{"foo": "bar", "thing": "stuff"},
+			additionalTags: infrav1.Tags{"foo": "bar"},
+			expectedTags:   infrav1.Tags{"foo": "bar"},
+		},
+		{
+			name:           "tag set outside of CAPZ is kept",
+			lastApplied:    `{"foo":"bar"}`,
+			existingTags:   infrav1.Tags{"foo": "bar", "external": "tag"},
+			additionalTags: nil,
+			expectedTags:   infrav1.Tags{"external": "tag"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			owner := &asoresourcesv1.ResourceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "owner",
+					Namespace: "namespace",
+				},
+			}
+			scheme := runtime.NewScheme()
+			g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+			gvk, err := apiutil.GVKForObject(owner, scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// The existing resource group was created with the tags which were last applied.
+			existing, err := (&GroupSpec{
+				Name:           "name",
+				Location:       "westus",
+				ClusterName:    "cluster",
+				AdditionalTags: test.existingTags,
+			}).Parameters(ctx, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+			existing.Name = "name"
+			existing.Namespace = "namespace"
+			existing.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: gvk.GroupVersion().String(),
+					Kind:       gvk.Kind,
+					Name:       owner.Name,
+					Controller: ptr.To(true),
+				},
+			}
+			existing.Annotations = map[string]string{
+				asoannotations.ReconcilePolicy:                             string(asoannotations.ReconcilePolicyManage),
+				"sigs.k8s.io/cluster-api-provider-azure-last-applied-tags": test.lastApplied,
+			}
+			existing.Status.Conditions = []conditions.Condition{
+				{
+					Type:   conditions.ConditionTypeReady,
+					Status: metav1.ConditionTrue,
+				},
+			}
+			ctrlClient := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(existing).
+				Build()
+
+			spec := &GroupSpec{
+				Name:           "name",
+				Location:       "westus",
+				ClusterName:    "cluster",
+				AdditionalTags: test.additionalTags,
+			}
+			_, err = aso.New[*asoresourcesv1.ResourceGroup](ctrlClient, "cluster", owner).CreateOrUpdateResource(ctx, spec, ServiceName)
+			g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+			updated := &asoresourcesv1.ResourceGroup{}
+			g.Expect(ctrlClient.Get(ctx, client.ObjectKeyFromObject(existing), updated)).To(Succeed())
+			g.Expect(updated.Spec.Location).To(Equal(ptr.To("westus")))
+			for k, v := range test.expectedTags {
+				g.Expect(updated.Spec.Tags).To(HaveKeyWithValue(k, v))
+			}
+			for k := range test.existingTags {
+				if _, ok := test.expectedTags[k]; !ok {
+					g.Expect(updated.Spec.Tags).NotTo(HaveKey(k))
+				}
+			}
+			g.Expect(infrav1.Tags(updated.Spec.Tags).HasOwned("cluster")).To(BeTrue())
+		})
+	}
+}
@@ -184,6 +184,18 @@ func (mr *MockGroupScopeMockRecorder) GroupSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupSpecs", reflect.TypeOf((*MockGroupScope)(nil).GroupSpecs))
 }
 
+// SetConditionFalse mocks base method.
+func (m *MockGroupScope) SetConditionFalse(conditionType v1beta10.ConditionType, reason string, severity v1beta10.ConditionSeverity, message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConditionFalse", conditionType, reason, severity, message)
+}
+
+// SetConditionFalse indicates an expected call of SetConditionFalse.
+func (mr *MockGroupScopeMockRecorder) SetConditionFalse(conditionType, reason, severity, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConditionFalse", reflect.TypeOf((*MockGroupScope)(nil).SetConditionFalse), conditionType, reason, severity, message)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockGroupScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -31,6 +31,7 @@ import (
 type Client interface {
 	Get(ctx context.Context, resourceGroupName, name string) (armmsi.Identity, error)
 	GetClientID(ctx context.Context, providerID string) (string, error)
+	GetPrincipalID(ctx context.Context, providerID string) (string, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
@@ -78,3 +79,19 @@ func (ac *AzureClient) GetClientID(ctx context.Context, providerID string) (stri
 	}
 	return ptr.Deref(ident.Properties.ClientID, ""), nil
 }
+
+// GetPrincipalID returns the principal ID of a managed service identity, given its full URL identifier.
+func (ac *AzureClient) GetPrincipalID(ctx context.Context, providerID string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "identities.AzureClient.GetPrincipalID")
+	defer done()
+
+	parsed, err := azureutil.ParseResourceID(providerID)
+	if err != nil {
+		return "", err
+	}
+	ident, err := ac.Get(ctx, parsed.ResourceGroupName, parsed.Name)
+	if err != nil {
+		return "", err
+	}
+	return ptr.Deref(ident.Properties.PrincipalID, ""), nil
+}
@@ -85,3 +85,18 @@ func (mr *MockClientMockRecorder) GetClientID(ctx, providerID any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientID", reflect.TypeOf((*MockClient)(nil).GetClientID), ctx, providerID)
 }
+
+// GetPrincipalID mocks base method.
+func (m *MockClient) GetPrincipalID(ctx context.Context, providerID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrincipalID", ctx, providerID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrincipalID indicates an expected call of GetPrincipalID.
+func (mr *MockClientMockRecorder) GetPrincipalID(ctx, providerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrincipalID", reflect.TypeOf((*MockClient)(nil).GetPrincipalID), ctx, providerID)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
@@ -33,6 +34,7 @@ const serviceName = "inboundnatrules"
 type InboundNatScope interface {
 	azure.ClusterDescriber
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	APIServerLBName() string
 	InboundNatSpecs() []azure.ResourceSpecGetter
 }
@@ -41,6 +43,7 @@ type InboundNatScope interface {
 type Service struct {
 	Scope InboundNatScope
 	client
+	async.Getter
 	async.Reconciler
 }
 
@@ -53,8 +56,9 @@ func New(scope InboundNatScope) (*Service, error) {
 	return &Service{
 		Scope:  scope,
 		client: client,
+		Getter: client,
 		Reconciler: async.New[armnetwork.InboundNatRulesClientCreateOrUpdateResponse,
-			armnetwork.InboundNatRulesClientDeleteResponse](scope, client, client),
+			armnetwork.InboundNatRulesClientDeleteResponse](scope, client, client).WithConflictAdoption(isAdoptableNatRule),
 	}, nil
 }
 
@@ -90,19 +94,34 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	portsInUse := make(map[int32]struct{})
+	existingPorts := make(map[string]int32)
 	for _, rule := range existingRules {
 		portsInUse[*rule.Properties.FrontendPort] = struct{}{} // Mark frontend port as in use
+		if rule.Name != nil {
+			existingPorts[*rule.Name] = *rule.Properties.FrontendPort
+		}
 	}
 
 	// We go through the list of InboundNatSpecs to reconcile each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
+	recordOwnership := s.Scope.IsOwnershipRecorded()
 	for _, spec := range specs {
-		// Find an available SSH port for the rule.
-		sshFrontendPort, err := getAvailableSSHFrontendPort(portsInUse)
-		if err != nil {
-			return errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", spec.ResourceName(), spec.OwnerResourceName())
+		if recordOwnership {
+			if err := async.RecordManagedResourceIfNotFoundOrOwned(ctx, s.Scope, s.Getter, spec, s.natRuleID(spec), isSSHNatRule); err != nil {
+				result = err
+				continue
+			}
+		}
+		// Keep the port of a rule which already exists, e.g. one created by an interrupted reconciliation, so that
+		// retrying its creation sends the same parameters. Otherwise, find an available SSH port for the rule.
+		sshFrontendPort, exists := existingPorts[spec.ResourceName()]
+		if !exists {
+			sshFrontendPort, err = getAvailableSSHFrontendPort(portsInUse)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", spec.ResourceName(), spec.OwnerResourceName())
+			}
 		}
 		natRule, ok := spec.(*InboundNatSpec)
 		if !ok {
@@ -145,6 +164,8 @@ func (s *Service) Delete(ctx context.Context) error {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+		} else {
+			s.Scope.ForgetManagedResource(s.natRuleID(natRule))
 		}
 	}
 
@@ -152,6 +173,36 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// isSSHNatRule returns true if the existing inbound NAT rule forwards one of the SSH frontend ports CAPZ allocates to
+// port 22, i.e. it was created by CAPZ for the machine it is named after even though its ownership wasn't recorded.
+// Inbound NAT rules can't be tagged.
+func isSSHNatRule(existing interface{}) bool {
+	rule, ok := existing.(armnetwork.InboundNatRule)
+	if !ok || rule.Properties == nil || ptr.Deref(rule.Properties.BackendPort, 0) != 22 {
+		return false
+	}
+	port := ptr.Deref(rule.Properties.FrontendPort, 0)
+	return port == 22 || (port >= 2201 && port < 2220)
+}
+
+// isAdoptableNatRule returns true if the existing inbound NAT rule conflicting with the creation of the rule of spec
+// can be adopted, i.e. it is an SSH rule created by CAPZ forwarding the frontend port of spec.
+func isAdoptableNatRule(spec azure.ResourceSpecGetter, existing interface{}) bool {
+	if !isSSHNatRule(existing) {
+		return false
+	}
+	natSpec, ok := spec.(*InboundNatSpec)
+	if !ok || natSpec.SSHFrontendPort == nil {
+		return true
+	}
+	return ptr.Deref(existing.(armnetwork.InboundNatRule).Properties.FrontendPort, 0) == *natSpec.SSHFrontendPort
+}
+
+// natRuleID returns the Azure resource ID of the inbound NAT rule.
+func (s *Service) natRuleID(spec azure.ResourceSpecGetter) string {
+	return azure.NATRuleID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName())
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO inbound NAT rules.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -71,6 +71,7 @@ var (
 		FrontendIPConfigurationID: ptr.To("frontend-ip-config-id-2"),
 	}
 
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
 	internalError = &azcore.ResponseError{
 		RawResponse: &http.Response{
 			Body:       io.NopCloser(strings.NewReader("#: Internal Server Error: StatusCode=500")),
@@ -96,6 +97,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 		expectedError string
 		expect        func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 			m *mock_inboundnatrules.MockclientMockRecorder,
+			g *mock_async.MockGetterMockRecorder,
 			r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
@@ -103,6 +105,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
@@ -115,12 +118,14 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
 				s.APIServerLBName().AnyTimes().Return(fakeLBName)
 				m.List(gomockinternal.AContext(), fakeGroupName, fakeLBName).Return(noExistingRules, nil)
 				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec), getFakeNatSpecWithoutPort(fakeNatSpec2)})
+				s.IsOwnershipRecorded().Return(false)
 				gomock.InOrder(
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 22), serviceName).Return(nil, nil),
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec2, 2201), serviceName).Return(nil, nil),
@@ -133,12 +138,14 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
 				s.APIServerLBName().AnyTimes().Return("my-lb")
 				m.List(gomockinternal.AContext(), fakeGroupName, "my-lb").Return(fakeExistingRules, nil)
 				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec)})
+				s.IsOwnershipRecorded().Return(false)
 				gomock.InOrder(
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 2202), serviceName).Return(nil, nil),
 					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
@@ -150,6 +157,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.APIServerLBName().AnyTimes().Return("")
@@ -160,6 +168,7 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: `failed to get existing NAT rules:.*#: Internal Server Error: StatusCode=500`,
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
@@ -174,18 +183,78 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			expectedError: "#: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
 				s.APIServerLBName().AnyTimes().Return("my-lb")
 				m.List(gomockinternal.AContext(), fakeGroupName, "my-lb").Return(fakeExistingRules, nil)
 				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{&fakeNatSpec})
+				s.IsOwnershipRecorded().Return(false)
 				gomock.InOrder(
 					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 2202), serviceName).Return(nil, internalError),
 					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, internalError),
 				)
 			},
 		},
+		{
+			name:          "NAT rules are recorded before they are created when ownership is recorded",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
+				s.APIServerLBName().AnyTimes().Return(fakeLBName)
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.List(gomockinternal.AContext(), fakeGroupName, fakeLBName).Return(noExistingRules, nil)
+				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec)})
+				s.IsOwnershipRecorded().Return(true)
+				gomock.InOrder(
+					s.IsManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-1"), false).Return(false),
+					g.Get(gomockinternal.AContext(), getFakeNatSpecWithoutPort(fakeNatSpec)).Return(nil, notFoundError),
+					s.RecordManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-1")),
+					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 22), serviceName).Return(nil, nil),
+					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
+				)
+			},
+		},
+		{
+			name:          "NAT rule created by an interrupted reconciliation is adopted with its port",
+			expectedError: "",
+			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
+				m *mock_inboundnatrules.MockclientMockRecorder,
+				g *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				interruptedRule := armnetwork.InboundNatRule{
+					Name: ptr.To("my-machine-1"),
+					ID:   ptr.To("my-machine-1-natrule-id"),
+					Properties: &armnetwork.InboundNatRulePropertiesFormat{
+						BackendPort:  ptr.To[int32](22),
+						FrontendPort: ptr.To[int32](2201),
+					},
+				}
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
+				s.APIServerLBName().AnyTimes().Return(fakeLBName)
+				s.SubscriptionID().AnyTimes().Return("123")
+				m.List(gomockinternal.AContext(), fakeGroupName, fakeLBName).Return([]armnetwork.InboundNatRule{fakeExistingRules[0], interruptedRule}, nil)
+				s.InboundNatSpecs().Return([]azure.ResourceSpecGetter{getFakeNatSpecWithoutPort(fakeNatSpec), getFakeNatSpecWithoutPort(fakeNatSpec2)})
+				s.IsOwnershipRecorded().Return(true)
+				gomock.InOrder(
+					s.IsManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-1"), false).Return(false),
+					g.Get(gomockinternal.AContext(), getFakeNatSpecWithoutPort(fakeNatSpec)).Return(interruptedRule, nil),
+					s.RecordManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-1")),
+					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec, 2201), serviceName).Return(nil, nil),
+					s.IsManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-2"), false).Return(false),
+					g.Get(gomockinternal.AContext(), getFakeNatSpecWithoutPort(fakeNatSpec2)).Return(nil, notFoundError),
+					s.RecordManagedResource(azure.NATRuleID("123", fakeGroupName, "my-lb-1", "my-machine-2")),
+					r.CreateOrUpdateResource(gomockinternal.AContext(), getFakeNatSpecWithPort(fakeNatSpec2, 2202), serviceName).Return(nil, nil),
+					s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -197,13 +266,15 @@ func TestReconcileInboundNATRule(t *testing.T) {
 			defer mockCtrl.Finish()
 			scopeMock := mock_inboundnatrules.NewMockInboundNatScope(mockCtrl)
 			clientMock := mock_inboundnatrules.NewMockclient(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), getterMock.EXPECT(), asyncMock.EXPECT())
 
 			s := &Service{
 				Scope:      scopeMock,
 				client:     clientMock,
+				Getter:     getterMock,
 				Reconciler: asyncMock,
 			}
 
@@ -245,6 +316,8 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.APIServerLBName().AnyTimes().Return(fakeLBName)
 				gomock.InOrder(
 					r.DeleteResource(gomockinternal.AContext(), &fakeNatSpec, serviceName).Return(nil),
+					s.SubscriptionID().Return("123"),
+					s.ForgetManagedResource(azure.NATRuleID("123", fakeNatSpec.ResourceGroupName(), fakeNatSpec.OwnerResourceName(), fakeNatSpec.ResourceName())),
 					s.UpdateDeleteStatus(infrav1.InboundNATRulesReadyCondition, serviceName, nil),
 				)
 			},
@@ -295,6 +295,18 @@ func (mr *MockInboundNatScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockInboundNatScope)(nil).FailureDomains))
 }
 
+// ForgetManagedResource mocks base method.
+func (m *MockInboundNatScope) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockInboundNatScopeMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockInboundNatScope)(nil).ForgetManagedResource), id)
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockInboundNatScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -337,6 +349,34 @@ func (mr *MockInboundNatScopeMockRecorder) InboundNatSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InboundNatSpecs", reflect.TypeOf((*MockInboundNatScope)(nil).InboundNatSpecs))
 }
 
+// IsManagedResource mocks base method.
+func (m *MockInboundNatScope) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockInboundNatScopeMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockInboundNatScope)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockInboundNatScope) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockInboundNatScopeMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockInboundNatScope)(nil).IsOwnershipRecorded))
+}
+
 // Location mocks base method.
 func (m *MockInboundNatScope) Location() string {
 	m.ctrl.T.Helper()
@@ -365,6 +405,18 @@ func (mr *MockInboundNatScopeMockRecorder) NodeResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockInboundNatScope)(nil).NodeResourceGroup))
 }
 
+// RecordManagedResource mocks base method.
+func (m *MockInboundNatScope) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockInboundNatScopeMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockInboundNatScope)(nil).RecordManagedResource), id)
+}
+
 // ResourceGroup mocks base method.
 func (m *MockInboundNatScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
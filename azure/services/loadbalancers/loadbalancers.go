@@ -23,28 +23,64 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 const (
 	serviceName           = "loadbalancers"
 	httpsProbe            = "HTTPSProbe"
+	tcpProbe              = "TCPProbe"
 	httpsProbeRequestPath = "/readyz"
 	lbRuleHTTPS           = "LBRuleHTTPS"
 	outboundNAT           = "OutboundNATAllProtocols"
+
+	// additionalPortLBRulePrefix and additionalPortProbePrefix prefix the names of the load balancing rules and health
+	// probes of the additional API server ports, so that the ones removed from the spec can be told apart and deleted.
+	additionalPortLBRulePrefix = "LBRuleAdditionalPort-"
+	additionalPortProbePrefix  = "ProbeAdditionalPort-"
+
+	defaultProbeIntervalInSeconds int32 = 15
+	defaultNumberOfProbes         int32 = 4
 )
 
 // LBScope defines the scope interface for a load balancer service.
 type LBScope interface {
 	azure.ClusterScoper
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	LBSpecs() []azure.ResourceSpecGetter
+	APIServerHost() string
+	APIServerPort() int32
+	IsAPIServerLBMigrationRequested() bool
+	StartAPIServerLBMigration()
+	SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationPhase)
+	SetAPIServerLBMigrationProgress(reason, message string)
+	ClearAPIServerLBMigration()
+	ControlPlaneCertificatesRolledOut(ctx context.Context, host string) (bool, error)
+	SwitchControlPlaneEndpoint(ctx context.Context) error
+	MachinesCreatedBeforeEndpointSwitch(ctx context.Context) ([]string, error)
+	RestrictAPIServerSecurityRule()
+}
+
+// availabilityChecker tells whether Azure Resource Health reports a resource as available.
+type availabilityChecker interface {
+	Available(ctx context.Context, resourceURI string) (bool, string, error)
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope LBScope
 	async.Reconciler
+
+	// publicIPGetter and publicIPReconciler delete the public IPs of the public API server load balancer once it is
+	// migrated to Internal.
+	publicIPGetter     async.Getter
+	publicIPReconciler async.Reconciler
+	// availabilityChecker tells whether the backends of the internal API server load balancer pass their health probes
+	// while it is migrated.
+	availabilityChecker availabilityChecker
 }
 
 // New creates a new service.
@@ -53,10 +89,22 @@ func New(scope LBScope) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	publicIPClient, err := publicips.NewClient(scope, scope.DefaultedAzureCallTimeout())
+	if err != nil {
+		return nil, err
+	}
+	availabilityChecker, err := resourcehealth.NewAvailabilityChecker(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope: scope,
 		Reconciler: async.New[armnetwork.LoadBalancersClientCreateOrUpdateResponse,
 			armnetwork.LoadBalancersClientDeleteResponse](scope, client, client),
+		publicIPGetter: publicIPClient,
+		publicIPReconciler: async.New[armnetwork.PublicIPAddressesClientCreateOrUpdateResponse,
+			armnetwork.PublicIPAddressesClientDeleteResponse](scope, publicIPClient, publicIPClient),
+		availabilityChecker: availabilityChecker,
 	}, nil
 }
 
@@ -73,6 +121,8 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
 	defer cancel()
 
+	s.startCreatingInternalLB(ctx)
+
 	specs := s.Scope.LBSpecs()
 	if len(specs) == 0 {
 		return nil
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// apiServerLBMigrationRequeue is the delay before checking again whether the migration of the API server load
+	// balancer can move on to its next phase.
+	apiServerLBMigrationRequeue = 30 * time.Second
+
+	// apiServerLBMigrationRolloutRequeue is the delay before checking again whether the machines rolled out by the
+	// migration of the API server load balancer are replaced.
+	apiServerLBMigrationRolloutRequeue = 2 * time.Minute
+
+	// maxReportedMachines is the maximum number of machines waited for that are named in the conditions of the
+	// migration of the API server load balancer.
+	maxReportedMachines = 5
+)
+
+// ReconcileAPIServerLBMigration migrates the API server load balancer from Public to Internal when requested with the
+// MigrateAPIServerLBToInternalAnnotation. The phase of the migration is recorded in the status of the cluster, so
+// that a migration interrupted at any point resumes where it stopped:
+//   - Pending: the public load balancer is recorded until the type of the API server load balancer is changed.
+//   - CreatingInternalLB: the internal load balancer is created along with the other load balancers.
+//   - SwitchingEndpoint: the control plane machines are added to the internal load balancer. Once Azure Resource
+//     Health reports it as available, i.e. once its backends pass their health probes, the host of the internal load
+//     balancer is added to the certificate SANs of the control plane, and the control plane endpoint is switched to
+//     it once the control plane is rolled out.
+//   - ReplacingMachines: the control plane is rolled out again, and the public load balancer is kept until all the
+//     machines created before the switch, whose kubelets still reach the API server through it, are replaced.
+//   - DeletingPublicLB: the control plane machines are removed from the public load balancer, which is then deleted
+//     along with its public IPs.
+//
+// It is called once all the services of the cluster are reconciled, so that the internal load balancer and its
+// private DNS record exist.
+func (s *Service) ReconcileAPIServerLBMigration(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.ReconcileAPIServerLBMigration")
+	defer done()
+
+	migration := s.Scope.APIServerLBMigration()
+	if migration == nil {
+		if s.Scope.IsAPIServerLBMigrationRequested() && !s.Scope.IsAPIServerPrivate() {
+			log.Info("recording the public API server load balancer to migrate it to Internal", "loadBalancer", s.Scope.APIServerLBName())
+			s.Scope.StartAPIServerLBMigration()
+		}
+		return nil
+	}
+
+	switch migration.Phase {
+	case infrav1.APIServerLBMigrationPending:
+		// The migration is only canceled until the type of the API server load balancer is changed.
+		if !s.Scope.IsAPIServerLBMigrationRequested() {
+			s.Scope.ClearAPIServerLBMigration()
+		}
+		return nil
+
+	case infrav1.APIServerLBMigrationCreatingInternalLB:
+		// The internal load balancer exists once all the services are reconciled, so the control plane machines can be
+		// added to its backend pool.
+		s.Scope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationSwitchingEndpoint)
+		return nil
+
+	case infrav1.APIServerLBMigrationSwitchingEndpoint:
+		return s.switchControlPlaneEndpoint(ctx)
+
+	case infrav1.APIServerLBMigrationReplacingMachines:
+		machines, err := s.Scope.MachinesCreatedBeforeEndpointSwitch(ctx)
+		if err != nil {
+			return err
+		}
+		if len(machines) > 0 {
+			message := fmt.Sprintf("waiting for %d machines created before the control plane endpoint was switched to be replaced: %s", len(machines), reportedMachines(machines))
+			s.Scope.SetAPIServerLBMigrationProgress(infrav1.WaitingForMachinesReplacementReason, message)
+			return azure.WithTransientError(errors.New(message), apiServerLBMigrationRolloutRequeue)
+		}
+		s.Scope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationDeletingPublicLB)
+		return nil
+
+	case infrav1.APIServerLBMigrationDeletingPublicLB:
+		if err := s.deletePublicAPIServerLB(ctx, migration); err != nil {
+			return err
+		}
+		log.Info("migrated the API server load balancer to Internal", "deletedLoadBalancer", migration.PublicLBName)
+		s.Scope.RestrictAPIServerSecurityRule()
+		s.Scope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCompleted)
+		return nil
+
+	default:
+		// The completed migration is reported until the annotation is removed.
+		if !s.Scope.IsAPIServerLBMigrationRequested() {
+			s.Scope.ClearAPIServerLBMigration()
+		}
+		return nil
+	}
+}
+
+// switchControlPlaneEndpoint switches the control plane endpoint to the internal API server load balancer once Azure
+// Resource Health reports it as available, and once the control plane is rolled out with certificates valid for its
+// host. Dialing the frontend of the internal load balancer isn't relied upon, as the management cluster may not be
+// able to reach it.
+func (s *Service) switchControlPlaneEndpoint(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.switchControlPlaneEndpoint")
+	defer done()
+
+	lbID := azure.LoadBalancerID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), s.Scope.APIServerLBName())
+	available, summary, err := s.availabilityChecker.Available(ctx, lbID)
+	if err != nil {
+		return azure.WithTransientError(err, apiServerLBMigrationRequeue)
+	}
+	if !available {
+		message := "waiting for Azure Resource Health to report the internal API server load balancer as available"
+		if summary != "" {
+			message = fmt.Sprintf("%s: %s", message, summary)
+		}
+		s.Scope.SetAPIServerLBMigrationProgress(infrav1.InternalAPIServerLBUnavailableReason, message)
+		return azure.WithTransientError(errors.New(message), apiServerLBMigrationRequeue)
+	}
+
+	host := s.Scope.APIServerHost()
+	rolledOut, err := s.Scope.ControlPlaneCertificatesRolledOut(ctx, host)
+	if err != nil {
+		return err
+	}
+	if !rolledOut {
+		message := fmt.Sprintf("waiting for the control plane to be rolled out with certificates valid for %s", host)
+		s.Scope.SetAPIServerLBMigrationProgress(infrav1.ControlPlaneCertificatesRollingOutReason, message)
+		return azure.WithTransientError(errors.New(message), apiServerLBMigrationRolloutRequeue)
+	}
+
+	log.Info("switching the control plane endpoint to the internal API server load balancer", "host", host)
+	if err := s.Scope.SwitchControlPlaneEndpoint(ctx); err != nil {
+		return err
+	}
+	s.Scope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationReplacingMachines)
+	return nil
+}
+
+// reportedMachines returns the names of the first machines waited for, to be reported in a condition.
+func reportedMachines(machines []string) string {
+	if len(machines) <= maxReportedMachines {
+		return strings.Join(machines, ", ")
+	}
+	return strings.Join(machines[:maxReportedMachines], ", ") + ", ..."
+}
+
+// startCreatingInternalLB moves a pending migration of the API server load balancer to its CreatingInternalLB phase
+// once the type of the API server load balancer is changed to Internal, before the internal load balancer is created.
+func (s *Service) startCreatingInternalLB(ctx context.Context) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.startCreatingInternalLB")
+	defer done()
+
+	migration := s.Scope.APIServerLBMigration()
+	if migration == nil || migration.Phase != infrav1.APIServerLBMigrationPending || !s.Scope.IsAPIServerPrivate() {
+		return
+	}
+	log.Info("migrating the API server load balancer to Internal", "publicLoadBalancer", migration.PublicLBName, "internalLoadBalancer", s.Scope.APIServerLBName())
+	s.Scope.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCreatingInternalLB)
+}
+
+// deletePublicAPIServerLB deletes the public API server load balancer recorded by the migration, then its public IPs.
+// Azure refuses to delete the load balancer until the control plane machines are removed from it.
+func (s *Service) deletePublicAPIServerLB(ctx context.Context, migration *infrav1.APIServerLBMigration) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.deletePublicAPIServerLB")
+	defer done()
+
+	lbSpec := &LBSpec{
+		Name:          migration.PublicLBName,
+		ResourceGroup: s.Scope.ResourceGroup(),
+	}
+	if err := s.DeleteResource(ctx, lbSpec, serviceName); err != nil {
+		if azure.IsOperationNotDoneError(err) {
+			return err
+		}
+		return azure.WithTransientError(errors.Wrapf(err, "failed to delete the public API server load balancer %s", migration.PublicLBName), apiServerLBMigrationRequeue)
+	}
+
+	for _, name := range migration.PublicIPNames {
+		ipSpec := &publicips.PublicIPSpec{
+			Name:          name,
+			ResourceGroup: s.Scope.ResourceGroup(),
+		}
+		id := azure.PublicIPID(s.Scope.SubscriptionID(), ipSpec.ResourceGroup, name)
+		existing, err := s.publicIPGetter.Get(ctx, ipSpec)
+		if azure.ResourceNotFound(err) {
+			s.Scope.ForgetManagedResource(id)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get public IP %s", name)
+		}
+		var ownedByTags bool
+		if ip, ok := existing.(armnetwork.PublicIPAddress); ok {
+			ownedByTags = converters.MapToTags(ip.Tags).HasOwned(s.Scope.ClusterName())
+		}
+		if !s.Scope.IsManagedResource(id, ownedByTags) {
+			log.V(2).Info("skipping the deletion of an unmanaged public IP", "publicIP", name)
+			continue
+		}
+		if err := s.publicIPReconciler.DeleteResource(ctx, ipSpec, serviceName); err != nil {
+			return errors.Wrapf(err, "failed to delete public IP %s", name)
+		}
+		s.Scope.ForgetManagedResource(id)
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers/mock_loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+var (
+	fakeMigration = infrav1.APIServerLBMigration{
+		PublicLBName:            "my-publiclb",
+		PublicLBBackendPoolName: "my-publiclb-backendPool",
+		PublicLBFrontendIPName:  "my-publiclb-frontEnd",
+		PublicIPNames:           []string{"my-publicip"},
+	}
+
+	fakePublicLBToDelete = &LBSpec{
+		Name:          "my-publiclb",
+		ResourceGroup: "my-rg",
+	}
+
+	fakePublicIPToDelete = &publicips.PublicIPSpec{
+		Name:          "my-publicip",
+		ResourceGroup: "my-rg",
+	}
+
+	fakePublicIPID = azure.PublicIPID("123", "my-rg", "my-publicip")
+
+	fakeInternalLBID = azure.LoadBalancerID("123", "my-rg", "my-internallb")
+
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+)
+
+func migrationInPhase(phase infrav1.APIServerLBMigrationPhase) *infrav1.APIServerLBMigration {
+	migration := fakeMigration.DeepCopy()
+	migration.Phase = phase
+	return migration
+}
+
+func TestReconcileAPIServerLBMigration(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		transient     bool
+		expect        func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder, ipr *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder)
+	}{
+		{
+			name: "noop if no migration is requested",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(nil)
+				s.IsAPIServerLBMigrationRequested().Return(false)
+			},
+		},
+		{
+			name: "noop if the API server load balancer is already internal",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(nil)
+				s.IsAPIServerLBMigrationRequested().Return(true)
+				s.IsAPIServerPrivate().Return(true)
+			},
+		},
+		{
+			name: "record the public load balancer once the migration is requested",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(nil)
+				s.IsAPIServerLBMigrationRequested().Return(true)
+				s.IsAPIServerPrivate().Return(false)
+				s.APIServerLBName().Return("my-publiclb")
+				s.StartAPIServerLBMigration()
+			},
+		},
+		{
+			name: "wait for the type of the load balancer to change while the migration is pending",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationPending))
+				s.IsAPIServerLBMigrationRequested().Return(true)
+			},
+		},
+		{
+			name: "cancel a pending migration once the annotation is removed",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationPending))
+				s.IsAPIServerLBMigrationRequested().Return(false)
+				s.ClearAPIServerLBMigration()
+			},
+		},
+		{
+			name: "add the control plane machines to the internal load balancer once it is created",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationCreatingInternalLB))
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationSwitchingEndpoint)
+			},
+		},
+		{
+			name:          "keep the control plane endpoint until the internal load balancer is available",
+			expectedError: "waiting for Azure Resource Health to report the internal API server load balancer as available: no backend passes its health probe",
+			transient:     true,
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(false, "no backend passes its health probe", nil)
+				s.SetAPIServerLBMigrationProgress(infrav1.InternalAPIServerLBUnavailableReason, "waiting for Azure Resource Health to report the internal API server load balancer as available: no backend passes its health probe")
+			},
+		},
+		{
+			name:          "retry getting the availability status of the internal load balancer",
+			expectedError: "some API error",
+			transient:     true,
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(false, "", errors.New("some API error"))
+			},
+		},
+		{
+			name:          "keep the control plane endpoint until the control plane is rolled out with certificates valid for the internal load balancer",
+			expectedError: "waiting for the control plane to be rolled out with certificates valid for apiserver.my-cluster.capz.io",
+			transient:     true,
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(true, "", nil)
+				s.APIServerHost().Return("apiserver.my-cluster.capz.io")
+				s.ControlPlaneCertificatesRolledOut(gomockinternal.AContext(), "apiserver.my-cluster.capz.io").Return(false, nil)
+				s.SetAPIServerLBMigrationProgress(infrav1.ControlPlaneCertificatesRollingOutReason, "waiting for the control plane to be rolled out with certificates valid for apiserver.my-cluster.capz.io")
+			},
+		},
+		{
+			name:          "keep the control plane endpoint of a control plane which can't be rolled out",
+			expectedError: "the control plane endpoint can only be switched to the internal API server load balancer for a control plane managed by a KubeadmControlPlane",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(true, "", nil)
+				s.APIServerHost().Return("apiserver.my-cluster.capz.io")
+				s.ControlPlaneCertificatesRolledOut(gomockinternal.AContext(), "apiserver.my-cluster.capz.io").Return(false, errors.New("the control plane endpoint can only be switched to the internal API server load balancer for a control plane managed by a KubeadmControlPlane"))
+			},
+		},
+		{
+			name: "switch the control plane endpoint once the control plane is rolled out",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(true, "", nil)
+				s.APIServerHost().Return("apiserver.my-cluster.capz.io")
+				s.ControlPlaneCertificatesRolledOut(gomockinternal.AContext(), "apiserver.my-cluster.capz.io").Return(true, nil)
+				s.SwitchControlPlaneEndpoint(gomockinternal.AContext()).Return(nil)
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationReplacingMachines)
+			},
+		},
+		{
+			name:          "retry switching the control plane endpoint",
+			expectedError: "failed to patch",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, a *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationSwitchingEndpoint))
+				s.SubscriptionID().Return("123")
+				s.ResourceGroup().Return("my-rg")
+				s.APIServerLBName().Return("my-internallb")
+				a.Available(gomockinternal.AContext(), fakeInternalLBID).Return(true, "", nil)
+				s.APIServerHost().Return("apiserver.my-cluster.capz.io")
+				s.ControlPlaneCertificatesRolledOut(gomockinternal.AContext(), "apiserver.my-cluster.capz.io").Return(true, nil)
+				s.SwitchControlPlaneEndpoint(gomockinternal.AContext()).Return(errors.New("failed to patch"))
+			},
+		},
+		{
+			name:          "keep the public load balancer until the machines created before the switch are replaced",
+			expectedError: "waiting for 7 machines created before the control plane endpoint was switched to be replaced: cp-0, cp-1, cp-2, md-0, md-1, ...",
+			transient:     true,
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationReplacingMachines))
+				s.MachinesCreatedBeforeEndpointSwitch(gomockinternal.AContext()).Return([]string{"cp-0", "cp-1", "cp-2", "md-0", "md-1", "md-2", "md-3"}, nil)
+				s.SetAPIServerLBMigrationProgress(infrav1.WaitingForMachinesReplacementReason, "waiting for 7 machines created before the control plane endpoint was switched to be replaced: cp-0, cp-1, cp-2, md-0, md-1, ...")
+			},
+		},
+		{
+			name: "delete the public load balancer once the machines created before the switch are replaced",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationReplacingMachines))
+				s.MachinesCreatedBeforeEndpointSwitch(gomockinternal.AContext()).Return(nil, nil)
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationDeletingPublicLB)
+			},
+		},
+		{
+			name: "delete the public load balancer and its public IP",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder, ipr *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationDeletingPublicLB))
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.SubscriptionID().Return("123")
+				s.ClusterName().Return("my-cluster")
+				r.DeleteResource(gomockinternal.AContext(), fakePublicLBToDelete, serviceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakePublicIPToDelete).Return(armnetwork.PublicIPAddress{
+					Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{ClusterName: "my-cluster", Lifecycle: infrav1.ResourceLifecycleOwned})),
+				}, nil)
+				s.IsManagedResource(fakePublicIPID, true).Return(true)
+				ipr.DeleteResource(gomockinternal.AContext(), fakePublicIPToDelete, serviceName).Return(nil)
+				s.ForgetManagedResource(fakePublicIPID)
+				s.RestrictAPIServerSecurityRule()
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCompleted)
+			},
+		},
+		{
+			name: "keep an unmanaged public IP",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationDeletingPublicLB))
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.SubscriptionID().Return("123")
+				s.ClusterName().Return("my-cluster")
+				r.DeleteResource(gomockinternal.AContext(), fakePublicLBToDelete, serviceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakePublicIPToDelete).Return(armnetwork.PublicIPAddress{}, nil)
+				s.IsManagedResource(fakePublicIPID, false).Return(false)
+				s.RestrictAPIServerSecurityRule()
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCompleted)
+			},
+		},
+		{
+			name: "resume the migration after the public load balancer and IP were deleted",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, g *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationDeletingPublicLB))
+				s.ResourceGroup().Return("my-rg").AnyTimes()
+				s.SubscriptionID().Return("123")
+				r.DeleteResource(gomockinternal.AContext(), fakePublicLBToDelete, serviceName).Return(nil)
+				g.Get(gomockinternal.AContext(), fakePublicIPToDelete).Return(nil, notFoundError)
+				s.ForgetManagedResource(fakePublicIPID)
+				s.RestrictAPIServerSecurityRule()
+				s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCompleted)
+			},
+		},
+		{
+			name:          "retry the deletion of the public load balancer while machines still reference it",
+			expectedError: "failed to delete the public API server load balancer my-publiclb",
+			transient:     true,
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationDeletingPublicLB))
+				s.ResourceGroup().Return("my-rg")
+				r.DeleteResource(gomockinternal.AContext(), fakePublicLBToDelete, serviceName).Return(internalError)
+			},
+		},
+		{
+			name:          "wait for the deletion of the public load balancer to complete",
+			expectedError: "operation type DELETE on Azure resource my-rg/my-publiclb is not done",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationDeletingPublicLB))
+				s.ResourceGroup().Return("my-rg")
+				r.DeleteResource(gomockinternal.AContext(), fakePublicLBToDelete, serviceName).Return(azure.NewOperationNotDoneError(&infrav1.Future{Type: infrav1.DeleteFuture, ResourceGroup: "my-rg", Name: "my-publiclb"}))
+			},
+		},
+		{
+			name: "report the completed migration until the annotation is removed",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationCompleted))
+				s.IsAPIServerLBMigrationRequested().Return(true)
+			},
+		},
+		{
+			name: "clear the completed migration once the annotation is removed",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_async.MockGetterMockRecorder, _ *mock_async.MockReconcilerMockRecorder, _ *mock_loadbalancers.MockavailabilityCheckerMockRecorder) {
+				s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationCompleted))
+				s.IsAPIServerLBMigrationRequested().Return(false)
+				s.ClearAPIServerLBMigration()
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			publicIPGetterMock := mock_async.NewMockGetter(mockCtrl)
+			publicIPReconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			availabilityCheckerMock := mock_loadbalancers.NewMockavailabilityChecker(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), publicIPGetterMock.EXPECT(), publicIPReconcilerMock.EXPECT(), availabilityCheckerMock.EXPECT())
+
+			s := &Service{
+				Scope:               scopeMock,
+				Reconciler:          asyncMock,
+				publicIPGetter:      publicIPGetterMock,
+				publicIPReconciler:  publicIPReconcilerMock,
+				availabilityChecker: availabilityCheckerMock,
+			}
+			err := s.ReconcileAPIServerLBMigration(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+				var recErr azure.ReconcileError
+				g.Expect(errors.As(err, &recErr)).To(Equal(tc.transient))
+				if tc.transient {
+					g.Expect(recErr.IsTransient()).To(BeTrue())
+				}
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileLoadBalancerStartsCreatingInternalLB(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+	asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+	s := scopeMock.EXPECT()
+	s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+	s.APIServerLBMigration().Return(migrationInPhase(infrav1.APIServerLBMigrationPending))
+	s.IsAPIServerPrivate().Return(true)
+	s.APIServerLBName().Return("my-private-lb")
+	// The phase is recorded before the internal load balancer is created, so that the control plane machines stay in
+	// the public load balancer if the creation is interrupted.
+	gomock.InOrder(
+		s.SetAPIServerLBMigrationPhase(infrav1.APIServerLBMigrationCreatingInternalLB),
+		s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakeInternalAPILBSpec}),
+	)
+	asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), &fakeInternalAPILBSpec, serviceName).Return(nil, nil)
+	s.UpdatePutStatus(infrav1.LoadBalancersReadyCondition, serviceName, nil)
+
+	svc := &Service{
+		Scope:      scopeMock,
+		Reconciler: asyncMock,
+	}
+	g.Expect(svc.Reconcile(context.TODO())).To(Succeed())
+}
@@ -26,16 +26,16 @@ limitations under the License.
 package mock_loadbalancers
 
 import (
-	reflect "reflect"
-	time "time"
-
+	context "context"
 	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	reflect "reflect"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
+	time "time"
 )
 
 // MockLBScope is a mock of LBScope interface.
@@ -61,6 +61,20 @@ func (m *MockLBScope) EXPECT() *MockLBScopeMockRecorder {
 	return m.recorder
 }
 
+// APIServerHost mocks base method.
+func (m *MockLBScope) APIServerHost() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerHost")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// APIServerHost indicates an expected call of APIServerHost.
+func (mr *MockLBScopeMockRecorder) APIServerHost() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerHost", reflect.TypeOf((*MockLBScope)(nil).APIServerHost))
+}
+
 // APIServerLB mocks base method.
 func (m *MockLBScope) APIServerLB() *v1beta1.LoadBalancerSpec {
 	m.ctrl.T.Helper()
@@ -75,6 +89,20 @@ func (mr *MockLBScopeMockRecorder) APIServerLB() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLB", reflect.TypeOf((*MockLBScope)(nil).APIServerLB))
 }
 
+// APIServerLBMigration mocks base method.
+func (m *MockLBScope) APIServerLBMigration() *v1beta1.APIServerLBMigration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerLBMigration")
+	ret0, _ := ret[0].(*v1beta1.APIServerLBMigration)
+	return ret0
+}
+
+// APIServerLBMigration indicates an expected call of APIServerLBMigration.
+func (mr *MockLBScopeMockRecorder) APIServerLBMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBMigration", reflect.TypeOf((*MockLBScope)(nil).APIServerLBMigration))
+}
+
 // APIServerLBName mocks base method.
 func (m *MockLBScope) APIServerLBName() string {
 	m.ctrl.T.Helper()
@@ -103,6 +131,20 @@ func (mr *MockLBScopeMockRecorder) APIServerLBPoolName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerLBPoolName", reflect.TypeOf((*MockLBScope)(nil).APIServerLBPoolName))
 }
 
+// APIServerPort mocks base method.
+func (m *MockLBScope) APIServerPort() int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerPort")
+	ret0, _ := ret[0].(int32)
+	return ret0
+}
+
+// APIServerPort indicates an expected call of APIServerPort.
+func (mr *MockLBScopeMockRecorder) APIServerPort() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerPort", reflect.TypeOf((*MockLBScope)(nil).APIServerPort))
+}
+
 // AdditionalTags mocks base method.
 func (m *MockLBScope) AdditionalTags() v1beta1.Tags {
 	m.ctrl.T.Helper()
@@ -145,6 +187,18 @@ func (mr *MockLBScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockLBScope)(nil).BaseURI))
 }
 
+// ClearAPIServerLBMigration mocks base method.
+func (m *MockLBScope) ClearAPIServerLBMigration() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearAPIServerLBMigration")
+}
+
+// ClearAPIServerLBMigration indicates an expected call of ClearAPIServerLBMigration.
+func (mr *MockLBScopeMockRecorder) ClearAPIServerLBMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAPIServerLBMigration", reflect.TypeOf((*MockLBScope)(nil).ClearAPIServerLBMigration))
+}
+
 // ClientID mocks base method.
 func (m *MockLBScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -215,6 +269,35 @@ func (mr *MockLBScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockLBScope)(nil).ClusterName))
 }
 
+// ClusterVMExtensions mocks base method.
+func (m *MockLBScope) ClusterVMExtensions() []v1beta1.ClusterVMExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterVMExtensions")
+	ret0, _ := ret[0].([]v1beta1.ClusterVMExtension)
+	return ret0
+}
+
+// ClusterVMExtensions indicates an expected call of ClusterVMExtensions.
+func (mr *MockLBScopeMockRecorder) ClusterVMExtensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterVMExtensions", reflect.TypeOf((*MockLBScope)(nil).ClusterVMExtensions))
+}
+
+// ControlPlaneCertificatesRolledOut mocks base method.
+func (m *MockLBScope) ControlPlaneCertificatesRolledOut(ctx context.Context, host string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControlPlaneCertificatesRolledOut", ctx, host)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ControlPlaneCertificatesRolledOut indicates an expected call of ControlPlaneCertificatesRolledOut.
+func (mr *MockLBScopeMockRecorder) ControlPlaneCertificatesRolledOut(ctx, host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneCertificatesRolledOut", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneCertificatesRolledOut), ctx, host)
+}
+
 // ControlPlaneRouteTable mocks base method.
 func (m *MockLBScope) ControlPlaneRouteTable() v1beta1.RouteTable {
 	m.ctrl.T.Helper()
@@ -243,6 +326,20 @@ func (mr *MockLBScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockLBScope)(nil).ControlPlaneSubnet))
 }
 
+// DefaultImage mocks base method.
+func (m *MockLBScope) DefaultImage() *v1beta1.DefaultImage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultImage")
+	ret0, _ := ret[0].(*v1beta1.DefaultImage)
+	return ret0
+}
+
+// DefaultImage indicates an expected call of DefaultImage.
+func (mr *MockLBScopeMockRecorder) DefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultImage", reflect.TypeOf((*MockLBScope)(nil).DefaultImage))
+}
+
 // DefaultedAzureCallTimeout mocks base method.
 func (m *MockLBScope) DefaultedAzureCallTimeout() time.Duration {
 	m.ctrl.T.Helper()
@@ -353,6 +450,18 @@ func (mr *MockLBScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockLBScope)(nil).FailureDomains))
 }
 
+// ForgetManagedResource mocks base method.
+func (m *MockLBScope) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockLBScopeMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockLBScope)(nil).ForgetManagedResource), id)
+}
+
 // GetClient mocks base method.
 func (m *MockLBScope) GetClient() client.Client {
 	m.ctrl.T.Helper()
@@ -423,6 +532,20 @@ func (mr *MockLBScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockLBScope)(nil).HashKey))
 }
 
+// IsAPIServerLBMigrationRequested mocks base method.
+func (m *MockLBScope) IsAPIServerLBMigrationRequested() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAPIServerLBMigrationRequested")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAPIServerLBMigrationRequested indicates an expected call of IsAPIServerLBMigrationRequested.
+func (mr *MockLBScopeMockRecorder) IsAPIServerLBMigrationRequested() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAPIServerLBMigrationRequested", reflect.TypeOf((*MockLBScope)(nil).IsAPIServerLBMigrationRequested))
+}
+
 // IsAPIServerPrivate mocks base method.
 func (m *MockLBScope) IsAPIServerPrivate() bool {
 	m.ctrl.T.Helper()
@@ -451,6 +574,34 @@ func (mr *MockLBScopeMockRecorder) IsIPv6Enabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIPv6Enabled", reflect.TypeOf((*MockLBScope)(nil).IsIPv6Enabled))
 }
 
+// IsManagedResource mocks base method.
+func (m *MockLBScope) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockLBScopeMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockLBScope)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockLBScope) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockLBScopeMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockLBScope)(nil).IsOwnershipRecorded))
+}
+
 // IsVnetManaged mocks base method.
 func (m *MockLBScope) IsVnetManaged() bool {
 	m.ctrl.T.Helper()
@@ -493,6 +644,35 @@ func (mr *MockLBScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockLBScope)(nil).Location))
 }
 
+// MachinesCreatedBeforeEndpointSwitch mocks base method.
+func (m *MockLBScope) MachinesCreatedBeforeEndpointSwitch(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MachinesCreatedBeforeEndpointSwitch", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MachinesCreatedBeforeEndpointSwitch indicates an expected call of MachinesCreatedBeforeEndpointSwitch.
+func (mr *MockLBScopeMockRecorder) MachinesCreatedBeforeEndpointSwitch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MachinesCreatedBeforeEndpointSwitch", reflect.TypeOf((*MockLBScope)(nil).MachinesCreatedBeforeEndpointSwitch), ctx)
+}
+
+// NamingTemplate mocks base method.
+func (m *MockLBScope) NamingTemplate() *v1beta1.NamingTemplate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamingTemplate")
+	ret0, _ := ret[0].(*v1beta1.NamingTemplate)
+	return ret0
+}
+
+// NamingTemplate indicates an expected call of NamingTemplate.
+func (mr *MockLBScopeMockRecorder) NamingTemplate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamingTemplate", reflect.TypeOf((*MockLBScope)(nil).NamingTemplate))
+}
+
 // NodeResourceGroup mocks base method.
 func (m *MockLBScope) NodeResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -549,6 +729,18 @@ func (mr *MockLBScopeMockRecorder) OutboundPoolName(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockLBScope)(nil).OutboundPoolName), arg0)
 }
 
+// RecordManagedResource mocks base method.
+func (m *MockLBScope) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockLBScopeMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockLBScope)(nil).RecordManagedResource), id)
+}
+
 // ResourceGroup mocks base method.
 func (m *MockLBScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -563,6 +755,42 @@ func (mr *MockLBScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockLBScope)(nil).ResourceGroup))
 }
 
+// RestrictAPIServerSecurityRule mocks base method.
+func (m *MockLBScope) RestrictAPIServerSecurityRule() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RestrictAPIServerSecurityRule")
+}
+
+// RestrictAPIServerSecurityRule indicates an expected call of RestrictAPIServerSecurityRule.
+func (mr *MockLBScopeMockRecorder) RestrictAPIServerSecurityRule() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestrictAPIServerSecurityRule", reflect.TypeOf((*MockLBScope)(nil).RestrictAPIServerSecurityRule))
+}
+
+// SetAPIServerLBMigrationPhase mocks base method.
+func (m *MockLBScope) SetAPIServerLBMigrationPhase(arg0 v1beta1.APIServerLBMigrationPhase) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAPIServerLBMigrationPhase", arg0)
+}
+
+// SetAPIServerLBMigrationPhase indicates an expected call of SetAPIServerLBMigrationPhase.
+func (mr *MockLBScopeMockRecorder) SetAPIServerLBMigrationPhase(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAPIServerLBMigrationPhase", reflect.TypeOf((*MockLBScope)(nil).SetAPIServerLBMigrationPhase), arg0)
+}
+
+// SetAPIServerLBMigrationProgress mocks base method.
+func (m *MockLBScope) SetAPIServerLBMigrationProgress(reason, message string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAPIServerLBMigrationProgress", reason, message)
+}
+
+// SetAPIServerLBMigrationProgress indicates an expected call of SetAPIServerLBMigrationProgress.
+func (mr *MockLBScopeMockRecorder) SetAPIServerLBMigrationProgress(reason, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAPIServerLBMigrationProgress", reflect.TypeOf((*MockLBScope)(nil).SetAPIServerLBMigrationProgress), reason, message)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockLBScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -587,6 +815,18 @@ func (mr *MockLBScopeMockRecorder) SetSubnet(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnet", reflect.TypeOf((*MockLBScope)(nil).SetSubnet), arg0)
 }
 
+// StartAPIServerLBMigration mocks base method.
+func (m *MockLBScope) StartAPIServerLBMigration() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartAPIServerLBMigration")
+}
+
+// StartAPIServerLBMigration indicates an expected call of StartAPIServerLBMigration.
+func (mr *MockLBScopeMockRecorder) StartAPIServerLBMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartAPIServerLBMigration", reflect.TypeOf((*MockLBScope)(nil).StartAPIServerLBMigration))
+}
+
 // Subnet mocks base method.
 func (m *MockLBScope) Subnet(arg0 string) v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -629,6 +869,20 @@ func (mr *MockLBScopeMockRecorder) SubscriptionID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockLBScope)(nil).SubscriptionID))
 }
 
+// SwitchControlPlaneEndpoint mocks base method.
+func (m *MockLBScope) SwitchControlPlaneEndpoint(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchControlPlaneEndpoint", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SwitchControlPlaneEndpoint indicates an expected call of SwitchControlPlaneEndpoint.
+func (mr *MockLBScopeMockRecorder) SwitchControlPlaneEndpoint(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchControlPlaneEndpoint", reflect.TypeOf((*MockLBScope)(nil).SwitchControlPlaneEndpoint), ctx)
+}
+
 // TenantID mocks base method.
 func (m *MockLBScope) TenantID() string {
 	m.ctrl.T.Helper()
@@ -706,3 +960,42 @@ func (mr *MockLBScopeMockRecorder) Vnet() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vnet", reflect.TypeOf((*MockLBScope)(nil).Vnet))
 }
+
+// MockavailabilityChecker is a mock of availabilityChecker interface.
+type MockavailabilityChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockavailabilityCheckerMockRecorder
+}
+
+// MockavailabilityCheckerMockRecorder is the mock recorder for MockavailabilityChecker.
+type MockavailabilityCheckerMockRecorder struct {
+	mock *MockavailabilityChecker
+}
+
+// NewMockavailabilityChecker creates a new mock instance.
+func NewMockavailabilityChecker(ctrl *gomock.Controller) *MockavailabilityChecker {
+	mock := &MockavailabilityChecker{ctrl: ctrl}
+	mock.recorder = &MockavailabilityCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockavailabilityChecker) EXPECT() *MockavailabilityCheckerMockRecorder {
+	return m.recorder
+}
+
+// Available mocks base method.
+func (m *MockavailabilityChecker) Available(ctx context.Context, resourceURI string) (bool, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Available", ctx, resourceURI)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Available indicates an expected call of Available.
+func (mr *MockavailabilityCheckerMockRecorder) Available(ctx, resourceURI any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Available", reflect.TypeOf((*MockavailabilityChecker)(nil).Available), ctx, resourceURI)
+}
@@ -18,6 +18,7 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
@@ -45,6 +46,9 @@ type LBSpec struct {
 	FrontendIPConfigs    []infrav1.FrontendIP
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
+	HealthProbe          *infrav1.LoadBalancerHealthProbe
+	AdditionalPorts      []infrav1.LoadBalancerPort
+	FailureDomains       []*string
 	AdditionalTags       map[string]string
 }
 
@@ -75,6 +79,10 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 		probes              []*armnetwork.Probe
 	)
 
+	if s.HealthProbe != nil && s.HealthProbe.Port != nil && *s.HealthProbe.Port != s.APIServerPort {
+		return nil, errors.Errorf("health probe port %d does not match the API server port %d", *s.HealthProbe.Port, s.APIServerPort)
+	}
+
 	if existing != nil {
 		existingLB, ok := existing.(armnetwork.LoadBalancer)
 		if !ok {
@@ -96,12 +104,20 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 		}
 
 		loadBalancingRules = existingLB.Properties.LoadBalancingRules
-		for _, rule := range getLoadBalancingRules(*s, wantedFrontendIDs) {
+		wantedRules := getLoadBalancingRules(*s, wantedFrontendIDs)
+		for _, rule := range wantedRules {
 			if !lbRuleExists(loadBalancingRules, *rule) {
 				update = true
 				loadBalancingRules = append(loadBalancingRules, rule)
 			}
 		}
+		var rulesChanged bool
+		if loadBalancingRules, rulesChanged = syncAdditionalPortLBRules(loadBalancingRules, wantedRules); rulesChanged {
+			update = true
+		}
+		if loadBalancingRules, rulesChanged = syncAPIServerLBRuleProbe(loadBalancingRules, wantedRules); rulesChanged {
+			update = true
+		}
 
 		backendAddressPools = existingLB.Properties.BackendAddressPools
 		for _, pool := range getBackendAddressPools(*s) {
@@ -120,12 +136,22 @@ func (s *LBSpec) Parameters(ctx context.Context, existing interface{}) (paramete
 		}
 
 		probes = existingLB.Properties.Probes
-		for _, probe := range getProbes(*s) {
-			if !probeExists(probes, *probe) {
+		wantedProbes := getProbes(*s)
+		for _, probe := range wantedProbes {
+			i := probeIndex(probes, *probe)
+			if i < 0 {
 				update = true
 				probes = append(probes, probe)
+			} else if !probePropertiesEqual(probes[i].Properties, probe.Properties) {
+				// Update the probe in place so the load balancing rule referencing it by name keeps working.
+				update = true
+				probes[i] = updateProbe(*probes[i], *probe.Properties)
 			}
 		}
+		var probesRemoved bool
+		if probes, probesRemoved = removeStaleProbes(probes, wantedProbes); probesRemoved {
+			update = true
+		}
 
 		if !update {
 			// load balancer already exists with all required defaults
@@ -167,7 +193,13 @@ func getFrontendIPConfigs(lbSpec LBSpec) ([]*armnetwork.FrontendIPConfiguration,
 	frontendIDs := make([]*armnetwork.SubResource, 0)
 	for _, ipConfig := range lbSpec.FrontendIPConfigs {
 		var properties armnetwork.FrontendIPConfigurationPropertiesFormat
+		var zones []*string
 		if lbSpec.Type == infrav1.Internal {
+			// A public frontend IP uses the zones of its public IP.
+			zones = lbSpec.FailureDomains
+			if len(ipConfig.Zones) > 0 {
+				zones = azure.PtrSlice(&ipConfig.Zones)
+			}
 			properties = armnetwork.FrontendIPConfigurationPropertiesFormat{
 				PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
 				Subnet: &armnetwork.Subnet{
@@ -185,6 +217,7 @@ func getFrontendIPConfigs(lbSpec LBSpec) ([]*armnetwork.FrontendIPConfiguration,
 		frontendIPConfigurations = append(frontendIPConfigurations, &armnetwork.FrontendIPConfiguration{
 			Properties: &properties,
 			Name:       ptr.To(ipConfig.Name),
+			Zones:      zones,
 		})
 		frontendIDs = append(frontendIDs, &armnetwork.SubResource{
 			ID: ptr.To(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
@@ -220,7 +253,7 @@ func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource)
 		if len(frontendIDs) != 0 {
 			frontendIPConfig = frontendIDs[0]
 		}
-		return []*armnetwork.LoadBalancingRule{
+		rules := []*armnetwork.LoadBalancingRule{
 			{
 				Name: ptr.To(lbRuleHTTPS),
 				Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
@@ -236,11 +269,33 @@ func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource)
 						ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
 					},
 					Probe: &armnetwork.SubResource{
-						ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, httpsProbe)),
+						ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, apiServerProbeName(lbSpec))),
 					},
 				},
 			},
 		}
+		for _, port := range lbSpec.AdditionalPorts {
+			rules = append(rules, &armnetwork.LoadBalancingRule{
+				Name: ptr.To(additionalPortLBRulePrefix + port.Name),
+				Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+					DisableOutboundSnat:     ptr.To(true),
+					Protocol:                ptr.To(armnetwork.TransportProtocolTCP),
+					FrontendPort:            ptr.To(port.Port),
+					BackendPort:             ptr.To(ptr.Deref(port.TargetPort, port.Port)),
+					IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
+					EnableFloatingIP:        ptr.To(false),
+					LoadDistribution:        ptr.To(armnetwork.LoadDistributionDefault),
+					FrontendIPConfiguration: frontendIPConfig,
+					BackendAddressPool: &armnetwork.SubResource{
+						ID: ptr.To(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
+					},
+					Probe: &armnetwork.SubResource{
+						ID: ptr.To(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, additionalPortProbePrefix+port.Name)),
+					},
+				},
+			})
+		}
+		return rules
 	}
 	return []*armnetwork.LoadBalancingRule{}
 }
@@ -253,31 +308,164 @@ func getBackendAddressPools(lbSpec LBSpec) []*armnetwork.BackendAddressPool {
 	}
 }
 
+// apiServerProbeName returns the name of the API server health probe, which is named after its protocol.
+func apiServerProbeName(lbSpec LBSpec) string {
+	if lbSpec.HealthProbe != nil && lbSpec.HealthProbe.Protocol == infrav1.ProbeProtocolTCP {
+		return tcpProbe
+	}
+	return httpsProbe
+}
+
 func getProbes(lbSpec LBSpec) []*armnetwork.Probe {
 	if lbSpec.Role == infrav1.APIServerRole {
-		return []*armnetwork.Probe{
+		healthProbe := ptr.Deref(lbSpec.HealthProbe, infrav1.LoadBalancerHealthProbe{})
+		properties := &armnetwork.ProbePropertiesFormat{
+			Protocol:          ptr.To(armnetwork.ProbeProtocolHTTPS),
+			Port:              ptr.To(ptr.Deref(healthProbe.Port, lbSpec.APIServerPort)),
+			IntervalInSeconds: ptr.To(ptr.Deref(healthProbe.IntervalInSeconds, defaultProbeIntervalInSeconds)),
+			NumberOfProbes:    ptr.To(ptr.Deref(healthProbe.NumberOfProbes, defaultNumberOfProbes)),
+		}
+		if healthProbe.Protocol == infrav1.ProbeProtocolTCP {
+			properties.Protocol = ptr.To(armnetwork.ProbeProtocolTCP)
+		} else {
+			properties.RequestPath = ptr.To(httpsProbeRequestPath)
+			if healthProbe.RequestPath != "" {
+				properties.RequestPath = ptr.To(healthProbe.RequestPath)
+			}
+		}
+		probes := []*armnetwork.Probe{
 			{
-				Name: ptr.To(httpsProbe),
+				Name:       ptr.To(apiServerProbeName(lbSpec)),
+				Properties: properties,
+			},
+		}
+		for _, port := range lbSpec.AdditionalPorts {
+			probes = append(probes, &armnetwork.Probe{
+				Name: ptr.To(additionalPortProbePrefix + port.Name),
 				Properties: &armnetwork.ProbePropertiesFormat{
-					Protocol:          ptr.To(armnetwork.ProbeProtocolHTTPS),
-					Port:              ptr.To[int32](lbSpec.APIServerPort),
-					RequestPath:       ptr.To(httpsProbeRequestPath),
-					IntervalInSeconds: ptr.To[int32](15),
-					NumberOfProbes:    ptr.To[int32](4),
+					Protocol:          ptr.To(armnetwork.ProbeProtocolTCP),
+					Port:              ptr.To(ptr.Deref(port.TargetPort, port.Port)),
+					IntervalInSeconds: ptr.To(defaultProbeIntervalInSeconds),
+					NumberOfProbes:    ptr.To(defaultNumberOfProbes),
 				},
-			},
+			})
 		}
+		return probes
 	}
 	return []*armnetwork.Probe{}
 }
 
-func probeExists(probes []*armnetwork.Probe, probe armnetwork.Probe) bool {
-	for _, p := range probes {
+// syncAdditionalPortLBRules removes the load balancing rules of the additional ports that aren't wanted anymore and
+// updates the ports of the remaining ones. It returns true if the rules changed.
+func syncAdditionalPortLBRules(rules, wanted []*armnetwork.LoadBalancingRule) ([]*armnetwork.LoadBalancingRule, bool) {
+	wantedByName := make(map[string]*armnetwork.LoadBalancingRule, len(wanted))
+	for _, rule := range wanted {
+		wantedByName[ptr.Deref(rule.Name, "")] = rule
+	}
+
+	changed := false
+	synced := make([]*armnetwork.LoadBalancingRule, 0, len(rules))
+	for _, rule := range rules {
+		name := ptr.Deref(rule.Name, "")
+		if !strings.HasPrefix(name, additionalPortLBRulePrefix) {
+			synced = append(synced, rule)
+			continue
+		}
+		wantedRule, ok := wantedByName[name]
+		if !ok {
+			changed = true
+			continue
+		}
+		if rule.Properties == nil || !ptr.Equal(rule.Properties.FrontendPort, wantedRule.Properties.FrontendPort) ||
+			!ptr.Equal(rule.Properties.BackendPort, wantedRule.Properties.BackendPort) {
+			changed = true
+			rule = wantedRule
+		}
+		synced = append(synced, rule)
+	}
+	return synced, changed
+}
+
+// syncAPIServerLBRuleProbe points the API server load balancing rule to the wanted health probe, which changes name
+// when its protocol changes. It returns true if the rule was updated.
+func syncAPIServerLBRuleProbe(rules, wanted []*armnetwork.LoadBalancingRule) ([]*armnetwork.LoadBalancingRule, bool) {
+	var wantedProbe *armnetwork.SubResource
+	for _, rule := range wanted {
+		if ptr.Deref(rule.Name, "") == lbRuleHTTPS && rule.Properties != nil {
+			wantedProbe = rule.Properties.Probe
+		}
+	}
+	if wantedProbe == nil {
+		return rules, false
+	}
+
+	changed := false
+	for i, rule := range rules {
+		if ptr.Deref(rule.Name, "") != lbRuleHTTPS || rule.Properties == nil {
+			continue
+		}
+		if rule.Properties.Probe != nil && strings.EqualFold(ptr.Deref(rule.Properties.Probe.ID, ""), ptr.Deref(wantedProbe.ID, "")) {
+			continue
+		}
+		properties := *rule.Properties
+		properties.Probe = wantedProbe
+		updated := *rule
+		updated.Properties = &properties
+		rules[i] = &updated
+		changed = true
+	}
+	return rules, changed
+}
+
+// removeStaleProbes removes the API server health probe of another protocol and the health probes of the additional
+// ports that aren't wanted anymore. It returns true if any probe was removed.
+func removeStaleProbes(probes, wanted []*armnetwork.Probe) ([]*armnetwork.Probe, bool) {
+	removed := false
+	kept := make([]*armnetwork.Probe, 0, len(probes))
+	for _, probe := range probes {
+		name := ptr.Deref(probe.Name, "")
+		managed := name == httpsProbe || name == tcpProbe || strings.HasPrefix(name, additionalPortProbePrefix)
+		if managed && probeIndex(wanted, *probe) < 0 {
+			removed = true
+			continue
+		}
+		kept = append(kept, probe)
+	}
+	return kept, removed
+}
+
+// probeIndex returns the index of the probe with the same name in probes, or -1 if there is none.
+func probeIndex(probes []*armnetwork.Probe, probe armnetwork.Probe) int {
+	for i, p := range probes {
 		if ptr.Deref(p.Name, "") == ptr.Deref(probe.Name, "") {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// updateProbe returns a copy of the existing probe with the properties managed by CAPZ set to the wanted ones.
+func updateProbe(existing armnetwork.Probe, wanted armnetwork.ProbePropertiesFormat) *armnetwork.Probe {
+	properties := ptr.Deref(existing.Properties, armnetwork.ProbePropertiesFormat{})
+	properties.Protocol = wanted.Protocol
+	properties.Port = wanted.Port
+	properties.RequestPath = wanted.RequestPath
+	properties.IntervalInSeconds = wanted.IntervalInSeconds
+	properties.NumberOfProbes = wanted.NumberOfProbes
+	existing.Properties = &properties
+	return &existing
+}
+
+// probePropertiesEqual returns true if the configurable properties of the probes are equal.
+func probePropertiesEqual(a, b *armnetwork.ProbePropertiesFormat) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return ptr.Equal(a.Protocol, b.Protocol) &&
+		ptr.Equal(a.Port, b.Port) &&
+		ptr.Deref(a.RequestPath, "") == ptr.Deref(b.RequestPath, "") &&
+		ptr.Equal(a.IntervalInSeconds, b.IntervalInSeconds) &&
+		ptr.Equal(a.NumberOfProbes, b.NumberOfProbes)
 }
 
 func outboundRuleExists(rules []*armnetwork.OutboundRule, rule armnetwork.OutboundRule) bool {
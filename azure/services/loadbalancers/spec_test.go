@@ -26,6 +26,21 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
+func newInternalAPILBSpecWithZones(failureDomains []*string, zones []string) *LBSpec {
+	spec := fakeInternalAPILBSpec
+	spec.FailureDomains = failureDomains
+	spec.FrontendIPConfigs = []infrav1.FrontendIP{
+		{
+			Name:  "my-private-lb-frontEnd",
+			Zones: zones,
+			FrontendIPClass: infrav1.FrontendIPClass{
+				PrivateIPAddress: "10.0.0.10",
+			},
+		},
+	}
+	return &spec
+}
+
 func getExistingLBWithMissingFrontendIPConfigs() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(false, true, true, true, true)
 	existingLB.Properties.FrontendIPConfigurations = []*armnetwork.FrontendIPConfiguration{}
@@ -146,6 +161,241 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "new API server load balancer with custom Tcp health probe",
+			spec: newPublicAPILBSpecWithHealthProbe(&infrav1.LoadBalancerHealthProbe{
+				Protocol:          infrav1.ProbeProtocolTCP,
+				IntervalInSeconds: ptr.To[int32](5),
+				NumberOfProbes:    ptr.To[int32](2),
+			}),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer).Properties.Probes).To(Equal([]*armnetwork.Probe{
+					{
+						Name: ptr.To(tcpProbe),
+						Properties: &armnetwork.ProbePropertiesFormat{
+							Protocol:          ptr.To(armnetwork.ProbeProtocolTCP),
+							Port:              ptr.To[int32](6443),
+							IntervalInSeconds: ptr.To[int32](5),
+							NumberOfProbes:    ptr.To[int32](2),
+						},
+					},
+				}))
+				g.Expect(result.(armnetwork.LoadBalancer).Properties.LoadBalancingRules[0].Properties.Probe.ID).To(Equal(
+					ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/TCPProbe")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "existing API server load balancer health probe is replaced when its protocol changes",
+			spec: newPublicAPILBSpecWithHealthProbe(&infrav1.LoadBalancerHealthProbe{
+				Protocol: infrav1.ProbeProtocolTCP,
+			}),
+			existing: newSamplePublicAPIServerLB(false, false, false, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.Probes).To(HaveLen(1))
+				g.Expect(lb.Properties.Probes[0].Name).To(Equal(ptr.To(tcpProbe)))
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(1))
+				g.Expect(lb.Properties.LoadBalancingRules[0].Properties.Probe.ID).To(Equal(
+					ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/TCPProbe")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "existing API server load balancer health probe is updated in place",
+			spec: newPublicAPILBSpecWithHealthProbe(&infrav1.LoadBalancerHealthProbe{
+				Protocol:    infrav1.ProbeProtocolHTTPS,
+				Port:        ptr.To[int32](6443),
+				RequestPath: "/livez",
+			}),
+			existing: newSamplePublicAPIServerLB(false, false, false, true, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer).Properties.Probes).To(Equal([]*armnetwork.Probe{
+					{
+						Name: ptr.To(httpsProbe),
+						Properties: &armnetwork.ProbePropertiesFormat{
+							Protocol:          ptr.To(armnetwork.ProbeProtocolHTTPS),
+							Port:              ptr.To[int32](6443),
+							RequestPath:       ptr.To("/livez"),
+							IntervalInSeconds: ptr.To[int32](15),
+							NumberOfProbes:    ptr.To[int32](4),
+							ProbeThreshold:    ptr.To[int32](2),
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "API server load balancer health probe port does not match the API server port",
+			spec: newPublicAPILBSpecWithHealthProbe(&infrav1.LoadBalancerHealthProbe{
+				Port: ptr.To[int32](8443),
+			}),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "health probe port 8443 does not match the API server port 6443",
+		},
+		{
+			name:     "new API server load balancer with multiple additional ports",
+			spec:     newPublicAPILBSpecWithAdditionalPorts(konnectivityPort, metricsPort),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lbRulePorts(lb)).To(Equal(map[string][2]int32{
+					lbRuleHTTPS:                         {6443, 6443},
+					"LBRuleAdditionalPort-konnectivity": {8132, 8132},
+					"LBRuleAdditionalPort-metrics":      {9443, 10250},
+				}))
+				g.Expect(probePorts(lb)).To(Equal(map[string]int32{
+					httpsProbe:                         6443,
+					"ProbeAdditionalPort-konnectivity": 8132,
+					"ProbeAdditionalPort-metrics":      10250,
+				}))
+				rule := lb.Properties.LoadBalancingRules[2]
+				g.Expect(rule.Properties.FrontendIPConfiguration.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")))
+				g.Expect(rule.Properties.BackendAddressPool.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool")))
+				g.Expect(rule.Properties.Probe.ID).To(Equal(ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/ProbeAdditionalPort-metrics")))
+				g.Expect(lb.Properties.Probes[2].Properties.Protocol).To(Equal(ptr.To(armnetwork.ProbeProtocolTCP)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing API server load balancer gets additional ports",
+			spec:     newPublicAPILBSpecWithAdditionalPorts(konnectivityPort, metricsPort),
+			existing: newSamplePublicAPIServerLBWithAdditionalPorts(konnectivityPort),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lbRulePorts(lb)).To(Equal(map[string][2]int32{
+					lbRuleHTTPS:                         {6443, 6443},
+					"LBRuleAdditionalPort-konnectivity": {8132, 8132},
+					"LBRuleAdditionalPort-metrics":      {9443, 10250},
+				}))
+				g.Expect(probePorts(lb)).To(Equal(map[string]int32{
+					httpsProbe:                         6443,
+					"ProbeAdditionalPort-konnectivity": 8132,
+					"ProbeAdditionalPort-metrics":      10250,
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing API server load balancer with all additional ports",
+			spec:     newPublicAPILBSpecWithAdditionalPorts(konnectivityPort, metricsPort),
+			existing: newSamplePublicAPIServerLBWithAdditionalPorts(konnectivityPort, metricsPort),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "additional ports removed from the spec are deleted",
+			spec:     newPublicAPILBSpecWithAdditionalPorts(metricsPort),
+			existing: newSamplePublicAPIServerLBWithAdditionalPorts(konnectivityPort, metricsPort),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lbRulePorts(lb)).To(Equal(map[string][2]int32{
+					lbRuleHTTPS:                    {6443, 6443},
+					"LBRuleAdditionalPort-metrics": {9443, 10250},
+				}))
+				g.Expect(probePorts(lb)).To(Equal(map[string]int32{
+					httpsProbe:                    6443,
+					"ProbeAdditionalPort-metrics": 10250,
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "additional port with a changed target port is updated",
+			spec: newPublicAPILBSpecWithAdditionalPorts(infrav1.LoadBalancerPort{
+				Name:       "konnectivity",
+				Port:       8132,
+				TargetPort: ptr.To[int32](8133),
+			}),
+			existing: newSamplePublicAPIServerLBWithAdditionalPorts(konnectivityPort),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lbRulePorts(lb)).To(Equal(map[string][2]int32{
+					lbRuleHTTPS:                         {6443, 6443},
+					"LBRuleAdditionalPort-konnectivity": {8132, 8133},
+				}))
+				g.Expect(probePorts(lb)).To(Equal(map[string]int32{
+					httpsProbe:                         6443,
+					"ProbeAdditionalPort-konnectivity": 8133,
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "rules not created for additional ports are kept",
+			spec:     &fakePublicAPILBSpec,
+			existing: newSamplePublicAPIServerLBWithRule("custom-rule"),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new internal load balancer frontend is zone-redundant in a location with availability zones",
+			spec:     newInternalAPILBSpecWithZones([]*string{ptr.To("1"), ptr.To("2"), ptr.To("3")}, nil),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontends := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontends).To(HaveLen(1))
+				g.Expect(frontends[0].Zones).To(Equal([]*string{ptr.To("1"), ptr.To("2"), ptr.To("3")}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new internal load balancer frontend is pinned to the zones of its spec",
+			spec:     newInternalAPILBSpecWithZones([]*string{ptr.To("1"), ptr.To("2"), ptr.To("3")}, []string{"2"}),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontends := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontends).To(HaveLen(1))
+				g.Expect(frontends[0].Zones).To(Equal([]*string{ptr.To("2")}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new internal load balancer frontend has no zones in a location without availability zones",
+			spec:     newInternalAPILBSpecWithZones(nil, nil),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontends := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontends).To(HaveLen(1))
+				g.Expect(frontends[0].Zones).To(BeEmpty())
+			},
+			expectedError: "",
+		},
+		{
+			name: "new public load balancer frontend uses the zones of its public IP",
+			spec: func() *LBSpec {
+				spec := fakePublicAPILBSpec
+				spec.FailureDomains = []*string{ptr.To("1"), ptr.To("2"), ptr.To("3")}
+				return &spec
+			}(),
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontends := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontends).To(HaveLen(1))
+				g.Expect(frontends[0].Zones).To(BeEmpty())
+			},
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -165,6 +415,82 @@ func TestParameters(t *testing.T) {
 	}
 }
 
+func newPublicAPILBSpecWithHealthProbe(healthProbe *infrav1.LoadBalancerHealthProbe) *LBSpec {
+	spec := fakePublicAPILBSpec
+	spec.HealthProbe = healthProbe
+	return &spec
+}
+
+var (
+	konnectivityPort = infrav1.LoadBalancerPort{Name: "konnectivity", Port: 8132}
+	metricsPort      = infrav1.LoadBalancerPort{Name: "metrics", Port: 9443, TargetPort: ptr.To[int32](10250)}
+)
+
+func newPublicAPILBSpecWithAdditionalPorts(ports ...infrav1.LoadBalancerPort) *LBSpec {
+	spec := fakePublicAPILBSpec
+	spec.AdditionalPorts = ports
+	return &spec
+}
+
+// newSamplePublicAPIServerLBWithAdditionalPorts returns an existing API server load balancer with the load balancing
+// rules and health probes of the given additional ports.
+func newSamplePublicAPIServerLBWithAdditionalPorts(ports ...infrav1.LoadBalancerPort) armnetwork.LoadBalancer {
+	lb := newSamplePublicAPIServerLB(false, false, false, false, false)
+	for _, port := range ports {
+		targetPort := ptr.Deref(port.TargetPort, port.Port)
+		lb.Properties.LoadBalancingRules = append(lb.Properties.LoadBalancingRules, &armnetwork.LoadBalancingRule{
+			Name: ptr.To("LBRuleAdditionalPort-" + port.Name),
+			Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+				Protocol:     ptr.To(armnetwork.TransportProtocolTCP),
+				FrontendPort: ptr.To(port.Port),
+				BackendPort:  ptr.To(targetPort),
+			},
+		})
+		lb.Properties.Probes = append(lb.Properties.Probes, &armnetwork.Probe{
+			Name: ptr.To("ProbeAdditionalPort-" + port.Name),
+			Properties: &armnetwork.ProbePropertiesFormat{
+				Protocol:          ptr.To(armnetwork.ProbeProtocolTCP),
+				Port:              ptr.To(targetPort),
+				IntervalInSeconds: ptr.To[int32](15),
+				NumberOfProbes:    ptr.To[int32](4),
+			},
+		})
+	}
+	return lb
+}
+
+// newSamplePublicAPIServerLBWithRule returns an existing API server load balancer with a load balancing rule that
+// wasn't created by CAPZ.
+func newSamplePublicAPIServerLBWithRule(name string) armnetwork.LoadBalancer {
+	lb := newSamplePublicAPIServerLB(false, false, false, false, false)
+	lb.Properties.LoadBalancingRules = append(lb.Properties.LoadBalancingRules, &armnetwork.LoadBalancingRule{
+		Name: ptr.To(name),
+		Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+			FrontendPort: ptr.To[int32](443),
+			BackendPort:  ptr.To[int32](443),
+		},
+	})
+	return lb
+}
+
+// lbRulePorts returns the frontend and backend ports of the load balancing rules by name.
+func lbRulePorts(lb armnetwork.LoadBalancer) map[string][2]int32 {
+	ports := map[string][2]int32{}
+	for _, rule := range lb.Properties.LoadBalancingRules {
+		ports[*rule.Name] = [2]int32{*rule.Properties.FrontendPort, *rule.Properties.BackendPort}
+	}
+	return ports
+}
+
+// probePorts returns the ports of the health probes by name.
+func probePorts(lb armnetwork.LoadBalancer) map[string]int32 {
+	ports := map[string]int32{}
+	for _, probe := range lb.Properties.Probes {
+		ports[*probe.Name] = *probe.Properties.Port
+	}
+	return ports
+}
+
 func newDefaultNodeOutboundLB() armnetwork.LoadBalancer {
 	return armnetwork.LoadBalancer{
 		Tags: map[string]*string{
@@ -212,7 +538,7 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 	var subnet *armnetwork.Subnet
 	var backendAddressPoolProps *armnetwork.BackendAddressPoolPropertiesFormat
 	enableFloatingIP := ptr.To(false)
-	numProbes := ptr.To[int32](4)
+	var probeThreshold *int32
 	idleTimeout := ptr.To[int32](4)
 
 	if verifyFrontendIP {
@@ -229,7 +555,7 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 		enableFloatingIP = ptr.To(true)
 	}
 	if verifyProbes {
-		numProbes = ptr.To[int32](999)
+		probeThreshold = ptr.To[int32](2)
 	}
 	if verifyOutboundRules {
 		idleTimeout = ptr.To[int32](1000)
@@ -289,7 +615,8 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 						Port:              ptr.To[int32](6443),
 						RequestPath:       ptr.To(httpsProbeRequestPath),
 						IntervalInSeconds: ptr.To[int32](15),
-						NumberOfProbes:    numProbes, // Add to verify that Probes aren't overwritten on update
+						NumberOfProbes:    ptr.To[int32](4),
+						ProbeThreshold:    probeThreshold, // Add to verify that Probes aren't overwritten on update
 					},
 				},
 			},
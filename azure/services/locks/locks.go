@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ServiceName is the name of this service.
+	ServiceName = "locks"
+
+	// authorizationFailedErrorCode is the error code returned by Azure when the identity of the cluster isn't allowed
+	// to perform an operation.
+	authorizationFailedErrorCode = "AuthorizationFailed"
+)
+
+// LockScope defines the scope interface for a management locks service.
+type LockScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ResourceLockSpecs() []azure.ResourceSpecGetter
+	AnnotationJSON(string) (map[string]interface{}, error)
+	UpdateAnnotationJSON(string, map[string]interface{}) error
+	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope LockScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope LockScope) (*Service, error) {
+	client, err := newClient(scope, scope.DefaultedAzureCallTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armresources.ClientCreateOrUpdateByIDResponse,
+			armresources.ClientDeleteByIDResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the locks and removes the previously created locks which are no longer
+// desired. The applied locks are recorded in an annotation so that they can be removed even after they are disabled.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "locks.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
+	defer cancel()
+
+	specs := s.Scope.ResourceLockSpecs()
+	lastApplied, err := s.Scope.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 && len(lastApplied) == 0 {
+		return nil
+	}
+
+	// We go through the list of locks to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	applied := map[string]interface{}{}
+	for _, spec := range specs {
+		applied[spec.OwnerResourceName()] = spec.ResourceName()
+		if _, err := s.CreateOrUpdateResource(ctx, spec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	for _, spec := range lockSpecs(lastApplied) {
+		if _, ok := applied[spec.OwnerResourceName()]; ok {
+			continue
+		}
+		if err := s.DeleteResource(ctx, spec, ServiceName); err != nil {
+			// Keep track of the lock until it is removed.
+			applied[spec.OwnerResourceName()] = spec.ResourceName()
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	if err := s.Scope.UpdateAnnotationJSON(azure.ResourceLocksLastAppliedAnnotation, applied); err != nil {
+		return err
+	}
+
+	if s.setAuthorizationFailedCondition(result) {
+		return result
+	}
+	s.Scope.UpdatePutStatus(infrav1.ResourceLocksReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete removes the desired and previously created locks, so that they don't block the deletion of the resources
+// of the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "locks.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
+	defer cancel()
+
+	specs := s.Scope.ResourceLockSpecs()
+	lastApplied, err := s.Scope.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 && len(lastApplied) == 0 {
+		return nil
+	}
+
+	desired := map[string]bool{}
+	for _, spec := range specs {
+		desired[spec.OwnerResourceName()] = true
+	}
+	for _, spec := range lockSpecs(lastApplied) {
+		if !desired[spec.OwnerResourceName()] {
+			specs = append(specs, spec)
+		}
+	}
+
+	// We go through the list of locks to delete each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
+	var result error
+	for _, spec := range specs {
+		if err := s.DeleteResource(ctx, spec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	if s.setAuthorizationFailedCondition(result) {
+		return result
+	}
+	s.Scope.UpdateDeleteStatus(infrav1.ResourceLocksReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged returns always returns true as CAPZ only manages the locks it creates.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// setAuthorizationFailedCondition explains on the ResourceLocksReady condition that the identity of the cluster
+// lacks the role required to manage locks, and reports whether err is such an authorization failure.
+func (s *Service) setAuthorizationFailedCondition(err error) bool {
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) || rerr.ErrorCode != authorizationFailedErrorCode {
+		return false
+	}
+	s.Scope.SetConditionFalse(infrav1.ResourceLocksReadyCondition, infrav1.ResourceLockAuthorizationFailedReason, clusterv1.ConditionSeverityError,
+		fmt.Sprintf("the identity of the cluster needs the Owner or User Access Administrator role to manage management locks. err: %s", err.Error()))
+	return true
+}
+
+// lockSpecs returns the specs of the locks recorded in the last applied annotation, which maps the resource ID of each
+// locked resource to the name of its lock.
+func lockSpecs(lastApplied map[string]interface{}) []azure.ResourceSpecGetter {
+	specs := make([]azure.ResourceSpecGetter, 0, len(lastApplied))
+	for scope, name := range lastApplied {
+		lockName, ok := name.(string)
+		if !ok {
+			continue
+		}
+		specs = append(specs, &LockSpec{
+			Name:  lockName,
+			Scope: scope,
+		})
+	}
+	return specs
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locks/mock_locks"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var (
+	fakeRGLockSpec = LockSpec{
+		Name:          "my-cluster-resourcegroup-lock",
+		ResourceGroup: "my-rg",
+		Scope:         "/subscriptions/123/resourceGroups/my-rg",
+		Level:         infrav1.ResourceLockLevelCanNotDelete,
+		ClusterName:   "my-cluster",
+	}
+	fakeVNetLockSpec = LockSpec{
+		Name:          "my-cluster-vnet-lock",
+		ResourceGroup: "my-rg",
+		Scope:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		Level:         infrav1.ResourceLockLevelCanNotDelete,
+		ClusterName:   "my-cluster",
+	}
+	// fakeStaleVNetLockSpec is fakeVNetLockSpec as recorded in the last applied annotation.
+	fakeStaleVNetLockSpec = LockSpec{
+		Name:  fakeVNetLockSpec.Name,
+		Scope: fakeVNetLockSpec.Scope,
+	}
+
+	authorizationFailedError = &azcore.ResponseError{
+		StatusCode: http.StatusForbidden,
+		ErrorCode:  authorizationFailedErrorCode,
+		RawResponse: &http.Response{
+			Body:       io.NopCloser(strings.NewReader("#: Authorization Failed: StatusCode=403")),
+			StatusCode: http.StatusForbidden,
+		},
+	}
+)
+
+func TestReconcileLocks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no locks are desired or applied",
+			expectedError: "",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return(nil)
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+			},
+		},
+		{
+			name:          "create locks and record them",
+			expectedError: "",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return([]azure.ResourceSpecGetter{&fakeRGLockSpec, &fakeVNetLockSpec})
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRGLockSpec, ServiceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVNetLockSpec, ServiceName).Return(nil, nil)
+				s.UpdateAnnotationJSON(azure.ResourceLocksLastAppliedAnnotation, map[string]interface{}{
+					fakeRGLockSpec.Scope:   fakeRGLockSpec.Name,
+					fakeVNetLockSpec.Scope: fakeVNetLockSpec.Name,
+				}).Return(nil)
+				s.UpdatePutStatus(infrav1.ResourceLocksReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "remove locks which are no longer desired",
+			expectedError: "",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return([]azure.ResourceSpecGetter{&fakeRGLockSpec})
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{
+					fakeRGLockSpec.Scope:   fakeRGLockSpec.Name,
+					fakeVNetLockSpec.Scope: fakeVNetLockSpec.Name,
+				}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRGLockSpec, ServiceName).Return(nil, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStaleVNetLockSpec, ServiceName).Return(nil)
+				s.UpdateAnnotationJSON(azure.ResourceLocksLastAppliedAnnotation, map[string]interface{}{
+					fakeRGLockSpec.Scope: fakeRGLockSpec.Name,
+				}).Return(nil)
+				s.UpdatePutStatus(infrav1.ResourceLocksReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "keep track of locks which fail to be removed",
+			expectedError: "boom",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return(nil)
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{
+					fakeVNetLockSpec.Scope: fakeVNetLockSpec.Name,
+				}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStaleVNetLockSpec, ServiceName).Return(errors.New("boom"))
+				s.UpdateAnnotationJSON(azure.ResourceLocksLastAppliedAnnotation, map[string]interface{}{
+					fakeVNetLockSpec.Scope: fakeVNetLockSpec.Name,
+				}).Return(nil)
+				s.UpdatePutStatus(infrav1.ResourceLocksReadyCondition, ServiceName, gomockinternal.ErrStrEq("boom"))
+			},
+		},
+		{
+			name:          "explain missing permissions to create locks",
+			expectedError: authorizationFailedError.Error(),
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return([]azure.ResourceSpecGetter{&fakeRGLockSpec})
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRGLockSpec, ServiceName).Return(nil, authorizationFailedError)
+				s.UpdateAnnotationJSON(azure.ResourceLocksLastAppliedAnnotation, map[string]interface{}{
+					fakeRGLockSpec.Scope: fakeRGLockSpec.Name,
+				}).Return(nil)
+				s.SetConditionFalse(infrav1.ResourceLocksReadyCondition, infrav1.ResourceLockAuthorizationFailedReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_locks.NewMockLockScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteLocks(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no locks are desired or applied",
+			expectedError: "",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return(nil)
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+			},
+		},
+		{
+			name:          "delete desired and previously applied locks",
+			expectedError: "",
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return([]azure.ResourceSpecGetter{&fakeRGLockSpec})
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{
+					fakeRGLockSpec.Scope:   fakeRGLockSpec.Name,
+					fakeVNetLockSpec.Scope: fakeVNetLockSpec.Name,
+				}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeRGLockSpec, ServiceName).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStaleVNetLockSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.ResourceLocksReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "explain missing permissions to delete locks",
+			expectedError: authorizationFailedError.Error(),
+			expect: func(s *mock_locks.MockLockScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceLockSpecs().Return([]azure.ResourceSpecGetter{&fakeRGLockSpec})
+				s.AnnotationJSON(azure.ResourceLocksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeRGLockSpec, ServiceName).Return(authorizationFailedError)
+				s.SetConditionFalse(infrav1.ResourceLocksReadyCondition, infrav1.ResourceLockAuthorizationFailedReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_locks.NewMockLockScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
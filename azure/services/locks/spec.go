@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// LockSpec defines the specification for a management lock on a resource.
+type LockSpec struct {
+	Name          string
+	ResourceGroup string
+	// Scope is the resource ID of the locked resource.
+	Scope       string
+	Level       infrav1.ResourceLockLevel
+	ClusterName string
+}
+
+// ResourceName returns the name of the lock.
+func (s *LockSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the locked resource.
+func (s *LockSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the resource ID of the locked resource.
+func (s *LockSpec) OwnerResourceName() string {
+	return s.Scope
+}
+
+// Parameters returns the parameters for the lock.
+func (s *LockSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	if existing != nil {
+		lock, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armresources.GenericResource", existing)
+		}
+		if properties, ok := lock.Properties.(map[string]interface{}); ok && properties["level"] == string(s.Level) {
+			// The lock is already at the desired level.
+			return nil, nil
+		}
+	}
+
+	return armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"level": string(s.Level),
+			"notes": fmt.Sprintf("Protects the resources of cluster %s. Removed by CAPZ when the cluster is deleted.", s.ClusterName),
+		},
+	}, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package locks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestParameters(t *testing.T) {
+	testcases := []struct {
+		name          string
+		spec          *LockSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "lock does not exist",
+			spec:     &fakeRGLockSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armresources.GenericResource{
+					Properties: map[string]interface{}{
+						"level": "CanNotDelete",
+						"notes": "Protects the resources of cluster my-cluster. Removed by CAPZ when the cluster is deleted.",
+					},
+				}))
+			},
+		},
+		{
+			name: "lock exists at the desired level",
+			spec: &fakeRGLockSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"level": "CanNotDelete",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "lock exists at a different level",
+			spec: &fakeRGLockSpec,
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"level": "ReadOnly",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armresources.GenericResource{}))
+				g.Expect(result.(armresources.GenericResource).Properties).To(HaveKeyWithValue("level", string(infrav1.ResourceLockLevelCanNotDelete)))
+			},
+		},
+		{
+			name:          "existing is not a generic resource",
+			spec:          &fakeRGLockSpec,
+			existing:      "not a lock",
+			expectedError: "string is not an armresources.GenericResource",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	maintenanceconfigurations *armcontainerservice.MaintenanceConfigurationsClient
+}
+
+// newClient creates a new maintenance configurations client from an authorizer.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create maintenanceconfigurations client options")
+	}
+	factory, err := armcontainerservice.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcontainerservice client factory")
+	}
+	return &azureClient{factory.NewMaintenanceConfigurationsClient()}, nil
+}
+
+// Get gets the specified maintenance configuration.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.azureClient.Get")
+	defer done()
+
+	resp, err := ac.maintenanceconfigurations.Get(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MaintenanceConfiguration, nil
+}
+
+// CreateOrUpdateAsync creates or updates a maintenance configuration.
+// Creating a maintenance configuration is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcontainerservice.MaintenanceConfigurationsClientCreateOrUpdateResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	config, ok := parameters.(armcontainerservice.MaintenanceConfiguration)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armcontainerservice.MaintenanceConfiguration", parameters)
+	}
+	resp, err := ac.maintenanceconfigurations.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), config, nil)
+	return resp.MaintenanceConfiguration, nil, err
+}
+
+// DeleteAsync deletes a maintenance configuration.
+// Deleting a maintenance configuration is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcontainerservice.MaintenanceConfigurationsClientDeleteResponse], err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.azureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.maintenanceconfigurations.Delete(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), nil)
+	return nil, err
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of this service.
+const ServiceName = "maintenanceconfigurations"
+
+// MaintenanceConfigurationScope defines the scope interface for a maintenance configurations service.
+type MaintenanceConfigurationScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	MaintenanceConfigurationSpec() azure.ResourceSpecGetter
+	AnnotationJSON(string) (map[string]interface{}, error)
+	UpdateAnnotationJSON(string, map[string]interface{}) error
+}
+
+// Service provides operations on Azure resources. The maintenance configuration is the only AKS child resource not
+// reconciled through ASO, because the ASO release CAPZ depends on (see ASO_VERSION in the Makefile) doesn't provide a
+// MaintenanceConfiguration resource. This service should be replaced with an aso.Service, and
+// maintenanceconfigurations.containerservice.azure.com added to ASO_CRDS, once ASO is upgraded to a release which
+// does.
+type Service struct {
+	Scope MaintenanceConfigurationScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope MaintenanceConfigurationScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope: scope,
+		Reconciler: async.New[armcontainerservice.MaintenanceConfigurationsClientCreateOrUpdateResponse,
+			armcontainerservice.MaintenanceConfigurationsClientDeleteResponse](scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Reconcile idempotently creates or updates the maintenance configuration of the managed cluster, and deletes the
+// previously applied one when the maintenance window is removed. The applied maintenance configuration is recorded in
+// an annotation so that it can be deleted even after the maintenance window is removed.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, s.Scope.DefaultedAzureServiceReconcileTimeout())
+	defer cancel()
+
+	spec := s.Scope.MaintenanceConfigurationSpec()
+	lastApplied, err := s.Scope.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	if spec == nil && len(lastApplied) == 0 {
+		return nil
+	}
+
+	var result error
+	applied := map[string]interface{}{}
+	if spec != nil {
+		_, result = s.CreateOrUpdateResource(ctx, spec, ServiceName)
+		applied = appliedConfig(spec)
+		s.Scope.UpdatePutStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, result)
+	} else {
+		previous := lastAppliedSpec(lastApplied)
+		if result = s.DeleteResource(ctx, previous, ServiceName); result != nil {
+			// Keep track of the maintenance configuration until it is deleted.
+			applied = lastApplied
+		}
+		s.Scope.UpdateDeleteStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, result)
+	}
+
+	if err := s.Scope.UpdateAnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation, applied); err != nil {
+		return err
+	}
+	return result
+}
+
+// Delete is a no-op as the maintenance configuration is deleted with the managed cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "maintenanceconfigurations.Service.Delete")
+	defer done()
+
+	return nil
+}
+
+// IsManaged always returns true as CAPZ does not support BYO maintenance configurations.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// appliedConfig returns the last applied annotation value recording the maintenance configuration of spec.
+func appliedConfig(spec azure.ResourceSpecGetter) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          spec.ResourceName(),
+		"resourceGroup": spec.ResourceGroupName(),
+		"clusterName":   spec.OwnerResourceName(),
+	}
+}
+
+// lastAppliedSpec returns the spec of the maintenance configuration recorded in the last applied annotation.
+func lastAppliedSpec(lastApplied map[string]interface{}) *MaintenanceConfigurationSpec {
+	spec := &MaintenanceConfigurationSpec{}
+	spec.Name, _ = lastApplied["name"].(string)
+	spec.ResourceGroup, _ = lastApplied["resourceGroup"].(string)
+	spec.ClusterName, _ = lastApplied["clusterName"].(string)
+	return spec
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/maintenanceconfigurations/mock_maintenanceconfigurations"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+var (
+	fakeMaintenanceConfigurationSpec = MaintenanceConfigurationSpec{
+		Name:          DefaultConfigName,
+		ResourceGroup: "my-rg",
+		ClusterName:   "my-cluster",
+		Window: &infrav1.MaintenanceWindow{
+			AllowedTimes: []infrav1.MaintenanceTimeInWeek{
+				{Day: "Saturday", HourSlots: []int32{0, 1, 2, 3}},
+			},
+		},
+	}
+	// fakeStaleMaintenanceConfigurationSpec is fakeMaintenanceConfigurationSpec as recorded in the last applied annotation.
+	fakeStaleMaintenanceConfigurationSpec = MaintenanceConfigurationSpec{
+		Name:          fakeMaintenanceConfigurationSpec.Name,
+		ResourceGroup: fakeMaintenanceConfigurationSpec.ResourceGroup,
+		ClusterName:   fakeMaintenanceConfigurationSpec.ClusterName,
+	}
+	fakeLastApplied = map[string]interface{}{
+		"name":          DefaultConfigName,
+		"resourceGroup": "my-rg",
+		"clusterName":   "my-cluster",
+	}
+)
+
+func TestReconcileMaintenanceConfigurations(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no maintenance window is desired or applied",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.MaintenanceConfigurationSpec().Return(nil)
+				s.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+			},
+		},
+		{
+			name:          "create maintenance configuration and record it",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.MaintenanceConfigurationSpec().Return(&fakeMaintenanceConfigurationSpec)
+				s.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeMaintenanceConfigurationSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, nil)
+				s.UpdateAnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation, fakeLastApplied).Return(nil)
+			},
+		},
+		{
+			name:          "fail to create maintenance configuration",
+			expectedError: "boom",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.MaintenanceConfigurationSpec().Return(&fakeMaintenanceConfigurationSpec)
+				s.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeMaintenanceConfigurationSpec, ServiceName).Return(nil, errors.New("boom"))
+				s.UpdatePutStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, gomockinternal.ErrStrEq("boom"))
+				s.UpdateAnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation, fakeLastApplied).Return(nil)
+			},
+		},
+		{
+			name:          "delete maintenance configuration which is no longer desired",
+			expectedError: "",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.MaintenanceConfigurationSpec().Return(nil)
+				s.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation).Return(fakeLastApplied, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStaleMaintenanceConfigurationSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, nil)
+				s.UpdateAnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
+			},
+		},
+		{
+			name:          "keep track of maintenance configuration which fails to be deleted",
+			expectedError: "boom",
+			expect: func(s *mock_maintenanceconfigurations.MockMaintenanceConfigurationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.MaintenanceConfigurationSpec().Return(nil)
+				s.AnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation).Return(fakeLastApplied, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeStaleMaintenanceConfigurationSpec, ServiceName).Return(errors.New("boom"))
+				s.UpdateDeleteStatus(infrav1.MaintenanceConfigurationReadyCondition, ServiceName, gomockinternal.ErrStrEq("boom"))
+				s.UpdateAnnotationJSON(azure.MaintenanceConfigurationLastAppliedAnnotation, fakeLastApplied).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_maintenanceconfigurations.NewMockMaintenanceConfigurationScope(mockCtrl)
+			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), reconcilerMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: reconcilerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
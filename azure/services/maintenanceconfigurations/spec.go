@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// DefaultConfigName is the name of the maintenance configuration which applies to the planned maintenance of AKS,
+// like upgrades and node image updates.
+const DefaultConfigName = "default"
+
+// MaintenanceConfigurationSpec defines the specification for the maintenance configuration of a managed cluster.
+type MaintenanceConfigurationSpec struct {
+	Name          string
+	ResourceGroup string
+	ClusterName   string
+	Window        *infrav1.MaintenanceWindow
+}
+
+// ResourceName returns the name of the maintenance configuration.
+func (s *MaintenanceConfigurationSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the managed cluster.
+func (s *MaintenanceConfigurationSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the managed cluster.
+func (s *MaintenanceConfigurationSpec) OwnerResourceName() string {
+	return s.ClusterName
+}
+
+// Parameters returns the parameters for the maintenance configuration.
+func (s *MaintenanceConfigurationSpec) Parameters(ctx context.Context, existing interface{}) (interface{}, error) {
+	properties := &armcontainerservice.MaintenanceConfigurationProperties{}
+	if s.Window != nil {
+		for _, allowed := range s.Window.AllowedTimes {
+			timeInWeek := &armcontainerservice.TimeInWeek{
+				Day: ptr.To(armcontainerservice.WeekDay(allowed.Day)),
+			}
+			for _, hour := range allowed.HourSlots {
+				timeInWeek.HourSlots = append(timeInWeek.HourSlots, ptr.To(hour))
+			}
+			properties.TimeInWeek = append(properties.TimeInWeek, timeInWeek)
+		}
+		for _, notAllowed := range s.Window.NotAllowedTimes {
+			properties.NotAllowedTime = append(properties.NotAllowedTime, &armcontainerservice.TimeSpan{
+				Start: ptr.To(notAllowed.Start.UTC()),
+				End:   ptr.To(notAllowed.End.UTC()),
+			})
+		}
+	}
+
+	if existing != nil {
+		config, ok := existing.(armcontainerservice.MaintenanceConfiguration)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armcontainerservice.MaintenanceConfiguration", existing)
+		}
+		if config.Properties != nil && sameTimes(properties, config.Properties) {
+			// The maintenance configuration is already up to date.
+			return nil, nil
+		}
+	}
+
+	return armcontainerservice.MaintenanceConfiguration{Properties: properties}, nil
+}
+
+// sameTimes reports whether the allowed and not allowed times of two maintenance configurations are the same.
+func sameTimes(a, b *armcontainerservice.MaintenanceConfigurationProperties) bool {
+	if len(a.TimeInWeek) != len(b.TimeInWeek) || len(a.NotAllowedTime) != len(b.NotAllowedTime) {
+		return false
+	}
+	for i := range a.TimeInWeek {
+		x, y := a.TimeInWeek[i], b.TimeInWeek[i]
+		if ptr.Deref(x.Day, "") != ptr.Deref(y.Day, "") || len(x.HourSlots) != len(y.HourSlots) {
+			return false
+		}
+		for j := range x.HourSlots {
+			if ptr.Deref(x.HourSlots[j], -1) != ptr.Deref(y.HourSlots[j], -1) {
+				return false
+			}
+		}
+	}
+	for i := range a.NotAllowedTime {
+		x, y := a.NotAllowedTime[i], b.NotAllowedTime[i]
+		if x.Start == nil || y.Start == nil || !x.Start.Equal(*y.Start) ||
+			x.End == nil || y.End == nil || !x.End.Equal(*y.End) {
+			return false
+		}
+	}
+	return true
+}
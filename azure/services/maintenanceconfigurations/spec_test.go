@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenanceconfigurations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestParameters(t *testing.T) {
+	start := time.Date(2024, time.December, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+	spec := &MaintenanceConfigurationSpec{
+		Name:          DefaultConfigName,
+		ResourceGroup: "my-rg",
+		ClusterName:   "my-cluster",
+		Window: &infrav1.MaintenanceWindow{
+			AllowedTimes: []infrav1.MaintenanceTimeInWeek{
+				{Day: "Saturday", HourSlots: []int32{0, 1, 2, 3}},
+			},
+			NotAllowedTimes: []infrav1.MaintenanceTimeSpan{
+				{Start: metav1.NewTime(start), End: metav1.NewTime(end)},
+			},
+		},
+	}
+	expected := armcontainerservice.MaintenanceConfiguration{
+		Properties: &armcontainerservice.MaintenanceConfigurationProperties{
+			TimeInWeek: []*armcontainerservice.TimeInWeek{
+				{
+					Day:       ptr.To(armcontainerservice.WeekDaySaturday),
+					HourSlots: []*int32{ptr.To[int32](0), ptr.To[int32](1), ptr.To[int32](2), ptr.To[int32](3)},
+				},
+			},
+			NotAllowedTime: []*armcontainerservice.TimeSpan{
+				{Start: ptr.To(start), End: ptr.To(end)},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *MaintenanceConfigurationSpec
+		existing      interface{}
+		expect        func(g *WithT, result interface{})
+		expectedError string
+	}{
+		{
+			name:     "maintenance configuration does not exist",
+			spec:     spec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(expected))
+			},
+		},
+		{
+			name: "maintenance configuration is up to date",
+			spec: spec,
+			existing: armcontainerservice.MaintenanceConfiguration{
+				Name: ptr.To(DefaultConfigName),
+				Properties: &armcontainerservice.MaintenanceConfigurationProperties{
+					TimeInWeek: expected.Properties.TimeInWeek,
+					NotAllowedTime: []*armcontainerservice.TimeSpan{
+						{Start: ptr.To(start.In(time.FixedZone("", 0))), End: ptr.To(end.In(time.FixedZone("", 0)))},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "maintenance configuration has different hours",
+			spec: spec,
+			existing: armcontainerservice.MaintenanceConfiguration{
+				Properties: &armcontainerservice.MaintenanceConfigurationProperties{
+					TimeInWeek: []*armcontainerservice.TimeInWeek{
+						{
+							Day:       ptr.To(armcontainerservice.WeekDaySaturday),
+							HourSlots: []*int32{ptr.To[int32](4), ptr.To[int32](5), ptr.To[int32](6), ptr.To[int32](7)},
+						},
+					},
+					NotAllowedTime: expected.Properties.NotAllowedTime,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(expected))
+			},
+		},
+		{
+			name:          "existing is not a maintenance configuration",
+			spec:          spec,
+			existing:      "not a maintenance configuration",
+			expectedError: "string is not an armcontainerservice.MaintenanceConfiguration",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(context.TODO(), tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.expect(g, result)
+			}
+		})
+	}
+}
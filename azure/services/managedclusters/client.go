@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListOutboundNetworkDependenciesEndpoints(ctx context.Context, resourceGroupName, resourceName string) ([]armcontainerservice.OutboundEnvironmentEndpoint, error)
+	GetPublicIPAddress(ctx context.Context, id string) (string, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	managedclusters *armcontainerservice.ManagedClustersClient
+	publicips       *armnetwork.PublicIPAddressesClient
+}
+
+var _ Client = (*azureClient)(nil)
+
+// newClient creates a new managed clusters client from an authorizer.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create managedclusters client options")
+	}
+	factory, err := armcontainerservice.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armcontainerservice client factory")
+	}
+	publicips, err := armnetwork.NewPublicIPAddressesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create publicips client")
+	}
+	return &azureClient{factory.NewManagedClustersClient(), publicips}, nil
+}
+
+// ListOutboundNetworkDependenciesEndpoints returns the endpoints a managed cluster needs outbound access to.
+func (ac *azureClient) ListOutboundNetworkDependenciesEndpoints(ctx context.Context, resourceGroupName, resourceName string) ([]armcontainerservice.OutboundEnvironmentEndpoint, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.ListOutboundNetworkDependenciesEndpoints")
+	defer done()
+
+	var endpoints []armcontainerservice.OutboundEnvironmentEndpoint
+	pager := ac.managedclusters.NewListOutboundNetworkDependenciesEndpointsPager(resourceGroupName, resourceName, nil)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return endpoints, errors.Wrap(err, "could not iterate outbound network dependencies endpoints")
+		}
+		for _, endpoint := range nextResult.Value {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// GetPublicIPAddress returns the address of the public IP with the given resource ID, or an empty string if it has
+// none yet.
+func (ac *azureClient) GetPublicIPAddress(ctx context.Context, id string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetPublicIPAddress")
+	defer done()
+
+	parsed, err := arm.ParseResourceID(id)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse public IP ID %s", id)
+	}
+	resp, err := ac.publicips.Get(ctx, parsed.ResourceGroupName, parsed.Name, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get public IP %s", id)
+	}
+	if resp.Properties == nil {
+		return "", nil
+	}
+	return ptr.Deref(resp.Properties.IPAddress, ""), nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// egressErrorCodes are the AKS error codes reported when the nodes fail to reach the endpoints the cluster depends on,
+// e.g. because egress is restricted by a firewall.
+var egressErrorCodes = []string{
+	"OutboundConnFailVMExtensionError",
+	"K8SAPIServerConnFailVMExtensionError",
+	"K8SAPIServerDNSLookupFailVMExtensionError",
+	"VMExtensionProvisioningError",
+}
+
+// isEgressError returns true if err is an AKS provisioning failure caused by restricted egress.
+func isEgressError(err error) bool {
+	if err == nil || azure.IsOperationNotDoneError(err) {
+		return false
+	}
+	msg := err.Error()
+	for _, code := range egressErrorCodes {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnoseProvisioningFailure adds the outbound endpoints the managed cluster depends on to provisioning failures
+// caused by restricted egress, so that they are reported in the failure condition. When the error mentions some of
+// them, only those are reported as they are likely blocked. The original error is returned if the endpoints can't be
+// listed.
+func diagnoseProvisioningFailure(ctx context.Context, cli Client, scope ManagedClusterScope, err error) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "managedclusters.diagnoseProvisioningFailure")
+	defer done()
+
+	if !isEgressError(err) {
+		return err
+	}
+	spec, ok := scope.ManagedClusterSpec().(*ManagedClusterSpec)
+	if !ok {
+		return err
+	}
+
+	endpoints, listErr := cli.ListOutboundNetworkDependenciesEndpoints(ctx, spec.ResourceGroup, spec.Name)
+	if listErr != nil {
+		log.Error(listErr, "failed to list outbound network dependencies endpoints", "resourceGroup", spec.ResourceGroup, "name", spec.Name)
+		return err
+	}
+
+	required, blocked := outboundEndpoints(endpoints, err.Error())
+	switch {
+	case len(blocked) > 0:
+		return errors.Wrapf(err, "outbound access to endpoints required by the cluster appears to be blocked: %s", strings.Join(blocked, ", "))
+	case len(required) > 0:
+		return errors.Wrapf(err, "the cluster failed to reach the endpoints it depends on, make sure outbound access is allowed to: %s", strings.Join(required, ", "))
+	default:
+		return err
+	}
+}
+
+// outboundEndpoints returns the endpoints as "domain:port", or "domain" when no port is given, along with the ones
+// whose domain is mentioned in msg.
+func outboundEndpoints(endpoints []armcontainerservice.OutboundEnvironmentEndpoint, msg string) (required []string, blocked []string) {
+	seen := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		for _, dependency := range endpoint.Endpoints {
+			if dependency == nil || ptr.Deref(dependency.DomainName, "") == "" {
+				continue
+			}
+			domain := *dependency.DomainName
+			mentioned := mentionsDomain(msg, domain)
+			var addresses []string
+			for _, detail := range dependency.EndpointDetails {
+				if detail != nil && detail.Port != nil {
+					addresses = append(addresses, fmt.Sprintf("%s:%d", domain, *detail.Port))
+				}
+			}
+			if len(addresses) == 0 {
+				addresses = []string{domain}
+			}
+			for _, address := range addresses {
+				if seen[address] {
+					continue
+				}
+				seen[address] = true
+				required = append(required, address)
+				if mentioned {
+					blocked = append(blocked, address)
+				}
+			}
+		}
+	}
+	return required, blocked
+}
+
+// mentionsDomain returns true if msg mentions the domain, or a subdomain of it if the domain is a wildcard.
+func mentionsDomain(msg, domain string) bool {
+	pattern := `(^|[^a-z0-9.-])` + regexp.QuoteMeta(strings.ToLower(domain)) + `($|[^a-z0-9-])`
+	if rest, ok := strings.CutPrefix(strings.ToLower(domain), "*."); ok {
+		pattern = `[a-z0-9-]\.` + regexp.QuoteMeta(rest) + `($|[^a-z0-9-])`
+	}
+	return regexp.MustCompile(pattern).MatchString(strings.ToLower(msg))
+}
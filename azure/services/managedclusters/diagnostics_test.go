@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var fakeOutboundEndpoints = []armcontainerservice.OutboundEnvironmentEndpoint{
+	{
+		Category: ptr.To("azure-resource-management"),
+		Endpoints: []*armcontainerservice.EndpointDependency{
+			{
+				DomainName: ptr.To("management.azure.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](443), Protocol: ptr.To("Https")},
+				},
+			},
+			{
+				DomainName: ptr.To("login.microsoftonline.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](443), Protocol: ptr.To("Https")},
+				},
+			},
+		},
+	},
+	{
+		Category: ptr.To("images"),
+		Endpoints: []*armcontainerservice.EndpointDependency{
+			{
+				DomainName: ptr.To("mcr.microsoft.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](443), Protocol: ptr.To("Https")},
+				},
+			},
+			{
+				DomainName: ptr.To("*.data.mcr.microsoft.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](443), Protocol: ptr.To("Https")},
+				},
+			},
+		},
+	},
+	{
+		Category: ptr.To("time-sync"),
+		Endpoints: []*armcontainerservice.EndpointDependency{
+			{
+				DomainName: ptr.To("ntp.ubuntu.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](123), Protocol: ptr.To("Udp")},
+				},
+			},
+			{
+				// Endpoints listed more than once are only reported once.
+				DomainName: ptr.To("management.azure.com"),
+				EndpointDetails: []*armcontainerservice.EndpointDetail{
+					{Port: ptr.To[int32](443), Protocol: ptr.To("Https")},
+				},
+			},
+		},
+	},
+}
+
+func TestDiagnoseProvisioningFailure(t *testing.T) {
+	egressErr := errors.New(`resource is not Ready: Code="OutboundConnFailVMExtensionError" Message="Outbound connectivity is not allowed. curl: (28) Connection timed out after 10000 milliseconds"`)
+	blockedErr := errors.New(`resource is not Ready: Code="VMExtensionProvisioningError" Message="Failed to pull image from xyz.data.mcr.microsoft.com"`)
+
+	tests := []struct {
+		name          string
+		err           error
+		expect        func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+			},
+			expectedError: "",
+		},
+		{
+			name: "operation in progress",
+			err:  azure.NewOperationNotDoneError(&infrav1.Future{}),
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+			},
+			expectedError: azure.NewOperationNotDoneError(&infrav1.Future{}).Error(),
+		},
+		{
+			name: "error not related to egress",
+			err:  errors.New(`resource is not Ready: Code="QuotaExceeded"`),
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+			},
+			expectedError: `resource is not Ready: Code="QuotaExceeded"`,
+		},
+		{
+			name: "egress error reports the required endpoints",
+			err:  egressErr,
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+				s.ManagedClusterSpec().Return(&ManagedClusterSpec{Name: "my-aks", ResourceGroup: "my-rg"})
+				c.ListOutboundNetworkDependenciesEndpoints(gomockinternal.AContext(), "my-rg", "my-aks").Return(fakeOutboundEndpoints, nil)
+			},
+			expectedError: "the cluster failed to reach the endpoints it depends on, make sure outbound access is allowed to: " +
+				"management.azure.com:443, login.microsoftonline.com:443, mcr.microsoft.com:443, *.data.mcr.microsoft.com:443, ntp.ubuntu.com:123: " +
+				egressErr.Error(),
+		},
+		{
+			name: "egress error reports the endpoints it mentions as blocked",
+			err:  blockedErr,
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+				s.ManagedClusterSpec().Return(&ManagedClusterSpec{Name: "my-aks", ResourceGroup: "my-rg"})
+				c.ListOutboundNetworkDependenciesEndpoints(gomockinternal.AContext(), "my-rg", "my-aks").Return(fakeOutboundEndpoints, nil)
+			},
+			expectedError: "outbound access to endpoints required by the cluster appears to be blocked: *.data.mcr.microsoft.com:443: " + blockedErr.Error(),
+		},
+		{
+			name: "egress error is returned as is when the endpoints can't be listed",
+			err:  egressErr,
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+				s.ManagedClusterSpec().Return(&ManagedClusterSpec{Name: "my-aks", ResourceGroup: "my-rg"})
+				c.ListOutboundNetworkDependenciesEndpoints(gomockinternal.AContext(), "my-rg", "my-aks").Return(nil, errors.New("forbidden"))
+			},
+			expectedError: egressErr.Error(),
+		},
+		{
+			name: "terminal egress error stays terminal",
+			err:  azure.WithTerminalError(egressErr),
+			expect: func(s *mock_managedclusters.MockManagedClusterScopeMockRecorder, c *mock_managedclusters.MockClientMockRecorder) {
+				s.ManagedClusterSpec().Return(&ManagedClusterSpec{Name: "my-aks", ResourceGroup: "my-rg"})
+				c.ListOutboundNetworkDependenciesEndpoints(gomockinternal.AContext(), "my-rg", "my-aks").Return(fakeOutboundEndpoints[2:], nil)
+			},
+			expectedError: "the cluster failed to reach the endpoints it depends on, make sure outbound access is allowed to: " +
+				"ntp.ubuntu.com:123, management.azure.com:443: " + azure.WithTerminalError(egressErr).Error(),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			err := diagnoseProvisioningFailure(context.Background(), clientMock, scopeMock, tc.err)
+			if tc.expectedError == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(tc.expectedError))
+			if errors.As(tc.err, &azure.ReconcileError{}) {
+				var reconcileErr azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+				g.Expect(reconcileErr.IsTerminal()).To(BeTrue())
+			}
+		})
+	}
+}
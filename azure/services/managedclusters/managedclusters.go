@@ -19,6 +19,7 @@ package managedclusters
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	"github.com/pkg/errors"
@@ -57,23 +58,35 @@ type ManagedClusterScope interface {
 	IsAADEnabled() bool
 	AreLocalAccountsDisabled() bool
 	SetOIDCIssuerProfileStatus(*infrav1.OIDCIssuerProfileStatus)
+	SetAADProfileStatus(*infrav1.AADProfileStatus)
 	MakeClusterCA() *corev1.Secret
+	MakeOIDCIssuerSecret() *corev1.Secret
+	OIDCIssuerURL() string
 	StoreClusterInfo(context.Context, []byte) error
 	SetAutoUpgradeVersionStatus(version string)
 	SetVersionStatus(version string)
+	SetNodeResourceGroupNameStatus(name string)
+	SetOutboundIPsStatus(addresses []string)
+	SetIdentityPrincipalIDStatus(principalID string)
 	IsManagedVersionUpgrade() bool
 }
 
 // New creates a new service.
-func New(scope ManagedClusterScope) *aso.Service[*asocontainerservicev1.ManagedCluster, ManagedClusterScope] {
+func New(scope ManagedClusterScope) (*aso.Service[*asocontainerservicev1.ManagedCluster, ManagedClusterScope], error) {
+	cli, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	svc := aso.NewService[*asocontainerservicev1.ManagedCluster](serviceName, scope)
 	svc.Specs = []azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedCluster]{scope.ManagedClusterSpec()}
 	svc.ConditionType = infrav1.ManagedClusterRunningCondition
-	svc.PostCreateOrUpdateResourceHook = postCreateOrUpdateResourceHook
-	return svc
+	svc.PostCreateOrUpdateResourceHook = func(ctx context.Context, scope ManagedClusterScope, managedCluster *asocontainerservicev1.ManagedCluster, err error) error {
+		return postCreateOrUpdateResourceHook(ctx, cli, scope, managedCluster, diagnoseProvisioningFailure(ctx, cli, scope, err))
+	}
+	return svc, nil
 }
 
-func postCreateOrUpdateResourceHook(ctx context.Context, scope ManagedClusterScope, managedCluster *asocontainerservicev1.ManagedCluster, err error) error {
+func postCreateOrUpdateResourceHook(ctx context.Context, cli Client, scope ManagedClusterScope, managedCluster *asocontainerservicev1.ManagedCluster, err error) error {
 	if err != nil {
 		return err
 	}
@@ -108,6 +121,24 @@ func postCreateOrUpdateResourceHook(ctx context.Context, scope ManagedClusterSco
 			IssuerURL: managedCluster.Status.OidcIssuerProfile.IssuerURL,
 		})
 	}
+	scope.SetAADProfileStatus(nil)
+	if managedCluster.Status.AadProfile != nil {
+		scope.SetAADProfileStatus(&infrav1.AADProfileStatus{
+			Managed:             ptr.Deref(managedCluster.Status.AadProfile.Managed, false),
+			AdminGroupObjectIDs: managedCluster.Status.AadProfile.AdminGroupObjectIDs,
+		})
+	}
+	scope.SetNodeResourceGroupNameStatus(ptr.Deref(managedCluster.Status.NodeResourceGroup, ""))
+	// The principal of a system-assigned identity is only known once AKS created it along with the managed cluster.
+	scope.SetIdentityPrincipalIDStatus("")
+	if managedCluster.Status.Identity != nil {
+		scope.SetIdentityPrincipalIDStatus(ptr.Deref(managedCluster.Status.Identity.PrincipalId, ""))
+	}
+	outboundIPs, err := getOutboundIPs(ctx, cli, managedCluster.Status.NetworkProfile)
+	if err != nil {
+		return errors.Wrap(err, "error while getting outbound IPs")
+	}
+	scope.SetOutboundIPsStatus(outboundIPs)
 	if managedCluster.Status.CurrentKubernetesVersion != nil {
 		currentKubernetesVersion := fmt.Sprintf("v%s", *managedCluster.Status.CurrentKubernetesVersion)
 		scope.SetVersionStatus(currentKubernetesVersion)
@@ -119,6 +150,37 @@ func postCreateOrUpdateResourceHook(ctx context.Context, scope ManagedClusterSco
 	return nil
 }
 
+// getOutboundIPs returns the sorted addresses of the effective outbound public IPs of the load balancer or NAT
+// gateway of a managed cluster.
+func getOutboundIPs(ctx context.Context, cli Client, networkProfile *asocontainerservicev1.ContainerServiceNetworkProfile_STATUS) ([]string, error) {
+	if networkProfile == nil {
+		return nil, nil
+	}
+	var refs []asocontainerservicev1.ResourceReference_STATUS
+	if networkProfile.LoadBalancerProfile != nil {
+		refs = append(refs, networkProfile.LoadBalancerProfile.EffectiveOutboundIPs...)
+	}
+	if networkProfile.NatGatewayProfile != nil {
+		refs = append(refs, networkProfile.NatGatewayProfile.EffectiveOutboundIPs...)
+	}
+
+	var addresses []string
+	for _, ref := range refs {
+		if ref.Id == nil {
+			continue
+		}
+		address, err := cli.GetPublicIPAddress(ctx, *ref.Id)
+		if err != nil {
+			return nil, err
+		}
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
 // reconcileKubeconfig will reconcile admin kubeconfig and user kubeconfig.
 /*
   Returns the admin kubeconfig and user kubeconfig
@@ -40,7 +40,7 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		scope := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
 
-		err := postCreateOrUpdateResourceHook(context.Background(), scope, nil, errors.New("an error"))
+		err := postCreateOrUpdateResourceHook(context.Background(), nil, scope, nil, errors.New("an error"))
 		g.Expect(err).To(HaveOccurred())
 	})
 
@@ -48,8 +48,11 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		g := NewGomegaWithT(t)
 		mockCtrl := gomock.NewController(t)
 		scope := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+		clientMock := mock_managedclusters.NewMockClient(mockCtrl)
 		namespace := "default"
 		clusterName := "cluster"
+		lbOutboundIPID := "/subscriptions/123/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/publicIPAddresses/lb-outbound-ip"
+		natGatewayOutboundIPID := "/subscriptions/123/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/publicIPAddresses/natgw-outbound-ip"
 
 		adminASOKubeconfig := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -87,6 +90,17 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		scope.EXPECT().SetOIDCIssuerProfileStatus(&infrav1.OIDCIssuerProfileStatus{
 			IssuerURL: ptr.To("oidc"),
 		})
+		scope.EXPECT().SetAADProfileStatus(gomock.Nil())
+		scope.EXPECT().SetAADProfileStatus(&infrav1.AADProfileStatus{
+			Managed:             false,
+			AdminGroupObjectIDs: []string{"admins"},
+		})
+		scope.EXPECT().SetNodeResourceGroupNameStatus("MC_rg_cluster_eastus")
+		scope.EXPECT().SetIdentityPrincipalIDStatus("")
+		scope.EXPECT().SetIdentityPrincipalIDStatus("system-principal")
+		clientMock.EXPECT().GetPublicIPAddress(gomock.Any(), lbOutboundIPID).Return("20.0.0.2", nil)
+		clientMock.EXPECT().GetPublicIPAddress(gomock.Any(), natGatewayOutboundIPID).Return("20.0.0.1", nil)
+		scope.EXPECT().SetOutboundIPsStatus([]string{"20.0.0.1", "20.0.0.2"})
 		scope.EXPECT().SetVersionStatus("v1.19.0")
 		scope.EXPECT().IsManagedVersionUpgrade().Return(true)
 		scope.EXPECT().SetAutoUpgradeVersionStatus("v1.19.0")
@@ -107,11 +121,27 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 				OidcIssuerProfile: &asocontainerservicev1.ManagedClusterOIDCIssuerProfile_STATUS{
 					IssuerURL: ptr.To("oidc"),
 				},
+				AadProfile: &asocontainerservicev1.ManagedClusterAADProfile_STATUS{
+					Managed:             ptr.To(false),
+					AdminGroupObjectIDs: []string{"admins"},
+				},
 				CurrentKubernetesVersion: ptr.To("1.19.0"),
+				NodeResourceGroup:        ptr.To("MC_rg_cluster_eastus"),
+				Identity: &asocontainerservicev1.ManagedClusterIdentity_STATUS{
+					PrincipalId: ptr.To("system-principal"),
+				},
+				NetworkProfile: &asocontainerservicev1.ContainerServiceNetworkProfile_STATUS{
+					LoadBalancerProfile: &asocontainerservicev1.ManagedClusterLoadBalancerProfile_STATUS{
+						EffectiveOutboundIPs: []asocontainerservicev1.ResourceReference_STATUS{{Id: ptr.To(lbOutboundIPID)}},
+					},
+					NatGatewayProfile: &asocontainerservicev1.ManagedClusterNATGatewayProfile_STATUS{
+						EffectiveOutboundIPs: []asocontainerservicev1.ResourceReference_STATUS{{Id: ptr.To(natGatewayOutboundIPID)}},
+					},
+				},
 			},
 		}
 
-		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
+		err := postCreateOrUpdateResourceHook(context.Background(), clientMock, scope, managedCluster, nil)
 		g.Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -147,7 +177,7 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 			},
 		}
 
-		err := postCreateOrUpdateResourceHook(context.Background(), scope, managedCluster, nil)
+		err := postCreateOrUpdateResourceHook(context.Background(), nil, scope, managedCluster, nil)
 		g.Expect(err).To(HaveOccurred())
 	})
 }
@@ -19,8 +19,9 @@ limitations under the License.
 //
 // Generated by this command:
 //
-//	mockgen -destination client_mock.go -package mock_managedclusters -source ../client.go CredentialGetter
+//	mockgen -destination client_mock.go -package mock_managedclusters -source ../client.go Client
 //
+
 // Package mock_managedclusters is a generated GoMock package.
 package mock_managedclusters
 
@@ -28,58 +29,59 @@ import (
 	context "context"
 	reflect "reflect"
 
+	armcontainerservice "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockCredentialGetter is a mock of CredentialGetter interface.
-type MockCredentialGetter struct {
+// MockClient is a mock of Client interface.
+type MockClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockCredentialGetterMockRecorder
+	recorder *MockClientMockRecorder
 }
 
-// MockCredentialGetterMockRecorder is the mock recorder for MockCredentialGetter.
-type MockCredentialGetterMockRecorder struct {
-	mock *MockCredentialGetter
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
 }
 
-// NewMockCredentialGetter creates a new mock instance.
-func NewMockCredentialGetter(ctrl *gomock.Controller) *MockCredentialGetter {
-	mock := &MockCredentialGetter{ctrl: ctrl}
-	mock.recorder = &MockCredentialGetterMockRecorder{mock}
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCredentialGetter) EXPECT() *MockCredentialGetterMockRecorder {
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
-// GetCredentials mocks base method.
-func (m *MockCredentialGetter) GetCredentials(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+// GetPublicIPAddress mocks base method.
+func (m *MockClient) GetPublicIPAddress(ctx context.Context, id string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCredentials", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "GetPublicIPAddress", ctx, id)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCredentials indicates an expected call of GetCredentials.
-func (mr *MockCredentialGetterMockRecorder) GetCredentials(arg0, arg1, arg2 any) *gomock.Call {
+// GetPublicIPAddress indicates an expected call of GetPublicIPAddress.
+func (mr *MockClientMockRecorder) GetPublicIPAddress(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockCredentialGetter)(nil).GetCredentials), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicIPAddress", reflect.TypeOf((*MockClient)(nil).GetPublicIPAddress), ctx, id)
 }
 
-// GetUserCredentials mocks base method.
-func (m *MockCredentialGetter) GetUserCredentials(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+// ListOutboundNetworkDependenciesEndpoints mocks base method.
+func (m *MockClient) ListOutboundNetworkDependenciesEndpoints(ctx context.Context, resourceGroupName, resourceName string) ([]armcontainerservice.OutboundEnvironmentEndpoint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserCredentials", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "ListOutboundNetworkDependenciesEndpoints", ctx, resourceGroupName, resourceName)
+	ret0, _ := ret[0].([]armcontainerservice.OutboundEnvironmentEndpoint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserCredentials indicates an expected call of GetUserCredentials.
-func (mr *MockCredentialGetterMockRecorder) GetUserCredentials(arg0, arg1, arg2 any) *gomock.Call {
+// ListOutboundNetworkDependenciesEndpoints indicates an expected call of ListOutboundNetworkDependenciesEndpoints.
+func (mr *MockClientMockRecorder) ListOutboundNetworkDependenciesEndpoints(ctx, resourceGroupName, resourceName any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCredentials", reflect.TypeOf((*MockCredentialGetter)(nil).GetUserCredentials), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboundNetworkDependenciesEndpoints", reflect.TypeOf((*MockClient)(nil).ListOutboundNetworkDependenciesEndpoints), ctx, resourceGroupName, resourceName)
 }
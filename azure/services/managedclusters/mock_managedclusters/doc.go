@@ -18,4 +18,8 @@ limitations under the License.
 //
 //go:generate ../../../../hack/tools/bin/mockgen -destination managedclusters_mock.go -package mock_managedclusters -source ../managedclusters.go ManagedClusterScope
 //go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt managedclusters_mock.go > _managedclusters_mock.go && mv _managedclusters_mock.go managedclusters_mock.go"
+//go:generate ../../../../hack/tools/bin/mockgen -destination client_mock.go -package mock_managedclusters -source ../client.go Client
+//go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt client_mock.go > _client_mock.go && mv _client_mock.go client_mock.go"
+//go:generate ../../../../hack/tools/bin/mockgen -destination noderesourcegroup_mock.go -package mock_managedclusters -source ../noderesourcegroup.go NodeResourceGroupLister
+//go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt noderesourcegroup_mock.go > _noderesourcegroup_mock.go && mv _noderesourcegroup_mock.go noderesourcegroup_mock.go"
 package mock_managedclusters
@@ -341,6 +341,20 @@ func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyKubeConfigSecret() *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyKubeConfigSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyKubeConfigSecret))
 }
 
+// MakeOIDCIssuerSecret mocks base method.
+func (m *MockManagedClusterScope) MakeOIDCIssuerSecret() *v1.Secret {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeOIDCIssuerSecret")
+	ret0, _ := ret[0].(*v1.Secret)
+	return ret0
+}
+
+// MakeOIDCIssuerSecret indicates an expected call of MakeOIDCIssuerSecret.
+func (mr *MockManagedClusterScopeMockRecorder) MakeOIDCIssuerSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeOIDCIssuerSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeOIDCIssuerSecret))
+}
+
 // ManagedClusterSpec mocks base method.
 func (m *MockManagedClusterScope) ManagedClusterSpec() azure.ASOResourceSpecGetter[*v1api20231001.ManagedCluster] {
 	m.ctrl.T.Helper()
@@ -355,6 +369,32 @@ func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterSpec() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterSpec", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterSpec))
 }
 
+// OIDCIssuerURL mocks base method.
+func (m *MockManagedClusterScope) OIDCIssuerURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCIssuerURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// OIDCIssuerURL indicates an expected call of OIDCIssuerURL.
+func (mr *MockManagedClusterScopeMockRecorder) OIDCIssuerURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCIssuerURL", reflect.TypeOf((*MockManagedClusterScope)(nil).OIDCIssuerURL))
+}
+
+// SetAADProfileStatus mocks base method.
+func (m *MockManagedClusterScope) SetAADProfileStatus(arg0 *v1beta1.AADProfileStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAADProfileStatus", arg0)
+}
+
+// SetAADProfileStatus indicates an expected call of SetAADProfileStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetAADProfileStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAADProfileStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetAADProfileStatus), arg0)
+}
+
 // SetAdminKubeconfigData mocks base method.
 func (m *MockManagedClusterScope) SetAdminKubeconfigData(arg0 []byte) {
 	m.ctrl.T.Helper()
@@ -391,6 +431,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneEndpoint(arg0 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneEndpoint", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneEndpoint), arg0)
 }
 
+// SetIdentityPrincipalIDStatus mocks base method.
+func (m *MockManagedClusterScope) SetIdentityPrincipalIDStatus(principalID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetIdentityPrincipalIDStatus", principalID)
+}
+
+// SetIdentityPrincipalIDStatus indicates an expected call of SetIdentityPrincipalIDStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetIdentityPrincipalIDStatus(principalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIdentityPrincipalIDStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetIdentityPrincipalIDStatus), principalID)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockManagedClusterScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -403,6 +455,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetLongRunningOperationState(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockManagedClusterScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetNodeResourceGroupNameStatus mocks base method.
+func (m *MockManagedClusterScope) SetNodeResourceGroupNameStatus(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNodeResourceGroupNameStatus", name)
+}
+
+// SetNodeResourceGroupNameStatus indicates an expected call of SetNodeResourceGroupNameStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetNodeResourceGroupNameStatus(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNodeResourceGroupNameStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetNodeResourceGroupNameStatus), name)
+}
+
 // SetOIDCIssuerProfileStatus mocks base method.
 func (m *MockManagedClusterScope) SetOIDCIssuerProfileStatus(arg0 *v1beta1.OIDCIssuerProfileStatus) {
 	m.ctrl.T.Helper()
@@ -415,6 +479,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetOIDCIssuerProfileStatus(arg0 a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOIDCIssuerProfileStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetOIDCIssuerProfileStatus), arg0)
 }
 
+// SetOutboundIPsStatus mocks base method.
+func (m *MockManagedClusterScope) SetOutboundIPsStatus(addresses []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOutboundIPsStatus", addresses)
+}
+
+// SetOutboundIPsStatus indicates an expected call of SetOutboundIPsStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetOutboundIPsStatus(addresses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundIPsStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetOutboundIPsStatus), addresses)
+}
+
 // SetUserKubeconfigData mocks base method.
 func (m *MockManagedClusterScope) SetUserKubeconfigData(arg0 []byte) {
 	m.ctrl.T.Helper()
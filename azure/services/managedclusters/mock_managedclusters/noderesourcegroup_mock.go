@@ -0,0 +1,72 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../noderesourcegroup.go
+//
+// Generated by this command:
+//
+//	mockgen -destination noderesourcegroup_mock.go -package mock_managedclusters -source ../noderesourcegroup.go NodeResourceGroupLister
+//
+
+// Package mock_managedclusters is a generated GoMock package.
+package mock_managedclusters
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armresources "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNodeResourceGroupLister is a mock of NodeResourceGroupLister interface.
+type MockNodeResourceGroupLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeResourceGroupListerMockRecorder
+}
+
+// MockNodeResourceGroupListerMockRecorder is the mock recorder for MockNodeResourceGroupLister.
+type MockNodeResourceGroupListerMockRecorder struct {
+	mock *MockNodeResourceGroupLister
+}
+
+// NewMockNodeResourceGroupLister creates a new mock instance.
+func NewMockNodeResourceGroupLister(ctrl *gomock.Controller) *MockNodeResourceGroupLister {
+	mock := &MockNodeResourceGroupLister{ctrl: ctrl}
+	mock.recorder = &MockNodeResourceGroupListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeResourceGroupLister) EXPECT() *MockNodeResourceGroupListerMockRecorder {
+	return m.recorder
+}
+
+// ListByResourceGroup mocks base method.
+func (m *MockNodeResourceGroupLister) ListByResourceGroup(ctx context.Context, resourceGroupName string) ([]*armresources.GenericResourceExpanded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByResourceGroup", ctx, resourceGroupName)
+	ret0, _ := ret[0].([]*armresources.GenericResourceExpanded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByResourceGroup indicates an expected call of ListByResourceGroup.
+func (mr *MockNodeResourceGroupListerMockRecorder) ListByResourceGroup(ctx, resourceGroupName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByResourceGroup", reflect.TypeOf((*MockNodeResourceGroupLister)(nil).ListByResourceGroup), ctx, resourceGroupName)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// aksManagedTagPrefixes are the prefixes of the tags that AKS and the Azure cloud provider set on the resources they
+// create in the node resource group.
+var aksManagedTagPrefixes = []string{"aks-managed-", "k8s-azure-", "kubernetes.io-created-for-"}
+
+// NodeResourceGroupLister lists the resources of the node resource group of a managed cluster.
+type NodeResourceGroupLister interface {
+	ListByResourceGroup(ctx context.Context, resourceGroupName string) ([]*armresources.GenericResourceExpanded, error)
+}
+
+// resourcesClient contains the Azure go-sdk resources client.
+type resourcesClient struct {
+	resources *armresources.Client
+}
+
+var _ NodeResourceGroupLister = (*resourcesClient)(nil)
+
+// NewNodeResourceGroupLister creates a NodeResourceGroupLister from an authorizer.
+func NewNodeResourceGroupLister(auth azure.Authorizer) (NodeResourceGroupLister, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resources client options")
+	}
+	factory, err := armresources.NewClientFactory(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create armresources client factory")
+	}
+	return &resourcesClient{factory.NewClient()}, nil
+}
+
+// ListByResourceGroup returns the resources of a resource group, or none if the resource group doesn't exist.
+func (rc *resourcesClient) ListByResourceGroup(ctx context.Context, resourceGroupName string) ([]*armresources.GenericResourceExpanded, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.resourcesClient.ListByResourceGroup")
+	defer done()
+
+	var resources []*armresources.GenericResourceExpanded
+	pager := rc.resources.NewListByResourceGroupPager(resourceGroupName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if azure.ResourceNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the resources of resource group %s", resourceGroupName)
+		}
+		resources = append(resources, page.Value...)
+	}
+	return resources, nil
+}
+
+// UnmanagedNodeResourceGroupResources returns the IDs of the resources of the node resource group of a managed
+// cluster which weren't created by AKS or the Azure cloud provider, and would be deleted along with the cluster.
+func UnmanagedNodeResourceGroupResources(ctx context.Context, lister NodeResourceGroupLister, nodeResourceGroup, managedClusterName string) ([]string, error) {
+	resources, err := lister.ListByResourceGroup(ctx, nodeResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var unmanaged []string
+	for _, resource := range resources {
+		if !isAKSManagedResource(resource, managedClusterName) {
+			unmanaged = append(unmanaged, ptr.Deref(resource.ID, ""))
+		}
+	}
+	return unmanaged, nil
+}
+
+// isAKSManagedResource returns true if a resource of the node resource group was created by AKS or the Azure cloud
+// provider. Besides tagged resources, AKS creates the kubelet identity "<cluster>-agentpool" and the identities of
+// the add-ons "<addon>-<cluster>", which aren't tagged.
+func isAKSManagedResource(resource *armresources.GenericResourceExpanded, managedClusterName string) bool {
+	for key := range resource.Tags {
+		for _, prefix := range aksManagedTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+
+	if strings.EqualFold(ptr.Deref(resource.Type, ""), "Microsoft.ManagedIdentity/userAssignedIdentities") {
+		name := ptr.Deref(resource.Name, "")
+		return name == managedClusterName+"-agentpool" || strings.HasSuffix(name, "-"+managedClusterName)
+	}
+
+	return false
+}
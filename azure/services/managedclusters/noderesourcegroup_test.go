@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+)
+
+func TestUnmanagedNodeResourceGroupResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		resources   []*armresources.GenericResourceExpanded
+		listErr     error
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:      "empty node resource group",
+			resources: nil,
+			expected:  nil,
+		},
+		{
+			name: "only AKS managed resources",
+			resources: []*armresources.GenericResourceExpanded{
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-pool0-12345678-vmss"),
+					Type: ptr.To("Microsoft.Compute/virtualMachineScaleSets"),
+					Tags: map[string]*string{"aks-managed-poolName": ptr.To("pool0")},
+				},
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/publicIPAddresses/kubernetes-a1b2"),
+					Type: ptr.To("Microsoft.Network/publicIPAddresses"),
+					Tags: map[string]*string{"k8s-azure-service": ptr.To("default/svc")},
+				},
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.ManagedIdentity/userAssignedIdentities/cluster-agentpool"),
+					Name: ptr.To("cluster-agentpool"),
+					Type: ptr.To("Microsoft.ManagedIdentity/userAssignedIdentities"),
+				},
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.ManagedIdentity/userAssignedIdentities/azurepolicy-cluster"),
+					Name: ptr.To("azurepolicy-cluster"),
+					Type: ptr.To("Microsoft.ManagedIdentity/userAssignedIdentities"),
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "resources not created by AKS",
+			resources: []*armresources.GenericResourceExpanded{
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-pool0-12345678-vmss"),
+					Type: ptr.To("Microsoft.Compute/virtualMachineScaleSets"),
+					Tags: map[string]*string{"aks-managed-poolName": ptr.To("pool0")},
+				},
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/disks/data"),
+					Type: ptr.To("Microsoft.Compute/disks"),
+					Tags: map[string]*string{"owner": ptr.To("me")},
+				},
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity"),
+					Name: ptr.To("my-identity"),
+					Type: ptr.To("Microsoft.ManagedIdentity/userAssignedIdentities"),
+				},
+			},
+			expected: []string{
+				"/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/disks/data",
+				"/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-identity",
+			},
+		},
+		{
+			name:        "listing the resources fails",
+			listErr:     errors.New("an error"),
+			expectedErr: "an error",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			lister := mock_managedclusters.NewMockNodeResourceGroupLister(mockCtrl)
+			lister.EXPECT().ListByResourceGroup(gomock.Any(), "MC_rg_cluster_eastus").Return(tc.resources, tc.listErr)
+
+			actual, err := UnmanagedNodeResourceGroupResources(context.Background(), lister, "MC_rg_cluster_eastus", "cluster")
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expected))
+		})
+	}
+}
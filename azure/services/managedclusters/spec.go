@@ -21,10 +21,15 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net"
+	"net/netip"
+	"strings"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -37,6 +42,14 @@ import (
 	"sigs.k8s.io/cluster-api/util/secret"
 )
 
+// authorizedIPRangesDriftAnnotation records the authorized IP ranges of the API server observed in Azure while a drift
+// from the desired ranges is being reverted.
+const authorizedIPRangesDriftAnnotation = "sigs.k8s.io/cluster-api-provider-azure-authorized-ip-ranges-drift"
+
+// defaultWindowsAdminUsername is the admin username AKS gives the Windows nodes of a cluster created without a
+// Windows profile.
+const defaultWindowsAdminUsername = "azureuser"
+
 // ManagedClusterSpec contains properties to create a managed cluster.
 type ManagedClusterSpec struct {
 	// Name is the name of this AKS Cluster.
@@ -87,6 +100,10 @@ type ManagedClusterSpec struct {
 	// GetAllAgentPools is a function that returns the list of agent pool specifications in this cluster.
 	GetAllAgentPools func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error)
 
+	// OnAuthorizedIPRangesDrift is called with the observed authorized IP ranges of the API server when they differ
+	// from the desired ones, e.g. because they were edited outside of CAPZ.
+	OnAuthorizedIPRangesDrift func(observed []string)
+
 	// PodCIDR is the CIDR block for IP addresses distributed to pods
 	PodCIDR string
 
@@ -99,6 +116,10 @@ type ManagedClusterSpec struct {
 	// AddonProfiles are the profiles of managed cluster add-on.
 	AddonProfiles []AddonProfile
 
+	// PreserveUnmanagedAddons keeps the add-ons of the existing cluster that aren't declared in AddonProfiles as
+	// well as the secret rotation config of the Azure Key Vault Secrets Provider add-on when it isn't declared.
+	PreserveUnmanagedAddons bool
+
 	// AADProfile is Azure Active Directory configuration to integrate with AKS, for aad authentication.
 	AADProfile *AADProfile
 
@@ -108,12 +129,19 @@ type ManagedClusterSpec struct {
 	// LoadBalancerProfile is the profile of the cluster load balancer.
 	LoadBalancerProfile *LoadBalancerProfile
 
+	// NatGatewayProfile is the profile of the NAT gateway AKS creates when OutboundType is managedNATGateway.
+	NatGatewayProfile *NatGatewayProfile
+
 	// APIServerAccessProfile is the access profile for AKS API server.
 	APIServerAccessProfile *APIServerAccessProfile
 
 	// AutoScalerProfile is the parameters to be applied to the cluster-autoscaler when enabled.
 	AutoScalerProfile *AutoScalerProfile
 
+	// AutoScalerProfileDefaults are the cluster-autoscaler parameters of the AzureManagedControlPlaneTemplate the
+	// cluster was cloned from. They apply to the parameters AutoScalerProfile leaves unset.
+	AutoScalerProfileDefaults *AutoScalerProfile
+
 	// Identity is the AKS control plane Identity configuration
 	Identity *infrav1.Identity
 
@@ -137,12 +165,40 @@ type ManagedClusterSpec struct {
 
 	// SecurityProfile defines the security profile for the cluster.
 	SecurityProfile *ManagedClusterSecurityProfile
+
+	// PodIdentityProfile is the AAD pod identity profile of the cluster.
+	PodIdentityProfile *PodIdentityProfile
+
+	// WindowsProfile configures the Windows nodes of the cluster.
+	WindowsProfile *ManagedClusterWindowsProfile
+}
+
+// PodIdentityProfile is the AAD pod identity profile of the cluster.
+type PodIdentityProfile struct {
+	// Enabled is whether the pod identity addon is enabled.
+	Enabled bool
+
+	// AllowNetworkPluginKubenet allows the pod identity addon to run on clusters using the kubenet network plugin.
+	AllowNetworkPluginKubenet *bool
+
+	// UserAssignedIdentityExceptions are the pods allowed to access the instance metadata endpoint.
+	UserAssignedIdentityExceptions []PodIdentityException
+}
+
+// PodIdentityException is a pod identity exception, matching pods by their labels.
+type PodIdentityException struct {
+	Name      string
+	Namespace string
+	PodLabels map[string]string
 }
 
 // ManagedClusterAutoUpgradeProfile auto upgrade profile for a managed cluster.
 type ManagedClusterAutoUpgradeProfile struct {
 	// UpgradeChannel defines the channel for auto upgrade configuration.
 	UpgradeChannel *infrav1.UpgradeChannel
+
+	// NodeOSUpgradeChannel defines the manner in which the OS on the nodes is updated.
+	NodeOSUpgradeChannel *infrav1.NodeOSUpgradeChannel
 }
 
 // HTTPProxyConfig is the HTTP proxy configuration for the cluster.
@@ -185,6 +241,15 @@ type SKU struct {
 	Tier string
 }
 
+// NatGatewayProfile is the profile of the AKS-managed NAT gateway.
+type NatGatewayProfile struct {
+	// ManagedOutboundIPCount is the desired number of outbound IPs AKS creates for the NAT gateway.
+	ManagedOutboundIPCount *int
+
+	// IdleTimeoutInMinutes is the desired outbound flow idle timeout in minutes.
+	IdleTimeoutInMinutes *int
+}
+
 // LoadBalancerProfile is the profile of the cluster load balancer.
 type LoadBalancerProfile struct {
 	// Load balancer profile must specify at most one of ManagedOutboundIPs, OutboundIPPrefixes and OutboundIPs.
@@ -273,6 +338,24 @@ func (s *ManagedClusterSpec) ResourceRef() *asocontainerservicev1.ManagedCluster
 	}
 }
 
+// ManagedClusterWindowsProfile configures the Windows nodes of the cluster.
+type ManagedClusterWindowsProfile struct {
+	// GmsaProfile configures Windows gMSA on the cluster.
+	GmsaProfile *WindowsGmsaProfile
+}
+
+// WindowsGmsaProfile configures Windows gMSA on the cluster.
+type WindowsGmsaProfile struct {
+	// Enabled enables Windows gMSA on the cluster.
+	Enabled *bool
+
+	// DNSServer is the DNS server for Windows gMSA.
+	DNSServer *string
+
+	// RootDomainName is the root domain name for Windows gMSA.
+	RootDomainName *string
+}
+
 // ManagedClusterSecurityProfile defines the security profile for the cluster.
 type ManagedClusterSecurityProfile struct {
 	// AzureKeyVaultKms defines Azure Key Vault key management service settings for the security profile.
@@ -338,6 +421,55 @@ type AzureKeyVaultKms struct {
 	KeyVaultResourceID *string
 }
 
+// mergeAutoScalerProfiles returns the autoscaler profile with the parameters set in overrides, and the ones of
+// defaults for the parameters overrides leaves unset.
+func mergeAutoScalerProfiles(overrides, defaults *AutoScalerProfile) *AutoScalerProfile {
+	if overrides == nil && defaults == nil {
+		return nil
+	}
+	if overrides == nil {
+		overrides = &AutoScalerProfile{}
+	}
+	if defaults == nil {
+		defaults = &AutoScalerProfile{}
+	}
+	return &AutoScalerProfile{
+		BalanceSimilarNodeGroups:      mergeAutoScalerProfileValue(overrides.BalanceSimilarNodeGroups, defaults.BalanceSimilarNodeGroups),
+		Expander:                      mergeAutoScalerProfileValue(overrides.Expander, defaults.Expander),
+		MaxEmptyBulkDelete:            mergeAutoScalerProfileValue(overrides.MaxEmptyBulkDelete, defaults.MaxEmptyBulkDelete),
+		MaxGracefulTerminationSec:     mergeAutoScalerProfileValue(overrides.MaxGracefulTerminationSec, defaults.MaxGracefulTerminationSec),
+		MaxNodeProvisionTime:          mergeAutoScalerProfileValue(overrides.MaxNodeProvisionTime, defaults.MaxNodeProvisionTime),
+		MaxTotalUnreadyPercentage:     mergeAutoScalerProfileValue(overrides.MaxTotalUnreadyPercentage, defaults.MaxTotalUnreadyPercentage),
+		NewPodScaleUpDelay:            mergeAutoScalerProfileValue(overrides.NewPodScaleUpDelay, defaults.NewPodScaleUpDelay),
+		OkTotalUnreadyCount:           mergeAutoScalerProfileValue(overrides.OkTotalUnreadyCount, defaults.OkTotalUnreadyCount),
+		ScanInterval:                  mergeAutoScalerProfileValue(overrides.ScanInterval, defaults.ScanInterval),
+		ScaleDownDelayAfterAdd:        mergeAutoScalerProfileValue(overrides.ScaleDownDelayAfterAdd, defaults.ScaleDownDelayAfterAdd),
+		ScaleDownDelayAfterDelete:     mergeAutoScalerProfileValue(overrides.ScaleDownDelayAfterDelete, defaults.ScaleDownDelayAfterDelete),
+		ScaleDownDelayAfterFailure:    mergeAutoScalerProfileValue(overrides.ScaleDownDelayAfterFailure, defaults.ScaleDownDelayAfterFailure),
+		ScaleDownUnneededTime:         mergeAutoScalerProfileValue(overrides.ScaleDownUnneededTime, defaults.ScaleDownUnneededTime),
+		ScaleDownUnreadyTime:          mergeAutoScalerProfileValue(overrides.ScaleDownUnreadyTime, defaults.ScaleDownUnreadyTime),
+		ScaleDownUtilizationThreshold: mergeAutoScalerProfileValue(overrides.ScaleDownUtilizationThreshold, defaults.ScaleDownUtilizationThreshold),
+		SkipNodesWithLocalStorage:     mergeAutoScalerProfileValue(overrides.SkipNodesWithLocalStorage, defaults.SkipNodesWithLocalStorage),
+		SkipNodesWithSystemPods:       mergeAutoScalerProfileValue(overrides.SkipNodesWithSystemPods, defaults.SkipNodesWithSystemPods),
+	}
+}
+
+// mergeAutoScalerProfileValue returns override if it is set, and def otherwise.
+func mergeAutoScalerProfileValue(override, def *string) *string {
+	if override != nil {
+		return override
+	}
+	return def
+}
+
+// disabledGmsaProfile returns the gMSA profile of a cluster whose gMSA profile was removed from the spec. AKS keeps the
+// Windows profile of a cluster, so gMSA is disabled rather than removed.
+func disabledGmsaProfile() *asocontainerservicev1.WindowsGmsaProfile {
+	return &asocontainerservicev1.WindowsGmsaProfile{
+		Enabled: ptr.To(false),
+	}
+}
+
 // buildAutoScalerProfile builds the AutoScalerProfile for the ManagedClusterProperties.
 func buildAutoScalerProfile(autoScalerProfile *AutoScalerProfile) *asocontainerservicev1.ManagedClusterProperties_AutoScalerProfile {
 	if autoScalerProfile == nil {
@@ -380,6 +512,54 @@ func (s *ManagedClusterSpec) getManagedClusterVersion(existing *asocontainerserv
 	return versions.GetHigherK8sVersion(s.Version, *existing.Status.CurrentKubernetesVersion)
 }
 
+// preserveUnmanagedAddons sets the add-ons of the existing cluster that aren't declared in AddonProfiles, e.g.
+// because they were enabled with `az aks enable-addons`, to their current state instead of disabling them. The
+// secret rotation config of a declared Azure Key Vault Secrets Provider add-on is kept unless it is declared too.
+func (s *ManagedClusterSpec) preserveUnmanagedAddons(managedCluster *asocontainerservicev1.ManagedCluster, current map[string]asocontainerservicev1.ManagedClusterAddonProfile_STATUS) {
+	for name, currentProfile := range current {
+		declared := s.addonProfile(name)
+		if declared == nil {
+			if managedCluster.Spec.AddonProfiles == nil {
+				managedCluster.Spec.AddonProfiles = map[string]asocontainerservicev1.ManagedClusterAddonProfile{}
+			}
+			managedCluster.Spec.AddonProfiles[name] = asocontainerservicev1.ManagedClusterAddonProfile{
+				Enabled: currentProfile.Enabled,
+				Config:  maps.Clone(currentProfile.Config),
+			}
+			continue
+		}
+
+		if !strings.EqualFold(declared.Name, infrav1.AzureKeyvaultSecretsProviderAddonName) {
+			continue
+		}
+		addonProfile := managedCluster.Spec.AddonProfiles[declared.Name]
+		for _, key := range []string{
+			infrav1.AzureKeyvaultSecretsProviderEnableSecretRotationKey,
+			infrav1.AzureKeyvaultSecretsProviderRotationPollIntervalKey,
+		} {
+			value, ok := currentProfile.Config[key]
+			if _, declared := declared.Config[key]; declared || !ok {
+				continue
+			}
+			if addonProfile.Config == nil {
+				addonProfile.Config = map[string]string{}
+			}
+			addonProfile.Config[key] = value
+		}
+		managedCluster.Spec.AddonProfiles[declared.Name] = addonProfile
+	}
+}
+
+// addonProfile returns the declared add-on with the given name, which AKS may report in a different case.
+func (s *ManagedClusterSpec) addonProfile(name string) *AddonProfile {
+	for i := range s.AddonProfiles {
+		if strings.EqualFold(s.AddonProfiles[i].Name, name) {
+			return &s.AddonProfiles[i]
+		}
+	}
+	return nil
+}
+
 // Parameters returns the parameters for the managed clusters.
 //
 //nolint:gocyclo // Function requires a lot of nil checks that raise complexity.
@@ -429,7 +609,7 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 	if s.NetworkDataplane != nil {
 		managedCluster.Spec.NetworkProfile.NetworkDataplane = ptr.To(asocontainerservicev1.ContainerServiceNetworkProfile_NetworkDataplane(*s.NetworkDataplane))
 	}
-	managedCluster.Spec.AutoScalerProfile = buildAutoScalerProfile(s.AutoScalerProfile)
+	managedCluster.Spec.AutoScalerProfile = buildAutoScalerProfile(mergeAutoScalerProfiles(s.AutoScalerProfile, s.AutoScalerProfileDefaults))
 
 	var decodedSSHPublicKey []byte
 	if s.SSHPublicKey != "" {
@@ -489,11 +669,20 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 		},
 	}
 	if s.AADProfile != nil {
-		managedCluster.Spec.AadProfile = &asocontainerservicev1.ManagedClusterAADProfile{
+		aadProfile := &asocontainerservicev1.ManagedClusterAADProfile{
 			Managed:             &s.AADProfile.Managed,
 			EnableAzureRBAC:     &s.AADProfile.EnableAzureRBAC,
 			AdminGroupObjectIDs: s.AADProfile.AdminGroupObjectIDs,
 		}
+		if existingProfile := managedCluster.Spec.AadProfile; !s.AADProfile.Managed && existingProfile != nil {
+			// Keep the applications of the legacy AAD integration, which CAPZ doesn't manage. Migrating to managed
+			// AAD drops them.
+			aadProfile.ClientAppID = existingProfile.ClientAppID
+			aadProfile.ServerAppID = existingProfile.ServerAppID
+			aadProfile.ServerAppSecret = existingProfile.ServerAppSecret
+			aadProfile.TenantID = existingProfile.TenantID
+		}
+		managedCluster.Spec.AadProfile = aadProfile
 		if s.DisableLocalAccounts != nil {
 			managedCluster.Spec.DisableLocalAccounts = s.DisableLocalAccounts
 		}
@@ -519,11 +708,15 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 			Enabled: &item.Enabled,
 		}
 		if item.Config != nil {
-			addonProfile.Config = item.Config
+			addonProfile.Config = maps.Clone(item.Config)
 		}
 		managedCluster.Spec.AddonProfiles[item.Name] = addonProfile
 	}
 
+	if s.PreserveUnmanagedAddons && existing != nil {
+		s.preserveUnmanagedAddons(managedCluster, existing.Status.AddonProfiles)
+	}
+
 	if s.SKU != nil {
 		tierName := asocontainerservicev1.ManagedClusterSKU_Tier(s.SKU.Tier)
 		managedCluster.Spec.Sku = &asocontainerservicev1.ManagedClusterSKU{
@@ -536,16 +729,31 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 		managedCluster.Spec.NetworkProfile.LoadBalancerProfile = s.GetLoadBalancerProfile()
 	}
 
+	if s.NatGatewayProfile != nil {
+		managedCluster.Spec.NetworkProfile.NatGatewayProfile = &asocontainerservicev1.ManagedClusterNATGatewayProfile{
+			IdleTimeoutInMinutes: s.NatGatewayProfile.IdleTimeoutInMinutes,
+		}
+		if s.NatGatewayProfile.ManagedOutboundIPCount != nil {
+			managedCluster.Spec.NetworkProfile.NatGatewayProfile.ManagedOutboundIPProfile = &asocontainerservicev1.ManagedClusterManagedOutboundIPProfile{
+				Count: s.NatGatewayProfile.ManagedOutboundIPCount,
+			}
+		}
+	}
+
 	if s.APIServerAccessProfile != nil {
+		var authorizedIPRanges []string
+		if s.APIServerAccessProfile.AuthorizedIPRanges != nil {
+			authorizedIPRanges = s.authorizedIPRanges(managedCluster)
+			if existing != nil {
+				s.recordAuthorizedIPRangesDrift(managedCluster)
+			}
+		}
 		managedCluster.Spec.ApiServerAccessProfile = &asocontainerservicev1.ManagedClusterAPIServerAccessProfile{
+			AuthorizedIPRanges:             authorizedIPRanges,
 			EnablePrivateCluster:           s.APIServerAccessProfile.EnablePrivateCluster,
 			PrivateDNSZone:                 s.APIServerAccessProfile.PrivateDNSZone,
 			EnablePrivateClusterPublicFQDN: s.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
 		}
-
-		if s.APIServerAccessProfile.AuthorizedIPRanges != nil {
-			managedCluster.Spec.ApiServerAccessProfile.AuthorizedIPRanges = s.APIServerAccessProfile.AuthorizedIPRanges
-		}
 	}
 
 	if s.OutboundType != nil {
@@ -589,7 +797,8 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 
 	if s.AutoUpgradeProfile != nil {
 		managedCluster.Spec.AutoUpgradeProfile = &asocontainerservicev1.ManagedClusterAutoUpgradeProfile{
-			UpgradeChannel: (*asocontainerservicev1.ManagedClusterAutoUpgradeProfile_UpgradeChannel)(s.AutoUpgradeProfile.UpgradeChannel),
+			UpgradeChannel:       (*asocontainerservicev1.ManagedClusterAutoUpgradeProfile_UpgradeChannel)(s.AutoUpgradeProfile.UpgradeChannel),
+			NodeOSUpgradeChannel: (*asocontainerservicev1.ManagedClusterAutoUpgradeProfile_NodeOSUpgradeChannel)(s.AutoUpgradeProfile.NodeOSUpgradeChannel),
 		}
 	}
 
@@ -636,6 +845,46 @@ func (s *ManagedClusterSpec) Parameters(ctx context.Context, existing *asocontai
 		managedCluster.Spec.SecurityProfile = securityProfile
 	}
 
+	if s.PodIdentityProfile != nil {
+		managedCluster.Spec.PodIdentityProfile = &asocontainerservicev1.ManagedClusterPodIdentityProfile{
+			Enabled:                   ptr.To(s.PodIdentityProfile.Enabled),
+			AllowNetworkPluginKubenet: s.PodIdentityProfile.AllowNetworkPluginKubenet,
+		}
+		for _, exception := range s.PodIdentityProfile.UserAssignedIdentityExceptions {
+			managedCluster.Spec.PodIdentityProfile.UserAssignedIdentityExceptions = append(managedCluster.Spec.PodIdentityProfile.UserAssignedIdentityExceptions, asocontainerservicev1.ManagedClusterPodIdentityException{
+				Name:      ptr.To(exception.Name),
+				Namespace: ptr.To(exception.Namespace),
+				PodLabels: exception.PodLabels,
+			})
+		}
+	}
+
+	if s.WindowsProfile != nil {
+		windowsProfile := managedCluster.Spec.WindowsProfile
+		if windowsProfile == nil {
+			// AKS requires the admin username of the Windows profile, keep the one AKS gave the cluster if any.
+			windowsProfile = &asocontainerservicev1.ManagedClusterWindowsProfile{
+				AdminUsername: ptr.To(defaultWindowsAdminUsername),
+			}
+			if managedCluster.Status.WindowsProfile != nil && managedCluster.Status.WindowsProfile.AdminUsername != nil {
+				windowsProfile.AdminUsername = managedCluster.Status.WindowsProfile.AdminUsername
+			}
+		}
+		switch {
+		case s.WindowsProfile.GmsaProfile != nil:
+			windowsProfile.GmsaProfile = &asocontainerservicev1.WindowsGmsaProfile{
+				Enabled:        s.WindowsProfile.GmsaProfile.Enabled,
+				DnsServer:      s.WindowsProfile.GmsaProfile.DNSServer,
+				RootDomainName: s.WindowsProfile.GmsaProfile.RootDomainName,
+			}
+		case windowsProfile.GmsaProfile != nil:
+			windowsProfile.GmsaProfile = disabledGmsaProfile()
+		}
+		managedCluster.Spec.WindowsProfile = windowsProfile
+	} else if managedCluster.Spec.WindowsProfile != nil && managedCluster.Spec.WindowsProfile.GmsaProfile != nil {
+		managedCluster.Spec.WindowsProfile.GmsaProfile = disabledGmsaProfile()
+	}
+
 	// Only include AgentPoolProfiles during initial cluster creation. Agent pools are managed solely by the
 	// AzureManagedMachinePool controller thereafter.
 	managedCluster.Spec.AgentPoolProfiles = nil
@@ -684,6 +933,111 @@ func (s *ManagedClusterSpec) GetLoadBalancerProfile() (loadBalancerProfile *asoc
 	return
 }
 
+// authorizedIPRanges returns the authorized IP ranges of the API server to set on the managed cluster. The ranges
+// already set on the existing managed cluster are kept when they are equivalent to the desired ones so that equivalent
+// representations don't cause updates.
+func (s *ManagedClusterSpec) authorizedIPRanges(existing *asocontainerservicev1.ManagedCluster) []string {
+	desired := normalizeAuthorizedIPRanges(s.APIServerAccessProfile.AuthorizedIPRanges)
+	if existing == nil {
+		return desired
+	}
+
+	if existing.Spec.ApiServerAccessProfile != nil &&
+		slices.Equal(normalizeAuthorizedIPRanges(existing.Spec.ApiServerAccessProfile.AuthorizedIPRanges), desired) {
+		return existing.Spec.ApiServerAccessProfile.AuthorizedIPRanges
+	}
+	return desired
+}
+
+// recordAuthorizedIPRangesDrift records a new drift of the authorized IP ranges of the API server of the existing
+// managed cluster in an annotation and reports it, so that each drift is only reported and reverted once. The
+// annotation is removed once the observed ranges are the desired ones again.
+func (s *ManagedClusterSpec) recordAuthorizedIPRangesDrift(managedCluster *asocontainerservicev1.ManagedCluster) {
+	annotations := managedCluster.GetAnnotations()
+	if observed, ok := s.newAuthorizedIPRangesDrift(managedCluster); ok {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[authorizedIPRangesDriftAnnotation] = strings.Join(normalizeAuthorizedIPRanges(observed), ",")
+		managedCluster.SetAnnotations(annotations)
+		if s.OnAuthorizedIPRangesDrift != nil {
+			s.OnAuthorizedIPRangesDrift(observed)
+		}
+		return
+	}
+	if _, ok := annotations[authorizedIPRangesDriftAnnotation]; ok && managedCluster.Status.ApiServerAccessProfile != nil &&
+		slices.Equal(
+			normalizeAuthorizedIPRanges(s.APIServerAccessProfile.AuthorizedIPRanges),
+			normalizeAuthorizedIPRanges(managedCluster.Status.ApiServerAccessProfile.AuthorizedIPRanges),
+		) {
+		delete(annotations, authorizedIPRangesDriftAnnotation)
+		managedCluster.SetAnnotations(annotations)
+	}
+}
+
+// newAuthorizedIPRangesDrift returns the authorized IP ranges of the API server observed in Azure and true if they
+// drifted from the desired ones and the drift wasn't recorded yet.
+func (s *ManagedClusterSpec) newAuthorizedIPRangesDrift(existing *asocontainerservicev1.ManagedCluster) ([]string, bool) {
+	if s.APIServerAccessProfile == nil || s.APIServerAccessProfile.AuthorizedIPRanges == nil ||
+		!AuthorizedIPRangesDrifted(s.APIServerAccessProfile.AuthorizedIPRanges, existing) {
+		return nil, false
+	}
+	observed := existing.Status.ApiServerAccessProfile.AuthorizedIPRanges
+	recorded, ok := existing.GetAnnotations()[authorizedIPRangesDriftAnnotation]
+	if ok && recorded == strings.Join(normalizeAuthorizedIPRanges(observed), ",") {
+		return nil, false
+	}
+	return observed, true
+}
+
+// NeedsResync implements aso.Resyncer. ASO needs to reconcile the managed cluster again to revert a new drift of the
+// authorized IP ranges of the API server, as its spec doesn't change when they are edited outside of CAPZ.
+func (s *ManagedClusterSpec) NeedsResync(existing *asocontainerservicev1.ManagedCluster) bool {
+	_, drifted := s.newAuthorizedIPRangesDrift(existing)
+	return drifted
+}
+
+// AuthorizedIPRangesDrifted returns whether the authorized IP ranges of the API server last observed in Azure differ
+// from desired. The ranges are compared regardless of their order and representation. Nothing is reported while the
+// managed cluster isn't done reconciling its latest spec, whose observed state is expected to lag behind.
+func AuthorizedIPRangesDrifted(desired []string, existing *asocontainerservicev1.ManagedCluster) bool {
+	if existing == nil || existing.Status.ApiServerAccessProfile == nil {
+		return false
+	}
+	ready, ok := existing.GetConditions().FindIndexByType(conditions.ConditionTypeReady)
+	if !ok {
+		return false
+	}
+	readyCondition := existing.GetConditions()[ready]
+	if readyCondition.Status != metav1.ConditionTrue || readyCondition.ObservedGeneration != existing.GetGeneration() {
+		return false
+	}
+	return !slices.Equal(
+		normalizeAuthorizedIPRanges(desired),
+		normalizeAuthorizedIPRanges(existing.Status.ApiServerAccessProfile.AuthorizedIPRanges),
+	)
+}
+
+// normalizeAuthorizedIPRanges returns the sorted, deduplicated CIDR form of ranges. Single IP addresses are turned
+// into a /32 (or /128 for IPv6) range as AKS does, and values that can't be parsed are kept as is.
+func normalizeAuthorizedIPRanges(ranges []string) []string {
+	if ranges == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		r = strings.TrimSpace(r)
+		if prefix, err := netip.ParsePrefix(r); err == nil {
+			r = prefix.String()
+		} else if addr, err := netip.ParseAddr(r); err == nil {
+			r = netip.PrefixFrom(addr, addr.BitLen()).String()
+		}
+		normalized = append(normalized, r)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
 func convertToResourceReferences(resources []string) []asocontainerservicev1.ResourceReference {
 	resourceReferences := make([]asocontainerservicev1.ResourceReference, len(resources))
 	for i := range resources {
@@ -736,6 +1090,8 @@ func (s *ManagedClusterSpec) WasManaged(resource *asocontainerservicev1.ManagedC
 
 var _ aso.TagsGetterSetter[*asocontainerservicev1.ManagedCluster] = (*ManagedClusterSpec)(nil)
 
+var _ aso.Resyncer[*asocontainerservicev1.ManagedCluster] = (*ManagedClusterSpec)(nil)
+
 // GetAdditionalTags implements aso.TagsGetterSetter.
 func (s *ManagedClusterSpec) GetAdditionalTags() infrav1.Tags {
 	return s.Tags
@@ -19,17 +19,27 @@ package managedclusters
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"testing"
 
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
+	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func TestParameters(t *testing.T) {
@@ -88,7 +98,8 @@ func TestParameters(t *testing.T) {
 				Expander: ptr.To("expander"),
 			},
 			AutoUpgradeProfile: &ManagedClusterAutoUpgradeProfile{
-				UpgradeChannel: ptr.To(infrav1.UpgradeChannelRapid),
+				UpgradeChannel:       ptr.To(infrav1.UpgradeChannelRapid),
+				NodeOSUpgradeChannel: ptr.To(infrav1.NodeOSUpgradeChannelNodeImage),
 			},
 			Identity: &infrav1.Identity{
 				Type:                           infrav1.ManagedControlPlaneIdentityType(asocontainerservicev1.ManagedClusterIdentity_Type_UserAssigned),
@@ -143,7 +154,6 @@ func TestParameters(t *testing.T) {
 						EnableAutoScaling: ptr.To(false),
 						Mode:              ptr.To(asocontainerservicev1.AgentPoolMode("mode")),
 						Name:              ptr.To("agentpool"),
-						OsDiskSizeGB:      ptr.To(asocontainerservicev1.ContainerServiceOSDisk(0)),
 						Type:              ptr.To(asocontainerservicev1.AgentPoolType_VirtualMachineScaleSets),
 					},
 				},
@@ -154,7 +164,8 @@ func TestParameters(t *testing.T) {
 					Expander: ptr.To(asocontainerservicev1.ManagedClusterProperties_AutoScalerProfile_Expander("expander")),
 				},
 				AutoUpgradeProfile: &asocontainerservicev1.ManagedClusterAutoUpgradeProfile{
-					UpgradeChannel: ptr.To(asocontainerservicev1.ManagedClusterAutoUpgradeProfile_UpgradeChannel_Rapid),
+					UpgradeChannel:       ptr.To(asocontainerservicev1.ManagedClusterAutoUpgradeProfile_UpgradeChannel_Rapid),
+					NodeOSUpgradeChannel: ptr.To(asocontainerservicev1.ManagedClusterAutoUpgradeProfile_NodeOSUpgradeChannel_NodeImage),
 				},
 				AzureName:            "name",
 				DisableLocalAccounts: ptr.To(true),
@@ -314,3 +325,686 @@ func TestParameters(t *testing.T) {
 		g.Expect(*actual.Spec.KubernetesVersion).To(Equal("1.26.6"))
 	})
 }
+
+func TestMergeAutoScalerProfiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides *AutoScalerProfile
+		defaults  *AutoScalerProfile
+		expected  *AutoScalerProfile
+	}{
+		{
+			name:     "neither the cluster nor the template set a profile",
+			expected: nil,
+		},
+		{
+			name:      "only the cluster sets a profile",
+			overrides: &AutoScalerProfile{Expander: ptr.To("least-waste")},
+			expected:  &AutoScalerProfile{Expander: ptr.To("least-waste")},
+		},
+		{
+			name:     "only the template sets a profile",
+			defaults: &AutoScalerProfile{Expander: ptr.To("priority"), BalanceSimilarNodeGroups: ptr.To("true")},
+			expected: &AutoScalerProfile{Expander: ptr.To("priority"), BalanceSimilarNodeGroups: ptr.To("true")},
+		},
+		{
+			name:      "the cluster overrides a key of the template",
+			overrides: &AutoScalerProfile{Expander: ptr.To("least-waste")},
+			defaults:  &AutoScalerProfile{Expander: ptr.To("priority"), BalanceSimilarNodeGroups: ptr.To("true")},
+			expected:  &AutoScalerProfile{Expander: ptr.To("least-waste"), BalanceSimilarNodeGroups: ptr.To("true")},
+		},
+		{
+			name:      "the cluster and the template set different keys",
+			overrides: &AutoScalerProfile{ScanInterval: ptr.To("20s"), SkipNodesWithSystemPods: ptr.To("false")},
+			defaults:  &AutoScalerProfile{BalanceSimilarNodeGroups: ptr.To("true"), MaxNodeProvisionTime: ptr.To("20m")},
+			expected: &AutoScalerProfile{
+				BalanceSimilarNodeGroups: ptr.To("true"),
+				MaxNodeProvisionTime:     ptr.To("20m"),
+				ScanInterval:             ptr.To("20s"),
+				SkipNodesWithSystemPods:  ptr.To("false"),
+			},
+		},
+		{
+			name:      "the cluster sets an empty profile",
+			overrides: &AutoScalerProfile{},
+			defaults:  &AutoScalerProfile{Expander: ptr.To("priority")},
+			expected:  &AutoScalerProfile{Expander: ptr.To("priority")},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(mergeAutoScalerProfiles(tc.overrides, tc.defaults)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParametersAutoScalerProfileDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &ManagedClusterSpec{
+		Version:                   "1.25.7",
+		AutoScalerProfile:         &AutoScalerProfile{Expander: ptr.To("least-waste")},
+		AutoScalerProfileDefaults: &AutoScalerProfile{Expander: ptr.To("priority"), BalanceSimilarNodeGroups: ptr.To("true")},
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+	}
+
+	actual, err := spec.Parameters(context.Background(), nil)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.AutoScalerProfile).To(Equal(&asocontainerservicev1.ManagedClusterProperties_AutoScalerProfile{
+		BalanceSimilarNodeGroups: ptr.To("true"),
+		Expander:                 ptr.To(asocontainerservicev1.ManagedClusterProperties_AutoScalerProfile_Expander_LeastWaste),
+	}))
+}
+
+func TestParametersPodIdentityProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &ManagedClusterSpec{
+		Version: "1.25.7",
+		PodIdentityProfile: &PodIdentityProfile{
+			Enabled:                   true,
+			AllowNetworkPluginKubenet: ptr.To(true),
+			UserAssignedIdentityExceptions: []PodIdentityException{
+				{
+					Name:      "legacy-app",
+					Namespace: "legacy",
+					PodLabels: map[string]string{"app": "legacy-app"},
+				},
+			},
+		},
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+	}
+
+	actual, err := spec.Parameters(context.Background(), nil)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.PodIdentityProfile).To(Equal(&asocontainerservicev1.ManagedClusterPodIdentityProfile{
+		Enabled:                   ptr.To(true),
+		AllowNetworkPluginKubenet: ptr.To(true),
+		UserAssignedIdentityExceptions: []asocontainerservicev1.ManagedClusterPodIdentityException{
+			{
+				Name:      ptr.To("legacy-app"),
+				Namespace: ptr.To("legacy"),
+				PodLabels: map[string]string{"app": "legacy-app"},
+			},
+		},
+	}))
+}
+
+func TestParametersWindowsProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *asocontainerservicev1.ManagedCluster
+		expected *asocontainerservicev1.ManagedClusterWindowsProfile
+	}{
+		{
+			name:     "new cluster gets the default admin username",
+			existing: nil,
+			expected: &asocontainerservicev1.ManagedClusterWindowsProfile{
+				AdminUsername: ptr.To("azureuser"),
+				GmsaProfile: &asocontainerservicev1.WindowsGmsaProfile{
+					Enabled:        ptr.To(true),
+					DnsServer:      ptr.To("10.0.0.4"),
+					RootDomainName: ptr.To("contoso.com"),
+				},
+			},
+		},
+		{
+			name: "existing cluster keeps the admin username AKS gave it",
+			existing: &asocontainerservicev1.ManagedCluster{
+				Status: asocontainerservicev1.ManagedCluster_STATUS{
+					WindowsProfile: &asocontainerservicev1.ManagedClusterWindowsProfile_STATUS{
+						AdminUsername: ptr.To("capzadmin"),
+					},
+				},
+			},
+			expected: &asocontainerservicev1.ManagedClusterWindowsProfile{
+				AdminUsername: ptr.To("capzadmin"),
+				GmsaProfile: &asocontainerservicev1.WindowsGmsaProfile{
+					Enabled:        ptr.To(true),
+					DnsServer:      ptr.To("10.0.0.4"),
+					RootDomainName: ptr.To("contoso.com"),
+				},
+			},
+		},
+		{
+			name: "existing windows profile is updated",
+			existing: &asocontainerservicev1.ManagedCluster{
+				Spec: asocontainerservicev1.ManagedCluster_Spec{
+					WindowsProfile: &asocontainerservicev1.ManagedClusterWindowsProfile{
+						AdminUsername:  ptr.To("capzadmin"),
+						EnableCSIProxy: ptr.To(true),
+					},
+				},
+			},
+			expected: &asocontainerservicev1.ManagedClusterWindowsProfile{
+				AdminUsername:  ptr.To("capzadmin"),
+				EnableCSIProxy: ptr.To(true),
+				GmsaProfile: &asocontainerservicev1.WindowsGmsaProfile{
+					Enabled:        ptr.To(true),
+					DnsServer:      ptr.To("10.0.0.4"),
+					RootDomainName: ptr.To("contoso.com"),
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			spec := &ManagedClusterSpec{
+				Version: "1.25.7",
+				WindowsProfile: &ManagedClusterWindowsProfile{
+					GmsaProfile: &WindowsGmsaProfile{
+						Enabled:        ptr.To(true),
+						DNSServer:      ptr.To("10.0.0.4"),
+						RootDomainName: ptr.To("contoso.com"),
+					},
+				},
+				GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+					return nil, nil
+				},
+			}
+
+			actual, err := spec.Parameters(context.Background(), tc.existing)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.WindowsProfile).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParametersWindowsProfileRemoved(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &ManagedClusterSpec{
+		Version: "1.25.7",
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+	}
+	existing := &asocontainerservicev1.ManagedCluster{
+		Spec: asocontainerservicev1.ManagedCluster_Spec{
+			WindowsProfile: &asocontainerservicev1.ManagedClusterWindowsProfile{
+				AdminUsername: ptr.To("capzadmin"),
+				GmsaProfile: &asocontainerservicev1.WindowsGmsaProfile{
+					Enabled:        ptr.To(true),
+					DnsServer:      ptr.To("10.0.0.4"),
+					RootDomainName: ptr.To("contoso.com"),
+				},
+			},
+		},
+	}
+
+	actual, err := spec.Parameters(context.Background(), existing)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.WindowsProfile).To(Equal(&asocontainerservicev1.ManagedClusterWindowsProfile{
+		AdminUsername: ptr.To("capzadmin"),
+		GmsaProfile: &asocontainerservicev1.WindowsGmsaProfile{
+			Enabled: ptr.To(false),
+		},
+	}))
+
+	// The gMSA profile alone can be removed as well.
+	spec.WindowsProfile = &ManagedClusterWindowsProfile{}
+	actual, err = spec.Parameters(context.Background(), existing)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.WindowsProfile.GmsaProfile).To(Equal(&asocontainerservicev1.WindowsGmsaProfile{
+		Enabled: ptr.To(false),
+	}))
+}
+
+func TestParametersNatGatewayProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &ManagedClusterSpec{
+		Version:      "1.25.7",
+		OutboundType: ptr.To(infrav1.ManagedControlPlaneOutboundTypeManagedNATGateway),
+		NatGatewayProfile: &NatGatewayProfile{
+			ManagedOutboundIPCount: ptr.To(2),
+			IdleTimeoutInMinutes:   ptr.To(10),
+		},
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+	}
+
+	actual, err := spec.Parameters(context.Background(), nil)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.NetworkProfile.OutboundType).To(Equal(ptr.To(asocontainerservicev1.ContainerServiceNetworkProfile_OutboundType_ManagedNATGateway)))
+	g.Expect(actual.Spec.NetworkProfile.NatGatewayProfile).To(Equal(&asocontainerservicev1.ManagedClusterNATGatewayProfile{
+		IdleTimeoutInMinutes: ptr.To(10),
+		ManagedOutboundIPProfile: &asocontainerservicev1.ManagedClusterManagedOutboundIPProfile{
+			Count: ptr.To(2),
+		},
+	}))
+}
+
+func TestParametersAuthorizedIPRanges(t *testing.T) {
+	existingManagedCluster := func(specRanges, observedRanges []string) *asocontainerservicev1.ManagedCluster {
+		return &asocontainerservicev1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 2,
+			},
+			Spec: asocontainerservicev1.ManagedCluster_Spec{
+				ApiServerAccessProfile: &asocontainerservicev1.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges: specRanges,
+				},
+			},
+			Status: asocontainerservicev1.ManagedCluster_STATUS{
+				ApiServerAccessProfile: &asocontainerservicev1.ManagedClusterAPIServerAccessProfile_STATUS{
+					AuthorizedIPRanges: observedRanges,
+				},
+				Conditions: []conditions.Condition{
+					{
+						Type:               conditions.ConditionTypeReady,
+						Status:             metav1.ConditionTrue,
+						ObservedGeneration: 2,
+					},
+				},
+			},
+		}
+	}
+
+	withAnnotations := func(existing *asocontainerservicev1.ManagedCluster, annotations map[string]string) *asocontainerservicev1.ManagedCluster {
+		existing.Annotations = annotations
+		return existing
+	}
+
+	tests := []struct {
+		name                string
+		desired             []string
+		existing            *asocontainerservicev1.ManagedCluster
+		expectedRanges      []string
+		expectedDrifted     bool
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:           "new managed cluster gets the normalized ranges",
+			desired:        []string{"192.168.0.0/16", "10.0.0.1"},
+			expectedRanges: []string{"10.0.0.1/32", "192.168.0.0/16"},
+		},
+		{
+			name:    "converged ranges in another order and representation",
+			desired: []string{"192.168.0.0/16", "10.0.0.1"},
+			existing: existingManagedCluster(
+				[]string{"10.0.0.1/32", "192.168.0.0/16"},
+				[]string{"192.168.0.0/16", "10.0.0.1/32"},
+			),
+			expectedRanges: []string{"10.0.0.1/32", "192.168.0.0/16"},
+		},
+		{
+			name:    "equivalent ranges of the existing spec are kept",
+			desired: []string{"10.0.0.1/32"},
+			existing: existingManagedCluster(
+				[]string{"10.0.0.1"},
+				[]string{"10.0.0.1/32"},
+			),
+			expectedRanges: []string{"10.0.0.1"},
+		},
+		{
+			name:    "ranges edited in Azure drifted",
+			desired: []string{"10.0.0.1/32"},
+			existing: existingManagedCluster(
+				[]string{"10.0.0.1/32"},
+				[]string{"10.0.0.1/32", "0.0.0.0/0"},
+			),
+			expectedRanges:  []string{"10.0.0.1/32"},
+			expectedDrifted: true,
+			expectedAnnotations: map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0,10.0.0.1/32",
+			},
+		},
+		{
+			name:    "drift being reverted isn't reported again",
+			desired: []string{"10.0.0.1/32"},
+			existing: withAnnotations(existingManagedCluster(
+				[]string{"10.0.0.1/32"},
+				[]string{"10.0.0.1/32", "0.0.0.0/0"},
+			), map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0,10.0.0.1/32",
+			}),
+			expectedRanges: []string{"10.0.0.1/32"},
+			expectedAnnotations: map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0,10.0.0.1/32",
+			},
+		},
+		{
+			name:    "another drift is reverted again",
+			desired: []string{"10.0.0.1/32"},
+			existing: withAnnotations(existingManagedCluster(
+				[]string{"10.0.0.1/32"},
+				[]string{"10.0.0.2/32"},
+			), map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0,10.0.0.1/32",
+			}),
+			expectedRanges:  []string{"10.0.0.1/32"},
+			expectedDrifted: true,
+			expectedAnnotations: map[string]string{
+				authorizedIPRangesDriftAnnotation: "10.0.0.2/32",
+			},
+		},
+		{
+			name:    "reverted drift is forgotten",
+			desired: []string{"10.0.0.1/32"},
+			existing: withAnnotations(existingManagedCluster(
+				[]string{"10.0.0.1/32"},
+				[]string{"10.0.0.1/32"},
+			), map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0,10.0.0.1/32",
+			}),
+			expectedRanges:      []string{"10.0.0.1/32"},
+			expectedAnnotations: map[string]string{},
+		},
+		{
+			name:    "ranges of the ASO resource edited in the cluster are reverted",
+			desired: []string{"10.0.0.1/32"},
+			existing: existingManagedCluster(
+				[]string{"0.0.0.0/0"},
+				[]string{"0.0.0.0/0"},
+			),
+			expectedRanges:  []string{"10.0.0.1/32"},
+			expectedDrifted: true,
+			expectedAnnotations: map[string]string{
+				authorizedIPRangesDriftAnnotation: "0.0.0.0/0",
+			},
+		},
+		{
+			name:    "ranges being updated don't drift",
+			desired: []string{"10.0.0.1/32"},
+			existing: func() *asocontainerservicev1.ManagedCluster {
+				existing := existingManagedCluster([]string{"10.0.0.1/32"}, []string{"10.0.0.2/32"})
+				existing.Generation = 3
+				return existing
+			}(),
+			expectedRanges: []string{"10.0.0.1/32"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			var drifted bool
+			spec := &ManagedClusterSpec{
+				Version: "1.25.7",
+				APIServerAccessProfile: &APIServerAccessProfile{
+					AuthorizedIPRanges: tc.desired,
+				},
+				GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+					return nil, nil
+				},
+				OnAuthorizedIPRangesDrift: func(observed []string) {
+					drifted = true
+					g.Expect(observed).To(Equal(tc.existing.Status.ApiServerAccessProfile.AuthorizedIPRanges))
+				},
+			}
+
+			actual, err := spec.Parameters(context.Background(), tc.existing)
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.ApiServerAccessProfile.AuthorizedIPRanges).To(Equal(tc.expectedRanges))
+			g.Expect(drifted).To(Equal(tc.expectedDrifted))
+			if tc.existing != nil {
+				g.Expect(actual.GetAnnotations()).To(Equal(tc.expectedAnnotations))
+			}
+		})
+	}
+}
+
+func TestAuthorizedIPRangesDriftIsReverted(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	sch := runtime.NewScheme()
+	g.Expect(asocontainerservicev1.AddToScheme(sch)).To(Succeed())
+	g.Expect(asoresourcesv1.AddToScheme(sch)).To(Succeed())
+	owner := &asoresourcesv1.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "ns"}}
+	existing := &asocontainerservicev1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns", Generation: 1},
+		Spec: asocontainerservicev1.ManagedCluster_Spec{
+			ApiServerAccessProfile: &asocontainerservicev1.ManagedClusterAPIServerAccessProfile{
+				AuthorizedIPRanges: []string{"10.0.0.1/32"},
+			},
+		},
+		Status: asocontainerservicev1.ManagedCluster_STATUS{
+			ApiServerAccessProfile: &asocontainerservicev1.ManagedClusterAPIServerAccessProfile_STATUS{
+				AuthorizedIPRanges: []string{"0.0.0.0/0"},
+			},
+			Conditions: []conditions.Condition{
+				{Type: conditions.ConditionTypeReady, Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			},
+		},
+	}
+	g.Expect(controllerutil.SetControllerReference(owner, existing, sch)).To(Succeed())
+	c := fakeclient.NewClientBuilder().WithScheme(sch).WithObjects(existing).Build()
+
+	var drifts int
+	spec := &ManagedClusterSpec{
+		Name:    "cluster",
+		Version: "1.25.7",
+		APIServerAccessProfile: &APIServerAccessProfile{
+			AuthorizedIPRanges: []string{"10.0.0.1/32"},
+		},
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+		OnAuthorizedIPRangesDrift: func([]string) { drifts++ },
+	}
+	r := aso.New[*asocontainerservicev1.ManagedCluster](c, "cluster", owner)
+	reconcile := func() map[string]string {
+		_, err := r.CreateOrUpdateResource(ctx, spec, serviceName)
+		g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue(), "expected the managed cluster to be patched")
+		managedCluster := &asocontainerservicev1.ManagedCluster{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(existing), managedCluster)).To(Succeed())
+		return managedCluster.GetAnnotations()
+	}
+
+	// The drift stops ASO from reconciling the managed cluster...
+	annotations := reconcile()
+	g.Expect(annotations).To(HaveKeyWithValue(asoannotations.ReconcilePolicy, string(asoannotations.ReconcilePolicySkip)))
+	g.Expect(annotations).To(HaveKeyWithValue(authorizedIPRangesDriftAnnotation, "0.0.0.0/0"))
+	g.Expect(drifts).To(Equal(1))
+
+	// ...and resumes it, which makes ASO reconcile the managed cluster and put the desired ranges back.
+	annotations = reconcile()
+	g.Expect(annotations).To(HaveKeyWithValue(asoannotations.ReconcilePolicy, string(asoannotations.ReconcilePolicyManage)))
+	g.Expect(drifts).To(Equal(1))
+
+	// The drift is forgotten once reverted.
+	managedCluster := &asocontainerservicev1.ManagedCluster{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(existing), managedCluster)).To(Succeed())
+	managedCluster.Status.ApiServerAccessProfile.AuthorizedIPRanges = []string{"10.0.0.1/32"}
+	g.Expect(c.Update(ctx, managedCluster)).To(Succeed())
+	annotations = reconcile()
+	g.Expect(annotations).NotTo(HaveKey(authorizedIPRangesDriftAnnotation))
+	g.Expect(drifts).To(Equal(1))
+}
+
+func TestParametersOmitsDockerBridgeCIDR(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A managed cluster created with an API version that still had the Docker bridge network.
+	existing := &asocontainerservicev1.ManagedCluster{}
+	g.Expect(json.Unmarshal([]byte(`{
+		"apiVersion": "containerservice.azure.com/v1api20230201",
+		"kind": "ManagedCluster",
+		"spec": {
+			"networkProfile": {
+				"dnsServiceIP": "10.0.0.10",
+				"dockerBridgeCidr": "172.17.0.1/16",
+				"serviceCidr": "10.0.0.0/16"
+			}
+		}
+	}`), existing)).To(Succeed())
+
+	spec := &ManagedClusterSpec{
+		Version:      "1.25.7",
+		ServiceCIDR:  "10.0.0.0/16",
+		DNSServiceIP: ptr.To("10.0.0.10"),
+		GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+			return nil, nil
+		},
+	}
+
+	actual, err := spec.Parameters(context.Background(), existing)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual.Spec.NetworkProfile.DnsServiceIP).To(Equal(ptr.To("10.0.0.10")))
+	data, err := json.Marshal(actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).NotTo(ContainSubstring("dockerBridgeCidr"))
+}
+
+func TestParametersLegacyAADProfile(t *testing.T) {
+	newExisting := func() *asocontainerservicev1.ManagedCluster {
+		return &asocontainerservicev1.ManagedCluster{
+			Spec: asocontainerservicev1.ManagedCluster_Spec{
+				AadProfile: &asocontainerservicev1.ManagedClusterAADProfile{
+					Managed:     ptr.To(false),
+					ClientAppID: ptr.To("client-app"),
+					ServerAppID: ptr.To("server-app"),
+					TenantID:    ptr.To("tenant"),
+				},
+			},
+		}
+	}
+	newSpec := func(managed bool) *ManagedClusterSpec {
+		return &ManagedClusterSpec{
+			Version: "1.25.7",
+			AADProfile: &AADProfile{
+				Managed:             managed,
+				EnableAzureRBAC:     managed,
+				AdminGroupObjectIDs: []string{"admins"},
+			},
+			GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+				return nil, nil
+			},
+		}
+	}
+
+	t.Run("the legacy AAD integration is kept", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		actual, err := newSpec(false).Parameters(context.Background(), newExisting())
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.AadProfile).To(Equal(&asocontainerservicev1.ManagedClusterAADProfile{
+			Managed:             ptr.To(false),
+			EnableAzureRBAC:     ptr.To(false),
+			AdminGroupObjectIDs: []string{"admins"},
+			ClientAppID:         ptr.To("client-app"),
+			ServerAppID:         ptr.To("server-app"),
+			TenantID:            ptr.To("tenant"),
+		}))
+	})
+
+	t.Run("migrating to managed AAD drops the legacy applications", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		actual, err := newSpec(true).Parameters(context.Background(), newExisting())
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.AadProfile).To(Equal(&asocontainerservicev1.ManagedClusterAADProfile{
+			Managed:             ptr.To(true),
+			EnableAzureRBAC:     ptr.To(true),
+			AdminGroupObjectIDs: []string{"admins"},
+		}))
+		g.Expect(actual.Spec.OperatorSpec.Secrets.UserCredentials).NotTo(BeNil())
+	})
+}
+
+func TestParametersAddonProfiles(t *testing.T) {
+	newExisting := func() *asocontainerservicev1.ManagedCluster {
+		return &asocontainerservicev1.ManagedCluster{
+			Status: asocontainerservicev1.ManagedCluster_STATUS{
+				AddonProfiles: map[string]asocontainerservicev1.ManagedClusterAddonProfile_STATUS{
+					"azurepolicy": {
+						Enabled: ptr.To(true),
+						Config:  map[string]string{"version": "v2"},
+					},
+					"azureKeyvaultSecretsProvider": {
+						Enabled: ptr.To(true),
+						Config: map[string]string{
+							"enableSecretRotation": "true",
+							"rotationPollInterval": "5m",
+						},
+					},
+				},
+			},
+		}
+	}
+	newSpec := func(preserve bool, keyVaultConfig map[string]string) *ManagedClusterSpec {
+		return &ManagedClusterSpec{
+			Version: "1.25.7",
+			AddonProfiles: []AddonProfile{
+				{
+					Name:    "azureKeyvaultSecretsProvider",
+					Enabled: true,
+					Config:  keyVaultConfig,
+				},
+			},
+			PreserveUnmanagedAddons: preserve,
+			GetAllAgentPools: func() ([]azure.ASOResourceSpecGetter[*asocontainerservicev1.ManagedClustersAgentPool], error) {
+				return nil, nil
+			},
+		}
+	}
+
+	t.Run("only the declared add-ons are set by default", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		actual, err := newSpec(false, nil).Parameters(context.Background(), newExisting())
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.AddonProfiles).To(Equal(map[string]asocontainerservicev1.ManagedClusterAddonProfile{
+			"azureKeyvaultSecretsProvider": {Enabled: ptr.To(true)},
+		}))
+	})
+
+	t.Run("unmanaged add-ons and the secret rotation config are preserved", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		actual, err := newSpec(true, nil).Parameters(context.Background(), newExisting())
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.AddonProfiles).To(Equal(map[string]asocontainerservicev1.ManagedClusterAddonProfile{
+			"azurepolicy": {
+				Enabled: ptr.To(true),
+				Config:  map[string]string{"version": "v2"},
+			},
+			"azureKeyvaultSecretsProvider": {
+				Enabled: ptr.To(true),
+				Config: map[string]string{
+					"enableSecretRotation": "true",
+					"rotationPollInterval": "5m",
+				},
+			},
+		}))
+	})
+
+	t.Run("the declared secret rotation config takes precedence", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		keyVaultConfig := map[string]string{"enableSecretRotation": "false"}
+		actual, err := newSpec(true, keyVaultConfig).Parameters(context.Background(), newExisting())
+
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(actual.Spec.AddonProfiles["azureKeyvaultSecretsProvider"].Config).To(Equal(map[string]string{
+			"enableSecretRotation": "false",
+			"rotationPollInterval": "5m",
+		}))
+		g.Expect(keyVaultConfig).To(Equal(map[string]string{"enableSecretRotation": "false"}))
+	})
+}
@@ -29,14 +29,16 @@ import (
 
 // NatGatewaySpec defines the specification for a NAT gateway.
 type NatGatewaySpec struct {
-	Name           string
-	ResourceGroup  string
-	SubscriptionID string
-	Location       string
-	NatGatewayIP   infrav1.PublicIPSpec
-	ClusterName    string
-	AdditionalTags infrav1.Tags
-	IsVnetManaged  bool
+	Name                 string
+	ResourceGroup        string
+	SubscriptionID       string
+	Location             string
+	Zone                 string
+	IdleTimeoutInMinutes *int32
+	NatGatewayIP         infrav1.PublicIPSpec
+	ClusterName          string
+	AdditionalTags       infrav1.Tags
+	IsVnetManaged        bool
 }
 
 // ResourceRef implements azure.ASOResourceSpecGetter.
@@ -65,6 +67,12 @@ func (s *NatGatewaySpec) Parameters(ctx context.Context, existingNatGateway *aso
 	natGateway.Spec.Sku = &asonetworkv1.NatGatewaySku{
 		Name: ptr.To(asonetworkv1.NatGatewaySku_Name_Standard),
 	}
+	if s.Zone != "" {
+		natGateway.Spec.Zones = []string{s.Zone}
+	}
+	if s.IdleTimeoutInMinutes != nil {
+		natGateway.Spec.IdleTimeoutInMinutes = ptr.To(int(*s.IdleTimeoutInMinutes))
+	}
 	natGateway.Spec.PublicIpAddresses = []asonetworkv1.ApplicationGatewaySubResource{
 		{
 			Reference: &genruntime.ResourceReference{
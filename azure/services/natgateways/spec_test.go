@@ -131,6 +131,47 @@ func TestParameters(t *testing.T) {
 				g.Expect(diff).To(BeEmpty())
 			},
 		},
+		{
+			name: "create a zonal NAT Gateway spec with an idle timeout",
+			spec: &NatGatewaySpec{
+				Name:                 "my-natgateway",
+				ResourceGroup:        "my-rg",
+				SubscriptionID:       "123",
+				Location:             "eastus",
+				Zone:                 "2",
+				IdleTimeoutInMinutes: ptr.To[int32](10),
+				NatGatewayIP: infrav1.PublicIPSpec{
+					Name: "my-natgateway-ip",
+				},
+				ClusterName:   "my-cluster",
+				IsVnetManaged: true,
+			},
+			existingSpec: nil,
+			expect: func(g *WithT, existing *asonetworkv1.NatGateway, parameters *asonetworkv1.NatGateway) {
+				g.Expect(parameters.Spec.Zones).To(Equal([]string{"2"}))
+				g.Expect(parameters.Spec.IdleTimeoutInMinutes).To(Equal(ptr.To(10)))
+			},
+		},
+		{
+			name: "update the idle timeout of an existing NAT Gateway in place",
+			spec: &NatGatewaySpec{
+				Name:                 "my-natgateway",
+				ResourceGroup:        "my-rg",
+				SubscriptionID:       "123",
+				Location:             "eastus",
+				IdleTimeoutInMinutes: ptr.To[int32](30),
+				NatGatewayIP: infrav1.PublicIPSpec{
+					Name: "my-natgateway-ip",
+				},
+				ClusterName:   "my-cluster",
+				IsVnetManaged: true,
+			},
+			existingSpec: existingNatGateway,
+			expect: func(g *WithT, existing *asonetworkv1.NatGateway, parameters *asonetworkv1.NatGateway) {
+				g.Expect(parameters.Spec.IdleTimeoutInMinutes).To(Equal(ptr.To(30)))
+				g.Expect(parameters.Spec.Zones).To(BeNil())
+			},
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -281,6 +281,18 @@ func (mr *MockNICScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockNICScope)(nil).FailureDomains))
 }
 
+// ForgetManagedResource mocks base method.
+func (m *MockNICScope) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockNICScopeMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockNICScope)(nil).ForgetManagedResource), id)
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockNICScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -309,6 +321,34 @@ func (mr *MockNICScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockNICScope)(nil).HashKey))
 }
 
+// IsManagedResource mocks base method.
+func (m *MockNICScope) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockNICScopeMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockNICScope)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockNICScope) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockNICScopeMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockNICScope)(nil).IsOwnershipRecorded))
+}
+
 // Location mocks base method.
 func (m *MockNICScope) Location() string {
 	m.ctrl.T.Helper()
@@ -351,6 +391,18 @@ func (mr *MockNICScopeMockRecorder) NodeResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockNICScope)(nil).NodeResourceGroup))
 }
 
+// RecordManagedResource mocks base method.
+func (m *MockNICScope) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockNICScopeMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockNICScope)(nil).RecordManagedResource), id)
+}
+
 // ResourceGroup mocks base method.
 func (m *MockNICScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
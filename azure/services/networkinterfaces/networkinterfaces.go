@@ -33,6 +33,7 @@ const serviceName = "interfaces"
 type NICScope interface {
 	azure.ClusterDescriber
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	NICSpecs() []azure.ResourceSpecGetter
 }
 
@@ -40,6 +41,7 @@ type NICScope interface {
 type Service struct {
 	Scope NICScope
 	async.Reconciler
+	async.Getter
 	resourceSKUCache *resourceskus.Cache
 }
 
@@ -50,9 +52,10 @@ func New(scope NICScope, skuCache *resourceskus.Cache) (*Service, error) {
 		return nil, err
 	}
 	return &Service{
-		Scope: scope,
+		Scope:  scope,
+		Getter: client,
 		Reconciler: async.New[armnetwork.InterfacesClientCreateOrUpdateResponse,
-			armnetwork.InterfacesClientDeleteResponse](scope, client, client),
+			armnetwork.InterfacesClientDeleteResponse](scope, client, client).WithDeleteConfirmation(client),
 		resourceSKUCache: skuCache,
 	}, nil
 }
@@ -79,7 +82,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
+	recordOwnership := s.Scope.IsOwnershipRecorded()
 	for _, nicSpec := range specs {
+		if recordOwnership {
+			if err := async.RecordManagedResourceIfNotFound(ctx, s.Scope, s.Getter, nicSpec, s.networkInterfaceID(nicSpec)); err != nil {
+				result = err
+				continue
+			}
+		}
 		if _, err := s.CreateOrUpdateResource(ctx, nicSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
@@ -113,6 +123,8 @@ func (s *Service) Delete(ctx context.Context) error {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+		} else {
+			s.Scope.ForgetManagedResource(s.networkInterfaceID(nicSpec))
 		}
 	}
 
@@ -120,6 +132,11 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// networkInterfaceID returns the Azure resource ID of the network interface.
+func (s *Service) networkInterfaceID(spec azure.ResourceSpecGetter) string {
+	return azure.NetworkInterfaceID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO network interfaces.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -73,6 +73,7 @@ var (
 		SKU:                   &fakeSku,
 		IPConfigs:             []IPConfig{{}, {}},
 	}
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
 	internalError = &azcore.ResponseError{
 		RawResponse: &http.Response{
 			Body:       io.NopCloser(strings.NewReader("#: Internal Server Error: StatusCode=500")),
@@ -85,12 +86,12 @@ func TestReconcileNetworkInterface(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
 			name:          "noop if no network interface specs are found",
 			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{})
 			},
@@ -98,9 +99,10 @@ func TestReconcileNetworkInterface(t *testing.T) {
 		{
 			name:          "successfully create a network interface",
 			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
 			},
@@ -108,9 +110,10 @@ func TestReconcileNetworkInterface(t *testing.T) {
 		{
 			name:          "successfully create a network interface with multiple IPConfigs",
 			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec3})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec3, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
 			},
@@ -118,9 +121,10 @@ func TestReconcileNetworkInterface(t *testing.T) {
 		{
 			name:          "successfully create multiple network interfaces",
 			expectedError: "",
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1, &fakeNICSpec2})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec2, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
@@ -129,14 +133,36 @@ func TestReconcileNetworkInterface(t *testing.T) {
 		{
 			name:          "network interface create fails",
 			expectedError: internalError.Error(),
-			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1, &fakeNICSpec2})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil, internalError)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec2, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "record network interfaces before creating them when ownership is recorded",
+			expectedError: "",
+			expect: func(s *mock_networkinterfaces.MockNICScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1, &fakeNICSpec2})
+				s.IsOwnershipRecorded().Return(true)
+
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.NetworkInterfaceID("123", "my-rg", "nic-1"), false).Return(false)
+				g.Get(gomockinternal.AContext(), &fakeNICSpec1).Return(nil, notFoundError)
+				s.RecordManagedResource(azure.NetworkInterfaceID("123", "my-rg", "nic-1"))
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil, nil)
+
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.NetworkInterfaceID("123", "my-rg", "nic-2"), false).Return(true)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNICSpec2, serviceName).Return(nil, nil)
+
+				s.UpdatePutStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -147,12 +173,14 @@ func TestReconcileNetworkInterface(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			scopeMock := mock_networkinterfaces.NewMockNICScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), asyncMock.EXPECT())
 
 			s := &Service{
 				Scope:      scopeMock,
+				Getter:     getterMock,
 				Reconciler: asyncMock,
 			}
 
@@ -190,6 +218,8 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1})
 				r.DeleteResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.NetworkInterfaceID("123", fakeNICSpec1.ResourceGroupName(), fakeNICSpec1.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
 			},
 		},
@@ -200,7 +230,11 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1, &fakeNICSpec2})
 				r.DeleteResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.NetworkInterfaceID("123", fakeNICSpec1.ResourceGroupName(), fakeNICSpec1.ResourceName()))
 				r.DeleteResource(gomockinternal.AContext(), &fakeNICSpec2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.NetworkInterfaceID("123", fakeNICSpec2.ResourceGroupName(), fakeNICSpec2.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, nil)
 			},
 		},
@@ -211,6 +245,8 @@ func TestDeleteNetworkInterface(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.NICSpecs().Return([]azure.ResourceSpecGetter{&fakeNICSpec1, &fakeNICSpec2})
 				r.DeleteResource(gomockinternal.AContext(), &fakeNICSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.NetworkInterfaceID("123", fakeNICSpec1.ResourceGroupName(), fakeNICSpec1.ResourceName()))
 				r.DeleteResource(gomockinternal.AContext(), &fakeNICSpec2, serviceName).Return(internalError)
 				s.UpdateDeleteStatus(infrav1.NetworkInterfaceReadyCondition, serviceName, internalError)
 			},
@@ -18,7 +18,9 @@ package networkinterfaces
 
 import (
 	"context"
+	"net"
 	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
@@ -46,15 +48,22 @@ type NICSpec struct {
 	PublicLBNATRuleName       string
 	InternalLBName            string
 	InternalLBAddressPoolName string
-	PublicIPName              string
-	AcceleratedNetworking     *bool
-	IPv6Enabled               bool
-	EnableIPForwarding        bool
-	SKU                       *resourceskus.SKU
-	DNSServers                []string
-	AdditionalTags            infrav1.Tags
-	ClusterName               string
-	IPConfigs                 []IPConfig
+	// UpdateLBReferences makes the load balancer backend pools and inbound NAT rule of the primary IP configuration of
+	// an existing network interface match the spec, e.g. while the API server load balancer is migrated.
+	UpdateLBReferences bool
+	// DetachedLBName is the name of a load balancer whose backend pools and inbound NAT rules the primary IP
+	// configuration is removed from when UpdateLBReferences is set.
+	DetachedLBName        string
+	PublicIPName          string
+	AcceleratedNetworking *bool
+	IPv6Enabled           bool
+	EnableIPForwarding    bool
+	SKU                   *resourceskus.SKU
+	DNSServers            []string
+	AdditionalTags        infrav1.Tags
+	ClusterName           string
+	IPConfigs             []IPConfig
+	SubnetCIDRs           []string
 }
 
 // IPConfig defines the specification for an IP address configuration.
@@ -80,12 +89,17 @@ func (s *NICSpec) OwnerResourceName() string {
 
 // Parameters returns the parameters for the network interface.
 func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
+	if err := s.validateSubnetSize(); err != nil {
+		return nil, err
+	}
+
 	if existing != nil {
-		if _, ok := existing.(armnetwork.Interface); !ok {
+		existingNIC, ok := existing.(armnetwork.Interface)
+		if !ok {
 			return nil, errors.Errorf("%T is not an armnetwork.Interface", existing)
 		}
 		// network interface already exists
-		return nil, nil
+		return s.existingParameters(existingNIC), nil
 	}
 
 	primaryIPConfig := &armnetwork.InterfaceIPConfigurationPropertiesFormat{
@@ -104,26 +118,15 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 	}
 
 	backendAddressPools := []*armnetwork.BackendAddressPool{}
-	if s.PublicLBName != "" {
-		if s.PublicLBAddressPoolName != "" {
-			backendAddressPools = append(backendAddressPools,
-				&armnetwork.BackendAddressPool{
-					ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName)),
-				})
-		}
-		if s.PublicLBNATRuleName != "" {
-			primaryIPConfig.LoadBalancerInboundNatRules = []*armnetwork.InboundNatRule{
-				{
-					ID: ptr.To(azure.NATRuleID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBNATRuleName)),
-				},
-			}
-		}
+	for _, id := range s.backendAddressPoolIDs() {
+		backendAddressPools = append(backendAddressPools, &armnetwork.BackendAddressPool{ID: ptr.To(id)})
 	}
-	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
-		backendAddressPools = append(backendAddressPools,
-			&armnetwork.BackendAddressPool{
-				ID: ptr.To(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
-			})
+	if id := s.inboundNATRuleID(); id != "" {
+		primaryIPConfig.LoadBalancerInboundNatRules = []*armnetwork.InboundNatRule{
+			{
+				ID: ptr.To(id),
+			},
+		}
 	}
 	primaryIPConfig.LoadBalancerBackendAddressPools = backendAddressPools
 
@@ -157,36 +160,7 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 
 	// Build additional IPConfigs if more than 1 is specified
 	for i := 1; i < len(s.IPConfigs); i++ {
-		c := s.IPConfigs[i]
-		newIPConfigPropertiesFormat := &armnetwork.InterfaceIPConfigurationPropertiesFormat{}
-		newIPConfigPropertiesFormat.Subnet = subnet
-		config := &armnetwork.InterfaceIPConfiguration{
-			Name:       ptr.To(s.Name + "-" + strconv.Itoa(i)),
-			Properties: newIPConfigPropertiesFormat,
-		}
-		if c.PrivateIP != nil && *c.PrivateIP != "" {
-			config.Properties.PrivateIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
-			config.Properties.PrivateIPAddress = c.PrivateIP
-		} else {
-			config.Properties.PrivateIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodDynamic)
-		}
-
-		if c.PublicIPAddress != nil && *c.PublicIPAddress != "" {
-			config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
-				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-					PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
-					IPAddress:                c.PublicIPAddress,
-				},
-			}
-		} else if c.PublicIPAddress != nil {
-			config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
-				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
-					PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
-				},
-			}
-		}
-		config.Properties.Primary = ptr.To(false)
-		ipConfigurations = append(ipConfigurations, config)
+		ipConfigurations = append(ipConfigurations, s.secondaryIPConfig(i, subnet))
 	}
 	if s.IPv6Enabled {
 		ipv6Config := &armnetwork.InterfaceIPConfiguration{
@@ -218,3 +192,236 @@ func (s *NICSpec) Parameters(ctx context.Context, existing interface{}) (paramet
 		})),
 	}, nil
 }
+
+// secondaryIPConfig returns the i-th secondary IP configuration of the network interface.
+func (s *NICSpec) secondaryIPConfig(i int, subnet *armnetwork.Subnet) *armnetwork.InterfaceIPConfiguration {
+	c := s.IPConfigs[i]
+	newIPConfigPropertiesFormat := &armnetwork.InterfaceIPConfigurationPropertiesFormat{}
+	newIPConfigPropertiesFormat.Subnet = subnet
+	config := &armnetwork.InterfaceIPConfiguration{
+		Name:       ptr.To(s.secondaryIPConfigName(i)),
+		Properties: newIPConfigPropertiesFormat,
+	}
+	if c.PrivateIP != nil && *c.PrivateIP != "" {
+		config.Properties.PrivateIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodStatic)
+		config.Properties.PrivateIPAddress = c.PrivateIP
+	} else {
+		config.Properties.PrivateIPAllocationMethod = ptr.To(armnetwork.IPAllocationMethodDynamic)
+	}
+
+	if c.PublicIPAddress != nil && *c.PublicIPAddress != "" {
+		config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodStatic),
+				IPAddress:                c.PublicIPAddress,
+			},
+		}
+	} else if c.PublicIPAddress != nil {
+		config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				PublicIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+			},
+		}
+	}
+	config.Properties.Primary = ptr.To(false)
+	return config
+}
+
+// secondaryIPConfigName returns the name of the i-th secondary IP configuration of the network interface.
+func (s *NICSpec) secondaryIPConfigName(i int) string {
+	return s.Name + "-" + strconv.Itoa(i)
+}
+
+// secondaryIPConfigIndex returns the index of a secondary IP configuration of the network interface from its name.
+func (s *NICSpec) secondaryIPConfigIndex(name string) (int, bool) {
+	suffix, ok := strings.CutPrefix(name, s.Name+"-")
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(suffix)
+	if err != nil || i < 1 {
+		return 0, false
+	}
+	return i, true
+}
+
+// existingParameters adds or removes the secondary IP configurations of an existing network interface to match the
+// IP configurations of the spec. It returns nil if the network interface is up to date.
+func (s *NICSpec) existingParameters(existing armnetwork.Interface) interface{} {
+	// Orphaned network interfaces are only deleted, so their specs don't describe any IP configuration.
+	if len(s.IPConfigs) == 0 || existing.Properties == nil {
+		return nil
+	}
+
+	updated := s.updateSecondaryIPConfigs(existing.Properties)
+	if s.UpdateLBReferences && s.updateLBReferences(existing.Properties) {
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	return existing
+}
+
+// updateSecondaryIPConfigs adds the missing secondary IP configurations of the spec to an existing network interface,
+// and removes the ones beyond the spec.
+func (s *NICSpec) updateSecondaryIPConfigs(existing *armnetwork.InterfacePropertiesFormat) bool {
+	secondaryIPConfigs := make(map[int]*armnetwork.InterfaceIPConfiguration)
+	var otherIPConfigs []*armnetwork.InterfaceIPConfiguration
+	for _, ipConfig := range existing.IPConfigurations {
+		if ipConfig == nil {
+			continue
+		}
+		if i, ok := s.secondaryIPConfigIndex(ptr.Deref(ipConfig.Name, "")); ok {
+			secondaryIPConfigs[i] = ipConfig
+			continue
+		}
+		otherIPConfigs = append(otherIPConfigs, ipConfig)
+	}
+
+	wanted := len(s.IPConfigs) - 1
+	upToDate := len(secondaryIPConfigs) == wanted
+	for i := 1; i <= wanted && upToDate; i++ {
+		_, upToDate = secondaryIPConfigs[i]
+	}
+	if upToDate || len(otherIPConfigs) == 0 {
+		return false
+	}
+
+	// Keep the primary IP configuration first, followed by the secondary ones and any other, e.g. IPv6, ones.
+	subnet := &armnetwork.Subnet{
+		ID: ptr.To(azure.SubnetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName, s.SubnetName)),
+	}
+	ipConfigurations := []*armnetwork.InterfaceIPConfiguration{otherIPConfigs[0]}
+	for i := 1; i <= wanted; i++ {
+		if ipConfig, ok := secondaryIPConfigs[i]; ok {
+			ipConfigurations = append(ipConfigurations, ipConfig)
+		} else {
+			ipConfigurations = append(ipConfigurations, s.secondaryIPConfig(i, subnet))
+		}
+	}
+	ipConfigurations = append(ipConfigurations, otherIPConfigs[1:]...)
+
+	existing.IPConfigurations = ipConfigurations
+	return true
+}
+
+// updateLBReferences adds the primary IP configuration of an existing network interface to the backend pools and
+// inbound NAT rule of the spec, and removes it from the ones of DetachedLBName. The references to other load balancers,
+// e.g. the ones of the cloud provider, are kept.
+func (s *NICSpec) updateLBReferences(existing *armnetwork.InterfacePropertiesFormat) bool {
+	primaryIndex := -1
+	for i, ipConfig := range existing.IPConfigurations {
+		if ipConfig != nil && ipConfig.Properties != nil && ptr.Deref(ipConfig.Properties.Primary, false) {
+			primaryIndex = i
+			break
+		}
+	}
+	if primaryIndex < 0 {
+		return false
+	}
+	primaryIPConfig := *existing.IPConfigurations[primaryIndex]
+	primary := *primaryIPConfig.Properties
+
+	var updated bool
+	detachedLB := "/loadbalancers/" + strings.ToLower(s.DetachedLBName) + "/"
+	isDetached := func(id *string) bool {
+		return s.DetachedLBName != "" && strings.Contains(strings.ToLower(ptr.Deref(id, "")), detachedLB)
+	}
+
+	pools := make([]*armnetwork.BackendAddressPool, 0, len(primary.LoadBalancerBackendAddressPools))
+	poolIDs := make(map[string]bool)
+	for _, pool := range primary.LoadBalancerBackendAddressPools {
+		if pool == nil {
+			continue
+		}
+		if isDetached(pool.ID) {
+			updated = true
+			continue
+		}
+		pools = append(pools, pool)
+		poolIDs[strings.ToLower(ptr.Deref(pool.ID, ""))] = true
+	}
+	for _, id := range s.backendAddressPoolIDs() {
+		if !poolIDs[strings.ToLower(id)] {
+			pools = append(pools, &armnetwork.BackendAddressPool{ID: ptr.To(id)})
+			updated = true
+		}
+	}
+
+	natRules := make([]*armnetwork.InboundNatRule, 0, len(primary.LoadBalancerInboundNatRules))
+	natRuleID := s.inboundNATRuleID()
+	for _, natRule := range primary.LoadBalancerInboundNatRules {
+		if natRule == nil {
+			continue
+		}
+		if isDetached(natRule.ID) {
+			updated = true
+			continue
+		}
+		if strings.EqualFold(ptr.Deref(natRule.ID, ""), natRuleID) {
+			natRuleID = ""
+		}
+		natRules = append(natRules, natRule)
+	}
+	if natRuleID != "" {
+		natRules = append(natRules, &armnetwork.InboundNatRule{ID: ptr.To(natRuleID)})
+		updated = true
+	}
+
+	if !updated {
+		return false
+	}
+	primary.LoadBalancerBackendAddressPools = pools
+	primary.LoadBalancerInboundNatRules = natRules
+	primaryIPConfig.Properties = &primary
+	ipConfigurations := make([]*armnetwork.InterfaceIPConfiguration, len(existing.IPConfigurations))
+	copy(ipConfigurations, existing.IPConfigurations)
+	ipConfigurations[primaryIndex] = &primaryIPConfig
+	existing.IPConfigurations = ipConfigurations
+	return true
+}
+
+// backendAddressPoolIDs returns the IDs of the load balancer backend pools of the primary IP configuration.
+func (s *NICSpec) backendAddressPoolIDs() []string {
+	var ids []string
+	if s.PublicLBName != "" && s.PublicLBAddressPoolName != "" {
+		ids = append(ids, azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName))
+	}
+	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
+		ids = append(ids, azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName))
+	}
+	return ids
+}
+
+// inboundNATRuleID returns the ID of the inbound NAT rule of the primary IP configuration, or an empty string if it
+// has none.
+func (s *NICSpec) inboundNATRuleID() string {
+	if s.PublicLBName == "" || s.PublicLBNATRuleName == "" {
+		return ""
+	}
+	return azure.NATRuleID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBNATRuleName)
+}
+
+// validateSubnetSize checks that the subnet of the network interface has enough addresses for its IP configurations.
+// Azure reserves five addresses of every subnet.
+func (s *NICSpec) validateSubnetSize() error {
+	var usable int64
+	var hasIPv4 bool
+	for _, cidr := range s.SubnetCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		hasIPv4 = true
+		ones, bits := ipNet.Mask.Size()
+		if size := int64(1)<<(bits-ones) - 5; size > 0 {
+			usable += size
+		}
+	}
+	if hasIPv4 && int64(len(s.IPConfigs)) > usable {
+		return azure.WithTerminalError(errors.Errorf("network interface %s has %d private IP configurations but subnet %s only has %d usable addresses",
+			s.Name, len(s.IPConfigs), s.SubnetName, usable))
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package networkinterfaces
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
@@ -249,8 +250,119 @@ var (
 		IPConfigs:             []IPConfig{{}, {}},
 		ClusterName:           "my-cluster",
 	}
+	fakeSmallSubnetNICSpec = NICSpec{
+		Name:              "my-net-interface",
+		ResourceGroup:     "my-rg",
+		Location:          "fake-location",
+		SubscriptionID:    "123",
+		MachineName:       "azure-test1",
+		SubnetName:        "my-subnet",
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-rg",
+		SKU:               &fakeSku,
+		IPConfigs:         []IPConfig{{}, {}, {}, {}},
+		SubnetCIDRs:       []string{"10.0.0.0/29"},
+		ClusterName:       "my-cluster",
+	}
+	fakeSubnet          = &armnetwork.Subnet{ID: ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")}
+	fakePrimaryIPConfig = &armnetwork.InterfaceIPConfiguration{
+		Name: ptr.To("pipConfig"),
+		Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+			Primary:                   ptr.To(true),
+			PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+			PrivateIPAddress:          ptr.To("10.0.0.4"),
+			Subnet:                    fakeSubnet,
+		},
+	}
+	fakeSecondaryIPConfig = &armnetwork.InterfaceIPConfiguration{
+		Name: ptr.To("my-net-interface-1"),
+		Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+			Primary:                   ptr.To(false),
+			PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+			PrivateIPAddress:          ptr.To("10.0.0.5"),
+			Subnet:                    fakeSubnet,
+		},
+	}
 )
 
+var (
+	fakePublicLBPoolID     = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-public-lb-backendPool"
+	fakePublicLBNATRuleID  = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/inboundNatRules/azure-test1"
+	fakeInternalLBPoolID   = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool"
+	fakeKubernetesLBPoolID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/kubernetes-internal/backendAddressPools/kubernetes"
+
+	// fakeMigratingNICSpec is the spec of the network interface of a control plane machine while the API server load
+	// balancer is migrated from Public to Internal, before the control plane endpoint is switched.
+	fakeMigratingNICSpec = NICSpec{
+		Name:                      "my-net-interface",
+		ResourceGroup:             "my-rg",
+		Location:                  "fake-location",
+		SubscriptionID:            "123",
+		MachineName:               "azure-test1",
+		SubnetName:                "my-subnet",
+		VNetName:                  "my-vnet",
+		VNetResourceGroup:         "my-rg",
+		PublicLBName:              "my-public-lb",
+		PublicLBAddressPoolName:   "my-public-lb-backendPool",
+		PublicLBNATRuleName:       "azure-test1",
+		InternalLBName:            "my-internal-lb",
+		InternalLBAddressPoolName: "my-internal-lb-backendPool",
+		UpdateLBReferences:        true,
+		SKU:                       &fakeSku,
+		IPConfigs:                 []IPConfig{{}},
+		ClusterName:               "my-cluster",
+	}
+	// fakeMigratedNICSpec is the spec of the same network interface once the public load balancer is being deleted.
+	fakeMigratedNICSpec = NICSpec{
+		Name:                      "my-net-interface",
+		ResourceGroup:             "my-rg",
+		Location:                  "fake-location",
+		SubscriptionID:            "123",
+		MachineName:               "azure-test1",
+		SubnetName:                "my-subnet",
+		VNetName:                  "my-vnet",
+		VNetResourceGroup:         "my-rg",
+		InternalLBName:            "my-internal-lb",
+		InternalLBAddressPoolName: "my-internal-lb-backendPool",
+		UpdateLBReferences:        true,
+		DetachedLBName:            "my-public-lb",
+		SKU:                       &fakeSku,
+		IPConfigs:                 []IPConfig{{}},
+		ClusterName:               "my-cluster",
+	}
+)
+
+// fakeLBIPConfig returns a primary IP configuration in the backend pools and inbound NAT rules with the given IDs.
+func fakeLBIPConfig(poolIDs []string, natRuleIDs []string) *armnetwork.InterfaceIPConfiguration {
+	ipConfig := &armnetwork.InterfaceIPConfiguration{
+		Name: ptr.To("pipConfig"),
+		Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+			Primary:                         ptr.To(true),
+			PrivateIPAllocationMethod:       ptr.To(armnetwork.IPAllocationMethodDynamic),
+			PrivateIPAddress:                ptr.To("10.0.0.4"),
+			Subnet:                          fakeSubnet,
+			LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
+			LoadBalancerInboundNatRules:     []*armnetwork.InboundNatRule{},
+		},
+	}
+	for _, id := range poolIDs {
+		ipConfig.Properties.LoadBalancerBackendAddressPools = append(ipConfig.Properties.LoadBalancerBackendAddressPools, &armnetwork.BackendAddressPool{ID: ptr.To(id)})
+	}
+	for _, id := range natRuleIDs {
+		ipConfig.Properties.LoadBalancerInboundNatRules = append(ipConfig.Properties.LoadBalancerInboundNatRules, &armnetwork.InboundNatRule{ID: ptr.To(id)})
+	}
+	return ipConfig
+}
+
+func fakeExistingNIC(ipConfigs ...*armnetwork.InterfaceIPConfiguration) armnetwork.Interface {
+	return armnetwork.Interface{
+		Name: ptr.To("my-net-interface"),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: ipConfigs,
+		},
+	}
+}
+
 func TestParameters(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -668,6 +780,109 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "network interface with the expected IP configurations is up to date",
+			spec:     &fakeTwoIPconfigNICSpec,
+			existing: fakeExistingNIC(fakePrimaryIPConfig, fakeSecondaryIPConfig),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "add a secondary IP configuration to an existing network interface",
+			spec:     &fakeTwoIPconfigNICSpec,
+			existing: fakeExistingNIC(fakePrimaryIPConfig),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Properties.IPConfigurations).To(Equal([]*armnetwork.InterfaceIPConfiguration{
+					fakePrimaryIPConfig,
+					{
+						Name: ptr.To("my-net-interface-1"),
+						Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							Primary:                   ptr.To(false),
+							PrivateIPAllocationMethod: ptr.To(armnetwork.IPAllocationMethodDynamic),
+							Subnet:                    fakeSubnet,
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "remove a secondary IP configuration from an existing network interface",
+			spec: &fakeTwoIPconfigNICSpec,
+			existing: fakeExistingNIC(fakePrimaryIPConfig, fakeSecondaryIPConfig, &armnetwork.InterfaceIPConfiguration{
+				Name: ptr.To("my-net-interface-2"),
+				Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+					Primary:          ptr.To(false),
+					PrivateIPAddress: ptr.To("10.0.0.6"),
+					Subnet:           fakeSubnet,
+				},
+			}),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Properties.IPConfigurations).To(Equal([]*armnetwork.InterfaceIPConfiguration{
+					fakePrimaryIPConfig,
+					fakeSecondaryIPConfig,
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "remove all secondary IP configurations from an existing network interface",
+			spec:     &fakeOneIPconfigNICSpec,
+			existing: fakeExistingNIC(fakePrimaryIPConfig, fakeSecondaryIPConfig),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Properties.IPConfigurations).To(Equal([]*armnetwork.InterfaceIPConfiguration{
+					fakePrimaryIPConfig,
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "add the primary IP configuration of an existing network interface to the internal API server load balancer",
+			spec:     &fakeMigratingNICSpec,
+			existing: fakeExistingNIC(fakeLBIPConfig([]string{fakePublicLBPoolID, fakeKubernetesLBPoolID}, []string{fakePublicLBNATRuleID})),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Properties.IPConfigurations).To(Equal([]*armnetwork.InterfaceIPConfiguration{
+					fakeLBIPConfig([]string{fakePublicLBPoolID, fakeKubernetesLBPoolID, fakeInternalLBPoolID}, []string{fakePublicLBNATRuleID}),
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "network interface in the expected load balancers is up to date",
+			spec:     &fakeMigratingNICSpec,
+			existing: fakeExistingNIC(fakeLBIPConfig([]string{fakeInternalLBPoolID, strings.ToUpper(fakePublicLBPoolID)}, []string{fakePublicLBNATRuleID})),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "remove the primary IP configuration of an existing network interface from the public API server load balancer",
+			spec:     &fakeMigratedNICSpec,
+			existing: fakeExistingNIC(fakeLBIPConfig([]string{fakePublicLBPoolID, fakeInternalLBPoolID, fakeKubernetesLBPoolID}, []string{fakePublicLBNATRuleID})),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Properties.IPConfigurations).To(Equal([]*armnetwork.InterfaceIPConfiguration{
+					fakeLBIPConfig([]string{fakeInternalLBPoolID, fakeKubernetesLBPoolID}, nil),
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "error when the subnet is too small for the IP configurations",
+			spec:     &fakeSmallSubnetNICSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: network interface my-net-interface has 4 private IP configurations but subnet my-subnet only has 3 usable addresses. Object will not be requeued",
+		},
 	}
 	format.MaxLength = 10000
 	for _, tc := range testcases {
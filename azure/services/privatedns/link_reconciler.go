@@ -18,6 +18,7 @@ package privatedns
 
 import (
 	"context"
+	"sort"
 
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -102,3 +103,50 @@ func (s *Service) deleteLinks(ctx context.Context, links []azure.ResourceSpecGet
 
 	return managed, resErr
 }
+
+// deleteStaleLinks deletes the virtual network links that were previously reconciled but are no longer desired,
+// e.g. because a virtual network was removed from the additional vnet links. The reconciled links are tracked in an
+// annotation so only links created by CAPZ are considered, and deleteLinks skips any link not tagged as owned.
+func (s *Service) deleteStaleLinks(ctx context.Context, zoneSpec azure.ResourceSpecGetter, links []azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.deleteStaleLinks")
+	defer done()
+
+	lastAppliedLinks, err := s.Scope.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+
+	newAnnotation := make(map[string]interface{}, len(links))
+	for _, linkSpec := range links {
+		var vnetID string
+		if link, ok := linkSpec.(LinkSpec); ok {
+			vnetID = link.VNetID()
+		}
+		newAnnotation[linkSpec.ResourceName()] = vnetID
+	}
+
+	staleLinkNames := make([]string, 0, len(lastAppliedLinks))
+	for name := range lastAppliedLinks {
+		if _, ok := newAnnotation[name]; !ok {
+			staleLinkNames = append(staleLinkNames, name)
+		}
+	}
+	sort.Strings(staleLinkNames)
+
+	staleLinks := make([]azure.ResourceSpecGetter, 0, len(staleLinkNames))
+	for _, name := range staleLinkNames {
+		log.V(2).Info("deleting vnet link that is no longer desired", "vnet link", name, "private dns zone", zoneSpec.ResourceName())
+		staleLinks = append(staleLinks, LinkSpec{
+			Name:          name,
+			ZoneName:      zoneSpec.ResourceName(),
+			ResourceGroup: zoneSpec.ResourceGroupName(),
+			ClusterName:   s.Scope.ClusterName(),
+		})
+	}
+
+	if _, err := s.deleteLinks(ctx, staleLinks); err != nil {
+		return errors.Wrap(err, "failed to delete stale vnet links")
+	}
+
+	return s.Scope.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, newAnnotation)
+}
@@ -54,6 +54,11 @@ func (s LinkSpec) ResourceGroupName() string {
 	return s.ResourceGroup
 }
 
+// VNetID returns the Azure resource ID of the linked virtual network.
+func (s LinkSpec) VNetID() string {
+	return azure.VNetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName)
+}
+
 // Parameters returns the parameters for the virtual network link.
 func (s LinkSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
 	if existing != nil {
@@ -67,7 +72,7 @@ func (s LinkSpec) Parameters(ctx context.Context, existing interface{}) (params
 	return armprivatedns.VirtualNetworkLink{
 		Properties: &armprivatedns.VirtualNetworkLinkProperties{
 			VirtualNetwork: &armprivatedns.SubResource{
-				ID: ptr.To(azure.VNetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName)),
+				ID: ptr.To(s.VNetID()),
 			},
 			RegistrationEnabled: ptr.To(false),
 		},
@@ -36,6 +36,8 @@ type Scope interface {
 	azure.ClusterDescriber
 	azure.Authorizer
 	azure.AsyncStatusUpdater
+	AnnotationJSON(string) (map[string]interface{}, error)
+	UpdateAnnotationJSON(string, map[string]interface{}) error
 	PrivateDNSSpec() (zoneSpec azure.ResourceSpecGetter, linksSpec, recordsSpec []azure.ResourceSpecGetter)
 }
 
@@ -104,6 +106,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return err
 	}
 
+	// Stale links are deleted first, as a virtual network can't be linked twice to the same zone under different names.
+	if err := s.deleteStaleLinks(ctx, zoneSpec, links); err != nil {
+		return err
+	}
+
 	managed, err = s.reconcileLinks(ctx, links)
 	if managed {
 		s.Scope.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, err)
@@ -130,6 +130,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, nil)
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, nil)
@@ -182,6 +184,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, nil)
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSRecordReadyCondition, serviceName, nil)
@@ -203,6 +207,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", fakeLink2.ResourceGroupName(), fakeLink2.OwnerResourceName(), fakeLink2.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
 
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, errFake)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, nil)
@@ -226,6 +232,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", fakeLink2.ResourceGroupName(), fakeLink2.OwnerResourceName(), fakeLink2.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
 
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, errFake)
@@ -249,6 +257,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", fakeLink2.ResourceGroupName(), fakeLink2.OwnerResourceName(), fakeLink2.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
 
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, notDoneError)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, errFake)
@@ -274,6 +284,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				s.ClusterName().Return(clusterName)
 
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSRecordReadyCondition, serviceName, nil)
@@ -297,12 +309,80 @@ func TestReconcilePrivateDNS(t *testing.T) {
 
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, nil)
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSRecordReadyCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "links that are no longer desired are deleted only if they are managed",
+			expectedError: "",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, z, l, r *mock_async.MockReconcilerMockRecorder, tg *mock_async.MockTagsGetterMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PrivateDNSSpec().Return(fakeZone, []azure.ResourceSpecGetter{fakeLink1}, []azure.ResourceSpecGetter{fakeRecord1}).Times(2)
+
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.PrivateDNSZoneID("123", fakeZone.ResourceGroupName(), fakeZone.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
+
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", fakeLink1.ResourceGroupName(), fakeLink1.OwnerResourceName(), fakeLink1.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
+
+				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
+				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, nil)
+
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{
+					linkName1:        fakeLink1.VNetID(),
+					linkName2:        fakeLink2.VNetID(),
+					"unmanaged-link": "some-vnet-id",
+				}, nil)
+				s.ClusterName().Return(clusterName).Times(2)
+				staleLink := LinkSpec{Name: linkName2, ZoneName: zoneName, ResourceGroup: resourceGroup, ClusterName: clusterName}
+				unmanagedLink := LinkSpec{Name: "unmanaged-link", ZoneName: zoneName, ResourceGroup: resourceGroup, ClusterName: clusterName}
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", resourceGroup, zoneName, linkName2)).Return(managedTags, nil)
+				s.ClusterName().Return(clusterName)
+				l.DeleteResource(gomockinternal.AContext(), staleLink, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", resourceGroup, zoneName, "unmanaged-link")).Return(armresources.TagsResource{}, nil)
+				s.ClusterName().Return(clusterName)
+				l.DeleteResource(gomockinternal.AContext(), unmanagedLink, serviceName).Times(0)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID()}).Return(nil)
+
+				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.PrivateDNSRecordReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "stale link deletion fails",
+			expectedError: "failed to delete stale vnet links: this is an error",
+			expect: func(s *mock_privatedns.MockScopeMockRecorder, z, l, r *mock_async.MockReconcilerMockRecorder, tg *mock_async.MockTagsGetterMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PrivateDNSSpec().Return(fakeZone, []azure.ResourceSpecGetter{fakeLink1}, []azure.ResourceSpecGetter{fakeRecord1}).Times(2)
+
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.PrivateDNSZoneID("123", fakeZone.ResourceGroupName(), fakeZone.ResourceName())).Return(armresources.TagsResource{}, notFoundError)
+
+				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
+
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{
+					linkName1: fakeLink1.VNetID(),
+					linkName2: fakeLink2.VNetID(),
+				}, nil)
+				s.ClusterName().Return(clusterName)
+				staleLink := LinkSpec{Name: linkName2, ZoneName: zoneName, ResourceGroup: resourceGroup, ClusterName: clusterName}
+				s.SubscriptionID().Return("123")
+				tg.GetAtScope(gomockinternal.AContext(), azure.VirtualNetworkLinkID("123", resourceGroup, zoneName, linkName2)).Return(managedTags, nil)
+				s.ClusterName().Return(clusterName)
+				l.DeleteResource(gomockinternal.AContext(), staleLink, serviceName).Return(errFake)
+
+				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "record creation fails",
 			expectedError: "this is an error",
@@ -322,6 +402,8 @@ func TestReconcilePrivateDNS(t *testing.T) {
 				z.CreateOrUpdateResource(gomockinternal.AContext(), fakeZone, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink1, serviceName).Return(nil, nil)
 				l.CreateOrUpdateResource(gomockinternal.AContext(), fakeLink2, serviceName).Return(nil, nil)
+				s.AnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.UpdateAnnotationJSON(azure.PrivateDNSLinksLastAppliedAnnotation, map[string]interface{}{linkName1: fakeLink1.VNetID(), linkName2: fakeLink2.VNetID()}).Return(nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), fakeRecord1, serviceName).Return(nil, errFake)
 				s.UpdatePutStatus(infrav1.PrivateDNSZoneReadyCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.PrivateDNSLinkReadyCondition, serviceName, nil)
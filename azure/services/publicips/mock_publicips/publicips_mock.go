@@ -281,6 +281,18 @@ func (mr *MockPublicIPScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockPublicIPScope)(nil).FailureDomains))
 }
 
+// ForgetManagedResource mocks base method.
+func (m *MockPublicIPScope) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockPublicIPScopeMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockPublicIPScope)(nil).ForgetManagedResource), id)
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockPublicIPScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -309,6 +321,34 @@ func (mr *MockPublicIPScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockPublicIPScope)(nil).HashKey))
 }
 
+// IsManagedResource mocks base method.
+func (m *MockPublicIPScope) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockPublicIPScopeMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockPublicIPScope)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockPublicIPScope) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockPublicIPScopeMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockPublicIPScope)(nil).IsOwnershipRecorded))
+}
+
 // Location mocks base method.
 func (m *MockPublicIPScope) Location() string {
 	m.ctrl.T.Helper()
@@ -351,6 +391,18 @@ func (mr *MockPublicIPScopeMockRecorder) PublicIPSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicIPSpecs", reflect.TypeOf((*MockPublicIPScope)(nil).PublicIPSpecs))
 }
 
+// RecordManagedResource mocks base method.
+func (m *MockPublicIPScope) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockPublicIPScopeMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockPublicIPScope)(nil).RecordManagedResource), id)
+}
+
 // ResourceGroup mocks base method.
 func (m *MockPublicIPScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -365,6 +417,18 @@ func (mr *MockPublicIPScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockPublicIPScope)(nil).ResourceGroup))
 }
 
+// SetConditionFalse mocks base method.
+func (m *MockPublicIPScope) SetConditionFalse(arg0 v1beta10.ConditionType, arg1 string, arg2 v1beta10.ConditionSeverity, arg3 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConditionFalse", arg0, arg1, arg2, arg3)
+}
+
+// SetConditionFalse indicates an expected call of SetConditionFalse.
+func (mr *MockPublicIPScopeMockRecorder) SetConditionFalse(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConditionFalse", reflect.TypeOf((*MockPublicIPScope)(nil).SetConditionFalse), arg0, arg1, arg2, arg3)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockPublicIPScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -377,6 +441,18 @@ func (mr *MockPublicIPScopeMockRecorder) SetLongRunningOperationState(arg0 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockPublicIPScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetOutboundIPs mocks base method.
+func (m *MockPublicIPScope) SetOutboundIPs(arg0 []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetOutboundIPs", arg0)
+}
+
+// SetOutboundIPs indicates an expected call of SetOutboundIPs.
+func (mr *MockPublicIPScopeMockRecorder) SetOutboundIPs(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboundIPs", reflect.TypeOf((*MockPublicIPScope)(nil).SetOutboundIPs), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockPublicIPScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -18,25 +18,38 @@ package publicips
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
-const serviceName = "publicips"
+const (
+	serviceName = "publicips"
+
+	// dnsRecordInUseErrorCode is the error code returned by Azure when the DNS label of a public IP is already used.
+	dnsRecordInUseErrorCode = "DnsRecordInUse"
+)
 
 // PublicIPScope defines the scope interface for a public IP service.
 type PublicIPScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
 	azure.ClusterDescriber
+	azure.ResourceOwnershipRecorder
 	PublicIPSpecs() []azure.ResourceSpecGetter
+	SetOutboundIPs([]string)
+	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
 }
 
 // Service provides operations on Azure resources.
@@ -57,12 +70,15 @@ func New(scope PublicIPScope) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Service{
+	s := &Service{
 		Scope:      scope,
 		Getter:     client,
 		TagsGetter: tagsClient,
-		Reconciler: async.New[armnetwork.PublicIPAddressesClientCreateOrUpdateResponse, armnetwork.PublicIPAddressesClientDeleteResponse](scope, client, client),
-	}, nil
+	}
+	s.Reconciler = async.New[armnetwork.PublicIPAddressesClientCreateOrUpdateResponse, armnetwork.PublicIPAddressesClientDeleteResponse](scope, client, client).
+		WithDeleteConfirmation(client).
+		WithConflictAdoption(s.isAdoptable)
+	return s, nil
 }
 
 // Name returns the service name.
@@ -80,6 +96,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	specs := s.Scope.PublicIPSpecs()
 	if len(specs) == 0 {
+		s.Scope.SetOutboundIPs(nil)
 		return nil
 	}
 
@@ -87,18 +104,78 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
+	outboundIPs := make(map[string]bool)
+	recordOwnership := s.Scope.IsOwnershipRecorded()
 	for _, publicIPSpec := range specs {
-		if _, err := s.CreateOrUpdateResource(ctx, publicIPSpec, serviceName); err != nil {
+		if recordOwnership {
+			if err := async.RecordManagedResourceIfNotFoundOrOwned(ctx, s.Scope, s.Getter, publicIPSpec, s.publicIPID(publicIPSpec), s.isOwnedByCluster); err != nil {
+				result = err
+				continue
+			}
+		}
+		publicIP, err := s.CreateOrUpdateResource(ctx, publicIPSpec, serviceName)
+		if err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+			continue
+		}
+		if spec, ok := publicIPSpec.(*PublicIPSpec); ok && spec.Outbound {
+			if address := publicIPAddress(publicIP); address != "" {
+				outboundIPs[address] = true
+			}
+		}
+	}
+
+	// The outbound IPs are only reported once all the public IPs are reconciled, so that the addresses of the public
+	// IPs which failed to reconcile aren't dropped from the status.
+	if result == nil {
+		var addresses []string
+		for address := range outboundIPs {
+			addresses = append(addresses, address)
 		}
+		sort.Strings(addresses)
+		s.Scope.SetOutboundIPs(addresses)
 	}
 
+	if isDNSRecordInUseError(result) {
+		s.Scope.SetConditionFalse(infrav1.PublicIPsReadyCondition, infrav1.DNSLabelInUseReason, clusterv1.ConditionSeverityError,
+			fmt.Sprintf("the DNS label of a public IP is already used by another public IP in the location, specify a unique dnsName for the public IP. err: %s", result.Error()))
+		return result
+	}
 	s.Scope.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, result)
 	return result
 }
 
+// isOwnedByCluster returns true if the existing public IP is tagged as owned by the cluster, i.e. it was created by
+// CAPZ for the cluster even though its ownership wasn't recorded.
+func (s *Service) isOwnedByCluster(existing interface{}) bool {
+	ip, ok := existing.(armnetwork.PublicIPAddress)
+	return ok && converters.MapToTags(ip.Tags).HasOwned(s.Scope.ClusterName())
+}
+
+// isAdoptable returns true if the existing public IP conflicting with the creation of a public IP can be adopted,
+// i.e. it is owned by the cluster.
+func (s *Service) isAdoptable(_ azure.ResourceSpecGetter, existing interface{}) bool {
+	return s.isOwnedByCluster(existing)
+}
+
+// publicIPAddress returns the address allocated to a public IP, or an empty string if it has none yet.
+func publicIPAddress(publicIP interface{}) string {
+	ip, ok := publicIP.(armnetwork.PublicIPAddress)
+	if !ok || ip.Properties == nil {
+		return ""
+	}
+	return ptr.Deref(ip.Properties.IPAddress, "")
+}
+
+// isDNSRecordInUseError returns true if the error is returned by Azure because the DNS label of a public IP is
+// already used by another public IP.
+func isDNSRecordInUseError(err error) bool {
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.ErrorCode == dnsRecordInUseErrorCode
+}
+
 // Delete deletes the public IP with the provided scope.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Delete")
@@ -135,6 +212,8 @@ func (s *Service) Delete(ctx context.Context) error {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+		} else {
+			s.Scope.ForgetManagedResource(s.publicIPID(publicIPSpec))
 		}
 
 		log.V(2).Info("deleted public IP", "public ip", publicIPSpec.ResourceName())
@@ -147,10 +226,11 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
-// isIPManaged returns true if the IP has an owned tag with the cluster name as value,
-// meaning that the IP's lifecycle is managed.
+// isIPManaged returns true if the IP was recorded as created by CAPZ or, for clusters that don't record the
+// resources created by CAPZ, if the IP has an owned tag with the cluster name as value, meaning that the IP's
+// lifecycle is managed.
 func (s *Service) isIPManaged(ctx context.Context, spec azure.ResourceSpecGetter) (bool, error) {
-	scope := azure.PublicIPID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+	scope := s.publicIPID(spec)
 	result, err := s.TagsGetter.GetAtScope(ctx, scope)
 	if err != nil {
 		return false, err
@@ -162,7 +242,12 @@ func (s *Service) isIPManaged(ctx context.Context, spec azure.ResourceSpecGetter
 	}
 
 	tags := converters.MapToTags(tagsMap)
-	return tags.HasOwned(s.Scope.ClusterName()), nil
+	return s.Scope.IsManagedResource(scope, tags.HasOwned(s.Scope.ClusterName())), nil
+}
+
+// publicIPID returns the Azure resource ID of the public IP.
+func (s *Service) publicIPID(spec azure.ResourceSpecGetter) string {
+	return azure.PublicIPID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
 }
 
 // IsManaged returns always returns true as public IPs are managed on a one-by-one basis.
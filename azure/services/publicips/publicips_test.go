@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -96,6 +97,20 @@ var (
 			"foo": "bar",
 		},
 	}
+	fakeOutboundLBPublicIPSpec = PublicIPSpec{
+		Name:          "pip-my-cluster-node-outbound",
+		ResourceGroup: "my-rg",
+		ClusterName:   "my-cluster",
+		Location:      "centralIndia",
+		Outbound:      true,
+	}
+	fakeNatGatewayPublicIPSpec = PublicIPSpec{
+		Name:          "pip-my-cluster-node-natgw",
+		ResourceGroup: "my-rg",
+		ClusterName:   "my-cluster",
+		Location:      "centralIndia",
+		Outbound:      true,
+	}
 
 	managedTags = armresources.TagsResource{
 		Properties: &armresources.Tags{
@@ -121,41 +136,89 @@ var (
 			StatusCode: http.StatusInternalServerError,
 		},
 	}
+
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
+	dnsRecordInUseError = &azcore.ResponseError{
+		ErrorCode: "DnsRecordInUse",
+		RawResponse: &http.Response{
+			Body:       io.NopCloser(strings.NewReader("#: DNS record my-cluster.centralindia.cloudapp.azure.com is already used by another public IP: StatusCode=400")),
+			StatusCode: http.StatusBadRequest,
+		},
+	}
 )
 
+func fakePublicIPAddress(address string) armnetwork.PublicIPAddress {
+	return armnetwork.PublicIPAddress{
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			IPAddress: ptr.To(address),
+		},
+	}
+}
+
 func TestReconcilePublicIP(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
 			name:          "noop if no public IPs",
 			expectedError: "",
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{})
+				s.SetOutboundIPs(nil)
 			},
 		},
 		{
 			name:          "successfully create public IPs",
 			expectedError: "",
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2, &fakePublicIPSpec3, &fakePublicIPSpecIpv6})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpecIpv6, serviceName).Return(nil, nil)
+				s.SetOutboundIPs(nil)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "report the addresses of the outbound public IPs",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakeOutboundLBPublicIPSpec, &fakePublicIPSpec1, &fakeNatGatewayPublicIPSpec, &fakeNatGatewayPublicIPSpec})
+				s.IsOwnershipRecorded().Return(false)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOutboundLBPublicIPSpec, serviceName).Return(fakePublicIPAddress("20.0.0.2"), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(fakePublicIPAddress("20.0.0.3"), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNatGatewayPublicIPSpec, serviceName).Return(fakePublicIPAddress("20.0.0.1"), nil).Times(2)
+				s.SetOutboundIPs([]string{"20.0.0.1", "20.0.0.2"})
 				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "don't report the outbound public IPs when a public IP fails to reconcile",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakeOutboundLBPublicIPSpec, &fakeNatGatewayPublicIPSpec})
+				s.IsOwnershipRecorded().Return(false)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeOutboundLBPublicIPSpec, serviceName).Return(fakePublicIPAddress("20.0.0.2"), nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNatGatewayPublicIPSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, internalError)
+			},
+		},
 		{
 			name:          "fail to create a public IP",
 			expectedError: internalError.Error(),
-			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2, &fakePublicIPSpec3, &fakePublicIPSpecIpv6})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(nil, internalError)
@@ -163,6 +226,65 @@ func TestReconcilePublicIP(t *testing.T) {
 				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "fail to create a public IP with a DNS label in use",
+			expectedError: dnsRecordInUseError.Error(),
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2})
+				s.IsOwnershipRecorded().Return(false)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, dnsRecordInUseError)
+				s.SetConditionFalse(infrav1.PublicIPsReadyCondition, infrav1.DNSLabelInUseReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+		{
+			name:          "record public IPs before creating them when ownership is recorded",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2})
+				s.IsOwnershipRecorded().Return(true)
+
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), false).Return(false)
+				g.Get(gomockinternal.AContext(), &fakePublicIPSpec1).Return(nil, notFoundError)
+				s.RecordManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()))
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
+
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()), false).Return(false)
+				g.Get(gomockinternal.AContext(), &fakePublicIPSpec2).Return(fakePublicIPSpec2, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil, nil)
+
+				s.SetOutboundIPs(nil)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "adopt a public IP tagged as owned by the cluster after an interrupted creation",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1})
+				s.IsOwnershipRecorded().Return(true)
+
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), false).Return(false)
+				g.Get(gomockinternal.AContext(), &fakePublicIPSpec1).Return(armnetwork.PublicIPAddress{
+					Name: ptr.To(fakePublicIPSpec1.Name),
+					Tags: map[string]*string{
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": ptr.To("owned"),
+					},
+				}, nil)
+				s.ClusterName().Return("my-cluster")
+				s.RecordManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()))
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil, nil)
+
+				s.SetOutboundIPs(nil)
+				s.UpdatePutStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -175,14 +297,14 @@ func TestReconcilePublicIP(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
-			tagsGetterMock := mock_async.NewMockTagsGetter(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
 			reconcilerMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), tagsGetterMock.EXPECT(), reconcilerMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), reconcilerMock.EXPECT())
 
 			s := &Service{
 				Scope:      scopeMock,
-				TagsGetter: tagsGetterMock,
+				Getter:     getterMock,
 				Reconciler: reconcilerMock,
 			}
 
@@ -221,21 +343,31 @@ func TestDeletePublicIP(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()))
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()))
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName())).Return(unmanagedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName()), false).Return(false)
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpecIpv6, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName()))
 
 				s.UpdateDeleteStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
 			},
@@ -250,18 +382,22 @@ func TestDeletePublicIP(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName())).Return(unmanagedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), false).Return(false)
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName())).Return(unmanagedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()), false).Return(false)
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName())).Return(unmanagedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName()), false).Return(false)
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName())).Return(unmanagedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName()), false).Return(false)
 			},
 		},
 		{
@@ -274,26 +410,59 @@ func TestDeletePublicIP(t *testing.T) {
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()))
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()))
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec3.ResourceGroupName(), fakePublicIPSpec3.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec3, serviceName).Return(internalError)
 
 				s.SubscriptionID().Return("123")
 				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName())).Return(managedTags, nil)
 				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName()), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpecIpv6, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpecIpv6.ResourceGroupName(), fakePublicIPSpecIpv6.ResourceName()))
 
 				s.UpdateDeleteStatus(infrav1.PublicIPsReadyCondition, serviceName, internalError)
 			},
 		},
+		{
+			name:          "do not delete public IPs not created by CAPZ when ownership is recorded",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_async.MockTagsGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.PublicIPSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPSpec1, &fakePublicIPSpec2})
+
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName())).Return(managedTags, nil)
+				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()), true).Return(true)
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPSpec1, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.PublicIPID("123", fakePublicIPSpec1.ResourceGroupName(), fakePublicIPSpec1.ResourceName()))
+
+				s.SubscriptionID().Return("123")
+				m.GetAtScope(gomockinternal.AContext(), azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName())).Return(managedTags, nil)
+				s.ClusterName().Return("my-cluster")
+				s.IsManagedResource(azure.PublicIPID("123", fakePublicIPSpec2.ResourceGroupName(), fakePublicIPSpec2.ResourceName()), true).Return(false)
+
+				s.UpdateDeleteStatus(infrav1.PublicIPsReadyCondition, serviceName, nil)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -39,6 +39,8 @@ type PublicIPSpec struct {
 	FailureDomains   []*string
 	AdditionalTags   infrav1.Tags
 	IPTags           []infrav1.IPTag
+	// Outbound is true for the public IPs the cluster egresses from, whose addresses are reported in its status.
+	Outbound bool
 }
 
 // ResourceName returns the name of the public IP.
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcehealth
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcehealth/armresourcehealth"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AvailabilityChecker looks up the availability status of resources, e.g. of a load balancer, whose availability is
+// derived by Azure from the health probes of its backends.
+type AvailabilityChecker struct {
+	client
+}
+
+// NewAvailabilityChecker creates a new AvailabilityChecker for the subscription of auth.
+func NewAvailabilityChecker(auth azure.Authorizer) (*AvailabilityChecker, error) {
+	cli, err := newClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AvailabilityChecker{client: cli}, nil
+}
+
+// Available returns true if the availability status of the resource with the resource ID resourceURI reports it as
+// available. Otherwise, it returns the summary of the availability status, e.g. the reason a load balancer is degraded
+// or why its availability is unknown.
+func (c *AvailabilityChecker) Available(ctx context.Context, resourceURI string) (bool, string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "resourcehealth.AvailabilityChecker.Available")
+	defer done()
+
+	status, err := c.GetByResource(ctx, resourceURI)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to get availability status of %s", resourceURI)
+	}
+	if status.Properties == nil {
+		return false, "", nil
+	}
+	state := ptr.Deref(status.Properties.AvailabilityState, armresourcehealth.AvailabilityStateValuesUnknown)
+	summary := ptr.Deref(status.Properties.Summary, "")
+	log.V(4).Info("got availability status", "resourceURI", resourceURI, "availabilityState", state, "summary", summary)
+	if state != armresourcehealth.AvailabilityStateValuesAvailable {
+		return false, summary, nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcehealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcehealth/armresourcehealth"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth/mock_resourcehealth"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func TestAvailable(t *testing.T) {
+	const resourceURI = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb"
+	testcases := []struct {
+		name            string
+		status          armresourcehealth.AvailabilityStatus
+		getErr          error
+		expected        bool
+		expectedSummary string
+		expectedError   string
+	}{
+		{
+			name:     "available",
+			status:   availabilityStatus(armresourcehealth.AvailabilityStateValuesAvailable, ""),
+			expected: true,
+		},
+		{
+			name: "degraded",
+			status: armresourcehealth.AvailabilityStatus{
+				Properties: &armresourcehealth.AvailabilityStatusProperties{
+					AvailabilityState: ptr.To(armresourcehealth.AvailabilityStateValuesDegraded),
+					Summary:           ptr.To("Some backends are failing their health probes"),
+				},
+			},
+			expectedSummary: "Some backends are failing their health probes",
+		},
+		{
+			name:   "unknown",
+			status: availabilityStatus(armresourcehealth.AvailabilityStateValuesUnknown, ""),
+		},
+		{
+			name: "no availability state",
+		},
+		{
+			name:          "API error",
+			getErr:        errors.New("some API error"),
+			expectedError: "failed to get availability status of " + resourceURI + ": some API error",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_resourcehealth.NewMockclient(mockCtrl)
+			clientMock.EXPECT().GetByResource(gomockinternal.AContext(), resourceURI).Return(tc.status, tc.getErr)
+
+			checker := &AvailabilityChecker{client: clientMock}
+			available, summary, err := checker.Available(context.TODO(), resourceURI)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(available).To(Equal(tc.expected))
+			g.Expect(summary).To(Equal(tc.expectedSummary))
+		})
+	}
+}
@@ -28,11 +28,13 @@ import (
 // client wraps go-sdk.
 type client interface {
 	GetByResource(context.Context, string) (armresourcehealth.AvailabilityStatus, error)
+	ListEvents(context.Context) ([]*armresourcehealth.Event, error)
 }
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
 	availabilityStatuses *armresourcehealth.AvailabilityStatusesClient
+	events               *armresourcehealth.EventsClient
 }
 
 // newClient creates a new resource health client from an authorizer.
@@ -45,7 +47,10 @@ func newClient(auth azure.Authorizer) (*azureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armresourcehealth client factory")
 	}
-	return &azureClient{factory.NewAvailabilityStatusesClient()}, nil
+	return &azureClient{
+		availabilityStatuses: factory.NewAvailabilityStatusesClient(),
+		events:               factory.NewEventsClient(),
+	}, nil
 }
 
 // GetByResource gets the availability status for the specified resource.
@@ -60,3 +65,21 @@ func (ac *azureClient) GetByResource(ctx context.Context, resourceURI string) (a
 	}
 	return resp.AvailabilityStatus, nil
 }
+
+// ListEvents lists the Service Health events of the subscription.
+func (ac *azureClient) ListEvents(ctx context.Context) ([]*armresourcehealth.Event, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourcehealth.AzureClient.ListEvents")
+	defer done()
+
+	opts := &armresourcehealth.EventsClientListBySubscriptionIDOptions{}
+	var events []*armresourcehealth.Event
+	pager := ac.events.NewListBySubscriptionIDPager(opts)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Value...)
+	}
+	return events, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcehealth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcehealth/armresourcehealth"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// EvictionChecker looks up the availability status of deallocated virtual machines to tell the ones deallocated by
+// Azure, e.g. by a Spot eviction, from the ones deallocated on purpose.
+type EvictionChecker struct {
+	client
+}
+
+// NewEvictionChecker creates a new EvictionChecker for the subscription of auth.
+func NewEvictionChecker(auth azure.Authorizer) (*EvictionChecker, error) {
+	cli, err := newClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &EvictionChecker{client: cli}, nil
+}
+
+// Evicted returns true if the availability status of the virtual machine with the resource ID resourceURI reports it
+// as unavailable for a reason which isn't initiated by the customer. A virtual machine deallocated by a user or a
+// process of the subscription, or whose availability status doesn't report a reason, isn't considered evicted.
+func (c *EvictionChecker) Evicted(ctx context.Context, resourceURI string) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "resourcehealth.EvictionChecker.Evicted")
+	defer done()
+
+	status, err := c.GetByResource(ctx, resourceURI)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get availability status of %s", resourceURI)
+	}
+	if status.Properties == nil ||
+		ptr.Deref(status.Properties.AvailabilityState, "") != armresourcehealth.AvailabilityStateValuesUnavailable {
+		return false, nil
+	}
+	reason := ptr.Deref(status.Properties.ReasonType, "")
+	log.V(4).Info("got availability status of deallocated virtual machine", "resourceURI", resourceURI, "reasonType", reason, "summary", ptr.Deref(status.Properties.Summary, ""))
+	return reason != "" && !isCustomerInitiated(reason), nil
+}
+
+// isCustomerInitiated returns true if reasonType, e.g. "Customer Initiated" or "UserInitiated", reports an action of
+// the customer.
+func isCustomerInitiated(reasonType string) bool {
+	reasonType = strings.ToLower(reasonType)
+	return strings.Contains(reasonType, "customer") || strings.Contains(reasonType, "user")
+}
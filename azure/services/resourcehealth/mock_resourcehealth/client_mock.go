@@ -70,3 +70,18 @@ func (mr *MockclientMockRecorder) GetByResource(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByResource", reflect.TypeOf((*Mockclient)(nil).GetByResource), arg0, arg1)
 }
+
+// ListEvents mocks base method.
+func (m *Mockclient) ListEvents(arg0 context.Context) ([]*armresourcehealth.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", arg0)
+	ret0, _ := ret[0].([]*armresourcehealth.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockclientMockRecorder) ListEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*Mockclient)(nil).ListEvents), arg0)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcehealth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcehealth/armresourcehealth"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// impactedServices are the Azure services, as named by Service Health, which an AzureCluster depends on. Incidents
+// only impacting other services, e.g. Azure Cosmos DB, don't affect the reconciliation of the cluster.
+var impactedServices = []string{
+	"Azure DNS",
+	"Azure Resource Manager",
+	"Load Balancer",
+	"NAT Gateway",
+	"Network Infrastructure",
+	"Virtual Machine Scale Sets",
+	"Virtual Machines",
+	"Virtual Network",
+}
+
+// RegionIncidentChecker looks up the Azure Service Health incidents impacting a region of a subscription.
+type RegionIncidentChecker struct {
+	client
+}
+
+// NewRegionIncidentChecker creates a new RegionIncidentChecker for the subscription of auth.
+func NewRegionIncidentChecker(auth azure.Authorizer) (*RegionIncidentChecker, error) {
+	cli, err := newClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	return &RegionIncidentChecker{client: cli}, nil
+}
+
+// ActiveIncident returns the title of an active service issue impacting one of the services an AzureCluster depends
+// on in location, or an empty string if there is none. An event is only considered active in location if the event as
+// well as its impact on location are active.
+func (c *RegionIncidentChecker) ActiveIncident(ctx context.Context, location string) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "resourcehealth.RegionIncidentChecker.ActiveIncident")
+	defer done()
+
+	events, err := c.ListEvents(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list service health events")
+	}
+	for _, event := range events {
+		if event == nil || event.Properties == nil {
+			continue
+		}
+		props := event.Properties
+		if ptr.Deref(props.EventType, "") != armresourcehealth.EventTypeValuesServiceIssue ||
+			ptr.Deref(props.Status, "") != armresourcehealth.EventStatusValuesActive {
+			continue
+		}
+		if impactsServicesInRegion(props.Impact, location) {
+			title := ptr.Deref(props.Title, ptr.Deref(event.Name, "service issue"))
+			log.V(2).Info("found an active service issue in region", "location", location, "event", ptr.Deref(event.Name, ""), "title", title)
+			return title, nil
+		}
+	}
+	return "", nil
+}
+
+// impactsServicesInRegion returns true if any of impacts on one of impactedServices is still active in location.
+// Service Health reports the display name of the regions, e.g. "East US", which is compared to the location name,
+// e.g. "eastus", ignoring case and spaces.
+func impactsServicesInRegion(impacts []*armresourcehealth.Impact, location string) bool {
+	location = normalizeRegion(location)
+	for _, impact := range impacts {
+		if impact == nil || !isImpactedService(ptr.Deref(impact.ImpactedService, "")) {
+			continue
+		}
+		for _, region := range impact.ImpactedRegions {
+			if region == nil || normalizeRegion(ptr.Deref(region.ImpactedRegion, "")) != location {
+				continue
+			}
+			if ptr.Deref(region.Status, armresourcehealth.EventStatusValuesActive) == armresourcehealth.EventStatusValuesActive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isImpactedService(service string) bool {
+	for _, impacted := range impactedServices {
+		if strings.EqualFold(service, impacted) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeRegion(region string) string {
+	return strings.ToLower(strings.ReplaceAll(region, " ", ""))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcehealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcehealth/armresourcehealth"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth/mock_resourcehealth"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+func serviceIssue(title string, status armresourcehealth.EventStatusValues, regions ...*armresourcehealth.ImpactedServiceRegion) *armresourcehealth.Event {
+	return &armresourcehealth.Event{
+		Name: ptr.To("tracking-id"),
+		Properties: &armresourcehealth.EventProperties{
+			EventType: ptr.To(armresourcehealth.EventTypeValuesServiceIssue),
+			Status:    ptr.To(status),
+			Title:     ptr.To(title),
+			Impact: []*armresourcehealth.Impact{
+				{
+					ImpactedService: ptr.To("Virtual Machines"),
+					ImpactedRegions: regions,
+				},
+			},
+		},
+	}
+}
+
+func TestActiveIncident(t *testing.T) {
+	testcases := []struct {
+		name          string
+		events        []*armresourcehealth.Event
+		listErr       error
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "active service issue in the region",
+			events: []*armresourcehealth.Event{
+				serviceIssue("Networking outage", armresourcehealth.EventStatusValuesActive,
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("West US")},
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("East US"), Status: ptr.To(armresourcehealth.EventStatusValuesActive)},
+				),
+			},
+			expected: "Networking outage",
+		},
+		{
+			name: "resolved service issue in the region",
+			events: []*armresourcehealth.Event{
+				serviceIssue("Networking outage", armresourcehealth.EventStatusValuesResolved,
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("East US"), Status: ptr.To(armresourcehealth.EventStatusValuesResolved)},
+				),
+			},
+		},
+		{
+			name: "active service issue resolved in the region",
+			events: []*armresourcehealth.Event{
+				serviceIssue("Networking outage", armresourcehealth.EventStatusValuesActive,
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("East US"), Status: ptr.To(armresourcehealth.EventStatusValuesResolved)},
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("West US"), Status: ptr.To(armresourcehealth.EventStatusValuesActive)},
+				),
+			},
+		},
+		{
+			name: "active service issue in another region",
+			events: []*armresourcehealth.Event{
+				serviceIssue("Networking outage", armresourcehealth.EventStatusValuesActive,
+					&armresourcehealth.ImpactedServiceRegion{ImpactedRegion: ptr.To("East US 2")},
+				),
+			},
+		},
+		{
+			name: "active service issue of another service in the region",
+			events: []*armresourcehealth.Event{
+				{
+					Properties: &armresourcehealth.EventProperties{
+						EventType: ptr.To(armresourcehealth.EventTypeValuesServiceIssue),
+						Status:    ptr.To(armresourcehealth.EventStatusValuesActive),
+						Title:     ptr.To("Cosmos DB outage"),
+						Impact: []*armresourcehealth.Impact{
+							{
+								ImpactedService: ptr.To("Azure Cosmos DB"),
+								ImpactedRegions: []*armresourcehealth.ImpactedServiceRegion{{ImpactedRegion: ptr.To("East US")}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "planned maintenance in the region",
+			events: []*armresourcehealth.Event{
+				{
+					Properties: &armresourcehealth.EventProperties{
+						EventType: ptr.To(armresourcehealth.EventTypeValuesPlannedMaintenance),
+						Status:    ptr.To(armresourcehealth.EventStatusValuesActive),
+						Impact: []*armresourcehealth.Impact{
+							{ImpactedRegions: []*armresourcehealth.ImpactedServiceRegion{{ImpactedRegion: ptr.To("East US")}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:          "API error",
+			listErr:       errors.New("some API error"),
+			expectedError: "failed to list service health events: some API error",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_resourcehealth.NewMockclient(mockCtrl)
+			clientMock.EXPECT().ListEvents(gomockinternal.AContext()).Return(tc.events, tc.listErr)
+
+			checker := &RegionIncidentChecker{client: clientMock}
+			incident, err := checker.ActiveIncident(context.TODO(), "eastus")
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(incident).To(Equal(tc.expected))
+		})
+	}
+}
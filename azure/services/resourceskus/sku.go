@@ -75,6 +75,14 @@ const (
 	ConfidentialComputingType = "ConfidentialComputingType"
 	// CPUArchitectureType identifies the capability for cpu architecture.
 	CPUArchitectureType = "CpuArchitectureType"
+	// CachedDiskBytes identifies the capability for the size of the cache disk in bytes.
+	CachedDiskBytes = "CachedDiskBytes"
+	// MaxResourceVolumeMB identifies the capability for the size of the temp disk in MB.
+	MaxResourceVolumeMB = "MaxResourceVolumeMB"
+	// DiskControllerTypes identifies the capability for the supported disk controller types, e.g. "SCSI, NVMe".
+	DiskControllerTypes = "DiskControllerTypes"
+	// HibernationSupported identifies the capability for hibernation support.
+	HibernationSupported = "HibernationSupported"
 )
 
 // HasCapability return true for a capability which can be either
@@ -138,6 +146,55 @@ func (s SKU) GetCapability(name string) (string, bool) {
 	return "", false
 }
 
+// MaxEphemeralOSDiskSizeGB returns the largest ephemeral OS disk size in GB supported by the SKU, which is the
+// larger of its cache disk and temp disk, since an ephemeral OS disk can be placed on either.
+func (s SKU) MaxEphemeralOSDiskSizeGB() (int64, error) {
+	var maxSizeGB int64
+	if value, ok := s.GetCapability(CachedDiskBytes); ok {
+		cachedDiskBytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse string '%s' as int64", value)
+		}
+		maxSizeGB = cachedDiskBytes / (1024 * 1024 * 1024)
+	}
+	tempDiskSizeGB, err := s.TempDiskSizeGB()
+	if err != nil {
+		return 0, err
+	}
+	if tempDiskSizeGB > maxSizeGB {
+		maxSizeGB = tempDiskSizeGB
+	}
+	return maxSizeGB, nil
+}
+
+// TempDiskSizeGB returns the size in GB of the temp disk of the SKU, which is 0 when the SKU has no temp disk.
+func (s SKU) TempDiskSizeGB() (int64, error) {
+	value, ok := s.GetCapability(MaxResourceVolumeMB)
+	if !ok {
+		return 0, nil
+	}
+	resourceVolumeMB, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse string '%s' as int64", value)
+	}
+	return resourceVolumeMB / 1024, nil
+}
+
+// SupportsDiskControllerType returns true if the SKU supports the given disk controller type. SKUs that don't report
+// their disk controller types only support SCSI.
+func (s SKU) SupportsDiskControllerType(controllerType string) bool {
+	value, ok := s.GetCapability(DiskControllerTypes)
+	if !ok {
+		return strings.EqualFold(controllerType, "SCSI")
+	}
+	for _, supported := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(supported), controllerType) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasLocationCapability returns true if the provided resource supports the location capability.
 func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
 	if s.LocationInfo == nil {
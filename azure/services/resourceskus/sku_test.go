@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestMaxEphemeralOSDiskSizeGB(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []*armcompute.ResourceSKUCapabilities
+		want         int64
+		wantErr      bool
+	}{
+		{
+			name: "no disk capabilities",
+			want: 0,
+		},
+		{
+			name: "cache disk is larger than temp disk",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("214748364800")},
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("102400")},
+			},
+			want: 200,
+		},
+		{
+			name: "temp disk is larger than cache disk",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("53687091200")},
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("131072")},
+			},
+			want: 128,
+		},
+		{
+			name: "invalid cache disk size",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("lots")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sku := SKU{Capabilities: tc.capabilities}
+			got, err := sku.MaxEphemeralOSDiskSizeGB()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestTempDiskSizeGB(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities []*armcompute.ResourceSKUCapabilities
+		want         int64
+		wantErr      bool
+	}{
+		{
+			name: "no temp disk",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(CachedDiskBytes), Value: ptr.To("53687091200")},
+			},
+			want: 0,
+		},
+		{
+			name: "temp disk",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("32768")},
+			},
+			want: 32,
+		},
+		{
+			name: "invalid temp disk size",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(MaxResourceVolumeMB), Value: ptr.To("lots")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sku := SKU{Capabilities: tc.capabilities}
+			got, err := sku.TempDiskSizeGB()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestSupportsDiskControllerType(t *testing.T) {
+	tests := []struct {
+		name           string
+		capabilities   []*armcompute.ResourceSKUCapabilities
+		controllerType string
+		want           bool
+	}{
+		{
+			name:           "SCSI without disk controller types",
+			controllerType: "SCSI",
+			want:           true,
+		},
+		{
+			name:           "NVMe without disk controller types",
+			controllerType: "NVMe",
+			want:           false,
+		},
+		{
+			name: "NVMe with SCSI only",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(DiskControllerTypes), Value: ptr.To("SCSI")},
+			},
+			controllerType: "NVMe",
+			want:           false,
+		},
+		{
+			name: "NVMe with SCSI and NVMe",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(DiskControllerTypes), Value: ptr.To("SCSI, NVMe")},
+			},
+			controllerType: "NVMe",
+			want:           true,
+		},
+		{
+			name: "SCSI with NVMe only",
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: ptr.To(DiskControllerTypes), Value: ptr.To("NVMe")},
+			},
+			controllerType: "SCSI",
+			want:           false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sku := SKU{Capabilities: tc.capabilities}
+			g.Expect(sku.SupportsDiskControllerType(tc.controllerType)).To(Equal(tc.want))
+		})
+	}
+}
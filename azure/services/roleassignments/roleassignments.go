@@ -18,10 +18,13 @@ package roleassignments
 
 import (
 	"context"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
@@ -29,7 +32,13 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-const serviceName = "roleassignments"
+const (
+	serviceName = "roleassignments"
+
+	// roleAssignmentExistsErrorCode is the error code returned by Azure when the principal already has the role on the
+	// scope through a role assignment with another name.
+	roleAssignmentExistsErrorCode = "RoleAssignmentExists"
+)
 
 // RoleAssignmentScope defines the scope interface for a role assignment service.
 type RoleAssignmentScope interface {
@@ -69,7 +78,7 @@ func New(scope RoleAssignmentScope) (*Service, error) {
 		virtualMachinesGetter:        virtualMachinesClient,
 		virtualMachineScaleSetGetter: scaleSetsClient,
 		Reconciler: async.New[armauthorization.RoleAssignmentsClientCreateResponse,
-			armauthorization.RoleAssignmentsClientDeleteResponse](scope, client, nil),
+			armauthorization.RoleAssignmentsClientDeleteResponse](scope, client, nil).WithConflictAdoption(isAdoptableRoleAssignment),
 	}, nil
 }
 
@@ -117,10 +126,18 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	for _, roleAssignmentSpec := range s.Scope.RoleAssignmentSpecs(principalID) {
 		log.V(2).Info("Creating role assignment")
-		if roleAssignmentSpec.ResourceName() == "" {
-			log.V(2).Info("RoleAssignmentName is empty. This is not expected and will cause this System Assigned Identity to have no permissions.")
+		if spec, ok := roleAssignmentSpec.(*RoleAssignmentSpec); ok && spec.Name == "" {
+			named := *spec
+			named.Name = azure.GenerateRoleAssignmentName(spec.Scope, spec.RoleDefinitionID, ptr.Deref(spec.PrincipalID, ""))
+			log.V(2).Info("RoleAssignmentName is empty, using a name generated from the scope, role and principal", "name", named.Name)
+			roleAssignmentSpec = &named
 		}
 		_, err := s.CreateOrUpdateResource(ctx, roleAssignmentSpec, serviceName)
+		if isRoleAssignmentExistsError(err) {
+			// The identity already has the role on the scope through a role assignment with another name.
+			log.V(2).Info("adopting existing role assignment of the system assigned identity", "scope", roleAssignmentSpec.OwnerResourceName())
+			continue
+		}
 		if err != nil {
 			return errors.Wrapf(err, "cannot assign role to %s system assigned identity", resourceType)
 		}
@@ -129,6 +146,31 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// isRoleAssignmentExistsError returns true if the error is returned by Azure because the principal already has the
+// role on the scope.
+func isRoleAssignmentExistsError(err error) bool {
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.ErrorCode == roleAssignmentExistsErrorCode
+}
+
+// isAdoptableRoleAssignment returns true if the existing role assignment conflicting with the creation of the role
+// assignment of spec can be adopted, i.e. it assigns the same role to the same principal on the same scope. Role
+// assignments can't be tagged, so they are adopted when they grant exactly what CAPZ would have.
+func isAdoptableRoleAssignment(spec azure.ResourceSpecGetter, existing interface{}) bool {
+	assignment, ok := existing.(armauthorization.RoleAssignment)
+	if !ok || assignment.Properties == nil {
+		return false
+	}
+	roleSpec, ok := spec.(*RoleAssignmentSpec)
+	if !ok {
+		return false
+	}
+	props := assignment.Properties
+	return ptr.Deref(props.PrincipalID, "") == ptr.Deref(roleSpec.PrincipalID, "") &&
+		strings.EqualFold(ptr.Deref(props.RoleDefinitionID, ""), roleSpec.RoleDefinitionID) &&
+		strings.EqualFold(ptr.Deref(props.Scope, ""), roleSpec.Scope)
+}
+
 // getVMPrincipalID returns the VM principal ID.
 func (s *Service) getVMPrincipalID(ctx context.Context) (*string, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.getVMPrincipalID")
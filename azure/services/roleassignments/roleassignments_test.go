@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -46,12 +47,14 @@ var (
 	}
 	fakePrincipalID     = "fake-p-id"
 	fakeRoleAssignment1 = RoleAssignmentSpec{
+		Name:          "fake-role-assignment-vm",
 		MachineName:   "test-vm",
 		ResourceGroup: "my-rg",
 		ResourceType:  azure.VirtualMachine,
 		PrincipalID:   ptr.To("fake-principal-id"),
 	}
 	fakeRoleAssignment2 = RoleAssignmentSpec{
+		Name:          "fake-role-assignment-vmss",
 		MachineName:   "test-vmss",
 		ResourceGroup: "my-rg",
 		ResourceType:  azure.VirtualMachineScaleSet,
@@ -65,6 +68,17 @@ var (
 	}
 )
 
+func roleAssignmentExistsError() *azcore.ResponseError {
+	return &azcore.ResponseError{
+		ErrorCode: "RoleAssignmentExists",
+		RawResponse: &http.Response{
+			Body:       io.NopCloser(strings.NewReader("#: The role assignment already exists.: StatusCode=409")),
+			StatusCode: http.StatusConflict,
+		},
+		StatusCode: http.StatusConflict,
+	}
+}
+
 func internalError() *azcore.ResponseError {
 	return &azcore.ResponseError{
 		RawResponse: &http.Response{
@@ -101,6 +115,27 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment1, serviceName).Return(&fakeRoleAssignment1, nil)
 			},
 		},
+		{
+			name:          "adopt a role assignment which already exists with another name",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
+				m *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.ResourceGroup().Return("my-rg")
+				s.Name().Return(fakeRoleAssignment1.MachineName)
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentResourceType().Return("VirtualMachine")
+				s.RoleAssignmentSpecs(&fakePrincipalID).Return(fakeRoleAssignmentSpecs[:1])
+				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{
+					Identity: &armcompute.VirtualMachineIdentity{
+						PrincipalID: &fakePrincipalID,
+					},
+				}, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRoleAssignment1, serviceName).Return(nil,
+					fmt.Errorf("failed to create or update resource: %w", roleAssignmentExistsError()))
+			},
+		},
 		{
 			name:          "error getting VM",
 			expectedError: "failed to assign role to system assigned identity: failed to get principal ID for VM:.*#: Internal Server Error: StatusCode=500",
@@ -170,6 +205,128 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 	}
 }
 
+func TestReconcileRoleAssignmentsInterruptedCreate(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+	vmGetterMock := mock_async.NewMockGetter(mockCtrl)
+	asyncMock := mock_async.NewMockReconciler(mockCtrl)
+	s := &Service{
+		Scope:                 scopeMock,
+		virtualMachinesGetter: vmGetterMock,
+		Reconciler:            asyncMock,
+	}
+
+	// Each reconciliation gets a role assignment spec without a name, as if its name had never been defaulted.
+	unnamedSpec := func() []azure.ResourceSpecGetter {
+		return []azure.ResourceSpecGetter{&RoleAssignmentSpec{
+			MachineName:      "test-vm",
+			ResourceGroup:    "my-rg",
+			ResourceType:     azure.VirtualMachine,
+			PrincipalID:      &fakePrincipalID,
+			RoleDefinitionID: "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/contributor",
+			Scope:            "/subscriptions/12345/",
+		}}
+	}
+	expectReconcile := func() {
+		scopeMock.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+		scopeMock.EXPECT().ResourceGroup().Return("my-rg")
+		scopeMock.EXPECT().Name().Return("test-vm")
+		scopeMock.EXPECT().HasSystemAssignedIdentity().Return(true)
+		scopeMock.EXPECT().RoleAssignmentResourceType().Return(azure.VirtualMachine)
+		scopeMock.EXPECT().RoleAssignmentSpecs(&fakePrincipalID).DoAndReturn(func(*string) []azure.ResourceSpecGetter { return unnamedSpec() })
+		vmGetterMock.EXPECT().Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{
+			Identity: &armcompute.VirtualMachineIdentity{
+				PrincipalID: &fakePrincipalID,
+			},
+		}, nil)
+	}
+
+	// The first reconciliation is interrupted while the role assignment is created.
+	var names []string
+	expectReconcile()
+	asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).DoAndReturn(
+		func(_ context.Context, spec azure.ResourceSpecGetter, _ string) (interface{}, error) {
+			names = append(names, spec.ResourceName())
+			return nil, context.DeadlineExceeded
+		})
+	g.Expect(s.Reconcile(context.TODO())).To(MatchError(ContainSubstring(context.DeadlineExceeded.Error())))
+
+	// The next one retries the creation under the same name.
+	expectReconcile()
+	asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).DoAndReturn(
+		func(_ context.Context, spec azure.ResourceSpecGetter, _ string) (interface{}, error) {
+			names = append(names, spec.ResourceName())
+			return nil, nil
+		})
+	g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+
+	g.Expect(names).To(HaveLen(2))
+	g.Expect(names[0]).NotTo(BeEmpty())
+	g.Expect(names[1]).To(Equal(names[0]))
+	g.Expect(names[0]).To(Equal(azure.GenerateRoleAssignmentName("/subscriptions/12345/", "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/contributor", fakePrincipalID)))
+}
+
+func TestIsAdoptableRoleAssignment(t *testing.T) {
+	spec := &RoleAssignmentSpec{
+		Name:             "fake-role-assignment",
+		PrincipalID:      &fakePrincipalID,
+		RoleDefinitionID: "/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/contributor",
+		Scope:            "/subscriptions/12345/",
+	}
+	testcases := []struct {
+		name     string
+		existing interface{}
+		expected bool
+	}{
+		{
+			name: "same role, principal and scope",
+			existing: armauthorization.RoleAssignment{Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      &fakePrincipalID,
+				RoleDefinitionID: ptr.To("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/Contributor"),
+				Scope:            ptr.To("/subscriptions/12345/"),
+			}},
+			expected: true,
+		},
+		{
+			name: "different role",
+			existing: armauthorization.RoleAssignment{Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      &fakePrincipalID,
+				RoleDefinitionID: ptr.To("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/owner"),
+				Scope:            ptr.To("/subscriptions/12345/"),
+			}},
+		},
+		{
+			name: "different principal",
+			existing: armauthorization.RoleAssignment{Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      ptr.To("other-principal-id"),
+				RoleDefinitionID: ptr.To("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/contributor"),
+				Scope:            ptr.To("/subscriptions/12345/"),
+			}},
+		},
+		{
+			name: "different scope",
+			existing: armauthorization.RoleAssignment{Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      &fakePrincipalID,
+				RoleDefinitionID: ptr.To("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/contributor"),
+				Scope:            ptr.To("/subscriptions/12345/resourceGroups/my-rg"),
+			}},
+		},
+		{
+			name:     "not a role assignment",
+			existing: armcompute.VirtualMachine{},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isAdoptableRoleAssignment(spec, tc.existing)).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 	testcases := []struct {
 		name   string
@@ -33,6 +33,7 @@ const serviceName = "routetables"
 type RouteTableScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	RouteTableSpecs() []azure.ResourceSpecGetter
 	IsVnetManaged() bool
 }
@@ -41,6 +42,7 @@ type RouteTableScope interface {
 type Service struct {
 	Scope RouteTableScope
 	async.Reconciler
+	async.Getter
 }
 
 // New creates a new service.
@@ -50,7 +52,8 @@ func New(scope RouteTableScope) (*Service, error) {
 		return nil, err
 	}
 	return &Service{
-		Scope: scope,
+		Scope:  scope,
+		Getter: client,
 		Reconciler: async.New[armnetwork.RouteTablesClientCreateOrUpdateResponse,
 			armnetwork.RouteTablesClientDeleteResponse](scope, client, client),
 	}, nil
@@ -83,10 +86,18 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	recordOwnership := s.Scope.IsOwnershipRecorded()
+
 	// We go through the list of route tables to reconcile each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	for _, rtSpec := range specs {
+		if recordOwnership {
+			if err := async.RecordManagedResourceIfNotFound(ctx, s.Scope, s.Getter, rtSpec, s.routeTableID(rtSpec)); err != nil {
+				resErr = err
+				continue
+			}
+		}
 		if _, err := s.CreateOrUpdateResource(ctx, rtSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || resErr == nil {
 				resErr = err
@@ -125,10 +136,16 @@ func (s *Service) Delete(ctx context.Context) error {
 	// order of precedence is: error deleting -> deleting in progress -> deleted (no error)
 	var result error
 	for _, rtSpec := range specs {
+		if !s.Scope.IsManagedResource(s.routeTableID(rtSpec), true) {
+			log.V(2).Info("Skipping route table deletion for route table not created by CAPZ", "route table", rtSpec.ResourceName())
+			continue
+		}
 		if err := s.DeleteResource(ctx, rtSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+		} else {
+			s.Scope.ForgetManagedResource(s.routeTableID(rtSpec))
 		}
 	}
 	s.Scope.UpdateDeleteStatus(infrav1.RouteTablesReadyCondition, serviceName, result)
@@ -142,3 +159,8 @@ func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 
 	return s.Scope.IsVnetManaged(), nil
 }
+
+// routeTableID returns the Azure resource ID of the route table.
+func (s *Service) routeTableID(spec azure.ResourceSpecGetter) string {
+	return azure.RouteTableID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+}
@@ -74,6 +74,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.RouteTablesReadyCondition, serviceName, nil)
@@ -86,6 +87,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(nil, errFake)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.RouteTablesReadyCondition, serviceName, errFake)
@@ -98,6 +100,7 @@ func TestReconcileRouteTables(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(nil, errFake)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil, notDoneError)
 				s.UpdatePutStatus(infrav1.RouteTablesReadyCondition, serviceName, errFake)
@@ -164,8 +167,16 @@ func TestDeleteRouteTable(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT.ResourceGroup, fakeRT.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.RouteTableID("123", fakeRT.ResourceGroupName(), fakeRT.ResourceName()))
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroup, fakeRT2.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroupName(), fakeRT2.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.RouteTablesReadyCondition, serviceName, nil)
 			},
 		},
@@ -176,8 +187,14 @@ func TestDeleteRouteTable(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT.ResourceGroup, fakeRT.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(errFake)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroup, fakeRT2.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroupName(), fakeRT2.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.RouteTablesReadyCondition, serviceName, errFake)
 			},
 		},
@@ -188,11 +205,32 @@ func TestDeleteRouteTable(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT.ResourceGroup, fakeRT.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT, serviceName).Return(errFake)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroup, fakeRT2.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(notDoneError)
 				s.UpdateDeleteStatus(infrav1.RouteTablesReadyCondition, serviceName, errFake)
 			},
 		},
+		{
+			name:          "skip route tables not created by CAPZ when ownership is recorded",
+			expectedError: "",
+			expect: func(s *mock_routetables.MockRouteTableScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.IsVnetManaged().Return(true)
+				s.RouteTableSpecs().Return([]azure.ResourceSpecGetter{&fakeRT, &fakeRT2})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT.ResourceGroup, fakeRT.Name), true).Return(false)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroup, fakeRT2.Name), true).Return(true)
+				r.DeleteResource(gomockinternal.AContext(), &fakeRT2, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.RouteTableID("123", fakeRT2.ResourceGroupName(), fakeRT2.ResourceName()))
+				s.UpdateDeleteStatus(infrav1.RouteTablesReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "noop if vnet is not managed",
 			expectedError: "",
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -33,6 +34,7 @@ type Client interface {
 	Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
 	List(context.Context, string) ([]armcompute.VirtualMachineScaleSet, error)
 	ListInstances(context.Context, string, string) ([]armcompute.VirtualMachineScaleSetVM, error)
+	ListInstancesWithInstanceView(context.Context, string, string) ([]armcompute.VirtualMachineScaleSetVM, error)
 
 	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse], err error)
 	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachineScaleSetsClientDeleteResponse], err error)
@@ -95,8 +97,23 @@ func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName stri
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstances")
 	defer done()
 
+	return ac.listInstances(ctx, resourceGroupName, resourceName, nil)
+}
+
+// ListInstancesWithInstanceView retrieves information about the model views of a virtual machine scale set along with
+// the instance views of the instances, which carry their fault domain and power state. Expanding the instance views
+// makes the request more expensive, so it is only done when they are needed.
+func (ac *AzureClient) ListInstancesWithInstanceView(ctx context.Context, resourceGroupName string, resourceName string) ([]armcompute.VirtualMachineScaleSetVM, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstancesWithInstanceView")
+	defer done()
+
+	opts := &armcompute.VirtualMachineScaleSetVMsClientListOptions{Expand: ptr.To("instanceView")}
+	return ac.listInstances(ctx, resourceGroupName, resourceName, opts)
+}
+
+func (ac *AzureClient) listInstances(ctx context.Context, resourceGroupName string, resourceName string, opts *armcompute.VirtualMachineScaleSetVMsClientListOptions) ([]armcompute.VirtualMachineScaleSetVM, error) {
 	var instances []armcompute.VirtualMachineScaleSetVM
-	pager := ac.scalesetvms.NewListPager(resourceGroupName, resourceName, nil)
+	pager := ac.scalesetvms.NewListPager(resourceGroupName, resourceName, opts)
 	for pager.More() {
 		nextResult, err := pager.NextPage(ctx)
 		if err != nil {
@@ -133,3 +133,18 @@ func (mr *MockClientMockRecorder) ListInstances(arg0, arg1, arg2 any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockClient)(nil).ListInstances), arg0, arg1, arg2)
 }
+
+// ListInstancesWithInstanceView mocks base method.
+func (m *MockClient) ListInstancesWithInstanceView(arg0 context.Context, arg1, arg2 string) ([]armcompute.VirtualMachineScaleSetVM, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstancesWithInstanceView", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]armcompute.VirtualMachineScaleSetVM)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstancesWithInstanceView indicates an expected call of ListInstancesWithInstanceView.
+func (mr *MockClientMockRecorder) ListInstancesWithInstanceView(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstancesWithInstanceView", reflect.TypeOf((*MockClient)(nil).ListInstancesWithInstanceView), arg0, arg1, arg2)
+}
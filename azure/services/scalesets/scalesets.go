@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -53,6 +54,7 @@ type (
 		Scope ScaleSetScope
 		Client
 		resourceSKUCache *resourceskus.Cache
+		instanceCache    *scalesetvms.InstanceCache
 		async.Reconciler
 	}
 )
@@ -63,12 +65,17 @@ func New(scope ScaleSetScope, skuCache *resourceskus.Cache) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	instanceCache, err := scalesetvms.GetInstanceCache()
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Reconciler: async.New[armcompute.VirtualMachineScaleSetsClientCreateOrUpdateResponse,
-			armcompute.VirtualMachineScaleSetsClientDeleteResponse](scope, client, client),
+			armcompute.VirtualMachineScaleSetsClientDeleteResponse](scope, client, client).WithDeleteConfirmation(client),
 		Client:           client,
 		Scope:            scope,
 		resourceSKUCache: skuCache,
+		instanceCache:    instanceCache,
 	}, nil
 }
 
@@ -99,7 +106,7 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	_, err := s.Client.Get(ctx, spec)
 	if err == nil {
 		// We can only get the existing instances if the VMSS already exists
-		scaleSetSpec.VMSSInstances, err = s.Client.ListInstances(ctx, spec.ResourceGroupName(), spec.ResourceName())
+		scaleSetSpec.VMSSInstances, err = s.listInstances(ctx, scaleSetSpec)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to get existing VMSS instances")
 			s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
@@ -112,6 +119,11 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	result, err := s.CreateOrUpdateResource(ctx, scaleSetSpec, serviceName)
 	s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
 
+	if err != nil || result == nil {
+		// The scale set is being created or updated, so the instances listed beforehand may be outdated.
+		s.invalidateInstances(scaleSetSpec)
+	}
+
 	if err == nil && result != nil {
 		vmss, ok := result.(armcompute.VirtualMachineScaleSet)
 		if !ok {
@@ -119,6 +131,7 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		}
 
 		fetchedVMSS := converters.SDKToVMSS(vmss, scaleSetSpec.VMSSInstances)
+		s.cacheInstances(scaleSetSpec, &fetchedVMSS)
 		if err := s.Scope.ReconcileReplicas(ctx, &fetchedVMSS); err != nil {
 			return errors.Wrap(err, "unable to reconcile VMSS replicas")
 		}
@@ -158,12 +171,47 @@ func (s *Service) Delete(ctx context.Context) error {
 	}()
 
 	err := s.DeleteResource(ctx, scaleSetSpec, serviceName)
+	if spec, ok := scaleSetSpec.(*ScaleSetSpec); ok {
+		s.invalidateInstances(spec)
+	}
 
 	s.Scope.UpdateDeleteStatus(infrav1.BootstrapSucceededCondition, serviceName, err)
 
 	return err
 }
 
+// listInstances lists the instances of the scale set, along with their instance views if the spec requires them.
+func (s *Service) listInstances(ctx context.Context, spec azure.ResourceSpecGetter) ([]armcompute.VirtualMachineScaleSetVM, error) {
+	if scaleSetSpec, ok := spec.(*ScaleSetSpec); ok && scaleSetSpec.ExpandInstanceView {
+		return s.Client.ListInstancesWithInstanceView(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	}
+	return s.Client.ListInstances(ctx, spec.ResourceGroupName(), spec.ResourceName())
+}
+
+// cacheInstances shares the instances listed for a uniform scale set with the AzureMachinePoolMachine reconciles,
+// which would otherwise get each instance individually. The instances are only cached while the scale set isn't
+// scaling, as they may not reflect the scale set otherwise.
+func (s *Service) cacheInstances(spec *ScaleSetSpec, vmss *azure.VMSS) {
+	if s.instanceCache == nil {
+		return
+	}
+	if spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode ||
+		vmss.State != infrav1.Succeeded ||
+		vmss.Capacity != int64(len(spec.VMSSInstances)) {
+		s.invalidateInstances(spec)
+		return
+	}
+	s.instanceCache.Set(s.Scope.SubscriptionID(), spec.ResourceGroup, spec.Name, spec.VMSSInstances)
+}
+
+// invalidateInstances removes the cached instances of the scale set.
+func (s *Service) invalidateInstances(spec *ScaleSetSpec) {
+	if s.instanceCache == nil {
+		return
+	}
+	s.instanceCache.Invalidate(s.Scope.SubscriptionID(), spec.ResourceGroup, spec.Name)
+}
+
 func (s *Service) validateSpec(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.validateSpec")
 	defer done()
@@ -257,6 +305,12 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		}
 	}
 
+	// Zone balancing is checked again here, as the failure domains of the MachinePool can change without going
+	// through the AzureMachinePool webhook.
+	if scaleSetSpec.ZoneSpreadPolicy != nil && scaleSetSpec.ZoneSpreadPolicy.ZoneBalance != nil && len(scaleSetSpec.FailureDomains) < 2 {
+		return azure.WithTerminalError(errors.Errorf("zone balancing requires more than one failure domain, the scale set has %d", len(scaleSetSpec.FailureDomains)))
+	}
+
 	// Checking if selected availability zones are available selected VM type in location
 	azsInLocation, err := s.resourceSKUCache.GetZonesWithVMSize(ctx, scaleSetSpec.Size, scaleSetSpec.Location)
 	if err != nil {
@@ -286,7 +340,7 @@ func (s *Service) getVirtualMachineScaleSet(ctx context.Context, spec azure.Reso
 		return nil, errors.Errorf("%T is not an armcompute.VirtualMachineScaleSet", vmssResult)
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	vmssInstances, err := s.listInstances(ctx, spec)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
@@ -35,6 +36,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -129,6 +132,24 @@ func TestReconcileVMSS(t *testing.T) {
 				s.SetVMSSState(&fetchedVMSS)
 			},
 		},
+		{
+			name:          "update an existing vmss whose instances are listed with their instance views",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				spec := getDefaultVMSSSpec()
+				spec.(*ScaleSetSpec).ExpandInstanceView = true
+				s.ScaleSetSpec(gomockinternal.AContext()).Return(spec).AnyTimes()
+				m.Get(gomockinternal.AContext(), spec).Return(&resultVMSS, nil)
+				m.ListInstancesWithInstanceView(gomockinternal.AContext(), defaultSpec.ResourceGroup, defaultSpec.Name).Return(defaultInstances, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), spec, serviceName).Return(getResultVMSS(), nil)
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+
+				s.ReconcileReplicas(gomockinternal.AContext(), &fetchedVMSS).Return(nil)
+				s.SetProviderID(azureutil.ProviderIDPrefix + defaultVMSSID)
+				s.SetVMSSState(&fetchedVMSS)
+			},
+		},
 		{
 			name:          "create a vmss, skip list instances if vmss doesn't exist",
 			expectedError: "",
@@ -289,6 +310,27 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
 			},
 		},
+		{
+			name:          "validate spec failure: fail to balance a vmss placed in a single failure domain",
+			expectedError: "reconcile error that cannot be recovered occurred: zone balancing requires more than one failure domain, the scale set has 1. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				spec := newDefaultVMSSSpec()
+				spec.FailureDomains = []string{"1"}
+				spec.ZoneSpreadPolicy = &infrav1exp.ZoneSpreadPolicy{ZoneBalance: ptr.To(true)}
+				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
+			},
+		},
+		{
+			name:          "validate spec failure: fail to create a vmss in a failure domain the location doesn't offer",
+			expectedError: "reconcile error that cannot be recovered occurred: availability zone 2 is not available for VM type VM_SIZE in location test-location. Object will not be requeued",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				spec := newDefaultVMSSSpec()
+				spec.FailureDomains = []string{"1", "2"}
+				s.ScaleSetSpec(gomockinternal.AContext()).Return(&spec).AnyTimes()
+			},
+		},
 		{
 			name:          "validate spec failure: fail to create a vm with diagnostics set to User Managed but empty StorageAccountURI",
 			expectedError: "reconcile error that cannot be recovered occurred: userManaged must be specified when storageAccountType is 'UserManaged'. Object will not be requeued",
@@ -341,6 +383,98 @@ func TestReconcileVMSS(t *testing.T) {
 	}
 }
 
+func TestReconcileVMSSInstanceCache(t *testing.T) {
+	defaultInstances := newDefaultInstances()
+
+	testcases := []struct {
+		name        string
+		capacity    int64
+		state       string
+		reconcileFn func(r *mock_async.MockReconcilerMockRecorder, result armcompute.VirtualMachineScaleSet)
+		expectCache bool
+	}{
+		{
+			name:     "caches the instances of a vmss that isn't scaling",
+			capacity: 2,
+			state:    "Succeeded",
+			reconcileFn: func(r *mock_async.MockReconcilerMockRecorder, result armcompute.VirtualMachineScaleSet) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).Return(result, nil)
+			},
+			expectCache: true,
+		},
+		{
+			name:     "invalidates the instances of a vmss being scaled",
+			capacity: 3,
+			state:    "Succeeded",
+			reconcileFn: func(r *mock_async.MockReconcilerMockRecorder, result armcompute.VirtualMachineScaleSet) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).Return(result, nil)
+			},
+		},
+		{
+			name:     "invalidates the instances of a vmss being updated",
+			capacity: 2,
+			state:    "Updating",
+			reconcileFn: func(r *mock_async.MockReconcilerMockRecorder, result armcompute.VirtualMachineScaleSet) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).Return(result, nil)
+			},
+		},
+		{
+			name:     "invalidates the instances while the vmss update is in progress",
+			capacity: 2,
+			state:    "Succeeded",
+			reconcileFn: func(r *mock_async.MockReconcilerMockRecorder, _ armcompute.VirtualMachineScaleSet) {
+				r.CreateOrUpdateResource(gomockinternal.AContext(), gomock.Any(), serviceName).Return(nil, internalError())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			result := getResultVMSS()
+			result.SKU.Capacity = ptr.To(tc.capacity)
+			result.Properties.ProvisioningState = ptr.To(tc.state)
+
+			scopeMock.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+			scopeMock.EXPECT().ScaleSetSpec(gomockinternal.AContext()).Return(getDefaultVMSSSpec()).AnyTimes()
+			scopeMock.EXPECT().SubscriptionID().Return(defaultSubscriptionID).AnyTimes()
+			clientMock.EXPECT().Get(gomockinternal.AContext(), &defaultSpec).Return(&result, nil)
+			clientMock.EXPECT().ListInstances(gomockinternal.AContext(), defaultSpec.ResourceGroup, defaultSpec.Name).Return(defaultInstances, nil)
+			tc.reconcileFn(asyncMock.EXPECT(), result)
+			scopeMock.EXPECT().UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomock.Any())
+			scopeMock.EXPECT().ReconcileReplicas(gomockinternal.AContext(), gomock.Any()).Return(nil).AnyTimes()
+			scopeMock.EXPECT().SetProviderID(gomock.Any()).AnyTimes()
+			scopeMock.EXPECT().SetVMSSState(gomock.Any()).AnyTimes()
+
+			instanceCache, err := scalesetvms.NewInstanceCache(10, time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			instanceCache.Set(defaultSubscriptionID, defaultSpec.ResourceGroup, defaultSpec.Name, defaultInstances[:1])
+
+			s := &Service{
+				Scope:            scopeMock,
+				Reconciler:       asyncMock,
+				Client:           clientMock,
+				resourceSKUCache: resourceskus.NewStaticCache(getFakeSkus(), "test-location"),
+				instanceCache:    instanceCache,
+			}
+
+			_ = s.Reconcile(context.TODO())
+
+			_, ok := instanceCache.Get(defaultSubscriptionID, defaultSpec.ResourceGroup, defaultSpec.Name, "my-vm-2")
+			g.Expect(ok).To(Equal(tc.expectCache))
+		})
+	}
+}
+
 func TestDeleteVMSS(t *testing.T) {
 	defaultSpec := newDefaultVMSSSpec()
 	defaultInstances := newDefaultInstances()
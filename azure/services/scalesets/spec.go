@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
@@ -29,6 +31,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -41,6 +44,7 @@ type ScaleSetSpec struct {
 	Capacity                     int64
 	SSHKeyData                   string
 	OSDisk                       infrav1.OSDisk
+	PatchSettings                *infrav1.PatchSettings
 	DataDisks                    []infrav1.DataDisk
 	SubnetName                   string
 	VNetName                     string
@@ -64,6 +68,8 @@ type ScaleSetSpec struct {
 	SubscriptionID               string
 	SKU                          resourceskus.SKU
 	VMSSExtensionSpecs           []azure.ResourceSpecGetter
+	ClusterVMExtensionNames      []string
+	RemovedVMExtensionNames      []string
 	VMImage                      *infrav1.Image
 	BootstrapData                string
 	VMSSInstances                []armcompute.VirtualMachineScaleSetVM
@@ -72,6 +78,12 @@ type ScaleSetSpec struct {
 	ShouldPatchCustomData        bool
 	HasReplicasExternallyManaged bool
 	AdditionalTags               infrav1.Tags
+	AutomaticRepairsPolicy       *infrav1exp.AutomaticRepairsPolicy
+	RollingUpdate                *infrav1exp.MachineRollingUpdateDeployment
+	AutomaticOSUpgradePolicy     *infrav1exp.AutomaticOSUpgradePolicy
+	ZoneSpreadPolicy             *infrav1exp.ZoneSpreadPolicy
+	// ExpandInstanceView is whether the instances of the scale set are listed with their instance views.
+	ExpandInstanceView bool
 }
 
 // ResourceName returns the name of the Scale Set.
@@ -109,22 +121,34 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 
 	vmss.Properties.VirtualMachineProfile.NetworkProfile = nil
 	vmss.ID = existingVMSS.ID
+	// Tags on an existing scale set are reconciled by the tags service, which only removes tags CAPZ previously
+	// applied. Keep the existing tags so this update neither clobbers tags added by other systems nor rolls the
+	// instances for a tag-only change.
+	vmss.Tags = existingVMSS.Tags
 
-	hasModelChanges := hasModelModifyingDifferences(&existingInfraVMSS, vmss)
+	hasModelChanges := hasModelModifyingDifferences(&existingInfraVMSS, vmss) || s.hasClusterExtensionChanges(existingInfraVMSS)
 	isFlex := s.OrchestrationMode == infrav1.FlexibleOrchestrationMode
 	updated := true
 	if !isFlex {
 		updated = existingInfraVMSS.HasEnoughLatestModelOrNotMixedModel()
 	}
 	if s.MaxSurge > 0 && (hasModelChanges || !updated) && !s.HasReplicasExternallyManaged {
-		// surge capacity with the intention of lowering during instance reconciliation
-		surge := s.Capacity + int64(s.MaxSurge)
-		vmss.SKU.Capacity = ptr.To[int64](surge)
+		// surge capacity with the intention of lowering during instance reconciliation, without adding more instances
+		// than there are instances to replace
+		surge := int64(s.MaxSurge)
+		if outdated := outdatedInstanceCount(existingInfraVMSS, hasModelChanges); outdated < surge {
+			surge = outdated
+		}
+		vmss.SKU.Capacity = ptr.To[int64](s.Capacity + surge)
 	}
 
 	// If there are no model changes and no increase in the replica count, do not update the VMSS.
 	// Decreases in replica count is handled by deleting AzureMachinePoolMachine instances in the MachinePoolScope
-	if *vmss.SKU.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !s.ShouldPatchCustomData {
+	if *vmss.SKU.Capacity <= existingInfraVMSS.Capacity && !hasModelChanges && !s.ShouldPatchCustomData &&
+		!hasAutomaticRepairsPolicyChanges(existingVMSS.Properties, vmss.Properties.AutomaticRepairsPolicy) &&
+		!hasRollingUpgradePolicyChanges(existingVMSS.Properties, vmss.Properties.UpgradePolicy) &&
+		!hasAutomaticOSUpgradePolicyChanges(existingVMSS.Properties, vmss.Properties.UpgradePolicy) &&
+		!hasZoneBalanceChanges(existingVMSS.Properties, vmss.Properties.ZoneBalance) {
 		// up to date, nothing to do
 		return nil, nil
 	}
@@ -132,6 +156,21 @@ func (s *ScaleSetSpec) existingParameters(ctx context.Context, existing interfac
 	return vmss, nil
 }
 
+// outdatedInstanceCount returns the number of instances of the scale set that need to be replaced to apply its latest
+// model. All of them need to be replaced if the model is being changed.
+func outdatedInstanceCount(vmss azure.VMSS, hasModelChanges bool) int64 {
+	if hasModelChanges {
+		return vmss.Capacity
+	}
+	var outdated int64
+	for _, instance := range vmss.Instances {
+		if !vmss.HasLatestModelApplied(instance) {
+			outdated++
+		}
+	}
+	return outdated
+}
+
 // Parameters returns the parameters for the Scale Set.
 func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
@@ -208,7 +247,11 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 	switch orchestrationMode {
 	case armcompute.OrchestrationModeUniform: // Uniform VMSS
 		vmss.Properties.Overprovision = ptr.To(false)
-		vmss.Properties.UpgradePolicy = &armcompute.UpgradePolicy{Mode: ptr.To(armcompute.UpgradeModeManual)}
+		vmss.Properties.UpgradePolicy = &armcompute.UpgradePolicy{
+			Mode:                     ptr.To(armcompute.UpgradeModeManual),
+			RollingUpgradePolicy:     s.getRollingUpgradePolicy(),
+			AutomaticOSUpgradePolicy: s.getAutomaticOSUpgradePolicy(),
+		}
 	case armcompute.OrchestrationModeFlexible: // VMSS Flex, VMs are treated as individual virtual machines
 		vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion =
 			ptr.To(armcompute.NetworkAPIVersionTwoThousandTwenty1101)
@@ -218,6 +261,16 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 		}
 	}
 
+	if s.ZoneSpreadPolicy != nil {
+		if s.ZoneSpreadPolicy.PlatformFaultDomainCount != nil {
+			vmss.Properties.PlatformFaultDomainCount = ptr.To(*s.ZoneSpreadPolicy.PlatformFaultDomainCount)
+		}
+		// Azure rejects zone balancing for scale sets which aren't spread across zones.
+		if s.ZoneSpreadPolicy.ZoneBalance != nil && len(s.FailureDomains) > 1 {
+			vmss.Properties.ZoneBalance = ptr.To(*s.ZoneSpreadPolicy.ZoneBalance)
+		}
+	}
+
 	// Assign Identity to VMSS
 	if s.Identity == infrav1.VMIdentitySystemAssigned {
 		vmss.Identity = &armcompute.VirtualMachineScaleSetIdentity{
@@ -261,6 +314,8 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 		}
 	}
 
+	vmss.Properties.AutomaticRepairsPolicy = s.getAutomaticRepairsPolicy()
+
 	tags := infrav1.Build(infrav1.BuildParams{
 		ClusterName: s.ClusterName,
 		Lifecycle:   infrav1.ResourceLifecycleOwned,
@@ -273,11 +328,176 @@ func (s *ScaleSetSpec) Parameters(ctx context.Context, existing interface{}) (pa
 	return vmss, nil
 }
 
+// getAutomaticRepairsPolicy returns the automatic repairs policy of the scale set, or nil when the policy is left
+// to Azure.
+func (s *ScaleSetSpec) getAutomaticRepairsPolicy() *armcompute.AutomaticRepairsPolicy {
+	if s.AutomaticRepairsPolicy == nil {
+		return nil
+	}
+	policy := &armcompute.AutomaticRepairsPolicy{
+		Enabled: ptr.To(ptr.Deref(s.AutomaticRepairsPolicy.Enabled, true)),
+	}
+	if gracePeriod := s.AutomaticRepairsPolicy.GracePeriod; gracePeriod != nil {
+		policy.GracePeriod = ptr.To(fmt.Sprintf("PT%dM", int(gracePeriod.Minutes())))
+	}
+	return policy
+}
+
+// hasAutomaticRepairsPolicyChanges returns true if the desired automatic repairs policy differs from the one of the
+// existing scale set. The policy isn't part of the instance model, so changing it doesn't roll the instances.
+func hasAutomaticRepairsPolicyChanges(existing *armcompute.VirtualMachineScaleSetProperties, desired *armcompute.AutomaticRepairsPolicy) bool {
+	if desired == nil {
+		return false
+	}
+	var current armcompute.AutomaticRepairsPolicy
+	if existing != nil && existing.AutomaticRepairsPolicy != nil {
+		current = *existing.AutomaticRepairsPolicy
+	}
+	if ptr.Deref(current.Enabled, false) != ptr.Deref(desired.Enabled, false) {
+		return true
+	}
+	return desired.GracePeriod != nil && !sameDuration(current.GracePeriod, *desired.GracePeriod)
+}
+
+// getRollingUpgradePolicy returns the rolling upgrade policy of the scale set, or nil when the policy is left to Azure.
+// The instances are still replaced by CAPZ, so the policy only applies to the upgrades driven by Azure.
+func (s *ScaleSetSpec) getRollingUpgradePolicy() *armcompute.RollingUpgradePolicy {
+	if s.RollingUpdate == nil || (s.RollingUpdate.MaxUnhealthyInstancePercent == nil && s.RollingUpdate.PauseTimeBetweenBatches == nil) {
+		return nil
+	}
+	policy := &armcompute.RollingUpgradePolicy{
+		MaxSurge:                    ptr.To(s.MaxSurge > 0),
+		MaxUnhealthyInstancePercent: s.RollingUpdate.MaxUnhealthyInstancePercent,
+	}
+	if pause := s.RollingUpdate.PauseTimeBetweenBatches; pause != nil {
+		policy.PauseTimeBetweenBatches = ptr.To(fmt.Sprintf("PT%dS", int(pause.Seconds())))
+	}
+	return policy
+}
+
+// hasRollingUpgradePolicyChanges returns true if the desired rolling upgrade policy differs from the one of the
+// existing scale set. Like the automatic repairs policy, it isn't part of the instance model.
+func hasRollingUpgradePolicyChanges(existing *armcompute.VirtualMachineScaleSetProperties, desired *armcompute.UpgradePolicy) bool {
+	if desired == nil || desired.RollingUpgradePolicy == nil {
+		return false
+	}
+	var current armcompute.RollingUpgradePolicy
+	if existing != nil && existing.UpgradePolicy != nil && existing.UpgradePolicy.RollingUpgradePolicy != nil {
+		current = *existing.UpgradePolicy.RollingUpgradePolicy
+	}
+	want := desired.RollingUpgradePolicy
+	if ptr.Deref(current.MaxSurge, false) != ptr.Deref(want.MaxSurge, false) {
+		return true
+	}
+	if want.MaxUnhealthyInstancePercent != nil && ptr.Deref(current.MaxUnhealthyInstancePercent, 0) != *want.MaxUnhealthyInstancePercent {
+		return true
+	}
+	return want.PauseTimeBetweenBatches != nil && !sameDuration(current.PauseTimeBetweenBatches, *want.PauseTimeBetweenBatches)
+}
+
+// iso8601DurationRegexp matches the ISO 8601 durations Azure uses for the durations of a scale set, e.g. PT1M30S.
+var iso8601DurationRegexp = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseISO8601Duration parses an ISO 8601 duration made of days, hours, minutes and seconds.
+func parseISO8601Duration(s string) (time.Duration, bool) {
+	match := iso8601DurationRegexp.FindStringSubmatch(s)
+	if match == nil || s == "P" || s == "PT" {
+		return 0, false
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+1] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(value * float64(unit))
+	}
+	return d, true
+}
+
+// sameDuration reports whether the ISO 8601 duration of the existing scale set is the desired one. Azure normalizes
+// the durations it returns, e.g. PT90S to PT1M30S, so they are compared by value.
+func sameDuration(current *string, desired string) bool {
+	if current == nil {
+		return false
+	}
+	currentDuration, ok := parseISO8601Duration(*current)
+	desiredDuration, desiredOK := parseISO8601Duration(desired)
+	if !ok || !desiredOK {
+		return *current == desired
+	}
+	return currentDuration == desiredDuration
+}
+
+// getAutomaticOSUpgradePolicy returns the automatic OS image upgrade policy of the scale set, or nil when the policy is
+// left to Azure.
+func (s *ScaleSetSpec) getAutomaticOSUpgradePolicy() *armcompute.AutomaticOSUpgradePolicy {
+	if s.AutomaticOSUpgradePolicy == nil {
+		return nil
+	}
+	return &armcompute.AutomaticOSUpgradePolicy{
+		EnableAutomaticOSUpgrade: ptr.To(ptr.Deref(s.AutomaticOSUpgradePolicy.EnableAutomaticOSUpgrade, false)),
+		DisableAutomaticRollback: ptr.To(ptr.Deref(s.AutomaticOSUpgradePolicy.DisableAutomaticRollback, false)),
+	}
+}
+
+// hasAutomaticOSUpgradePolicyChanges returns true if the desired automatic OS image upgrade policy differs from the one
+// of the existing scale set. Like the automatic repairs policy, it isn't part of the instance model.
+func hasAutomaticOSUpgradePolicyChanges(existing *armcompute.VirtualMachineScaleSetProperties, desired *armcompute.UpgradePolicy) bool {
+	if desired == nil || desired.AutomaticOSUpgradePolicy == nil {
+		return false
+	}
+	var current armcompute.AutomaticOSUpgradePolicy
+	if existing != nil && existing.UpgradePolicy != nil && existing.UpgradePolicy.AutomaticOSUpgradePolicy != nil {
+		current = *existing.UpgradePolicy.AutomaticOSUpgradePolicy
+	}
+	want := desired.AutomaticOSUpgradePolicy
+	return ptr.Deref(current.EnableAutomaticOSUpgrade, false) != ptr.Deref(want.EnableAutomaticOSUpgrade, false) ||
+		ptr.Deref(current.DisableAutomaticRollback, false) != ptr.Deref(want.DisableAutomaticRollback, false)
+}
+
+// hasZoneBalanceChanges returns true if the desired zone balancing differs from the one of the existing scale set. Like
+// the automatic repairs policy, it isn't part of the instance model.
+func hasZoneBalanceChanges(existing *armcompute.VirtualMachineScaleSetProperties, desired *bool) bool {
+	if desired == nil {
+		return false
+	}
+	var current bool
+	if existing != nil {
+		current = ptr.Deref(existing.ZoneBalance, false)
+	}
+	return current != *desired
+}
+
 func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss armcompute.VirtualMachineScaleSet) bool {
 	other := converters.SDKToVMSS(vmss, []armcompute.VirtualMachineScaleSetVM{})
 	return infraVMSS.HasModelChanges(other)
 }
 
+// hasClusterExtensionChanges returns true if an extension of the cluster is missing from the model of the existing scale
+// set, or if an extension removed from the cluster is still in the model. Other differences in the extensions aren't
+// considered so that extensions are only rolled out to the existing instances when the cluster asks for it.
+func (s *ScaleSetSpec) hasClusterExtensionChanges(existing azure.VMSS) bool {
+	installed := make(map[string]bool, len(existing.Extensions))
+	for _, name := range existing.Extensions {
+		installed[name] = true
+	}
+	for _, name := range s.ClusterVMExtensionNames {
+		if !installed[name] {
+			return true
+		}
+	}
+	for _, name := range s.RemovedVMExtensionNames {
+		if installed[name] {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *ScaleSetSpec) generateExtensions(ctx context.Context) ([]armcompute.VirtualMachineScaleSetExtension, error) {
 	extensions := make([]armcompute.VirtualMachineScaleSetExtension, len(s.VMSSExtensionSpecs))
 	for i, extensionSpec := range s.VMSSExtensionSpecs {
@@ -386,6 +606,17 @@ func (s *ScaleSetSpec) generateStorageProfile(ctx context.Context) (*armcompute.
 		}
 	}
 
+	if s.OSDisk.DiskControllerType != "" {
+		if !s.SKU.SupportsDiskControllerType(string(s.OSDisk.DiskControllerType)) {
+			return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support the %s disk controller type. select a different vm size or disk controller type", s.Size, s.OSDisk.DiskControllerType))
+		}
+		if s.OSDisk.DiskControllerType == infrav1.DiskControllerTypeNVMe && infrav1.IsGen1MarketplaceImage(s.VMImage) {
+			return nil, azure.WithTerminalError(fmt.Errorf("image sku %s is a generation 1 image which does not support the NVMe disk controller type. select a generation 2 image or a different disk controller type", s.VMImage.Marketplace.SKU))
+		}
+
+		storageProfile.DiskControllerType = ptr.To(string(s.OSDisk.DiskControllerType))
+	}
+
 	if s.OSDisk.ManagedDisk != nil {
 		storageProfile.OSDisk.ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{}
 		if s.OSDisk.ManagedDisk.StorageAccountType != "" {
@@ -457,7 +688,8 @@ func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*armcompute.Virtual
 		// Azure also provides a way to reset user passwords in the case of need.
 		osProfile.AdminPassword = ptr.To(generators.SudoRandomPassword(123))
 		osProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
-			EnableAutomaticUpdates: ptr.To(false),
+			EnableAutomaticUpdates: ptr.To(converters.IsWindowsAutomaticUpdatesEnabled(s.PatchSettings)),
+			PatchSettings:          converters.GetWindowsPatchSettings(s.PatchSettings),
 		}
 	default:
 		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
@@ -470,6 +702,7 @@ func (s *ScaleSetSpec) generateOSProfile(_ context.Context) (*armcompute.Virtual
 					},
 				},
 			},
+			PatchSettings: converters.GetLinuxPatchSettings(s.PatchSettings),
 		}
 	}
 
@@ -18,17 +18,21 @@ package scalesets
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
 var (
@@ -46,6 +50,9 @@ var (
 	hostEncryptionSpec, hostEncryptionVMSS                                             = getHostEncryptionVMSS()
 	hostEncryptionUnsupportedSpec                                                      = getHostEncryptionUnsupportedSpec()
 	ephemeralReadSpec, ephemeralReadVMSS                                               = getEphemeralReadOnlyVMSS()
+	nvmeSpec, nvmeVMSS                                                                 = getNVMeVMSS()
+	nvmeUnsupportedSpec                                                                = getNVMeUnsupportedSpec()
+	nvmeGen1ImageSpec                                                                  = getNVMeGen1ImageSpec()
 	defaultExistingSpec, defaultExistingVMSS, defaultExistingVMSSClone                 = getExistingDefaultVMSS()
 	userManagedStorageAccountDiagnosticsSpec, userManagedStorageAccountDiagnosticsVMSS = getUserManagedAndStorageAcccountDiagnosticsVMSS()
 	managedDiagnosticsSpec, managedDiagnoisticsVMSS                                    = getManagedDiagnosticsVMSS()
@@ -350,6 +357,45 @@ func getHostEncryptionUnsupportedSpec() ScaleSetSpec {
 	return spec
 }
 
+func getNVMeVMSS() (ScaleSetSpec, armcompute.VirtualMachineScaleSet) {
+	spec := newDefaultVMSSSpec()
+	spec.Size = "VM_SIZE_NVME"
+	spec.SKU = resourceskus.SKU{
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.DiskControllerTypes),
+				Value: ptr.To("SCSI, NVMe"),
+			},
+		},
+	}
+	spec.OSDisk.DiskControllerType = infrav1.DiskControllerTypeNVMe
+	vmss := newDefaultVMSS("VM_SIZE_NVME")
+	vmss.Properties.VirtualMachineProfile.StorageProfile.DiskControllerType = ptr.To(string(armcompute.DiskControllerTypesNVMe))
+
+	return spec, vmss
+}
+
+func getNVMeUnsupportedSpec() ScaleSetSpec {
+	spec, _ := getNVMeVMSS()
+	spec.SKU = resourceskus.SKU{}
+	return spec
+}
+
+func getNVMeGen1ImageSpec() ScaleSetSpec {
+	spec, _ := getNVMeVMSS()
+	spec.VMImage = &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			ImagePlan: infrav1.ImagePlan{
+				Publisher: "cncf-upstream",
+				Offer:     "capi",
+				SKU:       "ubuntu-2204-gen1",
+			},
+			Version: "latest",
+		},
+	}
+	return spec
+}
+
 func getEphemeralReadOnlyVMSS() (ScaleSetSpec, armcompute.VirtualMachineScaleSet) {
 	spec := newDefaultVMSSSpec()
 	spec.Size = "VM_SIZE_EPH"
@@ -642,6 +688,27 @@ func TestScaleSetParameters(t *testing.T) {
 			expected:      ephemeralReadVMSS,
 			expectedError: "",
 		},
+		{
+			name:          "nvme disk controller vmss",
+			spec:          nvmeSpec,
+			existing:      nil,
+			expected:      nvmeVMSS,
+			expectedError: "",
+		},
+		{
+			name:          "nvme disk controller unsupported vmss",
+			spec:          nvmeUnsupportedSpec,
+			existing:      nil,
+			expected:      nil,
+			expectedError: "reconcile error that cannot be recovered occurred: vm size VM_SIZE_NVME does not support the NVMe disk controller type. select a different vm size or disk controller type. Object will not be requeued",
+		},
+		{
+			name:          "nvme disk controller with generation 1 image vmss",
+			spec:          nvmeGen1ImageSpec,
+			existing:      nil,
+			expected:      nil,
+			expectedError: "reconcile error that cannot be recovered occurred: image sku ubuntu-2204-gen1 is a generation 1 image which does not support the NVMe disk controller type. select a generation 2 image or a different disk controller type. Object will not be requeued",
+		},
 		{
 			name:          "update for existing vmss",
 			spec:          defaultExistingSpec,
@@ -707,3 +774,377 @@ func TestScaleSetParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestScaleSetParametersKeepsExistingTags(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+	spec.AdditionalTags = infrav1.Tags{"env": "prod"}
+	existing.Tags["externalSystemTag"] = ptr.To("randomValue")
+
+	// A tag-only difference is left to the tags service and does not update the scale set model.
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Other updates keep the tags reported by Azure.
+	spec.Capacity = 3
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Tags).To(Equal(existing.Tags))
+}
+
+func TestScaleSetParametersOmitsEmptyZones(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	spec.FailureDomains = []string{}
+
+	param, err := spec.Parameters(context.TODO(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Zones).To(BeNil())
+
+	body, err := json.Marshal(vmss)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(body)).NotTo(ContainSubstring(`"zones"`))
+}
+
+func TestScaleSetParametersPatchSettings(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+	spec.PatchSettings = &infrav1.PatchSettings{
+		PatchMode:     infrav1.PatchModeAutomaticByPlatform,
+		RebootSetting: infrav1.PatchRebootSettingIfRequired,
+	}
+
+	// Changing the patch settings updates the scale set model.
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.VirtualMachineProfile.OSProfile.LinuxConfiguration.PatchSettings).To(Equal(&armcompute.LinuxPatchSettings{
+		PatchMode: ptr.To(armcompute.LinuxVMGuestPatchModeAutomaticByPlatform),
+		AutomaticByPlatformSettings: &armcompute.LinuxVMGuestPatchAutomaticByPlatformSettings{
+			RebootSetting: ptr.To(armcompute.LinuxVMGuestPatchAutomaticByPlatformRebootSettingIfRequired),
+		},
+	}))
+
+	// The defaults Azure reports for unset patch settings don't update the scale set model.
+	existing.Properties.VirtualMachineProfile.OSProfile.LinuxConfiguration.PatchSettings = &armcompute.LinuxPatchSettings{
+		PatchMode:      ptr.To(armcompute.LinuxVMGuestPatchModeAutomaticByPlatform),
+		AssessmentMode: ptr.To(armcompute.LinuxPatchAssessmentModeImageDefault),
+		AutomaticByPlatformSettings: &armcompute.LinuxVMGuestPatchAutomaticByPlatformSettings{
+			BypassPlatformSafetyChecksOnUserSchedule: ptr.To(false),
+			RebootSetting:                            ptr.To(armcompute.LinuxVMGuestPatchAutomaticByPlatformRebootSettingIfRequired),
+		},
+	}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+}
+
+func TestScaleSetParametersAutomaticRepairsPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+
+	// An unset policy is left to Azure.
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Setting the policy updates the scale set without a model change.
+	spec.AutomaticRepairsPolicy = &infrav1exp.AutomaticRepairsPolicy{GracePeriod: &metav1.Duration{Duration: 45 * time.Minute}}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.AutomaticRepairsPolicy).To(Equal(&armcompute.AutomaticRepairsPolicy{
+		Enabled:     ptr.To(true),
+		GracePeriod: ptr.To("PT45M"),
+	}))
+	g.Expect(*vmss.SKU.Capacity).To(Equal(spec.Capacity))
+
+	// The policy already applied to the scale set doesn't update it.
+	existing.Properties.AutomaticRepairsPolicy = &armcompute.AutomaticRepairsPolicy{
+		Enabled:     ptr.To(true),
+		GracePeriod: ptr.To("PT45M"),
+	}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Nor does the grace period normalized by Azure.
+	spec.AutomaticRepairsPolicy.GracePeriod = &metav1.Duration{Duration: 90 * time.Minute}
+	existing.Properties.AutomaticRepairsPolicy.GracePeriod = ptr.To("PT1H30M")
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Opting out disables the repairs.
+	spec.AutomaticRepairsPolicy = &infrav1exp.AutomaticRepairsPolicy{Enabled: ptr.To(false)}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok = param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.AutomaticRepairsPolicy).To(Equal(&armcompute.AutomaticRepairsPolicy{Enabled: ptr.To(false)}))
+}
+
+func TestScaleSetParametersRollingUpgradePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+
+	// A rolling update strategy without any of the policy knobs leaves the policy to Azure.
+	spec.RollingUpdate = &infrav1exp.MachineRollingUpdateDeployment{}
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Setting the policy updates the scale set without a model change.
+	spec.RollingUpdate = &infrav1exp.MachineRollingUpdateDeployment{
+		MaxUnhealthyInstancePercent: ptr.To[int32](50),
+		PauseTimeBetweenBatches:     &metav1.Duration{Duration: 90 * time.Second},
+	}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.UpgradePolicy).To(Equal(&armcompute.UpgradePolicy{
+		Mode: ptr.To(armcompute.UpgradeModeManual),
+		RollingUpgradePolicy: &armcompute.RollingUpgradePolicy{
+			MaxSurge:                    ptr.To(false),
+			MaxUnhealthyInstancePercent: ptr.To[int32](50),
+			PauseTimeBetweenBatches:     ptr.To("PT90S"),
+		},
+	}))
+	g.Expect(*vmss.SKU.Capacity).To(Equal(spec.Capacity))
+
+	// The policy already applied to the scale set doesn't update it.
+	existing.Properties.UpgradePolicy = vmss.Properties.UpgradePolicy
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Nor does the pause time normalized by Azure.
+	existing.Properties.UpgradePolicy.RollingUpgradePolicy.PauseTimeBetweenBatches = ptr.To("PT1M30S")
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Changing the pause time updates the scale set.
+	spec.RollingUpdate.PauseTimeBetweenBatches = &metav1.Duration{Duration: 2 * time.Minute}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok = param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.UpgradePolicy.RollingUpgradePolicy.PauseTimeBetweenBatches).To(Equal(ptr.To("PT120S")))
+}
+
+func TestSameDuration(t *testing.T) {
+	testcases := []struct {
+		name    string
+		current *string
+		desired string
+		want    bool
+	}{
+		{name: "unset", current: nil, desired: "PT30S", want: false},
+		{name: "same string", current: ptr.To("PT90S"), desired: "PT90S", want: true},
+		{name: "normalized minutes", current: ptr.To("PT1M"), desired: "PT60S", want: true},
+		{name: "normalized hours", current: ptr.To("PT1H30M"), desired: "PT90M", want: true},
+		{name: "days", current: ptr.To("P1D"), desired: "PT24H", want: true},
+		{name: "fractional seconds", current: ptr.To("PT0.5S"), desired: "PT0S", want: false},
+		{name: "different", current: ptr.To("PT1M"), desired: "PT120S", want: false},
+		{name: "unparsable", current: ptr.To("1m"), desired: "PT60S", want: false},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(sameDuration(tc.current, tc.desired)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestScaleSetParametersAutomaticOSUpgradePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+
+	// Enabling the automatic OS upgrades updates the scale set without a model change.
+	spec.AutomaticOSUpgradePolicy = &infrav1exp.AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(true)}
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.UpgradePolicy).To(Equal(&armcompute.UpgradePolicy{
+		Mode: ptr.To(armcompute.UpgradeModeManual),
+		AutomaticOSUpgradePolicy: &armcompute.AutomaticOSUpgradePolicy{
+			EnableAutomaticOSUpgrade: ptr.To(true),
+			DisableAutomaticRollback: ptr.To(false),
+		},
+	}))
+	g.Expect(*vmss.SKU.Capacity).To(Equal(spec.Capacity))
+
+	// The policy already applied to the scale set doesn't update it.
+	existing.Properties.UpgradePolicy = vmss.Properties.UpgradePolicy
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// Disabling the automatic rollback updates the scale set.
+	spec.AutomaticOSUpgradePolicy.DisableAutomaticRollback = ptr.To(true)
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	vmss, ok = param.(armcompute.VirtualMachineScaleSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(vmss.Properties.UpgradePolicy.AutomaticOSUpgradePolicy.DisableAutomaticRollback).To(Equal(ptr.To(true)))
+}
+
+func TestScaleSetParametersZoneSpreadPolicy(t *testing.T) {
+	t.Run("zone balance and fault domain count are passed to a new scale set", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := newDefaultVMSSSpec()
+		spec.ZoneSpreadPolicy = &infrav1exp.ZoneSpreadPolicy{
+			ZoneBalance:              ptr.To(true),
+			PlatformFaultDomainCount: ptr.To[int32](5),
+		}
+
+		param, err := spec.Parameters(context.TODO(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.Properties.ZoneBalance).To(Equal(ptr.To(true)))
+		g.Expect(vmss.Properties.PlatformFaultDomainCount).To(Equal(ptr.To[int32](5)))
+	})
+
+	t.Run("the fault domain count overrides the default of a Flexible scale set", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := newDefaultVMSSSpec()
+		spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+		spec.ZoneSpreadPolicy = &infrav1exp.ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](1)}
+
+		param, err := spec.Parameters(context.TODO(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.Properties.PlatformFaultDomainCount).To(Equal(ptr.To[int32](1)))
+	})
+
+	t.Run("zone balance is left out for a scale set in a single zone", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := newDefaultVMSSSpec()
+		spec.FailureDomains = []string{"1"}
+		spec.ZoneSpreadPolicy = &infrav1exp.ZoneSpreadPolicy{ZoneBalance: ptr.To(true)}
+
+		param, err := spec.Parameters(context.TODO(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.Properties.ZoneBalance).To(BeNil())
+	})
+
+	t.Run("changing the zone balance updates an existing scale set", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := newDefaultVMSSSpec()
+		existing := newDefaultExistingVMSS("VM_SIZE")
+		spec.ZoneSpreadPolicy = &infrav1exp.ZoneSpreadPolicy{ZoneBalance: ptr.To(true)}
+
+		param, err := spec.Parameters(context.TODO(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.Properties.ZoneBalance).To(Equal(ptr.To(true)))
+		g.Expect(*vmss.SKU.Capacity).To(Equal(spec.Capacity))
+
+		// The zone balance already applied to the scale set doesn't update it.
+		existing.Properties.ZoneBalance = ptr.To(true)
+		param, err = spec.Parameters(context.TODO(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(param).To(BeNil())
+	})
+}
+
+func TestScaleSetParametersClusterExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := newDefaultVMSSSpec()
+	existing := newDefaultExistingVMSS("VM_SIZE")
+
+	// Extensions of the cluster already in the model don't update the scale set.
+	spec.ClusterVMExtensionNames = []string{"someExtension"}
+	param, err := spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+
+	// An extension added to the cluster is rolled out to the existing scale set.
+	spec.ClusterVMExtensionNames = []string{"someExtension", "AzureMonitorLinuxAgent"}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).NotTo(BeNil())
+
+	// An extension removed from the cluster is removed from the existing scale set.
+	spec.ClusterVMExtensionNames = nil
+	spec.RemovedVMExtensionNames = []string{"someExtension"}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).NotTo(BeNil())
+
+	spec.RemovedVMExtensionNames = []string{"AzureMonitorLinuxAgent"}
+	param, err = spec.Parameters(context.TODO(), existing)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(param).To(BeNil())
+}
+
+func TestScaleSetParametersSurge(t *testing.T) {
+	t.Run("model changes surge by at most the number of existing instances", func(t *testing.T) {
+		g := NewWithT(t)
+		spec, existing, _ := getExistingDefaultVMSS()
+		spec.MaxSurge = 5
+
+		param, err := spec.Parameters(context.TODO(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.SKU.Capacity).To(Equal(ptr.To[int64](4)))
+	})
+
+	t.Run("a partially rolled out model surges by at most the number of outdated instances", func(t *testing.T) {
+		g := NewWithT(t)
+		spec := newDefaultVMSSSpec()
+		spec.Capacity = 2
+		spec.MaxSurge = 2
+		spec.VMSSInstances = newDefaultInstances()
+		spec.VMSSInstances[1].Properties.StorageProfile.ImageReference.Version = ptr.To("0.9")
+		existing := newDefaultExistingVMSS("VM_SIZE")
+		existing.SKU.Capacity = ptr.To[int64](2)
+
+		param, err := spec.Parameters(context.TODO(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.SKU.Capacity).To(Equal(ptr.To[int64](3)))
+	})
+
+	t.Run("no surge when surge is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		spec, existing, _ := getExistingDefaultVMSS()
+		spec.MaxSurge = 0
+
+		param, err := spec.Parameters(context.TODO(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		vmss, ok := param.(armcompute.VirtualMachineScaleSet)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(vmss.SKU.Capacity).To(Equal(ptr.To[int64](2)))
+	})
+}
@@ -18,13 +18,23 @@ package scalesets
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
+// applicationHealthExtensionPublisher is the publisher of the application health extensions.
+const applicationHealthExtensionPublisher = "Microsoft.ManagedServices"
+
+// applicationHealthIntegerSettings are the settings of the application health extensions which Azure expects as
+// numbers, while the settings of an extension are strings.
+var applicationHealthIntegerSettings = []string{"port", "intervalInSeconds", "numberOfProbes", "gracePeriod"}
+
 // VMSSExtensionSpec defines the specification for a VM or VMScaleSet extension.
 type VMSSExtensionSpec struct {
 	azure.ExtensionSpec
@@ -64,8 +74,27 @@ func (s *VMSSExtensionSpec) Parameters(ctx context.Context, existing interface{}
 			Publisher:          ptr.To(s.Publisher),
 			Type:               ptr.To(s.Name),
 			TypeHandlerVersion: ptr.To(s.Version),
-			Settings:           s.Settings,
+			Settings:           s.settings(),
 			ProtectedSettings:  s.ProtectedSettings,
 		},
 	}, nil
 }
+
+// settings returns the settings of the VMSS extension, with the numeric settings of the application health extensions
+// converted to numbers.
+func (s *VMSSExtensionSpec) settings() interface{} {
+	if s.Publisher != applicationHealthExtensionPublisher || !strings.HasPrefix(s.Name, "ApplicationHealth") {
+		return s.Settings
+	}
+	settings := make(map[string]interface{}, len(s.Settings))
+	for key, value := range s.Settings {
+		settings[key] = value
+		if !slices.Contains(applicationHealthIntegerSettings, key) {
+			continue
+		}
+		if number, err := strconv.Atoi(value); err == nil {
+			settings[key] = number
+		}
+	}
+	return settings
+}
@@ -18,6 +18,7 @@ package scalesets
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
@@ -77,6 +78,25 @@ func TestVMSSExtensionParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "application health extension with numeric settings",
+			spec: &VMSSExtensionSpec{
+				ExtensionSpec: azure.ExtensionSpec{
+					Name:      "ApplicationHealthLinux",
+					VMName:    "my-vm",
+					Publisher: "Microsoft.ManagedServices",
+					Version:   "1.0",
+					Settings:  map[string]string{"protocol": "http", "port": "10248", "requestPath": "/healthz", "numberOfProbes": "2"},
+				},
+				ResourceGroup: "my-rg",
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachineScaleSetExtension{}))
+				settings, err := json.Marshal(result.(armcompute.VirtualMachineScaleSetExtension).Properties.Settings)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(settings).To(MatchJSON(`{"protocol": "http", "port": 10248, "requestPath": "/healthz", "numberOfProbes": 2}`))
+			},
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
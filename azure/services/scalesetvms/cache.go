@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesetvms
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
+)
+
+const (
+	// instanceCacheSize is the maximum number of scale sets whose instances are cached.
+	instanceCacheSize = 1024
+	// instanceCacheTTL is how long a scale set instance list is served to AzureMachinePoolMachine reconciles.
+	instanceCacheTTL = 1 * time.Minute
+)
+
+// InstanceCache stores the instances of uniform scale sets as listed by the AzureMachinePool reconciler so that
+// AzureMachinePoolMachine reconciles don't need to get each instance individually.
+type InstanceCache struct {
+	cache ttllru.PeekingCacher
+}
+
+type instanceCacheKey struct {
+	subscriptionID string
+	resourceGroup  string
+	scaleSetName   string
+}
+
+var (
+	instanceCacheOnce sync.Once
+	instanceCache     *InstanceCache
+	instanceCacheErr  error
+)
+
+// NewInstanceCache creates a new scale set instance cache.
+func NewInstanceCache(size int, ttl time.Duration) (*InstanceCache, error) {
+	cache, err := ttllru.New(size, ttl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating LRU cache for scale set instances")
+	}
+	return &InstanceCache{cache: cache}, nil
+}
+
+// GetInstanceCache either creates a new scale set instance cache or returns the existing one.
+func GetInstanceCache() (*InstanceCache, error) {
+	instanceCacheOnce.Do(func() {
+		instanceCache, instanceCacheErr = NewInstanceCache(instanceCacheSize, instanceCacheTTL)
+	})
+	return instanceCache, instanceCacheErr
+}
+
+func newInstanceCacheKey(subscriptionID, resourceGroup, scaleSetName string) instanceCacheKey {
+	return instanceCacheKey{
+		subscriptionID: strings.ToLower(subscriptionID),
+		resourceGroup:  strings.ToLower(resourceGroup),
+		scaleSetName:   strings.ToLower(scaleSetName),
+	}
+}
+
+// Set replaces the cached instances of a scale set.
+func (c *InstanceCache) Set(subscriptionID, resourceGroup, scaleSetName string, instances []armcompute.VirtualMachineScaleSetVM) {
+	byInstanceID := make(map[string]armcompute.VirtualMachineScaleSetVM, len(instances))
+	for _, instance := range instances {
+		if instance.InstanceID == nil {
+			continue
+		}
+		byInstanceID[*instance.InstanceID] = instance
+	}
+	c.cache.Add(newInstanceCacheKey(subscriptionID, resourceGroup, scaleSetName), byInstanceID)
+}
+
+// Get returns the cached instance of a scale set. It returns false if the scale set's instances aren't cached, have
+// expired, or don't include the instance.
+func (c *InstanceCache) Get(subscriptionID, resourceGroup, scaleSetName, instanceID string) (armcompute.VirtualMachineScaleSetVM, bool) {
+	// Peek rather than Get so that reads don't extend the lifetime of the entry.
+	val, _, ok := c.cache.Peek(newInstanceCacheKey(subscriptionID, resourceGroup, scaleSetName))
+	if !ok {
+		return armcompute.VirtualMachineScaleSetVM{}, false
+	}
+	byInstanceID, ok := val.(map[string]armcompute.VirtualMachineScaleSetVM)
+	if !ok {
+		return armcompute.VirtualMachineScaleSetVM{}, false
+	}
+	instance, ok := byInstanceID[instanceID]
+	return instance, ok
+}
+
+// Invalidate removes the cached instances of a scale set.
+func (c *InstanceCache) Invalidate(subscriptionID, resourceGroup, scaleSetName string) {
+	c.cache.Remove(newInstanceCacheKey(subscriptionID, resourceGroup, scaleSetName))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalesetvms
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms/mock_scalesetvms"
+)
+
+func newScaleSetInstances(count int) []armcompute.VirtualMachineScaleSetVM {
+	instances := make([]armcompute.VirtualMachineScaleSetVM, count)
+	for i := range instances {
+		instances[i] = armcompute.VirtualMachineScaleSetVM{
+			ID:         ptr.To("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/" + strconv.Itoa(i)),
+			InstanceID: ptr.To(strconv.Itoa(i)),
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				ProvisioningState: ptr.To("Succeeded"),
+			},
+		}
+	}
+	return instances
+}
+
+func TestInstanceCache(t *testing.T) {
+	g := NewWithT(t)
+
+	c, err := NewInstanceCache(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, ok := c.Get("123", "my-rg", "my-vmss", "0")
+	g.Expect(ok).To(BeFalse())
+
+	c.Set("123", "my-rg", "my-vmss", newScaleSetInstances(2))
+
+	instance, ok := c.Get("123", "MY-RG", "My-VMSS", "1")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(instance.InstanceID).To(Equal(ptr.To("1")))
+
+	_, ok = c.Get("123", "my-rg", "my-vmss", "2")
+	g.Expect(ok).To(BeFalse())
+
+	_, ok = c.Get("456", "my-rg", "my-vmss", "1")
+	g.Expect(ok).To(BeFalse())
+
+	c.Invalidate("123", "my-rg", "my-vmss")
+	_, ok = c.Get("123", "my-rg", "my-vmss", "1")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestInstanceCacheExpires(t *testing.T) {
+	g := NewWithT(t)
+
+	c, err := NewInstanceCache(10, 10*time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	c.Set("123", "my-rg", "my-vmss", newScaleSetInstances(1))
+	_, ok := c.Get("123", "my-rg", "my-vmss", "0")
+	g.Expect(ok).To(BeTrue())
+
+	g.Eventually(func() bool {
+		_, ok := c.Get("123", "my-rg", "my-vmss", "0")
+		return ok
+	}).WithTimeout(time.Second).Should(BeFalse())
+}
+
+// reconcileMachinePool reconciles an instance of each machine of a uniform scale set of the given size and returns
+// the number of instances fetched from Azure.
+func reconcileMachinePool(tb testing.TB, instanceCache *InstanceCache, size int) int {
+	tb.Helper()
+	mockCtrl := gomock.NewController(tb)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+	asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+	instances := newScaleSetInstances(size)
+	gets := 0
+	scopeMock.EXPECT().SubscriptionID().Return("123").AnyTimes()
+	scopeMock.EXPECT().SetVMSSVM(gomock.Any()).AnyTimes()
+	asyncMock.EXPECT().CreateOrUpdateResource(gomock.Any(), gomock.Any(), serviceName).DoAndReturn(
+		func(_ context.Context, spec azure.ResourceSpecGetter, _ string) (interface{}, error) {
+			gets++
+			instanceID, err := strconv.Atoi(spec.ResourceName())
+			if err != nil {
+				return nil, err
+			}
+			return instances[instanceID], nil
+		}).AnyTimes()
+
+	s := &Service{
+		Scope:         scopeMock,
+		Reconciler:    asyncMock,
+		instanceCache: instanceCache,
+	}
+	for i := 0; i < size; i++ {
+		scopeMock.EXPECT().ScaleSetVMSpec().Return(&ScaleSetVMSpec{
+			Name:          "my-vmss-" + strconv.Itoa(i),
+			InstanceID:    strconv.Itoa(i),
+			ResourceGroup: "my-rg",
+			ScaleSetName:  "my-vmss",
+		})
+		if err := s.Reconcile(context.TODO()); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return gets
+}
+
+func TestReconcileMachinePoolInstanceGets(t *testing.T) {
+	const size = 500
+
+	t.Run("without a cached instance list every machine gets its instance", func(t *testing.T) {
+		g := NewWithT(t)
+		c, err := NewInstanceCache(10, time.Minute)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(reconcileMachinePool(t, c, size)).To(Equal(size))
+	})
+
+	t.Run("with a cached instance list no machine gets its instance", func(t *testing.T) {
+		g := NewWithT(t)
+		c, err := NewInstanceCache(10, time.Minute)
+		g.Expect(err).NotTo(HaveOccurred())
+		// A single list of the scale set's instances by the AzureMachinePool reconciler.
+		c.Set("123", "my-rg", "my-vmss", newScaleSetInstances(size))
+
+		g.Expect(reconcileMachinePool(t, c, size)).To(Equal(0))
+	})
+}
+
+func BenchmarkReconcileMachinePool(b *testing.B) {
+	const size = 500
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c, err := NewInstanceCache(10, time.Minute)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(reconcileMachinePool(b, c, size)), "gets/sync")
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c, err := NewInstanceCache(10, time.Minute)
+			if err != nil {
+				b.Fatal(err)
+			}
+			c.Set("123", "my-rg", "my-vmss", newScaleSetInstances(size))
+			b.ReportMetric(float64(reconcileMachinePool(b, c, size)), "gets/sync")
+		}
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -33,6 +34,8 @@ type client interface {
 	Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
 	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, string, interface{}) (interface{}, *runtime.Poller[armcompute.VirtualMachineScaleSetVMsClientUpdateResponse], error)
 	DeleteAsync(context.Context, azure.ResourceSpecGetter, string) (*runtime.Poller[armcompute.VirtualMachineScaleSetVMsClientDeleteResponse], error)
+	Start(context.Context, azure.ResourceSpecGetter) error
+	UpdateProtectionPolicy(context.Context, azure.ResourceSpecGetter, armcompute.VirtualMachineScaleSetVM, bool) error
 }
 
 // azureClient contains the Azure go-sdk Client.
@@ -61,7 +64,11 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Get")
 	defer done()
 
-	resp, err := ac.scalesetvms.Get(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), nil)
+	var opts *armcompute.VirtualMachineScaleSetVMsClientGetOptions
+	if vmSpec, ok := spec.(*ScaleSetVMSpec); ok && vmSpec.ExpandInstanceView {
+		opts = &armcompute.VirtualMachineScaleSetVMsClientGetOptions{Expand: ptr.To(armcompute.InstanceViewTypesInstanceView)}
+	}
+	resp, err := ac.scalesetvms.Get(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +110,46 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return a nil poller.
 	return nil, err
 }
+
+// Start starts a deallocated virtual machine scale set instance. It returns once Azure accepted the operation, whose
+// progress is reported by the power state of the instance.
+func (ac *azureClient) Start(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Start")
+	defer done()
+
+	_, err := ac.scalesetvms.BeginStart(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), nil)
+	return err
+}
+
+// UpdateProtectionPolicy sets whether a virtual machine scale set instance is protected from scale-in, and waits for
+// the update to complete. The instance is the one returned by Get, since the update replaces the whole instance.
+func (ac *azureClient) UpdateProtectionPolicy(ctx context.Context, spec azure.ResourceSpecGetter, instance armcompute.VirtualMachineScaleSetVM, protectFromScaleIn bool) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.UpdateProtectionPolicy")
+	defer done()
+
+	var properties armcompute.VirtualMachineScaleSetVMProperties
+	if instance.Properties != nil {
+		properties = *instance.Properties
+	}
+	// The instance view is read-only.
+	properties.InstanceView = nil
+	policy := armcompute.VirtualMachineScaleSetVMProtectionPolicy{}
+	if properties.ProtectionPolicy != nil {
+		policy = *properties.ProtectionPolicy
+	}
+	policy.ProtectFromScaleIn = ptr.To(protectFromScaleIn)
+	properties.ProtectionPolicy = &policy
+	instance.Properties = &properties
+
+	poller, err := ac.scalesetvms.BeginUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), instance, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ac.apiCallTimeout)
+	defer cancel()
+
+	pollOpts := &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}
+	_, err = poller.PollUntilDone(ctx, pollOpts)
+	return err
+}
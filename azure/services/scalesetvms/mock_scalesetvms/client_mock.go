@@ -103,3 +103,31 @@ func (mr *MockclientMockRecorder) Get(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockclient)(nil).Get), arg0, arg1)
 }
+
+// Start mocks base method.
+func (m *Mockclient) Start(arg0 context.Context, arg1 azure.ResourceSpecGetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockclientMockRecorder) Start(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*Mockclient)(nil).Start), arg0, arg1)
+}
+
+// UpdateProtectionPolicy mocks base method.
+func (m *Mockclient) UpdateProtectionPolicy(arg0 context.Context, arg1 azure.ResourceSpecGetter, arg2 armcompute.VirtualMachineScaleSetVM, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProtectionPolicy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProtectionPolicy indicates an expected call of UpdateProtectionPolicy.
+func (mr *MockclientMockRecorder) UpdateProtectionPolicy(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProtectionPolicy", reflect.TypeOf((*Mockclient)(nil).UpdateProtectionPolicy), arg0, arg1, arg2, arg3)
+}
@@ -26,10 +26,12 @@ limitations under the License.
 package mock_scalesetvms
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
 	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	gomock "go.uber.org/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -225,6 +227,20 @@ func (mr *MockScaleSetVMScopeMockRecorder) DeleteLongRunningOperationState(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockScaleSetVMScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
 }
 
+// EvictedInstanceRestartInterval mocks base method.
+func (m *MockScaleSetVMScope) EvictedInstanceRestartInterval() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictedInstanceRestartInterval")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// EvictedInstanceRestartInterval indicates an expected call of EvictedInstanceRestartInterval.
+func (mr *MockScaleSetVMScopeMockRecorder) EvictedInstanceRestartInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictedInstanceRestartInterval", reflect.TypeOf((*MockScaleSetVMScope)(nil).EvictedInstanceRestartInterval))
+}
+
 // ExtendedLocation mocks base method.
 func (m *MockScaleSetVMScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
 	m.ctrl.T.Helper()
@@ -323,6 +339,18 @@ func (mr *MockScaleSetVMScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockScaleSetVMScope)(nil).Location))
 }
 
+// MarkEvicted mocks base method.
+func (m *MockScaleSetVMScope) MarkEvicted() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MarkEvicted")
+}
+
+// MarkEvicted indicates an expected call of MarkEvicted.
+func (mr *MockScaleSetVMScopeMockRecorder) MarkEvicted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEvicted", reflect.TypeOf((*MockScaleSetVMScope)(nil).MarkEvicted))
+}
+
 // NodeResourceGroup mocks base method.
 func (m *MockScaleSetVMScope) NodeResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -478,3 +506,130 @@ func (mr *MockScaleSetVMScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockScaleSetVMScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
 }
+
+// MockinstanceStarter is a mock of instanceStarter interface.
+type MockinstanceStarter struct {
+	ctrl     *gomock.Controller
+	recorder *MockinstanceStarterMockRecorder
+}
+
+// MockinstanceStarterMockRecorder is the mock recorder for MockinstanceStarter.
+type MockinstanceStarterMockRecorder struct {
+	mock *MockinstanceStarter
+}
+
+// NewMockinstanceStarter creates a new mock instance.
+func NewMockinstanceStarter(ctrl *gomock.Controller) *MockinstanceStarter {
+	mock := &MockinstanceStarter{ctrl: ctrl}
+	mock.recorder = &MockinstanceStarterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinstanceStarter) EXPECT() *MockinstanceStarterMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockinstanceStarter) Start(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockinstanceStarterMockRecorder) Start(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockinstanceStarter)(nil).Start), ctx, spec)
+}
+
+// MockevictionChecker is a mock of evictionChecker interface.
+type MockevictionChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockevictionCheckerMockRecorder
+}
+
+// MockevictionCheckerMockRecorder is the mock recorder for MockevictionChecker.
+type MockevictionCheckerMockRecorder struct {
+	mock *MockevictionChecker
+}
+
+// NewMockevictionChecker creates a new mock instance.
+func NewMockevictionChecker(ctrl *gomock.Controller) *MockevictionChecker {
+	mock := &MockevictionChecker{ctrl: ctrl}
+	mock.recorder = &MockevictionCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockevictionChecker) EXPECT() *MockevictionCheckerMockRecorder {
+	return m.recorder
+}
+
+// Evicted mocks base method.
+func (m *MockevictionChecker) Evicted(ctx context.Context, resourceURI string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evicted", ctx, resourceURI)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evicted indicates an expected call of Evicted.
+func (mr *MockevictionCheckerMockRecorder) Evicted(ctx, resourceURI any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evicted", reflect.TypeOf((*MockevictionChecker)(nil).Evicted), ctx, resourceURI)
+}
+
+// MockinstanceProtector is a mock of instanceProtector interface.
+type MockinstanceProtector struct {
+	ctrl     *gomock.Controller
+	recorder *MockinstanceProtectorMockRecorder
+}
+
+// MockinstanceProtectorMockRecorder is the mock recorder for MockinstanceProtector.
+type MockinstanceProtectorMockRecorder struct {
+	mock *MockinstanceProtector
+}
+
+// NewMockinstanceProtector creates a new mock instance.
+func NewMockinstanceProtector(ctrl *gomock.Controller) *MockinstanceProtector {
+	mock := &MockinstanceProtector{ctrl: ctrl}
+	mock.recorder = &MockinstanceProtectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinstanceProtector) EXPECT() *MockinstanceProtectorMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockinstanceProtector) Get(arg0 context.Context, arg1 azure.ResourceSpecGetter) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockinstanceProtectorMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockinstanceProtector)(nil).Get), arg0, arg1)
+}
+
+// UpdateProtectionPolicy mocks base method.
+func (m *MockinstanceProtector) UpdateProtectionPolicy(arg0 context.Context, arg1 azure.ResourceSpecGetter, arg2 armcompute.VirtualMachineScaleSetVM, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProtectionPolicy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProtectionPolicy indicates an expected call of UpdateProtectionPolicy.
+func (mr *MockinstanceProtectorMockRecorder) UpdateProtectionPolicy(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProtectionPolicy", reflect.TypeOf((*MockinstanceProtector)(nil).UpdateProtectionPolicy), arg0, arg1, arg2, arg3)
+}
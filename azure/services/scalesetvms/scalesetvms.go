@@ -19,14 +19,18 @@ package scalesetvms
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -42,13 +46,37 @@ type (
 		ScaleSetVMSpec() azure.ResourceSpecGetter
 		SetVMSSVM(vmssvm *azure.VMSSVM)
 		SetVMSSVMState(state infrav1.ProvisioningState)
+		EvictedInstanceRestartInterval() time.Duration
+		MarkEvicted()
+	}
+
+	// instanceStarter starts deallocated instances.
+	instanceStarter interface {
+		Start(ctx context.Context, spec azure.ResourceSpecGetter) error
+	}
+
+	// evictionChecker tells whether a deallocated instance was evicted by Azure rather than deallocated on purpose.
+	evictionChecker interface {
+		Evicted(ctx context.Context, resourceURI string) (bool, error)
+	}
+
+	// instanceProtector gets the instances of uniform scale sets and updates their protection from scale-in.
+	instanceProtector interface {
+		Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
+		UpdateProtectionPolicy(context.Context, azure.ResourceSpecGetter, armcompute.VirtualMachineScaleSetVM, bool) error
 	}
 
 	// Service provides operations on Azure resources.
 	Service struct {
 		Scope ScaleSetVMScope
 		async.Reconciler
-		VMReconciler async.Reconciler
+		VMReconciler  async.Reconciler
+		instanceCache *InstanceCache
+		// instanceStarter starts the instances of uniform scale sets, and vmStarter the VMs of flexible scale sets.
+		instanceStarter   instanceStarter
+		vmStarter         instanceStarter
+		instanceProtector instanceProtector
+		evictionChecker   evictionChecker
 	}
 )
 
@@ -62,12 +90,25 @@ func NewService(scope ScaleSetVMScope) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	instanceCache, err := GetInstanceCache()
+	if err != nil {
+		return nil, err
+	}
+	evictionChecker, err := resourcehealth.NewEvictionChecker(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Reconciler: async.New[armcompute.VirtualMachineScaleSetVMsClientUpdateResponse,
 			armcompute.VirtualMachineScaleSetVMsClientDeleteResponse](scope, client, client),
 		VMReconciler: async.New[armcompute.VirtualMachinesClientCreateOrUpdateResponse,
 			armcompute.VirtualMachinesClientDeleteResponse](scope, vmClient, vmClient),
-		Scope: scope,
+		Scope:             scope,
+		instanceCache:     instanceCache,
+		instanceStarter:   client,
+		vmStarter:         vmClient,
+		instanceProtector: client,
+		evictionChecker:   evictionChecker,
 	}, nil
 }
 
@@ -101,6 +142,18 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		reconciler = s.VMReconciler
 	} else {
 		log.V(4).Info("VMSS is uniform", "vmssName", scaleSetVMSpec.Name, "providerID", scaleSetVMSpec.ProviderID, "instanceID", scaleSetVMSpec.InstanceID)
+		// Prefer the instance list fetched by the AzureMachinePool reconciler over getting every instance individually.
+		if s.instanceCache != nil {
+			if instance, ok := s.instanceCache.Get(s.Scope.SubscriptionID(), scaleSetVMSpec.ResourceGroup, scaleSetVMSpec.ScaleSetName, scaleSetVMSpec.InstanceID); ok {
+				log.V(4).Info("using cached VMSS instance", "vmssName", scaleSetVMSpec.ScaleSetName, "instanceID", scaleSetVMSpec.InstanceID)
+				vmssVM := converters.SDKToVMSSVM(instance)
+				s.Scope.SetVMSSVM(vmssVM)
+				if err := s.reconcileInstanceProtection(ctx, scaleSetVMSpec, instance); err != nil {
+					return err
+				}
+				return s.restartEvictedInstance(ctx, scaleSetVMSpec, getter, vmssVM)
+			}
+		}
 	}
 
 	// We only want to get the resource if it exists and handle the not found error.
@@ -117,18 +170,132 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		if !ok {
 			return errors.Errorf("%T is not of type armcompute.VirtualMachine", result)
 		}
-		s.Scope.SetVMSSVM(converters.SDKVMToVMSSVM(vm, infrav1.FlexibleOrchestrationMode))
-	} else {
-		instance, ok := result.(armcompute.VirtualMachineScaleSetVM)
-		if !ok {
-			return errors.Errorf("%T is not of type armcompute.VirtualMachineScaleSetVM", result)
-		}
-		s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+		vmssVM := converters.SDKVMToVMSSVM(vm, infrav1.FlexibleOrchestrationMode)
+		s.Scope.SetVMSSVM(vmssVM)
+		return s.restartEvictedInstance(ctx, scaleSetVMSpec, getter, vmssVM)
 	}
 
+	instance, ok := result.(armcompute.VirtualMachineScaleSetVM)
+	if !ok {
+		return errors.Errorf("%T is not of type armcompute.VirtualMachineScaleSetVM", result)
+	}
+	vmssVM := converters.SDKToVMSSVM(instance)
+	s.Scope.SetVMSSVM(vmssVM)
+	if err := s.reconcileInstanceProtection(ctx, scaleSetVMSpec, instance); err != nil {
+		return err
+	}
+
+	return s.restartEvictedInstance(ctx, scaleSetVMSpec, getter, vmssVM)
+}
+
+// reconcileInstanceProtection protects the instance of a uniform scale set from scale-in, or removes its protection, as
+// requested by the spec. Instances being deleted are left as they are.
+func (s *Service) reconcileInstanceProtection(ctx context.Context, scaleSetVMSpec *ScaleSetVMSpec, instance armcompute.VirtualMachineScaleSetVM) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.reconcileInstanceProtection")
+	defer done()
+
+	if isProtectedFromScaleIn(instance) == scaleSetVMSpec.ProtectFromScaleIn || isDeleting(instance) {
+		return nil
+	}
+
+	log.V(2).Info("updating the protection of the instance from scale-in", "vmssName", scaleSetVMSpec.ScaleSetName, "instanceID", scaleSetVMSpec.InstanceID, "protectFromScaleIn", scaleSetVMSpec.ProtectFromScaleIn)
+	err := s.instanceProtector.UpdateProtectionPolicy(ctx, scaleSetVMSpec, instance, scaleSetVMSpec.ProtectFromScaleIn)
+	if s.instanceCache != nil {
+		// The cached instance list of the scale set no longer reflects the protection of the instance.
+		s.instanceCache.Invalidate(s.Scope.SubscriptionID(), scaleSetVMSpec.ResourceGroup, scaleSetVMSpec.ScaleSetName)
+	}
+	if isInstanceDeletingError(err) {
+		// Azure refuses to update an instance which is already being deleted, e.g. by a scale-in.
+		log.V(2).Info("skipping the protection update of an instance being deleted", "vmssName", scaleSetVMSpec.ScaleSetName, "instanceID", scaleSetVMSpec.InstanceID)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the protection from scale-in of instance %s", scaleSetVMSpec.ProviderID)
+	}
 	return nil
 }
 
+// removeInstanceProtection removes the protection from scale-in of the instance of a uniform scale set before it is
+// deleted, since the scale set can't remove a protected instance when scaling in.
+func (s *Service) removeInstanceProtection(ctx context.Context, scaleSetVMSpec *ScaleSetVMSpec) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.removeInstanceProtection")
+	defer done()
+
+	existing, err := s.instanceProtector.Get(ctx, scaleSetVMSpec)
+	if azure.ResourceNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get instance %s", scaleSetVMSpec.ProviderID)
+	}
+	instance, ok := existing.(armcompute.VirtualMachineScaleSetVM)
+	if !ok {
+		return errors.Errorf("%T is not of type armcompute.VirtualMachineScaleSetVM", existing)
+	}
+	spec := *scaleSetVMSpec
+	spec.ProtectFromScaleIn = false
+	return s.reconcileInstanceProtection(ctx, &spec, instance)
+}
+
+// isProtectedFromScaleIn returns true if the instance of a uniform scale set is protected from scale-in.
+func isProtectedFromScaleIn(instance armcompute.VirtualMachineScaleSetVM) bool {
+	if instance.Properties == nil || instance.Properties.ProtectionPolicy == nil {
+		return false
+	}
+	return ptr.Deref(instance.Properties.ProtectionPolicy.ProtectFromScaleIn, false)
+}
+
+// isDeleting returns true if the instance of a uniform scale set is being deleted.
+func isDeleting(instance armcompute.VirtualMachineScaleSetVM) bool {
+	return instance.Properties != nil && ptr.Deref(instance.Properties.ProvisioningState, "") == string(infrav1.Deleting)
+}
+
+// isInstanceDeletingError returns true if Azure refused to update an instance because it is being deleted, or is
+// already deleted.
+func isInstanceDeletingError(err error) bool {
+	var rerr *azcore.ResponseError
+	return azure.ResourceNotFound(err) || (errors.As(err, &rerr) && rerr.StatusCode == http.StatusConflict)
+}
+
+// restartEvictedInstance starts an instance deallocated by a Spot eviction, and returns a transient error to check it
+// again after the restart interval of the machine pool, as Azure may not have the capacity to run it yet. Instances are
+// only restarted when the Spot VMs of the machine pool use the Deallocate eviction policy, and when the availability
+// status of the instance shows that it was deallocated by Azure rather than by a user or a process of the subscription.
+func (s *Service) restartEvictedInstance(ctx context.Context, scaleSetVMSpec *ScaleSetVMSpec, getter azure.ResourceSpecGetter, vmssVM *azure.VMSSVM) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.restartEvictedInstance")
+	defer done()
+
+	if vmssVM.PowerState != infrav1.VMPowerStateDeallocated {
+		return nil
+	}
+	interval := s.Scope.EvictedInstanceRestartInterval()
+	if interval <= 0 {
+		return nil
+	}
+	evicted, err := s.evictionChecker.Evicted(ctx, vmssVM.ID)
+	if err != nil {
+		return azure.WithTransientError(errors.Wrapf(err, "failed to check whether deallocated instance %s was evicted", scaleSetVMSpec.ProviderID), interval)
+	}
+	if !evicted {
+		log.V(2).Info("not restarting deallocated instance which wasn't evicted", "vmssName", scaleSetVMSpec.ScaleSetName, "providerID", scaleSetVMSpec.ProviderID)
+		return nil
+	}
+
+	s.Scope.MarkEvicted()
+	starter := s.instanceStarter
+	if scaleSetVMSpec.IsFlex {
+		starter = s.vmStarter
+	} else if s.instanceCache != nil {
+		// The cached instance list of the scale set no longer reflects the power state of the instance.
+		s.instanceCache.Invalidate(s.Scope.SubscriptionID(), scaleSetVMSpec.ResourceGroup, scaleSetVMSpec.ScaleSetName)
+	}
+	log.V(2).Info("restarting evicted Spot instance", "vmssName", scaleSetVMSpec.ScaleSetName, "providerID", scaleSetVMSpec.ProviderID)
+	if err := starter.Start(ctx, getter); err != nil {
+		return azure.WithTransientError(errors.Wrapf(err, "failed to restart evicted instance %s", scaleSetVMSpec.ProviderID), interval)
+	}
+	return azure.WithTransientError(errors.Errorf("restarting evicted instance %s", scaleSetVMSpec.ProviderID), interval)
+}
+
 // Delete deletes a scaleset instance asynchronously returning a future which encapsulates the long-running operation.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.Delete")
@@ -150,9 +317,15 @@ func (s *Service) Delete(ctx context.Context) error {
 			return errors.Wrap(err, "failed to convert scaleSetVMSpec to vmSpec")
 		}
 		reconciler = s.VMReconciler
+	} else if err := s.removeInstanceProtection(ctx, scaleSetVMSpec); err != nil {
+		return err
 	}
 
 	err = reconciler.DeleteResource(ctx, getter, serviceName)
+	if !scaleSetVMSpec.IsFlex && s.instanceCache != nil {
+		// The cached instance list of the scale set no longer reflects the instances being deleted.
+		s.instanceCache.Invalidate(s.Scope.SubscriptionID(), scaleSetVMSpec.ResourceGroup, scaleSetVMSpec.ScaleSetName)
+	}
 	if err != nil {
 		s.Scope.SetVMSSVMState(infrav1.Deleting)
 	} else {
@@ -18,16 +18,20 @@ package scalesetvms
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms/mock_scalesetvms"
@@ -48,6 +52,15 @@ var (
 		ID: &uniformScaleSetVMSpec.ResourceID,
 	}
 
+	protectedScaleSetVM = armcompute.VirtualMachineScaleSetVM{
+		ID: &uniformScaleSetVMSpec.ResourceID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			ProtectionPolicy: &armcompute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn: ptr.To(true),
+			},
+		},
+	}
+
 	flexScaleSetVMSpec = &ScaleSetVMSpec{
 		Name:          "my-vmss",
 		InstanceID:    "0",
@@ -75,6 +88,17 @@ func errInternal() *azcore.ResponseError {
 	}
 }
 
+func errConflict() *azcore.ResponseError {
+	return &azcore.ResponseError{
+		ErrorCode: "OperationNotAllowed",
+		RawResponse: &http.Response{
+			Body:       io.NopCloser(strings.NewReader("#: Operation not allowed since the instance is being deleted: StatusCode=409")),
+			StatusCode: http.StatusConflict,
+		},
+		StatusCode: http.StatusConflict,
+	}
+}
+
 func TestReconcileVMSS(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -164,17 +188,402 @@ func TestReconcileVMSS(t *testing.T) {
 	}
 }
 
+func TestReconcileVMSSInstanceCache(t *testing.T) {
+	cachedInstance := armcompute.VirtualMachineScaleSetVM{
+		ID:         &uniformScaleSetVMSpec.ResourceID,
+		InstanceID: &uniformScaleSetVMSpec.InstanceID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			ProvisioningState: ptr.To("Succeeded"),
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		cached []armcompute.VirtualMachineScaleSetVM
+		expect func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:   "uses the cached instance of a uniform vmss",
+			cached: []armcompute.VirtualMachineScaleSetVM{cachedInstance},
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				s.SubscriptionID().Return("123")
+				s.SetVMSSVM(converters.SDKToVMSSVM(cachedInstance))
+			},
+		},
+		{
+			name: "gets the instance if it isn't cached",
+			cached: []armcompute.VirtualMachineScaleSetVM{
+				{InstanceID: ptr.To("1")},
+			},
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				s.SubscriptionID().Return("123")
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(uniformScaleSetVM, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(uniformScaleSetVM))
+			},
+		},
+		{
+			name: "gets the instance if the instances of the vmss aren't cached",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				s.SubscriptionID().Return("123")
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(uniformScaleSetVM, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(uniformScaleSetVM))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			instanceCache, err := NewInstanceCache(10, time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.cached != nil {
+				instanceCache.Set("123", uniformScaleSetVMSpec.ResourceGroup, uniformScaleSetVMSpec.ScaleSetName, tc.cached)
+			}
+
+			s := &Service{
+				Scope:         scopeMock,
+				Reconciler:    asyncMock,
+				instanceCache: instanceCache,
+			}
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
+func TestReconcileEvictedVMSSVM(t *testing.T) {
+	deallocated := &armcompute.InstanceViewStatus{Code: ptr.To("PowerState/deallocated")}
+	evictedInstance := armcompute.VirtualMachineScaleSetVM{
+		ID:         &uniformScaleSetVMSpec.ResourceID,
+		InstanceID: &uniformScaleSetVMSpec.InstanceID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			ProvisioningState: ptr.To("Succeeded"),
+			InstanceView:      &armcompute.VirtualMachineScaleSetVMInstanceView{Statuses: []*armcompute.InstanceViewStatus{deallocated}},
+		},
+	}
+	evictedVM := armcompute.VirtualMachine{
+		ID:   &flexScaleSetVMSpec.ResourceID,
+		Name: &flexScaleSetVMSpec.Name,
+		Properties: &armcompute.VirtualMachineProperties{
+			ProvisioningState: ptr.To("Succeeded"),
+			InstanceView:      &armcompute.VirtualMachineInstanceView{Statuses: []*armcompute.InstanceViewStatus{deallocated}},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "restarts an evicted uniform vmss vm",
+			expectedError: "restarting evicted instance " + uniformScaleSetVMSpec.ProviderID + ". Object will be requeued after 5m0s",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(evictedInstance, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+				s.EvictedInstanceRestartInterval().Return(5 * time.Minute)
+				e.Evicted(gomockinternal.AContext(), uniformScaleSetVMSpec.ResourceID).Return(true, nil)
+				s.MarkEvicted()
+				u.Start(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(nil)
+			},
+		},
+		{
+			name:          "restarts an evicted vmss flex vm",
+			expectedError: "restarting evicted instance " + flexScaleSetVMSpec.ProviderID + ". Object will be requeued after 1m0s",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(flexScaleSetVMSpec)
+				v.CreateOrUpdateResource(gomockinternal.AContext(), flexGetter, serviceName).Return(evictedVM, nil)
+				s.SetVMSSVM(converters.SDKVMToVMSSVM(evictedVM, infrav1.FlexibleOrchestrationMode))
+				s.EvictedInstanceRestartInterval().Return(time.Minute)
+				e.Evicted(gomockinternal.AContext(), flexScaleSetVMSpec.ResourceID).Return(true, nil)
+				s.MarkEvicted()
+				f.Start(gomockinternal.AContext(), flexGetter).Return(nil)
+			},
+		},
+		{
+			name:          "retries restarting an evicted vmss vm without capacity",
+			expectedError: "failed to restart evicted instance " + uniformScaleSetVMSpec.ProviderID,
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(evictedInstance, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+				s.EvictedInstanceRestartInterval().Return(5 * time.Minute)
+				e.Evicted(gomockinternal.AContext(), uniformScaleSetVMSpec.ResourceID).Return(true, nil)
+				s.MarkEvicted()
+				u.Start(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(errInternal())
+			},
+		},
+		{
+			name: "doesn't restart a vmss vm deallocated on purpose",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(evictedInstance, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+				s.EvictedInstanceRestartInterval().Return(5 * time.Minute)
+				e.Evicted(gomockinternal.AContext(), uniformScaleSetVMSpec.ResourceID).Return(false, nil)
+			},
+		},
+		{
+			name:          "retries checking whether a deallocated vmss vm was evicted",
+			expectedError: "failed to check whether deallocated instance " + uniformScaleSetVMSpec.ProviderID + " was evicted",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(evictedInstance, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+				s.EvictedInstanceRestartInterval().Return(5 * time.Minute)
+				e.Evicted(gomockinternal.AContext(), uniformScaleSetVMSpec.ResourceID).Return(false, errInternal())
+			},
+		},
+		{
+			name: "doesn't restart a deallocated vmss vm without the Deallocate eviction policy",
+			expect: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, u *mock_scalesetvms.MockclientMockRecorder, f *mock_scalesetvms.MockinstanceStarterMockRecorder, e *mock_scalesetvms.MockevictionCheckerMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(evictedInstance, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+				s.EvictedInstanceRestartInterval().Return(time.Duration(0))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			vmAsyncMock := mock_async.NewMockReconciler(mockCtrl)
+			clientMock := mock_scalesetvms.NewMockclient(mockCtrl)
+			vmStarterMock := mock_scalesetvms.NewMockinstanceStarter(mockCtrl)
+			evictionCheckerMock := mock_scalesetvms.NewMockevictionChecker(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT(), vmAsyncMock.EXPECT(), clientMock.EXPECT(), vmStarterMock.EXPECT(), evictionCheckerMock.EXPECT())
+
+			s := &Service{
+				Scope:           scopeMock,
+				Reconciler:      asyncMock,
+				VMReconciler:    vmAsyncMock,
+				instanceStarter: clientMock,
+				vmStarter:       vmStarterMock,
+				evictionChecker: evictionCheckerMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				var reconcileErr azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+				g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileEvictedVMSSVMInvalidatesInstanceCache(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	evictedInstance := armcompute.VirtualMachineScaleSetVM{
+		ID:         &uniformScaleSetVMSpec.ResourceID,
+		InstanceID: &uniformScaleSetVMSpec.InstanceID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{{Code: ptr.To("PowerState/deallocated")}},
+			},
+		},
+	}
+	scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+	clientMock := mock_scalesetvms.NewMockclient(mockCtrl)
+	evictionCheckerMock := mock_scalesetvms.NewMockevictionChecker(mockCtrl)
+	scopeMock.EXPECT().ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+	scopeMock.EXPECT().SubscriptionID().Return("123").Times(2)
+	scopeMock.EXPECT().SetVMSSVM(converters.SDKToVMSSVM(evictedInstance))
+	scopeMock.EXPECT().EvictedInstanceRestartInterval().Return(5 * time.Minute)
+	evictionCheckerMock.EXPECT().Evicted(gomockinternal.AContext(), uniformScaleSetVMSpec.ResourceID).Return(true, nil)
+	scopeMock.EXPECT().MarkEvicted()
+	clientMock.EXPECT().Start(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(nil)
+
+	instanceCache, err := NewInstanceCache(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	instanceCache.Set("123", uniformScaleSetVMSpec.ResourceGroup, uniformScaleSetVMSpec.ScaleSetName, []armcompute.VirtualMachineScaleSetVM{evictedInstance})
+
+	s := &Service{
+		Scope:           scopeMock,
+		instanceCache:   instanceCache,
+		instanceStarter: clientMock,
+		evictionChecker: evictionCheckerMock,
+	}
+
+	var reconcileErr azure.ReconcileError
+	g.Expect(errors.As(s.Reconcile(context.TODO()), &reconcileErr)).To(BeTrue())
+	g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+	_, ok := instanceCache.Get("123", uniformScaleSetVMSpec.ResourceGroup, uniformScaleSetVMSpec.ScaleSetName, uniformScaleSetVMSpec.InstanceID)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestReconcileVMSSInstanceProtection(t *testing.T) {
+	protectedSpec := *uniformScaleSetVMSpec
+	protectedSpec.ProtectFromScaleIn = true
+	deletingScaleSetVM := armcompute.VirtualMachineScaleSetVM{
+		ID: &uniformScaleSetVMSpec.ResourceID,
+		Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+			ProvisioningState: ptr.To(string(infrav1.Deleting)),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		spec          *ScaleSetVMSpec
+		instance      armcompute.VirtualMachineScaleSetVM
+		expect        func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder)
+		expectedError string
+	}{
+		{
+			name:     "protect a uniform vmss vm from scale-in",
+			spec:     &protectedSpec,
+			instance: uniformScaleSetVM,
+			expect: func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), &protectedSpec, uniformScaleSetVM, true).Return(nil)
+			},
+		},
+		{
+			name:     "remove the protection from scale-in of a uniform vmss vm",
+			spec:     uniformScaleSetVMSpec,
+			instance: protectedScaleSetVM,
+			expect: func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), uniformScaleSetVMSpec, protectedScaleSetVM, false).Return(nil)
+			},
+		},
+		{
+			name:     "uniform vmss vm already protected from scale-in",
+			spec:     &protectedSpec,
+			instance: protectedScaleSetVM,
+			expect:   func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {},
+		},
+		{
+			name:     "uniform vmss vm being deleted",
+			spec:     &protectedSpec,
+			instance: deletingScaleSetVM,
+			expect:   func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {},
+		},
+		{
+			name:     "uniform vmss vm deleted while protecting it",
+			spec:     &protectedSpec,
+			instance: uniformScaleSetVM,
+			expect: func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), &protectedSpec, uniformScaleSetVM, true).Return(errConflict())
+			},
+		},
+		{
+			name:     "error when protecting a uniform vmss vm",
+			spec:     &protectedSpec,
+			instance: uniformScaleSetVM,
+			expect: func(p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), &protectedSpec, uniformScaleSetVM, true).Return(errInternal())
+			},
+			expectedError: "failed to update the protection from scale-in",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			protectorMock := mock_scalesetvms.NewMockinstanceProtector(mockCtrl)
+
+			scopeMock.EXPECT().ScaleSetVMSpec().Return(tc.spec)
+			asyncMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), tc.spec, serviceName).Return(tc.instance, nil)
+			scopeMock.EXPECT().SetVMSSVM(converters.SDKToVMSSVM(tc.instance))
+			scopeMock.EXPECT().EvictedInstanceRestartInterval().Return(time.Duration(0)).AnyTimes()
+			tc.expect(protectorMock.EXPECT())
+
+			s := &Service{
+				Scope:             scopeMock,
+				Reconciler:        asyncMock,
+				instanceProtector: protectorMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteVMSSInvalidatesInstanceCache(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
+	asyncMock := mock_async.NewMockReconciler(mockCtrl)
+	protectorMock := mock_scalesetvms.NewMockinstanceProtector(mockCtrl)
+
+	scopeMock.EXPECT().ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+	protectorMock.EXPECT().Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(uniformScaleSetVM, nil)
+	scopeMock.EXPECT().SubscriptionID().Return("123")
+	asyncMock.EXPECT().DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(nil)
+	scopeMock.EXPECT().SetVMSSVMState(infrav1.Deleted)
+
+	instanceCache, err := NewInstanceCache(10, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	instanceCache.Set("123", uniformScaleSetVMSpec.ResourceGroup, uniformScaleSetVMSpec.ScaleSetName, []armcompute.VirtualMachineScaleSetVM{
+		{InstanceID: ptr.To("1")},
+	})
+
+	s := &Service{
+		Scope:             scopeMock,
+		Reconciler:        asyncMock,
+		instanceCache:     instanceCache,
+		instanceProtector: protectorMock,
+	}
+
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	_, ok := instanceCache.Get("123", uniformScaleSetVMSpec.ResourceGroup, uniformScaleSetVMSpec.ScaleSetName, "1")
+	g.Expect(ok).To(BeFalse())
+}
+
 func TestDeleteVMSS(t *testing.T) {
 	testcases := []struct {
 		name          string
-		expect        func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder)
+		expect        func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder)
 		expectedError string
 	}{
 		{
 			name:          "delete a uniform vmss vm",
 			expectedError: "",
-			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
 				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(uniformScaleSetVM, nil)
 				r.DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(nil)
 				s.SetVMSSVMState(infrav1.Deleted)
 			},
@@ -182,17 +591,59 @@ func TestDeleteVMSS(t *testing.T) {
 		{
 			name:          "delete a vmss flex vm",
 			expectedError: "",
-			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
 				s.ScaleSetVMSpec().Return(flexScaleSetVMSpec)
 				v.DeleteResource(gomockinternal.AContext(), flexGetter, serviceName).Return(nil)
 				s.SetVMSSVMState(infrav1.Deleted)
 			},
 		},
+		{
+			name:          "remove the protection from scale-in of a uniform vmss vm before deleting it",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(protectedScaleSetVM, nil)
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), uniformScaleSetVMSpec, protectedScaleSetVM, false).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(nil)
+				s.SetVMSSVMState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "delete a uniform vmss vm already being deleted",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(protectedScaleSetVM, nil)
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), uniformScaleSetVMSpec, protectedScaleSetVM, false).Return(errConflict())
+				r.DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(nil)
+				s.SetVMSSVMState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "delete a uniform vmss vm which no longer exists",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+				r.DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(nil)
+				s.SetVMSSVMState(infrav1.Deleted)
+			},
+		},
+		{
+			name:          "error when removing the protection from scale-in of a uniform vmss vm",
+			expectedError: "failed to update the protection from scale-in",
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
+				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(protectedScaleSetVM, nil)
+				p.UpdateProtectionPolicy(gomockinternal.AContext(), uniformScaleSetVMSpec, protectedScaleSetVM, false).Return(errInternal())
+			},
+		},
 		{
 			name:          "error when deleting a uniform vmss vm",
 			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
 				s.ScaleSetVMSpec().Return(uniformScaleSetVMSpec)
+				p.Get(gomockinternal.AContext(), uniformScaleSetVMSpec).Return(uniformScaleSetVM, nil)
 				r.DeleteResource(gomockinternal.AContext(), uniformScaleSetVMSpec, serviceName).Return(errInternal())
 				s.SetVMSSVMState(infrav1.Deleting)
 			},
@@ -200,7 +651,7 @@ func TestDeleteVMSS(t *testing.T) {
 		{
 			name:          "error when deleting a vmss flex vm",
 			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder) {
+			expect: func(g *WithT, s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder, v *mock_async.MockReconcilerMockRecorder, p *mock_scalesetvms.MockinstanceProtectorMockRecorder) {
 				s.ScaleSetVMSpec().Return(flexScaleSetVMSpec)
 				v.DeleteResource(gomockinternal.AContext(), flexGetter, serviceName).Return(errInternal())
 				s.SetVMSSVMState(infrav1.Deleting)
@@ -219,13 +670,15 @@ func TestDeleteVMSS(t *testing.T) {
 			scopeMock := mock_scalesetvms.NewMockScaleSetVMScope(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
 			vmAsyncMock := mock_async.NewMockReconciler(mockCtrl)
+			protectorMock := mock_scalesetvms.NewMockinstanceProtector(mockCtrl)
 
-			tc.expect(g, scopeMock.EXPECT(), asyncMock.EXPECT(), vmAsyncMock.EXPECT())
+			tc.expect(g, scopeMock.EXPECT(), asyncMock.EXPECT(), vmAsyncMock.EXPECT(), protectorMock.EXPECT())
 
 			s := &Service{
-				Scope:        scopeMock,
-				Reconciler:   asyncMock,
-				VMReconciler: vmAsyncMock,
+				Scope:             scopeMock,
+				Reconciler:        asyncMock,
+				VMReconciler:      vmAsyncMock,
+				instanceProtector: protectorMock,
 			}
 
 			err := s.Delete(context.TODO())
@@ -29,6 +29,10 @@ type ScaleSetVMSpec struct {
 	ProviderID    string
 	ResourceID    string
 	IsFlex        bool
+	// ProtectFromScaleIn is whether the instance of a Uniform scale set is protected from scale-in.
+	ProtectFromScaleIn bool
+	// ExpandInstanceView is whether the instance of a Uniform scale set is fetched with its instance view.
+	ExpandInstanceView bool
 }
 
 // ResourceName returns the instance ID of the VMSS VM. This is because the it is identified by the instance ID in Azure instead of the name.
@@ -33,6 +33,7 @@ const serviceName = "securitygroups"
 type NSGScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	NSGSpecs() []azure.ResourceSpecGetter
 	IsVnetManaged() bool
 	UpdateAnnotationJSON(string, map[string]interface{}) error
@@ -42,6 +43,7 @@ type NSGScope interface {
 type Service struct {
 	Scope NSGScope
 	async.Reconciler
+	async.Getter
 }
 
 // New creates a new service.
@@ -51,7 +53,8 @@ func New(scope NSGScope) (*Service, error) {
 		return nil, err
 	}
 	return &Service{
-		Scope: scope,
+		Scope:  scope,
+		Getter: client,
 		Reconciler: async.New[armnetwork.SecurityGroupsClientCreateOrUpdateResponse,
 			armnetwork.SecurityGroupsClientDeleteResponse](scope, client, client),
 	}, nil
@@ -86,6 +89,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	var resErr error
 
 	newAnnotation := make(map[string]interface{})
+	recordOwnership := s.Scope.IsOwnershipRecorded()
 
 	// We go through the list of security groups to reconcile each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
@@ -94,7 +98,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		nsgSpec := resourceSpec.(*NSGSpec)
 		currentAnnotation := make(map[string]string)
 
-		if _, err := s.CreateOrUpdateResource(ctx, nsgSpec, serviceName); err != nil {
+		var err error
+		if recordOwnership {
+			err = async.RecordManagedResourceIfNotFound(ctx, s.Scope, s.Getter, nsgSpec, s.securityGroupID(nsgSpec))
+		}
+		if err != nil {
+			resErr = err
+		} else if _, err := s.CreateOrUpdateResource(ctx, nsgSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || resErr == nil {
 				resErr = err
 			}
@@ -144,10 +154,16 @@ func (s *Service) Delete(ctx context.Context) error {
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
 	for _, nsgSpec := range specs {
+		if !s.Scope.IsManagedResource(s.securityGroupID(nsgSpec), true) {
+			log.V(2).Info("Skipping network security group deletion for security group not created by CAPZ", "security group", nsgSpec.ResourceName())
+			continue
+		}
 		if err := s.DeleteResource(ctx, nsgSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
+		} else {
+			s.Scope.ForgetManagedResource(s.securityGroupID(nsgSpec))
 		}
 	}
 
@@ -162,3 +178,8 @@ func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 
 	return s.Scope.IsVnetManaged(), nil
 }
+
+// securityGroupID returns the Azure resource ID of the network security group.
+func (s *Service) securityGroupID(spec azure.ResourceSpecGetter) string {
+	return azure.SecurityGroupID(s.Scope.SubscriptionID(), spec.ResourceGroupName(), spec.ResourceName())
+}
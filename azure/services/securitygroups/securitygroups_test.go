@@ -102,6 +102,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{fakeNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}}).Times(1)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
@@ -114,6 +115,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&multipleRulesNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{multipleRulesNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description, securityRule2.Name: securityRule2.Description}}).Times(1)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &multipleRulesNSG, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
@@ -126,6 +128,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{fakeNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}}).Times(1)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil, nil)
@@ -139,6 +142,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{fakeNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}}).Times(1)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil, errFake)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil, nil)
@@ -152,6 +156,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{fakeNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}}).Times(1)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil, errFake)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil, notDoneError)
@@ -165,6 +170,7 @@ func TestReconcileSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG})
+				s.IsOwnershipRecorded().Return(false)
 				s.UpdateAnnotationJSON(annotation, map[string]interface{}{fakeNSG.Name: map[string]string{securityRule1.Name: securityRule1.Description}})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil, notDoneError)
 				s.UpdatePutStatus(infrav1.SecurityGroupsReadyCondition, serviceName, notDoneError)
@@ -221,8 +227,16 @@ func TestDeleteSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroup, fakeNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroupName(), fakeNSG.ResourceName()))
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroup, noRulesNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroupName(), noRulesNSG.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
 			},
 		},
@@ -233,8 +247,14 @@ func TestDeleteSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroup, fakeNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(errFake)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroup, noRulesNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroupName(), noRulesNSG.ResourceName()))
 				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, errFake)
 			},
 		},
@@ -245,7 +265,11 @@ func TestDeleteSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroup, fakeNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(errFake)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroup, noRulesNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(notDoneError)
 				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, errFake)
 			},
@@ -257,10 +281,29 @@ func TestDeleteSecurityGroups(t *testing.T) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.IsVnetManaged().Return(true)
 				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroup, fakeNSG.Name), true).Return(true)
 				r.DeleteResource(gomockinternal.AContext(), &fakeNSG, serviceName).Return(notDoneError)
 				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, notDoneError)
 			},
 		},
+		{
+			name:          "security groups not created by CAPZ are skipped when ownership is recorded, should return no error",
+			expectedError: "",
+			expect: func(s *mock_securitygroups.MockNSGScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.IsVnetManaged().Return(true)
+				s.NSGSpecs().Return([]azure.ResourceSpecGetter{&fakeNSG, &noRulesNSG})
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", fakeNSG.ResourceGroup, fakeNSG.Name), true).Return(false)
+				s.SubscriptionID().Return("123")
+				s.IsManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroup, noRulesNSG.Name), true).Return(true)
+				r.DeleteResource(gomockinternal.AContext(), &noRulesNSG, serviceName).Return(nil)
+				s.SubscriptionID().Return("123")
+				s.ForgetManagedResource(azure.SecurityGroupID("123", noRulesNSG.ResourceGroupName(), noRulesNSG.ResourceName()))
+				s.UpdateDeleteStatus(infrav1.SecurityGroupsReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "vnet is not managed, should skip delete",
 			expectedError: "",
@@ -70,16 +70,23 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 		// Check if the expected rules are present
 		update := false
 
+		replaced := make(map[string]bool)
 		for _, rule := range s.SecurityRules {
 			sdkRule := converters.SecurityRuleToSDK(rule)
-			if !ruleExists(existingNSG.Properties.SecurityRules, sdkRule) {
+			if !ruleExists(existingNSG.Properties.SecurityRules, sdkRule) || ruleSourceChanged(existingNSG.Properties.SecurityRules, sdkRule) {
 				update = true
 				securityRules = append(securityRules, sdkRule)
+				replaced[strings.ToLower(rule.Name)] = true
 			}
 			newAnnotation[rule.Name] = rule.Description
 		}
 
 		for _, oldRule := range existingNSG.Properties.SecurityRules {
+			// The rule is replaced by its updated version.
+			if replaced[strings.ToLower(ptr.Deref(oldRule.Name, ""))] {
+				update = true
+				continue
+			}
 			_, tracked := s.LastAppliedSecurityRules[*oldRule.Name]
 			// If rule is owned by CAPZ and applied last, and not found in the new rules, then it has been deleted
 			if _, ok := newAnnotation[*oldRule.Name]; !ok && tracked {
@@ -119,6 +126,22 @@ func (s *NSGSpec) Parameters(ctx context.Context, existing interface{}) (interfa
 }
 
 // TODO: review this logic and make sure it is what we want. It seems incorrect to skip rules that don't have a certain protocol, etc.
+// ruleSourceChanged returns true if a rule with the same name as rule allows another source address prefix, e.g.
+// when the API server rule is restricted to the virtual network. Rules without a source address prefix are ignored.
+func ruleSourceChanged(rules []*armnetwork.SecurityRule, rule *armnetwork.SecurityRule) bool {
+	if rule.Properties.SourceAddressPrefix == nil {
+		return false
+	}
+	for _, existingRule := range rules {
+		if !strings.EqualFold(ptr.Deref(existingRule.Name, ""), ptr.Deref(rule.Name, "")) || existingRule.Properties == nil ||
+			existingRule.Properties.SourceAddressPrefix == nil {
+			continue
+		}
+		return !strings.EqualFold(*existingRule.Properties.SourceAddressPrefix, *rule.Properties.SourceAddressPrefix)
+	}
+	return false
+}
+
 func ruleExists(rules []*armnetwork.SecurityRule, rule *armnetwork.SecurityRule) bool {
 	for _, existingRule := range rules {
 		if !strings.EqualFold(ptr.Deref(existingRule.Name, ""), ptr.Deref(rule.Name, "")) {
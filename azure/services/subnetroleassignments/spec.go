@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnetroleassignments
+
+import (
+	"context"
+
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// SubnetRoleAssignmentSpec defines the specification for a role assignment granting a principal a role on a subnet.
+type SubnetRoleAssignmentSpec struct {
+	Name             string
+	SubnetID         string
+	RoleDefinitionID string
+	PrincipalID      string
+}
+
+// ResourceRef implements azure.ASOResourceSpecGetter.
+func (s *SubnetRoleAssignmentSpec) ResourceRef() *asoauthorizationv1.RoleAssignment {
+	return &asoauthorizationv1.RoleAssignment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: s.Name,
+		},
+	}
+}
+
+// Parameters implements azure.ASOResourceSpecGetter.
+func (s *SubnetRoleAssignmentSpec) Parameters(ctx context.Context, existing *asoauthorizationv1.RoleAssignment) (*asoauthorizationv1.RoleAssignment, error) {
+	roleAssignment := &asoauthorizationv1.RoleAssignment{}
+	if existing != nil {
+		roleAssignment = existing
+	}
+
+	// The name of a role assignment must be a UUID. It is derived from the assignment itself so that a role
+	// assignment which already exists in Azure is adopted rather than duplicated.
+	roleAssignment.Spec.AzureName = azure.GenerateRoleAssignmentName(s.SubnetID, s.RoleDefinitionID, s.PrincipalID)
+	roleAssignment.Spec.Owner = &genruntime.ArbitraryOwnerReference{
+		ARMID: s.SubnetID,
+	}
+	roleAssignment.Spec.PrincipalId = ptr.To(s.PrincipalID)
+	roleAssignment.Spec.PrincipalType = ptr.To(asoauthorizationv1.RoleAssignmentProperties_PrincipalType_ServicePrincipal)
+	roleAssignment.Spec.RoleDefinitionReference = &genruntime.ResourceReference{
+		ARMID: s.RoleDefinitionID,
+	}
+
+	return roleAssignment, nil
+}
+
+// WasManaged implements azure.ASOResourceSpecGetter.
+func (s *SubnetRoleAssignmentSpec) WasManaged(resource *asoauthorizationv1.RoleAssignment) bool {
+	// Role assignments don't have tags, so a role assignment that already existed is assumed to be managed by the
+	// user.
+	return false
+}
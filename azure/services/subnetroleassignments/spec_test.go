@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnetroleassignments
+
+import (
+	"context"
+	"testing"
+
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+const (
+	fakeSubnetID         = "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/subnet"
+	fakeRoleDefinitionID = "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/4d97b98b-1d4f-4787-a291-c67834d212e7"
+)
+
+func TestSubnetRoleAssignmentSpec_Parameters(t *testing.T) {
+	spec := &SubnetRoleAssignmentSpec{
+		Name:             "cluster-subnet-network-contributor",
+		SubnetID:         fakeSubnetID,
+		RoleDefinitionID: fakeRoleDefinitionID,
+		PrincipalID:      "principal",
+	}
+	expected := asoauthorizationv1.RoleAssignment_Spec{
+		AzureName:               azure.GenerateRoleAssignmentName(fakeSubnetID, fakeRoleDefinitionID, "principal"),
+		Owner:                   &genruntime.ArbitraryOwnerReference{ARMID: fakeSubnetID},
+		PrincipalId:             ptr.To("principal"),
+		PrincipalType:           ptr.To(asoauthorizationv1.RoleAssignmentProperties_PrincipalType_ServicePrincipal),
+		RoleDefinitionReference: &genruntime.ResourceReference{ARMID: fakeRoleDefinitionID},
+	}
+
+	t.Run("new role assignment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := spec.Parameters(context.Background(), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Spec).To(Equal(expected))
+	})
+
+	t.Run("existing role assignment keeps its metadata", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := &asoauthorizationv1.RoleAssignment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-subnet-network-contributor",
+				Annotations: map[string]string{"foo": "bar"},
+			},
+			Spec: asoauthorizationv1.RoleAssignment_Spec{
+				AzureName:   azure.GenerateRoleAssignmentName(fakeSubnetID, fakeRoleDefinitionID, "principal"),
+				PrincipalId: ptr.To("principal"),
+			},
+		}
+		result, err := spec.Parameters(context.Background(), existing)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Annotations).To(HaveKeyWithValue("foo", "bar"))
+		g.Expect(result.Spec).To(Equal(expected))
+	})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnetroleassignments
+
+import (
+	"context"
+
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const serviceName = "subnetroleassignments"
+
+// SubnetRoleAssignmentScope defines the scope interface for a subnet role assignments service.
+type SubnetRoleAssignmentScope interface {
+	aso.Scope
+	azure.Authorizer
+	SubnetRoleAssignmentSpecs(principalID string) []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment]
+	UserAssignedIdentityResourceID() string
+	IdentityPrincipalID() string
+	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+}
+
+// Service grants the identity of a managed cluster permissions on its subnet.
+type Service struct {
+	*aso.Service[*asoauthorizationv1.RoleAssignment, SubnetRoleAssignmentScope]
+	identitiesGetter identities.Client
+}
+
+// New creates a new service.
+func New(scope SubnetRoleAssignmentScope) (*Service, error) {
+	identitiesClient, err := identities.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	svc := aso.NewService[*asoauthorizationv1.RoleAssignment, SubnetRoleAssignmentScope](serviceName, scope)
+	// The principal of the identity is only resolved when reconciling, it isn't needed to delete, pause or retain the
+	// role assignments.
+	svc.Specs = scope.SubnetRoleAssignmentSpecs("")
+	svc.ListFunc = list
+	svc.ConditionType = infrav1.SubnetRoleAssignmentReadyCondition
+	return &Service{
+		Service:          svc,
+		identitiesGetter: identitiesClient,
+	}, nil
+}
+
+// Reconcile idempotently creates or updates the role assignments.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "subnetroleassignments.Service.Reconcile")
+	defer done()
+
+	if len(s.Specs) == 0 {
+		// Role assignments which are no longer wanted are deleted.
+		return s.Service.Reconcile(ctx)
+	}
+
+	principalID, err := s.principalID(ctx)
+	if err != nil {
+		s.Scope.UpdatePutStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, err)
+		return err
+	}
+	if principalID == "" {
+		// AKS creates the system-assigned identity along with the managed cluster, so its permissions are granted by
+		// the reconciliation following the creation of the managed cluster.
+		log.V(2).Info("waiting for the system-assigned identity of the managed cluster to grant it permissions on the subnet")
+		s.Scope.SetConditionFalse(infrav1.SubnetRoleAssignmentReadyCondition, infrav1.WaitingForIdentityPrincipalReason, clusterv1.ConditionSeverityInfo,
+			"waiting for the system-assigned identity of the managed cluster")
+		return nil
+	}
+
+	s.Specs = s.Scope.SubnetRoleAssignmentSpecs(principalID)
+	return s.Service.Reconcile(ctx)
+}
+
+// principalID returns the principal ID of the user-assigned identity of the managed cluster, or the one of its
+// system-assigned identity once AKS created it.
+func (s *Service) principalID(ctx context.Context) (string, error) {
+	identityID := s.Scope.UserAssignedIdentityResourceID()
+	if identityID == "" {
+		return s.Scope.IdentityPrincipalID(), nil
+	}
+	principalID, err := s.identitiesGetter.GetPrincipalID(ctx, identityID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the principal ID of user-assigned identity %s", identityID)
+	}
+	return principalID, nil
+}
+
+func list(ctx context.Context, client client.Client, opts ...client.ListOption) ([]*asoauthorizationv1.RoleAssignment, error) {
+	list := &asoauthorizationv1.RoleAssignmentList{}
+	err := client.List(ctx, list, opts...)
+	return slice.ToPtrs(list.Items), err
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnetroleassignments
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso/mock_aso"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities/mock_identities"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnetroleassignments/mock_subnetroleassignments"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const fakeIdentityID = "/subscriptions/123/resourceGroups/identity-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane"
+
+func fakeSpecs(principalID string) []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment] {
+	return []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment]{
+		&SubnetRoleAssignmentSpec{
+			Name:             "cluster-subnet-network-contributor",
+			SubnetID:         fakeSubnetID,
+			RoleDefinitionID: fakeRoleDefinitionID,
+			PrincipalID:      principalID,
+		},
+	}
+}
+
+func newTestService(mockCtrl *gomock.Controller, scope *mock_subnetroleassignments.MockSubnetRoleAssignmentScope, specs []azure.ASOResourceSpecGetter[*asoauthorizationv1.RoleAssignment]) (*Service, *mock_identities.MockClient, *mock_aso.MockReconciler[*asoauthorizationv1.RoleAssignment]) {
+	scope.EXPECT().ClusterName().Return("cluster").AnyTimes()
+	scope.EXPECT().GetClient().Return(nil).AnyTimes()
+	scope.EXPECT().ASOOwner().Return(nil).AnyTimes()
+	scope.EXPECT().DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout).AnyTimes()
+
+	identitiesMock := mock_identities.NewMockClient(mockCtrl)
+	reconcilerMock := mock_aso.NewMockReconciler[*asoauthorizationv1.RoleAssignment](mockCtrl)
+	svc := aso.NewService[*asoauthorizationv1.RoleAssignment, SubnetRoleAssignmentScope](serviceName, scope)
+	svc.Reconciler = reconcilerMock
+	svc.Specs = specs
+	svc.ConditionType = infrav1.SubnetRoleAssignmentReadyCondition
+	return &Service{
+		Service:          svc,
+		identitiesGetter: identitiesMock,
+	}, identitiesMock, reconcilerMock
+}
+
+func TestReconcileUserAssignedIdentity(t *testing.T) {
+	t.Run("permissions are granted to the user-assigned identity", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scope := mock_subnetroleassignments.NewMockSubnetRoleAssignmentScope(mockCtrl)
+		s, identitiesMock, reconcilerMock := newTestService(mockCtrl, scope, fakeSpecs(""))
+
+		scope.EXPECT().UserAssignedIdentityResourceID().Return(fakeIdentityID)
+		identitiesMock.EXPECT().GetPrincipalID(gomockinternal.AContext(), fakeIdentityID).Return("uami-principal", nil)
+		scope.EXPECT().SubnetRoleAssignmentSpecs("uami-principal").Return(fakeSpecs("uami-principal"))
+		reconcilerMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), fakeSpecs("uami-principal")[0], serviceName).Return(&asoauthorizationv1.RoleAssignment{}, nil)
+		scope.EXPECT().UpdatePutStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, nil)
+
+		g.Expect(s.Reconcile(context.Background())).To(Succeed())
+	})
+
+	t.Run("the principal of the user-assigned identity can't be read", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		scope := mock_subnetroleassignments.NewMockSubnetRoleAssignmentScope(mockCtrl)
+		s, identitiesMock, _ := newTestService(mockCtrl, scope, fakeSpecs(""))
+
+		scope.EXPECT().UserAssignedIdentityResourceID().Return(fakeIdentityID)
+		identitiesMock.EXPECT().GetPrincipalID(gomockinternal.AContext(), fakeIdentityID).Return("", errors.New("identity not found"))
+		scope.EXPECT().UpdatePutStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, gomock.Any())
+
+		err := s.Reconcile(context.Background())
+		g.Expect(err).To(MatchError("failed to get the principal ID of user-assigned identity " + fakeIdentityID + ": identity not found"))
+	})
+}
+
+func TestReconcileSystemAssignedIdentity(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	scope := mock_subnetroleassignments.NewMockSubnetRoleAssignmentScope(mockCtrl)
+	s, _, reconcilerMock := newTestService(mockCtrl, scope, fakeSpecs(""))
+	scope.EXPECT().UserAssignedIdentityResourceID().Return("").Times(2)
+
+	// The first reconciliation doesn't block the creation of the managed cluster.
+	scope.EXPECT().IdentityPrincipalID().Return("")
+	scope.EXPECT().SetConditionFalse(infrav1.SubnetRoleAssignmentReadyCondition, infrav1.WaitingForIdentityPrincipalReason, clusterv1.ConditionSeverityInfo, gomock.Any())
+	g.Expect(s.Reconcile(context.Background())).To(Succeed())
+
+	// The next one grants permissions to the identity AKS created with the managed cluster.
+	scope.EXPECT().IdentityPrincipalID().Return("system-principal")
+	scope.EXPECT().SubnetRoleAssignmentSpecs("system-principal").Return(fakeSpecs("system-principal"))
+	reconcilerMock.EXPECT().CreateOrUpdateResource(gomockinternal.AContext(), fakeSpecs("system-principal")[0], serviceName).Return(&asoauthorizationv1.RoleAssignment{}, nil)
+	scope.EXPECT().UpdatePutStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, nil)
+	g.Expect(s.Reconcile(context.Background())).To(Succeed())
+}
+
+func TestReconcileWithoutAutoGrant(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	scope := mock_subnetroleassignments.NewMockSubnetRoleAssignmentScope(mockCtrl)
+	s, _, _ := newTestService(mockCtrl, scope, nil)
+
+	scope.EXPECT().UpdatePutStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, nil)
+
+	g.Expect(s.Reconcile(context.Background())).To(Succeed())
+}
+
+func TestDelete(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	scope := mock_subnetroleassignments.NewMockSubnetRoleAssignmentScope(mockCtrl)
+	s, _, reconcilerMock := newTestService(mockCtrl, scope, fakeSpecs(""))
+
+	reconcilerMock.EXPECT().DeleteResource(gomockinternal.AContext(), fakeSpecs("")[0].ResourceRef(), serviceName).Return(nil)
+	scope.EXPECT().UpdateDeleteStatus(infrav1.SubnetRoleAssignmentReadyCondition, serviceName, nil)
+
+	g.Expect(s.Delete(context.Background())).To(Succeed())
+}
@@ -28,6 +28,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
+// ManagedClustersDelegation is the service AKS pod subnets are delegated to.
+const ManagedClustersDelegation = "Microsoft.ContainerService/managedClusters"
+
 // SubnetSpec defines the specification for a Subnet.
 type SubnetSpec struct {
 	Name              string
@@ -40,7 +43,9 @@ type SubnetSpec struct {
 	RouteTableName    string
 	SecurityGroupName string
 	NatGatewayName    string
+	NatGatewayID      string
 	ServiceEndpoints  infrav1.ServiceEndpoints
+	Delegations       []string
 }
 
 // ResourceRef implements azure.ASOResourceSpecGetter.
@@ -87,6 +92,12 @@ func (s *SubnetSpec) Parameters(ctx context.Context, existing *asonetworkv1.Virt
 				ARMID: azure.NatGatewayID(s.SubscriptionID, s.ResourceGroup, s.NatGatewayName),
 			},
 		}
+	} else if s.NatGatewayID != "" {
+		subnet.Spec.NatGateway = &asonetworkv1.SubResource{
+			Reference: &genruntime.ResourceReference{
+				ARMID: s.NatGatewayID,
+			},
+		}
 	}
 
 	if s.SecurityGroupName != "" {
@@ -105,6 +116,15 @@ func (s *SubnetSpec) Parameters(ctx context.Context, existing *asonetworkv1.Virt
 	}
 	subnet.Spec.ServiceEndpoints = serviceEndpoints
 
+	var delegations []asonetworkv1.Delegation
+	for _, serviceName := range s.Delegations {
+		delegations = append(delegations, asonetworkv1.Delegation{
+			Name:        ptr.To(strings.ReplaceAll(serviceName, "/", ".")),
+			ServiceName: ptr.To(serviceName),
+		})
+	}
+	subnet.Spec.Delegations = delegations
+
 	return subnet, nil
 }
 
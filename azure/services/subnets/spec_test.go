@@ -148,6 +148,65 @@ func TestParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with existing NAT gateway ID",
+			spec: &SubnetSpec{
+				IsVNetManaged:     true,
+				Name:              "subnet",
+				SubscriptionID:    "sub",
+				ResourceGroup:     "rg",
+				VNetName:          "vnet",
+				VNetResourceGroup: "vnet-rg",
+				CIDRs:             []string{"cidr"},
+				NatGatewayID:      "/subscriptions/other-sub/resourceGroups/other-rg/providers/Microsoft.Network/natGateways/byo-natgateway",
+			},
+			existing: nil,
+			expected: &asonetworkv1.VirtualNetworksSubnet{
+				Spec: asonetworkv1.VirtualNetworks_Subnet_Spec{
+					AzureName: "subnet",
+					Owner: &genruntime.KnownResourceReference{
+						Name: "vnet",
+					},
+					AddressPrefixes: []string{"cidr"},
+					AddressPrefix:   ptr.To("cidr"),
+					NatGateway: &asonetworkv1.SubResource{
+						Reference: &genruntime.ResourceReference{
+							ARMID: "/subscriptions/other-sub/resourceGroups/other-rg/providers/Microsoft.Network/natGateways/byo-natgateway",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "with delegations",
+			spec: &SubnetSpec{
+				IsVNetManaged:     true,
+				Name:              "pod-subnet",
+				SubscriptionID:    "sub",
+				ResourceGroup:     "rg",
+				VNetName:          "vnet",
+				VNetResourceGroup: "vnet-rg",
+				CIDRs:             []string{"cidr"},
+				Delegations:       []string{"Microsoft.ContainerService/managedClusters"},
+			},
+			existing: nil,
+			expected: &asonetworkv1.VirtualNetworksSubnet{
+				Spec: asonetworkv1.VirtualNetworks_Subnet_Spec{
+					AzureName: "pod-subnet",
+					Owner: &genruntime.KnownResourceReference{
+						Name: "vnet",
+					},
+					AddressPrefixes: []string{"cidr"},
+					AddressPrefix:   ptr.To("cidr"),
+					Delegations: []asonetworkv1.Delegation{
+						{
+							Name:        ptr.To("Microsoft.ContainerService.managedClusters"),
+							ServiceName: ptr.To("Microsoft.ContainerService/managedClusters"),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -89,6 +89,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			continue
 		}
 
+		tags, err = s.migrateLegacyTags(ctx, tagsSpec.Scope, tags)
+		if err != nil {
+			return err
+		}
+
 		lastAppliedTags, err := s.Scope.AnnotationJSON(tagsSpec.Annotation)
 		if err != nil {
 			return err
@@ -133,6 +138,39 @@ func (s *Service) isResourceManaged(tags map[string]*string) bool {
 	return converters.MapToTags(tags).HasOwned(s.Scope.ClusterName())
 }
 
+// migrateLegacyTags rewrites the tags set with the default cluster tag prefix when a different prefix is configured,
+// and returns the tags of the resource once rewritten.
+func (s *Service) migrateLegacyTags(ctx context.Context, scope string, tags map[string]*string) (map[string]*string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "tags.Service.migrateLegacyTags")
+	defer done()
+
+	migrated, legacy := converters.MapToTags(tags).MigrateLegacyPrefix()
+	if len(legacy) == 0 {
+		return tags, nil
+	}
+
+	log.V(2).Info("Rewriting tags set with the legacy cluster tag prefix", "scope", scope)
+	renamedTags := make(map[string]*string)
+	for k, v := range migrated {
+		if _, ok := tags[k]; !ok {
+			renamedTags[k] = ptr.To(v)
+		}
+	}
+	if len(renamedTags) > 0 {
+		if _, err := s.client.UpdateAtScope(ctx, scope, armresources.TagsPatchResource{Operation: ptr.To(armresources.TagsPatchOperationMerge), Properties: &armresources.Tags{Tags: renamedTags}}); err != nil {
+			return nil, errors.Wrap(err, "cannot update tags")
+		}
+	}
+	legacyTags := make(map[string]*string)
+	for k, v := range legacy {
+		legacyTags[k] = ptr.To(v)
+	}
+	if _, err := s.client.UpdateAtScope(ctx, scope, armresources.TagsPatchResource{Operation: ptr.To(armresources.TagsPatchOperationDelete), Properties: &armresources.Tags{Tags: legacyTags}}); err != nil {
+		return nil, errors.Wrap(err, "cannot update tags")
+	}
+	return converters.TagsToMap(migrated), nil
+}
+
 // Delete is a no-op as the tags get deleted as part of VM deletion.
 func (s *Service) Delete(ctx context.Context) error {
 	_, _, done := tele.StartSpanWithLogger(ctx, "tags.Service.Delete")
@@ -28,6 +28,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags/mock_tags"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -264,6 +265,90 @@ func TestReconcileTags(t *testing.T) {
 				s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"key": "value"})
 			},
 		},
+		{
+			name:          "update and remove scale set tags while keeping tags added by other systems",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				annotation := azure.VMSSTagsLastAppliedAnnotation
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope: "/sub/123/vmss/scope",
+							Tags: map[string]string{
+								"env":  "prod",
+								"team": "infra",
+							},
+							Annotation: annotation,
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/vmss/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+							"env":               ptr.To("dev"),
+							"cost-center":       ptr.To("1234"),
+							"externalSystemTag": ptr.To("randomValue"),
+						},
+					}}, nil),
+					s.AnnotationJSON(annotation).Return(map[string]interface{}{"env": "dev", "cost-center": "1234"}, nil),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/vmss/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"env":  ptr.To("prod"),
+								"team": ptr.To("infra"),
+							},
+						},
+					}),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/vmss/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationDelete),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"cost-center": ptr.To("1234"),
+							},
+						},
+					}),
+					s.UpdateAnnotationJSON(annotation, map[string]interface{}{"env": "prod", "team": "infra"}),
+				)
+			},
+		},
+		{
+			name:          "scale set created before its tags were tracked only adds tags and records the annotation",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				annotation := azure.VMSSTagsLastAppliedAnnotation
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope: "/sub/123/vmss/scope",
+							Tags: map[string]string{
+								"env":  "prod",
+								"team": "infra",
+							},
+							Annotation: annotation,
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/vmss/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+							"env":               ptr.To("prod"),
+							"externalSystemTag": ptr.To("randomValue"),
+						},
+					}}, nil),
+					s.AnnotationJSON(annotation).Return(map[string]interface{}{}, nil),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/vmss/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"team": ptr.To("infra"),
+							},
+						},
+					}),
+					s.UpdateAnnotationJSON(annotation, map[string]interface{}{"env": "prod", "team": "infra"}),
+				)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -294,6 +379,138 @@ func TestReconcileTags(t *testing.T) {
 	}
 }
 
+func TestReconcileTagsWithClusterTagPrefix(t *testing.T) {
+	// The cluster tag prefix is global, so these test cases don't run in parallel.
+	g := NewWithT(t)
+	g.Expect(infrav1.SetClusterTagPrefix("acme_capz_")).To(Succeed())
+	t.Cleanup(func() { _ = infrav1.SetClusterTagPrefix(infrav1.NameAzureProviderPrefix) })
+
+	testcases := []struct {
+		name   string
+		expect func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder)
+	}{
+		{
+			name: "resource tagged with the legacy prefix is rewritten",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope:      "/sub/123/fake/scope",
+							Tags:       map[string]string{"foo": "bar"},
+							Annotation: "my-annotation",
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+							"foo": ptr.To("bar"),
+						},
+					}}, nil),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"acme_capz_cluster_test-cluster": ptr.To("owned"),
+							},
+						},
+					}),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationDelete),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+							},
+						},
+					}),
+					s.AnnotationJSON("my-annotation"),
+					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar"}),
+				)
+			},
+		},
+		{
+			name: "resource tagged with the new prefix is left as is",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope:      "/sub/123/fake/scope",
+							Tags:       map[string]string{"foo": "bar"},
+							Annotation: "my-annotation",
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"acme_capz_cluster_test-cluster": ptr.To("owned"),
+							"foo":                            ptr.To("bar"),
+						},
+					}}, nil),
+					s.AnnotationJSON("my-annotation"),
+					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar"}),
+				)
+			},
+		},
+		{
+			name: "resource tagged with both prefixes only deletes the legacy tags",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope:      "/sub/123/fake/scope",
+							Tags:       map[string]string{"foo": "bar"},
+							Annotation: "my-annotation",
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
+						Tags: map[string]*string{
+							"acme_capz_cluster_test-cluster":                              ptr.To("owned"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+						},
+					}}, nil),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationDelete),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": ptr.To("owned"),
+							},
+						},
+					}),
+					s.AnnotationJSON("my-annotation"),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: ptr.To(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"foo": ptr.To("bar"),
+							},
+						},
+					}),
+					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar"}),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			scopeMock := mock_tags.NewMockTagScope(mockCtrl)
+			clientMock := mock_tags.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			g.Expect(s.Reconcile(context.TODO())).To(Succeed())
+		})
+	}
+}
+
 func TestTagsChanged(t *testing.T) {
 	g := NewWithT(t)
 
@@ -19,20 +19,26 @@ package virtualmachineimages
 import (
 	"context"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// Client is an interface for listing VM images.
+// Client is an interface for listing VM images and getting compute gallery image versions.
 type Client interface {
 	List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error)
+	GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroup, gallery, image, version string) (armcompute.GalleryImageVersion, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	images *armcompute.VirtualMachineImagesClient
+	images     *armcompute.VirtualMachineImagesClient
+	credential azcore.TokenCredential
+	opts       *arm.ClientOptions
 }
 
 var _ Client = (*AzureClient)(nil)
@@ -47,7 +53,11 @@ func NewClient(auth azure.Authorizer) (*AzureClient, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create armcompute client factory")
 	}
-	return &AzureClient{computeClientFactory.NewVirtualMachineImagesClient()}, nil
+	return &AzureClient{
+		images:     computeClientFactory.NewVirtualMachineImagesClient(),
+		credential: auth.Token(),
+		opts:       opts,
+	}, nil
 }
 
 // List returns a VM image list response.
@@ -58,3 +68,21 @@ func (ac *AzureClient) List(ctx context.Context, location, publisher, offer, sku
 	opts := &armcompute.VirtualMachineImagesClientListOptions{}
 	return ac.images.List(ctx, location, publisher, offer, sku, opts)
 }
+
+// GetGalleryImageVersion returns a compute gallery image version along with its replication status. The gallery may
+// be in a different subscription than the cluster.
+func (ac *AzureClient) GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroup, gallery, image, version string) (armcompute.GalleryImageVersion, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.AzureClient.GetGalleryImageVersion")
+	defer done()
+
+	versions, err := armcompute.NewGalleryImageVersionsClient(subscriptionID, ac.credential, ac.opts)
+	if err != nil {
+		return armcompute.GalleryImageVersion{}, errors.Wrap(err, "failed to create gallery image versions client")
+	}
+	opts := &armcompute.GalleryImageVersionsClientGetOptions{Expand: ptr.To(armcompute.ReplicationStatusTypesReplicationStatus)}
+	resp, err := versions.Get(ctx, resourceGroup, gallery, image, version, opts)
+	if err != nil {
+		return armcompute.GalleryImageVersion{}, err
+	}
+	return resp.GalleryImageVersion, nil
+}
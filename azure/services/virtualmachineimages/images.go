@@ -22,8 +22,10 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -119,6 +121,70 @@ func (s *Service) GetDefaultWindowsImage(ctx context.Context, location, k8sVersi
 	return defaultImage, nil
 }
 
+// GetDefaultComputeGalleryImage returns the image spec for the provided version of Kubernetes from the
+// given Azure Compute Gallery image definition. Image versions are expected to be named after the Kubernetes
+// version they contain, e.g. "1.28.3".
+func GetDefaultComputeGalleryImage(defaultImage *infrav1.DefaultImage, k8sVersion string) (*infrav1.Image, error) {
+	v, err := semver.ParseTolerant(k8sVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse Kubernetes version \"%s\"", k8sVersion)
+	}
+
+	return &infrav1.Image{
+		ComputeGallery: &infrav1.AzureComputeGalleryImage{
+			Gallery:        defaultImage.Gallery,
+			Name:           defaultImage.Name,
+			Version:        fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch),
+			SubscriptionID: defaultImage.SubscriptionID,
+			ResourceGroup:  defaultImage.ResourceGroup,
+		},
+	}, nil
+}
+
+// IsGalleryImageReplicated returns true unless the image is a version of a private compute gallery image that has not
+// finished replicating to the given location yet. Community gallery images and the "latest" version are not checked.
+func (s *Service) IsGalleryImageReplicated(ctx context.Context, image *infrav1.Image, location string) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.Service.IsGalleryImageReplicated")
+	defer done()
+
+	var subscriptionID, resourceGroup, gallery, name, version string
+	switch {
+	case image == nil:
+		return true, nil
+	case image.ComputeGallery != nil && image.ComputeGallery.SubscriptionID != nil && image.ComputeGallery.ResourceGroup != nil:
+		subscriptionID, resourceGroup = *image.ComputeGallery.SubscriptionID, *image.ComputeGallery.ResourceGroup
+		gallery, name, version = image.ComputeGallery.Gallery, image.ComputeGallery.Name, image.ComputeGallery.Version
+	case image.SharedGallery != nil:
+		subscriptionID, resourceGroup = image.SharedGallery.SubscriptionID, image.SharedGallery.ResourceGroup
+		gallery, name, version = image.SharedGallery.Gallery, image.SharedGallery.Name, image.SharedGallery.Version
+	default:
+		return true, nil
+	}
+	if version == azure.LatestVersion {
+		return true, nil
+	}
+
+	imageVersion, err := s.Client.GetGalleryImageVersion(ctx, subscriptionID, resourceGroup, gallery, name, version)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get version %s of gallery image %s/%s", version, gallery, name)
+	}
+	if imageVersion.Properties == nil || imageVersion.Properties.ReplicationStatus == nil {
+		return false, nil
+	}
+
+	for _, status := range imageVersion.Properties.ReplicationStatus.Summary {
+		if status == nil || !strings.EqualFold(strings.ReplaceAll(ptr.Deref(status.Region, ""), " ", ""), location) {
+			continue
+		}
+		state := ptr.Deref(status.State, "")
+		log.V(4).Info("Found gallery image version replication status", "gallery", gallery, "image", name, "version", version, "location", location, "state", state)
+		return state == armcompute.ReplicationStateCompleted, nil
+	}
+
+	// The image version isn't replicated to the location, at least not yet.
+	return false, nil
+}
+
 // getSKUAndVersion gets the SKU ID and version of the image to use for the provided version of Kubernetes.
 // note: osAndVersion is expected to be in the format of {os}-{version} (ex: ubuntu-2004 or windows-2022)
 func (s *Service) getSKUAndVersion(ctx context.Context, location, publisher, offer, k8sVersion, osAndVersion string) (skuID string, imageVersion string, err error) {
@@ -24,11 +24,14 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
 func TestGetDefaultUbuntuImage(t *testing.T) {
@@ -343,6 +346,60 @@ func TestGetDefaultWindowsImage(t *testing.T) {
 	}
 }
 
+func TestGetDefaultComputeGalleryImage(t *testing.T) {
+	defaultImage := &infrav1.DefaultImage{
+		Gallery:        "my-gallery",
+		Name:           "capi-ubun2-2204",
+		SubscriptionID: ptr.To("123"),
+		ResourceGroup:  ptr.To("my-rg"),
+	}
+
+	var tests = []struct {
+		name            string
+		k8sVersion      string
+		expectedVersion string
+		expectedErr     string
+	}{
+		{
+			name:        "invalid k8sVersion",
+			k8sVersion:  "1.1.1.1.1.1",
+			expectedErr: "unable to parse Kubernetes version \"1.1.1.1.1.1\": Invalid character(s) found in patch number \"1.1.1.1\"",
+		},
+		{
+			name:            "k8sVersion with v prefix",
+			k8sVersion:      "v1.28.3",
+			expectedVersion: "1.28.3",
+		},
+		{
+			name:            "k8sVersion without v prefix",
+			k8sVersion:      "1.27.7",
+			expectedVersion: "1.27.7",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image, err := GetDefaultComputeGalleryImage(defaultImage, test.k8sVersion)
+			if test.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(Equal(test.expectedErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(image).To(Equal(&infrav1.Image{
+					ComputeGallery: &infrav1.AzureComputeGalleryImage{
+						Gallery:        "my-gallery",
+						Name:           "capi-ubun2-2204",
+						Version:        test.expectedVersion,
+						SubscriptionID: ptr.To("123"),
+						ResourceGroup:  ptr.To("my-rg"),
+					},
+				}))
+			}
+		})
+	}
+}
+
 func TestGetDefaultImageSKUID(t *testing.T) {
 	var tests = []struct {
 		k8sVersion      string
@@ -596,3 +653,136 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsGalleryImageReplicated(t *testing.T) {
+	computeGalleryImage := &infrav1.Image{
+		ComputeGallery: &infrav1.AzureComputeGalleryImage{
+			Gallery:        "gallery",
+			Name:           "image",
+			Version:        "1.0.0",
+			SubscriptionID: ptr.To("sub"),
+			ResourceGroup:  ptr.To("rg"),
+		},
+	}
+	replicationStatus := func(region string, state armcompute.ReplicationState) armcompute.GalleryImageVersion {
+		return armcompute.GalleryImageVersion{
+			Properties: &armcompute.GalleryImageVersionProperties{
+				ReplicationStatus: &armcompute.ReplicationStatus{
+					Summary: []*armcompute.RegionalReplicationStatus{
+						{Region: ptr.To("West Europe"), State: ptr.To(armcompute.ReplicationStateCompleted)},
+						{Region: ptr.To(region), State: ptr.To(state)},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		image         *infrav1.Image
+		expect        func(m *mock_virtualmachineimages.MockClientMockRecorder)
+		expected      bool
+		expectedError bool
+	}{
+		{
+			name:     "nil image",
+			expected: true,
+		},
+		{
+			name: "marketplace image",
+			image: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					ImagePlan: infrav1.ImagePlan{Publisher: "pub", Offer: "offer", SKU: "sku"},
+					Version:   "1.0.0",
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "community gallery image",
+			image: &infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{Gallery: "gallery", Name: "image", Version: "1.0.0"},
+			},
+			expected: true,
+		},
+		{
+			name: "latest version",
+			image: &infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "sub", ResourceGroup: "rg", Gallery: "gallery", Name: "image", Version: "latest",
+				},
+			},
+			expected: true,
+		},
+		{
+			name:  "compute gallery image version replicated to the location",
+			image: computeGalleryImage,
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "sub", "rg", "gallery", "image", "1.0.0").
+					Return(replicationStatus("East US", armcompute.ReplicationStateCompleted), nil)
+			},
+			expected: true,
+		},
+		{
+			name:  "compute gallery image version still replicating to the location",
+			image: computeGalleryImage,
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "sub", "rg", "gallery", "image", "1.0.0").
+					Return(replicationStatus("East US", armcompute.ReplicationStateReplicating), nil)
+			},
+			expected: false,
+		},
+		{
+			name:  "compute gallery image version not replicated to the location",
+			image: computeGalleryImage,
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "sub", "rg", "gallery", "image", "1.0.0").
+					Return(replicationStatus("West US", armcompute.ReplicationStateCompleted), nil)
+			},
+			expected: false,
+		},
+		{
+			name: "shared gallery image version replicated to the location",
+			image: &infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: "sub", ResourceGroup: "rg", Gallery: "gallery", Name: "image", Version: "1.0.0",
+				},
+			},
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "sub", "rg", "gallery", "image", "1.0.0").
+					Return(replicationStatus("eastus", armcompute.ReplicationStateCompleted), nil)
+			},
+			expected: true,
+		},
+		{
+			name:  "error getting the image version",
+			image: computeGalleryImage,
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "sub", "rg", "gallery", "image", "1.0.0").
+					Return(armcompute.GalleryImageVersion{}, errors.New("boom"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(mockClient.EXPECT())
+			}
+			svc := Service{Client: mockClient}
+
+			replicated, err := svc.IsGalleryImageReplicated(context.TODO(), tc.image, "eastus")
+			if tc.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(replicated).To(Equal(tc.expected))
+		})
+	}
+}
@@ -56,6 +56,21 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// GetGalleryImageVersion mocks base method.
+func (m *MockClient) GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroup, gallery, image, version string) (armcompute.GalleryImageVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGalleryImageVersion", ctx, subscriptionID, resourceGroup, gallery, image, version)
+	ret0, _ := ret[0].(armcompute.GalleryImageVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGalleryImageVersion indicates an expected call of GetGalleryImageVersion.
+func (mr *MockClientMockRecorder) GetGalleryImageVersion(ctx, subscriptionID, resourceGroup, gallery, image, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGalleryImageVersion", reflect.TypeOf((*MockClient)(nil).GetGalleryImageVersion), ctx, subscriptionID, resourceGroup, gallery, image, version)
+}
+
 // List mocks base method.
 func (m *MockClient) List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error) {
 	m.ctrl.T.Helper()
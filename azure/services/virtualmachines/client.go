@@ -41,6 +41,8 @@ type (
 		Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
 		CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller *runtime.Poller[armcompute.VirtualMachinesClientCreateOrUpdateResponse], err error)
 		DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller *runtime.Poller[armcompute.VirtualMachinesClientDeleteResponse], err error)
+		Start(ctx context.Context, spec azure.ResourceSpecGetter) error
+		Deallocate(ctx context.Context, spec azure.ResourceSpecGetter, hibernate bool) error
 	}
 )
 
@@ -59,12 +61,13 @@ func NewClient(auth azure.Authorizer, apiCallTimeout time.Duration) (*AzureClien
 	return &AzureClient{factory.NewVirtualMachinesClient(), apiCallTimeout}, nil
 }
 
-// Get retrieves information about the model view or the instance view of a virtual machine.
+// Get retrieves information about the model view and the instance view of a virtual machine.
 func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Get")
 	defer done()
 
-	resp, err := ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	opts := &armcompute.VirtualMachinesClientGetOptions{Expand: ptr.To(armcompute.InstanceViewTypesInstanceView)}
+	resp, err := ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +135,24 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	// if the operation completed, return a nil poller.
 	return nil, err
 }
+
+// Start starts a virtual machine. It returns once Azure accepted the operation, whose progress is reported by the power
+// state of the virtual machine.
+func (ac *AzureClient) Start(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Start")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginStart(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return err
+}
+
+// Deallocate deallocates a virtual machine, hibernating it if hibernate is true. It returns once Azure accepted the
+// operation, whose progress is reported by the power state of the virtual machine.
+func (ac *AzureClient) Deallocate(ctx context.Context, spec azure.ResourceSpecGetter, hibernate bool) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Deallocate")
+	defer done()
+
+	opts := &armcompute.VirtualMachinesClientBeginDeallocateOptions{Hibernate: ptr.To(hibernate)}
+	_, err := ac.virtualmachines.BeginDeallocate(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	return err
+}
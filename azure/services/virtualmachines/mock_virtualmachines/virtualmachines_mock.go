@@ -158,6 +158,18 @@ func (mr *MockVMScopeMockRecorder) DefaultedReconcilerRequeue() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultedReconcilerRequeue", reflect.TypeOf((*MockVMScope)(nil).DefaultedReconcilerRequeue))
 }
 
+// DeleteCondition mocks base method.
+func (m *MockVMScope) DeleteCondition(arg0 v1beta10.ConditionType) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteCondition", arg0)
+}
+
+// DeleteCondition indicates an expected call of DeleteCondition.
+func (mr *MockVMScopeMockRecorder) DeleteCondition(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCondition", reflect.TypeOf((*MockVMScope)(nil).DeleteCondition), arg0)
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockVMScope) DeleteLongRunningOperationState(arg0, arg1, arg2 string) {
 	m.ctrl.T.Helper()
@@ -170,6 +182,32 @@ func (mr *MockVMScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).DeleteLongRunningOperationState), arg0, arg1, arg2)
 }
 
+// DesiredPowerState mocks base method.
+func (m *MockVMScope) DesiredPowerState() v1beta1.VMPowerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredPowerState")
+	ret0, _ := ret[0].(v1beta1.VMPowerState)
+	return ret0
+}
+
+// DesiredPowerState indicates an expected call of DesiredPowerState.
+func (mr *MockVMScopeMockRecorder) DesiredPowerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredPowerState", reflect.TypeOf((*MockVMScope)(nil).DesiredPowerState))
+}
+
+// ForgetManagedResource mocks base method.
+func (m *MockVMScope) ForgetManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetManagedResource", id)
+}
+
+// ForgetManagedResource indicates an expected call of ForgetManagedResource.
+func (mr *MockVMScopeMockRecorder) ForgetManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetManagedResource", reflect.TypeOf((*MockVMScope)(nil).ForgetManagedResource), id)
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockVMScope) GetLongRunningOperationState(arg0, arg1, arg2 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -198,6 +236,46 @@ func (mr *MockVMScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockVMScope)(nil).HashKey))
 }
 
+// IsManagedResource mocks base method.
+func (m *MockVMScope) IsManagedResource(id string, ownedByTags bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsManagedResource", id, ownedByTags)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsManagedResource indicates an expected call of IsManagedResource.
+func (mr *MockVMScopeMockRecorder) IsManagedResource(id, ownedByTags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsManagedResource", reflect.TypeOf((*MockVMScope)(nil).IsManagedResource), id, ownedByTags)
+}
+
+// IsOwnershipRecorded mocks base method.
+func (m *MockVMScope) IsOwnershipRecorded() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwnershipRecorded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwnershipRecorded indicates an expected call of IsOwnershipRecorded.
+func (mr *MockVMScopeMockRecorder) IsOwnershipRecorded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnershipRecorded", reflect.TypeOf((*MockVMScope)(nil).IsOwnershipRecorded))
+}
+
+// RecordManagedResource mocks base method.
+func (m *MockVMScope) RecordManagedResource(id string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordManagedResource", id)
+}
+
+// RecordManagedResource indicates an expected call of RecordManagedResource.
+func (mr *MockVMScopeMockRecorder) RecordManagedResource(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManagedResource", reflect.TypeOf((*MockVMScope)(nil).RecordManagedResource), id)
+}
+
 // SetAddresses mocks base method.
 func (m *MockVMScope) SetAddresses(arg0 []v1.NodeAddress) {
 	m.ctrl.T.Helper()
@@ -234,6 +312,30 @@ func (mr *MockVMScopeMockRecorder) SetConditionFalse(arg0, arg1, arg2, arg3 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConditionFalse", reflect.TypeOf((*MockVMScope)(nil).SetConditionFalse), arg0, arg1, arg2, arg3)
 }
 
+// SetConditionTrue mocks base method.
+func (m *MockVMScope) SetConditionTrue(arg0 v1beta10.ConditionType) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetConditionTrue", arg0)
+}
+
+// SetConditionTrue indicates an expected call of SetConditionTrue.
+func (mr *MockVMScopeMockRecorder) SetConditionTrue(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConditionTrue", reflect.TypeOf((*MockVMScope)(nil).SetConditionTrue), arg0)
+}
+
+// SetLastBootTime mocks base method.
+func (m *MockVMScope) SetLastBootTime(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLastBootTime", arg0)
+}
+
+// SetLastBootTime indicates an expected call of SetLastBootTime.
+func (mr *MockVMScopeMockRecorder) SetLastBootTime(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastBootTime", reflect.TypeOf((*MockVMScope)(nil).SetLastBootTime), arg0)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockVMScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -246,6 +348,18 @@ func (mr *MockVMScopeMockRecorder) SetLongRunningOperationState(arg0 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetPowerState mocks base method.
+func (m *MockVMScope) SetPowerState(arg0 v1beta1.VMPowerState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPowerState", arg0)
+}
+
+// SetPowerState indicates an expected call of SetPowerState.
+func (mr *MockVMScopeMockRecorder) SetPowerState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerState", reflect.TypeOf((*MockVMScope)(nil).SetPowerState), arg0)
+}
+
 // SetProviderID mocks base method.
 func (m *MockVMScope) SetProviderID(arg0 string) {
 	m.ctrl.T.Helper()
@@ -258,6 +372,18 @@ func (mr *MockVMScopeMockRecorder) SetProviderID(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProviderID", reflect.TypeOf((*MockVMScope)(nil).SetProviderID), arg0)
 }
 
+// SetVMCreationTime mocks base method.
+func (m *MockVMScope) SetVMCreationTime(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMCreationTime", arg0)
+}
+
+// SetVMCreationTime indicates an expected call of SetVMCreationTime.
+func (mr *MockVMScopeMockRecorder) SetVMCreationTime(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMCreationTime", reflect.TypeOf((*MockVMScope)(nil).SetVMCreationTime), arg0)
+}
+
 // SetVMState mocks base method.
 func (m *MockVMScope) SetVMState(arg0 v1beta1.ProvisioningState) {
 	m.ctrl.T.Helper()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/pkg/errors"
@@ -31,6 +32,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
 )
 
+// vmNotFoundRequeue is how long to wait before checking again for a recently created VM that was not found.
+const vmNotFoundRequeue = 15 * time.Second
+
 // VMSpec defines the specification for a Virtual Machine.
 type VMSpec struct {
 	Name                   string
@@ -46,6 +50,7 @@ type VMSpec struct {
 	Zone                   string
 	Identity               infrav1.VMIdentity
 	OSDisk                 infrav1.OSDisk
+	PatchSettings          *infrav1.PatchSettings
 	DataDisks              []infrav1.DataDisk
 	UserAssignedIdentities []infrav1.UserAssignedIdentity
 	SpotVMOptions          *infrav1.SpotVMOptions
@@ -57,6 +62,23 @@ type VMSpec struct {
 	Image                  *infrav1.Image
 	BootstrapData          string
 	ProviderID             string
+	// CreationTime is when the VM was created, or the zero time if it hasn't been observed yet.
+	CreationTime time.Time
+	// NotFoundGracePeriod is the duration after CreationTime during which the VM not being found is attributed to
+	// Azure eventual consistency rather than to the VM having been deleted.
+	NotFoundGracePeriod time.Duration
+	// ResourceNames are the names rendered by the naming template of the cluster for the disks of the VM.
+	ResourceNames azure.MachineResourceNames
+}
+
+// diskNames returns the names of the resources of the VM, which are generated from its name unless the naming
+// template of the cluster rendered them.
+func (s *VMSpec) diskNames() azure.MachineResourceNames {
+	names := s.ResourceNames
+	if names.MachineName == "" {
+		names.MachineName = s.Name
+	}
+	return names
 }
 
 // ResourceName returns the name of the virtual machine.
@@ -84,11 +106,19 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 		return nil, nil
 	}
 
-	// VM got deleted outside of capz, do not recreate it as Machines are immutable.
 	if s.ProviderID != "" {
+		// A VM that was created recently may not be visible yet, check again shortly rather than failing the machine.
+		if !s.CreationTime.IsZero() && time.Since(s.CreationTime) < s.NotFoundGracePeriod {
+			return nil, azure.WithTransientError(errors.Errorf("virtual machine %s created at %s was not found, waiting for it to become visible", s.Name, s.CreationTime.Format(time.RFC3339)), vmNotFoundRequeue)
+		}
+		// VM got deleted outside of capz, do not recreate it as Machines are immutable.
 		return nil, azure.VMDeletedError{ProviderID: s.ProviderID}
 	}
 
+	if s.AdditionalCapabilities != nil && ptr.Deref(s.AdditionalCapabilities.HibernationEnabled, false) && !s.SKU.HasCapability(resourceskus.HibernationSupported) {
+		return nil, azure.WithTerminalError(errors.Errorf("VM size %s does not support hibernation. Select a different VM size or disable hibernation", s.Size))
+	}
+
 	storageProfile, err := s.generateStorageProfile()
 	if err != nil {
 		return nil, err
@@ -150,7 +180,7 @@ func (s *VMSpec) Parameters(ctx context.Context, existing interface{}) (params i
 // generateStorageProfile generates a pointer to an armcompute.StorageProfile which can utilized for VM creation.
 func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 	osDisk := &armcompute.OSDisk{
-		Name:         ptr.To(azure.GenerateOSDiskName(s.Name)),
+		Name:         ptr.To(s.diskNames().OSDiskName()),
 		OSType:       ptr.To(armcompute.OperatingSystemTypes(s.OSDisk.OSType)),
 		CreateOption: ptr.To(armcompute.DiskCreateOptionTypesFromImage),
 		DiskSizeGB:   s.OSDisk.DiskSizeGB,
@@ -191,6 +221,17 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 		}
 	}
 
+	if s.OSDisk.DiskControllerType != "" {
+		if !s.SKU.SupportsDiskControllerType(string(s.OSDisk.DiskControllerType)) {
+			return nil, azure.WithTerminalError(fmt.Errorf("VM size %s does not support the %s disk controller type. Select a different VM size or disk controller type", s.Size, s.OSDisk.DiskControllerType))
+		}
+		if s.OSDisk.DiskControllerType == infrav1.DiskControllerTypeNVMe && infrav1.IsGen1MarketplaceImage(s.Image) {
+			return nil, azure.WithTerminalError(fmt.Errorf("image SKU %s is a generation 1 image which does not support the NVMe disk controller type. Select a generation 2 image or a different disk controller type", s.Image.Marketplace.SKU))
+		}
+
+		storageProfile.DiskControllerType = ptr.To(armcompute.DiskControllerTypes(s.OSDisk.DiskControllerType))
+	}
+
 	if s.OSDisk.ManagedDisk != nil {
 		storageProfile.OSDisk.ManagedDisk = &armcompute.ManagedDiskParameters{}
 		if s.OSDisk.ManagedDisk.StorageAccountType != "" {
@@ -221,7 +262,7 @@ func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
 			CreateOption: ptr.To(armcompute.DiskCreateOptionTypesEmpty),
 			DiskSizeGB:   ptr.To[int32](disk.DiskSizeGB),
 			Lun:          disk.Lun,
-			Name:         ptr.To(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
+			Name:         ptr.To(s.diskNames().DataDiskName(disk.NameSuffix)),
 		}
 		if disk.CachingType != "" {
 			dataDisks[i].Caching = ptr.To(armcompute.CachingTypes(disk.CachingType))
@@ -277,7 +318,8 @@ func (s *VMSpec) generateOSProfile() (*armcompute.OSProfile, error) {
 		// Azure also provides a way to reset user passwords in the case of need.
 		osProfile.AdminPassword = ptr.To(generators.SudoRandomPassword(123))
 		osProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
-			EnableAutomaticUpdates: ptr.To(false),
+			EnableAutomaticUpdates: ptr.To(converters.IsWindowsAutomaticUpdatesEnabled(s.PatchSettings)),
+			PatchSettings:          converters.GetWindowsPatchSettings(s.PatchSettings),
 		}
 	default:
 		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
@@ -290,6 +332,7 @@ func (s *VMSpec) generateOSProfile() (*armcompute.OSProfile, error) {
 					},
 				},
 			},
+			PatchSettings: converters.GetLinuxPatchSettings(s.PatchSettings),
 		}
 	}
 
@@ -418,6 +461,7 @@ func (s *VMSpec) generateAdditionalCapabilities() *armcompute.AdditionalCapabili
 		if s.AdditionalCapabilities.UltraSSDEnabled != nil {
 			capabilities.UltraSSDEnabled = s.AdditionalCapabilities.UltraSSDEnabled
 		}
+		capabilities.HibernationEnabled = s.AdditionalCapabilities.HibernationEnabled
 	}
 
 	return capabilities
@@ -19,6 +19,7 @@ package virtualmachines
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
@@ -50,6 +51,28 @@ var (
 		},
 	}
 
+	validSKUWithHibernation = resourceskus.SKU{
+		Name: ptr.To("Standard_D2v3"),
+		Kind: ptr.To(string(resourceskus.VirtualMachines)),
+		Locations: []*string{
+			ptr.To("test-location"),
+		},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("2"),
+			},
+			{
+				Name:  ptr.To(resourceskus.MemoryGB),
+				Value: ptr.To("4"),
+			},
+			{
+				Name:  ptr.To(resourceskus.HibernationSupported),
+				Value: ptr.To("True"),
+			},
+		},
+	}
+
 	validSKUWithEncryptionAtHost = resourceskus.SKU{
 		Name: ptr.To("Standard_D2v3"),
 		Kind: ptr.To(string(resourceskus.VirtualMachines)),
@@ -138,6 +161,28 @@ var (
 		},
 	}
 
+	validSKUWithNVMe = resourceskus.SKU{
+		Name: ptr.To("Standard_D2s_v6"),
+		Kind: ptr.To(string(resourceskus.VirtualMachines)),
+		Locations: []*string{
+			ptr.To("test-location"),
+		},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  ptr.To(resourceskus.VCPUs),
+				Value: ptr.To("2"),
+			},
+			{
+				Name:  ptr.To(resourceskus.MemoryGB),
+				Value: ptr.To("8"),
+			},
+			{
+				Name:  ptr.To(resourceskus.DiskControllerTypes),
+				Value: ptr.To("SCSI, NVMe"),
+			},
+		},
+	}
+
 	validSKUWithUltraSSD = resourceskus.SKU{
 		Name: ptr.To("Standard_D2v3"),
 		Kind: ptr.To(string(resourceskus.VirtualMachines)),
@@ -213,6 +258,7 @@ var (
 )
 
 func TestParameters(t *testing.T) {
+	recentVMCreationTime := time.Now().Add(-1 * time.Minute)
 	testcases := []struct {
 		name          string
 		spec          *VMSpec
@@ -249,6 +295,34 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: azure.VMDeletedError{ProviderID: "fake/vm/id"}.Error(),
 		},
+		{
+			name: "retries if vm created recently is not found yet",
+			spec: &VMSpec{
+				Name:                "my-vm",
+				ProviderID:          "fake/vm/id",
+				CreationTime:        recentVMCreationTime,
+				NotFoundGracePeriod: 5 * time.Minute,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "virtual machine my-vm created at " + recentVMCreationTime.Format(time.RFC3339) + " was not found, waiting for it to become visible. Object will be requeued after 15s",
+		},
+		{
+			name: "fails if vm created before the grace period is not found",
+			spec: &VMSpec{
+				Name:                "my-vm",
+				ProviderID:          "fake/vm/id",
+				CreationTime:        time.Now().Add(-10 * time.Minute),
+				NotFoundGracePeriod: 5 * time.Minute,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: azure.VMDeletedError{ProviderID: "fake/vm/id"}.Error(),
+		},
 		{
 			name: "can create a vm with system assigned identity ",
 			spec: &VMSpec{
@@ -365,6 +439,70 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a windows vm with automatic guest patching",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Windows",
+					DiskSizeGB: ptr.To[int32](128),
+				},
+				PatchSettings: &infrav1.PatchSettings{
+					PatchMode:     infrav1.PatchModeAutomaticByPlatform,
+					RebootSetting: infrav1.PatchRebootSettingIfRequired,
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				windowsConfiguration := result.(armcompute.VirtualMachine).Properties.OSProfile.WindowsConfiguration
+				g.Expect(*windowsConfiguration.EnableAutomaticUpdates).Should(Equal(true))
+				g.Expect(windowsConfiguration.PatchSettings).To(Equal(&armcompute.PatchSettings{
+					PatchMode: ptr.To(armcompute.WindowsVMGuestPatchModeAutomaticByPlatform),
+					AutomaticByPlatformSettings: &armcompute.WindowsVMGuestPatchAutomaticByPlatformSettings{
+						RebootSetting: ptr.To(armcompute.WindowsVMGuestPatchAutomaticByPlatformRebootSettingIfRequired),
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a linux vm with automatic guest patching",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Zone:       "1",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: ptr.To[int32](128),
+				},
+				PatchSettings: &infrav1.PatchSettings{
+					PatchMode:      infrav1.PatchModeAutomaticByPlatform,
+					AssessmentMode: infrav1.PatchAssessmentModeAutomaticByPlatform,
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.OSProfile.LinuxConfiguration.PatchSettings).To(Equal(&armcompute.LinuxPatchSettings{
+					PatchMode:      ptr.To(armcompute.LinuxVMGuestPatchModeAutomaticByPlatform),
+					AssessmentMode: ptr.To(armcompute.LinuxPatchAssessmentModeAutomaticByPlatform),
+				}))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with encryption",
 			spec: &VMSpec{
@@ -461,6 +599,42 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with disk names rendered by the naming template",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: ptr.To[int32](128),
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 64,
+						Lun:        ptr.To[int32](0),
+					},
+				},
+				Image: &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:   validSKU,
+				ResourceNames: azure.MachineResourceNames{
+					MachineName: "my-vm",
+					Disk:        "disk-my-vm",
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				storageProfile := result.(armcompute.VirtualMachine).Properties.StorageProfile
+				g.Expect(storageProfile.OSDisk.Name).To(Equal(ptr.To("disk-my-vm")))
+				g.Expect(storageProfile.DataDisks).To(HaveLen(1))
+				g.Expect(storageProfile.DataDisks[0].Name).To(Equal(ptr.To("disk-my-vm_etcddisk")))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a trusted launch vm",
 			spec: &VMSpec{
@@ -835,6 +1009,82 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 does not support ephemeral os. Select a different VM size or disable ephemeral os. Object will not be requeued",
 		},
+		{
+			name: "can create a vm with the NVMe disk controller type",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2s_v6",
+				OSDisk: infrav1.OSDisk{
+					OSType:             "Linux",
+					DiskSizeGB:         ptr.To[int32](128),
+					DiskControllerType: infrav1.DiskControllerTypeNVMe,
+				},
+				Image: &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:   validSKUWithNVMe,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.DiskControllerType).To(Equal(ptr.To(armcompute.DiskControllerTypesNVMe)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create vm with the NVMe disk controller type if the VM size does not support it",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:             "Linux",
+					DiskSizeGB:         ptr.To[int32](128),
+					DiskControllerType: infrav1.DiskControllerTypeNVMe,
+				},
+				Image: &infrav1.Image{ID: ptr.To("fake-image-id")},
+				SKU:   validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 does not support the NVMe disk controller type. Select a different VM size or disk controller type. Object will not be requeued",
+		},
+		{
+			name: "cannot create vm with the NVMe disk controller type from a generation 1 image",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2s_v6",
+				OSDisk: infrav1.OSDisk{
+					OSType:             "Linux",
+					DiskSizeGB:         ptr.To[int32](128),
+					DiskControllerType: infrav1.DiskControllerTypeNVMe,
+				},
+				Image: &infrav1.Image{
+					Marketplace: &infrav1.AzureMarketplaceImage{
+						ImagePlan: infrav1.ImagePlan{
+							Publisher: "cncf-upstream",
+							Offer:     "capi",
+							SKU:       "ubuntu-2204-gen1",
+						},
+						Version: "latest",
+					},
+				},
+				SKU: validSKUWithNVMe,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: image SKU ubuntu-2204-gen1 is a generation 1 image which does not support the NVMe disk controller type. Select a generation 2 image or a different disk controller type. Object will not be requeued",
+		},
 		{
 			name: "cannot create vm if vCPU is less than 2",
 			spec: &VMSpec{
@@ -1235,6 +1485,49 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "creates a vm with hibernation enabled",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
+					HibernationEnabled: ptr.To(true),
+				},
+				SKU: validSKUWithHibernation,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.HibernationEnabled).To(Equal(ptr.To(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create a vm with hibernation enabled if the VM size does not support it",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Location:   "test-location",
+				Image:      &infrav1.Image{ID: ptr.To("fake-image-id")},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
+					HibernationEnabled: ptr.To(true),
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: VM size Standard_D2v3 does not support hibernation. Select a different VM size or disable hibernation. Object will not be requeued",
+		},
 		{
 			name: "creates a vm with Diagnostics disabled",
 			spec: &VMSpec{
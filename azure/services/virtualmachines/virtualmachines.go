@@ -18,7 +18,9 @@ package virtualmachines
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
@@ -38,27 +40,41 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
-const serviceName = "virtualmachine"
+const (
+	serviceName = "virtualmachine"
+
+	// powerStateRequeue is how often the power state of a virtual machine is checked while it is changing.
+	powerStateRequeue = 15 * time.Second
+)
 
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
+	azure.ResourceOwnershipRecorder
 	VMSpec() azure.ResourceSpecGetter
 	SetAnnotation(string, string)
 	SetProviderID(string)
 	SetAddresses([]corev1.NodeAddress)
 	SetVMState(infrav1.ProvisioningState)
+	DesiredPowerState() infrav1.VMPowerState
+	SetPowerState(infrav1.VMPowerState)
+	SetVMCreationTime(time.Time)
+	SetLastBootTime(time.Time)
+	SetConditionTrue(clusterv1.ConditionType)
 	SetConditionFalse(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+	DeleteCondition(clusterv1.ConditionType)
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMScope
 	async.Reconciler
+	async.Getter
 	interfacesGetter async.Getter
 	publicIPsGetter  async.Getter
 	identitiesGetter identities.Client
+	powerStateClient Client
 }
 
 // New creates a new service.
@@ -81,11 +97,13 @@ func New(scope VMScope) (*Service, error) {
 	}
 	return &Service{
 		Scope:            scope,
+		Getter:           Client,
 		interfacesGetter: interfacesSvc,
 		publicIPsGetter:  publicIPsSvc,
 		identitiesGetter: identitiesSvc,
+		powerStateClient: Client,
 		Reconciler: async.New[armcompute.VirtualMachinesClientCreateOrUpdateResponse,
-			armcompute.VirtualMachinesClientDeleteResponse](scope, Client, Client),
+			armcompute.VirtualMachinesClientDeleteResponse](scope, Client, Client).WithDeleteConfirmation(Client),
 	}, nil
 }
 
@@ -107,6 +125,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return nil
 	}
 
+	if s.Scope.IsOwnershipRecorded() {
+		if err := s.recordDisks(ctx, vmSpec); err != nil {
+			s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
+			return err
+		}
+	}
+
 	result, err := s.CreateOrUpdateResource(ctx, vmSpec, serviceName)
 	s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
 	// Set the DiskReady condition here since the disk gets created with the VM.
@@ -123,6 +148,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to parse VM ID %s", infraVM.ID)
 		}
 		s.Scope.SetProviderID(providerID)
+		if vm.Properties != nil {
+			s.Scope.SetVMCreationTime(ptr.Deref(vm.Properties.TimeCreated, time.Now()))
+		}
 		s.Scope.SetAnnotation("cluster-api-provider-azure", "true")
 
 		// Discover addresses for NICs associated with the VM
@@ -142,10 +170,107 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrap(err, "failed to check user assigned identities")
 		}
+
+		return s.reconcilePowerState(ctx, spec, vm)
 	}
 	return err
 }
 
+// reconcilePowerState starts, deallocates or hibernates the virtual machine until its power state is the desired one,
+// and reports a virtual machine that isn't running in the VMRunning condition.
+func (s *Service) reconcilePowerState(ctx context.Context, spec *VMSpec, vm armcompute.VirtualMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcilePowerState")
+	defer done()
+
+	var current infrav1.VMPowerState
+	if vm.Properties != nil {
+		current = converters.SDKToVMPowerState(vm.Properties.InstanceView)
+	}
+	if current == "" {
+		// The power state of a virtual machine that was just created isn't reported yet.
+		return nil
+	}
+	s.reconcileInstanceView(vm.Properties.InstanceView, current)
+	s.Scope.SetPowerState(current)
+
+	desired := s.Scope.DesiredPowerState()
+	if current == infrav1.VMPowerStateDeallocated && desired != infrav1.VMPowerStateDeallocated && isSpotDeallocatedOnEviction(spec.SpotVMOptions) {
+		// A deallocated Spot virtual machine was most likely evicted. It is reported, but not started again, since
+		// it would be billed while running and could be evicted again at any time.
+		s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMSpotEvictedReason, clusterv1.ConditionSeverityInfo, "Spot VM is deallocated, it was likely evicted")
+		return nil
+	}
+	if desired == "" {
+		// CAPZ doesn't manage the power state of the virtual machine, so a virtual machine that isn't running was
+		// stopped outside of CAPZ. It is reported, but not started again.
+		if current != infrav1.VMPowerStateRunning && current != infrav1.VMPowerStateStarting {
+			s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMStoppedOutOfBandReason, clusterv1.ConditionSeverityWarning, fmt.Sprintf("VM is %s, it was stopped outside of CAPZ", current))
+		}
+		return nil
+	}
+	if current == desired {
+		switch current {
+		case infrav1.VMPowerStateDeallocated:
+			s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMDeallocatedReason, clusterv1.ConditionSeverityInfo, "VM is deallocated")
+		case infrav1.VMPowerStateHibernated:
+			s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMHibernatedReason, clusterv1.ConditionSeverityInfo, "VM is hibernated")
+		}
+		return nil
+	}
+
+	var err error
+	switch {
+	case current == infrav1.VMPowerStateStarting || current == infrav1.VMPowerStateStopping || current == infrav1.VMPowerStateDeallocating:
+		// Wait for the ongoing operation to complete.
+	case desired == infrav1.VMPowerStateDeallocated && (current == infrav1.VMPowerStateRunning || current == infrav1.VMPowerStateStopped):
+		log.V(2).Info("deallocating VM", "vm", spec.Name, "powerState", current)
+		err = s.powerStateClient.Deallocate(ctx, spec, false)
+	case desired == infrav1.VMPowerStateHibernated && current == infrav1.VMPowerStateRunning:
+		log.V(2).Info("hibernating VM", "vm", spec.Name, "powerState", current)
+		err = s.powerStateClient.Deallocate(ctx, spec, true)
+	default:
+		// A virtual machine is started to be run, and to be deallocated or hibernated from another power state.
+		log.V(2).Info("starting VM", "vm", spec.Name, "powerState", current)
+		err = s.powerStateClient.Start(ctx, spec)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to change the power state of VM %s from %s to %s", spec.Name, current, desired)
+	}
+
+	s.Scope.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, fmt.Sprintf("VM is %s, changing its power state to %s", current, desired))
+	return azure.WithTransientError(errors.Errorf("power state of VM %s is %s, waiting for it to become %s", spec.Name, current, desired), powerStateRequeue)
+}
+
+// isSpotDeallocatedOnEviction returns whether the virtual machine is a Spot virtual machine deallocated when it is
+// evicted, which is the eviction policy Azure defaults to.
+func isSpotDeallocatedOnEviction(spotVMOptions *infrav1.SpotVMOptions) bool {
+	return spotVMOptions != nil && ptr.Deref(spotVMOptions.EvictionPolicy, infrav1.SpotEvictionPolicyDeallocate) == infrav1.SpotEvictionPolicyDeallocate
+}
+
+// reconcileInstanceView records when a running virtual machine last booted and reports the status of its VM agent in
+// the VMAgentReady condition. The condition is removed while the virtual machine isn't running or its VM agent doesn't
+// report a status, e.g. because the image doesn't include one.
+func (s *Service) reconcileInstanceView(instanceView *armcompute.VirtualMachineInstanceView, powerState infrav1.VMPowerState) {
+	if powerState != infrav1.VMPowerStateRunning {
+		s.Scope.DeleteCondition(infrav1.VMAgentReadyCondition)
+		return
+	}
+
+	if bootTime := converters.SDKToVMLastBootTime(instanceView); bootTime != nil {
+		s.Scope.SetLastBootTime(*bootTime)
+	}
+
+	ready, message, reported := converters.SDKToVMAgentStatus(instanceView)
+	switch {
+	case !reported:
+		s.Scope.DeleteCondition(infrav1.VMAgentReadyCondition)
+	case !ready:
+		s.Scope.SetConditionFalse(infrav1.VMAgentReadyCondition, infrav1.VMAgentNotReadyReason, clusterv1.ConditionSeverityWarning, message)
+	default:
+		s.Scope.SetConditionTrue(infrav1.VMAgentReadyCondition)
+	}
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Delete")
@@ -169,6 +294,40 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
+// recordDisks records the OS and data disks of the virtual machine as created by CAPZ if the virtual machine does not
+// exist yet, as they are created along with it. This ensures the disks of a failed creation are deleted with the machine.
+func (s *Service) recordDisks(ctx context.Context, vmSpec azure.ResourceSpecGetter) error {
+	spec, ok := vmSpec.(*VMSpec)
+	if !ok {
+		return errors.Errorf("%T is not a valid VM spec", vmSpec)
+	}
+
+	names := spec.diskNames()
+	diskIDs := []string{azure.DiskID(s.Scope.SubscriptionID(), spec.ResourceGroup, names.OSDiskName())}
+	for _, disk := range spec.DataDisks {
+		diskIDs = append(diskIDs, azure.DiskID(s.Scope.SubscriptionID(), spec.ResourceGroup, names.DataDiskName(disk.NameSuffix)))
+	}
+
+	recorded := true
+	for _, id := range diskIDs {
+		recorded = recorded && s.Scope.IsManagedResource(id, false)
+	}
+	if recorded {
+		return nil
+	}
+
+	if _, err := s.Getter.Get(ctx, vmSpec); err == nil {
+		return nil
+	} else if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get existing VM %s/%s", spec.ResourceGroup, spec.Name)
+	}
+
+	for _, id := range diskIDs {
+		s.Scope.RecordManagedResource(id)
+	}
+	return nil
+}
+
 func (s *Service) checkUserAssignedIdentities(ctx context.Context, specIdentities []infrav1.UserAssignedIdentity, vmIdentities []infrav1.UserAssignedIdentity) error {
 	expectedMap := make(map[string]struct{})
 	actualMap := make(map[string]struct{})
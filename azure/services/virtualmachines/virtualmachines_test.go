@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
@@ -32,6 +33,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities/mock_identities"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
@@ -43,7 +46,8 @@ import (
 )
 
 var (
-	fakeVMSpec = VMSpec{
+	fakeVMCreationTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fakeVMSpec         = VMSpec{
 		Name:              "test-vm",
 		ResourceGroup:     "test-group",
 		Location:          "test-location",
@@ -63,6 +67,7 @@ var (
 		Name: ptr.To("test-vm-name"),
 		Properties: &armcompute.VirtualMachineProperties{
 			ProvisioningState: ptr.To("Succeeded"),
+			TimeCreated:       ptr.To(fakeVMCreationTime),
 			NetworkProfile: &armcompute.NetworkProfile{
 				NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
 					{
@@ -130,16 +135,20 @@ func internalError() *azcore.ResponseError {
 	}
 }
 
+func notFoundError() *azcore.ResponseError {
+	return &azcore.ResponseError{StatusCode: http.StatusNotFound}
+}
+
 func TestReconcileVM(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
 	}{
 		{
 			name:          "noop if no vm spec is found",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.VMSpec().Return(nil)
 			},
@@ -147,13 +156,15 @@ func TestReconcileVM(t *testing.T) {
 		{
 			name:          "create vm succeeds",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
 				s.SetProviderID("azure://subscriptions/123/resourceGroups/my_resource_group/providers/Microsoft.Compute/virtualMachines/my-vm")
+				s.SetVMCreationTime(fakeVMCreationTime)
 				s.SetAnnotation("cluster-api-provider-azure", "true")
 				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
 				mpip.Get(gomockinternal.AContext(), &fakePublicIPSpec).Return(fakePublicIPs, nil)
@@ -164,9 +175,10 @@ func TestReconcileVM(t *testing.T) {
 		{
 			name:          "creating vm fails",
 			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil, internalError())
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, internalError())
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError())
@@ -175,13 +187,15 @@ func TestReconcileVM(t *testing.T) {
 		{
 			name:          "create vm succeeds but failed to get network interfaces",
 			expectedError: "failed to fetch VM addresses:.*#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
 				s.SetProviderID("azure://subscriptions/123/resourceGroups/my_resource_group/providers/Microsoft.Compute/virtualMachines/my-vm")
+				s.SetVMCreationTime(fakeVMCreationTime)
 				s.SetAnnotation("cluster-api-provider-azure", "true")
 				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(armnetwork.Interface{}, internalError())
 			},
@@ -189,18 +203,52 @@ func TestReconcileVM(t *testing.T) {
 		{
 			name:          "create vm succeeds but failed to get public IPs",
 			expectedError: "failed to fetch VM addresses:.*#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
 				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(false)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
 				s.SetProviderID("azure://subscriptions/123/resourceGroups/my_resource_group/providers/Microsoft.Compute/virtualMachines/my-vm")
+				s.SetVMCreationTime(fakeVMCreationTime)
 				s.SetAnnotation("cluster-api-provider-azure", "true")
 				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
 				mpip.Get(gomockinternal.AContext(), &fakePublicIPSpec).Return(armnetwork.PublicIPAddress{}, internalError())
 			},
 		},
+		{
+			name:          "disks are recorded before the vm is created when ownership is recorded",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				osDiskID := azure.DiskID("123", "test-group", "test-vm_OSDisk")
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.IsManagedResource(osDiskID, false).Return(false)
+				mvm.Get(gomockinternal.AContext(), &fakeVMSpec).Return(nil, notFoundError())
+				s.RecordManagedResource(osDiskID)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil, internalError())
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, internalError())
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError())
+			},
+		},
+		{
+			name:          "disks of an existing vm are not recorded",
+			expectedError: "#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mvm *mock_async.MockGetterMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.IsOwnershipRecorded().Return(true)
+				s.SubscriptionID().AnyTimes().Return("123")
+				s.IsManagedResource(azure.DiskID("123", "test-group", "test-vm_OSDisk"), false).Return(false)
+				mvm.Get(gomockinternal.AContext(), &fakeVMSpec).Return(fakeExistingVM, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil, internalError())
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, internalError())
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError())
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -212,14 +260,16 @@ func TestReconcileVM(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			vmMock := mock_async.NewMockGetter(mockCtrl)
 			interfaceMock := mock_async.NewMockGetter(mockCtrl)
 			publicIPMock := mock_async.NewMockGetter(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), vmMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT(), asyncMock.EXPECT())
 
 			s := &Service{
 				Scope:            scopeMock,
+				Getter:           vmMock,
 				interfacesGetter: interfaceMock,
 				publicIPsGetter:  publicIPMock,
 				Reconciler:       asyncMock,
@@ -236,6 +286,304 @@ func TestReconcileVM(t *testing.T) {
 	}
 }
 
+func TestReconcileVMNotFoundAfterCreation(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	futureScopeMock := mock_async.NewMockFutureScope(mockCtrl)
+	creatorMock := mock_async.NewMockCreator[armcompute.VirtualMachinesClientCreateOrUpdateResponse](mockCtrl)
+	svc := async.New[armcompute.VirtualMachinesClientCreateOrUpdateResponse, armcompute.VirtualMachinesClientDeleteResponse](futureScopeMock, creatorMock, nil)
+
+	spec := fakeVMSpec
+	spec.ProviderID = "azure://subscriptions/123/resourceGroups/test-group/providers/Microsoft.Compute/virtualMachines/test-vm"
+	spec.CreationTime = time.Now().Add(-30 * time.Second)
+	spec.NotFoundGracePeriod = reconciler.DefaultVMNotFoundGracePeriod
+
+	futureScopeMock.EXPECT().GetLongRunningOperationState("test-vm", serviceName, infrav1.PutFuture).Return(nil).Times(2)
+	gomock.InOrder(
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), &spec).Return(nil, notFoundError()),
+		creatorMock.EXPECT().Get(gomockinternal.AContext(), &spec).Return(fakeExistingVM, nil),
+	)
+
+	// The VM isn't visible yet right after its creation, it must be requeued rather than reported as deleted.
+	_, err := svc.CreateOrUpdateResource(context.TODO(), &spec, serviceName)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(errors.As(err, &azure.VMDeletedError{})).To(BeFalse())
+	var reconcileErr azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+	g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+	g.Expect(reconcileErr.RequeueAfter()).To(Equal(vmNotFoundRequeue))
+
+	// Once the VM is visible it is returned as is.
+	result, err := svc.CreateOrUpdateResource(context.TODO(), &spec, serviceName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(fakeExistingVM))
+}
+
+func TestReconcilePowerState(t *testing.T) {
+	vmWithPowerState := func(codes ...string) armcompute.VirtualMachine {
+		vm := armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{InstanceView: &armcompute.VirtualMachineInstanceView{}}}
+		for _, code := range codes {
+			vm.Properties.InstanceView.Statuses = append(vm.Properties.InstanceView.Statuses, &armcompute.InstanceViewStatus{Code: ptr.To(code)})
+		}
+		return vm
+	}
+	bootTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	vmWithAgentStatus := func(displayStatus, message string) armcompute.VirtualMachine {
+		vm := vmWithPowerState("PowerState/running")
+		vm.Properties.InstanceView.Statuses[0].Time = ptr.To(bootTime)
+		vm.Properties.InstanceView.VMAgent = &armcompute.VirtualMachineAgentInstanceView{
+			Statuses: []*armcompute.InstanceViewStatus{
+				{
+					Code:          ptr.To("ProvisioningState/succeeded"),
+					DisplayStatus: ptr.To(displayStatus),
+					Message:       ptr.To(message),
+				},
+			},
+		}
+		return vm
+	}
+	spotVMSpec := fakeVMSpec
+	spotVMSpec.SpotVMOptions = &infrav1.SpotVMOptions{}
+
+	testcases := []struct {
+		name          string
+		spec          *VMSpec
+		vm            armcompute.VirtualMachine
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name: "power state not reported yet",
+			vm:   fakeExistingVM,
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+			},
+		},
+		{
+			name: "running vm",
+			vm:   vmWithPowerState("PowerState/running"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+			},
+		},
+		{
+			name: "deallocated vm",
+			vm:   vmWithPowerState("PowerState/deallocated"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMDeallocatedReason, clusterv1.ConditionSeverityInfo, "VM is deallocated")
+			},
+		},
+		{
+			name: "hibernated vm",
+			vm:   vmWithPowerState("PowerState/deallocated", "HibernationState/Hibernated"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateHibernated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMHibernatedReason, clusterv1.ConditionSeverityInfo, "VM is hibernated")
+			},
+		},
+		{
+			name:          "running vm is hibernated",
+			vm:            vmWithPowerState("PowerState/running"),
+			expectedError: "power state of VM test-vm is Running, waiting for it to become Hibernated",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				c.Deallocate(gomockinternal.AContext(), &fakeVMSpec, true).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Running, changing its power state to Hibernated")
+			},
+		},
+		{
+			name:          "running vm is deallocated",
+			vm:            vmWithPowerState("PowerState/running"),
+			expectedError: "power state of VM test-vm is Running, waiting for it to become Deallocated",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				c.Deallocate(gomockinternal.AContext(), &fakeVMSpec, false).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Running, changing its power state to Deallocated")
+			},
+		},
+		{
+			name:          "hibernated vm is started",
+			vm:            vmWithPowerState("PowerState/deallocated", "HibernationState/Hibernated"),
+			expectedError: "power state of VM test-vm is Hibernated, waiting for it to become Running",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateHibernated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				c.Start(gomockinternal.AContext(), &fakeVMSpec).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Hibernated, changing its power state to Running")
+			},
+		},
+		{
+			name:          "deallocated vm is started before being hibernated",
+			vm:            vmWithPowerState("PowerState/deallocated"),
+			expectedError: "power state of VM test-vm is Deallocated, waiting for it to become Hibernated",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateHibernated)
+				c.Start(gomockinternal.AContext(), &fakeVMSpec).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Deallocated, changing its power state to Hibernated")
+			},
+		},
+		{
+			name:          "waits for an ongoing power state change",
+			vm:            vmWithPowerState("PowerState/deallocating"),
+			expectedError: "power state of VM test-vm is Deallocating, waiting for it to become Deallocated",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocating)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateDeallocated)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Deallocating, changing its power state to Deallocated")
+			},
+		},
+		{
+			name: "running vm with a ready agent",
+			vm:   vmWithAgentStatus("Ready", "Guest Agent is running"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.SetLastBootTime(bootTime)
+				s.SetConditionTrue(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+			},
+		},
+		{
+			name: "running vm with an agent not ready",
+			vm:   vmWithAgentStatus("Not Ready", "VM status blob is found but not yet populated."),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.SetLastBootTime(bootTime)
+				s.SetConditionFalse(infrav1.VMAgentReadyCondition, infrav1.VMAgentNotReadyReason, clusterv1.ConditionSeverityWarning, "VM status blob is found but not yet populated.")
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+			},
+		},
+		{
+			name: "running vm without a desired power state",
+			vm:   vmWithPowerState("PowerState/running"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateRunning)
+				s.DesiredPowerState().Return(infrav1.VMPowerState(""))
+			},
+		},
+		{
+			name: "vm stopped outside of CAPZ is reported but not started",
+			vm:   vmWithPowerState("PowerState/stopped"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateStopped)
+				s.DesiredPowerState().Return(infrav1.VMPowerState(""))
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMStoppedOutOfBandReason, clusterv1.ConditionSeverityWarning, "VM is Stopped, it was stopped outside of CAPZ")
+			},
+		},
+		{
+			name: "vm deallocated outside of CAPZ is reported but not started",
+			vm:   vmWithPowerState("PowerState/deallocated"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerState(""))
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMStoppedOutOfBandReason, clusterv1.ConditionSeverityWarning, "VM is Deallocated, it was stopped outside of CAPZ")
+			},
+		},
+		{
+			name:          "vm deallocated outside of CAPZ is started when its power state is Running",
+			vm:            vmWithPowerState("PowerState/deallocated"),
+			expectedError: "power state of VM test-vm is Deallocated, waiting for it to become Running",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				c.Start(gomockinternal.AContext(), &fakeVMSpec).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Deallocated, changing its power state to Running")
+			},
+		},
+		{
+			name: "evicted spot vm is reported but not started",
+			spec: &spotVMSpec,
+			vm:   vmWithPowerState("PowerState/deallocated"),
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMSpotEvictedReason, clusterv1.ConditionSeverityInfo, "Spot VM is deallocated, it was likely evicted")
+			},
+		},
+		{
+			name:          "stopped spot vm is started when its power state is Running",
+			spec:          &spotVMSpec,
+			vm:            vmWithPowerState("PowerState/stopped"),
+			expectedError: "power state of VM test-vm is Stopped, waiting for it to become Running",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateStopped)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				c.Start(gomockinternal.AContext(), &spotVMSpec).Return(nil)
+				s.SetConditionFalse(infrav1.VMRunningCondition, infrav1.VMPowerStateChangingReason, clusterv1.ConditionSeverityInfo, "VM is Stopped, changing its power state to Running")
+			},
+		},
+		{
+			name:          "starting vm fails",
+			vm:            vmWithPowerState("PowerState/deallocated"),
+			expectedError: "failed to change the power state of VM test-vm from Deallocated to Running:.*#: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, c *mock_virtualmachines.MockClientMockRecorder) {
+				s.DeleteCondition(infrav1.VMAgentReadyCondition)
+				s.SetPowerState(infrav1.VMPowerStateDeallocated)
+				s.DesiredPowerState().Return(infrav1.VMPowerStateRunning)
+				c.Start(gomockinternal.AContext(), &fakeVMSpec).Return(internalError())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				powerStateClient: clientMock,
+			}
+
+			spec := tc.spec
+			if spec == nil {
+				spec = &fakeVMSpec
+			}
+			err := s.reconcilePowerState(context.TODO(), spec, tc.vm)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.ReplaceAll(err.Error(), "\n", "")).To(MatchRegexp(tc.expectedError))
+				if !strings.HasPrefix(tc.expectedError, "failed") {
+					var reconcileErr azure.ReconcileError
+					g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+					g.Expect(reconcileErr.RequeueAfter()).To(Equal(powerStateRequeue))
+				}
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteVM(t *testing.T) {
 	testcases := []struct {
 		name          string
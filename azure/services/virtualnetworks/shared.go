@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"sort"
+
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// A virtual network created by one AzureCluster can be used by other AzureClusters in the same namespace. The ASO
+// resource of the virtual network is controlled by the AzureCluster managing it, and every other AzureCluster using it
+// is recorded as an additional owner of the ASO resource. Owner references are updated with optimistic locking so
+// that clusters deleted at the same time can't both give up or both keep the virtual network. An AzureCluster deleting
+// a virtual network nobody else uses marks it as released, also with optimistic locking, so that no AzureCluster can
+// start using it in the meantime.
+
+// vnetReleasedAnnotation marks the ASO resource of a virtual network which is deleted with the AzureCluster managing it.
+const vnetReleasedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-vnet-released"
+
+// addVNetUser records the owner of the scope as a user of a virtual network managed by another AzureCluster.
+func addVNetUser(ctx context.Context, scope VNetScope, vnet *asonetworkv1.VirtualNetwork) error {
+	owner := scope.ASOOwner()
+	if hasOwnerReference(vnet, owner) {
+		return nil
+	}
+	if _, ok := vnet.GetAnnotations()[vnetReleasedAnnotation]; ok {
+		return errors.Errorf("virtual network %s is being deleted with the AzureCluster managing it", vnet.Name)
+	}
+	patched := vnet.DeepCopy()
+	if err := controllerutil.SetOwnerReference(owner, patched, scope.GetClient().Scheme()); err != nil {
+		return errors.Wrap(err, "failed to set owner reference")
+	}
+	if err := scope.GetClient().Patch(ctx, patched, client.MergeFromWithOptions(vnet, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to record %s as a user of virtual network %s", owner.GetName(), vnet.Name)
+	}
+	return nil
+}
+
+// VNetUsers returns the names of the AzureClusters, other than the one of the scope, which use the virtual network of
+// the scope and aren't being deleted.
+func VNetUsers(ctx context.Context, scope VNetScope) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.VNetUsers")
+	defer done()
+
+	vnet, err := getVNet(ctx, scope)
+	if err != nil || vnet == nil {
+		return nil, err
+	}
+	users, err := liveUsers(ctx, scope, vnet)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	return names, nil
+}
+
+// ReleaseVNet releases the virtual network of the scope before its AzureCluster is deleted. A virtual network managed
+// by the AzureCluster and still used by other AzureClusters is handed over to the oldest of them, whose name is
+// returned, so that it isn't deleted with the cluster. An AzureCluster using a virtual network managed by another
+// AzureCluster stops being recorded as a user.
+func ReleaseVNet(ctx context.Context, scope VNetScope) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.ReleaseVNet")
+	defer done()
+
+	vnet, err := getVNet(ctx, scope)
+	if err != nil || vnet == nil {
+		return "", err
+	}
+
+	owner := scope.ASOOwner()
+	if !hasOwnerReference(vnet, owner) {
+		return "", nil
+	}
+	var newOwner *infrav1.AzureCluster
+	if metav1.IsControlledBy(vnet, owner) {
+		users, err := liveUsers(ctx, scope, vnet)
+		if err != nil {
+			return "", err
+		}
+		if len(users) == 0 {
+			return "", markVNetReleased(ctx, scope, vnet)
+		}
+		newOwner = users[0]
+	}
+
+	patched := vnet.DeepCopy()
+	patched.OwnerReferences = nil
+	for _, ref := range vnet.OwnerReferences {
+		if ref.UID == owner.GetUID() {
+			continue
+		}
+		if newOwner != nil && ref.UID == newOwner.UID {
+			ref.Controller = ptr.To(true)
+			ref.BlockOwnerDeletion = ptr.To(true)
+		}
+		patched.OwnerReferences = append(patched.OwnerReferences, ref)
+	}
+	if err := scope.GetClient().Patch(ctx, patched, client.MergeFromWithOptions(vnet, client.MergeFromWithOptimisticLock{})); err != nil {
+		return "", errors.Wrapf(err, "failed to release virtual network %s", vnet.Name)
+	}
+	if newOwner == nil {
+		return "", nil
+	}
+	log.V(2).Info("handed over shared virtual network", "vnet", vnet.Name, "newOwner", newOwner.Name)
+	return newOwner.Name, nil
+}
+
+// markVNetReleased marks a virtual network without other users as released, so that an AzureCluster which started
+// using it since it was read gets a conflict, and no AzureCluster starts using it afterwards.
+func markVNetReleased(ctx context.Context, scope VNetScope, vnet *asonetworkv1.VirtualNetwork) error {
+	if _, ok := vnet.GetAnnotations()[vnetReleasedAnnotation]; ok {
+		return nil
+	}
+	patched := vnet.DeepCopy()
+	annotations := patched.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[vnetReleasedAnnotation] = "true"
+	patched.SetAnnotations(annotations)
+	if err := scope.GetClient().Patch(ctx, patched, client.MergeFromWithOptions(vnet, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to release virtual network %s", vnet.Name)
+	}
+	return nil
+}
+
+// getVNet returns the ASO resource of the virtual network of the scope, or nil if it doesn't exist.
+func getVNet(ctx context.Context, scope VNetScope) (*asonetworkv1.VirtualNetwork, error) {
+	vnet := &asonetworkv1.VirtualNetwork{}
+	key := client.ObjectKey{Namespace: scope.ASOOwner().GetNamespace(), Name: scope.VNetSpec().ResourceRef().GetName()}
+	if err := scope.GetClient().Get(ctx, key, vnet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get virtual network %s", key.Name)
+	}
+	return vnet, nil
+}
+
+// liveUsers returns the AzureClusters recorded as owners of the virtual network, other than the owner of the scope,
+// which still exist and aren't being deleted, oldest first.
+func liveUsers(ctx context.Context, scope VNetScope, vnet *asonetworkv1.VirtualNetwork) ([]*infrav1.AzureCluster, error) {
+	owner := scope.ASOOwner()
+	var users []*infrav1.AzureCluster
+	for _, ref := range vnet.OwnerReferences {
+		if ref.UID == owner.GetUID() || ref.Kind != infrav1.AzureClusterKind || ref.APIVersion != infrav1.GroupVersion.String() {
+			continue
+		}
+		user := &infrav1.AzureCluster{}
+		if err := scope.GetClient().Get(ctx, client.ObjectKey{Namespace: vnet.Namespace, Name: ref.Name}, user); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get AzureCluster %s using virtual network %s", ref.Name, vnet.Name)
+		}
+		if user.UID != ref.UID || !user.DeletionTimestamp.IsZero() {
+			continue
+		}
+		users = append(users, user)
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if !users[i].CreationTimestamp.Equal(&users[j].CreationTimestamp) {
+			return users[i].CreationTimestamp.Before(&users[j].CreationTimestamp)
+		}
+		return users[i].Name < users[j].Name
+	})
+	return users, nil
+}
+
+func hasOwnerReference(obj metav1.Object, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetworks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks/mock_virtualnetworks"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var sharedVNetCreated = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sharedVNetCluster(name string, age time.Duration) *infrav1.AzureCluster {
+	return &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(name + "-uid"),
+			CreationTimestamp: metav1.NewTime(sharedVNetCreated.Add(-age)),
+		},
+	}
+}
+
+func sharedVNetOwnerRef(cluster *infrav1.AzureCluster, controller bool) metav1.OwnerReference {
+	ref := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       infrav1.AzureClusterKind,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+	if controller {
+		ref.Controller = ptr.To(true)
+		ref.BlockOwnerDeletion = ptr.To(true)
+	}
+	return ref
+}
+
+func sharedVNet(refs ...metav1.OwnerReference) *asonetworkv1.VirtualNetwork {
+	return &asonetworkv1.VirtualNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "vnet",
+			Namespace:       "default",
+			OwnerReferences: refs,
+		},
+	}
+}
+
+func newSharedVNetScope(t *testing.T, owner *infrav1.AzureCluster, c client.Client) *mock_virtualnetworks.MockVNetScope {
+	t.Helper()
+	scope := mock_virtualnetworks.NewMockVNetScope(gomock.NewController(t))
+	scope.EXPECT().ASOOwner().Return(owner).AnyTimes()
+	scope.EXPECT().GetClient().Return(c).AnyTimes()
+	scope.EXPECT().VNetSpec().Return(&VNetSpec{Name: "vnet"}).AnyTimes()
+	return scope
+}
+
+func newSharedVNetClient(t *testing.T, funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	NewGomegaWithT(t).Expect(asonetworkv1.AddToScheme(s)).To(Succeed())
+	NewGomegaWithT(t).Expect(infrav1.AddToScheme(s)).To(Succeed())
+	return fakeclient.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func TestAddVNetUser(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	first := sharedVNetCluster("first", 2*time.Hour)
+	second := sharedVNetCluster("second", time.Hour)
+	c := newSharedVNetClient(t, interceptor.Funcs{}, first, second, sharedVNet(sharedVNetOwnerRef(first, true)))
+	scope := newSharedVNetScope(t, second, c)
+
+	vnet := &asonetworkv1.VirtualNetwork{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "vnet"}, vnet)).To(Succeed())
+	g.Expect(addVNetUser(context.Background(), scope, vnet)).To(Succeed())
+
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "vnet"}, vnet)).To(Succeed())
+	g.Expect(vnet.OwnerReferences).To(ConsistOf(
+		sharedVNetOwnerRef(first, true),
+		sharedVNetOwnerRef(second, false),
+	))
+
+	// Recording the same user again doesn't change the owner references.
+	g.Expect(addVNetUser(context.Background(), scope, vnet)).To(Succeed())
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "vnet"}, vnet)).To(Succeed())
+	g.Expect(vnet.OwnerReferences).To(HaveLen(2))
+}
+
+func TestVNetUsers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	owner := sharedVNetCluster("owner", 4*time.Hour)
+	older := sharedVNetCluster("older", 3*time.Hour)
+	newer := sharedVNetCluster("newer", time.Hour)
+	deleting := sharedVNetCluster("deleting", 2*time.Hour)
+	deleting.DeletionTimestamp = ptr.To(metav1.Now())
+	deleting.Finalizers = []string{infrav1.ClusterFinalizer}
+	recreated := sharedVNetCluster("recreated", 2*time.Hour)
+	staleRef := sharedVNetOwnerRef(recreated, false)
+	staleRef.UID = "previous-uid"
+	gone := sharedVNetCluster("gone", 2*time.Hour)
+
+	c := newSharedVNetClient(t, interceptor.Funcs{}, owner, older, newer, deleting, recreated, sharedVNet(
+		sharedVNetOwnerRef(owner, true),
+		sharedVNetOwnerRef(newer, false),
+		sharedVNetOwnerRef(deleting, false),
+		staleRef,
+		sharedVNetOwnerRef(gone, false),
+		sharedVNetOwnerRef(older, false),
+	))
+
+	users, err := VNetUsers(context.Background(), newSharedVNetScope(t, owner, c))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(users).To(Equal([]string{"older", "newer"}))
+}
+
+func TestReleaseVNet(t *testing.T) {
+	key := client.ObjectKey{Namespace: "default", Name: "vnet"}
+
+	t.Run("virtual network doesn't exist", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", time.Hour)
+		c := newSharedVNetClient(t, interceptor.Funcs{}, owner)
+
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(BeEmpty())
+	})
+
+	t.Run("virtual network used only by its owner is kept for deletion", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", time.Hour)
+		c := newSharedVNetClient(t, interceptor.Funcs{}, owner, sharedVNet(sharedVNetOwnerRef(owner, true)))
+
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(BeEmpty())
+
+		vnet := &asonetworkv1.VirtualNetwork{}
+		g.Expect(c.Get(context.Background(), key, vnet)).To(Succeed())
+		g.Expect(vnet.OwnerReferences).To(ConsistOf(sharedVNetOwnerRef(owner, true)))
+		g.Expect(vnet.Annotations).To(HaveKey(vnetReleasedAnnotation))
+
+		// A cluster can't start using the released virtual network.
+		user := sharedVNetCluster("user", time.Minute)
+		g.Expect(c.Create(context.Background(), user)).To(Succeed())
+		g.Expect(addVNetUser(context.Background(), newSharedVNetScope(t, user, c), vnet)).NotTo(Succeed())
+	})
+
+	t.Run("cluster starting to use the virtual network while its owner releases it gets it handed over", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", 2*time.Hour)
+		user := sharedVNetCluster("user", time.Hour)
+		raced := false
+		c := newSharedVNetClient(t, interceptor.Funcs{
+			Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if !raced {
+					// The other cluster records itself as a user between the read and the patch.
+					raced = true
+					other := &asonetworkv1.VirtualNetwork{}
+					g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), other)).To(Succeed())
+					other.OwnerReferences = append(other.OwnerReferences, sharedVNetOwnerRef(user, false))
+					g.Expect(cl.Update(ctx, other)).To(Succeed())
+				}
+				return cl.Patch(ctx, obj, patch, opts...)
+			},
+		}, owner, user, sharedVNet(sharedVNetOwnerRef(owner, true)))
+
+		_, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(apierrors.IsConflict(err)).To(BeTrue(), "expected a conflict, got %v", err)
+
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(Equal("user"))
+
+		vnet := &asonetworkv1.VirtualNetwork{}
+		g.Expect(c.Get(context.Background(), key, vnet)).To(Succeed())
+		g.Expect(vnet.OwnerReferences).To(ConsistOf(sharedVNetOwnerRef(user, true)))
+		g.Expect(vnet.Annotations).NotTo(HaveKey(vnetReleasedAnnotation))
+	})
+
+	t.Run("virtual network is handed over to the oldest live user", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", 3*time.Hour)
+		older := sharedVNetCluster("older", 2*time.Hour)
+		newer := sharedVNetCluster("newer", time.Hour)
+		c := newSharedVNetClient(t, interceptor.Funcs{}, owner, older, newer, sharedVNet(
+			sharedVNetOwnerRef(owner, true),
+			sharedVNetOwnerRef(newer, false),
+			sharedVNetOwnerRef(older, false),
+		))
+
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(Equal("older"))
+
+		vnet := &asonetworkv1.VirtualNetwork{}
+		g.Expect(c.Get(context.Background(), key, vnet)).To(Succeed())
+		g.Expect(vnet.OwnerReferences).To(ConsistOf(
+			sharedVNetOwnerRef(newer, false),
+			sharedVNetOwnerRef(older, true),
+		))
+	})
+
+	t.Run("user stops being recorded", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", 2*time.Hour)
+		user := sharedVNetCluster("user", time.Hour)
+		c := newSharedVNetClient(t, interceptor.Funcs{}, owner, user, sharedVNet(
+			sharedVNetOwnerRef(owner, true),
+			sharedVNetOwnerRef(user, false),
+		))
+
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, user, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(BeEmpty())
+
+		vnet := &asonetworkv1.VirtualNetwork{}
+		g.Expect(c.Get(context.Background(), key, vnet)).To(Succeed())
+		g.Expect(vnet.OwnerReferences).To(ConsistOf(sharedVNetOwnerRef(owner, true)))
+	})
+
+	t.Run("clusters deleted at the same time don't both give up the virtual network", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		owner := sharedVNetCluster("owner", 2*time.Hour)
+		user := sharedVNetCluster("user", time.Hour)
+		raced := false
+		c := newSharedVNetClient(t, interceptor.Funcs{
+			Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if !raced {
+					// The other cluster releases the virtual network between the read and the patch.
+					raced = true
+					other := &asonetworkv1.VirtualNetwork{}
+					g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(obj), other)).To(Succeed())
+					other.OwnerReferences = []metav1.OwnerReference{sharedVNetOwnerRef(owner, true)}
+					g.Expect(cl.Update(ctx, other)).To(Succeed())
+				}
+				return cl.Patch(ctx, obj, patch, opts...)
+			},
+		}, owner, user, sharedVNet(
+			sharedVNetOwnerRef(owner, true),
+			sharedVNetOwnerRef(user, false),
+		))
+
+		_, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(apierrors.IsConflict(err)).To(BeTrue(), "expected a conflict, got %v", err)
+
+		// Retrying against the latest state leaves the virtual network to be deleted with its owner.
+		newOwner, err := ReleaseVNet(context.Background(), newSharedVNetScope(t, owner, c))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newOwner).To(BeEmpty())
+
+		vnet := &asonetworkv1.VirtualNetwork{}
+		g.Expect(c.Get(context.Background(), key, vnet)).To(Succeed())
+		g.Expect(vnet.OwnerReferences).To(ConsistOf(sharedVNetOwnerRef(owner, true)))
+	})
+}
@@ -18,12 +18,16 @@ package virtualnetworks
 
 import (
 	"context"
+	"net"
 
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
 
@@ -36,6 +40,12 @@ type VNetSpec struct {
 	ExtendedLocation *infrav1.ExtendedLocationSpec
 	ClusterName      string
 	AdditionalTags   infrav1.Tags
+	// IsManaged, when set, determines whether CAPZ created the virtual network given the result of the
+	// tag-based check, e.g. by consulting the resources recorded as created by CAPZ.
+	IsManaged func(ownedByTags bool) bool
+	// GetPeeredAddressSpaces, when set, returns the address space of the virtual networks peered with the virtual
+	// network, which CIDR blocks added to it must not overlap.
+	GetPeeredAddressSpaces func(ctx context.Context) ([]string, error)
 }
 
 // ResourceRef implements azure.ASOResourceSpecGetter.
@@ -49,6 +59,10 @@ func (s *VNetSpec) ResourceRef() *asonetworkv1.VirtualNetwork {
 
 // Parameters implements azure.ASOResourceSpecGetter.
 func (s *VNetSpec) Parameters(ctx context.Context, existing *asonetworkv1.VirtualNetwork) (*asonetworkv1.VirtualNetwork, error) {
+	if err := s.validateAddedCIDRs(ctx, existing); err != nil {
+		return nil, err
+	}
+
 	vnet := existing
 	if existing == nil {
 		vnet = &asonetworkv1.VirtualNetwork{
@@ -77,7 +91,48 @@ func (s *VNetSpec) Parameters(ctx context.Context, existing *asonetworkv1.Virtua
 	return vnet, nil
 }
 
+// validateAddedCIDRs returns a terminal error if the CIDR blocks added to an existing virtual network overlap the
+// address space of a peered virtual network, which Azure rejects.
+func (s *VNetSpec) validateAddedCIDRs(ctx context.Context, existing *asonetworkv1.VirtualNetwork) error {
+	if existing == nil || existing.Spec.AddressSpace == nil || s.GetPeeredAddressSpaces == nil {
+		return nil
+	}
+	var added []*net.IPNet
+	for _, cidr := range s.CIDRs {
+		if slices.Contains(existing.Spec.AddressSpace.AddressPrefixes, cidr) {
+			continue
+		}
+		if _, nw, err := net.ParseCIDR(cidr); err == nil {
+			added = append(added, nw)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	peeredAddressSpaces, err := s.GetPeeredAddressSpaces(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the address space of the peered virtual networks")
+	}
+	for _, peered := range peeredAddressSpaces {
+		_, peeredNw, err := net.ParseCIDR(peered)
+		if err != nil {
+			continue
+		}
+		for _, nw := range added {
+			if nw.Contains(peeredNw.IP) || peeredNw.Contains(nw.IP) {
+				return azure.WithTerminalError(errors.Errorf("CIDR block %s added to virtual network %s overlaps with the address space %s of a peered virtual network", nw, s.Name, peered))
+			}
+		}
+	}
+	return nil
+}
+
 // WasManaged implements azure.ASOResourceSpecGetter.
 func (s *VNetSpec) WasManaged(resource *asonetworkv1.VirtualNetwork) bool {
-	return infrav1.Tags(resource.Status.Tags).HasOwned(s.ClusterName)
+	ownedByTags := infrav1.Tags(resource.Status.Tags).HasOwned(s.ClusterName)
+	if s.IsManaged != nil {
+		return s.IsManaged(ownedByTags)
+	}
+	return ownedByTags
 }
@@ -24,8 +24,10 @@ import (
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 func TestParameters(t *testing.T) {
@@ -126,3 +128,106 @@ func TestParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestParametersAddedCIDRs(t *testing.T) {
+	existing := func() *asonetworkv1.VirtualNetwork {
+		return &asonetworkv1.VirtualNetwork{
+			Spec: asonetworkv1.VirtualNetwork_Spec{
+				AddressSpace: &asonetworkv1.AddressSpace{
+					AddressPrefixes: []string{"10.0.0.0/16"},
+				},
+			},
+		}
+	}
+	peeredAddressSpaces := func(context.Context) ([]string, error) {
+		return []string{"10.0.0.0/16", "10.1.0.0/16"}, nil
+	}
+
+	tests := []struct {
+		name          string
+		cidrs         []string
+		expectedError string
+	}{
+		{
+			name:  "unchanged address space",
+			cidrs: []string{"10.0.0.0/16"},
+		},
+		{
+			name:  "CIDR block appended",
+			cidrs: []string{"10.0.0.0/16", "10.2.0.0/16"},
+		},
+		{
+			name:          "appended CIDR block overlaps with a peered virtual network",
+			cidrs:         []string{"10.0.0.0/16", "10.1.128.0/17"},
+			expectedError: "CIDR block 10.1.128.0/17 added to virtual network name overlaps with the address space 10.1.0.0/16 of a peered virtual network",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			spec := VNetSpec{
+				ResourceGroup:          "rg",
+				Name:                   "name",
+				CIDRs:                  test.cidrs,
+				GetPeeredAddressSpaces: peeredAddressSpaces,
+			}
+			actual, err := spec.Parameters(context.Background(), existing())
+			if test.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(test.expectedError)))
+				var reconcileErr azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+				g.Expect(reconcileErr.IsTerminal()).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual.Spec.AddressSpace.AddressPrefixes).To(Equal(test.cidrs))
+		})
+	}
+}
+
+func TestWasManaged(t *testing.T) {
+	ownedVnet := &asonetworkv1.VirtualNetwork{
+		Status: asonetworkv1.VirtualNetwork_STATUS{
+			Tags: map[string]string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster": "owned",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		spec     VNetSpec
+		existing *asonetworkv1.VirtualNetwork
+		expected bool
+	}{
+		{
+			name:     "owned by tags",
+			spec:     VNetSpec{ClusterName: "cluster"},
+			existing: ownedVnet,
+			expected: true,
+		},
+		{
+			name:     "not owned by tags",
+			spec:     VNetSpec{ClusterName: "cluster"},
+			existing: &asonetworkv1.VirtualNetwork{},
+			expected: false,
+		},
+		{
+			name: "owned by tags but not created by CAPZ",
+			spec: VNetSpec{
+				ClusterName: "cluster",
+				IsManaged:   func(ownedByTags bool) bool { return false },
+			},
+			existing: ownedVnet,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(test.spec.WasManaged(test.existing)).To(Equal(test.expected))
+		})
+	}
+}
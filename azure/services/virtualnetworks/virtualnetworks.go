@@ -20,8 +20,11 @@ import (
 	"context"
 
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
+	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/labels"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -35,6 +38,7 @@ const serviceName = "virtualnetworks"
 // VNetScope defines the scope interface for a virtual network service.
 type VNetScope interface {
 	aso.Scope
+	azure.ResourceOwnershipRecorder
 	Vnet() *infrav1.VnetSpec
 	VNetSpec() azure.ASOResourceSpecGetter[*asonetworkv1.VirtualNetwork]
 	UpdateSubnetCIDRs(string, []string)
@@ -54,10 +58,24 @@ func postCreateOrUpdateResourceHook(ctx context.Context, scope VNetScope, existi
 		return err
 	}
 
+	// A virtual network created by another AzureCluster in the namespace is shared with it.
+	controlledByOwner := metav1.IsControlledBy(existingVnet, scope.ASOOwner())
+	if controller := metav1.GetControllerOf(existingVnet); controller != nil && !controlledByOwner {
+		if err := addVNetUser(ctx, scope, existingVnet); err != nil {
+			return err
+		}
+	}
+
 	vnet := scope.Vnet()
 	vnet.ID = ptr.Deref(existingVnet.Status.Id, "")
 	vnet.Tags = existingVnet.Status.Tags
 
+	// ASO only actively manages a virtual network that CAPZ adopted, which happens when the virtual network
+	// did not exist in Azure and CAPZ created it.
+	if controlledByOwner && existingVnet.GetAnnotations()[asoannotations.ReconcilePolicy] == string(asoannotations.ReconcilePolicyManage) {
+		scope.RecordManagedResource(vnet.ID)
+	}
+
 	// Update the subnet CIDRs if they already exist.
 	// This makes sure the subnet CIDRs are up to date and there are no validation errors when updating the VNet.
 	// Subnets that are not part of this cluster spec are silently ignored.
@@ -74,10 +92,19 @@ func postCreateOrUpdateResourceHook(ctx context.Context, scope VNetScope, existi
 	}
 	// Only update the vnet's CIDRBlocks when we also updated subnets' since the vnet is created before
 	// subnets to prevent an updated vnet CIDR from invalidating subnet CIDRs that were defaulted and do not
-	// exist yet.
-	if len(subnets.Items) > 0 && existingVnet.Status.AddressSpace != nil {
+	// exist yet. The status is also ignored until ASO applied the latest spec, so that CIDR blocks just added
+	// to the address space aren't reverted.
+	if len(subnets.Items) > 0 && existingVnet.Status.AddressSpace != nil && observedLatestSpec(existingVnet) {
 		vnet.CIDRBlocks = existingVnet.Status.AddressSpace.AddressPrefixes
 	}
 
 	return nil
 }
+
+// observedLatestSpec returns whether ASO reconciled the latest spec of the virtual network, so that its status
+// reflects the spec.
+func observedLatestSpec(vnet *asonetworkv1.VirtualNetwork) bool {
+	conds := vnet.GetConditions()
+	i, ok := conds.FindIndexByType(conditions.ConditionTypeReady)
+	return !ok || conds[i].ObservedGeneration == vnet.GetGeneration()
+}
@@ -24,6 +24,7 @@ import (
 	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	"github.com/Azure/azure-service-operator/v2/pkg/common/labels"
 	"github.com/Azure/azure-service-operator/v2/pkg/genruntime"
+	"github.com/Azure/azure-service-operator/v2/pkg/genruntime/conditions"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -65,6 +66,7 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 
 		vnet := &infrav1.VnetSpec{}
 		scope.EXPECT().Vnet().Return(vnet)
+		scope.EXPECT().ASOOwner().Return(&infrav1.AzureCluster{})
 
 		subnets := []client.Object{
 			&asonetworkv1.VirtualNetworksSubnet{
@@ -108,4 +110,67 @@ func TestPostCreateOrUpdateResourceHook(t *testing.T) {
 		g.Expect(vnet.Tags).To(Equal(infrav1.Tags{"actual": "tags"}))
 		g.Expect(vnet.CIDRBlocks).To(Equal([]string{"cidr"}))
 	})
+
+	t.Run("CIDR blocks added to the address space are kept until ASO applies them", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		mockCtrl := gomock.NewController(t)
+		scope := mock_virtualnetworks.NewMockVNetScope(mockCtrl)
+
+		existing := &asonetworkv1.VirtualNetwork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "vnet",
+				Generation: 2,
+			},
+			Status: asonetworkv1.VirtualNetwork_STATUS{
+				Id: ptr.To("id"),
+				AddressSpace: &asonetworkv1.AddressSpace_STATUS{
+					AddressPrefixes: []string{"10.0.0.0/16"},
+				},
+				Conditions: []conditions.Condition{
+					{
+						Type:               conditions.ConditionTypeReady,
+						Status:             metav1.ConditionTrue,
+						ObservedGeneration: 1,
+					},
+				},
+			},
+		}
+
+		vnet := &infrav1.VnetSpec{
+			VnetClassSpec: infrav1.VnetClassSpec{
+				CIDRBlocks: []string{"10.0.0.0/16", "10.1.0.0/16"},
+			},
+		}
+		scope.EXPECT().Vnet().Return(vnet)
+		scope.EXPECT().ASOOwner().Return(&infrav1.AzureCluster{})
+
+		subnet := &asonetworkv1.VirtualNetworksSubnet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "subnet",
+				Labels: map[string]string{
+					labels.OwnerNameLabel: existing.Name,
+				},
+			},
+			Spec: asonetworkv1.VirtualNetworks_Subnet_Spec{
+				AzureName: "azure-name",
+			},
+			Status: asonetworkv1.VirtualNetworks_Subnet_STATUS{
+				AddressPrefixes: []string{"10.0.0.0/24"},
+			},
+		}
+		scope.EXPECT().UpdateSubnetCIDRs("azure-name", []string{"10.0.0.0/24"})
+
+		s := runtime.NewScheme()
+		g.Expect(asonetworkv1.AddToScheme(s)).To(Succeed())
+		c := fakeclient.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(subnet).
+			Build()
+		scope.EXPECT().GetClient().Return(c)
+
+		g.Expect(postCreateOrUpdateResourceHook(context.Background(), scope, existing, nil)).To(Succeed())
+
+		g.Expect(vnet.CIDRBlocks).To(Equal([]string{"10.0.0.0/16", "10.1.0.0/16"}))
+	})
 }
@@ -59,6 +59,21 @@ func (m *MockVMExtensionScope) EXPECT() *MockVMExtensionScopeMockRecorder {
 	return m.recorder
 }
 
+// AnnotationJSON mocks base method.
+func (m *MockVMExtensionScope) AnnotationJSON(arg0 string) (map[string]any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnnotationJSON", arg0)
+	ret0, _ := ret[0].(map[string]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnnotationJSON indicates an expected call of AnnotationJSON.
+func (mr *MockVMExtensionScopeMockRecorder) AnnotationJSON(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnotationJSON", reflect.TypeOf((*MockVMExtensionScope)(nil).AnnotationJSON), arg0)
+}
+
 // BaseURI mocks base method.
 func (m *MockVMExtensionScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -115,6 +130,20 @@ func (mr *MockVMExtensionScopeMockRecorder) CloudEnvironment() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockVMExtensionScope)(nil).CloudEnvironment))
 }
 
+// ClusterVMExtensionNames mocks base method.
+func (m *MockVMExtensionScope) ClusterVMExtensionNames() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterVMExtensionNames")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ClusterVMExtensionNames indicates an expected call of ClusterVMExtensionNames.
+func (mr *MockVMExtensionScopeMockRecorder) ClusterVMExtensionNames() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterVMExtensionNames", reflect.TypeOf((*MockVMExtensionScope)(nil).ClusterVMExtensionNames))
+}
+
 // DefaultedAzureCallTimeout mocks base method.
 func (m *MockVMExtensionScope) DefaultedAzureCallTimeout() time.Duration {
 	m.ctrl.T.Helper()
@@ -197,6 +226,34 @@ func (mr *MockVMExtensionScopeMockRecorder) HashKey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockVMExtensionScope)(nil).HashKey))
 }
 
+// Name mocks base method.
+func (m *MockVMExtensionScope) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockVMExtensionScopeMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockVMExtensionScope)(nil).Name))
+}
+
+// NodeResourceGroup mocks base method.
+func (m *MockVMExtensionScope) NodeResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NodeResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NodeResourceGroup indicates an expected call of NodeResourceGroup.
+func (mr *MockVMExtensionScopeMockRecorder) NodeResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockVMExtensionScope)(nil).NodeResourceGroup))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockVMExtensionScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -251,6 +308,20 @@ func (mr *MockVMExtensionScopeMockRecorder) Token() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockVMExtensionScope)(nil).Token))
 }
 
+// UpdateAnnotationJSON mocks base method.
+func (m *MockVMExtensionScope) UpdateAnnotationJSON(arg0 string, arg1 map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAnnotationJSON", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAnnotationJSON indicates an expected call of UpdateAnnotationJSON.
+func (mr *MockVMExtensionScopeMockRecorder) UpdateAnnotationJSON(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnnotationJSON", reflect.TypeOf((*MockVMExtensionScope)(nil).UpdateAnnotationJSON), arg0, arg1)
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockVMExtensionScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
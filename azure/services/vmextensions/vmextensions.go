@@ -34,6 +34,11 @@ type VMExtensionScope interface {
 	azure.Authorizer
 	azure.AsyncStatusUpdater
 	VMExtensionSpecs() []azure.ResourceSpecGetter
+	ClusterVMExtensionNames() []string
+	Name() string
+	NodeResourceGroup() string
+	AnnotationJSON(string) (map[string]interface{}, error)
+	UpdateAnnotationJSON(string, map[string]interface{}) error
 }
 
 // Service provides operations on Azure resources.
@@ -60,7 +65,9 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
-// Reconcile idempotently creates or updates a VM extension.
+// Reconcile idempotently creates or updates the VM extensions, including the ones added to an existing VM, and removes
+// the extensions of the cluster which are no longer desired. The installed extensions of the cluster are recorded in
+// an annotation so that they can be removed after they are dropped from the cluster.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.Reconcile")
 	defer done()
@@ -69,10 +76,19 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	defer cancel()
 
 	specs := s.Scope.VMExtensionSpecs()
-	if len(specs) == 0 {
+	lastApplied, err := s.Scope.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 && len(lastApplied) == 0 {
 		return nil
 	}
 
+	removeErr := s.removeClusterExtensions(ctx, specs, lastApplied)
+	if len(specs) == 0 {
+		return removeErr
+	}
+
 	// We go through the list of ExtensionSpecs to reconcile each one, independently of the result of the previous one.
 	// If multiple errors occur, we return the most pressing one.
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
@@ -93,6 +109,48 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	s.Scope.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, resultErr)
+	if resultErr != nil {
+		return resultErr
+	}
+	return removeErr
+}
+
+// removeClusterExtensions deletes the extensions of the cluster recorded in the last applied annotation which are no
+// longer desired, and records the extensions of the cluster which are installed on the VM.
+func (s *Service) removeClusterExtensions(ctx context.Context, specs []azure.ResourceSpecGetter, lastApplied map[string]interface{}) error {
+	desired := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		desired[spec.ResourceName()] = true
+	}
+	applied := map[string]interface{}{}
+	for _, name := range s.Scope.ClusterVMExtensionNames() {
+		applied[name] = true
+	}
+
+	var resultErr error
+	for name := range lastApplied {
+		if desired[name] {
+			continue
+		}
+		spec := &VMExtensionSpec{
+			ExtensionSpec: azure.ExtensionSpec{
+				Name:   name,
+				VMName: s.Scope.Name(),
+			},
+			ResourceGroup: s.Scope.NodeResourceGroup(),
+		}
+		if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+			// Keep track of the extension until it is removed.
+			applied[name] = true
+			if !azure.IsOperationNotDoneError(err) || resultErr == nil {
+				resultErr = errors.Wrapf(err, "failed to remove VM extension %s", name)
+			}
+		}
+	}
+
+	if err := s.Scope.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, applied); err != nil {
+		return err
+	}
 	return resultErr
 }
 
@@ -58,6 +58,14 @@ var (
 		Location:      "test-location",
 	}
 
+	removedExtensionSpec = VMExtensionSpec{
+		ExtensionSpec: azure.ExtensionSpec{
+			Name:   "my-extension-2",
+			VMName: "my-vm",
+		},
+		ResourceGroup: "my-rg",
+	}
+
 	notDoneError          = azure.NewOperationNotDoneError(&infrav1.Future{})
 	extensionNotDoneError = errors.Wrapf(notDoneError, "extension is still in provisioning state. This likely means that bootstrapping has not yet completed on the VM")
 )
@@ -86,6 +94,9 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
 				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
@@ -96,6 +107,9 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: extensionFailedError().Error(),
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
 				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, internalError())
 				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomockinternal.ErrStrEq(extensionFailedError().Error()))
@@ -106,6 +120,9 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: extensionNotDoneError.Error(),
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
 				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, notDoneError)
 				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomockinternal.ErrStrEq(extensionNotDoneError.Error()))
@@ -116,6 +133,9 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
 				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1, &extensionSpec2})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec2, serviceName).Return(nil, nil)
@@ -127,12 +147,73 @@ func TestReconcileVMExtension(t *testing.T) {
 			expectedError: extensionFailedError().Error(),
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
 				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1, &extensionSpec2})
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, internalError())
 				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec2, serviceName).Return(nil, nil)
 				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, gomockinternal.ErrStrEq(extensionFailedError().Error()))
 			},
 		},
+		{
+			name:          "extension of the cluster is installed on an existing VM",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				s.ClusterVMExtensionNames().Return([]string{"my-extension-2"})
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{"my-extension-2": true}).Return(nil)
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1, &extensionSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec2, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "extension removed from the cluster is deleted",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{"my-extension-2": true}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.Name().Return("my-vm")
+				s.NodeResourceGroup().Return("my-rg")
+				r.DeleteResource(gomockinternal.AContext(), &removedExtensionSpec, serviceName).Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "extension of the cluster overridden by the machine is kept",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{"my-extension-2": true}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{}).Return(nil)
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{&extensionSpec1, &extensionSpec2})
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec1, serviceName).Return(nil, nil)
+				r.CreateOrUpdateResource(gomockinternal.AContext(), &extensionSpec2, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "extension removed from the cluster is tracked until it is deleted",
+			expectedError: errors.Wrapf(internalError(), "failed to remove VM extension my-extension-2").Error(),
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DefaultedAzureServiceReconcileTimeout().Return(reconciler.DefaultAzureServiceReconcileTimeout)
+				s.AnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation).Return(map[string]interface{}{"my-extension-2": true}, nil)
+				s.ClusterVMExtensionNames().Return(nil)
+				s.Name().Return("my-vm")
+				s.NodeResourceGroup().Return("my-rg")
+				r.DeleteResource(gomockinternal.AContext(), &removedExtensionSpec, serviceName).Return(internalError())
+				s.UpdateAnnotationJSON(azure.ClusterVMExtensionsLastAppliedAnnotation, map[string]interface{}{"my-extension-2": true}).Return(nil)
+				s.VMExtensionSpecs().Return([]azure.ResourceSpecGetter{})
+			},
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
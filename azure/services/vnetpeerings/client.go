@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -59,6 +60,37 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return resp.VirtualNetworkPeering, nil
 }
 
+// ListRemoteAddressSpaces returns the address space of the virtual networks the given virtual network is peered with.
+func (ac *AzureClient) ListRemoteAddressSpaces(ctx context.Context, resourceGroupName, vnetName string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vnetpeerings.AzureClient.ListRemoteAddressSpaces")
+	defer done()
+
+	var addressPrefixes []string
+	pager := ac.peerings.NewListPager(resourceGroupName, vnetName, nil)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not iterate virtual network peerings")
+		}
+		for _, peering := range nextResult.Value {
+			if peering.Properties == nil {
+				continue
+			}
+			addressSpace := peering.Properties.RemoteVirtualNetworkAddressSpace
+			if addressSpace == nil {
+				addressSpace = peering.Properties.RemoteAddressSpace
+			}
+			if addressSpace == nil {
+				continue
+			}
+			for _, prefix := range addressSpace.AddressPrefixes {
+				addressPrefixes = append(addressPrefixes, ptr.Deref(prefix, ""))
+			}
+		}
+	}
+	return addressPrefixes, nil
+}
+
 // CreateOrUpdateAsync creates or updates a virtual network peering asynchronously.
 // It sends a PUT request to Azure and if accepted without error, the func will return a Poller which can be used to track the ongoing
 // progress of the operation.
@@ -72,6 +104,9 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	}
 
 	opts := &armnetwork.VirtualNetworkPeeringsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	if needsAddressSpaceSync(peering) {
+		opts.SyncRemoteAddressSpace = ptr.To(armnetwork.SyncRemoteAddressSpaceTrue)
+	}
 	poller, err = ac.peerings.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), peering, opts)
 	if err != nil {
 		return nil, nil, err
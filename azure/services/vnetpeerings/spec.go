@@ -57,9 +57,15 @@ func (s *VnetPeeringSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the virtual network peering.
 func (s *VnetPeeringSpec) Parameters(ctx context.Context, existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(armnetwork.VirtualNetworkPeering); !ok {
+		existingPeering, ok := existing.(armnetwork.VirtualNetworkPeering)
+		if !ok {
 			return nil, errors.Errorf("%T is not an armnetwork.VnetPeering", existing)
 		}
+		// The address space of a peered virtual network changed, e.g. because CIDR blocks were added to it.
+		// Updating the peering as it is syncs it with the new address space.
+		if needsAddressSpaceSync(existingPeering) {
+			return existingPeering, nil
+		}
 		// virtual network peering already exists
 		return nil, nil
 	}
@@ -78,3 +84,12 @@ func (s *VnetPeeringSpec) Parameters(ctx context.Context, existing interface{})
 		Properties: &peeringProperties,
 	}, nil
 }
+
+// needsAddressSpaceSync returns whether the address space of either end of a virtual network peering changed since
+// the peering was last synced.
+func needsAddressSpaceSync(peering armnetwork.VirtualNetworkPeering) bool {
+	if peering.Properties == nil || peering.Properties.PeeringSyncLevel == nil {
+		return false
+	}
+	return *peering.Properties.PeeringSyncLevel != armnetwork.VirtualNetworkPeeringLevelFullyInSync
+}
@@ -44,6 +44,14 @@ var (
 	}
 )
 
+func fakeVnetPeeringWithSyncLevel(level armnetwork.VirtualNetworkPeeringLevel) armnetwork.VirtualNetworkPeering {
+	peering := fakeVnetPeering
+	peering.Properties = &armnetwork.VirtualNetworkPeeringPropertiesFormat{
+		PeeringSyncLevel: ptr.To(level),
+	}
+	return peering
+}
+
 func TestVnetPeeringSpec_Parameters(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -79,6 +87,33 @@ func TestVnetPeeringSpec_Parameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get result as nil when existing VnetPeering is in sync",
+			spec:     &fakeVnetPeeringSpec,
+			existing: fakeVnetPeeringWithSyncLevel(armnetwork.VirtualNetworkPeeringLevelFullyInSync),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "get existing VnetPeering when the remote address space changed",
+			spec:     &fakeVnetPeeringSpec,
+			existing: fakeVnetPeeringWithSyncLevel(armnetwork.VirtualNetworkPeeringLevelRemoteNotInSync),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeVnetPeeringWithSyncLevel(armnetwork.VirtualNetworkPeeringLevelRemoteNotInSync)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "get existing VnetPeering when the local address space changed",
+			spec:     &fakeVnetPeeringSpec,
+			existing: fakeVnetPeeringWithSyncLevel(armnetwork.VirtualNetworkPeeringLevelLocalNotInSync),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeVnetPeeringWithSyncLevel(armnetwork.VirtualNetworkPeeringLevelLocalNotInSync)))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "get VirtualNetworkPeering when all values are present",
 			spec:     &fakeVnetPeeringSpec,
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 )
@@ -101,23 +102,29 @@ type (
 		Image              infrav1.Image                 `json:"image,omitempty"`
 		Name               string                        `json:"name,omitempty"`
 		AvailabilityZone   string                        `json:"availabilityZone,omitempty"`
+		FaultDomain        *int32                        `json:"faultDomain,omitempty"`
+		VMSize             string                        `json:"vmSize,omitempty"`
+		Priority           string                        `json:"priority,omitempty"`
 		State              infrav1.ProvisioningState     `json:"vmState,omitempty"`
 		BootstrappingState infrav1.ProvisioningState     `json:"bootstrappingState,omitempty"`
 		OrchestrationMode  infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+		PowerState         infrav1.VMPowerState          `json:"powerState,omitempty"`
 	}
 
 	// VMSS defines a virtual machine scale set.
 	VMSS struct {
-		ID        string                    `json:"id,omitempty"`
-		Name      string                    `json:"name,omitempty"`
-		Sku       string                    `json:"sku,omitempty"`
-		Capacity  int64                     `json:"capacity,omitempty"`
-		Zones     []string                  `json:"zones,omitempty"`
-		Image     infrav1.Image             `json:"image,omitempty"`
-		State     infrav1.ProvisioningState `json:"vmState,omitempty"`
-		Identity  infrav1.VMIdentity        `json:"identity,omitempty"`
-		Tags      infrav1.Tags              `json:"tags,omitempty"`
-		Instances []VMSSVM                  `json:"instances,omitempty"`
+		ID            string                    `json:"id,omitempty"`
+		Name          string                    `json:"name,omitempty"`
+		Sku           string                    `json:"sku,omitempty"`
+		Capacity      int64                     `json:"capacity,omitempty"`
+		Zones         []string                  `json:"zones,omitempty"`
+		Image         infrav1.Image             `json:"image,omitempty"`
+		State         infrav1.ProvisioningState `json:"vmState,omitempty"`
+		Identity      infrav1.VMIdentity        `json:"identity,omitempty"`
+		Tags          infrav1.Tags              `json:"tags,omitempty"`
+		PatchSettings *infrav1.PatchSettings    `json:"patchSettings,omitempty"`
+		Extensions    []string                  `json:"extensions,omitempty"`
+		Instances     []VMSSVM                  `json:"instances,omitempty"`
 	}
 )
 
@@ -128,7 +135,23 @@ func (vmss VMSS) HasModelChanges(other VMSS) bool {
 		cmp.Equal(vmss.Zones, other.Zones) &&
 		cmp.Equal(vmss.Tags, other.Tags) &&
 		cmp.Equal(vmss.Sku, other.Sku)
-	return !equal
+	return !equal || hasPatchSettingsChanges(vmss.PatchSettings, other.PatchSettings)
+}
+
+// hasPatchSettingsChanges returns true if the desired patch settings set a value that differs from the existing ones.
+// Unset values are ignored since Azure reports its defaults for them.
+func hasPatchSettingsChanges(existing, desired *infrav1.PatchSettings) bool {
+	if desired == nil {
+		return false
+	}
+	if existing == nil {
+		existing = &infrav1.PatchSettings{}
+	}
+	return (desired.PatchMode != "" && desired.PatchMode != existing.PatchMode) ||
+		(desired.AssessmentMode != "" && desired.AssessmentMode != existing.AssessmentMode) ||
+		(desired.RebootSetting != "" && desired.RebootSetting != existing.RebootSetting) ||
+		(desired.BypassPlatformSafetyChecksOnUserSchedule != nil &&
+			*desired.BypassPlatformSafetyChecksOnUserSchedule != ptr.Deref(existing.BypassPlatformSafetyChecksOnUserSchedule, false))
 }
 
 // InstancesByProviderID returns VMSSVMs by ID.
@@ -136,6 +136,38 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 			},
 			HasModelChanges: true,
 		},
+		{
+			Name: "with different patch mode",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.PatchSettings = &infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByPlatform}
+				r := getDefaultVMSSForModelTesting()
+				r.PatchSettings = &infrav1.PatchSettings{PatchMode: infrav1.PatchModeImageDefault, AssessmentMode: infrav1.PatchAssessmentModeImageDefault}
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with the same patch mode and unset patch settings defaulted by Azure",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.PatchSettings = &infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByPlatform}
+				r := getDefaultVMSSForModelTesting()
+				r.PatchSettings = &infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByPlatform, AssessmentMode: infrav1.PatchAssessmentModeImageDefault}
+				return r, l
+			},
+			HasModelChanges: false,
+		},
+		{
+			Name: "without desired patch settings",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				r := getDefaultVMSSForModelTesting()
+				r.PatchSettings = &infrav1.PatchSettings{PatchMode: infrav1.PatchModeImageDefault}
+				return r, l
+			},
+			HasModelChanges: false,
+		},
 	}
 
 	for _, c := range cases {
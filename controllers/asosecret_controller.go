@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	asoconfig "github.com/Azure/azure-service-operator/v2/pkg/common/config"
 	"github.com/pkg/errors"
@@ -36,6 +37,8 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -254,9 +257,68 @@ func (asos *ASOSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile ASO secret")
 	}
 
+	if err := asos.reconcileASOSecretCondition(ctx, asoSecretOwner, newASOSecret); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile ASO secret condition")
+	}
+
+	// The record of the API server in an Azure DNS zone of another subscription is managed with a copy of the secret
+	// for the subscription of the zone.
+	var apiServerDNS *infrav1.APIServerDNS
+	switch o := asoSecretOwner.(type) {
+	case *infrav1.AzureCluster:
+		apiServerDNS = o.Spec.APIServerDNS
+	case *infrav1.AzureManagedControlPlane:
+		apiServerDNS = o.Spec.APIServerDNS
+	}
+	zoneSubscriptionID := apiServerDNS.ZoneSubscriptionID()
+	if zoneSubscriptionID != "" && !strings.EqualFold(zoneSubscriptionID, azureClient.SubscriptionID()) {
+		zoneASOSecret := newASOSecret.DeepCopy()
+		zoneASOSecret.ResourceVersion = ""
+		zoneASOSecret.Name = aso.GetASOSecretNameForSubscription(cluster.GetName(), zoneSubscriptionID)
+		zoneASOSecret.Data[asoconfig.AzureSubscriptionID] = []byte(zoneSubscriptionID)
+		if err := reconcileAzureSecret(ctx, asos.Client, owner, zoneASOSecret, cluster.GetName()); err != nil {
+			asos.Recorder.Eventf(cluster, corev1.EventTypeWarning, "Error reconciling ASO secret", err.Error())
+			return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile ASO secret for subscription %s", zoneSubscriptionID)
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// reconcileASOSecretCondition reports on the owner of the ASO secret whether the secret used by ASO targets the
+// subscription of the cluster. CAPZ doesn't update an ASO secret provided by the user, so a subscription mismatch
+// would otherwise silently create the resources of the cluster in another subscription.
+func (asos *ASOSecretReconciler) reconcileASOSecretCondition(ctx context.Context, owner client.Object, newASOSecret *corev1.Secret) error {
+	setter, ok := owner.(conditions.Setter)
+	if !ok {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(owner, asos.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+
+	asoSecret := &corev1.Secret{}
+	if err := asos.Get(ctx, client.ObjectKeyFromObject(newASOSecret), asoSecret); err != nil {
+		return errors.Wrap(err, "failed to fetch ASO secret")
+	}
+
+	subscriptionID := string(newASOSecret.Data[asoconfig.AzureSubscriptionID])
+	secretSubscriptionID := string(asoSecret.Data[asoconfig.AzureSubscriptionID])
+	if strings.EqualFold(secretSubscriptionID, subscriptionID) {
+		conditions.MarkTrue(setter, infrav1.ASOCredentialSecretReadyCondition)
+	} else {
+		msg := fmt.Sprintf("ASO secret %s targets subscription %q instead of the subscription %q of the cluster", asoSecret.Name, secretSubscriptionID, subscriptionID)
+		conditions.MarkFalse(setter, infrav1.ASOCredentialSecretReadyCondition, infrav1.ASOCredentialSubscriptionMismatchReason, clusterv1.ConditionSeverityError, msg)
+		asos.Recorder.Event(owner, corev1.EventTypeWarning, infrav1.ASOCredentialSubscriptionMismatchReason, msg)
+	}
+
+	return patchHelper.Patch(ctx, owner, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		infrav1.ASOCredentialSecretReadyCondition,
+	}})
+}
+
 func (asos *ASOSecretReconciler) createSecretFromClusterIdentity(ctx context.Context, clusterIdentity *corev1.ObjectReference, cluster *clusterv1.Cluster, azureClient scope.AzureClients) (*corev1.Secret, error) {
 	newASOSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
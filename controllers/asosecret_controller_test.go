@@ -31,6 +31,7 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -288,7 +289,11 @@ func TestASOSecretReconcile(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			g := NewWithT(t)
-			clientBuilder := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+			clientBuilder := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(tc.objects...).
+				WithStatusSubresource(&infrav1.AzureCluster{}, &infrav1.AzureManagedControlPlane{}).
+				Build()
 
 			reconciler := &ASOSecretReconciler{
 				Client:   clientBuilder,
@@ -327,6 +332,220 @@ func TestASOSecretReconcile(t *testing.T) {
 	}
 }
 
+func TestASOSecretReconcileAPIServerDNSZoneSubscription(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	identityRef := &corev1.ObjectReference{
+		Name:      "my-azure-cluster-identity",
+		Namespace: "default",
+	}
+	newAPIServerDNS := func(zoneSubscriptionID string) *infrav1.APIServerDNS {
+		return &infrav1.APIServerDNS{
+			ZoneResourceID: "/subscriptions/" + zoneSubscriptionID + "/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com",
+			RecordName:     "api",
+		}
+	}
+	newAzureCluster := func(zoneSubscriptionID string) *infrav1.AzureCluster {
+		return getASOAzureCluster(func(c *infrav1.AzureCluster) {
+			c.Spec.IdentityRef = identityRef
+			c.Spec.APIServerDNS = newAPIServerDNS(zoneSubscriptionID)
+		})
+	}
+	zoneASOSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster-aso-secret-456",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"AZURE_SUBSCRIPTION_ID": []byte("456"),
+			"AZURE_TENANT_ID":       []byte("fooTenant"),
+			"AZURE_CLIENT_ID":       []byte("fooClient"),
+			"AZURE_CLIENT_SECRET":   []byte("fooSecret"),
+		},
+	}
+
+	cases := map[string]struct {
+		owner         client.Object
+		zoneASOSecret *corev1.Secret
+	}{
+		"should create a secret for the subscription of a DNS zone in another subscription": {
+			owner:         newAzureCluster("456"),
+			zoneASOSecret: zoneASOSecret,
+		},
+		"should not create another secret for a DNS zone in the subscription of the cluster": {
+			owner: newAzureCluster("123"),
+		},
+		"should create a secret for the subscription of the DNS zone of a managed cluster in another subscription": {
+			owner: getASOAzureManagedControlPlane(func(c *infrav1.AzureManagedControlPlane) {
+				c.Spec.SubscriptionID = "123"
+				c.Spec.IdentityRef = identityRef
+				c.Spec.APIServerDNS = newAPIServerDNS("456")
+			}),
+			zoneASOSecret: zoneASOSecret,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&infrav1.AzureCluster{}, &infrav1.AzureManagedControlPlane{}).WithRuntimeObjects(
+				tc.owner,
+				getASOAzureClusterIdentity(func(identity *infrav1.AzureClusterIdentity) {
+					identity.Spec.Type = infrav1.ServicePrincipal
+					identity.Spec.ClientSecret = corev1.SecretReference{
+						Name:      "fooSecret",
+						Namespace: "default",
+					}
+				}),
+				getASOAzureClusterIdentitySecret(),
+				getASOCluster(),
+			).Build()
+
+			reconciler := &ASOSecretReconciler{
+				Client:   c,
+				Recorder: record.NewFakeRecorder(128),
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: client.ObjectKeyFromObject(tc.owner),
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			secrets := &corev1.SecretList{}
+			g.Expect(c.List(context.Background(), secrets, client.MatchingLabels{"my-cluster": "owned"})).To(Succeed())
+			if tc.zoneASOSecret == nil {
+				g.Expect(secrets.Items).To(HaveLen(1))
+				return
+			}
+			g.Expect(secrets.Items).To(HaveLen(2))
+			zoneASOSecret := &corev1.Secret{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(tc.zoneASOSecret), zoneASOSecret)).To(Succeed())
+			g.Expect(zoneASOSecret.Data).To(Equal(tc.zoneASOSecret.Data))
+		})
+	}
+}
+
+func TestASOSecretReconcileCredentialLayering(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	// userASOSecret is an ASO secret provided by the user, which CAPZ doesn't manage.
+	userASOSecret := func(subscriptionID string) *corev1.Secret {
+		return getASOSecret(getASOAzureCluster(), func(s *corev1.Secret) {
+			s.Labels = nil
+			s.OwnerReferences = nil
+			s.Data = map[string][]byte{
+				"AZURE_SUBSCRIPTION_ID": []byte(subscriptionID),
+				"AZURE_TENANT_ID":       []byte("userTenant"),
+				"AZURE_CLIENT_ID":       []byte("userClient"),
+				"AZURE_CLIENT_SECRET":   []byte("userSecret"),
+			}
+		})
+	}
+
+	cases := map[string]struct {
+		subscriptionID string
+		userASOSecret  *corev1.Secret
+		wantData       map[string][]byte
+		wantCondition  *clusterv1.Condition
+		wantEvent      string
+	}{
+		"identity of the manager with the subscription of the cluster": {
+			subscriptionID: "123",
+			wantData: map[string][]byte{
+				"AZURE_SUBSCRIPTION_ID": []byte("123"),
+				"AZURE_TENANT_ID":       []byte("fooTenant"),
+				"AZURE_CLIENT_ID":       []byte("fooClient"),
+				"AUTH_MODE":             []byte("podidentity"),
+			},
+			wantCondition: conditions.TrueCondition(infrav1.ASOCredentialSecretReadyCondition),
+		},
+		"identity of the manager with the subscription of another cluster": {
+			subscriptionID: "456",
+			wantData: map[string][]byte{
+				"AZURE_SUBSCRIPTION_ID": []byte("456"),
+				"AZURE_TENANT_ID":       []byte("fooTenant"),
+				"AZURE_CLIENT_ID":       []byte("fooClient"),
+				"AUTH_MODE":             []byte("podidentity"),
+			},
+			wantCondition: conditions.TrueCondition(infrav1.ASOCredentialSecretReadyCondition),
+		},
+		"user-provided secret with the subscription of the cluster": {
+			subscriptionID: "123",
+			userASOSecret:  userASOSecret("123"),
+			wantData:       userASOSecret("123").Data,
+			wantCondition:  conditions.TrueCondition(infrav1.ASOCredentialSecretReadyCondition),
+		},
+		"user-provided secret with another subscription": {
+			subscriptionID: "123",
+			userASOSecret:  userASOSecret("789"),
+			wantData:       userASOSecret("789").Data,
+			wantCondition: conditions.FalseCondition(
+				infrav1.ASOCredentialSecretReadyCondition,
+				infrav1.ASOCredentialSubscriptionMismatchReason,
+				clusterv1.ConditionSeverityError,
+				`ASO secret my-cluster-aso-secret targets subscription "789" instead of the subscription "123" of the cluster`,
+			),
+			wantEvent: infrav1.ASOCredentialSubscriptionMismatchReason,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			azureCluster := getASOAzureCluster(func(c *infrav1.AzureCluster) {
+				c.Spec.SubscriptionID = tc.subscriptionID
+				c.Spec.IdentityRef = &corev1.ObjectReference{
+					Name:      "my-azure-cluster-identity",
+					Namespace: "default",
+				}
+			})
+			objects := []runtime.Object{
+				azureCluster,
+				getASOAzureClusterIdentity(func(identity *infrav1.AzureClusterIdentity) {
+					identity.Spec.Type = infrav1.UserAssignedMSI
+				}),
+				getASOCluster(),
+			}
+			if tc.userASOSecret != nil {
+				objects = append(objects, tc.userASOSecret)
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(objects...).
+				WithStatusSubresource(&infrav1.AzureCluster{}).
+				Build()
+
+			reconciler := &ASOSecretReconciler{
+				Client:   c,
+				Recorder: record.NewFakeRecorder(128),
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: client.ObjectKeyFromObject(azureCluster),
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			asoSecret := &corev1.Secret{}
+			g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "my-cluster-aso-secret"}, asoSecret)).To(Succeed())
+			g.Expect(asoSecret.Data).To(Equal(tc.wantData))
+
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(azureCluster), azureCluster)).To(Succeed())
+			g.Expect(conditions.Get(azureCluster, infrav1.ASOCredentialSecretReadyCondition)).To(conditions.HaveSameStateOf(tc.wantCondition))
+
+			if tc.wantEvent != "" {
+				g.Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(tc.wantEvent)))
+			}
+		})
+	}
+}
+
 func getASOCluster(changes ...func(*clusterv1.Cluster)) *clusterv1.Cluster {
 	input := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -49,18 +51,26 @@ type AzureClusterReconciler struct {
 	Recorder                  record.EventRecorder
 	Timeouts                  reconciler.Timeouts
 	WatchFilterValue          string
+	ForceDeleteUnmanaged      bool
+	FilterControlPlaneZones   bool
 	createAzureClusterService azureClusterServiceCreator
+	serviceProgress           *serviceProgressRecorder
+	regionHealth              *regionHealthTracker
 }
 
 type azureClusterServiceCreator func(clusterScope *scope.ClusterScope) (*azureClusterService, error)
 
 // NewAzureClusterReconciler returns a new AzureClusterReconciler instance.
-func NewAzureClusterReconciler(client client.Client, recorder record.EventRecorder, timeouts reconciler.Timeouts, watchFilterValue string) *AzureClusterReconciler {
+func NewAzureClusterReconciler(client client.Client, recorder record.EventRecorder, timeouts reconciler.Timeouts, watchFilterValue string, forceDeleteUnmanaged, filterControlPlaneZones bool) *AzureClusterReconciler {
 	acr := &AzureClusterReconciler{
-		Client:           client,
-		Recorder:         recorder,
-		Timeouts:         timeouts,
-		WatchFilterValue: watchFilterValue,
+		Client:                  client,
+		Recorder:                recorder,
+		Timeouts:                timeouts,
+		WatchFilterValue:        watchFilterValue,
+		ForceDeleteUnmanaged:    forceDeleteUnmanaged,
+		FilterControlPlaneZones: filterControlPlaneZones,
+		serviceProgress:         newServiceProgressRecorder(recorder),
+		regionHealth:            newRegionHealthTracker(),
 	}
 
 	acr.createAzureClusterService = newAzureClusterService
@@ -84,6 +94,8 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options.Options).
 		For(&infrav1.AzureCluster{}).
+		// Requeue the AzureCluster when the address space of a subnet is allocated.
+		Owns(&ipamv1.IPAddressClaim{}).
 		WithEventFilter(predicates.ResourceHasFilterLabel(log, acr.WatchFilterValue)).
 		WithEventFilter(predicates.ResourceIsNotExternallyManaged(log)).
 		Build(r)
@@ -101,19 +113,40 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on AzureMachineTemplates to refresh the control plane failure domains when the control plane starts
+	// using a new template, which the control plane marks as owned by the cluster.
+	if acr.FilterControlPlaneZones {
+		azureMachineTemplateMapper, err := AzureMachineTemplateToAzureClusterMapper(ctx, acr.Client, log)
+		if err != nil {
+			return errors.Wrap(err, "failed to create AzureMachineTemplate to AzureCluster mapper")
+		}
+		if err = c.Watch(
+			source.Kind(mgr.GetCache(), &infrav1.AzureMachineTemplate{}),
+			handler.EnqueueRequestsFromMapFunc(azureMachineTemplateMapper),
+			predicates.ResourceHasFilterLabel(log, acr.WatchFilterValue),
+		); err != nil {
+			return errors.Wrap(err, "failed adding a watch for AzureMachineTemplates")
+		}
+	}
+
 	return nil
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusters/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusteridentities/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups/status,verbs=get;list;watch
-// +kubebuilder:rbac:groups=network.azure.com,resources=natgateways;bastionhosts;privateendpoints;virtualnetworks;virtualnetworkssubnets,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=network.azure.com,resources=natgateways/status;bastionhosts/status;privateendpoints/status;virtualnetworks/status;virtualnetworkssubnets/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=network.azure.com,resources=natgateways;bastionhosts;privateendpoints;virtualnetworks;virtualnetworkssubnets;dnszonesarecords;dnszonesaaaarecords;dnszonescnamerecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=network.azure.com,resources=natgateways/status;bastionhosts/status;privateendpoints/status;virtualnetworks/status;virtualnetworkssubnets/status;dnszonesarecords/status;dnszonesaaaarecords/status;dnszonescnamerecords/status,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a cluster.
 func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -156,10 +189,11 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
-		Client:       acr.Client,
-		Cluster:      cluster,
-		AzureCluster: azureCluster,
-		Timeouts:     acr.Timeouts,
+		Client:               acr.Client,
+		Cluster:              cluster,
+		AzureCluster:         azureCluster,
+		Timeouts:             acr.Timeouts,
+		ForceDeleteUnmanaged: acr.ForceDeleteUnmanaged,
 	})
 	if err != nil {
 		err = errors.Wrap(err, "failed to create scope")
@@ -169,9 +203,11 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	// Always close the scope when exiting this function so we can persist any AzureMachine changes.
 	defer func() {
+		reconcileIdentityFallback(acr.Recorder, azureCluster, clusterScope)
 		if err := clusterScope.Close(ctx); err != nil && reterr == nil {
 			reterr = err
 		}
+		updateManagedResourcesMetric(azureCluster, clusterScope.ClusterName(), infrav1.ClusterFinalizer, azureCluster.Status.ManagedResources)
 	}()
 
 	// Return early if the object or Cluster is paused.
@@ -207,6 +243,9 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	log.Info("Reconciling AzureCluster")
 	azureCluster := clusterScope.AzureCluster
 
+	// Start recording the Azure resources created by CAPZ before any of them is created.
+	clusterScope.InitManagedResources()
+
 	// Register our finalizer immediately to avoid orphaning Azure resources on delete
 	needsPatch := controllerutil.AddFinalizer(azureCluster, infrav1.ClusterFinalizer)
 	// Register the block-move annotation immediately to avoid moving un-paused ASO resources
@@ -217,10 +256,29 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		}
 	}
 
+	allocated, err := reconcileSubnetIPAddressClaims(ctx, acr.Client, azureCluster, acr.WatchFilterValue)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to allocate the address space of subnets from IPAM")
+	}
+	if !allocated {
+		// The AzureCluster is requeued when the IPAddressClaims it owns get an address.
+		log.Info("Waiting for the IPAM provider to allocate the address space of subnets")
+		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.WaitingForIPAddressAllocationReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{}, nil
+	}
+
+	if remaining := backoffRemaining(azureCluster, time.Now()); remaining > 0 {
+		log.V(2).Info("backing off after transient failures to reconcile AzureCluster", "remaining", remaining)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
 	acs, err := acr.createAzureClusterService(clusterScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
 	}
+	acs.progress = acr.serviceProgress.forObject(azureCluster)
+	acs.budget = serviceBudget{timeouts: acr.Timeouts}
+	acs.filterControlPlaneZones = acr.FilterControlPlaneZones
 
 	if err := acs.Reconcile(ctx); err != nil {
 		// Handle terminal & transient errors
@@ -235,14 +293,24 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 			if reconcileError.IsTransient() {
 				if azure.IsOperationNotDoneError(reconcileError) {
 					log.V(2).Info(fmt.Sprintf("AzureCluster reconcile not done: %s", reconcileError.Error()))
-				} else {
-					log.V(2).Info(fmt.Sprintf("transient failure to reconcile AzureCluster, retrying: %s", reconcileError.Error()))
+					return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
 				}
-				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+				log.V(2).Info(fmt.Sprintf("transient failure to reconcile AzureCluster, retrying: %s", reconcileError.Error()))
+				requeueAfter := recordTransientError(azureCluster, reconcileError.RequeueAfter(), time.Now())
+				if requeue := acr.regionHealth.recordFailure(ctx, clusterScope); requeue > requeueAfter {
+					requeueAfter = requeue
+				}
+				return reconcile.Result{RequeueAfter: requeueAfter}, nil
 			}
 		}
 
 		wrappedErr := errors.Wrap(err, "failed to reconcile cluster services")
+		if requeue := acr.regionHealth.recordFailure(ctx, clusterScope); requeue > 0 {
+			// Don't flood events and alerts while the region is known to be degraded.
+			log.Error(wrappedErr, "failed to reconcile AzureCluster in a degraded region")
+			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityWarning, wrappedErr.Error())
+			return reconcile.Result{RequeueAfter: requeue}, nil
+		}
 		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerNormalFailed", wrappedErr.Error())
 		conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, wrappedErr.Error())
 		return reconcile.Result{}, wrappedErr
@@ -257,6 +325,8 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	}
 
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
+	resetBackoff(azureCluster)
+	acr.regionHealth.reset(clusterScope)
 	azureCluster.Status.Ready = true
 	conditions.MarkTrue(azureCluster, infrav1.NetworkInfrastructureReadyCondition)
 
@@ -295,7 +365,15 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
 	}
 
-	if err := acs.Delete(ctx); err != nil {
+	if azureCluster.Spec.DeletionPolicy == infrav1.DeletionPolicyRetain {
+		ids, err := acs.Retain(ctx)
+		if err != nil {
+			wrappedErr := errors.Wrapf(err, "error retaining AzureCluster %s/%s", azureCluster.Namespace, azureCluster.Name)
+			acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ClusterReconcilerRetainFailed", wrappedErr.Error())
+			return reconcile.Result{}, wrappedErr
+		}
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "AzureResourcesRetained", retainedResourcesMessage(ids))
+	} else if err := acr.deleteAzureCluster(ctx, clusterScope, acs); err != nil {
 		// Handle transient errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
@@ -315,8 +393,14 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		return reconcile.Result{}, wrappedErr
 	}
 
+	if err := deleteSubnetIPAddressClaims(ctx, acr.Client, azureCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(azureCluster, infrav1.ClusterFinalizer)
+	acr.serviceProgress.forget(azureCluster)
+	acr.regionHealth.reset(clusterScope)
 
 	if azureCluster.Spec.IdentityRef != nil {
 		// Cluster is deleted so remove the identity finalizer.
@@ -328,3 +412,12 @@ func (acr *AzureClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 
 	return reconcile.Result{}, nil
 }
+
+// deleteAzureCluster releases a virtual network shared with other AzureClusters before deleting the Azure resources
+// of the AzureCluster.
+func (acr *AzureClusterReconciler) deleteAzureCluster(ctx context.Context, clusterScope *scope.ClusterScope, acs *azureClusterService) error {
+	if err := acr.releaseSharedVNet(ctx, clusterScope); err != nil {
+		return err
+	}
+	return acs.Delete(ctx)
+}
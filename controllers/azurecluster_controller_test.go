@@ -52,6 +52,7 @@ type TestClusterReconcileInput struct {
 	azureClusterOptions       func(ac *infrav1.AzureCluster)
 	clusterScopeFailureReason capierrors.ClusterStatusError
 	cache                     *scope.ClusterCache
+	objects                   []runtime.Object
 	expectedResult            reconcile.Result
 	expectedErr               string
 	ready                     bool
@@ -68,7 +69,7 @@ var _ = Describe("AzureClusterReconciler", func() {
 
 	Context("Reconcile an AzureCluster", func() {
 		It("should not error with minimal set up", func() {
-			reconciler := NewAzureClusterReconciler(testEnv, testEnv.GetEventRecorderFor("azurecluster-reconciler"), reconciler.Timeouts{}, "")
+			reconciler := NewAzureClusterReconciler(testEnv, testEnv.GetEventRecorderFor("azurecluster-reconciler"), reconciler.Timeouts{}, "", false, true)
 			By("Calling reconcile")
 			name := test.RandomName("foo", 10)
 			instance := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
@@ -271,11 +272,12 @@ func TestAzureClusterReconcilePaused(t *testing.T) {
 	c := fake.NewClientBuilder().
 		WithScheme(s).
 		WithRuntimeObjects(initObjects...).
+		WithStatusSubresource(&infrav1.AzureCluster{}).
 		Build()
 
 	recorder := record.NewFakeRecorder(1)
 
-	reconciler := NewAzureClusterReconciler(c, recorder, reconciler.Timeouts{}, "")
+	reconciler := NewAzureClusterReconciler(c, recorder, reconciler.Timeouts{}, "", false, true)
 	name := test.RandomName("paused", 10)
 	namespace := namespace
 
@@ -408,6 +410,40 @@ func TestAzureClusterReconcileDelete(t *testing.T) {
 			cache:       &scope.ClusterCache{},
 			expectedErr: "error deleting AzureCluster",
 		},
+		"should retain resources without deleting them with the Retain deletion policy": {
+			createAzureClusterService: func(cs *scope.ClusterScope) (*azureClusterService, error) {
+				return getDefaultAzureClusterService(func(acs *azureClusterService) {
+					acs.skuCache = resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, cs.Location())
+					acs.scope = cs
+					acs.Delete = func(context.Context) error {
+						return errors.New("resources should not be deleted")
+					}
+					acs.Retain = func(context.Context) ([]string, error) {
+						return []string{"/subscriptions/123/resourceGroups/my-rg"}, nil
+					}
+				}), nil
+			},
+			azureClusterOptions: func(ac *infrav1.AzureCluster) {
+				ac.Spec.DeletionPolicy = infrav1.DeletionPolicyRetain
+			},
+			cache: &scope.ClusterCache{},
+		},
+		"should fail to delete if resources fail to be retained": {
+			createAzureClusterService: func(cs *scope.ClusterScope) (*azureClusterService, error) {
+				return getDefaultAzureClusterService(func(acs *azureClusterService) {
+					acs.skuCache = resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, cs.Location())
+					acs.scope = cs
+					acs.Retain = func(context.Context) ([]string, error) {
+						return nil, errors.New("foo error")
+					}
+				}), nil
+			},
+			azureClusterOptions: func(ac *infrav1.AzureCluster) {
+				ac.Spec.DeletionPolicy = infrav1.DeletionPolicyRetain
+			},
+			cache:       &scope.ClusterCache{},
+			expectedErr: "error retaining AzureCluster",
+		},
 	}
 
 	for name, c := range cases {
@@ -443,6 +479,9 @@ func getDefaultAzureClusterService(changes ...func(*azureClusterService)) *azure
 		Pause: func(ctx context.Context) error {
 			return nil
 		},
+		Retain: func(ctx context.Context) ([]string, error) {
+			return nil, nil
+		},
 	}
 
 	for _, change := range changes {
@@ -457,6 +496,12 @@ func getClusterReconcileInputs(tc TestClusterReconcileInput) (*AzureClusterRecon
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := asonetworkv1.AddToScheme(scheme); err != nil {
+		return nil, nil, err
+	}
+	if err := asoresourcesv1.AddToScheme(scheme); err != nil {
+		return nil, nil, err
+	}
 
 	cluster := getFakeCluster()
 
@@ -489,6 +534,7 @@ func getClusterReconcileInputs(tc TestClusterReconcileInput) (*AzureClusterRecon
 		fakeIdentity,
 		fakeSecret,
 	}
+	objects = append(objects, tc.objects...)
 
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ipv6SubnetPrefixLength is the prefix length of the IPv6 address space of an Azure subnet.
+const ipv6SubnetPrefixLength = 64
+
+// subnetIPAddressClaimName returns the name of the IPAddressClaim allocating the address space of a subnet.
+func subnetIPAddressClaimName(azureCluster *infrav1.AzureCluster, subnetName string) string {
+	return fmt.Sprintf("%s-%s", azureCluster.Name, subnetName)
+}
+
+// reconcileSubnetIPAddressClaims claims the address space of the subnets allocated from an IPAM pool and writes the
+// allocated prefixes to the CIDR blocks of the subnets. It returns false while an allocation is pending.
+func reconcileSubnetIPAddressClaims(ctx context.Context, c client.Client, azureCluster *infrav1.AzureCluster, watchFilterValue string) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.reconcileSubnetIPAddressClaims")
+	defer done()
+
+	allocated := true
+	for i, subnet := range azureCluster.Spec.NetworkSpec.Subnets {
+		if subnet.IPAMPoolRef == nil || len(subnet.CIDRBlocks) > 0 {
+			continue
+		}
+
+		claim := &ipamv1.IPAddressClaim{}
+		claim.Name = subnetIPAddressClaimName(azureCluster, subnet.Name)
+		claim.Namespace = azureCluster.Namespace
+		poolRef := *subnet.IPAMPoolRef
+		if _, err := controllerutil.CreateOrPatch(ctx, c, claim, func() error {
+			if claim.Labels == nil {
+				claim.Labels = map[string]string{}
+			}
+			claim.Labels[clusterv1.ClusterNameLabel] = azureCluster.Labels[clusterv1.ClusterNameLabel]
+			if watchFilterValue != "" {
+				claim.Labels[clusterv1.WatchLabel] = watchFilterValue
+			}
+			claim.Spec.PoolRef = poolRef
+			return controllerutil.SetControllerReference(azureCluster, claim, c.Scheme())
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to reconcile IPAddressClaim %s", claim.Name)
+		}
+
+		if claim.Status.AddressRef.Name == "" {
+			log.V(2).Info("waiting for IP address allocation", "subnet", subnet.Name, "ipAddressClaim", claim.Name)
+			allocated = false
+			continue
+		}
+
+		address := &ipamv1.IPAddress{}
+		key := types.NamespacedName{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}
+		if err := c.Get(ctx, key, address); err != nil {
+			if apierrors.IsNotFound(err) {
+				allocated = false
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to get IPAddress %s", key.Name)
+		}
+
+		cidr, err := ipAddressToCIDR(address, azureCluster.Spec.NetworkSpec.Vnet.VnetClassSpec)
+		if err != nil {
+			return false, err
+		}
+		log.V(2).Info("allocated subnet address space", "subnet", subnet.Name, "cidr", cidr)
+		azureCluster.Spec.NetworkSpec.Subnets[i].CIDRBlocks = []string{cidr}
+	}
+
+	return allocated, nil
+}
+
+// deleteSubnetIPAddressClaims releases the address space of the subnets allocated from an IPAM pool.
+func deleteSubnetIPAddressClaims(ctx context.Context, c client.Client, azureCluster *infrav1.AzureCluster) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.deleteSubnetIPAddressClaims")
+	defer done()
+
+	for _, subnet := range azureCluster.Spec.NetworkSpec.Subnets {
+		if subnet.IPAMPoolRef == nil {
+			continue
+		}
+		claim := &ipamv1.IPAddressClaim{}
+		claim.Name = subnetIPAddressClaimName(azureCluster, subnet.Name)
+		claim.Namespace = azureCluster.Namespace
+		if err := c.Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete IPAddressClaim %s", claim.Name)
+		}
+	}
+	return nil
+}
+
+// ipAddressToCIDR returns the address space of a subnet allocated from an IPAM pool in CIDR notation. The address
+// space starts at the claimed address and has the prefix length requested for the subnets of the virtual network, or
+// /64 for IPv6, as the prefix of the IPAddress is the one of the whole pool. The claimed address must be the first
+// address of its address space, so that the address spaces of the subnets claiming from the same pool don't overlap.
+func ipAddressToCIDR(address *ipamv1.IPAddress, vnet infrav1.VnetClassSpec) (string, error) {
+	ip := net.ParseIP(address.Spec.Address)
+	if ip == nil {
+		return "", errors.Errorf("IPAddress %s has an invalid address %q", address.Name, address.Spec.Address)
+	}
+	bits, prefixLen := net.IPv6len*8, ipv6SubnetPrefixLength
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = net.IPv4len * 8
+		var err error
+		if prefixLen, err = ipv4SubnetPrefixLength(ip, vnet); err != nil {
+			return "", errors.Wrapf(err, "IPAddress %s can't be used as a subnet address space", address.Name)
+		}
+	}
+	mask := net.CIDRMask(prefixLen, bits)
+	if !ip.Mask(mask).Equal(ip) {
+		return "", errors.Errorf("IPAddress %s has the address %s, which isn't the first address of a /%d address space; the pool must only contain addresses aligned on the subnet prefix length",
+			address.Name, address.Spec.Address, prefixLen)
+	}
+	return (&net.IPNet{IP: ip, Mask: mask}).String(), nil
+}
+
+// ipv4SubnetPrefixLength returns the prefix length of the subnets generated from the CIDR block of the virtual network
+// containing ip.
+func ipv4SubnetPrefixLength(ip net.IP, vnet infrav1.VnetClassSpec) (int, error) {
+	vnetCIDRBlocks := vnet.CIDRBlocks
+	if len(vnetCIDRBlocks) == 0 {
+		vnetCIDRBlocks = []string{infrav1.DefaultVnetCIDR}
+	}
+	for _, cidr := range vnetCIDRBlocks {
+		if _, nw, err := net.ParseCIDR(cidr); err == nil && nw.Contains(ip) {
+			prefixLen := vnet.GeneratedSubnetPrefixLength(nw)
+			if ones, _ := nw.Mask.Size(); prefixLen < ones {
+				return 0, errors.Errorf("subnet prefix length %d is shorter than the prefix of the virtual network address space %s", prefixLen, cidr)
+			}
+			return prefixLen, nil
+		}
+	}
+	return 0, errors.Errorf("address %s is not within the address space %v of the virtual network", ip, vnetCIDRBlocks)
+}
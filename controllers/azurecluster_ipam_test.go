@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var fakeIPAMPoolRef = corev1.TypedLocalObjectReference{
+	APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+	Kind:     "InClusterIPPool",
+	Name:     "node-subnets",
+}
+
+// allocateIPAddressClaims acts as an IPAM provider allocating an address for every pending IPAddressClaim.
+func allocateIPAddressClaims(g *WithT, c client.Client, address string, prefix int) {
+	claims := &ipamv1.IPAddressClaimList{}
+	g.Expect(c.List(context.Background(), claims)).To(Succeed())
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Status.AddressRef.Name != "" {
+			continue
+		}
+		g.Expect(c.Create(context.Background(), &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: claim.Name, Namespace: claim.Namespace},
+			Spec: ipamv1.IPAddressSpec{
+				ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+				PoolRef:  claim.Spec.PoolRef,
+				Address:  address,
+				Prefix:   prefix,
+			},
+		})).To(Succeed())
+		claim.Status.AddressRef.Name = claim.Name
+		g.Expect(c.Status().Update(context.Background(), claim)).To(Succeed())
+	}
+}
+
+func TestReconcileSubnetIPAddressClaims(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+
+	azureCluster := getFakeAzureCluster(func(ac *infrav1.AzureCluster) {
+		ac.Labels = map[string]string{clusterv1.ClusterNameLabel: "my-cluster"}
+		ac.Spec.NetworkSpec.Subnets[0].IPAMPoolRef = fakeIPAMPoolRef.DeepCopy()
+		ac.Spec.NetworkSpec.Subnets = append(ac.Spec.NetworkSpec.Subnets, infrav1.SubnetSpec{
+			SubnetClassSpec: infrav1.SubnetClassSpec{
+				Name:        "control-plane",
+				Role:        infrav1.SubnetControlPlane,
+				CIDRBlocks:  []string{"10.0.0.0/16"},
+				IPAMPoolRef: fakeIPAMPoolRef.DeepCopy(),
+			},
+		})
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(azureCluster).
+		WithStatusSubresource(&ipamv1.IPAddressClaim{}).
+		Build()
+
+	// The node subnet is waiting for its address space.
+	allocated, err := reconcileSubnetIPAddressClaims(context.Background(), c, azureCluster, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeFalse())
+	g.Expect(azureCluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks).To(BeEmpty())
+
+	claims := &ipamv1.IPAddressClaimList{}
+	g.Expect(c.List(context.Background(), claims)).To(Succeed())
+	g.Expect(claims.Items).To(HaveLen(1))
+	claim := claims.Items[0]
+	g.Expect(claim.Name).To(Equal("my-azure-cluster-node"))
+	g.Expect(claim.Spec.PoolRef).To(Equal(fakeIPAMPoolRef))
+	g.Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "my-cluster"))
+	g.Expect(claim.OwnerReferences).To(HaveLen(1))
+	g.Expect(claim.OwnerReferences[0].Kind).To(Equal(infrav1.AzureClusterKind))
+	g.Expect(claim.OwnerReferences[0].Controller).To(Equal(ptr.To(true)))
+
+	// The IPAM provider allocates the address space.
+	allocateIPAddressClaims(g, c, "10.1.0.0", 8)
+
+	allocated, err = reconcileSubnetIPAddressClaims(context.Background(), c, azureCluster, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeTrue())
+	g.Expect(azureCluster.Spec.NetworkSpec.Subnets[0].CIDRBlocks).To(Equal([]string{"10.1.0.0/16"}))
+	g.Expect(azureCluster.Spec.NetworkSpec.Subnets[1].CIDRBlocks).To(Equal([]string{"10.0.0.0/16"}))
+
+	// The claims are released when the cluster is deleted.
+	g.Expect(deleteSubnetIPAddressClaims(context.Background(), c, azureCluster)).To(Succeed())
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: claim.Namespace, Name: claim.Name}, &ipamv1.IPAddressClaim{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(deleteSubnetIPAddressClaims(context.Background(), c, azureCluster)).To(Succeed())
+}
+
+func TestReconcileSubnetIPAddressClaimsWithoutPool(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+
+	azureCluster := getFakeAzureCluster()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(azureCluster).Build()
+
+	allocated, err := reconcileSubnetIPAddressClaims(context.Background(), c, azureCluster, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(allocated).To(BeTrue())
+
+	claims := &ipamv1.IPAddressClaimList{}
+	g.Expect(c.List(context.Background(), claims)).To(Succeed())
+	g.Expect(claims.Items).To(BeEmpty())
+}
+
+func TestIPAddressToCIDR(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		prefix      int
+		vnet        infrav1.VnetClassSpec
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "IPv4 address with the default subnet prefix length of the default vnet",
+			address:  "10.1.0.0",
+			prefix:   8,
+			expected: "10.1.0.0/16",
+		},
+		{
+			name:     "IPv4 address with the subnet prefix length of the vnet",
+			address:  "10.1.2.0",
+			prefix:   16,
+			vnet:     infrav1.VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}, SubnetPrefixLength: ptr.To[int32](24)},
+			expected: "10.1.2.0/24",
+		},
+		{
+			name:     "IPv4 address with the default subnet prefix length of the vnet CIDR block containing it",
+			address:  "192.168.1.0",
+			prefix:   16,
+			vnet:     infrav1.VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+			expected: "192.168.1.0/24",
+		},
+		{
+			name:     "IPv6 address",
+			address:  "2001:1234:5678:9abd::",
+			prefix:   48,
+			expected: "2001:1234:5678:9abd::/64",
+		},
+		{
+			name:        "IPv4 address not aligned on the subnet prefix length",
+			address:     "10.1.2.3",
+			prefix:      8,
+			expectedErr: "IPAddress my-address has the address 10.1.2.3, which isn't the first address of a /16 address space; the pool must only contain addresses aligned on the subnet prefix length",
+		},
+		{
+			name:        "IPv6 address not aligned on the subnet prefix length",
+			address:     "2001:1234:5678:9abd::1",
+			prefix:      48,
+			expectedErr: "IPAddress my-address has the address 2001:1234:5678:9abd::1, which isn't the first address of a /64 address space; the pool must only contain addresses aligned on the subnet prefix length",
+		},
+		{
+			name:        "IPv4 address outside of the vnet",
+			address:     "192.168.1.0",
+			prefix:      16,
+			expectedErr: "IPAddress my-address can't be used as a subnet address space: address 192.168.1.0 is not within the address space [10.0.0.0/8] of the virtual network",
+		},
+		{
+			name:        "invalid address",
+			address:     "10.1.2",
+			prefix:      24,
+			expectedErr: `IPAddress my-address has an invalid address "10.1.2"`,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			address := &ipamv1.IPAddress{}
+			address.Name = "my-address"
+			address.Spec.Address = tc.address
+			address.Spec.Prefix = tc.prefix
+
+			cidr, err := ipAddressToCIDR(address, tc.vnet)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(tc.expectedErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cidr).To(Equal(tc.expected))
+			}
+		})
+	}
+}
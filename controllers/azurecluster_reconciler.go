@@ -23,8 +23,10 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
@@ -39,16 +41,27 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// apiServerLBMigrator migrates the API server load balancer of a cluster from Public to Internal.
+type apiServerLBMigrator interface {
+	ReconcileAPIServerLBMigration(ctx context.Context) error
+}
+
 // azureClusterService is the reconciler called by the AzureCluster controller.
 type azureClusterService struct {
 	scope *scope.ClusterScope
 	// services is the list of services that are reconciled by this controller.
 	// The order of the services is important as it determines the order in which the services are reconciled.
-	services  []azure.ServiceReconciler
-	skuCache  *resourceskus.Cache
-	Reconcile func(context.Context) error
-	Pause     func(context.Context) error
-	Delete    func(context.Context) error
+	services []azure.ServiceReconciler
+	skuCache *resourceskus.Cache
+	progress *objectServiceProgress
+	budget   serviceBudget
+	// filterControlPlaneZones determines whether control plane machines are only placed in the zones where the VM
+	// size of the control plane is available.
+	filterControlPlaneZones bool
+	Reconcile               func(context.Context) error
+	Pause                   func(context.Context) error
+	Delete                  func(context.Context) error
+	Retain                  func(context.Context) ([]string, error)
 }
 
 // newAzureClusterService populates all the services based on input scope.
@@ -81,6 +94,10 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, err
 	}
+	locksSvc, err := locks.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	acs := &azureClusterService{
 		scope: scope,
 		services: []azure.ServiceReconciler{
@@ -94,14 +111,18 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 			vnetPeeringsSvc,
 			loadbalancersSvc,
 			privateDNSSvc,
+			dnsrecords.New(scope),
 			privateendpoints.New(scope),
 			bastionhosts.New(scope),
+			// Locks are created once the resources they protect exist, and deleted before any of them.
+			locksSvc,
 		},
 		skuCache: skuCache,
 	}
 	acs.Reconcile = acs.reconcile
 	acs.Pause = acs.pause
 	acs.Delete = acs.delete
+	acs.Retain = acs.retain
 
 	return acs, nil
 }
@@ -119,12 +140,24 @@ func (s *azureClusterService) reconcile(ctx context.Context) error {
 	s.scope.SetDNSName()
 	s.scope.SetControlPlaneSecurityRules()
 
-	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
+	if err := s.budget.reconcile(ctx, s.services, func(ctx context.Context, service azure.ServiceReconciler) error {
+		if err := s.progress.reconcile(ctx, service); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureCluster service %s", service.Name())
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
+	// The API server load balancer is migrated once all the services are reconciled, so that the control plane
+	// endpoint is only switched to the internal load balancer once it and its private DNS record exist.
+	for _, service := range s.services {
+		if migrator, ok := service.(apiServerLBMigrator); ok {
+			if err := migrator.ReconcileAPIServerLBMigration(ctx); err != nil {
+				return errors.Wrap(err, "failed to migrate the API server load balancer")
+			}
+		}
+	}
 	return nil
 }
 
@@ -152,6 +185,15 @@ func (s *azureClusterService) delete(ctx context.Context) error {
 	defer done()
 
 	if !ShouldDeleteIndividualResources(ctx, s.scope) {
+		// Management locks would block the deletion of the resource group, so they're removed first.
+		locksSvc, err := s.getService(locks.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get locks service")
+		}
+		if err := locksSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete resource locks")
+		}
+
 		// If the resource group is managed, delete it.
 		// We need to explicitly delete vnet peerings, as it is not part of the resource group.
 		vnetPeeringsSvc, err := s.getService(vnetpeerings.ServiceName)
@@ -162,6 +204,15 @@ func (s *azureClusterService) delete(ctx context.Context) error {
 			return errors.Wrap(err, "failed to delete peerings")
 		}
 
+		// Records in Azure DNS zones aren't part of the resource group either.
+		dnsRecordsSvc, err := s.getService(dnsrecords.ServiceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to get DNS records service")
+		}
+		if err := dnsRecordsSvc.Delete(ctx); err != nil {
+			return errors.Wrap(err, "failed to delete DNS records")
+		}
+
 		groupSvc, err := s.getService(groups.ServiceName)
 		if err != nil {
 			return errors.Wrap(err, "failed to get group service")
@@ -184,9 +235,40 @@ func (s *azureClusterService) delete(ctx context.Context) error {
 	return nil
 }
 
+// retain detaches the components making up the cluster so that they're kept in Azure when the cluster is deleted.
+// It returns the IDs of the retained Azure resources.
+func (s *azureClusterService) retain(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Retain")
+	defer done()
+
+	var ids []string
+	for _, service := range s.services {
+		retainer, ok := service.(azure.Retainer)
+		if !ok {
+			// Resources that aren't backed by ASO are only deleted when the services are deleted.
+			continue
+		}
+		retained, err := retainer.Retain(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retain AzureCluster service %s", service.Name())
+		}
+		ids = append(ids, retained...)
+	}
+	if s.scope.IsOwnershipRecorded() {
+		ids = append(ids, s.scope.AzureCluster.Status.ManagedResources.IDs...)
+	}
+
+	return dedupeResourceIDs(ids), nil
+}
+
 // setFailureDomainsForLocation sets the AzureCluster Status failure domains based on which Azure Availability Zones are available in the cluster location.
+// When filtering control plane zones, only the zones where the VM size of the control plane machines is available are
+// control plane failure domains, so that the control plane doesn't place machines in zones where they can't be created.
 // Note that this is not done in a webhook as it requires API calls to fetch the availability zones.
 func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.setFailureDomainsForLocation")
+	defer done()
+
 	if s.scope.ExtendedLocation() != nil {
 		return nil
 	}
@@ -196,15 +278,51 @@ func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context)
 		return errors.Wrapf(err, "failed to get zones for location %s", s.scope.Location())
 	}
 
+	controlPlaneZones, err := s.controlPlaneZones(ctx)
+	if err != nil {
+		return err
+	}
+
 	for _, zone := range zones {
+		controlPlane := controlPlaneZones == nil || controlPlaneZones[zone]
+		if !controlPlane {
+			log.V(4).Info("control plane VM size is not available in zone, it is not a control plane failure domain", "zone", zone)
+		}
 		s.scope.SetFailureDomain(zone, clusterv1.FailureDomainSpec{
-			ControlPlane: true,
+			ControlPlane: controlPlane,
 		})
 	}
 
 	return nil
 }
 
+// controlPlaneZones returns the zones where the VM size of the control plane machines is available, or nil when all
+// zones may host control plane machines.
+func (s *azureClusterService) controlPlaneZones(ctx context.Context) (map[string]bool, error) {
+	if !s.filterControlPlaneZones {
+		return nil, nil
+	}
+
+	vmSize, err := s.scope.ControlPlaneVMSize(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the VM size of the control plane")
+	}
+	if vmSize == "" {
+		return nil, nil
+	}
+
+	zones, err := s.skuCache.GetZonesWithVMSize(ctx, vmSize, s.scope.Location())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get zones for VM size %s in location %s", vmSize, s.scope.Location())
+	}
+
+	controlPlaneZones := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		controlPlaneZones[zone] = true
+	}
+	return controlPlaneZones, nil
+}
+
 func (s *azureClusterService) getService(name string) (azure.ServiceReconciler, error) {
 	for _, service := range s.services {
 		if service.Name() == name {
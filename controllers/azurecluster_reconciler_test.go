@@ -26,18 +26,26 @@ import (
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/locks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -106,6 +114,57 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 	}
 }
 
+func TestAzureClusterServiceReconcileSpans(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	var services []azure.ServiceReconciler
+	for _, name := range []string{"one", "two"} {
+		name := name
+		svc := mock_azure.NewMockServiceReconciler(mockCtrl)
+		svc.EXPECT().Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+			_, _, done := tele.StartSpanWithLogger(ctx, name+".Service.Reconcile")
+			defer done()
+			return nil
+		})
+		services = append(services, svc)
+	}
+
+	s := &azureClusterService{
+		scope: &scope.ClusterScope{
+			Cluster:      &clusterv1.Cluster{},
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+		services: services,
+		skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
+	}
+
+	// The root span stands for the span of the controller-runtime reconcile.
+	ctx, root := tele.Tracer().Start(context.Background(), "controllers.AzureClusterReconciler.Reconcile")
+	g.Expect(s.reconcile(ctx)).To(Succeed())
+	root.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == root.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+	g.Expect(spans).To(HaveKey("controllers.azureClusterService.Reconcile"))
+	reconcileSpan := spans["controllers.azureClusterService.Reconcile"]
+	g.Expect(reconcileSpan.Parent().SpanID()).To(Equal(root.SpanContext().SpanID()))
+	for _, name := range []string{"one.Service.Reconcile", "two.Service.Reconcile"} {
+		g.Expect(spans).To(HaveKey(name))
+		g.Expect(spans[name].Parent().SpanID()).To(Equal(reconcileSpan.SpanContext().SpanID()), name)
+	}
+}
+
 func TestAzureClusterServicePause(t *testing.T) {
 	type pausingServiceReconciler struct {
 		*mock_azure.MockServiceReconciler
@@ -222,12 +281,22 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, dns *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, lck *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					two.Name().Return("two"),
+					lck.Name().Return(locks.ServiceName),
+					lck.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
 					vpr.Name().Return(vnetpeerings.ServiceName),
 					vpr.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					dns.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
 					grp.Delete(gomockinternal.AContext()).Return(nil))
 			},
 		},
@@ -256,15 +325,60 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 
 				return c
 			},
-			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, dns *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, lck *mock_azure.MockServiceReconcilerMockRecorder) {
 				gomock.InOrder(
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					two.Name().Return("two"),
+					lck.Name().Return(locks.ServiceName),
+					lck.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
 					vpr.Name().Return(vnetpeerings.ServiceName),
 					vpr.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					dns.Delete(gomockinternal.AContext()).Return(nil),
+					grp.Name().Return(groups.ServiceName),
 					grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
 			},
 		},
+		"Resource locks delete fails": {
+			expectedError: "failed to delete resource locks: internal error",
+			clientBuilder: func(g Gomega) client.Client {
+				scheme := runtime.NewScheme()
+				g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+				g.Expect(asoresourcesv1.AddToScheme(scheme)).To(Succeed())
+
+				rg := &asoresourcesv1.ResourceGroup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            resourceGroup,
+						Namespace:       namespace,
+						OwnerReferences: ownerRefs,
+						Annotations: map[string]string{
+							asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+						},
+					},
+				}
+
+				c := fakeclient.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(rg).
+					Build()
+
+				return c
+			},
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, vpr *mock_azure.MockServiceReconcilerMockRecorder, dns *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, lck *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					grp.Name().Return(groups.ServiceName),
+					vpr.Name().Return(vnetpeerings.ServiceName),
+					dns.Name().Return(dnsrecords.ServiceName),
+					two.Name().Return("two"),
+					lck.Name().Return(locks.ServiceName),
+					lck.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
+			},
+		},
 		"Resource Group not owned by cluster": {
 			expectedError: "",
 			clientBuilder: func(g Gomega) client.Client {
@@ -389,3 +503,304 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureClusterServiceRetain(t *testing.T) {
+	type retainingServiceReconciler struct {
+		*mock_azure.MockServiceReconciler
+		*mock_azure.MockRetainer
+	}
+
+	cases := map[string]struct {
+		managedResources *infrav1.ManagedResources
+		expectedIDs      []string
+		expectedError    string
+		expect           func(aso retainingServiceReconciler, arm *mock_azure.MockServiceReconcilerMockRecorder)
+	}{
+		"ASO-backed resources are retained and direct ARM resources are not deleted": {
+			managedResources: &infrav1.ManagedResources{
+				IDs: []string{
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb",
+					"/SUBSCRIPTIONS/123/RESOURCEGROUPS/MY-RG",
+				},
+			},
+			expectedIDs: []string{
+				"/subscriptions/123/resourceGroups/my-rg",
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb",
+			},
+			expect: func(aso retainingServiceReconciler, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+				aso.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return([]string{
+					"/subscriptions/123/resourceGroups/my-rg",
+					"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				}, nil)
+			},
+		},
+		"resources created before ownership was recorded": {
+			managedResources: nil,
+			expectedIDs: []string{
+				"/subscriptions/123/resourceGroups/my-rg",
+			},
+			expect: func(aso retainingServiceReconciler, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+				aso.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return([]string{
+					"/subscriptions/123/resourceGroups/my-rg",
+				}, nil)
+			},
+		},
+		"service retain fails": {
+			expectedError: "failed to retain AzureCluster service aso: some error happened",
+			expect: func(aso retainingServiceReconciler, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					aso.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return(nil, errors.New("some error happened")),
+					aso.MockServiceReconciler.EXPECT().Name().Return("aso"))
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			asoMock := retainingServiceReconciler{
+				mock_azure.NewMockServiceReconciler(mockCtrl),
+				mock_azure.NewMockRetainer(mockCtrl),
+			}
+			// Any call to Delete on the direct ARM service fails the test.
+			armMock := mock_azure.NewMockServiceReconciler(mockCtrl)
+
+			tc.expect(asoMock, armMock.EXPECT())
+
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Status: infrav1.AzureClusterStatus{
+							ManagedResources: tc.managedResources,
+						},
+					},
+				},
+				services: []azure.ServiceReconciler{
+					asoMock,
+					armMock,
+				},
+			}
+
+			ids, err := s.retain(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ids).To(Equal(tc.expectedIDs))
+			}
+		})
+	}
+}
+
+func TestAzureClusterServiceSetFailureDomainsForLocation(t *testing.T) {
+	location := "westus2"
+	vmSKU := func(name string, restrictedZones ...string) armcompute.ResourceSKU {
+		sku := armcompute.ResourceSKU{
+			Name:         ptr.To(name),
+			ResourceType: ptr.To(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{ptr.To(location)},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: ptr.To(location),
+					Zones:    []*string{ptr.To("1"), ptr.To("2"), ptr.To("3")},
+				},
+			},
+		}
+		if len(restrictedZones) > 0 {
+			sku.Restrictions = []*armcompute.ResourceSKURestrictions{
+				{
+					Type: ptr.To(armcompute.ResourceSKURestrictionsTypeZone),
+					RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{
+						Zones: azure.PtrSlice(&restrictedZones),
+					},
+				},
+			}
+		}
+		return sku
+	}
+	skus := []armcompute.ResourceSKU{
+		vmSKU("Standard_D2s_v3"),
+		vmSKU("Standard_D4s_v3", "3"),
+	}
+
+	controlPlane := func(infrastructureRef map[string]interface{}) *unstructured.Unstructured {
+		kcp := &unstructured.Unstructured{}
+		kcp.SetAPIVersion("controlplane.cluster.x-k8s.io/v1beta1")
+		kcp.SetKind("KubeadmControlPlane")
+		kcp.SetNamespace("default")
+		kcp.SetName("my-cluster-control-plane")
+		if infrastructureRef != nil {
+			g := NewWithT(t)
+			g.Expect(unstructured.SetNestedMap(kcp.Object, infrastructureRef, "spec", "machineTemplate", "infrastructureRef")).To(Succeed())
+		}
+		return kcp
+	}
+	azureMachineTemplateRef := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": infrav1.GroupVersion.String(),
+			"kind":       infrav1.AzureMachineTemplateKind,
+			"name":       name,
+		}
+	}
+	azureMachineTemplate := func(name, vmSize string) *infrav1.AzureMachineTemplate {
+		return &infrav1.AzureMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: infrav1.AzureMachineTemplateSpec{
+				Template: infrav1.AzureMachineTemplateResource{
+					Spec: infrav1.AzureMachineSpec{VMSize: vmSize},
+				},
+			},
+		}
+	}
+	allZones := clusterv1.FailureDomains{
+		"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+	}
+	restrictedZones := clusterv1.FailureDomains{
+		"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"3": clusterv1.FailureDomainSpec{ControlPlane: false},
+	}
+
+	cases := map[string]struct {
+		filter                 bool
+		noControlPlaneRef      bool
+		objects                []client.Object
+		statusFailureDomains   clusterv1.FailureDomains
+		specFailureDomains     clusterv1.FailureDomains
+		expectedFailureDomains clusterv1.FailureDomains
+	}{
+		"control plane VM size is restricted in a zone": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane")),
+				azureMachineTemplate("control-plane", "Standard_D4s_v3"),
+			},
+			expectedFailureDomains: restrictedZones,
+		},
+		"control plane VM size is available in all zones": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane")),
+				azureMachineTemplate("control-plane", "Standard_D2s_v3"),
+			},
+			expectedFailureDomains: allZones,
+		},
+		"zones are restored when the control plane template changes": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane-2")),
+				azureMachineTemplate("control-plane-1", "Standard_D4s_v3"),
+				azureMachineTemplate("control-plane-2", "Standard_D2s_v3"),
+			},
+			statusFailureDomains:   restrictedZones,
+			expectedFailureDomains: allZones,
+		},
+		"zones disabled for the control plane in the spec stay disabled": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane")),
+				azureMachineTemplate("control-plane", "Standard_D2s_v3"),
+			},
+			specFailureDomains: clusterv1.FailureDomains{
+				"1": clusterv1.FailureDomainSpec{ControlPlane: false},
+			},
+			expectedFailureDomains: clusterv1.FailureDomains{
+				"1": clusterv1.FailureDomainSpec{ControlPlane: false},
+				"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+			},
+		},
+		"filtering is disabled": {
+			filter: false,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane")),
+				azureMachineTemplate("control-plane", "Standard_D4s_v3"),
+			},
+			expectedFailureDomains: allZones,
+		},
+		"cluster has no control plane": {
+			filter:                 true,
+			noControlPlaneRef:      true,
+			expectedFailureDomains: allZones,
+		},
+		"control plane doesn't exist yet": {
+			filter:                 true,
+			expectedFailureDomains: allZones,
+		},
+		"control plane template doesn't exist yet": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(azureMachineTemplateRef("control-plane")),
+			},
+			expectedFailureDomains: allZones,
+		},
+		"control plane machines are not created from an AzureMachineTemplate": {
+			filter: true,
+			objects: []client.Object{
+				controlPlane(map[string]interface{}{
+					"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+					"kind":       "OtherMachineTemplate",
+					"name":       "control-plane",
+				}),
+			},
+			expectedFailureDomains: allZones,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			scheme, err := newScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+			}
+			if !tc.noControlPlaneRef {
+				cluster.Spec.ControlPlaneRef = &corev1.ObjectReference{
+					APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+					Kind:       "KubeadmControlPlane",
+					Name:       "my-cluster-control-plane",
+					Namespace:  "default",
+				}
+			}
+
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Client:  fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build(),
+					Cluster: cluster,
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+						Spec: infrav1.AzureClusterSpec{
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location:       location,
+								FailureDomains: tc.specFailureDomains,
+							},
+						},
+						Status: infrav1.AzureClusterStatus{
+							FailureDomains: tc.statusFailureDomains.DeepCopy(),
+						},
+					},
+				},
+				skuCache:                resourceskus.NewStaticCache(skus, location),
+				filterControlPlaneZones: tc.filter,
+			}
+
+			g.Expect(s.setFailureDomainsForLocation(context.Background())).To(Succeed())
+			g.Expect(s.scope.AzureCluster.Status.FailureDomains).To(Equal(tc.expectedFailureDomains))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// sharedVNetInUseRequeueInterval is how often the deletion of an AzureCluster waits for the other AzureClusters using
+// a virtual network in its managed resource group to go away.
+const sharedVNetInUseRequeueInterval = time.Minute
+
+// releaseSharedVNet releases the virtual network of a deleted AzureCluster so that it isn't deleted while other
+// AzureClusters still use it. A virtual network in the managed resource group of the AzureCluster can't outlive the
+// resource group, so the deletion waits until no other AzureCluster uses it.
+func (acr *AzureClusterReconciler) releaseSharedVNet(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterReconciler.releaseSharedVNet")
+	defer done()
+
+	azureCluster := clusterScope.AzureCluster
+	if clusterScope.Vnet().ResourceGroup == clusterScope.ResourceGroup() && !ShouldDeleteIndividualResources(ctx, clusterScope) {
+		users, err := virtualnetworks.VNetUsers(ctx, clusterScope)
+		if err != nil {
+			return err
+		}
+		if len(users) > 0 {
+			msg := fmt.Sprintf("virtual network %s is still used by AzureClusters %s", clusterScope.Vnet().Name, strings.Join(users, ", "))
+			log.V(2).Info("waiting for shared virtual network to be released", "users", users)
+			acr.Recorder.Event(azureCluster, corev1.EventTypeWarning, "SharedVNetInUse", msg)
+			return azure.WithTransientError(errors.New(msg), sharedVNetInUseRequeueInterval)
+		}
+		return nil
+	}
+
+	newOwner, err := virtualnetworks.ReleaseVNet(ctx, clusterScope)
+	if err != nil {
+		return err
+	}
+	if newOwner != "" {
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeNormal, "SharedVNetHandedOver", "virtual network %s is now managed by AzureCluster %s", clusterScope.Vnet().Name, newOwner)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	asonetworkv1 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
+	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
+	asoannotations "github.com/Azure/azure-service-operator/v2/pkg/common/annotations"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestAzureClusterReconcileDeleteSharedVNet(t *testing.T) {
+	// The AzureCluster being deleted created the virtual network, which another AzureCluster uses.
+	owner := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       infrav1.AzureClusterKind,
+		Name:       "my-azure-cluster",
+		UID:        "my-azure-cluster-uid",
+		Controller: ptr.To(true),
+	}
+	other := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-azure-cluster",
+			Namespace: namespace,
+			UID:       "other-azure-cluster-uid",
+		},
+	}
+	user := metav1.OwnerReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       infrav1.AzureClusterKind,
+		Name:       other.Name,
+		UID:        other.UID,
+	}
+	newVNet := func() *asonetworkv1.VirtualNetwork {
+		return &asonetworkv1.VirtualNetwork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-vnet",
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{owner, user},
+			},
+		}
+	}
+	withSharedVNet := func(ac *infrav1.AzureCluster) {
+		ac.UID = owner.UID
+		ac.Spec.ResourceGroup = "my-rg"
+		ac.Spec.NetworkSpec.Vnet.Name = "my-vnet"
+		ac.Spec.NetworkSpec.Vnet.ResourceGroup = "my-rg"
+	}
+
+	cases := map[string]struct {
+		input           TestClusterReconcileInput
+		clusterDeleting bool
+		deleted         bool
+		vnetOwners      []metav1.OwnerReference
+		event           string
+	}{
+		"hands the virtual network over to the other cluster": {
+			input: TestClusterReconcileInput{
+				azureClusterOptions: withSharedVNet,
+				objects:             []runtime.Object{other, newVNet()},
+			},
+			deleted: true,
+			vnetOwners: []metav1.OwnerReference{
+				{
+					APIVersion:         user.APIVersion,
+					Kind:               user.Kind,
+					Name:               user.Name,
+					UID:                user.UID,
+					Controller:         ptr.To(true),
+					BlockOwnerDeletion: ptr.To(true),
+				},
+			},
+			event: "Normal SharedVNetHandedOver virtual network my-vnet is now managed by AzureCluster other-azure-cluster",
+		},
+		"waits for the other cluster before deleting the managed resource group": {
+			input: TestClusterReconcileInput{
+				azureClusterOptions: withSharedVNet,
+				objects: []runtime.Object{
+					other,
+					newVNet(),
+					&asoresourcesv1.ResourceGroup{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "my-rg",
+							Namespace:       namespace,
+							OwnerReferences: []metav1.OwnerReference{owner},
+							Annotations: map[string]string{
+								asoannotations.ReconcilePolicy: string(asoannotations.ReconcilePolicyManage),
+							},
+						},
+					},
+				},
+				expectedResult: reconcile.Result{RequeueAfter: sharedVNetInUseRequeueInterval},
+			},
+			clusterDeleting: true,
+			vnetOwners:      []metav1.OwnerReference{owner, user},
+			event:           "Warning SharedVNetInUse virtual network my-vnet is still used by AzureClusters other-azure-cluster",
+		},
+	}
+
+	for name, c := range cases {
+		tc := c
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			deleted := false
+			tc.input.cache = &scope.ClusterCache{}
+			tc.input.createAzureClusterService = func(cs *scope.ClusterScope) (*azureClusterService, error) {
+				return getDefaultAzureClusterService(func(acs *azureClusterService) {
+					acs.skuCache = resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, cs.Location())
+					acs.scope = cs
+					acs.Delete = func(context.Context) error {
+						if deleted {
+							return errors.New("resources deleted twice")
+						}
+						deleted = true
+						return nil
+					}
+				}), nil
+			}
+
+			reconciler, clusterScope, err := getClusterReconcileInputs(tc.input)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.clusterDeleting {
+				clusterScope.Cluster.DeletionTimestamp = ptr.To(metav1.Now())
+			}
+
+			result, err := reconciler.reconcileDelete(context.Background(), clusterScope)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.input.expectedResult))
+			g.Expect(deleted).To(Equal(tc.deleted))
+
+			vnet := &asonetworkv1.VirtualNetwork{}
+			g.Expect(reconciler.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "my-vnet"}, vnet)).To(Succeed())
+			g.Expect(vnet.OwnerReferences).To(Equal(tc.vnetOwners))
+
+			g.Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(tc.event)))
+		})
+	}
+}
@@ -19,6 +19,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -44,24 +47,32 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// preTerminateHookRequeueInterval is how often the deletion of an AzureMachine checks whether the pre-terminate hooks
+// of its Machine have been removed.
+const preTerminateHookRequeueInterval = 30 * time.Second
+
 // AzureMachineReconciler reconciles an AzureMachine object.
 type AzureMachineReconciler struct {
 	client.Client
 	Recorder                  record.EventRecorder
 	Timeouts                  reconciler.Timeouts
 	WatchFilterValue          string
+	FailOnProvisioningTimeout bool
 	createAzureMachineService azureMachineServiceCreator
+	serviceProgress           *serviceProgressRecorder
 }
 
 type azureMachineServiceCreator func(machineScope *scope.MachineScope) (*azureMachineService, error)
 
 // NewAzureMachineReconciler returns a new AzureMachineReconciler instance.
-func NewAzureMachineReconciler(client client.Client, recorder record.EventRecorder, timeouts reconciler.Timeouts, watchFilterValue string) *AzureMachineReconciler {
+func NewAzureMachineReconciler(client client.Client, recorder record.EventRecorder, timeouts reconciler.Timeouts, watchFilterValue string, failOnProvisioningTimeout bool) *AzureMachineReconciler {
 	amr := &AzureMachineReconciler{
-		Client:           client,
-		Recorder:         recorder,
-		Timeouts:         timeouts,
-		WatchFilterValue: watchFilterValue,
+		Client:                    client,
+		Recorder:                  recorder,
+		Timeouts:                  timeouts,
+		WatchFilterValue:          watchFilterValue,
+		FailOnProvisioningTimeout: failOnProvisioningTimeout,
+		serviceProgress:           newServiceProgressRecorder(recorder),
 	}
 
 	amr.createAzureMachineService = newAzureMachineService
@@ -208,6 +219,8 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		Machine:      machine,
 		AzureMachine: azureMachine,
 		ClusterScope: clusterScope,
+
+		VMNotFoundGracePeriod: amr.Timeouts.DefaultedVMNotFoundGracePeriod(),
 	})
 	if err != nil {
 		amr.Recorder.Eventf(azureMachine, corev1.EventTypeWarning, "Error creating the machine scope", err.Error())
@@ -277,13 +290,21 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 	err := machineScope.InitMachineCache(ctx)
 	if err != nil {
 		if errors.As(err, &reconcileError) && reconcileError.IsTerminal() {
-			amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "SKUNotFound", errors.Wrap(err, "failed to initialize machine cache").Error())
+			reason := "SKUNotFound"
+			if conditions.GetReason(machineScope.AzureMachine, infrav1.VMRunningCondition) == infrav1.InvalidBootstrapDataReason {
+				reason = infrav1.InvalidBootstrapDataReason
+			}
+			amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, reason, errors.Wrap(err, "failed to initialize machine cache").Error())
 			log.Error(err, "Failed to initialize machine cache")
 			machineScope.SetFailureReason(capierrors.InvalidConfigurationMachineError)
 			machineScope.SetFailureMessage(err)
 			machineScope.SetNotReady()
 			return reconcile.Result{}, nil
 		}
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+			log.V(2).Info(fmt.Sprintf("transient failure to initialize machine cache, retrying: %s", reconcileError.Error()))
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
 		return reconcile.Result{}, errors.Wrap(err, "failed to init machine scope cache")
 	}
 
@@ -296,10 +317,21 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.New("VM identities are not ready")
 	}
 
+	// Record the Azure resources created for the machine so that they are cleaned up on deletion even if the
+	// creation of the VM fails.
+	machineScope.InitManagedResources()
+
+	if remaining := backoffRemaining(machineScope.AzureMachine, time.Now()); remaining > 0 {
+		log.V(2).Info("backing off after transient failures to reconcile AzureMachine", "remaining", remaining)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
 	ams, err := amr.createAzureMachineService(machineScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 	}
+	ams.progress = amr.serviceProgress.forObject(machineScope.AzureMachine)
+	ams.budget = serviceBudget{timeouts: amr.Timeouts}
 
 	if err := ams.Reconcile(ctx); err != nil {
 		// This means that a VM was created and managed by this controller, but is not present anymore.
@@ -326,21 +358,44 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 			}
 
 			if reconcileError.IsTransient() {
+				// Bootstrapping may be stuck while the VM extensions are still being created.
+				amr.reconcileProvisioningTimeout(machineScope, time.Now())
 				if azure.IsOperationNotDoneError(reconcileError) {
 					log.V(2).Info(fmt.Sprintf("AzureMachine reconcile not done: %s", reconcileError.Error()))
-				} else {
-					log.V(2).Info(fmt.Sprintf("transient failure to reconcile AzureMachine, retrying: %s", reconcileError.Error()))
+					return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
 				}
-				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+				log.V(2).Info(fmt.Sprintf("transient failure to reconcile AzureMachine, retrying: %s", reconcileError.Error()))
+				return reconcile.Result{RequeueAfter: recordTransientError(machineScope.AzureMachine, reconcileError.RequeueAfter(), time.Now())}, nil
 			}
 		}
 		amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ReconcileError", errors.Wrapf(err, "failed to reconcile AzureMachine").Error())
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 	}
 
+	resetBackoff(machineScope.AzureMachine)
 	machineScope.SetReady()
 
-	return reconcile.Result{}, nil
+	// Requeue when the provisioning timeout expires, as nothing else triggers a reconcile while bootstrapping hangs.
+	return reconcile.Result{RequeueAfter: amr.reconcileProvisioningTimeout(machineScope, time.Now())}, nil
+}
+
+// reconcileProvisioningTimeout checks whether the VM bootstrapped within the provisioning timeout of the AzureMachine,
+// and marks the AzureMachine as failed when it didn't if FailOnProvisioningTimeout is set. It returns how long is
+// left until the timeout expires.
+func (amr *AzureMachineReconciler) reconcileProvisioningTimeout(machineScope *scope.MachineScope, now time.Time) time.Duration {
+	alreadyTimedOut := conditions.GetReason(machineScope.AzureMachine, infrav1.BootstrapWithinTimeoutCondition) == infrav1.BootstrapTimedOutReason
+	timedOut, remaining := machineScope.CheckProvisioningTimeout(now)
+	if !timedOut {
+		return remaining
+	}
+	if !alreadyTimedOut {
+		amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, infrav1.BootstrapTimedOutReason, conditions.GetMessage(machineScope.AzureMachine, infrav1.BootstrapWithinTimeoutCondition))
+	}
+	if amr.FailOnProvisioningTimeout && machineScope.AzureMachine.Status.FailureReason == nil {
+		machineScope.SetFailureReason(capierrors.CreateMachineError)
+		machineScope.SetFailureMessage(errors.New(conditions.GetMessage(machineScope.AzureMachine, infrav1.BootstrapWithinTimeoutCondition)))
+	}
+	return 0
 }
 
 func (amr *AzureMachineReconciler) reconcilePause(ctx context.Context, machineScope *scope.MachineScope) (reconcile.Result, error) {
@@ -367,6 +422,21 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 	defer done()
 
 	log.Info("Handling deleted AzureMachine")
+
+	// Like the Machine controller, wait for the owners of the pre-terminate hooks of the Machine to finish their
+	// cleanup before deleting the VM and its resources.
+	if hooks := preTerminateHooks(machineScope.Machine); len(hooks) > 0 {
+		log.Info("Waiting for pre-terminate hooks to be removed before deleting AzureMachine", "hooks", hooks)
+		conditions.Set(machineScope.AzureMachine, &clusterv1.Condition{
+			Type:    infrav1.WaitingForPreTerminateHookCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  infrav1.PreTerminateHookPendingReason,
+			Message: fmt.Sprintf("waiting for pre-terminate hooks %s", strings.Join(hooks, ", ")),
+		})
+		return reconcile.Result{RequeueAfter: preTerminateHookRequeueInterval}, nil
+	}
+	conditions.Delete(machineScope.AzureMachine, infrav1.WaitingForPreTerminateHookCondition)
+
 	conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := machineScope.PatchObject(ctx); err != nil {
 		return reconcile.Result{}, err
@@ -403,6 +473,19 @@ func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineS
 	// we're done deleting this AzureMachine so remove the finalizer.
 	log.Info("Removing finalizer from AzureMachine")
 	controllerutil.RemoveFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)
+	amr.serviceProgress.forget(machineScope.AzureMachine)
 
 	return reconcile.Result{}, nil
 }
+
+// preTerminateHooks returns the sorted pre-terminate deletion hook annotations of a Machine.
+func preTerminateHooks(machine *clusterv1.Machine) []string {
+	var hooks []string
+	for key := range machine.GetAnnotations() {
+		if strings.HasPrefix(key, clusterv1.PreTerminateDeleteHookAnnotationPrefix) {
+			hooks = append(hooks, key)
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
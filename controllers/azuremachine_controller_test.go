@@ -36,9 +36,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -264,6 +266,70 @@ func TestAzureMachineReconcileNormal(t *testing.T) {
 	}
 }
 
+func TestAzureMachineReconcileProvisioningTimeout(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		failOnProvisioningTimeout bool
+		alreadyTimedOut           bool
+		now                       time.Time
+		expectedRemaining         time.Duration
+		expectedFailureReason     *capierrors.MachineStatusError
+		expectedEvents            int
+	}{
+		"requeues until the timeout expires": {
+			now:               created.Add(10 * time.Minute),
+			expectedRemaining: 20 * time.Minute,
+		},
+		"reports the timeout without failing the machine": {
+			now:            created.Add(time.Hour),
+			expectedEvents: 1,
+		},
+		"fails the machine on timeout when enabled": {
+			failOnProvisioningTimeout: true,
+			now:                       created.Add(time.Hour),
+			expectedFailureReason:     ptr.To(capierrors.CreateMachineError),
+			expectedEvents:            1,
+		},
+		"reports the timeout only once": {
+			alreadyTimedOut: true,
+			now:             created.Add(2 * time.Hour),
+		},
+	}
+
+	for name, c := range cases {
+		tc := c
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			recorder := record.NewFakeRecorder(10)
+			amr := &AzureMachineReconciler{
+				Recorder:                  recorder,
+				FailOnProvisioningTimeout: tc.failOnProvisioningTimeout,
+			}
+			machineScope := &scope.MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						ProvisioningTimeout: &metav1.Duration{Duration: 30 * time.Minute},
+					},
+					Status: infrav1.AzureMachineStatus{
+						VMCreationTime: &metav1.Time{Time: created},
+					},
+				},
+			}
+			if tc.alreadyTimedOut {
+				machineScope.SetConditionFalse(infrav1.BootstrapWithinTimeoutCondition, infrav1.BootstrapTimedOutReason, clusterv1.ConditionSeverityError, "")
+			}
+
+			remaining := amr.reconcileProvisioningTimeout(machineScope, tc.now)
+			g.Expect(remaining).To(Equal(tc.expectedRemaining))
+			g.Expect(machineScope.AzureMachine.Status.FailureReason).To(Equal(tc.expectedFailureReason))
+			g.Expect(recorder.Events).To(HaveLen(tc.expectedEvents))
+		})
+	}
+}
+
 func TestAzureMachineReconcilePause(t *testing.T) {
 	cases := map[string]TestMachineReconcileInput{
 		"should pause successfully": {
@@ -333,9 +399,12 @@ func TestAzureMachineReconcileDelete(t *testing.T) {
 
 			reconciler, machineScope, clusterScope, err := getMachineReconcileInputs(tc)
 			g.Expect(err).NotTo(HaveOccurred())
+			controllerutil.AddFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)
 
 			result, err := reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
 			g.Expect(result).To(Equal(tc.expectedResult))
+			// The finalizer is only removed once all the resources of the AzureMachine are confirmed deleted.
+			g.Expect(controllerutil.ContainsFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)).To(Equal(tc.expectedErr != "" || !tc.expectedResult.IsZero()))
 
 			if tc.expectedErr != "" {
 				g.Expect(err).To(HaveOccurred())
@@ -347,6 +416,38 @@ func TestAzureMachineReconcileDelete(t *testing.T) {
 	}
 }
 
+func TestAzureMachineReconcileDeleteWaitsForPreTerminateHooks(t *testing.T) {
+	g := NewWithT(t)
+
+	reconciler, machineScope, clusterScope, err := getMachineReconcileInputs(TestMachineReconcileInput{
+		// Any attempt to delete the Azure resources while the hook is set fails the reconciliation.
+		createAzureMachineService: getFakeAzureMachineServiceWithFailure,
+		cache:                     &scope.MachineCache{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	controllerutil.AddFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)
+	machineScope.Machine.Annotations = map[string]string{
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/cleanup": "cleanup-controller",
+	}
+
+	result, err := reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: preTerminateHookRequeueInterval}))
+	g.Expect(controllerutil.ContainsFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)).To(BeTrue())
+	g.Expect(conditions.IsTrue(machineScope.AzureMachine, infrav1.WaitingForPreTerminateHookCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(machineScope.AzureMachine, infrav1.WaitingForPreTerminateHookCondition)).To(ContainSubstring("pre-terminate.delete.hook.machine.cluster.x-k8s.io/cleanup"))
+
+	// The deletion proceeds once the hook owner removes the annotation.
+	machineScope.Machine.Annotations = nil
+	reconciler.createAzureMachineService = getFakeAzureMachineService
+
+	result, err = reconciler.reconcileDelete(context.Background(), machineScope, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(controllerutil.ContainsFinalizer(machineScope.AzureMachine, infrav1.MachineFinalizer)).To(BeFalse())
+	g.Expect(conditions.Has(machineScope.AzureMachine, infrav1.WaitingForPreTerminateHookCondition)).To(BeFalse())
+}
+
 func getMachineReconcileInputs(tc TestMachineReconcileInput) (*AzureMachineReconciler, *scope.MachineScope, *scope.ClusterScope, error) {
 	scheme, err := newScheme()
 	if err != nil {
@@ -776,13 +877,17 @@ func TestConditions(t *testing.T) {
 				azureClusterIdentity,
 				defaultSecret,
 			}
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(initObjects...).
+				WithStatusSubresource(&infrav1.AzureMachine{}).
+				Build()
 			resultIdentity := &infrav1.AzureClusterIdentity{}
 			key := client.ObjectKey{Name: azureClusterIdentity.Name, Namespace: azureClusterIdentity.Namespace}
 			g.Expect(fakeClient.Get(context.TODO(), key, resultIdentity))
 			recorder := record.NewFakeRecorder(10)
 
-			reconciler := NewAzureMachineReconciler(fakeClient, recorder, reconciler.Timeouts{}, "")
+			reconciler := NewAzureMachineReconciler(fakeClient, recorder, reconciler.Timeouts{}, "", false)
 
 			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
 				Client:       fakeClient,
@@ -42,6 +42,8 @@ type azureMachineService struct {
 	// The order of the services is important as it determines the order in which the services are reconciled.
 	services  []azure.ServiceReconciler
 	skuCache  *resourceskus.Cache
+	progress  *objectServiceProgress
+	budget    serviceBudget
 	Reconcile func(context.Context) error
 	Pause     func(context.Context) error
 	Delete    func(context.Context) error
@@ -123,13 +125,12 @@ func (s *azureMachineService) reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed defaulting subnet name")
 	}
 
-	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
+	return s.budget.reconcile(ctx, s.services, func(ctx context.Context, service azure.ServiceReconciler) error {
+		if err := s.progress.reconcile(ctx, service); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureMachine service %s", service.Name())
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // pause pauses all components making up the machine.
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -44,6 +46,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// AuthorizedIPRangesDriftedReason is the event reason emitted when the authorized IP ranges of the API server of an
+// AKS cluster were changed outside of CAPZ.
+const AuthorizedIPRangesDriftedReason = "AuthorizedIPRangesDrifted"
+
+// nodeResourceGroupNotEmptyRequeue is how long to wait before checking again whether the resources blocking the
+// deletion of a protected node resource group have been removed.
+const nodeResourceGroupNotEmptyRequeue = time.Minute
+
 // AzureManagedControlPlaneReconciler reconciles an AzureManagedControlPlane object.
 type AzureManagedControlPlaneReconciler struct {
 	client.Client
@@ -51,6 +61,7 @@ type AzureManagedControlPlaneReconciler struct {
 	Timeouts                                 reconciler.Timeouts
 	WatchFilterValue                         string
 	getNewAzureManagedControlPlaneReconciler func(scope *scope.ManagedControlPlaneScope) (*azureManagedControlPlaneService, error)
+	serviceProgress                          *serviceProgressRecorder
 }
 
 // SetupWithManager initializes this controller with a manager.
@@ -62,6 +73,7 @@ func (amcpr *AzureManagedControlPlaneReconciler) SetupWithManager(ctx context.Co
 	defer done()
 
 	amcpr.getNewAzureManagedControlPlaneReconciler = newAzureManagedControlPlaneReconciler
+	amcpr.serviceProgress = newServiceProgressRecorder(amcpr.Recorder)
 	var r reconcile.Reconciler = amcpr
 	if options.Cache != nil {
 		r = coalescing.NewReconciler(amcpr, options.Cache, log)
@@ -111,17 +123,20 @@ func (amcpr *AzureManagedControlPlaneReconciler) SetupWithManager(ctx context.Co
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedcontrolplanes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedcontrolplanes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedcontrolplanetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=resources.azure.com,resources=resourcegroups/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=containerservice.azure.com,resources=managedclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=containerservice.azure.com,resources=managedclusters/status,verbs=get;list;watch
-// +kubebuilder:rbac:groups=network.azure.com,resources=privateendpoints;virtualnetworks;virtualnetworkssubnets,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=network.azure.com,resources=privateendpoints/status;virtualnetworks/status;virtualnetworkssubnets/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=network.azure.com,resources=privateendpoints;virtualnetworks;virtualnetworkssubnets;dnszonesarecords;dnszonesaaaarecords;dnszonescnamerecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=network.azure.com,resources=privateendpoints/status;virtualnetworks/status;virtualnetworkssubnets/status;dnszonesarecords/status;dnszonesaaaarecords/status;dnszonescnamerecords/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=containerservice.azure.com,resources=fleetsmembers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=containerservice.azure.com,resources=fleetsmembers/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=kubernetesconfiguration.azure.com,resources=extensions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubernetesconfiguration.azure.com,resources=extensions/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.azure.com,resources=roleassignments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authorization.azure.com,resources=roleassignments/status,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a managed control plane.
 func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -196,9 +211,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 
 	// Always patch when exiting so we can persist changes to finalizers and status
 	defer func() {
+		reconcileIdentityFallback(amcpr.Recorder, azureControlPlane, mcpScope)
 		if err := mcpScope.Close(ctx); err != nil && reterr == nil {
 			reterr = err
 		}
+		updateManagedResourcesMetric(azureControlPlane, mcpScope.ClusterName(), infrav1.ManagedClusterFinalizer, azureControlPlane.Status.ManagedResources)
 	}()
 
 	// Return early if the object or Cluster is paused.
@@ -248,11 +265,20 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Con
 		}
 	}
 
+	scope.InitManagedResources()
+
 	svc, err := amcpr.getNewAzureManagedControlPlaneReconciler(scope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
 	}
-	if err := svc.Reconcile(ctx); err != nil {
+	svc.progress = amcpr.serviceProgress.forObject(scope.ControlPlane)
+	err = svc.Reconcile(ctx)
+	if observed, drifted := scope.AuthorizedIPRangesDrift(); drifted {
+		amcpr.Recorder.Eventf(scope.ControlPlane, corev1.EventTypeWarning, AuthorizedIPRangesDriftedReason,
+			"authorized IP ranges of the API server %v don't match the desired ranges %v and are being reverted",
+			observed, scope.ControlPlane.Spec.APIServerAccessProfile.AuthorizedIPRanges)
+	}
+	if err != nil {
 		// Handle transient and terminal errors
 		log := log.WithValues("name", scope.ControlPlane.Name, "namespace", scope.ControlPlane.Namespace)
 		var reconcileError azure.ReconcileError
@@ -297,6 +323,8 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcilePause(ctx context.Cont
 		return reconcile.Result{}, errors.Wrap(err, "failed to pause control plane services")
 	}
 	RemoveBlockMoveAnnotation(scope.ControlPlane)
+	// The cluster may be moved while it's paused, so don't keep its workload cluster client around.
+	scope.InvalidateRemoteClient()
 
 	return reconcile.Result{}, nil
 }
@@ -311,7 +339,19 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Con
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
 	}
-	if err := svc.Delete(ctx); err != nil {
+	if scope.ControlPlane.Spec.DeletionPolicy == infrav1.DeletionPolicyRetain {
+		ids, err := svc.Retain(ctx)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "error retaining AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
+		}
+		amcpr.Recorder.Eventf(scope.ControlPlane, corev1.EventTypeNormal, "AzureResourcesRetained", retainedResourcesMessage(ids))
+	} else if unmanaged, err := svc.checkNodeResourceGroup(ctx, scope.ControlPlane); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
+	} else if len(unmanaged) > 0 {
+		amcpr.Recorder.Eventf(scope.ControlPlane, corev1.EventTypeWarning, infrav1.NodeResourceGroupNotEmptyReason,
+			"deletion is blocked until the resources not created by AKS are removed from node resource group: %s", strings.Join(unmanaged, ", "))
+		return reconcile.Result{RequeueAfter: nodeResourceGroupNotEmptyRequeue}, nil
+	} else if err := svc.Delete(ctx); err != nil {
 		// Handle transient errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
@@ -327,6 +367,8 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Con
 
 	// Cluster is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(scope.ControlPlane, infrav1.ManagedClusterFinalizer)
+	amcpr.serviceProgress.forget(scope.ControlPlane)
+	scope.InvalidateRemoteClient()
 
 	if scope.ControlPlane.Spec.IdentityRef != nil {
 		err := RemoveClusterIdentityFinalizer(ctx, amcpr.Client, scope.ControlPlane, scope.ControlPlane.Spec.IdentityRef, infrav1.ManagedClusterFinalizer)
@@ -347,9 +389,16 @@ func (amcpr *AzureManagedControlPlaneReconciler) ClusterToAzureManagedControlPla
 	}
 
 	controlPlaneRef := c.Spec.ControlPlaneRef
-	if controlPlaneRef != nil && controlPlaneRef.Kind == infrav1.AzureManagedControlPlaneKind {
-		return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: controlPlaneRef.Namespace, Name: controlPlaneRef.Name}}}
+	ok, err := isAzureManagedControlPlaneRef(controlPlaneRef)
+	if err != nil {
+		if amcpr.Recorder != nil {
+			amcpr.Recorder.Eventf(c, corev1.EventTypeWarning, "UnrecognizedControlPlaneRef", err.Error())
+		}
+		return nil
+	}
+	if !ok {
+		return nil
 	}
 
-	return nil
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: controlPlaneRef.Namespace, Name: controlPlaneRef.Name}}}
 }
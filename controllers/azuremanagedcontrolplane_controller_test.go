@@ -49,6 +49,7 @@ func TestClusterToAzureManagedControlPlane(t *testing.T) {
 		name            string
 		controlPlaneRef *corev1.ObjectReference
 		expected        []ctrl.Request
+		expectedEvent   string
 	}{
 		{
 			name:            "nil",
@@ -78,12 +79,69 @@ func TestClusterToAzureManagedControlPlane(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "current apiVersion",
+			controlPlaneRef: &corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       infrav1.AzureManagedControlPlaneKind,
+				Name:       "name",
+				Namespace:  "namespace",
+			},
+			expected: []ctrl.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "name",
+						Namespace: "namespace",
+					},
+				},
+			},
+		},
+		{
+			name: "older apiVersion",
+			controlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       infrav1.AzureManagedControlPlaneKind,
+				Name:       "name",
+				Namespace:  "namespace",
+			},
+			expected: []ctrl.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "name",
+						Namespace: "namespace",
+					},
+				},
+			},
+		},
+		{
+			name: "unrecognized apiVersion",
+			controlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+				Kind:       infrav1.AzureManagedControlPlaneKind,
+				Name:       "name",
+				Namespace:  "namespace",
+			},
+			expected:      nil,
+			expectedEvent: "Warning UnrecognizedControlPlaneRef",
+		},
+		{
+			name: "invalid apiVersion",
+			controlPlaneRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1/extra",
+				Kind:       infrav1.AzureManagedControlPlaneKind,
+				Name:       "name",
+				Namespace:  "namespace",
+			},
+			expected:      nil,
+			expectedEvent: "Warning UnrecognizedControlPlaneRef",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			g := NewWithT(t)
-			actual := (&AzureManagedControlPlaneReconciler{}).ClusterToAzureManagedControlPlane(context.TODO(), &clusterv1.Cluster{
+			recorder := record.NewFakeRecorder(1)
+			actual := (&AzureManagedControlPlaneReconciler{Recorder: recorder}).ClusterToAzureManagedControlPlane(context.TODO(), &clusterv1.Cluster{
 				Spec: clusterv1.ClusterSpec{
 					ControlPlaneRef: test.controlPlaneRef,
 				},
@@ -93,6 +151,11 @@ func TestClusterToAzureManagedControlPlane(t *testing.T) {
 			} else {
 				g.Expect(actual).To(Equal(test.expected))
 			}
+			if test.expectedEvent == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+			} else {
+				g.Expect(recorder.Events).To(Receive(HavePrefix(test.expectedEvent)))
+			}
 		})
 	}
 }
@@ -115,6 +178,7 @@ func TestAzureManagedControlPlaneReconcilePaused(t *testing.T) {
 	g.Expect(sb.AddToScheme(s)).To(Succeed())
 	c := fake.NewClientBuilder().
 		WithScheme(s).
+		WithStatusSubresource(&infrav1.AzureManagedControlPlane{}).
 		Build()
 
 	recorder := record.NewFakeRecorder(1)
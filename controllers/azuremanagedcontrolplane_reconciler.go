@@ -19,38 +19,73 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aksextensions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsrecords"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/maintenanceconfigurations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privateendpoints"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnetroleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+const (
+	// aksAADServerID is the application ID of the AKS AAD server, which the tokens to access AAD-enabled clusters
+	// are issued for.
+	aksAADServerID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+	// aksAADClientID is the application ID of the AKS AAD client used by `az aks get-credentials` to sign users in.
+	aksAADClientID = "80faf920-1908-4b52-b5ef-a8e7bedfc67a"
+)
+
 // azureManagedControlPlaneService contains the services required by the cluster controller.
 type azureManagedControlPlaneService struct {
-	kubeclient client.Client
-	scope      managedclusters.ManagedClusterScope
-	services   []azure.ServiceReconciler
+	kubeclient              client.Client
+	scope                   managedclusters.ManagedClusterScope
+	services                []azure.ServiceReconciler
+	progress                *objectServiceProgress
+	nodeResourceGroupLister managedclusters.NodeResourceGroupLister
 }
 
 // newAzureManagedControlPlaneReconciler populates all the services based on input scope.
 func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) (*azureManagedControlPlaneService, error) {
+	managedClustersSvc, err := managedclusters.New(scope)
+	if err != nil {
+		return nil, err
+	}
+	maintenanceConfigurationsSvc, err := maintenanceconfigurations.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	resourceHealthSvc, err := resourcehealth.New(scope)
 	if err != nil {
 		return nil, err
 	}
+	nodeResourceGroupLister, err := managedclusters.NewNodeResourceGroupLister(scope)
+	if err != nil {
+		return nil, err
+	}
+	subnetRoleAssignmentsSvc, err := subnetroleassignments.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureManagedControlPlaneService{
 		kubeclient: scope.Client,
 		scope:      scope,
@@ -58,12 +93,17 @@ func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope
 			groups.New(scope),
 			virtualnetworks.New(scope),
 			subnets.New(scope),
-			managedclusters.New(scope),
+			subnetRoleAssignmentsSvc,
+			managedClustersSvc,
+			// The record of the API server is a CNAME of the FQDN of the managed cluster.
+			dnsrecords.New(scope),
+			maintenanceConfigurationsSvc,
 			privateendpoints.New(scope),
 			fleetsmembers.New(scope),
 			aksextensions.New(scope),
 			resourceHealthSvc,
 		},
+		nodeResourceGroupLister: nodeResourceGroupLister,
 	}, nil
 }
 
@@ -73,7 +113,7 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 	defer done()
 
 	for _, service := range r.services {
-		if err := service.Reconcile(ctx); err != nil {
+		if err := r.progress.reconcile(ctx, service); err != nil {
 			return errors.Wrapf(err, "failed to reconcile AzureManagedControlPlane service %s", service.Name())
 		}
 	}
@@ -82,6 +122,10 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to reconcile kubeconfig secret")
 	}
 
+	if err := r.reconcileOIDCIssuerSecret(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile OIDC issuer secret")
+	}
+
 	return nil
 }
 
@@ -103,6 +147,28 @@ func (r *azureManagedControlPlaneService) Pause(ctx context.Context) error {
 	return nil
 }
 
+// Retain detaches all components making up the cluster so that they're kept in Azure when the cluster is deleted.
+// It returns the IDs of the retained Azure resources.
+func (r *azureManagedControlPlaneService) Retain(ctx context.Context) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Retain")
+	defer done()
+
+	var ids []string
+	for _, service := range r.services {
+		retainer, ok := service.(azure.Retainer)
+		if !ok {
+			continue
+		}
+		retained, err := retainer.Retain(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retain AzureManagedControlPlane service %s", service.Name())
+		}
+		ids = append(ids, retained...)
+	}
+
+	return dedupeResourceIDs(ids), nil
+}
+
 // Delete reconciles all the services in a predetermined order.
 func (r *azureManagedControlPlaneService) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Delete")
@@ -118,6 +184,37 @@ func (r *azureManagedControlPlaneService) Delete(ctx context.Context) error {
 	return nil
 }
 
+// checkNodeResourceGroup verifies that the node resource group of a control plane protected by the
+// ProtectNodeResourceGroupAnnotation only contains resources created by AKS, and sets the NodeResourceGroupEmpty
+// condition accordingly. It returns the IDs of the resources that block the deletion of the control plane.
+func (r *azureManagedControlPlaneService) checkNodeResourceGroup(ctx context.Context, controlPlane *infrav1.AzureManagedControlPlane) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.checkNodeResourceGroup")
+	defer done()
+
+	if controlPlane.Annotations[infrav1.ProtectNodeResourceGroupAnnotation] != "true" || r.nodeResourceGroupLister == nil {
+		return nil, nil
+	}
+	nodeResourceGroup := controlPlane.Status.NodeResourceGroupName
+	if nodeResourceGroup == "" {
+		nodeResourceGroup = controlPlane.Spec.NodeResourceGroupName
+	}
+	if nodeResourceGroup == "" {
+		return nil, nil
+	}
+
+	unmanaged, err := managedclusters.UnmanagedNodeResourceGroupResources(ctx, r.nodeResourceGroupLister, nodeResourceGroup, controlPlane.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check the resources of node resource group %s", nodeResourceGroup)
+	}
+	if len(unmanaged) > 0 {
+		conditions.MarkFalse(controlPlane, infrav1.NodeResourceGroupEmptyCondition, infrav1.NodeResourceGroupNotEmptyReason, clusterv1.ConditionSeverityWarning,
+			"node resource group %s contains resources not created by AKS: %s", nodeResourceGroup, strings.Join(unmanaged, ", "))
+		return unmanaged, nil
+	}
+	conditions.MarkTrue(controlPlane, infrav1.NodeResourceGroupEmptyCondition)
+	return nil, nil
+}
+
 func (r *azureManagedControlPlaneService) reconcileKubeconfig(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.reconcileKubeconfig")
 	defer done()
@@ -132,6 +229,13 @@ func (r *azureManagedControlPlaneService) reconcileKubeconfig(ctx context.Contex
 		if i == 1 {
 			// 2nd kubeconfig is the user kubeconfig
 			kubeConfigSecret.Name = fmt.Sprintf("%s-user", kubeConfigSecret.Name)
+			if r.scope.IsAADEnabled() {
+				// The user kubeconfig of an AAD-enabled cluster has no usable credentials, users sign in with kubelogin.
+				var err error
+				if kubeConfigData, err = kubeloginKubeconfig(kubeConfigData, r.scope.TenantID(), r.scope.CloudEnvironment()); err != nil {
+					return err
+				}
+			}
 		}
 		if _, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, &kubeConfigSecret, func() error {
 			kubeConfigSecret.Data = map[string][]byte{
@@ -168,3 +272,58 @@ func (r *azureManagedControlPlaneService) reconcileKubeconfig(ctx context.Contex
 
 	return nil
 }
+
+// kubeloginKubeconfig returns the kubeconfig with its users authenticating through the kubelogin exec plugin, the same
+// way as the kubeconfig returned by `az aks get-credentials` for AAD-enabled clusters.
+func kubeloginKubeconfig(kubeConfigData []byte, tenantID, environment string) ([]byte, error) {
+	config, err := clientcmd.Load(kubeConfigData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to turn aks user credentials into kubeconfig file struct")
+	}
+	for _, authInfo := range config.AuthInfos {
+		authInfo.Token = ""
+		authInfo.AuthProvider = nil
+		authInfo.Exec = &clientcmdapi.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "kubelogin",
+			Args: []string{
+				"get-token",
+				"--login", "devicecode",
+				"--server-id", aksAADServerID,
+				"--client-id", aksAADClientID,
+				"--tenant-id", tenantID,
+				"--environment", environment,
+			},
+			InstallHint:     "kubelogin is not installed which is required to connect to AAD enabled cluster.\nTo learn more, please go to https://aka.ms/aks/kubelogin",
+			InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		}
+	}
+	kubeconfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal user kubeconfig with kubelogin")
+	}
+	return kubeconfig, nil
+}
+
+// reconcileOIDCIssuerSecret stores the OIDC issuer URL of the cluster in a secret once AKS reports one.
+func (r *azureManagedControlPlaneService) reconcileOIDCIssuerSecret(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.reconcileOIDCIssuerSecret")
+	defer done()
+
+	issuerURL := r.scope.OIDCIssuerURL()
+	if issuerURL == "" {
+		return nil
+	}
+
+	oidcSecret := r.scope.MakeOIDCIssuerSecret()
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, oidcSecret, func() error {
+		oidcSecret.Data = map[string][]byte{
+			scope.OIDCIssuerURLSecretKey: []byte(issuerURL),
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "failed to reconcile OIDC issuer secret for cluster")
+	}
+
+	return nil
+}
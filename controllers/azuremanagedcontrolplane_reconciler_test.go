@@ -20,12 +20,27 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAzureManagedControlPlaneServicePause(t *testing.T) {
@@ -97,3 +112,308 @@ func TestAzureManagedControlPlaneServicePause(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureManagedControlPlaneServiceRetain(t *testing.T) {
+	type retainingServiceReconciler struct {
+		*mock_azure.MockServiceReconciler
+		*mock_azure.MockRetainer
+	}
+
+	cases := map[string]struct {
+		expectedIDs   []string
+		expectedError string
+		expect        func(one retainingServiceReconciler, two retainingServiceReconciler)
+	}{
+		"all services are retained in order": {
+			expectedIDs: []string{
+				"/subscriptions/123/resourceGroups/my-rg",
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-aks",
+			},
+			expect: func(one retainingServiceReconciler, two retainingServiceReconciler) {
+				gomock.InOrder(
+					one.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return([]string{"/subscriptions/123/resourceGroups/my-rg"}, nil),
+					two.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return([]string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-aks"}, nil))
+			},
+		},
+		"service retain fails": {
+			expectedError: "failed to retain AzureManagedControlPlane service two: some error happened",
+			expect: func(one retainingServiceReconciler, two retainingServiceReconciler) {
+				gomock.InOrder(
+					one.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return(nil, nil),
+					two.MockRetainer.EXPECT().Retain(gomockinternal.AContext()).Return(nil, errors.New("some error happened")),
+					two.MockServiceReconciler.EXPECT().Name().Return("two"))
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			newRetainingServiceReconciler := func() retainingServiceReconciler {
+				return retainingServiceReconciler{
+					mock_azure.NewMockServiceReconciler(mockCtrl),
+					mock_azure.NewMockRetainer(mockCtrl),
+				}
+			}
+			svcOneMock := newRetainingServiceReconciler()
+			svcTwoMock := newRetainingServiceReconciler()
+
+			tc.expect(svcOneMock, svcTwoMock)
+
+			s := &azureManagedControlPlaneService{
+				services: []azure.ServiceReconciler{
+					svcOneMock,
+					svcTwoMock,
+				},
+			}
+
+			ids, err := s.Retain(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ids).To(Equal(tc.expectedIDs))
+			}
+		})
+	}
+}
+
+func TestAzureManagedControlPlaneServiceReconcileOIDCIssuerSecret(t *testing.T) {
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster-oidc-issuer",
+				Namespace: "default",
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		issuerURL    string
+		existing     []client.Object
+		expectSecret bool
+		expectedURL  string
+	}{
+		"no secret while the OIDC issuer is not enabled": {
+			issuerURL:    "",
+			expectSecret: false,
+		},
+		"secret is created once AKS reports the issuer URL": {
+			issuerURL:    "https://oidc.prod-aks.azure.com/tenant/cluster/",
+			expectSecret: true,
+			expectedURL:  "https://oidc.prod-aks.azure.com/tenant/cluster/",
+		},
+		"secret is updated when the issuer URL changes": {
+			issuerURL: "https://oidc.prod-aks.azure.com/tenant/new/",
+			existing: []client.Object{func() *corev1.Secret {
+				s := newSecret()
+				s.Data = map[string][]byte{scope.OIDCIssuerURLSecretKey: []byte("https://oidc.prod-aks.azure.com/tenant/old/")}
+				return s
+			}()},
+			expectSecret: true,
+			expectedURL:  "https://oidc.prod-aks.azure.com/tenant/new/",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.existing...).Build()
+
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			scopeMock.EXPECT().OIDCIssuerURL().Return(tc.issuerURL)
+			if tc.issuerURL != "" {
+				scopeMock.EXPECT().MakeOIDCIssuerSecret().Return(newSecret())
+			}
+
+			s := &azureManagedControlPlaneService{
+				kubeclient: kubeclient,
+				scope:      scopeMock,
+			}
+			g.Expect(s.reconcileOIDCIssuerSecret(context.TODO())).To(Succeed())
+
+			secret := &corev1.Secret{}
+			err := kubeclient.Get(context.TODO(), client.ObjectKeyFromObject(newSecret()), secret)
+			if !tc.expectSecret {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(secret.Data).To(HaveKeyWithValue(scope.OIDCIssuerURLSecretKey, []byte(tc.expectedURL)))
+		})
+	}
+}
+
+func TestAzureManagedControlPlaneServiceReconcileKubeconfigAAD(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newKubeconfig := func(token string) []byte {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["my-cluster"] = &clientcmdapi.Cluster{
+			Server:                   "https://my-cluster.hcp.eastus.azmk8s.io:443",
+			CertificateAuthorityData: []byte("ca"),
+		}
+		config.AuthInfos["clusterUser_my-rg_my-cluster"] = &clientcmdapi.AuthInfo{Token: token}
+		config.Contexts["my-cluster"] = &clientcmdapi.Context{Cluster: "my-cluster", AuthInfo: "clusterUser_my-rg_my-cluster"}
+		config.CurrentContext = "my-cluster"
+		data, err := clientcmd.Write(*config)
+		g.Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+	getKubeconfig := func(kubeclient client.Client, name string) *clientcmdapi.Config {
+		kubeconfigSecret := &corev1.Secret{}
+		g.Expect(kubeclient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, kubeconfigSecret)).To(Succeed())
+		config, err := clientcmd.Load(kubeconfigSecret.Data[secret.KubeconfigDataName])
+		g.Expect(err).NotTo(HaveOccurred())
+		return config
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+	scopeMock.EXPECT().MakeEmptyKubeConfigSecret().Return(corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-kubeconfig", Namespace: "default"},
+	}).AnyTimes()
+	scopeMock.EXPECT().MakeClusterCA().Return(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ca", Namespace: "default"},
+	}).AnyTimes()
+	scopeMock.EXPECT().StoreClusterInfo(gomockinternal.AContext(), []byte("ca")).Return(nil).AnyTimes()
+	scopeMock.EXPECT().IsAADEnabled().Return(true).AnyTimes()
+	scopeMock.EXPECT().TenantID().Return("my-tenant").AnyTimes()
+	scopeMock.EXPECT().CloudEnvironment().Return("AzurePublicCloud").AnyTimes()
+	scopeMock.EXPECT().GetUserKubeconfigData().Return(newKubeconfig("")).AnyTimes()
+
+	s := &azureManagedControlPlaneService{
+		kubeclient: kubeclient,
+		scope:      scopeMock,
+	}
+
+	// While local accounts are enabled, the kubeconfig has the admin credentials.
+	scopeMock.EXPECT().GetAdminKubeconfigData().Return(newKubeconfig("admin-token"))
+	g.Expect(s.reconcileKubeconfig(context.TODO())).To(Succeed())
+	g.Expect(getKubeconfig(kubeclient, "my-cluster-kubeconfig").AuthInfos["clusterUser_my-rg_my-cluster"].Token).To(Equal("admin-token"))
+
+	userAuthInfo := getKubeconfig(kubeclient, "my-cluster-kubeconfig-user").AuthInfos["clusterUser_my-rg_my-cluster"]
+	g.Expect(userAuthInfo.Token).To(BeEmpty())
+	g.Expect(userAuthInfo.Exec).NotTo(BeNil())
+	g.Expect(userAuthInfo.Exec.Command).To(Equal("kubelogin"))
+	g.Expect(userAuthInfo.Exec.Args).To(Equal([]string{
+		"get-token",
+		"--login", "devicecode",
+		"--server-id", aksAADServerID,
+		"--client-id", aksAADClientID,
+		"--tenant-id", "my-tenant",
+		"--environment", "AzurePublicCloud",
+	}))
+
+	// Once local accounts are disabled, the kubeconfig is rewritten with an AAD token for the remote cluster tracker,
+	// and the user kubeconfig keeps using kubelogin.
+	scopeMock.EXPECT().GetAdminKubeconfigData().Return(newKubeconfig("aad-token"))
+	g.Expect(s.reconcileKubeconfig(context.TODO())).To(Succeed())
+	g.Expect(getKubeconfig(kubeclient, "my-cluster-kubeconfig").AuthInfos["clusterUser_my-rg_my-cluster"].Token).To(Equal("aad-token"))
+	g.Expect(getKubeconfig(kubeclient, "my-cluster-kubeconfig-user").AuthInfos["clusterUser_my-rg_my-cluster"].Exec.Command).To(Equal("kubelogin"))
+}
+
+func TestAzureManagedControlPlaneServiceCheckNodeResourceGroup(t *testing.T) {
+	const nodeResourceGroup = "MC_rg_cluster_eastus"
+	diskID := "/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/disks/data"
+
+	cases := map[string]struct {
+		annotations       map[string]string
+		resources         []*armresources.GenericResourceExpanded
+		expectList        bool
+		expectedUnmanaged []string
+		expectedCondition *clusterv1.Condition
+	}{
+		"node resource group not protected": {
+			annotations: nil,
+		},
+		"empty node resource group": {
+			annotations: map[string]string{infrav1.ProtectNodeResourceGroupAnnotation: "true"},
+			resources: []*armresources.GenericResourceExpanded{
+				{
+					ID:   ptr.To("/subscriptions/sub/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-pool0-vmss"),
+					Tags: map[string]*string{"aks-managed-poolName": ptr.To("pool0")},
+				},
+			},
+			expectList: true,
+			expectedCondition: &clusterv1.Condition{
+				Type:   infrav1.NodeResourceGroupEmptyCondition,
+				Status: corev1.ConditionTrue,
+			},
+		},
+		"non-empty node resource group": {
+			annotations: map[string]string{infrav1.ProtectNodeResourceGroupAnnotation: "true"},
+			resources: []*armresources.GenericResourceExpanded{
+				{ID: ptr.To(diskID)},
+			},
+			expectList:        true,
+			expectedUnmanaged: []string{diskID},
+			expectedCondition: &clusterv1.Condition{
+				Type:     infrav1.NodeResourceGroupEmptyCondition,
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   infrav1.NodeResourceGroupNotEmptyReason,
+				Message:  "node resource group MC_rg_cluster_eastus contains resources not created by AKS: " + diskID,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			lister := mock_managedclusters.NewMockNodeResourceGroupLister(mockCtrl)
+			if tc.expectList {
+				lister.EXPECT().ListByResourceGroup(gomockinternal.AContext(), nodeResourceGroup).Return(tc.resources, nil)
+			}
+
+			controlPlane := &infrav1.AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Annotations: tc.annotations,
+				},
+				Status: infrav1.AzureManagedControlPlaneStatus{
+					NodeResourceGroupName: nodeResourceGroup,
+				},
+			}
+			s := &azureManagedControlPlaneService{
+				nodeResourceGroupLister: lister,
+			}
+
+			unmanaged, err := s.checkNodeResourceGroup(context.Background(), controlPlane)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(unmanaged).To(Equal(tc.expectedUnmanaged))
+			actual := conditions.Get(controlPlane, infrav1.NodeResourceGroupEmptyCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(actual).To(BeNil())
+				return
+			}
+			g.Expect(actual).NotTo(BeNil())
+			actual.LastTransitionTime = metav1.Time{}
+			g.Expect(*actual).To(Equal(*tc.expectedCondition))
+		})
+	}
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -44,6 +45,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// agentPoolDrainBlockedTimeout is how long an agent pool deletion can run before the controller reports that the drain
+// of its nodes is likely blocked by PodDisruptionBudgets.
+const agentPoolDrainBlockedTimeout = 30 * time.Minute
+
 // AzureManagedMachinePoolReconciler reconciles an AzureManagedMachinePool object.
 type AzureManagedMachinePoolReconciler struct {
 	client.Client
@@ -51,6 +56,7 @@ type AzureManagedMachinePoolReconciler struct {
 	Timeouts                             reconciler.Timeouts
 	WatchFilterValue                     string
 	createAzureManagedMachinePoolService azureManagedMachinePoolServiceCreator
+	controlPlaneScopes                   *controlPlaneScopeCache
 }
 
 type azureManagedMachinePoolServiceCreator func(managedMachinePoolScope *scope.ManagedMachinePoolScope, apiCallTimeout time.Duration) (*azureManagedMachinePoolService, error)
@@ -58,10 +64,11 @@ type azureManagedMachinePoolServiceCreator func(managedMachinePoolScope *scope.M
 // NewAzureManagedMachinePoolReconciler returns a new AzureManagedMachinePoolReconciler instance.
 func NewAzureManagedMachinePoolReconciler(client client.Client, recorder record.EventRecorder, timeouts reconciler.Timeouts, watchFilterValue string) *AzureManagedMachinePoolReconciler {
 	ampr := &AzureManagedMachinePoolReconciler{
-		Client:           client,
-		Recorder:         recorder,
-		Timeouts:         timeouts,
-		WatchFilterValue: watchFilterValue,
+		Client:             client,
+		Recorder:           recorder,
+		Timeouts:           timeouts,
+		WatchFilterValue:   watchFilterValue,
+		controlPlaneScopes: newControlPlaneScopeCache(),
 	}
 
 	ampr.createAzureManagedMachinePoolService = newAzureManagedMachinePoolService
@@ -177,15 +184,20 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 
 	log = log.WithValues("ownerCluster", ownerCluster.Name)
 
-	// Fetch the corresponding control plane which has all the interesting data.
-	controlPlane := &infrav1.AzureManagedControlPlane{}
+	// Fetch the corresponding control plane which has all the interesting data. The control plane and its scope are
+	// shared with the other pools of the cluster reconciling at the same time.
 	controlPlaneName := client.ObjectKey{
 		Namespace: ownerCluster.Spec.ControlPlaneRef.Namespace,
 		Name:      ownerCluster.Spec.ControlPlaneRef.Name,
 	}
-	if err := ammpr.Client.Get(ctx, controlPlaneName, controlPlane); err != nil {
+	shared, release, err := ammpr.controlPlaneScopes.acquire(ctx, controlPlaneName, func(ctx context.Context) (sharedControlPlaneScope, error) {
+		return ammpr.loadControlPlaneScope(ctx, controlPlaneName, ownerCluster)
+	})
+	if err != nil {
 		return reconcile.Result{}, err
 	}
+	defer release()
+	controlPlane := shared.ControlPlane
 
 	// Upon first create of an AKS service, the node pools are provided to the CreateOrUpdate call. After the initial
 	// create of the control plane and node pools, the control plane will transition to initialized. After the control
@@ -194,17 +206,7 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 		log.Info("AzureManagedControlPlane is not initialized")
 		return reconcile.Result{}, nil
 	}
-
-	// create the managed control plane scope
-	managedControlPlaneScope, err := scope.NewManagedControlPlaneScope(ctx, scope.ManagedControlPlaneScopeParams{
-		Client:       ammpr.Client,
-		ControlPlane: controlPlane,
-		Cluster:      ownerCluster,
-		Timeouts:     ammpr.Timeouts,
-	})
-	if err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "failed to create ManagedControlPlane scope")
-	}
+	managedControlPlaneScope := shared.Scope
 
 	// Create the scope.
 	mcpScope, err := scope.NewManagedMachinePoolScope(ctx, scope.ManagedMachinePoolScopeParams{
@@ -216,6 +218,7 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 			InfraMachinePool: infraPool,
 		},
 		ManagedControlPlaneScope: managedControlPlaneScope,
+		Recorder:                 ammpr.Recorder,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create ManagedMachinePool scope")
@@ -246,6 +249,28 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 	return ammpr.reconcileNormal(ctx, mcpScope)
 }
 
+// loadControlPlaneScope gets the control plane with the given key and creates its scope once it is initialized.
+func (ammpr *AzureManagedMachinePoolReconciler) loadControlPlaneScope(ctx context.Context, controlPlaneName client.ObjectKey, cluster *clusterv1.Cluster) (sharedControlPlaneScope, error) {
+	controlPlane := &infrav1.AzureManagedControlPlane{}
+	if err := ammpr.Client.Get(ctx, controlPlaneName, controlPlane); err != nil {
+		return sharedControlPlaneScope{}, err
+	}
+	if !controlPlane.Status.Initialized {
+		return sharedControlPlaneScope{ControlPlane: controlPlane}, nil
+	}
+
+	managedControlPlaneScope, err := scope.NewManagedControlPlaneScope(ctx, scope.ManagedControlPlaneScopeParams{
+		Client:       ammpr.Client,
+		ControlPlane: controlPlane,
+		Cluster:      cluster,
+		Timeouts:     ammpr.Timeouts,
+	})
+	if err != nil {
+		return sharedControlPlaneScope{}, errors.Wrap(err, "failed to create ManagedControlPlane scope")
+	}
+	return sharedControlPlaneScope{ControlPlane: controlPlane, Scope: managedControlPlaneScope}, nil
+}
+
 func (ammpr *AzureManagedMachinePoolReconciler) reconcileNormal(ctx context.Context, scope *scope.ManagedMachinePoolScope) (reconcile.Result, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureManagedMachinePoolReconciler.reconcileNormal")
 	defer done()
@@ -326,6 +351,17 @@ func (ammpr *AzureManagedMachinePoolReconciler) reconcileDelete(ctx context.Cont
 	log.Info("Reconciling AzureManagedMachinePool delete")
 
 	if !scope.Cluster.DeletionTimestamp.IsZero() {
+		if scope.ControlPlane.Spec.DeletionPolicy == infrav1.DeletionPolicyRetain {
+			// The AKS cluster is retained, so make sure its agent pool isn't deleted along with the
+			// AzureManagedMachinePool either.
+			svc, err := ammpr.createAzureManagedMachinePoolService(scope, ammpr.Timeouts.DefaultedAzureServiceReconcileTimeout())
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to create an AzureManageMachinePoolService")
+			}
+			if err := svc.Retain(ctx); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "error retaining AzureManagedMachinePool %s/%s", scope.InfraMachinePool.Namespace, scope.InfraMachinePool.Name)
+			}
+		}
 		// Cluster was deleted, skip machine pool deletion and let AKS delete the whole cluster.
 		// So, remove the finalizer.
 		controllerutil.RemoveFinalizer(scope.InfraMachinePool, infrav1.ClusterFinalizer)
@@ -341,6 +377,9 @@ func (ammpr *AzureManagedMachinePoolReconciler) reconcileDelete(ctx context.Cont
 			if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
 				if azure.IsOperationNotDoneError(reconcileError) {
 					log.V(2).Info(fmt.Sprintf("AzureManagedMachinePool delete not done: %s", reconcileError.Error()))
+					if time.Since(scope.InfraMachinePool.DeletionTimestamp.Time) > agentPoolDrainBlockedTimeout {
+						markDrainBlocked(ctx, scope)
+					}
 				} else {
 					log.V(2).Info("transient failure to delete AzureManagedMachinePool, retrying")
 				}
@@ -358,3 +397,19 @@ func (ammpr *AzureManagedMachinePoolReconciler) reconcileDelete(ctx context.Cont
 
 	return reconcile.Result{}, nil
 }
+
+// markDrainBlocked explains on the AgentPoolsReady condition that a long running agent pool deletion is most likely
+// waiting on AKS to drain nodes whose pods PodDisruptionBudgets don't allow to be evicted.
+func markDrainBlocked(ctx context.Context, scope *scope.ManagedMachinePoolScope) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.markDrainBlocked")
+	defer done()
+
+	msg := fmt.Sprintf("agent pool deletion has been running for more than %s, draining its nodes may be blocked by PodDisruptionBudgets", agentPoolDrainBlockedTimeout)
+	pdbs, err := scope.PodDisruptionBudgetsBlockingDrain(ctx)
+	if err != nil {
+		log.V(4).Info("unable to discover the PodDisruptionBudgets blocking the drain", "error", err.Error())
+	} else if len(pdbs) > 0 {
+		msg = fmt.Sprintf("%s %s", msg, strings.Join(pdbs, ", "))
+	}
+	conditions.MarkFalse(scope.InfraMachinePool, infrav1.AgentPoolsReadyCondition, infrav1.PodDisruptionBudgetBlockingDrainReason, clusterv1.ConditionSeverityWarning, msg)
+}
@@ -41,6 +41,7 @@ import (
 	reconcilerutils "sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -49,12 +50,14 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 	type pausingReconciler struct {
 		*mock_azure.MockReconciler
 		*mock_azure.MockPauser
+		*mock_azure.MockRetainer
 	}
 
 	cases := []struct {
-		name   string
-		Setup  func(cb *fake.ClientBuilder, reconciler pausingReconciler, agentpools *mock_agentpools.MockAgentPoolScopeMockRecorder, nodelister *MockNodeListerMockRecorder)
-		Verify func(g *WithT, result ctrl.Result, err error)
+		name       string
+		Setup      func(cb *fake.ClientBuilder, reconciler pausingReconciler, agentpools *mock_agentpools.MockAgentPoolScopeMockRecorder, nodelister *MockNodeListerMockRecorder)
+		Verify     func(g *WithT, result ctrl.Result, err error)
+		VerifyPool func(g *WithT, ammp *infrav1.AzureManagedMachinePool)
 	}{
 		{
 			name: "Reconcile succeed",
@@ -110,6 +113,42 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			name: "Reconcile delete with the cluster",
+			Setup: func(cb *fake.ClientBuilder, _ pausingReconciler, _ *mock_agentpools.MockAgentPoolScopeMockRecorder, _ *MockNodeListerMockRecorder) {
+				cluster, azManagedCluster, azManagedControlPlane, ammp, mp := newReadyAzureManagedMachinePoolCluster()
+				cluster.Finalizers = []string{"test"}
+				cluster.DeletionTimestamp = &metav1.Time{
+					Time: time.Now(),
+				}
+				ammp.DeletionTimestamp = &metav1.Time{
+					Time: time.Now(),
+				}
+				cb.WithObjects(cluster, azManagedCluster, azManagedControlPlane, ammp, mp)
+			},
+			Verify: func(g *WithT, result ctrl.Result, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "Reconcile delete with a retained cluster",
+			Setup: func(cb *fake.ClientBuilder, reconciler pausingReconciler, _ *mock_agentpools.MockAgentPoolScopeMockRecorder, _ *MockNodeListerMockRecorder) {
+				cluster, azManagedCluster, azManagedControlPlane, ammp, mp := newReadyAzureManagedMachinePoolCluster()
+				azManagedControlPlane.Spec.DeletionPolicy = infrav1.DeletionPolicyRetain
+				reconciler.MockRetainer.EXPECT().Retain(gomock2.AContext()).Return(nil, nil)
+				cluster.Finalizers = []string{"test"}
+				cluster.DeletionTimestamp = &metav1.Time{
+					Time: time.Now(),
+				}
+				ammp.DeletionTimestamp = &metav1.Time{
+					Time: time.Now(),
+				}
+				cb.WithObjects(cluster, azManagedCluster, azManagedControlPlane, ammp, mp)
+			},
+			Verify: func(g *WithT, result ctrl.Result, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 		{
 			name: "Reconcile delete transient error",
 			Setup: func(cb *fake.ClientBuilder, reconciler pausingReconciler, agentpools *mock_agentpools.MockAgentPoolScopeMockRecorder, _ *MockNodeListerMockRecorder) {
@@ -126,6 +165,46 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 				g.Expect(result.RequeueAfter).To(Equal(76 * time.Second))
 			},
 		},
+		{
+			name: "Reconcile delete not done within the drain timeout",
+			Setup: func(cb *fake.ClientBuilder, reconciler pausingReconciler, agentpools *mock_agentpools.MockAgentPoolScopeMockRecorder, _ *MockNodeListerMockRecorder) {
+				cluster, azManagedCluster, azManagedControlPlane, ammp, mp := newReadyAzureManagedMachinePoolCluster()
+				reconciler.MockReconciler.EXPECT().Delete(gomock2.AContext()).Return(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{}), 15*time.Second))
+				agentpools.Name()
+				ammp.DeletionTimestamp = &metav1.Time{
+					Time: time.Now().Add(-5 * time.Minute),
+				}
+				cb.WithObjects(cluster, azManagedCluster, azManagedControlPlane, ammp, mp)
+			},
+			Verify: func(g *WithT, result ctrl.Result, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result.RequeueAfter).To(Equal(15 * time.Second))
+			},
+			VerifyPool: func(g *WithT, ammp *infrav1.AzureManagedMachinePool) {
+				g.Expect(conditions.GetReason(ammp, infrav1.AgentPoolsReadyCondition)).NotTo(Equal(infrav1.PodDisruptionBudgetBlockingDrainReason))
+			},
+		},
+		{
+			name: "Reconcile delete not done past the drain timeout",
+			Setup: func(cb *fake.ClientBuilder, reconciler pausingReconciler, agentpools *mock_agentpools.MockAgentPoolScopeMockRecorder, _ *MockNodeListerMockRecorder) {
+				cluster, azManagedCluster, azManagedControlPlane, ammp, mp := newReadyAzureManagedMachinePoolCluster()
+				reconciler.MockReconciler.EXPECT().Delete(gomock2.AContext()).Return(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{}), 15*time.Second))
+				agentpools.Name()
+				ammp.DeletionTimestamp = &metav1.Time{
+					Time: time.Now().Add(-time.Hour),
+				}
+				cb.WithObjects(cluster, azManagedCluster, azManagedControlPlane, ammp, mp)
+			},
+			Verify: func(g *WithT, result ctrl.Result, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result.RequeueAfter).To(Equal(15 * time.Second))
+			},
+			VerifyPool: func(g *WithT, ammp *infrav1.AzureManagedMachinePool) {
+				g.Expect(conditions.IsFalse(ammp, infrav1.AgentPoolsReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ammp, infrav1.AgentPoolsReadyCondition)).To(Equal(infrav1.PodDisruptionBudgetBlockingDrainReason))
+				g.Expect(conditions.GetSeverity(ammp, infrav1.AgentPoolsReadyCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityWarning)))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -137,6 +216,7 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 				reconciler = pausingReconciler{
 					MockReconciler: mock_azure.NewMockReconciler(mockCtrl),
 					MockPauser:     mock_azure.NewMockPauser(mockCtrl),
+					MockRetainer:   mock_azure.NewMockRetainer(mockCtrl),
 				}
 				agentpools   = mock_agentpools.NewMockAgentPoolScope(mockCtrl)
 				nodelister   = NewMockNodeLister(mockCtrl)
@@ -176,7 +256,8 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			c.Setup(cb, reconciler, agentpools.EXPECT(), nodelister.EXPECT())
-			controller := NewAzureManagedMachinePoolReconciler(cb.Build(), nil, reconcilerutils.Timeouts{}, "foo")
+			fakeClient := cb.Build()
+			controller := NewAzureManagedMachinePoolReconciler(fakeClient, nil, reconcilerutils.Timeouts{}, "foo")
 			controller.createAzureManagedMachinePoolService = func(_ *scope.ManagedMachinePoolScope, _ time.Duration) (*azureManagedMachinePoolService, error) {
 				return &azureManagedMachinePoolService{
 					scope:         agentpools,
@@ -191,6 +272,12 @@ func TestAzureManagedMachinePoolReconcile(t *testing.T) {
 				},
 			})
 			c.Verify(g, res, err)
+
+			if c.VerifyPool != nil {
+				ammp := &infrav1.AzureManagedMachinePool{}
+				g.Expect(fakeClient.Get(context.TODO(), types.NamespacedName{Name: "foo-ammp", Namespace: "foobar"}, ammp)).To(Succeed())
+				c.VerifyPool(g, ammp)
+			}
 		})
 	}
 }
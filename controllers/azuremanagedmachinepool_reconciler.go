@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -51,8 +52,31 @@ type (
 		ListInstances(context.Context, string, string) ([]armcompute.VirtualMachineScaleSetVM, error)
 		List(context.Context, string) ([]armcompute.VirtualMachineScaleSet, error)
 	}
+
+	// cachedNodeLister is a NodeLister which lists the scale sets of a resource group only once, so that the agent pool
+	// service and the machine pool service share the scale sets listed during a reconcile.
+	cachedNodeLister struct {
+		NodeLister
+		scaleSets map[string][]armcompute.VirtualMachineScaleSet
+	}
 )
 
+// List returns the scale sets of a resource group, listing them only the first time.
+func (l *cachedNodeLister) List(ctx context.Context, resourceGroupName string) ([]armcompute.VirtualMachineScaleSet, error) {
+	if scaleSets, ok := l.scaleSets[resourceGroupName]; ok {
+		return scaleSets, nil
+	}
+	scaleSets, err := l.NodeLister.List(ctx, resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+	if l.scaleSets == nil {
+		l.scaleSets = map[string][]armcompute.VirtualMachineScaleSet{}
+	}
+	l.scaleSets[resourceGroupName] = scaleSets
+	return scaleSets, nil
+}
+
 // NewAgentPoolVMSSNotFoundError creates a new AgentPoolVMSSNotFoundError.
 func NewAgentPoolVMSSNotFoundError(nodeResourceGroup, poolName string) *AgentPoolVMSSNotFoundError {
 	return &AgentPoolVMSSNotFoundError{
@@ -83,10 +107,16 @@ func newAzureManagedMachinePoolService(scope *scope.ManagedMachinePoolScope, api
 	if err != nil {
 		return nil, err
 	}
+	tagsClient, err := tags.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	// The services are created for every reconcile, so the scale sets are listed once per reconcile.
+	scaleSetsLister := &cachedNodeLister{NodeLister: scaleSetsClient}
 	return &azureManagedMachinePoolService{
 		scope:         scope,
-		agentPoolsSvc: agentpools.New(scope),
-		scaleSetsSvc:  scaleSetsClient,
+		agentPoolsSvc: agentpools.New(scope, scaleSetsLister, tagsClient),
+		scaleSetsSvc:  scaleSetsLister,
 	}, nil
 }
 
@@ -124,20 +154,7 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to list vmss in resource group %s", nodeResourceGroup)
 	}
 
-	var match *armcompute.VirtualMachineScaleSet
-	for _, ss := range vmss {
-		ss := ss
-		if ss.Tags["poolName"] != nil && *ss.Tags["poolName"] == agentPoolName {
-			match = &ss
-			break
-		}
-
-		if ss.Tags["aks-managed-poolName"] != nil && *ss.Tags["aks-managed-poolName"] == agentPoolName {
-			match = &ss
-			break
-		}
-	}
-
+	match := agentpools.FindScaleSet(vmss, agentPoolName)
 	if match == nil {
 		return azure.WithTransientError(NewAgentPoolVMSSNotFoundError(nodeResourceGroup, agentPoolName), 20*time.Second)
 	}
@@ -181,6 +198,23 @@ func (s *azureManagedMachinePoolService) Pause(ctx context.Context) error {
 	return nil
 }
 
+// Retain detaches the components making up the machine pool so that they're kept in Azure when the machine pool
+// is deleted.
+func (s *azureManagedMachinePoolService) Retain(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedMachinePoolService.Retain")
+	defer done()
+
+	retainer, ok := s.agentPoolsSvc.(azure.Retainer)
+	if !ok {
+		return nil
+	}
+	if _, err := retainer.Retain(ctx); err != nil {
+		return errors.Wrapf(err, "failed to retain machine pool %s", s.scope.Name())
+	}
+
+	return nil
+}
+
 // Delete reconciles all the services in a predetermined order.
 func (s *azureManagedMachinePoolService) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedMachinePoolService.Delete")
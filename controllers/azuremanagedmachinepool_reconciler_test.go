@@ -20,10 +20,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
@@ -136,3 +138,30 @@ func TestAzureManagedMachinePoolServicePause(t *testing.T) {
 		})
 	}
 }
+
+type countingNodeLister struct {
+	NodeLister
+	lists int
+}
+
+func (l *countingNodeLister) List(_ context.Context, resourceGroupName string) ([]armcompute.VirtualMachineScaleSet, error) {
+	l.lists++
+	return []armcompute.VirtualMachineScaleSet{{Name: ptr.To(resourceGroupName + "-vmss")}}, nil
+}
+
+func TestCachedNodeListerListsOnce(t *testing.T) {
+	g := gomega.NewWithT(t)
+	counting := &countingNodeLister{}
+	lister := &cachedNodeLister{NodeLister: counting}
+
+	for i := 0; i < 2; i++ {
+		scaleSets, err := lister.List(context.Background(), "node-rg")
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(scaleSets).To(gomega.HaveLen(1))
+	}
+	g.Expect(counting.lists).To(gomega.Equal(1))
+
+	_, err := lister.List(context.Background(), "other-rg")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(counting.lists).To(gomega.Equal(2))
+}
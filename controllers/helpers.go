@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -666,6 +668,16 @@ func EnsureClusterIdentity(ctx context.Context, c client.Client, object conditio
 		return errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current cluster namespace")
 	}
 
+	// The fallback identity is only followed one level, so the fallback identity's own fallback isn't checked.
+	fallbackIdentity, err := GetClusterIdentityFromRef(ctx, c, identity.Namespace, identity.Spec.FallbackIdentityRef)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if fallbackIdentity != nil && !scope.IsClusterNamespaceAllowed(ctx, c, fallbackIdentity.Spec.AllowedNamespaces, namespace) {
+		conditions.MarkFalse(object, infrav1.NetworkInfrastructureReadyCondition, infrav1.NamespaceNotAllowedByIdentity, clusterv1.ConditionSeverityError, "")
+		return errors.New("fallback AzureClusterIdentity list of allowed namespaces doesn't include current cluster namespace")
+	}
+
 	// Remove deprecated finalizer if it exists, Register the finalizer immediately to avoid orphaning Azure resources on delete.
 	needsPatch := controllerutil.RemoveFinalizer(identity, deprecatedClusterIdentityFinalizer(finalizerPrefix, namespace, name))
 	needsPatch = controllerutil.AddFinalizer(identity, clusterIdentityFinalizer(finalizerPrefix, namespace, name)) || needsPatch
@@ -681,6 +693,26 @@ func EnsureClusterIdentity(ctx context.Context, c client.Client, object conditio
 	return nil
 }
 
+// fallbackIdentityGetter reports whether the fallback identity of the cluster's AzureClusterIdentity was used.
+type fallbackIdentityGetter interface {
+	FallbackIdentity() (name string, fallback bool, ok bool)
+}
+
+// reconcileIdentityFallback reports the use of the fallback identity of the cluster's AzureClusterIdentity with an
+// event and the PrimaryIdentityAuthenticated condition, which is removed once the primary identity authenticates again.
+func reconcileIdentityFallback(recorder record.EventRecorder, object conditions.Setter, identities fallbackIdentityGetter) {
+	name, fallback, ok := identities.FallbackIdentity()
+	if !ok {
+		return
+	}
+	if !fallback {
+		conditions.Delete(object, infrav1.PrimaryIdentityAuthenticatedCondition)
+		return
+	}
+	recorder.Eventf(object, corev1.EventTypeWarning, infrav1.FallbackIdentityInUseReason, "AzureClusterIdentity failed to authenticate, authenticated with fallback AzureClusterIdentity %s", name)
+	conditions.MarkFalse(object, infrav1.PrimaryIdentityAuthenticatedCondition, infrav1.FallbackIdentityInUseReason, clusterv1.ConditionSeverityWarning, "authenticated with fallback AzureClusterIdentity %s", name)
+}
+
 // RemoveClusterIdentityFinalizer removes the finalizer on an AzureClusterIdentity.
 func RemoveClusterIdentityFinalizer(ctx context.Context, c client.Client, object client.Object, identityRef *corev1.ObjectReference, finalizerPrefix string) error {
 	name := object.GetName()
@@ -935,6 +967,97 @@ func AzureManagedControlPlaneToAzureManagedClusterMapper(ctx context.Context, c
 	}, nil
 }
 
+// AzureMachineTemplateToAzureClusterMapper creates a mapping handler to transform AzureMachineTemplates into
+// AzureClusters. The transform requires the AzureMachineTemplate to be owned by a Cluster, as the templates of
+// control planes are, then from the Cluster, collect the infrastructure reference.
+func AzureMachineTemplateToAzureClusterMapper(ctx context.Context, c client.Client, log logr.Logger) (handler.MapFunc, error) {
+	return func(ctx context.Context, o client.Object) []ctrl.Request {
+		ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultMappingTimeout)
+		defer cancel()
+
+		azureMachineTemplate, ok := o.(*infrav1.AzureMachineTemplate)
+		if !ok {
+			log.Error(errors.Errorf("expected an AzureMachineTemplate, got %T instead", o), "failed to map AzureMachineTemplate")
+			return nil
+		}
+
+		log := log.WithValues("AzureMachineTemplate", azureMachineTemplate.Name, "Namespace", azureMachineTemplate.Namespace)
+
+		// Don't handle deleted AzureMachineTemplates
+		if !azureMachineTemplate.ObjectMeta.DeletionTimestamp.IsZero() {
+			log.V(4).Info("AzureMachineTemplate has a deletion timestamp, skipping mapping.")
+			return nil
+		}
+
+		cluster, err := util.GetOwnerCluster(ctx, c, azureMachineTemplate.ObjectMeta)
+		if err != nil {
+			log.Error(err, "failed to get the owning cluster")
+			return nil
+		}
+
+		if cluster == nil {
+			return nil
+		}
+
+		ref := cluster.Spec.InfrastructureRef
+		if ref == nil || ref.Name == "" || ref.Kind != infrav1.AzureClusterKind {
+			return nil
+		}
+
+		return []ctrl.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: ref.Namespace,
+					Name:      ref.Name,
+				},
+			},
+		}
+	}, nil
+}
+
+// dedupeResourceIDs removes duplicate Azure resource IDs, which are case-insensitive, preserving their order.
+func dedupeResourceIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	var deduped []string
+	for _, id := range ids {
+		key := strings.ToLower(id)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, id)
+	}
+	return deduped
+}
+
+// retainedResourcesMessage returns the message of the event recording the Azure resources retained on deletion.
+func retainedResourcesMessage(ids []string) string {
+	if len(ids) == 0 {
+		return "Deletion policy is Retain, no Azure resources were deleted"
+	}
+	return fmt.Sprintf("Deletion policy is Retain, retained Azure resources: %s", strings.Join(ids, ", "))
+}
+
+// isAzureManagedControlPlaneRef returns whether a Cluster's control plane reference refers to an
+// AzureManagedControlPlane. References are matched on group and kind so that clusters created against an older
+// version of the API keep being reconciled. It returns an error when the reference has the kind of an
+// AzureManagedControlPlane but an apiVersion outside of the infrastructure API group.
+func isAzureManagedControlPlaneRef(ref *corev1.ObjectReference) (bool, error) {
+	if ref == nil || ref.Kind != infrav1.AzureManagedControlPlaneKind {
+		return false, nil
+	}
+	// References without an apiVersion have always been matched on kind alone.
+	if ref.APIVersion == "" {
+		return true, nil
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil || gv.Group != infrav1.GroupVersion.Group {
+		return false, errors.Errorf("control plane reference %s/%s has kind %s but unrecognized apiVersion %q, expected group %s",
+			ref.Namespace, ref.Name, ref.Kind, ref.APIVersion, infrav1.GroupVersion.Group)
+	}
+	return true, nil
+}
+
 // MachinePoolToAzureManagedControlPlaneMapFunc returns a handler.MapFunc that watches for
 // MachinePool events and returns reconciliation requests for a control plane object.
 func MachinePoolToAzureManagedControlPlaneMapFunc(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, log logr.Logger) handler.MapFunc {
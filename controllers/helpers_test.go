@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -43,6 +44,7 @@ import (
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -1205,6 +1207,58 @@ func TestAzureManagedControlPlaneToAzureManagedClusterMapper(t *testing.T) {
 	}))
 }
 
+func TestAzureMachineTemplateToAzureClusterMapper(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	cluster := newCluster("my-cluster")
+	cluster.Spec.InfrastructureRef = &corev1.ObjectReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       infrav1.AzureClusterKind,
+		Name:       "az-" + cluster.Name,
+		Namespace:  cluster.Namespace,
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster).Build()
+
+	sink := mock_log.NewMockLogSink(gomock.NewController(t))
+	sink.EXPECT().Init(logr.RuntimeInfo{CallDepth: 1})
+	sink.EXPECT().WithValues("AzureMachineTemplate", gomock.Any(), "Namespace", cluster.Namespace).AnyTimes()
+
+	mapper, err := AzureMachineTemplateToAzureClusterMapper(context.Background(), fakeClient, logr.New(sink))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	requests := mapper(context.TODO(), &infrav1.AzureMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "control-plane",
+			Namespace: cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					Name:       cluster.Name,
+					Kind:       "Cluster",
+					APIVersion: clusterv1.GroupVersion.String(),
+				},
+			},
+		},
+	})
+	g.Expect(requests).To(Equal([]reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      "az-" + cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+		},
+	}))
+
+	// Templates not owned by a cluster are not mapped.
+	requests = mapper(context.TODO(), &infrav1.AzureMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker",
+			Namespace: cluster.Namespace,
+		},
+	})
+	g.Expect(requests).To(BeEmpty())
+}
+
 func newAzureManagedControlPlane(cpName string) *infrav1.AzureManagedControlPlane {
 	return &infrav1.AzureManagedControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1647,3 +1701,77 @@ func TestRemoveBlockMoveAnnotation(t *testing.T) {
 		})
 	}
 }
+
+type fakeFallbackIdentityGetter struct {
+	name     string
+	fallback bool
+	ok       bool
+}
+
+func (f fakeFallbackIdentityGetter) FallbackIdentity() (string, bool, bool) {
+	return f.name, f.fallback, f.ok
+}
+
+func TestReconcileIdentityFallback(t *testing.T) {
+	degraded := clusterv1.Conditions{{
+		Type:     infrav1.PrimaryIdentityAuthenticatedCondition,
+		Status:   corev1.ConditionFalse,
+		Severity: clusterv1.ConditionSeverityWarning,
+		Reason:   infrav1.FallbackIdentityInUseReason,
+	}}
+	tests := []struct {
+		name          string
+		conditions    clusterv1.Conditions
+		identities    fakeFallbackIdentityGetter
+		wantCondition bool
+		wantEvent     bool
+	}{
+		{
+			name:       "no fallback identity",
+			identities: fakeFallbackIdentityGetter{},
+		},
+		{
+			name:       "primary identity authenticated",
+			identities: fakeFallbackIdentityGetter{name: "fallback", ok: true},
+		},
+		{
+			name:          "fallback identity authenticated",
+			identities:    fakeFallbackIdentityGetter{name: "fallback", fallback: true, ok: true},
+			wantCondition: true,
+			wantEvent:     true,
+		},
+		{
+			name:       "primary identity authenticates again",
+			conditions: degraded,
+			identities: fakeFallbackIdentityGetter{name: "fallback", ok: true},
+		},
+		{
+			name:          "no token requested keeps the condition",
+			conditions:    degraded,
+			identities:    fakeFallbackIdentityGetter{name: "fallback"},
+			wantCondition: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			azureCluster := &infrav1.AzureCluster{
+				Status: infrav1.AzureClusterStatus{Conditions: tc.conditions},
+			}
+			recorder := record.NewFakeRecorder(1)
+
+			reconcileIdentityFallback(recorder, azureCluster, tc.identities)
+
+			g.Expect(conditions.Has(azureCluster, infrav1.PrimaryIdentityAuthenticatedCondition)).To(Equal(tc.wantCondition))
+			if tc.wantCondition {
+				g.Expect(conditions.IsFalse(azureCluster, infrav1.PrimaryIdentityAuthenticatedCondition)).To(BeTrue())
+			}
+			if tc.wantEvent {
+				g.Expect(recorder.Events).To(HaveLen(1))
+			} else {
+				g.Expect(recorder.Events).To(BeEmpty())
+			}
+		})
+	}
+}
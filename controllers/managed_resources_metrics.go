@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var managedResources = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "capz_managed_resources",
+		Help: "Number of Azure resources recorded as created by CAPZ for each cluster, identified as namespace/name.",
+	},
+	[]string{"cluster"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(managedResources)
+}
+
+// updateManagedResourcesMetric exports the number of Azure resources recorded as created by CAPZ for a cluster. The
+// series of the cluster is removed once its infrastructure object no longer holds finalizer, i.e. it is deleted.
+func updateManagedResourcesMetric(obj client.Object, clusterName, finalizer string, resources *infrav1.ManagedResources) {
+	cluster := fmt.Sprintf("%s/%s", obj.GetNamespace(), clusterName)
+	if resources == nil || !controllerutil.ContainsFinalizer(obj, finalizer) {
+		managedResources.DeleteLabelValues(cluster)
+		return
+	}
+	managedResources.WithLabelValues(cluster).Set(float64(len(resources.IDs)))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestUpdateManagedResourcesMetric(t *testing.T) {
+	g := NewWithT(t)
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "metrics-cluster",
+			Finalizers: []string{infrav1.ClusterFinalizer},
+		},
+	}
+	resources := &infrav1.ManagedResources{IDs: []string{"id-1", "id-2"}}
+
+	updateManagedResourcesMetric(azureCluster, "metrics-cluster", infrav1.ClusterFinalizer, resources)
+	g.Expect(testutil.ToFloat64(managedResources.WithLabelValues("default/metrics-cluster"))).To(Equal(float64(2)))
+
+	resources.Remove("id-1")
+	updateManagedResourcesMetric(azureCluster, "metrics-cluster", infrav1.ClusterFinalizer, resources)
+	g.Expect(testutil.ToFloat64(managedResources.WithLabelValues("default/metrics-cluster"))).To(Equal(float64(1)))
+
+	azureCluster.Finalizers = nil
+	updateManagedResourcesMetric(azureCluster, "metrics-cluster", infrav1.ClusterFinalizer, resources)
+	g.Expect(testutil.CollectAndCount(managedResources)).To(BeZero())
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sharedControlPlaneScope is the AzureManagedControlPlane of a cluster along with its scope once it is initialized.
+//
+// The pools of the cluster reconciling at the same time use the scope concurrently, so they may only call the
+// ManagedControlPlaneScope methods which are safe for concurrent use:
+//   - the methods of azure.ManagedClusterScoper, which is how the ManagedMachinePoolScope uses it. They only read the
+//     AzureManagedControlPlane and the Cluster, e.g. ResourceGroup, NodeResourceGroup, Location and AdditionalTags,
+//     which returns a copy of the tags, or return the credentials and timeouts set up when the scope was created.
+//   - IsVnetManaged, which guards its cached result with a mutex.
+//
+// The methods updating the AzureManagedControlPlane, e.g. its status, conditions and recorded resources, as well as
+// PatchObject, aren't safe for concurrent use and must not be called by the pools.
+type sharedControlPlaneScope struct {
+	ControlPlane *infrav1.AzureManagedControlPlane
+	// Scope is nil until the control plane is initialized.
+	Scope *scope.ManagedControlPlaneScope
+}
+
+// controlPlaneScopeCache shares the control plane scope of a cluster between the AzureManagedMachinePools of the
+// cluster reconciling at the same time, so that a batch of pools reads the AzureManagedControlPlane and resolves its
+// credentials once rather than once per pool. A scope is only shared while at least one reconcile holds it: it is
+// dropped when the last one releases it, so the next batch loads the control plane again.
+// The shared scope must be treated as read-only by the pools.
+type controlPlaneScopeCache struct {
+	mu      sync.Mutex
+	entries map[client.ObjectKey]*controlPlaneScopeEntry
+}
+
+type controlPlaneScopeEntry struct {
+	refs   int
+	loaded chan struct{}
+	shared sharedControlPlaneScope
+	err    error
+}
+
+func newControlPlaneScopeCache() *controlPlaneScopeCache {
+	return &controlPlaneScopeCache{
+		entries: make(map[client.ObjectKey]*controlPlaneScopeEntry),
+	}
+}
+
+// acquire returns the control plane scope of the control plane with the given key, calling load if no reconcile holds
+// it yet. Concurrent callers for the same key wait for the first one to load the scope. The returned release func
+// must be called once the caller is done with the scope.
+func (c *controlPlaneScopeCache) acquire(ctx context.Context, key client.ObjectKey, load func(context.Context) (sharedControlPlaneScope, error)) (sharedControlPlaneScope, func(), error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &controlPlaneScopeEntry{loaded: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	entry.refs++
+	c.mu.Unlock()
+
+	release := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		entry.refs--
+		if entry.refs == 0 && c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+	}
+
+	if !ok {
+		entry.shared, entry.err = load(ctx)
+		if entry.err != nil {
+			// Don't hand out the error to reconciles starting after this one, they load the scope again.
+			c.mu.Lock()
+			if c.entries[key] == entry {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		close(entry.loaded)
+	} else {
+		select {
+		case <-entry.loaded:
+		case <-ctx.Done():
+			release()
+			return sharedControlPlaneScope{}, nil, ctx.Err()
+		}
+	}
+
+	if entry.err != nil {
+		release()
+		return sharedControlPlaneScope{}, nil, entry.err
+	}
+	return entry.shared, release, nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	gomock2 "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	reconcilerutils "sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newControlPlaneReadCountingReconciler returns a reconciler whose client counts the reads of
+// AzureManagedControlPlanes in reads.
+func newControlPlaneReadCountingReconciler(g *WithT, reads *atomic.Int32) (*AzureManagedMachinePoolReconciler, *clusterv1.Cluster, client.ObjectKey) {
+	cluster, _, controlPlane, _, _ := newReadyAzureManagedMachinePoolCluster()
+	controlPlane.Spec.SubscriptionID = "fake-subscription-id"
+	s := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(s)).To(Succeed())
+	g.Expect(corev1.AddToScheme(s)).To(Succeed())
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithRuntimeObjects(
+			controlPlane,
+			&corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}},
+			&infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake-identity", Namespace: "default"},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:     infrav1.ServicePrincipal,
+					TenantID: "fake-tenantid",
+				},
+			},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*infrav1.AzureManagedControlPlane); ok {
+					reads.Add(1)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	return NewAzureManagedMachinePoolReconciler(fakeClient, nil, reconcilerutils.Timeouts{}, ""), cluster, client.ObjectKeyFromObject(controlPlane)
+}
+
+// poolReads counts the reads of the AzureManagedMachinePool reconciles which the shared control plane scope is meant
+// to deduplicate.
+type poolReads struct {
+	// controlPlanes counts the reads of the AzureManagedControlPlane.
+	controlPlanes atomic.Int32
+	// credentials counts the reads of the AzureClusterIdentity, i.e. the credential resolutions.
+	credentials atomic.Int32
+	// managedClusters counts the reads of the ASO ManagedCluster.
+	managedClusters atomic.Int32
+}
+
+func TestControlPlaneScopeCacheReadsForConcurrentPools(t *testing.T) {
+	for _, pools := range []int{1, 5, 20} {
+		pools := pools
+		t.Run(fmt.Sprintf("%d pools", pools), func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+
+			cluster, azManagedCluster, controlPlane, ammp, mp := newReadyAzureManagedMachinePoolCluster()
+			controlPlane.Spec.SubscriptionID = "fake-subscription-id"
+			objects := []client.Object{
+				cluster,
+				azManagedCluster,
+				controlPlane,
+				&corev1.Secret{Data: map[string][]byte{"clientSecret": []byte("fooSecret")}},
+				&infrav1.AzureClusterIdentity{
+					ObjectMeta: metav1.ObjectMeta{Name: "fake-identity", Namespace: "default"},
+					Spec: infrav1.AzureClusterIdentitySpec{
+						Type:     infrav1.ServicePrincipal,
+						TenantID: "fake-tenantid",
+					},
+				},
+			}
+			requests := make([]ctrl.Request, pools)
+			for i := 0; i < pools; i++ {
+				pool := ammp.DeepCopy()
+				pool.Name = fmt.Sprintf("foo-ammp-%d", i)
+				machinePool := mp.DeepCopy()
+				machinePool.Name = fmt.Sprintf("foo-mp-%d", i)
+				machinePool.Spec.Template.Spec.InfrastructureRef.Name = pool.Name
+				pool.OwnerReferences[0].Name = machinePool.Name
+				objects = append(objects, pool, machinePool)
+				requests[i] = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)}
+			}
+
+			s := runtime.NewScheme()
+			for _, addTo := range []func(s *runtime.Scheme) error{
+				scheme.AddToScheme,
+				clusterv1.AddToScheme,
+				expv1.AddToScheme,
+				infrav1.AddToScheme,
+				asocontainerservicev1.AddToScheme,
+			} {
+				g.Expect(addTo(s)).To(Succeed())
+			}
+			var reads poolReads
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(s).
+				WithStatusSubresource(&infrav1.AzureManagedMachinePool{}).
+				WithObjects(objects...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						switch obj.(type) {
+						case *infrav1.AzureManagedControlPlane:
+							reads.controlPlanes.Add(1)
+						case *infrav1.AzureClusterIdentity:
+							reads.credentials.Add(1)
+						case *asocontainerservicev1.ManagedCluster:
+							reads.managedClusters.Add(1)
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+				}).
+				Build()
+
+			// Every pool waits in its agent pool reconcile until all of them reached it, so that they all hold the
+			// control plane scope at the same time like pools of a new cluster reconciling concurrently.
+			var reconciling sync.WaitGroup
+			reconciling.Add(pools)
+			agentPools := mock_azure.NewMockReconciler(mockCtrl)
+			agentPools.EXPECT().Reconcile(gomock2.AContext()).DoAndReturn(func(context.Context) error {
+				reconciling.Done()
+				reconciling.Wait()
+				return nil
+			}).Times(pools)
+			nodeLister := NewMockNodeLister(mockCtrl)
+			nodeLister.EXPECT().List(gomock2.AContext(), gomock.Any()).Return(nil, nil).Times(pools)
+
+			r := NewAzureManagedMachinePoolReconciler(fakeClient, nil, reconcilerutils.Timeouts{}, "")
+			r.createAzureManagedMachinePoolService = func(scope *scope.ManagedMachinePoolScope, _ time.Duration) (*azureManagedMachinePoolService, error) {
+				return &azureManagedMachinePoolService{
+					scope:         scope,
+					agentPoolsSvc: agentPools,
+					scaleSetsSvc:  nodeLister,
+				}, nil
+			}
+
+			var done sync.WaitGroup
+			done.Add(pools)
+			errs := make([]error, pools)
+			for i := 0; i < pools; i++ {
+				go func(i int) {
+					defer done.Done()
+					_, errs[i] = r.Reconcile(context.Background(), requests[i])
+				}(i)
+			}
+			done.Wait()
+
+			for i := 0; i < pools; i++ {
+				g.Expect(errs[i]).NotTo(HaveOccurred())
+			}
+			g.Expect(reads.controlPlanes.Load()).To(BeEquivalentTo(1))
+			g.Expect(reads.credentials.Load()).To(BeEquivalentTo(1))
+			g.Expect(reads.managedClusters.Load()).To(BeZero())
+			g.Expect(r.controlPlaneScopes.entries).To(BeEmpty())
+		})
+	}
+}
+
+func TestControlPlaneScopeCacheSequentialPools(t *testing.T) {
+	g := NewWithT(t)
+	var reads atomic.Int32
+	r, cluster, key := newControlPlaneReadCountingReconciler(g, &reads)
+	load := func(ctx context.Context) (sharedControlPlaneScope, error) {
+		return r.loadControlPlaneScope(ctx, key, cluster)
+	}
+
+	// Pools reconciling one after the other don't share a possibly stale control plane.
+	for i := 0; i < 3; i++ {
+		_, release, err := r.controlPlaneScopes.acquire(context.Background(), key, load)
+		g.Expect(err).NotTo(HaveOccurred())
+		release()
+	}
+	g.Expect(reads.Load()).To(BeEquivalentTo(3))
+}
+
+func TestControlPlaneScopeCacheLoadError(t *testing.T) {
+	g := NewWithT(t)
+	cache := newControlPlaneScopeCache()
+	key := client.ObjectKey{Namespace: "foobar", Name: "foo-azManagedControlPlane"}
+	loadErr := errors.New("control plane not found")
+
+	var loads int
+	_, _, err := cache.acquire(context.Background(), key, func(context.Context) (sharedControlPlaneScope, error) {
+		loads++
+		return sharedControlPlaneScope{}, loadErr
+	})
+	g.Expect(err).To(MatchError(loadErr))
+	g.Expect(cache.entries).To(BeEmpty())
+
+	// The error isn't handed out to the pools reconciling after the failed one.
+	shared, release, err := cache.acquire(context.Background(), key, func(context.Context) (sharedControlPlaneScope, error) {
+		loads++
+		return sharedControlPlaneScope{ControlPlane: &infrav1.AzureManagedControlPlane{}}, nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(shared.ControlPlane).NotTo(BeNil())
+	release()
+	g.Expect(loads).To(Equal(2))
+}
+
+func TestControlPlaneScopeCacheWaitCanceled(t *testing.T) {
+	g := NewWithT(t)
+	cache := newControlPlaneScopeCache()
+	key := client.ObjectKey{Namespace: "foobar", Name: "foo-azManagedControlPlane"}
+
+	loading := make(chan struct{})
+	unblock := make(chan struct{})
+	go func() {
+		_, release, err := cache.acquire(context.Background(), key, func(context.Context) (sharedControlPlaneScope, error) {
+			close(loading)
+			<-unblock
+			return sharedControlPlaneScope{ControlPlane: &infrav1.AzureManagedControlPlane{}}, nil
+		})
+		if err == nil {
+			release()
+		}
+	}()
+	<-loading
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := cache.acquire(ctx, key, func(context.Context) (sharedControlPlaneScope, error) {
+		t.Fatal("the scope is already loading")
+		return sharedControlPlaneScope{}, nil
+	})
+	g.Expect(err).To(MatchError(context.Canceled))
+	close(unblock)
+	g.Eventually(func() int {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.entries)
+	}).Should(BeZero())
+}
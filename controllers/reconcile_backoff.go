@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+// maxTransientErrorBackoff caps the delay before retrying a reconciliation which failed with a transient error.
+const maxTransientErrorBackoff = 15 * time.Minute
+
+// reconcileBackoffer is an object which records the consecutive transient failures of its reconciliation.
+type reconcileBackoffer interface {
+	GetGeneration() int64
+	GetReconcileBackoff() *infrav1.ReconcileBackoff
+	SetReconcileBackoff(*infrav1.ReconcileBackoff)
+}
+
+// backoffRemaining returns how long is left before the reconciliation of obj, which failed with a transient error,
+// may be attempted again. It returns 0 if the TransientErrorBackoff feature is disabled, or if the spec of obj
+// changed since the failure.
+func backoffRemaining(obj reconcileBackoffer, now time.Time) time.Duration {
+	if !feature.Gates.Enabled(feature.TransientErrorBackoff) {
+		return 0
+	}
+	backoff := obj.GetReconcileBackoff()
+	if backoff == nil || backoff.ObservedGeneration != obj.GetGeneration() {
+		return 0
+	}
+	if remaining := backoff.NextAttemptTime.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// recordTransientError records that the reconciliation of obj failed with a transient error and returns how long to
+// wait before retrying it. When the TransientErrorBackoff feature is enabled, the delay starts at requeueAfter and
+// doubles with each consecutive failure up to maxTransientErrorBackoff. Otherwise requeueAfter is returned as is.
+func recordTransientError(obj reconcileBackoffer, requeueAfter time.Duration, now time.Time) time.Duration {
+	if !feature.Gates.Enabled(feature.TransientErrorBackoff) {
+		return requeueAfter
+	}
+	var failures int32
+	if backoff := obj.GetReconcileBackoff(); backoff != nil && backoff.ObservedGeneration == obj.GetGeneration() {
+		failures = backoff.Failures
+	}
+	failures++
+	delay := transientErrorBackoff(requeueAfter, failures)
+	obj.SetReconcileBackoff(&infrav1.ReconcileBackoff{
+		Failures:           failures,
+		NextAttemptTime:    metav1.NewTime(now.Add(delay)),
+		ObservedGeneration: obj.GetGeneration(),
+	})
+	return delay
+}
+
+// resetBackoff clears the transient failures recorded for obj once it reconciled successfully.
+func resetBackoff(obj reconcileBackoffer) {
+	obj.SetReconcileBackoff(nil)
+}
+
+// transientErrorBackoff returns the delay before retrying a reconciliation after the given number of consecutive
+// transient failures.
+func transientErrorBackoff(base time.Duration, failures int32) time.Duration {
+	if base <= 0 {
+		base = reconciler.DefaultReconcilerRequeue
+	}
+	delay := base
+	for i := int32(1); i < failures && delay < maxTransientErrorBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxTransientErrorBackoff {
+		return maxTransientErrorBackoff
+	}
+	return delay
+}
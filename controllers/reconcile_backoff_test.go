@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+)
+
+func TestTransientErrorBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		failures int32
+		expected time.Duration
+	}{
+		{
+			name:     "first failure is retried after the requeue of the error",
+			base:     15 * time.Second,
+			failures: 1,
+			expected: 15 * time.Second,
+		},
+		{
+			name:     "delay doubles with each consecutive failure",
+			base:     15 * time.Second,
+			failures: 4,
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "delay is capped",
+			base:     15 * time.Second,
+			failures: 7,
+			expected: maxTransientErrorBackoff,
+		},
+		{
+			name:     "delay stays capped after many failures",
+			base:     15 * time.Second,
+			failures: 1000,
+			expected: maxTransientErrorBackoff,
+		},
+		{
+			name:     "requeue longer than the cap is capped",
+			base:     time.Hour,
+			failures: 1,
+			expected: maxTransientErrorBackoff,
+		},
+		{
+			name:     "missing requeue defaults",
+			base:     0,
+			failures: 2,
+			expected: 30 * time.Second,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(transientErrorBackoff(tc.base, tc.failures)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRecordTransientError(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("feature disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{}
+
+		g.Expect(recordTransientError(azureMachine, 15*time.Second, now)).To(Equal(15 * time.Second))
+		g.Expect(recordTransientError(azureMachine, 15*time.Second, now)).To(Equal(15 * time.Second))
+		g.Expect(azureMachine.Status.ReconcileBackoff).To(BeNil())
+		g.Expect(backoffRemaining(azureMachine, now)).To(BeZero())
+	})
+
+	t.Run("consecutive failures grow the backoff", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.TransientErrorBackoff, true)()
+		g := NewWithT(t)
+		azureMachine := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+		g.Expect(recordTransientError(azureMachine, 15*time.Second, now)).To(Equal(15 * time.Second))
+		g.Expect(recordTransientError(azureMachine, 15*time.Second, now)).To(Equal(30 * time.Second))
+		g.Expect(recordTransientError(azureMachine, 15*time.Second, now)).To(Equal(time.Minute))
+		g.Expect(azureMachine.Status.ReconcileBackoff).To(Equal(&infrav1.ReconcileBackoff{
+			Failures:           3,
+			NextAttemptTime:    metav1.NewTime(now.Add(time.Minute)),
+			ObservedGeneration: 2,
+		}))
+		g.Expect(backoffRemaining(azureMachine, now.Add(20*time.Second))).To(Equal(40 * time.Second))
+		g.Expect(backoffRemaining(azureMachine, now.Add(2*time.Minute))).To(BeZero())
+	})
+
+	t.Run("spec change resets the backoff", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.TransientErrorBackoff, true)()
+		g := NewWithT(t)
+		azureCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+
+		recordTransientError(azureCluster, 15*time.Second, now)
+		recordTransientError(azureCluster, 15*time.Second, now)
+		azureCluster.Generation = 2
+		g.Expect(backoffRemaining(azureCluster, now)).To(BeZero())
+		g.Expect(recordTransientError(azureCluster, 15*time.Second, now)).To(Equal(15 * time.Second))
+		g.Expect(azureCluster.Status.ReconcileBackoff.Failures).To(Equal(int32(1)))
+	})
+
+	t.Run("success resets the backoff", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.TransientErrorBackoff, true)()
+		g := NewWithT(t)
+		azureCluster := &infrav1.AzureCluster{}
+
+		recordTransientError(azureCluster, 15*time.Second, now)
+		recordTransientError(azureCluster, 15*time.Second, now)
+		resetBackoff(azureCluster)
+		g.Expect(azureCluster.Status.ReconcileBackoff).To(BeNil())
+		g.Expect(backoffRemaining(azureCluster, now)).To(BeZero())
+		g.Expect(recordTransientError(azureCluster, 15*time.Second, now)).To(Equal(15 * time.Second))
+	})
+}
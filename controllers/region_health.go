@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourcehealth"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// regionHealthFailureThreshold is the number of consecutive failures to reconcile an AzureCluster after which the
+	// Service Health incidents of its region are looked up.
+	regionHealthFailureThreshold = 3
+
+	// regionDegradedRequeue is the delay before reconciling an AzureCluster again while an incident is active in its
+	// region.
+	regionDegradedRequeue = 15 * time.Minute
+)
+
+// regionIncidentGetter returns the title of an active Service Health incident in the region of a cluster, or an
+// empty string if there is none.
+type regionIncidentGetter func(ctx context.Context, clusterScope *scope.ClusterScope) (string, error)
+
+// regionHealthTracker counts the consecutive failures to reconcile AzureClusters and, when the RegionHealth feature
+// is enabled, marks the clusters whose region has an active Service Health incident as degraded so that their
+// failures are retried less often until they reconcile successfully. Changes to a degraded cluster are still
+// reconciled right away. The failures are kept in memory, as they only need to
+// outlive a single reconciliation.
+type regionHealthTracker struct {
+	activeIncident regionIncidentGetter
+
+	mu       sync.Mutex
+	failures map[types.UID]int
+}
+
+// newRegionHealthTracker returns a regionHealthTracker looking up the incidents with Azure Service Health.
+func newRegionHealthTracker() *regionHealthTracker {
+	return &regionHealthTracker{
+		activeIncident: activeRegionIncident,
+		failures:       make(map[types.UID]int),
+	}
+}
+
+func activeRegionIncident(ctx context.Context, clusterScope *scope.ClusterScope) (string, error) {
+	checker, err := resourcehealth.NewRegionIncidentChecker(clusterScope)
+	if err != nil {
+		return "", err
+	}
+	return checker.ActiveIncident(ctx, clusterScope.Location())
+}
+
+// recordFailure records a failure to reconcile an AzureCluster. Once the consecutive failures reach
+// regionHealthFailureThreshold, it looks up the incidents in the region of the cluster and returns how long to wait
+// before reconciling it again if one is active, or 0 otherwise.
+func (r *regionHealthTracker) recordFailure(ctx context.Context, clusterScope *scope.ClusterScope) time.Duration {
+	if r == nil {
+		return 0
+	}
+	if !feature.Gates.Enabled(feature.RegionHealth) {
+		// Remove a condition left over from when the feature was enabled.
+		conditions.Delete(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)
+		return 0
+	}
+	r.mu.Lock()
+	r.failures[clusterScope.AzureCluster.GetUID()]++
+	failures := r.failures[clusterScope.AzureCluster.GetUID()]
+	r.mu.Unlock()
+	if failures < regionHealthFailureThreshold {
+		return 0
+	}
+	return r.checkRegion(ctx, clusterScope)
+}
+
+// reset drops the consecutive failures of an AzureCluster and its RegionDegraded condition, once it reconciled
+// successfully or is deleted.
+func (r *regionHealthTracker) reset(clusterScope *scope.ClusterScope) {
+	if r == nil {
+		return
+	}
+	conditions.Delete(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, clusterScope.AzureCluster.GetUID())
+}
+
+// checkRegion sets the RegionDegraded condition of an AzureCluster and returns regionDegradedRequeue if an incident
+// is active in its region, and removes the condition and returns 0 otherwise. Failing to look up the incidents
+// doesn't change the condition, as the Service Health API may be unavailable for the same reason as the region.
+func (r *regionHealthTracker) checkRegion(ctx context.Context, clusterScope *scope.ClusterScope) time.Duration {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.regionHealthTracker.checkRegion")
+	defer done()
+
+	azureCluster := clusterScope.AzureCluster
+	incident, err := r.activeIncident(ctx, clusterScope)
+	if err != nil {
+		log.Error(err, "failed to look up the service health incidents of the region", "location", clusterScope.Location())
+		if conditions.Has(azureCluster, infrav1.RegionDegradedCondition) {
+			return regionDegradedRequeue
+		}
+		return 0
+	}
+	if incident == "" {
+		conditions.Delete(azureCluster, infrav1.RegionDegradedCondition)
+		return 0
+	}
+	log.Info("an Azure service health incident is active in the region of the cluster, retrying less often", "location", clusterScope.Location(), "incident", incident)
+	conditions.Set(azureCluster, &clusterv1.Condition{
+		Type:    infrav1.RegionDegradedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.ServiceIssueActiveReason,
+		Message: incident,
+	})
+	return regionDegradedRequeue
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeRegionHealth returns a regionHealthTracker reporting incident as active, and counting the lookups.
+func fakeRegionHealth(incident *string, lookups *int) *regionHealthTracker {
+	return &regionHealthTracker{
+		activeIncident: func(context.Context, *scope.ClusterScope) (string, error) {
+			*lookups++
+			return *incident, nil
+		},
+		failures: make(map[types.UID]int),
+	}
+}
+
+func TestAzureClusterReconcileRegionHealth(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RegionHealth, true)()
+	g := NewWithT(t)
+
+	reconcileErr := errors.New("failed to reach the region")
+	reconciles := 0
+	reconciler, clusterScope, err := getClusterReconcileInputs(TestClusterReconcileInput{
+		createAzureClusterService: func(cs *scope.ClusterScope) (*azureClusterService, error) {
+			return getDefaultAzureClusterService(func(acs *azureClusterService) {
+				acs.skuCache = resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, cs.Location())
+				acs.scope = cs
+				acs.Reconcile = func(context.Context) error {
+					reconciles++
+					return reconcileErr
+				}
+			}), nil
+		},
+		cache: &scope.ClusterCache{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	incident, lookups := "Networking outage", 0
+	reconciler.regionHealth = fakeRegionHealth(&incident, &lookups)
+
+	// The region isn't looked up until the failures reach the threshold.
+	for i := 1; i < regionHealthFailureThreshold; i++ {
+		_, err := reconciler.reconcileNormal(context.Background(), clusterScope)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to reconcile cluster services")))
+	}
+	g.Expect(lookups).To(Equal(0))
+
+	// The cluster is marked as degraded once an incident is found, and reconciled less often.
+	result, err := reconciler.reconcileNormal(context.Background(), clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: regionDegradedRequeue}))
+	g.Expect(lookups).To(Equal(1))
+	cond := conditions.Get(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(infrav1.ServiceIssueActiveReason))
+	g.Expect(cond.Message).To(Equal(incident))
+
+	// The Azure services are still reconciled while the incident is active, only the failures are retried less often.
+	result, err = reconciler.reconcileNormal(context.Background(), clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: regionDegradedRequeue}))
+	g.Expect(reconciles).To(Equal(regionHealthFailureThreshold + 1))
+	g.Expect(lookups).To(Equal(2))
+	g.Expect(clusterScope.AzureCluster.Status.Ready).To(BeFalse())
+
+	// The condition is removed once the cluster reconciles successfully, without looking up the region again.
+	reconcileErr = nil
+	result, err = reconciler.reconcileNormal(context.Background(), clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(lookups).To(Equal(2))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)).To(BeFalse())
+	g.Expect(clusterScope.AzureCluster.Status.Ready).To(BeTrue())
+	g.Expect(reconciler.regionHealth.failures).To(BeEmpty())
+}
+
+func TestRegionHealthTrackerTransientErrors(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RegionHealth, true)()
+	g := NewWithT(t)
+
+	reconciler, clusterScope, err := getClusterReconcileInputs(TestClusterReconcileInput{
+		createAzureClusterService: func(cs *scope.ClusterScope) (*azureClusterService, error) {
+			return getDefaultAzureClusterService(func(acs *azureClusterService) {
+				acs.skuCache = resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, cs.Location())
+				acs.scope = cs
+				acs.Reconcile = func(context.Context) error {
+					return azure.WithTransientError(errors.New("failed to reconcile AzureCluster"), 10*time.Second)
+				}
+			}), nil
+		},
+		cache: &scope.ClusterCache{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	incident, lookups := "", 0
+	reconciler.regionHealth = fakeRegionHealth(&incident, &lookups)
+
+	// Without an incident in the region, transient errors are retried as usual.
+	for i := 0; i < regionHealthFailureThreshold; i++ {
+		result, err := reconciler.reconcileNormal(context.Background(), clusterScope)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+	}
+	g.Expect(lookups).To(Equal(1))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)).To(BeFalse())
+
+	// The retries are stretched once an incident is found.
+	incident = "Compute outage"
+	result, err := reconciler.reconcileNormal(context.Background(), clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: regionDegradedRequeue}))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)).To(BeTrue())
+}
+
+func TestRegionHealthTrackerDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	_, clusterScope, err := getClusterReconcileInputs(TestClusterReconcileInput{cache: &scope.ClusterCache{}})
+	g.Expect(err).NotTo(HaveOccurred())
+	incident, lookups := "Networking outage", 0
+	tracker := fakeRegionHealth(&incident, &lookups)
+	conditions.MarkTrue(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)
+
+	// A condition left over from when the feature was enabled is removed.
+	for i := 0; i < regionHealthFailureThreshold; i++ {
+		g.Expect(tracker.recordFailure(context.Background(), clusterScope)).To(BeZero())
+	}
+	g.Expect(lookups).To(Equal(0))
+	g.Expect(conditions.Has(clusterScope.AzureCluster, infrav1.RegionDegradedCondition)).To(BeFalse())
+}
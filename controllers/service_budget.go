@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+// serviceBudget splits the time left in a reconcile loop between the Azure services reconciled in it, weighted by
+// the service weights of its timeouts, so that a slow service can't starve the services after it.
+type serviceBudget struct {
+	timeouts reconciler.Timeouts
+}
+
+// reconcile calls reconcileService for each of services in order, each with a context bounded by the share of the
+// service in the time left before the deadline of ctx. The time a service doesn't use is shared between the
+// services after it. A service which runs out of its budget doesn't stop the services after it from being
+// reconciled: its error is returned as a transient error once they are all reconciled, unless one of them fails.
+func (b serviceBudget) reconcile(ctx context.Context, services []azure.ServiceReconciler, reconcileService func(context.Context, azure.ServiceReconciler) error) error {
+	var budgetErr error
+	for i, service := range services {
+		serviceCtx, cancel, budget := b.withBudget(ctx, services[i:])
+		err := reconcileService(serviceCtx, service)
+		exceeded := errors.Is(serviceCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+		if err == nil {
+			continue
+		}
+		if !exceeded {
+			return err
+		}
+		if budgetErr == nil {
+			budgetErr = azure.WithTransientError(errors.Wrapf(err, "service %s exceeded its reconcile budget of %s", service.Name(), budget), b.timeouts.DefaultedReconcilerRequeue())
+		}
+	}
+
+	return budgetErr
+}
+
+// withBudget returns a context bounded by the share of services[0] in the time left before the deadline of ctx,
+// split between services by weight, along with the budget. ctx is returned as is if it has no deadline.
+func (b serviceBudget) withBudget(ctx context.Context, services []azure.ServiceReconciler) (context.Context, context.CancelFunc, time.Duration) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}, 0
+	}
+
+	var total int
+	for _, service := range services {
+		total += b.timeouts.DefaultedServiceWeight(service.Name())
+	}
+	budget := time.Until(deadline) * time.Duration(b.timeouts.DefaultedServiceWeight(services[0].Name())) / time.Duration(total)
+	serviceCtx, cancel := context.WithTimeout(ctx, budget)
+
+	return serviceCtx, cancel, budget.Round(time.Millisecond)
+}
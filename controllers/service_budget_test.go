@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// sleepUntilDone is a service reconcile which takes until ctx is done.
+func sleepUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newMockServiceReconcilers(mockCtrl *gomock.Controller, names ...string) []*mock_azure.MockServiceReconciler {
+	services := make([]*mock_azure.MockServiceReconciler, len(names))
+	for i, name := range names {
+		services[i] = mock_azure.NewMockServiceReconciler(mockCtrl)
+		services[i].EXPECT().Name().Return(name).AnyTimes()
+	}
+	return services
+}
+
+func TestAzureClusterServiceReconcileBudget(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	services := newMockServiceReconcilers(mockCtrl, "loadbalancers", "privatedns", "bastionhosts")
+	services[0].EXPECT().Reconcile(gomockinternal.AContext()).DoAndReturn(sleepUntilDone)
+	services[1].EXPECT().Reconcile(gomockinternal.AContext()).Return(nil)
+	services[2].EXPECT().Reconcile(gomockinternal.AContext()).Return(nil)
+
+	acs := &azureClusterService{
+		scope: &scope.ClusterScope{
+			Cluster:      &clusterv1.Cluster{},
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+		services: []azure.ServiceReconciler{services[0], services[1], services[2]},
+		skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
+		budget:   serviceBudget{timeouts: reconciler.Timeouts{Requeue: time.Minute}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := acs.reconcile(ctx)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix("service loadbalancers exceeded its reconcile budget of"))
+	g.Expect(ctx.Err()).NotTo(HaveOccurred())
+
+	var reconcileError azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+	g.Expect(reconcileError.IsTransient()).To(BeTrue())
+	g.Expect(reconcileError.RequeueAfter()).To(Equal(time.Minute))
+}
+
+func TestServiceBudgetReconcile(t *testing.T) {
+	testcases := []struct {
+		name          string
+		weights       map[string]int
+		timeout       time.Duration
+		expect        func(g *WithT, cancel context.CancelFunc, one, two *mock_azure.MockServiceReconcilerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:    "services share the time left by weight",
+			weights: map[string]int{"virtualmachines": 3},
+			timeout: time.Hour,
+			expect: func(g *WithT, _ context.CancelFunc, one, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+					deadline, ok := ctx.Deadline()
+					g.Expect(ok).To(BeTrue())
+					g.Expect(time.Until(deadline)).To(BeNumerically("~", 15*time.Minute, time.Second))
+					return nil
+				})
+				two.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+					deadline, ok := ctx.Deadline()
+					g.Expect(ok).To(BeTrue())
+					g.Expect(time.Until(deadline)).To(BeNumerically("~", time.Hour, time.Second))
+					return nil
+				})
+			},
+		},
+		{
+			name:    "services aren't bounded without a deadline",
+			timeout: 0,
+			expect: func(g *WithT, _ context.CancelFunc, one, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+					_, ok := ctx.Deadline()
+					g.Expect(ok).To(BeFalse())
+					return nil
+				})
+				two.Reconcile(gomockinternal.AContext()).Return(nil)
+			},
+		},
+		{
+			name:    "a service exceeding its budget doesn't stop the next one",
+			timeout: 200 * time.Millisecond,
+			expect: func(_ *WithT, _ context.CancelFunc, one, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).DoAndReturn(sleepUntilDone)
+				two.Reconcile(gomockinternal.AContext()).Return(nil)
+			},
+			expectedError: "service vnet exceeded its reconcile budget of",
+		},
+		{
+			name:    "the error of a later service takes precedence over an exceeded budget",
+			timeout: 200 * time.Millisecond,
+			expect: func(_ *WithT, _ context.CancelFunc, one, two *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).DoAndReturn(sleepUntilDone)
+				two.Reconcile(gomockinternal.AContext()).Return(errors.New("boom"))
+			},
+			expectedError: "boom",
+		},
+		{
+			name:    "a failing service stops the next one",
+			timeout: time.Hour,
+			expect: func(_ *WithT, _ context.CancelFunc, one, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).Return(errors.New("boom"))
+			},
+			expectedError: "boom",
+		},
+		{
+			name:    "a cancelled reconcile loop isn't an exceeded budget",
+			timeout: time.Hour,
+			expect: func(_ *WithT, cancel context.CancelFunc, one, _ *mock_azure.MockServiceReconcilerMockRecorder) {
+				one.Reconcile(gomockinternal.AContext()).DoAndReturn(func(ctx context.Context) error {
+					cancel()
+					return sleepUntilDone(ctx)
+				})
+			},
+			expectedError: context.Canceled.Error(),
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			services := newMockServiceReconcilers(mockCtrl, "vnet", "virtualmachines")
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.timeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tc.timeout)
+			}
+			defer cancel()
+			tc.expect(g, cancel, services[0].EXPECT(), services[1].EXPECT())
+
+			b := serviceBudget{timeouts: reconciler.Timeouts{ServiceWeights: tc.weights}}
+			err := b.reconcile(ctx, []azure.ServiceReconciler{services[0], services[1]}, func(ctx context.Context, service azure.ServiceReconciler) error {
+				return service.Reconcile(ctx)
+			})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ServiceReconcilingReason is the event reason emitted when CAPZ starts reconciling an Azure service of an object.
+	ServiceReconcilingReason = "ServiceReconciling"
+	// ServiceReadyReason is the event reason emitted when an Azure service of an object is done reconciling.
+	ServiceReadyReason = "ServiceReady"
+
+	// serviceProgressEventInterval is the minimum time between two progress events for the same service of an object,
+	// so that periodic no-op reconciles don't spam events.
+	serviceProgressEventInterval = 10 * time.Minute
+)
+
+// serviceProgressKey identifies a service of a reconciled object.
+type serviceProgressKey struct {
+	uid     types.UID
+	service string
+}
+
+// serviceProgress is the progress of a service reconcile that may span several reconciliations.
+type serviceProgress struct {
+	start     time.Time
+	announced bool
+}
+
+// serviceProgressRecorder emits Normal events when the Azure services of an object start and finish reconciling.
+// It outlives a single reconciliation so that the elapsed duration of long-running operations can be reported and
+// repeated no-op reconciles can be rate limited.
+type serviceProgressRecorder struct {
+	recorder record.EventRecorder
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	inProgress map[serviceProgressKey]*serviceProgress
+	lastEvent  map[serviceProgressKey]time.Time
+}
+
+// newServiceProgressRecorder returns a serviceProgressRecorder emitting events with recorder.
+func newServiceProgressRecorder(recorder record.EventRecorder) *serviceProgressRecorder {
+	return &serviceProgressRecorder{
+		recorder:   recorder,
+		interval:   serviceProgressEventInterval,
+		now:        time.Now,
+		inProgress: make(map[serviceProgressKey]*serviceProgress),
+		lastEvent:  make(map[serviceProgressKey]time.Time),
+	}
+}
+
+// forObject returns the progress of the services of obj. It is nil if r is nil.
+func (r *serviceProgressRecorder) forObject(obj client.Object) *objectServiceProgress {
+	if r == nil {
+		return nil
+	}
+	return &objectServiceProgress{recorder: r, obj: obj}
+}
+
+// forget drops the progress of the services of obj, once it is deleted.
+func (r *serviceProgressRecorder) forget(obj client.Object) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.inProgress {
+		if key.uid == obj.GetUID() {
+			delete(r.inProgress, key)
+		}
+	}
+	for key := range r.lastEvent {
+		if key.uid == obj.GetUID() {
+			delete(r.lastEvent, key)
+		}
+	}
+}
+
+// announce emits the reconciling event for key. It must be called with r.mu held.
+func (r *serviceProgressRecorder) announce(obj client.Object, key serviceProgressKey, progress *serviceProgress) {
+	progress.announced = true
+	r.lastEvent[key] = r.now()
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, ServiceReconcilingReason, "Reconciling %s", key.service)
+}
+
+// objectServiceProgress reports the progress of the services of a single object.
+type objectServiceProgress struct {
+	recorder *serviceProgressRecorder
+	obj      client.Object
+}
+
+// reconcile reconciles service and reports its progress. A nil objectServiceProgress only reconciles service.
+func (p *objectServiceProgress) reconcile(ctx context.Context, service azure.ServiceReconciler) error {
+	if p == nil {
+		return service.Reconcile(ctx)
+	}
+	name := service.Name()
+	p.started(name)
+	if err := service.Reconcile(ctx); err != nil {
+		p.notReady(name)
+		return err
+	}
+	p.ready(name)
+	return nil
+}
+
+func (p *objectServiceProgress) key(service string) serviceProgressKey {
+	return serviceProgressKey{uid: p.obj.GetUID(), service: service}
+}
+
+// started records that service started reconciling. The reconciling event is only emitted if the service isn't
+// already in progress from a previous reconciliation and no progress event was emitted for it recently.
+func (p *objectServiceProgress) started(service string) {
+	r := p.recorder
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := p.key(service)
+	if _, ok := r.inProgress[key]; ok {
+		return
+	}
+	progress := &serviceProgress{start: r.now()}
+	r.inProgress[key] = progress
+	if last, ok := r.lastEvent[key]; !ok || r.now().Sub(last) >= r.interval {
+		r.announce(p.obj, key, progress)
+	}
+}
+
+// notReady records that service did not finish reconciling. The reconciling event is emitted if it was held back by
+// the rate limit, since the service is now known to be doing work that spans reconciliations.
+func (p *objectServiceProgress) notReady(service string) {
+	r := p.recorder
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := p.key(service)
+	if progress, ok := r.inProgress[key]; ok && !progress.announced {
+		r.announce(p.obj, key, progress)
+	}
+}
+
+// ready records that service is done reconciling and emits the ready event with the elapsed duration if the
+// reconciling event was emitted.
+func (p *objectServiceProgress) ready(service string) {
+	r := p.recorder
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := p.key(service)
+	progress, ok := r.inProgress[key]
+	if !ok {
+		return
+	}
+	delete(r.inProgress, key)
+	if !progress.announced {
+		return
+	}
+	r.lastEvent[key] = r.now()
+	elapsed := r.now().Sub(progress.start).Round(time.Second)
+	r.recorder.Eventf(p.obj, corev1.EventTypeNormal, ServiceReadyReason, "%s ready after %s", service, elapsed)
+}
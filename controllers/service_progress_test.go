@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// fakeClock is a settable clock for serviceProgressRecorder.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) step(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestServiceProgressRecorder() (*serviceProgressRecorder, *record.FakeRecorder, *fakeClock) {
+	recorder := record.NewFakeRecorder(20)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := newServiceProgressRecorder(recorder)
+	r.now = clock.Now
+	return r, recorder, clock
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestServiceProgressRecorder(t *testing.T) {
+	g := NewWithT(t)
+	r, recorder, clock := newTestServiceProgressRecorder()
+	azureCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", UID: "1234"}}
+	progress := r.forObject(azureCluster)
+
+	// A long-running operation spans several reconciliations and reports the total elapsed time.
+	progress.started("loadbalancers")
+	progress.notReady("loadbalancers")
+	clock.step(20 * time.Second)
+	progress.started("loadbalancers")
+	progress.notReady("loadbalancers")
+	clock.step(23 * time.Second)
+	progress.started("loadbalancers")
+	progress.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(Equal([]string{
+		"Normal ServiceReconciling Reconciling loadbalancers",
+		"Normal ServiceReady loadbalancers ready after 43s",
+	}))
+
+	// Repeated no-op reconciles are rate limited.
+	clock.step(time.Minute)
+	progress.started("loadbalancers")
+	progress.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+
+	// New work within the rate limit is still reported once it spans reconciliations.
+	clock.step(time.Minute)
+	progress.started("loadbalancers")
+	progress.notReady("loadbalancers")
+	clock.step(5 * time.Second)
+	progress.started("loadbalancers")
+	progress.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(Equal([]string{
+		"Normal ServiceReconciling Reconciling loadbalancers",
+		"Normal ServiceReady loadbalancers ready after 5s",
+	}))
+
+	// Once the rate limit interval has passed, a no-op reconcile is reported again.
+	clock.step(serviceProgressEventInterval)
+	progress.started("loadbalancers")
+	progress.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(Equal([]string{
+		"Normal ServiceReconciling Reconciling loadbalancers",
+		"Normal ServiceReady loadbalancers ready after 0s",
+	}))
+
+	// The rate limit is per object.
+	otherCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "other-cluster", UID: "5678"}}
+	other := r.forObject(otherCluster)
+	other.started("loadbalancers")
+	other.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(HaveLen(2))
+
+	// Forgetting an object resets its rate limit.
+	r.forget(azureCluster)
+	progress.started("loadbalancers")
+	progress.ready("loadbalancers")
+	g.Expect(drainEvents(recorder)).To(HaveLen(2))
+}
+
+func TestAzureClusterServiceReconcileProgressEvents(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	r, recorder, clock := newTestServiceProgressRecorder()
+	azureCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", UID: "1234"}}
+	one := mock_azure.NewMockServiceReconciler(mockCtrl)
+	two := mock_azure.NewMockServiceReconciler(mockCtrl)
+	one.EXPECT().Name().Return("groups").AnyTimes()
+	two.EXPECT().Name().Return("loadbalancers").AnyTimes()
+	gomock.InOrder(
+		one.EXPECT().Reconcile(gomockinternal.AContext()).Return(nil),
+		two.EXPECT().Reconcile(gomockinternal.AContext()).Return(errors.New("operation not done")),
+		one.EXPECT().Reconcile(gomockinternal.AContext()).Return(nil),
+		two.EXPECT().Reconcile(gomockinternal.AContext()).DoAndReturn(func(context.Context) error {
+			clock.step(43 * time.Second)
+			return nil
+		}),
+	)
+
+	acs := &azureClusterService{
+		scope: &scope.ClusterScope{
+			Cluster:      &clusterv1.Cluster{},
+			AzureCluster: azureCluster,
+		},
+		services: []azure.ServiceReconciler{one, two},
+		skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
+	}
+	reconcileServices := func() error {
+		acs.progress = r.forObject(azureCluster)
+		return acs.reconcile(context.Background())
+	}
+
+	g.Expect(reconcileServices()).To(HaveOccurred())
+	g.Expect(reconcileServices()).To(Succeed())
+	g.Expect(drainEvents(recorder)).To(Equal([]string{
+		"Normal ServiceReconciling Reconciling groups",
+		"Normal ServiceReady groups ready after 0s",
+		"Normal ServiceReconciling Reconciling loadbalancers",
+		"Normal ServiceReady loadbalancers ready after 43s",
+	}))
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// SpotEvictionRateHighReason is the event reason emitted on an AzureManagedMachinePool when many of its spot nodes
+	// are evicted in a short time.
+	SpotEvictionRateHighReason = "SpotEvictionRateHigh"
+
+	// preemptScheduledReason is the reason of the events the node problem detector of AKS emits on a spot node when
+	// Azure schedules its eviction.
+	preemptScheduledReason = "PreemptScheduled"
+
+	defaultSpotEvictionPollInterval = time.Minute
+	spotEvictionSpikeWindow         = 10 * time.Minute
+	spotEvictionSpikeThreshold      = 3
+)
+
+var spotEvictionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "capz_spot_evictions_total",
+		Help: "Number of spot node evictions observed for each AzureManagedMachinePool, identified as namespace/name.",
+	},
+	[]string{"pool"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(spotEvictionsTotal)
+}
+
+// SpotEvictionPoller periodically counts the evictions of the nodes of spot AzureManagedMachinePools from the events
+// reported in their workload cluster. It records the count in the status of the AzureManagedMachinePool, exports the
+// capz_spot_evictions_total metric and emits a Warning event on the AzureManagedMachinePool when evictions spike.
+type SpotEvictionPoller struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	Interval         time.Duration
+	WatchFilterValue string
+
+	getWorkloadClient func(ctx context.Context, cluster client.ObjectKey) (client.Client, error)
+	now               func() time.Time
+
+	// evictions are the times of the recent evictions of each pool.
+	evictions map[types.NamespacedName][]time.Time
+	// lastSpike is the time of the last spike event of each pool.
+	lastSpike map[types.NamespacedName]time.Time
+}
+
+var _ manager.LeaderElectionRunnable = (*SpotEvictionPoller)(nil)
+
+// NewSpotEvictionPoller returns a new SpotEvictionPoller instance.
+func NewSpotEvictionPoller(c client.Client, recorder record.EventRecorder, watchFilterValue string) *SpotEvictionPoller {
+	p := &SpotEvictionPoller{
+		Client:           c,
+		Recorder:         recorder,
+		Interval:         defaultSpotEvictionPollInterval,
+		WatchFilterValue: watchFilterValue,
+		now:              time.Now,
+		evictions:        make(map[types.NamespacedName][]time.Time),
+		lastSpike:        make(map[types.NamespacedName]time.Time),
+	}
+	p.getWorkloadClient = func(ctx context.Context, cluster client.ObjectKey) (client.Client, error) {
+		return scope.GetRemoteClientCache().GetClient(ctx, p.Client, cluster)
+	}
+	return p
+}
+
+// Start polls the workload clusters until ctx is done.
+func (p *SpotEvictionPoller) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, p.poll, p.Interval)
+	return nil
+}
+
+// NeedLeaderElection ensures only the leader counts evictions.
+func (p *SpotEvictionPoller) NeedLeaderElection() bool {
+	return true
+}
+
+// poll counts the new evictions of the spot pools of every cluster.
+func (p *SpotEvictionPoller) poll(ctx context.Context) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.SpotEvictionPoller.poll")
+	defer done()
+
+	pools := &infrav1.AzureManagedMachinePoolList{}
+	if err := p.Client.List(ctx, pools); err != nil {
+		log.Error(err, "failed to list AzureManagedMachinePools")
+		return
+	}
+
+	poolsByCluster := make(map[client.ObjectKey][]*infrav1.AzureManagedMachinePool)
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		if ptr.Deref(pool.Spec.ScaleSetPriority, "") != infrav1.ScaleSetPrioritySpot || !pool.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if p.WatchFilterValue != "" && pool.Labels[clusterv1.WatchLabel] != p.WatchFilterValue {
+			continue
+		}
+		clusterName := pool.Labels[clusterv1.ClusterNameLabel]
+		if clusterName == "" {
+			continue
+		}
+		cluster := client.ObjectKey{Namespace: pool.Namespace, Name: clusterName}
+		poolsByCluster[cluster] = append(poolsByCluster[cluster], pool)
+	}
+
+	for cluster, clusterPools := range poolsByCluster {
+		if err := p.pollCluster(ctx, cluster, clusterPools); err != nil {
+			// The workload cluster may not be reachable yet, try again on the next poll.
+			log.V(4).Info("failed to count spot evictions", "cluster", cluster, "error", err.Error())
+		}
+	}
+
+	p.forget(poolsByCluster)
+}
+
+// pollCluster counts the new evictions of the spot pools of a cluster.
+func (p *SpotEvictionPoller) pollCluster(ctx context.Context, cluster client.ObjectKey, pools []*infrav1.AzureManagedMachinePool) error {
+	workloadClient, err := p.getWorkloadClient(ctx, cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the workload cluster client")
+	}
+	events := &corev1.EventList{}
+	if err := workloadClient.List(ctx, events, client.MatchingFields{"reason": preemptScheduledReason}); err != nil {
+		return errors.Wrap(err, "failed to list eviction events")
+	}
+
+	poolEvents := make(map[*infrav1.AzureManagedMachinePool][]corev1.Event)
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind != "Node" {
+			continue
+		}
+		if pool := nodePool(pools, event.InvolvedObject.Name); pool != nil {
+			poolEvents[pool] = append(poolEvents[pool], event)
+		}
+	}
+
+	var errs []error
+	for _, pool := range pools {
+		if err := p.countEvictions(ctx, pool, poolEvents[pool]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// countEvictions counts the new evictions of a pool from its eviction events. The events already counted are recorded
+// in the status of the pool, so that they aren't counted again after a restart.
+func (p *SpotEvictionPoller) countEvictions(ctx context.Context, pool *infrav1.AzureManagedMachinePool, events []corev1.Event) error {
+	previous := make(map[string]int32)
+	evictions := &infrav1.SpotEvictionsStatus{}
+	if pool.Status.SpotEvictions != nil {
+		for _, event := range pool.Status.SpotEvictions.CountedEvents {
+			previous[event.UID] = event.Count
+		}
+		evictions.Count = pool.Status.SpotEvictions.Count
+		evictions.LastEvictionTime = pool.Status.SpotEvictions.LastEvictionTime
+	}
+
+	var newEvictions []time.Time
+	for _, event := range events {
+		count := event.Count
+		if count < 1 {
+			count = 1
+		}
+		evictions.CountedEvents = append(evictions.CountedEvents, infrav1.CountedEvictionEvent{UID: string(event.UID), Count: count})
+		t := eventTime(event)
+		for i := previous[string(event.UID)]; i < count; i++ {
+			newEvictions = append(newEvictions, t)
+		}
+		if count > previous[string(event.UID)] && (evictions.LastEvictionTime == nil || t.After(evictions.LastEvictionTime.Time)) {
+			evictions.LastEvictionTime = ptr.To(metav1.NewTime(t))
+		}
+	}
+	sort.Slice(evictions.CountedEvents, func(i, j int) bool {
+		return evictions.CountedEvents[i].UID < evictions.CountedEvents[j].UID
+	})
+	evictions.Count += int32(len(newEvictions))
+
+	if pool.Status.SpotEvictions == nil && evictions.Count == 0 {
+		return nil
+	}
+	if !apiequality.Semantic.DeepEqual(pool.Status.SpotEvictions, evictions) {
+		before := pool.DeepCopy()
+		pool.Status.SpotEvictions = evictions
+		if err := p.Client.Status().Patch(ctx, pool, client.MergeFrom(before)); err != nil {
+			return errors.Wrapf(err, "failed to record the spot evictions of AzureManagedMachinePool %s", pool.Name)
+		}
+	}
+
+	if len(newEvictions) > 0 {
+		key := types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}
+		p.evictions[key] = append(p.evictions[key], newEvictions...)
+		spotEvictionsTotal.WithLabelValues(key.String()).Add(float64(len(newEvictions)))
+		p.detectSpike(pool)
+	}
+	return nil
+}
+
+// detectSpike emits a Warning event on the pool when its recent evictions reach the spike threshold. At most one
+// event is emitted per spike window.
+func (p *SpotEvictionPoller) detectSpike(pool *infrav1.AzureManagedMachinePool) {
+	key := types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}
+	since := p.now().Add(-spotEvictionSpikeWindow)
+
+	var recent []time.Time
+	for _, t := range p.evictions[key] {
+		if t.After(since) {
+			recent = append(recent, t)
+		}
+	}
+	p.evictions[key] = recent
+
+	if len(recent) < spotEvictionSpikeThreshold || p.lastSpike[key].After(since) {
+		return
+	}
+	p.lastSpike[key] = p.now()
+	p.Recorder.Eventf(pool, corev1.EventTypeWarning, SpotEvictionRateHighReason,
+		"%d spot nodes of the pool were evicted in the last %s, consider raising the spot max price or using other VM sizes or zones",
+		len(recent), spotEvictionSpikeWindow)
+}
+
+// forget drops the state of the pools that are no longer polled.
+func (p *SpotEvictionPoller) forget(poolsByCluster map[client.ObjectKey][]*infrav1.AzureManagedMachinePool) {
+	pools := make(map[types.NamespacedName]bool)
+	for _, clusterPools := range poolsByCluster {
+		for _, pool := range clusterPools {
+			pools[types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}] = true
+		}
+	}
+	for key := range p.evictions {
+		if !pools[key] {
+			delete(p.evictions, key)
+			delete(p.lastSpike, key)
+			spotEvictionsTotal.DeleteLabelValues(key.String())
+		}
+	}
+}
+
+// nodePool returns the pool of the node, from the name AKS gives to the nodes of an agent pool:
+// aks-<agent pool name>-<hash>-vmss<instance>.
+func nodePool(pools []*infrav1.AzureManagedMachinePool, nodeName string) *infrav1.AzureManagedMachinePool {
+	for _, pool := range pools {
+		if strings.HasPrefix(nodeName, fmt.Sprintf("aks-%s-", ptr.Deref(pool.Spec.Name, pool.Name))) {
+			return pool
+		}
+	}
+	return nil
+}
+
+// eventTime returns the time an event was last seen.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
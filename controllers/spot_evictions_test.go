@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSpotPool(name, clusterName, priority string) *infrav1.AzureManagedMachinePool {
+	return &infrav1.AzureManagedMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+		},
+		Spec: infrav1.AzureManagedMachinePoolSpec{
+			AzureManagedMachinePoolClassSpec: infrav1.AzureManagedMachinePoolClassSpec{
+				Name:             ptr.To(name),
+				ScaleSetPriority: ptr.To(priority),
+			},
+		},
+	}
+}
+
+func newPreemptEvent(uid types.UID, nodeName string, count int32, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      string(uid),
+			Namespace: "default",
+			UID:       uid,
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: nodeName},
+		Reason:         preemptScheduledReason,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(lastTimestamp),
+	}
+}
+
+func newTestSpotEvictionPoller(g *WithT, workloadClient client.Client, objs ...client.Object) (*SpotEvictionPoller, *record.FakeRecorder, *fakeClock) {
+	c := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithObjects(objs...).WithStatusSubresource(objs...).Build()
+	recorder := record.NewFakeRecorder(10)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := NewSpotEvictionPoller(c, recorder, "")
+	p.now = clock.Now
+	p.getWorkloadClient = func(_ context.Context, _ client.ObjectKey) (client.Client, error) {
+		if workloadClient == nil {
+			return nil, errors.New("workload cluster is not reachable")
+		}
+		return workloadClient, nil
+	}
+	return p, recorder, clock
+}
+
+func newWorkloadClient(g *WithT, events ...*corev1.Event) client.WithWatch {
+	builder := fake.NewClientBuilder().WithScheme(setupScheme(g)).
+		WithIndex(&corev1.Event{}, "reason", func(o client.Object) []string {
+			return []string{o.(*corev1.Event).Reason}
+		})
+	for _, event := range events {
+		builder = builder.WithObjects(event)
+	}
+	return builder.Build()
+}
+
+func TestSpotEvictionPollerCountsEvictions(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	workloadClient := newWorkloadClient(g,
+		newPreemptEvent("evict-1", "aks-countspot-12345678-vmss000000", 1, now.Add(-time.Hour)),
+		newPreemptEvent("evict-2", "aks-countspot-12345678-vmss000001", 2, now.Add(-time.Hour)),
+		// Evictions of regular pools and other clusters' nodes are not counted.
+		newPreemptEvent("evict-3", "aks-countregular-12345678-vmss000000", 1, now.Add(-time.Hour)),
+		newPreemptEvent("evict-4", "aks-other-12345678-vmss000000", 1, now.Add(-time.Hour)),
+	)
+	// Events with another reason are not counted.
+	otherEvent := newPreemptEvent("other-event", "aks-countspot-12345678-vmss000000", 1, now)
+	otherEvent.Reason = "NodeNotReady"
+	g.Expect(workloadClient.Create(context.Background(), otherEvent)).To(Succeed())
+
+	p, recorder, _ := newTestSpotEvictionPoller(g, workloadClient,
+		newSpotPool("countspot", "my-cluster", infrav1.ScaleSetPrioritySpot),
+		newSpotPool("countregular", "my-cluster", "Regular"),
+	)
+
+	p.poll(context.Background())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/countspot"))).To(Equal(float64(3)))
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/countregular"))).To(BeZero())
+	// The evictions are too old to be a spike.
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+
+	// Evictions already counted are not counted again, only repeated events are.
+	p.poll(context.Background())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/countspot"))).To(Equal(float64(3)))
+
+	event := &corev1.Event{}
+	g.Expect(workloadClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "evict-1"}, event)).To(Succeed())
+	event.Count = 2
+	g.Expect(workloadClient.Update(context.Background(), event)).To(Succeed())
+	p.poll(context.Background())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/countspot"))).To(Equal(float64(4)))
+
+	pool := &infrav1.AzureManagedMachinePool{}
+	g.Expect(p.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "countspot"}, pool)).To(Succeed())
+	g.Expect(pool.Status.SpotEvictions).NotTo(BeNil())
+	g.Expect(pool.Status.SpotEvictions.Count).To(Equal(int32(4)))
+	g.Expect(pool.Status.SpotEvictions.CountedEvents).To(Equal([]infrav1.CountedEvictionEvent{
+		{UID: "evict-1", Count: 2},
+		{UID: "evict-2", Count: 2},
+	}))
+	g.Expect(pool.Status.SpotEvictions.LastEvictionTime.Time).To(BeTemporally("==", now.Add(-time.Hour)))
+	g.Expect(p.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "countregular"}, pool)).To(Succeed())
+	g.Expect(pool.Status.SpotEvictions).To(BeNil())
+}
+
+func TestSpotEvictionPollerKeepsCountsAcrossRestarts(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	workloadClient := newWorkloadClient(g,
+		newPreemptEvent("evict-1", "aks-restartspot-12345678-vmss000000", 2, now.Add(-time.Hour)),
+	)
+	p, _, _ := newTestSpotEvictionPoller(g, workloadClient, newSpotPool("restartspot", "my-cluster", infrav1.ScaleSetPrioritySpot))
+	p.poll(context.Background())
+
+	// A new poller, as after a restart of the controller, only counts the evictions not recorded in the status.
+	restarted := NewSpotEvictionPoller(p.Client, record.NewFakeRecorder(10), "")
+	restarted.getWorkloadClient = p.getWorkloadClient
+	g.Expect(workloadClient.Create(context.Background(), newPreemptEvent("evict-2", "aks-restartspot-12345678-vmss000001", 1, now))).To(Succeed())
+	restarted.poll(context.Background())
+
+	pool := &infrav1.AzureManagedMachinePool{}
+	g.Expect(p.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "restartspot"}, pool)).To(Succeed())
+	g.Expect(pool.Status.SpotEvictions.Count).To(Equal(int32(3)))
+	g.Expect(pool.Status.SpotEvictions.LastEvictionTime.Time).To(BeTemporally("==", now))
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/restartspot"))).To(Equal(float64(3)))
+
+	// Expired events are dropped from the status, the count is kept.
+	g.Expect(workloadClient.DeleteAllOf(context.Background(), &corev1.Event{}, client.InNamespace("default"))).To(Succeed())
+	restarted.poll(context.Background())
+	g.Expect(p.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "restartspot"}, pool)).To(Succeed())
+	g.Expect(pool.Status.SpotEvictions.Count).To(Equal(int32(3)))
+	g.Expect(pool.Status.SpotEvictions.CountedEvents).To(BeEmpty())
+}
+
+func TestSpotEvictionPollerDetectsSpikes(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	workloadClient := newWorkloadClient(g,
+		newPreemptEvent("evict-1", "aks-spikespot-12345678-vmss000000", 1, now.Add(-5*time.Minute)),
+		newPreemptEvent("evict-2", "aks-spikespot-12345678-vmss000001", 1, now.Add(-2*time.Minute)),
+	)
+	p, recorder, clock := newTestSpotEvictionPoller(g, workloadClient, newSpotPool("spikespot", "my-cluster", infrav1.ScaleSetPrioritySpot))
+
+	p.poll(context.Background())
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+
+	g.Expect(workloadClient.Create(context.Background(), newPreemptEvent("evict-3", "aks-spikespot-12345678-vmss000002", 1, now.Add(-time.Minute)))).To(Succeed())
+	p.poll(context.Background())
+	g.Expect(drainEvents(recorder)).To(ConsistOf("Warning SpotEvictionRateHigh 3 spot nodes of the pool were evicted in the last 10m0s, consider raising the spot max price or using other VM sizes or zones"))
+
+	// Only one event is emitted per spike window.
+	g.Expect(workloadClient.Create(context.Background(), newPreemptEvent("evict-4", "aks-spikespot-12345678-vmss000003", 1, now))).To(Succeed())
+	p.poll(context.Background())
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+
+	// Evictions outside of the window don't count toward a new spike.
+	clock.step(15 * time.Minute)
+	g.Expect(workloadClient.Create(context.Background(), newPreemptEvent("evict-5", "aks-spikespot-12345678-vmss000004", 1, clock.now))).To(Succeed())
+	p.poll(context.Background())
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/spikespot"))).To(Equal(float64(5)))
+}
+
+func TestSpotEvictionPollerUnreachableCluster(t *testing.T) {
+	g := NewWithT(t)
+	p, recorder, _ := newTestSpotEvictionPoller(g, nil, newSpotPool("unreachablespot", "my-cluster", infrav1.ScaleSetPrioritySpot))
+
+	p.poll(context.Background())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/unreachablespot"))).To(BeZero())
+	g.Expect(drainEvents(recorder)).To(BeEmpty())
+}
+
+func TestSpotEvictionPollerForgetsDeletedPools(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	workloadClient := newWorkloadClient(g, newPreemptEvent("evict-1", "aks-forgetspot-12345678-vmss000000", 1, now))
+	pool := newSpotPool("forgetspot", "my-cluster", infrav1.ScaleSetPrioritySpot)
+	p, _, _ := newTestSpotEvictionPoller(g, workloadClient, pool)
+
+	p.poll(context.Background())
+	g.Expect(testutil.ToFloat64(spotEvictionsTotal.WithLabelValues("default/forgetspot"))).To(Equal(float64(1)))
+
+	g.Expect(p.Client.Delete(context.Background(), pool)).To(Succeed())
+	p.poll(context.Background())
+	g.Expect(p.evictions).To(BeEmpty())
+	// The metric of the pool was already deleted.
+	g.Expect(spotEvictionsTotal.DeleteLabelValues("default/forgetspot")).To(BeFalse())
+}
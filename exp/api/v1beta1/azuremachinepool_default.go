@@ -19,17 +19,31 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
 	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// applicationHealthExtensionPublisher is the publisher of the application health extensions, which report the
+	// health of the instances of a scale set to Azure.
+	applicationHealthExtensionPublisher = "Microsoft.ManagedServices"
+	// applicationHealthExtensionVersion is the version of the default application health extension.
+	applicationHealthExtensionVersion = "1.0"
+	// kubeletHealthzPort and kubeletHealthzPath are the local endpoint of the kubelet's health check, which the default
+	// application health extension probes.
+	kubeletHealthzPort = "10248"
+	kubeletHealthzPath = "/healthz"
+)
+
 // SetDefaults sets the default values for an AzureMachinePool.
 func (amp *AzureMachinePool) SetDefaults(client client.Client) error {
 	var errs []error
@@ -42,6 +56,7 @@ func (amp *AzureMachinePool) SetDefaults(client client.Client) error {
 	}
 	amp.SetDiagnosticsDefaults()
 	amp.SetNetworkInterfacesDefaults()
+	amp.SetAutomaticOSUpgradeDefaults()
 
 	return kerrors.NewAggregate(errs)
 }
@@ -159,3 +174,33 @@ func (amp *AzureMachinePool) SetNetworkInterfacesDefaults() {
 		}
 	}
 }
+
+// SetAutomaticOSUpgradeDefaults adds an application health extension probing the health endpoint of the kubelet to the
+// template when the automatic OS image upgrades are enabled, unless the template already has one. Azure doesn't upgrade
+// the instances of a scale set without a health probe, and the instances of a machine pool run the kubelet rather than
+// an API server.
+func (amp *AzureMachinePool) SetAutomaticOSUpgradeDefaults() {
+	policy := amp.Spec.AutomaticOSUpgradePolicy
+	if policy == nil || !ptr.Deref(policy.EnableAutomaticOSUpgrade, false) {
+		return
+	}
+	for _, extension := range amp.Spec.Template.VMExtensions {
+		if extension.Publisher == applicationHealthExtensionPublisher && strings.HasPrefix(extension.Name, "ApplicationHealth") {
+			return
+		}
+	}
+	name := "ApplicationHealthLinux"
+	if amp.Spec.Template.OSDisk.OSType == infrav1.WindowsOS {
+		name = "ApplicationHealthWindows"
+	}
+	amp.Spec.Template.VMExtensions = append(amp.Spec.Template.VMExtensions, infrav1.VMExtension{
+		Name:      name,
+		Publisher: applicationHealthExtensionPublisher,
+		Version:   applicationHealthExtensionVersion,
+		Settings: infrav1.Tags{
+			"protocol":    "http",
+			"port":        kubeletHealthzPort,
+			"requestPath": kubeletHealthzPath,
+		},
+	})
+}
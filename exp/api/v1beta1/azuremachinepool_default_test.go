@@ -404,6 +404,80 @@ func TestAzureMachinePool_SetNetworkInterfacesDefaults(t *testing.T) {
 	}
 }
 
+func TestAzureMachinePool_SetAutomaticOSUpgradeDefaults(t *testing.T) {
+	linuxHealthExtension := infrav1.VMExtension{
+		Name:      "ApplicationHealthLinux",
+		Publisher: "Microsoft.ManagedServices",
+		Version:   "1.0",
+		Settings:  infrav1.Tags{"protocol": "http", "port": "10248", "requestPath": "/healthz"},
+	}
+	customHealthExtension := infrav1.VMExtension{
+		Name:      "ApplicationHealthLinux",
+		Publisher: "Microsoft.ManagedServices",
+		Version:   "2.0",
+		Settings:  infrav1.Tags{"protocol": "http", "port": "8080", "requestPath": "/healthz"},
+	}
+	otherExtension := infrav1.VMExtension{Name: "CustomScript", Publisher: "Microsoft.Azure.Extensions", Version: "2.1"}
+
+	testCases := []struct {
+		name       string
+		policy     *AutomaticOSUpgradePolicy
+		osType     string
+		extensions []infrav1.VMExtension
+		want       []infrav1.VMExtension
+	}{
+		{
+			name:       "automatic OS upgrades not configured",
+			extensions: []infrav1.VMExtension{otherExtension},
+			want:       []infrav1.VMExtension{otherExtension},
+		},
+		{
+			name:   "automatic OS upgrades disabled",
+			policy: &AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(false)},
+		},
+		{
+			name:       "automatic OS upgrades enabled without a health extension",
+			policy:     &AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(true)},
+			extensions: []infrav1.VMExtension{otherExtension},
+			want:       []infrav1.VMExtension{otherExtension, linuxHealthExtension},
+		},
+		{
+			name:   "automatic OS upgrades enabled for windows instances",
+			policy: &AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(true)},
+			osType: infrav1.WindowsOS,
+			want: []infrav1.VMExtension{{
+				Name:      "ApplicationHealthWindows",
+				Publisher: "Microsoft.ManagedServices",
+				Version:   "1.0",
+				Settings:  infrav1.Tags{"protocol": "http", "port": "10248", "requestPath": "/healthz"},
+			}},
+		},
+		{
+			name:       "automatic OS upgrades enabled with a health extension",
+			policy:     &AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(true)},
+			extensions: []infrav1.VMExtension{customHealthExtension},
+			want:       []infrav1.VMExtension{customHealthExtension},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					AutomaticOSUpgradePolicy: tc.policy,
+					Template: AzureMachinePoolMachineTemplate{
+						OSDisk:       infrav1.OSDisk{OSType: tc.osType},
+						VMExtensions: tc.extensions,
+					},
+				},
+			}
+			amp.SetAutomaticOSUpgradeDefaults()
+			g.Expect(amp.Spec.Template.VMExtensions).To(Equal(tc.want))
+		})
+	}
+}
+
 func createMachinePoolWithSSHPublicKey(sshPublicKey string) *AzureMachinePool {
 	return hardcodedAzureMachinePoolWithSSHKey(sshPublicKey)
 }
@@ -39,6 +39,9 @@ const (
 	NewestDeletePolicyType AzureMachinePoolDeletePolicyType = "Newest"
 	// RandomDeletePolicyType will delete machines in random order.
 	RandomDeletePolicyType AzureMachinePoolDeletePolicyType = "Random"
+
+	// DefaultInstanceMetadataLabelPrefix is the default prefix of the node labels derived from the Azure instance.
+	DefaultInstanceMetadataLabelPrefix = "instance.azure.cluster.x-k8s.io"
 )
 
 type (
@@ -58,6 +61,11 @@ type (
 		// OSDisk contains the operating system disk information for a Virtual Machine
 		OSDisk infrav1.OSDisk `json:"osDisk"`
 
+		// PatchSettings specifies the guest OS patching of the VMs in the scale set. Changes are applied to the scale
+		// set model.
+		// +optional
+		PatchSettings *infrav1.PatchSettings `json:"patchSettings,omitempty"`
+
 		// DataDisks specifies the list of data disks to be created for a Virtual Machine
 		// +optional
 		DataDisks []infrav1.DataDisk `json:"dataDisks,omitempty"`
@@ -160,6 +168,100 @@ type (
 		// OrchestrationMode specifies the orchestration mode for the Virtual Machine Scale Set
 		// +kubebuilder:default=Uniform
 		OrchestrationMode infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+
+		// InstanceMetadataLabels enables labeling the node of each instance with the zone, fault domain, instance ID,
+		// VM size and priority of the instance. When unset, the labels previously applied to the nodes are removed.
+		// +optional
+		InstanceMetadataLabels *InstanceMetadataLabels `json:"instanceMetadataLabels,omitempty"`
+
+		// SpreadAcrossAllFailureDomains places the Virtual Machine Scale Set in every availability zone of the
+		// cluster's region when the MachinePool does not list any failureDomains. It has no effect in regions
+		// without availability zones.
+		// +optional
+		SpreadAcrossAllFailureDomains *bool `json:"spreadAcrossAllFailureDomains,omitempty"`
+
+		// AutomaticRepairsPolicy configures Azure to replace the instances of the Virtual Machine Scale Set that are
+		// reported unhealthy. When unset, the automatic repairs policy of the scale set is left untouched.
+		// +optional
+		AutomaticRepairsPolicy *AutomaticRepairsPolicy `json:"automaticRepairsPolicy,omitempty"`
+
+		// EvictedInstanceRestartInterval is how often CAPZ tries to start the Spot instances of the scale set which were
+		// deallocated by an eviction, when the eviction policy of template.spotVMOptions is Deallocate. Starting an
+		// instance keeps failing while Azure has no Spot capacity for it. Defaults to 5 minutes.
+		// +optional
+		EvictedInstanceRestartInterval *metav1.Duration `json:"evictedInstanceRestartInterval,omitempty"`
+
+		// AutomaticOSUpgradePolicy configures Azure to upgrade the OS disk of the instances of a Uniform Virtual Machine
+		// Scale Set when a new version of its marketplace image is published, which requires the image version to be
+		// "latest". Azure upgrades the instances in place, in batches following strategy.rollingUpdate's
+		// maxUnhealthyInstancePercent and pauseTimeBetweenBatches, while the maxSurge and maxUnavailable of the
+		// deployment strategy only apply to the machines CAPZ replaces after a change to the AzureMachinePool. When the
+		// template has no application health extension, one probing the health endpoint of the kubelet is added so
+		// that Azure can tell whether the upgraded instances are healthy.
+		// +optional
+		AutomaticOSUpgradePolicy *AutomaticOSUpgradePolicy `json:"automaticOSUpgradePolicy,omitempty"`
+
+		// ZoneSpreadPolicy configures how the instances of the Virtual Machine Scale Set are spread across the failure
+		// domains of the MachinePool, and when the AzureMachinePool reports them as unbalanced with the
+		// ZonesUnbalanced condition.
+		// +optional
+		ZoneSpreadPolicy *ZoneSpreadPolicy `json:"zoneSpreadPolicy,omitempty"`
+	}
+
+	// ZoneSpreadPolicy configures the spreading of the instances of a Virtual Machine Scale Set across availability
+	// zones.
+	ZoneSpreadPolicy struct {
+		// ZoneBalance makes Azure keep the number of instances of each zone of the scale set within one of the others,
+		// failing the scale-outs which would break the balance instead of placing the instances in other zones. It
+		// can only be set when the scale set is placed in more than one failure domain.
+		// +optional
+		ZoneBalance *bool `json:"zoneBalance,omitempty"`
+
+		// PlatformFaultDomainCount is the number of fault domains the instances of each zone are spread across. It
+		// can't be changed once set. Flexible scale sets default to 1, or to the number of failure domains when placed
+		// in more than one, while Azure picks the default of Uniform scale sets.
+		// +kubebuilder:validation:Minimum=1
+		// +optional
+		PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+
+		// MaxSkew is the maximum difference between the numbers of instances of two zones of the scale set before the
+		// ZonesUnbalanced condition is set on the AzureMachinePool. Defaults to 1.
+		// +kubebuilder:validation:Minimum=1
+		// +optional
+		MaxSkew *int32 `json:"maxSkew,omitempty"`
+	}
+
+	// AutomaticOSUpgradePolicy configures the automatic OS image upgrades of a Virtual Machine Scale Set.
+	AutomaticOSUpgradePolicy struct {
+		// EnableAutomaticOSUpgrade turns the automatic OS image upgrades of the scale set on or off.
+		// +optional
+		EnableAutomaticOSUpgrade *bool `json:"enableAutomaticOSUpgrade,omitempty"`
+
+		// DisableAutomaticRollback keeps Azure from rolling back the OS image of the instances when an upgrade fails.
+		// +optional
+		DisableAutomaticRollback *bool `json:"disableAutomaticRollback,omitempty"`
+	}
+
+	// AutomaticRepairsPolicy configures the automatic repairs of the instances of a Virtual Machine Scale Set.
+	// Repairs only happen for instances reporting their health through the application health extension or a
+	// load balancer health probe.
+	AutomaticRepairsPolicy struct {
+		// Enabled turns the automatic repairs of the scale set on or off. Defaults to true when the policy is set.
+		// +optional
+		Enabled *bool `json:"enabled,omitempty"`
+
+		// GracePeriod is the time Azure waits after a state change of an instance before repairing it, between 10
+		// and 90 minutes with a minute granularity. Azure uses 30 minutes when unset.
+		// +optional
+		GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	}
+
+	// InstanceMetadataLabels configures the node labels derived from the Azure instance of an AzureMachinePoolMachine.
+	InstanceMetadataLabels struct {
+		// Prefix is the DNS subdomain prefixing the keys of the labels, e.g. "<prefix>/zone".
+		// +kubebuilder:default="instance.azure.cluster.x-k8s.io"
+		// +optional
+		Prefix string `json:"prefix,omitempty"`
 	}
 
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
@@ -227,6 +329,23 @@ type (
 		// +kubebuilder:validation:Enum=Random;Newest;Oldest
 		// +kubebuilder:default:=Oldest
 		DeletePolicy AzureMachinePoolDeletePolicyType `json:"deletePolicy,omitempty"`
+
+		// MaxUnhealthyInstancePercent is the maximum percentage of the instances of the Virtual Machine Scale Set
+		// which can be unhealthy, either because they are being upgraded or because they fail their health checks,
+		// before a rolling upgrade by Azure aborts. Azure uses 20% when unset. CAPZ replaces the machines itself and
+		// keeps the upgrade mode of the scale set Manual, so it only applies to the upgrades driven by Azure, like the
+		// automatic OS image upgrades. It is only supported for Uniform scale sets.
+		// +optional
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=100
+		MaxUnhealthyInstancePercent *int32 `json:"maxUnhealthyInstancePercent,omitempty"`
+
+		// PauseTimeBetweenBatches is the time a rolling upgrade by Azure waits between upgrading two batches of
+		// instances of the Virtual Machine Scale Set, with a second granularity. Azure doesn't wait when unset. Like
+		// MaxUnhealthyInstancePercent, it only applies to the upgrades driven by Azure and is only supported for
+		// Uniform scale sets.
+		// +optional
+		PauseTimeBetweenBatches *metav1.Duration `json:"pauseTimeBetweenBatches,omitempty"`
 	}
 
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool.
@@ -243,6 +362,11 @@ type (
 		// +optional
 		Instances []*AzureMachinePoolInstanceStatus `json:"instances,omitempty"`
 
+		// InstancesPerZone is the number of instances of the Virtual Machine Scale Set in each of its availability
+		// zones.
+		// +optional
+		InstancesPerZone map[string]int32 `json:"instancesPerZone,omitempty"`
+
 		// Image is the current image used in the AzureMachinePool. When the spec image is nil, this image is populated
 		// with the details of the defaulted Azure Marketplace "capi" offer.
 		// +optional
@@ -306,6 +430,10 @@ type (
 		// InfrastructureMachineKind is the kind of the infrastructure resources behind MachinePool Machines.
 		// +optional
 		InfrastructureMachineKind string `json:"infrastructureMachineKind,omitempty"`
+
+		// V1Beta2 groups the conditions of the AzureMachinePool following the Cluster API v1beta2 contract.
+		// +optional
+		V1Beta2 *infrav1.V1Beta2Status `json:"v1beta2,omitempty"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
@@ -378,6 +506,22 @@ func (amp *AzureMachinePool) SetConditions(conditions clusterv1.Conditions) {
 	amp.Status.Conditions = conditions
 }
 
+// GetV1Beta2Conditions returns the list of v1beta2 conditions for an AzureMachinePool API object.
+func (amp *AzureMachinePool) GetV1Beta2Conditions() []metav1.Condition {
+	if amp.Status.V1Beta2 == nil {
+		return nil
+	}
+	return amp.Status.V1Beta2.Conditions
+}
+
+// SetV1Beta2Conditions will set the given v1beta2 conditions on an AzureMachinePool object.
+func (amp *AzureMachinePool) SetV1Beta2Conditions(conditions []metav1.Condition) {
+	if amp.Status.V1Beta2 == nil {
+		amp.Status.V1Beta2 = &infrav1.V1Beta2Status{}
+	}
+	amp.Status.V1Beta2.Conditions = conditions
+}
+
 // GetFutures returns the list of long running operation states for an AzureMachinePool API object.
 func (amp *AzureMachinePool) GetFutures() infrav1.Futures {
 	return amp.Status.LongRunningOperationStates
@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/blang/semver"
@@ -28,10 +32,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	azureutil "sigs.k8s.io/cluster-api-provider-azure/util/azure"
+	webhookutils "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -105,11 +113,19 @@ func (amp *AzureMachinePool) Validate(old runtime.Object, client client.Client)
 		amp.ValidateSSHKey,
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateDiagnostics,
-		amp.ValidateOrchestrationMode(client),
+		amp.ValidateOrchestrationMode(old, client),
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
 		amp.ValidateSystemAssignedIdentityRole,
 		amp.ValidateNetwork,
+		amp.ValidateDiskControllerType(old),
+		amp.ValidatePatchSettings,
+		amp.ValidateInstanceMetadataLabels,
+		amp.ValidateAutomaticRepairsPolicy,
+		amp.ValidateEvictedInstanceRestartInterval,
+		amp.ValidateAutomaticOSUpgradePolicy,
+		amp.ValidateFailureDomains(client),
+		amp.ValidateZoneSpreadPolicy(old, client),
 	}
 
 	var errs []error
@@ -187,18 +203,61 @@ func (amp *AzureMachinePool) ValidateStrategy() func() error {
 	return func() error {
 		if amp.Spec.Strategy.Type == RollingUpdateAzureMachinePoolDeploymentStrategyType && amp.Spec.Strategy.RollingUpdate != nil {
 			rollingUpdateStrategy := amp.Spec.Strategy.RollingUpdate
-			maxSurge := rollingUpdateStrategy.MaxSurge
-			maxUnavailable := rollingUpdateStrategy.MaxUnavailable
-			if maxSurge.Type == intstr.Int && maxSurge.IntVal == 0 &&
-				maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal == 0 {
+			if isZeroIntOrPercent(rollingUpdateStrategy.MaxSurge, 1) && isZeroIntOrPercent(rollingUpdateStrategy.MaxUnavailable, 0) {
 				return errors.New("rolling update strategy MaxUnavailable must not be 0 if MaxSurge is 0")
 			}
+			fldPath := field.NewPath("spec", "strategy", "rollingUpdate")
+			if err := validateMaxSurge(rollingUpdateStrategy.MaxSurge, fldPath.Child("maxSurge")); err != nil {
+				return err
+			}
+			if percent := rollingUpdateStrategy.MaxUnhealthyInstancePercent; percent != nil && (*percent < 5 || *percent > 100) {
+				return field.Invalid(fldPath.Child("maxUnhealthyInstancePercent"), *percent, "value should be in between 5 and 100")
+			}
+			if pause := rollingUpdateStrategy.PauseTimeBetweenBatches; pause != nil && pause.Duration < 0 {
+				return field.Invalid(fldPath.Child("pauseTimeBetweenBatches"), pause.String(), "value must not be negative")
+			}
+			if (rollingUpdateStrategy.MaxUnhealthyInstancePercent != nil || rollingUpdateStrategy.PauseTimeBetweenBatches != nil) &&
+				orchestrationModeOrDefault(amp.Spec.OrchestrationMode) != infrav1.UniformOrchestrationMode {
+				return field.Forbidden(fldPath, "maxUnhealthyInstancePercent and pauseTimeBetweenBatches are only supported for Uniform scale sets")
+			}
 		}
 
 		return nil
 	}
 }
 
+// validateMaxSurge validates that a max surge is a non-negative number of machines or a percentage of at most 100%.
+func validateMaxSurge(v *intstr.IntOrString, fldPath *field.Path) error {
+	if v == nil {
+		return nil
+	}
+	if v.Type == intstr.Int {
+		if v.IntVal < 0 {
+			return field.Invalid(fldPath, v.IntVal, "value must not be negative")
+		}
+		return nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(v.StrVal, "%")))
+	if err != nil || !strings.HasSuffix(v.StrVal, "%") {
+		return field.Invalid(fldPath, v.StrVal, "value should be a number of machines or a percentage, e.g. 25%")
+	}
+	if percent < 0 || percent > 100 {
+		return field.Invalid(fldPath, v.StrVal, "percentage should be in between 0% and 100%")
+	}
+	return nil
+}
+
+// isZeroIntOrPercent returns true if v is 0 or 0%, or if it is nil and defaultValue is 0.
+func isZeroIntOrPercent(v *intstr.IntOrString, defaultValue int) bool {
+	if v == nil {
+		return defaultValue == 0
+	}
+	if v.Type == intstr.Int {
+		return v.IntVal == 0
+	}
+	return strings.TrimSpace(strings.TrimSuffix(v.StrVal, "%")) == "0"
+}
+
 // ValidateSystemAssignedIdentity validates system-assigned identity role.
 func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object) func() error {
 	return func() error {
@@ -228,6 +287,122 @@ func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object)
 	}
 }
 
+// ValidateDiskControllerType validates the disk controller type against the image and the VM size, and that it isn't
+// changed after the AzureMachinePool is created.
+func (amp *AzureMachinePool) ValidateDiskControllerType(old runtime.Object) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "template", "osDisk", "diskControllerType")
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if err := webhookutils.ValidateImmutable(
+				fldPath,
+				oldMachinePool.Spec.Template.OSDisk.DiskControllerType,
+				amp.Spec.Template.OSDisk.DiskControllerType); err != nil {
+				return err
+			}
+		}
+
+		if errs := infrav1.ValidateDiskControllerType(amp.Spec.Template.OSDisk.DiskControllerType, amp.Spec.Template.Image, amp.Spec.Template.VMSize, fldPath); len(errs) > 0 {
+			return kerrors.NewAggregate(errs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidatePatchSettings validates the guest OS patch settings of an AzureMachinePool. Azure only patches the instances
+// of Flexible scale sets with the AutomaticByPlatform patch mode.
+func (amp *AzureMachinePool) ValidatePatchSettings() error {
+	fldPath := field.NewPath("spec", "template", "patchSettings")
+	errs := infrav1.ValidatePatchSettings(amp.Spec.Template.PatchSettings, amp.Spec.Template.OSDisk.OSType, amp.Spec.Template.VMExtensions, fldPath)
+	if patchSettings := amp.Spec.Template.PatchSettings; patchSettings != nil && patchSettings.PatchMode == infrav1.PatchModeAutomaticByPlatform &&
+		orchestrationModeOrDefault(amp.Spec.OrchestrationMode) == infrav1.UniformOrchestrationMode {
+		errs = append(errs, field.Forbidden(fldPath.Child("patchMode"),
+			fmt.Sprintf("the %s patch mode is only supported for Flexible scale sets", infrav1.PatchModeAutomaticByPlatform)))
+	}
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// ValidateInstanceMetadataLabels validates the prefix of the node labels derived from the Azure instance.
+func (amp *AzureMachinePool) ValidateInstanceMetadataLabels() error {
+	labels := amp.Spec.InstanceMetadataLabels
+	if labels == nil || labels.Prefix == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(labels.Prefix); len(errs) > 0 {
+		return field.Invalid(field.NewPath("spec", "instanceMetadataLabels", "prefix"), labels.Prefix, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateAutomaticRepairsPolicy validates the grace period of the automatic repairs to be between 10 and 90 minutes.
+func (amp *AzureMachinePool) ValidateAutomaticRepairsPolicy() error {
+	policy := amp.Spec.AutomaticRepairsPolicy
+	if policy == nil || policy.GracePeriod == nil {
+		return nil
+	}
+	gracePeriod := policy.GracePeriod.Duration
+	fldPath := field.NewPath("spec", "automaticRepairsPolicy", "gracePeriod")
+	if gracePeriod < 10*time.Minute || gracePeriod > 90*time.Minute {
+		return field.Invalid(fldPath, policy.GracePeriod.String(), "value should be in between 10m and 90m")
+	}
+	if gracePeriod%time.Minute != 0 {
+		return field.Invalid(fldPath, policy.GracePeriod.String(), "value should be a whole number of minutes")
+	}
+	return nil
+}
+
+// ValidateEvictedInstanceRestartInterval validates that the restart interval of evicted instances is only set for Spot
+// instances deallocated on eviction, and is at least a minute.
+func (amp *AzureMachinePool) ValidateEvictedInstanceRestartInterval() error {
+	interval := amp.Spec.EvictedInstanceRestartInterval
+	if interval == nil {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "evictedInstanceRestartInterval")
+	spot := amp.Spec.Template.SpotVMOptions
+	if spot == nil || ptr.Deref(spot.EvictionPolicy, infrav1.SpotEvictionPolicyDeallocate) != infrav1.SpotEvictionPolicyDeallocate {
+		return field.Forbidden(fldPath, "can only be set for Spot instances with the Deallocate eviction policy")
+	}
+	if interval.Duration < time.Minute {
+		return field.Invalid(fldPath, interval.String(), "value should be at least 1m")
+	}
+	return nil
+}
+
+// ValidateAutomaticOSUpgradePolicy validates that the automatic OS image upgrades are only enabled for Uniform scale
+// sets using a marketplace image at its latest version, as Azure only upgrades instances of platform images.
+func (amp *AzureMachinePool) ValidateAutomaticOSUpgradePolicy() error {
+	policy := amp.Spec.AutomaticOSUpgradePolicy
+	if policy == nil || !ptr.Deref(policy.EnableAutomaticOSUpgrade, false) {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "automaticOSUpgradePolicy", "enableAutomaticOSUpgrade")
+	if orchestrationModeOrDefault(amp.Spec.OrchestrationMode) != infrav1.UniformOrchestrationMode {
+		return field.Forbidden(fldPath, "automatic OS image upgrades are only supported for Uniform scale sets")
+	}
+	image := amp.Spec.Template.Image
+	switch {
+	case image == nil:
+		return field.Forbidden(fldPath, "automatic OS image upgrades require a marketplace image to be set in template.image")
+	case image.SharedGallery != nil:
+		return field.Forbidden(fldPath, "automatic OS image upgrades are not supported for Shared Image Gallery images")
+	case image.ID != nil || (image.ComputeGallery != nil && image.ComputeGallery.Plan == nil):
+		return field.Forbidden(fldPath, "automatic OS image upgrades are not supported for custom images without a plan")
+	case image.Marketplace != nil && image.Marketplace.Version != "latest":
+		return field.Invalid(field.NewPath("spec", "template", "image", "marketplace", "version"), image.Marketplace.Version,
+			"must be latest when automatic OS image upgrades are enabled")
+	}
+	return nil
+}
+
 // ValidateSystemAssignedIdentityRole validates the scope and roleDefinitionID for the system-assigned identity.
 func (amp *AzureMachinePool) ValidateSystemAssignedIdentityRole() error {
 	var allErrs field.ErrorList
@@ -294,9 +469,25 @@ func (amp *AzureMachinePool) ValidateDiagnostics() error {
 	return nil
 }
 
-// ValidateOrchestrationMode validates requirements for the VMSS orchestration mode.
-func (amp *AzureMachinePool) ValidateOrchestrationMode(c client.Client) func() error {
+// ValidateOrchestrationMode validates requirements for the VMSS orchestration mode, which can't be changed once the
+// scale set is created. An unset mode is the same as Uniform, the mode of the scale sets created before the field was
+// defaulted.
+func (amp *AzureMachinePool) ValidateOrchestrationMode(old runtime.Object, c client.Client) func() error {
 	return func() error {
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			if err := webhookutils.ValidateImmutable(
+				field.NewPath("spec", "orchestrationMode"),
+				orchestrationModeOrDefault(oldMachinePool.Spec.OrchestrationMode),
+				orchestrationModeOrDefault(amp.Spec.OrchestrationMode)); err != nil {
+				return err
+			}
+		}
+
 		// Only Flexible orchestration mode requires validation.
 		if amp.Spec.OrchestrationMode == infrav1.OrchestrationModeType(armcompute.OrchestrationModeFlexible) {
 			parent, err := azureutil.FindParentMachinePoolWithRetry(amp.Name, c, 5)
@@ -319,3 +510,111 @@ func (amp *AzureMachinePool) ValidateOrchestrationMode(c client.Client) func() e
 		return nil
 	}
 }
+
+// orchestrationModeOrDefault returns mode, or Uniform if mode is unset.
+func orchestrationModeOrDefault(mode infrav1.OrchestrationModeType) infrav1.OrchestrationModeType {
+	if mode == "" {
+		return infrav1.UniformOrchestrationMode
+	}
+	return mode
+}
+
+// ValidateFailureDomains validates the failure domains of the parent MachinePool against the failure domains
+// discovered for the cluster's region. The check is skipped until the parent MachinePool exists and the cluster's
+// infrastructure is ready.
+func (amp *AzureMachinePool) ValidateFailureDomains(c client.Client) func() error {
+	return func() error {
+		if c == nil {
+			return nil
+		}
+		parent, err := azureutil.FindParentMachinePool(amp.Name, c)
+		if err != nil || len(parent.Spec.FailureDomains) == 0 {
+			return nil
+		}
+
+		cluster := &clusterv1.Cluster{}
+		key := client.ObjectKey{Namespace: parent.Namespace, Name: parent.Spec.ClusterName}
+		if err := c.Get(context.Background(), key, cluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get Cluster")
+		}
+		if !cluster.Status.InfrastructureReady {
+			return nil
+		}
+
+		validFailureDomains := make([]string, 0, len(cluster.Status.FailureDomains))
+		for id := range cluster.Status.FailureDomains {
+			validFailureDomains = append(validFailureDomains, id)
+		}
+		sort.Strings(validFailureDomains)
+
+		for _, fd := range parent.Spec.FailureDomains {
+			if _, ok := cluster.Status.FailureDomains[fd]; ok {
+				continue
+			}
+			if len(validFailureDomains) == 0 {
+				return errors.Errorf("failure domain %q of MachinePool %s is not available: the region of cluster %s has no availability zones, remove failureDomains", fd, parent.Name, cluster.Name)
+			}
+			return errors.Errorf("failure domain %q of MachinePool %s is not available in the region of cluster %s, valid failure domains are: %s", fd, parent.Name, cluster.Name, strings.Join(validFailureDomains, ", "))
+		}
+
+		return nil
+	}
+}
+
+// ValidateZoneSpreadPolicy validates the zone spread policy of an AzureMachinePool. The platform fault domain count
+// can't be changed once set, and zone balancing requires the scale set to be placed in more than one failure domain,
+// which is only checked once the parent MachinePool exists.
+func (amp *AzureMachinePool) ValidateZoneSpreadPolicy(old runtime.Object, c client.Client) func() error {
+	return func() error {
+		fldPath := field.NewPath("spec", "zoneSpreadPolicy")
+		policy := amp.Spec.ZoneSpreadPolicy
+		if old != nil {
+			oldMachinePool, ok := old.(*AzureMachinePool)
+			if !ok {
+				return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+					"AzureMachinePool", reflect.TypeOf(old))
+			}
+			var oldCount, newCount *int32
+			if oldMachinePool.Spec.ZoneSpreadPolicy != nil {
+				oldCount = oldMachinePool.Spec.ZoneSpreadPolicy.PlatformFaultDomainCount
+			}
+			if policy != nil {
+				newCount = policy.PlatformFaultDomainCount
+			}
+			if err := webhookutils.ValidateImmutable(fldPath.Child("platformFaultDomainCount"), oldCount, newCount); err != nil {
+				return err
+			}
+		}
+
+		if policy == nil || policy.ZoneBalance == nil || c == nil {
+			return nil
+		}
+		parent, err := azureutil.FindParentMachinePool(amp.Name, c)
+		if err != nil {
+			return nil
+		}
+		failureDomains := len(parent.Spec.FailureDomains)
+		if failureDomains == 0 && ptr.Deref(amp.Spec.SpreadAcrossAllFailureDomains, false) {
+			cluster := &clusterv1.Cluster{}
+			key := client.ObjectKey{Namespace: parent.Namespace, Name: parent.Spec.ClusterName}
+			if err := c.Get(context.Background(), key, cluster); err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return errors.Wrap(err, "failed to get Cluster")
+			}
+			if !cluster.Status.InfrastructureReady {
+				return nil
+			}
+			failureDomains = len(cluster.Status.FailureDomains)
+		}
+		if failureDomains < 2 {
+			return field.Forbidden(fldPath.Child("zoneBalance"),
+				fmt.Sprintf("zone balancing requires more than one failure domain, MachinePool %s has %d", parent.Name, failureDomains))
+		}
+		return nil
+	}
+}
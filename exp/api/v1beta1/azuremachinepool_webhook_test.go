@@ -23,6 +23,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	guuid "github.com/google/uuid"
@@ -31,6 +32,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -41,6 +43,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var (
@@ -93,6 +96,158 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			amp:     createMachinePoolWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0", ptr.To(10)),
 			wantErr: false,
 		},
+		{
+			name:    "azuremachinepool with NVMe disk controller type and generation 2 image",
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with NVMe disk controller type and generation 1 image",
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen1", "Standard_D2ds_v6"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with NVMe disk controller type and VM size older than v5",
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2s_v3"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with AutomaticByPlatform patch mode",
+			amp:     createMachinePoolWithPatchSettings(&infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByPlatform, RebootSetting: infrav1.PatchRebootSettingIfRequired}, infrav1.FlexibleOrchestrationMode),
+			version: "v1.26.0",
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with AutomaticByPlatform patch mode and Uniform orchestration mode",
+			amp:     createMachinePoolWithPatchSettings(&infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByPlatform}, infrav1.UniformOrchestrationMode),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with Windows-only patch mode on Linux",
+			amp:     createMachinePoolWithPatchSettings(&infrav1.PatchSettings{PatchMode: infrav1.PatchModeAutomaticByOS}, infrav1.FlexibleOrchestrationMode),
+			version: "v1.26.0",
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with a valid instance metadata label prefix",
+			amp:     createMachinePoolWithInstanceMetadataLabels(&InstanceMetadataLabels{Prefix: "topology.example.com"}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an invalid instance metadata label prefix",
+			amp:     createMachinePoolWithInstanceMetadataLabels(&InstanceMetadataLabels{Prefix: "Example.com/"}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with automatic repairs disabled",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(&AutomaticRepairsPolicy{Enabled: ptr.To(false)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a valid automatic repairs grace period",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(&AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: &metav1.Duration{Duration: 45 * time.Minute}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an automatic repairs grace period too short",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(&AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: &metav1.Duration{Duration: 5 * time.Minute}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an automatic repairs grace period too long",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(&AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: &metav1.Duration{Duration: 2 * time.Hour}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an automatic repairs grace period not in minutes",
+			amp:     createMachinePoolWithAutomaticRepairsPolicy(&AutomaticRepairsPolicy{Enabled: ptr.To(true), GracePeriod: &metav1.Duration{Duration: 30*time.Minute + 30*time.Second}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an evicted instance restart interval for spot instances deallocated on eviction",
+			amp:     createMachinePoolWithEvictedInstanceRestartInterval(&infrav1.SpotVMOptions{}, &metav1.Duration{Duration: 10 * time.Minute}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with an evicted instance restart interval for spot instances deleted on eviction",
+			amp:     createMachinePoolWithEvictedInstanceRestartInterval(&infrav1.SpotVMOptions{EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDelete)}, &metav1.Duration{Duration: 10 * time.Minute}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an evicted instance restart interval for regular instances",
+			amp:     createMachinePoolWithEvictedInstanceRestartInterval(nil, &metav1.Duration{Duration: 10 * time.Minute}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with an evicted instance restart interval too short",
+			amp:     createMachinePoolWithEvictedInstanceRestartInterval(&infrav1.SpotVMOptions{EvictionPolicy: ptr.To(infrav1.SpotEvictionPolicyDeallocate)}, &metav1.Duration{Duration: 30 * time.Second}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with automatic OS upgrades of the latest marketplace image",
+			amp:     createMachinePoolWithAutomaticOSUpgrade(createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10))),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with automatic OS upgrades of a pinned marketplace image version",
+			amp:     createMachinePoolWithAutomaticOSUpgrade(createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "128.0.20240101", ptr.To(10))),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with automatic OS upgrades of a shared image gallery image",
+			amp:     createMachinePoolWithAutomaticOSUpgrade(createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", ptr.To(10))),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with automatic OS upgrades of an image by ID",
+			amp:     createMachinePoolWithAutomaticOSUpgrade(createMachinePoolWithImageByID("/subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/images/my-image", ptr.To(10))),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrades of a compute gallery image without a plan",
+			amp: createMachinePoolWithAutomaticOSUpgrade(&AzureMachinePool{Spec: AzureMachinePoolSpec{Template: AzureMachinePoolMachineTemplate{
+				SSHPublicKey: validSSHPublicKey,
+				Image:        &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{Gallery: "gallery", Name: "image", Version: "latest"}},
+			}}}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrades of a compute gallery image with a plan",
+			amp: createMachinePoolWithAutomaticOSUpgrade(&AzureMachinePool{Spec: AzureMachinePoolSpec{Template: AzureMachinePoolMachineTemplate{
+				SSHPublicKey: validSSHPublicKey,
+				Image: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery: "gallery",
+					Name:    "image",
+					Version: "latest",
+					Plan:    &infrav1.ImagePlan{Publisher: "publisher", Offer: "offer", SKU: "sku"},
+				}},
+			}}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with automatic OS upgrades of the default image",
+			amp:     createMachinePoolWithAutomaticOSUpgrade(&AzureMachinePool{Spec: AzureMachinePoolSpec{Template: AzureMachinePoolMachineTemplate{SSHPublicKey: validSSHPublicKey}}}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrades of a flexible scale set",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithAutomaticOSUpgrade(createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10)))
+				amp.Spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+				return amp
+			}(),
+			version: "v1.26.0",
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrades disabled for a shared image gallery image",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", ptr.To(10))
+				amp.Spec.AutomaticOSUpgradePolicy = &AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: ptr.To(false)}
+				return amp
+			}(),
+			wantErr: false,
+		},
 		{
 			name:    "azuremachinepool with marketplace image - missing publisher",
 			amp:     createMachinePoolWithMarketPlaceImage("", "OFFER1234", "SKU1234", "1.0.0", ptr.To(10)),
@@ -192,6 +347,46 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			name: "azuremachinepool with invalid percentage MaxSurge and MaxUnavailable rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       ptr.To(intstr.FromString("0%")),
+					MaxUnavailable: &zero,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with invalid MaxSurge and unset MaxUnavailable rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge: &zero,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with unset MaxSurge and MaxUnavailable rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type:          RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with valid percentage MaxSurge rolling upgrade configuration",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       ptr.To(intstr.FromString("25%")),
+					MaxUnavailable: &zero,
+				},
+			}),
+			wantErr: false,
+		},
 		{
 			name: "azuremachinepool with valid MaxSurge and MaxUnavailable rolling upgrade configuration",
 			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
@@ -203,6 +398,83 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "azuremachinepool with MaxSurge greater than 100%",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge: ptr.To(intstr.FromString("150%")),
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with negative MaxSurge",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge:       ptr.To(intstr.FromInt32(-1)),
+					MaxUnavailable: &one,
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with 100% MaxSurge",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxSurge: ptr.To(intstr.FromString("100%")),
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with valid rolling upgrade policy",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnhealthyInstancePercent: ptr.To[int32](50),
+					PauseTimeBetweenBatches:     &metav1.Duration{Duration: 30 * time.Second},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade policy in Flexible mode",
+			amp: func() *AzureMachinePool {
+				amp := createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+					Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+					RollingUpdate: &MachineRollingUpdateDeployment{
+						PauseTimeBetweenBatches: &metav1.Duration{Duration: 30 * time.Second},
+					},
+				})
+				amp.Spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+				return amp
+			}(),
+			version: "v1.26.0",
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with MaxUnhealthyInstancePercent out of range",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnhealthyInstancePercent: ptr.To[int32](2),
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with negative PauseTimeBetweenBatches",
+			amp: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{
+				Type: RollingUpdateAzureMachinePoolDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					PauseTimeBetweenBatches: &metav1.Duration{Duration: -time.Second},
+				},
+			}),
+			wantErr: true,
+		},
 		{
 			name:    "azuremachinepool with valid legacy network configuration",
 			amp:     createMachinePoolWithNetworkConfig("testSubnet", []infrav1.NetworkInterface{}),
@@ -381,6 +653,42 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.NetworkInterface{{SubnetName: "testSubnet2"}}),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachinepool with disk controller type unchanged",
+			oldAMP:  createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with disk controller type changed",
+			oldAMP:  createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeSCSI, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with patch settings changed",
+			oldAMP:  createMachinePoolWithPatchSettings(nil, infrav1.UniformOrchestrationMode),
+			amp:     createMachinePoolWithPatchSettings(&infrav1.PatchSettings{PatchMode: infrav1.PatchModeImageDefault}, infrav1.UniformOrchestrationMode),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with disk controller type set",
+			oldAMP:  createMachinePoolWithDiskControllerType("", "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			amp:     createMachinePoolWithDiskControllerType(infrav1.DiskControllerTypeNVMe, "ubuntu-2204-gen2", "Standard_D2ds_v6"),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with orchestration mode changed",
+			oldAMP:  createMachinePoolWithOrchestrationMode(armcompute.OrchestrationModeUniform),
+			amp:     createMachinePoolWithOrchestrationMode(armcompute.OrchestrationModeFlexible),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with orchestration mode defaulted to Uniform",
+			oldAMP:  createMachinePoolWithOrchestrationMode(""),
+			amp:     createMachinePoolWithOrchestrationMode(armcompute.OrchestrationModeUniform),
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -629,6 +937,287 @@ func createMachinePoolWithStrategy(strategy AzureMachinePoolDeploymentStrategy)
 	}
 }
 
+func createMachinePoolWithDiskControllerType(diskControllerType infrav1.DiskControllerType, imageSKU, vmSize string) *AzureMachinePool {
+	amp := createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", imageSKU, "latest", ptr.To(10))
+	amp.Spec.Template.VMSize = vmSize
+	amp.Spec.Template.OSDisk.DiskControllerType = diskControllerType
+	return amp
+}
+
+func createMachinePoolWithPatchSettings(patchSettings *infrav1.PatchSettings, mode infrav1.OrchestrationModeType) *AzureMachinePool {
+	amp := createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10))
+	amp.Spec.OrchestrationMode = mode
+	amp.Spec.Template.OSDisk.OSType = infrav1.LinuxOS
+	amp.Spec.Template.PatchSettings = patchSettings
+	return amp
+}
+
+func createMachinePoolWithInstanceMetadataLabels(labels *InstanceMetadataLabels) *AzureMachinePool {
+	amp := createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10))
+	amp.Spec.InstanceMetadataLabels = labels
+	return amp
+}
+
+func createMachinePoolWithAutomaticRepairsPolicy(policy *AutomaticRepairsPolicy) *AzureMachinePool {
+	amp := createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10))
+	amp.Spec.AutomaticRepairsPolicy = policy
+	return amp
+}
+
+func createMachinePoolWithEvictedInstanceRestartInterval(spot *infrav1.SpotVMOptions, interval *metav1.Duration) *AzureMachinePool {
+	amp := createMachinePoolWithMarketPlaceImage("cncf-upstream", "capi", "ubuntu-2204-gen2", "latest", ptr.To(10))
+	amp.Spec.Template.SpotVMOptions = spot
+	amp.Spec.EvictedInstanceRestartInterval = interval
+	return amp
+}
+
+func createMachinePoolWithAutomaticOSUpgrade(amp *AzureMachinePool) *AzureMachinePool {
+	amp.Spec.AutomaticOSUpgradePolicy = &AutomaticOSUpgradePolicy{
+		EnableAutomaticOSUpgrade: ptr.To(true),
+		DisableAutomaticRollback: ptr.To(false),
+	}
+	return amp
+}
+
+// regionFailureDomains are the failure domains discovered for a zonal and a zoneless region.
+var regionFailureDomains = map[string]clusterv1.FailureDomains{
+	"eastus": {
+		"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+	},
+	"westcentralus": {},
+}
+
+func TestAzureMachinePool_ValidateFailureDomains(t *testing.T) {
+	tests := []struct {
+		name                string
+		region              string
+		infraNotReady       bool
+		noCluster           bool
+		noMachinePool       bool
+		failureDomains      []string
+		wantErrMsgSubstring string
+	}{
+		{
+			name:           "failure domains available in the region",
+			region:         "eastus",
+			failureDomains: []string{"1", "3"},
+		},
+		{
+			name:                "failure domain not available in the region",
+			region:              "eastus",
+			failureDomains:      []string{"1", "4"},
+			wantErrMsgSubstring: `failure domain "4" of MachinePool mp is not available in the region of cluster test-cluster, valid failure domains are: 1, 2, 3`,
+		},
+		{
+			name:                "failure domains in a zoneless region",
+			region:              "westcentralus",
+			failureDomains:      []string{"1", "2", "3"},
+			wantErrMsgSubstring: "the region of cluster test-cluster has no availability zones",
+		},
+		{
+			name:   "no failure domains in a zoneless region",
+			region: "westcentralus",
+		},
+		{
+			name:           "cluster infrastructure not ready",
+			region:         "westcentralus",
+			infraNotReady:  true,
+			failureDomains: []string{"1"},
+		},
+		{
+			name:           "cluster not found",
+			region:         "westcentralus",
+			noCluster:      true,
+			failureDomains: []string{"1"},
+		},
+		{
+			name:           "parent MachinePool not found",
+			region:         "westcentralus",
+			noMachinePool:  true,
+			failureDomains: []string{"1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = expv1.AddToScheme(scheme)
+
+			amp := &AzureMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "amp", Namespace: "default"}}
+			var objs []runtime.Object
+			if !tc.noMachinePool {
+				objs = append(objs, &expv1.MachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "mp", Namespace: "default"},
+					Spec: expv1.MachinePoolSpec{
+						ClusterName:    "test-cluster",
+						FailureDomains: tc.failureDomains,
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								InfrastructureRef: corev1.ObjectReference{Name: amp.Name},
+							},
+						},
+					},
+				})
+			}
+			if !tc.noCluster {
+				objs = append(objs, &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+					Status: clusterv1.ClusterStatus{
+						InfrastructureReady: !tc.infraNotReady,
+						FailureDomains:      regionFailureDomains[tc.region],
+					},
+				})
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+
+			err := amp.ValidateFailureDomains(c)()
+			if tc.wantErrMsgSubstring != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErrMsgSubstring))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateZoneSpreadPolicy(t *testing.T) {
+	tests := []struct {
+		name                string
+		region              string
+		noMachinePool       bool
+		failureDomains      []string
+		spreadAcrossAll     bool
+		policy              *ZoneSpreadPolicy
+		oldPolicy           *ZoneSpreadPolicy
+		update              bool
+		wantErrMsgSubstring string
+	}{
+		{
+			name:           "zone balance across several failure domains",
+			region:         "eastus",
+			failureDomains: []string{"1", "2"},
+			policy:         &ZoneSpreadPolicy{ZoneBalance: ptr.To(true)},
+		},
+		{
+			name:                "zone balance in a single failure domain",
+			region:              "eastus",
+			failureDomains:      []string{"1"},
+			policy:              &ZoneSpreadPolicy{ZoneBalance: ptr.To(true)},
+			wantErrMsgSubstring: "zone balancing requires more than one failure domain, MachinePool mp has 1",
+		},
+		{
+			name:                "zone balance without failure domains",
+			region:              "eastus",
+			policy:              &ZoneSpreadPolicy{ZoneBalance: ptr.To(false)},
+			wantErrMsgSubstring: "zone balancing requires more than one failure domain, MachinePool mp has 0",
+		},
+		{
+			name:            "zone balance spread across all the failure domains of the region",
+			region:          "eastus",
+			spreadAcrossAll: true,
+			policy:          &ZoneSpreadPolicy{ZoneBalance: ptr.To(true)},
+		},
+		{
+			name:                "zone balance spread across all the failure domains of a zoneless region",
+			region:              "westcentralus",
+			spreadAcrossAll:     true,
+			policy:              &ZoneSpreadPolicy{ZoneBalance: ptr.To(true)},
+			wantErrMsgSubstring: "zone balancing requires more than one failure domain",
+		},
+		{
+			name:          "parent MachinePool not found",
+			region:        "eastus",
+			noMachinePool: true,
+			policy:        &ZoneSpreadPolicy{ZoneBalance: ptr.To(true)},
+		},
+		{
+			name:      "platform fault domain count unchanged",
+			region:    "eastus",
+			policy:    &ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](2)},
+			oldPolicy: &ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](2)},
+			update:    true,
+		},
+		{
+			name:                "platform fault domain count changed",
+			region:              "eastus",
+			policy:              &ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](3)},
+			oldPolicy:           &ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](2)},
+			update:              true,
+			wantErrMsgSubstring: "spec.zoneSpreadPolicy.platformFaultDomainCount",
+		},
+		{
+			name:                "platform fault domain count set on an existing machine pool",
+			region:              "eastus",
+			policy:              &ZoneSpreadPolicy{PlatformFaultDomainCount: ptr.To[int32](2)},
+			update:              true,
+			wantErrMsgSubstring: "spec.zoneSpreadPolicy.platformFaultDomainCount",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = expv1.AddToScheme(scheme)
+
+			amp := &AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "amp", Namespace: "default"},
+				Spec: AzureMachinePoolSpec{
+					SpreadAcrossAllFailureDomains: ptr.To(tc.spreadAcrossAll),
+					ZoneSpreadPolicy:              tc.policy,
+				},
+			}
+			objs := []runtime.Object{
+				&clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+					Status: clusterv1.ClusterStatus{
+						InfrastructureReady: true,
+						FailureDomains:      regionFailureDomains[tc.region],
+					},
+				},
+			}
+			if !tc.noMachinePool {
+				objs = append(objs, &expv1.MachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "mp", Namespace: "default"},
+					Spec: expv1.MachinePoolSpec{
+						ClusterName:    "test-cluster",
+						FailureDomains: tc.failureDomains,
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								InfrastructureRef: corev1.ObjectReference{Name: amp.Name},
+							},
+						},
+					},
+				})
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+
+			var old runtime.Object
+			if tc.update {
+				oldAMP := amp.DeepCopy()
+				oldAMP.Spec.ZoneSpreadPolicy = tc.oldPolicy
+				old = oldAMP
+			}
+
+			err := amp.ValidateZoneSpreadPolicy(old, c)()
+			if tc.wantErrMsgSubstring != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErrMsgSubstring))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func createMachinePoolWithOrchestrationMode(mode armcompute.OrchestrationMode) *AzureMachinePool {
 	return &AzureMachinePool{
 		Spec: AzureMachinePoolSpec{
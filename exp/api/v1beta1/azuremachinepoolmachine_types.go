@@ -30,6 +30,15 @@ const (
 
 	// AzureMachinePoolMachineKind indicates the kind of an AzureMachinePoolMachine.
 	AzureMachinePoolMachineKind = "AzureMachinePoolMachine"
+
+	// ProtectFromScaleInAnnotation can be set to "true" on an AzureMachinePoolMachine, or on its Machine, to protect
+	// the instance of a Uniform scale set from being removed when the scale set scales in. The protection is removed
+	// when the annotation is removed or when the machine is deleted.
+	ProtectFromScaleInAnnotation = "infrastructure.cluster.x-k8s.io/protect-from-scale-in"
+
+	// KeepDeallocatedAnnotation can be set to "true" on an AzureMachinePoolMachine to keep its Spot instance
+	// deallocated, even when it looks like it was deallocated by an eviction.
+	KeepDeallocatedAnnotation = "infrastructure.cluster.x-k8s.io/keep-deallocated"
 )
 
 type (
@@ -97,6 +106,10 @@ type (
 		// +optional
 		LatestModelApplied bool `json:"latestModelApplied,omitempty"`
 
+		// LastEvictionTime is when CAPZ last found the Spot instance deallocated by an eviction.
+		// +optional
+		LastEvictionTime *metav1.Time `json:"lastEvictionTime,omitempty"`
+
 		// Ready is true when the provider resource is ready.
 		// +optional
 		Ready bool `json:"ready"`
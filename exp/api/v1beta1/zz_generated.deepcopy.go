@@ -21,7 +21,8 @@ limitations under the License.
 package v1beta1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -29,6 +30,56 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticOSUpgradePolicy) DeepCopyInto(out *AutomaticOSUpgradePolicy) {
+	*out = *in
+	if in.EnableAutomaticOSUpgrade != nil {
+		in, out := &in.EnableAutomaticOSUpgrade, &out.EnableAutomaticOSUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableAutomaticRollback != nil {
+		in, out := &in.DisableAutomaticRollback, &out.DisableAutomaticRollback
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticOSUpgradePolicy.
+func (in *AutomaticOSUpgradePolicy) DeepCopy() *AutomaticOSUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticOSUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticRepairsPolicy) DeepCopyInto(out *AutomaticRepairsPolicy) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticRepairsPolicy.
+func (in *AutomaticRepairsPolicy) DeepCopy() *AutomaticRepairsPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticRepairsPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePool) DeepCopyInto(out *AzureMachinePool) {
 	*out = *in
@@ -207,7 +258,7 @@ func (in *AzureMachinePoolMachineStatus) DeepCopyInto(out *AzureMachinePoolMachi
 	*out = *in
 	if in.NodeRef != nil {
 		in, out := &in.NodeRef, &out.NodeRef
-		*out = new(v1.ObjectReference)
+		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
 	if in.ProvisioningState != nil {
@@ -237,6 +288,10 @@ func (in *AzureMachinePoolMachineStatus) DeepCopyInto(out *AzureMachinePoolMachi
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastEvictionTime != nil {
+		in, out := &in.LastEvictionTime, &out.LastEvictionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineStatus.
@@ -258,6 +313,11 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 		(*in).DeepCopyInto(*out)
 	}
 	in.OSDisk.DeepCopyInto(&out.OSDisk)
+	if in.PatchSettings != nil {
+		in, out := &in.PatchSettings, &out.PatchSettings
+		*out = new(apiv1beta1.PatchSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DataDisks != nil {
 		in, out := &in.DataDisks, &out.DataDisks
 		*out = make([]apiv1beta1.DataDisk, len(*in))
@@ -343,6 +403,36 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		copy(*out, *in)
 	}
 	in.Strategy.DeepCopyInto(&out.Strategy)
+	if in.InstanceMetadataLabels != nil {
+		in, out := &in.InstanceMetadataLabels, &out.InstanceMetadataLabels
+		*out = new(InstanceMetadataLabels)
+		**out = **in
+	}
+	if in.SpreadAcrossAllFailureDomains != nil {
+		in, out := &in.SpreadAcrossAllFailureDomains, &out.SpreadAcrossAllFailureDomains
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutomaticRepairsPolicy != nil {
+		in, out := &in.AutomaticRepairsPolicy, &out.AutomaticRepairsPolicy
+		*out = new(AutomaticRepairsPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictedInstanceRestartInterval != nil {
+		in, out := &in.EvictedInstanceRestartInterval, &out.EvictedInstanceRestartInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AutomaticOSUpgradePolicy != nil {
+		in, out := &in.AutomaticOSUpgradePolicy, &out.AutomaticOSUpgradePolicy
+		*out = new(AutomaticOSUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneSpreadPolicy != nil {
+		in, out := &in.ZoneSpreadPolicy, &out.ZoneSpreadPolicy
+		*out = new(ZoneSpreadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolSpec.
@@ -369,6 +459,13 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 			}
 		}
 	}
+	if in.InstancesPerZone != nil {
+		in, out := &in.InstancesPerZone, &out.InstancesPerZone
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(apiv1beta1.Image)
@@ -401,6 +498,11 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.V1Beta2 != nil {
+		in, out := &in.V1Beta2, &out.V1Beta2
+		*out = new(apiv1beta1.V1Beta2Status)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStatus.
@@ -413,6 +515,21 @@ func (in *AzureMachinePoolStatus) DeepCopy() *AzureMachinePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceMetadataLabels) DeepCopyInto(out *InstanceMetadataLabels) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceMetadataLabels.
+func (in *InstanceMetadataLabels) DeepCopy() *InstanceMetadataLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceMetadataLabels)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
 	*out = *in
@@ -426,6 +543,16 @@ func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdate
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.MaxUnhealthyInstancePercent != nil {
+		in, out := &in.MaxUnhealthyInstancePercent, &out.MaxUnhealthyInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PauseTimeBetweenBatches != nil {
+		in, out := &in.PauseTimeBetweenBatches, &out.PauseTimeBetweenBatches
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRollingUpdateDeployment.
@@ -437,3 +564,33 @@ func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeploy
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneSpreadPolicy) DeepCopyInto(out *ZoneSpreadPolicy) {
+	*out = *in
+	if in.ZoneBalance != nil {
+		in, out := &in.ZoneBalance, &out.ZoneBalance
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSkew != nil {
+		in, out := &in.MaxSkew, &out.MaxSkew
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSpreadPolicy.
+func (in *ZoneSpreadPolicy) DeepCopy() *ZoneSpreadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneSpreadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
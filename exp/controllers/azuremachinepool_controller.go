@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -40,6 +41,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -231,6 +233,7 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 		MachinePool:      machinePool,
 		AzureMachinePool: azMachinePool,
 		ClusterScope:     clusterScope,
+		Recorder:         ampr.Recorder,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create machinepool scope")
@@ -298,13 +301,21 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 	err := machinePoolScope.InitMachinePoolCache(ctx)
 	if err != nil {
 		if errors.As(err, &reconcileError) && reconcileError.IsTerminal() {
-			ampr.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, "SKUNotFound", errors.Wrap(err, "failed to initialize machinepool cache").Error())
+			reason := "SKUNotFound"
+			if conditions.GetReason(machinePoolScope.AzureMachinePool, infrav1.ScaleSetRunningCondition) == infrav1.InvalidBootstrapDataReason {
+				reason = infrav1.InvalidBootstrapDataReason
+			}
+			ampr.Recorder.Eventf(machinePoolScope.AzureMachinePool, corev1.EventTypeWarning, reason, errors.Wrap(err, "failed to initialize machinepool cache").Error())
 			log.Error(err, "Failed to initialize machinepool cache")
 			machinePoolScope.SetFailureReason(capierrors.InvalidConfigurationMachineError)
 			machinePoolScope.SetFailureMessage(err)
 			machinePoolScope.SetNotReady()
 			return reconcile.Result{}, nil
 		}
+		if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+			log.V(2).Info(fmt.Sprintf("transient failure to initialize machinepool cache, retrying: %s", reconcileError.Error()))
+			return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+		}
 		return reconcile.Result{}, errors.Wrap(err, "failed to init machinepool scope cache")
 	}
 
@@ -387,6 +398,16 @@ func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, mac
 
 		log.V(4).Info("deleting AzureMachinePool resource individually")
 		if err := amps.Delete(ctx); err != nil {
+			// Keep the finalizer while the scale set delete is in progress or until Azure confirms it's gone.
+			var reconcileError azure.ReconcileError
+			if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+				if azure.IsOperationNotDoneError(reconcileError) {
+					log.V(2).Info(fmt.Sprintf("AzureMachinePool delete not done: %s", reconcileError.Error()))
+				} else {
+					log.V(2).Info("transient failure to delete AzureMachinePool, retrying")
+				}
+				return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}, nil
+			}
 			return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureMachinePool %s/%s", machinePoolScope.AzureMachinePool.Namespace, machinePoolScope.Name())
 		}
 	}
@@ -75,6 +75,7 @@ func TestAzureMachinePoolReconcilePaused(t *testing.T) {
 	g.Expect(sb.AddToScheme(s)).To(Succeed())
 	c := fake.NewClientBuilder().
 		WithScheme(s).
+		WithStatusSubresource(&infrav1exp.AzureMachinePool{}).
 		Build()
 
 	recorder := record.NewFakeRecorder(1)
@@ -17,20 +17,28 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func Test_newAzureMachinePoolService(t *testing.T) {
@@ -75,6 +83,82 @@ func Test_newAzureMachinePoolService(t *testing.T) {
 	g.Expect(subject.skuCache).NotTo(BeNil())
 }
 
+func TestAzureMachinePoolReconcileDelete(t *testing.T) {
+	deleteFuture := &infrav1.Future{
+		Type:          infrav1.DeleteFuture,
+		ServiceName:   "scalesets",
+		Name:          "poolName",
+		ResourceGroup: "my-rg",
+		Data:          "ZmFrZSBiNjQgZnV0dXJlIGRhdGEK",
+	}
+
+	cases := map[string]struct {
+		deleteErr      error
+		expectedResult reconcile.Result
+		expectedErr    string
+	}{
+		"scale set delete in progress": {
+			deleteErr:      azure.WithTransientError(azure.NewOperationNotDoneError(deleteFuture), 15*time.Second),
+			expectedResult: reconcile.Result{RequeueAfter: 15 * time.Second},
+		},
+		"scale set still exists after the delete completed": {
+			deleteErr:      azure.WithTransientError(errors.New("resource my-rg/poolName still exists after being deleted (service: scalesets)"), 15*time.Second),
+			expectedResult: reconcile.Result{RequeueAfter: 15 * time.Second},
+		},
+		"scale set delete fails mid-way": {
+			deleteErr:   errors.New("failed to delete resource my-rg/poolName (service: scalesets): InternalServerError"),
+			expectedErr: "error deleting AzureMachinePool default/poolName",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+
+			client := fake.NewClientBuilder().WithScheme(newScheme(g)).Build()
+			clusterScope := &scope.ClusterScope{
+				Client:       client,
+				Cluster:      newCluster("fakeCluster"),
+				AzureCluster: newAzureCluster("fakeCluster"),
+			}
+
+			amp := newAzureMachinePool("fakeCluster", "poolName")
+			amp.Finalizers = []string{expv1.MachinePoolFinalizer}
+			mps := &scope.MachinePoolScope{
+				ClusterScoper:    clusterScope,
+				MachinePool:      newMachinePool("fakeCluster", "poolName"),
+				AzureMachinePool: amp,
+			}
+
+			scaleSetsSvc := mock_azure.NewMockServiceReconciler(mockCtrl)
+			scaleSetsSvc.EXPECT().Delete(gomock.Any()).Return(tc.deleteErr)
+			scaleSetsSvc.EXPECT().Name().Return("scalesets").AnyTimes()
+
+			ampr := &AzureMachinePoolReconciler{
+				Client:   client,
+				Recorder: record.NewFakeRecorder(10),
+				createAzureMachinePoolService: func(machinePoolScope *scope.MachinePoolScope) (*azureMachinePoolService, error) {
+					return &azureMachinePoolService{
+						scope:    machinePoolScope,
+						services: []azure.ServiceReconciler{scaleSetsSvc},
+					}, nil
+				},
+			}
+
+			result, err := ampr.reconcileDelete(context.Background(), mps, clusterScope)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(result).To(Equal(tc.expectedResult))
+			g.Expect(controllerutil.ContainsFinalizer(amp, expv1.MachinePoolFinalizer)).To(BeTrue())
+		})
+	}
+}
+
 func newScheme(g *GomegaWithT) *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	for _, f := range []func(*runtime.Scheme) error{
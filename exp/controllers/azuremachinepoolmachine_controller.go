@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
@@ -65,6 +66,7 @@ type (
 	azureMachinePoolMachineReconciler struct {
 		Scope              *scope.MachinePoolMachineScope
 		scalesetVMsService *scalesetvms.Service
+		tagsService        *tags.Service
 	}
 )
 
@@ -171,9 +173,16 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azureMachine) {
 		logger.Info("AzureMachinePoolMachine or linked Cluster is marked as paused. Won't reconcile")
+		// The cluster may be moved while it's paused, so don't keep its workload cluster client around.
+		scope.GetRemoteClientCache().Invalidate(client.ObjectKeyFromObject(cluster))
 		return ctrl.Result{}, nil
 	}
 
+	if !cluster.DeletionTimestamp.IsZero() {
+		// Don't keep the client of a workload cluster that is going away.
+		defer scope.GetRemoteClientCache().Invalidate(client.ObjectKeyFromObject(cluster))
+	}
+
 	clusterScope, err := infracontroller.GetClusterScoper(ctx, logger, ampmr.Client, cluster, ampmr.Timeouts)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create cluster scope for cluster %s/%s", cluster.Namespace, cluster.Name)
@@ -376,9 +385,14 @@ func newAzureMachinePoolMachineReconciler(scope *scope.MachinePoolMachineScope)
 	if err != nil {
 		return nil, err
 	}
+	tagsSvc, err := tags.New(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureMachinePoolMachineReconciler{
 		Scope:              scope,
 		scalesetVMsService: scaleSetVMsSvc,
+		tagsService:        tagsSvc,
 	}, nil
 }
 
@@ -391,6 +405,10 @@ func (r *azureMachinePoolMachineReconciler) Reconcile(ctx context.Context) error
 		return errors.Wrap(err, "failed to reconcile scalesetVMs")
 	}
 
+	if err := r.tagsService.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile VMSS VM tags")
+	}
+
 	if err := r.Scope.UpdateNodeStatus(ctx); err != nil {
 		return errors.Wrap(err, "failed to update VMSS VM node status")
 	}
@@ -47,6 +47,22 @@ const (
 	// owner: @upxinxin
 	// alpha: v1.8
 	EdgeZone featuregate.Feature = "EdgeZone"
+
+	// ZoneValidation is the feature gate for validating the availability zones requested by AzureClusters
+	// against the zones of their location on creation.
+	// alpha: v1.13
+	ZoneValidation featuregate.Feature = "ZoneValidation"
+
+	// TransientErrorBackoff is the feature gate for retrying the reconciliation of AzureClusters and AzureMachines
+	// which failed with a transient error with an exponential backoff.
+	// alpha: v1.13
+	TransientErrorBackoff featuregate.Feature = "TransientErrorBackoff"
+
+	// RegionHealth is the feature gate for looking up Azure Service Health incidents in the region of AzureClusters
+	// which repeatedly fail to reconcile, and for slowing down their reconciliation during an incident. It requires
+	// the Microsoft.ResourceHealth/events/read permission on the subscription of the clusters.
+	// alpha: v1.13
+	RegionHealth featuregate.Feature = "RegionHealth"
 )
 
 func init() {
@@ -57,7 +73,10 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS:               {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // Remove in 1.12
-	AKSResourceHealth: {Default: false, PreRelease: featuregate.Alpha},
-	EdgeZone:          {Default: false, PreRelease: featuregate.Alpha},
+	AKS:                   {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // Remove in 1.12
+	AKSResourceHealth:     {Default: false, PreRelease: featuregate.Alpha},
+	EdgeZone:              {Default: false, PreRelease: featuregate.Alpha},
+	ZoneValidation:        {Default: false, PreRelease: featuregate.Alpha},
+	TransientErrorBackoff: {Default: false, PreRelease: featuregate.Alpha},
+	RegionHealth:          {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -23,18 +23,22 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	// +kubebuilder:scaffold:imports
+	asoauthorizationv1 "github.com/Azure/azure-service-operator/v2/api/authorization/v1api20220401"
 	asocontainerservicev1preview "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20230315preview"
 	asocontainerservicev1 "github.com/Azure/azure-service-operator/v2/api/containerservice/v1api20231001"
 	asokubernetesconfigurationv1 "github.com/Azure/azure-service-operator/v2/api/kubernetesconfiguration/v1api20230501"
+	asonetworkv1api20180501 "github.com/Azure/azure-service-operator/v2/api/network/v1api20180501"
 	asonetworkv1api20201101 "github.com/Azure/azure-service-operator/v2/api/network/v1api20201101"
 	asonetworkv1api20220701 "github.com/Azure/azure-service-operator/v2/api/network/v1api20220701"
 	asoresourcesv1 "github.com/Azure/azure-service-operator/v2/api/resources/v1api20200601"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/server/routes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -42,17 +46,20 @@ import (
 	"k8s.io/component-base/logs"
 	"k8s.io/klog/v2"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/ot"
+	"sigs.k8s.io/cluster-api-provider-azure/util/placement"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -76,13 +83,16 @@ func init() {
 	_ = infrav1exp.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
 	_ = expv1.AddToScheme(scheme)
+	_ = ipamv1.AddToScheme(scheme)
 	_ = kubeadmv1.AddToScheme(scheme)
 	_ = asoresourcesv1.AddToScheme(scheme)
 	_ = asocontainerservicev1.AddToScheme(scheme)
 	_ = asonetworkv1api20220701.AddToScheme(scheme)
 	_ = asonetworkv1api20201101.AddToScheme(scheme)
+	_ = asonetworkv1api20180501.AddToScheme(scheme)
 	_ = asocontainerservicev1preview.AddToScheme(scheme)
 	_ = asokubernetesconfigurationv1.AddToScheme(scheme)
+	_ = asoauthorizationv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -107,6 +117,14 @@ var (
 	diagnosticsOptions                 = DiagnosticsOptions{}
 	timeouts                           reconciler.Timeouts
 	enableTracing                      bool
+	forceDeleteUnmanaged               bool
+	filterControlPlaneZones            bool
+	failOnProvisioningTimeout          bool
+	allowedSubscriptions               []string
+	allowedLocations                   []string
+	placementAllowlistConfigMap        string
+	clusterTagPrefix                   string
+	enforceNodePoolSubnetCapacity      bool
 )
 
 // InitFlags initializes all command-line flags.
@@ -228,6 +246,12 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The maximum duration each Azure service reconcile can run (e.g. 90m)",
 	)
 
+	fs.StringToIntVar(&timeouts.ServiceWeights,
+		"service-timeout-weights",
+		nil,
+		"The weights of Azure services, by service name, in the split of the reconcile timeout between the services of a reconcile loop; services default to a weight of 1 (e.g. loadbalancers=3,virtualmachines=4)",
+	)
+
 	fs.DurationVar(&timeouts.AzureCall,
 		"api-call-timeout",
 		reconciler.DefaultAzureCallTimeout,
@@ -240,11 +264,73 @@ func InitFlags(fs *pflag.FlagSet) {
 		"The duration to wait before retrying after a transient reconcile error occurs (e.g. 15s)",
 	)
 
+	fs.DurationVar(&timeouts.VMNotFoundGracePeriod,
+		"vm-not-found-grace-period",
+		reconciler.DefaultVMNotFoundGracePeriod,
+		"The duration after the creation of a VM during which the VM not being found is retried instead of failing the AzureMachine (e.g. 5m)",
+	)
+
 	fs.BoolVar(
 		&enableTracing,
 		"enable-tracing",
 		false,
-		"Enable tracing to the opentelemetry-collector service in the same namespace.",
+		"Enable tracing to the opentelemetry-collector service in the same namespace, or to the endpoint set with the standard OTEL_EXPORTER_OTLP_* environment variables.",
+	)
+
+	fs.BoolVar(
+		&forceDeleteUnmanaged,
+		"force-delete-unmanaged",
+		false,
+		"Determine whether Azure resources are managed by CAPZ from their tags, even for clusters that record the resources created by CAPZ. This may delete resources not created by CAPZ if they carry copied tags.",
+	)
+
+	fs.BoolVar(
+		&failOnProvisioningTimeout,
+		"fail-on-provisioning-timeout",
+		false,
+		"Set the failure reason of AzureMachines whose VM exceeds their provisioning timeout without bootstrapping, so any MachineHealthCheck of the machine remediates it even without targeting the BootstrapWithinTimeout condition.",
+	)
+
+	fs.BoolVar(
+		&filterControlPlaneZones,
+		"filter-control-plane-zones",
+		true,
+		"Only use the availability zones where the VM size of the control plane machines is available as control plane failure domains. Set to false to use all the availability zones of the location.",
+	)
+
+	fs.StringSliceVar(
+		&allowedSubscriptions,
+		"allowed-subscriptions",
+		nil,
+		"Comma-separated list of the Azure subscriptions that AzureClusters, AzureManagedControlPlanes and AzureClusterIdentities can reference. If unspecified, all subscriptions are allowed.",
+	)
+
+	fs.StringSliceVar(
+		&allowedLocations,
+		"allowed-locations",
+		nil,
+		"Comma-separated list of the Azure locations that AzureClusters and AzureManagedControlPlanes can target. If unspecified, all locations are allowed.",
+	)
+
+	fs.StringVar(
+		&placementAllowlistConfigMap,
+		"placement-allowlist-configmap",
+		"",
+		fmt.Sprintf("Namespace/name of a ConfigMap whose %q and %q keys override --allowed-subscriptions and --allowed-locations. Changes to the ConfigMap apply without restarting the manager.", placement.AllowedSubscriptionsKey, placement.AllowedLocationsKey),
+	)
+
+	fs.StringVar(
+		&clusterTagPrefix,
+		"cluster-tag-prefix",
+		infrav1.NameAzureProviderPrefix,
+		"Prefix of the keys of the tags CAPZ sets on the Azure resources it manages. Resources tagged with the default prefix are still recognized, and their tags are rewritten with this prefix when reconciled.",
+	)
+
+	fs.BoolVar(
+		&enforceNodePoolSubnetCapacity,
+		"enforce-node-pool-subnet-capacity",
+		false,
+		"Reject AzureManagedMachinePools whose nodes and pods may not fit in their subnet with Azure CNI when scaled to their max count, instead of admitting them with a warning.",
 	)
 
 	AddDiagnosticsOptions(fs, &diagnosticsOptions)
@@ -271,6 +357,11 @@ func main() {
 		BurstSize: 100,
 	})
 
+	if err := infrav1.SetClusterTagPrefix(clusterTagPrefix); err != nil {
+		setupLog.Error(err, "invalid --cluster-tag-prefix")
+		os.Exit(1)
+	}
+
 	diagnosticsOpts := GetDiagnosticsOptions(diagnosticsOptions)
 
 	var watchNamespaces map[string]cache.Config
@@ -358,6 +449,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		timeouts,
 		watchFilterValue,
+		failOnProvisioningTimeout,
 	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
@@ -372,6 +464,8 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		mgr.GetEventRecorderFor("azurecluster-reconciler"),
 		timeouts,
 		watchFilterValue,
+		forceDeleteUnmanaged,
+		filterControlPlaneZones,
 	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureCluster")
 		os.Exit(1)
@@ -465,6 +559,15 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			os.Exit(1)
 		}
 
+		if err := mgr.Add(controllers.NewSpotEvictionPoller(
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("spotevictionpoller"),
+			watchFilterValue,
+		)); err != nil {
+			setupLog.Error(err, "unable to add spot eviction poller")
+			os.Exit(1)
+		}
+
 		mcCache, err := coalescing.NewRequestCache(debouncingTimer)
 		if err != nil {
 			setupLog.Error(err, "failed to build mcCache ReconcileCache")
@@ -497,8 +600,16 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 	}
 }
 
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
 func registerWebhooks(mgr manager.Manager) {
-	if err := (&infrav1.AzureCluster{}).SetupWebhookWithManager(mgr); err != nil {
+	allowlist, err := newPlacementAllowlist(mgr)
+	if err != nil {
+		setupLog.Error(err, "invalid placement allowlist")
+		os.Exit(1)
+	}
+
+	if err := (&infrav1.AzureCluster{}).SetupWebhookWithManager(mgr, &scope.AzureClusterZonesGetter{Client: mgr.GetClient()}, allowlist); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureCluster")
 		os.Exit(1)
 	}
@@ -513,7 +624,7 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
-	if err := (&infrav1.AzureClusterIdentity{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&infrav1.AzureClusterIdentity{}).SetupWebhookWithManager(mgr, allowlist); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureClusterIdentity")
 		os.Exit(1)
 	}
@@ -545,7 +656,7 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
-	if err := infrav1.SetupAzureManagedMachinePoolWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureManagedMachinePoolWebhookWithManager(mgr, &scope.ManagedMachinePoolLocalDiskSizeGetter{Client: mgr.GetClient()}, enforceNodePoolSubnetCapacity); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureManagedMachinePool")
 		os.Exit(1)
 	}
@@ -555,7 +666,7 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
-	if err := infrav1.SetupAzureManagedControlPlaneWebhookWithManager(mgr); err != nil {
+	if err := infrav1.SetupAzureManagedControlPlaneWebhookWithManager(mgr, allowlist); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureManagedControlPlane")
 		os.Exit(1)
 	}
@@ -640,3 +751,21 @@ func GetDiagnosticsOptions(options DiagnosticsOptions) metricsserver.Options {
 		},
 	}
 }
+
+// newPlacementAllowlist returns the allowlist of subscriptions and locations configured with the manager flags.
+func newPlacementAllowlist(mgr manager.Manager) (*placement.Allowlist, error) {
+	allowlist := &placement.Allowlist{
+		Subscriptions: placement.ParseList(strings.Join(allowedSubscriptions, ",")),
+		Locations:     placement.ParseList(strings.Join(allowedLocations, ",")),
+		// ConfigMaps aren't cached by the manager client, so changes to the ConfigMap are seen by the next lookup.
+		Reader: mgr.GetClient(),
+	}
+	if placementAllowlistConfigMap != "" {
+		namespace, name, ok := strings.Cut(placementAllowlistConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("--placement-allowlist-configmap must be in the form namespace/name, got %q", placementAllowlistConfigMap)
+		}
+		allowlist.ConfigMap = &types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return allowlist, nil
+}
@@ -18,6 +18,7 @@ package ot
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -32,9 +33,13 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
 
+// defaultCollectorEndpoint is the endpoint of the opentelemetry-collector service in the same namespace, to which
+// traces are exported unless an endpoint is set with the standard OTEL_EXPORTER_OTLP_* environment variables.
+const defaultCollectorEndpoint = "opentelemetry-collector:4317"
+
 // RegisterTracing enables code tracing via OpenTelemetry.
 func RegisterTracing(ctx context.Context, log logr.Logger) error {
-	tp, err := otlpTracerProvider(ctx, "opentelemetry-collector:4317")
+	tp, err := otlpTracerProvider(ctx, defaultCollectorEndpoint)
 	if err != nil {
 		return err
 	}
@@ -54,6 +59,8 @@ func RegisterTracing(ctx context.Context, log logr.Logger) error {
 }
 
 // otlpTracerProvider initializes an OTLP exporter and configures the corresponding tracer provider.
+// The exporter, resource and sampler honor the standard OTEL environment variables, the exporter falling back to an
+// insecure connection to url when no endpoint is set.
 func otlpTracerProvider(ctx context.Context, url string) (*sdktrace.TracerProvider, error) {
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
@@ -62,25 +69,34 @@ func otlpTracerProvider(ctx context.Context, url string) (*sdktrace.TracerProvid
 			attribute.String("version", version.Get().String()),
 			attribute.String("azuresdk.version", version.Get().AzureSdkVersion),
 		),
+		resource.WithFromEnv(),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create opentelemetry resource")
 	}
 
-	traceExporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithEndpoint(url),
-	)
+	var exporterOpts []otlptracegrpc.Option
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		exporterOpts = append(exporterOpts,
+			otlptracegrpc.WithInsecure(),
+			otlptracegrpc.WithEndpoint(url),
+		)
+	}
+	traceExporter, err := otlptracegrpc.New(ctx, exporterOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create otlp trace exporter")
 	}
 
 	bsp := sdktrace.NewBatchSpanProcessor(traceExporter)
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	providerOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
 		sdktrace.WithSpanProcessor(bsp),
-	)
+	}
+	// The SDK reads the sampler from OTEL_TRACES_SAMPLER, so only default to sampling every trace when it isn't set.
+	if os.Getenv("OTEL_TRACES_SAMPLER") == "" {
+		providerOpts = append(providerOpts, sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(providerOpts...)
 	otel.SetTracerProvider(tracerProvider)
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
@@ -16,9 +16,18 @@ limitations under the License.
 
 package aso
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // GetASOSecretName formats the name of the ASO Secret created by the capz controller.
 func GetASOSecretName(clusterOwner string) string {
 	return fmt.Sprintf("%s-aso-secret", clusterOwner)
 }
+
+// GetASOSecretNameForSubscription formats the name of the ASO Secret created by the capz controller to manage the
+// resources of a cluster that are in another subscription than the cluster.
+func GetASOSecretNameForSubscription(clusterOwner, subscriptionID string) string {
+	return fmt.Sprintf("%s-aso-secret-%s", clusterOwner, strings.ToLower(subscriptionID))
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions reports the conditions of the CAPZ objects following the Cluster API v1beta2 contract alongside
+// their v1beta1 conditions.
+package conditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// ReadyV1Beta2Condition aggregates the other v1beta2 conditions of an object.
+	ReadyV1Beta2Condition = string(clusterv1.ReadyCondition)
+	// ReadyV1Beta2Reason is the reason of a True Ready condition.
+	ReadyV1Beta2Reason = "Ready"
+	// NotReadyV1Beta2Reason is the reason of a False Ready condition.
+	NotReadyV1Beta2Reason = "NotReady"
+	// ReadyUnknownV1Beta2Reason is the reason of an Unknown Ready condition.
+	ReadyUnknownV1Beta2Reason = "ReadyUnknown"
+
+	// DeletingV1Beta2Condition is True while an object is being deleted. It has a negative polarity: False is the
+	// healthy state.
+	DeletingV1Beta2Condition = "Deleting"
+	// DeletingV1Beta2Reason is the reason of a True Deleting condition.
+	DeletingV1Beta2Reason = "Deleting"
+	// NotDeletingV1Beta2Reason is the reason of a False Deleting condition.
+	NotDeletingV1Beta2Reason = "NotDeleting"
+
+	// NoReasonReportedV1Beta2Reason is the reason of a v1beta2 condition converted from a v1beta1 condition without
+	// reason, as v1beta2 conditions require one.
+	NoReasonReportedV1Beta2Reason = "NoReasonReported"
+)
+
+// negativePolarityV1Beta2Conditions are the v1beta2 conditions for which False rather than True is the healthy state.
+var negativePolarityV1Beta2Conditions = map[string]bool{
+	DeletingV1Beta2Condition:                            true,
+	string(infrav1.RegionDegradedCondition):             true,
+	string(infrav1.WaitingForPreTerminateHookCondition): true,
+	string(infrav1.ZonesUnbalancedCondition):            true,
+}
+
+// V1Beta2Setter is an object with v1beta1 conditions which also reports v1beta2 conditions.
+type V1Beta2Setter interface {
+	conditions.Setter
+	GetV1Beta2Conditions() []metav1.Condition
+	SetV1Beta2Conditions([]metav1.Condition)
+}
+
+// SetV1Beta2Conditions sets the v1beta2 conditions of obj from its v1beta1 conditions. Every v1beta1 condition but
+// Ready is converted as is, a Deleting condition reports whether obj is being deleted, and the Ready condition
+// aggregates all of them. All the conditions are stamped with the generation of obj.
+// It must be called after the v1beta1 conditions are up to date, i.e. after conditions.SetSummary.
+func SetV1Beta2Conditions(obj V1Beta2Setter) {
+	generation := obj.GetGeneration()
+	previous := map[string]metav1.Condition{}
+	for _, c := range obj.GetV1Beta2Conditions() {
+		previous[c.Type] = c
+	}
+
+	var v1beta2Conditions []metav1.Condition
+	for _, c := range obj.GetConditions() {
+		if c.Type == clusterv1.ReadyCondition {
+			continue
+		}
+		v1beta2Conditions = append(v1beta2Conditions, ToV1Beta2Condition(c, generation))
+	}
+	sort.Slice(v1beta2Conditions, func(i, j int) bool {
+		return v1beta2Conditions[i].Type < v1beta2Conditions[j].Type
+	})
+
+	deleting := metav1.Condition{
+		Type:               DeletingV1Beta2Condition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             NotDeletingV1Beta2Reason,
+	}
+	if !obj.GetDeletionTimestamp().IsZero() {
+		deleting.Status = metav1.ConditionTrue
+		deleting.Reason = DeletingV1Beta2Reason
+	}
+	v1beta2Conditions = append(v1beta2Conditions, deleting)
+
+	ready := aggregateReady(v1beta2Conditions, generation)
+	v1beta2Conditions = append([]metav1.Condition{ready}, v1beta2Conditions...)
+
+	// The conditions which aren't converted from a v1beta1 condition only transition when their status changes.
+	now := metav1.Now()
+	for i := range v1beta2Conditions {
+		c := &v1beta2Conditions[i]
+		if !c.LastTransitionTime.IsZero() {
+			continue
+		}
+		if prev, ok := previous[c.Type]; ok && prev.Status == c.Status {
+			c.LastTransitionTime = prev.LastTransitionTime
+		} else {
+			c.LastTransitionTime = now
+		}
+	}
+
+	obj.SetV1Beta2Conditions(v1beta2Conditions)
+}
+
+// aggregateReady returns a Ready condition which is False if any of the conditions isn't healthy, Unknown if any of
+// them is Unknown, and True otherwise.
+func aggregateReady(v1beta2Conditions []metav1.Condition, generation int64) metav1.Condition {
+	var notReady, unknown []string
+	for _, c := range v1beta2Conditions {
+		switch NormalizedStatus(c) {
+		case metav1.ConditionFalse:
+			notReady = append(notReady, conditionSummary(c))
+		case metav1.ConditionUnknown:
+			unknown = append(unknown, conditionSummary(c))
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               ReadyV1Beta2Condition,
+		ObservedGeneration: generation,
+	}
+	switch {
+	case len(notReady) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = NotReadyV1Beta2Reason
+		ready.Message = strings.Join(notReady, "; ")
+	case len(unknown) > 0:
+		ready.Status = metav1.ConditionUnknown
+		ready.Reason = ReadyUnknownV1Beta2Reason
+		ready.Message = strings.Join(unknown, "; ")
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = ReadyV1Beta2Reason
+	}
+	return ready
+}
+
+func conditionSummary(c metav1.Condition) string {
+	if c.Message == "" {
+		return fmt.Sprintf("%s: %s", c.Type, c.Reason)
+	}
+	return fmt.Sprintf("%s: %s", c.Type, c.Message)
+}
+
+// NormalizedStatus returns the status of a v1beta2 condition as if it had a positive polarity, i.e. True if the
+// condition reports a healthy state and False if it doesn't.
+func NormalizedStatus(c metav1.Condition) metav1.ConditionStatus {
+	if !negativePolarityV1Beta2Conditions[c.Type] {
+		return c.Status
+	}
+	switch c.Status {
+	case metav1.ConditionTrue:
+		return metav1.ConditionFalse
+	case metav1.ConditionFalse:
+		return metav1.ConditionTrue
+	default:
+		return c.Status
+	}
+}
+
+// ToV1Beta2Condition converts a v1beta1 condition to a v1beta2 condition which observed the given generation.
+// The severity of the v1beta1 condition is dropped, as v1beta2 conditions have none.
+func ToV1Beta2Condition(c clusterv1.Condition, observedGeneration int64) metav1.Condition {
+	status := metav1.ConditionStatus(c.Status)
+	if status != metav1.ConditionTrue && status != metav1.ConditionFalse {
+		status = metav1.ConditionUnknown
+	}
+	reason := c.Reason
+	if reason == "" {
+		reason = NoReasonReportedV1Beta2Reason
+	}
+	return metav1.Condition{
+		Type:               string(c.Type),
+		Status:             status,
+		ObservedGeneration: observedGeneration,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             reason,
+		Message:            c.Message,
+	}
+}
+
+// FromV1Beta2Condition converts a v1beta2 condition to a v1beta1 condition. As v1beta1 conditions always have a
+// positive polarity, the status of a negative polarity condition is normalized. An unhealthy condition gets the
+// Warning severity if it is False in the v1beta2 contract and the Info severity otherwise.
+func FromV1Beta2Condition(c metav1.Condition) clusterv1.Condition {
+	condition := clusterv1.Condition{
+		Type:               clusterv1.ConditionType(c.Type),
+		Status:             corev1.ConditionStatus(NormalizedStatus(c)),
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             c.Reason,
+		Message:            c.Message,
+	}
+	if condition.Status == corev1.ConditionFalse {
+		condition.Severity = clusterv1.ConditionSeverityInfo
+		if c.Status == metav1.ConditionFalse {
+			condition.Severity = clusterv1.ConditionSeverityWarning
+		}
+	}
+	return condition
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var (
+	_ V1Beta2Setter = &infrav1.AzureCluster{}
+	_ V1Beta2Setter = &infrav1.AzureMachine{}
+	_ V1Beta2Setter = &infrav1.AzureManagedControlPlane{}
+	_ V1Beta2Setter = &infrav1exp.AzureMachinePool{}
+)
+
+func TestSetV1Beta2Conditions(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name       string
+		conditions clusterv1.Conditions
+		deleting   bool
+		wantReady  metav1.Condition
+	}{
+		{
+			name: "all conditions healthy",
+			conditions: clusterv1.Conditions{
+				{Type: infrav1.VMRunningCondition, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime},
+				{Type: infrav1.NetworkInterfaceReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime},
+			},
+			wantReady: metav1.Condition{Type: ReadyV1Beta2Condition, Status: metav1.ConditionTrue, Reason: ReadyV1Beta2Reason},
+		},
+		{
+			name: "a condition is False",
+			conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(infrav1.VMRunningCondition, infrav1.VMProvisionFailedReason, clusterv1.ConditionSeverityError, "VM failed"),
+				{Type: infrav1.NetworkInterfaceReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime},
+			},
+			wantReady: metav1.Condition{Type: ReadyV1Beta2Condition, Status: metav1.ConditionFalse, Reason: NotReadyV1Beta2Reason, Message: "VMRunning: VM failed"},
+		},
+		{
+			name: "a condition is Unknown",
+			conditions: clusterv1.Conditions{
+				*conditions.UnknownCondition(infrav1.VMRunningCondition, "", ""),
+			},
+			wantReady: metav1.Condition{Type: ReadyV1Beta2Condition, Status: metav1.ConditionUnknown, Reason: ReadyUnknownV1Beta2Reason, Message: "VMRunning: NoReasonReported"},
+		},
+		{
+			name: "being deleted",
+			conditions: clusterv1.Conditions{
+				{Type: infrav1.VMRunningCondition, Status: corev1.ConditionTrue, LastTransitionTime: transitionTime},
+			},
+			deleting:  true,
+			wantReady: metav1.Condition{Type: ReadyV1Beta2Condition, Status: metav1.ConditionFalse, Reason: NotReadyV1Beta2Reason, Message: "Deleting: Deleting"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+			if tc.deleting {
+				machine.DeletionTimestamp = &transitionTime
+			}
+			machine.SetConditions(tc.conditions)
+			conditions.SetSummary(machine)
+
+			SetV1Beta2Conditions(machine)
+
+			v1beta2Conditions := machine.GetV1Beta2Conditions()
+			g.Expect(v1beta2Conditions).To(HaveLen(len(tc.conditions) + 2))
+			g.Expect(v1beta2Conditions[0].Type).To(Equal(ReadyV1Beta2Condition))
+			g.Expect(v1beta2Conditions[len(v1beta2Conditions)-1].Type).To(Equal(DeletingV1Beta2Condition))
+			for _, c := range v1beta2Conditions {
+				g.Expect(c.ObservedGeneration).To(Equal(int64(3)), c.Type)
+				g.Expect(c.Reason).NotTo(BeEmpty(), c.Type)
+				g.Expect(c.LastTransitionTime.IsZero()).To(BeFalse(), c.Type)
+			}
+			ready := v1beta2Conditions[0]
+			g.Expect(ready.Status).To(Equal(tc.wantReady.Status))
+			g.Expect(ready.Reason).To(Equal(tc.wantReady.Reason))
+			g.Expect(ready.Message).To(Equal(tc.wantReady.Message))
+		})
+	}
+}
+
+func TestSetV1Beta2ConditionsKeepsTransitionTime(t *testing.T) {
+	g := NewWithT(t)
+	previousTime := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	cluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	cluster.SetConditions(clusterv1.Conditions{
+		{Type: infrav1.NetworkInfrastructureReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: previousTime},
+	})
+	cluster.SetV1Beta2Conditions([]metav1.Condition{
+		{Type: ReadyV1Beta2Condition, Status: metav1.ConditionTrue, Reason: ReadyV1Beta2Reason, LastTransitionTime: previousTime},
+		{Type: DeletingV1Beta2Condition, Status: metav1.ConditionFalse, Reason: NotDeletingV1Beta2Reason, LastTransitionTime: previousTime},
+	})
+	cluster.Generation = 2
+
+	SetV1Beta2Conditions(cluster)
+
+	g.Expect(cluster.GetV1Beta2Conditions()).To(HaveLen(3))
+	for _, c := range cluster.GetV1Beta2Conditions() {
+		g.Expect(c.LastTransitionTime).To(Equal(previousTime), c.Type)
+		g.Expect(c.ObservedGeneration).To(Equal(int64(2)), c.Type)
+	}
+
+	// The Ready condition transitions once another condition becomes unhealthy.
+	conditions.MarkFalse(cluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityWarning, "")
+	SetV1Beta2Conditions(cluster)
+	ready := cluster.GetV1Beta2Conditions()[0]
+	g.Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(ready.LastTransitionTime).NotTo(Equal(previousTime))
+}
+
+func TestNormalizedStatus(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(NormalizedStatus(metav1.Condition{Type: "VMRunning", Status: metav1.ConditionTrue})).To(Equal(metav1.ConditionTrue))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: "VMRunning", Status: metav1.ConditionFalse})).To(Equal(metav1.ConditionFalse))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: DeletingV1Beta2Condition, Status: metav1.ConditionTrue})).To(Equal(metav1.ConditionFalse))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: DeletingV1Beta2Condition, Status: metav1.ConditionFalse})).To(Equal(metav1.ConditionTrue))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: DeletingV1Beta2Condition, Status: metav1.ConditionUnknown})).To(Equal(metav1.ConditionUnknown))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: string(infrav1.RegionDegradedCondition), Status: metav1.ConditionTrue})).To(Equal(metav1.ConditionFalse))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: string(infrav1.WaitingForPreTerminateHookCondition), Status: metav1.ConditionTrue})).To(Equal(metav1.ConditionFalse))
+	g.Expect(NormalizedStatus(metav1.Condition{Type: string(infrav1.ZonesUnbalancedCondition), Status: metav1.ConditionTrue})).To(Equal(metav1.ConditionFalse))
+}
+
+func TestV1Beta2ConditionConversion(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		v1beta1 clusterv1.Condition
+		v1beta2 metav1.Condition
+	}{
+		{
+			name:    "True condition",
+			v1beta1: clusterv1.Condition{Type: "VMRunning", Status: corev1.ConditionTrue, Reason: "Succeeded", LastTransitionTime: transitionTime},
+			v1beta2: metav1.Condition{Type: "VMRunning", Status: metav1.ConditionTrue, Reason: "Succeeded", ObservedGeneration: 4, LastTransitionTime: transitionTime},
+		},
+		{
+			name:    "False condition",
+			v1beta1: clusterv1.Condition{Type: "VMRunning", Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityWarning, Reason: "Failed", Message: "boom", LastTransitionTime: transitionTime},
+			v1beta2: metav1.Condition{Type: "VMRunning", Status: metav1.ConditionFalse, Reason: "Failed", Message: "boom", ObservedGeneration: 4, LastTransitionTime: transitionTime},
+		},
+		{
+			name:    "Unknown condition",
+			v1beta1: clusterv1.Condition{Type: "VMRunning", Status: corev1.ConditionUnknown, Reason: "Creating", LastTransitionTime: transitionTime},
+			v1beta2: metav1.Condition{Type: "VMRunning", Status: metav1.ConditionUnknown, Reason: "Creating", ObservedGeneration: 4, LastTransitionTime: transitionTime},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ToV1Beta2Condition(tc.v1beta1, 4)).To(Equal(tc.v1beta2))
+			g.Expect(FromV1Beta2Condition(tc.v1beta2)).To(Equal(tc.v1beta1))
+		})
+	}
+}
+
+func TestToV1Beta2ConditionWithoutReason(t *testing.T) {
+	g := NewWithT(t)
+	c := ToV1Beta2Condition(clusterv1.Condition{Type: "VMRunning", Status: ""}, 1)
+	g.Expect(c.Status).To(Equal(metav1.ConditionUnknown))
+	g.Expect(c.Reason).To(Equal(NoReasonReportedV1Beta2Reason))
+}
+
+func TestFromV1Beta2ConditionNegativePolarity(t *testing.T) {
+	g := NewWithT(t)
+	c := FromV1Beta2Condition(metav1.Condition{Type: DeletingV1Beta2Condition, Status: metav1.ConditionTrue, Reason: DeletingV1Beta2Reason})
+	g.Expect(c.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(c.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+
+	c = FromV1Beta2Condition(metav1.Condition{Type: DeletingV1Beta2Condition, Status: metav1.ConditionFalse, Reason: NotDeletingV1Beta2Reason})
+	g.Expect(c.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(c.Severity).To(BeEmpty())
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placement restricts the Azure subscriptions and locations that clusters can target.
+package placement
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AllowedSubscriptionsKey is the ConfigMap key holding the comma-separated allowed subscriptions.
+	AllowedSubscriptionsKey = "allowed-subscriptions"
+	// AllowedLocationsKey is the ConfigMap key holding the comma-separated allowed locations.
+	AllowedLocationsKey = "allowed-locations"
+)
+
+// Allowlist holds the Azure subscriptions and locations that clusters are allowed to target. The lists are set from
+// the manager flags and can be overridden by a ConfigMap, which is read on every lookup so that changes apply without
+// restarting the manager.
+type Allowlist struct {
+	// Subscriptions are the allowed subscriptions. Empty allows all subscriptions.
+	Subscriptions []string
+	// Locations are the allowed locations. Empty allows all locations.
+	Locations []string
+	// ConfigMap is the optional ConfigMap overriding the lists. A key that is present overrides the corresponding
+	// list, even when its value is empty. A missing ConfigMap leaves the lists unchanged.
+	ConfigMap *types.NamespacedName
+	// Reader reads the ConfigMap. It should not be backed by an informer cache, to avoid caching every ConfigMap.
+	Reader client.Reader
+}
+
+var _ infrav1.PlacementAllowlist = (*Allowlist)(nil)
+
+// AllowedPlacement returns the allowed subscriptions and locations.
+func (a *Allowlist) AllowedPlacement(ctx context.Context) (subscriptions []string, locations []string, err error) {
+	subscriptions, locations = a.Subscriptions, a.Locations
+	if a.ConfigMap == nil {
+		return subscriptions, locations, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := a.Reader.Get(ctx, *a.ConfigMap, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return subscriptions, locations, nil
+		}
+		return nil, nil, errors.Wrapf(err, "failed to get ConfigMap %s", a.ConfigMap)
+	}
+	if v, ok := cm.Data[AllowedSubscriptionsKey]; ok {
+		subscriptions = ParseList(v)
+	}
+	if v, ok := cm.Data[AllowedLocationsKey]; ok {
+		locations = ParseList(v)
+	}
+	return subscriptions, locations, nil
+}
+
+// ParseList splits a comma-separated list, dropping blank entries.
+func ParseList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
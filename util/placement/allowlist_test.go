@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAllowlist_AllowedPlacement(t *testing.T) {
+	configMapName := types.NamespacedName{Namespace: "capz-system", Name: "placement-allowlist"}
+	configMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+			Data:       data,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		configMap         *types.NamespacedName
+		existing          []runtime.Object
+		wantSubscriptions []string
+		wantLocations     []string
+	}{
+		{
+			name:              "flags only",
+			wantSubscriptions: []string{"123"},
+			wantLocations:     []string{"westus2"},
+		},
+		{
+			name:              "missing ConfigMap keeps the flags",
+			configMap:         &configMapName,
+			wantSubscriptions: []string{"123"},
+			wantLocations:     []string{"westus2"},
+		},
+		{
+			name:      "ConfigMap overrides the flags",
+			configMap: &configMapName,
+			existing: []runtime.Object{configMap(map[string]string{
+				AllowedSubscriptionsKey: "456, 789",
+				AllowedLocationsKey:     "eastus,,westeurope",
+			})},
+			wantSubscriptions: []string{"456", "789"},
+			wantLocations:     []string{"eastus", "westeurope"},
+		},
+		{
+			name:      "empty ConfigMap key allows everything",
+			configMap: &configMapName,
+			existing: []runtime.Object{configMap(map[string]string{
+				AllowedLocationsKey: "",
+			})},
+			wantSubscriptions: []string{"123"},
+			wantLocations:     nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			a := &Allowlist{
+				Subscriptions: []string{"123"},
+				Locations:     []string{"westus2"},
+				ConfigMap:     tc.configMap,
+				Reader:        fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.existing...).Build(),
+			}
+			subscriptions, locations, err := a.AllowedPlacement(context.Background())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(subscriptions).To(Equal(tc.wantSubscriptions))
+			g.Expect(locations).To(Equal(tc.wantLocations))
+		})
+	}
+}
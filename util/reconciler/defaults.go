@@ -33,6 +33,9 @@ const (
 	DefaultReconcilerRequeue = 15 * time.Second
 	// DefaultHTTP429RetryAfter is a default backoff wait time when we get a HTTP 429 response with no Retry-After data.
 	DefaultHTTP429RetryAfter = 1 * time.Minute
+	// DefaultVMNotFoundGracePeriod is the default duration after the creation of a VM during which the VM not being
+	// found is attributed to Azure eventual consistency rather than to the VM having been deleted.
+	DefaultVMNotFoundGracePeriod = 5 * time.Minute
 )
 
 // Timeouts defines the timeouts for a reconciler.
@@ -45,6 +48,12 @@ type Timeouts struct {
 	AzureCall time.Duration
 	// Requeue is the value for the reconcile retry.
 	Requeue time.Duration
+	// VMNotFoundGracePeriod is the duration after the creation of a VM during which the VM not being found is
+	// attributed to Azure eventual consistency rather than to the VM having been deleted.
+	VMNotFoundGracePeriod time.Duration
+	// ServiceWeights are the weights of the Azure services in the split of the time left in a reconcile loop between
+	// the services reconciled in it, keyed by service name. Services without a weight have a weight of 1.
+	ServiceWeights map[string]int
 }
 
 // DefaultedAzureCallTimeout will default the timeout if it is zero-valued.
@@ -82,3 +91,22 @@ func (t Timeouts) DefaultedLoopTimeout() time.Duration {
 
 	return t.Loop
 }
+
+// DefaultedVMNotFoundGracePeriod will default the grace period if it is zero-valued.
+func (t Timeouts) DefaultedVMNotFoundGracePeriod() time.Duration {
+	if t.VMNotFoundGracePeriod <= 0 {
+		return DefaultVMNotFoundGracePeriod
+	}
+
+	return t.VMNotFoundGracePeriod
+}
+
+// DefaultedServiceWeight returns the weight of the service with the given name, defaulted to 1 if it is unset or
+// not positive.
+func (t Timeouts) DefaultedServiceWeight(service string) int {
+	if weight := t.ServiceWeights[service]; weight > 0 {
+		return weight
+	}
+
+	return 1
+}
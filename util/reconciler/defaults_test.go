@@ -167,3 +167,80 @@ func TestDefaultedAzureServiceReconcileTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultedVMNotFoundGracePeriod(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Subject  time.Duration
+		Expected time.Duration
+	}{
+		{
+			Name:     "WithZeroValueDefaults",
+			Subject:  time.Duration(0),
+			Expected: reconciler.DefaultVMNotFoundGracePeriod,
+		},
+		{
+			Name:     "WithRealValue",
+			Subject:  2 * time.Minute,
+			Expected: 2 * time.Minute,
+		},
+		{
+			Name:     "WithNegativeValue",
+			Subject:  time.Duration(-2),
+			Expected: reconciler.DefaultVMNotFoundGracePeriod,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+			timeouts := reconciler.Timeouts{
+				VMNotFoundGracePeriod: c.Subject,
+			}
+			g.Expect(timeouts.DefaultedVMNotFoundGracePeriod()).To(gomega.Equal(c.Expected))
+		})
+	}
+}
+
+func TestDefaultedServiceWeight(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Subject  map[string]int
+		Expected int
+	}{
+		{
+			Name:     "WithZeroValueDefaults",
+			Subject:  nil,
+			Expected: 1,
+		},
+		{
+			Name:     "WithRealValue",
+			Subject:  map[string]int{"loadbalancers": 3},
+			Expected: 3,
+		},
+		{
+			Name:     "WithOtherService",
+			Subject:  map[string]int{"virtualmachines": 3},
+			Expected: 1,
+		},
+		{
+			Name:     "WithNegativeValue",
+			Subject:  map[string]int{"loadbalancers": -2},
+			Expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+			timeouts := reconciler.Timeouts{
+				ServiceWeights: c.Subject,
+			}
+			g.Expect(timeouts.DefaultedServiceWeight("loadbalancers")).To(gomega.Equal(c.Expected))
+		})
+	}
+}
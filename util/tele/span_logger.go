@@ -136,6 +136,17 @@ func KVP(key, value string) Option {
 	}
 }
 
+// AddKVPs adds the key-value pairs of the given Options as
+// attributes of the span of ctx, for values which are only
+// known after the span was started.
+func AddKVPs(ctx context.Context, opts ...Option) {
+	cfg := &Config{KVPs: make(map[string]string)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(cfg.teleKeyValues()...)
+}
+
 // StartSpanWithLogger starts a new span with the global
 // tracer returned from Tracer(), then returns a new logger
 // implementation that composes both the logger from the
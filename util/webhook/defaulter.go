@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type defaulterWarningsKey struct{}
+
+// DefaulterWithWarnings returns a defaulting webhook for the defaulter which returns the warnings recorded with
+// AddDefaulterWarnings while defaulting an object in the admission response, e.g. to tell users about deprecated
+// fields migrated to their replacement.
+func DefaulterWithWarnings(scheme *runtime.Scheme, obj runtime.Object, defaulter admission.CustomDefaulter) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &defaulterWithWarnings{handler: admission.WithCustomDefaulter(scheme, obj, defaulter).Handler},
+	}
+}
+
+type defaulterWithWarnings struct {
+	handler admission.Handler
+}
+
+// Handle handles admission requests.
+func (h *defaulterWithWarnings) Handle(ctx context.Context, req admission.Request) admission.Response {
+	warnings := &admission.Warnings{}
+	resp := h.handler.Handle(context.WithValue(ctx, defaulterWarningsKey{}, warnings), req)
+	return resp.WithWarnings(*warnings...)
+}
+
+// AddDefaulterWarnings records warnings to return in the admission response of a webhook created with
+// DefaulterWithWarnings. It does nothing when defaulting outside of such a webhook.
+func AddDefaulterWarnings(ctx context.Context, warnings ...string) {
+	if w, ok := ctx.Value(defaulterWarningsKey{}).(*admission.Warnings); ok {
+		*w = append(*w, warnings...)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type configMapDefaulter struct{}
+
+func (configMapDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cm := obj.(*corev1.ConfigMap)
+	if cm.Data["old"] != "" {
+		cm.Data["new"] = cm.Data["old"]
+		delete(cm.Data, "old")
+		AddDefaulterWarnings(ctx, "data.old is deprecated and was moved to data.new")
+	}
+	return nil
+}
+
+func TestDefaulterWithWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		wantWarnings []string
+		wantPatches  int
+	}{
+		{
+			name: "no deprecated field",
+			data: map[string]string{"new": "value"},
+		},
+		{
+			name:         "deprecated field is migrated",
+			data:         map[string]string{"old": "value"},
+			wantWarnings: []string{"data.old is deprecated and was moved to data.new"},
+			wantPatches:  2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			raw, err := json.Marshal(&corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Data:       tc.data,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			webhook := DefaulterWithWarnings(scheme, &corev1.ConfigMap{}, configMapDefaulter{})
+			resp := webhook.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+			g.Expect(resp.Allowed).To(BeTrue())
+			g.Expect(resp.Warnings).To(Equal(tc.wantWarnings))
+			g.Expect(resp.Patches).To(HaveLen(tc.wantPatches))
+		})
+	}
+}
+
+func TestAddDefaulterWarningsWithoutWebhook(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(func() { AddDefaulterWarnings(context.Background(), "ignored") }).NotTo(Panic())
+}